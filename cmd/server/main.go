@@ -1,6 +1,7 @@
 package main
 
 import (
+	"cmp"
 	"context"
 	"log"
 	"net/http"
@@ -10,12 +11,25 @@ import (
 	"buf.build/go/protovalidate"
 	"connectrpc.com/connect"
 	"connectrpc.com/vanguard"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/multitracer"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"github.com/atlekbai/schema_registry/internal/bootstrap"
 	"github.com/atlekbai/schema_registry/internal/config"
 	"github.com/atlekbai/schema_registry/internal/db"
+	"github.com/atlekbai/schema_registry/internal/fieldcrypt"
+	"github.com/atlekbai/schema_registry/internal/hrql/parser"
+	hrqlpg "github.com/atlekbai/schema_registry/internal/hrql/pg"
+	"github.com/atlekbai/schema_registry/internal/metrics"
+	"github.com/atlekbai/schema_registry/internal/retention"
 	"github.com/atlekbai/schema_registry/internal/schema"
 	"github.com/atlekbai/schema_registry/internal/server"
 	"github.com/atlekbai/schema_registry/internal/service"
+	"github.com/atlekbai/schema_registry/internal/webhook"
+	"github.com/atlekbai/schema_registry/migrations"
 )
 
 func main() {
@@ -27,31 +41,178 @@ func main() {
 		log.Fatalf("failed to load config: %v", err)
 	}
 
-	pool, err := db.NewPool(ctx, cfg.DatabaseURL)
+	var slowLog *db.SlowQueryLog
+	var stmtStats *db.StatementCacheStats
+	var tracers []pgx.QueryTracer
+	if cfg.SlowQueryThreshold > 0 {
+		slowLog = db.NewSlowQueryLog(cfg.SlowQueryThreshold, cfg.SlowQueryExplainSample)
+		tracers = append(tracers, slowLog)
+	}
+	if cfg.StatementCacheSize > 0 {
+		stmtStats = db.NewStatementCacheStats()
+		tracers = append(tracers, stmtStats)
+	}
+	var tracer pgx.QueryTracer
+	if len(tracers) > 0 {
+		tracer = multitracer.New(tracers...)
+	}
+
+	pool, err := db.NewPool(ctx, cfg.DatabaseURL, tracer, cfg.StatementCacheSize)
 	if err != nil {
 		log.Fatalf("failed to connect to database: %v", err)
 	}
 	defer pool.Close()
 
+	if slowLog != nil {
+		slowLog.Start(ctx, pool)
+		log.Printf("slow query log enabled: threshold %s, explain sample %g", cfg.SlowQueryThreshold, cfg.SlowQueryExplainSample)
+	}
+
+	migrator, err := db.NewMigrator(pool, migrations.FS)
+	if err != nil {
+		log.Fatalf("failed to load migrations: %v", err)
+	}
+	if cfg.MigrationsBaseline > 0 {
+		if err := migrator.Baseline(ctx, cfg.MigrationsBaseline); err != nil {
+			log.Fatalf("failed to baseline migrations: %v", err)
+		}
+	}
+	switch cfg.MigrateOnStart {
+	case "auto":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			log.Fatalf("failed to apply migrations: %v", err)
+		}
+		log.Printf("migrations up to date: %d applied", len(applied))
+	case "verify":
+		if err := migrator.Verify(ctx); err != nil {
+			log.Fatalf("migrations not up to date: %v", err)
+		}
+	}
+
+	if cfg.SeedStandardObjects {
+		res, err := bootstrap.Apply(ctx, pool, bootstrap.Standard(), false)
+		if err != nil {
+			log.Fatalf("failed to seed standard objects: %v", err)
+		}
+		if res.Applied {
+			log.Printf("standard objects seeded (version %d): %d objects created, %d updated; %d fields created, %d updated",
+				res.Version, res.ObjectsCreated, res.ObjectsUpdated, res.FieldsCreated, res.FieldsUpdated)
+		}
+	}
+
+	var cipher *fieldcrypt.Cipher
+	if cfg.FieldEncryptionKey != nil {
+		cipher, err = fieldcrypt.New(cfg.FieldEncryptionKey)
+		if err != nil {
+			log.Fatalf("failed to create field cipher: %v", err)
+		}
+	} else {
+		log.Printf("no field encryption key configured: writes to ENCRYPTED fields are disabled")
+	}
+
+	cursors := hrqlpg.NewRandomCursorSigner()
+	if cfg.CursorSigningKey != nil {
+		cursors = hrqlpg.NewCursorSigner(cfg.CursorSigningKey)
+	} else {
+		log.Printf("no cursor signing key configured: cursors are only accepted by the instance that issued them")
+	}
+
+	expand, err := hrqlpg.ParseExpandStrategy(cfg.ExpandStrategy)
+	if err != nil {
+		log.Fatalf("invalid config: %v", err)
+	}
+
 	cache := schema.NewCache()
 	if err := cache.Load(ctx, pool); err != nil {
 		log.Fatalf("failed to load schema cache: %v", err)
 	}
 	log.Printf("schema cache loaded: %d objects", cache.ObjectCount())
+	for _, obj := range cache.Objects() {
+		if obj.MaxPageSize > cfg.ListLimits.MaxPageSize || obj.DefaultPageSize > cfg.ListLimits.MaxPageSize {
+			log.Printf("object %s: page sizes above MAX_PAGE_SIZE (%d) are capped to it", obj.APIName, cfg.ListLimits.MaxPageSize)
+		}
+	}
+	// Catalog writes from any instance reach this cache through the outbox.
+	schema.NewWatcher(pool, cache, cfg.SchemaPollInterval).Start(ctx)
+
+	idents := schema.NewIdentifierPolicy(cfg.APINameMaxLength, append(parser.ReservedWords(), cfg.ReservedAPINames...)...)
 
 	validator, err := protovalidate.New()
 	if err != nil {
 		log.Fatalf("failed to create validator: %v", err)
 	}
 
+	maintenance := server.NewMaintenance(cfg.ReadOnly, cfg.ReadOnlyReason)
+	if cfg.ReadOnly {
+		log.Printf("starting in read-only maintenance mode")
+	}
+
+	// API usage is recorded first, so rejected requests count as errors.
+	var apiUsage *metrics.APIUsage
+	if cfg.UsageFlushInterval > 0 {
+		apiUsage = metrics.NewAPIUsage(cfg.UsageRetention)
+		apiUsage.Start(ctx, pool, cfg.UsageFlushInterval)
+	}
+
 	interceptors := []connect.Interceptor{
+		server.UsageInterceptor(apiUsage),
+		server.ReadOnlyInterceptor(maintenance),
 		server.ValidationInterceptor(validator),
+		server.QueryLabelsInterceptor(),
+		server.ActorInterceptor(),
+	}
+
+	snapshots := db.NewSnapshots(pool, cfg.SnapshotTTL, cfg.SnapshotMax)
+	snapshots.Start(ctx)
+
+	enforcer := retention.NewEnforcer(pool, cache, cfg.RetentionBatchSize, func() bool {
+		readOnly, _, _ := maintenance.State()
+		return readOnly
+	})
+	if cfg.RetentionInterval > 0 {
+		enforcer.Start(ctx, cfg.RetentionInterval)
+	}
+
+	webhooks := webhook.NewValidator(&http.Client{})
+	webhooks.OnFailOpen = func(obj *schema.ObjectDef, err error) {
+		log.Printf("validation webhook: %s failed open: %v", obj.APIName, err)
+	}
+
+	// Pages get a slot per pool connection and counts half as many, unless
+	// configured otherwise.
+	maxConns := int(pool.Config().MaxConns)
+	limits := service.QueryLimits{
+		List:          db.NewLimiter("list", cmp.Or(cfg.ListConcurrency, maxConns), cfg.QueryQueueTimeout),
+		Count:         db.NewLimiter("count", cmp.Or(cfg.CountConcurrency, max(maxConns/2, 1)), cfg.QueryQueueTimeout),
+		ExpandColumns: cfg.ExpandColumnBudget,
+		MaxCost:       cfg.HRQLMaxCost,
+		MaxRows:       cfg.HRQLMaxRows,
+		Lists:         cfg.ListLimits,
+		StreamRows:    cfg.HRQLStreamMaxRows,
+	}
+
+	usage := metrics.NewHRQL()
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(usage, limits.List, limits.Count, collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	if stmtStats != nil {
+		registry.MustRegister(stmtStats)
+	}
+
+	dataLimits := schema.DataLimits{MaxCustomFields: cfg.MaxCustomFields, MaxDataBytes: cfg.MaxDataBytes}
+	meta := service.NewMetadataService(pool, cache, idents, dataLimits, cfg.AutoSearchIndexes, cfg.AutoSortIndexes, cfg.MetadataChangeApproval)
+	if cfg.MetadataChangeApproval {
+		log.Printf("metadata change approval enabled: mutations are held for review")
 	}
 
 	services := []server.ConnectService{
-		service.NewRegistryService(pool, cache),
-		service.NewMetadataService(pool, cache),
-		service.NewOrgService(pool, cache),
+		service.NewRegistryService(pool, cache, cipher, expand, snapshots, webhooks, limits, dataLimits, cursors),
+		meta,
+		service.NewOrgService(pool, cache, cipher, expand, usage, limits, cursors),
+		service.NewStatsService(pool, cache, usage, cfg.ListLimits),
+		service.NewUsageService(pool, cache),
+		service.NewAdminService(pool, cache, migrator, maintenance, enforcer, usage),
+		service.NewReviewService(pool, meta),
 	}
 
 	vanguardServices := make([]*vanguard.Service, len(services))
@@ -67,11 +228,28 @@ func main() {
 	}
 
 	mux := http.NewServeMux()
-	mux.Handle("/", transcoder)
+	mux.Handle("/", server.ConditionalGET(server.RawListHandler(transcoder)))
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+
+	// Browser clients: CORS preflights are answered before reaching the
+	// transcoder, which would reject OPTIONS.
+	var handler http.Handler = server.CORSHandler(server.CORS{
+		AllowedOrigins:   cfg.CORSAllowedOrigins,
+		AllowedMethods:   cfg.CORSAllowedMethods,
+		AllowedHeaders:   cfg.CORSAllowedHeaders,
+		AllowCredentials: cfg.CORSAllowCredentials,
+		MaxAge:           cfg.CORSMaxAge,
+	}, mux)
+	if cfg.SecurityHeaders {
+		handler = server.SecurityHeaders(handler)
+	}
+	if len(cfg.CORSAllowedOrigins) > 0 {
+		log.Printf("CORS enabled for origins %v", cfg.CORSAllowedOrigins)
+	}
 
 	srv := &http.Server{
 		Addr:    cfg.Addr(),
-		Handler: mux,
+		Handler: handler,
 	}
 
 	go func() {
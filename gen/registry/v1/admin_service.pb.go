@@ -0,0 +1,2575 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: registry/v1/admin_service.proto
+
+package registryv1
+
+import (
+	_ "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type MigrationStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MigrationStatusRequest) Reset() {
+	*x = MigrationStatusRequest{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MigrationStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MigrationStatusRequest) ProtoMessage() {}
+
+func (x *MigrationStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MigrationStatusRequest.ProtoReflect.Descriptor instead.
+func (*MigrationStatusRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{0}
+}
+
+type Migration struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Version int64                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Name    string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Applied bool                   `protobuf:"varint,3,opt,name=applied,proto3" json:"applied,omitempty"`
+	// When the migration was applied; empty for pending migrations and for
+	// versions recorded by a baseline.
+	AppliedAt     string `protobuf:"bytes,4,opt,name=applied_at,json=appliedAt,proto3" json:"applied_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Migration) Reset() {
+	*x = Migration{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Migration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Migration) ProtoMessage() {}
+
+func (x *Migration) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Migration.ProtoReflect.Descriptor instead.
+func (*Migration) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Migration) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Migration) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Migration) GetApplied() bool {
+	if x != nil {
+		return x.Applied
+	}
+	return false
+}
+
+func (x *Migration) GetAppliedAt() string {
+	if x != nil {
+		return x.AppliedAt
+	}
+	return ""
+}
+
+type MigrationStatusResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Highest applied version; 0 when none.
+	CurrentVersion int64 `protobuf:"varint,1,opt,name=current_version,json=currentVersion,proto3" json:"current_version,omitempty"`
+	// True when a migration failed part-way and needs manual repair.
+	Dirty bool `protobuf:"varint,2,opt,name=dirty,proto3" json:"dirty,omitempty"`
+	// Number of embedded migrations not yet applied.
+	Pending       int32        `protobuf:"varint,3,opt,name=pending,proto3" json:"pending,omitempty"`
+	Migrations    []*Migration `protobuf:"bytes,4,rep,name=migrations,proto3" json:"migrations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MigrationStatusResponse) Reset() {
+	*x = MigrationStatusResponse{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MigrationStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MigrationStatusResponse) ProtoMessage() {}
+
+func (x *MigrationStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MigrationStatusResponse.ProtoReflect.Descriptor instead.
+func (*MigrationStatusResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *MigrationStatusResponse) GetCurrentVersion() int64 {
+	if x != nil {
+		return x.CurrentVersion
+	}
+	return 0
+}
+
+func (x *MigrationStatusResponse) GetDirty() bool {
+	if x != nil {
+		return x.Dirty
+	}
+	return false
+}
+
+func (x *MigrationStatusResponse) GetPending() int32 {
+	if x != nil {
+		return x.Pending
+	}
+	return 0
+}
+
+func (x *MigrationStatusResponse) GetMigrations() []*Migration {
+	if x != nil {
+		return x.Migrations
+	}
+	return nil
+}
+
+type MaintenanceMode struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ReadOnly bool                   `protobuf:"varint,1,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
+	// Shown to clients whose writes are rejected, e.g. "reorg import until 18:00".
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	// When read-only mode was last switched on; empty when off.
+	Since         string `protobuf:"bytes,3,opt,name=since,proto3" json:"since,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MaintenanceMode) Reset() {
+	*x = MaintenanceMode{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MaintenanceMode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MaintenanceMode) ProtoMessage() {}
+
+func (x *MaintenanceMode) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MaintenanceMode.ProtoReflect.Descriptor instead.
+func (*MaintenanceMode) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *MaintenanceMode) GetReadOnly() bool {
+	if x != nil {
+		return x.ReadOnly
+	}
+	return false
+}
+
+func (x *MaintenanceMode) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *MaintenanceMode) GetSince() string {
+	if x != nil {
+		return x.Since
+	}
+	return ""
+}
+
+type GetMaintenanceModeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMaintenanceModeRequest) Reset() {
+	*x = GetMaintenanceModeRequest{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMaintenanceModeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMaintenanceModeRequest) ProtoMessage() {}
+
+func (x *GetMaintenanceModeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMaintenanceModeRequest.ProtoReflect.Descriptor instead.
+func (*GetMaintenanceModeRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{4}
+}
+
+type GetMaintenanceModeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mode          *MaintenanceMode       `protobuf:"bytes,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMaintenanceModeResponse) Reset() {
+	*x = GetMaintenanceModeResponse{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMaintenanceModeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMaintenanceModeResponse) ProtoMessage() {}
+
+func (x *GetMaintenanceModeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMaintenanceModeResponse.ProtoReflect.Descriptor instead.
+func (*GetMaintenanceModeResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetMaintenanceModeResponse) GetMode() *MaintenanceMode {
+	if x != nil {
+		return x.Mode
+	}
+	return nil
+}
+
+type SetMaintenanceModeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReadOnly      bool                   `protobuf:"varint,1,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetMaintenanceModeRequest) Reset() {
+	*x = SetMaintenanceModeRequest{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetMaintenanceModeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetMaintenanceModeRequest) ProtoMessage() {}
+
+func (x *SetMaintenanceModeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetMaintenanceModeRequest.ProtoReflect.Descriptor instead.
+func (*SetMaintenanceModeRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SetMaintenanceModeRequest) GetReadOnly() bool {
+	if x != nil {
+		return x.ReadOnly
+	}
+	return false
+}
+
+func (x *SetMaintenanceModeRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type SetMaintenanceModeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mode          *MaintenanceMode       `protobuf:"bytes,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetMaintenanceModeResponse) Reset() {
+	*x = SetMaintenanceModeResponse{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetMaintenanceModeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetMaintenanceModeResponse) ProtoMessage() {}
+
+func (x *SetMaintenanceModeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetMaintenanceModeResponse.ProtoReflect.Descriptor instead.
+func (*SetMaintenanceModeResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SetMaintenanceModeResponse) GetMode() *MaintenanceMode {
+	if x != nil {
+		return x.Mode
+	}
+	return nil
+}
+
+type CachedObject struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ApiName    string                 `protobuf:"bytes,2,opt,name=api_name,json=apiName,proto3" json:"api_name,omitempty"`
+	IsStandard bool                   `protobuf:"varint,3,opt,name=is_standard,json=isStandard,proto3" json:"is_standard,omitempty"`
+	// Catalog fields, excluding the synthetic system fields.
+	FieldCount    int32 `protobuf:"varint,4,opt,name=field_count,json=fieldCount,proto3" json:"field_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CachedObject) Reset() {
+	*x = CachedObject{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CachedObject) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CachedObject) ProtoMessage() {}
+
+func (x *CachedObject) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CachedObject.ProtoReflect.Descriptor instead.
+func (*CachedObject) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CachedObject) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CachedObject) GetApiName() string {
+	if x != nil {
+		return x.ApiName
+	}
+	return ""
+}
+
+func (x *CachedObject) GetIsStandard() bool {
+	if x != nil {
+		return x.IsStandard
+	}
+	return false
+}
+
+func (x *CachedObject) GetFieldCount() int32 {
+	if x != nil {
+		return x.FieldCount
+	}
+	return 0
+}
+
+type SchemaCache struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Generation uint64                 `protobuf:"varint,1,opt,name=generation,proto3" json:"generation,omitempty"`
+	// Time of the last full load.
+	LoadedAt      string          `protobuf:"bytes,2,opt,name=loaded_at,json=loadedAt,proto3" json:"loaded_at,omitempty"`
+	Objects       []*CachedObject `protobuf:"bytes,3,rep,name=objects,proto3" json:"objects,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SchemaCache) Reset() {
+	*x = SchemaCache{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SchemaCache) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SchemaCache) ProtoMessage() {}
+
+func (x *SchemaCache) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SchemaCache.ProtoReflect.Descriptor instead.
+func (*SchemaCache) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SchemaCache) GetGeneration() uint64 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+func (x *SchemaCache) GetLoadedAt() string {
+	if x != nil {
+		return x.LoadedAt
+	}
+	return ""
+}
+
+func (x *SchemaCache) GetObjects() []*CachedObject {
+	if x != nil {
+		return x.Objects
+	}
+	return nil
+}
+
+type GetSchemaCacheRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSchemaCacheRequest) Reset() {
+	*x = GetSchemaCacheRequest{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSchemaCacheRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSchemaCacheRequest) ProtoMessage() {}
+
+func (x *GetSchemaCacheRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSchemaCacheRequest.ProtoReflect.Descriptor instead.
+func (*GetSchemaCacheRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{10}
+}
+
+type GetSchemaCacheResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cache         *SchemaCache           `protobuf:"bytes,1,opt,name=cache,proto3" json:"cache,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSchemaCacheResponse) Reset() {
+	*x = GetSchemaCacheResponse{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSchemaCacheResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSchemaCacheResponse) ProtoMessage() {}
+
+func (x *GetSchemaCacheResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSchemaCacheResponse.ProtoReflect.Descriptor instead.
+func (*GetSchemaCacheResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetSchemaCacheResponse) GetCache() *SchemaCache {
+	if x != nil {
+		return x.Cache
+	}
+	return nil
+}
+
+type ReloadSchemaCacheRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReloadSchemaCacheRequest) Reset() {
+	*x = ReloadSchemaCacheRequest{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReloadSchemaCacheRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReloadSchemaCacheRequest) ProtoMessage() {}
+
+func (x *ReloadSchemaCacheRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReloadSchemaCacheRequest.ProtoReflect.Descriptor instead.
+func (*ReloadSchemaCacheRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{12}
+}
+
+type ReloadSchemaCacheResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cache         *SchemaCache           `protobuf:"bytes,1,opt,name=cache,proto3" json:"cache,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReloadSchemaCacheResponse) Reset() {
+	*x = ReloadSchemaCacheResponse{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReloadSchemaCacheResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReloadSchemaCacheResponse) ProtoMessage() {}
+
+func (x *ReloadSchemaCacheResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReloadSchemaCacheResponse.ProtoReflect.Descriptor instead.
+func (*ReloadSchemaCacheResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ReloadSchemaCacheResponse) GetCache() *SchemaCache {
+	if x != nil {
+		return x.Cache
+	}
+	return nil
+}
+
+type EvictSchemaCacheObjectRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiName       string                 `protobuf:"bytes,1,opt,name=api_name,json=apiName,proto3" json:"api_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EvictSchemaCacheObjectRequest) Reset() {
+	*x = EvictSchemaCacheObjectRequest{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EvictSchemaCacheObjectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvictSchemaCacheObjectRequest) ProtoMessage() {}
+
+func (x *EvictSchemaCacheObjectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvictSchemaCacheObjectRequest.ProtoReflect.Descriptor instead.
+func (*EvictSchemaCacheObjectRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *EvictSchemaCacheObjectRequest) GetApiName() string {
+	if x != nil {
+		return x.ApiName
+	}
+	return ""
+}
+
+type EvictSchemaCacheObjectResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// False when the object no longer exists in the catalog.
+	Found         bool   `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"`
+	Generation    uint64 `protobuf:"varint,2,opt,name=generation,proto3" json:"generation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EvictSchemaCacheObjectResponse) Reset() {
+	*x = EvictSchemaCacheObjectResponse{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EvictSchemaCacheObjectResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvictSchemaCacheObjectResponse) ProtoMessage() {}
+
+func (x *EvictSchemaCacheObjectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvictSchemaCacheObjectResponse.ProtoReflect.Descriptor instead.
+func (*EvictSchemaCacheObjectResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *EvictSchemaCacheObjectResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *EvictSchemaCacheObjectResponse) GetGeneration() uint64 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+type RetentionPolicy struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	ObjectName string                 `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	// DATE or DATETIME field the retention period counts from, e.g. end_date.
+	DateField  string `protobuf:"bytes,2,opt,name=date_field,json=dateField,proto3" json:"date_field,omitempty"`
+	RetainDays int32  `protobuf:"varint,3,opt,name=retain_days,json=retainDays,proto3" json:"retain_days,omitempty"`
+	// DELETE or ANONYMIZE.
+	Action string `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
+	// Fields cleared by ANONYMIZE; empty for DELETE.
+	AnonymizeFields []string `protobuf:"bytes,5,rep,name=anonymize_fields,json=anonymizeFields,proto3" json:"anonymize_fields,omitempty"`
+	Enabled         bool     `protobuf:"varint,6,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// When the scheduler last applied the policy; empty if never.
+	LastRunAt     string `protobuf:"bytes,7,opt,name=last_run_at,json=lastRunAt,proto3" json:"last_run_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetentionPolicy) Reset() {
+	*x = RetentionPolicy{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetentionPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetentionPolicy) ProtoMessage() {}
+
+func (x *RetentionPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetentionPolicy.ProtoReflect.Descriptor instead.
+func (*RetentionPolicy) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RetentionPolicy) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+func (x *RetentionPolicy) GetDateField() string {
+	if x != nil {
+		return x.DateField
+	}
+	return ""
+}
+
+func (x *RetentionPolicy) GetRetainDays() int32 {
+	if x != nil {
+		return x.RetainDays
+	}
+	return 0
+}
+
+func (x *RetentionPolicy) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *RetentionPolicy) GetAnonymizeFields() []string {
+	if x != nil {
+		return x.AnonymizeFields
+	}
+	return nil
+}
+
+func (x *RetentionPolicy) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *RetentionPolicy) GetLastRunAt() string {
+	if x != nil {
+		return x.LastRunAt
+	}
+	return ""
+}
+
+type ListRetentionPoliciesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRetentionPoliciesRequest) Reset() {
+	*x = ListRetentionPoliciesRequest{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRetentionPoliciesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRetentionPoliciesRequest) ProtoMessage() {}
+
+func (x *ListRetentionPoliciesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRetentionPoliciesRequest.ProtoReflect.Descriptor instead.
+func (*ListRetentionPoliciesRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{17}
+}
+
+type ListRetentionPoliciesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Policies      []*RetentionPolicy     `protobuf:"bytes,1,rep,name=policies,proto3" json:"policies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRetentionPoliciesResponse) Reset() {
+	*x = ListRetentionPoliciesResponse{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRetentionPoliciesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRetentionPoliciesResponse) ProtoMessage() {}
+
+func (x *ListRetentionPoliciesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRetentionPoliciesResponse.ProtoReflect.Descriptor instead.
+func (*ListRetentionPoliciesResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListRetentionPoliciesResponse) GetPolicies() []*RetentionPolicy {
+	if x != nil {
+		return x.Policies
+	}
+	return nil
+}
+
+type SetRetentionPolicyRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ObjectName      string                 `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	DateField       string                 `protobuf:"bytes,2,opt,name=date_field,json=dateField,proto3" json:"date_field,omitempty"`
+	RetainDays      int32                  `protobuf:"varint,3,opt,name=retain_days,json=retainDays,proto3" json:"retain_days,omitempty"`
+	Action          string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
+	AnonymizeFields []string               `protobuf:"bytes,5,rep,name=anonymize_fields,json=anonymizeFields,proto3" json:"anonymize_fields,omitempty"`
+	Enabled         bool                   `protobuf:"varint,6,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SetRetentionPolicyRequest) Reset() {
+	*x = SetRetentionPolicyRequest{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRetentionPolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRetentionPolicyRequest) ProtoMessage() {}
+
+func (x *SetRetentionPolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRetentionPolicyRequest.ProtoReflect.Descriptor instead.
+func (*SetRetentionPolicyRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SetRetentionPolicyRequest) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+func (x *SetRetentionPolicyRequest) GetDateField() string {
+	if x != nil {
+		return x.DateField
+	}
+	return ""
+}
+
+func (x *SetRetentionPolicyRequest) GetRetainDays() int32 {
+	if x != nil {
+		return x.RetainDays
+	}
+	return 0
+}
+
+func (x *SetRetentionPolicyRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *SetRetentionPolicyRequest) GetAnonymizeFields() []string {
+	if x != nil {
+		return x.AnonymizeFields
+	}
+	return nil
+}
+
+func (x *SetRetentionPolicyRequest) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+type SetRetentionPolicyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Policy        *RetentionPolicy       `protobuf:"bytes,1,opt,name=policy,proto3" json:"policy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetRetentionPolicyResponse) Reset() {
+	*x = SetRetentionPolicyResponse{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRetentionPolicyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRetentionPolicyResponse) ProtoMessage() {}
+
+func (x *SetRetentionPolicyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRetentionPolicyResponse.ProtoReflect.Descriptor instead.
+func (*SetRetentionPolicyResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SetRetentionPolicyResponse) GetPolicy() *RetentionPolicy {
+	if x != nil {
+		return x.Policy
+	}
+	return nil
+}
+
+type DeleteRetentionPolicyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ObjectName    string                 `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRetentionPolicyRequest) Reset() {
+	*x = DeleteRetentionPolicyRequest{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRetentionPolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRetentionPolicyRequest) ProtoMessage() {}
+
+func (x *DeleteRetentionPolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRetentionPolicyRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRetentionPolicyRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *DeleteRetentionPolicyRequest) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+type DeleteRetentionPolicyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRetentionPolicyResponse) Reset() {
+	*x = DeleteRetentionPolicyResponse{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRetentionPolicyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRetentionPolicyResponse) ProtoMessage() {}
+
+func (x *DeleteRetentionPolicyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRetentionPolicyResponse.ProtoReflect.Descriptor instead.
+func (*DeleteRetentionPolicyResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{22}
+}
+
+type RunRetentionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunRetentionRequest) Reset() {
+	*x = RunRetentionRequest{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunRetentionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunRetentionRequest) ProtoMessage() {}
+
+func (x *RunRetentionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunRetentionRequest.ProtoReflect.Descriptor instead.
+func (*RunRetentionRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{23}
+}
+
+type RetentionRunResult struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	ObjectName string                 `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	Action     string                 `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	Purged     int32                  `protobuf:"varint,3,opt,name=purged,proto3" json:"purged,omitempty"`
+	// Expired records left in place, e.g. because another record still
+	// references them.
+	Skipped       int32  `protobuf:"varint,4,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	Error         string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetentionRunResult) Reset() {
+	*x = RetentionRunResult{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetentionRunResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetentionRunResult) ProtoMessage() {}
+
+func (x *RetentionRunResult) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetentionRunResult.ProtoReflect.Descriptor instead.
+func (*RetentionRunResult) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *RetentionRunResult) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+func (x *RetentionRunResult) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *RetentionRunResult) GetPurged() int32 {
+	if x != nil {
+		return x.Purged
+	}
+	return 0
+}
+
+func (x *RetentionRunResult) GetSkipped() int32 {
+	if x != nil {
+		return x.Skipped
+	}
+	return 0
+}
+
+func (x *RetentionRunResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type RunRetentionResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// False when the run was skipped: another instance holds the retention
+	// lock or this instance is read-only.
+	Ran           bool                  `protobuf:"varint,1,opt,name=ran,proto3" json:"ran,omitempty"`
+	Results       []*RetentionRunResult `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunRetentionResponse) Reset() {
+	*x = RunRetentionResponse{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunRetentionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunRetentionResponse) ProtoMessage() {}
+
+func (x *RunRetentionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunRetentionResponse.ProtoReflect.Descriptor instead.
+func (*RunRetentionResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *RunRetentionResponse) GetRan() bool {
+	if x != nil {
+		return x.Ran
+	}
+	return false
+}
+
+func (x *RunRetentionResponse) GetResults() []*RetentionRunResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type ListRetentionAuditRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Restricts entries to one object when set.
+	ObjectName string `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	// Defaults to 100.
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRetentionAuditRequest) Reset() {
+	*x = ListRetentionAuditRequest{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRetentionAuditRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRetentionAuditRequest) ProtoMessage() {}
+
+func (x *ListRetentionAuditRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRetentionAuditRequest.ProtoReflect.Descriptor instead.
+func (*ListRetentionAuditRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ListRetentionAuditRequest) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+func (x *ListRetentionAuditRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type RetentionAuditEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PurgedAt      string                 `protobuf:"bytes,1,opt,name=purged_at,json=purgedAt,proto3" json:"purged_at,omitempty"`
+	ObjectName    string                 `protobuf:"bytes,2,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	RecordId      string                 `protobuf:"bytes,3,opt,name=record_id,json=recordId,proto3" json:"record_id,omitempty"`
+	Action        string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
+	DateField     string                 `protobuf:"bytes,5,opt,name=date_field,json=dateField,proto3" json:"date_field,omitempty"`
+	RetainDays    int32                  `protobuf:"varint,6,opt,name=retain_days,json=retainDays,proto3" json:"retain_days,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetentionAuditEntry) Reset() {
+	*x = RetentionAuditEntry{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetentionAuditEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetentionAuditEntry) ProtoMessage() {}
+
+func (x *RetentionAuditEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetentionAuditEntry.ProtoReflect.Descriptor instead.
+func (*RetentionAuditEntry) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *RetentionAuditEntry) GetPurgedAt() string {
+	if x != nil {
+		return x.PurgedAt
+	}
+	return ""
+}
+
+func (x *RetentionAuditEntry) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+func (x *RetentionAuditEntry) GetRecordId() string {
+	if x != nil {
+		return x.RecordId
+	}
+	return ""
+}
+
+func (x *RetentionAuditEntry) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *RetentionAuditEntry) GetDateField() string {
+	if x != nil {
+		return x.DateField
+	}
+	return ""
+}
+
+func (x *RetentionAuditEntry) GetRetainDays() int32 {
+	if x != nil {
+		return x.RetainDays
+	}
+	return 0
+}
+
+type ListRetentionAuditResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*RetentionAuditEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRetentionAuditResponse) Reset() {
+	*x = ListRetentionAuditResponse{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRetentionAuditResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRetentionAuditResponse) ProtoMessage() {}
+
+func (x *ListRetentionAuditResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRetentionAuditResponse.ProtoReflect.Descriptor instead.
+func (*ListRetentionAuditResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListRetentionAuditResponse) GetEntries() []*RetentionAuditEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type RebuildHierarchyPathsRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	ObjectName string                 `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	// Self-referencing LOOKUP field with a path column, e.g. manager.
+	Field         string `protobuf:"bytes,2,opt,name=field,proto3" json:"field,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RebuildHierarchyPathsRequest) Reset() {
+	*x = RebuildHierarchyPathsRequest{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RebuildHierarchyPathsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RebuildHierarchyPathsRequest) ProtoMessage() {}
+
+func (x *RebuildHierarchyPathsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RebuildHierarchyPathsRequest.ProtoReflect.Descriptor instead.
+func (*RebuildHierarchyPathsRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *RebuildHierarchyPathsRequest) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+func (x *RebuildHierarchyPathsRequest) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+type RebuildHierarchyPathsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Rows whose path was rewritten.
+	Updated int64 `protobuf:"varint,1,opt,name=updated,proto3" json:"updated,omitempty"`
+	// Records left with their stored path because they sit on or below a
+	// cycle, point at a missing parent, or are nested deeper than the maximum
+	// hierarchy depth.
+	BrokenIds     []string `protobuf:"bytes,2,rep,name=broken_ids,json=brokenIds,proto3" json:"broken_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RebuildHierarchyPathsResponse) Reset() {
+	*x = RebuildHierarchyPathsResponse{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RebuildHierarchyPathsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RebuildHierarchyPathsResponse) ProtoMessage() {}
+
+func (x *RebuildHierarchyPathsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RebuildHierarchyPathsResponse.ProtoReflect.Descriptor instead.
+func (*RebuildHierarchyPathsResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *RebuildHierarchyPathsResponse) GetUpdated() int64 {
+	if x != nil {
+		return x.Updated
+	}
+	return 0
+}
+
+func (x *RebuildHierarchyPathsResponse) GetBrokenIds() []string {
+	if x != nil {
+		return x.BrokenIds
+	}
+	return nil
+}
+
+type SearchIndexReportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchIndexReportRequest) Reset() {
+	*x = SearchIndexReportRequest{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchIndexReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchIndexReportRequest) ProtoMessage() {}
+
+func (x *SearchIndexReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchIndexReportRequest.ProtoReflect.Descriptor instead.
+func (*SearchIndexReportRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{31}
+}
+
+type SearchIndexReportResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unindexed fields with string operations first, most used first.
+	Fields        []*SearchIndexEntry `protobuf:"bytes,1,rep,name=fields,proto3" json:"fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchIndexReportResponse) Reset() {
+	*x = SearchIndexReportResponse{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchIndexReportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchIndexReportResponse) ProtoMessage() {}
+
+func (x *SearchIndexReportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchIndexReportResponse.ProtoReflect.Descriptor instead.
+func (*SearchIndexReportResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *SearchIndexReportResponse) GetFields() []*SearchIndexEntry {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type SearchIndexEntry struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	ObjectName   string                 `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	Field        string                 `protobuf:"bytes,2,opt,name=field,proto3" json:"field,omitempty"`
+	IsSearchable bool                   `protobuf:"varint,3,opt,name=is_searchable,json=isSearchable,proto3" json:"is_searchable,omitempty"`
+	// Name of the trigram index created for the field.
+	IndexName string `protobuf:"bytes,4,opt,name=index_name,json=indexName,proto3" json:"index_name,omitempty"`
+	// The index exists and is valid; a failed concurrent build leaves an
+	// invalid index behind.
+	Indexed bool `protobuf:"varint,5,opt,name=indexed,proto3" json:"indexed,omitempty"`
+	// HRQL string operations compiled on the field since startup.
+	StringOpUses  int64 `protobuf:"varint,6,opt,name=string_op_uses,json=stringOpUses,proto3" json:"string_op_uses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchIndexEntry) Reset() {
+	*x = SearchIndexEntry{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchIndexEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchIndexEntry) ProtoMessage() {}
+
+func (x *SearchIndexEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchIndexEntry.ProtoReflect.Descriptor instead.
+func (*SearchIndexEntry) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *SearchIndexEntry) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+func (x *SearchIndexEntry) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *SearchIndexEntry) GetIsSearchable() bool {
+	if x != nil {
+		return x.IsSearchable
+	}
+	return false
+}
+
+func (x *SearchIndexEntry) GetIndexName() string {
+	if x != nil {
+		return x.IndexName
+	}
+	return ""
+}
+
+func (x *SearchIndexEntry) GetIndexed() bool {
+	if x != nil {
+		return x.Indexed
+	}
+	return false
+}
+
+func (x *SearchIndexEntry) GetStringOpUses() int64 {
+	if x != nil {
+		return x.StringOpUses
+	}
+	return 0
+}
+
+type LintSchemaRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Skip the checks that scan records (unused custom fields), which read
+	// every table in the registry.
+	SkipData      bool `protobuf:"varint,1,opt,name=skip_data,json=skipData,proto3" json:"skip_data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LintSchemaRequest) Reset() {
+	*x = LintSchemaRequest{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LintSchemaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LintSchemaRequest) ProtoMessage() {}
+
+func (x *LintSchemaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LintSchemaRequest.ProtoReflect.Descriptor instead.
+func (*LintSchemaRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *LintSchemaRequest) GetSkipData() bool {
+	if x != nil {
+		return x.SkipData
+	}
+	return false
+}
+
+type LintSchemaResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Errors first, then warnings, then infos; by object and field within each.
+	Findings      []*LintFinding `protobuf:"bytes,1,rep,name=findings,proto3" json:"findings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LintSchemaResponse) Reset() {
+	*x = LintSchemaResponse{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LintSchemaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LintSchemaResponse) ProtoMessage() {}
+
+func (x *LintSchemaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LintSchemaResponse.ProtoReflect.Descriptor instead.
+func (*LintSchemaResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *LintSchemaResponse) GetFindings() []*LintFinding {
+	if x != nil {
+		return x.Findings
+	}
+	return nil
+}
+
+type LintFinding struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "error" (the schema cannot work as defined), "warning" or "info".
+	Severity string `protobuf:"bytes,1,opt,name=severity,proto3" json:"severity,omitempty"`
+	// What was found: "lookup_cycle", "missing_title", "name_overlap",
+	// "unused_field" or "unindexed_sort".
+	Code       string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	ObjectName string `protobuf:"bytes,3,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	// Empty for findings about the object itself.
+	Field   string `protobuf:"bytes,4,opt,name=field,proto3" json:"field,omitempty"`
+	Message string `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	// The other objects or fields (object.field) involved, e.g. the lookups
+	// of a cycle or the names a field overlaps.
+	Related       []string `protobuf:"bytes,6,rep,name=related,proto3" json:"related,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LintFinding) Reset() {
+	*x = LintFinding{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LintFinding) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LintFinding) ProtoMessage() {}
+
+func (x *LintFinding) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LintFinding.ProtoReflect.Descriptor instead.
+func (*LintFinding) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *LintFinding) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *LintFinding) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *LintFinding) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+func (x *LintFinding) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *LintFinding) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *LintFinding) GetRelated() []string {
+	if x != nil {
+		return x.Related
+	}
+	return nil
+}
+
+type AnonymizeObjectRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	ObjectName string                 `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	// HMAC key of the fakes. Keep it secret: fakes made without one could be
+	// matched against hashes of guessed values.
+	Salt          string `protobuf:"bytes,2,opt,name=salt,proto3" json:"salt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnonymizeObjectRequest) Reset() {
+	*x = AnonymizeObjectRequest{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnonymizeObjectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnonymizeObjectRequest) ProtoMessage() {}
+
+func (x *AnonymizeObjectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnonymizeObjectRequest.ProtoReflect.Descriptor instead.
+func (*AnonymizeObjectRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *AnonymizeObjectRequest) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+func (x *AnonymizeObjectRequest) GetSalt() string {
+	if x != nil {
+		return x.Salt
+	}
+	return ""
+}
+
+type AnonymizeObjectResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The is_pii fields rewritten.
+	Fields []string `protobuf:"bytes,1,rep,name=fields,proto3" json:"fields,omitempty"`
+	// Records holding a value in at least one of them.
+	Anonymized    int32 `protobuf:"varint,2,opt,name=anonymized,proto3" json:"anonymized,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnonymizeObjectResponse) Reset() {
+	*x = AnonymizeObjectResponse{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnonymizeObjectResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnonymizeObjectResponse) ProtoMessage() {}
+
+func (x *AnonymizeObjectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnonymizeObjectResponse.ProtoReflect.Descriptor instead.
+func (*AnonymizeObjectResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *AnonymizeObjectResponse) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+func (x *AnonymizeObjectResponse) GetAnonymized() int32 {
+	if x != nil {
+		return x.Anonymized
+	}
+	return 0
+}
+
+type SeedStandardObjectsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Reapply even if this seed version was already recorded, e.g. after a
+	// standard field was edited by hand.
+	Force         bool `protobuf:"varint,1,opt,name=force,proto3" json:"force,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SeedStandardObjectsRequest) Reset() {
+	*x = SeedStandardObjectsRequest{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SeedStandardObjectsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SeedStandardObjectsRequest) ProtoMessage() {}
+
+func (x *SeedStandardObjectsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SeedStandardObjectsRequest.ProtoReflect.Descriptor instead.
+func (*SeedStandardObjectsRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *SeedStandardObjectsRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+type SeedStandardObjectsResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Version int32                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	// False when the version was already applied and force was not set.
+	Applied        bool  `protobuf:"varint,2,opt,name=applied,proto3" json:"applied,omitempty"`
+	ObjectsCreated int32 `protobuf:"varint,3,opt,name=objects_created,json=objectsCreated,proto3" json:"objects_created,omitempty"`
+	ObjectsUpdated int32 `protobuf:"varint,4,opt,name=objects_updated,json=objectsUpdated,proto3" json:"objects_updated,omitempty"`
+	FieldsCreated  int32 `protobuf:"varint,5,opt,name=fields_created,json=fieldsCreated,proto3" json:"fields_created,omitempty"`
+	FieldsUpdated  int32 `protobuf:"varint,6,opt,name=fields_updated,json=fieldsUpdated,proto3" json:"fields_updated,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SeedStandardObjectsResponse) Reset() {
+	*x = SeedStandardObjectsResponse{}
+	mi := &file_registry_v1_admin_service_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SeedStandardObjectsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SeedStandardObjectsResponse) ProtoMessage() {}
+
+func (x *SeedStandardObjectsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_admin_service_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SeedStandardObjectsResponse.ProtoReflect.Descriptor instead.
+func (*SeedStandardObjectsResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_admin_service_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *SeedStandardObjectsResponse) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *SeedStandardObjectsResponse) GetApplied() bool {
+	if x != nil {
+		return x.Applied
+	}
+	return false
+}
+
+func (x *SeedStandardObjectsResponse) GetObjectsCreated() int32 {
+	if x != nil {
+		return x.ObjectsCreated
+	}
+	return 0
+}
+
+func (x *SeedStandardObjectsResponse) GetObjectsUpdated() int32 {
+	if x != nil {
+		return x.ObjectsUpdated
+	}
+	return 0
+}
+
+func (x *SeedStandardObjectsResponse) GetFieldsCreated() int32 {
+	if x != nil {
+		return x.FieldsCreated
+	}
+	return 0
+}
+
+func (x *SeedStandardObjectsResponse) GetFieldsUpdated() int32 {
+	if x != nil {
+		return x.FieldsUpdated
+	}
+	return 0
+}
+
+var File_registry_v1_admin_service_proto protoreflect.FileDescriptor
+
+const file_registry_v1_admin_service_proto_rawDesc = "" +
+	"\n" +
+	"\x1fregistry/v1/admin_service.proto\x12\vregistry.v1\x1a\x1bbuf/validate/validate.proto\x1a\x1cgoogle/api/annotations.proto\"\x18\n" +
+	"\x16MigrationStatusRequest\"r\n" +
+	"\tMigration\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x03R\aversion\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\aapplied\x18\x03 \x01(\bR\aapplied\x12\x1d\n" +
+	"\n" +
+	"applied_at\x18\x04 \x01(\tR\tappliedAt\"\xaa\x01\n" +
+	"\x17MigrationStatusResponse\x12'\n" +
+	"\x0fcurrent_version\x18\x01 \x01(\x03R\x0ecurrentVersion\x12\x14\n" +
+	"\x05dirty\x18\x02 \x01(\bR\x05dirty\x12\x18\n" +
+	"\apending\x18\x03 \x01(\x05R\apending\x126\n" +
+	"\n" +
+	"migrations\x18\x04 \x03(\v2\x16.registry.v1.MigrationR\n" +
+	"migrations\"\\\n" +
+	"\x0fMaintenanceMode\x12\x1b\n" +
+	"\tread_only\x18\x01 \x01(\bR\breadOnly\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x14\n" +
+	"\x05since\x18\x03 \x01(\tR\x05since\"\x1b\n" +
+	"\x19GetMaintenanceModeRequest\"N\n" +
+	"\x1aGetMaintenanceModeResponse\x120\n" +
+	"\x04mode\x18\x01 \x01(\v2\x1c.registry.v1.MaintenanceModeR\x04mode\"P\n" +
+	"\x19SetMaintenanceModeRequest\x12\x1b\n" +
+	"\tread_only\x18\x01 \x01(\bR\breadOnly\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"N\n" +
+	"\x1aSetMaintenanceModeResponse\x120\n" +
+	"\x04mode\x18\x01 \x01(\v2\x1c.registry.v1.MaintenanceModeR\x04mode\"{\n" +
+	"\fCachedObject\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bapi_name\x18\x02 \x01(\tR\aapiName\x12\x1f\n" +
+	"\vis_standard\x18\x03 \x01(\bR\n" +
+	"isStandard\x12\x1f\n" +
+	"\vfield_count\x18\x04 \x01(\x05R\n" +
+	"fieldCount\"\x7f\n" +
+	"\vSchemaCache\x12\x1e\n" +
+	"\n" +
+	"generation\x18\x01 \x01(\x04R\n" +
+	"generation\x12\x1b\n" +
+	"\tloaded_at\x18\x02 \x01(\tR\bloadedAt\x123\n" +
+	"\aobjects\x18\x03 \x03(\v2\x19.registry.v1.CachedObjectR\aobjects\"\x17\n" +
+	"\x15GetSchemaCacheRequest\"H\n" +
+	"\x16GetSchemaCacheResponse\x12.\n" +
+	"\x05cache\x18\x01 \x01(\v2\x18.registry.v1.SchemaCacheR\x05cache\"\x1a\n" +
+	"\x18ReloadSchemaCacheRequest\"K\n" +
+	"\x19ReloadSchemaCacheResponse\x12.\n" +
+	"\x05cache\x18\x01 \x01(\v2\x18.registry.v1.SchemaCacheR\x05cache\"C\n" +
+	"\x1dEvictSchemaCacheObjectRequest\x12\"\n" +
+	"\bapi_name\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\aapiName\"V\n" +
+	"\x1eEvictSchemaCacheObjectResponse\x12\x14\n" +
+	"\x05found\x18\x01 \x01(\bR\x05found\x12\x1e\n" +
+	"\n" +
+	"generation\x18\x02 \x01(\x04R\n" +
+	"generation\"\xef\x01\n" +
+	"\x0fRetentionPolicy\x12\x1f\n" +
+	"\vobject_name\x18\x01 \x01(\tR\n" +
+	"objectName\x12\x1d\n" +
+	"\n" +
+	"date_field\x18\x02 \x01(\tR\tdateField\x12\x1f\n" +
+	"\vretain_days\x18\x03 \x01(\x05R\n" +
+	"retainDays\x12\x16\n" +
+	"\x06action\x18\x04 \x01(\tR\x06action\x12)\n" +
+	"\x10anonymize_fields\x18\x05 \x03(\tR\x0fanonymizeFields\x12\x18\n" +
+	"\aenabled\x18\x06 \x01(\bR\aenabled\x12\x1e\n" +
+	"\vlast_run_at\x18\a \x01(\tR\tlastRunAt\"\x1e\n" +
+	"\x1cListRetentionPoliciesRequest\"Y\n" +
+	"\x1dListRetentionPoliciesResponse\x128\n" +
+	"\bpolicies\x18\x01 \x03(\v2\x1c.registry.v1.RetentionPolicyR\bpolicies\"\x8e\x02\n" +
+	"\x19SetRetentionPolicyRequest\x12(\n" +
+	"\vobject_name\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\n" +
+	"objectName\x12&\n" +
+	"\n" +
+	"date_field\x18\x02 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\tdateField\x12(\n" +
+	"\vretain_days\x18\x03 \x01(\x05B\a\xbaH\x04\x1a\x02 \x00R\n" +
+	"retainDays\x120\n" +
+	"\x06action\x18\x04 \x01(\tB\x18\xbaH\x15r\x13R\x06DELETER\tANONYMIZER\x06action\x12)\n" +
+	"\x10anonymize_fields\x18\x05 \x03(\tR\x0fanonymizeFields\x12\x18\n" +
+	"\aenabled\x18\x06 \x01(\bR\aenabled\"R\n" +
+	"\x1aSetRetentionPolicyResponse\x124\n" +
+	"\x06policy\x18\x01 \x01(\v2\x1c.registry.v1.RetentionPolicyR\x06policy\"H\n" +
+	"\x1cDeleteRetentionPolicyRequest\x12(\n" +
+	"\vobject_name\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\n" +
+	"objectName\"\x1f\n" +
+	"\x1dDeleteRetentionPolicyResponse\"\x15\n" +
+	"\x13RunRetentionRequest\"\x95\x01\n" +
+	"\x12RetentionRunResult\x12\x1f\n" +
+	"\vobject_name\x18\x01 \x01(\tR\n" +
+	"objectName\x12\x16\n" +
+	"\x06action\x18\x02 \x01(\tR\x06action\x12\x16\n" +
+	"\x06purged\x18\x03 \x01(\x05R\x06purged\x12\x18\n" +
+	"\askipped\x18\x04 \x01(\x05R\askipped\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\"c\n" +
+	"\x14RunRetentionResponse\x12\x10\n" +
+	"\x03ran\x18\x01 \x01(\bR\x03ran\x129\n" +
+	"\aresults\x18\x02 \x03(\v2\x1f.registry.v1.RetentionRunResultR\aresults\"^\n" +
+	"\x19ListRetentionAuditRequest\x12\x1f\n" +
+	"\vobject_name\x18\x01 \x01(\tR\n" +
+	"objectName\x12 \n" +
+	"\x05limit\x18\x02 \x01(\x05B\n" +
+	"\xbaH\a\x1a\x05\x18\xe8\a(\x00R\x05limit\"\xc8\x01\n" +
+	"\x13RetentionAuditEntry\x12\x1b\n" +
+	"\tpurged_at\x18\x01 \x01(\tR\bpurgedAt\x12\x1f\n" +
+	"\vobject_name\x18\x02 \x01(\tR\n" +
+	"objectName\x12\x1b\n" +
+	"\trecord_id\x18\x03 \x01(\tR\brecordId\x12\x16\n" +
+	"\x06action\x18\x04 \x01(\tR\x06action\x12\x1d\n" +
+	"\n" +
+	"date_field\x18\x05 \x01(\tR\tdateField\x12\x1f\n" +
+	"\vretain_days\x18\x06 \x01(\x05R\n" +
+	"retainDays\"X\n" +
+	"\x1aListRetentionAuditResponse\x12:\n" +
+	"\aentries\x18\x01 \x03(\v2 .registry.v1.RetentionAuditEntryR\aentries\"g\n" +
+	"\x1cRebuildHierarchyPathsRequest\x12(\n" +
+	"\vobject_name\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\n" +
+	"objectName\x12\x1d\n" +
+	"\x05field\x18\x02 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\x05field\"X\n" +
+	"\x1dRebuildHierarchyPathsResponse\x12\x18\n" +
+	"\aupdated\x18\x01 \x01(\x03R\aupdated\x12\x1d\n" +
+	"\n" +
+	"broken_ids\x18\x02 \x03(\tR\tbrokenIds\"\x1a\n" +
+	"\x18SearchIndexReportRequest\"R\n" +
+	"\x19SearchIndexReportResponse\x125\n" +
+	"\x06fields\x18\x01 \x03(\v2\x1d.registry.v1.SearchIndexEntryR\x06fields\"\xcd\x01\n" +
+	"\x10SearchIndexEntry\x12\x1f\n" +
+	"\vobject_name\x18\x01 \x01(\tR\n" +
+	"objectName\x12\x14\n" +
+	"\x05field\x18\x02 \x01(\tR\x05field\x12#\n" +
+	"\ris_searchable\x18\x03 \x01(\bR\fisSearchable\x12\x1d\n" +
+	"\n" +
+	"index_name\x18\x04 \x01(\tR\tindexName\x12\x18\n" +
+	"\aindexed\x18\x05 \x01(\bR\aindexed\x12$\n" +
+	"\x0estring_op_uses\x18\x06 \x01(\x03R\fstringOpUses\"0\n" +
+	"\x11LintSchemaRequest\x12\x1b\n" +
+	"\tskip_data\x18\x01 \x01(\bR\bskipData\"J\n" +
+	"\x12LintSchemaResponse\x124\n" +
+	"\bfindings\x18\x01 \x03(\v2\x18.registry.v1.LintFindingR\bfindings\"\xa8\x01\n" +
+	"\vLintFinding\x12\x1a\n" +
+	"\bseverity\x18\x01 \x01(\tR\bseverity\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x1f\n" +
+	"\vobject_name\x18\x03 \x01(\tR\n" +
+	"objectName\x12\x14\n" +
+	"\x05field\x18\x04 \x01(\tR\x05field\x12\x18\n" +
+	"\amessage\x18\x05 \x01(\tR\amessage\x12\x18\n" +
+	"\arelated\x18\x06 \x03(\tR\arelated\"_\n" +
+	"\x16AnonymizeObjectRequest\x12(\n" +
+	"\vobject_name\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\n" +
+	"objectName\x12\x1b\n" +
+	"\x04salt\x18\x02 \x01(\tB\a\xbaH\x04r\x02\x10\x10R\x04salt\"Q\n" +
+	"\x17AnonymizeObjectResponse\x12\x16\n" +
+	"\x06fields\x18\x01 \x03(\tR\x06fields\x12\x1e\n" +
+	"\n" +
+	"anonymized\x18\x02 \x01(\x05R\n" +
+	"anonymized\"2\n" +
+	"\x1aSeedStandardObjectsRequest\x12\x14\n" +
+	"\x05force\x18\x01 \x01(\bR\x05force\"\xf1\x01\n" +
+	"\x1bSeedStandardObjectsResponse\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x05R\aversion\x12\x18\n" +
+	"\aapplied\x18\x02 \x01(\bR\aapplied\x12'\n" +
+	"\x0fobjects_created\x18\x03 \x01(\x05R\x0eobjectsCreated\x12'\n" +
+	"\x0fobjects_updated\x18\x04 \x01(\x05R\x0eobjectsUpdated\x12%\n" +
+	"\x0efields_created\x18\x05 \x01(\x05R\rfieldsCreated\x12%\n" +
+	"\x0efields_updated\x18\x06 \x01(\x05R\rfieldsUpdated2\xe1\x11\n" +
+	"\fAdminService\x12{\n" +
+	"\x0fMigrationStatus\x12#.registry.v1.MigrationStatusRequest\x1a$.registry.v1.MigrationStatusResponse\"\x1d\x82\xd3\xe4\x93\x02\x17\x12\x15/api/admin/migrations\x12\x85\x01\n" +
+	"\x12GetMaintenanceMode\x12&.registry.v1.GetMaintenanceModeRequest\x1a'.registry.v1.GetMaintenanceModeResponse\"\x1e\x82\xd3\xe4\x93\x02\x18\x12\x16/api/admin/maintenance\x12\x88\x01\n" +
+	"\x12SetMaintenanceMode\x12&.registry.v1.SetMaintenanceModeRequest\x1a'.registry.v1.SetMaintenanceModeResponse\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\x1a\x16/api/admin/maintenance\x12s\n" +
+	"\x0eGetSchemaCache\x12\".registry.v1.GetSchemaCacheRequest\x1a#.registry.v1.GetSchemaCacheResponse\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/api/admin/cache\x12\x86\x01\n" +
+	"\x11ReloadSchemaCache\x12%.registry.v1.ReloadSchemaCacheRequest\x1a&.registry.v1.ReloadSchemaCacheResponse\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*\"\x17/api/admin/cache/reload\x12\xa7\x01\n" +
+	"\x16EvictSchemaCacheObject\x12*.registry.v1.EvictSchemaCacheObjectRequest\x1a+.registry.v1.EvictSchemaCacheObjectResponse\"4\x82\xd3\xe4\x93\x02.:\x01*\")/api/admin/cache/objects/{api_name}/evict\x12\x95\x01\n" +
+	"\x15ListRetentionPolicies\x12).registry.v1.ListRetentionPoliciesRequest\x1a*.registry.v1.ListRetentionPoliciesResponse\"%\x82\xd3\xe4\x93\x02\x1f\x12\x1d/api/admin/retention/policies\x12\x9d\x01\n" +
+	"\x12SetRetentionPolicy\x12&.registry.v1.SetRetentionPolicyRequest\x1a'.registry.v1.SetRetentionPolicyResponse\"6\x82\xd3\xe4\x93\x020:\x01*\x1a+/api/admin/retention/policies/{object_name}\x12\xa3\x01\n" +
+	"\x15DeleteRetentionPolicy\x12).registry.v1.DeleteRetentionPolicyRequest\x1a*.registry.v1.DeleteRetentionPolicyResponse\"3\x82\xd3\xe4\x93\x02-*+/api/admin/retention/policies/{object_name}\x12x\n" +
+	"\fRunRetention\x12 .registry.v1.RunRetentionRequest\x1a!.registry.v1.RunRetentionResponse\"#\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/api/admin/retention/run\x12\x89\x01\n" +
+	"\x12ListRetentionAudit\x12&.registry.v1.ListRetentionAuditRequest\x1a'.registry.v1.ListRetentionAuditResponse\"\"\x82\xd3\xe4\x93\x02\x1c\x12\x1a/api/admin/retention/audit\x12\xaf\x01\n" +
+	"\x15RebuildHierarchyPaths\x12).registry.v1.RebuildHierarchyPathsRequest\x1a*.registry.v1.RebuildHierarchyPathsResponse\"?\x82\xd3\xe4\x93\x029:\x01*\"4/api/admin/hierarchies/{object_name}/{field}/rebuild\x12\x85\x01\n" +
+	"\x11SearchIndexReport\x12%.registry.v1.SearchIndexReportRequest\x1a&.registry.v1.SearchIndexReportResponse\"!\x82\xd3\xe4\x93\x02\x1b\x12\x19/api/admin/search-indexes\x12f\n" +
+	"\n" +
+	"LintSchema\x12\x1e.registry.v1.LintSchemaRequest\x1a\x1f.registry.v1.LintSchemaResponse\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/api/admin/lint\x12\x8b\x01\n" +
+	"\x0fAnonymizeObject\x12#.registry.v1.AnonymizeObjectRequest\x1a$.registry.v1.AnonymizeObjectResponse\"-\x82\xd3\xe4\x93\x02':\x01*\"\"/api/admin/anonymize/{object_name}\x12\x84\x01\n" +
+	"\x13SeedStandardObjects\x12'.registry.v1.SeedStandardObjectsRequest\x1a(.registry.v1.SeedStandardObjectsResponse\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/api/admin/seedB\xb1\x01\n" +
+	"\x0fcom.registry.v1B\x11AdminServiceProtoP\x01Z>github.com/atlekbai/schema_registry/gen/registry/v1;registryv1\xa2\x02\x03RXX\xaa\x02\vRegistry.V1\xca\x02\vRegistry\\V1\xe2\x02\x17Registry\\V1\\GPBMetadata\xea\x02\fRegistry::V1b\x06proto3"
+
+var (
+	file_registry_v1_admin_service_proto_rawDescOnce sync.Once
+	file_registry_v1_admin_service_proto_rawDescData []byte
+)
+
+func file_registry_v1_admin_service_proto_rawDescGZIP() []byte {
+	file_registry_v1_admin_service_proto_rawDescOnce.Do(func() {
+		file_registry_v1_admin_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_registry_v1_admin_service_proto_rawDesc), len(file_registry_v1_admin_service_proto_rawDesc)))
+	})
+	return file_registry_v1_admin_service_proto_rawDescData
+}
+
+var file_registry_v1_admin_service_proto_msgTypes = make([]protoimpl.MessageInfo, 41)
+var file_registry_v1_admin_service_proto_goTypes = []any{
+	(*MigrationStatusRequest)(nil),         // 0: registry.v1.MigrationStatusRequest
+	(*Migration)(nil),                      // 1: registry.v1.Migration
+	(*MigrationStatusResponse)(nil),        // 2: registry.v1.MigrationStatusResponse
+	(*MaintenanceMode)(nil),                // 3: registry.v1.MaintenanceMode
+	(*GetMaintenanceModeRequest)(nil),      // 4: registry.v1.GetMaintenanceModeRequest
+	(*GetMaintenanceModeResponse)(nil),     // 5: registry.v1.GetMaintenanceModeResponse
+	(*SetMaintenanceModeRequest)(nil),      // 6: registry.v1.SetMaintenanceModeRequest
+	(*SetMaintenanceModeResponse)(nil),     // 7: registry.v1.SetMaintenanceModeResponse
+	(*CachedObject)(nil),                   // 8: registry.v1.CachedObject
+	(*SchemaCache)(nil),                    // 9: registry.v1.SchemaCache
+	(*GetSchemaCacheRequest)(nil),          // 10: registry.v1.GetSchemaCacheRequest
+	(*GetSchemaCacheResponse)(nil),         // 11: registry.v1.GetSchemaCacheResponse
+	(*ReloadSchemaCacheRequest)(nil),       // 12: registry.v1.ReloadSchemaCacheRequest
+	(*ReloadSchemaCacheResponse)(nil),      // 13: registry.v1.ReloadSchemaCacheResponse
+	(*EvictSchemaCacheObjectRequest)(nil),  // 14: registry.v1.EvictSchemaCacheObjectRequest
+	(*EvictSchemaCacheObjectResponse)(nil), // 15: registry.v1.EvictSchemaCacheObjectResponse
+	(*RetentionPolicy)(nil),                // 16: registry.v1.RetentionPolicy
+	(*ListRetentionPoliciesRequest)(nil),   // 17: registry.v1.ListRetentionPoliciesRequest
+	(*ListRetentionPoliciesResponse)(nil),  // 18: registry.v1.ListRetentionPoliciesResponse
+	(*SetRetentionPolicyRequest)(nil),      // 19: registry.v1.SetRetentionPolicyRequest
+	(*SetRetentionPolicyResponse)(nil),     // 20: registry.v1.SetRetentionPolicyResponse
+	(*DeleteRetentionPolicyRequest)(nil),   // 21: registry.v1.DeleteRetentionPolicyRequest
+	(*DeleteRetentionPolicyResponse)(nil),  // 22: registry.v1.DeleteRetentionPolicyResponse
+	(*RunRetentionRequest)(nil),            // 23: registry.v1.RunRetentionRequest
+	(*RetentionRunResult)(nil),             // 24: registry.v1.RetentionRunResult
+	(*RunRetentionResponse)(nil),           // 25: registry.v1.RunRetentionResponse
+	(*ListRetentionAuditRequest)(nil),      // 26: registry.v1.ListRetentionAuditRequest
+	(*RetentionAuditEntry)(nil),            // 27: registry.v1.RetentionAuditEntry
+	(*ListRetentionAuditResponse)(nil),     // 28: registry.v1.ListRetentionAuditResponse
+	(*RebuildHierarchyPathsRequest)(nil),   // 29: registry.v1.RebuildHierarchyPathsRequest
+	(*RebuildHierarchyPathsResponse)(nil),  // 30: registry.v1.RebuildHierarchyPathsResponse
+	(*SearchIndexReportRequest)(nil),       // 31: registry.v1.SearchIndexReportRequest
+	(*SearchIndexReportResponse)(nil),      // 32: registry.v1.SearchIndexReportResponse
+	(*SearchIndexEntry)(nil),               // 33: registry.v1.SearchIndexEntry
+	(*LintSchemaRequest)(nil),              // 34: registry.v1.LintSchemaRequest
+	(*LintSchemaResponse)(nil),             // 35: registry.v1.LintSchemaResponse
+	(*LintFinding)(nil),                    // 36: registry.v1.LintFinding
+	(*AnonymizeObjectRequest)(nil),         // 37: registry.v1.AnonymizeObjectRequest
+	(*AnonymizeObjectResponse)(nil),        // 38: registry.v1.AnonymizeObjectResponse
+	(*SeedStandardObjectsRequest)(nil),     // 39: registry.v1.SeedStandardObjectsRequest
+	(*SeedStandardObjectsResponse)(nil),    // 40: registry.v1.SeedStandardObjectsResponse
+}
+var file_registry_v1_admin_service_proto_depIdxs = []int32{
+	1,  // 0: registry.v1.MigrationStatusResponse.migrations:type_name -> registry.v1.Migration
+	3,  // 1: registry.v1.GetMaintenanceModeResponse.mode:type_name -> registry.v1.MaintenanceMode
+	3,  // 2: registry.v1.SetMaintenanceModeResponse.mode:type_name -> registry.v1.MaintenanceMode
+	8,  // 3: registry.v1.SchemaCache.objects:type_name -> registry.v1.CachedObject
+	9,  // 4: registry.v1.GetSchemaCacheResponse.cache:type_name -> registry.v1.SchemaCache
+	9,  // 5: registry.v1.ReloadSchemaCacheResponse.cache:type_name -> registry.v1.SchemaCache
+	16, // 6: registry.v1.ListRetentionPoliciesResponse.policies:type_name -> registry.v1.RetentionPolicy
+	16, // 7: registry.v1.SetRetentionPolicyResponse.policy:type_name -> registry.v1.RetentionPolicy
+	24, // 8: registry.v1.RunRetentionResponse.results:type_name -> registry.v1.RetentionRunResult
+	27, // 9: registry.v1.ListRetentionAuditResponse.entries:type_name -> registry.v1.RetentionAuditEntry
+	33, // 10: registry.v1.SearchIndexReportResponse.fields:type_name -> registry.v1.SearchIndexEntry
+	36, // 11: registry.v1.LintSchemaResponse.findings:type_name -> registry.v1.LintFinding
+	0,  // 12: registry.v1.AdminService.MigrationStatus:input_type -> registry.v1.MigrationStatusRequest
+	4,  // 13: registry.v1.AdminService.GetMaintenanceMode:input_type -> registry.v1.GetMaintenanceModeRequest
+	6,  // 14: registry.v1.AdminService.SetMaintenanceMode:input_type -> registry.v1.SetMaintenanceModeRequest
+	10, // 15: registry.v1.AdminService.GetSchemaCache:input_type -> registry.v1.GetSchemaCacheRequest
+	12, // 16: registry.v1.AdminService.ReloadSchemaCache:input_type -> registry.v1.ReloadSchemaCacheRequest
+	14, // 17: registry.v1.AdminService.EvictSchemaCacheObject:input_type -> registry.v1.EvictSchemaCacheObjectRequest
+	17, // 18: registry.v1.AdminService.ListRetentionPolicies:input_type -> registry.v1.ListRetentionPoliciesRequest
+	19, // 19: registry.v1.AdminService.SetRetentionPolicy:input_type -> registry.v1.SetRetentionPolicyRequest
+	21, // 20: registry.v1.AdminService.DeleteRetentionPolicy:input_type -> registry.v1.DeleteRetentionPolicyRequest
+	23, // 21: registry.v1.AdminService.RunRetention:input_type -> registry.v1.RunRetentionRequest
+	26, // 22: registry.v1.AdminService.ListRetentionAudit:input_type -> registry.v1.ListRetentionAuditRequest
+	29, // 23: registry.v1.AdminService.RebuildHierarchyPaths:input_type -> registry.v1.RebuildHierarchyPathsRequest
+	31, // 24: registry.v1.AdminService.SearchIndexReport:input_type -> registry.v1.SearchIndexReportRequest
+	34, // 25: registry.v1.AdminService.LintSchema:input_type -> registry.v1.LintSchemaRequest
+	37, // 26: registry.v1.AdminService.AnonymizeObject:input_type -> registry.v1.AnonymizeObjectRequest
+	39, // 27: registry.v1.AdminService.SeedStandardObjects:input_type -> registry.v1.SeedStandardObjectsRequest
+	2,  // 28: registry.v1.AdminService.MigrationStatus:output_type -> registry.v1.MigrationStatusResponse
+	5,  // 29: registry.v1.AdminService.GetMaintenanceMode:output_type -> registry.v1.GetMaintenanceModeResponse
+	7,  // 30: registry.v1.AdminService.SetMaintenanceMode:output_type -> registry.v1.SetMaintenanceModeResponse
+	11, // 31: registry.v1.AdminService.GetSchemaCache:output_type -> registry.v1.GetSchemaCacheResponse
+	13, // 32: registry.v1.AdminService.ReloadSchemaCache:output_type -> registry.v1.ReloadSchemaCacheResponse
+	15, // 33: registry.v1.AdminService.EvictSchemaCacheObject:output_type -> registry.v1.EvictSchemaCacheObjectResponse
+	18, // 34: registry.v1.AdminService.ListRetentionPolicies:output_type -> registry.v1.ListRetentionPoliciesResponse
+	20, // 35: registry.v1.AdminService.SetRetentionPolicy:output_type -> registry.v1.SetRetentionPolicyResponse
+	22, // 36: registry.v1.AdminService.DeleteRetentionPolicy:output_type -> registry.v1.DeleteRetentionPolicyResponse
+	25, // 37: registry.v1.AdminService.RunRetention:output_type -> registry.v1.RunRetentionResponse
+	28, // 38: registry.v1.AdminService.ListRetentionAudit:output_type -> registry.v1.ListRetentionAuditResponse
+	30, // 39: registry.v1.AdminService.RebuildHierarchyPaths:output_type -> registry.v1.RebuildHierarchyPathsResponse
+	32, // 40: registry.v1.AdminService.SearchIndexReport:output_type -> registry.v1.SearchIndexReportResponse
+	35, // 41: registry.v1.AdminService.LintSchema:output_type -> registry.v1.LintSchemaResponse
+	38, // 42: registry.v1.AdminService.AnonymizeObject:output_type -> registry.v1.AnonymizeObjectResponse
+	40, // 43: registry.v1.AdminService.SeedStandardObjects:output_type -> registry.v1.SeedStandardObjectsResponse
+	28, // [28:44] is the sub-list for method output_type
+	12, // [12:28] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_registry_v1_admin_service_proto_init() }
+func file_registry_v1_admin_service_proto_init() {
+	if File_registry_v1_admin_service_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_registry_v1_admin_service_proto_rawDesc), len(file_registry_v1_admin_service_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   41,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_registry_v1_admin_service_proto_goTypes,
+		DependencyIndexes: file_registry_v1_admin_service_proto_depIdxs,
+		MessageInfos:      file_registry_v1_admin_service_proto_msgTypes,
+	}.Build()
+	File_registry_v1_admin_service_proto = out.File
+	file_registry_v1_admin_service_proto_goTypes = nil
+	file_registry_v1_admin_service_proto_depIdxs = nil
+}
@@ -37,8 +37,38 @@ type ObjectMeta struct {
 	Fields               []*FieldMeta           `protobuf:"bytes,11,rep,name=fields,proto3" json:"fields,omitempty"`
 	CreatedAt            string                 `protobuf:"bytes,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt            string                 `protobuf:"bytes,13,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	unknownFields        protoimpl.UnknownFields
-	sizeCache            protoimpl.SizeCache
+	// Order applied to List and HRQL queries that specify none, in the REST
+	// order syntax ("last_name", "start_date.desc"). Empty orders by id.
+	DefaultOrder string `protobuf:"bytes,14,opt,name=default_order,json=defaultOrder,proto3" json:"default_order,omitempty"`
+	// Page size applied when a request has no limit; 0 uses the server default.
+	DefaultPageSize int32 `protobuf:"varint,15,opt,name=default_page_size,json=defaultPageSize,proto3" json:"default_page_size,omitempty"`
+	// Largest limit a request may ask for; 0 uses the server maximum, which
+	// also caps a larger value.
+	MaxPageSize int32 `protobuf:"varint,16,opt,name=max_page_size,json=maxPageSize,proto3" json:"max_page_size,omitempty"`
+	// External validator of candidate records (see ValidationWebhook); unset
+	// when the object has none.
+	ValidationWebhook *ValidationWebhook `protobuf:"bytes,17,opt,name=validation_webhook,json=validationWebhook,proto3" json:"validation_webhook,omitempty"`
+	// Template rendering a record's display name from its fields, e.g.
+	// "{first_name} {last_name}". Expanded records carry it as "_display" and
+	// RegistryService.Typeahead searches it. Empty when unset.
+	DisplayTemplate string `protobuf:"bytes,18,opt,name=display_template,json=displayTemplate,proto3" json:"display_template,omitempty"`
+	// API lifecycle notice; unset while the object is current.
+	Deprecation *ObjectDeprecation `protobuf:"bytes,19,opt,name=deprecation,proto3" json:"deprecation,omitempty"`
+	// Former api_names, oldest first (see RenameObjectRequest).
+	Aliases []string `protobuf:"bytes,20,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	// Most custom fields the object may have, and largest record document in
+	// bytes of JSON text; 0 uses the server default (see GetObjectUsage).
+	MaxCustomFields int32 `protobuf:"varint,21,opt,name=max_custom_fields,json=maxCustomFields,proto3" json:"max_custom_fields,omitempty"`
+	MaxDataBytes    int32 `protobuf:"varint,22,opt,name=max_data_bytes,json=maxDataBytes,proto3" json:"max_data_bytes,omitempty"`
+	// Planner estimate of a list's rows at or below which its total count is
+	// exact rather than estimated; 0 uses the server default.
+	ExactCountThreshold int64 `protobuf:"varint,23,opt,name=exact_count_threshold,json=exactCountThreshold,proto3" json:"exact_count_threshold,omitempty"`
+	// Seconds browsers and CDNs may reuse REST List and Get responses of the
+	// object (Cache-Control max-age), for reference data that rarely changes;
+	// 0 disables caching.
+	CacheMaxAgeSeconds int32 `protobuf:"varint,24,opt,name=cache_max_age_seconds,json=cacheMaxAgeSeconds,proto3" json:"cache_max_age_seconds,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *ObjectMeta) Reset() {
@@ -162,6 +192,217 @@ func (x *ObjectMeta) GetUpdatedAt() string {
 	return ""
 }
 
+func (x *ObjectMeta) GetDefaultOrder() string {
+	if x != nil {
+		return x.DefaultOrder
+	}
+	return ""
+}
+
+func (x *ObjectMeta) GetDefaultPageSize() int32 {
+	if x != nil {
+		return x.DefaultPageSize
+	}
+	return 0
+}
+
+func (x *ObjectMeta) GetMaxPageSize() int32 {
+	if x != nil {
+		return x.MaxPageSize
+	}
+	return 0
+}
+
+func (x *ObjectMeta) GetValidationWebhook() *ValidationWebhook {
+	if x != nil {
+		return x.ValidationWebhook
+	}
+	return nil
+}
+
+func (x *ObjectMeta) GetDisplayTemplate() string {
+	if x != nil {
+		return x.DisplayTemplate
+	}
+	return ""
+}
+
+func (x *ObjectMeta) GetDeprecation() *ObjectDeprecation {
+	if x != nil {
+		return x.Deprecation
+	}
+	return nil
+}
+
+func (x *ObjectMeta) GetAliases() []string {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+func (x *ObjectMeta) GetMaxCustomFields() int32 {
+	if x != nil {
+		return x.MaxCustomFields
+	}
+	return 0
+}
+
+func (x *ObjectMeta) GetMaxDataBytes() int32 {
+	if x != nil {
+		return x.MaxDataBytes
+	}
+	return 0
+}
+
+func (x *ObjectMeta) GetExactCountThreshold() int64 {
+	if x != nil {
+		return x.ExactCountThreshold
+	}
+	return 0
+}
+
+func (x *ObjectMeta) GetCacheMaxAgeSeconds() int32 {
+	if x != nil {
+		return x.CacheMaxAgeSeconds
+	}
+	return 0
+}
+
+// ObjectDeprecation announces that an object will be removed. It keeps
+// working until then, but RegistryService List and Get on it answer with
+// Deprecation (RFC 9745) and Sunset (RFC 8594) headers, a successor-version
+// Link to the replacement and a warning in the response.
+type ObjectDeprecation struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// RFC 3339 timestamp; empty in UpdateObject means now.
+	DeprecatedAt string `protobuf:"bytes,1,opt,name=deprecated_at,json=deprecatedAt,proto3" json:"deprecated_at,omitempty"`
+	// Planned removal as an RFC 3339 timestamp; empty when not scheduled.
+	SunsetAt string `protobuf:"bytes,2,opt,name=sunset_at,json=sunsetAt,proto3" json:"sunset_at,omitempty"`
+	// API name of the object consumers should migrate to, if any.
+	Replacement   string `protobuf:"bytes,3,opt,name=replacement,proto3" json:"replacement,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ObjectDeprecation) Reset() {
+	*x = ObjectDeprecation{}
+	mi := &file_registry_v1_metadata_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ObjectDeprecation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ObjectDeprecation) ProtoMessage() {}
+
+func (x *ObjectDeprecation) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_metadata_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ObjectDeprecation.ProtoReflect.Descriptor instead.
+func (*ObjectDeprecation) Descriptor() ([]byte, []int) {
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ObjectDeprecation) GetDeprecatedAt() string {
+	if x != nil {
+		return x.DeprecatedAt
+	}
+	return ""
+}
+
+func (x *ObjectDeprecation) GetSunsetAt() string {
+	if x != nil {
+		return x.SunsetAt
+	}
+	return ""
+}
+
+func (x *ObjectDeprecation) GetReplacement() string {
+	if x != nil {
+		return x.Replacement
+	}
+	return ""
+}
+
+// ValidationWebhook is POSTed every record a Create, Update or Upsert is about
+// to commit, as {"object", "operation", "id", "record"}. It answers with
+// {"allowed": bool, "violations": [{"field", "message"}]}; a rejection fails
+// the write with INVALID_ARGUMENT and a ValidationFailed detail.
+type ValidationWebhook struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Url   string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	// 0 uses the service default (2s).
+	TimeoutMs int32 `protobuf:"varint,2,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`
+	// Accept writes when the webhook errors or times out instead of failing
+	// them with UNAVAILABLE.
+	FailOpen      bool `protobuf:"varint,3,opt,name=fail_open,json=failOpen,proto3" json:"fail_open,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidationWebhook) Reset() {
+	*x = ValidationWebhook{}
+	mi := &file_registry_v1_metadata_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidationWebhook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidationWebhook) ProtoMessage() {}
+
+func (x *ValidationWebhook) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_metadata_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidationWebhook.ProtoReflect.Descriptor instead.
+func (*ValidationWebhook) Descriptor() ([]byte, []int) {
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ValidationWebhook) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *ValidationWebhook) GetTimeoutMs() int32 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+func (x *ValidationWebhook) GetFailOpen() bool {
+	if x != nil {
+		return x.FailOpen
+	}
+	return false
+}
+
 type FieldMeta struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -178,13 +419,36 @@ type FieldMeta struct {
 	LookupObjectId string                 `protobuf:"bytes,12,opt,name=lookup_object_id,json=lookupObjectId,proto3" json:"lookup_object_id,omitempty"`
 	CreatedAt      string                 `protobuf:"bytes,13,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt      string                 `protobuf:"bytes,14,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// External key usable by RegistryService.Upsert; implies is_unique.
+	IsExternalId bool `protobuf:"varint,15,opt,name=is_external_id,json=isExternalId,proto3" json:"is_external_id,omitempty"`
+	// CHOICE/MULTICHOICE options with labels for the requested locale. Values
+	// are the stored keys and never change with the locale.
+	Options []*ChoiceOption `protobuf:"bytes,16,rep,name=options,proto3" json:"options,omitempty"`
+	// Searched with contains/starts_with/ends_with; backed by a trigram index
+	// when the server creates search indexes.
+	IsSearchable bool `protobuf:"varint,17,opt,name=is_searchable,json=isSearchable,proto3" json:"is_searchable,omitempty"`
+	// May be used in filters and HRQL where conditions.
+	IsFilterable bool `protobuf:"varint,18,opt,name=is_filterable,json=isFilterable,proto3" json:"is_filterable,omitempty"`
+	// May be used in order, sort_by and min_by/max_by.
+	IsSortable bool `protobuf:"varint,19,opt,name=is_sortable,json=isSortable,proto3" json:"is_sortable,omitempty"`
+	// contains/starts_with/ends_with ignore accents, as HRQL contains_fold
+	// does; the search index is built on the unaccented value.
+	IsAccentInsensitive bool `protobuf:"varint,20,opt,name=is_accent_insensitive,json=isAccentInsensitive,proto3" json:"is_accent_insensitive,omitempty"`
+	// De-identifies the value in record reads for callers without full access;
+	// unset when the field is unmasked.
+	Masking *FieldMasking `protobuf:"bytes,21,opt,name=masking,proto3" json:"masking,omitempty"`
+	// Holds personal data: AdminService.AnonymizeObject replaces its values
+	// with fakes. TEXT, EMAIL, URL, PHONE and DATE fields only.
+	IsPii bool `protobuf:"varint,22,opt,name=is_pii,json=isPii,proto3" json:"is_pii,omitempty"`
+	// Former api_names, oldest first (see RenameFieldRequest).
+	Aliases       []string `protobuf:"bytes,23,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *FieldMeta) Reset() {
 	*x = FieldMeta{}
-	mi := &file_registry_v1_metadata_proto_msgTypes[1]
+	mi := &file_registry_v1_metadata_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -196,7 +460,7 @@ func (x *FieldMeta) String() string {
 func (*FieldMeta) ProtoMessage() {}
 
 func (x *FieldMeta) ProtoReflect() protoreflect.Message {
-	mi := &file_registry_v1_metadata_proto_msgTypes[1]
+	mi := &file_registry_v1_metadata_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -209,7 +473,7 @@ func (x *FieldMeta) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FieldMeta.ProtoReflect.Descriptor instead.
 func (*FieldMeta) Descriptor() ([]byte, []int) {
-	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{1}
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *FieldMeta) GetId() string {
@@ -310,64 +574,100 @@ func (x *FieldMeta) GetUpdatedAt() string {
 	return ""
 }
 
-type ListObjectsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *FieldMeta) GetIsExternalId() bool {
+	if x != nil {
+		return x.IsExternalId
+	}
+	return false
 }
 
-func (x *ListObjectsRequest) Reset() {
-	*x = ListObjectsRequest{}
-	mi := &file_registry_v1_metadata_proto_msgTypes[2]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *FieldMeta) GetOptions() []*ChoiceOption {
+	if x != nil {
+		return x.Options
+	}
+	return nil
 }
 
-func (x *ListObjectsRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *FieldMeta) GetIsSearchable() bool {
+	if x != nil {
+		return x.IsSearchable
+	}
+	return false
 }
 
-func (*ListObjectsRequest) ProtoMessage() {}
+func (x *FieldMeta) GetIsFilterable() bool {
+	if x != nil {
+		return x.IsFilterable
+	}
+	return false
+}
 
-func (x *ListObjectsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_registry_v1_metadata_proto_msgTypes[2]
+func (x *FieldMeta) GetIsSortable() bool {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.IsSortable
 	}
-	return mi.MessageOf(x)
+	return false
 }
 
-// Deprecated: Use ListObjectsRequest.ProtoReflect.Descriptor instead.
-func (*ListObjectsRequest) Descriptor() ([]byte, []int) {
-	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{2}
+func (x *FieldMeta) GetIsAccentInsensitive() bool {
+	if x != nil {
+		return x.IsAccentInsensitive
+	}
+	return false
 }
 
-type ListObjectsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Objects       []*ObjectMeta          `protobuf:"bytes,1,rep,name=objects,proto3" json:"objects,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *FieldMeta) GetMasking() *FieldMasking {
+	if x != nil {
+		return x.Masking
+	}
+	return nil
 }
 
-func (x *ListObjectsResponse) Reset() {
-	*x = ListObjectsResponse{}
-	mi := &file_registry_v1_metadata_proto_msgTypes[3]
+func (x *FieldMeta) GetIsPii() bool {
+	if x != nil {
+		return x.IsPii
+	}
+	return false
+}
+
+func (x *FieldMeta) GetAliases() []string {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+// FieldMasking selects how record reads present a field's value: the first
+// rule whose permission the caller holds (X-Principal-Permissions) picks the
+// transform, and callers matching no rule get default_transform. Transforms:
+// "none" (the value as stored), "hide" (null), "last4" ("***1234"; TEXT,
+// PHONE), "email_domain" ("example.com"; EMAIL) and "year" ("1990"; DATE,
+// DATETIME). ENCRYPTED fields take any transform, applied after decryption;
+// "none" still requires pii:read there. HRQL projections and aggregates of a
+// field masked for the caller are rejected.
+type FieldMasking struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	DefaultTransform string                 `protobuf:"bytes,1,opt,name=default_transform,json=defaultTransform,proto3" json:"default_transform,omitempty"`
+	Rules            []*MaskingRule         `protobuf:"bytes,2,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *FieldMasking) Reset() {
+	*x = FieldMasking{}
+	mi := &file_registry_v1_metadata_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListObjectsResponse) String() string {
+func (x *FieldMasking) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListObjectsResponse) ProtoMessage() {}
+func (*FieldMasking) ProtoMessage() {}
 
-func (x *ListObjectsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_registry_v1_metadata_proto_msgTypes[3]
+func (x *FieldMasking) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_metadata_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -378,40 +678,48 @@ func (x *ListObjectsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListObjectsResponse.ProtoReflect.Descriptor instead.
-func (*ListObjectsResponse) Descriptor() ([]byte, []int) {
-	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use FieldMasking.ProtoReflect.Descriptor instead.
+func (*FieldMasking) Descriptor() ([]byte, []int) {
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *ListObjectsResponse) GetObjects() []*ObjectMeta {
+func (x *FieldMasking) GetDefaultTransform() string {
 	if x != nil {
-		return x.Objects
+		return x.DefaultTransform
+	}
+	return ""
+}
+
+func (x *FieldMasking) GetRules() []*MaskingRule {
+	if x != nil {
+		return x.Rules
 	}
 	return nil
 }
 
-type GetObjectRequest struct {
+type MaskingRule struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Permission    string                 `protobuf:"bytes,1,opt,name=permission,proto3" json:"permission,omitempty"`
+	Transform     string                 `protobuf:"bytes,2,opt,name=transform,proto3" json:"transform,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetObjectRequest) Reset() {
-	*x = GetObjectRequest{}
-	mi := &file_registry_v1_metadata_proto_msgTypes[4]
+func (x *MaskingRule) Reset() {
+	*x = MaskingRule{}
+	mi := &file_registry_v1_metadata_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetObjectRequest) String() string {
+func (x *MaskingRule) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetObjectRequest) ProtoMessage() {}
+func (*MaskingRule) ProtoMessage() {}
 
-func (x *GetObjectRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_registry_v1_metadata_proto_msgTypes[4]
+func (x *MaskingRule) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_metadata_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -422,40 +730,48 @@ func (x *GetObjectRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetObjectRequest.ProtoReflect.Descriptor instead.
-func (*GetObjectRequest) Descriptor() ([]byte, []int) {
-	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use MaskingRule.ProtoReflect.Descriptor instead.
+func (*MaskingRule) Descriptor() ([]byte, []int) {
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *GetObjectRequest) GetId() string {
+func (x *MaskingRule) GetPermission() string {
 	if x != nil {
-		return x.Id
+		return x.Permission
 	}
 	return ""
 }
 
-type GetObjectResponse struct {
+func (x *MaskingRule) GetTransform() string {
+	if x != nil {
+		return x.Transform
+	}
+	return ""
+}
+
+type ChoiceOption struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Object        *ObjectMeta            `protobuf:"bytes,1,opt,name=object,proto3" json:"object,omitempty"`
+	Value         string                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Label         string                 `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetObjectResponse) Reset() {
-	*x = GetObjectResponse{}
-	mi := &file_registry_v1_metadata_proto_msgTypes[5]
+func (x *ChoiceOption) Reset() {
+	*x = ChoiceOption{}
+	mi := &file_registry_v1_metadata_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetObjectResponse) String() string {
+func (x *ChoiceOption) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetObjectResponse) ProtoMessage() {}
+func (*ChoiceOption) ProtoMessage() {}
 
-func (x *GetObjectResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_registry_v1_metadata_proto_msgTypes[5]
+func (x *ChoiceOption) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_metadata_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -466,9 +782,211 @@ func (x *GetObjectResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetObjectResponse.ProtoReflect.Descriptor instead.
-func (*GetObjectResponse) Descriptor() ([]byte, []int) {
-	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use ChoiceOption.ProtoReflect.Descriptor instead.
+func (*ChoiceOption) Descriptor() ([]byte, []int) {
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ChoiceOption) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *ChoiceOption) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+type ListObjectsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Consistency   string                 `protobuf:"bytes,1,opt,name=consistency,proto3" json:"consistency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListObjectsRequest) Reset() {
+	*x = ListObjectsRequest{}
+	mi := &file_registry_v1_metadata_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListObjectsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListObjectsRequest) ProtoMessage() {}
+
+func (x *ListObjectsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_metadata_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListObjectsRequest.ProtoReflect.Descriptor instead.
+func (*ListObjectsRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListObjectsRequest) GetConsistency() string {
+	if x != nil {
+		return x.Consistency
+	}
+	return ""
+}
+
+type ListObjectsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Objects       []*ObjectMeta          `protobuf:"bytes,1,rep,name=objects,proto3" json:"objects,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListObjectsResponse) Reset() {
+	*x = ListObjectsResponse{}
+	mi := &file_registry_v1_metadata_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListObjectsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListObjectsResponse) ProtoMessage() {}
+
+func (x *ListObjectsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_metadata_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListObjectsResponse.ProtoReflect.Descriptor instead.
+func (*ListObjectsResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListObjectsResponse) GetObjects() []*ObjectMeta {
+	if x != nil {
+		return x.Objects
+	}
+	return nil
+}
+
+type GetObjectRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Consistency string                 `protobuf:"bytes,2,opt,name=consistency,proto3" json:"consistency,omitempty"`
+	// Locale for field titles and option labels, e.g. "de" or "pt-BR";
+	// defaults to the Accept-Language header. Translations live in
+	// type_config.translations.
+	Locale        string `protobuf:"bytes,3,opt,name=locale,proto3" json:"locale,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetObjectRequest) Reset() {
+	*x = GetObjectRequest{}
+	mi := &file_registry_v1_metadata_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetObjectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetObjectRequest) ProtoMessage() {}
+
+func (x *GetObjectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_metadata_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetObjectRequest.ProtoReflect.Descriptor instead.
+func (*GetObjectRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetObjectRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetObjectRequest) GetConsistency() string {
+	if x != nil {
+		return x.Consistency
+	}
+	return ""
+}
+
+func (x *GetObjectRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type GetObjectResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Object        *ObjectMeta            `protobuf:"bytes,1,opt,name=object,proto3" json:"object,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetObjectResponse) Reset() {
+	*x = GetObjectResponse{}
+	mi := &file_registry_v1_metadata_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetObjectResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetObjectResponse) ProtoMessage() {}
+
+func (x *GetObjectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_metadata_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetObjectResponse.ProtoReflect.Descriptor instead.
+func (*GetObjectResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *GetObjectResponse) GetObject() *ObjectMeta {
@@ -486,13 +1004,29 @@ type CreateObjectRequest struct {
 	Description          string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
 	CategoryId           string                 `protobuf:"bytes,5,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
 	SupportsCustomFields bool                   `protobuf:"varint,6,opt,name=supports_custom_fields,json=supportsCustomFields,proto3" json:"supports_custom_fields,omitempty"`
-	unknownFields        protoimpl.UnknownFields
-	sizeCache            protoimpl.SizeCache
+	// List defaults (see ObjectMeta). default_order may only name system
+	// fields until the object has others; set it later with UpdateObject.
+	DefaultOrder      string             `protobuf:"bytes,7,opt,name=default_order,json=defaultOrder,proto3" json:"default_order,omitempty"`
+	DefaultPageSize   int32              `protobuf:"varint,8,opt,name=default_page_size,json=defaultPageSize,proto3" json:"default_page_size,omitempty"`
+	MaxPageSize       int32              `protobuf:"varint,9,opt,name=max_page_size,json=maxPageSize,proto3" json:"max_page_size,omitempty"`
+	ValidationWebhook *ValidationWebhook `protobuf:"bytes,10,opt,name=validation_webhook,json=validationWebhook,proto3" json:"validation_webhook,omitempty"`
+	// Display template (see ObjectMeta); like default_order it may only name
+	// system fields until the object has others.
+	DisplayTemplate string `protobuf:"bytes,11,opt,name=display_template,json=displayTemplate,proto3" json:"display_template,omitempty"`
+	// Data limits (see ObjectMeta); 0 uses the server default.
+	MaxCustomFields int32 `protobuf:"varint,12,opt,name=max_custom_fields,json=maxCustomFields,proto3" json:"max_custom_fields,omitempty"`
+	MaxDataBytes    int32 `protobuf:"varint,13,opt,name=max_data_bytes,json=maxDataBytes,proto3" json:"max_data_bytes,omitempty"`
+	// Exact count threshold (see ObjectMeta); 0 uses the server default.
+	ExactCountThreshold int64 `protobuf:"varint,14,opt,name=exact_count_threshold,json=exactCountThreshold,proto3" json:"exact_count_threshold,omitempty"`
+	// Response caching (see ObjectMeta); 0 disables it.
+	CacheMaxAgeSeconds int32 `protobuf:"varint,15,opt,name=cache_max_age_seconds,json=cacheMaxAgeSeconds,proto3" json:"cache_max_age_seconds,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *CreateObjectRequest) Reset() {
 	*x = CreateObjectRequest{}
-	mi := &file_registry_v1_metadata_proto_msgTypes[6]
+	mi := &file_registry_v1_metadata_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -504,7 +1038,7 @@ func (x *CreateObjectRequest) String() string {
 func (*CreateObjectRequest) ProtoMessage() {}
 
 func (x *CreateObjectRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_registry_v1_metadata_proto_msgTypes[6]
+	mi := &file_registry_v1_metadata_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -517,7 +1051,7 @@ func (x *CreateObjectRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateObjectRequest.ProtoReflect.Descriptor instead.
 func (*CreateObjectRequest) Descriptor() ([]byte, []int) {
-	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{6}
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *CreateObjectRequest) GetApiName() string {
@@ -562,6 +1096,69 @@ func (x *CreateObjectRequest) GetSupportsCustomFields() bool {
 	return false
 }
 
+func (x *CreateObjectRequest) GetDefaultOrder() string {
+	if x != nil {
+		return x.DefaultOrder
+	}
+	return ""
+}
+
+func (x *CreateObjectRequest) GetDefaultPageSize() int32 {
+	if x != nil {
+		return x.DefaultPageSize
+	}
+	return 0
+}
+
+func (x *CreateObjectRequest) GetMaxPageSize() int32 {
+	if x != nil {
+		return x.MaxPageSize
+	}
+	return 0
+}
+
+func (x *CreateObjectRequest) GetValidationWebhook() *ValidationWebhook {
+	if x != nil {
+		return x.ValidationWebhook
+	}
+	return nil
+}
+
+func (x *CreateObjectRequest) GetDisplayTemplate() string {
+	if x != nil {
+		return x.DisplayTemplate
+	}
+	return ""
+}
+
+func (x *CreateObjectRequest) GetMaxCustomFields() int32 {
+	if x != nil {
+		return x.MaxCustomFields
+	}
+	return 0
+}
+
+func (x *CreateObjectRequest) GetMaxDataBytes() int32 {
+	if x != nil {
+		return x.MaxDataBytes
+	}
+	return 0
+}
+
+func (x *CreateObjectRequest) GetExactCountThreshold() int64 {
+	if x != nil {
+		return x.ExactCountThreshold
+	}
+	return 0
+}
+
+func (x *CreateObjectRequest) GetCacheMaxAgeSeconds() int32 {
+	if x != nil {
+		return x.CacheMaxAgeSeconds
+	}
+	return 0
+}
+
 type CreateObjectResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Object        *ObjectMeta            `protobuf:"bytes,1,opt,name=object,proto3" json:"object,omitempty"`
@@ -571,7 +1168,7 @@ type CreateObjectResponse struct {
 
 func (x *CreateObjectResponse) Reset() {
 	*x = CreateObjectResponse{}
-	mi := &file_registry_v1_metadata_proto_msgTypes[7]
+	mi := &file_registry_v1_metadata_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -583,7 +1180,7 @@ func (x *CreateObjectResponse) String() string {
 func (*CreateObjectResponse) ProtoMessage() {}
 
 func (x *CreateObjectResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_registry_v1_metadata_proto_msgTypes[7]
+	mi := &file_registry_v1_metadata_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -596,7 +1193,7 @@ func (x *CreateObjectResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateObjectResponse.ProtoReflect.Descriptor instead.
 func (*CreateObjectResponse) Descriptor() ([]byte, []int) {
-	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{7}
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *CreateObjectResponse) GetObject() *ObjectMeta {
@@ -614,25 +1211,475 @@ type UpdateObjectRequest struct {
 	Description          string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
 	CategoryId           string                 `protobuf:"bytes,5,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
 	SupportsCustomFields bool                   `protobuf:"varint,6,opt,name=supports_custom_fields,json=supportsCustomFields,proto3" json:"supports_custom_fields,omitempty"`
-	unknownFields        protoimpl.UnknownFields
-	sizeCache            protoimpl.SizeCache
+	// List defaults (see ObjectMeta); unset keeps the current value, ""/0 clears it.
+	DefaultOrder    *string `protobuf:"bytes,7,opt,name=default_order,json=defaultOrder,proto3,oneof" json:"default_order,omitempty"`
+	DefaultPageSize *int32  `protobuf:"varint,8,opt,name=default_page_size,json=defaultPageSize,proto3,oneof" json:"default_page_size,omitempty"`
+	MaxPageSize     *int32  `protobuf:"varint,9,opt,name=max_page_size,json=maxPageSize,proto3,oneof" json:"max_page_size,omitempty"`
+	// Unset keeps the current webhook; set clear_validation_webhook to remove it.
+	ValidationWebhook      *ValidationWebhook `protobuf:"bytes,10,opt,name=validation_webhook,json=validationWebhook,proto3" json:"validation_webhook,omitempty"`
+	ClearValidationWebhook bool               `protobuf:"varint,11,opt,name=clear_validation_webhook,json=clearValidationWebhook,proto3" json:"clear_validation_webhook,omitempty"`
+	// Unset keeps the current template, "" clears it.
+	DisplayTemplate *string `protobuf:"bytes,12,opt,name=display_template,json=displayTemplate,proto3,oneof" json:"display_template,omitempty"`
+	// Unset keeps the current notice; set clear_deprecation to undeprecate.
+	Deprecation      *ObjectDeprecation `protobuf:"bytes,13,opt,name=deprecation,proto3" json:"deprecation,omitempty"`
+	ClearDeprecation bool               `protobuf:"varint,14,opt,name=clear_deprecation,json=clearDeprecation,proto3" json:"clear_deprecation,omitempty"`
+	// Data limits (see ObjectMeta); unset keeps the current value, 0 restores
+	// the server default.
+	MaxCustomFields *int32 `protobuf:"varint,15,opt,name=max_custom_fields,json=maxCustomFields,proto3,oneof" json:"max_custom_fields,omitempty"`
+	MaxDataBytes    *int32 `protobuf:"varint,16,opt,name=max_data_bytes,json=maxDataBytes,proto3,oneof" json:"max_data_bytes,omitempty"`
+	// Exact count threshold (see ObjectMeta); unset keeps the current value, 0
+	// restores the server default.
+	ExactCountThreshold *int64 `protobuf:"varint,17,opt,name=exact_count_threshold,json=exactCountThreshold,proto3,oneof" json:"exact_count_threshold,omitempty"`
+	// Response caching (see ObjectMeta); unset keeps the current value, 0
+	// disables it.
+	CacheMaxAgeSeconds *int32 `protobuf:"varint,18,opt,name=cache_max_age_seconds,json=cacheMaxAgeSeconds,proto3,oneof" json:"cache_max_age_seconds,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *UpdateObjectRequest) Reset() {
+	*x = UpdateObjectRequest{}
+	mi := &file_registry_v1_metadata_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateObjectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateObjectRequest) ProtoMessage() {}
+
+func (x *UpdateObjectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_metadata_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateObjectRequest.ProtoReflect.Descriptor instead.
+func (*UpdateObjectRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UpdateObjectRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateObjectRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *UpdateObjectRequest) GetPluralTitle() string {
+	if x != nil {
+		return x.PluralTitle
+	}
+	return ""
+}
+
+func (x *UpdateObjectRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *UpdateObjectRequest) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+func (x *UpdateObjectRequest) GetSupportsCustomFields() bool {
+	if x != nil {
+		return x.SupportsCustomFields
+	}
+	return false
+}
+
+func (x *UpdateObjectRequest) GetDefaultOrder() string {
+	if x != nil && x.DefaultOrder != nil {
+		return *x.DefaultOrder
+	}
+	return ""
+}
+
+func (x *UpdateObjectRequest) GetDefaultPageSize() int32 {
+	if x != nil && x.DefaultPageSize != nil {
+		return *x.DefaultPageSize
+	}
+	return 0
+}
+
+func (x *UpdateObjectRequest) GetMaxPageSize() int32 {
+	if x != nil && x.MaxPageSize != nil {
+		return *x.MaxPageSize
+	}
+	return 0
+}
+
+func (x *UpdateObjectRequest) GetValidationWebhook() *ValidationWebhook {
+	if x != nil {
+		return x.ValidationWebhook
+	}
+	return nil
+}
+
+func (x *UpdateObjectRequest) GetClearValidationWebhook() bool {
+	if x != nil {
+		return x.ClearValidationWebhook
+	}
+	return false
+}
+
+func (x *UpdateObjectRequest) GetDisplayTemplate() string {
+	if x != nil && x.DisplayTemplate != nil {
+		return *x.DisplayTemplate
+	}
+	return ""
+}
+
+func (x *UpdateObjectRequest) GetDeprecation() *ObjectDeprecation {
+	if x != nil {
+		return x.Deprecation
+	}
+	return nil
+}
+
+func (x *UpdateObjectRequest) GetClearDeprecation() bool {
+	if x != nil {
+		return x.ClearDeprecation
+	}
+	return false
+}
+
+func (x *UpdateObjectRequest) GetMaxCustomFields() int32 {
+	if x != nil && x.MaxCustomFields != nil {
+		return *x.MaxCustomFields
+	}
+	return 0
+}
+
+func (x *UpdateObjectRequest) GetMaxDataBytes() int32 {
+	if x != nil && x.MaxDataBytes != nil {
+		return *x.MaxDataBytes
+	}
+	return 0
+}
+
+func (x *UpdateObjectRequest) GetExactCountThreshold() int64 {
+	if x != nil && x.ExactCountThreshold != nil {
+		return *x.ExactCountThreshold
+	}
+	return 0
+}
+
+func (x *UpdateObjectRequest) GetCacheMaxAgeSeconds() int32 {
+	if x != nil && x.CacheMaxAgeSeconds != nil {
+		return *x.CacheMaxAgeSeconds
+	}
+	return 0
+}
+
+type UpdateObjectResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Object        *ObjectMeta            `protobuf:"bytes,1,opt,name=object,proto3" json:"object,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateObjectResponse) Reset() {
+	*x = UpdateObjectResponse{}
+	mi := &file_registry_v1_metadata_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateObjectResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateObjectResponse) ProtoMessage() {}
+
+func (x *UpdateObjectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_metadata_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateObjectResponse.ProtoReflect.Descriptor instead.
+func (*UpdateObjectResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *UpdateObjectResponse) GetObject() *ObjectMeta {
+	if x != nil {
+		return x.Object
+	}
+	return nil
+}
+
+// DeleteObjectRequest deletes an object with its records. LOOKUP fields on
+// other objects that reference it block the delete (FAILED_PRECONDITION)
+// unless cascade is "nullify"; PreviewDelete lists them.
+type DeleteObjectRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// "block" (the default) or "nullify": clear the referencing fields on
+	// every record and drop them before the object. Standard fields cannot be
+	// dropped and block either way.
+	Cascade       string `protobuf:"bytes,2,opt,name=cascade,proto3" json:"cascade,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteObjectRequest) Reset() {
+	*x = DeleteObjectRequest{}
+	mi := &file_registry_v1_metadata_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteObjectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteObjectRequest) ProtoMessage() {}
+
+func (x *DeleteObjectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_metadata_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteObjectRequest.ProtoReflect.Descriptor instead.
+func (*DeleteObjectRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DeleteObjectRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteObjectRequest) GetCascade() string {
+	if x != nil {
+		return x.Cascade
+	}
+	return ""
+}
+
+type DeleteObjectResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteObjectResponse) Reset() {
+	*x = DeleteObjectResponse{}
+	mi := &file_registry_v1_metadata_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteObjectResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteObjectResponse) ProtoMessage() {}
+
+func (x *DeleteObjectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_metadata_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteObjectResponse.ProtoReflect.Descriptor instead.
+func (*DeleteObjectResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{16}
+}
+
+// PreviewDeleteRequest lists what DeleteObject would affect, in the order it
+// is handled, without changing anything.
+type PreviewDeleteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PreviewDeleteRequest) Reset() {
+	*x = PreviewDeleteRequest{}
+	mi := &file_registry_v1_metadata_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PreviewDeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PreviewDeleteRequest) ProtoMessage() {}
+
+func (x *PreviewDeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_metadata_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PreviewDeleteRequest.ProtoReflect.Descriptor instead.
+func (*PreviewDeleteRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PreviewDeleteRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type PreviewDeleteResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	ApiName string                 `protobuf:"bytes,1,opt,name=api_name,json=apiName,proto3" json:"api_name,omitempty"`
+	// LOOKUP fields on other objects referencing this one, handled first;
+	// ordered by object and field api_name. Any of them blocks cascade
+	// "block"; a standard one blocks "nullify" too.
+	References []*DeleteReference `protobuf:"bytes,2,rep,name=references,proto3" json:"references,omitempty"`
+	// Records deleted with the object.
+	Records int64 `protobuf:"varint,3,opt,name=records,proto3" json:"records,omitempty"`
+	// The object's validation webhook, removed with it; empty when none.
+	ValidationWebhookUrl string `protobuf:"bytes,4,opt,name=validation_webhook_url,json=validationWebhookUrl,proto3" json:"validation_webhook_url,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *PreviewDeleteResponse) Reset() {
+	*x = PreviewDeleteResponse{}
+	mi := &file_registry_v1_metadata_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PreviewDeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PreviewDeleteResponse) ProtoMessage() {}
+
+func (x *PreviewDeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_metadata_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PreviewDeleteResponse.ProtoReflect.Descriptor instead.
+func (*PreviewDeleteResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PreviewDeleteResponse) GetApiName() string {
+	if x != nil {
+		return x.ApiName
+	}
+	return ""
+}
+
+func (x *PreviewDeleteResponse) GetReferences() []*DeleteReference {
+	if x != nil {
+		return x.References
+	}
+	return nil
+}
+
+func (x *PreviewDeleteResponse) GetRecords() int64 {
+	if x != nil {
+		return x.Records
+	}
+	return 0
+}
+
+func (x *PreviewDeleteResponse) GetValidationWebhookUrl() string {
+	if x != nil {
+		return x.ValidationWebhookUrl
+	}
+	return ""
 }
 
-func (x *UpdateObjectRequest) Reset() {
-	*x = UpdateObjectRequest{}
-	mi := &file_registry_v1_metadata_proto_msgTypes[8]
+// DeleteReference is a LOOKUP field pointing at an object being deleted.
+type DeleteReference struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ObjectId      string                 `protobuf:"bytes,1,opt,name=object_id,json=objectId,proto3" json:"object_id,omitempty"`
+	ObjectApiName string                 `protobuf:"bytes,2,opt,name=object_api_name,json=objectApiName,proto3" json:"object_api_name,omitempty"`
+	FieldId       string                 `protobuf:"bytes,3,opt,name=field_id,json=fieldId,proto3" json:"field_id,omitempty"`
+	FieldApiName  string                 `protobuf:"bytes,4,opt,name=field_api_name,json=fieldApiName,proto3" json:"field_api_name,omitempty"`
+	// Records setting the field, cleared by cascade "nullify".
+	Records int64 `protobuf:"varint,5,opt,name=records,proto3" json:"records,omitempty"`
+	// Standard fields cannot be dropped, so they block every cascade.
+	IsStandard    bool `protobuf:"varint,6,opt,name=is_standard,json=isStandard,proto3" json:"is_standard,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteReference) Reset() {
+	*x = DeleteReference{}
+	mi := &file_registry_v1_metadata_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateObjectRequest) String() string {
+func (x *DeleteReference) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateObjectRequest) ProtoMessage() {}
+func (*DeleteReference) ProtoMessage() {}
 
-func (x *UpdateObjectRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_registry_v1_metadata_proto_msgTypes[8]
+func (x *DeleteReference) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_metadata_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -643,75 +1690,133 @@ func (x *UpdateObjectRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateObjectRequest.ProtoReflect.Descriptor instead.
-func (*UpdateObjectRequest) Descriptor() ([]byte, []int) {
-	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use DeleteReference.ProtoReflect.Descriptor instead.
+func (*DeleteReference) Descriptor() ([]byte, []int) {
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *UpdateObjectRequest) GetId() string {
+func (x *DeleteReference) GetObjectId() string {
 	if x != nil {
-		return x.Id
+		return x.ObjectId
 	}
 	return ""
 }
 
-func (x *UpdateObjectRequest) GetTitle() string {
+func (x *DeleteReference) GetObjectApiName() string {
 	if x != nil {
-		return x.Title
+		return x.ObjectApiName
 	}
 	return ""
 }
 
-func (x *UpdateObjectRequest) GetPluralTitle() string {
+func (x *DeleteReference) GetFieldId() string {
 	if x != nil {
-		return x.PluralTitle
+		return x.FieldId
 	}
 	return ""
 }
 
-func (x *UpdateObjectRequest) GetDescription() string {
+func (x *DeleteReference) GetFieldApiName() string {
 	if x != nil {
-		return x.Description
+		return x.FieldApiName
 	}
 	return ""
 }
 
-func (x *UpdateObjectRequest) GetCategoryId() string {
+func (x *DeleteReference) GetRecords() int64 {
 	if x != nil {
-		return x.CategoryId
+		return x.Records
 	}
-	return ""
+	return 0
 }
 
-func (x *UpdateObjectRequest) GetSupportsCustomFields() bool {
+func (x *DeleteReference) GetIsStandard() bool {
 	if x != nil {
-		return x.SupportsCustomFields
+		return x.IsStandard
 	}
 	return false
 }
 
-type UpdateObjectResponse struct {
+// RenameObjectRequest changes an object's api_name. The current name becomes
+// an alias: registry and HRQL calls using it keep working, and RegistryService
+// List and Get through it answer with a Deprecation header, a
+// successor-version Link to the new name and a warning. Deprecation
+// replacements naming the object are updated. Standard objects cannot be
+// renamed.
+type RenameObjectRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ApiName       string                 `protobuf:"bytes,2,opt,name=api_name,json=apiName,proto3" json:"api_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameObjectRequest) Reset() {
+	*x = RenameObjectRequest{}
+	mi := &file_registry_v1_metadata_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameObjectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameObjectRequest) ProtoMessage() {}
+
+func (x *RenameObjectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_metadata_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameObjectRequest.ProtoReflect.Descriptor instead.
+func (*RenameObjectRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RenameObjectRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RenameObjectRequest) GetApiName() string {
+	if x != nil {
+		return x.ApiName
+	}
+	return ""
+}
+
+type RenameObjectResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Object        *ObjectMeta            `protobuf:"bytes,1,opt,name=object,proto3" json:"object,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateObjectResponse) Reset() {
-	*x = UpdateObjectResponse{}
-	mi := &file_registry_v1_metadata_proto_msgTypes[9]
+func (x *RenameObjectResponse) Reset() {
+	*x = RenameObjectResponse{}
+	mi := &file_registry_v1_metadata_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateObjectResponse) String() string {
+func (x *RenameObjectResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateObjectResponse) ProtoMessage() {}
+func (*RenameObjectResponse) ProtoMessage() {}
 
-func (x *UpdateObjectResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_registry_v1_metadata_proto_msgTypes[9]
+func (x *RenameObjectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_metadata_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -722,40 +1827,45 @@ func (x *UpdateObjectResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateObjectResponse.ProtoReflect.Descriptor instead.
-func (*UpdateObjectResponse) Descriptor() ([]byte, []int) {
-	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use RenameObjectResponse.ProtoReflect.Descriptor instead.
+func (*RenameObjectResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *UpdateObjectResponse) GetObject() *ObjectMeta {
+func (x *RenameObjectResponse) GetObject() *ObjectMeta {
 	if x != nil {
 		return x.Object
 	}
 	return nil
 }
 
-type DeleteObjectRequest struct {
+// GetObjectUsageRequest reports an object's use of its data limits. Custom
+// fields beyond max_custom_fields are rejected by CreateField with
+// RESOURCE_EXHAUSTED, and registry writes leaving a record's document over
+// max_data_bytes with INVALID_ARGUMENT. Finding the largest document reads
+// every record of the object.
+type GetObjectUsageRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteObjectRequest) Reset() {
-	*x = DeleteObjectRequest{}
-	mi := &file_registry_v1_metadata_proto_msgTypes[10]
+func (x *GetObjectUsageRequest) Reset() {
+	*x = GetObjectUsageRequest{}
+	mi := &file_registry_v1_metadata_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteObjectRequest) String() string {
+func (x *GetObjectUsageRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteObjectRequest) ProtoMessage() {}
+func (*GetObjectUsageRequest) ProtoMessage() {}
 
-func (x *DeleteObjectRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_registry_v1_metadata_proto_msgTypes[10]
+func (x *GetObjectUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_metadata_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -766,39 +1876,48 @@ func (x *DeleteObjectRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteObjectRequest.ProtoReflect.Descriptor instead.
-func (*DeleteObjectRequest) Descriptor() ([]byte, []int) {
-	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use GetObjectUsageRequest.ProtoReflect.Descriptor instead.
+func (*GetObjectUsageRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *DeleteObjectRequest) GetId() string {
+func (x *GetObjectUsageRequest) GetId() string {
 	if x != nil {
 		return x.Id
 	}
 	return ""
 }
 
-type DeleteObjectResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+type GetObjectUsageResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	CustomFields int32                  `protobuf:"varint,1,opt,name=custom_fields,json=customFields,proto3" json:"custom_fields,omitempty"`
+	// Effective limits: the object's own, else the server's; 0 is unlimited.
+	MaxCustomFields int32 `protobuf:"varint,2,opt,name=max_custom_fields,json=maxCustomFields,proto3" json:"max_custom_fields,omitempty"`
+	Records         int64 `protobuf:"varint,3,opt,name=records,proto3" json:"records,omitempty"`
+	// Size of the object's largest record document, in bytes of JSON text.
+	LargestDataBytes int64 `protobuf:"varint,4,opt,name=largest_data_bytes,json=largestDataBytes,proto3" json:"largest_data_bytes,omitempty"`
+	MaxDataBytes     int32 `protobuf:"varint,5,opt,name=max_data_bytes,json=maxDataBytes,proto3" json:"max_data_bytes,omitempty"`
+	// One per limit the object has reached 80% of.
+	Warnings      []string `protobuf:"bytes,6,rep,name=warnings,proto3" json:"warnings,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteObjectResponse) Reset() {
-	*x = DeleteObjectResponse{}
-	mi := &file_registry_v1_metadata_proto_msgTypes[11]
+func (x *GetObjectUsageResponse) Reset() {
+	*x = GetObjectUsageResponse{}
+	mi := &file_registry_v1_metadata_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteObjectResponse) String() string {
+func (x *GetObjectUsageResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteObjectResponse) ProtoMessage() {}
+func (*GetObjectUsageResponse) ProtoMessage() {}
 
-func (x *DeleteObjectResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_registry_v1_metadata_proto_msgTypes[11]
+func (x *GetObjectUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_metadata_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -809,21 +1928,74 @@ func (x *DeleteObjectResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteObjectResponse.ProtoReflect.Descriptor instead.
-func (*DeleteObjectResponse) Descriptor() ([]byte, []int) {
-	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use GetObjectUsageResponse.ProtoReflect.Descriptor instead.
+func (*GetObjectUsageResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetObjectUsageResponse) GetCustomFields() int32 {
+	if x != nil {
+		return x.CustomFields
+	}
+	return 0
+}
+
+func (x *GetObjectUsageResponse) GetMaxCustomFields() int32 {
+	if x != nil {
+		return x.MaxCustomFields
+	}
+	return 0
+}
+
+func (x *GetObjectUsageResponse) GetRecords() int64 {
+	if x != nil {
+		return x.Records
+	}
+	return 0
+}
+
+func (x *GetObjectUsageResponse) GetLargestDataBytes() int64 {
+	if x != nil {
+		return x.LargestDataBytes
+	}
+	return 0
+}
+
+func (x *GetObjectUsageResponse) GetMaxDataBytes() int32 {
+	if x != nil {
+		return x.MaxDataBytes
+	}
+	return 0
+}
+
+func (x *GetObjectUsageResponse) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
 }
 
 type ListFieldsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ObjectId      string                 `protobuf:"bytes,1,opt,name=object_id,json=objectId,proto3" json:"object_id,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	ObjectId    string                 `protobuf:"bytes,1,opt,name=object_id,json=objectId,proto3" json:"object_id,omitempty"`
+	Consistency string                 `protobuf:"bytes,2,opt,name=consistency,proto3" json:"consistency,omitempty"`
+	// Sort key: "created_at" (default), "api_name" or "title"; append ".desc" to reverse.
+	OrderBy string `protobuf:"bytes,3,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	// Maximum fields per page (at most 500). 0 returns all fields.
+	PageSize int32 `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token from a previous response, with the same order_by.
+	PageToken string `protobuf:"bytes,5,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Locale for field titles and option labels, e.g. "de" or "pt-BR";
+	// defaults to the Accept-Language header. Translations live in
+	// type_config.translations.
+	Locale        string `protobuf:"bytes,6,opt,name=locale,proto3" json:"locale,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListFieldsRequest) Reset() {
 	*x = ListFieldsRequest{}
-	mi := &file_registry_v1_metadata_proto_msgTypes[12]
+	mi := &file_registry_v1_metadata_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -835,7 +2007,7 @@ func (x *ListFieldsRequest) String() string {
 func (*ListFieldsRequest) ProtoMessage() {}
 
 func (x *ListFieldsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_registry_v1_metadata_proto_msgTypes[12]
+	mi := &file_registry_v1_metadata_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -848,7 +2020,7 @@ func (x *ListFieldsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListFieldsRequest.ProtoReflect.Descriptor instead.
 func (*ListFieldsRequest) Descriptor() ([]byte, []int) {
-	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{12}
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *ListFieldsRequest) GetObjectId() string {
@@ -858,16 +2030,55 @@ func (x *ListFieldsRequest) GetObjectId() string {
 	return ""
 }
 
+func (x *ListFieldsRequest) GetConsistency() string {
+	if x != nil {
+		return x.Consistency
+	}
+	return ""
+}
+
+func (x *ListFieldsRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+func (x *ListFieldsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListFieldsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListFieldsRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
 type ListFieldsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Fields        []*FieldMeta           `protobuf:"bytes,1,rep,name=fields,proto3" json:"fields,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Fields []*FieldMeta           `protobuf:"bytes,1,rep,name=fields,proto3" json:"fields,omitempty"`
+	// Token for the next page; empty on the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// Number of fields on the object.
+	TotalSize     int32 `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListFieldsResponse) Reset() {
 	*x = ListFieldsResponse{}
-	mi := &file_registry_v1_metadata_proto_msgTypes[13]
+	mi := &file_registry_v1_metadata_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -879,7 +2090,7 @@ func (x *ListFieldsResponse) String() string {
 func (*ListFieldsResponse) ProtoMessage() {}
 
 func (x *ListFieldsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_registry_v1_metadata_proto_msgTypes[13]
+	mi := &file_registry_v1_metadata_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -892,7 +2103,7 @@ func (x *ListFieldsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListFieldsResponse.ProtoReflect.Descriptor instead.
 func (*ListFieldsResponse) Descriptor() ([]byte, []int) {
-	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{13}
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *ListFieldsResponse) GetFields() []*FieldMeta {
@@ -902,17 +2113,36 @@ func (x *ListFieldsResponse) GetFields() []*FieldMeta {
 	return nil
 }
 
+func (x *ListFieldsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListFieldsResponse) GetTotalSize() int32 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
 type GetFieldRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ObjectId      string                 `protobuf:"bytes,1,opt,name=object_id,json=objectId,proto3" json:"object_id,omitempty"`
-	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	ObjectId    string                 `protobuf:"bytes,1,opt,name=object_id,json=objectId,proto3" json:"object_id,omitempty"`
+	Id          string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Consistency string                 `protobuf:"bytes,3,opt,name=consistency,proto3" json:"consistency,omitempty"`
+	// Locale for field titles and option labels, e.g. "de" or "pt-BR";
+	// defaults to the Accept-Language header. Translations live in
+	// type_config.translations.
+	Locale        string `protobuf:"bytes,4,opt,name=locale,proto3" json:"locale,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetFieldRequest) Reset() {
 	*x = GetFieldRequest{}
-	mi := &file_registry_v1_metadata_proto_msgTypes[14]
+	mi := &file_registry_v1_metadata_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -924,7 +2154,7 @@ func (x *GetFieldRequest) String() string {
 func (*GetFieldRequest) ProtoMessage() {}
 
 func (x *GetFieldRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_registry_v1_metadata_proto_msgTypes[14]
+	mi := &file_registry_v1_metadata_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -937,7 +2167,7 @@ func (x *GetFieldRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetFieldRequest.ProtoReflect.Descriptor instead.
 func (*GetFieldRequest) Descriptor() ([]byte, []int) {
-	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{14}
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *GetFieldRequest) GetObjectId() string {
@@ -954,6 +2184,20 @@ func (x *GetFieldRequest) GetId() string {
 	return ""
 }
 
+func (x *GetFieldRequest) GetConsistency() string {
+	if x != nil {
+		return x.Consistency
+	}
+	return ""
+}
+
+func (x *GetFieldRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
 type GetFieldResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Field         *FieldMeta             `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
@@ -963,7 +2207,7 @@ type GetFieldResponse struct {
 
 func (x *GetFieldResponse) Reset() {
 	*x = GetFieldResponse{}
-	mi := &file_registry_v1_metadata_proto_msgTypes[15]
+	mi := &file_registry_v1_metadata_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -975,7 +2219,7 @@ func (x *GetFieldResponse) String() string {
 func (*GetFieldResponse) ProtoMessage() {}
 
 func (x *GetFieldResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_registry_v1_metadata_proto_msgTypes[15]
+	mi := &file_registry_v1_metadata_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -988,7 +2232,7 @@ func (x *GetFieldResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetFieldResponse.ProtoReflect.Descriptor instead.
 func (*GetFieldResponse) Descriptor() ([]byte, []int) {
-	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{15}
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *GetFieldResponse) GetField() *FieldMeta {
@@ -1009,13 +2253,29 @@ type CreateFieldRequest struct {
 	IsRequired     bool                   `protobuf:"varint,7,opt,name=is_required,json=isRequired,proto3" json:"is_required,omitempty"`
 	IsUnique       bool                   `protobuf:"varint,8,opt,name=is_unique,json=isUnique,proto3" json:"is_unique,omitempty"`
 	LookupObjectId string                 `protobuf:"bytes,9,opt,name=lookup_object_id,json=lookupObjectId,proto3" json:"lookup_object_id,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// Flag the field as an external key (see FieldMeta.is_external_id).
+	IsExternalId bool `protobuf:"varint,10,opt,name=is_external_id,json=isExternalId,proto3" json:"is_external_id,omitempty"`
+	// Flag the field for string search (see FieldMeta.is_searchable).
+	IsSearchable bool `protobuf:"varint,11,opt,name=is_searchable,json=isSearchable,proto3" json:"is_searchable,omitempty"`
+	// Allow filtering on the field (see FieldMeta.is_filterable); true when
+	// absent, except for JSON fields, which only filter by is.null/is.not_null.
+	IsFilterable *bool `protobuf:"varint,12,opt,name=is_filterable,json=isFilterable,proto3,oneof" json:"is_filterable,omitempty"`
+	// Allow sorting by the field (see FieldMeta.is_sortable); true when absent,
+	// except for JSON fields, which cannot be sorted.
+	IsSortable *bool `protobuf:"varint,13,opt,name=is_sortable,json=isSortable,proto3,oneof" json:"is_sortable,omitempty"`
+	// Match the field ignoring accents (see FieldMeta.is_accent_insensitive).
+	IsAccentInsensitive bool `protobuf:"varint,14,opt,name=is_accent_insensitive,json=isAccentInsensitive,proto3" json:"is_accent_insensitive,omitempty"`
+	// Mask the field in record reads (see FieldMasking).
+	Masking *FieldMasking `protobuf:"bytes,15,opt,name=masking,proto3" json:"masking,omitempty"`
+	// Flag the field as personal data (see FieldMeta.is_pii).
+	IsPii         bool `protobuf:"varint,16,opt,name=is_pii,json=isPii,proto3" json:"is_pii,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateFieldRequest) Reset() {
 	*x = CreateFieldRequest{}
-	mi := &file_registry_v1_metadata_proto_msgTypes[16]
+	mi := &file_registry_v1_metadata_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1027,7 +2287,7 @@ func (x *CreateFieldRequest) String() string {
 func (*CreateFieldRequest) ProtoMessage() {}
 
 func (x *CreateFieldRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_registry_v1_metadata_proto_msgTypes[16]
+	mi := &file_registry_v1_metadata_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1040,7 +2300,7 @@ func (x *CreateFieldRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateFieldRequest.ProtoReflect.Descriptor instead.
 func (*CreateFieldRequest) Descriptor() ([]byte, []int) {
-	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{16}
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *CreateFieldRequest) GetObjectId() string {
@@ -1052,70 +2312,121 @@ func (x *CreateFieldRequest) GetObjectId() string {
 
 func (x *CreateFieldRequest) GetApiName() string {
 	if x != nil {
-		return x.ApiName
+		return x.ApiName
+	}
+	return ""
+}
+
+func (x *CreateFieldRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateFieldRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateFieldRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *CreateFieldRequest) GetTypeConfig() string {
+	if x != nil {
+		return x.TypeConfig
+	}
+	return ""
+}
+
+func (x *CreateFieldRequest) GetIsRequired() bool {
+	if x != nil {
+		return x.IsRequired
+	}
+	return false
+}
+
+func (x *CreateFieldRequest) GetIsUnique() bool {
+	if x != nil {
+		return x.IsUnique
+	}
+	return false
+}
+
+func (x *CreateFieldRequest) GetLookupObjectId() string {
+	if x != nil {
+		return x.LookupObjectId
 	}
 	return ""
 }
 
-func (x *CreateFieldRequest) GetTitle() string {
+func (x *CreateFieldRequest) GetIsExternalId() bool {
 	if x != nil {
-		return x.Title
+		return x.IsExternalId
 	}
-	return ""
+	return false
 }
 
-func (x *CreateFieldRequest) GetDescription() string {
+func (x *CreateFieldRequest) GetIsSearchable() bool {
 	if x != nil {
-		return x.Description
+		return x.IsSearchable
 	}
-	return ""
+	return false
 }
 
-func (x *CreateFieldRequest) GetType() string {
-	if x != nil {
-		return x.Type
+func (x *CreateFieldRequest) GetIsFilterable() bool {
+	if x != nil && x.IsFilterable != nil {
+		return *x.IsFilterable
 	}
-	return ""
+	return false
 }
 
-func (x *CreateFieldRequest) GetTypeConfig() string {
-	if x != nil {
-		return x.TypeConfig
+func (x *CreateFieldRequest) GetIsSortable() bool {
+	if x != nil && x.IsSortable != nil {
+		return *x.IsSortable
 	}
-	return ""
+	return false
 }
 
-func (x *CreateFieldRequest) GetIsRequired() bool {
+func (x *CreateFieldRequest) GetIsAccentInsensitive() bool {
 	if x != nil {
-		return x.IsRequired
+		return x.IsAccentInsensitive
 	}
 	return false
 }
 
-func (x *CreateFieldRequest) GetIsUnique() bool {
+func (x *CreateFieldRequest) GetMasking() *FieldMasking {
 	if x != nil {
-		return x.IsUnique
+		return x.Masking
 	}
-	return false
+	return nil
 }
 
-func (x *CreateFieldRequest) GetLookupObjectId() string {
+func (x *CreateFieldRequest) GetIsPii() bool {
 	if x != nil {
-		return x.LookupObjectId
+		return x.IsPii
 	}
-	return ""
+	return false
 }
 
 type CreateFieldResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Field         *FieldMeta             `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Field *FieldMeta             `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	// Set when the object has reached 80% of its custom field limit.
+	Warning       string `protobuf:"bytes,2,opt,name=warning,proto3" json:"warning,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateFieldResponse) Reset() {
 	*x = CreateFieldResponse{}
-	mi := &file_registry_v1_metadata_proto_msgTypes[17]
+	mi := &file_registry_v1_metadata_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1127,7 +2438,7 @@ func (x *CreateFieldResponse) String() string {
 func (*CreateFieldResponse) ProtoMessage() {}
 
 func (x *CreateFieldResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_registry_v1_metadata_proto_msgTypes[17]
+	mi := &file_registry_v1_metadata_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1140,7 +2451,7 @@ func (x *CreateFieldResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateFieldResponse.ProtoReflect.Descriptor instead.
 func (*CreateFieldResponse) Descriptor() ([]byte, []int) {
-	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{17}
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *CreateFieldResponse) GetField() *FieldMeta {
@@ -1150,22 +2461,43 @@ func (x *CreateFieldResponse) GetField() *FieldMeta {
 	return nil
 }
 
+func (x *CreateFieldResponse) GetWarning() string {
+	if x != nil {
+		return x.Warning
+	}
+	return ""
+}
+
 type UpdateFieldRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ObjectId      string                 `protobuf:"bytes,1,opt,name=object_id,json=objectId,proto3" json:"object_id,omitempty"`
-	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
-	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
-	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
-	TypeConfig    string                 `protobuf:"bytes,5,opt,name=type_config,json=typeConfig,proto3" json:"type_config,omitempty"` // JSON string
-	IsRequired    bool                   `protobuf:"varint,6,opt,name=is_required,json=isRequired,proto3" json:"is_required,omitempty"`
-	IsUnique      bool                   `protobuf:"varint,7,opt,name=is_unique,json=isUnique,proto3" json:"is_unique,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	ObjectId    string                 `protobuf:"bytes,1,opt,name=object_id,json=objectId,proto3" json:"object_id,omitempty"`
+	Id          string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Title       string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Description string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	TypeConfig  string                 `protobuf:"bytes,5,opt,name=type_config,json=typeConfig,proto3" json:"type_config,omitempty"` // JSON string
+	IsRequired  bool                   `protobuf:"varint,6,opt,name=is_required,json=isRequired,proto3" json:"is_required,omitempty"`
+	IsUnique    bool                   `protobuf:"varint,7,opt,name=is_unique,json=isUnique,proto3" json:"is_unique,omitempty"`
+	// Set or clear is_searchable; unchanged when absent.
+	IsSearchable *bool `protobuf:"varint,8,opt,name=is_searchable,json=isSearchable,proto3,oneof" json:"is_searchable,omitempty"`
+	// Set or clear is_filterable; unchanged when absent.
+	IsFilterable *bool `protobuf:"varint,9,opt,name=is_filterable,json=isFilterable,proto3,oneof" json:"is_filterable,omitempty"`
+	// Set or clear is_sortable; unchanged when absent.
+	IsSortable *bool `protobuf:"varint,10,opt,name=is_sortable,json=isSortable,proto3,oneof" json:"is_sortable,omitempty"`
+	// Set or clear is_accent_insensitive; unchanged when absent. A search
+	// index is rebuilt on the new expression.
+	IsAccentInsensitive *bool `protobuf:"varint,11,opt,name=is_accent_insensitive,json=isAccentInsensitive,proto3,oneof" json:"is_accent_insensitive,omitempty"`
+	// Unset keeps the current masking; set clear_masking to unmask the field.
+	Masking      *FieldMasking `protobuf:"bytes,12,opt,name=masking,proto3" json:"masking,omitempty"`
+	ClearMasking bool          `protobuf:"varint,13,opt,name=clear_masking,json=clearMasking,proto3" json:"clear_masking,omitempty"`
+	// Set or clear is_pii; unchanged when absent.
+	IsPii         *bool `protobuf:"varint,14,opt,name=is_pii,json=isPii,proto3,oneof" json:"is_pii,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UpdateFieldRequest) Reset() {
 	*x = UpdateFieldRequest{}
-	mi := &file_registry_v1_metadata_proto_msgTypes[18]
+	mi := &file_registry_v1_metadata_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1177,7 +2509,7 @@ func (x *UpdateFieldRequest) String() string {
 func (*UpdateFieldRequest) ProtoMessage() {}
 
 func (x *UpdateFieldRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_registry_v1_metadata_proto_msgTypes[18]
+	mi := &file_registry_v1_metadata_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1190,7 +2522,7 @@ func (x *UpdateFieldRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateFieldRequest.ProtoReflect.Descriptor instead.
 func (*UpdateFieldRequest) Descriptor() ([]byte, []int) {
-	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{18}
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *UpdateFieldRequest) GetObjectId() string {
@@ -1242,6 +2574,55 @@ func (x *UpdateFieldRequest) GetIsUnique() bool {
 	return false
 }
 
+func (x *UpdateFieldRequest) GetIsSearchable() bool {
+	if x != nil && x.IsSearchable != nil {
+		return *x.IsSearchable
+	}
+	return false
+}
+
+func (x *UpdateFieldRequest) GetIsFilterable() bool {
+	if x != nil && x.IsFilterable != nil {
+		return *x.IsFilterable
+	}
+	return false
+}
+
+func (x *UpdateFieldRequest) GetIsSortable() bool {
+	if x != nil && x.IsSortable != nil {
+		return *x.IsSortable
+	}
+	return false
+}
+
+func (x *UpdateFieldRequest) GetIsAccentInsensitive() bool {
+	if x != nil && x.IsAccentInsensitive != nil {
+		return *x.IsAccentInsensitive
+	}
+	return false
+}
+
+func (x *UpdateFieldRequest) GetMasking() *FieldMasking {
+	if x != nil {
+		return x.Masking
+	}
+	return nil
+}
+
+func (x *UpdateFieldRequest) GetClearMasking() bool {
+	if x != nil {
+		return x.ClearMasking
+	}
+	return false
+}
+
+func (x *UpdateFieldRequest) GetIsPii() bool {
+	if x != nil && x.IsPii != nil {
+		return *x.IsPii
+	}
+	return false
+}
+
 type UpdateFieldResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Field         *FieldMeta             `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
@@ -1251,7 +2632,7 @@ type UpdateFieldResponse struct {
 
 func (x *UpdateFieldResponse) Reset() {
 	*x = UpdateFieldResponse{}
-	mi := &file_registry_v1_metadata_proto_msgTypes[19]
+	mi := &file_registry_v1_metadata_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1263,7 +2644,7 @@ func (x *UpdateFieldResponse) String() string {
 func (*UpdateFieldResponse) ProtoMessage() {}
 
 func (x *UpdateFieldResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_registry_v1_metadata_proto_msgTypes[19]
+	mi := &file_registry_v1_metadata_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1276,7 +2657,7 @@ func (x *UpdateFieldResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateFieldResponse.ProtoReflect.Descriptor instead.
 func (*UpdateFieldResponse) Descriptor() ([]byte, []int) {
-	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{19}
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *UpdateFieldResponse) GetField() *FieldMeta {
@@ -1296,7 +2677,7 @@ type DeleteFieldRequest struct {
 
 func (x *DeleteFieldRequest) Reset() {
 	*x = DeleteFieldRequest{}
-	mi := &file_registry_v1_metadata_proto_msgTypes[20]
+	mi := &file_registry_v1_metadata_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1308,7 +2689,7 @@ func (x *DeleteFieldRequest) String() string {
 func (*DeleteFieldRequest) ProtoMessage() {}
 
 func (x *DeleteFieldRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_registry_v1_metadata_proto_msgTypes[20]
+	mi := &file_registry_v1_metadata_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1321,7 +2702,7 @@ func (x *DeleteFieldRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteFieldRequest.ProtoReflect.Descriptor instead.
 func (*DeleteFieldRequest) Descriptor() ([]byte, []int) {
-	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{20}
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *DeleteFieldRequest) GetObjectId() string {
@@ -1346,7 +2727,7 @@ type DeleteFieldResponse struct {
 
 func (x *DeleteFieldResponse) Reset() {
 	*x = DeleteFieldResponse{}
-	mi := &file_registry_v1_metadata_proto_msgTypes[21]
+	mi := &file_registry_v1_metadata_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1358,7 +2739,7 @@ func (x *DeleteFieldResponse) String() string {
 func (*DeleteFieldResponse) ProtoMessage() {}
 
 func (x *DeleteFieldResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_registry_v1_metadata_proto_msgTypes[21]
+	mi := &file_registry_v1_metadata_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1371,14 +2752,269 @@ func (x *DeleteFieldResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteFieldResponse.ProtoReflect.Descriptor instead.
 func (*DeleteFieldResponse) Descriptor() ([]byte, []int) {
-	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{21}
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{33}
+}
+
+// RenameFieldRequest changes the api_name of a custom object's field. The
+// current name becomes an alias: select, filters, order, expand, writes and
+// HRQL using it keep working on the field, and HRQL answers with a
+// renamed_field warning. Record documents are rewritten under the new key,
+// as are the object's record history, default_order and display_template,
+// its retention policy and lookups denormalizing the field as their label,
+// all in one transaction. Standard objects' fields cannot be renamed.
+type RenameFieldRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ObjectId      string                 `protobuf:"bytes,1,opt,name=object_id,json=objectId,proto3" json:"object_id,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	ApiName       string                 `protobuf:"bytes,3,opt,name=api_name,json=apiName,proto3" json:"api_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameFieldRequest) Reset() {
+	*x = RenameFieldRequest{}
+	mi := &file_registry_v1_metadata_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameFieldRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameFieldRequest) ProtoMessage() {}
+
+func (x *RenameFieldRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_metadata_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameFieldRequest.ProtoReflect.Descriptor instead.
+func (*RenameFieldRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *RenameFieldRequest) GetObjectId() string {
+	if x != nil {
+		return x.ObjectId
+	}
+	return ""
+}
+
+func (x *RenameFieldRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RenameFieldRequest) GetApiName() string {
+	if x != nil {
+		return x.ApiName
+	}
+	return ""
+}
+
+type RenameFieldResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Field *FieldMeta             `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	// Records whose documents were rewritten under the new key.
+	Records       int64 `protobuf:"varint,2,opt,name=records,proto3" json:"records,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameFieldResponse) Reset() {
+	*x = RenameFieldResponse{}
+	mi := &file_registry_v1_metadata_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameFieldResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameFieldResponse) ProtoMessage() {}
+
+func (x *RenameFieldResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_metadata_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameFieldResponse.ProtoReflect.Descriptor instead.
+func (*RenameFieldResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *RenameFieldResponse) GetField() *FieldMeta {
+	if x != nil {
+		return x.Field
+	}
+	return nil
+}
+
+func (x *RenameFieldResponse) GetRecords() int64 {
+	if x != nil {
+		return x.Records
+	}
+	return 0
+}
+
+type GenerateClientRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Client language: "typescript" or "go".
+	Language string `protobuf:"bytes,1,opt,name=language,proto3" json:"language,omitempty"`
+	// API names of the objects to include; all objects when empty.
+	Objects []string `protobuf:"bytes,2,rep,name=objects,proto3" json:"objects,omitempty"`
+	// Package name of Go clients; defaults to "registryclient".
+	GoPackage     string `protobuf:"bytes,3,opt,name=go_package,json=goPackage,proto3" json:"go_package,omitempty"`
+	Consistency   string `protobuf:"bytes,4,opt,name=consistency,proto3" json:"consistency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateClientRequest) Reset() {
+	*x = GenerateClientRequest{}
+	mi := &file_registry_v1_metadata_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateClientRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateClientRequest) ProtoMessage() {}
+
+func (x *GenerateClientRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_metadata_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateClientRequest.ProtoReflect.Descriptor instead.
+func (*GenerateClientRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *GenerateClientRequest) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *GenerateClientRequest) GetObjects() []string {
+	if x != nil {
+		return x.Objects
+	}
+	return nil
+}
+
+func (x *GenerateClientRequest) GetGoPackage() string {
+	if x != nil {
+		return x.GoPackage
+	}
+	return ""
+}
+
+func (x *GenerateClientRequest) GetConsistency() string {
+	if x != nil {
+		return x.Consistency
+	}
+	return ""
+}
+
+type GenerateClientResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Suggested file name, e.g. "registry-client.ts".
+	Filename string `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	Source   string `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	// Hash of the object and field definitions the client was generated
+	// from. It changes whenever they do, so CI can compare it with the hash
+	// in a checked-in client's header to detect a stale client.
+	SchemaHash    string `protobuf:"bytes,3,opt,name=schema_hash,json=schemaHash,proto3" json:"schema_hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateClientResponse) Reset() {
+	*x = GenerateClientResponse{}
+	mi := &file_registry_v1_metadata_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateClientResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateClientResponse) ProtoMessage() {}
+
+func (x *GenerateClientResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_metadata_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateClientResponse.ProtoReflect.Descriptor instead.
+func (*GenerateClientResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_metadata_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *GenerateClientResponse) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *GenerateClientResponse) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *GenerateClientResponse) GetSchemaHash() string {
+	if x != nil {
+		return x.SchemaHash
+	}
+	return ""
 }
 
 var File_registry_v1_metadata_proto protoreflect.FileDescriptor
 
 const file_registry_v1_metadata_proto_rawDesc = "" +
 	"\n" +
-	"\x1aregistry/v1/metadata.proto\x12\vregistry.v1\x1a\x1bbuf/validate/validate.proto\"\xc4\x03\n" +
+	"\x1aregistry/v1/metadata.proto\x12\vregistry.v1\x1a\x1bbuf/validate/validate.proto\"\xc8\a\n" +
 	"\n" +
 	"ObjectMeta\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
@@ -1398,7 +3034,27 @@ const file_registry_v1_metadata_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\f \x01(\tR\tcreatedAt\x12\x1d\n" +
 	"\n" +
-	"updated_at\x18\r \x01(\tR\tupdatedAt\"\xae\x03\n" +
+	"updated_at\x18\r \x01(\tR\tupdatedAt\x12#\n" +
+	"\rdefault_order\x18\x0e \x01(\tR\fdefaultOrder\x12*\n" +
+	"\x11default_page_size\x18\x0f \x01(\x05R\x0fdefaultPageSize\x12\"\n" +
+	"\rmax_page_size\x18\x10 \x01(\x05R\vmaxPageSize\x12M\n" +
+	"\x12validation_webhook\x18\x11 \x01(\v2\x1e.registry.v1.ValidationWebhookR\x11validationWebhook\x12)\n" +
+	"\x10display_template\x18\x12 \x01(\tR\x0fdisplayTemplate\x12@\n" +
+	"\vdeprecation\x18\x13 \x01(\v2\x1e.registry.v1.ObjectDeprecationR\vdeprecation\x12\x18\n" +
+	"\aaliases\x18\x14 \x03(\tR\aaliases\x12*\n" +
+	"\x11max_custom_fields\x18\x15 \x01(\x05R\x0fmaxCustomFields\x12$\n" +
+	"\x0emax_data_bytes\x18\x16 \x01(\x05R\fmaxDataBytes\x122\n" +
+	"\x15exact_count_threshold\x18\x17 \x01(\x03R\x13exactCountThreshold\x121\n" +
+	"\x15cache_max_age_seconds\x18\x18 \x01(\x05R\x12cacheMaxAgeSeconds\"w\n" +
+	"\x11ObjectDeprecation\x12#\n" +
+	"\rdeprecated_at\x18\x01 \x01(\tR\fdeprecatedAt\x12\x1b\n" +
+	"\tsunset_at\x18\x02 \x01(\tR\bsunsetAt\x12 \n" +
+	"\vreplacement\x18\x03 \x01(\tR\vreplacement\"x\n" +
+	"\x11ValidationWebhook\x12\x1a\n" +
+	"\x03url\x18\x01 \x01(\tB\b\xbaH\x05r\x03\x88\x01\x01R\x03url\x12*\n" +
+	"\n" +
+	"timeout_ms\x18\x02 \x01(\x05B\v\xbaH\b\x1a\x06\x18\xb0\xea\x01(\x00R\ttimeoutMs\x12\x1b\n" +
+	"\tfail_open\x18\x03 \x01(\bR\bfailOpen\"\x8e\x06\n" +
 	"\tFieldMeta\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
 	"\tobject_id\x18\x02 \x01(\tR\bobjectId\x12\x19\n" +
@@ -1419,14 +3075,38 @@ const file_registry_v1_metadata_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\r \x01(\tR\tcreatedAt\x12\x1d\n" +
 	"\n" +
-	"updated_at\x18\x0e \x01(\tR\tupdatedAt\"\x14\n" +
-	"\x12ListObjectsRequest\"H\n" +
+	"updated_at\x18\x0e \x01(\tR\tupdatedAt\x12$\n" +
+	"\x0eis_external_id\x18\x0f \x01(\bR\fisExternalId\x123\n" +
+	"\aoptions\x18\x10 \x03(\v2\x19.registry.v1.ChoiceOptionR\aoptions\x12#\n" +
+	"\ris_searchable\x18\x11 \x01(\bR\fisSearchable\x12#\n" +
+	"\ris_filterable\x18\x12 \x01(\bR\fisFilterable\x12\x1f\n" +
+	"\vis_sortable\x18\x13 \x01(\bR\n" +
+	"isSortable\x122\n" +
+	"\x15is_accent_insensitive\x18\x14 \x01(\bR\x13isAccentInsensitive\x123\n" +
+	"\amasking\x18\x15 \x01(\v2\x19.registry.v1.FieldMaskingR\amasking\x12\x15\n" +
+	"\x06is_pii\x18\x16 \x01(\bR\x05isPii\x12\x18\n" +
+	"\aaliases\x18\x17 \x03(\tR\aaliases\"\x99\x01\n" +
+	"\fFieldMasking\x12Y\n" +
+	"\x11default_transform\x18\x01 \x01(\tB,\xbaH)r'R\x04noneR\x04hideR\x05last4R\femail_domainR\x04yearR\x10defaultTransform\x12.\n" +
+	"\x05rules\x18\x02 \x03(\v2\x18.registry.v1.MaskingRuleR\x05rules\"\x82\x01\n" +
+	"\vMaskingRule\x12'\n" +
+	"\n" +
+	"permission\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\n" +
+	"permission\x12J\n" +
+	"\ttransform\x18\x02 \x01(\tB,\xbaH)r'R\x04noneR\x04hideR\x05last4R\femail_domainR\x04yearR\ttransform\":\n" +
+	"\fChoiceOption\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\tR\x05value\x12\x14\n" +
+	"\x05label\x18\x02 \x01(\tR\x05label\"O\n" +
+	"\x12ListObjectsRequest\x129\n" +
+	"\vconsistency\x18\x01 \x01(\tB\x17\xbaH\x14r\x12R\x00R\x06cachedR\x06strongR\vconsistency\"H\n" +
 	"\x13ListObjectsResponse\x121\n" +
-	"\aobjects\x18\x01 \x03(\v2\x17.registry.v1.ObjectMetaR\aobjects\",\n" +
+	"\aobjects\x18\x01 \x03(\v2\x17.registry.v1.ObjectMetaR\aobjects\"\x7f\n" +
 	"\x10GetObjectRequest\x12\x18\n" +
-	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\"D\n" +
+	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\x129\n" +
+	"\vconsistency\x18\x02 \x01(\tB\x17\xbaH\x14r\x12R\x00R\x06cachedR\x06strongR\vconsistency\x12\x16\n" +
+	"\x06locale\x18\x03 \x01(\tR\x06locale\"D\n" +
 	"\x11GetObjectResponse\x12/\n" +
-	"\x06object\x18\x01 \x01(\v2\x17.registry.v1.ObjectMetaR\x06object\"\xfd\x01\n" +
+	"\x06object\x18\x01 \x01(\v2\x17.registry.v1.ObjectMetaR\x06object\"\x91\x06\n" +
 	"\x13CreateObjectRequest\x12\"\n" +
 	"\bapi_name\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\aapiName\x12\x1d\n" +
 	"\x05title\x18\x02 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\x05title\x12*\n" +
@@ -1434,9 +3114,19 @@ const file_registry_v1_metadata_proto_rawDesc = "" +
 	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x1f\n" +
 	"\vcategory_id\x18\x05 \x01(\tR\n" +
 	"categoryId\x124\n" +
-	"\x16supports_custom_fields\x18\x06 \x01(\bR\x14supportsCustomFields\"G\n" +
+	"\x16supports_custom_fields\x18\x06 \x01(\bR\x14supportsCustomFields\x12O\n" +
+	"\rdefault_order\x18\a \x01(\tB*\xbaH'r%2#^([a-z][a-z0-9_]*(\\.(asc|desc))?)?$R\fdefaultOrder\x123\n" +
+	"\x11default_page_size\x18\b \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\x0fdefaultPageSize\x12+\n" +
+	"\rmax_page_size\x18\t \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\vmaxPageSize\x12M\n" +
+	"\x12validation_webhook\x18\n" +
+	" \x01(\v2\x1e.registry.v1.ValidationWebhookR\x11validationWebhook\x123\n" +
+	"\x10display_template\x18\v \x01(\tB\b\xbaH\x05r\x03\x18\xc8\x01R\x0fdisplayTemplate\x123\n" +
+	"\x11max_custom_fields\x18\f \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\x0fmaxCustomFields\x12-\n" +
+	"\x0emax_data_bytes\x18\r \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\fmaxDataBytes\x12;\n" +
+	"\x15exact_count_threshold\x18\x0e \x01(\x03B\a\xbaH\x04\"\x02(\x00R\x13exactCountThreshold\x12:\n" +
+	"\x15cache_max_age_seconds\x18\x0f \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\x12cacheMaxAgeSeconds\"G\n" +
 	"\x14CreateObjectResponse\x12/\n" +
-	"\x06object\x18\x01 \x01(\v2\x17.registry.v1.ObjectMetaR\x06object\"\xe1\x01\n" +
+	"\x06object\x18\x01 \x01(\v2\x17.registry.v1.ObjectMetaR\x06object\"\xf2\b\n" +
 	"\x13UpdateObjectRequest\x12\x18\n" +
 	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12!\n" +
@@ -1444,21 +3134,86 @@ const file_registry_v1_metadata_proto_rawDesc = "" +
 	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x1f\n" +
 	"\vcategory_id\x18\x05 \x01(\tR\n" +
 	"categoryId\x124\n" +
-	"\x16supports_custom_fields\x18\x06 \x01(\bR\x14supportsCustomFields\"G\n" +
+	"\x16supports_custom_fields\x18\x06 \x01(\bR\x14supportsCustomFields\x12T\n" +
+	"\rdefault_order\x18\a \x01(\tB*\xbaH'r%2#^([a-z][a-z0-9_]*(\\.(asc|desc))?)?$H\x00R\fdefaultOrder\x88\x01\x01\x128\n" +
+	"\x11default_page_size\x18\b \x01(\x05B\a\xbaH\x04\x1a\x02(\x00H\x01R\x0fdefaultPageSize\x88\x01\x01\x120\n" +
+	"\rmax_page_size\x18\t \x01(\x05B\a\xbaH\x04\x1a\x02(\x00H\x02R\vmaxPageSize\x88\x01\x01\x12M\n" +
+	"\x12validation_webhook\x18\n" +
+	" \x01(\v2\x1e.registry.v1.ValidationWebhookR\x11validationWebhook\x128\n" +
+	"\x18clear_validation_webhook\x18\v \x01(\bR\x16clearValidationWebhook\x128\n" +
+	"\x10display_template\x18\f \x01(\tB\b\xbaH\x05r\x03\x18\xc8\x01H\x03R\x0fdisplayTemplate\x88\x01\x01\x12@\n" +
+	"\vdeprecation\x18\r \x01(\v2\x1e.registry.v1.ObjectDeprecationR\vdeprecation\x12+\n" +
+	"\x11clear_deprecation\x18\x0e \x01(\bR\x10clearDeprecation\x128\n" +
+	"\x11max_custom_fields\x18\x0f \x01(\x05B\a\xbaH\x04\x1a\x02(\x00H\x04R\x0fmaxCustomFields\x88\x01\x01\x122\n" +
+	"\x0emax_data_bytes\x18\x10 \x01(\x05B\a\xbaH\x04\x1a\x02(\x00H\x05R\fmaxDataBytes\x88\x01\x01\x12@\n" +
+	"\x15exact_count_threshold\x18\x11 \x01(\x03B\a\xbaH\x04\"\x02(\x00H\x06R\x13exactCountThreshold\x88\x01\x01\x12?\n" +
+	"\x15cache_max_age_seconds\x18\x12 \x01(\x05B\a\xbaH\x04\x1a\x02(\x00H\aR\x12cacheMaxAgeSeconds\x88\x01\x01B\x10\n" +
+	"\x0e_default_orderB\x14\n" +
+	"\x12_default_page_sizeB\x10\n" +
+	"\x0e_max_page_sizeB\x13\n" +
+	"\x11_display_templateB\x14\n" +
+	"\x12_max_custom_fieldsB\x11\n" +
+	"\x0f_max_data_bytesB\x18\n" +
+	"\x16_exact_count_thresholdB\x18\n" +
+	"\x16_cache_max_age_seconds\"G\n" +
 	"\x14UpdateObjectResponse\x12/\n" +
-	"\x06object\x18\x01 \x01(\v2\x17.registry.v1.ObjectMetaR\x06object\"/\n" +
+	"\x06object\x18\x01 \x01(\v2\x17.registry.v1.ObjectMetaR\x06object\"b\n" +
 	"\x13DeleteObjectRequest\x12\x18\n" +
-	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\"\x16\n" +
-	"\x14DeleteObjectResponse\":\n" +
+	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\x121\n" +
+	"\acascade\x18\x02 \x01(\tB\x17\xbaH\x14r\x12R\x00R\x05blockR\anullifyR\acascade\"\x16\n" +
+	"\x14DeleteObjectResponse\"0\n" +
+	"\x14PreviewDeleteRequest\x12\x18\n" +
+	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\"\xc0\x01\n" +
+	"\x15PreviewDeleteResponse\x12\x19\n" +
+	"\bapi_name\x18\x01 \x01(\tR\aapiName\x12<\n" +
+	"\n" +
+	"references\x18\x02 \x03(\v2\x1c.registry.v1.DeleteReferenceR\n" +
+	"references\x12\x18\n" +
+	"\arecords\x18\x03 \x01(\x03R\arecords\x124\n" +
+	"\x16validation_webhook_url\x18\x04 \x01(\tR\x14validationWebhookUrl\"\xd2\x01\n" +
+	"\x0fDeleteReference\x12\x1b\n" +
+	"\tobject_id\x18\x01 \x01(\tR\bobjectId\x12&\n" +
+	"\x0fobject_api_name\x18\x02 \x01(\tR\robjectApiName\x12\x19\n" +
+	"\bfield_id\x18\x03 \x01(\tR\afieldId\x12$\n" +
+	"\x0efield_api_name\x18\x04 \x01(\tR\ffieldApiName\x12\x18\n" +
+	"\arecords\x18\x05 \x01(\x03R\arecords\x12\x1f\n" +
+	"\vis_standard\x18\x06 \x01(\bR\n" +
+	"isStandard\"S\n" +
+	"\x13RenameObjectRequest\x12\x18\n" +
+	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\x12\"\n" +
+	"\bapi_name\x18\x02 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\aapiName\"G\n" +
+	"\x14RenameObjectResponse\x12/\n" +
+	"\x06object\x18\x01 \x01(\v2\x17.registry.v1.ObjectMetaR\x06object\"1\n" +
+	"\x15GetObjectUsageRequest\x12\x18\n" +
+	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\"\xf3\x01\n" +
+	"\x16GetObjectUsageResponse\x12#\n" +
+	"\rcustom_fields\x18\x01 \x01(\x05R\fcustomFields\x12*\n" +
+	"\x11max_custom_fields\x18\x02 \x01(\x05R\x0fmaxCustomFields\x12\x18\n" +
+	"\arecords\x18\x03 \x01(\x03R\arecords\x12,\n" +
+	"\x12largest_data_bytes\x18\x04 \x01(\x03R\x10largestDataBytes\x12$\n" +
+	"\x0emax_data_bytes\x18\x05 \x01(\x05R\fmaxDataBytes\x12\x1a\n" +
+	"\bwarnings\x18\x06 \x03(\tR\bwarnings\"\xa2\x02\n" +
 	"\x11ListFieldsRequest\x12%\n" +
-	"\tobject_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\bobjectId\"D\n" +
+	"\tobject_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\bobjectId\x129\n" +
+	"\vconsistency\x18\x02 \x01(\tB\x17\xbaH\x14r\x12R\x00R\x06cachedR\x06strongR\vconsistency\x12K\n" +
+	"\border_by\x18\x03 \x01(\tB0\xbaH-r+2)^((created_at|api_name|title)(\\.desc)?)?$R\aorderBy\x12'\n" +
+	"\tpage_size\x18\x04 \x01(\x05B\n" +
+	"\xbaH\a\x1a\x05\x18\xf4\x03(\x00R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x05 \x01(\tR\tpageToken\x12\x16\n" +
+	"\x06locale\x18\x06 \x01(\tR\x06locale\"\x8b\x01\n" +
 	"\x12ListFieldsResponse\x12.\n" +
-	"\x06fields\x18\x01 \x03(\v2\x16.registry.v1.FieldMetaR\x06fields\"R\n" +
+	"\x06fields\x18\x01 \x03(\v2\x16.registry.v1.FieldMetaR\x06fields\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x03 \x01(\x05R\ttotalSize\"\xa5\x01\n" +
 	"\x0fGetFieldRequest\x12%\n" +
 	"\tobject_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\bobjectId\x12\x18\n" +
-	"\x02id\x18\x02 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\"@\n" +
+	"\x02id\x18\x02 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\x129\n" +
+	"\vconsistency\x18\x03 \x01(\tB\x17\xbaH\x14r\x12R\x00R\x06cachedR\x06strongR\vconsistency\x12\x16\n" +
+	"\x06locale\x18\x04 \x01(\tR\x06locale\"@\n" +
 	"\x10GetFieldResponse\x12,\n" +
-	"\x05field\x18\x01 \x01(\v2\x16.registry.v1.FieldMetaR\x05field\"\xc6\x02\n" +
+	"\x05field\x18\x01 \x01(\v2\x16.registry.v1.FieldMetaR\x05field\"\x83\x05\n" +
 	"\x12CreateFieldRequest\x12%\n" +
 	"\tobject_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\bobjectId\x12\"\n" +
 	"\bapi_name\x18\x02 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\aapiName\x12\x1d\n" +
@@ -1470,9 +3225,21 @@ const file_registry_v1_metadata_proto_rawDesc = "" +
 	"\vis_required\x18\a \x01(\bR\n" +
 	"isRequired\x12\x1b\n" +
 	"\tis_unique\x18\b \x01(\bR\bisUnique\x12(\n" +
-	"\x10lookup_object_id\x18\t \x01(\tR\x0elookupObjectId\"C\n" +
+	"\x10lookup_object_id\x18\t \x01(\tR\x0elookupObjectId\x12$\n" +
+	"\x0eis_external_id\x18\n" +
+	" \x01(\bR\fisExternalId\x12#\n" +
+	"\ris_searchable\x18\v \x01(\bR\fisSearchable\x12(\n" +
+	"\ris_filterable\x18\f \x01(\bH\x00R\fisFilterable\x88\x01\x01\x12$\n" +
+	"\vis_sortable\x18\r \x01(\bH\x01R\n" +
+	"isSortable\x88\x01\x01\x122\n" +
+	"\x15is_accent_insensitive\x18\x0e \x01(\bR\x13isAccentInsensitive\x123\n" +
+	"\amasking\x18\x0f \x01(\v2\x19.registry.v1.FieldMaskingR\amasking\x12\x15\n" +
+	"\x06is_pii\x18\x10 \x01(\bR\x05isPiiB\x10\n" +
+	"\x0e_is_filterableB\x0e\n" +
+	"\f_is_sortable\"]\n" +
 	"\x13CreateFieldResponse\x12,\n" +
-	"\x05field\x18\x01 \x01(\v2\x16.registry.v1.FieldMetaR\x05field\"\xec\x01\n" +
+	"\x05field\x18\x01 \x01(\v2\x16.registry.v1.FieldMetaR\x05field\x12\x18\n" +
+	"\awarning\x18\x02 \x01(\tR\awarning\"\xee\x04\n" +
 	"\x12UpdateFieldRequest\x12%\n" +
 	"\tobject_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\bobjectId\x12\x18\n" +
 	"\x02id\x18\x02 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\x12\x14\n" +
@@ -1482,13 +3249,46 @@ const file_registry_v1_metadata_proto_rawDesc = "" +
 	"typeConfig\x12\x1f\n" +
 	"\vis_required\x18\x06 \x01(\bR\n" +
 	"isRequired\x12\x1b\n" +
-	"\tis_unique\x18\a \x01(\bR\bisUnique\"C\n" +
+	"\tis_unique\x18\a \x01(\bR\bisUnique\x12(\n" +
+	"\ris_searchable\x18\b \x01(\bH\x00R\fisSearchable\x88\x01\x01\x12(\n" +
+	"\ris_filterable\x18\t \x01(\bH\x01R\fisFilterable\x88\x01\x01\x12$\n" +
+	"\vis_sortable\x18\n" +
+	" \x01(\bH\x02R\n" +
+	"isSortable\x88\x01\x01\x127\n" +
+	"\x15is_accent_insensitive\x18\v \x01(\bH\x03R\x13isAccentInsensitive\x88\x01\x01\x123\n" +
+	"\amasking\x18\f \x01(\v2\x19.registry.v1.FieldMaskingR\amasking\x12#\n" +
+	"\rclear_masking\x18\r \x01(\bR\fclearMasking\x12\x1a\n" +
+	"\x06is_pii\x18\x0e \x01(\bH\x04R\x05isPii\x88\x01\x01B\x10\n" +
+	"\x0e_is_searchableB\x10\n" +
+	"\x0e_is_filterableB\x0e\n" +
+	"\f_is_sortableB\x18\n" +
+	"\x16_is_accent_insensitiveB\t\n" +
+	"\a_is_pii\"C\n" +
 	"\x13UpdateFieldResponse\x12,\n" +
 	"\x05field\x18\x01 \x01(\v2\x16.registry.v1.FieldMetaR\x05field\"U\n" +
 	"\x12DeleteFieldRequest\x12%\n" +
 	"\tobject_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\bobjectId\x12\x18\n" +
 	"\x02id\x18\x02 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\"\x15\n" +
-	"\x13DeleteFieldResponseB\xad\x01\n" +
+	"\x13DeleteFieldResponse\"y\n" +
+	"\x12RenameFieldRequest\x12%\n" +
+	"\tobject_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\bobjectId\x12\x18\n" +
+	"\x02id\x18\x02 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\x12\"\n" +
+	"\bapi_name\x18\x03 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\aapiName\"]\n" +
+	"\x13RenameFieldResponse\x12,\n" +
+	"\x05field\x18\x01 \x01(\v2\x16.registry.v1.FieldMetaR\x05field\x12\x18\n" +
+	"\arecords\x18\x02 \x01(\x03R\arecords\"\xbe\x01\n" +
+	"\x15GenerateClientRequest\x121\n" +
+	"\blanguage\x18\x01 \x01(\tB\x15\xbaH\x12r\x10R\n" +
+	"typescriptR\x02goR\blanguage\x12\x18\n" +
+	"\aobjects\x18\x02 \x03(\tR\aobjects\x12\x1d\n" +
+	"\n" +
+	"go_package\x18\x03 \x01(\tR\tgoPackage\x129\n" +
+	"\vconsistency\x18\x04 \x01(\tB\x17\xbaH\x14r\x12R\x00R\x06cachedR\x06strongR\vconsistency\"m\n" +
+	"\x16GenerateClientResponse\x12\x1a\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x12\x1f\n" +
+	"\vschema_hash\x18\x03 \x01(\tR\n" +
+	"schemaHashB\xad\x01\n" +
 	"\x0fcom.registry.v1B\rMetadataProtoP\x01Z>github.com/atlekbai/schema_registry/gen/registry/v1;registryv1\xa2\x02\x03RXX\xaa\x02\vRegistry.V1\xca\x02\vRegistry\\V1\xe2\x02\x17Registry\\V1\\GPBMetadata\xea\x02\fRegistry::V1b\x06proto3"
 
 var (
@@ -1503,46 +3303,75 @@ func file_registry_v1_metadata_proto_rawDescGZIP() []byte {
 	return file_registry_v1_metadata_proto_rawDescData
 }
 
-var file_registry_v1_metadata_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
+var file_registry_v1_metadata_proto_msgTypes = make([]protoimpl.MessageInfo, 38)
 var file_registry_v1_metadata_proto_goTypes = []any{
-	(*ObjectMeta)(nil),           // 0: registry.v1.ObjectMeta
-	(*FieldMeta)(nil),            // 1: registry.v1.FieldMeta
-	(*ListObjectsRequest)(nil),   // 2: registry.v1.ListObjectsRequest
-	(*ListObjectsResponse)(nil),  // 3: registry.v1.ListObjectsResponse
-	(*GetObjectRequest)(nil),     // 4: registry.v1.GetObjectRequest
-	(*GetObjectResponse)(nil),    // 5: registry.v1.GetObjectResponse
-	(*CreateObjectRequest)(nil),  // 6: registry.v1.CreateObjectRequest
-	(*CreateObjectResponse)(nil), // 7: registry.v1.CreateObjectResponse
-	(*UpdateObjectRequest)(nil),  // 8: registry.v1.UpdateObjectRequest
-	(*UpdateObjectResponse)(nil), // 9: registry.v1.UpdateObjectResponse
-	(*DeleteObjectRequest)(nil),  // 10: registry.v1.DeleteObjectRequest
-	(*DeleteObjectResponse)(nil), // 11: registry.v1.DeleteObjectResponse
-	(*ListFieldsRequest)(nil),    // 12: registry.v1.ListFieldsRequest
-	(*ListFieldsResponse)(nil),   // 13: registry.v1.ListFieldsResponse
-	(*GetFieldRequest)(nil),      // 14: registry.v1.GetFieldRequest
-	(*GetFieldResponse)(nil),     // 15: registry.v1.GetFieldResponse
-	(*CreateFieldRequest)(nil),   // 16: registry.v1.CreateFieldRequest
-	(*CreateFieldResponse)(nil),  // 17: registry.v1.CreateFieldResponse
-	(*UpdateFieldRequest)(nil),   // 18: registry.v1.UpdateFieldRequest
-	(*UpdateFieldResponse)(nil),  // 19: registry.v1.UpdateFieldResponse
-	(*DeleteFieldRequest)(nil),   // 20: registry.v1.DeleteFieldRequest
-	(*DeleteFieldResponse)(nil),  // 21: registry.v1.DeleteFieldResponse
+	(*ObjectMeta)(nil),             // 0: registry.v1.ObjectMeta
+	(*ObjectDeprecation)(nil),      // 1: registry.v1.ObjectDeprecation
+	(*ValidationWebhook)(nil),      // 2: registry.v1.ValidationWebhook
+	(*FieldMeta)(nil),              // 3: registry.v1.FieldMeta
+	(*FieldMasking)(nil),           // 4: registry.v1.FieldMasking
+	(*MaskingRule)(nil),            // 5: registry.v1.MaskingRule
+	(*ChoiceOption)(nil),           // 6: registry.v1.ChoiceOption
+	(*ListObjectsRequest)(nil),     // 7: registry.v1.ListObjectsRequest
+	(*ListObjectsResponse)(nil),    // 8: registry.v1.ListObjectsResponse
+	(*GetObjectRequest)(nil),       // 9: registry.v1.GetObjectRequest
+	(*GetObjectResponse)(nil),      // 10: registry.v1.GetObjectResponse
+	(*CreateObjectRequest)(nil),    // 11: registry.v1.CreateObjectRequest
+	(*CreateObjectResponse)(nil),   // 12: registry.v1.CreateObjectResponse
+	(*UpdateObjectRequest)(nil),    // 13: registry.v1.UpdateObjectRequest
+	(*UpdateObjectResponse)(nil),   // 14: registry.v1.UpdateObjectResponse
+	(*DeleteObjectRequest)(nil),    // 15: registry.v1.DeleteObjectRequest
+	(*DeleteObjectResponse)(nil),   // 16: registry.v1.DeleteObjectResponse
+	(*PreviewDeleteRequest)(nil),   // 17: registry.v1.PreviewDeleteRequest
+	(*PreviewDeleteResponse)(nil),  // 18: registry.v1.PreviewDeleteResponse
+	(*DeleteReference)(nil),        // 19: registry.v1.DeleteReference
+	(*RenameObjectRequest)(nil),    // 20: registry.v1.RenameObjectRequest
+	(*RenameObjectResponse)(nil),   // 21: registry.v1.RenameObjectResponse
+	(*GetObjectUsageRequest)(nil),  // 22: registry.v1.GetObjectUsageRequest
+	(*GetObjectUsageResponse)(nil), // 23: registry.v1.GetObjectUsageResponse
+	(*ListFieldsRequest)(nil),      // 24: registry.v1.ListFieldsRequest
+	(*ListFieldsResponse)(nil),     // 25: registry.v1.ListFieldsResponse
+	(*GetFieldRequest)(nil),        // 26: registry.v1.GetFieldRequest
+	(*GetFieldResponse)(nil),       // 27: registry.v1.GetFieldResponse
+	(*CreateFieldRequest)(nil),     // 28: registry.v1.CreateFieldRequest
+	(*CreateFieldResponse)(nil),    // 29: registry.v1.CreateFieldResponse
+	(*UpdateFieldRequest)(nil),     // 30: registry.v1.UpdateFieldRequest
+	(*UpdateFieldResponse)(nil),    // 31: registry.v1.UpdateFieldResponse
+	(*DeleteFieldRequest)(nil),     // 32: registry.v1.DeleteFieldRequest
+	(*DeleteFieldResponse)(nil),    // 33: registry.v1.DeleteFieldResponse
+	(*RenameFieldRequest)(nil),     // 34: registry.v1.RenameFieldRequest
+	(*RenameFieldResponse)(nil),    // 35: registry.v1.RenameFieldResponse
+	(*GenerateClientRequest)(nil),  // 36: registry.v1.GenerateClientRequest
+	(*GenerateClientResponse)(nil), // 37: registry.v1.GenerateClientResponse
 }
 var file_registry_v1_metadata_proto_depIdxs = []int32{
-	1, // 0: registry.v1.ObjectMeta.fields:type_name -> registry.v1.FieldMeta
-	0, // 1: registry.v1.ListObjectsResponse.objects:type_name -> registry.v1.ObjectMeta
-	0, // 2: registry.v1.GetObjectResponse.object:type_name -> registry.v1.ObjectMeta
-	0, // 3: registry.v1.CreateObjectResponse.object:type_name -> registry.v1.ObjectMeta
-	0, // 4: registry.v1.UpdateObjectResponse.object:type_name -> registry.v1.ObjectMeta
-	1, // 5: registry.v1.ListFieldsResponse.fields:type_name -> registry.v1.FieldMeta
-	1, // 6: registry.v1.GetFieldResponse.field:type_name -> registry.v1.FieldMeta
-	1, // 7: registry.v1.CreateFieldResponse.field:type_name -> registry.v1.FieldMeta
-	1, // 8: registry.v1.UpdateFieldResponse.field:type_name -> registry.v1.FieldMeta
-	9, // [9:9] is the sub-list for method output_type
-	9, // [9:9] is the sub-list for method input_type
-	9, // [9:9] is the sub-list for extension type_name
-	9, // [9:9] is the sub-list for extension extendee
-	0, // [0:9] is the sub-list for field type_name
+	3,  // 0: registry.v1.ObjectMeta.fields:type_name -> registry.v1.FieldMeta
+	2,  // 1: registry.v1.ObjectMeta.validation_webhook:type_name -> registry.v1.ValidationWebhook
+	1,  // 2: registry.v1.ObjectMeta.deprecation:type_name -> registry.v1.ObjectDeprecation
+	6,  // 3: registry.v1.FieldMeta.options:type_name -> registry.v1.ChoiceOption
+	4,  // 4: registry.v1.FieldMeta.masking:type_name -> registry.v1.FieldMasking
+	5,  // 5: registry.v1.FieldMasking.rules:type_name -> registry.v1.MaskingRule
+	0,  // 6: registry.v1.ListObjectsResponse.objects:type_name -> registry.v1.ObjectMeta
+	0,  // 7: registry.v1.GetObjectResponse.object:type_name -> registry.v1.ObjectMeta
+	2,  // 8: registry.v1.CreateObjectRequest.validation_webhook:type_name -> registry.v1.ValidationWebhook
+	0,  // 9: registry.v1.CreateObjectResponse.object:type_name -> registry.v1.ObjectMeta
+	2,  // 10: registry.v1.UpdateObjectRequest.validation_webhook:type_name -> registry.v1.ValidationWebhook
+	1,  // 11: registry.v1.UpdateObjectRequest.deprecation:type_name -> registry.v1.ObjectDeprecation
+	0,  // 12: registry.v1.UpdateObjectResponse.object:type_name -> registry.v1.ObjectMeta
+	19, // 13: registry.v1.PreviewDeleteResponse.references:type_name -> registry.v1.DeleteReference
+	0,  // 14: registry.v1.RenameObjectResponse.object:type_name -> registry.v1.ObjectMeta
+	3,  // 15: registry.v1.ListFieldsResponse.fields:type_name -> registry.v1.FieldMeta
+	3,  // 16: registry.v1.GetFieldResponse.field:type_name -> registry.v1.FieldMeta
+	4,  // 17: registry.v1.CreateFieldRequest.masking:type_name -> registry.v1.FieldMasking
+	3,  // 18: registry.v1.CreateFieldResponse.field:type_name -> registry.v1.FieldMeta
+	4,  // 19: registry.v1.UpdateFieldRequest.masking:type_name -> registry.v1.FieldMasking
+	3,  // 20: registry.v1.UpdateFieldResponse.field:type_name -> registry.v1.FieldMeta
+	3,  // 21: registry.v1.RenameFieldResponse.field:type_name -> registry.v1.FieldMeta
+	22, // [22:22] is the sub-list for method output_type
+	22, // [22:22] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
 }
 
 func init() { file_registry_v1_metadata_proto_init() }
@@ -1550,13 +3379,16 @@ func file_registry_v1_metadata_proto_init() {
 	if File_registry_v1_metadata_proto != nil {
 		return
 	}
+	file_registry_v1_metadata_proto_msgTypes[13].OneofWrappers = []any{}
+	file_registry_v1_metadata_proto_msgTypes[28].OneofWrappers = []any{}
+	file_registry_v1_metadata_proto_msgTypes[30].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_registry_v1_metadata_proto_rawDesc), len(file_registry_v1_metadata_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   22,
+			NumMessages:   38,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
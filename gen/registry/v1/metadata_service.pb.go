@@ -25,42 +25,57 @@ var File_registry_v1_metadata_service_proto protoreflect.FileDescriptor
 
 const file_registry_v1_metadata_service_proto_rawDesc = "" +
 	"\n" +
-	"\"registry/v1/metadata_service.proto\x12\vregistry.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1aregistry/v1/metadata.proto2\xd6\t\n" +
+	"\"registry/v1/metadata_service.proto\x12\vregistry.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1aregistry/v1/metadata.proto2\xef\x0e\n" +
 	"\x0fMetadataService\x12k\n" +
 	"\vListObjects\x12\x1f.registry.v1.ListObjectsRequest\x1a .registry.v1.ListObjectsResponse\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/api/meta/objects\x12j\n" +
 	"\tGetObject\x12\x1d.registry.v1.GetObjectRequest\x1a\x1e.registry.v1.GetObjectResponse\"\x1e\x82\xd3\xe4\x93\x02\x18\x12\x16/api/meta/objects/{id}\x12q\n" +
 	"\fCreateObject\x12 .registry.v1.CreateObjectRequest\x1a!.registry.v1.CreateObjectResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/api/meta/objects\x12v\n" +
 	"\fUpdateObject\x12 .registry.v1.UpdateObjectRequest\x1a!.registry.v1.UpdateObjectResponse\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\x1a\x16/api/meta/objects/{id}\x12s\n" +
-	"\fDeleteObject\x12 .registry.v1.DeleteObjectRequest\x1a!.registry.v1.DeleteObjectResponse\"\x1e\x82\xd3\xe4\x93\x02\x18*\x16/api/meta/objects/{id}\x12{\n" +
+	"\fDeleteObject\x12 .registry.v1.DeleteObjectRequest\x1a!.registry.v1.DeleteObjectResponse\"\x1e\x82\xd3\xe4\x93\x02\x18*\x16/api/meta/objects/{id}\x12\x85\x01\n" +
+	"\rPreviewDelete\x12!.registry.v1.PreviewDeleteRequest\x1a\".registry.v1.PreviewDeleteResponse\"-\x82\xd3\xe4\x93\x02'\x12%/api/meta/objects/{id}/delete-preview\x12}\n" +
+	"\fRenameObject\x12 .registry.v1.RenameObjectRequest\x1a!.registry.v1.RenameObjectResponse\"(\x82\xd3\xe4\x93\x02\":\x01*\"\x1d/api/meta/objects/{id}/rename\x12\x7f\n" +
+	"\x0eGetObjectUsage\x12\".registry.v1.GetObjectUsageRequest\x1a#.registry.v1.GetObjectUsageResponse\"$\x82\xd3\xe4\x93\x02\x1e\x12\x1c/api/meta/objects/{id}/usage\x12{\n" +
 	"\n" +
 	"ListFields\x12\x1e.registry.v1.ListFieldsRequest\x1a\x1f.registry.v1.ListFieldsResponse\",\x82\xd3\xe4\x93\x02&\x12$/api/meta/objects/{object_id}/fields\x12z\n" +
 	"\bGetField\x12\x1c.registry.v1.GetFieldRequest\x1a\x1d.registry.v1.GetFieldResponse\"1\x82\xd3\xe4\x93\x02+\x12)/api/meta/objects/{object_id}/fields/{id}\x12\x81\x01\n" +
 	"\vCreateField\x12\x1f.registry.v1.CreateFieldRequest\x1a .registry.v1.CreateFieldResponse\"/\x82\xd3\xe4\x93\x02):\x01*\"$/api/meta/objects/{object_id}/fields\x12\x86\x01\n" +
 	"\vUpdateField\x12\x1f.registry.v1.UpdateFieldRequest\x1a .registry.v1.UpdateFieldResponse\"4\x82\xd3\xe4\x93\x02.:\x01*\x1a)/api/meta/objects/{object_id}/fields/{id}\x12\x83\x01\n" +
-	"\vDeleteField\x12\x1f.registry.v1.DeleteFieldRequest\x1a .registry.v1.DeleteFieldResponse\"1\x82\xd3\xe4\x93\x02+*)/api/meta/objects/{object_id}/fields/{id}B\xb4\x01\n" +
+	"\vDeleteField\x12\x1f.registry.v1.DeleteFieldRequest\x1a .registry.v1.DeleteFieldResponse\"1\x82\xd3\xe4\x93\x02+*)/api/meta/objects/{object_id}/fields/{id}\x12\x8d\x01\n" +
+	"\vRenameField\x12\x1f.registry.v1.RenameFieldRequest\x1a .registry.v1.RenameFieldResponse\";\x82\xd3\xe4\x93\x025:\x01*\"0/api/meta/objects/{object_id}/fields/{id}/rename\x12\x7f\n" +
+	"\x0eGenerateClient\x12\".registry.v1.GenerateClientRequest\x1a#.registry.v1.GenerateClientResponse\"$\x82\xd3\xe4\x93\x02\x1e\x12\x1c/api/meta/clients/{language}B\xb4\x01\n" +
 	"\x0fcom.registry.v1B\x14MetadataServiceProtoP\x01Z>github.com/atlekbai/schema_registry/gen/registry/v1;registryv1\xa2\x02\x03RXX\xaa\x02\vRegistry.V1\xca\x02\vRegistry\\V1\xe2\x02\x17Registry\\V1\\GPBMetadata\xea\x02\fRegistry::V1b\x06proto3"
 
 var file_registry_v1_metadata_service_proto_goTypes = []any{
-	(*ListObjectsRequest)(nil),   // 0: registry.v1.ListObjectsRequest
-	(*GetObjectRequest)(nil),     // 1: registry.v1.GetObjectRequest
-	(*CreateObjectRequest)(nil),  // 2: registry.v1.CreateObjectRequest
-	(*UpdateObjectRequest)(nil),  // 3: registry.v1.UpdateObjectRequest
-	(*DeleteObjectRequest)(nil),  // 4: registry.v1.DeleteObjectRequest
-	(*ListFieldsRequest)(nil),    // 5: registry.v1.ListFieldsRequest
-	(*GetFieldRequest)(nil),      // 6: registry.v1.GetFieldRequest
-	(*CreateFieldRequest)(nil),   // 7: registry.v1.CreateFieldRequest
-	(*UpdateFieldRequest)(nil),   // 8: registry.v1.UpdateFieldRequest
-	(*DeleteFieldRequest)(nil),   // 9: registry.v1.DeleteFieldRequest
-	(*ListObjectsResponse)(nil),  // 10: registry.v1.ListObjectsResponse
-	(*GetObjectResponse)(nil),    // 11: registry.v1.GetObjectResponse
-	(*CreateObjectResponse)(nil), // 12: registry.v1.CreateObjectResponse
-	(*UpdateObjectResponse)(nil), // 13: registry.v1.UpdateObjectResponse
-	(*DeleteObjectResponse)(nil), // 14: registry.v1.DeleteObjectResponse
-	(*ListFieldsResponse)(nil),   // 15: registry.v1.ListFieldsResponse
-	(*GetFieldResponse)(nil),     // 16: registry.v1.GetFieldResponse
-	(*CreateFieldResponse)(nil),  // 17: registry.v1.CreateFieldResponse
-	(*UpdateFieldResponse)(nil),  // 18: registry.v1.UpdateFieldResponse
-	(*DeleteFieldResponse)(nil),  // 19: registry.v1.DeleteFieldResponse
+	(*ListObjectsRequest)(nil),     // 0: registry.v1.ListObjectsRequest
+	(*GetObjectRequest)(nil),       // 1: registry.v1.GetObjectRequest
+	(*CreateObjectRequest)(nil),    // 2: registry.v1.CreateObjectRequest
+	(*UpdateObjectRequest)(nil),    // 3: registry.v1.UpdateObjectRequest
+	(*DeleteObjectRequest)(nil),    // 4: registry.v1.DeleteObjectRequest
+	(*PreviewDeleteRequest)(nil),   // 5: registry.v1.PreviewDeleteRequest
+	(*RenameObjectRequest)(nil),    // 6: registry.v1.RenameObjectRequest
+	(*GetObjectUsageRequest)(nil),  // 7: registry.v1.GetObjectUsageRequest
+	(*ListFieldsRequest)(nil),      // 8: registry.v1.ListFieldsRequest
+	(*GetFieldRequest)(nil),        // 9: registry.v1.GetFieldRequest
+	(*CreateFieldRequest)(nil),     // 10: registry.v1.CreateFieldRequest
+	(*UpdateFieldRequest)(nil),     // 11: registry.v1.UpdateFieldRequest
+	(*DeleteFieldRequest)(nil),     // 12: registry.v1.DeleteFieldRequest
+	(*RenameFieldRequest)(nil),     // 13: registry.v1.RenameFieldRequest
+	(*GenerateClientRequest)(nil),  // 14: registry.v1.GenerateClientRequest
+	(*ListObjectsResponse)(nil),    // 15: registry.v1.ListObjectsResponse
+	(*GetObjectResponse)(nil),      // 16: registry.v1.GetObjectResponse
+	(*CreateObjectResponse)(nil),   // 17: registry.v1.CreateObjectResponse
+	(*UpdateObjectResponse)(nil),   // 18: registry.v1.UpdateObjectResponse
+	(*DeleteObjectResponse)(nil),   // 19: registry.v1.DeleteObjectResponse
+	(*PreviewDeleteResponse)(nil),  // 20: registry.v1.PreviewDeleteResponse
+	(*RenameObjectResponse)(nil),   // 21: registry.v1.RenameObjectResponse
+	(*GetObjectUsageResponse)(nil), // 22: registry.v1.GetObjectUsageResponse
+	(*ListFieldsResponse)(nil),     // 23: registry.v1.ListFieldsResponse
+	(*GetFieldResponse)(nil),       // 24: registry.v1.GetFieldResponse
+	(*CreateFieldResponse)(nil),    // 25: registry.v1.CreateFieldResponse
+	(*UpdateFieldResponse)(nil),    // 26: registry.v1.UpdateFieldResponse
+	(*DeleteFieldResponse)(nil),    // 27: registry.v1.DeleteFieldResponse
+	(*RenameFieldResponse)(nil),    // 28: registry.v1.RenameFieldResponse
+	(*GenerateClientResponse)(nil), // 29: registry.v1.GenerateClientResponse
 }
 var file_registry_v1_metadata_service_proto_depIdxs = []int32{
 	0,  // 0: registry.v1.MetadataService.ListObjects:input_type -> registry.v1.ListObjectsRequest
@@ -68,23 +83,33 @@ var file_registry_v1_metadata_service_proto_depIdxs = []int32{
 	2,  // 2: registry.v1.MetadataService.CreateObject:input_type -> registry.v1.CreateObjectRequest
 	3,  // 3: registry.v1.MetadataService.UpdateObject:input_type -> registry.v1.UpdateObjectRequest
 	4,  // 4: registry.v1.MetadataService.DeleteObject:input_type -> registry.v1.DeleteObjectRequest
-	5,  // 5: registry.v1.MetadataService.ListFields:input_type -> registry.v1.ListFieldsRequest
-	6,  // 6: registry.v1.MetadataService.GetField:input_type -> registry.v1.GetFieldRequest
-	7,  // 7: registry.v1.MetadataService.CreateField:input_type -> registry.v1.CreateFieldRequest
-	8,  // 8: registry.v1.MetadataService.UpdateField:input_type -> registry.v1.UpdateFieldRequest
-	9,  // 9: registry.v1.MetadataService.DeleteField:input_type -> registry.v1.DeleteFieldRequest
-	10, // 10: registry.v1.MetadataService.ListObjects:output_type -> registry.v1.ListObjectsResponse
-	11, // 11: registry.v1.MetadataService.GetObject:output_type -> registry.v1.GetObjectResponse
-	12, // 12: registry.v1.MetadataService.CreateObject:output_type -> registry.v1.CreateObjectResponse
-	13, // 13: registry.v1.MetadataService.UpdateObject:output_type -> registry.v1.UpdateObjectResponse
-	14, // 14: registry.v1.MetadataService.DeleteObject:output_type -> registry.v1.DeleteObjectResponse
-	15, // 15: registry.v1.MetadataService.ListFields:output_type -> registry.v1.ListFieldsResponse
-	16, // 16: registry.v1.MetadataService.GetField:output_type -> registry.v1.GetFieldResponse
-	17, // 17: registry.v1.MetadataService.CreateField:output_type -> registry.v1.CreateFieldResponse
-	18, // 18: registry.v1.MetadataService.UpdateField:output_type -> registry.v1.UpdateFieldResponse
-	19, // 19: registry.v1.MetadataService.DeleteField:output_type -> registry.v1.DeleteFieldResponse
-	10, // [10:20] is the sub-list for method output_type
-	0,  // [0:10] is the sub-list for method input_type
+	5,  // 5: registry.v1.MetadataService.PreviewDelete:input_type -> registry.v1.PreviewDeleteRequest
+	6,  // 6: registry.v1.MetadataService.RenameObject:input_type -> registry.v1.RenameObjectRequest
+	7,  // 7: registry.v1.MetadataService.GetObjectUsage:input_type -> registry.v1.GetObjectUsageRequest
+	8,  // 8: registry.v1.MetadataService.ListFields:input_type -> registry.v1.ListFieldsRequest
+	9,  // 9: registry.v1.MetadataService.GetField:input_type -> registry.v1.GetFieldRequest
+	10, // 10: registry.v1.MetadataService.CreateField:input_type -> registry.v1.CreateFieldRequest
+	11, // 11: registry.v1.MetadataService.UpdateField:input_type -> registry.v1.UpdateFieldRequest
+	12, // 12: registry.v1.MetadataService.DeleteField:input_type -> registry.v1.DeleteFieldRequest
+	13, // 13: registry.v1.MetadataService.RenameField:input_type -> registry.v1.RenameFieldRequest
+	14, // 14: registry.v1.MetadataService.GenerateClient:input_type -> registry.v1.GenerateClientRequest
+	15, // 15: registry.v1.MetadataService.ListObjects:output_type -> registry.v1.ListObjectsResponse
+	16, // 16: registry.v1.MetadataService.GetObject:output_type -> registry.v1.GetObjectResponse
+	17, // 17: registry.v1.MetadataService.CreateObject:output_type -> registry.v1.CreateObjectResponse
+	18, // 18: registry.v1.MetadataService.UpdateObject:output_type -> registry.v1.UpdateObjectResponse
+	19, // 19: registry.v1.MetadataService.DeleteObject:output_type -> registry.v1.DeleteObjectResponse
+	20, // 20: registry.v1.MetadataService.PreviewDelete:output_type -> registry.v1.PreviewDeleteResponse
+	21, // 21: registry.v1.MetadataService.RenameObject:output_type -> registry.v1.RenameObjectResponse
+	22, // 22: registry.v1.MetadataService.GetObjectUsage:output_type -> registry.v1.GetObjectUsageResponse
+	23, // 23: registry.v1.MetadataService.ListFields:output_type -> registry.v1.ListFieldsResponse
+	24, // 24: registry.v1.MetadataService.GetField:output_type -> registry.v1.GetFieldResponse
+	25, // 25: registry.v1.MetadataService.CreateField:output_type -> registry.v1.CreateFieldResponse
+	26, // 26: registry.v1.MetadataService.UpdateField:output_type -> registry.v1.UpdateFieldResponse
+	27, // 27: registry.v1.MetadataService.DeleteField:output_type -> registry.v1.DeleteFieldResponse
+	28, // 28: registry.v1.MetadataService.RenameField:output_type -> registry.v1.RenameFieldResponse
+	29, // 29: registry.v1.MetadataService.GenerateClient:output_type -> registry.v1.GenerateClientResponse
+	15, // [15:30] is the sub-list for method output_type
+	0,  // [0:15] is the sub-list for method input_type
 	0,  // [0:0] is the sub-list for extension type_name
 	0,  // [0:0] is the sub-list for extension extendee
 	0,  // [0:0] is the sub-list for field type_name
@@ -32,12 +32,26 @@ type QueryRequest struct {
 	Select string `protobuf:"bytes,2,opt,name=select,proto3" json:"select,omitempty"`
 	Expand string `protobuf:"bytes,3,opt,name=expand,proto3" json:"expand,omitempty"`
 	Order  string `protobuf:"bytes,4,opt,name=order,proto3" json:"order,omitempty"`
+	// Page size: up to the object's max page size of records (200 by default), or up to 10000 IDs for a query ending in ids.
 	Limit  int32  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
 	Cursor string `protobuf:"bytes,6,opt,name=cursor,proto3" json:"cursor,omitempty"`
 	// UUID of the employee context (the "self" pronoun). Required when query references "self".
-	SelfId        string `protobuf:"bytes,7,opt,name=self_id,json=selfId,proto3" json:"self_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	SelfId string `protobuf:"bytes,7,opt,name=self_id,json=selfId,proto3" json:"self_id,omitempty"`
+	// Evaluate the query against historical state at this date (YYYY-MM-DD) or
+	// RFC 3339 timestamp. Equivalent to an as_of(...) pipe step.
+	AsOf string `protobuf:"bytes,8,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
+	// Run the query even when the planner's estimate exceeds the server's cost
+	// ceilings. Requires the hrql:unbounded permission.
+	SkipCostCheck bool `protobuf:"varint,9,opt,name=skip_cost_check,json=skipCostCheck,proto3" json:"skip_cost_check,omitempty"`
+	// IANA time zone (e.g. "Europe/Berlin") the calendar helpers
+	// (start_of_month(), this_quarter(), ...) resolve "now" in. Defaults to UTC.
+	TimeZone string `protobuf:"bytes,10,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	// Keep employees who have left (end_date on or before today, or before the
+	// as_of moment) in the results of org functions, which leave them out by
+	// default. An include_terminated: argument on a call overrides it.
+	IncludeTerminated bool `protobuf:"varint,11,opt,name=include_terminated,json=includeTerminated,proto3" json:"include_terminated,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *QueryRequest) Reset() {
@@ -119,6 +133,34 @@ func (x *QueryRequest) GetSelfId() string {
 	return ""
 }
 
+func (x *QueryRequest) GetAsOf() string {
+	if x != nil {
+		return x.AsOf
+	}
+	return ""
+}
+
+func (x *QueryRequest) GetSkipCostCheck() bool {
+	if x != nil {
+		return x.SkipCostCheck
+	}
+	return false
+}
+
+func (x *QueryRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *QueryRequest) GetIncludeTerminated() bool {
+	if x != nil {
+		return x.IncludeTerminated
+	}
+	return false
+}
+
 type QueryResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// List results (org functions, employees | where).
@@ -128,7 +170,27 @@ type QueryResponse struct {
 	// Boolean result (reports_to).
 	ReportsTo *bool `protobuf:"varint,4,opt,name=reports_to,json=reportsTo,proto3,oneof" json:"reports_to,omitempty"`
 	// Scalar result (aggregation output like count, avg, sum, min, max).
-	Scalar        *float64 `protobuf:"fixed64,5,opt,name=scalar,proto3,oneof" json:"scalar,omitempty"`
+	Scalar *float64 `protobuf:"fixed64,5,opt,name=scalar,proto3,oneof" json:"scalar,omitempty"`
+	// Constructs the query used that were approximated or ignored.
+	Warnings []*QueryWarning `protobuf:"bytes,6,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	// Set instead of scalar when the scalar result is NULL: avg, min or max
+	// over no values, or a division by zero. Use ?? in the query for a default.
+	ScalarNull bool `protobuf:"varint,7,opt,name=scalar_null,json=scalarNull,proto3" json:"scalar_null,omitempty"`
+	// Set when total_count (then -1) of a list result could not be resolved;
+	// the results themselves are complete.
+	CountUnknown bool `protobuf:"varint,8,opt,name=count_unknown,json=countUnknown,proto3" json:"count_unknown,omitempty"`
+	// Bucket result (bucket(.field, [b1, b2, ...])): one entry per range, in
+	// order, including empty ones.
+	Buckets []*QueryBucket `protobuf:"bytes,9,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	// IDs result (... | ids): the record IDs of the page, in order.
+	Ids []string `protobuf:"bytes,10,rep,name=ids,proto3" json:"ids,omitempty"`
+	// How the server interpreted a list or ids query; unset for other
+	// results and for union.
+	QueryEcho *QueryEcho `protobuf:"bytes,11,opt,name=query_echo,json=queryEcho,proto3" json:"query_echo,omitempty"`
+	// The result typed by the kind of query: list, scalar and boolean results
+	// are set here as well as in results, scalar/scalar_null and reports_to.
+	// Unset for ids and bucket results, which have typed fields of their own.
+	Result        *ResultValue `protobuf:"bytes,12,opt,name=result,proto3" json:"result,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -198,79 +260,1773 @@ func (x *QueryResponse) GetScalar() float64 {
 	return 0
 }
 
-var File_registry_v1_org_service_proto protoreflect.FileDescriptor
+func (x *QueryResponse) GetWarnings() []*QueryWarning {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
 
-const file_registry_v1_org_service_proto_rawDesc = "" +
-	"\n" +
-	"\x1dregistry/v1/org_service.proto\x12\vregistry.v1\x1a\x1bbuf/validate/validate.proto\x1a\x1cgoogle/api/annotations.proto\x1a\x1cgoogle/protobuf/struct.proto\"\xc6\x01\n" +
-	"\fQueryRequest\x12\x1d\n" +
-	"\x05query\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\x05query\x12\x16\n" +
-	"\x06select\x18\x02 \x01(\tR\x06select\x12\x16\n" +
-	"\x06expand\x18\x03 \x01(\tR\x06expand\x12\x14\n" +
-	"\x05order\x18\x04 \x01(\tR\x05order\x12 \n" +
-	"\x05limit\x18\x05 \x01(\x05B\n" +
-	"\xbaH\a\x1a\x05\x18\xc8\x01(\x00R\x05limit\x12\x16\n" +
-	"\x06cursor\x18\x06 \x01(\tR\x06cursor\x12\x17\n" +
-	"\aself_id\x18\a \x01(\tR\x06selfId\"\xf4\x01\n" +
-	"\rQueryResponse\x121\n" +
-	"\aresults\x18\x01 \x03(\v2\x17.google.protobuf.StructR\aresults\x12\x1f\n" +
-	"\vtotal_count\x18\x02 \x01(\x03R\n" +
-	"totalCount\x12$\n" +
-	"\vnext_cursor\x18\x03 \x01(\tH\x00R\n" +
-	"nextCursor\x88\x01\x01\x12\"\n" +
-	"\n" +
-	"reports_to\x18\x04 \x01(\bH\x01R\treportsTo\x88\x01\x01\x12\x1b\n" +
-	"\x06scalar\x18\x05 \x01(\x01H\x02R\x06scalar\x88\x01\x01B\x0e\n" +
-	"\f_next_cursorB\r\n" +
-	"\v_reports_toB\t\n" +
-	"\a_scalar2g\n" +
-	"\n" +
-	"OrgService\x12Y\n" +
-	"\x05Query\x12\x19.registry.v1.QueryRequest\x1a\x1a.registry.v1.QueryResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/api/org/queryB\xaf\x01\n" +
-	"\x0fcom.registry.v1B\x0fOrgServiceProtoP\x01Z>github.com/atlekbai/schema_registry/gen/registry/v1;registryv1\xa2\x02\x03RXX\xaa\x02\vRegistry.V1\xca\x02\vRegistry\\V1\xe2\x02\x17Registry\\V1\\GPBMetadata\xea\x02\fRegistry::V1b\x06proto3"
+func (x *QueryResponse) GetScalarNull() bool {
+	if x != nil {
+		return x.ScalarNull
+	}
+	return false
+}
 
-var (
-	file_registry_v1_org_service_proto_rawDescOnce sync.Once
-	file_registry_v1_org_service_proto_rawDescData []byte
-)
+func (x *QueryResponse) GetCountUnknown() bool {
+	if x != nil {
+		return x.CountUnknown
+	}
+	return false
+}
 
-func file_registry_v1_org_service_proto_rawDescGZIP() []byte {
-	file_registry_v1_org_service_proto_rawDescOnce.Do(func() {
-		file_registry_v1_org_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_registry_v1_org_service_proto_rawDesc), len(file_registry_v1_org_service_proto_rawDesc)))
-	})
-	return file_registry_v1_org_service_proto_rawDescData
+func (x *QueryResponse) GetBuckets() []*QueryBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
 }
 
-var file_registry_v1_org_service_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
-var file_registry_v1_org_service_proto_goTypes = []any{
-	(*QueryRequest)(nil),    // 0: registry.v1.QueryRequest
-	(*QueryResponse)(nil),   // 1: registry.v1.QueryResponse
-	(*structpb.Struct)(nil), // 2: google.protobuf.Struct
+func (x *QueryResponse) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
 }
-var file_registry_v1_org_service_proto_depIdxs = []int32{
-	2, // 0: registry.v1.QueryResponse.results:type_name -> google.protobuf.Struct
-	0, // 1: registry.v1.OrgService.Query:input_type -> registry.v1.QueryRequest
-	1, // 2: registry.v1.OrgService.Query:output_type -> registry.v1.QueryResponse
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+
+func (x *QueryResponse) GetQueryEcho() *QueryEcho {
+	if x != nil {
+		return x.QueryEcho
+	}
+	return nil
 }
 
-func init() { file_registry_v1_org_service_proto_init() }
-func file_registry_v1_org_service_proto_init() {
-	if File_registry_v1_org_service_proto != nil {
-		return
+func (x *QueryResponse) GetResult() *ResultValue {
+	if x != nil {
+		return x.Result
 	}
-	file_registry_v1_org_service_proto_msgTypes[1].OneofWrappers = []any{}
+	return nil
+}
+
+// ResultValue is an HRQL result in the type it has in the query, so clients
+// need not infer it: count is an integer, other aggregates and arithmetic
+// doubles, min or max of a date or text field the value as text. A field of
+// a picked record (first | .field) is a double, a bool or text by its type.
+type ResultValue struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Value:
+	//
+	//	*ResultValue_IntValue
+	//	*ResultValue_DoubleValue
+	//	*ResultValue_BoolValue
+	//	*ResultValue_StringValue
+	//	*ResultValue_ListValue
+	//	*ResultValue_NullValue
+	Value         isResultValue_Value `protobuf_oneof:"value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResultValue) Reset() {
+	*x = ResultValue{}
+	mi := &file_registry_v1_org_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResultValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResultValue) ProtoMessage() {}
+
+func (x *ResultValue) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_org_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResultValue.ProtoReflect.Descriptor instead.
+func (*ResultValue) Descriptor() ([]byte, []int) {
+	return file_registry_v1_org_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ResultValue) GetValue() isResultValue_Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *ResultValue) GetIntValue() int64 {
+	if x != nil {
+		if x, ok := x.Value.(*ResultValue_IntValue); ok {
+			return x.IntValue
+		}
+	}
+	return 0
+}
+
+func (x *ResultValue) GetDoubleValue() float64 {
+	if x != nil {
+		if x, ok := x.Value.(*ResultValue_DoubleValue); ok {
+			return x.DoubleValue
+		}
+	}
+	return 0
+}
+
+func (x *ResultValue) GetBoolValue() bool {
+	if x != nil {
+		if x, ok := x.Value.(*ResultValue_BoolValue); ok {
+			return x.BoolValue
+		}
+	}
+	return false
+}
+
+func (x *ResultValue) GetStringValue() string {
+	if x != nil {
+		if x, ok := x.Value.(*ResultValue_StringValue); ok {
+			return x.StringValue
+		}
+	}
+	return ""
+}
+
+func (x *ResultValue) GetListValue() *StructList {
+	if x != nil {
+		if x, ok := x.Value.(*ResultValue_ListValue); ok {
+			return x.ListValue
+		}
+	}
+	return nil
+}
+
+func (x *ResultValue) GetNullValue() structpb.NullValue {
+	if x != nil {
+		if x, ok := x.Value.(*ResultValue_NullValue); ok {
+			return x.NullValue
+		}
+	}
+	return structpb.NullValue(0)
+}
+
+type isResultValue_Value interface {
+	isResultValue_Value()
+}
+
+type ResultValue_IntValue struct {
+	IntValue int64 `protobuf:"varint,1,opt,name=int_value,json=intValue,proto3,oneof"`
+}
+
+type ResultValue_DoubleValue struct {
+	DoubleValue float64 `protobuf:"fixed64,2,opt,name=double_value,json=doubleValue,proto3,oneof"`
+}
+
+type ResultValue_BoolValue struct {
+	BoolValue bool `protobuf:"varint,3,opt,name=bool_value,json=boolValue,proto3,oneof"`
+}
+
+type ResultValue_StringValue struct {
+	StringValue string `protobuf:"bytes,4,opt,name=string_value,json=stringValue,proto3,oneof"`
+}
+
+type ResultValue_ListValue struct {
+	// The page of records of a list result.
+	ListValue *StructList `protobuf:"bytes,5,opt,name=list_value,json=listValue,proto3,oneof"`
+}
+
+type ResultValue_NullValue struct {
+	// An aggregate over no values, a division by zero, or a boolean that
+	// could not be decided.
+	NullValue structpb.NullValue `protobuf:"varint,6,opt,name=null_value,json=nullValue,proto3,enum=google.protobuf.NullValue,oneof"`
+}
+
+func (*ResultValue_IntValue) isResultValue_Value() {}
+
+func (*ResultValue_DoubleValue) isResultValue_Value() {}
+
+func (*ResultValue_BoolValue) isResultValue_Value() {}
+
+func (*ResultValue_StringValue) isResultValue_Value() {}
+
+func (*ResultValue_ListValue) isResultValue_Value() {}
+
+func (*ResultValue_NullValue) isResultValue_Value() {}
+
+type StructList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []*structpb.Struct     `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StructList) Reset() {
+	*x = StructList{}
+	mi := &file_registry_v1_org_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StructList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StructList) ProtoMessage() {}
+
+func (x *StructList) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_org_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StructList.ProtoReflect.Descriptor instead.
+func (*StructList) Descriptor() ([]byte, []int) {
+	return file_registry_v1_org_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StructList) GetValues() []*structpb.Struct {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+// QueryBucket counts the records whose value falls in [lower, upper).
+type QueryBucket struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Absent for the range below the first bound.
+	Lower *float64 `protobuf:"fixed64,1,opt,name=lower,proto3,oneof" json:"lower,omitempty"`
+	// Absent for the range from the last bound up.
+	Upper         *float64 `protobuf:"fixed64,2,opt,name=upper,proto3,oneof" json:"upper,omitempty"`
+	Count         int64    `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryBucket) Reset() {
+	*x = QueryBucket{}
+	mi := &file_registry_v1_org_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryBucket) ProtoMessage() {}
+
+func (x *QueryBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_org_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryBucket.ProtoReflect.Descriptor instead.
+func (*QueryBucket) Descriptor() ([]byte, []int) {
+	return file_registry_v1_org_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *QueryBucket) GetLower() float64 {
+	if x != nil && x.Lower != nil {
+		return *x.Lower
+	}
+	return 0
+}
+
+func (x *QueryBucket) GetUpper() float64 {
+	if x != nil && x.Upper != nil {
+		return *x.Upper
+	}
+	return 0
+}
+
+func (x *QueryBucket) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// QueryCostExceeded is attached to FAILED_PRECONDITION errors when the
+// planner's estimate for an HRQL query exceeds the server's ceilings. Narrow
+// the query with filters, or retry with skip_cost_check if permitted.
+type QueryCostExceeded struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Always "QUERY_COST_EXCEEDED".
+	Reason string `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Planner estimate for the query, in PostgreSQL cost units.
+	EstimatedCost float64 `protobuf:"fixed64,2,opt,name=estimated_cost,json=estimatedCost,proto3" json:"estimated_cost,omitempty"`
+	// Most rows any step of the query is estimated to produce.
+	EstimatedRows int64 `protobuf:"varint,3,opt,name=estimated_rows,json=estimatedRows,proto3" json:"estimated_rows,omitempty"`
+	// Configured ceilings; 0 means no ceiling.
+	MaxCost       float64 `protobuf:"fixed64,4,opt,name=max_cost,json=maxCost,proto3" json:"max_cost,omitempty"`
+	MaxRows       int64   `protobuf:"varint,5,opt,name=max_rows,json=maxRows,proto3" json:"max_rows,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryCostExceeded) Reset() {
+	*x = QueryCostExceeded{}
+	mi := &file_registry_v1_org_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryCostExceeded) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryCostExceeded) ProtoMessage() {}
+
+func (x *QueryCostExceeded) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_org_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryCostExceeded.ProtoReflect.Descriptor instead.
+func (*QueryCostExceeded) Descriptor() ([]byte, []int) {
+	return file_registry_v1_org_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *QueryCostExceeded) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *QueryCostExceeded) GetEstimatedCost() float64 {
+	if x != nil {
+		return x.EstimatedCost
+	}
+	return 0
+}
+
+func (x *QueryCostExceeded) GetEstimatedRows() int64 {
+	if x != nil {
+		return x.EstimatedRows
+	}
+	return 0
+}
+
+func (x *QueryCostExceeded) GetMaxCost() float64 {
+	if x != nil {
+		return x.MaxCost
+	}
+	return 0
+}
+
+func (x *QueryCostExceeded) GetMaxRows() int64 {
+	if x != nil {
+		return x.MaxRows
+	}
+	return 0
+}
+
+// QueryWarning reports an HRQL construct that was accepted but approximated,
+// so the result may not mean exactly what the query says.
+type QueryWarning struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Stable identifier: "no_op" (a function with no effect, such as unique,
+	// was ignored) or "lookup_chain_truncated" (only the first field of a
+	// chain such as .department.title was used).
+	Code          string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryWarning) Reset() {
+	*x = QueryWarning{}
+	mi := &file_registry_v1_org_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryWarning) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryWarning) ProtoMessage() {}
+
+func (x *QueryWarning) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_org_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryWarning.ProtoReflect.Descriptor instead.
+func (*QueryWarning) Descriptor() ([]byte, []int) {
+	return file_registry_v1_org_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *QueryWarning) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *QueryWarning) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ToFiltersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// HRQL expression to convert.
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// UUID of the employee context, if the query references "self".
+	SelfId string `protobuf:"bytes,2,opt,name=self_id,json=selfId,proto3" json:"self_id,omitempty"`
+	// IANA time zone for the calendar helpers, as in QueryRequest.
+	TimeZone      string `protobuf:"bytes,3,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ToFiltersRequest) Reset() {
+	*x = ToFiltersRequest{}
+	mi := &file_registry_v1_org_service_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ToFiltersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ToFiltersRequest) ProtoMessage() {}
+
+func (x *ToFiltersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_org_service_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ToFiltersRequest.ProtoReflect.Descriptor instead.
+func (*ToFiltersRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_org_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ToFiltersRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *ToFiltersRequest) GetSelfId() string {
+	if x != nil {
+		return x.SelfId
+	}
+	return ""
+}
+
+func (x *ToFiltersRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+type ToFiltersResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// True when the expression maps onto REST filters.
+	Translatable bool `protobuf:"varint,1,opt,name=translatable,proto3" json:"translatable,omitempty"`
+	// Filters for GET /api/employees, keyed by field API name ("op.value").
+	Filters map[string]string `protobuf:"bytes,2,rep,name=filters,proto3" json:"filters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Why the expression cannot be expressed as filters (when translatable is false).
+	Reason string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Constructs the query used that were approximated or ignored.
+	Warnings      []*QueryWarning `protobuf:"bytes,4,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ToFiltersResponse) Reset() {
+	*x = ToFiltersResponse{}
+	mi := &file_registry_v1_org_service_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ToFiltersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ToFiltersResponse) ProtoMessage() {}
+
+func (x *ToFiltersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_org_service_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ToFiltersResponse.ProtoReflect.Descriptor instead.
+func (*ToFiltersResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_org_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ToFiltersResponse) GetTranslatable() bool {
+	if x != nil {
+		return x.Translatable
+	}
+	return false
+}
+
+func (x *ToFiltersResponse) GetFilters() map[string]string {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+func (x *ToFiltersResponse) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ToFiltersResponse) GetWarnings() []*QueryWarning {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+type ExplainRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// HRQL expression to parse.
+	Query         string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExplainRequest) Reset() {
+	*x = ExplainRequest{}
+	mi := &file_registry_v1_org_service_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExplainRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExplainRequest) ProtoMessage() {}
+
+func (x *ExplainRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_org_service_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExplainRequest.ProtoReflect.Descriptor instead.
+func (*ExplainRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_org_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ExplainRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+type ExplainResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The AST: nested nodes, each with a "type" (pipe, field, call, where,
+	// binary, literal, ...) and "pos", the byte offset of the token that
+	// introduces it (the operator for binary and in nodes). The other keys
+	// depend on the type; see parser.NodeJSON.
+	Ast           *structpb.Struct `protobuf:"bytes,1,opt,name=ast,proto3" json:"ast,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExplainResponse) Reset() {
+	*x = ExplainResponse{}
+	mi := &file_registry_v1_org_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExplainResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExplainResponse) ProtoMessage() {}
+
+func (x *ExplainResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_org_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExplainResponse.ProtoReflect.Descriptor instead.
+func (*ExplainResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_org_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ExplainResponse) GetAst() *structpb.Struct {
+	if x != nil {
+		return x.Ast
+	}
+	return nil
+}
+
+type BatchEvaluateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Items []*BatchEvaluateItem   `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	// UUID of the employee context for items whose query references "self".
+	SelfId string `protobuf:"bytes,2,opt,name=self_id,json=selfId,proto3" json:"self_id,omitempty"`
+	// Evaluate every item against historical state at this date (YYYY-MM-DD) or
+	// RFC 3339 timestamp.
+	AsOf          string `protobuf:"bytes,3,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchEvaluateRequest) Reset() {
+	*x = BatchEvaluateRequest{}
+	mi := &file_registry_v1_org_service_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchEvaluateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchEvaluateRequest) ProtoMessage() {}
+
+func (x *BatchEvaluateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_org_service_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchEvaluateRequest.ProtoReflect.Descriptor instead.
+func (*BatchEvaluateRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_org_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BatchEvaluateRequest) GetItems() []*BatchEvaluateItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *BatchEvaluateRequest) GetSelfId() string {
+	if x != nil {
+		return x.SelfId
+	}
+	return ""
+}
+
+func (x *BatchEvaluateRequest) GetAsOf() string {
+	if x != nil {
+		return x.AsOf
+	}
+	return ""
+}
+
+type BatchEvaluateItem struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Check:
+	//
+	//	*BatchEvaluateItem_Query
+	//	*BatchEvaluateItem_ReportsTo
+	Check         isBatchEvaluateItem_Check `protobuf_oneof:"check"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchEvaluateItem) Reset() {
+	*x = BatchEvaluateItem{}
+	mi := &file_registry_v1_org_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchEvaluateItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchEvaluateItem) ProtoMessage() {}
+
+func (x *BatchEvaluateItem) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_org_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchEvaluateItem.ProtoReflect.Descriptor instead.
+func (*BatchEvaluateItem) Descriptor() ([]byte, []int) {
+	return file_registry_v1_org_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *BatchEvaluateItem) GetCheck() isBatchEvaluateItem_Check {
+	if x != nil {
+		return x.Check
+	}
+	return nil
+}
+
+func (x *BatchEvaluateItem) GetQuery() string {
+	if x != nil {
+		if x, ok := x.Check.(*BatchEvaluateItem_Query); ok {
+			return x.Query
+		}
+	}
+	return ""
+}
+
+func (x *BatchEvaluateItem) GetReportsTo() *ReportsToPair {
+	if x != nil {
+		if x, ok := x.Check.(*BatchEvaluateItem_ReportsTo); ok {
+			return x.ReportsTo
+		}
+	}
+	return nil
+}
+
+type isBatchEvaluateItem_Check interface {
+	isBatchEvaluateItem_Check()
+}
+
+type BatchEvaluateItem_Query struct {
+	// Boolean HRQL expression, e.g. "reports_to(self.manager, \"<uuid>\")".
+	Query string `protobuf:"bytes,1,opt,name=query,proto3,oneof"`
+}
+
+type BatchEvaluateItem_ReportsTo struct {
+	// Shorthand for reports_to("<employee_id>", "<manager_id>").
+	ReportsTo *ReportsToPair `protobuf:"bytes,2,opt,name=reports_to,json=reportsTo,proto3,oneof"`
+}
+
+func (*BatchEvaluateItem_Query) isBatchEvaluateItem_Check() {}
+
+func (*BatchEvaluateItem_ReportsTo) isBatchEvaluateItem_Check() {}
+
+type ReportsToPair struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EmployeeId    string                 `protobuf:"bytes,1,opt,name=employee_id,json=employeeId,proto3" json:"employee_id,omitempty"`
+	ManagerId     string                 `protobuf:"bytes,2,opt,name=manager_id,json=managerId,proto3" json:"manager_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportsToPair) Reset() {
+	*x = ReportsToPair{}
+	mi := &file_registry_v1_org_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportsToPair) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportsToPair) ProtoMessage() {}
+
+func (x *ReportsToPair) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_org_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportsToPair.ProtoReflect.Descriptor instead.
+func (*ReportsToPair) Descriptor() ([]byte, []int) {
+	return file_registry_v1_org_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ReportsToPair) GetEmployeeId() string {
+	if x != nil {
+		return x.EmployeeId
+	}
+	return ""
+}
+
+func (x *ReportsToPair) GetManagerId() string {
+	if x != nil {
+		return x.ManagerId
+	}
+	return ""
+}
+
+type BatchEvaluateResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One result per request item, in the same order.
+	Results       []*BatchEvaluateResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchEvaluateResponse) Reset() {
+	*x = BatchEvaluateResponse{}
+	mi := &file_registry_v1_org_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchEvaluateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchEvaluateResponse) ProtoMessage() {}
+
+func (x *BatchEvaluateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_org_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchEvaluateResponse.ProtoReflect.Descriptor instead.
+func (*BatchEvaluateResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_org_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *BatchEvaluateResponse) GetResults() []*BatchEvaluateResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type BatchEvaluateResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unset when either employee does not exist or the item failed.
+	Result *bool `protobuf:"varint,1,opt,name=result,proto3,oneof" json:"result,omitempty"`
+	// Why the item could not be evaluated (parse/compile errors, non-boolean query).
+	Error         string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchEvaluateResult) Reset() {
+	*x = BatchEvaluateResult{}
+	mi := &file_registry_v1_org_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchEvaluateResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchEvaluateResult) ProtoMessage() {}
+
+func (x *BatchEvaluateResult) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_org_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchEvaluateResult.ProtoReflect.Descriptor instead.
+func (*BatchEvaluateResult) Descriptor() ([]byte, []int) {
+	return file_registry_v1_org_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *BatchEvaluateResult) GetResult() bool {
+	if x != nil && x.Result != nil {
+		return *x.Result
+	}
+	return false
+}
+
+func (x *BatchEvaluateResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type DiffRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Compare the state at from with the state at to; each is a date
+	// (YYYY-MM-DD, meaning its start) or an RFC 3339 timestamp.
+	From string `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To   string `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	// Only report employees in this employee's subtree at either date (the
+	// employee included).
+	RootId string `protobuf:"bytes,3,opt,name=root_id,json=rootId,proto3" json:"root_id,omitempty"`
+	// Maximum number of changed employees to return (0-5000, 0 means 1000).
+	Limit         int32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffRequest) Reset() {
+	*x = DiffRequest{}
+	mi := &file_registry_v1_org_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffRequest) ProtoMessage() {}
+
+func (x *DiffRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_org_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffRequest.ProtoReflect.Descriptor instead.
+func (*DiffRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_org_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DiffRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *DiffRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *DiffRequest) GetRootId() string {
+	if x != nil {
+		return x.RootId
+	}
+	return ""
+}
+
+func (x *DiffRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type DiffResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Changes ordered by employee id; an employee may have several.
+	Changes []*OrgChange `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	Summary *DiffSummary `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"`
+	// More employees changed than limit; narrow the window or set root_id.
+	Truncated     bool `protobuf:"varint,3,opt,name=truncated,proto3" json:"truncated,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffResponse) Reset() {
+	*x = DiffResponse{}
+	mi := &file_registry_v1_org_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffResponse) ProtoMessage() {}
+
+func (x *DiffResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_org_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffResponse.ProtoReflect.Descriptor instead.
+func (*DiffResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_org_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *DiffResponse) GetChanges() []*OrgChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *DiffResponse) GetSummary() *DiffSummary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+func (x *DiffResponse) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+type OrgChange struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	EmployeeId string                 `protobuf:"bytes,1,opt,name=employee_id,json=employeeId,proto3" json:"employee_id,omitempty"`
+	// "hire", "departure", "manager_change" or "department_change". An
+	// employee who is gone at to, or whose end_date passed in between, departs.
+	Kind string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	// Display name, as of to (as of from for employees no longer present).
+	Display string `protobuf:"bytes,3,opt,name=display,proto3" json:"display,omitempty"`
+	// Manager and department ids before and after the change; empty when
+	// unset or, for hires and departures, on the side the employee is absent.
+	FromManagerId    string `protobuf:"bytes,4,opt,name=from_manager_id,json=fromManagerId,proto3" json:"from_manager_id,omitempty"`
+	ToManagerId      string `protobuf:"bytes,5,opt,name=to_manager_id,json=toManagerId,proto3" json:"to_manager_id,omitempty"`
+	FromDepartmentId string `protobuf:"bytes,6,opt,name=from_department_id,json=fromDepartmentId,proto3" json:"from_department_id,omitempty"`
+	ToDepartmentId   string `protobuf:"bytes,7,opt,name=to_department_id,json=toDepartmentId,proto3" json:"to_department_id,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *OrgChange) Reset() {
+	*x = OrgChange{}
+	mi := &file_registry_v1_org_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrgChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrgChange) ProtoMessage() {}
+
+func (x *OrgChange) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_org_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrgChange.ProtoReflect.Descriptor instead.
+func (*OrgChange) Descriptor() ([]byte, []int) {
+	return file_registry_v1_org_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *OrgChange) GetEmployeeId() string {
+	if x != nil {
+		return x.EmployeeId
+	}
+	return ""
+}
+
+func (x *OrgChange) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *OrgChange) GetDisplay() string {
+	if x != nil {
+		return x.Display
+	}
+	return ""
+}
+
+func (x *OrgChange) GetFromManagerId() string {
+	if x != nil {
+		return x.FromManagerId
+	}
+	return ""
+}
+
+func (x *OrgChange) GetToManagerId() string {
+	if x != nil {
+		return x.ToManagerId
+	}
+	return ""
+}
+
+func (x *OrgChange) GetFromDepartmentId() string {
+	if x != nil {
+		return x.FromDepartmentId
+	}
+	return ""
+}
+
+func (x *OrgChange) GetToDepartmentId() string {
+	if x != nil {
+		return x.ToDepartmentId
+	}
+	return ""
+}
+
+type DiffSummary struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Hires             int32                  `protobuf:"varint,1,opt,name=hires,proto3" json:"hires,omitempty"`
+	Departures        int32                  `protobuf:"varint,2,opt,name=departures,proto3" json:"departures,omitempty"`
+	ManagerChanges    int32                  `protobuf:"varint,3,opt,name=manager_changes,json=managerChanges,proto3" json:"manager_changes,omitempty"`
+	DepartmentChanges int32                  `protobuf:"varint,4,opt,name=department_changes,json=departmentChanges,proto3" json:"department_changes,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *DiffSummary) Reset() {
+	*x = DiffSummary{}
+	mi := &file_registry_v1_org_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffSummary) ProtoMessage() {}
+
+func (x *DiffSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_org_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffSummary.ProtoReflect.Descriptor instead.
+func (*DiffSummary) Descriptor() ([]byte, []int) {
+	return file_registry_v1_org_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *DiffSummary) GetHires() int32 {
+	if x != nil {
+		return x.Hires
+	}
+	return 0
+}
+
+func (x *DiffSummary) GetDepartures() int32 {
+	if x != nil {
+		return x.Departures
+	}
+	return 0
+}
+
+func (x *DiffSummary) GetManagerChanges() int32 {
+	if x != nil {
+		return x.ManagerChanges
+	}
+	return 0
+}
+
+func (x *DiffSummary) GetDepartmentChanges() int32 {
+	if x != nil {
+		return x.DepartmentChanges
+	}
+	return 0
+}
+
+type ExecuteStreamRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// HRQL expression producing a list or ids, e.g. "reports(self) | where(.employment_type == \"FULL_TIME\") | ids".
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// As in QueryRequest; select and expand do not apply to ids.
+	Select            string `protobuf:"bytes,2,opt,name=select,proto3" json:"select,omitempty"`
+	Expand            string `protobuf:"bytes,3,opt,name=expand,proto3" json:"expand,omitempty"`
+	Order             string `protobuf:"bytes,4,opt,name=order,proto3" json:"order,omitempty"`
+	SelfId            string `protobuf:"bytes,5,opt,name=self_id,json=selfId,proto3" json:"self_id,omitempty"`
+	AsOf              string `protobuf:"bytes,6,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
+	TimeZone          string `protobuf:"bytes,7,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	IncludeTerminated bool   `protobuf:"varint,8,opt,name=include_terminated,json=includeTerminated,proto3" json:"include_terminated,omitempty"`
+	SkipCostCheck     bool   `protobuf:"varint,9,opt,name=skip_cost_check,json=skipCostCheck,proto3" json:"skip_cost_check,omitempty"`
+	// Records or ids per message, up to 1000; 0 means 100.
+	BatchSize int32 `protobuf:"varint,10,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`
+	// Records to stream at most; 0, or more than the server's stream row
+	// limit, means that limit. A limit in the query (first(n), sample(n))
+	// applies as well.
+	Limit         int64 `protobuf:"varint,11,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecuteStreamRequest) Reset() {
+	*x = ExecuteStreamRequest{}
+	mi := &file_registry_v1_org_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteStreamRequest) ProtoMessage() {}
+
+func (x *ExecuteStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_org_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteStreamRequest.ProtoReflect.Descriptor instead.
+func (*ExecuteStreamRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_org_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ExecuteStreamRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *ExecuteStreamRequest) GetSelect() string {
+	if x != nil {
+		return x.Select
+	}
+	return ""
+}
+
+func (x *ExecuteStreamRequest) GetExpand() string {
+	if x != nil {
+		return x.Expand
+	}
+	return ""
+}
+
+func (x *ExecuteStreamRequest) GetOrder() string {
+	if x != nil {
+		return x.Order
+	}
+	return ""
+}
+
+func (x *ExecuteStreamRequest) GetSelfId() string {
+	if x != nil {
+		return x.SelfId
+	}
+	return ""
+}
+
+func (x *ExecuteStreamRequest) GetAsOf() string {
+	if x != nil {
+		return x.AsOf
+	}
+	return ""
+}
+
+func (x *ExecuteStreamRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *ExecuteStreamRequest) GetIncludeTerminated() bool {
+	if x != nil {
+		return x.IncludeTerminated
+	}
+	return false
+}
+
+func (x *ExecuteStreamRequest) GetSkipCostCheck() bool {
+	if x != nil {
+		return x.SkipCostCheck
+	}
+	return false
+}
+
+func (x *ExecuteStreamRequest) GetBatchSize() int32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+func (x *ExecuteStreamRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ExecuteStreamResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// A batch of list results, in order.
+	Results []*structpb.Struct `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	// A batch of record IDs, for a query ending in ids.
+	Ids []string `protobuf:"bytes,2,rep,name=ids,proto3" json:"ids,omitempty"`
+	// Constructs the query used that were approximated or ignored; set on the
+	// first message only.
+	Warnings []*QueryWarning `protobuf:"bytes,3,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	// Set on the last message, which may carry a final batch.
+	Done bool `protobuf:"varint,4,opt,name=done,proto3" json:"done,omitempty"`
+	// Records streamed in all; set on the last message.
+	Total int64 `protobuf:"varint,5,opt,name=total,proto3" json:"total,omitempty"`
+	// Set on the last message when more records matched than the limit.
+	Truncated     bool `protobuf:"varint,6,opt,name=truncated,proto3" json:"truncated,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecuteStreamResponse) Reset() {
+	*x = ExecuteStreamResponse{}
+	mi := &file_registry_v1_org_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteStreamResponse) ProtoMessage() {}
+
+func (x *ExecuteStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_org_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteStreamResponse.ProtoReflect.Descriptor instead.
+func (*ExecuteStreamResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_org_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ExecuteStreamResponse) GetResults() []*structpb.Struct {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *ExecuteStreamResponse) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *ExecuteStreamResponse) GetWarnings() []*QueryWarning {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+func (x *ExecuteStreamResponse) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+func (x *ExecuteStreamResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ExecuteStreamResponse) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+var File_registry_v1_org_service_proto protoreflect.FileDescriptor
+
+const file_registry_v1_org_service_proto_rawDesc = "" +
+	"\n" +
+	"\x1dregistry/v1/org_service.proto\x12\vregistry.v1\x1a\x1bbuf/validate/validate.proto\x1a\x1cgoogle/api/annotations.proto\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1aregistry/v1/registry.proto\"\xcf\x02\n" +
+	"\fQueryRequest\x12\x1d\n" +
+	"\x05query\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\x05query\x12\x16\n" +
+	"\x06select\x18\x02 \x01(\tR\x06select\x12\x16\n" +
+	"\x06expand\x18\x03 \x01(\tR\x06expand\x12\x14\n" +
+	"\x05order\x18\x04 \x01(\tR\x05order\x12 \n" +
+	"\x05limit\x18\x05 \x01(\x05B\n" +
+	"\xbaH\a\x1a\x05\x18\x90N(\x00R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x06 \x01(\tR\x06cursor\x12\x17\n" +
+	"\aself_id\x18\a \x01(\tR\x06selfId\x12\x13\n" +
+	"\x05as_of\x18\b \x01(\tR\x04asOf\x12&\n" +
+	"\x0fskip_cost_check\x18\t \x01(\bR\rskipCostCheck\x12\x1b\n" +
+	"\ttime_zone\x18\n" +
+	" \x01(\tR\btimeZone\x12-\n" +
+	"\x12include_terminated\x18\v \x01(\bR\x11includeTerminated\"\xa0\x04\n" +
+	"\rQueryResponse\x121\n" +
+	"\aresults\x18\x01 \x03(\v2\x17.google.protobuf.StructR\aresults\x12\x1f\n" +
+	"\vtotal_count\x18\x02 \x01(\x03R\n" +
+	"totalCount\x12$\n" +
+	"\vnext_cursor\x18\x03 \x01(\tH\x00R\n" +
+	"nextCursor\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"reports_to\x18\x04 \x01(\bH\x01R\treportsTo\x88\x01\x01\x12\x1b\n" +
+	"\x06scalar\x18\x05 \x01(\x01H\x02R\x06scalar\x88\x01\x01\x125\n" +
+	"\bwarnings\x18\x06 \x03(\v2\x19.registry.v1.QueryWarningR\bwarnings\x12\x1f\n" +
+	"\vscalar_null\x18\a \x01(\bR\n" +
+	"scalarNull\x12#\n" +
+	"\rcount_unknown\x18\b \x01(\bR\fcountUnknown\x122\n" +
+	"\abuckets\x18\t \x03(\v2\x18.registry.v1.QueryBucketR\abuckets\x12\x10\n" +
+	"\x03ids\x18\n" +
+	" \x03(\tR\x03ids\x125\n" +
+	"\n" +
+	"query_echo\x18\v \x01(\v2\x16.registry.v1.QueryEchoR\tqueryEcho\x120\n" +
+	"\x06result\x18\f \x01(\v2\x18.registry.v1.ResultValueR\x06resultB\x0e\n" +
+	"\f_next_cursorB\r\n" +
+	"\v_reports_toB\t\n" +
+	"\a_scalar\"\x97\x02\n" +
+	"\vResultValue\x12\x1d\n" +
+	"\tint_value\x18\x01 \x01(\x03H\x00R\bintValue\x12#\n" +
+	"\fdouble_value\x18\x02 \x01(\x01H\x00R\vdoubleValue\x12\x1f\n" +
+	"\n" +
+	"bool_value\x18\x03 \x01(\bH\x00R\tboolValue\x12#\n" +
+	"\fstring_value\x18\x04 \x01(\tH\x00R\vstringValue\x128\n" +
+	"\n" +
+	"list_value\x18\x05 \x01(\v2\x17.registry.v1.StructListH\x00R\tlistValue\x12;\n" +
+	"\n" +
+	"null_value\x18\x06 \x01(\x0e2\x1a.google.protobuf.NullValueH\x00R\tnullValueB\a\n" +
+	"\x05value\"=\n" +
+	"\n" +
+	"StructList\x12/\n" +
+	"\x06values\x18\x01 \x03(\v2\x17.google.protobuf.StructR\x06values\"m\n" +
+	"\vQueryBucket\x12\x19\n" +
+	"\x05lower\x18\x01 \x01(\x01H\x00R\x05lower\x88\x01\x01\x12\x19\n" +
+	"\x05upper\x18\x02 \x01(\x01H\x01R\x05upper\x88\x01\x01\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x03R\x05countB\b\n" +
+	"\x06_lowerB\b\n" +
+	"\x06_upper\"\xaf\x01\n" +
+	"\x11QueryCostExceeded\x12\x16\n" +
+	"\x06reason\x18\x01 \x01(\tR\x06reason\x12%\n" +
+	"\x0eestimated_cost\x18\x02 \x01(\x01R\restimatedCost\x12%\n" +
+	"\x0eestimated_rows\x18\x03 \x01(\x03R\restimatedRows\x12\x19\n" +
+	"\bmax_cost\x18\x04 \x01(\x01R\amaxCost\x12\x19\n" +
+	"\bmax_rows\x18\x05 \x01(\x03R\amaxRows\"<\n" +
+	"\fQueryWarning\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"g\n" +
+	"\x10ToFiltersRequest\x12\x1d\n" +
+	"\x05query\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\x05query\x12\x17\n" +
+	"\aself_id\x18\x02 \x01(\tR\x06selfId\x12\x1b\n" +
+	"\ttime_zone\x18\x03 \x01(\tR\btimeZone\"\x89\x02\n" +
+	"\x11ToFiltersResponse\x12\"\n" +
+	"\ftranslatable\x18\x01 \x01(\bR\ftranslatable\x12E\n" +
+	"\afilters\x18\x02 \x03(\v2+.registry.v1.ToFiltersResponse.FiltersEntryR\afilters\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x125\n" +
+	"\bwarnings\x18\x04 \x03(\v2\x19.registry.v1.QueryWarningR\bwarnings\x1a:\n" +
+	"\fFiltersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"/\n" +
+	"\x0eExplainRequest\x12\x1d\n" +
+	"\x05query\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\x05query\"<\n" +
+	"\x0fExplainResponse\x12)\n" +
+	"\x03ast\x18\x01 \x01(\v2\x17.google.protobuf.StructR\x03ast\"\x87\x01\n" +
+	"\x14BatchEvaluateRequest\x12A\n" +
+	"\x05items\x18\x01 \x03(\v2\x1e.registry.v1.BatchEvaluateItemB\v\xbaH\b\x92\x01\x05\b\x01\x10\xf4\x03R\x05items\x12\x17\n" +
+	"\aself_id\x18\x02 \x01(\tR\x06selfId\x12\x13\n" +
+	"\x05as_of\x18\x03 \x01(\tR\x04asOf\"x\n" +
+	"\x11BatchEvaluateItem\x12\x16\n" +
+	"\x05query\x18\x01 \x01(\tH\x00R\x05query\x12;\n" +
+	"\n" +
+	"reports_to\x18\x02 \x01(\v2\x1a.registry.v1.ReportsToPairH\x00R\treportsToB\x0e\n" +
+	"\x05check\x12\x05\xbaH\x02\b\x01\"c\n" +
+	"\rReportsToPair\x12)\n" +
+	"\vemployee_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\n" +
+	"employeeId\x12'\n" +
+	"\n" +
+	"manager_id\x18\x02 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\tmanagerId\"S\n" +
+	"\x15BatchEvaluateResponse\x12:\n" +
+	"\aresults\x18\x01 \x03(\v2 .registry.v1.BatchEvaluateResultR\aresults\"S\n" +
+	"\x13BatchEvaluateResult\x12\x1b\n" +
+	"\x06result\x18\x01 \x01(\bH\x00R\x06result\x88\x01\x01\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05errorB\t\n" +
+	"\a_result\"~\n" +
+	"\vDiffRequest\x12\x1b\n" +
+	"\x04from\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\x04from\x12\x17\n" +
+	"\x02to\x18\x02 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\x02to\x12\x17\n" +
+	"\aroot_id\x18\x03 \x01(\tR\x06rootId\x12 \n" +
+	"\x05limit\x18\x04 \x01(\x05B\n" +
+	"\xbaH\a\x1a\x05\x18\x88'(\x00R\x05limit\"\x92\x01\n" +
+	"\fDiffResponse\x120\n" +
+	"\achanges\x18\x01 \x03(\v2\x16.registry.v1.OrgChangeR\achanges\x122\n" +
+	"\asummary\x18\x02 \x01(\v2\x18.registry.v1.DiffSummaryR\asummary\x12\x1c\n" +
+	"\ttruncated\x18\x03 \x01(\bR\ttruncated\"\xfe\x01\n" +
+	"\tOrgChange\x12\x1f\n" +
+	"\vemployee_id\x18\x01 \x01(\tR\n" +
+	"employeeId\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x18\n" +
+	"\adisplay\x18\x03 \x01(\tR\adisplay\x12&\n" +
+	"\x0ffrom_manager_id\x18\x04 \x01(\tR\rfromManagerId\x12\"\n" +
+	"\rto_manager_id\x18\x05 \x01(\tR\vtoManagerId\x12,\n" +
+	"\x12from_department_id\x18\x06 \x01(\tR\x10fromDepartmentId\x12(\n" +
+	"\x10to_department_id\x18\a \x01(\tR\x0etoDepartmentId\"\x9b\x01\n" +
+	"\vDiffSummary\x12\x14\n" +
+	"\x05hires\x18\x01 \x01(\x05R\x05hires\x12\x1e\n" +
+	"\n" +
+	"departures\x18\x02 \x01(\x05R\n" +
+	"departures\x12'\n" +
+	"\x0fmanager_changes\x18\x03 \x01(\x05R\x0emanagerChanges\x12-\n" +
+	"\x12department_changes\x18\x04 \x01(\x05R\x11departmentChanges\"\xe7\x02\n" +
+	"\x14ExecuteStreamRequest\x12\x1d\n" +
+	"\x05query\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\x05query\x12\x16\n" +
+	"\x06select\x18\x02 \x01(\tR\x06select\x12\x16\n" +
+	"\x06expand\x18\x03 \x01(\tR\x06expand\x12\x14\n" +
+	"\x05order\x18\x04 \x01(\tR\x05order\x12\x17\n" +
+	"\aself_id\x18\x05 \x01(\tR\x06selfId\x12\x13\n" +
+	"\x05as_of\x18\x06 \x01(\tR\x04asOf\x12\x1b\n" +
+	"\ttime_zone\x18\a \x01(\tR\btimeZone\x12-\n" +
+	"\x12include_terminated\x18\b \x01(\bR\x11includeTerminated\x12&\n" +
+	"\x0fskip_cost_check\x18\t \x01(\bR\rskipCostCheck\x12)\n" +
+	"\n" +
+	"batch_size\x18\n" +
+	" \x01(\x05B\n" +
+	"\xbaH\a\x1a\x05\x18\xe8\a(\x00R\tbatchSize\x12\x1d\n" +
+	"\x05limit\x18\v \x01(\x03B\a\xbaH\x04\"\x02(\x00R\x05limit\"\xdb\x01\n" +
+	"\x15ExecuteStreamResponse\x121\n" +
+	"\aresults\x18\x01 \x03(\v2\x17.google.protobuf.StructR\aresults\x12\x10\n" +
+	"\x03ids\x18\x02 \x03(\tR\x03ids\x125\n" +
+	"\bwarnings\x18\x03 \x03(\v2\x19.registry.v1.QueryWarningR\bwarnings\x12\x12\n" +
+	"\x04done\x18\x04 \x01(\bR\x04done\x12\x14\n" +
+	"\x05total\x18\x05 \x01(\x03R\x05total\x12\x1c\n" +
+	"\ttruncated\x18\x06 \x01(\bR\ttruncated2\xe6\x04\n" +
+	"\n" +
+	"OrgService\x12Y\n" +
+	"\x05Query\x12\x19.registry.v1.QueryRequest\x1a\x1a.registry.v1.QueryResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/api/org/query\x12m\n" +
+	"\tToFilters\x12\x1d.registry.v1.ToFiltersRequest\x1a\x1e.registry.v1.ToFiltersResponse\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/api/org/query/filters\x12g\n" +
+	"\aExplain\x12\x1b.registry.v1.ExplainRequest\x1a\x1c.registry.v1.ExplainResponse\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/api/org/query/explain\x12w\n" +
+	"\rBatchEvaluate\x12!.registry.v1.BatchEvaluateRequest\x1a\".registry.v1.BatchEvaluateResponse\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/api/org/query/batch\x12R\n" +
+	"\x04Diff\x12\x18.registry.v1.DiffRequest\x1a\x19.registry.v1.DiffResponse\"\x15\x82\xd3\xe4\x93\x02\x0f\x12\r/api/org/diff\x12X\n" +
+	"\rExecuteStream\x12!.registry.v1.ExecuteStreamRequest\x1a\".registry.v1.ExecuteStreamResponse0\x01B\xaf\x01\n" +
+	"\x0fcom.registry.v1B\x0fOrgServiceProtoP\x01Z>github.com/atlekbai/schema_registry/gen/registry/v1;registryv1\xa2\x02\x03RXX\xaa\x02\vRegistry.V1\xca\x02\vRegistry\\V1\xe2\x02\x17Registry\\V1\\GPBMetadata\xea\x02\fRegistry::V1b\x06proto3"
+
+var (
+	file_registry_v1_org_service_proto_rawDescOnce sync.Once
+	file_registry_v1_org_service_proto_rawDescData []byte
+)
+
+func file_registry_v1_org_service_proto_rawDescGZIP() []byte {
+	file_registry_v1_org_service_proto_rawDescOnce.Do(func() {
+		file_registry_v1_org_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_registry_v1_org_service_proto_rawDesc), len(file_registry_v1_org_service_proto_rawDesc)))
+	})
+	return file_registry_v1_org_service_proto_rawDescData
+}
+
+var file_registry_v1_org_service_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
+var file_registry_v1_org_service_proto_goTypes = []any{
+	(*QueryRequest)(nil),          // 0: registry.v1.QueryRequest
+	(*QueryResponse)(nil),         // 1: registry.v1.QueryResponse
+	(*ResultValue)(nil),           // 2: registry.v1.ResultValue
+	(*StructList)(nil),            // 3: registry.v1.StructList
+	(*QueryBucket)(nil),           // 4: registry.v1.QueryBucket
+	(*QueryCostExceeded)(nil),     // 5: registry.v1.QueryCostExceeded
+	(*QueryWarning)(nil),          // 6: registry.v1.QueryWarning
+	(*ToFiltersRequest)(nil),      // 7: registry.v1.ToFiltersRequest
+	(*ToFiltersResponse)(nil),     // 8: registry.v1.ToFiltersResponse
+	(*ExplainRequest)(nil),        // 9: registry.v1.ExplainRequest
+	(*ExplainResponse)(nil),       // 10: registry.v1.ExplainResponse
+	(*BatchEvaluateRequest)(nil),  // 11: registry.v1.BatchEvaluateRequest
+	(*BatchEvaluateItem)(nil),     // 12: registry.v1.BatchEvaluateItem
+	(*ReportsToPair)(nil),         // 13: registry.v1.ReportsToPair
+	(*BatchEvaluateResponse)(nil), // 14: registry.v1.BatchEvaluateResponse
+	(*BatchEvaluateResult)(nil),   // 15: registry.v1.BatchEvaluateResult
+	(*DiffRequest)(nil),           // 16: registry.v1.DiffRequest
+	(*DiffResponse)(nil),          // 17: registry.v1.DiffResponse
+	(*OrgChange)(nil),             // 18: registry.v1.OrgChange
+	(*DiffSummary)(nil),           // 19: registry.v1.DiffSummary
+	(*ExecuteStreamRequest)(nil),  // 20: registry.v1.ExecuteStreamRequest
+	(*ExecuteStreamResponse)(nil), // 21: registry.v1.ExecuteStreamResponse
+	nil,                           // 22: registry.v1.ToFiltersResponse.FiltersEntry
+	(*structpb.Struct)(nil),       // 23: google.protobuf.Struct
+	(*QueryEcho)(nil),             // 24: registry.v1.QueryEcho
+	(structpb.NullValue)(0),       // 25: google.protobuf.NullValue
+}
+var file_registry_v1_org_service_proto_depIdxs = []int32{
+	23, // 0: registry.v1.QueryResponse.results:type_name -> google.protobuf.Struct
+	6,  // 1: registry.v1.QueryResponse.warnings:type_name -> registry.v1.QueryWarning
+	4,  // 2: registry.v1.QueryResponse.buckets:type_name -> registry.v1.QueryBucket
+	24, // 3: registry.v1.QueryResponse.query_echo:type_name -> registry.v1.QueryEcho
+	2,  // 4: registry.v1.QueryResponse.result:type_name -> registry.v1.ResultValue
+	3,  // 5: registry.v1.ResultValue.list_value:type_name -> registry.v1.StructList
+	25, // 6: registry.v1.ResultValue.null_value:type_name -> google.protobuf.NullValue
+	23, // 7: registry.v1.StructList.values:type_name -> google.protobuf.Struct
+	22, // 8: registry.v1.ToFiltersResponse.filters:type_name -> registry.v1.ToFiltersResponse.FiltersEntry
+	6,  // 9: registry.v1.ToFiltersResponse.warnings:type_name -> registry.v1.QueryWarning
+	23, // 10: registry.v1.ExplainResponse.ast:type_name -> google.protobuf.Struct
+	12, // 11: registry.v1.BatchEvaluateRequest.items:type_name -> registry.v1.BatchEvaluateItem
+	13, // 12: registry.v1.BatchEvaluateItem.reports_to:type_name -> registry.v1.ReportsToPair
+	15, // 13: registry.v1.BatchEvaluateResponse.results:type_name -> registry.v1.BatchEvaluateResult
+	18, // 14: registry.v1.DiffResponse.changes:type_name -> registry.v1.OrgChange
+	19, // 15: registry.v1.DiffResponse.summary:type_name -> registry.v1.DiffSummary
+	23, // 16: registry.v1.ExecuteStreamResponse.results:type_name -> google.protobuf.Struct
+	6,  // 17: registry.v1.ExecuteStreamResponse.warnings:type_name -> registry.v1.QueryWarning
+	0,  // 18: registry.v1.OrgService.Query:input_type -> registry.v1.QueryRequest
+	7,  // 19: registry.v1.OrgService.ToFilters:input_type -> registry.v1.ToFiltersRequest
+	9,  // 20: registry.v1.OrgService.Explain:input_type -> registry.v1.ExplainRequest
+	11, // 21: registry.v1.OrgService.BatchEvaluate:input_type -> registry.v1.BatchEvaluateRequest
+	16, // 22: registry.v1.OrgService.Diff:input_type -> registry.v1.DiffRequest
+	20, // 23: registry.v1.OrgService.ExecuteStream:input_type -> registry.v1.ExecuteStreamRequest
+	1,  // 24: registry.v1.OrgService.Query:output_type -> registry.v1.QueryResponse
+	8,  // 25: registry.v1.OrgService.ToFilters:output_type -> registry.v1.ToFiltersResponse
+	10, // 26: registry.v1.OrgService.Explain:output_type -> registry.v1.ExplainResponse
+	14, // 27: registry.v1.OrgService.BatchEvaluate:output_type -> registry.v1.BatchEvaluateResponse
+	17, // 28: registry.v1.OrgService.Diff:output_type -> registry.v1.DiffResponse
+	21, // 29: registry.v1.OrgService.ExecuteStream:output_type -> registry.v1.ExecuteStreamResponse
+	24, // [24:30] is the sub-list for method output_type
+	18, // [18:24] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
+}
+
+func init() { file_registry_v1_org_service_proto_init() }
+func file_registry_v1_org_service_proto_init() {
+	if File_registry_v1_org_service_proto != nil {
+		return
+	}
+	file_registry_v1_registry_proto_init()
+	file_registry_v1_org_service_proto_msgTypes[1].OneofWrappers = []any{}
+	file_registry_v1_org_service_proto_msgTypes[2].OneofWrappers = []any{
+		(*ResultValue_IntValue)(nil),
+		(*ResultValue_DoubleValue)(nil),
+		(*ResultValue_BoolValue)(nil),
+		(*ResultValue_StringValue)(nil),
+		(*ResultValue_ListValue)(nil),
+		(*ResultValue_NullValue)(nil),
+	}
+	file_registry_v1_org_service_proto_msgTypes[4].OneofWrappers = []any{}
+	file_registry_v1_org_service_proto_msgTypes[12].OneofWrappers = []any{
+		(*BatchEvaluateItem_Query)(nil),
+		(*BatchEvaluateItem_ReportsTo)(nil),
+	}
+	file_registry_v1_org_service_proto_msgTypes[15].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_registry_v1_org_service_proto_rawDesc), len(file_registry_v1_org_service_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   23,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
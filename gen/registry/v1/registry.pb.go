@@ -27,20 +27,47 @@ type ListRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// The API name of the object (e.g. "employees", "departments").
 	ObjectName string `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
-	// Comma-separated field names to include in the response.
+	// Comma-separated field names to include in the response. Dotted names
+	// (e.g. "department.title") select fields of an expanded lookup, which then
+	// only reads those columns.
 	Select string `protobuf:"bytes,2,opt,name=select,proto3" json:"select,omitempty"`
 	// Comma-separated lookup fields to expand (e.g. "Department,Department.Company").
+	// "<relation>:count" adds "<relation>_count" to each record instead: the
+	// number of records of a reverse relation (e.g. "positions:count") or, on
+	// employees, "reports:count", their reports at any depth.
 	Expand string `protobuf:"bytes,3,opt,name=expand,proto3" json:"expand,omitempty"`
-	// Sort field, optionally suffixed with ".desc" (e.g. "CreatedAt.desc").
+	// Sort field, optionally suffixed with ".asc" or ".desc", then ".nullsfirst"
+	// or ".nullslast" (e.g. "CreatedAt.desc", "end_date.desc.nullsfirst").
+	// NULLs sort last by default.
 	Order string `protobuf:"bytes,4,opt,name=order,proto3" json:"order,omitempty"`
-	// Page size (0-200, 0 means server default).
+	// Page size (0 means the object's default), capped at the object's
+	// maximum (200 unless the server or object configures another).
 	Limit int32 `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
 	// Opaque cursor token from a previous response.
 	Cursor string `protobuf:"bytes,6,opt,name=cursor,proto3" json:"cursor,omitempty"`
 	// Filters keyed by field API name, values in "op.value" format (e.g. "eq.active").
-	Filters       map[string]string `protobuf:"bytes,7,rep,name=filters,proto3" json:"filters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	Filters map[string]string `protobuf:"bytes,7,rep,name=filters,proto3" json:"filters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Read every page as of the moment the first page was read, so rows do not
+	// shift between pages as writes happen. The snapshot is pinned in the cursor
+	// and expires after the server's snapshot TTL; later pages keep reading it
+	// whether or not they set this flag.
+	Snapshot bool `protobuf:"varint,8,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	// Skip the total count and the next cursor, for clients that only want the
+	// rows. Over REST (GET /api/{object_name}?raw=true) the body is then a bare
+	// JSON array of the records instead of the ListResponse envelope. Cannot be
+	// combined with snapshot, which needs a cursor to carry it.
+	Raw bool `protobuf:"varint,9,opt,name=raw,proto3" json:"raw,omitempty"`
+	// How expanded records appear: "inline" (the default) nests a copy in
+	// every record pointing at it; "referenced" leaves the lookup's id in the
+	// record and returns each expanded record once in ListResponse.included,
+	// nested expands and reverse expands (as arrays of ids) included. Cannot
+	// be combined with raw, which has no envelope for included.
+	ExpandMode string `protobuf:"bytes,10,opt,name=expand_mode,json=expandMode,proto3" json:"expand_mode,omitempty"`
+	// Keep employees who have left (end_date on or before today) in employees
+	// lists, which leave them out by default. Other objects ignore it.
+	IncludeTerminated bool `protobuf:"varint,11,opt,name=include_terminated,json=includeTerminated,proto3" json:"include_terminated,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *ListRequest) Reset() {
@@ -122,11 +149,50 @@ func (x *ListRequest) GetFilters() map[string]string {
 	return nil
 }
 
+func (x *ListRequest) GetSnapshot() bool {
+	if x != nil {
+		return x.Snapshot
+	}
+	return false
+}
+
+func (x *ListRequest) GetRaw() bool {
+	if x != nil {
+		return x.Raw
+	}
+	return false
+}
+
+func (x *ListRequest) GetExpandMode() string {
+	if x != nil {
+		return x.ExpandMode
+	}
+	return ""
+}
+
+func (x *ListRequest) GetIncludeTerminated() bool {
+	if x != nil {
+		return x.IncludeTerminated
+	}
+	return false
+}
+
 type ListResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	TotalCount    int64                  `protobuf:"varint,1,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
-	NextCursor    *string                `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3,oneof" json:"next_cursor,omitempty"`
-	Results       []*structpb.Struct     `protobuf:"bytes,3,rep,name=results,proto3" json:"results,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Matching records: exact for small results, the planner's estimate for
+	// large ones, or -1 when count_unknown.
+	TotalCount int64              `protobuf:"varint,1,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	NextCursor *string            `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3,oneof" json:"next_cursor,omitempty"`
+	Results    []*structpb.Struct `protobuf:"bytes,3,rep,name=results,proto3" json:"results,omitempty"`
+	// Set when the object is deprecated (see ObjectDeprecation) or was read
+	// through a former name (see RenameObjectRequest).
+	Warning string `protobuf:"bytes,4,opt,name=warning,proto3" json:"warning,omitempty"`
+	// Set when the count could not be resolved; the page itself is complete.
+	CountUnknown bool `protobuf:"varint,5,opt,name=count_unknown,json=countUnknown,proto3" json:"count_unknown,omitempty"`
+	// How the server interpreted the request.
+	QueryEcho *QueryEcho `protobuf:"bytes,6,opt,name=query_echo,json=queryEcho,proto3" json:"query_echo,omitempty"`
+	// Expanded records keyed by id, with expand_mode "referenced".
+	Included      map[string]*structpb.Struct `protobuf:"bytes,7,rep,name=included,proto3" json:"included,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -182,34 +248,73 @@ func (x *ListResponse) GetResults() []*structpb.Struct {
 	return nil
 }
 
-type GetRequest struct {
+func (x *ListResponse) GetWarning() string {
+	if x != nil {
+		return x.Warning
+	}
+	return ""
+}
+
+func (x *ListResponse) GetCountUnknown() bool {
+	if x != nil {
+		return x.CountUnknown
+	}
+	return false
+}
+
+func (x *ListResponse) GetQueryEcho() *QueryEcho {
+	if x != nil {
+		return x.QueryEcho
+	}
+	return nil
+}
+
+func (x *ListResponse) GetIncluded() map[string]*structpb.Struct {
+	if x != nil {
+		return x.Included
+	}
+	return nil
+}
+
+// QueryEcho describes the list query the server ran after normalizing the
+// request: defaults filled in, limits capped, expands resolved and fields
+// withheld for lack of permission. Compare it with the request when a page
+// is not what was expected.
+type QueryEcho struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// The API name of the object.
-	ObjectName string `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
-	// UUID of the record.
-	Id string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
-	// Comma-separated field names to include.
-	Select string `protobuf:"bytes,3,opt,name=select,proto3" json:"select,omitempty"`
-	// Comma-separated lookup fields to expand.
-	Expand        string `protobuf:"bytes,4,opt,name=expand,proto3" json:"expand,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	// Applied conditions, AND'd, sorted by field and filter.
+	Conditions []*QueryEchoCondition `protobuf:"bytes,1,rep,name=conditions,proto3" json:"conditions,omitempty"`
+	// Effective order in the order syntax ("field.desc", with ".nullsfirst" when
+	// NULLs sort first), "random", or empty for id order.
+	Order string `protobuf:"bytes,2,opt,name=order,proto3" json:"order,omitempty"`
+	// Page size after defaults and caps.
+	Limit int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Selected fields; empty selects all.
+	Select []string `protobuf:"bytes,4,rep,name=select,proto3" json:"select,omitempty"`
+	// Expands applied, as dotted paths, then inline counts as
+	// "<relation>:count"; requested expands that did not resolve are missing.
+	Expand []string `protobuf:"bytes,5,rep,name=expand,proto3" json:"expand,omitempty"`
+	// ENCRYPTED fields returned as null because the caller lacks pii:read,
+	// as dotted paths for expanded records.
+	RedactedFields []string `protobuf:"bytes,6,rep,name=redacted_fields,json=redactedFields,proto3" json:"redacted_fields,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *GetRequest) Reset() {
-	*x = GetRequest{}
+func (x *QueryEcho) Reset() {
+	*x = QueryEcho{}
 	mi := &file_registry_v1_registry_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetRequest) String() string {
+func (x *QueryEcho) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetRequest) ProtoMessage() {}
+func (*QueryEcho) ProtoMessage() {}
 
-func (x *GetRequest) ProtoReflect() protoreflect.Message {
+func (x *QueryEcho) ProtoReflect() protoreflect.Message {
 	mi := &file_registry_v1_registry_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -221,60 +326,81 @@ func (x *GetRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetRequest.ProtoReflect.Descriptor instead.
-func (*GetRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use QueryEcho.ProtoReflect.Descriptor instead.
+func (*QueryEcho) Descriptor() ([]byte, []int) {
 	return file_registry_v1_registry_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *GetRequest) GetObjectName() string {
+func (x *QueryEcho) GetConditions() []*QueryEchoCondition {
 	if x != nil {
-		return x.ObjectName
+		return x.Conditions
 	}
-	return ""
+	return nil
 }
 
-func (x *GetRequest) GetId() string {
+func (x *QueryEcho) GetOrder() string {
 	if x != nil {
-		return x.Id
+		return x.Order
 	}
 	return ""
 }
 
-func (x *GetRequest) GetSelect() string {
+func (x *QueryEcho) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *QueryEcho) GetSelect() []string {
 	if x != nil {
 		return x.Select
 	}
-	return ""
+	return nil
 }
 
-func (x *GetRequest) GetExpand() string {
+func (x *QueryEcho) GetExpand() []string {
 	if x != nil {
 		return x.Expand
 	}
-	return ""
+	return nil
 }
 
-type GetResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Record        *structpb.Struct       `protobuf:"bytes,1,opt,name=record,proto3" json:"record,omitempty"`
+func (x *QueryEcho) GetRedactedFields() []string {
+	if x != nil {
+		return x.RedactedFields
+	}
+	return nil
+}
+
+// QueryEchoCondition is one applied condition. Conditions with a REST filter
+// form (see ListRequest.filters) set field and filter; others, such as HRQL
+// or and org functions, only set kind.
+type QueryEchoCondition struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Field string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	// "op.value", e.g. "eq.FULL_TIME".
+	Filter string `protobuf:"bytes,2,opt,name=filter,proto3" json:"filter,omitempty"`
+	// Condition type for those without a filter form, e.g. "OrCond".
+	Kind          string `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetResponse) Reset() {
-	*x = GetResponse{}
+func (x *QueryEchoCondition) Reset() {
+	*x = QueryEchoCondition{}
 	mi := &file_registry_v1_registry_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetResponse) String() string {
+func (x *QueryEchoCondition) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetResponse) ProtoMessage() {}
+func (*QueryEchoCondition) ProtoMessage() {}
 
-func (x *GetResponse) ProtoReflect() protoreflect.Message {
+func (x *QueryEchoCondition) ProtoReflect() protoreflect.Message {
 	mi := &file_registry_v1_registry_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -286,52 +412,2139 @@ func (x *GetResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetResponse.ProtoReflect.Descriptor instead.
-func (*GetResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use QueryEchoCondition.ProtoReflect.Descriptor instead.
+func (*QueryEchoCondition) Descriptor() ([]byte, []int) {
 	return file_registry_v1_registry_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *GetResponse) GetRecord() *structpb.Struct {
+func (x *QueryEchoCondition) GetField() string {
 	if x != nil {
-		return x.Record
+		return x.Field
+	}
+	return ""
+}
+
+func (x *QueryEchoCondition) GetFilter() string {
+	if x != nil {
+		return x.Filter
+	}
+	return ""
+}
+
+func (x *QueryEchoCondition) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+type SplitListRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The API name of the object.
+	ObjectName string `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	// Number of partitions wanted (1-64). Fewer are returned when there are
+	// not enough matching records to tell them apart.
+	Partitions int32 `protobuf:"varint,2,opt,name=partitions,proto3" json:"partitions,omitempty"`
+	// Filters, as in ListRequest. They only place the partition bounds: pass
+	// the same filters to every List that reads a partition.
+	Filters map[string]string `protobuf:"bytes,3,rep,name=filters,proto3" json:"filters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Pin every partition to one database snapshot, as ListRequest.snapshot
+	// does for the pages of a single List.
+	Snapshot      bool `protobuf:"varint,4,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SplitListRequest) Reset() {
+	*x = SplitListRequest{}
+	mi := &file_registry_v1_registry_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SplitListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SplitListRequest) ProtoMessage() {}
+
+func (x *SplitListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SplitListRequest.ProtoReflect.Descriptor instead.
+func (*SplitListRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SplitListRequest) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+func (x *SplitListRequest) GetPartitions() int32 {
+	if x != nil {
+		return x.Partitions
+	}
+	return 0
+}
+
+func (x *SplitListRequest) GetFilters() map[string]string {
+	if x != nil {
+		return x.Filters
 	}
 	return nil
 }
 
-var File_registry_v1_registry_proto protoreflect.FileDescriptor
+func (x *SplitListRequest) GetSnapshot() bool {
+	if x != nil {
+		return x.Snapshot
+	}
+	return false
+}
 
-const file_registry_v1_registry_proto_rawDesc = "" +
-	"\n" +
-	"\x1aregistry/v1/registry.proto\x12\vregistry.v1\x1a\x1bbuf/validate/validate.proto\x1a\x1cgoogle/protobuf/struct.proto\"\xb4\x02\n" +
-	"\vListRequest\x12(\n" +
-	"\vobject_name\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\n" +
-	"objectName\x12\x16\n" +
-	"\x06select\x18\x02 \x01(\tR\x06select\x12\x16\n" +
-	"\x06expand\x18\x03 \x01(\tR\x06expand\x12\x14\n" +
-	"\x05order\x18\x04 \x01(\tR\x05order\x12 \n" +
-	"\x05limit\x18\x05 \x01(\x05B\n" +
-	"\xbaH\a\x1a\x05\x18\xc8\x01(\x00R\x05limit\x12\x16\n" +
-	"\x06cursor\x18\x06 \x01(\tR\x06cursor\x12?\n" +
-	"\afilters\x18\a \x03(\v2%.registry.v1.ListRequest.FiltersEntryR\afilters\x1a:\n" +
-	"\fFiltersEntry\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x98\x01\n" +
-	"\fListResponse\x12\x1f\n" +
-	"\vtotal_count\x18\x01 \x01(\x03R\n" +
-	"totalCount\x12$\n" +
-	"\vnext_cursor\x18\x02 \x01(\tH\x00R\n" +
-	"nextCursor\x88\x01\x01\x121\n" +
-	"\aresults\x18\x03 \x03(\v2\x17.google.protobuf.StructR\aresultsB\x0e\n" +
-	"\f_next_cursor\"\x80\x01\n" +
-	"\n" +
-	"GetRequest\x12(\n" +
-	"\vobject_name\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\n" +
-	"objectName\x12\x18\n" +
-	"\x02id\x18\x02 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\x12\x16\n" +
-	"\x06select\x18\x03 \x01(\tR\x06select\x12\x16\n" +
-	"\x06expand\x18\x04 \x01(\tR\x06expand\">\n" +
-	"\vGetResponse\x12/\n" +
-	"\x06record\x18\x01 \x01(\v2\x17.google.protobuf.StructR\x06recordB\xad\x01\n" +
+type ListPartition struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Cursor for ListRequest.cursor. The List and its next_cursor pages stay
+	// within the partition and are done when next_cursor is unset.
+	Cursor string `protobuf:"bytes,1,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// The partition's id range: ids greater than after, up to and including
+	// until.
+	After         string `protobuf:"bytes,2,opt,name=after,proto3" json:"after,omitempty"`
+	Until         string `protobuf:"bytes,3,opt,name=until,proto3" json:"until,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPartition) Reset() {
+	*x = ListPartition{}
+	mi := &file_registry_v1_registry_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPartition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPartition) ProtoMessage() {}
+
+func (x *ListPartition) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPartition.ProtoReflect.Descriptor instead.
+func (*ListPartition) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListPartition) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListPartition) GetAfter() string {
+	if x != nil {
+		return x.After
+	}
+	return ""
+}
+
+func (x *ListPartition) GetUntil() string {
+	if x != nil {
+		return x.Until
+	}
+	return ""
+}
+
+type SplitListResponse struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Partitions []*ListPartition       `protobuf:"bytes,1,rep,name=partitions,proto3" json:"partitions,omitempty"`
+	// The planner's estimate of the matching records, across all partitions.
+	EstimatedRows int64 `protobuf:"varint,2,opt,name=estimated_rows,json=estimatedRows,proto3" json:"estimated_rows,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SplitListResponse) Reset() {
+	*x = SplitListResponse{}
+	mi := &file_registry_v1_registry_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SplitListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SplitListResponse) ProtoMessage() {}
+
+func (x *SplitListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SplitListResponse.ProtoReflect.Descriptor instead.
+func (*SplitListResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SplitListResponse) GetPartitions() []*ListPartition {
+	if x != nil {
+		return x.Partitions
+	}
+	return nil
+}
+
+func (x *SplitListResponse) GetEstimatedRows() int64 {
+	if x != nil {
+		return x.EstimatedRows
+	}
+	return 0
+}
+
+type GetRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The API name of the object.
+	ObjectName string `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	// UUID of the record.
+	Id string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// Comma-separated field names to include; dotted names select fields of an
+	// expanded lookup (see ListRequest.select).
+	Select string `protobuf:"bytes,3,opt,name=select,proto3" json:"select,omitempty"`
+	// Comma-separated lookup fields to expand, and inline counts as in
+	// ListRequest.expand.
+	Expand        string `protobuf:"bytes,4,opt,name=expand,proto3" json:"expand,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRequest) Reset() {
+	*x = GetRequest{}
+	mi := &file_registry_v1_registry_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRequest) ProtoMessage() {}
+
+func (x *GetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRequest.ProtoReflect.Descriptor instead.
+func (*GetRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetRequest) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+func (x *GetRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetRequest) GetSelect() string {
+	if x != nil {
+		return x.Select
+	}
+	return ""
+}
+
+func (x *GetRequest) GetExpand() string {
+	if x != nil {
+		return x.Expand
+	}
+	return ""
+}
+
+type GetResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Record *structpb.Struct       `protobuf:"bytes,1,opt,name=record,proto3" json:"record,omitempty"`
+	// Set when the object is deprecated (see ObjectDeprecation) or was read
+	// through a former name (see RenameObjectRequest).
+	Warning       string `protobuf:"bytes,2,opt,name=warning,proto3" json:"warning,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetResponse) Reset() {
+	*x = GetResponse{}
+	mi := &file_registry_v1_registry_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetResponse) ProtoMessage() {}
+
+func (x *GetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetResponse.ProtoReflect.Descriptor instead.
+func (*GetResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetResponse) GetRecord() *structpb.Struct {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+func (x *GetResponse) GetWarning() string {
+	if x != nil {
+		return x.Warning
+	}
+	return ""
+}
+
+type CreateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The API name of the object.
+	ObjectName string `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	// Field values keyed by field API name.
+	Data *structpb.Struct `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	// Validate and return the would-be record, including database defaults,
+	// without committing.
+	DryRun        bool `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateRequest) Reset() {
+	*x = CreateRequest{}
+	mi := &file_registry_v1_registry_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRequest) ProtoMessage() {}
+
+func (x *CreateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRequest.ProtoReflect.Descriptor instead.
+func (*CreateRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CreateRequest) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+func (x *CreateRequest) GetData() *structpb.Struct {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *CreateRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type CreateResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Record *structpb.Struct       `protobuf:"bytes,1,opt,name=record,proto3" json:"record,omitempty"`
+	// True when the write was rolled back (dry_run).
+	DryRun        bool `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateResponse) Reset() {
+	*x = CreateResponse{}
+	mi := &file_registry_v1_registry_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateResponse) ProtoMessage() {}
+
+func (x *CreateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateResponse.ProtoReflect.Descriptor instead.
+func (*CreateResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CreateResponse) GetRecord() *structpb.Struct {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+func (x *CreateResponse) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type UpdateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The API name of the object.
+	ObjectName string `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	// UUID of the record.
+	Id string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// Field values to set, keyed by field API name. Omitted fields are left unchanged.
+	Data *structpb.Struct `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	// Version the client last read. The write is rejected with FAILED_PRECONDITION
+	// if the record has changed since. 0 falls back to the If-Match header (if any).
+	ExpectedVersion int64 `protobuf:"varint,4,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	// Validate and return the would-be record without committing.
+	DryRun        bool `protobuf:"varint,5,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateRequest) Reset() {
+	*x = UpdateRequest{}
+	mi := &file_registry_v1_registry_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRequest) ProtoMessage() {}
+
+func (x *UpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRequest.ProtoReflect.Descriptor instead.
+func (*UpdateRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UpdateRequest) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+func (x *UpdateRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateRequest) GetData() *structpb.Struct {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *UpdateRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+func (x *UpdateRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type UpdateResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Record *structpb.Struct       `protobuf:"bytes,1,opt,name=record,proto3" json:"record,omitempty"`
+	// True when the write was rolled back (dry_run).
+	DryRun        bool `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateResponse) Reset() {
+	*x = UpdateResponse{}
+	mi := &file_registry_v1_registry_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateResponse) ProtoMessage() {}
+
+func (x *UpdateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateResponse.ProtoReflect.Descriptor instead.
+func (*UpdateResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UpdateResponse) GetRecord() *structpb.Struct {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+func (x *UpdateResponse) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type UpdateWhereRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The API name of the object.
+	ObjectName string `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	// Records to update, as REST filters (see ListRequest.filters) or as an
+	// HRQL where condition (e.g. ".employment_type == \"CONTRACTOR\""). Exactly
+	// one must be set: UpdateWhere never updates every record implicitly.
+	Filters map[string]string `protobuf:"bytes,2,rep,name=filters,proto3" json:"filters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Where   string            `protobuf:"bytes,3,opt,name=where,proto3" json:"where,omitempty"`
+	// Field values to set on every matching record (see UpdateRequest.data).
+	Data *structpb.Struct `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	// Count the matching records and validate the update without committing.
+	DryRun bool `protobuf:"varint,5,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	// Required unless dry_run: the matched count a dry run reported. The
+	// update fails with FAILED_PRECONDITION when the filter now matches a
+	// different number of records.
+	ExpectedCount int64 `protobuf:"varint,6,opt,name=expected_count,json=expectedCount,proto3" json:"expected_count,omitempty"`
+	// Most records the update may touch; 0 or above the server cap (10000)
+	// uses the cap. More matches fail with FAILED_PRECONDITION.
+	MaxRows       int32 `protobuf:"varint,7,opt,name=max_rows,json=maxRows,proto3" json:"max_rows,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateWhereRequest) Reset() {
+	*x = UpdateWhereRequest{}
+	mi := &file_registry_v1_registry_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateWhereRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateWhereRequest) ProtoMessage() {}
+
+func (x *UpdateWhereRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateWhereRequest.ProtoReflect.Descriptor instead.
+func (*UpdateWhereRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UpdateWhereRequest) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+func (x *UpdateWhereRequest) GetFilters() map[string]string {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+func (x *UpdateWhereRequest) GetWhere() string {
+	if x != nil {
+		return x.Where
+	}
+	return ""
+}
+
+func (x *UpdateWhereRequest) GetData() *structpb.Struct {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *UpdateWhereRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *UpdateWhereRequest) GetExpectedCount() int64 {
+	if x != nil {
+		return x.ExpectedCount
+	}
+	return 0
+}
+
+func (x *UpdateWhereRequest) GetMaxRows() int32 {
+	if x != nil {
+		return x.MaxRows
+	}
+	return 0
+}
+
+type UpdateWhereResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Records the filter matched, all of which were updated unless dry_run.
+	Matched int64 `protobuf:"varint,1,opt,name=matched,proto3" json:"matched,omitempty"`
+	// True when the write was rolled back (dry_run).
+	DryRun        bool `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateWhereResponse) Reset() {
+	*x = UpdateWhereResponse{}
+	mi := &file_registry_v1_registry_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateWhereResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateWhereResponse) ProtoMessage() {}
+
+func (x *UpdateWhereResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateWhereResponse.ProtoReflect.Descriptor instead.
+func (*UpdateWhereResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *UpdateWhereResponse) GetMatched() int64 {
+	if x != nil {
+		return x.Matched
+	}
+	return 0
+}
+
+func (x *UpdateWhereResponse) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type UpsertRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The API name of the object.
+	ObjectName string `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	// External ID field to match on. May be omitted when the object has exactly one.
+	ExternalIdField string `protobuf:"bytes,2,opt,name=external_id_field,json=externalIdField,proto3" json:"external_id_field,omitempty"`
+	// Field values keyed by field API name; must include the external ID. On a
+	// match the values are merged into the stored record.
+	Data *structpb.Struct `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	// Validate and return the would-be record without committing.
+	DryRun        bool `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpsertRequest) Reset() {
+	*x = UpsertRequest{}
+	mi := &file_registry_v1_registry_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpsertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpsertRequest) ProtoMessage() {}
+
+func (x *UpsertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpsertRequest.ProtoReflect.Descriptor instead.
+func (*UpsertRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UpsertRequest) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+func (x *UpsertRequest) GetExternalIdField() string {
+	if x != nil {
+		return x.ExternalIdField
+	}
+	return ""
+}
+
+func (x *UpsertRequest) GetData() *structpb.Struct {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *UpsertRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type UpsertResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Record *structpb.Struct       `protobuf:"bytes,1,opt,name=record,proto3" json:"record,omitempty"`
+	// True when no record matched the external ID and a new one was inserted.
+	Created bool `protobuf:"varint,2,opt,name=created,proto3" json:"created,omitempty"`
+	// True when the write was rolled back (dry_run).
+	DryRun        bool `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpsertResponse) Reset() {
+	*x = UpsertResponse{}
+	mi := &file_registry_v1_registry_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpsertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpsertResponse) ProtoMessage() {}
+
+func (x *UpsertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpsertResponse.ProtoReflect.Descriptor instead.
+func (*UpsertResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *UpsertResponse) GetRecord() *structpb.Struct {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+func (x *UpsertResponse) GetCreated() bool {
+	if x != nil {
+		return x.Created
+	}
+	return false
+}
+
+func (x *UpsertResponse) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type DeleteRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The API name of the object.
+	ObjectName string `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	// UUID of the record.
+	Id string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// Version the client last read (see UpdateRequest.expected_version).
+	ExpectedVersion int64 `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	// Validate the delete and return the record that would be removed without committing.
+	DryRun        bool `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRequest) Reset() {
+	*x = DeleteRequest{}
+	mi := &file_registry_v1_registry_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRequest) ProtoMessage() {}
+
+func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *DeleteRequest) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+func (x *DeleteRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+func (x *DeleteRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type DeleteResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The record that would be deleted; only set for dry runs.
+	Record *structpb.Struct `protobuf:"bytes,1,opt,name=record,proto3" json:"record,omitempty"`
+	// True when the delete was rolled back (dry_run).
+	DryRun        bool `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteResponse) Reset() {
+	*x = DeleteResponse{}
+	mi := &file_registry_v1_registry_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResponse) ProtoMessage() {}
+
+func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
+func (*DeleteResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DeleteResponse) GetRecord() *structpb.Struct {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+func (x *DeleteResponse) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type GetRecordHistoryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The API name of the object.
+	ObjectName string `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	// UUID of the record.
+	Id            string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRecordHistoryRequest) Reset() {
+	*x = GetRecordHistoryRequest{}
+	mi := &file_registry_v1_registry_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRecordHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRecordHistoryRequest) ProtoMessage() {}
+
+func (x *GetRecordHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRecordHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetRecordHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetRecordHistoryRequest) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+func (x *GetRecordHistoryRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetRecordHistoryResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The record's versions, oldest first. Deleted records keep their history.
+	Versions      []*RecordVersion `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRecordHistoryResponse) Reset() {
+	*x = GetRecordHistoryResponse{}
+	mi := &file_registry_v1_registry_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRecordHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRecordHistoryResponse) ProtoMessage() {}
+
+func (x *GetRecordHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRecordHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetRecordHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetRecordHistoryResponse) GetVersions() []*RecordVersion {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+// RecordVersion is one write to a record.
+type RecordVersion struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Version int64                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	// INSERT, UPDATE or DELETE, or SNAPSHOT for the state when history began.
+	Operation string `protobuf:"bytes,2,opt,name=operation,proto3" json:"operation,omitempty"`
+	// RFC 3339 time of the write.
+	ChangedAt string `protobuf:"bytes,3,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+	// Actor and request ID the write was made under; empty when unknown.
+	ActorId   string `protobuf:"bytes,4,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	RequestId string `protobuf:"bytes,5,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// The record as written; unset for DELETE. Denormalized lookup labels are
+	// not kept current in history.
+	Record *structpb.Struct `protobuf:"bytes,6,opt,name=record,proto3" json:"record,omitempty"`
+	// Fields that differ from the previous version; empty for the first.
+	Changes       []*FieldChange `protobuf:"bytes,7,rep,name=changes,proto3" json:"changes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecordVersion) Reset() {
+	*x = RecordVersion{}
+	mi := &file_registry_v1_registry_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordVersion) ProtoMessage() {}
+
+func (x *RecordVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordVersion.ProtoReflect.Descriptor instead.
+func (*RecordVersion) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RecordVersion) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *RecordVersion) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *RecordVersion) GetChangedAt() string {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return ""
+}
+
+func (x *RecordVersion) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *RecordVersion) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *RecordVersion) GetRecord() *structpb.Struct {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+func (x *RecordVersion) GetChanges() []*FieldChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+// FieldChange is a field value changed by a write. ENCRYPTED values are
+// null without the pii:read permission.
+type FieldChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	OldValue      *structpb.Value        `protobuf:"bytes,2,opt,name=old_value,json=oldValue,proto3" json:"old_value,omitempty"`
+	NewValue      *structpb.Value        `protobuf:"bytes,3,opt,name=new_value,json=newValue,proto3" json:"new_value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldChange) Reset() {
+	*x = FieldChange{}
+	mi := &file_registry_v1_registry_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldChange) ProtoMessage() {}
+
+func (x *FieldChange) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldChange.ProtoReflect.Descriptor instead.
+func (*FieldChange) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *FieldChange) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FieldChange) GetOldValue() *structpb.Value {
+	if x != nil {
+		return x.OldValue
+	}
+	return nil
+}
+
+func (x *FieldChange) GetNewValue() *structpb.Value {
+	if x != nil {
+		return x.NewValue
+	}
+	return nil
+}
+
+type RevertRecordRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The API name of the object.
+	ObjectName string `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	// UUID of the record.
+	Id string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// Version whose field values to restore (see GetRecordHistory).
+	Version int64 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	// Version the client last read (see UpdateRequest.expected_version).
+	ExpectedVersion int64 `protobuf:"varint,4,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	// Validate and return the would-be record without committing.
+	DryRun        bool `protobuf:"varint,5,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevertRecordRequest) Reset() {
+	*x = RevertRecordRequest{}
+	mi := &file_registry_v1_registry_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevertRecordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevertRecordRequest) ProtoMessage() {}
+
+func (x *RevertRecordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevertRecordRequest.ProtoReflect.Descriptor instead.
+func (*RevertRecordRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *RevertRecordRequest) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+func (x *RevertRecordRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RevertRecordRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *RevertRecordRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+func (x *RevertRecordRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type RevertRecordResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Record *structpb.Struct       `protobuf:"bytes,1,opt,name=record,proto3" json:"record,omitempty"`
+	// True when the write was rolled back (dry_run).
+	DryRun        bool `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevertRecordResponse) Reset() {
+	*x = RevertRecordResponse{}
+	mi := &file_registry_v1_registry_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevertRecordResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevertRecordResponse) ProtoMessage() {}
+
+func (x *RevertRecordResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevertRecordResponse.ProtoReflect.Descriptor instead.
+func (*RevertRecordResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *RevertRecordResponse) GetRecord() *structpb.Struct {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+func (x *RevertRecordResponse) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+// VersionConflict is attached to FAILED_PRECONDITION errors when a write's
+// expected version does not match the stored record.
+type TypeaheadRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The API name of the object; it must have a display template.
+	ObjectName string `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	// Case-insensitive prefix of the display name; empty matches every record.
+	Q string `protobuf:"bytes,2,opt,name=q,proto3" json:"q,omitempty"`
+	// Number of matches (0-50, 0 means 10).
+	Limit         int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TypeaheadRequest) Reset() {
+	*x = TypeaheadRequest{}
+	mi := &file_registry_v1_registry_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TypeaheadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TypeaheadRequest) ProtoMessage() {}
+
+func (x *TypeaheadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TypeaheadRequest.ProtoReflect.Descriptor instead.
+func (*TypeaheadRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *TypeaheadRequest) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+func (x *TypeaheadRequest) GetQ() string {
+	if x != nil {
+		return x.Q
+	}
+	return ""
+}
+
+func (x *TypeaheadRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type TypeaheadResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Matches       []*TypeaheadMatch      `protobuf:"bytes,1,rep,name=matches,proto3" json:"matches,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TypeaheadResponse) Reset() {
+	*x = TypeaheadResponse{}
+	mi := &file_registry_v1_registry_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TypeaheadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TypeaheadResponse) ProtoMessage() {}
+
+func (x *TypeaheadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TypeaheadResponse.ProtoReflect.Descriptor instead.
+func (*TypeaheadResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *TypeaheadResponse) GetMatches() []*TypeaheadMatch {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+// TypeaheadMatch is a record rendered by its object's display template.
+type TypeaheadMatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Display       string                 `protobuf:"bytes,2,opt,name=display,proto3" json:"display,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TypeaheadMatch) Reset() {
+	*x = TypeaheadMatch{}
+	mi := &file_registry_v1_registry_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TypeaheadMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TypeaheadMatch) ProtoMessage() {}
+
+func (x *TypeaheadMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TypeaheadMatch.ProtoReflect.Descriptor instead.
+func (*TypeaheadMatch) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *TypeaheadMatch) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TypeaheadMatch) GetDisplay() string {
+	if x != nil {
+		return x.Display
+	}
+	return ""
+}
+
+type LookupRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The API name of the object holding the LOOKUP field.
+	ObjectName string `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	// The LOOKUP field; its target object must have a display template.
+	Field string `protobuf:"bytes,2,opt,name=field,proto3" json:"field,omitempty"`
+	// Search text: display names containing it or with a word similar to it
+	// match, prefix matches first. Empty lists records alphabetically.
+	Q string `protobuf:"bytes,3,opt,name=q,proto3" json:"q,omitempty"`
+	// Number of matches (0-50, 0 means 20).
+	Limit         int32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupRequest) Reset() {
+	*x = LookupRequest{}
+	mi := &file_registry_v1_registry_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupRequest) ProtoMessage() {}
+
+func (x *LookupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupRequest.ProtoReflect.Descriptor instead.
+func (*LookupRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *LookupRequest) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+func (x *LookupRequest) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *LookupRequest) GetQ() string {
+	if x != nil {
+		return x.Q
+	}
+	return ""
+}
+
+func (x *LookupRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type LookupResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The API name of the LOOKUP field's target object.
+	ObjectName    string            `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	Matches       []*TypeaheadMatch `protobuf:"bytes,2,rep,name=matches,proto3" json:"matches,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupResponse) Reset() {
+	*x = LookupResponse{}
+	mi := &file_registry_v1_registry_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupResponse) ProtoMessage() {}
+
+func (x *LookupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupResponse.ProtoReflect.Descriptor instead.
+func (*LookupResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *LookupResponse) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+func (x *LookupResponse) GetMatches() []*TypeaheadMatch {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+type VersionConflict struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ExpectedVersion int64                  `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	CurrentVersion  int64                  `protobuf:"varint,3,opt,name=current_version,json=currentVersion,proto3" json:"current_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *VersionConflict) Reset() {
+	*x = VersionConflict{}
+	mi := &file_registry_v1_registry_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VersionConflict) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VersionConflict) ProtoMessage() {}
+
+func (x *VersionConflict) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VersionConflict.ProtoReflect.Descriptor instead.
+func (*VersionConflict) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *VersionConflict) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *VersionConflict) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+func (x *VersionConflict) GetCurrentVersion() int64 {
+	if x != nil {
+		return x.CurrentVersion
+	}
+	return 0
+}
+
+// ValidationFailed is attached to INVALID_ARGUMENT errors when an object's
+// validation webhook rejects a write.
+type ValidationFailed struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Violations    []*FieldViolation      `protobuf:"bytes,1,rep,name=violations,proto3" json:"violations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidationFailed) Reset() {
+	*x = ValidationFailed{}
+	mi := &file_registry_v1_registry_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidationFailed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidationFailed) ProtoMessage() {}
+
+func (x *ValidationFailed) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidationFailed.ProtoReflect.Descriptor instead.
+func (*ValidationFailed) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ValidationFailed) GetViolations() []*FieldViolation {
+	if x != nil {
+		return x.Violations
+	}
+	return nil
+}
+
+type FieldViolation struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Field api_name; empty for record-level messages.
+	Field   string `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// Where the violation came from; "webhook" for the validation webhook.
+	Source        string `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldViolation) Reset() {
+	*x = FieldViolation{}
+	mi := &file_registry_v1_registry_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldViolation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldViolation) ProtoMessage() {}
+
+func (x *FieldViolation) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldViolation.ProtoReflect.Descriptor instead.
+func (*FieldViolation) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *FieldViolation) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FieldViolation) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *FieldViolation) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+// CursorInvalidated is attached to FAILED_PRECONDITION errors when a pagination
+// cursor no longer matches the object schema or the requested ordering, e.g.
+// because the sort field was deleted between pages. Restart without a cursor.
+type CursorInvalidated struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Always "CURSOR_INVALIDATED".
+	Reason string `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Sort field the cursor was issued for.
+	OrderField string `protobuf:"bytes,2,opt,name=order_field,json=orderField,proto3" json:"order_field,omitempty"`
+	// Human-readable explanation.
+	Message       string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CursorInvalidated) Reset() {
+	*x = CursorInvalidated{}
+	mi := &file_registry_v1_registry_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CursorInvalidated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CursorInvalidated) ProtoMessage() {}
+
+func (x *CursorInvalidated) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_registry_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CursorInvalidated.ProtoReflect.Descriptor instead.
+func (*CursorInvalidated) Descriptor() ([]byte, []int) {
+	return file_registry_v1_registry_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *CursorInvalidated) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *CursorInvalidated) GetOrderField() string {
+	if x != nil {
+		return x.OrderField
+	}
+	return ""
+}
+
+func (x *CursorInvalidated) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_registry_v1_registry_proto protoreflect.FileDescriptor
+
+const file_registry_v1_registry_proto_rawDesc = "" +
+	"\n" +
+	"\x1aregistry/v1/registry.proto\x12\vregistry.v1\x1a\x1bbuf/validate/validate.proto\x1a\x1cgoogle/protobuf/struct.proto\"\xcc\x03\n" +
+	"\vListRequest\x12(\n" +
+	"\vobject_name\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\n" +
+	"objectName\x12\x16\n" +
+	"\x06select\x18\x02 \x01(\tR\x06select\x12\x16\n" +
+	"\x06expand\x18\x03 \x01(\tR\x06expand\x12\x14\n" +
+	"\x05order\x18\x04 \x01(\tR\x05order\x12\x1d\n" +
+	"\x05limit\x18\x05 \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x06 \x01(\tR\x06cursor\x12?\n" +
+	"\afilters\x18\a \x03(\v2%.registry.v1.ListRequest.FiltersEntryR\afilters\x12\x1a\n" +
+	"\bsnapshot\x18\b \x01(\bR\bsnapshot\x12\x10\n" +
+	"\x03raw\x18\t \x01(\bR\x03raw\x12<\n" +
+	"\vexpand_mode\x18\n" +
+	" \x01(\tB\x1b\xbaH\x18r\x16R\x00R\x06inlineR\n" +
+	"referencedR\n" +
+	"expandMode\x12-\n" +
+	"\x12include_terminated\x18\v \x01(\bR\x11includeTerminated\x1a:\n" +
+	"\fFiltersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xa9\x03\n" +
+	"\fListResponse\x12\x1f\n" +
+	"\vtotal_count\x18\x01 \x01(\x03R\n" +
+	"totalCount\x12$\n" +
+	"\vnext_cursor\x18\x02 \x01(\tH\x00R\n" +
+	"nextCursor\x88\x01\x01\x121\n" +
+	"\aresults\x18\x03 \x03(\v2\x17.google.protobuf.StructR\aresults\x12\x18\n" +
+	"\awarning\x18\x04 \x01(\tR\awarning\x12#\n" +
+	"\rcount_unknown\x18\x05 \x01(\bR\fcountUnknown\x125\n" +
+	"\n" +
+	"query_echo\x18\x06 \x01(\v2\x16.registry.v1.QueryEchoR\tqueryEcho\x12C\n" +
+	"\bincluded\x18\a \x03(\v2'.registry.v1.ListResponse.IncludedEntryR\bincluded\x1aT\n" +
+	"\rIncludedEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12-\n" +
+	"\x05value\x18\x02 \x01(\v2\x17.google.protobuf.StructR\x05value:\x028\x01B\x0e\n" +
+	"\f_next_cursor\"\xd1\x01\n" +
+	"\tQueryEcho\x12?\n" +
+	"\n" +
+	"conditions\x18\x01 \x03(\v2\x1f.registry.v1.QueryEchoConditionR\n" +
+	"conditions\x12\x14\n" +
+	"\x05order\x18\x02 \x01(\tR\x05order\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06select\x18\x04 \x03(\tR\x06select\x12\x16\n" +
+	"\x06expand\x18\x05 \x03(\tR\x06expand\x12'\n" +
+	"\x0fredacted_fields\x18\x06 \x03(\tR\x0eredactedFields\"V\n" +
+	"\x12QueryEchoCondition\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x16\n" +
+	"\x06filter\x18\x02 \x01(\tR\x06filter\x12\x12\n" +
+	"\x04kind\x18\x03 \x01(\tR\x04kind\"\x85\x02\n" +
+	"\x10SplitListRequest\x12(\n" +
+	"\vobject_name\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\n" +
+	"objectName\x12)\n" +
+	"\n" +
+	"partitions\x18\x02 \x01(\x05B\t\xbaH\x06\x1a\x04\x18@(\x01R\n" +
+	"partitions\x12D\n" +
+	"\afilters\x18\x03 \x03(\v2*.registry.v1.SplitListRequest.FiltersEntryR\afilters\x12\x1a\n" +
+	"\bsnapshot\x18\x04 \x01(\bR\bsnapshot\x1a:\n" +
+	"\fFiltersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"S\n" +
+	"\rListPartition\x12\x16\n" +
+	"\x06cursor\x18\x01 \x01(\tR\x06cursor\x12\x14\n" +
+	"\x05after\x18\x02 \x01(\tR\x05after\x12\x14\n" +
+	"\x05until\x18\x03 \x01(\tR\x05until\"v\n" +
+	"\x11SplitListResponse\x12:\n" +
+	"\n" +
+	"partitions\x18\x01 \x03(\v2\x1a.registry.v1.ListPartitionR\n" +
+	"partitions\x12%\n" +
+	"\x0eestimated_rows\x18\x02 \x01(\x03R\restimatedRows\"\x80\x01\n" +
+	"\n" +
+	"GetRequest\x12(\n" +
+	"\vobject_name\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\n" +
+	"objectName\x12\x18\n" +
+	"\x02id\x18\x02 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\x12\x16\n" +
+	"\x06select\x18\x03 \x01(\tR\x06select\x12\x16\n" +
+	"\x06expand\x18\x04 \x01(\tR\x06expand\"X\n" +
+	"\vGetResponse\x12/\n" +
+	"\x06record\x18\x01 \x01(\v2\x17.google.protobuf.StructR\x06record\x12\x18\n" +
+	"\awarning\x18\x02 \x01(\tR\awarning\"\x87\x01\n" +
+	"\rCreateRequest\x12(\n" +
+	"\vobject_name\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\n" +
+	"objectName\x123\n" +
+	"\x04data\x18\x02 \x01(\v2\x17.google.protobuf.StructB\x06\xbaH\x03\xc8\x01\x01R\x04data\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\"Z\n" +
+	"\x0eCreateResponse\x12/\n" +
+	"\x06record\x18\x01 \x01(\v2\x17.google.protobuf.StructR\x06record\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\"\xd5\x01\n" +
+	"\rUpdateRequest\x12(\n" +
+	"\vobject_name\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\n" +
+	"objectName\x12\x18\n" +
+	"\x02id\x18\x02 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\x123\n" +
+	"\x04data\x18\x03 \x01(\v2\x17.google.protobuf.StructB\x06\xbaH\x03\xc8\x01\x01R\x04data\x122\n" +
+	"\x10expected_version\x18\x04 \x01(\x03B\a\xbaH\x04\"\x02(\x00R\x0fexpectedVersion\x12\x17\n" +
+	"\adry_run\x18\x05 \x01(\bR\x06dryRun\"Z\n" +
+	"\x0eUpdateResponse\x12/\n" +
+	"\x06record\x18\x01 \x01(\v2\x17.google.protobuf.StructR\x06record\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\"\xfa\x02\n" +
+	"\x12UpdateWhereRequest\x12(\n" +
+	"\vobject_name\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\n" +
+	"objectName\x12F\n" +
+	"\afilters\x18\x02 \x03(\v2,.registry.v1.UpdateWhereRequest.FiltersEntryR\afilters\x12\x14\n" +
+	"\x05where\x18\x03 \x01(\tR\x05where\x123\n" +
+	"\x04data\x18\x04 \x01(\v2\x17.google.protobuf.StructB\x06\xbaH\x03\xc8\x01\x01R\x04data\x12\x17\n" +
+	"\adry_run\x18\x05 \x01(\bR\x06dryRun\x12.\n" +
+	"\x0eexpected_count\x18\x06 \x01(\x03B\a\xbaH\x04\"\x02(\x00R\rexpectedCount\x12\"\n" +
+	"\bmax_rows\x18\a \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\amaxRows\x1a:\n" +
+	"\fFiltersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"H\n" +
+	"\x13UpdateWhereResponse\x12\x18\n" +
+	"\amatched\x18\x01 \x01(\x03R\amatched\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\"\xb3\x01\n" +
+	"\rUpsertRequest\x12(\n" +
+	"\vobject_name\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\n" +
+	"objectName\x12*\n" +
+	"\x11external_id_field\x18\x02 \x01(\tR\x0fexternalIdField\x123\n" +
+	"\x04data\x18\x03 \x01(\v2\x17.google.protobuf.StructB\x06\xbaH\x03\xc8\x01\x01R\x04data\x12\x17\n" +
+	"\adry_run\x18\x04 \x01(\bR\x06dryRun\"t\n" +
+	"\x0eUpsertResponse\x12/\n" +
+	"\x06record\x18\x01 \x01(\v2\x17.google.protobuf.StructR\x06record\x12\x18\n" +
+	"\acreated\x18\x02 \x01(\bR\acreated\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\"\xa0\x01\n" +
+	"\rDeleteRequest\x12(\n" +
+	"\vobject_name\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\n" +
+	"objectName\x12\x18\n" +
+	"\x02id\x18\x02 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\x122\n" +
+	"\x10expected_version\x18\x03 \x01(\x03B\a\xbaH\x04\"\x02(\x00R\x0fexpectedVersion\x12\x17\n" +
+	"\adry_run\x18\x04 \x01(\bR\x06dryRun\"Z\n" +
+	"\x0eDeleteResponse\x12/\n" +
+	"\x06record\x18\x01 \x01(\v2\x17.google.protobuf.StructR\x06record\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\"]\n" +
+	"\x17GetRecordHistoryRequest\x12(\n" +
+	"\vobject_name\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\n" +
+	"objectName\x12\x18\n" +
+	"\x02id\x18\x02 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\"R\n" +
+	"\x18GetRecordHistoryResponse\x126\n" +
+	"\bversions\x18\x01 \x03(\v2\x1a.registry.v1.RecordVersionR\bversions\"\x85\x02\n" +
+	"\rRecordVersion\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x03R\aversion\x12\x1c\n" +
+	"\toperation\x18\x02 \x01(\tR\toperation\x12\x1d\n" +
+	"\n" +
+	"changed_at\x18\x03 \x01(\tR\tchangedAt\x12\x19\n" +
+	"\bactor_id\x18\x04 \x01(\tR\aactorId\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x05 \x01(\tR\trequestId\x12/\n" +
+	"\x06record\x18\x06 \x01(\v2\x17.google.protobuf.StructR\x06record\x122\n" +
+	"\achanges\x18\a \x03(\v2\x18.registry.v1.FieldChangeR\achanges\"\x8d\x01\n" +
+	"\vFieldChange\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x123\n" +
+	"\told_value\x18\x02 \x01(\v2\x16.google.protobuf.ValueR\boldValue\x123\n" +
+	"\tnew_value\x18\x03 \x01(\v2\x16.google.protobuf.ValueR\bnewValue\"\xc9\x01\n" +
+	"\x13RevertRecordRequest\x12(\n" +
+	"\vobject_name\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\n" +
+	"objectName\x12\x18\n" +
+	"\x02id\x18\x02 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\x12!\n" +
+	"\aversion\x18\x03 \x01(\x03B\a\xbaH\x04\"\x02 \x00R\aversion\x122\n" +
+	"\x10expected_version\x18\x04 \x01(\x03B\a\xbaH\x04\"\x02(\x00R\x0fexpectedVersion\x12\x17\n" +
+	"\adry_run\x18\x05 \x01(\bR\x06dryRun\"`\n" +
+	"\x14RevertRecordResponse\x12/\n" +
+	"\x06record\x18\x01 \x01(\v2\x17.google.protobuf.StructR\x06record\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\"u\n" +
+	"\x10TypeaheadRequest\x12(\n" +
+	"\vobject_name\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\n" +
+	"objectName\x12\x16\n" +
+	"\x01q\x18\x02 \x01(\tB\b\xbaH\x05r\x03\x18\xc8\x01R\x01q\x12\x1f\n" +
+	"\x05limit\x18\x03 \x01(\x05B\t\xbaH\x06\x1a\x04\x182(\x00R\x05limit\"J\n" +
+	"\x11TypeaheadResponse\x125\n" +
+	"\amatches\x18\x01 \x03(\v2\x1b.registry.v1.TypeaheadMatchR\amatches\":\n" +
+	"\x0eTypeaheadMatch\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\adisplay\x18\x02 \x01(\tR\adisplay\"\x91\x01\n" +
+	"\rLookupRequest\x12(\n" +
+	"\vobject_name\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\n" +
+	"objectName\x12\x1d\n" +
+	"\x05field\x18\x02 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\x05field\x12\x16\n" +
+	"\x01q\x18\x03 \x01(\tB\b\xbaH\x05r\x03\x18\xc8\x01R\x01q\x12\x1f\n" +
+	"\x05limit\x18\x04 \x01(\x05B\t\xbaH\x06\x1a\x04\x182(\x00R\x05limit\"h\n" +
+	"\x0eLookupResponse\x12\x1f\n" +
+	"\vobject_name\x18\x01 \x01(\tR\n" +
+	"objectName\x125\n" +
+	"\amatches\x18\x02 \x03(\v2\x1b.registry.v1.TypeaheadMatchR\amatches\"u\n" +
+	"\x0fVersionConflict\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12)\n" +
+	"\x10expected_version\x18\x02 \x01(\x03R\x0fexpectedVersion\x12'\n" +
+	"\x0fcurrent_version\x18\x03 \x01(\x03R\x0ecurrentVersion\"O\n" +
+	"\x10ValidationFailed\x12;\n" +
+	"\n" +
+	"violations\x18\x01 \x03(\v2\x1b.registry.v1.FieldViolationR\n" +
+	"violations\"X\n" +
+	"\x0eFieldViolation\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\"f\n" +
+	"\x11CursorInvalidated\x12\x16\n" +
+	"\x06reason\x18\x01 \x01(\tR\x06reason\x12\x1f\n" +
+	"\vorder_field\x18\x02 \x01(\tR\n" +
+	"orderField\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessageB\xad\x01\n" +
 	"\x0fcom.registry.v1B\rRegistryProtoP\x01Z>github.com/atlekbai/schema_registry/gen/registry/v1;registryv1\xa2\x02\x03RXX\xaa\x02\vRegistry.V1\xca\x02\vRegistry\\V1\xe2\x02\x17Registry\\V1\\GPBMetadata\xea\x02\fRegistry::V1b\x06proto3"
 
 var (
@@ -346,24 +2559,82 @@ func file_registry_v1_registry_proto_rawDescGZIP() []byte {
 	return file_registry_v1_registry_proto_rawDescData
 }
 
-var file_registry_v1_registry_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_registry_v1_registry_proto_msgTypes = make([]protoimpl.MessageInfo, 38)
 var file_registry_v1_registry_proto_goTypes = []any{
-	(*ListRequest)(nil),     // 0: registry.v1.ListRequest
-	(*ListResponse)(nil),    // 1: registry.v1.ListResponse
-	(*GetRequest)(nil),      // 2: registry.v1.GetRequest
-	(*GetResponse)(nil),     // 3: registry.v1.GetResponse
-	nil,                     // 4: registry.v1.ListRequest.FiltersEntry
-	(*structpb.Struct)(nil), // 5: google.protobuf.Struct
+	(*ListRequest)(nil),              // 0: registry.v1.ListRequest
+	(*ListResponse)(nil),             // 1: registry.v1.ListResponse
+	(*QueryEcho)(nil),                // 2: registry.v1.QueryEcho
+	(*QueryEchoCondition)(nil),       // 3: registry.v1.QueryEchoCondition
+	(*SplitListRequest)(nil),         // 4: registry.v1.SplitListRequest
+	(*ListPartition)(nil),            // 5: registry.v1.ListPartition
+	(*SplitListResponse)(nil),        // 6: registry.v1.SplitListResponse
+	(*GetRequest)(nil),               // 7: registry.v1.GetRequest
+	(*GetResponse)(nil),              // 8: registry.v1.GetResponse
+	(*CreateRequest)(nil),            // 9: registry.v1.CreateRequest
+	(*CreateResponse)(nil),           // 10: registry.v1.CreateResponse
+	(*UpdateRequest)(nil),            // 11: registry.v1.UpdateRequest
+	(*UpdateResponse)(nil),           // 12: registry.v1.UpdateResponse
+	(*UpdateWhereRequest)(nil),       // 13: registry.v1.UpdateWhereRequest
+	(*UpdateWhereResponse)(nil),      // 14: registry.v1.UpdateWhereResponse
+	(*UpsertRequest)(nil),            // 15: registry.v1.UpsertRequest
+	(*UpsertResponse)(nil),           // 16: registry.v1.UpsertResponse
+	(*DeleteRequest)(nil),            // 17: registry.v1.DeleteRequest
+	(*DeleteResponse)(nil),           // 18: registry.v1.DeleteResponse
+	(*GetRecordHistoryRequest)(nil),  // 19: registry.v1.GetRecordHistoryRequest
+	(*GetRecordHistoryResponse)(nil), // 20: registry.v1.GetRecordHistoryResponse
+	(*RecordVersion)(nil),            // 21: registry.v1.RecordVersion
+	(*FieldChange)(nil),              // 22: registry.v1.FieldChange
+	(*RevertRecordRequest)(nil),      // 23: registry.v1.RevertRecordRequest
+	(*RevertRecordResponse)(nil),     // 24: registry.v1.RevertRecordResponse
+	(*TypeaheadRequest)(nil),         // 25: registry.v1.TypeaheadRequest
+	(*TypeaheadResponse)(nil),        // 26: registry.v1.TypeaheadResponse
+	(*TypeaheadMatch)(nil),           // 27: registry.v1.TypeaheadMatch
+	(*LookupRequest)(nil),            // 28: registry.v1.LookupRequest
+	(*LookupResponse)(nil),           // 29: registry.v1.LookupResponse
+	(*VersionConflict)(nil),          // 30: registry.v1.VersionConflict
+	(*ValidationFailed)(nil),         // 31: registry.v1.ValidationFailed
+	(*FieldViolation)(nil),           // 32: registry.v1.FieldViolation
+	(*CursorInvalidated)(nil),        // 33: registry.v1.CursorInvalidated
+	nil,                              // 34: registry.v1.ListRequest.FiltersEntry
+	nil,                              // 35: registry.v1.ListResponse.IncludedEntry
+	nil,                              // 36: registry.v1.SplitListRequest.FiltersEntry
+	nil,                              // 37: registry.v1.UpdateWhereRequest.FiltersEntry
+	(*structpb.Struct)(nil),          // 38: google.protobuf.Struct
+	(*structpb.Value)(nil),           // 39: google.protobuf.Value
 }
 var file_registry_v1_registry_proto_depIdxs = []int32{
-	4, // 0: registry.v1.ListRequest.filters:type_name -> registry.v1.ListRequest.FiltersEntry
-	5, // 1: registry.v1.ListResponse.results:type_name -> google.protobuf.Struct
-	5, // 2: registry.v1.GetResponse.record:type_name -> google.protobuf.Struct
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	34, // 0: registry.v1.ListRequest.filters:type_name -> registry.v1.ListRequest.FiltersEntry
+	38, // 1: registry.v1.ListResponse.results:type_name -> google.protobuf.Struct
+	2,  // 2: registry.v1.ListResponse.query_echo:type_name -> registry.v1.QueryEcho
+	35, // 3: registry.v1.ListResponse.included:type_name -> registry.v1.ListResponse.IncludedEntry
+	3,  // 4: registry.v1.QueryEcho.conditions:type_name -> registry.v1.QueryEchoCondition
+	36, // 5: registry.v1.SplitListRequest.filters:type_name -> registry.v1.SplitListRequest.FiltersEntry
+	5,  // 6: registry.v1.SplitListResponse.partitions:type_name -> registry.v1.ListPartition
+	38, // 7: registry.v1.GetResponse.record:type_name -> google.protobuf.Struct
+	38, // 8: registry.v1.CreateRequest.data:type_name -> google.protobuf.Struct
+	38, // 9: registry.v1.CreateResponse.record:type_name -> google.protobuf.Struct
+	38, // 10: registry.v1.UpdateRequest.data:type_name -> google.protobuf.Struct
+	38, // 11: registry.v1.UpdateResponse.record:type_name -> google.protobuf.Struct
+	37, // 12: registry.v1.UpdateWhereRequest.filters:type_name -> registry.v1.UpdateWhereRequest.FiltersEntry
+	38, // 13: registry.v1.UpdateWhereRequest.data:type_name -> google.protobuf.Struct
+	38, // 14: registry.v1.UpsertRequest.data:type_name -> google.protobuf.Struct
+	38, // 15: registry.v1.UpsertResponse.record:type_name -> google.protobuf.Struct
+	38, // 16: registry.v1.DeleteResponse.record:type_name -> google.protobuf.Struct
+	21, // 17: registry.v1.GetRecordHistoryResponse.versions:type_name -> registry.v1.RecordVersion
+	38, // 18: registry.v1.RecordVersion.record:type_name -> google.protobuf.Struct
+	22, // 19: registry.v1.RecordVersion.changes:type_name -> registry.v1.FieldChange
+	39, // 20: registry.v1.FieldChange.old_value:type_name -> google.protobuf.Value
+	39, // 21: registry.v1.FieldChange.new_value:type_name -> google.protobuf.Value
+	38, // 22: registry.v1.RevertRecordResponse.record:type_name -> google.protobuf.Struct
+	27, // 23: registry.v1.TypeaheadResponse.matches:type_name -> registry.v1.TypeaheadMatch
+	27, // 24: registry.v1.LookupResponse.matches:type_name -> registry.v1.TypeaheadMatch
+	32, // 25: registry.v1.ValidationFailed.violations:type_name -> registry.v1.FieldViolation
+	38, // 26: registry.v1.ListResponse.IncludedEntry.value:type_name -> google.protobuf.Struct
+	27, // [27:27] is the sub-list for method output_type
+	27, // [27:27] is the sub-list for method input_type
+	27, // [27:27] is the sub-list for extension type_name
+	27, // [27:27] is the sub-list for extension extendee
+	0,  // [0:27] is the sub-list for field type_name
 }
 
 func init() { file_registry_v1_registry_proto_init() }
@@ -378,7 +2649,7 @@ func file_registry_v1_registry_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_registry_v1_registry_proto_rawDesc), len(file_registry_v1_registry_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   38,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
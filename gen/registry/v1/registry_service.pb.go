@@ -25,28 +25,79 @@ var File_registry_v1_registry_service_proto protoreflect.FileDescriptor
 
 const file_registry_v1_registry_service_proto_rawDesc = "" +
 	"\n" +
-	"\"registry/v1/registry_service.proto\x12\vregistry.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1aregistry/v1/registry.proto2\xc5\x01\n" +
+	"\"registry/v1/registry_service.proto\x12\vregistry.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1aregistry/v1/registry.proto2\xa2\n" +
+	"\n" +
 	"\x0fRegistryService\x12W\n" +
-	"\x04List\x12\x18.registry.v1.ListRequest\x1a\x19.registry.v1.ListResponse\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/api/{object_name}\x12Y\n" +
-	"\x03Get\x12\x17.registry.v1.GetRequest\x1a\x18.registry.v1.GetResponse\"\x1f\x82\xd3\xe4\x93\x02\x19\x12\x17/api/{object_name}/{id}B\xb4\x01\n" +
+	"\x04List\x12\x18.registry.v1.ListRequest\x1a\x19.registry.v1.ListResponse\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/api/{object_name}\x12q\n" +
+	"\tSplitList\x12\x1d.registry.v1.SplitListRequest\x1a\x1e.registry.v1.SplitListResponse\"%\x82\xd3\xe4\x93\x02\x1f\x12\x1d/api/{object_name}/partitions\x12p\n" +
+	"\tTypeahead\x12\x1d.registry.v1.TypeaheadRequest\x1a\x1e.registry.v1.TypeaheadResponse\"$\x82\xd3\xe4\x93\x02\x1e\x12\x1c/api/{object_name}/typeahead\x12d\n" +
+	"\x06Lookup\x12\x1a.registry.v1.LookupRequest\x1a\x1b.registry.v1.LookupResponse\"!\x82\xd3\xe4\x93\x02\x1b\x12\x19/api/{object_name}/lookup\x12Y\n" +
+	"\x03Get\x12\x17.registry.v1.GetRequest\x1a\x18.registry.v1.GetResponse\"\x1f\x82\xd3\xe4\x93\x02\x19\x12\x17/api/{object_name}/{id}\x12`\n" +
+	"\x06Create\x12\x1a.registry.v1.CreateRequest\x1a\x1b.registry.v1.CreateResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/api/{object_name}\x12e\n" +
+	"\x06Update\x12\x1a.registry.v1.UpdateRequest\x1a\x1b.registry.v1.UpdateResponse\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*2\x17/api/{object_name}/{id}\x12o\n" +
+	"\vUpdateWhere\x12\x1f.registry.v1.UpdateWhereRequest\x1a .registry.v1.UpdateWhereResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*2\x12/api/{object_name}\x12g\n" +
+	"\x06Upsert\x12\x1a.registry.v1.UpsertRequest\x1a\x1b.registry.v1.UpsertResponse\"$\x82\xd3\xe4\x93\x02\x1e:\x01*\"\x19/api/{object_name}/upsert\x12b\n" +
+	"\x06Delete\x12\x1a.registry.v1.DeleteRequest\x1a\x1b.registry.v1.DeleteResponse\"\x1f\x82\xd3\xe4\x93\x02\x19*\x17/api/{object_name}/{id}\x12\x88\x01\n" +
+	"\x10GetRecordHistory\x12$.registry.v1.GetRecordHistoryRequest\x1a%.registry.v1.GetRecordHistoryResponse\"'\x82\xd3\xe4\x93\x02!\x12\x1f/api/{object_name}/{id}/history\x12~\n" +
+	"\fRevertRecord\x12 .registry.v1.RevertRecordRequest\x1a!.registry.v1.RevertRecordResponse\")\x82\xd3\xe4\x93\x02#:\x01*\"\x1e/api/{object_name}/{id}/revertB\xb4\x01\n" +
 	"\x0fcom.registry.v1B\x14RegistryServiceProtoP\x01Z>github.com/atlekbai/schema_registry/gen/registry/v1;registryv1\xa2\x02\x03RXX\xaa\x02\vRegistry.V1\xca\x02\vRegistry\\V1\xe2\x02\x17Registry\\V1\\GPBMetadata\xea\x02\fRegistry::V1b\x06proto3"
 
 var file_registry_v1_registry_service_proto_goTypes = []any{
-	(*ListRequest)(nil),  // 0: registry.v1.ListRequest
-	(*GetRequest)(nil),   // 1: registry.v1.GetRequest
-	(*ListResponse)(nil), // 2: registry.v1.ListResponse
-	(*GetResponse)(nil),  // 3: registry.v1.GetResponse
+	(*ListRequest)(nil),              // 0: registry.v1.ListRequest
+	(*SplitListRequest)(nil),         // 1: registry.v1.SplitListRequest
+	(*TypeaheadRequest)(nil),         // 2: registry.v1.TypeaheadRequest
+	(*LookupRequest)(nil),            // 3: registry.v1.LookupRequest
+	(*GetRequest)(nil),               // 4: registry.v1.GetRequest
+	(*CreateRequest)(nil),            // 5: registry.v1.CreateRequest
+	(*UpdateRequest)(nil),            // 6: registry.v1.UpdateRequest
+	(*UpdateWhereRequest)(nil),       // 7: registry.v1.UpdateWhereRequest
+	(*UpsertRequest)(nil),            // 8: registry.v1.UpsertRequest
+	(*DeleteRequest)(nil),            // 9: registry.v1.DeleteRequest
+	(*GetRecordHistoryRequest)(nil),  // 10: registry.v1.GetRecordHistoryRequest
+	(*RevertRecordRequest)(nil),      // 11: registry.v1.RevertRecordRequest
+	(*ListResponse)(nil),             // 12: registry.v1.ListResponse
+	(*SplitListResponse)(nil),        // 13: registry.v1.SplitListResponse
+	(*TypeaheadResponse)(nil),        // 14: registry.v1.TypeaheadResponse
+	(*LookupResponse)(nil),           // 15: registry.v1.LookupResponse
+	(*GetResponse)(nil),              // 16: registry.v1.GetResponse
+	(*CreateResponse)(nil),           // 17: registry.v1.CreateResponse
+	(*UpdateResponse)(nil),           // 18: registry.v1.UpdateResponse
+	(*UpdateWhereResponse)(nil),      // 19: registry.v1.UpdateWhereResponse
+	(*UpsertResponse)(nil),           // 20: registry.v1.UpsertResponse
+	(*DeleteResponse)(nil),           // 21: registry.v1.DeleteResponse
+	(*GetRecordHistoryResponse)(nil), // 22: registry.v1.GetRecordHistoryResponse
+	(*RevertRecordResponse)(nil),     // 23: registry.v1.RevertRecordResponse
 }
 var file_registry_v1_registry_service_proto_depIdxs = []int32{
-	0, // 0: registry.v1.RegistryService.List:input_type -> registry.v1.ListRequest
-	1, // 1: registry.v1.RegistryService.Get:input_type -> registry.v1.GetRequest
-	2, // 2: registry.v1.RegistryService.List:output_type -> registry.v1.ListResponse
-	3, // 3: registry.v1.RegistryService.Get:output_type -> registry.v1.GetResponse
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: registry.v1.RegistryService.List:input_type -> registry.v1.ListRequest
+	1,  // 1: registry.v1.RegistryService.SplitList:input_type -> registry.v1.SplitListRequest
+	2,  // 2: registry.v1.RegistryService.Typeahead:input_type -> registry.v1.TypeaheadRequest
+	3,  // 3: registry.v1.RegistryService.Lookup:input_type -> registry.v1.LookupRequest
+	4,  // 4: registry.v1.RegistryService.Get:input_type -> registry.v1.GetRequest
+	5,  // 5: registry.v1.RegistryService.Create:input_type -> registry.v1.CreateRequest
+	6,  // 6: registry.v1.RegistryService.Update:input_type -> registry.v1.UpdateRequest
+	7,  // 7: registry.v1.RegistryService.UpdateWhere:input_type -> registry.v1.UpdateWhereRequest
+	8,  // 8: registry.v1.RegistryService.Upsert:input_type -> registry.v1.UpsertRequest
+	9,  // 9: registry.v1.RegistryService.Delete:input_type -> registry.v1.DeleteRequest
+	10, // 10: registry.v1.RegistryService.GetRecordHistory:input_type -> registry.v1.GetRecordHistoryRequest
+	11, // 11: registry.v1.RegistryService.RevertRecord:input_type -> registry.v1.RevertRecordRequest
+	12, // 12: registry.v1.RegistryService.List:output_type -> registry.v1.ListResponse
+	13, // 13: registry.v1.RegistryService.SplitList:output_type -> registry.v1.SplitListResponse
+	14, // 14: registry.v1.RegistryService.Typeahead:output_type -> registry.v1.TypeaheadResponse
+	15, // 15: registry.v1.RegistryService.Lookup:output_type -> registry.v1.LookupResponse
+	16, // 16: registry.v1.RegistryService.Get:output_type -> registry.v1.GetResponse
+	17, // 17: registry.v1.RegistryService.Create:output_type -> registry.v1.CreateResponse
+	18, // 18: registry.v1.RegistryService.Update:output_type -> registry.v1.UpdateResponse
+	19, // 19: registry.v1.RegistryService.UpdateWhere:output_type -> registry.v1.UpdateWhereResponse
+	20, // 20: registry.v1.RegistryService.Upsert:output_type -> registry.v1.UpsertResponse
+	21, // 21: registry.v1.RegistryService.Delete:output_type -> registry.v1.DeleteResponse
+	22, // 22: registry.v1.RegistryService.GetRecordHistory:output_type -> registry.v1.GetRecordHistoryResponse
+	23, // 23: registry.v1.RegistryService.RevertRecord:output_type -> registry.v1.RevertRecordResponse
+	12, // [12:24] is the sub-list for method output_type
+	0,  // [0:12] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_registry_v1_registry_service_proto_init() }
@@ -0,0 +1,635 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: registry/v1/admin_service.proto
+
+package registryv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/atlekbai/schema_registry/gen/registry/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// AdminServiceName is the fully-qualified name of the AdminService service.
+	AdminServiceName = "registry.v1.AdminService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// AdminServiceMigrationStatusProcedure is the fully-qualified name of the AdminService's
+	// MigrationStatus RPC.
+	AdminServiceMigrationStatusProcedure = "/registry.v1.AdminService/MigrationStatus"
+	// AdminServiceGetMaintenanceModeProcedure is the fully-qualified name of the AdminService's
+	// GetMaintenanceMode RPC.
+	AdminServiceGetMaintenanceModeProcedure = "/registry.v1.AdminService/GetMaintenanceMode"
+	// AdminServiceSetMaintenanceModeProcedure is the fully-qualified name of the AdminService's
+	// SetMaintenanceMode RPC.
+	AdminServiceSetMaintenanceModeProcedure = "/registry.v1.AdminService/SetMaintenanceMode"
+	// AdminServiceGetSchemaCacheProcedure is the fully-qualified name of the AdminService's
+	// GetSchemaCache RPC.
+	AdminServiceGetSchemaCacheProcedure = "/registry.v1.AdminService/GetSchemaCache"
+	// AdminServiceReloadSchemaCacheProcedure is the fully-qualified name of the AdminService's
+	// ReloadSchemaCache RPC.
+	AdminServiceReloadSchemaCacheProcedure = "/registry.v1.AdminService/ReloadSchemaCache"
+	// AdminServiceEvictSchemaCacheObjectProcedure is the fully-qualified name of the AdminService's
+	// EvictSchemaCacheObject RPC.
+	AdminServiceEvictSchemaCacheObjectProcedure = "/registry.v1.AdminService/EvictSchemaCacheObject"
+	// AdminServiceListRetentionPoliciesProcedure is the fully-qualified name of the AdminService's
+	// ListRetentionPolicies RPC.
+	AdminServiceListRetentionPoliciesProcedure = "/registry.v1.AdminService/ListRetentionPolicies"
+	// AdminServiceSetRetentionPolicyProcedure is the fully-qualified name of the AdminService's
+	// SetRetentionPolicy RPC.
+	AdminServiceSetRetentionPolicyProcedure = "/registry.v1.AdminService/SetRetentionPolicy"
+	// AdminServiceDeleteRetentionPolicyProcedure is the fully-qualified name of the AdminService's
+	// DeleteRetentionPolicy RPC.
+	AdminServiceDeleteRetentionPolicyProcedure = "/registry.v1.AdminService/DeleteRetentionPolicy"
+	// AdminServiceRunRetentionProcedure is the fully-qualified name of the AdminService's RunRetention
+	// RPC.
+	AdminServiceRunRetentionProcedure = "/registry.v1.AdminService/RunRetention"
+	// AdminServiceListRetentionAuditProcedure is the fully-qualified name of the AdminService's
+	// ListRetentionAudit RPC.
+	AdminServiceListRetentionAuditProcedure = "/registry.v1.AdminService/ListRetentionAudit"
+	// AdminServiceRebuildHierarchyPathsProcedure is the fully-qualified name of the AdminService's
+	// RebuildHierarchyPaths RPC.
+	AdminServiceRebuildHierarchyPathsProcedure = "/registry.v1.AdminService/RebuildHierarchyPaths"
+	// AdminServiceSearchIndexReportProcedure is the fully-qualified name of the AdminService's
+	// SearchIndexReport RPC.
+	AdminServiceSearchIndexReportProcedure = "/registry.v1.AdminService/SearchIndexReport"
+	// AdminServiceLintSchemaProcedure is the fully-qualified name of the AdminService's LintSchema RPC.
+	AdminServiceLintSchemaProcedure = "/registry.v1.AdminService/LintSchema"
+	// AdminServiceAnonymizeObjectProcedure is the fully-qualified name of the AdminService's
+	// AnonymizeObject RPC.
+	AdminServiceAnonymizeObjectProcedure = "/registry.v1.AdminService/AnonymizeObject"
+	// AdminServiceSeedStandardObjectsProcedure is the fully-qualified name of the AdminService's
+	// SeedStandardObjects RPC.
+	AdminServiceSeedStandardObjectsProcedure = "/registry.v1.AdminService/SeedStandardObjects"
+)
+
+// AdminServiceClient is a client for the registry.v1.AdminService service.
+type AdminServiceClient interface {
+	// MigrationStatus lists the schema migrations embedded in the server binary
+	// and whether each has been applied to the connected database.
+	MigrationStatus(context.Context, *connect.Request[v1.MigrationStatusRequest]) (*connect.Response[v1.MigrationStatusResponse], error)
+	// GetMaintenanceMode reports whether this server instance is read-only.
+	GetMaintenanceMode(context.Context, *connect.Request[v1.GetMaintenanceModeRequest]) (*connect.Response[v1.GetMaintenanceModeResponse], error)
+	// SetMaintenanceMode switches this server instance into or out of read-only
+	// mode. While read-only, record writes and metadata mutations fail with
+	// UNAVAILABLE; reads and HRQL queries keep working. The switch is per
+	// instance and resets to READ_ONLY on restart.
+	SetMaintenanceMode(context.Context, *connect.Request[v1.SetMaintenanceModeRequest]) (*connect.Response[v1.SetMaintenanceModeResponse], error)
+	// GetSchemaCache describes this instance's in-memory schema cache: the
+	// cached objects with their field counts, when it was last loaded and its
+	// generation, which increases on every load or object reload.
+	GetSchemaCache(context.Context, *connect.Request[v1.GetSchemaCacheRequest]) (*connect.Response[v1.GetSchemaCacheResponse], error)
+	// ReloadSchemaCache re-reads every object and field from the catalog.
+	ReloadSchemaCache(context.Context, *connect.Request[v1.ReloadSchemaCacheRequest]) (*connect.Response[v1.ReloadSchemaCacheResponse], error)
+	// EvictSchemaCacheObject drops one object from the cache and re-reads it
+	// from the catalog; an object deleted from the catalog stays evicted.
+	EvictSchemaCacheObject(context.Context, *connect.Request[v1.EvictSchemaCacheObjectRequest]) (*connect.Response[v1.EvictSchemaCacheObjectResponse], error)
+	// ListRetentionPolicies lists the data retention policy of every object
+	// that has one.
+	ListRetentionPolicies(context.Context, *connect.Request[v1.ListRetentionPoliciesRequest]) (*connect.Response[v1.ListRetentionPoliciesResponse], error)
+	// SetRetentionPolicy creates or replaces an object's retention policy.
+	// Records whose date_field is more than retain_days in the past are deleted
+	// or have anonymize_fields cleared by the retention scheduler.
+	SetRetentionPolicy(context.Context, *connect.Request[v1.SetRetentionPolicyRequest]) (*connect.Response[v1.SetRetentionPolicyResponse], error)
+	// DeleteRetentionPolicy removes an object's retention policy.
+	DeleteRetentionPolicy(context.Context, *connect.Request[v1.DeleteRetentionPolicyRequest]) (*connect.Response[v1.DeleteRetentionPolicyResponse], error)
+	// RunRetention applies every enabled policy now instead of waiting for the
+	// scheduler. Nothing runs while another instance holds the retention lock.
+	RunRetention(context.Context, *connect.Request[v1.RunRetentionRequest]) (*connect.Response[v1.RunRetentionResponse], error)
+	// ListRetentionAudit lists records purged by retention policies, newest first.
+	ListRetentionAudit(context.Context, *connect.Request[v1.ListRetentionAuditRequest]) (*connect.Response[v1.ListRetentionAuditResponse], error)
+	// RebuildHierarchyPaths recomputes the ltree path column of a hierarchy
+	// (e.g. employees.manager → manager_path) from its lookup column and
+	// rewrites the rows whose stored path is wrong. Writes to the table wait
+	// until it finishes.
+	RebuildHierarchyPaths(context.Context, *connect.Request[v1.RebuildHierarchyPathsRequest]) (*connect.Response[v1.RebuildHierarchyPathsResponse], error)
+	// SearchIndexReport lists the fields HRQL string operations (contains,
+	// starts_with, ends_with) have run on since startup and the fields flagged
+	// is_searchable, with whether a trigram index serves them.
+	SearchIndexReport(context.Context, *connect.Request[v1.SearchIndexReportRequest]) (*connect.Response[v1.SearchIndexReportResponse], error)
+	// LintSchema analyzes the catalog for quality problems: lookup cycles,
+	// objects and fields without titles, api_names that only differ in case,
+	// underscores or the __c suffix, custom fields no record has a value for,
+	// and sortable fields without an index. Findings come most severe first.
+	LintSchema(context.Context, *connect.Request[v1.LintSchemaRequest]) (*connect.Response[v1.LintSchemaResponse], error)
+	// AnonymizeObject replaces the values of an object's fields flagged is_pii
+	// with fakes derived from an HMAC of each value under salt, so a restored
+	// production snapshot can be used in a lower environment. Equal values get
+	// equal fakes, in every object and on every run with the same salt, so
+	// uniqueness and matches across records survive. Records are rewritten in
+	// batches through the regular write path (new version, lookup labels);
+	// their earlier history versions are dropped as retention does. The
+	// point-in-time employee history (core.employees_history) is not rewritten.
+	AnonymizeObject(context.Context, *connect.Request[v1.AnonymizeObjectRequest]) (*connect.Response[v1.AnonymizeObjectResponse], error)
+	// SeedStandardObjects applies the built-in standard object definitions to
+	// the catalog: missing objects and fields are created, and drifted storage
+	// mappings, types and constraints are reset. Titles and descriptions are
+	// kept. A version already recorded is skipped unless force is set. The
+	// schema cache is reloaded when anything changed.
+	SeedStandardObjects(context.Context, *connect.Request[v1.SeedStandardObjectsRequest]) (*connect.Response[v1.SeedStandardObjectsResponse], error)
+}
+
+// NewAdminServiceClient constructs a client for the registry.v1.AdminService service. By default,
+// it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and
+// sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC()
+// or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewAdminServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) AdminServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	adminServiceMethods := v1.File_registry_v1_admin_service_proto.Services().ByName("AdminService").Methods()
+	return &adminServiceClient{
+		migrationStatus: connect.NewClient[v1.MigrationStatusRequest, v1.MigrationStatusResponse](
+			httpClient,
+			baseURL+AdminServiceMigrationStatusProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("MigrationStatus")),
+			connect.WithClientOptions(opts...),
+		),
+		getMaintenanceMode: connect.NewClient[v1.GetMaintenanceModeRequest, v1.GetMaintenanceModeResponse](
+			httpClient,
+			baseURL+AdminServiceGetMaintenanceModeProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("GetMaintenanceMode")),
+			connect.WithClientOptions(opts...),
+		),
+		setMaintenanceMode: connect.NewClient[v1.SetMaintenanceModeRequest, v1.SetMaintenanceModeResponse](
+			httpClient,
+			baseURL+AdminServiceSetMaintenanceModeProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("SetMaintenanceMode")),
+			connect.WithClientOptions(opts...),
+		),
+		getSchemaCache: connect.NewClient[v1.GetSchemaCacheRequest, v1.GetSchemaCacheResponse](
+			httpClient,
+			baseURL+AdminServiceGetSchemaCacheProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("GetSchemaCache")),
+			connect.WithClientOptions(opts...),
+		),
+		reloadSchemaCache: connect.NewClient[v1.ReloadSchemaCacheRequest, v1.ReloadSchemaCacheResponse](
+			httpClient,
+			baseURL+AdminServiceReloadSchemaCacheProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("ReloadSchemaCache")),
+			connect.WithClientOptions(opts...),
+		),
+		evictSchemaCacheObject: connect.NewClient[v1.EvictSchemaCacheObjectRequest, v1.EvictSchemaCacheObjectResponse](
+			httpClient,
+			baseURL+AdminServiceEvictSchemaCacheObjectProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("EvictSchemaCacheObject")),
+			connect.WithClientOptions(opts...),
+		),
+		listRetentionPolicies: connect.NewClient[v1.ListRetentionPoliciesRequest, v1.ListRetentionPoliciesResponse](
+			httpClient,
+			baseURL+AdminServiceListRetentionPoliciesProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("ListRetentionPolicies")),
+			connect.WithClientOptions(opts...),
+		),
+		setRetentionPolicy: connect.NewClient[v1.SetRetentionPolicyRequest, v1.SetRetentionPolicyResponse](
+			httpClient,
+			baseURL+AdminServiceSetRetentionPolicyProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("SetRetentionPolicy")),
+			connect.WithClientOptions(opts...),
+		),
+		deleteRetentionPolicy: connect.NewClient[v1.DeleteRetentionPolicyRequest, v1.DeleteRetentionPolicyResponse](
+			httpClient,
+			baseURL+AdminServiceDeleteRetentionPolicyProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("DeleteRetentionPolicy")),
+			connect.WithClientOptions(opts...),
+		),
+		runRetention: connect.NewClient[v1.RunRetentionRequest, v1.RunRetentionResponse](
+			httpClient,
+			baseURL+AdminServiceRunRetentionProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("RunRetention")),
+			connect.WithClientOptions(opts...),
+		),
+		listRetentionAudit: connect.NewClient[v1.ListRetentionAuditRequest, v1.ListRetentionAuditResponse](
+			httpClient,
+			baseURL+AdminServiceListRetentionAuditProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("ListRetentionAudit")),
+			connect.WithClientOptions(opts...),
+		),
+		rebuildHierarchyPaths: connect.NewClient[v1.RebuildHierarchyPathsRequest, v1.RebuildHierarchyPathsResponse](
+			httpClient,
+			baseURL+AdminServiceRebuildHierarchyPathsProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("RebuildHierarchyPaths")),
+			connect.WithClientOptions(opts...),
+		),
+		searchIndexReport: connect.NewClient[v1.SearchIndexReportRequest, v1.SearchIndexReportResponse](
+			httpClient,
+			baseURL+AdminServiceSearchIndexReportProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("SearchIndexReport")),
+			connect.WithClientOptions(opts...),
+		),
+		lintSchema: connect.NewClient[v1.LintSchemaRequest, v1.LintSchemaResponse](
+			httpClient,
+			baseURL+AdminServiceLintSchemaProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("LintSchema")),
+			connect.WithClientOptions(opts...),
+		),
+		anonymizeObject: connect.NewClient[v1.AnonymizeObjectRequest, v1.AnonymizeObjectResponse](
+			httpClient,
+			baseURL+AdminServiceAnonymizeObjectProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("AnonymizeObject")),
+			connect.WithClientOptions(opts...),
+		),
+		seedStandardObjects: connect.NewClient[v1.SeedStandardObjectsRequest, v1.SeedStandardObjectsResponse](
+			httpClient,
+			baseURL+AdminServiceSeedStandardObjectsProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("SeedStandardObjects")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// adminServiceClient implements AdminServiceClient.
+type adminServiceClient struct {
+	migrationStatus        *connect.Client[v1.MigrationStatusRequest, v1.MigrationStatusResponse]
+	getMaintenanceMode     *connect.Client[v1.GetMaintenanceModeRequest, v1.GetMaintenanceModeResponse]
+	setMaintenanceMode     *connect.Client[v1.SetMaintenanceModeRequest, v1.SetMaintenanceModeResponse]
+	getSchemaCache         *connect.Client[v1.GetSchemaCacheRequest, v1.GetSchemaCacheResponse]
+	reloadSchemaCache      *connect.Client[v1.ReloadSchemaCacheRequest, v1.ReloadSchemaCacheResponse]
+	evictSchemaCacheObject *connect.Client[v1.EvictSchemaCacheObjectRequest, v1.EvictSchemaCacheObjectResponse]
+	listRetentionPolicies  *connect.Client[v1.ListRetentionPoliciesRequest, v1.ListRetentionPoliciesResponse]
+	setRetentionPolicy     *connect.Client[v1.SetRetentionPolicyRequest, v1.SetRetentionPolicyResponse]
+	deleteRetentionPolicy  *connect.Client[v1.DeleteRetentionPolicyRequest, v1.DeleteRetentionPolicyResponse]
+	runRetention           *connect.Client[v1.RunRetentionRequest, v1.RunRetentionResponse]
+	listRetentionAudit     *connect.Client[v1.ListRetentionAuditRequest, v1.ListRetentionAuditResponse]
+	rebuildHierarchyPaths  *connect.Client[v1.RebuildHierarchyPathsRequest, v1.RebuildHierarchyPathsResponse]
+	searchIndexReport      *connect.Client[v1.SearchIndexReportRequest, v1.SearchIndexReportResponse]
+	lintSchema             *connect.Client[v1.LintSchemaRequest, v1.LintSchemaResponse]
+	anonymizeObject        *connect.Client[v1.AnonymizeObjectRequest, v1.AnonymizeObjectResponse]
+	seedStandardObjects    *connect.Client[v1.SeedStandardObjectsRequest, v1.SeedStandardObjectsResponse]
+}
+
+// MigrationStatus calls registry.v1.AdminService.MigrationStatus.
+func (c *adminServiceClient) MigrationStatus(ctx context.Context, req *connect.Request[v1.MigrationStatusRequest]) (*connect.Response[v1.MigrationStatusResponse], error) {
+	return c.migrationStatus.CallUnary(ctx, req)
+}
+
+// GetMaintenanceMode calls registry.v1.AdminService.GetMaintenanceMode.
+func (c *adminServiceClient) GetMaintenanceMode(ctx context.Context, req *connect.Request[v1.GetMaintenanceModeRequest]) (*connect.Response[v1.GetMaintenanceModeResponse], error) {
+	return c.getMaintenanceMode.CallUnary(ctx, req)
+}
+
+// SetMaintenanceMode calls registry.v1.AdminService.SetMaintenanceMode.
+func (c *adminServiceClient) SetMaintenanceMode(ctx context.Context, req *connect.Request[v1.SetMaintenanceModeRequest]) (*connect.Response[v1.SetMaintenanceModeResponse], error) {
+	return c.setMaintenanceMode.CallUnary(ctx, req)
+}
+
+// GetSchemaCache calls registry.v1.AdminService.GetSchemaCache.
+func (c *adminServiceClient) GetSchemaCache(ctx context.Context, req *connect.Request[v1.GetSchemaCacheRequest]) (*connect.Response[v1.GetSchemaCacheResponse], error) {
+	return c.getSchemaCache.CallUnary(ctx, req)
+}
+
+// ReloadSchemaCache calls registry.v1.AdminService.ReloadSchemaCache.
+func (c *adminServiceClient) ReloadSchemaCache(ctx context.Context, req *connect.Request[v1.ReloadSchemaCacheRequest]) (*connect.Response[v1.ReloadSchemaCacheResponse], error) {
+	return c.reloadSchemaCache.CallUnary(ctx, req)
+}
+
+// EvictSchemaCacheObject calls registry.v1.AdminService.EvictSchemaCacheObject.
+func (c *adminServiceClient) EvictSchemaCacheObject(ctx context.Context, req *connect.Request[v1.EvictSchemaCacheObjectRequest]) (*connect.Response[v1.EvictSchemaCacheObjectResponse], error) {
+	return c.evictSchemaCacheObject.CallUnary(ctx, req)
+}
+
+// ListRetentionPolicies calls registry.v1.AdminService.ListRetentionPolicies.
+func (c *adminServiceClient) ListRetentionPolicies(ctx context.Context, req *connect.Request[v1.ListRetentionPoliciesRequest]) (*connect.Response[v1.ListRetentionPoliciesResponse], error) {
+	return c.listRetentionPolicies.CallUnary(ctx, req)
+}
+
+// SetRetentionPolicy calls registry.v1.AdminService.SetRetentionPolicy.
+func (c *adminServiceClient) SetRetentionPolicy(ctx context.Context, req *connect.Request[v1.SetRetentionPolicyRequest]) (*connect.Response[v1.SetRetentionPolicyResponse], error) {
+	return c.setRetentionPolicy.CallUnary(ctx, req)
+}
+
+// DeleteRetentionPolicy calls registry.v1.AdminService.DeleteRetentionPolicy.
+func (c *adminServiceClient) DeleteRetentionPolicy(ctx context.Context, req *connect.Request[v1.DeleteRetentionPolicyRequest]) (*connect.Response[v1.DeleteRetentionPolicyResponse], error) {
+	return c.deleteRetentionPolicy.CallUnary(ctx, req)
+}
+
+// RunRetention calls registry.v1.AdminService.RunRetention.
+func (c *adminServiceClient) RunRetention(ctx context.Context, req *connect.Request[v1.RunRetentionRequest]) (*connect.Response[v1.RunRetentionResponse], error) {
+	return c.runRetention.CallUnary(ctx, req)
+}
+
+// ListRetentionAudit calls registry.v1.AdminService.ListRetentionAudit.
+func (c *adminServiceClient) ListRetentionAudit(ctx context.Context, req *connect.Request[v1.ListRetentionAuditRequest]) (*connect.Response[v1.ListRetentionAuditResponse], error) {
+	return c.listRetentionAudit.CallUnary(ctx, req)
+}
+
+// RebuildHierarchyPaths calls registry.v1.AdminService.RebuildHierarchyPaths.
+func (c *adminServiceClient) RebuildHierarchyPaths(ctx context.Context, req *connect.Request[v1.RebuildHierarchyPathsRequest]) (*connect.Response[v1.RebuildHierarchyPathsResponse], error) {
+	return c.rebuildHierarchyPaths.CallUnary(ctx, req)
+}
+
+// SearchIndexReport calls registry.v1.AdminService.SearchIndexReport.
+func (c *adminServiceClient) SearchIndexReport(ctx context.Context, req *connect.Request[v1.SearchIndexReportRequest]) (*connect.Response[v1.SearchIndexReportResponse], error) {
+	return c.searchIndexReport.CallUnary(ctx, req)
+}
+
+// LintSchema calls registry.v1.AdminService.LintSchema.
+func (c *adminServiceClient) LintSchema(ctx context.Context, req *connect.Request[v1.LintSchemaRequest]) (*connect.Response[v1.LintSchemaResponse], error) {
+	return c.lintSchema.CallUnary(ctx, req)
+}
+
+// AnonymizeObject calls registry.v1.AdminService.AnonymizeObject.
+func (c *adminServiceClient) AnonymizeObject(ctx context.Context, req *connect.Request[v1.AnonymizeObjectRequest]) (*connect.Response[v1.AnonymizeObjectResponse], error) {
+	return c.anonymizeObject.CallUnary(ctx, req)
+}
+
+// SeedStandardObjects calls registry.v1.AdminService.SeedStandardObjects.
+func (c *adminServiceClient) SeedStandardObjects(ctx context.Context, req *connect.Request[v1.SeedStandardObjectsRequest]) (*connect.Response[v1.SeedStandardObjectsResponse], error) {
+	return c.seedStandardObjects.CallUnary(ctx, req)
+}
+
+// AdminServiceHandler is an implementation of the registry.v1.AdminService service.
+type AdminServiceHandler interface {
+	// MigrationStatus lists the schema migrations embedded in the server binary
+	// and whether each has been applied to the connected database.
+	MigrationStatus(context.Context, *connect.Request[v1.MigrationStatusRequest]) (*connect.Response[v1.MigrationStatusResponse], error)
+	// GetMaintenanceMode reports whether this server instance is read-only.
+	GetMaintenanceMode(context.Context, *connect.Request[v1.GetMaintenanceModeRequest]) (*connect.Response[v1.GetMaintenanceModeResponse], error)
+	// SetMaintenanceMode switches this server instance into or out of read-only
+	// mode. While read-only, record writes and metadata mutations fail with
+	// UNAVAILABLE; reads and HRQL queries keep working. The switch is per
+	// instance and resets to READ_ONLY on restart.
+	SetMaintenanceMode(context.Context, *connect.Request[v1.SetMaintenanceModeRequest]) (*connect.Response[v1.SetMaintenanceModeResponse], error)
+	// GetSchemaCache describes this instance's in-memory schema cache: the
+	// cached objects with their field counts, when it was last loaded and its
+	// generation, which increases on every load or object reload.
+	GetSchemaCache(context.Context, *connect.Request[v1.GetSchemaCacheRequest]) (*connect.Response[v1.GetSchemaCacheResponse], error)
+	// ReloadSchemaCache re-reads every object and field from the catalog.
+	ReloadSchemaCache(context.Context, *connect.Request[v1.ReloadSchemaCacheRequest]) (*connect.Response[v1.ReloadSchemaCacheResponse], error)
+	// EvictSchemaCacheObject drops one object from the cache and re-reads it
+	// from the catalog; an object deleted from the catalog stays evicted.
+	EvictSchemaCacheObject(context.Context, *connect.Request[v1.EvictSchemaCacheObjectRequest]) (*connect.Response[v1.EvictSchemaCacheObjectResponse], error)
+	// ListRetentionPolicies lists the data retention policy of every object
+	// that has one.
+	ListRetentionPolicies(context.Context, *connect.Request[v1.ListRetentionPoliciesRequest]) (*connect.Response[v1.ListRetentionPoliciesResponse], error)
+	// SetRetentionPolicy creates or replaces an object's retention policy.
+	// Records whose date_field is more than retain_days in the past are deleted
+	// or have anonymize_fields cleared by the retention scheduler.
+	SetRetentionPolicy(context.Context, *connect.Request[v1.SetRetentionPolicyRequest]) (*connect.Response[v1.SetRetentionPolicyResponse], error)
+	// DeleteRetentionPolicy removes an object's retention policy.
+	DeleteRetentionPolicy(context.Context, *connect.Request[v1.DeleteRetentionPolicyRequest]) (*connect.Response[v1.DeleteRetentionPolicyResponse], error)
+	// RunRetention applies every enabled policy now instead of waiting for the
+	// scheduler. Nothing runs while another instance holds the retention lock.
+	RunRetention(context.Context, *connect.Request[v1.RunRetentionRequest]) (*connect.Response[v1.RunRetentionResponse], error)
+	// ListRetentionAudit lists records purged by retention policies, newest first.
+	ListRetentionAudit(context.Context, *connect.Request[v1.ListRetentionAuditRequest]) (*connect.Response[v1.ListRetentionAuditResponse], error)
+	// RebuildHierarchyPaths recomputes the ltree path column of a hierarchy
+	// (e.g. employees.manager → manager_path) from its lookup column and
+	// rewrites the rows whose stored path is wrong. Writes to the table wait
+	// until it finishes.
+	RebuildHierarchyPaths(context.Context, *connect.Request[v1.RebuildHierarchyPathsRequest]) (*connect.Response[v1.RebuildHierarchyPathsResponse], error)
+	// SearchIndexReport lists the fields HRQL string operations (contains,
+	// starts_with, ends_with) have run on since startup and the fields flagged
+	// is_searchable, with whether a trigram index serves them.
+	SearchIndexReport(context.Context, *connect.Request[v1.SearchIndexReportRequest]) (*connect.Response[v1.SearchIndexReportResponse], error)
+	// LintSchema analyzes the catalog for quality problems: lookup cycles,
+	// objects and fields without titles, api_names that only differ in case,
+	// underscores or the __c suffix, custom fields no record has a value for,
+	// and sortable fields without an index. Findings come most severe first.
+	LintSchema(context.Context, *connect.Request[v1.LintSchemaRequest]) (*connect.Response[v1.LintSchemaResponse], error)
+	// AnonymizeObject replaces the values of an object's fields flagged is_pii
+	// with fakes derived from an HMAC of each value under salt, so a restored
+	// production snapshot can be used in a lower environment. Equal values get
+	// equal fakes, in every object and on every run with the same salt, so
+	// uniqueness and matches across records survive. Records are rewritten in
+	// batches through the regular write path (new version, lookup labels);
+	// their earlier history versions are dropped as retention does. The
+	// point-in-time employee history (core.employees_history) is not rewritten.
+	AnonymizeObject(context.Context, *connect.Request[v1.AnonymizeObjectRequest]) (*connect.Response[v1.AnonymizeObjectResponse], error)
+	// SeedStandardObjects applies the built-in standard object definitions to
+	// the catalog: missing objects and fields are created, and drifted storage
+	// mappings, types and constraints are reset. Titles and descriptions are
+	// kept. A version already recorded is skipped unless force is set. The
+	// schema cache is reloaded when anything changed.
+	SeedStandardObjects(context.Context, *connect.Request[v1.SeedStandardObjectsRequest]) (*connect.Response[v1.SeedStandardObjectsResponse], error)
+}
+
+// NewAdminServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewAdminServiceHandler(svc AdminServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	adminServiceMethods := v1.File_registry_v1_admin_service_proto.Services().ByName("AdminService").Methods()
+	adminServiceMigrationStatusHandler := connect.NewUnaryHandler(
+		AdminServiceMigrationStatusProcedure,
+		svc.MigrationStatus,
+		connect.WithSchema(adminServiceMethods.ByName("MigrationStatus")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceGetMaintenanceModeHandler := connect.NewUnaryHandler(
+		AdminServiceGetMaintenanceModeProcedure,
+		svc.GetMaintenanceMode,
+		connect.WithSchema(adminServiceMethods.ByName("GetMaintenanceMode")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceSetMaintenanceModeHandler := connect.NewUnaryHandler(
+		AdminServiceSetMaintenanceModeProcedure,
+		svc.SetMaintenanceMode,
+		connect.WithSchema(adminServiceMethods.ByName("SetMaintenanceMode")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceGetSchemaCacheHandler := connect.NewUnaryHandler(
+		AdminServiceGetSchemaCacheProcedure,
+		svc.GetSchemaCache,
+		connect.WithSchema(adminServiceMethods.ByName("GetSchemaCache")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceReloadSchemaCacheHandler := connect.NewUnaryHandler(
+		AdminServiceReloadSchemaCacheProcedure,
+		svc.ReloadSchemaCache,
+		connect.WithSchema(adminServiceMethods.ByName("ReloadSchemaCache")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceEvictSchemaCacheObjectHandler := connect.NewUnaryHandler(
+		AdminServiceEvictSchemaCacheObjectProcedure,
+		svc.EvictSchemaCacheObject,
+		connect.WithSchema(adminServiceMethods.ByName("EvictSchemaCacheObject")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceListRetentionPoliciesHandler := connect.NewUnaryHandler(
+		AdminServiceListRetentionPoliciesProcedure,
+		svc.ListRetentionPolicies,
+		connect.WithSchema(adminServiceMethods.ByName("ListRetentionPolicies")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceSetRetentionPolicyHandler := connect.NewUnaryHandler(
+		AdminServiceSetRetentionPolicyProcedure,
+		svc.SetRetentionPolicy,
+		connect.WithSchema(adminServiceMethods.ByName("SetRetentionPolicy")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceDeleteRetentionPolicyHandler := connect.NewUnaryHandler(
+		AdminServiceDeleteRetentionPolicyProcedure,
+		svc.DeleteRetentionPolicy,
+		connect.WithSchema(adminServiceMethods.ByName("DeleteRetentionPolicy")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceRunRetentionHandler := connect.NewUnaryHandler(
+		AdminServiceRunRetentionProcedure,
+		svc.RunRetention,
+		connect.WithSchema(adminServiceMethods.ByName("RunRetention")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceListRetentionAuditHandler := connect.NewUnaryHandler(
+		AdminServiceListRetentionAuditProcedure,
+		svc.ListRetentionAudit,
+		connect.WithSchema(adminServiceMethods.ByName("ListRetentionAudit")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceRebuildHierarchyPathsHandler := connect.NewUnaryHandler(
+		AdminServiceRebuildHierarchyPathsProcedure,
+		svc.RebuildHierarchyPaths,
+		connect.WithSchema(adminServiceMethods.ByName("RebuildHierarchyPaths")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceSearchIndexReportHandler := connect.NewUnaryHandler(
+		AdminServiceSearchIndexReportProcedure,
+		svc.SearchIndexReport,
+		connect.WithSchema(adminServiceMethods.ByName("SearchIndexReport")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceLintSchemaHandler := connect.NewUnaryHandler(
+		AdminServiceLintSchemaProcedure,
+		svc.LintSchema,
+		connect.WithSchema(adminServiceMethods.ByName("LintSchema")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceAnonymizeObjectHandler := connect.NewUnaryHandler(
+		AdminServiceAnonymizeObjectProcedure,
+		svc.AnonymizeObject,
+		connect.WithSchema(adminServiceMethods.ByName("AnonymizeObject")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceSeedStandardObjectsHandler := connect.NewUnaryHandler(
+		AdminServiceSeedStandardObjectsProcedure,
+		svc.SeedStandardObjects,
+		connect.WithSchema(adminServiceMethods.ByName("SeedStandardObjects")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/registry.v1.AdminService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case AdminServiceMigrationStatusProcedure:
+			adminServiceMigrationStatusHandler.ServeHTTP(w, r)
+		case AdminServiceGetMaintenanceModeProcedure:
+			adminServiceGetMaintenanceModeHandler.ServeHTTP(w, r)
+		case AdminServiceSetMaintenanceModeProcedure:
+			adminServiceSetMaintenanceModeHandler.ServeHTTP(w, r)
+		case AdminServiceGetSchemaCacheProcedure:
+			adminServiceGetSchemaCacheHandler.ServeHTTP(w, r)
+		case AdminServiceReloadSchemaCacheProcedure:
+			adminServiceReloadSchemaCacheHandler.ServeHTTP(w, r)
+		case AdminServiceEvictSchemaCacheObjectProcedure:
+			adminServiceEvictSchemaCacheObjectHandler.ServeHTTP(w, r)
+		case AdminServiceListRetentionPoliciesProcedure:
+			adminServiceListRetentionPoliciesHandler.ServeHTTP(w, r)
+		case AdminServiceSetRetentionPolicyProcedure:
+			adminServiceSetRetentionPolicyHandler.ServeHTTP(w, r)
+		case AdminServiceDeleteRetentionPolicyProcedure:
+			adminServiceDeleteRetentionPolicyHandler.ServeHTTP(w, r)
+		case AdminServiceRunRetentionProcedure:
+			adminServiceRunRetentionHandler.ServeHTTP(w, r)
+		case AdminServiceListRetentionAuditProcedure:
+			adminServiceListRetentionAuditHandler.ServeHTTP(w, r)
+		case AdminServiceRebuildHierarchyPathsProcedure:
+			adminServiceRebuildHierarchyPathsHandler.ServeHTTP(w, r)
+		case AdminServiceSearchIndexReportProcedure:
+			adminServiceSearchIndexReportHandler.ServeHTTP(w, r)
+		case AdminServiceLintSchemaProcedure:
+			adminServiceLintSchemaHandler.ServeHTTP(w, r)
+		case AdminServiceAnonymizeObjectProcedure:
+			adminServiceAnonymizeObjectHandler.ServeHTTP(w, r)
+		case AdminServiceSeedStandardObjectsProcedure:
+			adminServiceSeedStandardObjectsHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedAdminServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedAdminServiceHandler struct{}
+
+func (UnimplementedAdminServiceHandler) MigrationStatus(context.Context, *connect.Request[v1.MigrationStatusRequest]) (*connect.Response[v1.MigrationStatusResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.AdminService.MigrationStatus is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) GetMaintenanceMode(context.Context, *connect.Request[v1.GetMaintenanceModeRequest]) (*connect.Response[v1.GetMaintenanceModeResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.AdminService.GetMaintenanceMode is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) SetMaintenanceMode(context.Context, *connect.Request[v1.SetMaintenanceModeRequest]) (*connect.Response[v1.SetMaintenanceModeResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.AdminService.SetMaintenanceMode is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) GetSchemaCache(context.Context, *connect.Request[v1.GetSchemaCacheRequest]) (*connect.Response[v1.GetSchemaCacheResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.AdminService.GetSchemaCache is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) ReloadSchemaCache(context.Context, *connect.Request[v1.ReloadSchemaCacheRequest]) (*connect.Response[v1.ReloadSchemaCacheResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.AdminService.ReloadSchemaCache is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) EvictSchemaCacheObject(context.Context, *connect.Request[v1.EvictSchemaCacheObjectRequest]) (*connect.Response[v1.EvictSchemaCacheObjectResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.AdminService.EvictSchemaCacheObject is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) ListRetentionPolicies(context.Context, *connect.Request[v1.ListRetentionPoliciesRequest]) (*connect.Response[v1.ListRetentionPoliciesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.AdminService.ListRetentionPolicies is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) SetRetentionPolicy(context.Context, *connect.Request[v1.SetRetentionPolicyRequest]) (*connect.Response[v1.SetRetentionPolicyResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.AdminService.SetRetentionPolicy is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) DeleteRetentionPolicy(context.Context, *connect.Request[v1.DeleteRetentionPolicyRequest]) (*connect.Response[v1.DeleteRetentionPolicyResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.AdminService.DeleteRetentionPolicy is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) RunRetention(context.Context, *connect.Request[v1.RunRetentionRequest]) (*connect.Response[v1.RunRetentionResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.AdminService.RunRetention is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) ListRetentionAudit(context.Context, *connect.Request[v1.ListRetentionAuditRequest]) (*connect.Response[v1.ListRetentionAuditResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.AdminService.ListRetentionAudit is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) RebuildHierarchyPaths(context.Context, *connect.Request[v1.RebuildHierarchyPathsRequest]) (*connect.Response[v1.RebuildHierarchyPathsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.AdminService.RebuildHierarchyPaths is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) SearchIndexReport(context.Context, *connect.Request[v1.SearchIndexReportRequest]) (*connect.Response[v1.SearchIndexReportResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.AdminService.SearchIndexReport is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) LintSchema(context.Context, *connect.Request[v1.LintSchemaRequest]) (*connect.Response[v1.LintSchemaResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.AdminService.LintSchema is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) AnonymizeObject(context.Context, *connect.Request[v1.AnonymizeObjectRequest]) (*connect.Response[v1.AnonymizeObjectResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.AdminService.AnonymizeObject is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) SeedStandardObjects(context.Context, *connect.Request[v1.SeedStandardObjectsRequest]) (*connect.Response[v1.SeedStandardObjectsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.AdminService.SeedStandardObjects is not implemented"))
+}
@@ -48,6 +48,15 @@ const (
 	// MetadataServiceDeleteObjectProcedure is the fully-qualified name of the MetadataService's
 	// DeleteObject RPC.
 	MetadataServiceDeleteObjectProcedure = "/registry.v1.MetadataService/DeleteObject"
+	// MetadataServicePreviewDeleteProcedure is the fully-qualified name of the MetadataService's
+	// PreviewDelete RPC.
+	MetadataServicePreviewDeleteProcedure = "/registry.v1.MetadataService/PreviewDelete"
+	// MetadataServiceRenameObjectProcedure is the fully-qualified name of the MetadataService's
+	// RenameObject RPC.
+	MetadataServiceRenameObjectProcedure = "/registry.v1.MetadataService/RenameObject"
+	// MetadataServiceGetObjectUsageProcedure is the fully-qualified name of the MetadataService's
+	// GetObjectUsage RPC.
+	MetadataServiceGetObjectUsageProcedure = "/registry.v1.MetadataService/GetObjectUsage"
 	// MetadataServiceListFieldsProcedure is the fully-qualified name of the MetadataService's
 	// ListFields RPC.
 	MetadataServiceListFieldsProcedure = "/registry.v1.MetadataService/ListFields"
@@ -63,6 +72,12 @@ const (
 	// MetadataServiceDeleteFieldProcedure is the fully-qualified name of the MetadataService's
 	// DeleteField RPC.
 	MetadataServiceDeleteFieldProcedure = "/registry.v1.MetadataService/DeleteField"
+	// MetadataServiceRenameFieldProcedure is the fully-qualified name of the MetadataService's
+	// RenameField RPC.
+	MetadataServiceRenameFieldProcedure = "/registry.v1.MetadataService/RenameField"
+	// MetadataServiceGenerateClientProcedure is the fully-qualified name of the MetadataService's
+	// GenerateClient RPC.
+	MetadataServiceGenerateClientProcedure = "/registry.v1.MetadataService/GenerateClient"
 )
 
 // MetadataServiceClient is a client for the registry.v1.MetadataService service.
@@ -72,11 +87,19 @@ type MetadataServiceClient interface {
 	CreateObject(context.Context, *connect.Request[v1.CreateObjectRequest]) (*connect.Response[v1.CreateObjectResponse], error)
 	UpdateObject(context.Context, *connect.Request[v1.UpdateObjectRequest]) (*connect.Response[v1.UpdateObjectResponse], error)
 	DeleteObject(context.Context, *connect.Request[v1.DeleteObjectRequest]) (*connect.Response[v1.DeleteObjectResponse], error)
+	PreviewDelete(context.Context, *connect.Request[v1.PreviewDeleteRequest]) (*connect.Response[v1.PreviewDeleteResponse], error)
+	RenameObject(context.Context, *connect.Request[v1.RenameObjectRequest]) (*connect.Response[v1.RenameObjectResponse], error)
+	GetObjectUsage(context.Context, *connect.Request[v1.GetObjectUsageRequest]) (*connect.Response[v1.GetObjectUsageResponse], error)
 	ListFields(context.Context, *connect.Request[v1.ListFieldsRequest]) (*connect.Response[v1.ListFieldsResponse], error)
 	GetField(context.Context, *connect.Request[v1.GetFieldRequest]) (*connect.Response[v1.GetFieldResponse], error)
 	CreateField(context.Context, *connect.Request[v1.CreateFieldRequest]) (*connect.Response[v1.CreateFieldResponse], error)
 	UpdateField(context.Context, *connect.Request[v1.UpdateFieldRequest]) (*connect.Response[v1.UpdateFieldResponse], error)
 	DeleteField(context.Context, *connect.Request[v1.DeleteFieldRequest]) (*connect.Response[v1.DeleteFieldResponse], error)
+	RenameField(context.Context, *connect.Request[v1.RenameFieldRequest]) (*connect.Response[v1.RenameFieldResponse], error)
+	// Generates a typed client for the registered objects: per-object record
+	// types and list/get/create/update/upsert/delete methods whose options
+	// only accept the object's fields.
+	GenerateClient(context.Context, *connect.Request[v1.GenerateClientRequest]) (*connect.Response[v1.GenerateClientResponse], error)
 }
 
 // NewMetadataServiceClient constructs a client for the registry.v1.MetadataService service. By
@@ -120,6 +143,24 @@ func NewMetadataServiceClient(httpClient connect.HTTPClient, baseURL string, opt
 			connect.WithSchema(metadataServiceMethods.ByName("DeleteObject")),
 			connect.WithClientOptions(opts...),
 		),
+		previewDelete: connect.NewClient[v1.PreviewDeleteRequest, v1.PreviewDeleteResponse](
+			httpClient,
+			baseURL+MetadataServicePreviewDeleteProcedure,
+			connect.WithSchema(metadataServiceMethods.ByName("PreviewDelete")),
+			connect.WithClientOptions(opts...),
+		),
+		renameObject: connect.NewClient[v1.RenameObjectRequest, v1.RenameObjectResponse](
+			httpClient,
+			baseURL+MetadataServiceRenameObjectProcedure,
+			connect.WithSchema(metadataServiceMethods.ByName("RenameObject")),
+			connect.WithClientOptions(opts...),
+		),
+		getObjectUsage: connect.NewClient[v1.GetObjectUsageRequest, v1.GetObjectUsageResponse](
+			httpClient,
+			baseURL+MetadataServiceGetObjectUsageProcedure,
+			connect.WithSchema(metadataServiceMethods.ByName("GetObjectUsage")),
+			connect.WithClientOptions(opts...),
+		),
 		listFields: connect.NewClient[v1.ListFieldsRequest, v1.ListFieldsResponse](
 			httpClient,
 			baseURL+MetadataServiceListFieldsProcedure,
@@ -150,21 +191,38 @@ func NewMetadataServiceClient(httpClient connect.HTTPClient, baseURL string, opt
 			connect.WithSchema(metadataServiceMethods.ByName("DeleteField")),
 			connect.WithClientOptions(opts...),
 		),
+		renameField: connect.NewClient[v1.RenameFieldRequest, v1.RenameFieldResponse](
+			httpClient,
+			baseURL+MetadataServiceRenameFieldProcedure,
+			connect.WithSchema(metadataServiceMethods.ByName("RenameField")),
+			connect.WithClientOptions(opts...),
+		),
+		generateClient: connect.NewClient[v1.GenerateClientRequest, v1.GenerateClientResponse](
+			httpClient,
+			baseURL+MetadataServiceGenerateClientProcedure,
+			connect.WithSchema(metadataServiceMethods.ByName("GenerateClient")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // metadataServiceClient implements MetadataServiceClient.
 type metadataServiceClient struct {
-	listObjects  *connect.Client[v1.ListObjectsRequest, v1.ListObjectsResponse]
-	getObject    *connect.Client[v1.GetObjectRequest, v1.GetObjectResponse]
-	createObject *connect.Client[v1.CreateObjectRequest, v1.CreateObjectResponse]
-	updateObject *connect.Client[v1.UpdateObjectRequest, v1.UpdateObjectResponse]
-	deleteObject *connect.Client[v1.DeleteObjectRequest, v1.DeleteObjectResponse]
-	listFields   *connect.Client[v1.ListFieldsRequest, v1.ListFieldsResponse]
-	getField     *connect.Client[v1.GetFieldRequest, v1.GetFieldResponse]
-	createField  *connect.Client[v1.CreateFieldRequest, v1.CreateFieldResponse]
-	updateField  *connect.Client[v1.UpdateFieldRequest, v1.UpdateFieldResponse]
-	deleteField  *connect.Client[v1.DeleteFieldRequest, v1.DeleteFieldResponse]
+	listObjects    *connect.Client[v1.ListObjectsRequest, v1.ListObjectsResponse]
+	getObject      *connect.Client[v1.GetObjectRequest, v1.GetObjectResponse]
+	createObject   *connect.Client[v1.CreateObjectRequest, v1.CreateObjectResponse]
+	updateObject   *connect.Client[v1.UpdateObjectRequest, v1.UpdateObjectResponse]
+	deleteObject   *connect.Client[v1.DeleteObjectRequest, v1.DeleteObjectResponse]
+	previewDelete  *connect.Client[v1.PreviewDeleteRequest, v1.PreviewDeleteResponse]
+	renameObject   *connect.Client[v1.RenameObjectRequest, v1.RenameObjectResponse]
+	getObjectUsage *connect.Client[v1.GetObjectUsageRequest, v1.GetObjectUsageResponse]
+	listFields     *connect.Client[v1.ListFieldsRequest, v1.ListFieldsResponse]
+	getField       *connect.Client[v1.GetFieldRequest, v1.GetFieldResponse]
+	createField    *connect.Client[v1.CreateFieldRequest, v1.CreateFieldResponse]
+	updateField    *connect.Client[v1.UpdateFieldRequest, v1.UpdateFieldResponse]
+	deleteField    *connect.Client[v1.DeleteFieldRequest, v1.DeleteFieldResponse]
+	renameField    *connect.Client[v1.RenameFieldRequest, v1.RenameFieldResponse]
+	generateClient *connect.Client[v1.GenerateClientRequest, v1.GenerateClientResponse]
 }
 
 // ListObjects calls registry.v1.MetadataService.ListObjects.
@@ -192,6 +250,21 @@ func (c *metadataServiceClient) DeleteObject(ctx context.Context, req *connect.R
 	return c.deleteObject.CallUnary(ctx, req)
 }
 
+// PreviewDelete calls registry.v1.MetadataService.PreviewDelete.
+func (c *metadataServiceClient) PreviewDelete(ctx context.Context, req *connect.Request[v1.PreviewDeleteRequest]) (*connect.Response[v1.PreviewDeleteResponse], error) {
+	return c.previewDelete.CallUnary(ctx, req)
+}
+
+// RenameObject calls registry.v1.MetadataService.RenameObject.
+func (c *metadataServiceClient) RenameObject(ctx context.Context, req *connect.Request[v1.RenameObjectRequest]) (*connect.Response[v1.RenameObjectResponse], error) {
+	return c.renameObject.CallUnary(ctx, req)
+}
+
+// GetObjectUsage calls registry.v1.MetadataService.GetObjectUsage.
+func (c *metadataServiceClient) GetObjectUsage(ctx context.Context, req *connect.Request[v1.GetObjectUsageRequest]) (*connect.Response[v1.GetObjectUsageResponse], error) {
+	return c.getObjectUsage.CallUnary(ctx, req)
+}
+
 // ListFields calls registry.v1.MetadataService.ListFields.
 func (c *metadataServiceClient) ListFields(ctx context.Context, req *connect.Request[v1.ListFieldsRequest]) (*connect.Response[v1.ListFieldsResponse], error) {
 	return c.listFields.CallUnary(ctx, req)
@@ -217,6 +290,16 @@ func (c *metadataServiceClient) DeleteField(ctx context.Context, req *connect.Re
 	return c.deleteField.CallUnary(ctx, req)
 }
 
+// RenameField calls registry.v1.MetadataService.RenameField.
+func (c *metadataServiceClient) RenameField(ctx context.Context, req *connect.Request[v1.RenameFieldRequest]) (*connect.Response[v1.RenameFieldResponse], error) {
+	return c.renameField.CallUnary(ctx, req)
+}
+
+// GenerateClient calls registry.v1.MetadataService.GenerateClient.
+func (c *metadataServiceClient) GenerateClient(ctx context.Context, req *connect.Request[v1.GenerateClientRequest]) (*connect.Response[v1.GenerateClientResponse], error) {
+	return c.generateClient.CallUnary(ctx, req)
+}
+
 // MetadataServiceHandler is an implementation of the registry.v1.MetadataService service.
 type MetadataServiceHandler interface {
 	ListObjects(context.Context, *connect.Request[v1.ListObjectsRequest]) (*connect.Response[v1.ListObjectsResponse], error)
@@ -224,11 +307,19 @@ type MetadataServiceHandler interface {
 	CreateObject(context.Context, *connect.Request[v1.CreateObjectRequest]) (*connect.Response[v1.CreateObjectResponse], error)
 	UpdateObject(context.Context, *connect.Request[v1.UpdateObjectRequest]) (*connect.Response[v1.UpdateObjectResponse], error)
 	DeleteObject(context.Context, *connect.Request[v1.DeleteObjectRequest]) (*connect.Response[v1.DeleteObjectResponse], error)
+	PreviewDelete(context.Context, *connect.Request[v1.PreviewDeleteRequest]) (*connect.Response[v1.PreviewDeleteResponse], error)
+	RenameObject(context.Context, *connect.Request[v1.RenameObjectRequest]) (*connect.Response[v1.RenameObjectResponse], error)
+	GetObjectUsage(context.Context, *connect.Request[v1.GetObjectUsageRequest]) (*connect.Response[v1.GetObjectUsageResponse], error)
 	ListFields(context.Context, *connect.Request[v1.ListFieldsRequest]) (*connect.Response[v1.ListFieldsResponse], error)
 	GetField(context.Context, *connect.Request[v1.GetFieldRequest]) (*connect.Response[v1.GetFieldResponse], error)
 	CreateField(context.Context, *connect.Request[v1.CreateFieldRequest]) (*connect.Response[v1.CreateFieldResponse], error)
 	UpdateField(context.Context, *connect.Request[v1.UpdateFieldRequest]) (*connect.Response[v1.UpdateFieldResponse], error)
 	DeleteField(context.Context, *connect.Request[v1.DeleteFieldRequest]) (*connect.Response[v1.DeleteFieldResponse], error)
+	RenameField(context.Context, *connect.Request[v1.RenameFieldRequest]) (*connect.Response[v1.RenameFieldResponse], error)
+	// Generates a typed client for the registered objects: per-object record
+	// types and list/get/create/update/upsert/delete methods whose options
+	// only accept the object's fields.
+	GenerateClient(context.Context, *connect.Request[v1.GenerateClientRequest]) (*connect.Response[v1.GenerateClientResponse], error)
 }
 
 // NewMetadataServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -268,6 +359,24 @@ func NewMetadataServiceHandler(svc MetadataServiceHandler, opts ...connect.Handl
 		connect.WithSchema(metadataServiceMethods.ByName("DeleteObject")),
 		connect.WithHandlerOptions(opts...),
 	)
+	metadataServicePreviewDeleteHandler := connect.NewUnaryHandler(
+		MetadataServicePreviewDeleteProcedure,
+		svc.PreviewDelete,
+		connect.WithSchema(metadataServiceMethods.ByName("PreviewDelete")),
+		connect.WithHandlerOptions(opts...),
+	)
+	metadataServiceRenameObjectHandler := connect.NewUnaryHandler(
+		MetadataServiceRenameObjectProcedure,
+		svc.RenameObject,
+		connect.WithSchema(metadataServiceMethods.ByName("RenameObject")),
+		connect.WithHandlerOptions(opts...),
+	)
+	metadataServiceGetObjectUsageHandler := connect.NewUnaryHandler(
+		MetadataServiceGetObjectUsageProcedure,
+		svc.GetObjectUsage,
+		connect.WithSchema(metadataServiceMethods.ByName("GetObjectUsage")),
+		connect.WithHandlerOptions(opts...),
+	)
 	metadataServiceListFieldsHandler := connect.NewUnaryHandler(
 		MetadataServiceListFieldsProcedure,
 		svc.ListFields,
@@ -298,6 +407,18 @@ func NewMetadataServiceHandler(svc MetadataServiceHandler, opts ...connect.Handl
 		connect.WithSchema(metadataServiceMethods.ByName("DeleteField")),
 		connect.WithHandlerOptions(opts...),
 	)
+	metadataServiceRenameFieldHandler := connect.NewUnaryHandler(
+		MetadataServiceRenameFieldProcedure,
+		svc.RenameField,
+		connect.WithSchema(metadataServiceMethods.ByName("RenameField")),
+		connect.WithHandlerOptions(opts...),
+	)
+	metadataServiceGenerateClientHandler := connect.NewUnaryHandler(
+		MetadataServiceGenerateClientProcedure,
+		svc.GenerateClient,
+		connect.WithSchema(metadataServiceMethods.ByName("GenerateClient")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/registry.v1.MetadataService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case MetadataServiceListObjectsProcedure:
@@ -310,6 +431,12 @@ func NewMetadataServiceHandler(svc MetadataServiceHandler, opts ...connect.Handl
 			metadataServiceUpdateObjectHandler.ServeHTTP(w, r)
 		case MetadataServiceDeleteObjectProcedure:
 			metadataServiceDeleteObjectHandler.ServeHTTP(w, r)
+		case MetadataServicePreviewDeleteProcedure:
+			metadataServicePreviewDeleteHandler.ServeHTTP(w, r)
+		case MetadataServiceRenameObjectProcedure:
+			metadataServiceRenameObjectHandler.ServeHTTP(w, r)
+		case MetadataServiceGetObjectUsageProcedure:
+			metadataServiceGetObjectUsageHandler.ServeHTTP(w, r)
 		case MetadataServiceListFieldsProcedure:
 			metadataServiceListFieldsHandler.ServeHTTP(w, r)
 		case MetadataServiceGetFieldProcedure:
@@ -320,6 +447,10 @@ func NewMetadataServiceHandler(svc MetadataServiceHandler, opts ...connect.Handl
 			metadataServiceUpdateFieldHandler.ServeHTTP(w, r)
 		case MetadataServiceDeleteFieldProcedure:
 			metadataServiceDeleteFieldHandler.ServeHTTP(w, r)
+		case MetadataServiceRenameFieldProcedure:
+			metadataServiceRenameFieldHandler.ServeHTTP(w, r)
+		case MetadataServiceGenerateClientProcedure:
+			metadataServiceGenerateClientHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -349,6 +480,18 @@ func (UnimplementedMetadataServiceHandler) DeleteObject(context.Context, *connec
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.MetadataService.DeleteObject is not implemented"))
 }
 
+func (UnimplementedMetadataServiceHandler) PreviewDelete(context.Context, *connect.Request[v1.PreviewDeleteRequest]) (*connect.Response[v1.PreviewDeleteResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.MetadataService.PreviewDelete is not implemented"))
+}
+
+func (UnimplementedMetadataServiceHandler) RenameObject(context.Context, *connect.Request[v1.RenameObjectRequest]) (*connect.Response[v1.RenameObjectResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.MetadataService.RenameObject is not implemented"))
+}
+
+func (UnimplementedMetadataServiceHandler) GetObjectUsage(context.Context, *connect.Request[v1.GetObjectUsageRequest]) (*connect.Response[v1.GetObjectUsageResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.MetadataService.GetObjectUsage is not implemented"))
+}
+
 func (UnimplementedMetadataServiceHandler) ListFields(context.Context, *connect.Request[v1.ListFieldsRequest]) (*connect.Response[v1.ListFieldsResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.MetadataService.ListFields is not implemented"))
 }
@@ -368,3 +511,11 @@ func (UnimplementedMetadataServiceHandler) UpdateField(context.Context, *connect
 func (UnimplementedMetadataServiceHandler) DeleteField(context.Context, *connect.Request[v1.DeleteFieldRequest]) (*connect.Response[v1.DeleteFieldResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.MetadataService.DeleteField is not implemented"))
 }
+
+func (UnimplementedMetadataServiceHandler) RenameField(context.Context, *connect.Request[v1.RenameFieldRequest]) (*connect.Response[v1.RenameFieldResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.MetadataService.RenameField is not implemented"))
+}
+
+func (UnimplementedMetadataServiceHandler) GenerateClient(context.Context, *connect.Request[v1.GenerateClientRequest]) (*connect.Response[v1.GenerateClientResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.MetadataService.GenerateClient is not implemented"))
+}
@@ -35,6 +35,18 @@ const (
 const (
 	// OrgServiceQueryProcedure is the fully-qualified name of the OrgService's Query RPC.
 	OrgServiceQueryProcedure = "/registry.v1.OrgService/Query"
+	// OrgServiceToFiltersProcedure is the fully-qualified name of the OrgService's ToFilters RPC.
+	OrgServiceToFiltersProcedure = "/registry.v1.OrgService/ToFilters"
+	// OrgServiceExplainProcedure is the fully-qualified name of the OrgService's Explain RPC.
+	OrgServiceExplainProcedure = "/registry.v1.OrgService/Explain"
+	// OrgServiceBatchEvaluateProcedure is the fully-qualified name of the OrgService's BatchEvaluate
+	// RPC.
+	OrgServiceBatchEvaluateProcedure = "/registry.v1.OrgService/BatchEvaluate"
+	// OrgServiceDiffProcedure is the fully-qualified name of the OrgService's Diff RPC.
+	OrgServiceDiffProcedure = "/registry.v1.OrgService/Diff"
+	// OrgServiceExecuteStreamProcedure is the fully-qualified name of the OrgService's ExecuteStream
+	// RPC.
+	OrgServiceExecuteStreamProcedure = "/registry.v1.OrgService/ExecuteStream"
 )
 
 // OrgServiceClient is a client for the registry.v1.OrgService service.
@@ -42,6 +54,26 @@ type OrgServiceClient interface {
 	// Query parses an HRQL expression and executes it against the employee hierarchy.
 	// Examples: "reports(self, 1)", "employees | where(.employment_type == \"CONTRACTOR\") | count"
 	Query(context.Context, *connect.Request[v1.QueryRequest]) (*connect.Response[v1.QueryResponse], error)
+	// ToFilters converts a where-only HRQL expression (e.g. "employees | where(.employment_type == \"FULL_TIME\")")
+	// into the equivalent REST list filters, or explains why it has no REST equivalent.
+	ToFilters(context.Context, *connect.Request[v1.ToFiltersRequest]) (*connect.Response[v1.ToFiltersResponse], error)
+	// Explain parses an HRQL expression without running or compiling it and
+	// returns its syntax tree, so linters, editors and query builders need not
+	// re-implement the parser. Syntax errors fail with INVALID_ARGUMENT.
+	Explain(context.Context, *connect.Request[v1.ExplainRequest]) (*connect.Response[v1.ExplainResponse], error)
+	// BatchEvaluate evaluates many boolean checks (e.g. "reports_to(self, \"<uuid>\")")
+	// in a single SQL statement, returning one result per item in request order.
+	BatchEvaluate(context.Context, *connect.Request[v1.BatchEvaluateRequest]) (*connect.Response[v1.BatchEvaluateResponse], error)
+	// Diff compares the reporting tree at two points in time, listing hires,
+	// departures, manager changes and department moves between them.
+	Diff(context.Context, *connect.Request[v1.DiffRequest]) (*connect.Response[v1.DiffResponse], error)
+	// ExecuteStream runs an HRQL list or ids query and streams all of its
+	// records in batches as the database returns them, rather than a page at a
+	// time (e.g. to materialize an audience). It stops after limit records, at
+	// most the server's stream row limit, marking the last message truncated
+	// when more matched. Other results fail with INVALID_ARGUMENT. Connect and
+	// gRPC only: the stream has no REST mapping.
+	ExecuteStream(context.Context, *connect.Request[v1.ExecuteStreamRequest]) (*connect.ServerStreamForClient[v1.ExecuteStreamResponse], error)
 }
 
 // NewOrgServiceClient constructs a client for the registry.v1.OrgService service. By default, it
@@ -61,12 +93,47 @@ func NewOrgServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...
 			connect.WithSchema(orgServiceMethods.ByName("Query")),
 			connect.WithClientOptions(opts...),
 		),
+		toFilters: connect.NewClient[v1.ToFiltersRequest, v1.ToFiltersResponse](
+			httpClient,
+			baseURL+OrgServiceToFiltersProcedure,
+			connect.WithSchema(orgServiceMethods.ByName("ToFilters")),
+			connect.WithClientOptions(opts...),
+		),
+		explain: connect.NewClient[v1.ExplainRequest, v1.ExplainResponse](
+			httpClient,
+			baseURL+OrgServiceExplainProcedure,
+			connect.WithSchema(orgServiceMethods.ByName("Explain")),
+			connect.WithClientOptions(opts...),
+		),
+		batchEvaluate: connect.NewClient[v1.BatchEvaluateRequest, v1.BatchEvaluateResponse](
+			httpClient,
+			baseURL+OrgServiceBatchEvaluateProcedure,
+			connect.WithSchema(orgServiceMethods.ByName("BatchEvaluate")),
+			connect.WithClientOptions(opts...),
+		),
+		diff: connect.NewClient[v1.DiffRequest, v1.DiffResponse](
+			httpClient,
+			baseURL+OrgServiceDiffProcedure,
+			connect.WithSchema(orgServiceMethods.ByName("Diff")),
+			connect.WithClientOptions(opts...),
+		),
+		executeStream: connect.NewClient[v1.ExecuteStreamRequest, v1.ExecuteStreamResponse](
+			httpClient,
+			baseURL+OrgServiceExecuteStreamProcedure,
+			connect.WithSchema(orgServiceMethods.ByName("ExecuteStream")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // orgServiceClient implements OrgServiceClient.
 type orgServiceClient struct {
-	query *connect.Client[v1.QueryRequest, v1.QueryResponse]
+	query         *connect.Client[v1.QueryRequest, v1.QueryResponse]
+	toFilters     *connect.Client[v1.ToFiltersRequest, v1.ToFiltersResponse]
+	explain       *connect.Client[v1.ExplainRequest, v1.ExplainResponse]
+	batchEvaluate *connect.Client[v1.BatchEvaluateRequest, v1.BatchEvaluateResponse]
+	diff          *connect.Client[v1.DiffRequest, v1.DiffResponse]
+	executeStream *connect.Client[v1.ExecuteStreamRequest, v1.ExecuteStreamResponse]
 }
 
 // Query calls registry.v1.OrgService.Query.
@@ -74,11 +141,56 @@ func (c *orgServiceClient) Query(ctx context.Context, req *connect.Request[v1.Qu
 	return c.query.CallUnary(ctx, req)
 }
 
+// ToFilters calls registry.v1.OrgService.ToFilters.
+func (c *orgServiceClient) ToFilters(ctx context.Context, req *connect.Request[v1.ToFiltersRequest]) (*connect.Response[v1.ToFiltersResponse], error) {
+	return c.toFilters.CallUnary(ctx, req)
+}
+
+// Explain calls registry.v1.OrgService.Explain.
+func (c *orgServiceClient) Explain(ctx context.Context, req *connect.Request[v1.ExplainRequest]) (*connect.Response[v1.ExplainResponse], error) {
+	return c.explain.CallUnary(ctx, req)
+}
+
+// BatchEvaluate calls registry.v1.OrgService.BatchEvaluate.
+func (c *orgServiceClient) BatchEvaluate(ctx context.Context, req *connect.Request[v1.BatchEvaluateRequest]) (*connect.Response[v1.BatchEvaluateResponse], error) {
+	return c.batchEvaluate.CallUnary(ctx, req)
+}
+
+// Diff calls registry.v1.OrgService.Diff.
+func (c *orgServiceClient) Diff(ctx context.Context, req *connect.Request[v1.DiffRequest]) (*connect.Response[v1.DiffResponse], error) {
+	return c.diff.CallUnary(ctx, req)
+}
+
+// ExecuteStream calls registry.v1.OrgService.ExecuteStream.
+func (c *orgServiceClient) ExecuteStream(ctx context.Context, req *connect.Request[v1.ExecuteStreamRequest]) (*connect.ServerStreamForClient[v1.ExecuteStreamResponse], error) {
+	return c.executeStream.CallServerStream(ctx, req)
+}
+
 // OrgServiceHandler is an implementation of the registry.v1.OrgService service.
 type OrgServiceHandler interface {
 	// Query parses an HRQL expression and executes it against the employee hierarchy.
 	// Examples: "reports(self, 1)", "employees | where(.employment_type == \"CONTRACTOR\") | count"
 	Query(context.Context, *connect.Request[v1.QueryRequest]) (*connect.Response[v1.QueryResponse], error)
+	// ToFilters converts a where-only HRQL expression (e.g. "employees | where(.employment_type == \"FULL_TIME\")")
+	// into the equivalent REST list filters, or explains why it has no REST equivalent.
+	ToFilters(context.Context, *connect.Request[v1.ToFiltersRequest]) (*connect.Response[v1.ToFiltersResponse], error)
+	// Explain parses an HRQL expression without running or compiling it and
+	// returns its syntax tree, so linters, editors and query builders need not
+	// re-implement the parser. Syntax errors fail with INVALID_ARGUMENT.
+	Explain(context.Context, *connect.Request[v1.ExplainRequest]) (*connect.Response[v1.ExplainResponse], error)
+	// BatchEvaluate evaluates many boolean checks (e.g. "reports_to(self, \"<uuid>\")")
+	// in a single SQL statement, returning one result per item in request order.
+	BatchEvaluate(context.Context, *connect.Request[v1.BatchEvaluateRequest]) (*connect.Response[v1.BatchEvaluateResponse], error)
+	// Diff compares the reporting tree at two points in time, listing hires,
+	// departures, manager changes and department moves between them.
+	Diff(context.Context, *connect.Request[v1.DiffRequest]) (*connect.Response[v1.DiffResponse], error)
+	// ExecuteStream runs an HRQL list or ids query and streams all of its
+	// records in batches as the database returns them, rather than a page at a
+	// time (e.g. to materialize an audience). It stops after limit records, at
+	// most the server's stream row limit, marking the last message truncated
+	// when more matched. Other results fail with INVALID_ARGUMENT. Connect and
+	// gRPC only: the stream has no REST mapping.
+	ExecuteStream(context.Context, *connect.Request[v1.ExecuteStreamRequest], *connect.ServerStream[v1.ExecuteStreamResponse]) error
 }
 
 // NewOrgServiceHandler builds an HTTP handler from the service implementation. It returns the path
@@ -94,10 +206,50 @@ func NewOrgServiceHandler(svc OrgServiceHandler, opts ...connect.HandlerOption)
 		connect.WithSchema(orgServiceMethods.ByName("Query")),
 		connect.WithHandlerOptions(opts...),
 	)
+	orgServiceToFiltersHandler := connect.NewUnaryHandler(
+		OrgServiceToFiltersProcedure,
+		svc.ToFilters,
+		connect.WithSchema(orgServiceMethods.ByName("ToFilters")),
+		connect.WithHandlerOptions(opts...),
+	)
+	orgServiceExplainHandler := connect.NewUnaryHandler(
+		OrgServiceExplainProcedure,
+		svc.Explain,
+		connect.WithSchema(orgServiceMethods.ByName("Explain")),
+		connect.WithHandlerOptions(opts...),
+	)
+	orgServiceBatchEvaluateHandler := connect.NewUnaryHandler(
+		OrgServiceBatchEvaluateProcedure,
+		svc.BatchEvaluate,
+		connect.WithSchema(orgServiceMethods.ByName("BatchEvaluate")),
+		connect.WithHandlerOptions(opts...),
+	)
+	orgServiceDiffHandler := connect.NewUnaryHandler(
+		OrgServiceDiffProcedure,
+		svc.Diff,
+		connect.WithSchema(orgServiceMethods.ByName("Diff")),
+		connect.WithHandlerOptions(opts...),
+	)
+	orgServiceExecuteStreamHandler := connect.NewServerStreamHandler(
+		OrgServiceExecuteStreamProcedure,
+		svc.ExecuteStream,
+		connect.WithSchema(orgServiceMethods.ByName("ExecuteStream")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/registry.v1.OrgService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case OrgServiceQueryProcedure:
 			orgServiceQueryHandler.ServeHTTP(w, r)
+		case OrgServiceToFiltersProcedure:
+			orgServiceToFiltersHandler.ServeHTTP(w, r)
+		case OrgServiceExplainProcedure:
+			orgServiceExplainHandler.ServeHTTP(w, r)
+		case OrgServiceBatchEvaluateProcedure:
+			orgServiceBatchEvaluateHandler.ServeHTTP(w, r)
+		case OrgServiceDiffProcedure:
+			orgServiceDiffHandler.ServeHTTP(w, r)
+		case OrgServiceExecuteStreamProcedure:
+			orgServiceExecuteStreamHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -110,3 +262,23 @@ type UnimplementedOrgServiceHandler struct{}
 func (UnimplementedOrgServiceHandler) Query(context.Context, *connect.Request[v1.QueryRequest]) (*connect.Response[v1.QueryResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.OrgService.Query is not implemented"))
 }
+
+func (UnimplementedOrgServiceHandler) ToFilters(context.Context, *connect.Request[v1.ToFiltersRequest]) (*connect.Response[v1.ToFiltersResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.OrgService.ToFilters is not implemented"))
+}
+
+func (UnimplementedOrgServiceHandler) Explain(context.Context, *connect.Request[v1.ExplainRequest]) (*connect.Response[v1.ExplainResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.OrgService.Explain is not implemented"))
+}
+
+func (UnimplementedOrgServiceHandler) BatchEvaluate(context.Context, *connect.Request[v1.BatchEvaluateRequest]) (*connect.Response[v1.BatchEvaluateResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.OrgService.BatchEvaluate is not implemented"))
+}
+
+func (UnimplementedOrgServiceHandler) Diff(context.Context, *connect.Request[v1.DiffRequest]) (*connect.Response[v1.DiffResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.OrgService.Diff is not implemented"))
+}
+
+func (UnimplementedOrgServiceHandler) ExecuteStream(context.Context, *connect.Request[v1.ExecuteStreamRequest], *connect.ServerStream[v1.ExecuteStreamResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.OrgService.ExecuteStream is not implemented"))
+}
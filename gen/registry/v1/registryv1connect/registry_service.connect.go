@@ -35,16 +35,68 @@ const (
 const (
 	// RegistryServiceListProcedure is the fully-qualified name of the RegistryService's List RPC.
 	RegistryServiceListProcedure = "/registry.v1.RegistryService/List"
+	// RegistryServiceSplitListProcedure is the fully-qualified name of the RegistryService's SplitList
+	// RPC.
+	RegistryServiceSplitListProcedure = "/registry.v1.RegistryService/SplitList"
+	// RegistryServiceTypeaheadProcedure is the fully-qualified name of the RegistryService's Typeahead
+	// RPC.
+	RegistryServiceTypeaheadProcedure = "/registry.v1.RegistryService/Typeahead"
+	// RegistryServiceLookupProcedure is the fully-qualified name of the RegistryService's Lookup RPC.
+	RegistryServiceLookupProcedure = "/registry.v1.RegistryService/Lookup"
 	// RegistryServiceGetProcedure is the fully-qualified name of the RegistryService's Get RPC.
 	RegistryServiceGetProcedure = "/registry.v1.RegistryService/Get"
+	// RegistryServiceCreateProcedure is the fully-qualified name of the RegistryService's Create RPC.
+	RegistryServiceCreateProcedure = "/registry.v1.RegistryService/Create"
+	// RegistryServiceUpdateProcedure is the fully-qualified name of the RegistryService's Update RPC.
+	RegistryServiceUpdateProcedure = "/registry.v1.RegistryService/Update"
+	// RegistryServiceUpdateWhereProcedure is the fully-qualified name of the RegistryService's
+	// UpdateWhere RPC.
+	RegistryServiceUpdateWhereProcedure = "/registry.v1.RegistryService/UpdateWhere"
+	// RegistryServiceUpsertProcedure is the fully-qualified name of the RegistryService's Upsert RPC.
+	RegistryServiceUpsertProcedure = "/registry.v1.RegistryService/Upsert"
+	// RegistryServiceDeleteProcedure is the fully-qualified name of the RegistryService's Delete RPC.
+	RegistryServiceDeleteProcedure = "/registry.v1.RegistryService/Delete"
+	// RegistryServiceGetRecordHistoryProcedure is the fully-qualified name of the RegistryService's
+	// GetRecordHistory RPC.
+	RegistryServiceGetRecordHistoryProcedure = "/registry.v1.RegistryService/GetRecordHistory"
+	// RegistryServiceRevertRecordProcedure is the fully-qualified name of the RegistryService's
+	// RevertRecord RPC.
+	RegistryServiceRevertRecordProcedure = "/registry.v1.RegistryService/RevertRecord"
 )
 
 // RegistryServiceClient is a client for the registry.v1.RegistryService service.
 type RegistryServiceClient interface {
 	// List returns a paginated list of records for the given object.
 	List(context.Context, *connect.Request[v1.ListRequest]) (*connect.Response[v1.ListResponse], error)
+	// SplitList divides the records of an object matching filters into id
+	// ranges of about equal size and returns a List cursor for each, so batch
+	// consumers can read the partitions in parallel workers.
+	SplitList(context.Context, *connect.Request[v1.SplitListRequest]) (*connect.Response[v1.SplitListResponse], error)
+	// Typeahead prefix-searches records by display name for pickers.
+	Typeahead(context.Context, *connect.Request[v1.TypeaheadRequest]) (*connect.Response[v1.TypeaheadResponse], error)
+	// Lookup searches the records a LOOKUP field can reference, for dropdowns.
+	Lookup(context.Context, *connect.Request[v1.LookupRequest]) (*connect.Response[v1.LookupResponse], error)
 	// Get returns a single record by ID.
 	Get(context.Context, *connect.Request[v1.GetRequest]) (*connect.Response[v1.GetResponse], error)
+	// Create inserts a new record and returns it.
+	Create(context.Context, *connect.Request[v1.CreateRequest]) (*connect.Response[v1.CreateResponse], error)
+	// Update sets the given fields on a record, optionally guarded by an expected version.
+	Update(context.Context, *connect.Request[v1.UpdateRequest]) (*connect.Response[v1.UpdateResponse], error)
+	// UpdateWhere applies one patch to every record matching a filter, in a
+	// single transaction. A dry run reports how many records match; the update
+	// itself must repeat that count and is refused above a row cap.
+	UpdateWhere(context.Context, *connect.Request[v1.UpdateWhereRequest]) (*connect.Response[v1.UpdateWhereResponse], error)
+	// Upsert creates or merges a record keyed by an external ID field, so
+	// integrations can sync idempotently without knowing registry UUIDs.
+	Upsert(context.Context, *connect.Request[v1.UpsertRequest]) (*connect.Response[v1.UpsertResponse], error)
+	// Delete removes a record, optionally guarded by an expected version.
+	Delete(context.Context, *connect.Request[v1.DeleteRequest]) (*connect.Response[v1.DeleteResponse], error)
+	// GetRecordHistory returns every version of a record with the actor that
+	// wrote it and the fields it changed.
+	GetRecordHistory(context.Context, *connect.Request[v1.GetRecordHistoryRequest]) (*connect.Response[v1.GetRecordHistoryResponse], error)
+	// RevertRecord restores the field values of a prior version as a new
+	// update, so the revert itself is a version in the history.
+	RevertRecord(context.Context, *connect.Request[v1.RevertRecordRequest]) (*connect.Response[v1.RevertRecordResponse], error)
 }
 
 // NewRegistryServiceClient constructs a client for the registry.v1.RegistryService service. By
@@ -64,19 +116,89 @@ func NewRegistryServiceClient(httpClient connect.HTTPClient, baseURL string, opt
 			connect.WithSchema(registryServiceMethods.ByName("List")),
 			connect.WithClientOptions(opts...),
 		),
+		splitList: connect.NewClient[v1.SplitListRequest, v1.SplitListResponse](
+			httpClient,
+			baseURL+RegistryServiceSplitListProcedure,
+			connect.WithSchema(registryServiceMethods.ByName("SplitList")),
+			connect.WithClientOptions(opts...),
+		),
+		typeahead: connect.NewClient[v1.TypeaheadRequest, v1.TypeaheadResponse](
+			httpClient,
+			baseURL+RegistryServiceTypeaheadProcedure,
+			connect.WithSchema(registryServiceMethods.ByName("Typeahead")),
+			connect.WithClientOptions(opts...),
+		),
+		lookup: connect.NewClient[v1.LookupRequest, v1.LookupResponse](
+			httpClient,
+			baseURL+RegistryServiceLookupProcedure,
+			connect.WithSchema(registryServiceMethods.ByName("Lookup")),
+			connect.WithClientOptions(opts...),
+		),
 		get: connect.NewClient[v1.GetRequest, v1.GetResponse](
 			httpClient,
 			baseURL+RegistryServiceGetProcedure,
 			connect.WithSchema(registryServiceMethods.ByName("Get")),
 			connect.WithClientOptions(opts...),
 		),
+		create: connect.NewClient[v1.CreateRequest, v1.CreateResponse](
+			httpClient,
+			baseURL+RegistryServiceCreateProcedure,
+			connect.WithSchema(registryServiceMethods.ByName("Create")),
+			connect.WithClientOptions(opts...),
+		),
+		update: connect.NewClient[v1.UpdateRequest, v1.UpdateResponse](
+			httpClient,
+			baseURL+RegistryServiceUpdateProcedure,
+			connect.WithSchema(registryServiceMethods.ByName("Update")),
+			connect.WithClientOptions(opts...),
+		),
+		updateWhere: connect.NewClient[v1.UpdateWhereRequest, v1.UpdateWhereResponse](
+			httpClient,
+			baseURL+RegistryServiceUpdateWhereProcedure,
+			connect.WithSchema(registryServiceMethods.ByName("UpdateWhere")),
+			connect.WithClientOptions(opts...),
+		),
+		upsert: connect.NewClient[v1.UpsertRequest, v1.UpsertResponse](
+			httpClient,
+			baseURL+RegistryServiceUpsertProcedure,
+			connect.WithSchema(registryServiceMethods.ByName("Upsert")),
+			connect.WithClientOptions(opts...),
+		),
+		delete: connect.NewClient[v1.DeleteRequest, v1.DeleteResponse](
+			httpClient,
+			baseURL+RegistryServiceDeleteProcedure,
+			connect.WithSchema(registryServiceMethods.ByName("Delete")),
+			connect.WithClientOptions(opts...),
+		),
+		getRecordHistory: connect.NewClient[v1.GetRecordHistoryRequest, v1.GetRecordHistoryResponse](
+			httpClient,
+			baseURL+RegistryServiceGetRecordHistoryProcedure,
+			connect.WithSchema(registryServiceMethods.ByName("GetRecordHistory")),
+			connect.WithClientOptions(opts...),
+		),
+		revertRecord: connect.NewClient[v1.RevertRecordRequest, v1.RevertRecordResponse](
+			httpClient,
+			baseURL+RegistryServiceRevertRecordProcedure,
+			connect.WithSchema(registryServiceMethods.ByName("RevertRecord")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // registryServiceClient implements RegistryServiceClient.
 type registryServiceClient struct {
-	list *connect.Client[v1.ListRequest, v1.ListResponse]
-	get  *connect.Client[v1.GetRequest, v1.GetResponse]
+	list             *connect.Client[v1.ListRequest, v1.ListResponse]
+	splitList        *connect.Client[v1.SplitListRequest, v1.SplitListResponse]
+	typeahead        *connect.Client[v1.TypeaheadRequest, v1.TypeaheadResponse]
+	lookup           *connect.Client[v1.LookupRequest, v1.LookupResponse]
+	get              *connect.Client[v1.GetRequest, v1.GetResponse]
+	create           *connect.Client[v1.CreateRequest, v1.CreateResponse]
+	update           *connect.Client[v1.UpdateRequest, v1.UpdateResponse]
+	updateWhere      *connect.Client[v1.UpdateWhereRequest, v1.UpdateWhereResponse]
+	upsert           *connect.Client[v1.UpsertRequest, v1.UpsertResponse]
+	delete           *connect.Client[v1.DeleteRequest, v1.DeleteResponse]
+	getRecordHistory *connect.Client[v1.GetRecordHistoryRequest, v1.GetRecordHistoryResponse]
+	revertRecord     *connect.Client[v1.RevertRecordRequest, v1.RevertRecordResponse]
 }
 
 // List calls registry.v1.RegistryService.List.
@@ -84,17 +206,94 @@ func (c *registryServiceClient) List(ctx context.Context, req *connect.Request[v
 	return c.list.CallUnary(ctx, req)
 }
 
+// SplitList calls registry.v1.RegistryService.SplitList.
+func (c *registryServiceClient) SplitList(ctx context.Context, req *connect.Request[v1.SplitListRequest]) (*connect.Response[v1.SplitListResponse], error) {
+	return c.splitList.CallUnary(ctx, req)
+}
+
+// Typeahead calls registry.v1.RegistryService.Typeahead.
+func (c *registryServiceClient) Typeahead(ctx context.Context, req *connect.Request[v1.TypeaheadRequest]) (*connect.Response[v1.TypeaheadResponse], error) {
+	return c.typeahead.CallUnary(ctx, req)
+}
+
+// Lookup calls registry.v1.RegistryService.Lookup.
+func (c *registryServiceClient) Lookup(ctx context.Context, req *connect.Request[v1.LookupRequest]) (*connect.Response[v1.LookupResponse], error) {
+	return c.lookup.CallUnary(ctx, req)
+}
+
 // Get calls registry.v1.RegistryService.Get.
 func (c *registryServiceClient) Get(ctx context.Context, req *connect.Request[v1.GetRequest]) (*connect.Response[v1.GetResponse], error) {
 	return c.get.CallUnary(ctx, req)
 }
 
+// Create calls registry.v1.RegistryService.Create.
+func (c *registryServiceClient) Create(ctx context.Context, req *connect.Request[v1.CreateRequest]) (*connect.Response[v1.CreateResponse], error) {
+	return c.create.CallUnary(ctx, req)
+}
+
+// Update calls registry.v1.RegistryService.Update.
+func (c *registryServiceClient) Update(ctx context.Context, req *connect.Request[v1.UpdateRequest]) (*connect.Response[v1.UpdateResponse], error) {
+	return c.update.CallUnary(ctx, req)
+}
+
+// UpdateWhere calls registry.v1.RegistryService.UpdateWhere.
+func (c *registryServiceClient) UpdateWhere(ctx context.Context, req *connect.Request[v1.UpdateWhereRequest]) (*connect.Response[v1.UpdateWhereResponse], error) {
+	return c.updateWhere.CallUnary(ctx, req)
+}
+
+// Upsert calls registry.v1.RegistryService.Upsert.
+func (c *registryServiceClient) Upsert(ctx context.Context, req *connect.Request[v1.UpsertRequest]) (*connect.Response[v1.UpsertResponse], error) {
+	return c.upsert.CallUnary(ctx, req)
+}
+
+// Delete calls registry.v1.RegistryService.Delete.
+func (c *registryServiceClient) Delete(ctx context.Context, req *connect.Request[v1.DeleteRequest]) (*connect.Response[v1.DeleteResponse], error) {
+	return c.delete.CallUnary(ctx, req)
+}
+
+// GetRecordHistory calls registry.v1.RegistryService.GetRecordHistory.
+func (c *registryServiceClient) GetRecordHistory(ctx context.Context, req *connect.Request[v1.GetRecordHistoryRequest]) (*connect.Response[v1.GetRecordHistoryResponse], error) {
+	return c.getRecordHistory.CallUnary(ctx, req)
+}
+
+// RevertRecord calls registry.v1.RegistryService.RevertRecord.
+func (c *registryServiceClient) RevertRecord(ctx context.Context, req *connect.Request[v1.RevertRecordRequest]) (*connect.Response[v1.RevertRecordResponse], error) {
+	return c.revertRecord.CallUnary(ctx, req)
+}
+
 // RegistryServiceHandler is an implementation of the registry.v1.RegistryService service.
 type RegistryServiceHandler interface {
 	// List returns a paginated list of records for the given object.
 	List(context.Context, *connect.Request[v1.ListRequest]) (*connect.Response[v1.ListResponse], error)
+	// SplitList divides the records of an object matching filters into id
+	// ranges of about equal size and returns a List cursor for each, so batch
+	// consumers can read the partitions in parallel workers.
+	SplitList(context.Context, *connect.Request[v1.SplitListRequest]) (*connect.Response[v1.SplitListResponse], error)
+	// Typeahead prefix-searches records by display name for pickers.
+	Typeahead(context.Context, *connect.Request[v1.TypeaheadRequest]) (*connect.Response[v1.TypeaheadResponse], error)
+	// Lookup searches the records a LOOKUP field can reference, for dropdowns.
+	Lookup(context.Context, *connect.Request[v1.LookupRequest]) (*connect.Response[v1.LookupResponse], error)
 	// Get returns a single record by ID.
 	Get(context.Context, *connect.Request[v1.GetRequest]) (*connect.Response[v1.GetResponse], error)
+	// Create inserts a new record and returns it.
+	Create(context.Context, *connect.Request[v1.CreateRequest]) (*connect.Response[v1.CreateResponse], error)
+	// Update sets the given fields on a record, optionally guarded by an expected version.
+	Update(context.Context, *connect.Request[v1.UpdateRequest]) (*connect.Response[v1.UpdateResponse], error)
+	// UpdateWhere applies one patch to every record matching a filter, in a
+	// single transaction. A dry run reports how many records match; the update
+	// itself must repeat that count and is refused above a row cap.
+	UpdateWhere(context.Context, *connect.Request[v1.UpdateWhereRequest]) (*connect.Response[v1.UpdateWhereResponse], error)
+	// Upsert creates or merges a record keyed by an external ID field, so
+	// integrations can sync idempotently without knowing registry UUIDs.
+	Upsert(context.Context, *connect.Request[v1.UpsertRequest]) (*connect.Response[v1.UpsertResponse], error)
+	// Delete removes a record, optionally guarded by an expected version.
+	Delete(context.Context, *connect.Request[v1.DeleteRequest]) (*connect.Response[v1.DeleteResponse], error)
+	// GetRecordHistory returns every version of a record with the actor that
+	// wrote it and the fields it changed.
+	GetRecordHistory(context.Context, *connect.Request[v1.GetRecordHistoryRequest]) (*connect.Response[v1.GetRecordHistoryResponse], error)
+	// RevertRecord restores the field values of a prior version as a new
+	// update, so the revert itself is a version in the history.
+	RevertRecord(context.Context, *connect.Request[v1.RevertRecordRequest]) (*connect.Response[v1.RevertRecordResponse], error)
 }
 
 // NewRegistryServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -110,18 +309,98 @@ func NewRegistryServiceHandler(svc RegistryServiceHandler, opts ...connect.Handl
 		connect.WithSchema(registryServiceMethods.ByName("List")),
 		connect.WithHandlerOptions(opts...),
 	)
+	registryServiceSplitListHandler := connect.NewUnaryHandler(
+		RegistryServiceSplitListProcedure,
+		svc.SplitList,
+		connect.WithSchema(registryServiceMethods.ByName("SplitList")),
+		connect.WithHandlerOptions(opts...),
+	)
+	registryServiceTypeaheadHandler := connect.NewUnaryHandler(
+		RegistryServiceTypeaheadProcedure,
+		svc.Typeahead,
+		connect.WithSchema(registryServiceMethods.ByName("Typeahead")),
+		connect.WithHandlerOptions(opts...),
+	)
+	registryServiceLookupHandler := connect.NewUnaryHandler(
+		RegistryServiceLookupProcedure,
+		svc.Lookup,
+		connect.WithSchema(registryServiceMethods.ByName("Lookup")),
+		connect.WithHandlerOptions(opts...),
+	)
 	registryServiceGetHandler := connect.NewUnaryHandler(
 		RegistryServiceGetProcedure,
 		svc.Get,
 		connect.WithSchema(registryServiceMethods.ByName("Get")),
 		connect.WithHandlerOptions(opts...),
 	)
+	registryServiceCreateHandler := connect.NewUnaryHandler(
+		RegistryServiceCreateProcedure,
+		svc.Create,
+		connect.WithSchema(registryServiceMethods.ByName("Create")),
+		connect.WithHandlerOptions(opts...),
+	)
+	registryServiceUpdateHandler := connect.NewUnaryHandler(
+		RegistryServiceUpdateProcedure,
+		svc.Update,
+		connect.WithSchema(registryServiceMethods.ByName("Update")),
+		connect.WithHandlerOptions(opts...),
+	)
+	registryServiceUpdateWhereHandler := connect.NewUnaryHandler(
+		RegistryServiceUpdateWhereProcedure,
+		svc.UpdateWhere,
+		connect.WithSchema(registryServiceMethods.ByName("UpdateWhere")),
+		connect.WithHandlerOptions(opts...),
+	)
+	registryServiceUpsertHandler := connect.NewUnaryHandler(
+		RegistryServiceUpsertProcedure,
+		svc.Upsert,
+		connect.WithSchema(registryServiceMethods.ByName("Upsert")),
+		connect.WithHandlerOptions(opts...),
+	)
+	registryServiceDeleteHandler := connect.NewUnaryHandler(
+		RegistryServiceDeleteProcedure,
+		svc.Delete,
+		connect.WithSchema(registryServiceMethods.ByName("Delete")),
+		connect.WithHandlerOptions(opts...),
+	)
+	registryServiceGetRecordHistoryHandler := connect.NewUnaryHandler(
+		RegistryServiceGetRecordHistoryProcedure,
+		svc.GetRecordHistory,
+		connect.WithSchema(registryServiceMethods.ByName("GetRecordHistory")),
+		connect.WithHandlerOptions(opts...),
+	)
+	registryServiceRevertRecordHandler := connect.NewUnaryHandler(
+		RegistryServiceRevertRecordProcedure,
+		svc.RevertRecord,
+		connect.WithSchema(registryServiceMethods.ByName("RevertRecord")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/registry.v1.RegistryService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case RegistryServiceListProcedure:
 			registryServiceListHandler.ServeHTTP(w, r)
+		case RegistryServiceSplitListProcedure:
+			registryServiceSplitListHandler.ServeHTTP(w, r)
+		case RegistryServiceTypeaheadProcedure:
+			registryServiceTypeaheadHandler.ServeHTTP(w, r)
+		case RegistryServiceLookupProcedure:
+			registryServiceLookupHandler.ServeHTTP(w, r)
 		case RegistryServiceGetProcedure:
 			registryServiceGetHandler.ServeHTTP(w, r)
+		case RegistryServiceCreateProcedure:
+			registryServiceCreateHandler.ServeHTTP(w, r)
+		case RegistryServiceUpdateProcedure:
+			registryServiceUpdateHandler.ServeHTTP(w, r)
+		case RegistryServiceUpdateWhereProcedure:
+			registryServiceUpdateWhereHandler.ServeHTTP(w, r)
+		case RegistryServiceUpsertProcedure:
+			registryServiceUpsertHandler.ServeHTTP(w, r)
+		case RegistryServiceDeleteProcedure:
+			registryServiceDeleteHandler.ServeHTTP(w, r)
+		case RegistryServiceGetRecordHistoryProcedure:
+			registryServiceGetRecordHistoryHandler.ServeHTTP(w, r)
+		case RegistryServiceRevertRecordProcedure:
+			registryServiceRevertRecordHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -135,6 +414,46 @@ func (UnimplementedRegistryServiceHandler) List(context.Context, *connect.Reques
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.RegistryService.List is not implemented"))
 }
 
+func (UnimplementedRegistryServiceHandler) SplitList(context.Context, *connect.Request[v1.SplitListRequest]) (*connect.Response[v1.SplitListResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.RegistryService.SplitList is not implemented"))
+}
+
+func (UnimplementedRegistryServiceHandler) Typeahead(context.Context, *connect.Request[v1.TypeaheadRequest]) (*connect.Response[v1.TypeaheadResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.RegistryService.Typeahead is not implemented"))
+}
+
+func (UnimplementedRegistryServiceHandler) Lookup(context.Context, *connect.Request[v1.LookupRequest]) (*connect.Response[v1.LookupResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.RegistryService.Lookup is not implemented"))
+}
+
 func (UnimplementedRegistryServiceHandler) Get(context.Context, *connect.Request[v1.GetRequest]) (*connect.Response[v1.GetResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.RegistryService.Get is not implemented"))
 }
+
+func (UnimplementedRegistryServiceHandler) Create(context.Context, *connect.Request[v1.CreateRequest]) (*connect.Response[v1.CreateResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.RegistryService.Create is not implemented"))
+}
+
+func (UnimplementedRegistryServiceHandler) Update(context.Context, *connect.Request[v1.UpdateRequest]) (*connect.Response[v1.UpdateResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.RegistryService.Update is not implemented"))
+}
+
+func (UnimplementedRegistryServiceHandler) UpdateWhere(context.Context, *connect.Request[v1.UpdateWhereRequest]) (*connect.Response[v1.UpdateWhereResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.RegistryService.UpdateWhere is not implemented"))
+}
+
+func (UnimplementedRegistryServiceHandler) Upsert(context.Context, *connect.Request[v1.UpsertRequest]) (*connect.Response[v1.UpsertResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.RegistryService.Upsert is not implemented"))
+}
+
+func (UnimplementedRegistryServiceHandler) Delete(context.Context, *connect.Request[v1.DeleteRequest]) (*connect.Response[v1.DeleteResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.RegistryService.Delete is not implemented"))
+}
+
+func (UnimplementedRegistryServiceHandler) GetRecordHistory(context.Context, *connect.Request[v1.GetRecordHistoryRequest]) (*connect.Response[v1.GetRecordHistoryResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.RegistryService.GetRecordHistory is not implemented"))
+}
+
+func (UnimplementedRegistryServiceHandler) RevertRecord(context.Context, *connect.Request[v1.RevertRecordRequest]) (*connect.Response[v1.RevertRecordResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.RegistryService.RevertRecord is not implemented"))
+}
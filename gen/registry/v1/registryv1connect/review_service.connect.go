@@ -0,0 +1,181 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: registry/v1/review_service.proto
+
+package registryv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/atlekbai/schema_registry/gen/registry/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// ReviewServiceName is the fully-qualified name of the ReviewService service.
+	ReviewServiceName = "registry.v1.ReviewService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// ReviewServiceListChangeRequestsProcedure is the fully-qualified name of the ReviewService's
+	// ListChangeRequests RPC.
+	ReviewServiceListChangeRequestsProcedure = "/registry.v1.ReviewService/ListChangeRequests"
+	// ReviewServiceApproveChangeRequestProcedure is the fully-qualified name of the ReviewService's
+	// ApproveChangeRequest RPC.
+	ReviewServiceApproveChangeRequestProcedure = "/registry.v1.ReviewService/ApproveChangeRequest"
+	// ReviewServiceRejectChangeRequestProcedure is the fully-qualified name of the ReviewService's
+	// RejectChangeRequest RPC.
+	ReviewServiceRejectChangeRequestProcedure = "/registry.v1.ReviewService/RejectChangeRequest"
+)
+
+// ReviewServiceClient is a client for the registry.v1.ReviewService service.
+type ReviewServiceClient interface {
+	// ListChangeRequests lists change requests, newest first.
+	ListChangeRequests(context.Context, *connect.Request[v1.ListChangeRequestsRequest]) (*connect.Response[v1.ListChangeRequestsResponse], error)
+	// ApproveChangeRequest applies a pending change and reloads the schema
+	// cache. Requires the metadata:review permission, and reviewers cannot
+	// approve their own requests. A change that no longer applies (e.g. its
+	// field was deleted meanwhile) is marked FAILED and its error returned.
+	ApproveChangeRequest(context.Context, *connect.Request[v1.ApproveChangeRequestRequest]) (*connect.Response[v1.ApproveChangeRequestResponse], error)
+	// RejectChangeRequest discards a pending change. Requires the
+	// metadata:review permission.
+	RejectChangeRequest(context.Context, *connect.Request[v1.RejectChangeRequestRequest]) (*connect.Response[v1.RejectChangeRequestResponse], error)
+}
+
+// NewReviewServiceClient constructs a client for the registry.v1.ReviewService service. By default,
+// it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and
+// sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC()
+// or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewReviewServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) ReviewServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	reviewServiceMethods := v1.File_registry_v1_review_service_proto.Services().ByName("ReviewService").Methods()
+	return &reviewServiceClient{
+		listChangeRequests: connect.NewClient[v1.ListChangeRequestsRequest, v1.ListChangeRequestsResponse](
+			httpClient,
+			baseURL+ReviewServiceListChangeRequestsProcedure,
+			connect.WithSchema(reviewServiceMethods.ByName("ListChangeRequests")),
+			connect.WithClientOptions(opts...),
+		),
+		approveChangeRequest: connect.NewClient[v1.ApproveChangeRequestRequest, v1.ApproveChangeRequestResponse](
+			httpClient,
+			baseURL+ReviewServiceApproveChangeRequestProcedure,
+			connect.WithSchema(reviewServiceMethods.ByName("ApproveChangeRequest")),
+			connect.WithClientOptions(opts...),
+		),
+		rejectChangeRequest: connect.NewClient[v1.RejectChangeRequestRequest, v1.RejectChangeRequestResponse](
+			httpClient,
+			baseURL+ReviewServiceRejectChangeRequestProcedure,
+			connect.WithSchema(reviewServiceMethods.ByName("RejectChangeRequest")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// reviewServiceClient implements ReviewServiceClient.
+type reviewServiceClient struct {
+	listChangeRequests   *connect.Client[v1.ListChangeRequestsRequest, v1.ListChangeRequestsResponse]
+	approveChangeRequest *connect.Client[v1.ApproveChangeRequestRequest, v1.ApproveChangeRequestResponse]
+	rejectChangeRequest  *connect.Client[v1.RejectChangeRequestRequest, v1.RejectChangeRequestResponse]
+}
+
+// ListChangeRequests calls registry.v1.ReviewService.ListChangeRequests.
+func (c *reviewServiceClient) ListChangeRequests(ctx context.Context, req *connect.Request[v1.ListChangeRequestsRequest]) (*connect.Response[v1.ListChangeRequestsResponse], error) {
+	return c.listChangeRequests.CallUnary(ctx, req)
+}
+
+// ApproveChangeRequest calls registry.v1.ReviewService.ApproveChangeRequest.
+func (c *reviewServiceClient) ApproveChangeRequest(ctx context.Context, req *connect.Request[v1.ApproveChangeRequestRequest]) (*connect.Response[v1.ApproveChangeRequestResponse], error) {
+	return c.approveChangeRequest.CallUnary(ctx, req)
+}
+
+// RejectChangeRequest calls registry.v1.ReviewService.RejectChangeRequest.
+func (c *reviewServiceClient) RejectChangeRequest(ctx context.Context, req *connect.Request[v1.RejectChangeRequestRequest]) (*connect.Response[v1.RejectChangeRequestResponse], error) {
+	return c.rejectChangeRequest.CallUnary(ctx, req)
+}
+
+// ReviewServiceHandler is an implementation of the registry.v1.ReviewService service.
+type ReviewServiceHandler interface {
+	// ListChangeRequests lists change requests, newest first.
+	ListChangeRequests(context.Context, *connect.Request[v1.ListChangeRequestsRequest]) (*connect.Response[v1.ListChangeRequestsResponse], error)
+	// ApproveChangeRequest applies a pending change and reloads the schema
+	// cache. Requires the metadata:review permission, and reviewers cannot
+	// approve their own requests. A change that no longer applies (e.g. its
+	// field was deleted meanwhile) is marked FAILED and its error returned.
+	ApproveChangeRequest(context.Context, *connect.Request[v1.ApproveChangeRequestRequest]) (*connect.Response[v1.ApproveChangeRequestResponse], error)
+	// RejectChangeRequest discards a pending change. Requires the
+	// metadata:review permission.
+	RejectChangeRequest(context.Context, *connect.Request[v1.RejectChangeRequestRequest]) (*connect.Response[v1.RejectChangeRequestResponse], error)
+}
+
+// NewReviewServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewReviewServiceHandler(svc ReviewServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	reviewServiceMethods := v1.File_registry_v1_review_service_proto.Services().ByName("ReviewService").Methods()
+	reviewServiceListChangeRequestsHandler := connect.NewUnaryHandler(
+		ReviewServiceListChangeRequestsProcedure,
+		svc.ListChangeRequests,
+		connect.WithSchema(reviewServiceMethods.ByName("ListChangeRequests")),
+		connect.WithHandlerOptions(opts...),
+	)
+	reviewServiceApproveChangeRequestHandler := connect.NewUnaryHandler(
+		ReviewServiceApproveChangeRequestProcedure,
+		svc.ApproveChangeRequest,
+		connect.WithSchema(reviewServiceMethods.ByName("ApproveChangeRequest")),
+		connect.WithHandlerOptions(opts...),
+	)
+	reviewServiceRejectChangeRequestHandler := connect.NewUnaryHandler(
+		ReviewServiceRejectChangeRequestProcedure,
+		svc.RejectChangeRequest,
+		connect.WithSchema(reviewServiceMethods.ByName("RejectChangeRequest")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/registry.v1.ReviewService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case ReviewServiceListChangeRequestsProcedure:
+			reviewServiceListChangeRequestsHandler.ServeHTTP(w, r)
+		case ReviewServiceApproveChangeRequestProcedure:
+			reviewServiceApproveChangeRequestHandler.ServeHTTP(w, r)
+		case ReviewServiceRejectChangeRequestProcedure:
+			reviewServiceRejectChangeRequestHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedReviewServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedReviewServiceHandler struct{}
+
+func (UnimplementedReviewServiceHandler) ListChangeRequests(context.Context, *connect.Request[v1.ListChangeRequestsRequest]) (*connect.Response[v1.ListChangeRequestsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.ReviewService.ListChangeRequests is not implemented"))
+}
+
+func (UnimplementedReviewServiceHandler) ApproveChangeRequest(context.Context, *connect.Request[v1.ApproveChangeRequestRequest]) (*connect.Response[v1.ApproveChangeRequestResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.ReviewService.ApproveChangeRequest is not implemented"))
+}
+
+func (UnimplementedReviewServiceHandler) RejectChangeRequest(context.Context, *connect.Request[v1.RejectChangeRequestRequest]) (*connect.Response[v1.RejectChangeRequestResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.ReviewService.RejectChangeRequest is not implemented"))
+}
@@ -0,0 +1,147 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: registry/v1/stats_service.proto
+
+package registryv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/atlekbai/schema_registry/gen/registry/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// StatsServiceName is the fully-qualified name of the StatsService service.
+	StatsServiceName = "registry.v1.StatsService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// StatsServiceObjectStatsProcedure is the fully-qualified name of the StatsService's ObjectStats
+	// RPC.
+	StatsServiceObjectStatsProcedure = "/registry.v1.StatsService/ObjectStats"
+	// StatsServiceHRQLUsageProcedure is the fully-qualified name of the StatsService's HRQLUsage RPC.
+	StatsServiceHRQLUsageProcedure = "/registry.v1.StatsService/HRQLUsage"
+)
+
+// StatsServiceClient is a client for the registry.v1.StatsService service.
+type StatsServiceClient interface {
+	// ObjectStats returns record counts, storage size and last write time for every
+	// registered object in one call.
+	ObjectStats(context.Context, *connect.Request[v1.ObjectStatsRequest]) (*connect.Response[v1.ObjectStatsResponse], error)
+	// HRQLUsage reports which HRQL functions and steps this instance has seen
+	// since it started, with plan kinds, failure counts and compile time. The
+	// same counters are exported as Prometheus metrics on /metrics.
+	HRQLUsage(context.Context, *connect.Request[v1.HRQLUsageRequest]) (*connect.Response[v1.HRQLUsageResponse], error)
+}
+
+// NewStatsServiceClient constructs a client for the registry.v1.StatsService service. By default,
+// it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and
+// sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC()
+// or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewStatsServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) StatsServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	statsServiceMethods := v1.File_registry_v1_stats_service_proto.Services().ByName("StatsService").Methods()
+	return &statsServiceClient{
+		objectStats: connect.NewClient[v1.ObjectStatsRequest, v1.ObjectStatsResponse](
+			httpClient,
+			baseURL+StatsServiceObjectStatsProcedure,
+			connect.WithSchema(statsServiceMethods.ByName("ObjectStats")),
+			connect.WithClientOptions(opts...),
+		),
+		hRQLUsage: connect.NewClient[v1.HRQLUsageRequest, v1.HRQLUsageResponse](
+			httpClient,
+			baseURL+StatsServiceHRQLUsageProcedure,
+			connect.WithSchema(statsServiceMethods.ByName("HRQLUsage")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// statsServiceClient implements StatsServiceClient.
+type statsServiceClient struct {
+	objectStats *connect.Client[v1.ObjectStatsRequest, v1.ObjectStatsResponse]
+	hRQLUsage   *connect.Client[v1.HRQLUsageRequest, v1.HRQLUsageResponse]
+}
+
+// ObjectStats calls registry.v1.StatsService.ObjectStats.
+func (c *statsServiceClient) ObjectStats(ctx context.Context, req *connect.Request[v1.ObjectStatsRequest]) (*connect.Response[v1.ObjectStatsResponse], error) {
+	return c.objectStats.CallUnary(ctx, req)
+}
+
+// HRQLUsage calls registry.v1.StatsService.HRQLUsage.
+func (c *statsServiceClient) HRQLUsage(ctx context.Context, req *connect.Request[v1.HRQLUsageRequest]) (*connect.Response[v1.HRQLUsageResponse], error) {
+	return c.hRQLUsage.CallUnary(ctx, req)
+}
+
+// StatsServiceHandler is an implementation of the registry.v1.StatsService service.
+type StatsServiceHandler interface {
+	// ObjectStats returns record counts, storage size and last write time for every
+	// registered object in one call.
+	ObjectStats(context.Context, *connect.Request[v1.ObjectStatsRequest]) (*connect.Response[v1.ObjectStatsResponse], error)
+	// HRQLUsage reports which HRQL functions and steps this instance has seen
+	// since it started, with plan kinds, failure counts and compile time. The
+	// same counters are exported as Prometheus metrics on /metrics.
+	HRQLUsage(context.Context, *connect.Request[v1.HRQLUsageRequest]) (*connect.Response[v1.HRQLUsageResponse], error)
+}
+
+// NewStatsServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewStatsServiceHandler(svc StatsServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	statsServiceMethods := v1.File_registry_v1_stats_service_proto.Services().ByName("StatsService").Methods()
+	statsServiceObjectStatsHandler := connect.NewUnaryHandler(
+		StatsServiceObjectStatsProcedure,
+		svc.ObjectStats,
+		connect.WithSchema(statsServiceMethods.ByName("ObjectStats")),
+		connect.WithHandlerOptions(opts...),
+	)
+	statsServiceHRQLUsageHandler := connect.NewUnaryHandler(
+		StatsServiceHRQLUsageProcedure,
+		svc.HRQLUsage,
+		connect.WithSchema(statsServiceMethods.ByName("HRQLUsage")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/registry.v1.StatsService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case StatsServiceObjectStatsProcedure:
+			statsServiceObjectStatsHandler.ServeHTTP(w, r)
+		case StatsServiceHRQLUsageProcedure:
+			statsServiceHRQLUsageHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedStatsServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedStatsServiceHandler struct{}
+
+func (UnimplementedStatsServiceHandler) ObjectStats(context.Context, *connect.Request[v1.ObjectStatsRequest]) (*connect.Response[v1.ObjectStatsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.StatsService.ObjectStats is not implemented"))
+}
+
+func (UnimplementedStatsServiceHandler) HRQLUsage(context.Context, *connect.Request[v1.HRQLUsageRequest]) (*connect.Response[v1.HRQLUsageResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("registry.v1.StatsService.HRQLUsage is not implemented"))
+}
@@ -0,0 +1,622 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: registry/v1/review_service.proto
+
+package registryv1
+
+import (
+	_ "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ChangeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// MetadataService method, e.g. "CreateField".
+	Method string `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+	// Object the change applies to; empty for CreateObject.
+	ObjectId string `protobuf:"bytes,3,opt,name=object_id,json=objectId,proto3" json:"object_id,omitempty"`
+	// The request of the held call, in its JSON form.
+	Payload *structpb.Struct `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"`
+	// PENDING, APPROVED, REJECTED or FAILED.
+	Status string `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	// X-Principal-Id of the caller who made the change.
+	RequestedBy   string `protobuf:"bytes,6,opt,name=requested_by,json=requestedBy,proto3" json:"requested_by,omitempty"`
+	CreatedAt     string `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ReviewedBy    string `protobuf:"bytes,8,opt,name=reviewed_by,json=reviewedBy,proto3" json:"reviewed_by,omitempty"`
+	ReviewedAt    string `protobuf:"bytes,9,opt,name=reviewed_at,json=reviewedAt,proto3" json:"reviewed_at,omitempty"`
+	ReviewComment string `protobuf:"bytes,10,opt,name=review_comment,json=reviewComment,proto3" json:"review_comment,omitempty"`
+	// Why applying an approved change failed (status FAILED).
+	Error         string `protobuf:"bytes,11,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeRequest) Reset() {
+	*x = ChangeRequest{}
+	mi := &file_registry_v1_review_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeRequest) ProtoMessage() {}
+
+func (x *ChangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_review_service_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeRequest.ProtoReflect.Descriptor instead.
+func (*ChangeRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_review_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ChangeRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ChangeRequest) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *ChangeRequest) GetObjectId() string {
+	if x != nil {
+		return x.ObjectId
+	}
+	return ""
+}
+
+func (x *ChangeRequest) GetPayload() *structpb.Struct {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *ChangeRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ChangeRequest) GetRequestedBy() string {
+	if x != nil {
+		return x.RequestedBy
+	}
+	return ""
+}
+
+func (x *ChangeRequest) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *ChangeRequest) GetReviewedBy() string {
+	if x != nil {
+		return x.ReviewedBy
+	}
+	return ""
+}
+
+func (x *ChangeRequest) GetReviewedAt() string {
+	if x != nil {
+		return x.ReviewedAt
+	}
+	return ""
+}
+
+func (x *ChangeRequest) GetReviewComment() string {
+	if x != nil {
+		return x.ReviewComment
+	}
+	return ""
+}
+
+func (x *ChangeRequest) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// ChangePending is attached to the FAILED_PRECONDITION error of a metadata
+// mutation held for review.
+type ChangePending struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Always "CHANGE_PENDING_APPROVAL".
+	Reason          string `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	ChangeRequestId string `protobuf:"bytes,2,opt,name=change_request_id,json=changeRequestId,proto3" json:"change_request_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ChangePending) Reset() {
+	*x = ChangePending{}
+	mi := &file_registry_v1_review_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePending) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePending) ProtoMessage() {}
+
+func (x *ChangePending) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_review_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePending.ProtoReflect.Descriptor instead.
+func (*ChangePending) Descriptor() ([]byte, []int) {
+	return file_registry_v1_review_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ChangePending) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ChangePending) GetChangeRequestId() string {
+	if x != nil {
+		return x.ChangeRequestId
+	}
+	return ""
+}
+
+type ListChangeRequestsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Restrict to one status; empty lists all.
+	Status string `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	// Restrict to changes of one object.
+	ObjectId string `protobuf:"bytes,2,opt,name=object_id,json=objectId,proto3" json:"object_id,omitempty"`
+	// Number of requests (0-200, 0 means 50).
+	Limit         int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListChangeRequestsRequest) Reset() {
+	*x = ListChangeRequestsRequest{}
+	mi := &file_registry_v1_review_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListChangeRequestsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChangeRequestsRequest) ProtoMessage() {}
+
+func (x *ListChangeRequestsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_review_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChangeRequestsRequest.ProtoReflect.Descriptor instead.
+func (*ListChangeRequestsRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_review_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListChangeRequestsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListChangeRequestsRequest) GetObjectId() string {
+	if x != nil {
+		return x.ObjectId
+	}
+	return ""
+}
+
+func (x *ListChangeRequestsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListChangeRequestsResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ChangeRequests []*ChangeRequest       `protobuf:"bytes,1,rep,name=change_requests,json=changeRequests,proto3" json:"change_requests,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListChangeRequestsResponse) Reset() {
+	*x = ListChangeRequestsResponse{}
+	mi := &file_registry_v1_review_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListChangeRequestsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChangeRequestsResponse) ProtoMessage() {}
+
+func (x *ListChangeRequestsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_review_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChangeRequestsResponse.ProtoReflect.Descriptor instead.
+func (*ListChangeRequestsResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_review_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListChangeRequestsResponse) GetChangeRequests() []*ChangeRequest {
+	if x != nil {
+		return x.ChangeRequests
+	}
+	return nil
+}
+
+type ApproveChangeRequestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Comment       string                 `protobuf:"bytes,2,opt,name=comment,proto3" json:"comment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApproveChangeRequestRequest) Reset() {
+	*x = ApproveChangeRequestRequest{}
+	mi := &file_registry_v1_review_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApproveChangeRequestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApproveChangeRequestRequest) ProtoMessage() {}
+
+func (x *ApproveChangeRequestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_review_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApproveChangeRequestRequest.ProtoReflect.Descriptor instead.
+func (*ApproveChangeRequestRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_review_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ApproveChangeRequestRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ApproveChangeRequestRequest) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+type ApproveChangeRequestResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChangeRequest *ChangeRequest         `protobuf:"bytes,1,opt,name=change_request,json=changeRequest,proto3" json:"change_request,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApproveChangeRequestResponse) Reset() {
+	*x = ApproveChangeRequestResponse{}
+	mi := &file_registry_v1_review_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApproveChangeRequestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApproveChangeRequestResponse) ProtoMessage() {}
+
+func (x *ApproveChangeRequestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_review_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApproveChangeRequestResponse.ProtoReflect.Descriptor instead.
+func (*ApproveChangeRequestResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_review_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ApproveChangeRequestResponse) GetChangeRequest() *ChangeRequest {
+	if x != nil {
+		return x.ChangeRequest
+	}
+	return nil
+}
+
+type RejectChangeRequestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Comment       string                 `protobuf:"bytes,2,opt,name=comment,proto3" json:"comment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectChangeRequestRequest) Reset() {
+	*x = RejectChangeRequestRequest{}
+	mi := &file_registry_v1_review_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectChangeRequestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectChangeRequestRequest) ProtoMessage() {}
+
+func (x *RejectChangeRequestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_review_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectChangeRequestRequest.ProtoReflect.Descriptor instead.
+func (*RejectChangeRequestRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_review_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RejectChangeRequestRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RejectChangeRequestRequest) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+type RejectChangeRequestResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChangeRequest *ChangeRequest         `protobuf:"bytes,1,opt,name=change_request,json=changeRequest,proto3" json:"change_request,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectChangeRequestResponse) Reset() {
+	*x = RejectChangeRequestResponse{}
+	mi := &file_registry_v1_review_service_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectChangeRequestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectChangeRequestResponse) ProtoMessage() {}
+
+func (x *RejectChangeRequestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_review_service_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectChangeRequestResponse.ProtoReflect.Descriptor instead.
+func (*RejectChangeRequestResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_review_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RejectChangeRequestResponse) GetChangeRequest() *ChangeRequest {
+	if x != nil {
+		return x.ChangeRequest
+	}
+	return nil
+}
+
+var File_registry_v1_review_service_proto protoreflect.FileDescriptor
+
+const file_registry_v1_review_service_proto_rawDesc = "" +
+	"\n" +
+	" registry/v1/review_service.proto\x12\vregistry.v1\x1a\x1bbuf/validate/validate.proto\x1a\x1cgoogle/api/annotations.proto\x1a\x1cgoogle/protobuf/struct.proto\"\xe0\x02\n" +
+	"\rChangeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06method\x18\x02 \x01(\tR\x06method\x12\x1b\n" +
+	"\tobject_id\x18\x03 \x01(\tR\bobjectId\x121\n" +
+	"\apayload\x18\x04 \x01(\v2\x17.google.protobuf.StructR\apayload\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12!\n" +
+	"\frequested_by\x18\x06 \x01(\tR\vrequestedBy\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\tR\tcreatedAt\x12\x1f\n" +
+	"\vreviewed_by\x18\b \x01(\tR\n" +
+	"reviewedBy\x12\x1f\n" +
+	"\vreviewed_at\x18\t \x01(\tR\n" +
+	"reviewedAt\x12%\n" +
+	"\x0ereview_comment\x18\n" +
+	" \x01(\tR\rreviewComment\x12\x14\n" +
+	"\x05error\x18\v \x01(\tR\x05error\"S\n" +
+	"\rChangePending\x12\x16\n" +
+	"\x06reason\x18\x01 \x01(\tR\x06reason\x12*\n" +
+	"\x11change_request_id\x18\x02 \x01(\tR\x0fchangeRequestId\"\xa0\x01\n" +
+	"\x19ListChangeRequestsRequest\x12D\n" +
+	"\x06status\x18\x01 \x01(\tB,\xbaH)r'R\x00R\aPENDINGR\bAPPROVEDR\bREJECTEDR\x06FAILEDR\x06status\x12\x1b\n" +
+	"\tobject_id\x18\x02 \x01(\tR\bobjectId\x12 \n" +
+	"\x05limit\x18\x03 \x01(\x05B\n" +
+	"\xbaH\a\x1a\x05\x18\xc8\x01(\x00R\x05limit\"a\n" +
+	"\x1aListChangeRequestsResponse\x12C\n" +
+	"\x0fchange_requests\x18\x01 \x03(\v2\x1a.registry.v1.ChangeRequestR\x0echangeRequests\"Q\n" +
+	"\x1bApproveChangeRequestRequest\x12\x18\n" +
+	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\x12\x18\n" +
+	"\acomment\x18\x02 \x01(\tR\acomment\"a\n" +
+	"\x1cApproveChangeRequestResponse\x12A\n" +
+	"\x0echange_request\x18\x01 \x01(\v2\x1a.registry.v1.ChangeRequestR\rchangeRequest\"P\n" +
+	"\x1aRejectChangeRequestRequest\x12\x18\n" +
+	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\x12\x18\n" +
+	"\acomment\x18\x02 \x01(\tR\acomment\"`\n" +
+	"\x1bRejectChangeRequestResponse\x12A\n" +
+	"\x0echange_request\x18\x01 \x01(\v2\x1a.registry.v1.ChangeRequestR\rchangeRequest2\xd8\x03\n" +
+	"\rReviewService\x12\x88\x01\n" +
+	"\x12ListChangeRequests\x12&.registry.v1.ListChangeRequestsRequest\x1a'.registry.v1.ListChangeRequestsResponse\"!\x82\xd3\xe4\x93\x02\x1b\x12\x19/api/meta/change-requests\x12\x9e\x01\n" +
+	"\x14ApproveChangeRequest\x12(.registry.v1.ApproveChangeRequestRequest\x1a).registry.v1.ApproveChangeRequestResponse\"1\x82\xd3\xe4\x93\x02+:\x01*\"&/api/meta/change-requests/{id}/approve\x12\x9a\x01\n" +
+	"\x13RejectChangeRequest\x12'.registry.v1.RejectChangeRequestRequest\x1a(.registry.v1.RejectChangeRequestResponse\"0\x82\xd3\xe4\x93\x02*:\x01*\"%/api/meta/change-requests/{id}/rejectB\xb2\x01\n" +
+	"\x0fcom.registry.v1B\x12ReviewServiceProtoP\x01Z>github.com/atlekbai/schema_registry/gen/registry/v1;registryv1\xa2\x02\x03RXX\xaa\x02\vRegistry.V1\xca\x02\vRegistry\\V1\xe2\x02\x17Registry\\V1\\GPBMetadata\xea\x02\fRegistry::V1b\x06proto3"
+
+var (
+	file_registry_v1_review_service_proto_rawDescOnce sync.Once
+	file_registry_v1_review_service_proto_rawDescData []byte
+)
+
+func file_registry_v1_review_service_proto_rawDescGZIP() []byte {
+	file_registry_v1_review_service_proto_rawDescOnce.Do(func() {
+		file_registry_v1_review_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_registry_v1_review_service_proto_rawDesc), len(file_registry_v1_review_service_proto_rawDesc)))
+	})
+	return file_registry_v1_review_service_proto_rawDescData
+}
+
+var file_registry_v1_review_service_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_registry_v1_review_service_proto_goTypes = []any{
+	(*ChangeRequest)(nil),                // 0: registry.v1.ChangeRequest
+	(*ChangePending)(nil),                // 1: registry.v1.ChangePending
+	(*ListChangeRequestsRequest)(nil),    // 2: registry.v1.ListChangeRequestsRequest
+	(*ListChangeRequestsResponse)(nil),   // 3: registry.v1.ListChangeRequestsResponse
+	(*ApproveChangeRequestRequest)(nil),  // 4: registry.v1.ApproveChangeRequestRequest
+	(*ApproveChangeRequestResponse)(nil), // 5: registry.v1.ApproveChangeRequestResponse
+	(*RejectChangeRequestRequest)(nil),   // 6: registry.v1.RejectChangeRequestRequest
+	(*RejectChangeRequestResponse)(nil),  // 7: registry.v1.RejectChangeRequestResponse
+	(*structpb.Struct)(nil),              // 8: google.protobuf.Struct
+}
+var file_registry_v1_review_service_proto_depIdxs = []int32{
+	8, // 0: registry.v1.ChangeRequest.payload:type_name -> google.protobuf.Struct
+	0, // 1: registry.v1.ListChangeRequestsResponse.change_requests:type_name -> registry.v1.ChangeRequest
+	0, // 2: registry.v1.ApproveChangeRequestResponse.change_request:type_name -> registry.v1.ChangeRequest
+	0, // 3: registry.v1.RejectChangeRequestResponse.change_request:type_name -> registry.v1.ChangeRequest
+	2, // 4: registry.v1.ReviewService.ListChangeRequests:input_type -> registry.v1.ListChangeRequestsRequest
+	4, // 5: registry.v1.ReviewService.ApproveChangeRequest:input_type -> registry.v1.ApproveChangeRequestRequest
+	6, // 6: registry.v1.ReviewService.RejectChangeRequest:input_type -> registry.v1.RejectChangeRequestRequest
+	3, // 7: registry.v1.ReviewService.ListChangeRequests:output_type -> registry.v1.ListChangeRequestsResponse
+	5, // 8: registry.v1.ReviewService.ApproveChangeRequest:output_type -> registry.v1.ApproveChangeRequestResponse
+	7, // 9: registry.v1.ReviewService.RejectChangeRequest:output_type -> registry.v1.RejectChangeRequestResponse
+	7, // [7:10] is the sub-list for method output_type
+	4, // [4:7] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_registry_v1_review_service_proto_init() }
+func file_registry_v1_review_service_proto_init() {
+	if File_registry_v1_review_service_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_registry_v1_review_service_proto_rawDesc), len(file_registry_v1_review_service_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_registry_v1_review_service_proto_goTypes,
+		DependencyIndexes: file_registry_v1_review_service_proto_depIdxs,
+		MessageInfos:      file_registry_v1_review_service_proto_msgTypes,
+	}.Build()
+	File_registry_v1_review_service_proto = out.File
+	file_registry_v1_review_service_proto_goTypes = nil
+	file_registry_v1_review_service_proto_depIdxs = nil
+}
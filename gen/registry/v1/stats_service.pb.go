@@ -0,0 +1,498 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: registry/v1/stats_service.proto
+
+package registryv1
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ObjectStatsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Scan the tables instead of reading their statistics: exact record counts
+	// above the exact-count threshold, last_write_at from the records, and
+	// custom objects' storage_bytes as the sum of their row sizes.
+	Exact         bool `protobuf:"varint,1,opt,name=exact,proto3" json:"exact,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ObjectStatsRequest) Reset() {
+	*x = ObjectStatsRequest{}
+	mi := &file_registry_v1_stats_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ObjectStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ObjectStatsRequest) ProtoMessage() {}
+
+func (x *ObjectStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_stats_service_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ObjectStatsRequest.ProtoReflect.Descriptor instead.
+func (*ObjectStatsRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_stats_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ObjectStatsRequest) GetExact() bool {
+	if x != nil {
+		return x.Exact
+	}
+	return false
+}
+
+type ObjectStats struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	ObjectId    string                 `protobuf:"bytes,1,opt,name=object_id,json=objectId,proto3" json:"object_id,omitempty"`
+	ApiName     string                 `protobuf:"bytes,2,opt,name=api_name,json=apiName,proto3" json:"api_name,omitempty"`
+	IsStandard  bool                   `protobuf:"varint,3,opt,name=is_standard,json=isStandard,proto3" json:"is_standard,omitempty"`
+	RecordCount int64                  `protobuf:"varint,4,opt,name=record_count,json=recordCount,proto3" json:"record_count,omitempty"`
+	// True when record_count is an estimate: pg_stat_user_tables.n_live_tup for
+	// standard objects, the planner's row estimate for custom objects.
+	CountIsEstimate bool `protobuf:"varint,5,opt,name=count_is_estimate,json=countIsEstimate,proto3" json:"count_is_estimate,omitempty"`
+	// Standard objects: total relation size including indexes and TOAST.
+	// Custom objects: their share of metadata.records by record count, or with
+	// exact the size of their rows.
+	StorageBytes int64 `protobuf:"varint,6,opt,name=storage_bytes,json=storageBytes,proto3" json:"storage_bytes,omitempty"`
+	// The latest updated_at across the object's records. Only set with exact;
+	// table statistics have no last write time. Empty when unknown.
+	LastWriteAt   string `protobuf:"bytes,7,opt,name=last_write_at,json=lastWriteAt,proto3" json:"last_write_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ObjectStats) Reset() {
+	*x = ObjectStats{}
+	mi := &file_registry_v1_stats_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ObjectStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ObjectStats) ProtoMessage() {}
+
+func (x *ObjectStats) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_stats_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ObjectStats.ProtoReflect.Descriptor instead.
+func (*ObjectStats) Descriptor() ([]byte, []int) {
+	return file_registry_v1_stats_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ObjectStats) GetObjectId() string {
+	if x != nil {
+		return x.ObjectId
+	}
+	return ""
+}
+
+func (x *ObjectStats) GetApiName() string {
+	if x != nil {
+		return x.ApiName
+	}
+	return ""
+}
+
+func (x *ObjectStats) GetIsStandard() bool {
+	if x != nil {
+		return x.IsStandard
+	}
+	return false
+}
+
+func (x *ObjectStats) GetRecordCount() int64 {
+	if x != nil {
+		return x.RecordCount
+	}
+	return 0
+}
+
+func (x *ObjectStats) GetCountIsEstimate() bool {
+	if x != nil {
+		return x.CountIsEstimate
+	}
+	return false
+}
+
+func (x *ObjectStats) GetStorageBytes() int64 {
+	if x != nil {
+		return x.StorageBytes
+	}
+	return 0
+}
+
+func (x *ObjectStats) GetLastWriteAt() string {
+	if x != nil {
+		return x.LastWriteAt
+	}
+	return ""
+}
+
+type ObjectStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Objects       []*ObjectStats         `protobuf:"bytes,1,rep,name=objects,proto3" json:"objects,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ObjectStatsResponse) Reset() {
+	*x = ObjectStatsResponse{}
+	mi := &file_registry_v1_stats_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ObjectStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ObjectStatsResponse) ProtoMessage() {}
+
+func (x *ObjectStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_stats_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ObjectStatsResponse.ProtoReflect.Descriptor instead.
+func (*ObjectStatsResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_stats_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ObjectStatsResponse) GetObjects() []*ObjectStats {
+	if x != nil {
+		return x.Objects
+	}
+	return nil
+}
+
+type HRQLUsageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HRQLUsageRequest) Reset() {
+	*x = HRQLUsageRequest{}
+	mi := &file_registry_v1_stats_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HRQLUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HRQLUsageRequest) ProtoMessage() {}
+
+func (x *HRQLUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_stats_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HRQLUsageRequest.ProtoReflect.Descriptor instead.
+func (*HRQLUsageRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_stats_service_proto_rawDescGZIP(), []int{3}
+}
+
+type HRQLFunctionUsage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Function or step name, e.g. "reports", "where", "count".
+	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Uses          int64  `protobuf:"varint,2,opt,name=uses,proto3" json:"uses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HRQLFunctionUsage) Reset() {
+	*x = HRQLFunctionUsage{}
+	mi := &file_registry_v1_stats_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HRQLFunctionUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HRQLFunctionUsage) ProtoMessage() {}
+
+func (x *HRQLFunctionUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_stats_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HRQLFunctionUsage.ProtoReflect.Descriptor instead.
+func (*HRQLFunctionUsage) Descriptor() ([]byte, []int) {
+	return file_registry_v1_stats_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *HRQLFunctionUsage) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *HRQLFunctionUsage) GetUses() int64 {
+	if x != nil {
+		return x.Uses
+	}
+	return 0
+}
+
+type HRQLUsageResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Queries received (parse attempts), across Query, ToFilters and BatchEvaluate.
+	Queries       int64 `protobuf:"varint,1,opt,name=queries,proto3" json:"queries,omitempty"`
+	ParseErrors   int64 `protobuf:"varint,2,opt,name=parse_errors,json=parseErrors,proto3" json:"parse_errors,omitempty"`
+	CompileErrors int64 `protobuf:"varint,3,opt,name=compile_errors,json=compileErrors,proto3" json:"compile_errors,omitempty"`
+	// parse_errors / queries; 0 before the first query.
+	ParseErrorRate float64 `protobuf:"fixed64,4,opt,name=parse_error_rate,json=parseErrorRate,proto3" json:"parse_error_rate,omitempty"`
+	// Compiled plans by kind: list, scalar, boolean.
+	Plans map[string]int64 `protobuf:"bytes,5,rep,name=plans,proto3" json:"plans,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	// Sorted by uses, most used first.
+	Functions     []*HRQLFunctionUsage `protobuf:"bytes,6,rep,name=functions,proto3" json:"functions,omitempty"`
+	MeanCompileMs float64              `protobuf:"fixed64,7,opt,name=mean_compile_ms,json=meanCompileMs,proto3" json:"mean_compile_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HRQLUsageResponse) Reset() {
+	*x = HRQLUsageResponse{}
+	mi := &file_registry_v1_stats_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HRQLUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HRQLUsageResponse) ProtoMessage() {}
+
+func (x *HRQLUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_stats_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HRQLUsageResponse.ProtoReflect.Descriptor instead.
+func (*HRQLUsageResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_stats_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *HRQLUsageResponse) GetQueries() int64 {
+	if x != nil {
+		return x.Queries
+	}
+	return 0
+}
+
+func (x *HRQLUsageResponse) GetParseErrors() int64 {
+	if x != nil {
+		return x.ParseErrors
+	}
+	return 0
+}
+
+func (x *HRQLUsageResponse) GetCompileErrors() int64 {
+	if x != nil {
+		return x.CompileErrors
+	}
+	return 0
+}
+
+func (x *HRQLUsageResponse) GetParseErrorRate() float64 {
+	if x != nil {
+		return x.ParseErrorRate
+	}
+	return 0
+}
+
+func (x *HRQLUsageResponse) GetPlans() map[string]int64 {
+	if x != nil {
+		return x.Plans
+	}
+	return nil
+}
+
+func (x *HRQLUsageResponse) GetFunctions() []*HRQLFunctionUsage {
+	if x != nil {
+		return x.Functions
+	}
+	return nil
+}
+
+func (x *HRQLUsageResponse) GetMeanCompileMs() float64 {
+	if x != nil {
+		return x.MeanCompileMs
+	}
+	return 0
+}
+
+var File_registry_v1_stats_service_proto protoreflect.FileDescriptor
+
+const file_registry_v1_stats_service_proto_rawDesc = "" +
+	"\n" +
+	"\x1fregistry/v1/stats_service.proto\x12\vregistry.v1\x1a\x1cgoogle/api/annotations.proto\"*\n" +
+	"\x12ObjectStatsRequest\x12\x14\n" +
+	"\x05exact\x18\x01 \x01(\bR\x05exact\"\xfe\x01\n" +
+	"\vObjectStats\x12\x1b\n" +
+	"\tobject_id\x18\x01 \x01(\tR\bobjectId\x12\x19\n" +
+	"\bapi_name\x18\x02 \x01(\tR\aapiName\x12\x1f\n" +
+	"\vis_standard\x18\x03 \x01(\bR\n" +
+	"isStandard\x12!\n" +
+	"\frecord_count\x18\x04 \x01(\x03R\vrecordCount\x12*\n" +
+	"\x11count_is_estimate\x18\x05 \x01(\bR\x0fcountIsEstimate\x12#\n" +
+	"\rstorage_bytes\x18\x06 \x01(\x03R\fstorageBytes\x12\"\n" +
+	"\rlast_write_at\x18\a \x01(\tR\vlastWriteAt\"I\n" +
+	"\x13ObjectStatsResponse\x122\n" +
+	"\aobjects\x18\x01 \x03(\v2\x18.registry.v1.ObjectStatsR\aobjects\"\x12\n" +
+	"\x10HRQLUsageRequest\";\n" +
+	"\x11HRQLFunctionUsage\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04uses\x18\x02 \x01(\x03R\x04uses\"\x82\x03\n" +
+	"\x11HRQLUsageResponse\x12\x18\n" +
+	"\aqueries\x18\x01 \x01(\x03R\aqueries\x12!\n" +
+	"\fparse_errors\x18\x02 \x01(\x03R\vparseErrors\x12%\n" +
+	"\x0ecompile_errors\x18\x03 \x01(\x03R\rcompileErrors\x12(\n" +
+	"\x10parse_error_rate\x18\x04 \x01(\x01R\x0eparseErrorRate\x12?\n" +
+	"\x05plans\x18\x05 \x03(\v2).registry.v1.HRQLUsageResponse.PlansEntryR\x05plans\x12<\n" +
+	"\tfunctions\x18\x06 \x03(\v2\x1e.registry.v1.HRQLFunctionUsageR\tfunctions\x12&\n" +
+	"\x0fmean_compile_ms\x18\a \x01(\x01R\rmeanCompileMs\x1a8\n" +
+	"\n" +
+	"PlansEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x012\xe1\x01\n" +
+	"\fStatsService\x12l\n" +
+	"\vObjectStats\x12\x1f.registry.v1.ObjectStatsRequest\x1a .registry.v1.ObjectStatsResponse\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/api/stats/objects\x12c\n" +
+	"\tHRQLUsage\x12\x1d.registry.v1.HRQLUsageRequest\x1a\x1e.registry.v1.HRQLUsageResponse\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/api/stats/hrqlB\xb1\x01\n" +
+	"\x0fcom.registry.v1B\x11StatsServiceProtoP\x01Z>github.com/atlekbai/schema_registry/gen/registry/v1;registryv1\xa2\x02\x03RXX\xaa\x02\vRegistry.V1\xca\x02\vRegistry\\V1\xe2\x02\x17Registry\\V1\\GPBMetadata\xea\x02\fRegistry::V1b\x06proto3"
+
+var (
+	file_registry_v1_stats_service_proto_rawDescOnce sync.Once
+	file_registry_v1_stats_service_proto_rawDescData []byte
+)
+
+func file_registry_v1_stats_service_proto_rawDescGZIP() []byte {
+	file_registry_v1_stats_service_proto_rawDescOnce.Do(func() {
+		file_registry_v1_stats_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_registry_v1_stats_service_proto_rawDesc), len(file_registry_v1_stats_service_proto_rawDesc)))
+	})
+	return file_registry_v1_stats_service_proto_rawDescData
+}
+
+var file_registry_v1_stats_service_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_registry_v1_stats_service_proto_goTypes = []any{
+	(*ObjectStatsRequest)(nil),  // 0: registry.v1.ObjectStatsRequest
+	(*ObjectStats)(nil),         // 1: registry.v1.ObjectStats
+	(*ObjectStatsResponse)(nil), // 2: registry.v1.ObjectStatsResponse
+	(*HRQLUsageRequest)(nil),    // 3: registry.v1.HRQLUsageRequest
+	(*HRQLFunctionUsage)(nil),   // 4: registry.v1.HRQLFunctionUsage
+	(*HRQLUsageResponse)(nil),   // 5: registry.v1.HRQLUsageResponse
+	nil,                         // 6: registry.v1.HRQLUsageResponse.PlansEntry
+}
+var file_registry_v1_stats_service_proto_depIdxs = []int32{
+	1, // 0: registry.v1.ObjectStatsResponse.objects:type_name -> registry.v1.ObjectStats
+	6, // 1: registry.v1.HRQLUsageResponse.plans:type_name -> registry.v1.HRQLUsageResponse.PlansEntry
+	4, // 2: registry.v1.HRQLUsageResponse.functions:type_name -> registry.v1.HRQLFunctionUsage
+	0, // 3: registry.v1.StatsService.ObjectStats:input_type -> registry.v1.ObjectStatsRequest
+	3, // 4: registry.v1.StatsService.HRQLUsage:input_type -> registry.v1.HRQLUsageRequest
+	2, // 5: registry.v1.StatsService.ObjectStats:output_type -> registry.v1.ObjectStatsResponse
+	5, // 6: registry.v1.StatsService.HRQLUsage:output_type -> registry.v1.HRQLUsageResponse
+	5, // [5:7] is the sub-list for method output_type
+	3, // [3:5] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_registry_v1_stats_service_proto_init() }
+func file_registry_v1_stats_service_proto_init() {
+	if File_registry_v1_stats_service_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_registry_v1_stats_service_proto_rawDesc), len(file_registry_v1_stats_service_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_registry_v1_stats_service_proto_goTypes,
+		DependencyIndexes: file_registry_v1_stats_service_proto_depIdxs,
+		MessageInfos:      file_registry_v1_stats_service_proto_msgTypes,
+	}.Build()
+	File_registry_v1_stats_service_proto = out.File
+	file_registry_v1_stats_service_proto_goTypes = nil
+	file_registry_v1_stats_service_proto_depIdxs = nil
+}
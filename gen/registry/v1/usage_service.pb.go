@@ -0,0 +1,378 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: registry/v1/usage_service.proto
+
+package registryv1
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetUsageRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Window in hours, counted back from now; 0 means 24. Buckets are hourly,
+	// so the window starts at the top of the hour.
+	Hours int32 `protobuf:"varint,1,opt,name=hours,proto3" json:"hours,omitempty"`
+	// Restrict the rows to one object, method (e.g. "List") or principal.
+	ObjectName    string `protobuf:"bytes,2,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	Method        string `protobuf:"bytes,3,opt,name=method,proto3" json:"method,omitempty"`
+	Principal     string `protobuf:"bytes,4,opt,name=principal,proto3" json:"principal,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsageRequest) Reset() {
+	*x = GetUsageRequest{}
+	mi := &file_registry_v1_usage_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageRequest) ProtoMessage() {}
+
+func (x *GetUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_usage_service_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageRequest.ProtoReflect.Descriptor instead.
+func (*GetUsageRequest) Descriptor() ([]byte, []int) {
+	return file_registry_v1_usage_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetUsageRequest) GetHours() int32 {
+	if x != nil {
+		return x.Hours
+	}
+	return 0
+}
+
+func (x *GetUsageRequest) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+func (x *GetUsageRequest) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *GetUsageRequest) GetPrincipal() string {
+	if x != nil {
+		return x.Principal
+	}
+	return ""
+}
+
+type UsageStats struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Empty for requests not addressing an object (e.g. metadata calls).
+	ObjectName string `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	// RPC method name, e.g. "List" or "Query".
+	Method string `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+	// X-Principal-Id of the caller; empty when the gateway sent none.
+	Principal string `protobuf:"bytes,3,opt,name=principal,proto3" json:"principal,omitempty"`
+	Requests  int64  `protobuf:"varint,4,opt,name=requests,proto3" json:"requests,omitempty"`
+	Errors    int64  `protobuf:"varint,5,opt,name=errors,proto3" json:"errors,omitempty"`
+	// errors / requests.
+	ErrorRate     float64 `protobuf:"fixed64,6,opt,name=error_rate,json=errorRate,proto3" json:"error_rate,omitempty"`
+	MeanLatencyMs float64 `protobuf:"fixed64,7,opt,name=mean_latency_ms,json=meanLatencyMs,proto3" json:"mean_latency_ms,omitempty"`
+	MaxLatencyMs  float64 `protobuf:"fixed64,8,opt,name=max_latency_ms,json=maxLatencyMs,proto3" json:"max_latency_ms,omitempty"`
+	// Records returned across all requests.
+	ResultRecords     int64   `protobuf:"varint,9,opt,name=result_records,json=resultRecords,proto3" json:"result_records,omitempty"`
+	MeanResultRecords float64 `protobuf:"fixed64,10,opt,name=mean_result_records,json=meanResultRecords,proto3" json:"mean_result_records,omitempty"`
+	// Start of the latest hour with traffic (RFC 3339).
+	LastSeenAt    string `protobuf:"bytes,11,opt,name=last_seen_at,json=lastSeenAt,proto3" json:"last_seen_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UsageStats) Reset() {
+	*x = UsageStats{}
+	mi := &file_registry_v1_usage_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UsageStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UsageStats) ProtoMessage() {}
+
+func (x *UsageStats) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_usage_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UsageStats.ProtoReflect.Descriptor instead.
+func (*UsageStats) Descriptor() ([]byte, []int) {
+	return file_registry_v1_usage_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *UsageStats) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+func (x *UsageStats) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *UsageStats) GetPrincipal() string {
+	if x != nil {
+		return x.Principal
+	}
+	return ""
+}
+
+func (x *UsageStats) GetRequests() int64 {
+	if x != nil {
+		return x.Requests
+	}
+	return 0
+}
+
+func (x *UsageStats) GetErrors() int64 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+func (x *UsageStats) GetErrorRate() float64 {
+	if x != nil {
+		return x.ErrorRate
+	}
+	return 0
+}
+
+func (x *UsageStats) GetMeanLatencyMs() float64 {
+	if x != nil {
+		return x.MeanLatencyMs
+	}
+	return 0
+}
+
+func (x *UsageStats) GetMaxLatencyMs() float64 {
+	if x != nil {
+		return x.MaxLatencyMs
+	}
+	return 0
+}
+
+func (x *UsageStats) GetResultRecords() int64 {
+	if x != nil {
+		return x.ResultRecords
+	}
+	return 0
+}
+
+func (x *UsageStats) GetMeanResultRecords() float64 {
+	if x != nil {
+		return x.MeanResultRecords
+	}
+	return 0
+}
+
+func (x *UsageStats) GetLastSeenAt() string {
+	if x != nil {
+		return x.LastSeenAt
+	}
+	return ""
+}
+
+type GetUsageResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Sorted by requests, busiest first.
+	Stats []*UsageStats `protobuf:"bytes,1,rep,name=stats,proto3" json:"stats,omitempty"`
+	// Custom objects with no requests in the window, candidates for
+	// deprecation. Only set when no filter is given.
+	UnusedObjects []string `protobuf:"bytes,2,rep,name=unused_objects,json=unusedObjects,proto3" json:"unused_objects,omitempty"`
+	// Start of the window (RFC 3339).
+	Since         string `protobuf:"bytes,3,opt,name=since,proto3" json:"since,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsageResponse) Reset() {
+	*x = GetUsageResponse{}
+	mi := &file_registry_v1_usage_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageResponse) ProtoMessage() {}
+
+func (x *GetUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registry_v1_usage_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageResponse.ProtoReflect.Descriptor instead.
+func (*GetUsageResponse) Descriptor() ([]byte, []int) {
+	return file_registry_v1_usage_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetUsageResponse) GetStats() []*UsageStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *GetUsageResponse) GetUnusedObjects() []string {
+	if x != nil {
+		return x.UnusedObjects
+	}
+	return nil
+}
+
+func (x *GetUsageResponse) GetSince() string {
+	if x != nil {
+		return x.Since
+	}
+	return ""
+}
+
+var File_registry_v1_usage_service_proto protoreflect.FileDescriptor
+
+const file_registry_v1_usage_service_proto_rawDesc = "" +
+	"\n" +
+	"\x1fregistry/v1/usage_service.proto\x12\vregistry.v1\x1a\x1cgoogle/api/annotations.proto\"~\n" +
+	"\x0fGetUsageRequest\x12\x14\n" +
+	"\x05hours\x18\x01 \x01(\x05R\x05hours\x12\x1f\n" +
+	"\vobject_name\x18\x02 \x01(\tR\n" +
+	"objectName\x12\x16\n" +
+	"\x06method\x18\x03 \x01(\tR\x06method\x12\x1c\n" +
+	"\tprincipal\x18\x04 \x01(\tR\tprincipal\"\xfd\x02\n" +
+	"\n" +
+	"UsageStats\x12\x1f\n" +
+	"\vobject_name\x18\x01 \x01(\tR\n" +
+	"objectName\x12\x16\n" +
+	"\x06method\x18\x02 \x01(\tR\x06method\x12\x1c\n" +
+	"\tprincipal\x18\x03 \x01(\tR\tprincipal\x12\x1a\n" +
+	"\brequests\x18\x04 \x01(\x03R\brequests\x12\x16\n" +
+	"\x06errors\x18\x05 \x01(\x03R\x06errors\x12\x1d\n" +
+	"\n" +
+	"error_rate\x18\x06 \x01(\x01R\terrorRate\x12&\n" +
+	"\x0fmean_latency_ms\x18\a \x01(\x01R\rmeanLatencyMs\x12$\n" +
+	"\x0emax_latency_ms\x18\b \x01(\x01R\fmaxLatencyMs\x12%\n" +
+	"\x0eresult_records\x18\t \x01(\x03R\rresultRecords\x12.\n" +
+	"\x13mean_result_records\x18\n" +
+	" \x01(\x01R\x11meanResultRecords\x12 \n" +
+	"\flast_seen_at\x18\v \x01(\tR\n" +
+	"lastSeenAt\"~\n" +
+	"\x10GetUsageResponse\x12-\n" +
+	"\x05stats\x18\x01 \x03(\v2\x17.registry.v1.UsageStatsR\x05stats\x12%\n" +
+	"\x0eunused_objects\x18\x02 \x03(\tR\runusedObjects\x12\x14\n" +
+	"\x05since\x18\x03 \x01(\tR\x05since2k\n" +
+	"\fUsageService\x12[\n" +
+	"\bGetUsage\x12\x1c.registry.v1.GetUsageRequest\x1a\x1d.registry.v1.GetUsageResponse\"\x12\x82\xd3\xe4\x93\x02\f\x12\n" +
+	"/api/usageB\xb1\x01\n" +
+	"\x0fcom.registry.v1B\x11UsageServiceProtoP\x01Z>github.com/atlekbai/schema_registry/gen/registry/v1;registryv1\xa2\x02\x03RXX\xaa\x02\vRegistry.V1\xca\x02\vRegistry\\V1\xe2\x02\x17Registry\\V1\\GPBMetadata\xea\x02\fRegistry::V1b\x06proto3"
+
+var (
+	file_registry_v1_usage_service_proto_rawDescOnce sync.Once
+	file_registry_v1_usage_service_proto_rawDescData []byte
+)
+
+func file_registry_v1_usage_service_proto_rawDescGZIP() []byte {
+	file_registry_v1_usage_service_proto_rawDescOnce.Do(func() {
+		file_registry_v1_usage_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_registry_v1_usage_service_proto_rawDesc), len(file_registry_v1_usage_service_proto_rawDesc)))
+	})
+	return file_registry_v1_usage_service_proto_rawDescData
+}
+
+var file_registry_v1_usage_service_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_registry_v1_usage_service_proto_goTypes = []any{
+	(*GetUsageRequest)(nil),  // 0: registry.v1.GetUsageRequest
+	(*UsageStats)(nil),       // 1: registry.v1.UsageStats
+	(*GetUsageResponse)(nil), // 2: registry.v1.GetUsageResponse
+}
+var file_registry_v1_usage_service_proto_depIdxs = []int32{
+	1, // 0: registry.v1.GetUsageResponse.stats:type_name -> registry.v1.UsageStats
+	0, // 1: registry.v1.UsageService.GetUsage:input_type -> registry.v1.GetUsageRequest
+	2, // 2: registry.v1.UsageService.GetUsage:output_type -> registry.v1.GetUsageResponse
+	2, // [2:3] is the sub-list for method output_type
+	1, // [1:2] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_registry_v1_usage_service_proto_init() }
+func file_registry_v1_usage_service_proto_init() {
+	if File_registry_v1_usage_service_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_registry_v1_usage_service_proto_rawDesc), len(file_registry_v1_usage_service_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_registry_v1_usage_service_proto_goTypes,
+		DependencyIndexes: file_registry_v1_usage_service_proto_depIdxs,
+		MessageInfos:      file_registry_v1_usage_service_proto_msgTypes,
+	}.Build()
+	File_registry_v1_usage_service_proto = out.File
+	file_registry_v1_usage_service_proto_goTypes = nil
+	file_registry_v1_usage_service_proto_depIdxs = nil
+}
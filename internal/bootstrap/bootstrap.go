@@ -0,0 +1,237 @@
+// Package bootstrap seeds the catalog entries of the standard objects
+// (organizations, departments, employees, ...) from the declarative
+// definition in standard.json. Everything else assumes these entries exist
+// and match the core tables, so the seed can be applied at startup
+// (SEED_STANDARD_OBJECTS) or through AdminService to create missing ones and
+// repair drifted ones.
+package bootstrap
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/atlekbai/schema_registry/internal/db"
+)
+
+// seedLockKey serializes seed runs across server instances.
+const seedLockKey int64 = 0x7365_6564 // "seed"
+
+//go:embed standard.json
+var standardJSON []byte
+
+// Definition is a versioned set of standard objects. Bump Version whenever
+// the objects change, so databases seeded with an older version are
+// reconciled on the next run.
+type Definition struct {
+	Version int      `json:"version"`
+	Objects []Object `json:"objects"`
+}
+
+// Object is a standard object backed by StorageSchema.StorageTable.
+// DefaultOrder and DisplayTemplate are only set when the object is created.
+type Object struct {
+	APIName              string  `json:"api_name"`
+	Category             string  `json:"category"`
+	Title                string  `json:"title"`
+	PluralTitle          string  `json:"plural_title"`
+	Description          string  `json:"description"`
+	StorageSchema        string  `json:"storage_schema"`
+	StorageTable         string  `json:"storage_table"`
+	SupportsCustomFields bool    `json:"supports_custom_fields"`
+	DefaultOrder         string  `json:"default_order,omitempty"`
+	DisplayTemplate      string  `json:"display_template,omitempty"`
+	Fields               []Field `json:"fields,omitempty"`
+}
+
+// Field is a standard field stored in StorageColumn. Lookup names the target
+// object of a LOOKUP.
+type Field struct {
+	APIName             string          `json:"api_name"`
+	Title               string          `json:"title"`
+	Description         string          `json:"description"`
+	Type                string          `json:"type"`
+	TypeConfig          json.RawMessage `json:"type_config,omitempty"`
+	Required            bool            `json:"required,omitempty"`
+	Unique              bool            `json:"unique,omitempty"`
+	ExternalID          bool            `json:"external_id,omitempty"`
+	StorageColumn       string          `json:"storage_column"`
+	Lookup              string          `json:"lookup,omitempty"`
+	HierarchyPathColumn string          `json:"hierarchy_path_column,omitempty"`
+}
+
+// Standard returns the built-in definition of the standard objects.
+func Standard() Definition {
+	var def Definition
+	if err := json.Unmarshal(standardJSON, &def); err != nil {
+		panic(fmt.Sprintf("bootstrap: invalid standard.json: %v", err))
+	}
+	return def
+}
+
+// Result summarizes a seed run.
+type Result struct {
+	Version int
+	// Applied is false when the database already had this version (or a
+	// later one) and the run was not forced.
+	Applied        bool
+	ObjectsCreated int
+	ObjectsUpdated int
+	FieldsCreated  int
+	FieldsUpdated  int
+}
+
+// Apply reconciles the catalog with def in one transaction. Missing objects
+// and fields are created; existing ones get their storage mapping, type and
+// constraints reset to the definition, while titles, descriptions and list
+// settings (which admins may edit) are left alone. Fields the definition
+// does not list are kept. Unless force is set, a database that already
+// recorded def.Version (or later) in metadata.seed_versions is left as is.
+func Apply(ctx context.Context, pool *pgxpool.Pool, def Definition, force bool) (Result, error) {
+	res := Result{Version: def.Version}
+	tx, err := db.Begin(ctx, pool)
+	if err != nil {
+		return res, fmt.Errorf("begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, seedLockKey); err != nil {
+		return res, fmt.Errorf("lock: %w", err)
+	}
+	if !force {
+		var seeded bool
+		if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM metadata.seed_versions WHERE "version" >= $1)`, def.Version).Scan(&seeded); err != nil {
+			return res, fmt.Errorf("read seed version: %w", err)
+		}
+		if seeded {
+			return res, nil
+		}
+	}
+
+	names := make([]string, len(def.Objects))
+	for i, obj := range def.Objects {
+		names[i] = obj.APIName
+	}
+	var custom string
+	err = tx.QueryRow(ctx, `SELECT "api_name" FROM metadata.objects WHERE "api_name" = ANY($1) AND NOT "is_standard" LIMIT 1`, names).Scan(&custom)
+	if err == nil {
+		return res, fmt.Errorf("object %q exists as a custom object; rename it before seeding", custom)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return res, fmt.Errorf("check objects: %w", err)
+	}
+
+	// Objects first, so LOOKUPs can reference any of them.
+	for _, obj := range def.Objects {
+		created, changed, err := upsertObject(ctx, tx, obj)
+		if err != nil {
+			return res, fmt.Errorf("object %q: %w", obj.APIName, err)
+		}
+		res.ObjectsCreated += count(created)
+		res.ObjectsUpdated += count(changed && !created)
+	}
+	for _, obj := range def.Objects {
+		for _, f := range obj.Fields {
+			created, changed, err := upsertField(ctx, tx, obj.APIName, f)
+			if err != nil {
+				return res, fmt.Errorf("field %s.%s: %w", obj.APIName, f.APIName, err)
+			}
+			res.FieldsCreated += count(created)
+			res.FieldsUpdated += count(changed && !created)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO metadata.seed_versions ("version") VALUES ($1)
+		ON CONFLICT ("version") DO UPDATE SET "applied_at" = now()
+	`, def.Version); err != nil {
+		return res, fmt.Errorf("record seed version: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return res, fmt.Errorf("commit: %w", err)
+	}
+	res.Applied = true
+	return res, nil
+}
+
+// upsertObject creates obj or resets its storage mapping. changed is false
+// when the row already matched.
+func upsertObject(ctx context.Context, tx pgx.Tx, obj Object) (created, changed bool, err error) {
+	err = tx.QueryRow(ctx, `
+		INSERT INTO metadata.objects AS o (
+			"category_id", "api_name", "title", "plural_title", "description", "is_standard",
+			"storage_schema", "storage_table", "supports_custom_fields", "default_order", "display_template"
+		) VALUES (
+			(SELECT "id" FROM metadata.object_categories WHERE "title" = $1 ORDER BY "created_at" LIMIT 1),
+			$2, $3, $4, NULLIF($5, ''), TRUE, $6, $7, $8, NULLIF($9, ''), NULLIF($10, '')
+		)
+		ON CONFLICT ("api_name") DO UPDATE SET
+			"storage_schema" = EXCLUDED."storage_schema",
+			"storage_table" = EXCLUDED."storage_table",
+			"supports_custom_fields" = EXCLUDED."supports_custom_fields",
+			"updated_at" = now()
+		WHERE (o."storage_schema", o."storage_table", o."supports_custom_fields")
+			IS DISTINCT FROM (EXCLUDED."storage_schema", EXCLUDED."storage_table", EXCLUDED."supports_custom_fields")
+		RETURNING (xmax = 0)
+	`, obj.Category, obj.APIName, obj.Title, obj.PluralTitle, obj.Description,
+		obj.StorageSchema, obj.StorageTable, obj.SupportsCustomFields, obj.DefaultOrder, obj.DisplayTemplate,
+	).Scan(&created)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, false, nil
+	}
+	return created, err == nil, err
+}
+
+// upsertField creates f on the object or resets its type, constraints and
+// storage.
+func upsertField(ctx context.Context, tx pgx.Tx, object string, f Field) (created, changed bool, err error) {
+	typeConfig := string(f.TypeConfig)
+	if typeConfig == "" {
+		typeConfig = "{}"
+	}
+	err = tx.QueryRow(ctx, `
+		INSERT INTO metadata.fields AS f (
+			"object_id", "api_name", "title", "description", "type", "type_config",
+			"is_required", "is_unique", "is_external_id", "is_standard", "storage_column",
+			"lookup_object_id", "hierarchy_path_column"
+		) VALUES (
+			(SELECT "id" FROM metadata.objects WHERE "api_name" = $1),
+			$2, $3, NULLIF($4, ''), $5, $6::jsonb, $7, $8, $9, TRUE, $10,
+			(SELECT "id" FROM metadata.objects WHERE "api_name" = NULLIF($11, '')), NULLIF($12, '')
+		)
+		ON CONFLICT ("object_id", "api_name") DO UPDATE SET
+			"type" = EXCLUDED."type",
+			"type_config" = EXCLUDED."type_config",
+			"is_required" = EXCLUDED."is_required",
+			"is_unique" = EXCLUDED."is_unique",
+			"is_external_id" = EXCLUDED."is_external_id",
+			"is_standard" = TRUE,
+			"storage_column" = EXCLUDED."storage_column",
+			"lookup_object_id" = EXCLUDED."lookup_object_id",
+			"hierarchy_path_column" = EXCLUDED."hierarchy_path_column",
+			"updated_at" = now()
+		WHERE (f."type", f."type_config", f."is_required", f."is_unique", f."is_external_id",
+			f."is_standard", f."storage_column", f."lookup_object_id", f."hierarchy_path_column")
+			IS DISTINCT FROM (EXCLUDED."type", EXCLUDED."type_config", EXCLUDED."is_required", EXCLUDED."is_unique",
+			EXCLUDED."is_external_id", TRUE, EXCLUDED."storage_column", EXCLUDED."lookup_object_id", EXCLUDED."hierarchy_path_column")
+		RETURNING (xmax = 0)
+	`, object, f.APIName, f.Title, f.Description, f.Type, typeConfig,
+		f.Required, f.Unique, f.ExternalID, f.StorageColumn, f.Lookup, f.HierarchyPathColumn,
+	).Scan(&created)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, false, nil
+	}
+	return created, err == nil, err
+}
+
+func count(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
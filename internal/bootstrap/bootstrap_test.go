@@ -0,0 +1,43 @@
+package bootstrap
+
+import "testing"
+
+// TestStandardDefinition checks the embedded definition is self-consistent:
+// every field is stored in a column and every LOOKUP targets a seeded object.
+func TestStandardDefinition(t *testing.T) {
+	def := Standard()
+	if def.Version < 1 {
+		t.Fatalf("version = %d, want >= 1", def.Version)
+	}
+	objects := make(map[string]bool)
+	for _, obj := range def.Objects {
+		if objects[obj.APIName] {
+			t.Errorf("object %q defined twice", obj.APIName)
+		}
+		objects[obj.APIName] = true
+		if obj.StorageSchema == "" || obj.StorageTable == "" || obj.Category == "" {
+			t.Errorf("object %q: storage and category are required", obj.APIName)
+		}
+	}
+	for _, obj := range def.Objects {
+		fields := make(map[string]bool)
+		for _, f := range obj.Fields {
+			if fields[f.APIName] {
+				t.Errorf("field %s.%s defined twice", obj.APIName, f.APIName)
+			}
+			fields[f.APIName] = true
+			if f.StorageColumn == "" {
+				t.Errorf("field %s.%s has no storage column", obj.APIName, f.APIName)
+			}
+			if (f.Type == "LOOKUP") != (f.Lookup != "") {
+				t.Errorf("field %s.%s: lookup target must be set exactly for LOOKUP fields", obj.APIName, f.APIName)
+			}
+			if f.Lookup != "" && !objects[f.Lookup] {
+				t.Errorf("field %s.%s references %q, which is not seeded", obj.APIName, f.APIName, f.Lookup)
+			}
+			if f.ExternalID && !f.Unique {
+				t.Errorf("field %s.%s: external ids must be unique", obj.APIName, f.APIName)
+			}
+		}
+	}
+}
@@ -0,0 +1,229 @@
+// Package codegen renders typed API clients for registered objects from the
+// schema cache: one record type per object with its fields, and per-object
+// list/get/create/update/upsert/delete methods whose select, expand, order
+// and filter options only accept the object's fields. The clients call the
+// Connect JSON endpoints (POST /registry.v1.RegistryService/<Method>), which
+// take the whole request, filters included, as a JSON body.
+//
+// Output is deterministic for a given schema; SchemaHash identifies the
+// schema a client was generated from, so CI can regenerate when it changes.
+package codegen
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// Language is a client language.
+type Language string
+
+const (
+	TypeScript Language = "typescript"
+	Go         Language = "go"
+)
+
+// DefaultGoPackage names the package of generated Go clients.
+const DefaultGoPackage = "registryclient"
+
+// File is a generated client.
+type File struct {
+	Name       string // suggested file name
+	Source     string
+	SchemaHash string
+}
+
+// Options tune the output.
+type Options struct {
+	// GoPackage is the package clause of Go clients (DefaultGoPackage when empty).
+	GoPackage string
+}
+
+// Generate renders a client for objs in lang. Lookups to objects outside
+// objs are typed as untyped records when expanded.
+func Generate(lang Language, objs []*schema.ObjectDef, cache *schema.Cache, opts Options) (*File, error) {
+	models := buildModels(objs, cache)
+	hash := SchemaHash(objs, cache)
+	switch lang {
+	case TypeScript:
+		return &File{Name: "registry-client.ts", Source: typeScript(models, hash), SchemaHash: hash}, nil
+	case Go:
+		pkg := cmp.Or(opts.GoPackage, DefaultGoPackage)
+		if !isIdent(pkg) {
+			return nil, fmt.Errorf("invalid Go package name %q", pkg)
+		}
+		src, err := goSource(models, hash, pkg)
+		if err != nil {
+			return nil, err
+		}
+		return &File{Name: pkg + ".go", Source: src, SchemaHash: hash}, nil
+	default:
+		return nil, fmt.Errorf("unsupported language %q (typescript, go)", lang)
+	}
+}
+
+// SchemaHash returns a short hash of the parts of objs' definitions a client
+// depends on: object and field names, types, required flags, choice options
+// and lookup targets.
+func SchemaHash(objs []*schema.ObjectDef, cache *schema.Cache) string {
+	type fieldKey struct {
+		Name     string
+		Type     schema.FieldType
+		Required bool
+		Options  []string `json:",omitempty"`
+		Lookup   string   `json:",omitempty"`
+	}
+	type objectKey struct {
+		Name     string
+		Fields   []fieldKey
+		Display  bool `json:",omitempty"`
+		External bool `json:",omitempty"`
+	}
+
+	keys := make([]objectKey, 0, len(objs))
+	for _, m := range buildModels(objs, cache) {
+		k := objectKey{Name: m.def.APIName, Display: m.def.DisplayTemplate != "", External: m.hasExternalID}
+		for _, f := range m.fields {
+			k.Fields = append(k.Fields, fieldKey{Name: f.name, Type: f.def.Type, Required: f.required, Options: f.options, Lookup: f.lookupObject})
+		}
+		keys = append(keys, k)
+	}
+	data, _ := json.Marshal(keys)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// objectModel is an object as the generators see it.
+type objectModel struct {
+	def           *schema.ObjectDef
+	typeName      string // record type, e.g. Employee
+	clientName    string // accessor, e.g. Employees
+	fields        []fieldModel
+	hasExternalID bool
+}
+
+type fieldModel struct {
+	def          *schema.FieldDef
+	name         string // API name
+	goName       string
+	options      []string // CHOICE/MULTICHOICE option keys
+	system       bool     // id, created_at, updated_at, version
+	readOnly     bool     // system and FORMULA fields
+	required     bool
+	filterable   bool // usable in filters and order
+	lookupObject string
+	lookupType   string // record type of the target when generated, else ""
+}
+
+// buildModels sorts objs by API name and names their types, falling back to
+// the API name when a title does not make a usable or unique identifier.
+func buildModels(objs []*schema.ObjectDef, cache *schema.Cache) []objectModel {
+	sorted := slices.Clone(objs)
+	slices.SortFunc(sorted, func(a, b *schema.ObjectDef) int { return strings.Compare(a.APIName, b.APIName) })
+
+	models := make([]objectModel, len(sorted))
+	typeNames := make(map[string]bool)
+	byID := make(map[string]*objectModel)
+	for i, obj := range sorted {
+		typeName := pascal(obj.Title)
+		if typeName == "" || typeNames[typeName] || reservedTypeNames[typeName] {
+			typeName = pascal(obj.APIName) + "Record"
+		}
+		typeNames[typeName] = true
+		models[i] = objectModel{def: obj, typeName: typeName, clientName: pascal(obj.APIName)}
+		byID[obj.ID.String()] = &models[i]
+	}
+
+	for i := range models {
+		m := &models[i]
+		for _, name := range schema.SystemFieldNames() {
+			if fd := m.def.FieldsByAPIName[name]; fd != nil {
+				m.fields = append(m.fields, fieldModel{def: fd, name: name, goName: pascal(name), system: true, readOnly: true, filterable: true})
+			}
+		}
+		for j := range m.def.Fields {
+			fd := &m.def.Fields[j]
+			if schema.IsSystemField(fd.APIName) {
+				continue
+			}
+			f := fieldModel{
+				def:        fd,
+				name:       fd.APIName,
+				goName:     pascal(fd.APIName),
+				readOnly:   fd.Type == schema.FieldFormula,
+				required:   fd.IsRequired,
+				filterable: !fd.IsEncrypted() && !fd.IsJSON(),
+			}
+			for _, opt := range fd.ChoiceOptions("") {
+				f.options = append(f.options, opt.Value)
+			}
+			if fd.Type == schema.FieldLookup && fd.LookupObjectID != nil {
+				if target := byID[fd.LookupObjectID.String()]; target != nil {
+					f.lookupObject, f.lookupType = target.def.APIName, target.typeName
+				} else if target := cache.GetByID(*fd.LookupObjectID); target != nil {
+					f.lookupObject = target.APIName
+				}
+			}
+			m.hasExternalID = m.hasExternalID || fd.IsExternalID
+			m.fields = append(m.fields, f)
+		}
+	}
+	return models
+}
+
+// reservedTypeNames are the names of the generated runtime types.
+var reservedTypeNames = map[string]bool{
+	"Client": true, "ClientOptions": true, "Error": true, "Filter": true, "GetOptions": true,
+	"ListOptions": true, "ListResult": true, "Lookup": true, "New": true, "ObjectClient": true,
+	"RegistryClient": true, "RegistryError": true, "SchemaHash": true, "Transport": true,
+	"TypeaheadMatch": true, "VersionedWriteOptions": true, "WriteOptions": true,
+}
+
+// pascal turns "first_name", "Cost center" or "FULL_TIME" into "FirstName",
+// "CostCenter" or "FullTime". It returns "" when the result would not start
+// with an ASCII letter or s has other letters or digits.
+func pascal(s string) string {
+	if strings.ToUpper(s) == s {
+		s = strings.ToLower(s)
+	}
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if upper {
+				r = unicode.ToUpper(r)
+			}
+			b.WriteRune(r)
+			upper = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			return ""
+		default:
+			upper = true
+		}
+	}
+	out := b.String()
+	if out == "" || !unicode.IsLetter(rune(out[0])) {
+		return ""
+	}
+	return out
+}
+
+func isIdent(s string) bool {
+	if s == "" || !unicode.IsLetter(rune(s[0])) {
+		return false
+	}
+	for _, r := range s {
+		if r >= unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_') {
+			return false
+		}
+	}
+	return true
+}
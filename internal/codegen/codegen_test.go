@@ -0,0 +1,161 @@
+package codegen
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+var (
+	empObjID  = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	deptObjID = uuid.MustParse("00000000-0000-0000-0000-000000000002")
+)
+
+func testObjects() (*schema.Cache, []*schema.ObjectDef) {
+	dept := &schema.ObjectDef{ID: deptObjID, APIName: "departments", Title: "Department", FieldsByAPIName: map[string]*schema.FieldDef{}}
+	dept.Fields = []schema.FieldDef{
+		{APIName: "title", Title: "Title", Type: schema.FieldText, IsRequired: true},
+	}
+	emp := &schema.ObjectDef{ID: empObjID, APIName: "employees", Title: "Employee", FieldsByAPIName: map[string]*schema.FieldDef{}}
+	emp.Fields = []schema.FieldDef{
+		{APIName: "employee_number", Title: "Employee Number", Type: schema.FieldText, IsRequired: true, IsExternalID: true},
+		{APIName: "employment_type", Title: "Employment Type", Type: schema.FieldChoice, TypeConfig: []byte(`{"options":["FULL_TIME","PART_TIME"]}`)},
+		{APIName: "skills", Title: "Skills", Type: schema.FieldMultichoice, TypeConfig: []byte(`{"options":["go","sql"]}`)},
+		{APIName: "salary", Title: "Salary", Type: schema.FieldCurrency},
+		{APIName: "manager", Title: "Manager", Type: schema.FieldLookup, LookupObjectID: new(empObjID)},
+		{APIName: "department", Title: "Department", Type: schema.FieldLookup, LookupObjectID: new(deptObjID)},
+		{APIName: "national_id", Title: "National ID", Type: schema.FieldEncrypted},
+		{APIName: "tenure", Title: "Tenure", Type: schema.FieldFormula},
+		{APIName: "preferences", Title: "Preferences", Type: schema.FieldJSON},
+	}
+	for _, obj := range []*schema.ObjectDef{dept, emp} {
+		for i := range obj.Fields {
+			obj.FieldsByAPIName[obj.Fields[i].APIName] = &obj.Fields[i]
+		}
+	}
+	return schema.NewCacheFromObjects(dept, emp), []*schema.ObjectDef{emp, dept}
+}
+
+func TestTypeScript(t *testing.T) {
+	cache, objs := testObjects()
+	f, err := Generate(TypeScript, objs, cache, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"// Schema hash: " + f.SchemaHash,
+		"export interface Employee {\n  id: string;\n",
+		`  employment_type?: "FULL_TIME" | "PART_TIME" | null;`,
+		`  skills?: ("go" | "sql")[] | null;`,
+		"  salary?: number | null;",
+		"  manager?: string | Employee | null;",
+		"  department?: string | Department | null;",
+		"  tenure?: unknown | null;",
+		"  preferences?: unknown | null;",
+		"export interface EmployeeInput {\n  employee_number: string;\n",
+		`export type EmployeeExpand = "manager" | "department";`,
+		`readonly employees: ObjectClient<Employee, EmployeeInput, EmployeeField, EmployeeFilterField, EmployeeExpand>;`,
+		`this.departments = new ObjectClient(transport, "departments");`,
+	} {
+		if !strings.Contains(f.Source, want) {
+			t.Errorf("missing %q", want)
+		}
+	}
+	input := f.Source[strings.Index(f.Source, "export interface EmployeeInput"):]
+	input = input[:strings.Index(input, "}")]
+	for _, absent := range []string{"id:", "version", "tenure"} {
+		if strings.Contains(input, absent) {
+			t.Errorf("EmployeeInput should not contain %q", absent)
+		}
+	}
+	filter := f.Source[strings.Index(f.Source, "export type EmployeeFilterField"):]
+	filter = filter[:strings.Index(filter, ";")]
+	if strings.Contains(filter, "national_id") {
+		t.Error("encrypted field should not be filterable")
+	}
+	if strings.Contains(filter, "preferences") {
+		t.Error("JSON field should not be filterable")
+	}
+}
+
+func TestGo(t *testing.T) {
+	cache, objs := testObjects()
+	f, err := Generate(Go, objs, cache, Options{GoPackage: "hrclient"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Name != "hrclient.go" {
+		t.Errorf("name = %q", f.Name)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, f.Name, f.Source, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ast.IsGenerated(file) {
+		t.Error("missing generated-code header")
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := conf.Check("hrclient", fset, []*ast.File{file}, nil); err != nil {
+		t.Fatalf("generated client does not type-check: %v", err)
+	}
+
+	for _, want := range []string{
+		`EmployeeEmploymentTypeFullTime EmployeeEmploymentType = "FULL_TIME"`,
+		"EmploymentType *EmployeeEmploymentType `json:\"employment_type,omitempty\"`",
+		"Manager        *Lookup[Employee]",
+		"Department     *Lookup[Department]",
+		"Version        int64",
+		`EmployeeFieldNationalId     EmployeeField = "national_id"`,
+		"Employees   *ObjectClient[Employee, EmployeeInput, EmployeeField]",
+	} {
+		if !strings.Contains(f.Source, want) {
+			t.Errorf("missing %q", want)
+		}
+	}
+}
+
+func TestSchemaHash(t *testing.T) {
+	cache, objs := testObjects()
+	before := SchemaHash(objs, cache)
+	if again := SchemaHash([]*schema.ObjectDef{objs[1], objs[0]}, cache); again != before {
+		t.Errorf("hash depends on object order: %s != %s", again, before)
+	}
+	objs[1].Fields[0].IsRequired = false
+	if after := SchemaHash(objs, cache); after == before {
+		t.Error("hash did not change with the schema")
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	cache, objs := testObjects()
+	if _, err := Generate("java", objs, cache, Options{}); err == nil || !strings.Contains(err.Error(), "unsupported language") {
+		t.Errorf("expected unsupported language, got %v", err)
+	}
+	if _, err := Generate(Go, objs, cache, Options{GoPackage: "my-client"}); err == nil {
+		t.Error("expected invalid package error")
+	}
+}
+
+func TestPascal(t *testing.T) {
+	for in, want := range map[string]string{
+		"first_name":  "FirstName",
+		"Cost center": "CostCenter",
+		"FULL_TIME":   "FullTime",
+		"2fa":         "",
+		"Ärzte":       "",
+	} {
+		if got := pascal(in); got != want {
+			t.Errorf("pascal(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
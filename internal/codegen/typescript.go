@@ -0,0 +1,303 @@
+package codegen
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// tsRuntime is the object-independent part of TypeScript clients.
+const tsRuntime = `export class RegistryError extends Error {
+  constructor(
+    readonly status: number,
+    readonly code: string,
+    message: string,
+    readonly details?: unknown[],
+  ) {
+    super(message);
+    this.name = "RegistryError";
+  }
+}
+
+/** Filter values use the "op.value" syntax, e.g. "eq.active" or "gte.2024-01-01". */
+export type Filter = string;
+
+export interface ListOptions<F extends string, S extends string, E extends string> {
+  select?: F[];
+  expand?: E[];
+  order?: S | ` + "`${S}.desc`" + `;
+  limit?: number;
+  cursor?: string;
+  filters?: Partial<Record<S, Filter>>;
+  snapshot?: boolean;
+}
+
+export interface ListResult<T> {
+  totalCount: number;
+  nextCursor?: string;
+  results: T[];
+}
+
+export interface GetOptions<F extends string, E extends string> {
+  select?: F[];
+  expand?: E[];
+}
+
+export interface WriteOptions {
+  dryRun?: boolean;
+}
+
+export interface VersionedWriteOptions extends WriteOptions {
+  /** Fail with code "aborted" unless the record is at this version. */
+  expectedVersion?: number;
+}
+
+export interface TypeaheadMatch {
+  id: string;
+  display: string;
+}
+
+export interface ClientOptions {
+  /** Extra headers sent with every request, e.g. Authorization. */
+  headers?: Record<string, string>;
+  fetch?: typeof fetch;
+}
+
+class Transport {
+  private readonly fetch: typeof fetch;
+
+  constructor(
+    private readonly baseUrl: string,
+    private readonly options: ClientOptions,
+  ) {
+    this.fetch = options.fetch ?? globalThis.fetch.bind(globalThis);
+  }
+
+  async call<T>(method: string, body: unknown): Promise<T> {
+    const res = await this.fetch(` + "`${this.baseUrl.replace(/\\/+$/, \"\")}/registry.v1.RegistryService/${method}`" + `, {
+      method: "POST",
+      headers: { "Content-Type": "application/json", ...this.options.headers },
+      body: JSON.stringify(body),
+    });
+    const data = await res.json().catch(() => ({}));
+    if (!res.ok) {
+      throw new RegistryError(res.status, data.code ?? "unknown", data.message ?? res.statusText, data.details);
+    }
+    return data as T;
+  }
+}
+
+const csv = (names?: string[]) => (names?.length ? names.join(",") : undefined);
+
+export class ObjectClient<T, I, F extends string, S extends string, E extends string> {
+  constructor(
+    private readonly transport: Transport,
+    readonly objectName: string,
+  ) {}
+
+  async list(opts: ListOptions<F, S, E> = {}): Promise<ListResult<T>> {
+    const res = await this.transport.call<{ totalCount?: string; nextCursor?: string; results?: T[] }>("List", {
+      objectName: this.objectName,
+      select: csv(opts.select),
+      expand: csv(opts.expand),
+      order: opts.order,
+      limit: opts.limit,
+      cursor: opts.cursor,
+      filters: opts.filters,
+      snapshot: opts.snapshot,
+    });
+    return { totalCount: Number(res.totalCount ?? 0), nextCursor: res.nextCursor, results: res.results ?? [] };
+  }
+
+  async get(id: string, opts: GetOptions<F, E> = {}): Promise<T> {
+    const res = await this.transport.call<{ record: T }>("Get", {
+      objectName: this.objectName,
+      id,
+      select: csv(opts.select),
+      expand: csv(opts.expand),
+    });
+    return res.record;
+  }
+
+  async create(data: I, opts: WriteOptions = {}): Promise<T> {
+    const res = await this.transport.call<{ record: T }>("Create", { objectName: this.objectName, data, dryRun: opts.dryRun });
+    return res.record;
+  }
+
+  async update(id: string, data: Partial<I>, opts: VersionedWriteOptions = {}): Promise<T> {
+    const res = await this.transport.call<{ record: T }>("Update", {
+      objectName: this.objectName,
+      id,
+      data,
+      expectedVersion: opts.expectedVersion,
+      dryRun: opts.dryRun,
+    });
+    return res.record;
+  }
+
+  /** Creates or updates the record whose externalIdField matches data's value. */
+  async upsert(externalIdField: F, data: Partial<I>, opts: WriteOptions = {}): Promise<{ record: T; created: boolean }> {
+    const res = await this.transport.call<{ record: T; created?: boolean }>("Upsert", {
+      objectName: this.objectName,
+      externalIdField,
+      data,
+      dryRun: opts.dryRun,
+    });
+    return { record: res.record, created: res.created ?? false };
+  }
+
+  async delete(id: string, opts: VersionedWriteOptions = {}): Promise<T> {
+    const res = await this.transport.call<{ record: T }>("Delete", {
+      objectName: this.objectName,
+      id,
+      expectedVersion: opts.expectedVersion,
+      dryRun: opts.dryRun,
+    });
+    return res.record;
+  }
+
+  /** Matches records by their display template; objects without one return no matches. */
+  async typeahead(q: string, limit?: number): Promise<TypeaheadMatch[]> {
+    const res = await this.transport.call<{ matches?: TypeaheadMatch[] }>("Typeahead", { objectName: this.objectName, q, limit });
+    return res.matches ?? [];
+  }
+}
+`
+
+// typeScript renders a TypeScript client.
+func typeScript(models []objectModel, hash string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Code generated by schema-registry. DO NOT EDIT.\n// Schema hash: %s\n\n", hash)
+	b.WriteString(tsRuntime)
+
+	for _, m := range models {
+		t := m.typeName
+		fmt.Fprintf(&b, "\n/** %s (%s). */\nexport interface %s {\n", tsComment(m.def.Title), m.def.APIName, t)
+		for _, f := range m.fields {
+			opt := "?"
+			if f.system {
+				opt = ""
+			}
+			fmt.Fprintf(&b, "  %s%s: %s;\n", tsKey(f.name), opt, tsType(f))
+		}
+		b.WriteString("}\n")
+
+		fmt.Fprintf(&b, "\nexport interface %sInput {\n", t)
+		for _, f := range m.fields {
+			if f.readOnly {
+				continue
+			}
+			opt := "?"
+			if f.required {
+				opt = ""
+			}
+			fmt.Fprintf(&b, "  %s%s: %s;\n", tsKey(f.name), opt, tsInputType(f))
+		}
+		b.WriteString("}\n")
+
+		var all, filterable, expandable []string
+		for _, f := range m.fields {
+			all = append(all, strconv.Quote(f.name))
+			if f.filterable {
+				filterable = append(filterable, strconv.Quote(f.name))
+			}
+			if f.def.Type == schema.FieldLookup {
+				expandable = append(expandable, strconv.Quote(f.name))
+			}
+		}
+		fmt.Fprintf(&b, "\nexport type %sField = %s;\n", t, tsUnion(all))
+		fmt.Fprintf(&b, "export type %sFilterField = %s;\n", t, tsUnion(filterable))
+		fmt.Fprintf(&b, "export type %sExpand = %s;\n", t, tsUnion(expandable))
+	}
+
+	b.WriteString("\nexport class RegistryClient {\n")
+	for _, m := range models {
+		t := m.typeName
+		fmt.Fprintf(&b, "  readonly %s: ObjectClient<%s, %sInput, %sField, %sFilterField, %sExpand>;\n",
+			lowerFirst(m.clientName), t, t, t, t, t)
+	}
+	b.WriteString("\n  constructor(baseUrl: string, options: ClientOptions = {}) {\n")
+	b.WriteString("    const transport = new Transport(baseUrl, options);\n")
+	for _, m := range models {
+		fmt.Fprintf(&b, "    this.%s = new ObjectClient(transport, %s);\n", lowerFirst(m.clientName), strconv.Quote(m.def.APIName))
+	}
+	b.WriteString("  }\n}\n")
+	return b.String()
+}
+
+// tsType is the type of a field in records read from the API.
+func tsType(f fieldModel) string {
+	base := tsBaseType(f)
+	if f.def.Type == schema.FieldLookup {
+		base = "string | " + cmp.Or(f.lookupType, "Record<string, unknown>")
+	}
+	if f.system {
+		return base
+	}
+	return base + " | null"
+}
+
+// tsInputType is the type of a field in writes, where lookups take ids.
+func tsInputType(f fieldModel) string {
+	if f.required {
+		return tsBaseType(f)
+	}
+	return tsBaseType(f) + " | null"
+}
+
+func tsBaseType(f fieldModel) string {
+	switch f.def.Type {
+	case schema.FieldNumber, schema.FieldCurrency, schema.FieldPercentage:
+		return "number"
+	case schema.FieldBoolean:
+		return "boolean"
+	case schema.FieldChoice:
+		return tsOptions(f.options)
+	case schema.FieldMultichoice:
+		return "(" + tsOptions(f.options) + ")[]"
+	case schema.FieldFormula, schema.FieldJSON:
+		return "unknown"
+	default:
+		return "string"
+	}
+}
+
+func tsOptions(options []string) string {
+	if len(options) == 0 {
+		return "string"
+	}
+	quoted := make([]string, len(options))
+	for i, o := range options {
+		quoted[i] = strconv.Quote(o)
+	}
+	return strings.Join(quoted, " | ")
+}
+
+func tsUnion(members []string) string {
+	if len(members) == 0 {
+		return "never"
+	}
+	return strings.Join(members, " | ")
+}
+
+func tsKey(name string) string {
+	if isIdent(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+// tsComment keeps user text from closing the doc comment.
+func tsComment(s string) string {
+	return strings.ReplaceAll(s, "*/", "* /")
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
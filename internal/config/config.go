@@ -1,13 +1,154 @@
 package config
 
 import (
+	"encoding/base64"
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atlekbai/schema_registry/internal/schema"
 )
 
 type Config struct {
 	DatabaseURL string
 	Port        string
+
+	// ExpandStrategy selects how expands are fetched: "auto" (default), "lateral" or "batch".
+	ExpandStrategy string
+
+	// FieldEncryptionKey is the AES-256 key for ENCRYPTED fields. Nil when unset,
+	// in which case writes to ENCRYPTED fields are rejected.
+	FieldEncryptionKey []byte
+	// CursorSigningKey is the HMAC key pagination cursors are signed with.
+	// Nil when unset, in which case each process signs with a random key and
+	// rejects the cursors of other instances.
+	CursorSigningKey []byte
+
+	// APINameMaxLength caps object and field api_names (0 means the default, 63).
+	APINameMaxLength int
+	// ReservedAPINames are extra words rejected as api_names, on top of HRQL
+	// keywords and system fields.
+	ReservedAPINames []string
+
+	// SlowQueryThreshold is the duration above which queries are logged to
+	// diagnostics.slow_queries (0 disables the slow query log).
+	SlowQueryThreshold time.Duration
+	// SlowQueryExplainSample is the fraction (0..1) of slow read-only queries
+	// re-run under EXPLAIN (ANALYZE, BUFFERS) to capture their plan.
+	SlowQueryExplainSample float64
+
+	// StatementCacheSize is how many prepared statements each connection
+	// keeps (default 512; 0 disables the cache, e.g. behind PgBouncer in
+	// transaction mode).
+	StatementCacheSize int
+
+	// MigrateOnStart is what the server does with the embedded migrations at
+	// startup: "off" (default), "verify" (refuse to start with pending ones)
+	// or "auto" (apply pending ones).
+	MigrateOnStart string
+	// MigrationsBaseline marks migrations up to this version as applied on a
+	// database migrated before the history table existed (0 disables).
+	MigrationsBaseline int64
+
+	// ReadOnly starts the server in read-only maintenance mode (writes fail
+	// with UNAVAILABLE); ReadOnlyReason is shown to rejected clients. The mode
+	// can be switched at runtime through AdminService.
+	ReadOnly       bool
+	ReadOnlyReason string
+
+	// SnapshotTTL is how long a snapshot List keeps its database snapshot;
+	// SnapshotMax caps the snapshots held at once, each holding a pool
+	// connection (0 disables snapshot Lists); db.NewSnapshots lowers it to a
+	// quarter of the pool's connections.
+	SnapshotTTL time.Duration
+	SnapshotMax int
+
+	// RetentionInterval is how often retention policies are enforced (0
+	// disables the scheduler; RunRetention still works). RetentionBatchSize
+	// caps the records purged per transaction.
+	RetentionInterval  time.Duration
+	RetentionBatchSize int
+
+	// SchemaPollInterval is how often the schema watcher checks the catalog
+	// outbox for changes whose notification it missed (default 30s; 0 relies
+	// on notifications alone).
+	SchemaPollInterval time.Duration
+
+	// ListConcurrency and CountConcurrency cap the list and count queries
+	// run at once (0 sizes them from the connection pool); queries beyond
+	// the cap wait up to QueryQueueTimeout for a slot before failing with
+	// RESOURCE_EXHAUSTED.
+	ListConcurrency   int
+	CountConcurrency  int
+	QueryQueueTimeout time.Duration
+
+	// ExpandColumnBudget caps the columns the expands of a read may add to
+	// each record; larger requests fail with INVALID_ARGUMENT (0 disables).
+	ExpandColumnBudget int
+
+	// ListLimits are the default and maximum page sizes of a List and the
+	// planner estimate at or below which a list's total count is exact
+	// (DEFAULT_PAGE_SIZE, MAX_PAGE_SIZE, EXACT_COUNT_THRESHOLD; defaults 50,
+	// 200 and 50000); objects may override them, within MAX_PAGE_SIZE.
+	ListLimits schema.ListLimits
+
+	// MaxCustomFields and MaxDataBytes are the default soft limits on each
+	// object's custom field count and record document size in bytes of JSON
+	// text (0 disables each); objects may override them.
+	MaxCustomFields int
+	MaxDataBytes    int
+
+	// HRQLMaxCost and HRQLMaxRows reject HRQL queries whose EXPLAIN estimate
+	// exceeds them, in planner cost units and rows (0 disables each).
+	// Callers with the hrql:unbounded permission can skip the check.
+	HRQLMaxCost float64
+	HRQLMaxRows int64
+	// HRQLStreamMaxRows caps the records one OrgService.ExecuteStream returns
+	// (0 means the default, 1,000,000).
+	HRQLStreamMaxRows int64
+
+	// CORSAllowedOrigins lists the origins browsers may call the API from,
+	// or "*" for any (empty disables CORS). CORSAllowedMethods and
+	// CORSAllowedHeaders are answered to preflights, which browsers may
+	// cache for CORSMaxAge; CORSAllowCredentials lets them send cookies and
+	// HTTP auth.
+	CORSAllowedOrigins   []string
+	CORSAllowedMethods   []string
+	CORSAllowedHeaders   []string
+	CORSAllowCredentials bool
+	CORSMaxAge           time.Duration
+
+	// SecurityHeaders adds nosniff, frame-denial, referrer and CSP headers
+	// to every response (default true).
+	SecurityHeaders bool
+
+	// AutoSearchIndexes creates a pg_trgm index for each field flagged
+	// is_searchable (default false: indexes are left to the DBA, guided by
+	// the admin search index report).
+	AutoSearchIndexes bool
+
+	// AutoSortIndexes creates the keyset pagination indexes of each document
+	// field flagged is_sortable (default false).
+	AutoSortIndexes bool
+
+	// UsageFlushInterval is how often API usage rollups are written to
+	// diagnostics.api_usage (0 disables usage tracking); buckets older than
+	// UsageRetention are pruned.
+	UsageFlushInterval time.Duration
+	UsageRetention     time.Duration
+
+	// SeedStandardObjects applies the standard object seed (internal/bootstrap)
+	// at startup, after migrations (default false; AdminService can apply it
+	// on demand).
+	SeedStandardObjects bool
+
+	// MetadataChangeApproval holds MetadataService mutations as change
+	// requests until a reviewer approves them through ReviewService (default
+	// false).
+	MetadataChangeApproval bool
 }
 
 func Load() (*Config, error) {
@@ -21,12 +162,409 @@ func Load() (*Config, error) {
 		port = "8080"
 	}
 
+	expandStrategy := os.Getenv("EXPAND_STRATEGY")
+	if expandStrategy == "" {
+		expandStrategy = "auto"
+	}
+
+	key, err := loadKey("FIELD_ENCRYPTION_KEY", "field encryption key")
+	if err != nil {
+		return nil, err
+	}
+	cursorKey, err := loadKey("CURSOR_SIGNING_KEY", "cursor signing key")
+	if err != nil {
+		return nil, err
+	}
+	if cursorKey != nil && len(cursorKey) < 32 {
+		return nil, fmt.Errorf("cursor signing key: expected at least 32 bytes, got %d", len(cursorKey))
+	}
+
+	var maxLen int
+	if v := os.Getenv("API_NAME_MAX_LENGTH"); v != "" {
+		maxLen, err = strconv.Atoi(v)
+		if err != nil || maxLen < 1 {
+			return nil, fmt.Errorf("API_NAME_MAX_LENGTH: expected a positive integer, got %q", v)
+		}
+	}
+
+	reserved := splitList(os.Getenv("RESERVED_API_NAMES"))
+
+	var slowThreshold time.Duration
+	if v := os.Getenv("SLOW_QUERY_THRESHOLD"); v != "" {
+		slowThreshold, err = time.ParseDuration(v)
+		if err != nil || slowThreshold < 0 {
+			return nil, fmt.Errorf("SLOW_QUERY_THRESHOLD: expected a duration such as 500ms, got %q", v)
+		}
+	}
+
+	var explainSample float64
+	if v := os.Getenv("SLOW_QUERY_EXPLAIN_SAMPLE"); v != "" {
+		explainSample, err = strconv.ParseFloat(v, 64)
+		if err != nil || explainSample < 0 || explainSample > 1 {
+			return nil, fmt.Errorf("SLOW_QUERY_EXPLAIN_SAMPLE: expected a number between 0 and 1, got %q", v)
+		}
+	}
+
+	stmtCacheSize := 512
+	if v := os.Getenv("STATEMENT_CACHE_SIZE"); v != "" {
+		stmtCacheSize, err = strconv.Atoi(v)
+		if err != nil || stmtCacheSize < 0 {
+			return nil, fmt.Errorf("STATEMENT_CACHE_SIZE: expected a non-negative integer, or 0 to disable, got %q", v)
+		}
+	}
+
+	migrateOnStart := os.Getenv("MIGRATE_ON_START")
+	switch migrateOnStart {
+	case "":
+		migrateOnStart = "off"
+	case "off", "verify", "auto":
+	default:
+		return nil, fmt.Errorf("MIGRATE_ON_START: expected off, verify or auto, got %q", migrateOnStart)
+	}
+
+	var baseline int64
+	if v := os.Getenv("MIGRATIONS_BASELINE"); v != "" {
+		baseline, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || baseline < 1 {
+			return nil, fmt.Errorf("MIGRATIONS_BASELINE: expected a positive migration version, got %q", v)
+		}
+	}
+
+	var readOnly bool
+	if v := os.Getenv("READ_ONLY"); v != "" {
+		readOnly, err = strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("READ_ONLY: expected true or false, got %q", v)
+		}
+	}
+
+	snapshotTTL := 5 * time.Minute
+	if v := os.Getenv("SNAPSHOT_TTL"); v != "" {
+		snapshotTTL, err = time.ParseDuration(v)
+		if err != nil || snapshotTTL <= 0 {
+			return nil, fmt.Errorf("SNAPSHOT_TTL: expected a positive duration such as 5m, got %q", v)
+		}
+	}
+
+	snapshotMax := 8
+	if v := os.Getenv("SNAPSHOT_MAX"); v != "" {
+		snapshotMax, err = strconv.Atoi(v)
+		if err != nil || snapshotMax < 0 {
+			return nil, fmt.Errorf("SNAPSHOT_MAX: expected a non-negative integer, got %q", v)
+		}
+	}
+
+	retentionInterval := time.Hour
+	if v := os.Getenv("RETENTION_INTERVAL"); v != "" {
+		retentionInterval, err = time.ParseDuration(v)
+		if err != nil || retentionInterval < 0 {
+			return nil, fmt.Errorf("RETENTION_INTERVAL: expected a duration such as 1h, or 0 to disable, got %q", v)
+		}
+	}
+
+	schemaPoll := 30 * time.Second
+	if v := os.Getenv("SCHEMA_POLL_INTERVAL"); v != "" {
+		schemaPoll, err = time.ParseDuration(v)
+		if err != nil || schemaPoll < 0 {
+			return nil, fmt.Errorf("SCHEMA_POLL_INTERVAL: expected a duration such as 30s, or 0 to disable, got %q", v)
+		}
+	}
+
+	retentionBatch := 500
+	if v := os.Getenv("RETENTION_BATCH_SIZE"); v != "" {
+		retentionBatch, err = strconv.Atoi(v)
+		if err != nil || retentionBatch < 1 {
+			return nil, fmt.Errorf("RETENTION_BATCH_SIZE: expected a positive integer, got %q", v)
+		}
+	}
+
+	var listConcurrency, countConcurrency int
+	if v := os.Getenv("LIST_CONCURRENCY"); v != "" {
+		listConcurrency, err = strconv.Atoi(v)
+		if err != nil || listConcurrency < 0 {
+			return nil, fmt.Errorf("LIST_CONCURRENCY: expected a non-negative integer, got %q", v)
+		}
+	}
+	if v := os.Getenv("COUNT_CONCURRENCY"); v != "" {
+		countConcurrency, err = strconv.Atoi(v)
+		if err != nil || countConcurrency < 0 {
+			return nil, fmt.Errorf("COUNT_CONCURRENCY: expected a non-negative integer, got %q", v)
+		}
+	}
+
+	queueTimeout := 2 * time.Second
+	if v := os.Getenv("QUERY_QUEUE_TIMEOUT"); v != "" {
+		queueTimeout, err = time.ParseDuration(v)
+		if err != nil || queueTimeout < 0 {
+			return nil, fmt.Errorf("QUERY_QUEUE_TIMEOUT: expected a duration such as 2s, or 0 to wait indefinitely, got %q", v)
+		}
+	}
+
+	expandBudget := 500
+	if v := os.Getenv("EXPAND_COLUMN_BUDGET"); v != "" {
+		expandBudget, err = strconv.Atoi(v)
+		if err != nil || expandBudget < 0 {
+			return nil, fmt.Errorf("EXPAND_COLUMN_BUDGET: expected a non-negative integer, or 0 to disable, got %q", v)
+		}
+	}
+
+	lists := schema.ListLimits{
+		DefaultPageSize:     schema.DefaultPageSize,
+		MaxPageSize:         schema.MaxPageSize,
+		ExactCountThreshold: schema.DefaultExactCountThreshold,
+	}
+	if v := os.Getenv("DEFAULT_PAGE_SIZE"); v != "" {
+		lists.DefaultPageSize, err = strconv.Atoi(v)
+		if err != nil || lists.DefaultPageSize < 1 {
+			return nil, fmt.Errorf("DEFAULT_PAGE_SIZE: expected a positive integer, got %q", v)
+		}
+	}
+	if v := os.Getenv("MAX_PAGE_SIZE"); v != "" {
+		lists.MaxPageSize, err = strconv.Atoi(v)
+		if err != nil || lists.MaxPageSize < 1 {
+			return nil, fmt.Errorf("MAX_PAGE_SIZE: expected a positive integer, got %q", v)
+		}
+	}
+	if v := os.Getenv("EXACT_COUNT_THRESHOLD"); v != "" {
+		lists.ExactCountThreshold, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || lists.ExactCountThreshold < 1 {
+			return nil, fmt.Errorf("EXACT_COUNT_THRESHOLD: expected a positive integer, got %q", v)
+		}
+	}
+	if err := lists.Validate(); err != nil {
+		return nil, fmt.Errorf("DEFAULT_PAGE_SIZE, MAX_PAGE_SIZE: %w", err)
+	}
+
+	maxCustomFields := 500
+	if v := os.Getenv("MAX_CUSTOM_FIELDS"); v != "" {
+		maxCustomFields, err = strconv.Atoi(v)
+		if err != nil || maxCustomFields < 0 {
+			return nil, fmt.Errorf("MAX_CUSTOM_FIELDS: expected a non-negative integer, or 0 to disable, got %q", v)
+		}
+	}
+
+	maxDataBytes := 256 << 10
+	if v := os.Getenv("MAX_DATA_BYTES"); v != "" {
+		maxDataBytes, err = strconv.Atoi(v)
+		if err != nil || maxDataBytes < 0 {
+			return nil, fmt.Errorf("MAX_DATA_BYTES: expected a non-negative integer, or 0 to disable, got %q", v)
+		}
+	}
+
+	var hrqlMaxCost float64
+	if v := os.Getenv("HRQL_MAX_COST"); v != "" {
+		hrqlMaxCost, err = strconv.ParseFloat(v, 64)
+		if err != nil || hrqlMaxCost < 0 {
+			return nil, fmt.Errorf("HRQL_MAX_COST: expected a non-negative number, or 0 to disable, got %q", v)
+		}
+	}
+
+	var hrqlMaxRows int64
+	if v := os.Getenv("HRQL_MAX_ROWS"); v != "" {
+		hrqlMaxRows, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || hrqlMaxRows < 0 {
+			return nil, fmt.Errorf("HRQL_MAX_ROWS: expected a non-negative integer, or 0 to disable, got %q", v)
+		}
+	}
+	var hrqlStreamMaxRows int64
+	if v := os.Getenv("HRQL_STREAM_MAX_ROWS"); v != "" {
+		hrqlStreamMaxRows, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || hrqlStreamMaxRows < 1 {
+			return nil, fmt.Errorf("HRQL_STREAM_MAX_ROWS: expected a positive integer, got %q", v)
+		}
+	}
+
+	corsOrigins := splitList(os.Getenv("CORS_ALLOWED_ORIGINS"))
+	corsMethods := splitList(os.Getenv("CORS_ALLOWED_METHODS"))
+	if corsMethods == nil {
+		corsMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+	}
+	corsHeaders := splitList(os.Getenv("CORS_ALLOWED_HEADERS"))
+	if corsHeaders == nil {
+		corsHeaders = []string{
+			"Content-Type", "Authorization", "If-Match", "If-None-Match", "If-Modified-Since",
+			"Connect-Protocol-Version", "Connect-Timeout-Ms", "Connect-Content-Encoding", "Connect-Accept-Encoding",
+			"Grpc-Timeout", "X-Grpc-Web", "X-User-Agent",
+		}
+	}
+
+	var corsCredentials bool
+	if v := os.Getenv("CORS_ALLOW_CREDENTIALS"); v != "" {
+		corsCredentials, err = strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("CORS_ALLOW_CREDENTIALS: expected true or false, got %q", v)
+		}
+	}
+	if corsCredentials && slices.Contains(corsOrigins, "*") {
+		return nil, fmt.Errorf("CORS_ALLOW_CREDENTIALS: credentials cannot be allowed for any origin; list the origins in CORS_ALLOWED_ORIGINS")
+	}
+
+	corsMaxAge := 10 * time.Minute
+	if v := os.Getenv("CORS_MAX_AGE"); v != "" {
+		corsMaxAge, err = time.ParseDuration(v)
+		if err != nil || corsMaxAge < 0 {
+			return nil, fmt.Errorf("CORS_MAX_AGE: expected a duration such as 10m, got %q", v)
+		}
+	}
+
+	securityHeaders := true
+	if v := os.Getenv("SECURITY_HEADERS"); v != "" {
+		securityHeaders, err = strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("SECURITY_HEADERS: expected true or false, got %q", v)
+		}
+	}
+
+	var autoSearchIndexes bool
+	if v := os.Getenv("AUTO_SEARCH_INDEXES"); v != "" {
+		autoSearchIndexes, err = strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("AUTO_SEARCH_INDEXES: expected true or false, got %q", v)
+		}
+	}
+
+	var autoSortIndexes bool
+	if v := os.Getenv("AUTO_SORT_INDEXES"); v != "" {
+		autoSortIndexes, err = strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("AUTO_SORT_INDEXES: expected true or false, got %q", v)
+		}
+	}
+
+	usageFlush := time.Minute
+	if v := os.Getenv("USAGE_FLUSH_INTERVAL"); v != "" {
+		usageFlush, err = time.ParseDuration(v)
+		if err != nil || usageFlush < 0 {
+			return nil, fmt.Errorf("USAGE_FLUSH_INTERVAL: expected a duration such as 1m, or 0 to disable, got %q", v)
+		}
+	}
+
+	usageRetention := 30 * 24 * time.Hour
+	if v := os.Getenv("USAGE_RETENTION"); v != "" {
+		usageRetention, err = time.ParseDuration(v)
+		if err != nil || usageRetention < time.Hour {
+			return nil, fmt.Errorf("USAGE_RETENTION: expected a duration of at least 1h such as 720h, got %q", v)
+		}
+	}
+
+	var seedStandard bool
+	if v := os.Getenv("SEED_STANDARD_OBJECTS"); v != "" {
+		seedStandard, err = strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("SEED_STANDARD_OBJECTS: expected true or false, got %q", v)
+		}
+	}
+
+	var changeApproval bool
+	if v := os.Getenv("METADATA_CHANGE_APPROVAL"); v != "" {
+		changeApproval, err = strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("METADATA_CHANGE_APPROVAL: expected true or false, got %q", v)
+		}
+	}
+
 	return &Config{
-		DatabaseURL: dbURL,
-		Port:        port,
+		DatabaseURL:        dbURL,
+		Port:               port,
+		ExpandStrategy:     expandStrategy,
+		FieldEncryptionKey: key,
+		CursorSigningKey:   cursorKey,
+		APINameMaxLength:   maxLen,
+		ReservedAPINames:   reserved,
+
+		SlowQueryThreshold:     slowThreshold,
+		SlowQueryExplainSample: explainSample,
+
+		StatementCacheSize: stmtCacheSize,
+
+		MigrateOnStart:     migrateOnStart,
+		MigrationsBaseline: baseline,
+
+		ReadOnly:       readOnly,
+		ReadOnlyReason: os.Getenv("READ_ONLY_REASON"),
+
+		SnapshotTTL: snapshotTTL,
+		SnapshotMax: snapshotMax,
+
+		RetentionInterval:  retentionInterval,
+		RetentionBatchSize: retentionBatch,
+
+		SchemaPollInterval: schemaPoll,
+
+		ListConcurrency:   listConcurrency,
+		CountConcurrency:  countConcurrency,
+		QueryQueueTimeout: queueTimeout,
+
+		ExpandColumnBudget: expandBudget,
+
+		ListLimits: lists,
+
+		MaxCustomFields: maxCustomFields,
+		MaxDataBytes:    maxDataBytes,
+
+		HRQLMaxCost: hrqlMaxCost,
+		HRQLMaxRows: hrqlMaxRows,
+
+		HRQLStreamMaxRows: hrqlStreamMaxRows,
+
+		CORSAllowedOrigins:   corsOrigins,
+		CORSAllowedMethods:   corsMethods,
+		CORSAllowedHeaders:   corsHeaders,
+		CORSAllowCredentials: corsCredentials,
+		CORSMaxAge:           corsMaxAge,
+
+		SecurityHeaders: securityHeaders,
+
+		AutoSearchIndexes: autoSearchIndexes,
+		AutoSortIndexes:   autoSortIndexes,
+
+		UsageFlushInterval: usageFlush,
+		UsageRetention:     usageRetention,
+
+		SeedStandardObjects: seedStandard,
+
+		MetadataChangeApproval: changeApproval,
 	}, nil
 }
 
+// splitList splits a comma-separated variable, dropping blank entries; nil
+// when there are none.
+func splitList(v string) []string {
+	var out []string
+	for w := range strings.SplitSeq(v, ",") {
+		if w = strings.TrimSpace(w); w != "" {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// loadKey reads a base64-encoded key from the env variable or, for keys
+// delivered by a KMS agent or secret mount, from the file named by env_FILE.
+// what names the key in errors.
+func loadKey(env, what string) ([]byte, error) {
+	raw := os.Getenv(env)
+	if path := os.Getenv(env + "_FILE"); path != "" {
+		if raw != "" {
+			return nil, fmt.Errorf("set only one of %s and %s_FILE", env, env)
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", what, err)
+		}
+		raw = string(b)
+	}
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: expected base64: %w", what, err)
+	}
+	return key, nil
+}
+
 func (c *Config) Addr() string {
 	return fmt.Sprintf(":%s", c.Port)
 }
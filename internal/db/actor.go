@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Session settings naming who a transaction runs for. Database triggers and
+// RLS policies read them with current_setting('app.actor_id', true), which
+// is NULL or empty outside a request.
+const (
+	ActorSetting     = "app.actor_id"
+	RequestIDSetting = "app.request_id"
+)
+
+// Actor identifies the principal a request runs for and the request itself.
+type Actor struct {
+	ID        string
+	RequestID string
+}
+
+type actorKey struct{}
+
+// WithActor attaches the actor to ctx, so transactions begun with Begin
+// attribute their changes to it.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor attached to ctx, if any.
+func ActorFrom(ctx context.Context) (Actor, bool) {
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	return actor, ok
+}
+
+// TxBeginner starts transactions; *pgxpool.Pool, *pgx.Conn and pgx.Tx (for a
+// savepoint) implement it.
+type TxBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// Begin starts a transaction and, when ctx carries an actor, sets
+// ActorSetting and RequestIDSetting for its duration (SET LOCAL semantics:
+// they reset on commit or rollback, so pooled connections do not leak them
+// into the next request).
+func Begin(ctx context.Context, b TxBeginner) (pgx.Tx, error) {
+	tx, err := b.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	actor, ok := ActorFrom(ctx)
+	if !ok || (actor.ID == "" && actor.RequestID == "") {
+		return tx, nil
+	}
+	// SET LOCAL takes no parameters; set_config(..., true) is its
+	// parameterized form.
+	if _, err := tx.Exec(ctx, `SELECT set_config($1, $2, true), set_config($3, $4, true)`,
+		ActorSetting, actor.ID, RequestIDSetting, actor.RequestID); err != nil {
+		tx.Rollback(context.WithoutCancel(ctx))
+		return nil, fmt.Errorf("set actor: %w", err)
+	}
+	return tx, nil
+}
@@ -0,0 +1,17 @@
+package db
+
+import (
+	"context"
+	"testing"
+)
+
+func TestActorFrom(t *testing.T) {
+	if _, ok := ActorFrom(context.Background()); ok {
+		t.Error("expected no actor on a bare context")
+	}
+	ctx := WithActor(context.Background(), Actor{ID: "user-1", RequestID: "req-1"})
+	actor, ok := ActorFrom(ctx)
+	if !ok || actor.ID != "user-1" || actor.RequestID != "req-1" {
+		t.Errorf("ActorFrom = %+v, %v", actor, ok)
+	}
+}
@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ErrSaturated is returned by Limiter.Do when no slot frees up within the
+// limiter's maximum wait.
+var ErrSaturated = errors.New("query capacity exhausted")
+
+// Limiter caps how many queries of one kind run at once. Requests fan out
+// into several queries (a List runs its count and its page concurrently), so
+// without a cap a traffic spike turns into a pile of goroutines blocked on
+// pool connections. Callers beyond the cap queue for up to maxWait and then
+// fail with ErrSaturated, so spikes degrade into fast, retryable errors.
+//
+// Limiter implements prometheus.Collector, reporting queue wait times and
+// the queries running, queued and rejected. A nil *Limiter runs everything
+// immediately.
+type Limiter struct {
+	name    string
+	slots   chan struct{}
+	maxWait time.Duration
+
+	queued   atomic.Int64
+	rejected atomic.Uint64
+	wait     prometheus.Histogram
+
+	runningDesc, queuedDesc, rejectedDesc *prometheus.Desc
+}
+
+// NewLimiter returns a limiter named name (the "pool" label of its metrics)
+// running at most size queries at once, each waiting at most maxWait for a
+// slot (0 waits until the caller's context ends).
+func NewLimiter(name string, size int, maxWait time.Duration) *Limiter {
+	labels := prometheus.Labels{"pool": name}
+	return &Limiter{
+		name:    name,
+		slots:   make(chan struct{}, max(size, 1)),
+		maxWait: maxWait,
+		wait: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:        "query_pool_wait_seconds",
+			Help:        "Time queries waited for a query pool slot, including rejected ones.",
+			ConstLabels: labels,
+			Buckets:     []float64{.0005, .001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}),
+		runningDesc: prometheus.NewDesc("query_pool_running",
+			"Queries holding a query pool slot.", nil, labels),
+		queuedDesc: prometheus.NewDesc("query_pool_queued",
+			"Queries waiting for a query pool slot.", nil, labels),
+		rejectedDesc: prometheus.NewDesc("query_pool_rejected_total",
+			"Queries that gave up waiting for a query pool slot.", nil, labels),
+	}
+}
+
+// Do runs fn once a slot is free. It returns an error wrapping ErrSaturated
+// if none frees up within the maximum wait, and ctx's error if ctx ends
+// first.
+func (l *Limiter) Do(ctx context.Context, fn func() error) error {
+	if l == nil {
+		return fn()
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		l.wait.Observe(0)
+	default:
+		if err := l.queue(ctx); err != nil {
+			return err
+		}
+	}
+	defer func() { <-l.slots }()
+	return fn()
+}
+
+func (l *Limiter) queue(ctx context.Context) error {
+	l.queued.Add(1)
+	defer l.queued.Add(-1)
+
+	start := time.Now()
+	defer func() { l.wait.Observe(time.Since(start).Seconds()) }()
+
+	var timeout <-chan time.Time
+	if l.maxWait > 0 {
+		t := time.NewTimer(l.maxWait)
+		defer t.Stop()
+		timeout = t.C
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-timeout:
+		l.rejected.Add(1)
+		return fmt.Errorf("%w: %s queries waited %s for a slot", ErrSaturated, l.name, l.maxWait)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *Limiter) Describe(ch chan<- *prometheus.Desc) {
+	l.wait.Describe(ch)
+	ch <- l.runningDesc
+	ch <- l.queuedDesc
+	ch <- l.rejectedDesc
+}
+
+func (l *Limiter) Collect(ch chan<- prometheus.Metric) {
+	l.wait.Collect(ch)
+	ch <- prometheus.MustNewConstMetric(l.runningDesc, prometheus.GaugeValue, float64(len(l.slots)))
+	ch <- prometheus.MustNewConstMetric(l.queuedDesc, prometheus.GaugeValue, float64(l.queued.Load()))
+	ch <- prometheus.MustNewConstMetric(l.rejectedDesc, prometheus.CounterValue, float64(l.rejected.Load()))
+}
@@ -0,0 +1,59 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLimiterSaturates(t *testing.T) {
+	l := NewLimiter("test", 1, 20*time.Millisecond)
+
+	held := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- l.Do(context.Background(), func() error {
+			<-held
+			return nil
+		})
+	}()
+	for len(l.slots) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	err := l.Do(context.Background(), func() error { return nil })
+	if !errors.Is(err, ErrSaturated) {
+		t.Fatalf("expected ErrSaturated, got %v", err)
+	}
+	if got := l.rejected.Load(); got != 1 {
+		t.Errorf("rejected = %d, want 1", got)
+	}
+
+	close(held)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Do(context.Background(), func() error { return nil }); err != nil {
+		t.Fatalf("slot not released: %v", err)
+	}
+}
+
+func TestLimiterContextCancel(t *testing.T) {
+	l := NewLimiter("test", 1, 0)
+	l.slots <- struct{}{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.Do(ctx, func() error { return nil }); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestLimiterNil(t *testing.T) {
+	var l *Limiter
+	ran := false
+	if err := l.Do(context.Background(), func() error { ran = true; return nil }); err != nil || !ran {
+		t.Fatalf("nil limiter: ran=%v err=%v", ran, err)
+	}
+}
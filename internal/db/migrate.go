@@ -0,0 +1,290 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// migrateLockKey is the advisory lock serializing migrations across server
+// instances starting at the same time.
+const migrateLockKey int64 = 0x5ec4e3a1
+
+// migrationFile matches NNNNNN_name.up.sql.
+var migrationFile = regexp.MustCompile(`^(\d+)_(\w+)\.up\.sql$`)
+
+// Migration is an embedded up migration.
+type Migration struct {
+	Version int64
+	Name    string
+	sql     string
+}
+
+// AppliedMigration is a row of public.schema_migrations.
+type AppliedMigration struct {
+	Version   int64
+	Dirty     bool
+	AppliedAt *time.Time // nil for baselined versions
+}
+
+// MigrationStatus compares the embedded migrations with the database.
+type MigrationStatus struct {
+	Migrations []Migration
+	Applied    map[int64]AppliedMigration
+	// Untracked is set when the schema exists but no version is recorded.
+	Untracked bool
+}
+
+// Current returns the highest applied version, or 0.
+func (s MigrationStatus) Current() int64 {
+	var v int64
+	for version := range s.Applied {
+		v = max(v, version)
+	}
+	return v
+}
+
+// Dirty reports whether a migration failed part-way.
+func (s MigrationStatus) Dirty() bool {
+	for _, a := range s.Applied {
+		if a.Dirty {
+			return true
+		}
+	}
+	return false
+}
+
+// Pending returns the migrations not yet applied, in order.
+func (s MigrationStatus) Pending() []Migration {
+	var pending []Migration
+	for _, m := range s.Migrations {
+		if _, ok := s.Applied[m.Version]; !ok {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
+// Migrator applies embedded SQL migrations and records them in
+// public.schema_migrations, one row per version. A version is inserted as
+// dirty before its SQL runs and cleared afterwards, so a failure leaves a
+// dirty row that blocks further runs until repaired by hand.
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+}
+
+// NewMigrator reads the *.up.sql files at the root of fsys.
+func NewMigrator(pool *pgxpool.Pool, fsys fs.FS) (*Migrator, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	var migrations []Migration
+	for _, e := range entries {
+		m := migrationFile.FindStringSubmatch(e.Name())
+		if m == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", e.Name(), err)
+		}
+		sql, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, err
+		}
+		if i := slices.IndexFunc(migrations, func(o Migration) bool { return o.Version == version }); i >= 0 {
+			return nil, fmt.Errorf("duplicate migration version %d (%s, %s)", version, migrations[i].Name, m[2])
+		}
+		migrations = append(migrations, Migration{Version: version, Name: m[2], sql: string(sql)})
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int { return int(a.Version - b.Version) })
+	return &Migrator{pool: pool, migrations: migrations}, nil
+}
+
+// Migrations returns the embedded migrations in version order.
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Status reads the applied versions.
+func (m *Migrator) Status(ctx context.Context) (MigrationStatus, error) {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return MigrationStatus{}, err
+	}
+	defer conn.Release()
+	applied, err := readApplied(ctx, conn.Conn())
+	if err != nil {
+		return MigrationStatus{}, err
+	}
+	st := MigrationStatus{Migrations: m.migrations, Applied: applied}
+	st.Untracked, err = untracked(ctx, conn.Conn(), applied)
+	return st, err
+}
+
+// Verify returns an error when migrations are pending or one is dirty.
+func (m *Migrator) Verify(ctx context.Context) error {
+	st, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if st.Untracked {
+		return errUntracked
+	}
+	if err := checkHistory(st); err != nil {
+		return err
+	}
+	if pending := st.Pending(); len(pending) > 0 {
+		return fmt.Errorf("database is at version %d, %d migration(s) pending (first: %06d_%s)",
+			st.Current(), len(pending), pending[0].Version, pending[0].Name)
+	}
+	return nil
+}
+
+// Up applies all pending migrations in order under an advisory lock and
+// returns those it applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.locked(ctx, func(conn *pgx.Conn) error {
+		done, err := readApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if u, err := untracked(ctx, conn, done); err != nil {
+			return err
+		} else if u {
+			return errUntracked
+		}
+		st := MigrationStatus{Migrations: m.migrations, Applied: done}
+		if err := checkHistory(st); err != nil {
+			return err
+		}
+		for _, mig := range st.Pending() {
+			if err := apply(ctx, conn, mig); err != nil {
+				return err
+			}
+			log.Printf("applied migration %06d_%s", mig.Version, mig.Name)
+			applied = append(applied, mig)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Baseline records every embedded migration up to version as applied without
+// running it, for databases migrated before the history table existed (e.g.
+// with `task migrate-up`). It is a no-op once any version is recorded.
+func (m *Migrator) Baseline(ctx context.Context, version int64) error {
+	return m.locked(ctx, func(conn *pgx.Conn) error {
+		done, err := readApplied(ctx, conn)
+		if err != nil || len(done) > 0 {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if mig.Version > version {
+				break
+			}
+			if _, err := conn.Exec(ctx, `INSERT INTO public.schema_migrations ("version", "name") VALUES ($1, $2)`,
+				mig.Version, mig.Name); err != nil {
+				return err
+			}
+		}
+		log.Printf("baselined migrations up to version %d", version)
+		return nil
+	})
+}
+
+// checkHistory rejects a database where a migration failed part-way.
+func checkHistory(st MigrationStatus) error {
+	for v, a := range st.Applied {
+		if a.Dirty {
+			return fmt.Errorf("migration %d is dirty: a previous run failed part-way; repair the schema and delete or clear its row in public.schema_migrations", v)
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) locked(ctx context.Context, fn func(*pgx.Conn) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrateLockKey); err != nil {
+		return fmt.Errorf("migration lock: %w", err)
+	}
+	defer conn.Exec(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, migrateLockKey)
+
+	if _, err := conn.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS public.schema_migrations (
+			"version"		BIGINT PRIMARY KEY,
+			"name"			TEXT NOT NULL,
+			"dirty"			BOOLEAN NOT NULL DEFAULT FALSE,
+			"applied_at"	TIMESTAMPTZ
+		)`); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+	return fn(conn.Conn())
+}
+
+// errUntracked reports a database that has the registry schema but no
+// recorded history; it must be baselined before migrating.
+var errUntracked = errors.New("database has a schema but no migration history; set MIGRATIONS_BASELINE to its current version")
+
+func untracked(ctx context.Context, conn *pgx.Conn, applied map[int64]AppliedMigration) (bool, error) {
+	if len(applied) > 0 {
+		return false, nil
+	}
+	var exists bool
+	err := conn.QueryRow(ctx, `SELECT to_regclass('metadata.objects') IS NOT NULL`).Scan(&exists)
+	return exists, err
+}
+
+// apply runs one migration. Its SQL is sent over the simple protocol (no
+// arguments), so multi-statement files with their own BEGIN/COMMIT work.
+func apply(ctx context.Context, conn *pgx.Conn, mig Migration) error {
+	if _, err := conn.Exec(ctx, `INSERT INTO public.schema_migrations ("version", "name", "dirty") VALUES ($1, $2, TRUE)`,
+		mig.Version, mig.Name); err != nil {
+		return err
+	}
+	if _, err := conn.Exec(ctx, mig.sql); err != nil {
+		// Roll back an open transaction so the error is not masked.
+		_, _ = conn.Exec(context.WithoutCancel(ctx), "ROLLBACK")
+		return fmt.Errorf("migration %06d_%s: %w", mig.Version, mig.Name, err)
+	}
+	_, err := conn.Exec(ctx, `UPDATE public.schema_migrations SET "dirty" = FALSE, "applied_at" = now() WHERE "version" = $1`, mig.Version)
+	return err
+}
+
+func readApplied(ctx context.Context, conn *pgx.Conn) (map[int64]AppliedMigration, error) {
+	applied := make(map[int64]AppliedMigration)
+	var exists bool
+	if err := conn.QueryRow(ctx, `SELECT to_regclass('public.schema_migrations') IS NOT NULL`).Scan(&exists); err != nil || !exists {
+		return applied, err
+	}
+	rows, err := conn.Query(ctx, `SELECT "version", "dirty", "applied_at" FROM public.schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var a AppliedMigration
+		if err := rows.Scan(&a.Version, &a.Dirty, &a.AppliedAt); err != nil {
+			return nil, err
+		}
+		applied[a.Version] = a
+	}
+	return applied, rows.Err()
+}
@@ -0,0 +1,122 @@
+package db_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/atlekbai/schema_registry/internal/db"
+	"github.com/atlekbai/schema_registry/internal/testutil"
+	"github.com/atlekbai/schema_registry/migrations"
+)
+
+func TestIntegrationMigrateFresh(t *testing.T) {
+	pool := testutil.NewEmptyDatabase(t)
+	ctx := context.Background()
+
+	m, err := db.NewMigrator(pool, migrations.FS)
+	if err != nil {
+		t.Fatalf("read migrations: %v", err)
+	}
+	// A database without scripts/init.sql gets every extension from the
+	// migrations themselves.
+	applied, err := m.Up(ctx)
+	if err != nil {
+		t.Fatalf("up: %v", err)
+	}
+	if len(applied) != len(m.Migrations()) {
+		t.Errorf("applied %d of %d migrations", len(applied), len(m.Migrations()))
+	}
+	if err := m.Verify(ctx); err != nil {
+		t.Errorf("verify after up: %v", err)
+	}
+
+	applied, err = m.Up(ctx)
+	if err != nil || len(applied) != 0 {
+		t.Errorf("second up applied %d migrations, err %v; want a no-op", len(applied), err)
+	}
+}
+
+func TestIntegrationMigratePendingAndDirty(t *testing.T) {
+	pool := testutil.NewEmptyDatabase(t)
+	ctx := context.Background()
+
+	files := fstest.MapFS{
+		"000001_one.up.sql":   {Data: []byte(`CREATE TABLE public.one ("id" INT);`)},
+		"000001_one.down.sql": {Data: []byte(`DROP TABLE public.one;`)},
+		"000002_two.up.sql":   {Data: []byte(`CREATE TABLE public.two ("id" INT);`)},
+	}
+	m, err := db.NewMigrator(pool, files)
+	if err != nil {
+		t.Fatalf("read migrations: %v", err)
+	}
+	if err := m.Verify(ctx); err == nil || !strings.Contains(err.Error(), "000001_one") {
+		t.Errorf("verify before up: error %v, want 000001_one pending", err)
+	}
+	if applied, err := m.Up(ctx); err != nil || len(applied) != 2 {
+		t.Fatalf("up: applied %d, err %v; want 2", len(applied), err)
+	}
+
+	// A new migration is pending until applied, and one that fails stays
+	// recorded as dirty, rolled back, and blocks further runs.
+	files["000003_bad.up.sql"] = &fstest.MapFile{Data: []byte(`BEGIN; CREATE TABLE public.three ("id" INT); SELECT 1/0; COMMIT;`)}
+	m, err = db.NewMigrator(pool, files)
+	if err != nil {
+		t.Fatalf("read migrations: %v", err)
+	}
+	if err := m.Verify(ctx); err == nil || !strings.Contains(err.Error(), "1 migration(s) pending (first: 000003_bad)") {
+		t.Errorf("verify with a new migration: error %v, want 000003_bad pending", err)
+	}
+	if _, err := m.Up(ctx); err == nil || !strings.Contains(err.Error(), "000003_bad") {
+		t.Fatalf("up with a failing migration: error %v, want it to name 000003_bad", err)
+	}
+	st, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if a, ok := st.Applied[3]; !ok || !a.Dirty || a.AppliedAt != nil {
+		t.Errorf("failed migration row = %+v (recorded %t), want dirty", a, ok)
+	}
+	if !st.Dirty() || st.Current() != 3 {
+		t.Errorf("status dirty %t at %d, want dirty at 3", st.Dirty(), st.Current())
+	}
+	var exists bool
+	if err := pool.QueryRow(ctx, `SELECT to_regclass('public.three') IS NOT NULL`).Scan(&exists); err != nil || exists {
+		t.Errorf("public.three exists %t (err %v), want the failed migration rolled back", exists, err)
+	}
+	for name, run := range map[string]func() error{
+		"verify": func() error { return m.Verify(ctx) },
+		"up":     func() error { _, err := m.Up(ctx); return err },
+	} {
+		if err := run(); err == nil || !strings.Contains(err.Error(), "migration 3 is dirty") {
+			t.Errorf("%s on a dirty database: error %v, want it to report migration 3", name, err)
+		}
+	}
+}
+
+func TestIntegrationMigrateBaseline(t *testing.T) {
+	pool := testutil.NewEmptyDatabase(t)
+	ctx := context.Background()
+
+	files := fstest.MapFS{
+		"000001_one.up.sql": {Data: []byte(`CREATE TABLE public.one ("id" INT);`)},
+		"000002_two.up.sql": {Data: []byte(`CREATE TABLE public.two ("id" INT);`)},
+	}
+	m, err := db.NewMigrator(pool, files)
+	if err != nil {
+		t.Fatalf("read migrations: %v", err)
+	}
+	if err := m.Baseline(ctx, 1); err != nil {
+		t.Fatalf("baseline: %v", err)
+	}
+	// Baselined versions are recorded without running.
+	applied, err := m.Up(ctx)
+	if err != nil || len(applied) != 1 || applied[0].Version != 2 {
+		t.Fatalf("up after baseline applied %v, err %v; want only version 2", applied, err)
+	}
+	var exists bool
+	if err := pool.QueryRow(ctx, `SELECT to_regclass('public.one') IS NOT NULL`).Scan(&exists); err != nil || exists {
+		t.Errorf("public.one exists %t (err %v), want the baselined migration not run", exists, err)
+	}
+}
@@ -3,11 +3,29 @@ package db
 import (
 	"context"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func NewPool(ctx context.Context, databaseURL string) (*pgxpool.Pool, error) {
-	pool, err := pgxpool.New(ctx, databaseURL)
+// NewPool connects to databaseURL. tracer, when non-nil, observes every query
+// run through the pool (e.g. a SlowQueryLog).
+//
+// Each connection prepares the statements it runs and keeps up to
+// statementCacheSize of them, so queries of the same shape skip parsing and
+// planning after the first run. 0 disables the cache: statements are then
+// prepared unnamed on every run, which also works behind poolers that do not
+// keep prepared statements (e.g. PgBouncer in transaction mode).
+func NewPool(ctx context.Context, databaseURL string, tracer pgx.QueryTracer, statementCacheSize int) (*pgxpool.Pool, error) {
+	cfg, err := pgxpool.ParseConfig(databaseURL)
+	if err != nil {
+		return nil, err
+	}
+	if tracer != nil {
+		cfg.ConnConfig.Tracer = tracer
+	}
+	ConfigureStatementCache(cfg.ConnConfig, statementCacheSize)
+
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -19,3 +37,14 @@ func NewPool(ctx context.Context, databaseURL string) (*pgxpool.Pool, error) {
 
 	return pool, nil
 }
+
+// ConfigureStatementCache sets up cfg's statement cache as NewPool does.
+func ConfigureStatementCache(cfg *pgx.ConnConfig, size int) {
+	if size > 0 {
+		cfg.DefaultQueryExecMode = pgx.QueryExecModeCacheStatement
+		cfg.StatementCacheCapacity = size
+		return
+	}
+	cfg.DefaultQueryExecMode = pgx.QueryExecModeExec
+	cfg.StatementCacheCapacity = 0
+}
@@ -0,0 +1,231 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// slowQueryQueueSize bounds the entries waiting to be persisted. When the
+// database is slow enough to back the queue up, further entries are dropped
+// (and counted) rather than adding load or blocking requests.
+const slowQueryQueueSize = 256
+
+// SlowQueryLog is a pgx.QueryTracer that records queries running longer than
+// a threshold to the log and to diagnostics.slow_queries. A sampled fraction
+// of slow read-only queries is re-run under EXPLAIN (ANALYZE, BUFFERS) in a
+// read-only transaction to capture the plan.
+type SlowQueryLog struct {
+	threshold     time.Duration
+	explainSample float64
+
+	pool    *pgxpool.Pool
+	queue   chan slowQuery
+	mu      sync.Mutex
+	dropped int
+}
+
+type slowQuery struct {
+	at        time.Time
+	duration  time.Duration
+	sql       string
+	args      []any // only retained for entries sampled for EXPLAIN
+	argTypes  []string
+	object    string
+	procedure string
+	err       string
+}
+
+type traceStartKey struct{}
+
+type traceStart struct {
+	at   time.Time
+	sql  string
+	args []any
+}
+
+type labelsKey struct{}
+
+type queryLabels struct {
+	object    string
+	procedure string
+}
+
+type skipKey struct{}
+
+// WithQueryLabels tags queries run with ctx, so slow query entries name the
+// RPC procedure and the object being read or written.
+func WithQueryLabels(ctx context.Context, procedure, object string) context.Context {
+	return context.WithValue(ctx, labelsKey{}, queryLabels{object: object, procedure: procedure})
+}
+
+// NewSlowQueryLog returns a tracer logging queries slower than threshold.
+// explainSample (0..1) is the fraction of slow SELECTs whose plan is captured.
+// Entries are persisted once Start has been called.
+func NewSlowQueryLog(threshold time.Duration, explainSample float64) *SlowQueryLog {
+	return &SlowQueryLog{
+		threshold:     threshold,
+		explainSample: explainSample,
+		queue:         make(chan slowQuery, slowQueryQueueSize),
+	}
+}
+
+// Start persists queued entries using pool until ctx is done.
+func (l *SlowQueryLog) Start(ctx context.Context, pool *pgxpool.Pool) {
+	l.pool = pool
+	go l.run(ctx)
+}
+
+func (l *SlowQueryLog) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	if ctx.Value(skipKey{}) != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, traceStartKey{}, traceStart{at: time.Now(), sql: data.SQL, args: data.Args})
+}
+
+func (l *SlowQueryLog) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(traceStartKey{}).(traceStart)
+	if !ok {
+		return
+	}
+	elapsed := time.Since(start.at)
+	if elapsed < l.threshold {
+		return
+	}
+
+	labels, _ := ctx.Value(labelsKey{}).(queryLabels)
+	q := slowQuery{
+		at:        start.at,
+		duration:  elapsed,
+		sql:       NormalizeSQL(start.sql),
+		argTypes:  argTypes(start.args),
+		object:    labels.object,
+		procedure: labels.procedure,
+	}
+	if data.Err != nil {
+		q.err = data.Err.Error()
+	}
+	if l.explainSample > 0 && data.Err == nil && isReadOnly(start.sql) && rand.Float64() < l.explainSample {
+		q.sql = start.sql
+		q.args = start.args
+	}
+
+	log.Printf("slow query: %s object=%q procedure=%q args=%v: %s",
+		elapsed.Round(time.Millisecond), q.object, q.procedure, q.argTypes, NormalizeSQL(start.sql))
+
+	select {
+	case l.queue <- q:
+	default:
+		l.mu.Lock()
+		l.dropped++
+		l.mu.Unlock()
+	}
+}
+
+func (l *SlowQueryLog) run(ctx context.Context) {
+	ctx = context.WithValue(ctx, skipKey{}, true)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case q := <-l.queue:
+			if err := l.persist(ctx, q); err != nil {
+				log.Printf("slow query log: %v", err)
+			}
+			l.mu.Lock()
+			if l.dropped > 0 {
+				log.Printf("slow query log: dropped %d entries (queue full)", l.dropped)
+				l.dropped = 0
+			}
+			l.mu.Unlock()
+		}
+	}
+}
+
+func (l *SlowQueryLog) persist(ctx context.Context, q slowQuery) error {
+	var plan []byte
+	if q.args != nil {
+		var err error
+		plan, err = l.explain(ctx, q.sql, q.args)
+		if err != nil {
+			log.Printf("slow query log: explain: %v", err)
+		}
+		q.sql = NormalizeSQL(q.sql)
+	}
+
+	types, err := json.Marshal(q.argTypes)
+	if err != nil {
+		return err
+	}
+	_, err = l.pool.Exec(ctx, `
+		INSERT INTO diagnostics.slow_queries
+			(occurred_at, duration_ms, object_name, procedure, sql, arg_types, plan, error)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), $5, $6::jsonb, $7::jsonb, NULLIF($8, ''))
+	`, q.at, float64(q.duration.Microseconds())/1000, q.object, q.procedure, q.sql, string(types), plan, q.err)
+	if err != nil {
+		return fmt.Errorf("persist: %w", err)
+	}
+	return nil
+}
+
+// explain re-runs a read-only query under EXPLAIN (ANALYZE, BUFFERS) inside a
+// READ ONLY transaction, so even a misclassified write cannot take effect.
+func (l *SlowQueryLog) explain(ctx context.Context, sql string, args []any) ([]byte, error) {
+	tx, err := l.pool.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var plan []byte
+	if err := tx.QueryRow(ctx, "EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) "+sql, args...).Scan(&plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+var (
+	sqlStringLit  = regexp.MustCompile(`'(?:[^']|'')*'`)
+	sqlWhitespace = regexp.MustCompile(`\s+`)
+	// sqlWriteWord matches the keywords of row locks (FOR [NO KEY] UPDATE,
+	// FOR [KEY] SHARE), SELECT INTO and data-modifying statements.
+	sqlWriteWord = regexp.MustCompile(`(?i)\b(UPDATE|SHARE|INTO|INSERT|DELETE|MERGE)\b`)
+)
+
+// NormalizeSQL collapses whitespace and replaces inline string literals with
+// '?', so entries group by query shape and never carry values. Bound
+// arguments are already $N placeholders.
+func NormalizeSQL(sql string) string {
+	sql = sqlStringLit.ReplaceAllString(sql, "'?'")
+	return strings.TrimSpace(sqlWhitespace.ReplaceAllString(sql, " "))
+}
+
+// argTypes describes bound arguments by Go type only; values may be PII.
+func argTypes(args []any) []string {
+	types := make([]string, len(args))
+	for i, a := range args {
+		types[i] = fmt.Sprintf("%T", a)
+	}
+	return types
+}
+
+// isReadOnly reports whether sql is a plain SELECT that is safe to re-run.
+// Anything else, including WITH queries (which may hold an INSERT), row
+// locking SELECTs and SELECT INTO, is not; string literals are ignored so
+// a value cannot hide or fake a keyword.
+func isReadOnly(sql string) bool {
+	s := NormalizeSQL(sql)
+	if len(s) < len("SELECT") || !strings.EqualFold(s[:len("SELECT")], "SELECT") {
+		return false
+	}
+	return !sqlWriteWord.MatchString(s)
+}
@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func TestNormalizeSQL(t *testing.T) {
+	for in, want := range map[string]string{
+		"SELECT 1": "SELECT 1",
+		"  SELECT *\n\tFROM t\n  WHERE id = $1  ":     "SELECT * FROM t WHERE id = $1",
+		"SELECT * FROM t WHERE name = 'Ada Lovelace'": "SELECT * FROM t WHERE name = '?'",
+		"SELECT 'it''s', 'x' || 'y'":                  "SELECT '?', '?' || '?'",
+		"SELECT ''":                                   "SELECT '?'",
+		"SELECT 'multi\n  line'":                      "SELECT '?'",
+		`SELECT "data"->>'email' FROM t`:              `SELECT "data"->>'?' FROM t`,
+	} {
+		if got := NormalizeSQL(in); got != want {
+			t.Errorf("NormalizeSQL(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestIsReadOnly(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want bool
+	}{
+		{"SELECT * FROM core.employees WHERE id = $1", true},
+		{"  select count(*) from metadata.records", true},
+		{`SELECT "updated_at", "share_count" FROM t`, true},
+		{"SELECT * FROM t WHERE note = 'for update'", true},
+		{"SELECT * FROM t WHERE id = $1 FOR UPDATE", false},
+		{"SELECT * FROM t WHERE id = $1 for update of t", false},
+		{"SELECT * FROM t FOR\n\tUPDATE", false},
+		{"SELECT * FROM t FOR NO KEY UPDATE", false},
+		{"SELECT * FROM t FOR SHARE", false},
+		{"SELECT * FROM t FOR KEY SHARE SKIP LOCKED", false},
+		{"SELECT * INTO copy FROM t", false},
+		{"WITH ins AS (INSERT INTO t (id) VALUES ($1) RETURNING id) SELECT * FROM ins", false},
+		{"WITH d AS (DELETE FROM t RETURNING *) SELECT count(*) FROM d", false},
+		{"WITH x AS (SELECT 1) SELECT * FROM x", false},
+		{"INSERT INTO t SELECT * FROM u", false},
+		{"UPDATE t SET a = 1", false},
+		{"DELETE FROM t", false},
+		{"EXPLAIN ANALYZE DELETE FROM t", false},
+		{"SEL", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isReadOnly(tt.sql); got != tt.want {
+			t.Errorf("isReadOnly(%q) = %t, want %t", tt.sql, got, tt.want)
+		}
+	}
+}
+
+// trace runs a query through l's tracer hooks as pgx would.
+func trace(l *SlowQueryLog, sql string, args []any, err error) {
+	ctx := l.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: sql, Args: args})
+	l.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: err})
+}
+
+func TestSlowQueryLogQueue(t *testing.T) {
+	out := log.Writer()
+	log.SetOutput(io.Discard)
+	t.Cleanup(func() { log.SetOutput(out) })
+
+	l := NewSlowQueryLog(0, 1)
+
+	// With every plan sampled, only successful reads keep their arguments
+	// for EXPLAIN; entries otherwise hold the normalized SQL and arg types.
+	trace(l, "SELECT * FROM t WHERE name = $1 AND  kind = 'x'", []any{"Ada"}, nil)
+	trace(l, "SELECT * FROM t WHERE id = $1 FOR UPDATE", []any{1}, nil)
+	trace(l, "SELECT * FROM t WHERE id = $1", []any{1}, errors.New("canceled"))
+	trace(l, "UPDATE t SET name = $1", []any{"Ada"}, nil)
+
+	read := <-l.queue
+	if read.args == nil || read.sql != "SELECT * FROM t WHERE name = $1 AND  kind = 'x'" {
+		t.Errorf("sampled read = %q with args %v, want the original SQL and args", read.sql, read.args)
+	}
+	for _, what := range []string{"locking read", "failed read", "write"} {
+		q := <-l.queue
+		if q.args != nil {
+			t.Errorf("%s kept its arguments for EXPLAIN: %q", what, q.sql)
+		}
+		if len(q.argTypes) != 1 || q.sql != NormalizeSQL(q.sql) {
+			t.Errorf("%s: sql %q, arg types %v", what, q.sql, q.argTypes)
+		}
+	}
+
+	// Once the queue is full, entries are dropped and counted instead of
+	// blocking the query.
+	for range slowQueryQueueSize + 3 {
+		trace(l, "SELECT 1", nil, nil)
+	}
+	if len(l.queue) != slowQueryQueueSize || l.dropped != 3 {
+		t.Errorf("queue holds %d, dropped %d; want %d and 3", len(l.queue), l.dropped, slowQueryQueueSize)
+	}
+
+	// Queries under the threshold are not queued, nor are the log's own
+	// inserts.
+	fast := NewSlowQueryLog(time.Hour, 0)
+	trace(fast, "SELECT 1", nil, nil)
+	if len(fast.queue) != 0 {
+		t.Errorf("query under the threshold queued")
+	}
+	own := NewSlowQueryLog(0, 0)
+	ctx := own.TraceQueryStart(context.WithValue(context.Background(), skipKey{}, true), nil, pgx.TraceQueryStartData{SQL: "INSERT INTO diagnostics.slow_queries"})
+	own.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+	if len(own.queue) != 0 {
+		t.Errorf("the log's own insert was queued")
+	}
+}
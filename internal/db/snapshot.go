@@ -0,0 +1,162 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	// ErrSnapshotExpired is returned by Snapshots.Run when the snapshot is no
+	// longer held, because its TTL passed or the holding server restarted.
+	ErrSnapshotExpired = errors.New("snapshot expired")
+	// ErrSnapshotLimit is returned by Snapshots.Export when the maximum number
+	// of snapshots is already held, or snapshots are disabled.
+	ErrSnapshotLimit = errors.New("too many open snapshots")
+)
+
+// snapshotPoolShare is the fraction of the pool's connections (1/n) snapshots
+// may hold at once, so held snapshots leave room for every other query,
+// including the reads that import them.
+const snapshotPoolShare = 4
+
+// snapshotIDPattern matches identifiers returned by pg_export_snapshot(). IDs
+// are interpolated into SET TRANSACTION SNAPSHOT, which takes no parameters.
+var snapshotIDPattern = regexp.MustCompile(`^[0-9A-F]+-[0-9A-F]+(-[0-9]+)?$`)
+
+// Snapshots holds exported REPEATABLE READ snapshots so successive requests
+// can read the database as of the same instant. Each snapshot keeps one pool
+// connection in an open read-only transaction until its TTL passes; any
+// server instance connected to the same database can import it meanwhile.
+type Snapshots struct {
+	pool *pgxpool.Pool
+	ttl  time.Duration
+	max  int
+
+	mu   sync.Mutex
+	held map[string]heldSnapshot
+	// opening counts Exports between taking a slot and holding a snapshot.
+	opening int
+}
+
+type heldSnapshot struct {
+	tx      pgx.Tx
+	expires time.Time
+}
+
+// NewSnapshots returns a snapshot holder keeping at most max snapshots open
+// for ttl each (max 0 disables snapshots). max is capped at a quarter of the
+// pool's connections (snapshotPoolShare). Expired snapshots are released
+// once Start has been called.
+func NewSnapshots(pool *pgxpool.Pool, ttl time.Duration, max int) *Snapshots {
+	if limit := int(pool.Config().MaxConns) / snapshotPoolShare; max > limit {
+		log.Printf("snapshots: capping SNAPSHOT_MAX %d at %d, a quarter of the pool's %d connections", max, limit, pool.Config().MaxConns)
+		max = limit
+	}
+	return &Snapshots{pool: pool, ttl: ttl, max: max, held: make(map[string]heldSnapshot)}
+}
+
+// Start releases expired snapshots until ctx is done, then releases the rest.
+func (s *Snapshots) Start(ctx context.Context) {
+	go func() {
+		t := time.NewTicker(min(s.ttl/4, time.Minute))
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				s.release(func(heldSnapshot) bool { return true })
+				return
+			case now := <-t.C:
+				s.release(func(h heldSnapshot) bool { return now.After(h.expires) })
+			}
+		}
+	}()
+}
+
+// Export pins the current database state and returns its snapshot ID and
+// when it expires.
+func (s *Snapshots) Export(ctx context.Context) (string, time.Time, error) {
+	// The slot is taken before the transaction begins, so concurrent
+	// Exports cannot hold more than max between them.
+	s.mu.Lock()
+	if len(s.held)+s.opening >= s.max {
+		s.mu.Unlock()
+		return "", time.Time{}, ErrSnapshotLimit
+	}
+	s.opening++
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.opening--
+		s.mu.Unlock()
+	}()
+
+	// The holding transaction outlives the request, so it must not be
+	// cancelled with the request context.
+	tx, err := s.pool.BeginTx(context.WithoutCancel(ctx), pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("begin snapshot: %w", err)
+	}
+	var id string
+	if err := tx.QueryRow(ctx, "SELECT pg_export_snapshot()").Scan(&id); err != nil {
+		tx.Rollback(context.Background())
+		return "", time.Time{}, fmt.Errorf("export snapshot: %w", err)
+	}
+
+	expires := time.Now().Add(s.ttl)
+	s.mu.Lock()
+	s.held[id] = heldSnapshot{tx: tx, expires: expires}
+	s.mu.Unlock()
+	return id, expires, nil
+}
+
+// Run calls fn in a read-only transaction that sees the database as of the
+// snapshot id.
+func (s *Snapshots) Run(ctx context.Context, id string, fn func(pgx.Tx) error) error {
+	if !snapshotIDPattern.MatchString(id) {
+		return ErrSnapshotExpired
+	}
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return fmt.Errorf("begin snapshot read: %w", err)
+	}
+	defer tx.Rollback(context.Background())
+
+	if _, err := tx.Exec(ctx, "SET TRANSACTION SNAPSHOT '"+id+"'"); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "22023" { // invalid_parameter_value: snapshot not found
+			return ErrSnapshotExpired
+		}
+		return fmt.Errorf("import snapshot: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (s *Snapshots) release(expired func(heldSnapshot) bool) {
+	s.mu.Lock()
+	var done []heldSnapshot
+	for id, h := range s.held {
+		if expired(h) {
+			done = append(done, h)
+			delete(s.held, id)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, h := range done {
+		if err := h.tx.Rollback(context.Background()); err != nil {
+			log.Printf("release snapshot: %v", err)
+		}
+	}
+}
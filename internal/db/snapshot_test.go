@@ -0,0 +1,49 @@
+package db_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/atlekbai/schema_registry/internal/db"
+	"github.com/atlekbai/schema_registry/internal/testutil"
+)
+
+func TestIntegrationSnapshotLimit(t *testing.T) {
+	pool := testutil.NewDatabase(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Asking for more snapshots than connections gets a quarter of the pool.
+	snapshots := db.NewSnapshots(pool, time.Minute, 1000)
+	snapshots.Start(ctx)
+	limit := int(pool.Config().MaxConns) / 4
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		exported int
+		refused  int
+	)
+	for range 4 * limit {
+		wg.Go(func() {
+			_, _, err := snapshots.Export(ctx)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				exported++
+			case errors.Is(err, db.ErrSnapshotLimit):
+				refused++
+			default:
+				t.Errorf("export: %v", err)
+			}
+		})
+	}
+	wg.Wait()
+	if exported != limit || refused != 3*limit {
+		t.Errorf("exported %d and refused %d snapshots, want %d and %d", exported, refused, limit, 3*limit)
+	}
+}
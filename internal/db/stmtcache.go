@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// stmtCachePrefix is the name pgx gives the statements it prepares into a
+// connection's statement cache (stmtcache.StatementName).
+const stmtCachePrefix = "stmtcache_"
+
+// StatementCacheStats is a pgx tracer counting how often queries find their
+// statement already prepared on the connection. Only queries with arguments
+// are counted: pgx runs the others over the simple protocol, without the
+// cache. It implements prometheus.Collector, reporting
+// db_statement_cache_lookups_total by result (hit or miss).
+type StatementCacheStats struct {
+	lookups atomic.Int64
+	misses  atomic.Int64
+
+	lookupsDesc *prometheus.Desc
+}
+
+// NewStatementCacheStats returns a StatementCacheStats to pass to NewPool.
+func NewStatementCacheStats() *StatementCacheStats {
+	return &StatementCacheStats{
+		lookupsDesc: prometheus.NewDesc("db_statement_cache_lookups_total",
+			"Queries looked up in the connection statement cache, by result.", []string{"result"}, nil),
+	}
+}
+
+// Hits and Misses return the lookups counted so far.
+func (s *StatementCacheStats) Hits() int64   { return s.lookups.Load() - s.misses.Load() }
+func (s *StatementCacheStats) Misses() int64 { return s.misses.Load() }
+
+func (s *StatementCacheStats) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	if len(data.Args) > 0 {
+		s.lookups.Add(1)
+	}
+	return ctx
+}
+
+func (s *StatementCacheStats) TraceQueryEnd(context.Context, *pgx.Conn, pgx.TraceQueryEndData) {}
+
+// TracePrepareStart counts a miss: pgx only prepares a cached statement when
+// the connection does not hold it yet.
+func (s *StatementCacheStats) TracePrepareStart(ctx context.Context, _ *pgx.Conn, data pgx.TracePrepareStartData) context.Context {
+	if strings.HasPrefix(data.Name, stmtCachePrefix) {
+		s.misses.Add(1)
+	}
+	return ctx
+}
+
+func (s *StatementCacheStats) TracePrepareEnd(context.Context, *pgx.Conn, pgx.TracePrepareEndData) {}
+
+func (s *StatementCacheStats) Describe(ch chan<- *prometheus.Desc) {
+	ch <- s.lookupsDesc
+}
+
+func (s *StatementCacheStats) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(s.lookupsDesc, prometheus.CounterValue, float64(s.Hits()), "hit")
+	ch <- prometheus.MustNewConstMetric(s.lookupsDesc, prometheus.CounterValue, float64(s.Misses()), "miss")
+}
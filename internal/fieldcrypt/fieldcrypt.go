@@ -0,0 +1,83 @@
+// Package fieldcrypt encrypts ENCRYPTED field values at the application layer
+// with AES-256-GCM so plaintext PII never reaches the database.
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeySize is the required key length in bytes (AES-256).
+const KeySize = 32
+
+// prefix versions the ciphertext format so keys or algorithms can be rotated.
+const prefix = "v1:"
+
+// ErrNoKey is returned by a nil Cipher: no encryption key is configured.
+var ErrNoKey = errors.New("no field encryption key configured")
+
+// Cipher encrypts and decrypts field values. A nil *Cipher is valid and fails
+// every operation with ErrNoKey.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// New returns a Cipher for a 32-byte key.
+func New(key []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("field encryption key must be %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// Encrypt seals plaintext and returns "v1:" + base64(nonce || ciphertext).
+// aad binds the ciphertext to its context (the field ID), so a value copied
+// into another field fails to decrypt.
+func (c *Cipher) Encrypt(plaintext, aad []byte) (string, error) {
+	if c == nil {
+		return "", ErrNoKey
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generate nonce: %w", err)
+	}
+	sealed := c.aead.Seal(nonce, nonce, plaintext, aad)
+	return prefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value produced by Encrypt with the same aad.
+func (c *Cipher) Decrypt(token string, aad []byte) ([]byte, error) {
+	if c == nil {
+		return nil, ErrNoKey
+	}
+	enc, ok := strings.CutPrefix(token, prefix)
+	if !ok {
+		return nil, fmt.Errorf("unsupported ciphertext format")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(enc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ciphertext encoding")
+	}
+	n := c.aead.NonceSize()
+	if len(sealed) < n {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	plaintext, err := c.aead.Open(nil, sealed[:n], sealed[n:], aad)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt: %w", err)
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,98 @@
+package hrql
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/atlekbai/schema_registry/internal/hrql/parser"
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// calendarVal is a calendar bucket helper in where value position:
+// start_of_month(), end_of_quarter(), this_year(), ... The period is
+// [start, next) in the request time zone.
+type calendarVal struct {
+	fn          string
+	start, next time.Time
+}
+
+// calendarPeriod returns the month, quarter or year containing now.
+func calendarPeriod(unit string, now time.Time) (start, next time.Time) {
+	y, m, _ := now.Date()
+	loc := now.Location()
+	switch unit {
+	case "month":
+		start = time.Date(y, m, 1, 0, 0, 0, 0, loc)
+		return start, start.AddDate(0, 1, 0)
+	case "quarter":
+		start = time.Date(y, m-(m-1)%3, 1, 0, 0, 0, 0, loc)
+		return start, start.AddDate(0, 3, 0)
+	default:
+		start = time.Date(y, time.January, 1, 0, 0, 0, 0, loc)
+		return start, start.AddDate(1, 0, 0)
+	}
+}
+
+// compileCalendar resolves a calendar helper against the compiler's clock.
+func (c *Compiler) compileCalendar(fn *parser.FuncCall) calendarVal {
+	unit := fn.Name[strings.LastIndexByte(fn.Name, '_')+1:]
+	now := c.now
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+	start, next := calendarPeriod(unit, now)
+	return calendarVal{fn: fn.Name, start: start, next: next}
+}
+
+// compare builds field op v. start_of_* stands for the first day of the
+// period and end_of_* for the last; <= and > against end_of_* cover that
+// whole day, so DATETIME fields compare as expected. this_* is the whole
+// period: == tests membership, < means before it and > after it.
+func (v calendarVal) compare(pos int, op string, f fieldRef, fd *schema.FieldDef) (Condition, error) {
+	if fd != nil && classOf(fd) != classTime && classOf(fd) != classAny {
+		return nil, typeErr(pos, "%s is %s; %s() can only be compared with a DATE or DATETIME field", fieldName(f.chain), fd.Type, v.fn)
+	}
+	datetime := fd != nil && fd.Type == schema.FieldDatetime
+	cmp := func(op string, t time.Time) FieldCmp {
+		value := t.Format(time.DateOnly)
+		if datetime {
+			value = t.Format(time.RFC3339)
+		}
+		return FieldCmp{Field: f.chain, Op: op, Value: value, Default: f.def}
+	}
+
+	switch {
+	case strings.HasPrefix(v.fn, "start_of_"):
+		return cmp(op, v.start), nil
+	case strings.HasPrefix(v.fn, "end_of_"):
+		switch op {
+		case "<=":
+			return cmp("<", v.next), nil
+		case ">":
+			return cmp(">=", v.next), nil
+		}
+		return cmp(op, v.next.AddDate(0, 0, -1)), nil
+	}
+	switch op {
+	case "==":
+		return AndCond{Left: cmp(">=", v.start), Right: cmp("<", v.next)}, nil
+	case "!=":
+		return OrCond{Left: cmp("<", v.start), Right: cmp(">=", v.next)}, nil
+	case "<", ">=":
+		return cmp(op, v.start), nil
+	case "<=":
+		return cmp("<", v.next), nil
+	default: // >
+		return cmp(">=", v.next), nil
+	}
+}
+
+// compareCalendar compiles other op v, where other must be a date field.
+func (c *Compiler) compareCalendar(op *parser.BinaryOp, cmpOp string, other any, v calendarVal) (Condition, error) {
+	f, ok := other.(fieldRef)
+	if !ok {
+		return nil, fmt.Errorf("%s() can only be compared with a date field", v.fn)
+	}
+	return v.compare(op.Pos, cmpOp, f, c.fieldAt(c.obj, f.chain))
+}
@@ -1,7 +1,14 @@
 package hrql
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
 
 	"github.com/atlekbai/schema_registry/internal/hrql/parser"
 	"github.com/atlekbai/schema_registry/internal/schema"
@@ -15,8 +22,23 @@ func (c *Compiler) compileWhereCond(node parser.Node) (Condition, error) {
 		return c.compileWhereOp(n)
 	case *parser.FuncCall:
 		return c.compileWhereFuncCall(n)
+	case *parser.InExpr:
+		return c.compileIn(n)
 	case *parser.PipeExpr:
 		if cond, ok := c.tryCompileStringOp(n); ok {
+			m := cond.(StringMatch)
+			fd := c.obj.FieldsByAPIName[m.Field[0]]
+			if err := checkComparable(fd); err != nil {
+				return nil, err
+			}
+			if err := fd.CheckFilterable(); err != nil {
+				return nil, err
+			}
+			if fd.Type == schema.FieldChoice {
+				if labels := fd.OptionLabels(); len(labels) > 0 {
+					return InFilter{Field: m.Field[:1], Values: choiceMatches(m, labels)}, nil
+				}
+			}
 			return cond, nil
 		}
 		return c.compileWhereSubquery(n)
@@ -68,10 +90,37 @@ func (c *Compiler) compileComparison(op *parser.BinaryOp) (Condition, error) {
 		return nil, fmt.Errorf("where right: %w", err)
 	}
 
+	// tenure(.start_date) > 2y, .start_date | years_since >= 5
+	if d, ok := left.(durationVal); ok {
+		return d.compare(op.Op, right)
+	}
+	if d, ok := right.(durationVal); ok {
+		return d.compare(reverseOp(op.Op), left)
+	}
+	if v, ok := right.(calendarVal); ok {
+		return c.compareCalendar(op, op.Op, left, v)
+	}
+	if v, ok := left.(calendarVal); ok {
+		return c.compareCalendar(op, reverseOp(op.Op), right, v)
+	}
+	if _, ok := left.(durationLit); ok {
+		return nil, fmt.Errorf("durations can only be compared with tenure()")
+	}
+	if _, ok := right.(durationLit); ok {
+		return nil, fmt.Errorf("durations can only be compared with tenure()")
+	}
+
+	if err := c.checkComparison(op, left, right); err != nil {
+		return nil, err
+	}
+
 	// field == literal or field == field
 	if f, ok := left.(fieldRef); ok {
 		if lit, ok := right.(literalVal); ok {
-			return FieldCmp{Field: f.chain, Op: op.Op, Value: string(lit)}, nil
+			return FieldCmp{Field: f.chain, Op: op.Op, Value: string(lit), Default: f.def}, nil
+		}
+		if f.def != nil {
+			return nil, fmt.Errorf("a field with a ?? default can only be compared with a literal")
 		}
 		if rf, ok := right.(fieldRef); ok {
 			return FieldCmp{Field: f.chain, Op: op.Op, Value: "field:" + joinChain(rf.chain)}, nil
@@ -83,22 +132,145 @@ func (c *Compiler) compileComparison(op *parser.BinaryOp) (Condition, error) {
 
 	if f, ok := right.(fieldRef); ok {
 		if lit, ok := left.(literalVal); ok {
-			return FieldCmp{Field: f.chain, Op: reverseOp(op.Op), Value: string(lit)}, nil
+			return FieldCmp{Field: f.chain, Op: reverseOp(op.Op), Value: string(lit), Default: f.def}, nil
 		}
 	}
 
 	// subquery comparison: left is a subquery
 	if sub, ok := left.(subqueryVal); ok {
 		if lit, ok := right.(literalVal); ok {
-			sub.cond.Op = op.Op
-			sub.cond.Value = string(lit)
-			return sub.cond, nil
+			return sub.compare(op.Op, string(lit)), nil
 		}
 	}
 
 	return nil, fmt.Errorf("unsupported comparison operands")
 }
 
+// compileIn compiles `.field in (a, b, ...)` and `.field in <list>`. Literal
+// values become an InFilter and employee references (self.department) an
+// equality each, OR'd together; a list source becomes an InSubquery.
+func (c *Compiler) compileIn(n *parser.InExpr) (Condition, error) {
+	left, err := c.compileWhereValue(n.Left)
+	if err != nil {
+		return nil, fmt.Errorf("in left: %w", err)
+	}
+	f, ok := left.(fieldRef)
+	if !ok || f.def != nil || len(f.chain) != 1 {
+		return nil, fmt.Errorf("in requires a single field on the left, e.g. .department in (...)")
+	}
+
+	source := n.Source
+	if len(n.Values) == 1 && isListSource(n.Values[0]) {
+		source = n.Values[0]
+	}
+	if source != nil {
+		return c.compileInSubquery(f.chain[0], source)
+	}
+
+	fd := c.fieldAt(c.obj, f.chain)
+	if err := checkOperator(n.Pos, "in", fd, fieldName(f.chain)); err != nil {
+		return nil, err
+	}
+
+	var (
+		literals []string
+		cond     Condition
+	)
+	for _, v := range n.Values {
+		val, err := c.compileWhereValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("in value: %w", err)
+		}
+		switch val := val.(type) {
+		case literalVal:
+			if err := checkLiteral(n.Pos, fd, f.chain, whereLiteral(v)); err != nil {
+				return nil, err
+			}
+			literals = append(literals, string(val))
+		case empRefVal:
+			cond = orCond(cond, FieldCmpRef{Field: f.chain, Op: "==", Ref: val.ref})
+		default:
+			return nil, fmt.Errorf("in values must be literals or self fields; use a list source for subqueries")
+		}
+	}
+	if len(literals) > 0 {
+		cond = orCond(InFilter{Field: f.chain, Values: literals}, cond)
+	}
+	return cond, nil
+}
+
+// isListSource reports whether an in value is a list to take ids from rather
+// than a single value: an object, a source function such as chain(self), or
+// a pipe not ending in a field access (self.department is a value).
+func isListSource(node parser.Node) bool {
+	switch n := node.(type) {
+	case *parser.IdentExpr:
+		return true
+	case *parser.FuncCall:
+		return SourceCalls[n.Name] != nil
+	case *parser.PipeExpr:
+		_, value := n.Steps[len(n.Steps)-1].(*parser.FieldAccess)
+		return !value
+	}
+	return false
+}
+
+// compileInSubquery compiles .field in source. The source is compiled as a
+// query of its own: '.field' inside it refers to its records, so it cannot
+// depend on the outer record.
+func (c *Compiler) compileInSubquery(field string, source parser.Node) (Condition, error) {
+	prev := c.obj
+	defer func() { c.obj = prev }()
+	c.obj = c.empObj
+	if root := rootIdent(source); root != nil && root.Name != "employees" {
+		obj := c.cache.Get(root.Name)
+		if obj == nil {
+			return nil, fmt.Errorf("unknown identifier %q", root.Name)
+		}
+		c.obj = obj
+	}
+	plan, err := c.compileNode(source)
+	if err != nil {
+		return nil, fmt.Errorf("in source: %w", err)
+	}
+	if plan.Kind != PlanList || plan.AggField != "" || plan.Case != nil {
+		return nil, fmt.Errorf("in source must be a list of records, got %v", plan.Kind)
+	}
+	if plan.PickOp != "" || plan.Sample > 0 || plan.AsOf != nil || plan.Union != nil {
+		return nil, fmt.Errorf("in source cannot pick, sample, use as_of or union")
+	}
+
+	// The field must hold ids of the source's object.
+	srcName := cmp.Or(plan.Object, c.empObj.APIName)
+	fd := prev.FieldsByAPIName[field]
+	target := ""
+	switch {
+	case fd.APIName == "id":
+		target = prev.APIName
+	case fd.Type == schema.FieldLookup && fd.LookupObjectID != nil:
+		if obj := c.cache.GetByID(*fd.LookupObjectID); obj != nil {
+			target = obj.APIName
+		}
+	default:
+		return nil, fmt.Errorf("in with a list compares record ids; .%s is neither id nor a LOOKUP", field)
+	}
+	if target != srcName {
+		return nil, fmt.Errorf("in: .%s refers to %s, but the list is of %s", field, target, srcName)
+	}
+	return InSubquery{Field: field, Source: plan}, nil
+}
+
+// orCond ORs two conditions, either of which may be nil.
+func orCond(left, right Condition) Condition {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	}
+	return OrCond{Left: left, Right: right}
+}
+
 // compileWhereValue compiles a value expression inside a where condition.
 // Returns a fieldRef, literalVal, empRefVal, or subqueryVal.
 func (c *Compiler) compileWhereValue(node parser.Node) (any, error) {
@@ -108,13 +280,28 @@ func (c *Compiler) compileWhereValue(node parser.Node) (any, error) {
 	case *parser.DotExpr:
 		return nil, fmt.Errorf("bare '.' in where condition; use '.field' to access a field")
 	case *parser.Literal:
+		if n.Kind == parser.TokDuration {
+			interval, err := DurationInterval(n.Value)
+			if err != nil {
+				return nil, err
+			}
+			return durationLit(interval), nil
+		}
 		return literalVal(n.Value), nil
 	case *parser.SelfExpr:
 		return literalVal(c.selfID), nil
 	case *parser.PipeExpr:
+		if d, ok, err := c.trySinceValue(n); ok {
+			return d, err
+		}
 		return c.compileSelfFieldLookup(n)
 	case *parser.FuncCall:
 		return c.compileWhereFuncValue(n)
+	case *parser.BinaryOp:
+		if n.Op == "??" {
+			return c.compileWhereDefault(n)
+		}
+		return nil, fmt.Errorf("unsupported operator %q in where value", n.Op)
 	case *parser.UnaryMinus:
 		inner, err := c.compileWhereValue(n.Expr)
 		if err != nil {
@@ -129,6 +316,29 @@ func (c *Compiler) compileWhereValue(node parser.Node) (any, error) {
 	}
 }
 
+// compileWhereDefault compiles `.field ?? literal`: the field compares as
+// the literal wherever it is NULL.
+func (c *Compiler) compileWhereDefault(op *parser.BinaryOp) (any, error) {
+	left, err := c.compileWhereValue(op.Left)
+	if err != nil {
+		return nil, err
+	}
+	f, ok := left.(fieldRef)
+	if !ok || f.def != nil {
+		return nil, fmt.Errorf("?? in where needs a field on the left")
+	}
+	right, err := c.compileWhereValue(op.Right)
+	if err != nil {
+		return nil, err
+	}
+	lit, ok := right.(literalVal)
+	if !ok {
+		return nil, fmt.Errorf("?? in where needs a literal default")
+	}
+	f.def = new(string(lit))
+	return f, nil
+}
+
 // resolveFieldRef validates a field access chain and returns a fieldRef.
 func (c *Compiler) resolveFieldRef(fa *parser.FieldAccess) (any, error) {
 	if len(fa.Chain) == 0 {
@@ -136,10 +346,16 @@ func (c *Compiler) resolveFieldRef(fa *parser.FieldAccess) (any, error) {
 	}
 
 	fieldName := fa.Chain[0]
-	fd, ok := c.empObj.FieldsByAPIName[fieldName]
+	fd, ok := c.field(c.obj, fieldName)
 	if !ok {
 		return nil, fmt.Errorf("unknown field %q", fieldName)
 	}
+	if err := checkComparable(fd); err != nil {
+		return nil, err
+	}
+	if err := fd.CheckFilterable(); err != nil {
+		return nil, err
+	}
 
 	if len(fa.Chain) == 1 {
 		return fieldRef{chain: fa.Chain}, nil
@@ -157,10 +373,16 @@ func (c *Compiler) resolveFieldRef(fa *parser.FieldAccess) (any, error) {
 
 	for i := 1; i < len(fa.Chain); i++ {
 		nextFieldName := fa.Chain[i]
-		nextFd, ok := currentObj.FieldsByAPIName[nextFieldName]
+		nextFd, ok := c.field(currentObj, nextFieldName)
 		if !ok {
 			return nil, fmt.Errorf("unknown field %q on %s", nextFieldName, currentObj.APIName)
 		}
+		if err := checkComparable(nextFd); err != nil {
+			return nil, err
+		}
+		if err := nextFd.CheckFilterable(); err != nil {
+			return nil, err
+		}
 
 		if i < len(fa.Chain)-1 {
 			if nextFd.Type != schema.FieldLookup || nextFd.LookupObjectID == nil {
@@ -177,12 +399,15 @@ func (c *Compiler) resolveFieldRef(fa *parser.FieldAccess) (any, error) {
 }
 
 // compileSelfFieldLookup returns an empRefVal for self.field (deferred to SQL).
-// Delegates to resolveEmployeeArg for validation (validates all chain fields, not just the first).
+// Delegates to resolvePipeRef for validation (validates all chain fields, not just the first).
 func (c *Compiler) compileSelfFieldLookup(pipe *parser.PipeExpr) (any, error) {
 	if len(pipe.Steps) == 2 {
 		if _, ok := pipe.Steps[0].(*parser.SelfExpr); ok {
 			if _, ok := pipe.Steps[1].(*parser.FieldAccess); ok {
-				ref, err := c.resolveEmployeeArg(pipe)
+				if err := c.requireEmployees("self.field"); err != nil {
+					return nil, err
+				}
+				ref, err := c.resolvePipeRef(pipe, true)
 				if err != nil {
 					return nil, err
 				}
@@ -199,11 +424,11 @@ func (c *Compiler) compileWhereSubqueryValue(pipe *parser.PipeExpr) (any, error)
 	if err != nil {
 		return nil, err
 	}
-	sub, ok := cond.(SubqueryAgg)
-	if !ok {
-		return nil, fmt.Errorf("expected subquery aggregate in value position")
+	switch cond.(type) {
+	case SubqueryAgg, RelatedAgg:
+		return subqueryVal{cond: cond}, nil
 	}
-	return subqueryVal{cond: sub}, nil
+	return nil, fmt.Errorf("expected subquery aggregate in value position")
 }
 
 // compileWhereSubquery compiles a pipe expression as a scalar subquery inside a where condition.
@@ -212,10 +437,16 @@ func (c *Compiler) compileWhereSubquery(pipe *parser.PipeExpr) (Condition, error
 		return nil, fmt.Errorf("subquery in where requires at least 2 pipe steps (source | aggregate)")
 	}
 
+	if rel, ok := pipe.Steps[0].(*parser.RelationExpr); ok {
+		return c.compileRelationAgg(rel, pipe.Steps[1:])
+	}
 	fn, ok := pipe.Steps[0].(*parser.FuncCall)
 	if !ok {
 		return nil, fmt.Errorf("subquery source must be a function call, got %T", pipe.Steps[0])
 	}
+	if err := c.requireEmployees(fn.Name + "()"); err != nil {
+		return nil, err
+	}
 
 	aggOp := ""
 	for _, step := range pipe.Steps[1:] {
@@ -242,13 +473,135 @@ func (c *Compiler) compileWhereSubquery(pipe *parser.PipeExpr) (Condition, error
 		}
 	}
 
-	return SubqueryAgg{OrgFunc: fn.Name, Depth: depth, AggFunc: aggOp}, nil
+	via, err := c.resolveVia(fn)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireTree(fn, via); err != nil {
+		return nil, err
+	}
+
+	employed, err := c.excludeTerminated(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	return SubqueryAgg{OrgFunc: fn.Name, Depth: depth, Via: via, AggFunc: aggOp, Employed: employed}, nil
+}
+
+// compileRelationAgg compiles a relation in a where subquery, e.g.
+// employees(.) | where(.status == "active") | .salary | avg > 100000: the
+// related records pointing at the outer one, filtered by where steps and
+// aggregated over an optional field. The where steps resolve fields against
+// the related object.
+func (c *Compiler) compileRelationAgg(rel *parser.RelationExpr, steps []parser.Node) (Condition, error) {
+	related := c.cache.Get(rel.Object)
+	if related == nil {
+		return nil, fmt.Errorf("unknown object %q", rel.Object)
+	}
+	via, err := c.relationVia(rel, related)
+	if err != nil {
+		return nil, err
+	}
+
+	agg := RelatedAgg{Object: related.APIName, Via: via}
+	outer := c.obj
+	c.obj = related
+	defer func() { c.obj = outer }()
+
+	for i, step := range steps {
+		if agg.AggFunc != "" {
+			return nil, fmt.Errorf("%s(.): the aggregation must be the last step", rel.Object)
+		}
+		switch s := step.(type) {
+		case *parser.WhereExpr:
+			if agg.AggField != "" {
+				return nil, fmt.Errorf("%s(.): where must come before the field", rel.Object)
+			}
+			cond, err := c.compileWhereCond(s.Cond)
+			if err != nil {
+				return nil, fmt.Errorf("%s(.) where: %w", rel.Object, err)
+			}
+			agg.Conditions = append(agg.Conditions, cond)
+		case *parser.FieldAccess:
+			if len(s.Chain) != 1 {
+				return nil, fmt.Errorf("%s(.): expected single field (.field), got .%s", rel.Object, joinChain(s.Chain))
+			}
+			fd, ok := c.field(related, s.Chain[0])
+			if !ok {
+				return nil, fmt.Errorf("unknown field %q on %s", s.Chain[0], related.APIName)
+			}
+			if err := checkComparable(fd); err != nil {
+				return nil, err
+			}
+			agg.AggField = fd.APIName
+		case *parser.FuncCall:
+			if s.Name != "unique" || agg.AggField == "" {
+				return nil, fmt.Errorf("%s(.): %s() is not supported in a subquery; only unique after a field is", rel.Object, s.Name)
+			}
+			agg.Distinct = true
+		case *parser.AggExpr:
+			if s.Op != "count" && agg.AggField == "" {
+				return nil, fmt.Errorf("%s(.) | %s needs a field, e.g. %s(.) | .salary | %s", rel.Object, s.Op, rel.Object, s.Op)
+			}
+			if (s.Op == "sum" || s.Op == "avg") && !related.FieldsByAPIName[agg.AggField].IsNumeric() {
+				return nil, fmt.Errorf("%s(.) | %s: field %q is not numeric", rel.Object, s.Op, agg.AggField)
+			}
+			agg.AggFunc = s.Op
+		default:
+			return nil, fmt.Errorf("unsupported step %T in %s(.) subquery (step %d)", step, rel.Object, i+2)
+		}
+	}
+	if agg.AggFunc == "" {
+		return nil, fmt.Errorf("where subquery must end with an aggregation (count, sum, avg, min, max)")
+	}
+	return agg, nil
+}
+
+// relationVia returns the LOOKUP field of related that points at the current
+// object: the via argument, or the only such field.
+func (c *Compiler) relationVia(rel *parser.RelationExpr, related *schema.ObjectDef) (string, error) {
+	points := func(fd *schema.FieldDef) bool {
+		return fd.Type == schema.FieldLookup && fd.LookupObjectID != nil && *fd.LookupObjectID == c.obj.ID
+	}
+
+	if rel.Via != nil {
+		if len(rel.Via.Chain) != 1 {
+			return "", fmt.Errorf("%s(.) via: expected a single field (.field)", rel.Object)
+		}
+		fd, ok := c.field(related, rel.Via.Chain[0])
+		if !ok {
+			return "", fmt.Errorf("%s(.) via: unknown field %q on %s", rel.Object, rel.Via.Chain[0], related.APIName)
+		}
+		if !points(fd) {
+			return "", fmt.Errorf("%s(.) via: field %q is not a lookup to %s", rel.Object, fd.APIName, c.obj.APIName)
+		}
+		return fd.APIName, nil
+	}
+
+	var candidates []string
+	for i := range related.Fields {
+		if fd := &related.Fields[i]; points(fd) {
+			candidates = append(candidates, fd.APIName)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("%s has no lookup to %s", related.APIName, c.obj.APIName)
+	case 1:
+		return candidates[0], nil
+	default:
+		return "", fmt.Errorf("%s has several lookups to %s (%s); choose one with via: .field", related.APIName, c.obj.APIName, strings.Join(candidates, ", "))
+	}
 }
 
 // compileWhereFuncCall compiles a function call as a boolean condition.
 func (c *Compiler) compileWhereFuncCall(fn *parser.FuncCall) (Condition, error) {
 	switch fn.Name {
 	case "reports_to":
+		if err := c.requireEmployees("reports_to()"); err != nil {
+			return nil, err
+		}
 		if len(fn.Args) != 2 {
 			return nil, fmt.Errorf("reports_to() requires 2 arguments")
 		}
@@ -261,13 +614,72 @@ func (c *Compiler) compileWhereFuncCall(fn *parser.FuncCall) (Condition, error)
 			return nil, fmt.Errorf("reports_to arg 2: %w", err)
 		}
 
-		return ReportsTo{Target: targetRef}, nil
+		via, err := c.resolveVia(fn)
+		if err != nil {
+			return nil, err
+		}
+
+		return ReportsTo{Target: targetRef, Via: via}, nil
+
+	case "member_of":
+		if err := c.requireEmployees("member_of()"); err != nil {
+			return nil, err
+		}
+		return c.resolveTeam(fn)
+
+	case "all", "none":
+		if err := c.requireEmployees(fn.Name + "()"); err != nil {
+			return nil, err
+		}
+		return c.compileQuantified(fn)
 
 	default:
 		return nil, fmt.Errorf("function %q is not supported as a where condition", fn.Name)
 	}
 }
 
+// compileQuantified compiles all(reports(., depth), pred) and none(...): the
+// org list must be relative to the outer employee, and pred follows where rules.
+func (c *Compiler) compileQuantified(fn *parser.FuncCall) (Condition, error) {
+	src, ok := fn.Args[0].(*parser.FuncCall)
+	if !ok || src.Name != "reports" {
+		return nil, fmt.Errorf("%s() expects reports(., depth) as its first argument", fn.Name)
+	}
+	if len(src.Args) == 0 {
+		return nil, fmt.Errorf("%s(): reports() requires an employee argument", fn.Name)
+	}
+	if _, ok := src.Args[0].(*parser.DotExpr); !ok {
+		return nil, fmt.Errorf("%s(): reports() inside where expects '.' as first argument", fn.Name)
+	}
+
+	depth := 0
+	if len(src.Args) >= 2 {
+		var err error
+		depth, err = c.resolveIntArg(src.Args[1])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	via, err := c.resolveVia(src)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireTree(src, via); err != nil {
+		return nil, err
+	}
+
+	pred, err := c.compileWhereCond(fn.Args[1])
+	if err != nil {
+		return nil, fmt.Errorf("%s(): %w", fn.Name, err)
+	}
+	employed, err := c.excludeTerminated(src)
+	if err != nil {
+		return nil, err
+	}
+	return Quantified{Quantifier: fn.Name, OrgFunc: src.Name, Depth: depth, Via: via, Pred: pred, Employed: employed}, nil
+}
+
 // tryCompileStringOp checks if a PipeExpr is a string operation pattern like `.field | contains("str")`.
 func (c *Compiler) tryCompileStringOp(pipe *parser.PipeExpr) (Condition, bool) {
 	if len(pipe.Steps) != 2 {
@@ -290,21 +702,89 @@ func (c *Compiler) tryCompileStringOp(pipe *parser.PipeExpr) (Condition, bool) {
 	if len(fa.Chain) == 0 {
 		return nil, false
 	}
-	if _, ok := c.empObj.FieldsByAPIName[fa.Chain[0]]; !ok {
+	if _, ok := c.field(c.obj, fa.Chain[0]); !ok {
 		return nil, false
 	}
 
 	switch fn.Name {
 	case "contains", "starts_with", "ends_with":
-		return StringMatch{Field: fa.Chain, Op: fn.Name, Pattern: lit.Value}, true
+		c.warnChain(fn.Name+"()", fa.Chain)
+		fold := c.obj.FieldsByAPIName[fa.Chain[0]].AccentInsensitive
+		return StringMatch{Field: fa.Chain, Op: fn.Name, Pattern: lit.Value, Fold: fold}, true
+	case "contains_fold":
+		c.warnChain(fn.Name+"()", fa.Chain)
+		return StringMatch{Field: fa.Chain, Op: "contains", Pattern: lit.Value, Fold: true}, true
 	default:
 		return nil, false
 	}
 }
 
+// choiceMatches resolves a string match on a CHOICE field against its
+// option labels: the keys of the options whose value or label in some locale
+// matches, ignoring case (and accents when folded) as ILIKE would. The
+// stored keys are then filtered by equality, which indexes serve and which
+// does not depend on how the keys happen to be spelled. Never nil, so no
+// match compares against an empty list rather than NULL.
+func choiceMatches(m StringMatch, labels []schema.ChoiceOption) []string {
+	normalize := strings.ToLower
+	if m.Fold {
+		normalize = func(s string) string { return strings.ToLower(unaccent(s)) }
+	}
+	match := strings.Contains
+	switch m.Op {
+	case "starts_with":
+		match = strings.HasPrefix
+	case "ends_with":
+		match = strings.HasSuffix
+	}
+	pattern := normalize(m.Pattern)
+	keys := []string{}
+	for _, l := range labels {
+		if !slices.Contains(keys, l.Value) && match(normalize(l.Label), pattern) {
+			keys = append(keys, l.Value)
+		}
+	}
+	return keys
+}
+
+// unaccent strips combining marks from s after canonical decomposition, the
+// Go counterpart of metadata.unaccent for the letters option labels use.
+func unaccent(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		return r
+	}, norm.NFD.String(s))
+}
+
 // compileWhereFuncValue compiles a function in value position inside where.
 func (c *Compiler) compileWhereFuncValue(fn *parser.FuncCall) (any, error) {
 	switch fn.Name {
+	case "tenure":
+		var d durationVal
+		for i, arg := range fn.Args {
+			fa, ok := arg.(*parser.FieldAccess)
+			if !ok || len(fa.Chain) != 1 {
+				return nil, fmt.Errorf("tenure arg %d: expected a single date field (.field)", i+1)
+			}
+			if err := c.checkDateField("tenure", fa.Chain[0]); err != nil {
+				return nil, err
+			}
+			if err := c.obj.FieldsByAPIName[fa.Chain[0]].CheckFilterable(); err != nil {
+				return nil, err
+			}
+			if i == 0 {
+				d.from = fa.Chain[0]
+			} else {
+				d.to = fa.Chain[0]
+			}
+		}
+		return d, nil
+	case "start_of_month", "start_of_quarter", "start_of_year",
+		"end_of_month", "end_of_quarter", "end_of_year",
+		"this_month", "this_quarter", "this_year":
+		return c.compileCalendar(fn), nil
 	case "contains":
 		return nil, fmt.Errorf("contains() should be used with pipe syntax: .field | contains(\"str\")")
 	default:
@@ -312,15 +792,81 @@ func (c *Compiler) compileWhereFuncValue(fn *parser.FuncCall) (any, error) {
 	}
 }
 
+// trySinceValue compiles `.date_field | years_since` (or months_since,
+// days_since) in where value position.
+func (c *Compiler) trySinceValue(pipe *parser.PipeExpr) (any, bool, error) {
+	if len(pipe.Steps) != 2 {
+		return nil, false, nil
+	}
+	fa, isFA := pipe.Steps[0].(*parser.FieldAccess)
+	fn, isFn := pipe.Steps[1].(*parser.FuncCall)
+	if !isFA || !isFn || PipeCalls[fn.Name] == nil || !strings.HasSuffix(fn.Name, "_since") {
+		return nil, false, nil
+	}
+	if len(fa.Chain) != 1 {
+		return nil, true, fmt.Errorf("%s: expected a single date field (.field)", fn.Name)
+	}
+	if err := c.checkDateField(fn.Name, fa.Chain[0]); err != nil {
+		return nil, true, err
+	}
+	if err := c.obj.FieldsByAPIName[fa.Chain[0]].CheckFilterable(); err != nil {
+		return nil, true, err
+	}
+	return durationVal{from: fa.Chain[0], unit: sinceUnit(fn.Name)}, true, nil
+}
+
 // --- Internal value types for where compilation ---
 
 type (
-	fieldRef    struct{ chain []string }       // a validated field reference (API names)
+	// fieldRef is a validated field reference (API names); def is its ?? default.
+	fieldRef struct {
+		chain []string
+		def   *string
+	}
 	literalVal  string                          // a literal value
 	empRefVal   struct{ ref EmployeeRef }       // an unresolved employee reference (self.field)
-	subqueryVal struct{ cond SubqueryAgg }
+	subqueryVal struct{ cond Condition }        // a SubqueryAgg or RelatedAgg
+	durationLit string                          // a duration literal as an interval, e.g. "2 years"
+	durationVal struct{ from, to, unit string } // tenure(...) or .field | years_since
 )
 
+// compare sets the outer comparison of a subquery aggregate.
+func (s subqueryVal) compare(op, value string) Condition {
+	switch cond := s.cond.(type) {
+	case SubqueryAgg:
+		cond.Op, cond.Value = op, value
+		return cond
+	case RelatedAgg:
+		cond.Op, cond.Value = op, value
+		return cond
+	}
+	return s.cond
+}
+
+// compare builds the DurationCmp of d op other. tenure() compares with
+// duration literals, *_since with numbers.
+func (d durationVal) compare(op string, other any) (Condition, error) {
+	cmp := DurationCmp{From: d.from, To: d.to, Unit: d.unit, Op: op}
+	switch v := other.(type) {
+	case durationLit:
+		if d.unit != "" {
+			return nil, fmt.Errorf("%s_since counts whole %s; compare it with a number, not a duration", d.unit, d.unit)
+		}
+		cmp.Value = string(v)
+	case literalVal:
+		if d.unit == "" {
+			return nil, fmt.Errorf("tenure() must be compared with a duration such as 2y, 6mo, 3w or 90d")
+		}
+		if _, err := strconv.ParseFloat(string(v), 64); err != nil {
+			return nil, fmt.Errorf("%s_since must be compared with a number, got %q", d.unit, string(v))
+		}
+		cmp.Value = string(v)
+	default:
+		return nil, fmt.Errorf("unsupported comparison operands")
+	}
+	return cmp, nil
+}
+
 func reverseOp(op string) string {
 	switch op {
 	case ">":
@@ -2,6 +2,11 @@ package hrql
 
 import (
 	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/atlekbai/schema_registry/internal/hrql/parser"
 	"github.com/atlekbai/schema_registry/internal/schema"
@@ -12,23 +17,119 @@ type Compiler struct {
 	cache  *schema.Cache
 	selfID string
 	empObj *schema.ObjectDef
+	// obj is the object whose fields '.field' resolves against: employees,
+	// the object a query starts from (departments | ...), or the related
+	// object inside a relation's where (employees(.) | where(...)).
+	obj *schema.ObjectDef
+
+	// pipeRef is what '.' resolves to while compiling an org function in pipe
+	// position (see compileOrgStep).
+	pipeRef *EmployeeRef
+
+	// now is the moment calendar helpers (start_of_month(), ...) resolve
+	// against, in the request time zone; zero means the current time in UTC.
+	now time.Time
+
+	// includeTerminated keeps employees who have left in org function
+	// results (see Employed); an include_terminated: argument overrides it.
+	includeTerminated bool
+
+	warnings []Warning
 }
 
 // NewCompiler creates a compiler for HRQL expressions.
 func NewCompiler(cache *schema.Cache, selfID string) *Compiler {
+	emp := cache.Get("employees")
 	return &Compiler{
 		cache:  cache,
 		selfID: selfID,
-		empObj: cache.Get("employees"),
+		empObj: emp,
+		obj:    emp,
 	}
 }
 
-// Compile compiles an AST node into a storage-agnostic Plan.
-func (c *Compiler) Compile(node parser.Node) (*Plan, error) {
+// At sets the moment, and through its location the time zone, that
+// calendar helpers resolve against.
+func (c *Compiler) At(now time.Time) *Compiler {
+	c.now = now
+	return c
+}
+
+// IncludeTerminated sets whether org functions keep employees who have left,
+// for calls without an include_terminated: argument.
+func (c *Compiler) IncludeTerminated(include bool) *Compiler {
+	c.includeTerminated = include
+	return c
+}
+
+// Compile compiles an AST node into a storage-agnostic Plan. Warnings list
+// the constructs it approximated (see Warning).
+func (c *Compiler) Compile(node parser.Node) (*Plan, []Warning, error) {
 	if c.empObj == nil {
-		return nil, fmt.Errorf("employees object not found in schema cache")
+		return nil, nil, fmt.Errorf("employees object not found in schema cache")
+	}
+	c.obj = c.empObj
+	c.warnings = nil
+	if root := rootIdent(node); root != nil && root.Name != "employees" {
+		obj := c.cache.Get(root.Name)
+		if obj == nil {
+			return nil, nil, fmt.Errorf("unknown identifier %q", root.Name)
+		}
+		c.obj = obj
+	}
+	plan, err := c.compileNode(node)
+	if err != nil {
+		return nil, nil, err
+	}
+	if plan.Distinct && plan.AggFunc == "" {
+		c.warn(WarnNoOp, "unique over .%s has no effect without an aggregation after it (count, sum, avg)", plan.AggField)
+		plan.Distinct = false
+	}
+	return plan, c.warnings, nil
+}
+
+// warn records a warning, once per distinct message.
+func (c *Compiler) warn(code, format string, args ...any) {
+	w := Warning{Code: code, Message: fmt.Sprintf(format, args...)}
+	if !slices.Contains(c.warnings, w) {
+		c.warnings = append(c.warnings, w)
+	}
+}
+
+// warnChain warns when what uses only the first field of a chain.
+func (c *Compiler) warnChain(what string, chain []string) {
+	if len(chain) > 1 {
+		c.warn(WarnChainTruncated, "%s uses .%s only; .%s is ignored", what, chain[0], joinChain(chain))
 	}
-	return c.compileNode(node)
+}
+
+// field finds obj's field called name, warning when name is one of its
+// former api_names.
+func (c *Compiler) field(obj *schema.ObjectDef, name string) (*schema.FieldDef, bool) {
+	fd, ok := obj.FieldsByAPIName[name]
+	if ok && fd.APIName != name {
+		c.warn(WarnRenamedField, "field %q of %s was renamed to %q; use the new name", name, obj.APIName, fd.APIName)
+	}
+	return fd, ok
+}
+
+// rootIdent returns the identifier a query starts from (departments,
+// departments | where(...)), or nil when it starts from anything else.
+func rootIdent(node parser.Node) *parser.IdentExpr {
+	if pipe, ok := node.(*parser.PipeExpr); ok && len(pipe.Steps) > 0 {
+		node = pipe.Steps[0]
+	}
+	ident, _ := node.(*parser.IdentExpr)
+	return ident
+}
+
+// requireEmployees rejects employee-only constructs (org functions, employee
+// references) while '.' is a record of another object.
+func (c *Compiler) requireEmployees(what string) error {
+	if c.obj != c.empObj {
+		return fmt.Errorf("%s requires employees, not %s", what, c.obj.APIName)
+	}
+	return nil
 }
 
 func (c *Compiler) compileNode(node parser.Node) (*Plan, error) {
@@ -65,8 +166,12 @@ func (c *Compiler) compilePipe(pipe *parser.PipeExpr) (*Plan, error) {
 		return nil, err
 	}
 
-	for _, step := range pipe.Steps[1:] {
-		plan, err = c.applyStep(plan, step)
+	for i := 1; i < len(pipe.Steps); i++ {
+		if fn, ok := pipe.Steps[i].(*parser.FuncCall); ok && SourceCalls[fn.Name] != nil {
+			plan, err = c.compileOrgStep(pipe.Steps[:i], fn)
+		} else {
+			plan, err = c.applyStep(plan, pipe.Steps[i])
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -75,8 +180,36 @@ func (c *Compiler) compilePipe(pipe *parser.PipeExpr) (*Plan, error) {
 	return plan, nil
 }
 
+// compileOrgStep compiles an org function in pipe position, where '.' refers to
+// the single employee selected by the steps before it:
+// chain("<uuid>") | first | reports(.), self.manager | peers(.).
+func (c *Compiler) compileOrgStep(prefix []parser.Node, fn *parser.FuncCall) (*Plan, error) {
+	ref, err := c.resolvePipeRef(&parser.PipeExpr{Steps: prefix}, false)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name, err)
+	}
+	if err := c.requireEmployees(fn.Name + "()"); err != nil {
+		return nil, err
+	}
+	prev := c.pipeRef
+	c.pipeRef = &ref
+	defer func() { c.pipeRef = prev }()
+	return SourceCalls[fn.Name](c, fn)
+}
+
 // applyStep applies a single pipe step to the current plan.
 func (c *Compiler) applyStep(plan *Plan, step parser.Node) (*Plan, error) {
+	if plan.Sample > 0 {
+		if err := checkAfterSample(step); err != nil {
+			return nil, err
+		}
+	}
+	if plan.Union != nil && plan.Kind == PlanList {
+		if err := checkAfterUnion(step); err != nil {
+			return nil, err
+		}
+	}
+
 	switch s := step.(type) {
 	case *parser.FieldAccess:
 		return c.applyFieldAccess(plan, s)
@@ -88,6 +221,12 @@ func (c *Compiler) applyStep(plan *Plan, step parser.Node) (*Plan, error) {
 		return c.applyPick(plan, s)
 	case *parser.AggExpr:
 		return c.applyAgg(plan, s)
+	case *parser.BucketExpr:
+		return c.applyBucket(plan, s)
+	case *parser.IDsExpr:
+		return c.applyIDs(plan)
+	case *parser.CaseExpr:
+		return c.applyCase(plan, s)
 	case *parser.FuncCall:
 		return c.applyFuncInPipe(plan, s)
 	default:
@@ -107,11 +246,14 @@ func (c *Compiler) compileSelf() (*Plan, error) {
 	}, nil
 }
 
-// compileIdent: `employees` → full scan.
+// compileIdent: `employees` → full scan. Other objects may only start a
+// query (see Compile), which makes them the current object.
 func (c *Compiler) compileIdent(n *parser.IdentExpr) (*Plan, error) {
-	switch n.Name {
-	case "employees":
+	switch {
+	case n.Name == "employees":
 		return &Plan{Kind: PlanList}, nil
+	case c.obj != c.empObj && (n.Name == c.obj.APIName || c.obj.Alias(n.Name) != nil):
+		return &Plan{Kind: PlanList, Object: c.obj.APIName}, nil
 	default:
 		return nil, fmt.Errorf("unknown identifier %q", n.Name)
 	}
@@ -127,16 +269,28 @@ func (c *Compiler) applyFieldAccess(plan *Plan, fa *parser.FieldAccess) (*Plan,
 		return nil, fmt.Errorf("empty field access")
 	}
 
-	fd, ok := c.empObj.FieldsByAPIName[fa.Chain[0]]
+	fd, ok := c.field(c.obj, fa.Chain[0])
 	if !ok {
-		return nil, fmt.Errorf("unknown field %q on employees", fa.Chain[0])
+		return nil, fmt.Errorf("unknown field %q on %s", fa.Chain[0], c.obj.APIName)
 	}
-
-	// For LOOKUP fields with deeper chains, tracked for service layer.
-	if fd.Type == schema.FieldLookup && len(fa.Chain) > 1 {
+	if err := checkQueryable(fd); err != nil {
+		return nil, err
 	}
 
+	c.warnChain("field access", fa.Chain)
+
 	plan.AggField = fd.APIName
+	plan.Since = ""
+	plan.Distinct = false
+	plan.Case = nil
+	// After a pick the list holds one record at most, so the field is its
+	// value: reports(self, 1) | first | .employee_number.
+	if plan.PickOp != "" {
+		if plan.Union != nil {
+			return nil, fmt.Errorf("field access after %s is not supported on union(...)", plan.PickOp)
+		}
+		plan.Kind = PlanScalar
+	}
 	return plan, nil
 }
 
@@ -158,19 +312,57 @@ func (c *Compiler) applySort(plan *Plan, s *parser.SortExpr) (*Plan, error) {
 	if plan.Kind != PlanList {
 		return nil, fmt.Errorf("sort_by requires a list source")
 	}
+	if s.Random {
+		plan.OrderBy = &OrderBy{Random: true}
+		return plan, nil
+	}
 	if len(s.Field.Chain) == 0 {
 		return nil, fmt.Errorf("sort_by: empty field")
 	}
 
 	fieldName := s.Field.Chain[0]
-	if _, ok := c.empObj.FieldsByAPIName[fieldName]; !ok {
+	fd, ok := c.field(c.obj, fieldName)
+	if !ok {
 		return nil, fmt.Errorf("sort_by: unknown field %q", fieldName)
 	}
+	if err := checkComparable(fd); err != nil {
+		return nil, fmt.Errorf("sort_by: %w", err)
+	}
+	if err := fd.CheckSortable(); err != nil {
+		return nil, fmt.Errorf("sort_by: %w", err)
+	}
+	c.warnChain("sort_by", s.Field.Chain)
 
-	plan.OrderBy = &OrderBy{Field: fieldName, Desc: s.Desc}
+	plan.OrderBy = &OrderBy{Field: fieldName, Desc: s.Desc, NullsFirst: s.Nulls == "first"}
 	return plan, nil
 }
 
+// checkAfterSample rejects steps that would filter, reorder or aggregate a
+// sample(n) list: they would apply to the population rather than the sample.
+func checkAfterSample(step parser.Node) error {
+	var name string
+	switch s := step.(type) {
+	case *parser.WhereExpr:
+		name = "where"
+	case *parser.SortExpr:
+		name = "sort_by"
+	case *parser.PickExpr:
+		name = s.Op
+	case *parser.AggExpr:
+		name = s.Op
+	case *parser.BucketExpr:
+		name = "bucket"
+	case *parser.FuncCall:
+		if s.Name != "sample" && s.Name != "length" {
+			return nil
+		}
+		name = s.Name
+	default:
+		return nil
+	}
+	return fmt.Errorf("%s cannot follow sample(n); filter and sort before sampling", name)
+}
+
 func (c *Compiler) applyPick(plan *Plan, p *parser.PickExpr) (*Plan, error) {
 	if plan.Kind != PlanList {
 		return nil, fmt.Errorf("%s requires a list source", p.Op)
@@ -184,15 +376,45 @@ func (c *Compiler) applyPick(plan *Plan, p *parser.PickExpr) (*Plan, error) {
 		plan.Limit = 1
 	case "last":
 		plan.Limit = 1
+		// The last record is the first of the reversed order, nulls included.
 		if plan.OrderBy != nil {
 			plan.OrderBy.Desc = !plan.OrderBy.Desc
+			plan.OrderBy.NullsFirst = !plan.OrderBy.NullsFirst
 		} else {
 			plan.OrderBy = &OrderBy{Field: "id", Desc: true}
 		}
 	case "nth":
 		plan.Limit = 1
+	case "min_by", "max_by":
+		return c.applyPickBy(plan, p)
+	}
+
+	return plan, nil
+}
+
+// applyPickBy compiles min_by/max_by: the record with the smallest/largest
+// field value, i.e. ORDER BY field LIMIT 1. Records where the field is null are
+// skipped so max_by never picks a null (which sorts first descending).
+func (c *Compiler) applyPickBy(plan *Plan, p *parser.PickExpr) (*Plan, error) {
+	if len(p.Field.Chain) != 1 {
+		return nil, fmt.Errorf("%s: expected single field (.field), got .%s", p.Op, joinChain(p.Field.Chain))
 	}
 
+	fieldName := p.Field.Chain[0]
+	fd, ok := c.field(c.obj, fieldName)
+	if !ok {
+		return nil, fmt.Errorf("%s: unknown field %q", p.Op, fieldName)
+	}
+	if err := checkComparable(fd); err != nil {
+		return nil, fmt.Errorf("%s: %w", p.Op, err)
+	}
+	if err := fd.CheckSortable(); err != nil {
+		return nil, fmt.Errorf("%s: %w", p.Op, err)
+	}
+
+	plan.Conditions = append(plan.Conditions, IsNullFilter{Field: []string{fieldName}, IsNull: false})
+	plan.OrderBy = &OrderBy{Field: fieldName, Desc: p.Op == "max_by"}
+	plan.Limit = 1
 	return plan, nil
 }
 
@@ -201,11 +423,179 @@ func (c *Compiler) applyAgg(plan *Plan, a *parser.AggExpr) (*Plan, error) {
 		return nil, fmt.Errorf("%s requires a list source", a.Op)
 	}
 
+	if plan.Case != nil && a.Op != "count" && !plan.Case.Numeric {
+		return nil, fmt.Errorf("%s requires a numeric case; use count or make every branch a number", a.Op)
+	}
+
 	plan.Kind = PlanScalar
 	plan.AggFunc = a.Op
 	return plan, nil
 }
 
+// applyIDs compiles ids, which returns the IDs of the records the list
+// holds instead of the records.
+func (c *Compiler) applyIDs(plan *Plan) (*Plan, error) {
+	if plan.Kind != PlanList {
+		return nil, fmt.Errorf("ids requires a list source")
+	}
+	if plan.AggField != "" || plan.Case != nil {
+		return nil, fmt.Errorf("ids cannot follow a projection; it returns the IDs of the records")
+	}
+	plan.Kind = PlanIDs
+	return plan, nil
+}
+
+// maxBucketBounds caps the bounds of bucket(...), which counts one more
+// range than it has bounds.
+const maxBucketBounds = 50
+
+// applyBucket compiles bucket(...): the number of records per range of a
+// numeric field, or of a date field projected with years_since, months_since
+// or days_since. Records whose value is null are not counted.
+func (c *Compiler) applyBucket(plan *Plan, b *parser.BucketExpr) (*Plan, error) {
+	if plan.Kind != PlanList {
+		return nil, fmt.Errorf("bucket requires a list source")
+	}
+	if b.Value != nil {
+		steps := []parser.Node{b.Value}
+		if pipe, ok := b.Value.(*parser.PipeExpr); ok {
+			steps = pipe.Steps
+		}
+		fa, ok := steps[0].(*parser.FieldAccess)
+		if !ok || len(steps) > 2 {
+			return nil, fmt.Errorf("bucket: expected a field (.field) or a date field with years_since, e.g. .start_date | years_since")
+		}
+		var err error
+		if plan, err = c.applyFieldAccess(plan, fa); err != nil {
+			return nil, fmt.Errorf("bucket: %w", err)
+		}
+		if len(steps) == 2 {
+			fn, ok := steps[1].(*parser.FuncCall)
+			if !ok || !strings.HasSuffix(fn.Name, "_since") {
+				return nil, fmt.Errorf("bucket: only years_since, months_since or days_since may follow the field")
+			}
+			if plan, err = c.applyFuncInPipe(plan, fn); err != nil {
+				return nil, fmt.Errorf("bucket: %w", err)
+			}
+		}
+	}
+
+	if plan.AggField == "" {
+		return nil, fmt.Errorf("bucket needs a field, e.g. employees | bucket(.salary, [50000, 100000])")
+	}
+	fd := c.obj.FieldsByAPIName[plan.AggField]
+	if plan.Since == "" && (fd == nil || !fd.IsNumeric()) {
+		return nil, fmt.Errorf("bucket: field %q is not numeric; bucket dates with years_since, months_since or days_since", plan.AggField)
+	}
+	if len(b.Bounds) > maxBucketBounds {
+		return nil, fmt.Errorf("bucket: %d bounds exceed the limit of %d", len(b.Bounds), maxBucketBounds)
+	}
+	prev := math.Inf(-1)
+	for _, raw := range b.Bounds {
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil || v <= prev {
+			return nil, fmt.Errorf("bucket: bounds must be ascending numbers, got [%s]", strings.Join(b.Bounds, ", "))
+		}
+		prev = v
+	}
+
+	plan.Kind = PlanBuckets
+	plan.Bounds = b.Bounds
+	return plan, nil
+}
+
+// applyCase compiles case(when cond then value ... else value) into a
+// per-record computed value. Conditions follow where() rules; branch values
+// are literals or single fields of the record.
+func (c *Compiler) applyCase(plan *Plan, ce *parser.CaseExpr) (*Plan, error) {
+	if plan.Kind != PlanList {
+		return nil, fmt.Errorf("case requires a list source")
+	}
+
+	out := &Case{Numeric: true}
+	for _, w := range ce.Whens {
+		cond, err := c.compileWhereCond(w.Cond)
+		if err != nil {
+			return nil, fmt.Errorf("case: %w", err)
+		}
+		val, numeric, err := c.compileCaseValue(w.Then)
+		if err != nil {
+			return nil, err
+		}
+		out.Whens = append(out.Whens, CaseWhen{Cond: cond, Then: val})
+		out.Numeric = out.Numeric && numeric
+	}
+	if ce.Else != nil {
+		val, numeric, err := c.compileCaseValue(ce.Else)
+		if err != nil {
+			return nil, err
+		}
+		out.Else = &val
+		out.Numeric = out.Numeric && numeric
+	}
+
+	plan.Case = out
+	plan.AggField = ""
+	plan.Since = ""
+	return plan, nil
+}
+
+// compileCaseValue compiles a case branch result and reports whether it is numeric.
+func (c *Compiler) compileCaseValue(node parser.Node) (CaseValue, bool, error) {
+	switch n := node.(type) {
+	case *parser.Literal:
+		return CaseValue{Literal: n.Value}, n.Kind == parser.TokNumber, nil
+	case *parser.UnaryMinus:
+		if lit, ok := n.Expr.(*parser.Literal); ok && lit.Kind == parser.TokNumber {
+			return CaseValue{Literal: "-" + lit.Value}, true, nil
+		}
+	case *parser.FieldAccess:
+		if len(n.Chain) != 1 {
+			return CaseValue{}, false, fmt.Errorf("case: expected single field (.field), got .%s", joinChain(n.Chain))
+		}
+		fd, ok := c.field(c.obj, n.Chain[0])
+		if !ok {
+			return CaseValue{}, false, fmt.Errorf("case: unknown field %q", n.Chain[0])
+		}
+		if err := checkQueryable(fd); err != nil {
+			return CaseValue{}, false, fmt.Errorf("case: %w", err)
+		}
+		return CaseValue{Field: fd.APIName}, fd.IsNumeric(), nil
+	}
+	return CaseValue{}, false, fmt.Errorf("case: branch value must be a literal or a field, got %T", node)
+}
+
+// checkQueryable rejects ENCRYPTED fields: their stored ciphertext cannot be
+// compared, sorted or aggregated.
+// checkNumericProjection rejects a projection of a field other than a number
+// where a number is computed with it (first | .employee_number + 1).
+func (c *Compiler) checkNumericProjection(plan *Plan, what string) error {
+	if fd := c.obj.FieldsByAPIName[plan.AggField]; plan.IsProjection() && fd != nil && !fd.IsNumeric() {
+		return fmt.Errorf("field %q is not numeric and cannot be used in %s", fd.APIName, what)
+	}
+	return nil
+}
+
+func checkQueryable(fd *schema.FieldDef) error {
+	if fd.IsEncrypted() {
+		return fmt.Errorf("field %q is ENCRYPTED and cannot be used in queries", fd.APIName)
+	}
+	return nil
+}
+
+// checkComparable is checkQueryable for fields whose values are compared,
+// sorted or aggregated, which also rules out JSON blobs: they are returned
+// verbatim but have no order or equality the registry vouches for.
+func checkComparable(fd *schema.FieldDef) error {
+	if err := checkQueryable(fd); err != nil {
+		return err
+	}
+	if fd.IsJSON() {
+		return fmt.Errorf("field %q is JSON and cannot be compared, sorted or aggregated", fd.APIName)
+	}
+	return nil
+}
+
 // --- Arithmetic expression compilation ---
 
 func isArithOp(op string) bool {
@@ -213,7 +603,7 @@ func isArithOp(op string) bool {
 }
 
 // compileScalarExpr compiles a node into a ScalarExpr for arithmetic contexts.
-// Handles literals, unary minus, arithmetic BinaryOp, and falls back to compileNode
+// Handles literals, unary minus, arithmetic and ?? BinaryOps, and falls back to compileNode
 // for pipe expressions / function calls that produce PlanScalar.
 func (c *Compiler) compileScalarExpr(node parser.Node) (ScalarExpr, error) {
 	switch n := node.(type) {
@@ -243,6 +633,17 @@ func (c *Compiler) compileScalarExpr(node parser.Node) (ScalarExpr, error) {
 			}
 			return ScalarArith{Op: n.Op, Left: left, Right: right}, nil
 		}
+		if n.Op == "??" {
+			value, err := c.compileScalarExpr(n.Left)
+			if err != nil {
+				return nil, err
+			}
+			def, err := c.compileScalarExpr(n.Right)
+			if err != nil {
+				return nil, err
+			}
+			return ScalarCoalesce{Value: value, Default: def}, nil
+		}
 		return nil, fmt.Errorf("unsupported operator %q in arithmetic expression", n.Op)
 	default:
 		plan, err := c.compileNode(node)
@@ -252,6 +653,9 @@ func (c *Compiler) compileScalarExpr(node parser.Node) (ScalarExpr, error) {
 		if plan.Kind != PlanScalar {
 			return nil, fmt.Errorf("expected scalar expression, got %v", plan.Kind)
 		}
+		if err := c.checkNumericProjection(plan, "arithmetic"); err != nil {
+			return nil, err
+		}
 		return ScalarSubquery{Plan: plan}, nil
 	}
 }
@@ -79,23 +79,6 @@ func TestOrgChainAllWithRef(t *testing.T) {
 	_ = cond // Plan condition is a value type — no SQL to check here.
 }
 
-func TestLtreeLabelToUUID(t *testing.T) {
-	label := "550e8400e29b41d4a716446655440000"
-	got := LtreeLabelToUUID(label)
-	want := "550e8400-e29b-41d4-a716-446655440000"
-	if got != want {
-		t.Fatalf("expected %q, got %q", want, got)
-	}
-}
-
-func TestLtreeLabelToUUIDShort(t *testing.T) {
-	label := "short"
-	got := LtreeLabelToUUID(label)
-	if got != label {
-		t.Fatalf("expected %q, got %q", label, got)
-	}
-}
-
 // --- reverseOp tests ---
 
 func TestReverseOp(t *testing.T) {
@@ -147,7 +130,7 @@ func TestJoinChain(t *testing.T) {
 func TestTryCompileStringOp(t *testing.T) {
 	obj := testEmployeesObj()
 	cache := &schema.Cache{}
-	c := &Compiler{cache: cache, empObj: obj}
+	c := &Compiler{cache: cache, empObj: obj, obj: obj}
 
 	tests := []struct {
 		name   string
@@ -188,7 +171,7 @@ func TestTryCompileStringOp(t *testing.T) {
 
 func TestTryCompileStringOpNoMatch(t *testing.T) {
 	obj := testEmployeesObj()
-	c := &Compiler{empObj: obj}
+	c := &Compiler{empObj: obj, obj: obj}
 
 	pipe := &parser.PipeExpr{Steps: []parser.Node{
 		&parser.FieldAccess{Chain: []string{"employment_type"}},
@@ -200,26 +183,6 @@ func TestTryCompileStringOpNoMatch(t *testing.T) {
 	}
 }
 
-// --- isDescendant tests ---
-
-func TestIsDescendant(t *testing.T) {
-	tests := []struct {
-		emp, tgt string
-		want     bool
-	}{
-		{"a.b.c", "a.b", true},
-		{"a.b", "a.b", false},
-		{"a.b", "a.b.c", false},
-		{"a.b.c", "x.y", false},
-	}
-	for _, tt := range tests {
-		got := isDescendant(tt.emp, tt.tgt)
-		if got != tt.want {
-			t.Errorf("isDescendant(%q, %q): expected %v, got %v", tt.emp, tt.tgt, tt.want, got)
-		}
-	}
-}
-
 // --- Condition type assertions ---
 
 func TestConditionTypes(t *testing.T) {
@@ -1,11 +1,18 @@
 package e2e_test
 
 import (
+	"cmp"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"os"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
+	sq "github.com/Masterminds/squirrel"
 	"github.com/atlekbai/schema_registry/internal/hrql"
 	"github.com/atlekbai/schema_registry/internal/hrql/parser"
 	"github.com/atlekbai/schema_registry/internal/hrql/pg"
@@ -23,12 +30,15 @@ var (
 
 var testCache *schema.Cache
 
+var testCursors = pg.NewCursorSigner([]byte("e2e-cursor-signing-key-0123456789"))
+
 func TestMain(m *testing.M) {
 	testCache = buildCache()
 	os.Exit(m.Run())
 }
 
-func buildCache() *schema.Cache {
+// buildCache builds departments and employees; extra fields are appended to employees.
+func buildCache(extra ...schema.FieldDef) *schema.Cache {
 	// departments object (lookup target for employees.department)
 	deptObj := &schema.ObjectDef{
 		ID:              deptObjID,
@@ -59,13 +69,15 @@ func buildCache() *schema.Cache {
 		FieldsByAPIName: make(map[string]*schema.FieldDef),
 	}
 	empObj.Fields = []schema.FieldDef{
-		{ID: uuid.New(), APIName: "employee_number", Title: "Employee Number", Type: schema.FieldText, IsStandard: true, StorageColumn: new("employee_number")},
+		{ID: uuid.New(), APIName: "employee_number", Title: "Employee Number", Type: schema.FieldText, IsUnique: true, IsExternalID: true, IsStandard: true, StorageColumn: new("employee_number")},
 		{ID: uuid.New(), APIName: "employment_type", Title: "Employment Type", Type: schema.FieldChoice, IsStandard: true, StorageColumn: new("employment_type")},
 		{ID: uuid.New(), APIName: "start_date", Title: "Start Date", Type: schema.FieldDate, IsStandard: true, StorageColumn: new("start_date")},
 		{ID: uuid.New(), APIName: "end_date", Title: "End Date", Type: schema.FieldDate, IsStandard: true, StorageColumn: new("end_date")},
-		{ID: uuid.New(), APIName: "manager", Title: "Manager", Type: schema.FieldLookup, IsStandard: true, StorageColumn: new("manager_id"), LookupObjectID: new(empObjID)},
+		{ID: uuid.New(), APIName: "manager", Title: "Manager", Type: schema.FieldLookup, IsStandard: true, StorageColumn: new("manager_id"), LookupObjectID: new(empObjID), PathColumn: "manager_path"},
 		{ID: uuid.New(), APIName: "department", Title: "Department", Type: schema.FieldLookup, IsStandard: true, StorageColumn: new("department_id"), LookupObjectID: new(deptObjID)},
+		{ID: uuid.New(), APIName: "national_id", Title: "National ID", Type: schema.FieldEncrypted},
 	}
+	empObj.Fields = append(empObj.Fields, extra...)
 	for i := range empObj.Fields {
 		empObj.FieldsByAPIName[empObj.Fields[i].APIName] = &empObj.Fields[i]
 	}
@@ -84,22 +96,22 @@ func pipeline(t *testing.T, input, selfID string) (*hrql.Plan, *pg.SQLResult, st
 	}
 
 	comp := hrql.NewCompiler(testCache, selfID)
-	plan, err := comp.Compile(ast)
+	plan, _, err := comp.Compile(ast)
 	if err != nil {
 		t.Fatalf("compile %q: %v", input, err)
 	}
 
-	empObj := testCache.Get("employees")
+	obj := testCache.Get(cmp.Or(plan.Object, "employees"))
 
 	if plan.Kind == hrql.PlanBoolean {
-		sql, args, err := pg.TranslateBooleanPlan(plan, empObj)
+		sql, args, err := pg.TranslateBooleanPlan(plan, obj)
 		if err != nil {
 			t.Fatalf("translate boolean %q: %v", input, err)
 		}
 		return plan, nil, sql, args
 	}
 
-	result, err := pg.Translate(plan, empObj, testCache)
+	result, err := pg.Translate(plan, obj, testCache)
 	if err != nil {
 		t.Fatalf("translate %q: %v", input, err)
 	}
@@ -114,19 +126,19 @@ func pipelineErr(input, selfID string) error {
 	}
 
 	comp := hrql.NewCompiler(testCache, selfID)
-	plan, err := comp.Compile(ast)
+	plan, _, err := comp.Compile(ast)
 	if err != nil {
 		return err
 	}
 
-	empObj := testCache.Get("employees")
+	obj := testCache.Get(cmp.Or(plan.Object, "employees"))
 
 	if plan.Kind == hrql.PlanBoolean {
-		_, _, err = pg.TranslateBooleanPlan(plan, empObj)
+		_, _, err = pg.TranslateBooleanPlan(plan, obj)
 		return err
 	}
 
-	_, err = pg.Translate(plan, empObj, testCache)
+	_, err = pg.Translate(plan, obj, testCache)
 	return err
 }
 
@@ -289,6 +301,66 @@ func TestWhereEndsWith(t *testing.T) {
 	assertArgEquals(t, args, 0, "time")
 }
 
+func TestKeywordFieldNames(t *testing.T) {
+	cache := buildCache(
+		schema.FieldDef{ID: uuid.New(), APIName: "count", Title: "Count", Type: schema.FieldNumber},
+		schema.FieldDef{ID: uuid.New(), APIName: "desc", Title: "Description", Type: schema.FieldText},
+	)
+	ast, err := parser.Parse(`employees | where(.desc == "x" and ."count" > 2) | sort_by(.count, desc)`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	plan, _, err := hrql.NewCompiler(cache, "").Compile(ast)
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	if plan.OrderBy == nil || plan.OrderBy.Field != "count" || !plan.OrderBy.Desc {
+		t.Errorf("order = %+v, want count desc", plan.OrderBy)
+	}
+	res, err := pg.Translate(plan, cache.Get("employees"), cache)
+	if err != nil {
+		t.Fatalf("translate: %v", err)
+	}
+	var sql string
+	for _, c := range res.Conditions {
+		s, _ := condToSQL(t, c)
+		sql += s
+	}
+	assertContains(t, sql, `"_e"."custom_fields"->>'desc'`)
+	assertContains(t, sql, `"_e"."custom_fields"->>'count'`)
+}
+
+func TestWhereAccentInsensitive(t *testing.T) {
+	_, result, _, _ := pipeline(t, `employees | where(.employment_type | contains_fold("José"))`, "")
+	sql, args := condToSQL(t, result.Conditions[0])
+	assertContains(t, sql, `"metadata"."unaccent"("_e"."employment_type") ILIKE '%' || "metadata"."unaccent"(?) || '%'`)
+	assertArgEquals(t, args, 0, "José")
+
+	// A flagged field folds every string operation.
+	cache := buildCache(schema.FieldDef{ID: uuid.New(), APIName: "nickname__c", Title: "Nickname", Type: schema.FieldText, AccentInsensitive: true})
+	ast, err := parser.Parse(`employees | where(.nickname__c | starts_with("Jo"))`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	plan, _, err := hrql.NewCompiler(cache, "").Compile(ast)
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	if m := plan.Conditions[0].(hrql.StringMatch); !m.Fold || m.Op != "starts_with" {
+		t.Errorf("condition = %+v, want a folded starts_with", m)
+	}
+	res, err := pg.Translate(plan, cache.Get("employees"), cache)
+	if err != nil {
+		t.Fatalf("translate: %v", err)
+	}
+	sql, _ = condToSQL(t, res.Conditions[0])
+	assertContains(t, sql, `"metadata"."unaccent"("_e"."custom_fields"->>'nickname__c') ILIKE "metadata"."unaccent"(?) || '%'`)
+
+	if _, err := pg.PlanToFilters(plan); err == nil || !strings.Contains(err.Error(), "no filter equivalent") {
+		t.Errorf("PlanToFilters: expected no filter equivalent, got %v", err)
+	}
+}
+
 // --- Test: sort and pick ---
 
 func TestSortByAsc(t *testing.T) {
@@ -413,8 +485,7 @@ func TestMinOnField(t *testing.T) {
 		t.Errorf("expected AggField=start_date, got %q", plan.AggField)
 	}
 
-	assertContains(t, result.AggSQL, `min(`)
-	assertContains(t, result.AggSQL, `"_e"."start_date"`)
+	assertContains(t, result.AggSQL, `min("_e"."start_date")::text`)
 }
 
 func TestMaxOnField(t *testing.T) {
@@ -427,8 +498,43 @@ func TestMaxOnField(t *testing.T) {
 		t.Errorf("expected AggFunc=max, got %q", plan.AggFunc)
 	}
 
-	assertContains(t, result.AggSQL, `max(`)
-	assertContains(t, result.AggSQL, `"_e"."employee_number"`)
+	assertContains(t, result.AggSQL, `max("_e"."employee_number")::text`)
+}
+
+func TestBucket(t *testing.T) {
+	plan, result := bonusPipeline(t, `employees | where(.employment_type == "FULL_TIME") | bucket(.bonus__c, [0, 1000, 5000])`)
+	if plan.Kind != hrql.PlanBuckets {
+		t.Fatalf("expected PlanBuckets, got %v", plan.Kind)
+	}
+	assertContains(t, result.AggSQL, `SELECT width_bucket((("_e"."custom_fields"->>'bonus__c')::numeric)::numeric, ARRAY[$1::numeric, $2::numeric, $3::numeric]), count(*)`)
+	assertContains(t, result.AggSQL, `::numeric IS NOT NULL`)
+	assertContains(t, result.AggSQL, `GROUP BY 1`)
+	assertArgEquals(t, result.AggArgs, 2, "5000")
+	assertArgEquals(t, result.AggArgs, 3, "FULL_TIME")
+
+	plan, result, _, _ = pipeline(t, `employees | .start_date | years_since | bucket([1, 2, 5])`, "")
+	if plan.Kind != hrql.PlanBuckets || plan.Since != "years" {
+		t.Fatalf("expected years_since buckets, got %v since %q", plan.Kind, plan.Since)
+	}
+	assertContains(t, result.AggSQL, `width_bucket((EXTRACT(YEAR FROM age(now(), "_e"."start_date")))::numeric`)
+
+	if plan, _, _, _ = pipeline(t, `employees | bucket(.start_date | years_since, [1])`, ""); plan.Since != "years" {
+		t.Errorf("bucket value with years_since: since = %q", plan.Since)
+	}
+
+	for input, want := range map[string]string{
+		`employees | bucket(.start_date, [1])`:                                   "not numeric",
+		`employees | bucket(.start_date | upper, [1])`:                           "only years_since",
+		`employees | bucket([1, 2])`:                                             "bucket needs a field",
+		`employees | .start_date | years_since | bucket([2, 1])`:                 "ascending",
+		`self | .manager | bucket([1])`:                                          "not numeric",
+		`employees | sample(5) | bucket(.start_date | years_since, [1])`:         "cannot follow sample",
+		`union(employees, departments) | bucket(.start_date | years_since, [1])`: "cannot follow union",
+	} {
+		if err := pipelineErr(input, selfUUID); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", input, want, err)
+		}
+	}
 }
 
 func TestLengthAsCount(t *testing.T) {
@@ -452,8 +558,8 @@ func TestChainAll(t *testing.T) {
 	if plan.Kind != hrql.PlanList {
 		t.Fatalf("expected PlanList, got %v", plan.Kind)
 	}
-	if len(result.Conditions) != 1 {
-		t.Fatalf("expected 1 condition, got %d", len(result.Conditions))
+	if len(result.Conditions) != 2 {
+		t.Fatalf("expected 2 conditions (chain + employed), got %d", len(result.Conditions))
 	}
 
 	sql, args := condToSQL(t, result.Conditions[0])
@@ -468,6 +574,38 @@ func TestChainAll(t *testing.T) {
 	assertArgEquals(t, args, 1, targetUUID)
 }
 
+func TestHierarchyFromMetadata(t *testing.T) {
+	// The default hierarchy is the object's first self-lookup with a path
+	// column, whatever its table and column names.
+	cache := buildCache()
+	emp := cache.Get("employees")
+	emp.StorageSchema, emp.StorageTable = new("org"), new("staff")
+	emp.FieldsByAPIName["manager"].PathColumn = "lead_path"
+
+	ast, err := parser.Parse(fmt.Sprintf(`reports("%s")`, targetUUID))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	plan, _, err := hrql.NewCompiler(cache, "").Compile(ast)
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	result, err := pg.Translate(plan, emp, cache)
+	if err != nil {
+		t.Fatalf("translate: %v", err)
+	}
+	sql, _ := condToSQL(t, result.Conditions[0])
+	assertContains(t, sql, `"_e"."lead_path" <@ (SELECT "lead_path" FROM "org"."staff" WHERE "id" = ?)`)
+	if strings.Contains(sql, "manager_path") || strings.Contains(sql, "employees") {
+		t.Errorf("hardcoded hierarchy in %s", sql)
+	}
+
+	emp.FieldsByAPIName["manager"].PathColumn = ""
+	if _, _, err := hrql.NewCompiler(cache, "").Compile(ast); err == nil || !strings.Contains(err.Error(), "has no hierarchy") {
+		t.Errorf("reports without a hierarchy: err = %v", err)
+	}
+}
+
 func TestChainWithDepth(t *testing.T) {
 	_, result, _, _ := pipeline(t, fmt.Sprintf(`chain("%s", 2)`, targetUUID), "")
 
@@ -530,6 +668,31 @@ func TestColleagues(t *testing.T) {
 	}
 }
 
+func TestNetwork(t *testing.T) {
+	_, result, _, _ := pipeline(t, `network(self, 2)`, selfUUID)
+
+	sql, args := condToSQL(t, result.Conditions[0])
+	// One branch per ancestor level k = 0..2, each bounded by nlevel.
+	if n := strings.Count(sql, `<@ subpath(`); n != 3 {
+		t.Errorf("expected 3 ancestor branches, got %d: %s", n, sql)
+	}
+	assertContains(t, sql, `"_e"."id" !=`)
+	// Per branch: 4 path subqueries, k twice, and the level bound; plus the exclude ref.
+	assertArgCount(t, args, 3*7+1)
+	assertArgEquals(t, args, 6, 2)  // k=0 bound: degree
+	assertArgEquals(t, args, 13, 0) // k=1 bound: degree - 2
+	assertArgEquals(t, args, len(args)-1, selfUUID)
+}
+
+func TestNetworkDegreeOutOfRange(t *testing.T) {
+	for _, input := range []string{`network(self, 0)`, `network(self, 7)`} {
+		err := pipelineErr(input, selfUUID)
+		if err == nil || !strings.Contains(err.Error(), "degree must be between") {
+			t.Errorf("%s: expected degree range error, got %v", input, err)
+		}
+	}
+}
+
 // --- Test: reports_to (boolean) ---
 
 func TestReportsToBoolean(t *testing.T) {
@@ -562,6 +725,33 @@ func TestReportsToInWhere(t *testing.T) {
 	assertArgEquals(t, args, 0, targetUUID)
 }
 
+func TestBatchReportsTo(t *testing.T) {
+	plan, _, _, _ := pipeline(t, fmt.Sprintf(`reports_to(self.manager, "%s")`, targetUUID), selfUUID)
+	chained := plan.BoolCondition.(hrql.ReportsToCheck)
+	checks := []hrql.ReportsToCheck{
+		{Emp: hrql.EmployeeRef{ID: selfUUID}, Target: hrql.EmployeeRef{ID: targetUUID}},
+		chained,
+	}
+
+	sql, args, err := pg.BatchReportsToSQL(checks, testCache.Get("employees"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// One statement: a VALUES row per check, joined to both employees.
+	assertContains(t, sql, `FROM (VALUES (0, $1::uuid, $2::uuid), (1, (SELECT "manager_id" FROM`)
+	assertContains(t, sql, `WHERE "id" = $3)::uuid, $4::uuid)`)
+	assertContains(t, sql, `"_be"."manager_path" <@ "_bt"."manager_path"`)
+	assertContains(t, sql, `ORDER BY "_b"."idx"`)
+	assertArgCount(t, args, 4)
+	assertArgEquals(t, args, 2, selfUUID)
+	assertArgEquals(t, args, 3, targetUUID)
+
+	if _, _, err := pg.BatchReportsToSQL(nil, testCache.Get("employees")); err == nil {
+		t.Fatal("expected error for empty batch")
+	}
+}
+
 // --- Test: self field references ---
 
 func TestWhereFieldEqualsSelfField(t *testing.T) {
@@ -585,6 +775,66 @@ func TestChainWithSelfManager(t *testing.T) {
 	assertArgEquals(t, args, 0, selfUUID)
 }
 
+func TestReportsDeepSelfChain(t *testing.T) {
+	_, result, _, _ := pipeline(t, `reports(self.manager.manager)`, selfUUID)
+
+	sql, args := condToSQL(t, result.Conditions[0])
+	// Two nested dereferences: manager of (manager of self).
+	assertContains(t, sql, `(SELECT "manager_id" FROM "core"."employees" WHERE "id" = (SELECT "manager_id" FROM "core"."employees" WHERE "id" = ?))`)
+	assertArgEquals(t, args, 0, selfUUID)
+}
+
+func TestReportsOverPipeRef(t *testing.T) {
+	for _, q := range []string{
+		fmt.Sprintf(`chain("%s") | first | reports(.)`, targetUUID),
+		fmt.Sprintf(`reports(chain("%s") | first)`, targetUUID),
+	} {
+		_, result, _, _ := pipeline(t, q, "")
+
+		sql, args := condToSQL(t, result.Conditions[0])
+		// The single-record pipe becomes a scalar subquery yielding the base id.
+		assertContains(t, sql, `(SELECT "_e"."id" FROM "core"."employees" "_e" WHERE "_e"."manager_path" @>`)
+		assertContains(t, sql, `ORDER BY "_e"."id" ASC LIMIT 1)`)
+		assertArgEquals(t, args, 0, targetUUID)
+	}
+}
+
+func TestPipeRefWithChain(t *testing.T) {
+	_, result, _, _ := pipeline(t, `employees | sort_by(.start_date) | first | peers(.)`, "")
+
+	sql, _ := condToSQL(t, result.Conditions[0])
+	assertContains(t, sql, `ORDER BY "_e"."start_date" ASC NULLS LAST, "_e"."id" ASC LIMIT 1`)
+
+	// A trailing field access before the step dereferences: self.manager | peers(.)
+	_, result, _, _ = pipeline(t, `self.manager.manager | peers(.)`, selfUUID)
+	sql, _ = condToSQL(t, result.Conditions[0])
+	assertContains(t, sql, `(SELECT "manager_id" FROM "core"."employees" WHERE "id" = (SELECT "manager_id"`)
+
+	plan, _, boolSQL, args := pipeline(t, fmt.Sprintf(`reports_to(self.manager.manager, "%s")`, targetUUID), selfUUID)
+	if plan.Kind != hrql.PlanBoolean {
+		t.Fatalf("expected PlanBoolean, got %v", plan.Kind)
+	}
+	assertContains(t, boolSQL, `WHERE "id" = (SELECT "manager_id"`)
+	assertArgEquals(t, args, 0, selfUUID)
+}
+
+func TestEmployeeRefErrors(t *testing.T) {
+	cases := map[string]string{
+		`reports(self.department)`:                                         "does not reference an employee",
+		`reports(self.manager.department)`:                                 "does not reference an employee",
+		`reports(chain(self))`:                                             "single employee",
+		`employees | reports(.)`:                                           "single employee",
+		`chain(self) | nth(2) | reports(.)`:                                "nth is not supported",
+		`employees | where(.department.title == "x") | first | reports(.)`: "lookup chains",
+	}
+	for q, want := range cases {
+		err := pipelineErr(q, selfUUID)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", q, want, err)
+		}
+	}
+}
+
 // --- Test: lookup chain (cross-object field comparison) ---
 
 func TestWhereLookupChain(t *testing.T) {
@@ -695,7 +945,7 @@ func TestErrors(t *testing.T) {
 	}{
 		{"no self_id", `self`, "", "self_id"},
 		{"unknown field", `employees | where(.nonexistent == "val")`, "", "nonexistent"},
-		{"unknown identifier", `departments`, "", "departments"},
+		{"unknown identifier", `projects`, "", "projects"},
 		{"sort unknown field", `employees | sort_by(.nonexistent, asc)`, "", "nonexistent"},
 		{"field access no source", `.employment_type`, "", ""},
 		{"contains outside where", `employees | contains("test")`, "", "where"},
@@ -727,6 +977,35 @@ func TestUniquePassthrough(t *testing.T) {
 	}
 }
 
+func TestUniqueCount(t *testing.T) {
+	plan, result, _, _ := pipeline(t, `reports(self) | .department | unique | count`, selfUUID)
+	if plan.Kind != hrql.PlanScalar || !plan.Distinct || plan.AggField != "department" {
+		t.Fatalf("expected a distinct count of department, got %+v", plan)
+	}
+	assertContains(t, result.AggSQL, `SELECT count(DISTINCT "_e"."department_id")`)
+
+	_, result = bonusPipeline(t, `employees | .bonus__c | unique | sum`)
+	assertContains(t, result.AggSQL, `COALESCE(sum(DISTINCT ("_e"."custom_fields"->>'bonus__c')::numeric), 0)`)
+
+	// A second field access starts a new projection.
+	plan = planFor(t, `employees | .department | unique | .manager | count`)
+	if plan.Distinct {
+		t.Error("unique carried over to .manager")
+	}
+
+	plan = planFor(t, `departments | where(employees(.) | .manager | unique | count > 2)`)
+	if agg, ok := plan.Conditions[0].(hrql.RelatedAgg); !ok || !agg.Distinct {
+		t.Fatalf("expected a distinct related count, got %#v", plan.Conditions[0])
+	}
+	_, result, _, _ = pipeline(t, `departments | where(employees(.) | .manager | unique | count > 2)`, "")
+	sql, _ := condToSQL(t, result.Conditions[0])
+	assertContains(t, sql, `(SELECT count(DISTINCT "_r"."v") FROM`)
+
+	if err := pipelineErr(`departments | where(employees(.) | .manager | upper | count > 2)`, ""); err == nil {
+		t.Error("expected upper to be rejected in a relation subquery")
+	}
+}
+
 // --- Test: arithmetic expressions ---
 
 func TestArithPureLiterals(t *testing.T) {
@@ -797,6 +1076,95 @@ func TestArithTwoSubqueries(t *testing.T) {
 	}
 }
 
+// --- Test: null handling and safe division ---
+
+func TestArithDivisionByZeroIsNull(t *testing.T) {
+	_, result, _, _ := pipeline(t, `(employees | count) / (reports(self, 0) | count)`, selfUUID)
+
+	assertContains(t, result.AggSQL, `::numeric / NULLIF((SELECT count(*)`)
+	assertContains(t, result.AggSQL, `::numeric, 0))`)
+}
+
+// bonusPipeline compiles and translates input against a cache with a custom
+// numeric field, bonus__c, on employees.
+func bonusPipeline(t *testing.T, input string) (*hrql.Plan, *pg.SQLResult) {
+	t.Helper()
+	cache := buildCache(schema.FieldDef{
+		ID: uuid.New(), APIName: "bonus__c", Title: "Bonus", Type: schema.FieldNumber,
+	})
+	ast, err := parser.Parse(input)
+	if err != nil {
+		t.Fatalf("parse %q: %v", input, err)
+	}
+	plan, _, err := hrql.NewCompiler(cache, "").Compile(ast)
+	if err != nil {
+		t.Fatalf("compile %q: %v", input, err)
+	}
+	result, err := pg.Translate(plan, cache.Get("employees"), cache)
+	if err != nil {
+		t.Fatalf("translate %q: %v", input, err)
+	}
+	return plan, result
+}
+
+func TestCoalesceScalar(t *testing.T) {
+	plan, result := bonusPipeline(t, `employees | where(.employment_type == "CONTRACTOR") | .bonus__c | avg ?? 0`)
+
+	if _, ok := plan.ScalarExpr.(hrql.ScalarCoalesce); !ok {
+		t.Fatalf("expected ScalarCoalesce, got %T", plan.ScalarExpr)
+	}
+	assertContains(t, result.AggSQL, `SELECT COALESCE((SELECT avg(("_e"."custom_fields"->>'bonus__c')::numeric)`)
+	assertContains(t, result.AggSQL, `)::numeric, $2::numeric)`)
+	assertArgEquals(t, result.AggArgs, 1, "0")
+
+	// 1 / 0 ?? -1: the default applies to the NULL quotient.
+	_, result, _, _ = pipeline(t, `1 / 0 ?? -1`, "")
+	assertContains(t, result.AggSQL, `SELECT COALESCE(($1::numeric / NULLIF($2::numeric, 0))::numeric, $3::numeric)`)
+}
+
+func TestSumOfNothingIsZero(t *testing.T) {
+	// sum skips NULL custom values and is 0 over no rows.
+	_, result := bonusPipeline(t, `employees | .bonus__c | sum`)
+	assertContains(t, result.AggSQL, `SELECT COALESCE(sum(("_e"."custom_fields"->>'bonus__c')::numeric), 0)`)
+
+	// avg stays NULL over no rows.
+	_, result = bonusPipeline(t, `employees | .bonus__c | avg`)
+	assertContains(t, result.AggSQL, `SELECT avg(("_e"."custom_fields"->>'bonus__c')::numeric)`)
+}
+
+func TestWhereFieldDefault(t *testing.T) {
+	_, result, _, _ := pipeline(t, `employees | where(.end_date ?? "9999-12-31" > "2025-01-01")`, "")
+
+	sql, args := condToSQL(t, result.Conditions[0])
+	assertContains(t, sql, `COALESCE("_e"."end_date", ?) > ?`)
+	assertArgEquals(t, args, 0, "9999-12-31")
+	assertArgEquals(t, args, 1, "2025-01-01")
+
+	// Reversed comparison keeps the default.
+	_, result, _, _ = pipeline(t, `employees | where("2025-01-01" < .end_date ?? "9999-12-31")`, "")
+	sql, _ = condToSQL(t, result.Conditions[0])
+	assertContains(t, sql, `COALESCE("_e"."end_date", ?) > ?`)
+
+	// Lookup chains.
+	_, result, _, _ = pipeline(t, `employees | where(.department.title ?? "none" == "none")`, "")
+	sql, _ = condToSQL(t, result.Conditions[0])
+	assertContains(t, sql, `COALESCE((SELECT "_sub"."title" FROM "core"."departments" "_sub" WHERE "_sub"."id" = "_e"."department_id"), ?) = ?`)
+}
+
+func TestCoalesceErrors(t *testing.T) {
+	for input, want := range map[string]string{
+		`employees | where("x" ?? .end_date == "y")`:        "needs a field on the left",
+		`employees | where(.end_date ?? .start_date > "x")`: "needs a literal default",
+		`employees | where(.end_date ?? "x" > .start_date)`: "can only be compared with a literal",
+		`employees ?? 0`: "expected scalar",
+	} {
+		err := pipelineErr(input, "")
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", input, want, err)
+		}
+	}
+}
+
 // --- Test: literal reversed comparison ---
 
 func TestReversedComparison(t *testing.T) {
@@ -809,3 +1177,3078 @@ func TestReversedComparison(t *testing.T) {
 	assertArgCount(t, args, 1)
 	assertArgEquals(t, args, 0, "2024-01-01")
 }
+
+// --- Test: REST filter operators ---
+
+// restFilter parses a REST filter on employees and translates it to SQL.
+func restFilter(t *testing.T, field, raw string) (string, []any) {
+	t.Helper()
+	empObj := testCache.Get("employees")
+	params, err := pg.ParseParams(empObj, pg.ParamsInput{Filters: map[string]string{field: raw}})
+	if err != nil {
+		t.Fatalf("parse filter %s=%s: %v", field, raw, err)
+	}
+	conds, err := pg.TranslateConditions(params.Conditions, empObj, testCache)
+	if err != nil {
+		t.Fatalf("translate filter %s=%s: %v", field, raw, err)
+	}
+	return condToSQL(t, conds[0])
+}
+
+func TestRestFilterBuiltin(t *testing.T) {
+	sql, args := restFilter(t, "start_date", "gte.2024-01-01")
+	assertContains(t, sql, `"_e"."start_date" >=`)
+	assertArgEquals(t, args, 0, "2024-01-01")
+}
+
+func TestRestFilterOpTypeMismatch(t *testing.T) {
+	empObj := testCache.Get("employees")
+	_, err := pg.ParseParams(empObj, pg.ParamsInput{Filters: map[string]string{"manager": "like.%x%"}})
+	if err == nil || !strings.Contains(err.Error(), "does not apply to LOOKUP field") {
+		t.Fatalf("expected type mismatch error, got %v", err)
+	}
+}
+
+func TestRestFilterUnknownOp(t *testing.T) {
+	empObj := testCache.Get("employees")
+	_, err := pg.ParseParams(empObj, pg.ParamsInput{Filters: map[string]string{"employee_number": "sounds.x"}})
+	if err == nil || !strings.Contains(err.Error(), "unknown filter operator") {
+		t.Fatalf("expected unknown operator error, got %v", err)
+	}
+}
+
+func TestRegisterFilterOperatorInvalid(t *testing.T) {
+	if err := pg.RegisterFilterOperator("bad.name", pg.FilterOperator{SQL: func(string, *schema.FieldDef, string) (sq.Sqlizer, error) { return nil, nil }}); err == nil {
+		t.Error("expected error for dotted operator name")
+	}
+	if err := pg.RegisterFilterOperator("empty", pg.FilterOperator{}); err == nil {
+		t.Error("expected error for operator without Parse or SQL")
+	}
+}
+
+// --- Test: cursor invalidation ---
+
+func TestCursorOrderRoundTrip(t *testing.T) {
+	empObj := testCache.Get("employees")
+	order := &pg.OrderClause{FieldAPIName: "start_date", Desc: true}
+	cursor := testCursors.Encode(empObj, selfUUID, new("2024-01-01"), order)
+
+	params, err := pg.ParseParams(empObj, pg.ParamsInput{Order: "start_date.desc", Cursor: cursor, Cursors: testCursors})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if params.Cursor.OrderField != "start_date" || !params.Cursor.Desc {
+		t.Errorf("unexpected cursor %+v", params.Cursor)
+	}
+}
+
+func TestCursorInvalidatedDeletedField(t *testing.T) {
+	empObj := testCache.Get("employees")
+	cursor := testCursors.Encode(empObj, selfUUID, new("x"), &pg.OrderClause{FieldAPIName: "salary"})
+
+	_, err := pg.ParseParams(empObj, pg.ParamsInput{Order: "salary", Cursor: cursor, Cursors: testCursors})
+	var ci *pg.CursorInvalidatedError
+	if !errors.As(err, &ci) {
+		t.Fatalf("expected CursorInvalidatedError, got %v", err)
+	}
+	if ci.OrderField != "salary" {
+		t.Errorf("OrderField = %q, want salary", ci.OrderField)
+	}
+}
+
+func TestCursorInvalidatedOrderChanged(t *testing.T) {
+	empObj := testCache.Get("employees")
+	cursor := testCursors.Encode(empObj, selfUUID, new("2024-01-01"), &pg.OrderClause{FieldAPIName: "start_date"})
+
+	for _, order := range []string{"", "end_date", "start_date.desc", "start_date.nullsfirst"} {
+		_, err := pg.ParseParams(empObj, pg.ParamsInput{Order: order, Cursor: cursor, Cursors: testCursors})
+		if _, ok := errors.AsType[*pg.CursorInvalidatedError](err); !ok {
+			t.Errorf("order %q: expected CursorInvalidatedError, got %v", order, err)
+		}
+	}
+}
+
+func TestCursorSignature(t *testing.T) {
+	empObj := testCache.Get("employees")
+	order := &pg.OrderClause{FieldAPIName: "start_date"}
+	cursor := testCursors.Encode(empObj, selfUUID, new("2024-01-01"), order)
+	parse := func(obj *schema.ObjectDef, cursor string, signer *pg.CursorSigner) error {
+		_, err := pg.ParseParams(obj, pg.ParamsInput{Order: "start_date", Cursor: cursor, Cursors: signer})
+		return err
+	}
+
+	// A forged sort value fails the signature.
+	payload, sig, _ := strings.Cut(cursor, ".")
+	raw, _ := base64.RawURLEncoding.DecodeString(payload)
+	forged := strings.Replace(string(raw), "2024-01-01", "2024-01-01') OR true --", 1)
+	for name, token := range map[string]string{
+		"forged":       base64.RawURLEncoding.EncodeToString([]byte(forged)) + "." + sig,
+		"unsigned":     payload,
+		"other key":    pg.NewCursorSigner([]byte("another-cursor-signing-key-0123456")).Encode(empObj, selfUUID, new("2024-01-01"), order),
+		"no signature": payload + ".",
+	} {
+		if err := parse(empObj, token, testCursors); err == nil || !strings.Contains(err.Error(), "invalid cursor") {
+			t.Errorf("%s: error %v, want invalid cursor", name, err)
+		}
+	}
+	if err := parse(empObj, cursor, nil); err == nil {
+		t.Error("expected a signed cursor to be rejected without a signer")
+	}
+
+	// Cursors do not carry over to another object.
+	dept := testCache.Get("departments")
+	if _, err := pg.ParseParams(dept, pg.ParamsInput{Cursor: testCursors.Encode(dept, selfUUID, nil, nil), Cursors: testCursors}); err != nil {
+		t.Fatalf("departments cursor: %v", err)
+	}
+	if err := parse(dept, cursor, testCursors); err == nil || !strings.Contains(err.Error(), "another object") {
+		t.Errorf("error %v, want cursor for another object", err)
+	}
+
+	// Plain ids carry no sort value and stay accepted.
+	if err := parse(empObj, selfUUID, nil); err != nil {
+		t.Errorf("plain id cursor: %v", err)
+	}
+}
+
+func TestCursorInvalidatedSortFieldChanged(t *testing.T) {
+	objID := uuid.MustParse("01900000-0000-7000-8000-0000000000ac")
+	priority := schema.FieldDef{ID: uuid.New(), APIName: "priority", Title: "Priority", Type: schema.FieldNumber}
+	obj := customObject(objID, "tickets", priority)
+	cursor := testCursors.Encode(obj, selfUUID, new("3"), &pg.OrderClause{FieldAPIName: "priority"})
+
+	retyped := priority
+	retyped.Type = schema.FieldText
+	recreated := priority
+	recreated.ID = uuid.New()
+	for name, fd := range map[string]schema.FieldDef{"retyped": retyped, "recreated": recreated} {
+		_, err := pg.ParseParams(customObject(objID, "tickets", fd), pg.ParamsInput{Order: "priority", Cursor: cursor, Cursors: testCursors})
+		ci, ok := errors.AsType[*pg.CursorInvalidatedError](err)
+		if !ok || ci.OrderField != "priority" {
+			t.Errorf("%s: expected CursorInvalidatedError on priority, got %v", name, err)
+		}
+	}
+	if _, err := pg.ParseParams(obj, pg.ParamsInput{Order: "priority", Cursor: cursor, Cursors: testCursors}); err != nil {
+		t.Errorf("unchanged field: %v", err)
+	}
+}
+
+// --- Test: NULL ordering ---
+
+func TestParseOrderNulls(t *testing.T) {
+	empObj := testCache.Get("employees")
+	for input, want := range map[string]pg.OrderClause{
+		"end_date":                 {FieldAPIName: "end_date"},
+		"end_date.desc":            {FieldAPIName: "end_date", Desc: true},
+		"end_date.nullsfirst":      {FieldAPIName: "end_date", NullsFirst: true},
+		"end_date.DESC.NullsFirst": {FieldAPIName: "end_date", Desc: true, NullsFirst: true},
+		"end_date.asc.nullslast":   {FieldAPIName: "end_date"},
+	} {
+		got, err := pg.ParseOrder(empObj, input)
+		if err != nil || *got != want {
+			t.Errorf("ParseOrder(%q) = %+v, %v; want %+v", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"end_date.up", "end_date.nullsfirst.desc", "end_date.desc.nullslast.x"} {
+		if _, err := pg.ParseOrder(empObj, input); err == nil || !strings.Contains(err.Error(), "invalid order") {
+			t.Errorf("ParseOrder(%q): error %v, want invalid order", input, err)
+		}
+	}
+}
+
+func TestOrderNullsSQL(t *testing.T) {
+	empObj := testCache.Get("employees")
+	build := func(order string, cursor *string) string {
+		t.Helper()
+		input := pg.ParamsInput{Order: order, Cursors: testCursors}
+		if cursor != nil {
+			input.Cursor = *cursor
+		}
+		params, err := pg.ParseParams(empObj, input)
+		if err != nil {
+			t.Fatalf("parse %q: %v", order, err)
+		}
+		sql, _, err := pg.NewBuilder(empObj).BuildList(params)
+		if err != nil {
+			t.Fatalf("build %q: %v", order, err)
+		}
+		return sql
+	}
+
+	assertContains(t, build("end_date.desc", nil), `ORDER BY "_e"."end_date" DESC NULLS LAST, "_e"."id" DESC`)
+	assertContains(t, build("end_date.nullsfirst", nil), `ORDER BY "_e"."end_date" ASC NULLS FIRST, "_e"."id" ASC`)
+
+	// A value cursor with nulls last still reaches the NULLs; a NULL cursor
+	// only continues among them.
+	desc := &pg.OrderClause{FieldAPIName: "end_date", Desc: true}
+	assertContains(t, build("end_date.desc", new(testCursors.Encode(empObj, selfUUID, new("2024-01-01"), desc))),
+		`(("_e"."end_date", "_e"."id") < ($1, $2) OR "_e"."end_date" IS NULL)`)
+	assertContains(t, build("end_date.desc", new(testCursors.Encode(empObj, selfUUID, nil, desc))),
+		`("_e"."end_date" IS NULL AND "_e"."id" < $1)`)
+
+	first := &pg.OrderClause{FieldAPIName: "end_date", NullsFirst: true}
+	assertContains(t, build("end_date.nullsfirst", new(testCursors.Encode(empObj, selfUUID, nil, first))),
+		`(("_e"."end_date" IS NULL AND "_e"."id" > $1) OR "_e"."end_date" IS NOT NULL)`)
+	sql := build("end_date.nullsfirst", new(testCursors.Encode(empObj, selfUUID, new("2024-01-01"), first)))
+	assertContains(t, sql, `("_e"."end_date", "_e"."id") > ($1, $2)`)
+	if strings.Contains(sql, "IS NULL") {
+		t.Errorf("nulls first: a value cursor is past the NULLs, got %s", sql)
+	}
+}
+
+func TestSortByNulls(t *testing.T) {
+	for input, want := range map[string]pg.OrderClause{
+		`employees | sort_by(.end_date, desc)`:              {FieldAPIName: "end_date", Desc: true},
+		`employees | sort_by(.end_date, desc, nulls_first)`: {FieldAPIName: "end_date", Desc: true, NullsFirst: true},
+		`employees | sort_by(.end_date, nulls_last)`:        {FieldAPIName: "end_date"},
+		// last reverses the nulls with the direction: the last record of an
+		// ascending, nulls-last order is a NULL if there is one.
+		`employees | sort_by(.end_date) | last`:              {FieldAPIName: "end_date", Desc: true, NullsFirst: true},
+		`employees | sort_by(.end_date, nulls_first) | last`: {FieldAPIName: "end_date", Desc: true},
+	} {
+		_, result, _, _ := pipeline(t, input, selfUUID)
+		if result.OrderBy == nil || *result.OrderBy != want {
+			t.Errorf("%s: order = %+v, want %+v", input, result.OrderBy, want)
+		}
+	}
+}
+
+// --- Test: parallel scan partitions ---
+
+func TestPartitions(t *testing.T) {
+	parts := pg.Partitions([]string{selfUUID, selfUUID, targetUUID})
+	want := []pg.Partition{
+		{After: "00000000-0000-0000-0000-000000000000", Until: selfUUID},
+		{After: selfUUID, Until: targetUUID},
+		{After: targetUUID, Until: "ffffffff-ffff-ffff-ffff-ffffffffffff"},
+	}
+	if !reflect.DeepEqual(parts, want) {
+		t.Errorf("Partitions = %+v, want %+v", parts, want)
+	}
+	// No matching records: one partition over every id.
+	if parts := pg.Partitions(nil); len(parts) != 1 || parts[0].Until != want[2].Until {
+		t.Errorf("Partitions(nil) = %+v", parts)
+	}
+}
+
+func TestPartitionBounds(t *testing.T) {
+	empObj := testCache.Get("employees")
+	params := &pg.QueryParams{}
+	sql, args, err := pg.BuildPartitionBounds(empObj, params, 4, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, sql, `SELECT percentile_disc($1::float8[]) WITHIN GROUP (ORDER BY "_e"."id")::text[] FROM "core"."employees" "_e"`)
+	if !reflect.DeepEqual(args[0], []float64{0.25, 0.5, 0.75}) {
+		t.Errorf("fractions = %v", args[0])
+	}
+
+	// Large tables are sampled.
+	sql, _, _ = pg.BuildPartitionBounds(empObj, params, 4, 10_000_000)
+	assertContains(t, sql, `TABLESAMPLE BERNOULLI (1)`)
+}
+
+func TestPartitionCursor(t *testing.T) {
+	empObj := testCache.Get("employees")
+	part := pg.Partition{After: selfUUID, Until: targetUUID}
+	params, err := pg.ParseParams(empObj, pg.ParamsInput{Cursor: testCursors.EncodePartition(empObj, part, "", time.Time{}), Cursors: testCursors})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if p := params.Partition(); p == nil || *p != part || params.Cursor.ID != selfUUID {
+		t.Fatalf("cursor = %+v", params.Cursor)
+	}
+
+	builder := pg.NewBuilder(empObj)
+	for name, build := range map[string]func(*pg.QueryParams) (string, []any, error){
+		"list": builder.BuildList, "count": builder.BuildCount, "estimate": builder.BuildEstimate,
+	} {
+		sql, _, err := build(params)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		assertContains(t, sql, `("_e"."id" > $1 AND "_e"."id" <= $2)`)
+	}
+
+	// Later pages keep the partition.
+	next := testCursors.EncodeSnapshot(empObj, targetUUID, nil, nil, params.Partition(), "", time.Time{})
+	params, err = pg.ParseParams(empObj, pg.ParamsInput{Cursor: next, Cursors: testCursors})
+	if err != nil || params.Partition() == nil || *params.Partition() != part {
+		t.Errorf("next page: %+v, %v", params, err)
+	}
+
+	bad := testCursors.EncodePartition(empObj, pg.Partition{After: targetUUID, Until: selfUUID}, "", time.Time{})
+	if _, err := pg.ParseParams(empObj, pg.ParamsInput{Cursor: bad, Cursors: testCursors}); err == nil {
+		t.Error("expected an empty partition range to be rejected")
+	}
+}
+
+// --- Test: HRQL → REST filters ---
+
+func planFor(t *testing.T, input string) *hrql.Plan {
+	t.Helper()
+	plan, _, _, _ := pipeline(t, input, selfUUID)
+	return plan
+}
+
+func TestWhereStringMatchLiteral(t *testing.T) {
+	_, result, _, _ := pipeline(t, `employees | where(.employee_number | contains(r"50%_off\"))`, "")
+	_, args := condToSQL(t, result.Conditions[0])
+	assertArgEquals(t, args, 0, `50\%\_off\\`)
+
+	_, result, _, _ = pipeline(t, `employees | where(.employee_number | starts_with('O"Brien'))`, "")
+	_, args = condToSQL(t, result.Conditions[0])
+	assertArgEquals(t, args, 0, `O"Brien`)
+
+	_, result, _, _ = pipeline(t, `employees | where(.employee_number == "O\"Brien" or .employee_number == r"O'Brien")`, "")
+	_, args = condToSQL(t, result.Conditions[0])
+	assertArgEquals(t, args, 0, `O"Brien`)
+	assertArgEquals(t, args, 1, `O'Brien`)
+
+	filters, err := pg.PlanToFilters(planFor(t, `employees | where(.employee_number | ends_with("_1"))`))
+	if err != nil {
+		t.Fatalf("PlanToFilters: %v", err)
+	}
+	if got := filters["employee_number"]; got != `ilike.%\_1` {
+		t.Errorf("filter = %q, want the _ escaped", got)
+	}
+}
+
+func TestPlanToFilters(t *testing.T) {
+	plan := planFor(t, `employees | where(.employment_type == "FULL_TIME" and .start_date >= "2024-01-01" and (.employee_number | starts_with("E")))`)
+	filters, err := pg.PlanToFilters(plan)
+	if err != nil {
+		t.Fatalf("PlanToFilters: %v", err)
+	}
+	want := map[string]string{
+		"employment_type": "eq.FULL_TIME",
+		"start_date":      "gte.2024-01-01",
+		"employee_number": "ilike.E%",
+	}
+	if len(filters) != len(want) {
+		t.Fatalf("got %v, want %v", filters, want)
+	}
+	for k, v := range want {
+		if filters[k] != v {
+			t.Errorf("filters[%q] = %q, want %q", k, filters[k], v)
+		}
+	}
+}
+
+func TestPlanToFiltersRoundTrip(t *testing.T) {
+	plan := planFor(t, `employees | where(.employment_type != "CONTRACTOR")`)
+	filters, err := pg.PlanToFilters(plan)
+	if err != nil {
+		t.Fatalf("PlanToFilters: %v", err)
+	}
+	sql, args := restFilter(t, "employment_type", filters["employment_type"])
+	assertContains(t, sql, `"_e"."employment_type" <>`)
+	assertArgEquals(t, args, 0, "CONTRACTOR")
+}
+
+func TestPlanToFiltersUntranslatable(t *testing.T) {
+	tests := map[string]string{
+		`reports(self, 1)`: "has no filter equivalent",
+		`employees | where(.employment_type == "A" or .employment_type == "B")`:        "or has no filter equivalent",
+		`employees | where(.start_date > "2024-01-01" and .start_date < "2025-01-01")`: "filtered more than once",
+		`employees | where(.department.title == "Engineering")`:                        "lookup chain",
+		`employees | sort_by(.start_date) | first`:                                     "sort_by",
+		`employees | where(.employment_type == "X") | as_of("2024-01-01")`:             "as_of has no filter equivalent",
+		`employees | count`: "only list expressions",
+	}
+	for input, want := range tests {
+		_, err := pg.PlanToFilters(planFor(t, input))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", input, want, err)
+		}
+	}
+}
+
+// --- Test: as_of time travel ---
+
+func TestAsOfScalar(t *testing.T) {
+	plan, _, _, _ := pipeline(t, `reports(self) | count | as_of("2024-06-01")`, selfUUID)
+	if plan.Kind != hrql.PlanScalar {
+		t.Fatalf("expected PlanScalar, got %v", plan.Kind)
+	}
+	if plan.AsOf == nil || plan.AsOf.Format("2006-01-02") != "2024-06-01" {
+		t.Fatalf("expected AsOf 2024-06-01, got %v", plan.AsOf)
+	}
+
+	histObj, err := pg.AsOf(testCache.Get("employees"), *plan.AsOf)
+	if err != nil {
+		t.Fatalf("AsOf: %v", err)
+	}
+	result, err := pg.Translate(plan, histObj, testCache)
+	if err != nil {
+		t.Fatalf("translate: %v", err)
+	}
+	assertContains(t, result.AggSQL, `"core"."employees_as_of"('2024-06-01T00:00:00Z'::timestamptz)`)
+	if strings.Contains(result.AggSQL, `"core"."employees" `) {
+		t.Errorf("expected every employees reference to use the snapshot: %s", result.AggSQL)
+	}
+}
+
+func TestAsOfErrors(t *testing.T) {
+	tests := map[string]string{
+		`employees | as_of("yesterday")`:                        "invalid date",
+		`employees | as_of("2024-01-01") | as_of("2024-02-01")`: "only appear once",
+	}
+	for input, want := range tests {
+		err := pipelineErr(input, selfUUID)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", input, want, err)
+		}
+	}
+
+	if _, err := pg.AsOf(testCache.Get("departments"), time.Now()); err == nil {
+		t.Error("expected departments to have no history")
+	}
+}
+
+// --- Test: ENCRYPTED fields are never filterable or sortable ---
+
+func TestEncryptedFieldQueries(t *testing.T) {
+	tests := []string{
+		`employees | where(.national_id == "123")`,
+		`employees | where(.national_id | contains("12"))`,
+		`employees | where(.employee_number == self.national_id)`,
+		`employees | sort_by(.national_id)`,
+		`employees | .national_id | count`,
+		`colleagues(self, .national_id)`,
+	}
+	for _, input := range tests {
+		err := pipelineErr(input, selfUUID)
+		if err == nil || !strings.Contains(err.Error(), "ENCRYPTED") {
+			t.Errorf("%s: expected ENCRYPTED error, got %v", input, err)
+		}
+	}
+}
+
+func TestEncryptedFieldRestParams(t *testing.T) {
+	empObj := testCache.Get("employees")
+
+	_, err := pg.ParseParams(empObj, pg.ParamsInput{Filters: map[string]string{"national_id": "eq.123"}})
+	if err == nil || !strings.Contains(err.Error(), "cannot be filtered") {
+		t.Errorf("filter: expected ENCRYPTED error, got %v", err)
+	}
+
+	_, err = pg.ParseParams(empObj, pg.ParamsInput{Order: "national_id.desc"})
+	if err == nil || !strings.Contains(err.Error(), "cannot be used in order") {
+		t.Errorf("order: expected ENCRYPTED error, got %v", err)
+	}
+
+	if _, err := pg.ParseParams(empObj, pg.ParamsInput{Select: "employee_number,national_id"}); err != nil {
+		t.Errorf("select: unexpected error %v", err)
+	}
+}
+
+// --- Test: fields outside the filter/sort allowlist ---
+
+func TestFieldAccessFlags(t *testing.T) {
+	cache := buildCache(
+		schema.FieldDef{ID: uuid.New(), APIName: "salary__c", Type: schema.FieldNumber, NotFilterable: true, NotSortable: true},
+		schema.FieldDef{ID: uuid.New(), APIName: "hired__c", Type: schema.FieldDate, NotFilterable: true},
+		schema.FieldDef{ID: uuid.New(), APIName: "rank__c", Type: schema.FieldNumber, NotSortable: true},
+	)
+	compile := func(input string) error {
+		ast, err := parser.Parse(input)
+		if err != nil {
+			t.Fatalf("parse %q: %v", input, err)
+		}
+		_, _, err = hrql.NewCompiler(cache, selfUUID).Compile(ast)
+		return err
+	}
+
+	denied := []string{
+		`employees | where(.salary__c > 100)`,
+		`employees | where(.salary__c in (1, 2))`,
+		`employees | where(.rank__c == .salary__c)`,
+		`employees | where(.manager.salary__c > 100)`,
+		`employees | where(.hired__c | years_since >= 2)`,
+		`employees | where(tenure(.hired__c) > 1y)`,
+		`employees | sort_by(.salary__c)`,
+		`employees | sort_by(.rank__c, desc)`,
+		`employees | max_by(.rank__c)`,
+		`colleagues(self, .salary__c)`,
+	}
+	for _, input := range denied {
+		err := compile(input)
+		if _, ok := errors.AsType[*schema.FieldAccessError](err); !ok {
+			t.Errorf("%s: expected FieldAccessError, got %v", input, err)
+		}
+	}
+
+	allowed := []string{
+		`employees | .salary__c | sum`,
+		`employees | where(.rank__c > 1)`,
+		`employees | sort_by(.hired__c)`,
+	}
+	for _, input := range allowed {
+		if err := compile(input); err != nil {
+			t.Errorf("%s: unexpected error %v", input, err)
+		}
+	}
+
+	empObj := cache.Get("employees")
+	_, err := pg.ParseParams(empObj, pg.ParamsInput{Filters: map[string]string{"salary__c": "gt.100"}})
+	if _, ok := errors.AsType[*schema.FieldAccessError](err); !ok {
+		t.Errorf("filter: expected FieldAccessError, got %v", err)
+	}
+	_, err = pg.ParseParams(empObj, pg.ParamsInput{Order: "rank__c.desc"})
+	if _, ok := errors.AsType[*schema.FieldAccessError](err); !ok {
+		t.Errorf("order: expected FieldAccessError, got %v", err)
+	}
+	if _, err := pg.ParseParams(empObj, pg.ParamsInput{Select: "salary__c", Order: "hired__c"}); err != nil {
+		t.Errorf("select and order: unexpected error %v", err)
+	}
+
+	// A default order on a field that is not sortable falls back to id order.
+	empObj.DefaultOrder = "rank__c"
+	params, err := pg.ParseParams(empObj, pg.ParamsInput{})
+	if err != nil || params.Order != nil {
+		t.Errorf("default order: got %+v, %v", params, err)
+	}
+}
+
+// --- Test: expand strategies ---
+
+// expandParams returns list params for employees expanding manager.department.
+func expandParams(t testing.TB, limit int32) (*schema.ObjectDef, *pg.QueryParams) {
+	t.Helper()
+	empObj := testCache.Get("employees")
+	params, err := pg.ParseParams(empObj, pg.ParamsInput{Expand: "manager.department,department", Limit: limit})
+	if err != nil {
+		t.Fatalf("parse params: %v", err)
+	}
+	params.ExpandPlans = pg.ResolveExpands(params.Expand, empObj, testCache)
+	return empObj, params
+}
+
+func TestExpandStrategyResolve(t *testing.T) {
+	_, small := expandParams(t, 10)
+	_, large := expandParams(t, 150)
+
+	if got := pg.ExpandAuto.Resolve(small); got != pg.ExpandLateral {
+		t.Errorf("auto, small page: got %s, want lateral", got)
+	}
+	if got := pg.ExpandAuto.Resolve(large); got != pg.ExpandBatch {
+		t.Errorf("auto, large page: got %s, want batch", got)
+	}
+	if got := pg.ExpandLateral.Resolve(large); got != pg.ExpandLateral {
+		t.Errorf("explicit lateral: got %s", got)
+	}
+	if got := pg.ExpandAuto.Resolve(&pg.QueryParams{Limit: 200}); got != pg.ExpandLateral {
+		t.Errorf("auto without expands: got %s, want lateral", got)
+	}
+
+	if _, err := pg.ParseExpandStrategy("join"); err == nil {
+		t.Error("expected error for unknown strategy")
+	}
+	if s, err := pg.ParseExpandStrategy(""); err != nil || s != pg.ExpandAuto {
+		t.Errorf("empty strategy: got %q, %v", s, err)
+	}
+}
+
+func TestExpandProjection(t *testing.T) {
+	empObj := testCache.Get("employees")
+	params, err := pg.ParseParams(empObj, pg.ParamsInput{
+		Select: "employee_number,manager.start_date,manager.department.title",
+		Expand: "manager.department",
+	})
+	if err != nil {
+		t.Fatalf("parse params: %v", err)
+	}
+	if got := strings.Join(params.Select, ","); got != "employee_number,manager" {
+		t.Errorf("select = %q", got)
+	}
+	params.ExpandPlans = pg.ResolveExpands(params.Expand, empObj, testCache)
+	if err := pg.ProjectExpands(params.ExpandPlans, params.ExpandSelect); err != nil {
+		t.Fatalf("project: %v", err)
+	}
+
+	sql, _, err := pg.NewBuilder(empObj).BuildList(params)
+	if err != nil {
+		t.Fatalf("build list: %v", err)
+	}
+	assertContains(t, sql, `"_xp_manager_t"."start_date" AS "start_date"`)
+	assertContains(t, sql, `"_xp_manager__department_t"."title" AS "title"`)
+	for _, col := range []string{`"_xp_manager_t"."employee_number"`, `"_xp_manager_t"."end_date"`, `'end_date'`} {
+		if strings.Contains(sql, col) {
+			t.Errorf("projected list still reads %s:\n%s", col, sql)
+		}
+	}
+
+	// Batch expands share the projection.
+	batch, _, err := pg.BuildExpandBatch(&params.ExpandPlans[0], []string{targetUUID})
+	if err != nil {
+		t.Fatalf("build batch: %v", err)
+	}
+	if strings.Contains(batch, `"employee_number"`) {
+		t.Errorf("batch expand reads unselected columns: %s", batch)
+	}
+}
+
+func TestExpandProjectionErrors(t *testing.T) {
+	empObj := testCache.Get("employees")
+	for input, want := range map[string]string{
+		"manager.title":          "requires expand=manager",
+		"employee_number.title":  "not a LOOKUP",
+		"manager..title":         "invalid select path",
+		"nope.title":             "unknown field",
+		"manager.department.x.y": "invalid select path",
+	} {
+		_, err := pg.ParseParams(empObj, pg.ParamsInput{Select: input})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("select %q: got %v, want %q", input, err, want)
+		}
+	}
+
+	params, err := pg.ParseParams(empObj, pg.ParamsInput{Select: "manager.nope", Expand: "manager"})
+	if err != nil {
+		t.Fatalf("parse params: %v", err)
+	}
+	plans := pg.ResolveExpands(params.Expand, empObj, testCache)
+	if err := pg.ProjectExpands(plans, params.ExpandSelect); err == nil || !strings.Contains(err.Error(), `unknown field "nope"`) {
+		t.Errorf("unknown nested field: got %v", err)
+	}
+}
+
+func TestExpandBatchList(t *testing.T) {
+	empObj, params := expandParams(t, 10)
+	params.ExpandStrategy = pg.ExpandBatch
+
+	sql, _, err := pg.NewBuilder(empObj).BuildList(params)
+	if err != nil {
+		t.Fatalf("build list: %v", err)
+	}
+	if strings.Contains(sql, "LATERAL") {
+		t.Errorf("batch strategy must not emit LATERAL joins: %s", sql)
+	}
+	assertContains(t, sql, `'manager', "_e"."manager_id"`)
+	assertContains(t, sql, `'department', "_e"."department_id"`)
+
+	params.ExpandStrategy = pg.ExpandLateral
+	sql, _, err = pg.NewBuilder(empObj).BuildList(params)
+	if err != nil {
+		t.Fatalf("build list: %v", err)
+	}
+	assertContains(t, sql, "LATERAL")
+}
+
+func TestExpandBatchQuery(t *testing.T) {
+	_, params := expandParams(t, 10)
+	ids := []string{selfUUID, targetUUID}
+
+	var manager *pg.ExpandPlan
+	for i := range params.ExpandPlans {
+		if params.ExpandPlans[i].FieldName == "manager" {
+			manager = &params.ExpandPlans[i]
+		}
+	}
+	if manager == nil {
+		t.Fatal("manager expand plan not resolved")
+	}
+
+	sql, args, err := pg.BuildExpandBatch(manager, ids)
+	if err != nil {
+		t.Fatalf("build batch: %v", err)
+	}
+	assertContains(t, sql, `"_xp_manager_t"."id" = ANY($1::uuid[])`)
+	// Nested expand (manager.department) stays a lateral join inside the batch query.
+	assertContains(t, sql, `LEFT JOIN LATERAL`)
+	assertContains(t, sql, `AS "department"`)
+	if len(args) != 1 {
+		t.Fatalf("expected 1 arg, got %d: %v", len(args), args)
+	}
+}
+
+func BenchmarkBuildListExpandLateral(b *testing.B) {
+	empObj, params := expandParams(b, 200)
+	params.ExpandStrategy = pg.ExpandLateral
+	builder := pg.NewBuilder(empObj)
+	for b.Loop() {
+		if _, _, err := builder.BuildList(params); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBuildListExpandBatch(b *testing.B) {
+	empObj, params := expandParams(b, 200)
+	params.ExpandStrategy = pg.ExpandBatch
+	builder := pg.NewBuilder(empObj)
+	ids := make([]string, 200)
+	for i := range ids {
+		ids[i] = uuid.NewString()
+	}
+	for b.Loop() {
+		if _, _, err := builder.BuildList(params); err != nil {
+			b.Fatal(err)
+		}
+		for i := range params.ExpandPlans {
+			if _, _, err := pg.BuildExpandBatch(&params.ExpandPlans[i], ids); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// --- Test: min_by / max_by ---
+
+func TestPickMinBy(t *testing.T) {
+	plan, result, _, _ := pipeline(t, `reports(self, 1) | min_by(.start_date)`, selfUUID)
+
+	if plan.Limit != 1 {
+		t.Errorf("expected Limit=1, got %d", plan.Limit)
+	}
+	if result.PickOp != "min_by" {
+		t.Errorf("expected PickOp=min_by, got %q", result.PickOp)
+	}
+	if result.OrderBy == nil || result.OrderBy.FieldAPIName != "start_date" || result.OrderBy.Desc {
+		t.Errorf("expected ORDER BY start_date ASC, got %+v", result.OrderBy)
+	}
+	if len(result.Conditions) != 3 {
+		t.Fatalf("expected 3 conditions (reports + employed + not null), got %d", len(result.Conditions))
+	}
+	sql, _ := condToSQL(t, result.Conditions[2])
+	assertContains(t, sql, `"_e"."start_date" IS NOT NULL`)
+}
+
+func TestPickMaxByOverridesSort(t *testing.T) {
+	_, result, _, _ := pipeline(t, `employees | sort_by(.employee_number) | max_by(.start_date) | .employee_number`, "")
+
+	if result.OrderBy == nil || result.OrderBy.FieldAPIName != "start_date" || !result.OrderBy.Desc {
+		t.Errorf("expected ORDER BY start_date DESC, got %+v", result.OrderBy)
+	}
+}
+
+func TestPickByErrors(t *testing.T) {
+	tests := map[string]string{
+		`employees | max_by(.nonexistent)`:        "unknown field",
+		`employees | min_by(.department.title)`:   "single field",
+		`employees | count | max_by(.start_date)`: "requires a list source",
+		`employees | max_by(.national_id)`:        "ENCRYPTED",
+	}
+	for input, want := range tests {
+		err := pipelineErr(input, "")
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", input, want, err)
+		}
+	}
+}
+
+// --- Test: external ID upsert ---
+
+func TestUpsertStandardColumn(t *testing.T) {
+	b := pg.NewBuilder(testCache.Get("employees"))
+	sql, args, err := b.BuildUpsert(map[string]any{"employee_number": "E-42", "employment_type": "FULL_TIME"}, "employee_number")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertContains(t, sql, `INSERT INTO "core"."employees" AS "_t" ("employee_number", "employment_type")`)
+	assertContains(t, sql, `ON CONFLICT ("employee_number") DO UPDATE SET "version" = "_t"."version" + 1`)
+	assertContains(t, sql, `"employment_type" = EXCLUDED."employment_type"`)
+	assertContains(t, sql, `RETURNING "_t"."id"::text, ("_t"."xmax" = 0)`)
+	assertArgCount(t, args, 1)
+}
+
+func TestUpsertCustomObject(t *testing.T) {
+	objID := uuid.MustParse("01900000-0000-7000-8000-0000000000aa")
+	obj := &schema.ObjectDef{ID: objID, APIName: "badges__c", FieldsByAPIName: map[string]*schema.FieldDef{}}
+	obj.Fields = []schema.FieldDef{
+		{ID: uuid.New(), APIName: "badge_code", Type: schema.FieldText, IsUnique: true, IsExternalID: true},
+		{ID: uuid.New(), APIName: "label", Type: schema.FieldText},
+	}
+	for i := range obj.Fields {
+		obj.FieldsByAPIName[obj.Fields[i].APIName] = &obj.Fields[i]
+	}
+
+	sql, args, err := pg.NewBuilder(obj).BuildUpsert(map[string]any{"badge_code": "B1", "label": "Blue"}, "badge_code")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Conflict target must match the partial expression index exactly.
+	target := `("data"->>'badge_code')) WHERE "object_id" = '01900000-0000-7000-8000-0000000000aa'::uuid`
+	assertContains(t, sql, `ON CONFLICT (`+target)
+	assertContains(t, sql, `"data" = "_t"."data" || EXCLUDED."data"`)
+	assertArgCount(t, args, 2)
+
+	ddl := pg.BuildExternalIDIndex(obj, &obj.Fields[0])
+	assertContains(t, ddl, `CREATE UNIQUE INDEX "uq_external_id_`)
+	assertContains(t, ddl, `ON "metadata"."records" (`+target)
+}
+
+func TestSearchIndexDDL(t *testing.T) {
+	emp := testCache.Get("employees")
+	col := emp.FieldsByAPIName["employee_number"]
+	ddl := pg.BuildSearchIndex(emp, col)
+	assertContains(t, ddl, `CREATE INDEX CONCURRENTLY IF NOT EXISTS "`+pg.SearchIndexName(col)+`" ON "core"."employees" USING gin ("employee_number" gin_trgm_ops)`)
+
+	emp = buildCache(schema.FieldDef{ID: uuid.New(), APIName: "nickname__c", Type: schema.FieldText, IsSearchable: true}).Get("employees")
+	custom := emp.FieldsByAPIName["nickname__c"]
+	ddl = pg.BuildSearchIndex(emp, custom)
+	assertContains(t, ddl, `ON "core"."employees" USING gin (("custom_fields"->>'nickname__c') gin_trgm_ops)`)
+	assertContains(t, pg.BuildDropSearchIndex(emp, custom, true), `DROP INDEX CONCURRENTLY IF EXISTS "core"."ix_search_`)
+
+	objID := uuid.MustParse("01900000-0000-7000-8000-0000000000aa")
+	obj := &schema.ObjectDef{ID: objID, APIName: "badges__c"}
+	fd := &schema.FieldDef{ID: uuid.New(), APIName: "label", Type: schema.FieldText, IsSearchable: true}
+	ddl = pg.BuildSearchIndex(obj, fd)
+	assertContains(t, ddl, `ON "metadata"."records" USING gin (("data"->>'label') gin_trgm_ops) WHERE "object_id" = '01900000-0000-7000-8000-0000000000aa'::uuid`)
+	if drop := pg.BuildDropSearchIndex(obj, fd, false); drop != `DROP INDEX IF EXISTS "metadata"."`+pg.SearchIndexName(fd)+`"` {
+		t.Errorf("unexpected drop DDL %q", drop)
+	}
+
+	fd.AccentInsensitive = true
+	assertContains(t, pg.BuildSearchIndex(obj, fd), `USING gin (("metadata"."unaccent"(("data"->>'label'))) gin_trgm_ops)`)
+	folded := *col
+	folded.AccentInsensitive = true
+	assertContains(t, pg.BuildSearchIndex(testCache.Get("employees"), &folded), `USING gin (("metadata"."unaccent"("employee_number")) gin_trgm_ops)`)
+}
+
+func TestSortIndexSQL(t *testing.T) {
+	objID := uuid.MustParse("01900000-0000-7000-8000-0000000000ab")
+	obj := customObject(objID, "tickets",
+		schema.FieldDef{ID: uuid.New(), APIName: "priority", Title: "Priority", Type: schema.FieldNumber},
+		schema.FieldDef{ID: uuid.New(), APIName: "due", Title: "Due", Type: schema.FieldDate},
+	)
+	build := func(order string, cursor string) string {
+		t.Helper()
+		params, err := pg.ParseParams(obj, pg.ParamsInput{Order: order, Cursor: cursor, Cursors: testCursors})
+		if err != nil {
+			t.Fatalf("parse %q: %v", order, err)
+		}
+		sql, _, err := pg.NewBuilder(obj).BuildList(params)
+		if err != nil {
+			t.Fatalf("build %q: %v", order, err)
+		}
+		return sql
+	}
+
+	// Document fields sort by their nulls key, so one row comparison
+	// resumes the page, whatever the cursor holds.
+	sql := build("priority", "")
+	assertContains(t, sql, `("_e"."data"->>'priority')::numeric::text AS _cursor_val`)
+	assertContains(t, sql, `ORDER BY (("_e"."data"->>'priority')::numeric IS NULL) ASC, ("_e"."data"->>'priority')::numeric ASC, "_e"."id" ASC`)
+	if strings.Contains(sql, "NULLS") {
+		t.Errorf("document fields are ordered without NULLS FIRST/LAST, got %s", sql)
+	}
+
+	asc := &pg.OrderClause{FieldAPIName: "priority"}
+	sql = build("priority", testCursors.Encode(obj, selfUUID, new("3"), asc))
+	assertContains(t, sql, `((("_e"."data"->>'priority')::numeric IS NULL), ("_e"."data"->>'priority')::numeric, "_e"."id") > ($2, $3, $4)`)
+	sql = build("priority", testCursors.Encode(obj, selfUUID, nil, asc))
+	assertContains(t, sql, `((("_e"."data"->>'priority')::numeric IS NULL), "_e"."id") > ($2, $3)`)
+
+	desc := &pg.OrderClause{FieldAPIName: "due", Desc: true}
+	sql = build("due.desc", testCursors.Encode(obj, selfUUID, new("2024-01-01"), desc))
+	assertContains(t, sql, `ORDER BY ("metadata"."sort_timestamptz"("_e"."data"->>'due') IS NOT NULL) DESC, "metadata"."sort_timestamptz"("_e"."data"->>'due') DESC, "_e"."id" DESC`)
+	assertContains(t, sql, `(("metadata"."sort_timestamptz"("_e"."data"->>'due') IS NOT NULL), "metadata"."sort_timestamptz"("_e"."data"->>'due'), "_e"."id") < ($2, $3, $4)`)
+
+	// NULLs first flip the key to IS NOT NULL, the second index.
+	assertContains(t, build("priority.nullsfirst", ""), `ORDER BY (("_e"."data"->>'priority')::numeric IS NOT NULL) ASC`)
+
+	fd := obj.FieldsByAPIName["priority"]
+	names := pg.SortIndexNames(fd)
+	ddl := pg.BuildSortIndexes(obj, fd)
+	assertContains(t, ddl[0], `CREATE INDEX CONCURRENTLY IF NOT EXISTS "`+names[0]+`" ON "metadata"."records" ((("data"->>'priority')::numeric IS NULL), (("data"->>'priority')::numeric), "id") WHERE "object_id" = '01900000-0000-7000-8000-0000000000ab'::uuid`)
+	assertContains(t, ddl[1], `"`+names[1]+`" ON "metadata"."records" ((("data"->>'priority')::numeric IS NOT NULL),`)
+	assertContains(t, pg.BuildSortIndexes(obj, obj.FieldsByAPIName["due"])[0], `(("metadata"."sort_timestamptz"("data"->>'due') IS NULL), ("metadata"."sort_timestamptz"("data"->>'due')), "id")`)
+	if drop := pg.BuildDropSortIndexes(obj, fd, true); drop[1] != `DROP INDEX CONCURRENTLY IF EXISTS "metadata"."`+names[1]+`"` {
+		t.Errorf("unexpected drop DDL %q", drop[1])
+	}
+
+	emp := buildCache(schema.FieldDef{ID: uuid.New(), APIName: "level__c", Type: schema.FieldNumber}).Get("employees")
+	assertContains(t, pg.BuildSortIndexes(emp, emp.FieldsByAPIName["level__c"])[0], `ON "core"."employees" ((("custom_fields"->>'level__c')::numeric IS NULL),`)
+	for _, fd := range []*schema.FieldDef{emp.FieldsByAPIName["end_date"], {Type: schema.FieldJSON}, {Type: schema.FieldNumber, NotSortable: true}} {
+		if pg.HasSortIndexes(fd) {
+			t.Errorf("%s field %q should not get sort indexes", fd.Type, fd.APIName)
+		}
+	}
+}
+
+func TestUpsertErrors(t *testing.T) {
+	b := pg.NewBuilder(testCache.Get("employees"))
+	cases := map[string]struct {
+		values map[string]any
+		field  string
+	}{
+		"missing value":   {map[string]any{"employment_type": "FULL_TIME"}, "employee_number"},
+		"not external id": {map[string]any{"employment_type": "FULL_TIME"}, "employment_type"},
+		"unknown field":   {map[string]any{"employee_number": "E-1"}, "nope"},
+	}
+	for name, tc := range cases {
+		if _, _, err := b.BuildUpsert(tc.values, tc.field); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	if ddl := pg.BuildExternalIDIndex(testCache.Get("employees"), testCache.Get("employees").FieldsByAPIName["employee_number"]); ddl != "" {
+		t.Errorf("standard columns use their UNIQUE constraint, got DDL %q", ddl)
+	}
+}
+
+// --- Test: scalar formatting functions ---
+
+func TestPercentOfRound(t *testing.T) {
+	plan, result, _, _ := pipeline(t, `employees | where(.employment_type == "FULL_TIME") | count | percent_of(employees | count) | round(1)`, "")
+	if plan.Kind != hrql.PlanScalar {
+		t.Fatalf("expected PlanScalar, got %v", plan.Kind)
+	}
+	fn, ok := plan.ScalarExpr.(hrql.ScalarFunc)
+	if !ok || fn.Name != "round" {
+		t.Fatalf("expected round at the top, got %#v", plan.ScalarExpr)
+	}
+	assertContains(t, result.AggSQL, `SELECT round((100 * (SELECT count(*) FROM`)
+	assertContains(t, result.AggSQL, `/ NULLIF((SELECT count(*) FROM`)
+	assertContains(t, result.AggSQL, `, 0))::numeric, $2::int)`)
+	assertArgCount(t, result.AggArgs, 2)
+	assertArgEquals(t, result.AggArgs, 0, "FULL_TIME")
+	assertArgEquals(t, result.AggArgs, 1, "1")
+}
+
+func TestFloorCeilWithoutParens(t *testing.T) {
+	for _, name := range []string{"floor", "ceil"} {
+		_, result, _, _ := pipeline(t, `employees | count | `+name, "")
+		assertContains(t, result.AggSQL, `SELECT `+name+`((SELECT count(*)`)
+	}
+	_, result, _, _ := pipeline(t, `employees | count | round`, "")
+	assertContains(t, result.AggSQL, `SELECT round((SELECT count(*)`)
+	assertArgEquals(t, result.AggArgs, 0, "0")
+}
+
+func TestScalarFuncKeepsAsOf(t *testing.T) {
+	plan, _, _, _ := pipeline(t, `reports(self) | count | as_of("2024-06-01") | round`, selfUUID)
+	if plan.AsOf == nil {
+		t.Fatal("expected as_of to survive round")
+	}
+}
+
+func TestScalarFuncErrors(t *testing.T) {
+	tests := map[string]string{
+		`employees | round`:                         "requires a scalar",
+		`employees | count | round(11)`:             "decimals must be between",
+		`employees | count | percent_of`:            "requires arguments",
+		`employees | count | percent_of(employees)`: "expected scalar",
+	}
+	for input, want := range tests {
+		err := pipelineErr(input, "")
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", input, want, err)
+		}
+	}
+}
+
+// --- Test: denormalized lookup labels ---
+
+// labelCache returns employees with a department lookup that denormalizes the
+// department title, plus the departments object.
+func labelCache() *schema.Cache {
+	depts := testCache.Get("departments")
+	emp := &schema.ObjectDef{
+		ID: empObjID, APIName: "employees", IsStandard: true, SupportsCustomFields: true,
+		StorageSchema: new("core"), StorageTable: new("employees"),
+		FieldsByAPIName: map[string]*schema.FieldDef{},
+	}
+	emp.Fields = []schema.FieldDef{
+		{ID: uuid.New(), APIName: "department", Type: schema.FieldLookup, IsStandard: true, StorageColumn: new("department_id"),
+			LookupObjectID: new(deptObjID), TypeConfig: []byte(`{"denormalize_label": "title"}`)},
+		{ID: uuid.New(), APIName: "employee_number", Type: schema.FieldText, IsStandard: true, StorageColumn: new("employee_number")},
+	}
+	for i := range emp.Fields {
+		emp.FieldsByAPIName[emp.Fields[i].APIName] = &emp.Fields[i]
+	}
+	return schema.NewCacheFromObjects(emp, depts)
+}
+
+func TestRefreshLabels(t *testing.T) {
+	cache := labelCache()
+	emp := cache.Get("employees")
+	id := uuid.MustParse(selfUUID)
+
+	stmt, ok, err := pg.BuildRefreshLabels(emp, cache, id, map[string]any{"department": targetUUID})
+	if err != nil || !ok {
+		t.Fatalf("expected a refresh statement, got ok=%v err=%v", ok, err)
+	}
+	assertContains(t, stmt.SQL, `UPDATE "core"."employees" AS "_r" SET "custom_fields" = "_r"."custom_fields" || jsonb_build_object('department__label', (SELECT to_jsonb("_lt"."title") FROM "core"."departments" "_lt" WHERE "_lt"."id" = "_r"."department_id"))`)
+	assertContains(t, stmt.SQL, `AND "_r"."id" = $1`)
+	assertArgCount(t, stmt.Args, 1)
+
+	if _, ok, _ := pg.BuildRefreshLabels(emp, cache, id, map[string]any{"employee_number": "E-1"}); ok {
+		t.Error("writes not touching the lookup should not refresh labels")
+	}
+	backfill, ok, _ := pg.BuildRefreshLabels(emp, cache, uuid.Nil, nil)
+	if !ok || strings.Contains(backfill.SQL, `"_r"."id" =`) {
+		t.Errorf("expected an unrestricted backfill, got %q", backfill.SQL)
+	}
+}
+
+func TestPropagateLabels(t *testing.T) {
+	cache := labelCache()
+	depts := cache.Get("departments")
+	id := uuid.MustParse(targetUUID)
+
+	stmts, err := pg.BuildPropagateLabels(depts, cache, id, map[string]any{"title": "R&D"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stmts) != 1 {
+		t.Fatalf("expected 1 statement, got %d", len(stmts))
+	}
+	assertContains(t, stmts[0].SQL, `WHERE "_lt"."id" = $1)) WHERE "_r"."department_id" = $2`)
+	assertArgCount(t, stmts[0].Args, 2)
+
+	if stmts, _ := pg.BuildPropagateLabels(depts, cache, id, map[string]any{"parent": nil}); len(stmts) != 0 {
+		t.Errorf("label unchanged, expected no statements, got %d", len(stmts))
+	}
+}
+
+func TestLabelInRecordJSON(t *testing.T) {
+	cache := labelCache()
+	sql, _, err := pg.NewBuilder(cache.Get("employees")).BuildList(&pg.QueryParams{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, sql, `'department__label', "_e"."custom_fields"->'department__label'`)
+
+	if err := pg.ValidateLabelField(cache.Get("departments"), "nope"); err == nil {
+		t.Error("expected unknown label field to be rejected")
+	}
+}
+
+// --- Test: system fields (id, created_at, updated_at, version) ---
+
+func TestWhereSystemFields(t *testing.T) {
+	_, result, _, _ := pipeline(t, `employees | where(.created_at > "2024-01-01" and .version > 1)`, "")
+	sql, args := condToSQL(t, result.Conditions[0])
+	assertContains(t, sql, `"_e"."created_at" >`)
+	assertContains(t, sql, `"_e"."version" >`)
+	assertArgCount(t, args, 2)
+
+	_, result, _, _ = pipeline(t, fmt.Sprintf(`employees | where(.id == "%s")`, targetUUID), "")
+	sql, args = condToSQL(t, result.Conditions[0])
+	assertContains(t, sql, `"_e"."id" =`)
+	assertArgEquals(t, args, 0, targetUUID)
+
+	_, result, _, _ = pipeline(t, `employees | sort_by(.updated_at, desc)`, "")
+	if result.OrderBy == nil || result.OrderBy.FieldAPIName != "updated_at" || !result.OrderBy.Desc {
+		t.Errorf("expected order by updated_at desc, got %+v", result.OrderBy)
+	}
+}
+
+func TestSystemFieldFilterOnCustomObject(t *testing.T) {
+	obj := &schema.ObjectDef{ID: uuid.New(), APIName: "projects__c", Title: "Project", PluralTitle: "Projects"}
+	cache := schema.NewCacheFromObjects(obj)
+
+	params, err := pg.ParseParams(obj, pg.ParamsInput{
+		Order:   "created_at.desc",
+		Filters: map[string]string{"updated_at": "gte.2024-01-01"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	params.SQLConditions, err = pg.TranslateConditions(params.Conditions, obj, cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sql, _, err := pg.NewBuilder(obj).BuildList(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, sql, `"_e"."updated_at" >=`)
+	assertContains(t, sql, `ORDER BY "_e"."created_at" DESC`)
+
+	if len(obj.Fields) != 0 {
+		t.Errorf("system fields must not be added to Fields, got %d", len(obj.Fields))
+	}
+}
+
+// --- Test: compare-and-swap writes ---
+
+func TestVersionedWrites(t *testing.T) {
+	b := pg.NewBuilder(testCache.Get("employees"))
+	id := uuid.MustParse(selfUUID)
+
+	sql, args, err := b.BuildUpdate(id, map[string]any{"employee_number": "E-1"}, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, sql, `"version" = "version" + 1`)
+	assertContains(t, sql, `WHERE "id" = $2 AND "version" = $3 RETURNING "version"`)
+	assertArgEquals(t, args, 2, int64(3))
+
+	// Without an expected version the write is unconditional.
+	sql, args, err = b.BuildUpdate(id, map[string]any{"employee_number": "E-1"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, sql, `WHERE "id" = $2 RETURNING "version"`)
+	assertArgCount(t, args, 2)
+
+	sql, args, err = b.BuildDelete(id, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, sql, `DELETE FROM "core"."employees" WHERE "id" = $1 AND "version" = $2`)
+	assertArgEquals(t, args, 1, int64(3))
+
+	sql, _, err = b.BuildDelete(id, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(sql, `"version"`) {
+		t.Errorf("unconditional delete %q checks the version", sql)
+	}
+
+	// Custom records are also narrowed to their object.
+	obj := &schema.ObjectDef{ID: uuid.New(), APIName: "projects__c", Title: "Project", PluralTitle: "Projects"}
+	sql, args, err = pg.NewBuilder(obj).BuildDelete(id, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, sql, `DELETE FROM "metadata"."records" WHERE "id" = $1 AND "object_id" = $2 AND "version" = $3`)
+	assertArgEquals(t, args, 2, int64(2))
+
+	sql, _, err = pg.NewBuilder(obj).BuildVersion(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, sql, `SELECT "version" FROM "metadata"."records" WHERE "id" = $1 AND "object_id" = $2`)
+}
+
+// --- Test: per-object list defaults ---
+
+func TestObjectListDefaults(t *testing.T) {
+	dept := *testCache.Get("departments")
+	dept.DefaultOrder = "title.desc"
+	dept.DefaultPageSize = 25
+	dept.MaxPageSize = 100
+
+	params, err := pg.ParseParams(&dept, pg.ParamsInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Order == nil || params.Order.FieldAPIName != "title" || !params.Order.Desc {
+		t.Errorf("expected default order title desc, got %+v", params.Order)
+	}
+	if params.Limit != 25 {
+		t.Errorf("expected default limit 25, got %d", params.Limit)
+	}
+
+	params, err = pg.ParseParams(&dept, pg.ParamsInput{Order: "created_at", Limit: 150})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Order.FieldAPIName != "created_at" || params.Order.Desc {
+		t.Errorf("expected request order to win, got %+v", params.Order)
+	}
+	if params.Limit != 100 {
+		t.Errorf("expected limit capped at max_page_size 100, got %d", params.Limit)
+	}
+
+	// A default naming a deleted field falls back to id order.
+	dept.DefaultOrder = "name"
+	params, err = pg.ParseParams(&dept, pg.ParamsInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Order != nil {
+		t.Errorf("expected no order for a stale default, got %+v", params.Order)
+	}
+}
+
+func TestServerListLimits(t *testing.T) {
+	dept := *testCache.Get("departments")
+	limits := schema.ListLimits{DefaultPageSize: 20, MaxPageSize: 500, ExactCountThreshold: 1000}
+
+	params, err := pg.ParseParams(&dept, pg.ParamsInput{Limit: 400, Limits: limits})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Limit != 400 {
+		t.Errorf("expected limit 400 under a server max of 500, got %d", params.Limit)
+	}
+	params, err = pg.ParseParams(&dept, pg.ParamsInput{Limits: limits})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Limit != 20 {
+		t.Errorf("expected server default limit 20, got %d", params.Limit)
+	}
+
+	// Object overrides win, but never above the server maximum.
+	dept.DefaultPageSize = 800
+	dept.MaxPageSize = 1000
+	dept.ExactCountThreshold = 10
+	got := limits.For(&dept)
+	want := schema.ListLimits{DefaultPageSize: 500, MaxPageSize: 500, ExactCountThreshold: 10}
+	if got != want {
+		t.Errorf("For(departments) = %+v, want %+v", got, want)
+	}
+	if got := (schema.ListLimits{}).For(nil); got.DefaultPageSize != pg.DefaultLimit || got.MaxPageSize != pg.MaxLimit || got.ExactCountThreshold != schema.DefaultExactCountThreshold {
+		t.Errorf("zero limits = %+v, want the built-in defaults", got)
+	}
+
+	if err := (schema.ListLimits{DefaultPageSize: 300}).Validate(); err == nil {
+		t.Error("expected a default page size above the built-in max to be rejected")
+	}
+	if err := limits.Validate(); err != nil {
+		t.Errorf("validate: %v", err)
+	}
+}
+
+// --- Test: case/when ---
+
+func TestCaseProjection(t *testing.T) {
+	plan, result, _, _ := pipeline(t, `employees | where(.department.title == "Eng") | case(when .employment_type == "FULL_TIME" then "FT" else "Other")`, "")
+	if plan.Kind != hrql.PlanList || plan.Case == nil {
+		t.Fatalf("expected list plan with a case, got kind=%v case=%v", plan.Kind, plan.Case)
+	}
+	if len(result.Computed) != 1 || result.Computed[0].Key != pg.CaseKey {
+		t.Fatalf("expected one computed %q column, got %+v", pg.CaseKey, result.Computed)
+	}
+
+	params := &pg.QueryParams{Limit: 10, Computed: result.Computed, SQLConditions: result.Conditions}
+	sql, args, err := pg.NewBuilder(testCache.Get("employees")).BuildList(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, sql, `'case', CASE WHEN "_e"."employment_type" = $1 THEN $2::text ELSE $3::text END`)
+	assertArgEquals(t, args, 0, "FULL_TIME")
+	assertArgEquals(t, args, 1, "FT")
+	assertArgEquals(t, args, 2, "Other")
+	assertArgEquals(t, args, 3, "Eng")
+}
+
+func TestCaseAggregate(t *testing.T) {
+	_, result, _, _ := pipeline(t, `employees | case(when .employment_type == "CONTRACTOR" then 1 else 0) | sum`, "")
+	assertContains(t, result.AggSQL, `sum(CASE WHEN "_e"."employment_type" = $1 THEN $2::numeric ELSE $3::numeric END)`)
+	assertArgEquals(t, result.AggArgs, 0, "CONTRACTOR")
+
+	_, result, _, _ = pipeline(t, `employees | case(when .employment_type == "CONTRACTOR" then "c") | count`, "")
+	assertContains(t, result.AggSQL, `count(CASE WHEN "_e"."employment_type" = $1 THEN $2::text END)`)
+
+	_, result, _, _ = pipeline(t, `(employees | case(when .employment_type == "CONTRACTOR" then 1 else 0) | sum) / (employees | count)`, "")
+	assertContains(t, result.AggSQL, `sum(CASE WHEN`)
+	assertContains(t, result.AggSQL, `count(*)`)
+}
+
+func TestCaseFieldBranch(t *testing.T) {
+	_, result, _, _ := pipeline(t, `employees | case(when .end_date == "2026-01-01" then .end_date else .start_date)`, "")
+	sql := result.Computed[0].SQL
+	assertContains(t, sql, `THEN ("_e"."end_date")::text ELSE ("_e"."start_date")::text END`)
+}
+
+func TestCaseErrors(t *testing.T) {
+	for input, want := range map[string]string{
+		`employees | case(when .employment_type == "A" then "x") | sum`:                "numeric case",
+		`employees | case(when .employment_type == "A" then .national_id)`:             "ENCRYPTED",
+		`employees | case(when .employment_type == "A" then .manager.employee_number)`: "single field",
+		`employees | case(when .nope == "A" then 1)`:                                   "nope",
+		`employees | count | case(when .employment_type == "A" then 1)`:                "requires a list",
+	} {
+		err := pipelineErr(input, "")
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", input, want, err)
+		}
+	}
+}
+
+// --- Test: expand of JSONB-backed lookups ---
+
+func TestExpandCustomLookup(t *testing.T) {
+	cache := buildCache(schema.FieldDef{
+		ID: uuid.New(), APIName: "mentor__c", Title: "Mentor", Type: schema.FieldLookup, LookupObjectID: new(empObjID),
+	})
+	emp := cache.Get("employees")
+	if got := emp.FieldsByAPIName["mentor__c"].DocColumn; got != "custom_fields" {
+		t.Fatalf("expected custom_fields doc column on a standard object, got %q", got)
+	}
+
+	params := &pg.QueryParams{Limit: 10, ExpandPlans: pg.ResolveExpands([]string{"mentor__c"}, emp, cache)}
+	if len(params.ExpandPlans) != 1 {
+		t.Fatalf("expected mentor__c to resolve, got %d plans", len(params.ExpandPlans))
+	}
+	sql, _, err := pg.NewBuilder(emp).BuildList(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, sql, `"_xp_mentor__c_t"."id" = ("_e"."custom_fields"->>'mentor__c')::uuid`)
+
+	params.ExpandStrategy = pg.ExpandBatch
+	sql, _, err = pg.NewBuilder(emp).BuildList(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, sql, `'mentor__c', ("_e"."custom_fields"->>'mentor__c')::uuid`)
+
+	// HRQL chains dereference the document value too.
+	ast, err := parser.Parse(`chain(self.mentor__c, 1)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, _, err := hrql.NewCompiler(cache, selfUUID).Compile(ast)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := pg.Translate(plan, emp, cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	condSQL, _ := condToSQL(t, result.Conditions[0])
+	assertContains(t, condSQL, `(SELECT ("custom_fields"->>'mentor__c')::uuid FROM "core"."employees" WHERE "id" = ?)`)
+}
+
+// --- Test: in operator ---
+
+func TestInSubquery(t *testing.T) {
+	_, result, _, _ := pipeline(t, `employees | where(.id in chain(self))`, selfUUID)
+	sql, args := condToSQL(t, result.Conditions[0])
+	assertContains(t, sql, `"_e"."id" IN (SELECT "_e"."id" FROM "core"."employees" "_e" WHERE "_e"."manager_path" @> (SELECT "manager_path" FROM "core"."employees" WHERE "id" = ?)`)
+	assertArgEquals(t, args, 0, selfUUID)
+
+	// Another object's records, through a LOOKUP.
+	_, result, _, _ = pipeline(t, `employees | where(.department in (departments | where(.title == "Eng")))`, "")
+	sql, args = condToSQL(t, result.Conditions[0])
+	assertContains(t, sql, `"_e"."department_id" IN (SELECT "_e"."id" FROM "core"."departments" "_e" WHERE "_e"."title" = ?)`)
+	assertArgEquals(t, args, 0, "Eng")
+}
+
+func TestInTuple(t *testing.T) {
+	plan, result, _, _ := pipeline(t, `employees | where(.employment_type in ("FULL_TIME", "CONTRACTOR"))`, "")
+	if in, ok := plan.Conditions[0].(hrql.InFilter); !ok || len(in.Values) != 2 {
+		t.Fatalf("expected InFilter with 2 values, got %#v", plan.Conditions[0])
+	}
+	sql, _ := condToSQL(t, result.Conditions[0])
+	assertContains(t, sql, `"_e"."employment_type" = ANY(?)`)
+
+	// Employee references are OR'd with the literals.
+	_, result, _, _ = pipeline(t, fmt.Sprintf(`employees | where(.department in (self.department, "%s"))`, targetUUID), selfUUID)
+	sql, args := condToSQL(t, result.Conditions[0])
+	assertContains(t, sql, `("_e"."department_id" = ANY(?) OR "_e"."department_id" = (SELECT "department_id" FROM "core"."employees" WHERE "id" = ?))`)
+	assertArgEquals(t, args, 1, selfUUID)
+}
+
+func TestInErrors(t *testing.T) {
+	for input, want := range map[string]string{
+		`employees | where(.department in chain(self))`:      "refers to departments, but the list is of employees",
+		`employees | where(.employment_type in chain(self))`: "neither id nor a LOOKUP",
+		`employees | where(.id in (chain(self) | first))`:    "cannot pick",
+		`employees | where(.department.title in ("a"))`:      "single field on the left",
+		`employees | where(.id in (employees | count))`:      "must be a list of records",
+	} {
+		err := pipelineErr(input, selfUUID)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", input, want, err)
+		}
+	}
+}
+
+// --- Test: comparison type checking ---
+
+// typedCompile compiles input against a cache with typed custom fields.
+func typedCompile(input string) error {
+	cache := buildCache(
+		schema.FieldDef{ID: uuid.New(), APIName: "salary", Title: "Salary", Type: schema.FieldCurrency},
+		schema.FieldDef{ID: uuid.New(), APIName: "remote", Title: "Remote", Type: schema.FieldBoolean},
+		schema.FieldDef{ID: uuid.New(), APIName: "level", Title: "Level", Type: schema.FieldChoice, TypeConfig: []byte(`{"options": ["L1", "L2", "L3"]}`)},
+		schema.FieldDef{ID: uuid.New(), APIName: "skills", Title: "Skills", Type: schema.FieldMultichoice},
+	)
+	ast, err := parser.Parse(input)
+	if err != nil {
+		return err
+	}
+	_, _, err = hrql.NewCompiler(cache, selfUUID).Compile(ast)
+	return err
+}
+
+func TestTypeCheckAccepts(t *testing.T) {
+	for _, input := range []string{
+		`employees | where(.salary > 100000 and .salary <= "2e5")`,
+		`employees | where(.salary ?? 0 > -5)`,
+		`employees | where(.start_date >= "2024-01-01" and .end_date < "2025-06-30T00:00:00Z")`,
+		`employees | where(.remote == true)`,
+		`employees | where(.level == "L2" or .level in ("L1", "L3"))`,
+		`employees | where(.department == "` + targetUUID + `" and .manager == self)`,
+		`employees | where(.start_date < .end_date and .department == self.department)`,
+		`employees | where(.employee_number == .level)`,
+	} {
+		if err := typedCompile(input); err != nil {
+			t.Errorf("%s: %v", input, err)
+		}
+	}
+}
+
+func TestTypeCheckRejects(t *testing.T) {
+	for input, want := range map[string]string{
+		`employees | where(.start_date > 5)`:                                  "position 30: .start_date is DATE; compare it with a date string",
+		`employees | where(.start_date == "last week")`:                       `"last week" is not a date`,
+		`employees | where(.salary == "lots")`:                                `.salary is CURRENCY; compare it with a number, not the string "lots"`,
+		`employees | where(.salary ?? "none" > 1)`:                            `not the string "none"`,
+		`employees | where(.remote == "yes")`:                                 "compare it with true or false",
+		`employees | where(.level == "L9")`:                                   `"L9" is not an option of .level (L1, L2, L3)`,
+		`employees | where(.level > "L1")`:                                    "CHOICE and has no order",
+		`employees | where(.level in ("L1", "X"))`:                            `position 25: "X" is not an option of .level`,
+		`employees | where(.skills == "go")`:                                  "MULTICHOICE",
+		`employees | where(.department == "eng")`:                             `"eng" is not a UUID`,
+		`employees | where(.manager > "` + targetUUID + `")`:                  "LOOKUP and has no order",
+		`employees | where(.start_date == .salary)`:                           "cannot compare .start_date (DATE) with .salary (CURRENCY)",
+		`employees | where(.department == .manager)`:                          "cannot compare .department (a departments record) with .manager (a employees record)",
+		`employees | where(.employee_number == true)`:                         "compare it with a string, not true",
+		`employees | where(.start_date == self.salary)`:                       "with self.salary (CURRENCY)",
+		`departments | where(employees(.) | where(.remote == 1) | count > 0)`: "compare it with true or false, not the number 1",
+	} {
+		err := typedCompile(input)
+		var te *hrql.TypeError
+		if !errors.As(err, &te) || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected a type error containing %q, got %v", input, want, err)
+		}
+	}
+}
+
+// --- Test: string matches on CHOICE fields ---
+
+func TestChoiceStringMatch(t *testing.T) {
+	cache := buildCache(schema.FieldDef{
+		ID: uuid.New(), APIName: "level", Title: "Level", Type: schema.FieldChoice,
+		TypeConfig: []byte(`{"options": ["JUNIOR", "SENIOR", "STAFF"], "translations": {"de": {"options": {"SENIOR": "Fortgeschritten"}}, "fr": {"options": {"STAFF": "Expérimenté"}}}}`),
+	})
+	for input, want := range map[string][]string{
+		`employees | where(.level | contains("or"))`:               {"JUNIOR", "SENIOR"},
+		`employees | where(.level | starts_with("fort"))`:          {"SENIOR"},
+		`employees | where(.level | ends_with("FF"))`:              {"STAFF"},
+		`employees | where(.level | contains_fold("experimente"))`: {"STAFF"},
+		`employees | where(.level | contains("experimente"))`:      {},
+		`employees | where(.employment_type | contains("full"))`:   nil,
+	} {
+		ast, err := parser.Parse(input)
+		if err != nil {
+			t.Fatalf("parse %q: %v", input, err)
+		}
+		plan, _, err := hrql.NewCompiler(cache, selfUUID).Compile(ast)
+		if err != nil {
+			t.Fatalf("compile %q: %v", input, err)
+		}
+		if want == nil {
+			// Without options in its type_config the field is matched as text.
+			if _, ok := plan.Conditions[0].(hrql.StringMatch); !ok {
+				t.Errorf("%s: expected a StringMatch, got %T", input, plan.Conditions[0])
+			}
+			continue
+		}
+		in, ok := plan.Conditions[0].(hrql.InFilter)
+		if !ok {
+			t.Fatalf("%s: expected an InFilter, got %T", input, plan.Conditions[0])
+		}
+		if !slices.Equal(in.Values, want) || in.Values == nil {
+			t.Errorf("%s: keys = %#v, want %v", input, in.Values, want)
+		}
+		cond, err := pg.ConditionToSQL(in, cache.Get("employees"), cache)
+		if err != nil {
+			t.Fatalf("%s: translate: %v", input, err)
+		}
+		sql, _ := condToSQL(t, cond)
+		assertContains(t, sql, `"_e"."custom_fields"->>'level' = ANY(?)`)
+	}
+}
+
+// --- Test: all()/none() quantifiers ---
+
+func TestQuantifiedAll(t *testing.T) {
+	_, result, _, _ := pipeline(t, `employees | where(all(reports(., 1), .employment_type == "FULL_TIME"))`, "")
+	sql, args := condToSQL(t, result.Conditions[0])
+	assertContains(t, sql, `NOT EXISTS (SELECT 1 FROM (SELECT "_e"."manager_path" FROM "core"."employees" "_e" WHERE ("_e"."employment_type" = ?) IS NOT TRUE AND ("_e"."end_date" IS NULL OR "_e"."end_date" > CURRENT_DATE)) "_q"`)
+	assertContains(t, sql, `WHERE "_q"."manager_path" <@ "_e"."manager_path" AND nlevel("_q"."manager_path") = nlevel("_e"."manager_path") + 1)`)
+	assertArgCount(t, args, 1)
+	assertArgEquals(t, args, 0, "FULL_TIME")
+}
+
+func TestQuantifiedNone(t *testing.T) {
+	_, result, _, _ := pipeline(t, `employees | where(none(reports(.), .employment_type == "CONTRACTOR" and .end_date == "2026-01-01") and .employment_type == "FULL_TIME")`, "")
+	sql, args := condToSQL(t, result.Conditions[0])
+	assertContains(t, sql, `WHERE (("_e"."employment_type" = ? AND "_e"."end_date" = ?)) AND ("_e"."end_date" IS NULL OR "_e"."end_date" > CURRENT_DATE)) "_q"`)
+	assertContains(t, sql, `"_q"."manager_path" != "_e"."manager_path"`)
+	assertArgCount(t, args, 3)
+}
+
+func TestQuantifiedErrors(t *testing.T) {
+	for input, want := range map[string]string{
+		`employees | where(all(peers(.), .employment_type == "A"))`:      "expects reports(., depth)",
+		`employees | where(all(reports(self), .employment_type == "A"))`: "expects '.'",
+		`employees | where(none(reports(.), .nope == "A"))`:              "nope",
+	} {
+		err := pipelineErr(input, selfUUID)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", input, want, err)
+		}
+	}
+}
+
+// --- Test: org functions over other hierarchies (via:) ---
+
+// viaPipeline compiles and translates input against a cache with a
+// dotted_manager hierarchy next to the management tree.
+func viaPipeline(t *testing.T, input string) (*hrql.Plan, *pg.SQLResult, *schema.Cache) {
+	t.Helper()
+	cache := buildCache(schema.FieldDef{
+		ID: uuid.New(), APIName: "dotted_manager", Title: "Dotted-line Manager", Type: schema.FieldLookup,
+		StorageColumn: new("dotted_manager_id"), LookupObjectID: new(empObjID), PathColumn: "dotted_manager_path",
+	})
+	ast, err := parser.Parse(input)
+	if err != nil {
+		t.Fatalf("parse %q: %v", input, err)
+	}
+	plan, _, err := hrql.NewCompiler(cache, selfUUID).Compile(ast)
+	if err != nil {
+		t.Fatalf("compile %q: %v", input, err)
+	}
+	if plan.Kind == hrql.PlanBoolean {
+		return plan, nil, cache
+	}
+	result, err := pg.Translate(plan, cache.Get("employees"), cache)
+	if err != nil {
+		t.Fatalf("translate %q: %v", input, err)
+	}
+	return plan, result, cache
+}
+
+func TestViaReports(t *testing.T) {
+	_, result, _ := viaPipeline(t, `reports(self, via: .dotted_manager)`)
+	sql, _ := condToSQL(t, result.Conditions[0])
+	assertContains(t, sql, `"_e"."dotted_manager_path" <@ (SELECT "dotted_manager_path" FROM "core"."employees" WHERE "id" = ?)`)
+	if strings.Contains(sql, `"_e"."manager_path"`) {
+		t.Errorf("expected only the dotted_manager hierarchy, got %s", sql)
+	}
+
+	_, result, _ = viaPipeline(t, `chain(self, 2, via: .dotted_manager)`)
+	sql, _ = condToSQL(t, result.Conditions[0])
+	assertContains(t, sql, `"_e"."dotted_manager_path" = subpath((SELECT "dotted_manager_path"`)
+
+	// via: .manager is the management tree spelled out.
+	_, result, _ = viaPipeline(t, `reports(self, 1, via: .manager)`)
+	sql, _ = condToSQL(t, result.Conditions[0])
+	assertContains(t, sql, `"_e"."manager_path" <@ (SELECT "manager_path"`)
+}
+
+func TestViaPeers(t *testing.T) {
+	_, result, _ := viaPipeline(t, `peers(self, via: .dotted_manager)`)
+	sql, _ := condToSQL(t, result.Conditions[0])
+	assertContains(t, sql, `"_e"."dotted_manager_id" = (SELECT "dotted_manager_id" FROM "core"."employees" WHERE "id" = ?)`)
+}
+
+func TestViaWhere(t *testing.T) {
+	_, result, _ := viaPipeline(t, fmt.Sprintf(`employees | where(reports_to(., "%s", via: .dotted_manager) and all(reports(., 1, via: .dotted_manager), .employment_type == "FULL_TIME"))`, targetUUID))
+	sql, _ := condToSQL(t, result.Conditions[0])
+	assertContains(t, sql, `"_e"."dotted_manager_path" <@ (SELECT "dotted_manager_path"`)
+	assertContains(t, sql, `WHERE "_q"."dotted_manager_path" <@ "_e"."dotted_manager_path"`)
+
+	_, result, _ = viaPipeline(t, `employees | where(reports(., 1, via: .dotted_manager) | count > 3)`)
+	sql, _ = condToSQL(t, result.Conditions[0])
+	assertContains(t, sql, `"_sub_e"."dotted_manager_path" <@ "_e"."dotted_manager_path"`)
+}
+
+func TestViaReportsToBatch(t *testing.T) {
+	plan, _, cache := viaPipeline(t, fmt.Sprintf(`reports_to(self, "%s", via: .dotted_manager)`, targetUUID))
+	emp := cache.Get("employees")
+	sql, _, err := pg.TranslateBooleanPlan(plan, emp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, sql, `SELECT ((SELECT "dotted_manager_path" FROM`)
+
+	checks := []hrql.ReportsToCheck{
+		{Emp: hrql.EmployeeRef{ID: selfUUID}, Target: hrql.EmployeeRef{ID: targetUUID}},
+		plan.BoolCondition.(hrql.ReportsToCheck),
+	}
+	sql, _, err = pg.BatchReportsToSQL(checks, emp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, sql, `CASE "_b"."path" WHEN 0 THEN ("_be"."manager_path" <@ "_bt"."manager_path"`)
+	assertContains(t, sql, `WHEN 1 THEN ("_be"."dotted_manager_path" <@ "_bt"."dotted_manager_path"`)
+	assertContains(t, sql, `(1, $3::uuid, $4::uuid, 1)`)
+}
+
+func TestViaErrors(t *testing.T) {
+	for input, want := range map[string]string{
+		`reports(self, via: .department)`:              "not a hierarchy",
+		`reports(self, via: .nope)`:                    "unknown field",
+		`reports(self, via: self)`:                     "expected a single field",
+		`colleagues(self, .department, via: .manager)`: "no argument named",
+		`reports(self, via: .manager, 1)`:              "positional argument after named",
+		`reports(self, via: .manager, via: .manager)`:  "given twice",
+	} {
+		err := pipelineErr(input, selfUUID)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", input, want, err)
+		}
+	}
+}
+
+// --- Test: tenure() and *_since durations ---
+
+func TestTenureWhere(t *testing.T) {
+	_, result, _, _ := pipeline(t, `employees | where(tenure(.start_date) > 2y)`, "")
+	sql, args := condToSQL(t, result.Conditions[0])
+	assertContains(t, sql, `age(now(), "_e"."start_date") > ?::interval`)
+	if len(args) != 1 || args[0] != "2 years" {
+		t.Errorf("args = %v, want [2 years]", args)
+	}
+
+	// With an end field, open-ended records count up to now.
+	_, result, _, _ = pipeline(t, `employees | where(6mo <= tenure(.start_date, .end_date))`, "")
+	sql, args = condToSQL(t, result.Conditions[0])
+	assertContains(t, sql, `age(COALESCE("_e"."end_date", now()), "_e"."start_date") >= ?::interval`)
+	if args[0] != "6 months" {
+		t.Errorf("args = %v, want [6 months]", args)
+	}
+}
+
+func TestSinceWhere(t *testing.T) {
+	_, result, _, _ := pipeline(t, `employees | where(.start_date | years_since >= 5 and .end_date | days_since < 30)`, "")
+	sql, args := condToSQL(t, result.Conditions[0])
+	assertContains(t, sql, `EXTRACT(YEAR FROM age(now(), "_e"."start_date")) >= ?::numeric`)
+	assertContains(t, sql, `EXTRACT(DAY FROM (now())::timestamptz - ("_e"."end_date")::timestamptz) < ?::numeric`)
+	if len(args) != 2 || args[0] != "5" || args[1] != "30" {
+		t.Errorf("args = %v", args)
+	}
+}
+
+func TestSinceProjection(t *testing.T) {
+	plan, result, _, _ := pipeline(t, `employees | .start_date | months_since`, "")
+	if plan.Kind != hrql.PlanList || len(result.Computed) != 1 {
+		t.Fatalf("expected a list with one computed column, got %v %+v", plan.Kind, result.Computed)
+	}
+	if result.Computed[0].Key != "months_since" {
+		t.Errorf("key = %q", result.Computed[0].Key)
+	}
+	assertContains(t, result.Computed[0].SQL, `EXTRACT(YEAR FROM age(now(), "_e"."start_date")) * 12 + EXTRACT(MONTH FROM`)
+
+	_, result, _, _ = pipeline(t, `employees | where(.employment_type == "FULL_TIME") | .start_date | years_since | avg`, "")
+	assertContains(t, result.AggSQL, `SELECT avg(EXTRACT(YEAR FROM age(now(), "_e"."start_date")))`)
+}
+
+func TestDurationErrors(t *testing.T) {
+	for input, want := range map[string]string{
+		`employees | where(tenure(.start_date) > 2)`:                                     "compared with a duration",
+		`employees | where(.start_date | years_since > 2y)`:                              "compare it with a number",
+		`employees | where(tenure(.employee_number) > 2y)`:                               "expected DATE or DATETIME",
+		`employees | where(tenure(.manager.start_date) > 2y)`:                            "expected a single date field",
+		`employees | where(.start_date > 2y)`:                                            "only be compared with tenure()",
+		`employees | .employee_number | years_since`:                                     "expected DATE or DATETIME",
+		`employees | years_since`:                                                        "requires a date field",
+		`employees | where(.start_date | years_since > "many")`:                          "must be compared with a number",
+		`employees | where(tenure(.start_date) > 2y and .start_date | days_since > "x")`: "must be compared with a number",
+	} {
+		err := pipelineErr(input, selfUUID)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", input, want, err)
+		}
+	}
+}
+
+// --- Test: calendar buckets ---
+
+// calendarConditions compiles input at now against a cache with a custom
+// DATETIME field, reviewed_at__c, on employees.
+func calendarConditions(t *testing.T, input string, now time.Time) []hrql.Condition {
+	t.Helper()
+	cache := buildCache(schema.FieldDef{
+		ID: uuid.New(), APIName: "reviewed_at__c", Title: "Reviewed At", Type: schema.FieldDatetime,
+	})
+	ast, err := parser.Parse(input)
+	if err != nil {
+		t.Fatalf("parse %q: %v", input, err)
+	}
+	plan, _, err := hrql.NewCompiler(cache, "").At(now).Compile(ast)
+	if err != nil {
+		t.Fatalf("compile %q: %v", input, err)
+	}
+	return plan.Conditions
+}
+
+func TestCalendarBuckets(t *testing.T) {
+	now := time.Date(2026, time.August, 20, 15, 0, 0, 0, time.UTC)
+	fc := func(field, op, value string) hrql.FieldCmp {
+		return hrql.FieldCmp{Field: []string{field}, Op: op, Value: value}
+	}
+	for input, want := range map[string]hrql.Condition{
+		`employees | where(.start_date >= start_of_month())`:  fc("start_date", ">=", "2026-08-01"),
+		`employees | where(.start_date < start_of_quarter())`: fc("start_date", "<", "2026-07-01"),
+		`employees | where(.start_date == start_of_year())`:   fc("start_date", "==", "2026-01-01"),
+		`employees | where(.end_date == end_of_month())`:      fc("end_date", "==", "2026-08-31"),
+		`employees | where(.end_date <= end_of_quarter())`:    fc("end_date", "<", "2026-10-01"),
+		`employees | where(end_of_year() < .end_date)`:        fc("end_date", ">=", "2027-01-01"),
+		`employees | where(.start_date == this_month())`: hrql.AndCond{
+			Left: fc("start_date", ">=", "2026-08-01"), Right: fc("start_date", "<", "2026-09-01"),
+		},
+		`employees | where(.start_date != this_quarter())`: hrql.OrCond{
+			Left: fc("start_date", "<", "2026-07-01"), Right: fc("start_date", ">=", "2026-10-01"),
+		},
+		`employees | where(.start_date < this_year())`:  fc("start_date", "<", "2026-01-01"),
+		`employees | where(.start_date > this_year())`:  fc("start_date", ">=", "2027-01-01"),
+		`employees | where(.start_date <= this_year())`: fc("start_date", "<", "2027-01-01"),
+	} {
+		conds := calendarConditions(t, input, now)
+		if len(conds) != 1 || !reflect.DeepEqual(conds[0], want) {
+			t.Errorf("%s: got %+v, want %+v", input, conds, want)
+		}
+	}
+}
+
+func TestCalendarBucketsTimeZone(t *testing.T) {
+	// 23:30 UTC on March 31 is already April 1 in Tokyo.
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip(err)
+	}
+	now := time.Date(2026, time.March, 31, 23, 30, 0, 0, time.UTC).In(tokyo)
+
+	conds := calendarConditions(t, `employees | where(.start_date >= start_of_quarter())`, now)
+	if got := conds[0].(hrql.FieldCmp).Value; got != "2026-04-01" {
+		t.Errorf("start_of_quarter() = %q, want 2026-04-01", got)
+	}
+	// DATETIME fields compare with the instant the period starts in the zone.
+	conds = calendarConditions(t, `employees | where(.reviewed_at__c == this_month())`, now)
+	and, ok := conds[0].(hrql.AndCond)
+	if !ok {
+		t.Fatalf("expected AndCond, got %T", conds[0])
+	}
+	if got := and.Left.(hrql.FieldCmp).Value; got != "2026-04-01T00:00:00+09:00" {
+		t.Errorf("period start = %q", got)
+	}
+	if got := and.Right.(hrql.FieldCmp).Value; got != "2026-05-01T00:00:00+09:00" {
+		t.Errorf("period end = %q", got)
+	}
+}
+
+func TestCalendarBucketErrors(t *testing.T) {
+	for input, want := range map[string]string{
+		`employees | where(.employee_number >= start_of_month())`: "can only be compared with a DATE or DATETIME field",
+		`employees | where(this_year() == "2026")`:                "can only be compared with a date field",
+		`employees | where(.start_date in (this_year()))`:         "in values must be literals",
+	} {
+		err := pipelineErr(input, selfUUID)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", input, want, err)
+		}
+	}
+}
+
+// --- Test: sample(n) and sort_by(random) ---
+
+func TestSample(t *testing.T) {
+	plan, result, _, _ := pipeline(t, `employees | where(.employment_type == "FULL_TIME") | sample(25) | .start_date | years_since`, "")
+	if plan.Kind != hrql.PlanList || plan.Sample != 25 || plan.Limit != 25 {
+		t.Fatalf("expected a 25-record sample, got %+v", plan)
+	}
+	if !result.Random || result.OrderBy != nil || result.Sample != 25 || len(result.Conditions) != 1 {
+		t.Errorf("result = %+v", result)
+	}
+
+	_, result, _, _ = pipeline(t, `employees | sort_by(random)`, "")
+	if !result.Random || result.Limit != 0 || result.Sample != 0 {
+		t.Errorf("sort_by(random): %+v", result)
+	}
+}
+
+func TestIDs(t *testing.T) {
+	plan, result, _, _ := pipeline(t, `reports(self) | where(.employment_type == "FULL_TIME") | sort_by(.start_date) | ids`, selfUUID)
+	if plan.Kind != hrql.PlanIDs || len(plan.Conditions) != 3 {
+		t.Fatalf("expected PlanIDs with the reports, employed and where conditions, got %+v", plan)
+	}
+	if result.OrderBy == nil || result.OrderBy.FieldAPIName != "start_date" {
+		t.Errorf("order = %+v, want start_date", result.OrderBy)
+	}
+
+	empObj := testCache.Get("employees")
+	params, err := pg.ParseParams(empObj, pg.ParamsInput{Order: "start_date"})
+	if err != nil {
+		t.Fatalf("parse params: %v", err)
+	}
+	params.Limit = pg.MaxIDsLimit
+	sql, args, err := pg.NewBuilder(empObj).BuildIDs(params)
+	if err != nil {
+		t.Fatalf("build ids: %v", err)
+	}
+	assertContains(t, sql, `SELECT "_e"."id"::text AS _cursor_id, "_e"."start_date"::text AS _cursor_val FROM "core"."employees" "_e"`)
+	if strings.Contains(sql, "jsonb_build_object") {
+		t.Errorf("ids query builds records: %s", sql)
+	}
+	if got := args[len(args)-1]; got != pg.MaxIDsLimit+1 {
+		t.Errorf("limit arg = %v, want %d", got, pg.MaxIDsLimit+1)
+	}
+
+	for input, want := range map[string]string{
+		`employees | count | ids`:    "requires a list",
+		`employees | ids | count`:    "requires a list",
+		`employees | .manager | ids`: "cannot follow a projection",
+		`employees | case(when .employment_type == "CONTRACTOR" then 1) | ids`: "cannot follow a projection",
+		`union(employees, employees) | ids`:                                    "cannot follow union",
+	} {
+		err := pipelineErr(input, selfUUID)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: err = %v, want %q", input, err, want)
+		}
+	}
+}
+
+func TestUpdateIDsSQL(t *testing.T) {
+	empObj := testCache.Get("employees")
+	params, err := pg.ParseParams(empObj, pg.ParamsInput{Filters: map[string]string{"employment_type": "eq.CONTRACTOR"}})
+	if err != nil {
+		t.Fatalf("parse params: %v", err)
+	}
+	params.Order, params.Lock = nil, true
+	builder := pg.NewBuilder(empObj)
+	sql, _, err := builder.BuildIDs(params)
+	if err != nil {
+		t.Fatalf("build ids: %v", err)
+	}
+	assertContains(t, sql, `ORDER BY "_e"."id" ASC LIMIT $1 FOR UPDATE OF "_e"`)
+
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+	sql, args, err := builder.BuildUpdateIDs(ids, map[string]any{"employment_type": "FULL_TIME"})
+	if err != nil {
+		t.Fatalf("build update: %v", err)
+	}
+	assertContains(t, sql, `UPDATE "core"."employees" SET ("employment_type") =`)
+	assertContains(t, sql, `"version" = "version" + 1`)
+	assertContains(t, sql, `WHERE "id" = ANY($2::uuid[]) RETURNING "id"`)
+	if got, ok := args[len(args)-1].([]uuid.UUID); !ok || len(got) != 2 {
+		t.Errorf("ids arg = %v, want the two ids", args[len(args)-1])
+	}
+}
+
+func TestSampleList(t *testing.T) {
+	empObj := testCache.Get("employees")
+	params, err := pg.ParseParams(empObj, pg.ParamsInput{Limit: 25})
+	if err != nil {
+		t.Fatalf("parse params: %v", err)
+	}
+	params.Random = true
+
+	sql, _, err := pg.NewBuilder(empObj).BuildList(params)
+	if err != nil {
+		t.Fatalf("build list: %v", err)
+	}
+	assertContains(t, sql, "ORDER BY random() LIMIT")
+	if strings.Contains(sql, "TABLESAMPLE") {
+		t.Errorf("unsampled list reads a table sample: %s", sql)
+	}
+
+	params.SamplePercent = pg.SamplePercent(25, 1_000_000)
+	sql, _, err = pg.NewBuilder(empObj).BuildList(params)
+	if err != nil {
+		t.Fatalf("build list: %v", err)
+	}
+	assertContains(t, sql, `FROM "core"."employees" "_e" TABLESAMPLE BERNOULLI (0.05)`)
+}
+
+func TestSamplePercent(t *testing.T) {
+	for _, tc := range []struct {
+		n    int
+		rows float64
+		want float64
+	}{
+		{100, 50_000, 0},      // small table: ORDER BY random()
+		{100, -1, 0},          // never analyzed
+		{100, 1_000_000, 0.2}, // 2000 of 1M rows
+		{200, 100_000, 4},     // 4000 of 100k rows
+		{200, 300_000, 1.334}, // rounded up
+		{10_000, 150_000, 0},  // would need the whole table
+	} {
+		if got := pg.SamplePercent(tc.n, tc.rows); got != tc.want {
+			t.Errorf("SamplePercent(%d, %v) = %v, want %v", tc.n, tc.rows, got, tc.want)
+		}
+	}
+
+	if pg.Sampleable(&schema.ObjectDef{APIName: "projects"}) {
+		t.Error("custom objects share metadata.records and must not be sampled")
+	}
+	hist, err := pg.AsOf(testCache.Get("employees"), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("as_of: %v", err)
+	}
+	if pg.Sampleable(hist) || !pg.Sampleable(testCache.Get("employees")) {
+		t.Error("only the live standard table can be sampled")
+	}
+}
+
+func TestSampleErrors(t *testing.T) {
+	for input, want := range map[string]string{
+		`employees | sample(0)`:                                    "expected a positive count",
+		`employees | sample(.salary)`:                              "expected integer literal",
+		`employees | sort_by(.start_date) | sample(10)`:            "cannot follow sort_by",
+		`self | sample(1)`:                                         "single record",
+		`employees | count | sample(5)`:                            "requires a list source",
+		`employees | sample(10) | where(.employment_type == "X")`:  "where cannot follow sample(n)",
+		`employees | sample(10) | sort_by(.start_date)`:            "sort_by cannot follow sample(n)",
+		`employees | sample(10) | first`:                           "first cannot follow sample(n)",
+		`employees | sample(10) | count`:                           "count cannot follow sample(n)",
+		`employees | sample(10) | .start_date | years_since | avg`: "avg cannot follow sample(n)",
+		`employees | sample(10) | length`:                          "length cannot follow sample(n)",
+		`employees | sample(10) | sample(5)`:                       "sample cannot follow sample(n)",
+	} {
+		err := pipelineErr(input, selfUUID)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", input, want, err)
+		}
+	}
+}
+
+// --- Test: display templates ---
+
+func TestDisplayTemplate(t *testing.T) {
+	parts, err := schema.ParseDisplayTemplate("{title} ({ code })")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	want := []schema.DisplayPart{{Field: "title"}, {Text: " ("}, {Field: "code"}, {Text: ")"}}
+	if fmt.Sprint(parts) != fmt.Sprint(want) {
+		t.Errorf("parts = %v, want %v", parts, want)
+	}
+
+	for tmpl, msg := range map[string]string{
+		"plain text":               "references no field",
+		"{title":                   "unclosed {",
+		"title}":                   "unmatched }",
+		"{}":                       "expected a field name",
+		"{a{b}":                    "expected a field name",
+		strings.Repeat("{x}", 100): "longer than",
+	} {
+		if _, err := schema.ParseDisplayTemplate(tmpl); err == nil || !strings.Contains(err.Error(), msg) {
+			t.Errorf("%q: got %v, want %q", tmpl, err, msg)
+		}
+	}
+
+	empObj := testCache.Get("employees")
+	for tmpl, msg := range map[string]string{
+		"{nope}":        "unknown field",
+		"{national_id}": "ENCRYPTED fields cannot be displayed",
+		"{manager}":     "LOOKUP fields cannot be displayed",
+	} {
+		if err := empObj.CheckDisplayTemplate(tmpl); err == nil || !strings.Contains(err.Error(), msg) {
+			t.Errorf("%q: got %v, want %q", tmpl, err, msg)
+		}
+	}
+	if err := empObj.CheckDisplayTemplate("#{employee_number} since {start_date}"); err != nil {
+		t.Errorf("valid template: %v", err)
+	}
+}
+
+func TestDisplayExpand(t *testing.T) {
+	cache := buildCache()
+	cache.Get("departments").DisplayTemplate = "{title} '{gone}'"
+	empObj := cache.Get("employees")
+
+	if got := pg.DisplayExpr(empObj, "_e"); got != "" {
+		t.Errorf("object without template: %q", got)
+	}
+
+	params, err := pg.ParseParams(empObj, pg.ParamsInput{Expand: "department"})
+	if err != nil {
+		t.Fatalf("parse params: %v", err)
+	}
+	params.ExpandPlans = pg.ResolveExpands(params.Expand, empObj, cache)
+	sql, _, err := pg.NewBuilder(empObj).BuildList(params)
+	if err != nil {
+		t.Fatalf("build list: %v", err)
+	}
+	// Unknown fields drop out and literal quotes are escaped.
+	assertContains(t, sql, `NULLIF(btrim(concat(("_xp_department_t"."title")::text, ' ''', '''')), '') AS "_display"`)
+
+	batch, _, err := pg.BuildExpandBatch(&params.ExpandPlans[0], []string{targetUUID})
+	if err != nil {
+		t.Fatalf("build batch: %v", err)
+	}
+	assertContains(t, batch, `AS "_display"`)
+}
+
+func TestTypeaheadSQL(t *testing.T) {
+	cache := buildCache()
+	dept := cache.Get("departments")
+	if _, _, err := pg.BuildTypeahead(dept, "a", 10); err == nil {
+		t.Error("expected an error for an object without a display template")
+	}
+
+	dept.DisplayTemplate = "{title}"
+	sql, args, err := pg.BuildTypeahead(dept, `50%_off\`, 10)
+	if err != nil {
+		t.Fatalf("build typeahead: %v", err)
+	}
+	assertContains(t, sql, `SELECT "_e"."id"::text, NULLIF(btrim(concat(("_e"."title")::text)), '') FROM "core"."departments" "_e" WHERE NULLIF(btrim(concat(("_e"."title")::text)), '') ILIKE $1 ORDER BY`)
+	assertContains(t, sql, "LIMIT $2")
+	if len(args) != 2 || args[0] != `50\%\_off\\%` || args[1] != 10 {
+		t.Errorf("args = %q", args)
+	}
+}
+
+func TestLookupSearchSQL(t *testing.T) {
+	cache := buildCache()
+	dept := cache.Get("departments")
+	if _, _, err := pg.BuildLookupSearch(dept, "eng", 20); err == nil {
+		t.Error("expected an error for an object without a display template")
+	}
+
+	dept.DisplayTemplate = "{title}"
+	display := `NULLIF(btrim(concat(("_e"."title")::text)), '')`
+	sql, args, err := pg.BuildLookupSearch(dept, "eng_", 20)
+	if err != nil {
+		t.Fatalf("build lookup search: %v", err)
+	}
+	assertContains(t, sql, `WHERE (`+display+` ILIKE $1 OR word_similarity($2, `+display+`) >= $3)`)
+	assertContains(t, sql, `ORDER BY `+display+` ILIKE $4 DESC, word_similarity($5, `+display+`) DESC, `+display+`, "_e"."id" LIMIT $6`)
+	want := []any{`%eng\_%`, "eng_", pg.LookupSimilarity, `eng\_%`, "eng_", 20}
+	if fmt.Sprint(args) != fmt.Sprint(want) {
+		t.Errorf("args = %q, want %q", args, want)
+	}
+
+	sql, args, err = pg.BuildLookupSearch(dept, "", 5)
+	if err != nil {
+		t.Fatalf("build lookup search: %v", err)
+	}
+	if strings.Contains(sql, "WHERE") || fmt.Sprint(args) != "[5]" {
+		t.Errorf("empty search should list every record: %s %v", sql, args)
+	}
+	assertContains(t, sql, `ORDER BY `+display+`, "_e"."id" LIMIT $1`)
+}
+
+// --- Test: relations (object(.)) in where ---
+
+func TestRelationCount(t *testing.T) {
+	plan, result, _, _ := pipeline(t, `departments | where(employees(.) | count > 50)`, "")
+	if plan.Object != "departments" || plan.Kind != hrql.PlanList {
+		t.Fatalf("expected a departments list, got %q %v", plan.Object, plan.Kind)
+	}
+	agg, ok := plan.Conditions[0].(hrql.RelatedAgg)
+	if !ok || agg.Object != "employees" || agg.Via != "department" || agg.AggFunc != "count" {
+		t.Fatalf("expected employees(.) | count via department, got %#v", plan.Conditions[0])
+	}
+	sql, args := condToSQL(t, result.Conditions[0])
+	assertContains(t, sql, `(SELECT count(*) FROM (SELECT "_e"."department_id" AS "fk", NULL AS "v" FROM "core"."employees" "_e") "_r" WHERE "_r"."fk" = "_e"."id") > ?`)
+	assertArgCount(t, args, 1)
+	assertArgEquals(t, args, 0, "50")
+}
+
+func TestRelationFilteredAggregate(t *testing.T) {
+	_, result, _, _ := pipeline(t, `departments | where(employees(.) | where(.employment_type == "FULL_TIME") | .start_date | min < "2020-01-01")`, "")
+	sql, args := condToSQL(t, result.Conditions[0])
+	assertContains(t, sql, `(SELECT min("_r"."v") FROM (SELECT "_e"."department_id" AS "fk", "_e"."start_date" AS "v" FROM "core"."employees" "_e" WHERE "_e"."employment_type" = ?) "_r" WHERE "_r"."fk" = "_e"."id") < ?`)
+	assertArgCount(t, args, 2)
+	assertArgEquals(t, args, 0, "FULL_TIME")
+	assertArgEquals(t, args, 1, "2020-01-01")
+}
+
+func TestRelationSelf(t *testing.T) {
+	// employees has one lookup back to employees (manager), so via is optional.
+	for _, q := range []string{
+		`employees | where(employees(.) | count >= 5)`,
+		`employees | where(employees(., via: .manager) | count >= 5)`,
+	} {
+		_, result, _, _ := pipeline(t, q, "")
+		sql, _ := condToSQL(t, result.Conditions[0])
+		assertContains(t, sql, `SELECT "_e"."manager_id" AS "fk"`)
+	}
+}
+
+func TestRelationOverOtherObject(t *testing.T) {
+	plan, result, _, _ := pipeline(t, `departments | where(.title | contains("Eng")) | count`, "")
+	if plan.Object != "departments" || plan.Kind != hrql.PlanScalar {
+		t.Fatalf("expected a departments count, got %q %v", plan.Object, plan.Kind)
+	}
+	assertContains(t, result.AggSQL, `FROM "core"."departments" "_e"`)
+}
+
+func TestRelationErrors(t *testing.T) {
+	for q, msg := range map[string]string{
+		`departments | where(reports(.) | count > 0)`:                    "reports() requires employees, not departments",
+		`departments | where(reports_to(., self))`:                       "reports_to() requires employees",
+		`departments | first | reports(.)`:                               "reports() requires employees",
+		`departments | where(.title == self.department)`:                 "self.field requires employees",
+		`departments | where(.manager == "x")`:                           `unknown field "manager"`,
+		`departments | where(employees(.) | sum > 1)`:                    "sum needs a field",
+		`departments | where(employees(.) | .employee_number | avg > 1)`: "is not numeric",
+		`departments | where(employees(.) | count | count > 1)`:          "aggregation must be the last step",
+		`departments | where(employees(.) | .start_date > 1)`:            "must end with an aggregation",
+		`departments | where(employees(., via: .manager) | count > 1)`:   "not a lookup to departments",
+		`employees | where(departments(.) | count > 0)`:                  "departments has no lookup to employees",
+		`departments | where(projects(.) | count > 0)`:                   `unknown object "projects"`,
+		`projects | count`: `unknown identifier "projects"`,
+		`(employees | count) / (departments | count)`: `unknown identifier "departments"`,
+	} {
+		err := pipelineErr(q, selfUUID)
+		if err == nil || !strings.Contains(err.Error(), msg) {
+			t.Errorf("%s: got %v, want %q", q, err, msg)
+		}
+	}
+}
+
+// --- Test: org chart diff ---
+
+func TestOrgDiffSQL(t *testing.T) {
+	emp := testCache.Get("employees")
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	sql, args, err := pg.BuildOrgDiff(emp, from, to, "", 101)
+	if err != nil {
+		t.Fatalf("build diff: %v", err)
+	}
+	assertContains(t, sql, `FROM "core"."employees_as_of"('2024-01-01T00:00:00Z'::timestamptz) "_a" FULL JOIN "core"."employees_as_of"('2024-07-01T00:00:00Z'::timestamptz) "_b" ON "_a"."id" = "_b"."id"`)
+	assertContains(t, sql, `"_a"."manager_id" IS DISTINCT FROM "_b"."manager_id"`)
+	assertContains(t, sql, `"_a"."department_id" IS DISTINCT FROM "_b"."department_id"`)
+	assertContains(t, sql, `"_b"."end_date" <= $1::date AND ("_a"."end_date" IS NULL OR "_a"."end_date" > $2::date)`)
+	assertContains(t, sql, "LIMIT 101")
+	assertArgCount(t, args, 4)
+	assertArgEquals(t, args, 0, "2024-07-01")
+	assertArgEquals(t, args, 1, "2024-01-01")
+
+	sql, args, err = pg.BuildOrgDiff(emp, from, to, targetUUID, 10)
+	if err != nil {
+		t.Fatalf("build diff with root: %v", err)
+	}
+	assertContains(t, sql, `"_a"."manager_path" <@ (SELECT "_r"."manager_path" FROM "core"."employees_as_of"('2024-01-01T00:00:00Z'::timestamptz) "_r" WHERE "_r"."id" = $5)`)
+	assertArgEquals(t, args, 5, targetUUID)
+
+	if _, _, err := pg.BuildOrgDiff(testCache.Get("departments"), from, to, "", 10); err == nil {
+		t.Error("expected an error for an object without a reporting hierarchy")
+	}
+}
+
+// --- Test: compiler warnings ---
+
+func TestCompileWarnings(t *testing.T) {
+	for q, want := range map[string][]string{
+		`employees | where(.department.title == "Eng") | count`: nil,
+		`employees | unique`:                                     {hrql.WarnNoOp},
+		`employees | unique | unique | count`:                    {hrql.WarnNoOp},
+		`employees | .department | unique | count`:               nil,
+		`employees | .department | unique`:                       {hrql.WarnNoOp},
+		`employees | .employee_number | upper | count`:           {hrql.WarnNoOp},
+		`employees | sort_by(.department.title)`:                 {hrql.WarnChainTruncated},
+		`employees | where(.department.title | contains("Eng"))`: {hrql.WarnChainTruncated},
+		`employees | .department.title | count`:                  {hrql.WarnChainTruncated},
+		`employees | sort_by(.department.title) | lower`:         {hrql.WarnChainTruncated, hrql.WarnNoOp},
+	} {
+		ast, err := parser.Parse(q)
+		if err != nil {
+			t.Fatalf("parse %q: %v", q, err)
+		}
+		_, warnings, err := hrql.NewCompiler(testCache, selfUUID).Compile(ast)
+		if err != nil {
+			t.Fatalf("compile %q: %v", q, err)
+		}
+		var got []string
+		for _, w := range warnings {
+			got = append(got, w.Code)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: warnings %v, want %v", q, warnings, want)
+		}
+	}
+
+	ast, _ := parser.Parse(`employees | sort_by(.department.title)`)
+	_, warnings, _ := hrql.NewCompiler(testCache, "").Compile(ast)
+	if len(warnings) != 1 || warnings[0].Message != "sort_by uses .department only; .department.title is ignored" {
+		t.Errorf("message = %v", warnings)
+	}
+}
+
+// --- Test: expand cost budget ---
+
+func TestExpandBudget(t *testing.T) {
+	emp := testCache.Get("employees")
+	plans := func(expand, sel string) []pg.ExpandPlan {
+		t.Helper()
+		params, err := pg.ParseParams(emp, pg.ParamsInput{Expand: expand, Select: sel})
+		if err != nil {
+			t.Fatalf("parse params: %v", err)
+		}
+		ps := pg.ResolveExpands(params.Expand, emp, testCache)
+		if err := pg.ProjectExpands(ps, params.ExpandSelect); err != nil {
+			t.Fatalf("project: %v", err)
+		}
+		return ps
+	}
+
+	// employees: 7 fields + 4 system fields; departments: 1 + 4.
+	total, costs := pg.ExpandCosts(plans("department,manager,manager.department", ""))
+	if total != 21 {
+		t.Errorf("total = %d, want 21", total)
+	}
+	want := []pg.ExpandCost{{Path: "manager", Columns: 11}, {Path: "department", Columns: 5}, {Path: "manager.department", Columns: 5}}
+	if !slices.Equal(costs, want) {
+		t.Errorf("costs = %v, want %v", costs, want)
+	}
+
+	err := pg.CheckExpandBudget(plans("department,manager,manager.department", ""), 20)
+	budgetErr, ok := errors.AsType[*pg.ExpandBudgetError](err)
+	if !ok {
+		t.Fatalf("expected an ExpandBudgetError, got %v", err)
+	}
+	if budgetErr.Columns != 21 || !strings.Contains(err.Error(), "manager (11), department (5), manager.department (5)") {
+		t.Errorf("error = %v", err)
+	}
+
+	// A select on the expanded record narrows it under the budget.
+	if err := pg.CheckExpandBudget(plans("department,manager,manager.department", "manager.employee_number"), 20); err != nil {
+		t.Errorf("narrowed expands: %v", err)
+	}
+	if err := pg.CheckExpandBudget(plans("department,manager,manager.department", ""), 0); err != nil {
+		t.Errorf("disabled budget: %v", err)
+	}
+}
+
+// --- Test: positions ---
+
+var posObjID = uuid.MustParse("00000000-0000-0000-0000-000000000003")
+
+// positionsCache is testCache plus the positions object: employee (required)
+// and manager lookups to employees and a department lookup.
+func positionsCache() *schema.Cache {
+	pos := &schema.ObjectDef{
+		ID:              posObjID,
+		APIName:         "positions",
+		Title:           "Position",
+		PluralTitle:     "Positions",
+		IsStandard:      true,
+		StorageSchema:   new("core"),
+		StorageTable:    new("positions"),
+		FieldsByAPIName: make(map[string]*schema.FieldDef),
+	}
+	pos.Fields = []schema.FieldDef{
+		{ID: uuid.New(), APIName: "employee", Title: "Employee", Type: schema.FieldLookup, IsRequired: true, IsStandard: true, StorageColumn: new("employee_id"), LookupObjectID: new(empObjID)},
+		{ID: uuid.New(), APIName: "department", Title: "Department", Type: schema.FieldLookup, IsRequired: true, IsStandard: true, StorageColumn: new("department_id"), LookupObjectID: new(deptObjID)},
+		{ID: uuid.New(), APIName: "manager", Title: "Manager", Type: schema.FieldLookup, IsStandard: true, StorageColumn: new("manager_id"), LookupObjectID: new(empObjID)},
+		{ID: uuid.New(), APIName: "title", Title: "Title", Type: schema.FieldText, IsRequired: true, IsStandard: true, StorageColumn: new("title")},
+		{ID: uuid.New(), APIName: "fte", Title: "FTE", Type: schema.FieldNumber, IsRequired: true, IsStandard: true, StorageColumn: new("fte")},
+	}
+	for i := range pos.Fields {
+		pos.FieldsByAPIName[pos.Fields[i].APIName] = &pos.Fields[i]
+	}
+	return schema.NewCacheFromObjects(testCache.Get("departments"), testCache.Get("employees"), pos)
+}
+
+func compilePositions(t *testing.T, cache *schema.Cache, input string) (*hrql.Plan, error) {
+	t.Helper()
+	ast, err := parser.Parse(input)
+	if err != nil {
+		t.Fatalf("parse %q: %v", input, err)
+	}
+	plan, _, err := hrql.NewCompiler(cache, selfUUID).Compile(ast)
+	return plan, err
+}
+
+func TestPositionsOrgFunctions(t *testing.T) {
+	cache := positionsCache()
+	emp := cache.Get("employees")
+	listSQL := func(input string) (string, []any) {
+		t.Helper()
+		plan, err := compilePositions(t, cache, input)
+		if err != nil {
+			t.Fatalf("compile %q: %v", input, err)
+		}
+		result, err := pg.Translate(plan, emp, cache)
+		if err != nil {
+			t.Fatalf("translate %q: %v", input, err)
+		}
+		return condToSQL(t, result.Conditions[0])
+	}
+
+	sql, args := listSQL(`reports(self, via: positions)`)
+	assertContains(t, sql, `"_e"."id" IN (WITH RECURSIVE "_pg"("id", "depth") AS (SELECT "_p"."employee_id", 1 FROM "core"."positions" "_p" WHERE "_p"."manager_id" = ?`)
+	assertContains(t, sql, `JOIN "_pg" ON "_p"."manager_id" = "_pg"."id"`)
+	assertContains(t, sql, `"_p"."start_date" <= CURRENT_DATE`)
+	assertContains(t, sql, `AND "_e"."id" != ?`)
+	if strings.Contains(sql, "manager_path") || strings.Contains(sql, `WHERE "depth" = ?`) {
+		t.Errorf("expected an unbounded walk of the position graph, got %s", sql)
+	}
+	assertArgEquals(t, args, 0, selfUUID)
+	assertArgEquals(t, args, 1, 64)
+
+	sql, args = listSQL(`reports(self, 1, via: positions)`)
+	assertContains(t, sql, `SELECT "id" FROM "_pg" WHERE "depth" = ?)`)
+	assertArgEquals(t, args, 2, 1)
+
+	sql, _ = listSQL(`chain(self, 2, via: positions)`)
+	assertContains(t, sql, `SELECT "_p"."manager_id", 1 FROM "core"."positions" "_p" WHERE "_p"."employee_id" = ?`)
+	assertContains(t, sql, `WHERE "depth" = ?`)
+
+	sql, _ = listSQL(fmt.Sprintf(`employees | where(reports_to(., "%s", via: positions))`, targetUUID))
+	assertContains(t, sql, `WHERE "_p"."manager_id" = ?`)
+
+	plan, err := compilePositions(t, cache, fmt.Sprintf(`reports_to(self, "%s", via: positions)`, targetUUID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	boolSQL, args, err := pg.TranslateBooleanPlan(plan, emp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, boolSQL, `SELECT ($1 IN (WITH RECURSIVE "_pg"`)
+	assertContains(t, boolSQL, `WHERE "_p"."employee_id" = $2`)
+	assertArgEquals(t, args, 0, targetUUID)
+	assertArgEquals(t, args, 1, selfUUID)
+
+	if _, _, err := pg.BatchReportsToSQL([]hrql.ReportsToCheck{plan.BoolCondition.(hrql.ReportsToCheck)}, emp); err == nil {
+		t.Error("expected batches to reject via: positions")
+	}
+}
+
+func TestPositionsOrgFunctionErrors(t *testing.T) {
+	cache := positionsCache()
+	for input, want := range map[string]string{
+		`network(self, 2, via: positions)`:                             "only supported by chain, reports and reports_to",
+		`peers(self, via: positions)`:                                  "only supported by chain, reports and reports_to",
+		`employees | where(reports(., 1, via: positions) | count > 2)`: "only supported by chain, reports and reports_to",
+		`reports(self, via: departments)`:                              "expected a single field (.field) or positions",
+	} {
+		if _, err := compilePositions(t, cache, input); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: error %v, want %q", input, err, want)
+		}
+	}
+
+	if _, err := compilePositions(t, testCache, `reports(self, via: positions)`); err == nil || !strings.Contains(err.Error(), "positions object not found") {
+		t.Errorf("without positions: error %v", err)
+	}
+}
+
+func TestReverseExpand(t *testing.T) {
+	cache := positionsCache()
+	emp := cache.Get("employees")
+
+	if _, err := pg.ParseParams(emp, pg.ParamsInput{Expand: "positions"}); err == nil {
+		t.Error("expected reverse expands to need the cache")
+	}
+
+	params, err := pg.ParseParams(emp, pg.ParamsInput{Expand: "positions,positions.department", Select: "employee_number,positions.title,positions.department", Cache: cache})
+	if err != nil {
+		t.Fatal(err)
+	}
+	params.ExpandPlans = pg.ResolveExpands(params.Expand, emp, cache)
+	if err := pg.ProjectExpands(params.ExpandPlans, params.ExpandSelect); err != nil {
+		t.Fatal(err)
+	}
+	if len(params.ExpandPlans) != 1 || !params.ExpandPlans[0].Reverse || params.ExpandPlans[0].Field.APIName != "employee" {
+		t.Fatalf("plans = %+v", params.ExpandPlans)
+	}
+	if slices.Contains(params.Select, "positions") {
+		t.Errorf("select = %v, reverse expands are not fields", params.Select)
+	}
+
+	sql, _, err := pg.NewBuilder(emp).BuildList(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, sql, `'positions', "_xp_positions"."records"`)
+	assertContains(t, sql, `LEFT JOIN LATERAL (SELECT COALESCE(jsonb_agg(to_jsonb("_xp_positions_r".*) ORDER BY "_xp_positions_r"."id"), '[]'::jsonb) AS "records"`)
+	assertContains(t, sql, `WHERE "_xp_positions_t"."employee_id" = "_e"."id" ORDER BY 1 LIMIT 100`)
+	assertContains(t, sql, `"_xp_positions_t"."title" AS "title"`)
+	assertContains(t, sql, `CASE WHEN "_xp_positions__department"."id" IS NOT NULL THEN to_jsonb("_xp_positions__department".*) ELSE NULL END AS "department"`)
+
+	batch, args, err := pg.BuildReverseExpandBatch(&params.ExpandPlans[0], []string{selfUUID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, batch, `SELECT "_o"."id"::text, "_xp_positions"."records" FROM unnest($1::uuid[]) AS "_o"("id") LEFT JOIN LATERAL`)
+	assertContains(t, batch, `"_xp_positions_t"."employee_id" = "_o"."id"`)
+	if ids, ok := args[0].([]string); !ok || !slices.Equal(ids, []string{selfUUID}) {
+		t.Errorf("args = %v", args)
+	}
+
+	// departments: positions has one lookup to departments.
+	dept := cache.Get("departments")
+	if plans := pg.ResolveExpands([]string{"positions"}, dept, cache); len(plans) != 1 || plans[0].Field.APIName != "department" {
+		t.Errorf("department plans = %+v", plans)
+	}
+}
+
+// --- Test: union of employees and a custom object ---
+
+func TestExpandCount(t *testing.T) {
+	cache := positionsCache()
+	emp := cache.Get("employees")
+
+	params, err := pg.ParseParams(emp, pg.ParamsInput{Expand: "manager, reports:count,positions:count,reports:count", Cache: cache})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(params.Expand, []string{"manager"}) || len(params.RelatedCounts) != 2 {
+		t.Fatalf("expand = %v, counts = %+v", params.Expand, params.RelatedCounts)
+	}
+	params.ExpandPlans = pg.ResolveExpands(params.Expand, emp, cache)
+	sql, _, err := pg.NewBuilder(emp).BuildList(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, sql, `'reports_count', (SELECT count(*) FROM "core"."employees" "_r" WHERE "_r"."manager_path" <@ "_e"."manager_path" AND "_r"."manager_path" != "_e"."manager_path" AND ("_r"."end_date" IS NULL OR "_r"."end_date" > CURRENT_DATE))`)
+	assertContains(t, sql, `'positions_count', (SELECT count(*) FROM "core"."positions" "_r" WHERE "_r"."employee_id" = "_e"."id")`)
+	if echo := pg.EchoParams(emp, params, true); !slices.Equal(echo.Expand, []string{"manager", "reports:count", "positions:count"}) {
+		t.Errorf("echo expand = %v", echo.Expand)
+	}
+
+	params, err = pg.ParseParams(emp, pg.ParamsInput{Expand: "reports:count", IncludeTerminated: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sql, _, _ = pg.NewBuilder(emp).BuildList(params)
+	if strings.Contains(sql, `"_r"."end_date"`) {
+		t.Errorf("include_terminated: reports counted with an end_date check, got %s", sql)
+	}
+
+	dept := cache.Get("departments")
+	for input, want := range map[string]string{
+		"reports:sum":   `unsupported aggregate "sum"`,
+		"nope:count":    `"nope" is not a relation of employees`,
+		"manager:count": `"manager" is not a relation of employees`,
+	} {
+		if _, err := pg.ParseParams(emp, pg.ParamsInput{Expand: input, Cache: cache}); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: error %v, want %q", input, err, want)
+		}
+	}
+	if _, err := pg.ParseParams(dept, pg.ParamsInput{Expand: "reports:count", Cache: cache}); err == nil {
+		t.Error("expected reports:count to be limited to employees")
+	}
+}
+
+var contractorObjID = uuid.MustParse("00000000-0000-0000-0000-000000000004")
+
+// unionCache is testCache plus contractors, a custom object sharing
+// start_date, department and employment_type with employees. end_date is
+// TEXT there, so it cannot line up with the employees DATE.
+func unionCache(endDateType schema.FieldType) *schema.Cache {
+	obj := &schema.ObjectDef{
+		ID:              contractorObjID,
+		APIName:         "contractors",
+		Title:           "Contractor",
+		PluralTitle:     "Contractors",
+		FieldsByAPIName: make(map[string]*schema.FieldDef),
+	}
+	obj.Fields = []schema.FieldDef{
+		{ID: uuid.New(), APIName: "start_date", Title: "Start Date", Type: schema.FieldDate},
+		{ID: uuid.New(), APIName: "department", Title: "Department", Type: schema.FieldLookup, LookupObjectID: new(deptObjID)},
+		{ID: uuid.New(), APIName: "employment_type", Title: "Employment Type", Type: schema.FieldText},
+		{ID: uuid.New(), APIName: "end_date", Title: "End Date", Type: endDateType},
+		{ID: uuid.New(), APIName: "rate", Title: "Rate", Type: schema.FieldNumber},
+	}
+	for i := range obj.Fields {
+		obj.FieldsByAPIName[obj.Fields[i].APIName] = &obj.Fields[i]
+	}
+	return schema.NewCacheFromObjects(testCache.Get("departments"), testCache.Get("employees"), obj)
+}
+
+func TestUnionList(t *testing.T) {
+	cache := unionCache(schema.FieldDate)
+	plan, err := compilePositions(t, cache, `union(employees | where(.employment_type == "FULL_TIME"), contractors | where(.rate > 100)) | sort_by(.start_date, desc) | first`)
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	if plan.Kind != hrql.PlanList || plan.Union == nil || len(plan.Union.Sources) != 2 {
+		t.Fatalf("expected a union list plan, got %+v", plan)
+	}
+	if got := strings.Join(plan.Union.Fields, ","); got != "employment_type,start_date,end_date,department" {
+		t.Errorf("shared fields = %s", got)
+	}
+	if plan.Union.Sources[0].Object != "employees" || plan.Union.Sources[1].Object != "contractors" {
+		t.Errorf("source objects = %q, %q", plan.Union.Sources[0].Object, plan.Union.Sources[1].Object)
+	}
+
+	sql, args, err := pg.BuildUnionList(plan.Union, cache, pg.UnionListParams{Order: &pg.OrderClause{FieldAPIName: "start_date", Desc: true}, Limit: 1})
+	if err != nil {
+		t.Fatalf("build union list: %v", err)
+	}
+	assertContains(t, sql, `'_object', 'employees', 'employment_type', "_e"."employment_type", 'start_date', "_e"."start_date"`)
+	assertContains(t, sql, `'_object', 'contractors', 'employment_type', "_e"."data"->'employment_type', 'start_date', "_e"."data"->'start_date'`)
+	assertContains(t, sql, `"_e"."start_date"::timestamptz AS _sort FROM "core"."employees" "_e" WHERE "_e"."employment_type" = $1)`)
+	assertContains(t, sql, `("_e"."data"->>'start_date')::timestamptz AS _sort FROM "metadata"."records" "_e" WHERE "_e"."object_id" = $2`)
+	assertContains(t, sql, `) UNION ALL (SELECT`)
+	assertContains(t, sql, `ORDER BY "_u"."_sort" DESC NULLS LAST, "_u"."_id" DESC LIMIT $4`)
+	assertContains(t, sql, `("_e"."data"->>'rate')::numeric > $3`)
+	if strings.Contains(sql, `'rate',`) {
+		t.Errorf("rate is not shared and must not be projected: %s", sql)
+	}
+	assertArgEquals(t, args, 0, "FULL_TIME")
+	if args[len(args)-1] != 2 {
+		t.Errorf("LIMIT arg = %v, want 2 (one more than the page)", args[len(args)-1])
+	}
+
+	sql, _, err = pg.BuildUnionList(plan.Union, cache, pg.UnionListParams{Select: []string{"department"}, Limit: 10})
+	if err != nil {
+		t.Fatalf("build union list: %v", err)
+	}
+	assertContains(t, sql, `'_object', 'employees', 'department', "_e"."department_id")`)
+	if strings.Contains(sql, "_sort") || strings.Contains(sql, "start_date") {
+		t.Errorf("expected only the selected field and id order: %s", sql)
+	}
+
+	sql, _, err = pg.BuildUnionCount(plan.Union, cache)
+	if err != nil {
+		t.Fatalf("build union count: %v", err)
+	}
+	assertContains(t, sql, `SELECT count(*) FROM ((SELECT 1 FROM "core"."employees" "_e" WHERE "_e"."employment_type" = $1) UNION ALL (SELECT 1 FROM "metadata"."records" "_e"`)
+}
+
+func TestUnionScalar(t *testing.T) {
+	cache := unionCache(schema.FieldDate)
+	plan, err := compilePositions(t, cache, `union(employees, contractors | where(.rate > 100)) | count | percent_of(union(employees, contractors) | count)`)
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	result, err := pg.Translate(plan, cache.Get("employees"), cache)
+	if err != nil {
+		t.Fatalf("translate: %v", err)
+	}
+	assertContains(t, result.AggSQL, `(SELECT count(*) FROM ((SELECT 1 AS _v FROM "core"."employees" "_e") UNION ALL (SELECT 1 AS _v FROM "metadata"."records" "_e" WHERE "_e"."object_id" = $1 AND ("_e"."data"->>'rate')::numeric > $2)) "_u")`)
+
+	plan, err = compilePositions(t, cache, `union(employees, contractors) | .start_date | min`)
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	result, err = pg.Translate(plan, cache.Get("employees"), cache)
+	if err != nil {
+		t.Fatalf("translate: %v", err)
+	}
+	assertContains(t, result.AggSQL, `SELECT min("_u"."_v")::text FROM ((SELECT "_e"."start_date"::timestamptz AS _v FROM "core"."employees" "_e") UNION ALL`)
+}
+
+func TestUnionLeavesOutMaskedFields(t *testing.T) {
+	cache := unionCache(schema.FieldDate)
+	cache.Get("contractors").FieldsByAPIName["employment_type"].Masking = &schema.MaskingPolicy{Default: schema.MaskHide}
+	plan, err := compilePositions(t, cache, `union(employees, contractors)`)
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	if got := strings.Join(plan.Union.Fields, ","); got != "start_date,end_date,department" {
+		t.Errorf("shared fields = %s", got)
+	}
+}
+
+func TestUnionErrors(t *testing.T) {
+	cache := unionCache(schema.FieldDate)
+	for input, want := range map[string]string{
+		`union(employees, contractors) | where(.start_date > "2024-01-01")`:           "where cannot follow union",
+		`union(employees, contractors) | sort_by(.rate)`:                              `unknown field "rate"`,
+		`union(employees, contractors) | nth(2)`:                                      "nth cannot follow union",
+		`union(employees, union(employees, contractors))`:                             "unions cannot be nested",
+		`union(employees | count, contractors)`:                                       "union source 1: expected a list",
+		`union(employees | sort_by(.start_date), contractors)`:                        "sort after union",
+		`union(employees, vendors)`:                                                   `unknown identifier "vendors"`,
+		`employees | where(.id in union(employees, contractors))`:                     "cannot pick, sample, use as_of or union",
+		`union(employees | as_of("2024-01-01"), contractors)`:                         "as_of is not supported inside union",
+		`union(employees | where(.start_date > "2024-01-01") | sample(3), employees)`: "sample and random order",
+	} {
+		_, err := compilePositions(t, cache, input)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: err = %v, want %q", input, err, want)
+		}
+	}
+
+	_, err := compilePositions(t, unionCache(schema.FieldText), `union(employees, contractors)`)
+	if err == nil || !strings.Contains(err.Error(), `field "end_date" is DATE on employees but TEXT on contractors`) {
+		t.Errorf("incompatible end_date: err = %v", err)
+	}
+}
+
+// --- Test: lookup chains into custom objects ---
+
+var (
+	projectObjID = uuid.MustParse("00000000-0000-0000-0000-000000000005")
+	programObjID = uuid.MustParse("00000000-0000-0000-0000-000000000006")
+)
+
+// customObject returns a custom object (stored in metadata.records) with the
+// given fields.
+func customObject(id uuid.UUID, apiName string, fields ...schema.FieldDef) *schema.ObjectDef {
+	obj := &schema.ObjectDef{ID: id, APIName: apiName, Title: apiName, PluralTitle: apiName, Fields: fields, FieldsByAPIName: make(map[string]*schema.FieldDef)}
+	for i := range obj.Fields {
+		obj.Fields[i].ObjectID = id
+		obj.FieldsByAPIName[obj.Fields[i].APIName] = &obj.Fields[i]
+	}
+	return obj
+}
+
+// nestedCache adds employees.custom_project → projects.program → programs,
+// both custom objects.
+func nestedCache() *schema.Cache {
+	base := buildCache(schema.FieldDef{ID: uuid.New(), APIName: "custom_project", Title: "Project", Type: schema.FieldLookup, LookupObjectID: new(projectObjID)})
+	projects := customObject(projectObjID, "projects",
+		schema.FieldDef{ID: uuid.New(), APIName: "status", Title: "Status", Type: schema.FieldText},
+		schema.FieldDef{ID: uuid.New(), APIName: "budget", Title: "Budget", Type: schema.FieldNumber},
+		schema.FieldDef{ID: uuid.New(), APIName: "program", Title: "Program", Type: schema.FieldLookup, LookupObjectID: new(programObjID)},
+		schema.FieldDef{ID: uuid.New(), APIName: "sponsor", Title: "Sponsor", Type: schema.FieldLookup, LookupObjectID: new(deptObjID)},
+	)
+	programs := customObject(programObjID, "programs",
+		schema.FieldDef{ID: uuid.New(), APIName: "name", Title: "Name", Type: schema.FieldText},
+	)
+	return schema.NewCacheFromObjects(base.Get("departments"), base.Get("employees"), projects, programs)
+}
+
+func TestWhereLookupChainCustomTarget(t *testing.T) {
+	cache := nestedCache()
+	cases := []struct {
+		query string
+		want  []string
+		arg   any
+	}{
+		{
+			`employees | where(.custom_project.status == "active")`,
+			[]string{`(SELECT "_sub"."data"->>'status' FROM "metadata"."records" "_sub" WHERE "_sub"."id" = ("_e"."custom_fields"->>'custom_project')::uuid AND "_sub"."object_id" = '00000000-0000-0000-0000-000000000005'::uuid) = ?`},
+			"active",
+		},
+		{
+			`employees | where(.custom_project.budget > 1000)`,
+			[]string{`(SELECT ("_sub"."data"->>'budget')::numeric FROM "metadata"."records" "_sub"`, `) > ?`},
+			"1000",
+		},
+		{
+			`employees | where(.custom_project.program.name == "Apollo")`,
+			[]string{
+				`(SELECT (SELECT "_sub2"."data"->>'name' FROM "metadata"."records" "_sub2" WHERE "_sub2"."id" = ("_sub"."data"->>'program')::uuid AND "_sub2"."object_id" = '00000000-0000-0000-0000-000000000006'::uuid) FROM "metadata"."records" "_sub"`,
+			},
+			"Apollo",
+		},
+		{
+			// Back into a standard object.
+			`employees | where(.custom_project.sponsor.title == "Engineering")`,
+			[]string{`(SELECT "_sub2"."title" FROM "core"."departments" "_sub2" WHERE "_sub2"."id" = ("_sub"."data"->>'sponsor')::uuid)`},
+			"Engineering",
+		},
+	}
+	for _, tc := range cases {
+		plan, err := compilePositions(t, cache, tc.query)
+		if err != nil {
+			t.Fatalf("%s: compile: %v", tc.query, err)
+		}
+		conds, err := pg.TranslateConditions(plan.Conditions, cache.Get("employees"), cache)
+		if err != nil {
+			t.Fatalf("%s: translate: %v", tc.query, err)
+		}
+		q, args := condToSQL(t, conds[0])
+		for _, want := range tc.want {
+			assertContains(t, q, want)
+		}
+		assertArgEquals(t, args, 0, tc.arg)
+	}
+}
+
+func TestObjectAlias(t *testing.T) {
+	base := buildCache()
+	projects := customObject(projectObjID, "initiatives",
+		schema.FieldDef{ID: uuid.New(), APIName: "status", Title: "Status", Type: schema.FieldText},
+	)
+	projects.Aliases = []schema.ObjectAlias{{Name: "projects"}}
+	cache := schema.NewCacheFromObjects(base.Get("departments"), base.Get("employees"), projects)
+
+	if cache.Get("projects") != projects || cache.Get("initiatives") != projects {
+		t.Fatal("cache does not resolve an object by its alias")
+	}
+	for _, query := range []string{`projects | where(.status == "active")`, `initiatives | where(.status == "active")`} {
+		ast, err := parser.Parse(query)
+		if err != nil {
+			t.Fatalf("parse %q: %v", query, err)
+		}
+		plan, _, err := hrql.NewCompiler(cache, "").Compile(ast)
+		if err != nil {
+			t.Fatalf("compile %q: %v", query, err)
+		}
+		if plan.Object != "initiatives" {
+			t.Errorf("%q compiled to object %q, want initiatives", query, plan.Object)
+		}
+	}
+}
+
+// --- Test: query echo ---
+
+func TestEchoParams(t *testing.T) {
+	empObj := testCache.Get("employees")
+	params, err := pg.ParseParams(empObj, pg.ParamsInput{
+		Expand:  "manager",
+		Order:   "start_date.desc",
+		Limit:   500,
+		Filters: map[string]string{"start_date": "gte.2024-01-01", "employment_type": "eq.FULL_TIME"},
+	})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	params.ExpandPlans = pg.ResolveExpands(params.Expand, empObj, testCache)
+
+	e := pg.EchoParams(empObj, params, false)
+	want := []pg.EchoCondition{
+		{Field: "employment_type", Filter: "eq.FULL_TIME"},
+		{Field: "start_date", Filter: "gte.2024-01-01"},
+	}
+	if !slices.Equal(e.Conditions, want) {
+		t.Errorf("conditions = %v, want %v", e.Conditions, want)
+	}
+	if e.Order != "start_date.desc" || e.Limit != pg.MaxLimit {
+		t.Errorf("order, limit = %q, %d; want start_date.desc, %d", e.Order, e.Limit, pg.MaxLimit)
+	}
+	if !slices.Equal(e.Expand, []string{"manager"}) {
+		t.Errorf("expand = %v, want [manager]", e.Expand)
+	}
+	if !slices.Equal(e.Redacted, []string{"national_id", "manager.national_id"}) {
+		t.Errorf("redacted = %v", e.Redacted)
+	}
+	if e := pg.EchoParams(empObj, params, true); e.Redacted != nil {
+		t.Errorf("redacted with reveal = %v, want none", e.Redacted)
+	}
+}
+
+func TestEchoConditionsNoFilterForm(t *testing.T) {
+	conds := []hrql.Condition{hrql.AndCond{
+		Left: hrql.OrCond{
+			Left:  hrql.FieldCmp{Field: []string{"employment_type"}, Op: "==", Value: "FULL_TIME"},
+			Right: hrql.FieldCmp{Field: []string{"employment_type"}, Op: "==", Value: "PART_TIME"},
+		},
+		Right: hrql.FieldCmp{Field: []string{"start_date"}, Op: ">=", Value: "2024-01-01"},
+	}}
+	got := pg.EchoConditions(conds)
+	want := []pg.EchoCondition{
+		{Kind: "OrCond"},
+		{Field: "start_date", Filter: "gte.2024-01-01"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("conditions = %v, want %v", got, want)
+	}
+}
+
+// --- Test: teams ---
+
+var (
+	teamObjID       = uuid.MustParse("00000000-0000-0000-0000-000000000007")
+	membershipObjID = uuid.MustParse("00000000-0000-0000-0000-000000000008")
+)
+
+// teamsCache is testCache plus the teams object and its memberships, which
+// look up the team and the employee.
+func teamsCache() *schema.Cache {
+	team := &schema.ObjectDef{
+		ID:              teamObjID,
+		APIName:         "teams",
+		Title:           "Team",
+		PluralTitle:     "Teams",
+		IsStandard:      true,
+		StorageSchema:   new("core"),
+		StorageTable:    new("teams"),
+		FieldsByAPIName: make(map[string]*schema.FieldDef),
+	}
+	team.Fields = []schema.FieldDef{
+		{ID: uuid.New(), APIName: "slug", Title: "Slug", Type: schema.FieldText, IsRequired: true, IsUnique: true, IsExternalID: true, IsStandard: true, StorageColumn: new("slug")},
+		{ID: uuid.New(), APIName: "title", Title: "Title", Type: schema.FieldText, IsRequired: true, IsStandard: true, StorageColumn: new("title")},
+	}
+	membership := &schema.ObjectDef{
+		ID:              membershipObjID,
+		APIName:         "team_memberships",
+		Title:           "Team Membership",
+		PluralTitle:     "Team Memberships",
+		IsStandard:      true,
+		StorageSchema:   new("core"),
+		StorageTable:    new("team_memberships"),
+		FieldsByAPIName: make(map[string]*schema.FieldDef),
+	}
+	membership.Fields = []schema.FieldDef{
+		{ID: uuid.New(), APIName: "team", Title: "Team", Type: schema.FieldLookup, IsRequired: true, IsStandard: true, StorageColumn: new("team_id"), LookupObjectID: new(teamObjID)},
+		{ID: uuid.New(), APIName: "employee", Title: "Employee", Type: schema.FieldLookup, IsRequired: true, IsStandard: true, StorageColumn: new("employee_id"), LookupObjectID: new(empObjID)},
+	}
+	for _, obj := range []*schema.ObjectDef{team, membership} {
+		for i := range obj.Fields {
+			obj.FieldsByAPIName[obj.Fields[i].APIName] = &obj.Fields[i]
+		}
+	}
+	return schema.NewCacheFromObjects(testCache.Get("departments"), testCache.Get("employees"), team, membership)
+}
+
+func TestTeams(t *testing.T) {
+	cache := teamsCache()
+	emp := cache.Get("employees")
+	listSQL := func(input string) (string, []any) {
+		t.Helper()
+		plan, err := compilePositions(t, cache, input)
+		if err != nil {
+			t.Fatalf("compile %q: %v", input, err)
+		}
+		result, err := pg.Translate(plan, emp, cache)
+		if err != nil {
+			t.Fatalf("translate %q: %v", input, err)
+		}
+		return condToSQL(t, result.Conditions[0])
+	}
+
+	sql, args := listSQL(`team("platform")`)
+	assertContains(t, sql, `EXISTS (SELECT 1 FROM "core"."team_memberships" "_tm" JOIN "core"."teams" "_t" ON "_t"."id" = "_tm"."team_id"`)
+	assertContains(t, sql, `WHERE "_tm"."employee_id" = "_e"."id" AND "_t"."slug" = ?)`)
+	assertArgEquals(t, args, 0, "platform")
+
+	sql, args = listSQL(`employees | where(member_of("platform") or member_of("data"))`)
+	assertContains(t, sql, `"_t"."slug" = ?) OR EXISTS (`)
+	assertArgEquals(t, args, 1, "data")
+
+	plan, err := compilePositions(t, cache, `team("platform") | where(.employment_type == "FULL_TIME") | count`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Kind != hrql.PlanScalar || len(plan.Conditions) != 2 {
+		t.Errorf("plan = %+v, want a count over two conditions", plan)
+	}
+}
+
+func TestTeamsErrors(t *testing.T) {
+	cache := teamsCache()
+	for input, want := range map[string]string{
+		`team(.title)`: "expected a team slug string",
+		`departments | where(member_of("platform"))`: "member_of() requires employees",
+	} {
+		if _, err := compilePositions(t, cache, input); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: error %v, want %q", input, err, want)
+		}
+	}
+
+	if _, err := compilePositions(t, testCache, `team("platform")`); err == nil || !strings.Contains(err.Error(), "teams object not found") {
+		t.Errorf("without teams: error %v", err)
+	}
+}
+
+func TestJSONField(t *testing.T) {
+	cache := buildCache(schema.FieldDef{ID: uuid.New(), APIName: "preferences__c", Title: "Preferences", Type: schema.FieldJSON})
+	emp := cache.Get("employees")
+
+	params, err := pg.ParseParams(emp, pg.ParamsInput{Filters: map[string]string{"preferences__c": "is.not_null"}})
+	if err != nil {
+		t.Fatalf("is.not_null: %v", err)
+	}
+	conds, err := pg.TranslateConditions(params.Conditions, emp, cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sql, _ := condToSQL(t, conds[0])
+	assertContains(t, sql, `"_e"."custom_fields"->>'preferences__c' IS NOT NULL`)
+
+	for _, raw := range []string{"eq.x", "like.%x%", "in.(a,b)"} {
+		_, err := pg.ParseParams(emp, pg.ParamsInput{Filters: map[string]string{"preferences__c": raw}})
+		if err == nil || !strings.Contains(err.Error(), "only supports is.null and is.not_null") {
+			t.Errorf("%s: error %v", raw, err)
+		}
+	}
+
+	for _, input := range []string{
+		`employees | where(.preferences__c == "x")`,
+		`employees | where(.preferences__c | contains("x"))`,
+		`employees | sort_by(.preferences__c)`,
+		`employees | max_by(.preferences__c)`,
+	} {
+		if _, err := compilePositions(t, cache, input); err == nil || !strings.Contains(err.Error(), "is JSON and cannot be compared") {
+			t.Errorf("%s: error %v", input, err)
+		}
+	}
+}
+
+func TestDeleteCascadeSQL(t *testing.T) {
+	cache := buildCache(schema.FieldDef{ID: uuid.New(), APIName: "mentor__c", Title: "Mentor", Type: schema.FieldLookup, LookupObjectID: new(empObjID)})
+	emp := cache.Get("employees")
+	mentor := emp.FieldsByAPIName["mentor__c"]
+
+	sql, _, err := pg.BuildReferenceCount(emp, mentor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, sql, `SELECT count(*) FROM "core"."employees" "_e" WHERE ("_e"."custom_fields"->>'mentor__c')::uuid IS NOT NULL`)
+
+	sql, args, err := pg.BuildNullifyLookup(emp, mentor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, sql, `UPDATE "core"."employees" "_e" SET "custom_fields" = "custom_fields" - $1::text WHERE ("_e"."custom_fields"->>'mentor__c')::uuid IS NOT NULL`)
+	assertArgEquals(t, args, 0, "mentor__c")
+
+	sql, _, err = pg.BuildNullifyLookup(emp, emp.FieldsByAPIName["manager"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, sql, `SET "manager_id" = $1 WHERE "_e"."manager_id" IS NOT NULL`)
+
+	if _, _, err := pg.BuildNullifyLookup(emp, emp.FieldsByAPIName["employee_number"]); err == nil {
+		t.Error("expected an error for a non-LOOKUP field")
+	}
+}
+
+func TestPickProjection(t *testing.T) {
+	plan, result, _, _ := pipeline(t, `reports(self, 1) | first | .employee_number`, selfUUID)
+	if !plan.IsProjection() {
+		t.Fatalf("kind = %v, agg = %q: want a projection", plan.Kind, plan.AggFunc)
+	}
+	assertContains(t, result.AggSQL, `SELECT "_e"."employee_number"::text FROM "core"."employees" "_e" WHERE`)
+	assertContains(t, result.AggSQL, `ORDER BY "_e"."id" ASC LIMIT 1`)
+
+	// last reverses the list's order, nulls included.
+	_, result, _, _ = pipeline(t, `employees | sort_by(.start_date) | last | .start_date`, selfUUID)
+	assertContains(t, result.AggSQL, `SELECT "_e"."start_date"::text FROM "core"."employees" "_e" ORDER BY "_e"."start_date" DESC NULLS FIRST, "_e"."id" DESC LIMIT 1`)
+	_, result, _, _ = pipeline(t, `employees | min_by(.start_date) | .employee_number`, selfUUID)
+	assertContains(t, result.AggSQL, `"_e"."start_date" IS NOT NULL`)
+	assertContains(t, result.AggSQL, `ORDER BY "_e"."start_date" ASC NULLS LAST, "_e"."id" ASC LIMIT 1`)
+	_, result, _, _ = pipeline(t, `employees | nth(3) | .employee_number`, selfUUID)
+	assertContains(t, result.AggSQL, `LIMIT 1 OFFSET 2`)
+
+	// Numbers stay numeric, so they compose with arithmetic.
+	cache := buildCache(schema.FieldDef{ID: uuid.New(), APIName: "salary", Title: "Salary", Type: schema.FieldNumber})
+	plan, err := compilePositions(t, cache, `(employees | sort_by(.salary, desc) | first | .salary) - (employees | .salary | avg)`)
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	result, err = pg.Translate(plan, cache.Get("employees"), cache)
+	if err != nil {
+		t.Fatalf("translate: %v", err)
+	}
+	assertContains(t, result.AggSQL, `SELECT ((SELECT ("_e"."custom_fields"->>'salary')::numeric FROM "core"."employees" "_e" ORDER BY`)
+
+	// A list, not a single record, still projects per record.
+	if plan, _, _, _ := pipeline(t, `employees | .employee_number`, selfUUID); plan.Kind != hrql.PlanList {
+		t.Errorf("employees | .employee_number: kind = %v, want list", plan.Kind)
+	}
+
+	for _, input := range []string{
+		`(employees | first | .employee_number) + 1`,
+		`employees | first | .employee_number | round`,
+		`employees | first | .national_id`,
+		`employees | first | .employee_number | count`,
+	} {
+		if err := pipelineErr(input, selfUUID); err == nil {
+			t.Errorf("%s: expected an error", input)
+		}
+	}
+}
+
+// --- Test: terminated employees ---
+
+func TestOrgFunctionsLeaveOutTerminated(t *testing.T) {
+	employed := func(input string, include bool) bool {
+		t.Helper()
+		ast, err := parser.Parse(input)
+		if err != nil {
+			t.Fatalf("parse %q: %v", input, err)
+		}
+		plan, _, err := hrql.NewCompiler(testCache, selfUUID).IncludeTerminated(include).Compile(ast)
+		if err != nil {
+			t.Fatalf("compile %q: %v", input, err)
+		}
+		return slices.Contains(plan.Conditions, hrql.Condition(hrql.Employed{}))
+	}
+	for input, want := range map[string]bool{
+		`reports(self)`:                           true,
+		`peers(self)`:                             true,
+		`chain(self, 1)`:                          true,
+		`network(self, 2)`:                        true,
+		`colleagues(self, .department)`:           true,
+		`reports(self, include_terminated: true)`: false,
+		`colleagues(self, .department, include_terminated: true)`: false,
+		`employees`: false,
+	} {
+		if got := employed(input, false); got != want {
+			t.Errorf("%s: employed condition = %v, want %v", input, got, want)
+		}
+	}
+	if employed(`reports(self)`, true) {
+		t.Error("reports(self) leaves out terminated employees the compiler includes")
+	}
+	if !employed(`reports(self, include_terminated: false)`, true) {
+		t.Error("include_terminated: false does not override the compiler")
+	}
+
+	_, result, _, _ := pipeline(t, `peers(self)`, selfUUID)
+	sql, _ := condToSQL(t, result.Conditions[1])
+	assertContains(t, sql, `("_e"."end_date" IS NULL OR "_e"."end_date" > CURRENT_DATE)`)
+
+	_, result, _, _ = pipeline(t, `employees | where(reports(., 1) | count > 2)`, "")
+	sql, args := condToSQL(t, result.Conditions[0])
+	assertContains(t, sql, `AND ("_sub_e"."end_date" IS NULL OR "_sub_e"."end_date" > CURRENT_DATE)) > ?`)
+	assertArgCount(t, args, 1)
+
+	if err := pipelineErr(`reports(self, include_terminated: "yes")`, selfUUID); err == nil || !strings.Contains(err.Error(), "expected true or false") {
+		t.Errorf("include_terminated: \"yes\": err = %v", err)
+	}
+}
+
+func TestEmployedAsOf(t *testing.T) {
+	hist, err := pg.AsOf(testCache.Get("employees"), time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cond, err := pg.ConditionToSQL(hrql.Employed{}, hist, testCache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sql, args := condToSQL(t, cond)
+	assertContains(t, sql, `"_e"."end_date" > ?::date`)
+	assertArgEquals(t, args, 0, "2024-03-01")
+}
+
+// --- Test: renamed field aliases ---
+
+func TestRenamedFieldAlias(t *testing.T) {
+	base := buildCache()
+	projects := customObject(projectObjID, "projects",
+		schema.FieldDef{ID: uuid.New(), APIName: "budget", Title: "Budget", Type: schema.FieldNumber, Aliases: []schema.FieldAlias{{Name: "cost"}}},
+		schema.FieldDef{ID: uuid.New(), APIName: "status", Title: "Status", Type: schema.FieldText},
+	)
+	cache := schema.NewCacheFromObjects(base.Get("departments"), base.Get("employees"), projects)
+	obj := cache.Get("projects")
+
+	// HRQL resolves the former name to the field and says so.
+	ast, err := parser.Parse(`projects | where(.cost > 100) | sort_by(.cost)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, warnings, err := hrql.NewCompiler(cache, selfUUID).Compile(ast)
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	if len(warnings) != 1 || warnings[0].Code != hrql.WarnRenamedField ||
+		warnings[0].Message != `field "cost" of projects was renamed to "budget"; use the new name` {
+		t.Errorf("warnings = %v", warnings)
+	}
+	conds, err := pg.TranslateConditions(plan.Conditions, obj, cache)
+	if err != nil {
+		t.Fatalf("translate: %v", err)
+	}
+	q, _ := condToSQL(t, conds[0])
+	assertContains(t, q, `"data"->>'budget'`)
+
+	// Registry params read it under the current name.
+	params, err := pg.ParseParams(obj, pg.ParamsInput{Select: "cost,status,budget", Order: "cost.desc", Filters: map[string]string{"cost": "gt.5"}})
+	if err != nil {
+		t.Fatalf("ParseParams: %v", err)
+	}
+	if !slices.Equal(params.Select, []string{"budget", "status"}) {
+		t.Errorf("select = %v", params.Select)
+	}
+	if params.Order.FieldAPIName != "budget" {
+		t.Errorf("order = %q", params.Order.FieldAPIName)
+	}
+	sql, _, err := pg.NewBuilder(obj).BuildList(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(sql, "cost") {
+		t.Errorf("list SQL names the alias: %s", sql)
+	}
+
+	// Writes through the former name land under the current key.
+	values := map[string]any{"cost": 5}
+	_, args, err := pg.NewBuilder(obj).BuildInsert(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, args[1].(string), `"budget":5`)
+	if _, _, err := pg.NewBuilder(obj).BuildInsert(map[string]any{"cost": 5, "budget": 6}); err == nil {
+		t.Error("expected an error setting a field under both names")
+	}
+
+	// A field cannot be created under another field's former name.
+	if err := schema.NewIdentifierPolicy(0).ValidateFieldName(obj, "cost"); err == nil {
+		t.Error("expected the alias to be taken")
+	}
+	if err := schema.NewIdentifierPolicy(0).ValidateFieldRename(obj, obj.FieldsByAPIName["budget"], "cost"); err != nil {
+		t.Errorf("renaming back to the alias: %v", err)
+	}
+}
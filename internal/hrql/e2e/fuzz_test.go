@@ -0,0 +1,127 @@
+package e2e_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/atlekbai/schema_registry/internal/hrql"
+	"github.com/atlekbai/schema_registry/internal/hrql/parser"
+	"github.com/atlekbai/schema_registry/internal/hrql/pg"
+)
+
+// fuzzCanary replaces every string literal before compiling. It must only
+// ever reach the database as a bind argument, never in SQL text.
+const fuzzCanary = `x'); DROP TABLE core.employees; -- "hrql-fuzz`
+
+// pipelineSeeds are queries from the tests above; they reach every plan kind.
+var pipelineSeeds = []string{
+	`employees`,
+	`self`,
+	`self.manager.manager | peers(.)`,
+	`chain(self.manager)`,
+	`colleagues(self, .department)`,
+	`network(self, 2)`,
+	`reports(self.manager.manager)`,
+	`reports(self, 1) | min_by(.start_date)`,
+	`reports(self, via: .manager) | count`,
+	`reports_to(self, "bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb")`,
+	`employees | where(.employment_type == "full_time" or .employment_type == "part_time")`,
+	`employees | where(.department.title == "Engineering")`,
+	`employees | where(.employment_type | starts_with("full"))`,
+	`employees | where(.employment_type | contains("full"))`,
+	`employees | where("2024-01-01" < .start_date)`,
+	`employees | where(.created_at > "2024-01-01" and .version > 1)`,
+	`employees | where(.department == self.department)`,
+	`employees | where(reports(., 1) | count > 0)`,
+	`employees | where(all(reports(., 1), .employment_type == "FULL_TIME"))`,
+	`employees | where(none(reports(.), .employment_type == "CONTRACTOR" and .end_date == "2026-01-01"))`,
+	`employees | sort_by(.start_date, desc) | first`,
+	`employees | where(.employment_type == "FULL_TIME") | sample(20)`,
+	`departments | where(employees(.) | where(.employment_type == "FULL_TIME") | count > 50)`,
+	`employees | sort_by(random) | first`,
+	`employees | sort_by(.employee_number) | max_by(.start_date) | .employee_number`,
+	`employees | where(tenure(.start_date, .end_date) > 2y)`,
+	`employees | where(.start_date | months_since >= 6)`,
+	`employees | where(.start_date >= start_of_quarter() and .end_date == this_year())`,
+	`employees | .start_date | years_since | avg`,
+	`employees | unique`,
+	`employees | .start_date | min`,
+	`employees | case(when .employment_type == "CONTRACTOR" then "c") | count`,
+	`employees | case(when .end_date == "2026-01-01" then .end_date else .start_date)`,
+	`(employees | case(when .employment_type == "CONTRACTOR" then 1 else 0) | sum) / (employees | count)`,
+	`employees | where(.employment_type == "FULL_TIME") | count | percent_of(employees | count) | round(1)`,
+	`reports(self) | count | as_of("2024-06-01") | round`,
+	`1 + (reports(self, 0) | count)`,
+}
+
+// FuzzPipeline runs Parse → Compile → Translate on arbitrary input against
+// the synthetic cache. Any stage may reject the input, but none may panic.
+// Inputs that translate are run again with every string literal replaced by
+// fuzzCanary, which must not leak into the generated SQL.
+func FuzzPipeline(f *testing.F) {
+	for _, s := range pipelineSeeds {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		ast, err := parser.Parse(input)
+		if err != nil {
+			return
+		}
+		if _, err := fuzzTranslate(ast); err != nil {
+			return
+		}
+
+		var literals int
+		parser.Walk(ast, func(n parser.Node) {
+			if lit, ok := n.(*parser.Literal); ok && lit.Kind == parser.TokString {
+				lit.Value = fuzzCanary
+				literals++
+			}
+		})
+		if literals == 0 {
+			return
+		}
+		stmts, err := fuzzTranslate(ast)
+		if err != nil {
+			return // e.g. the canary is not a valid date
+		}
+		for _, sql := range stmts {
+			if strings.Contains(sql, "hrql-fuzz") {
+				t.Fatalf("string literal interpolated into SQL for %q:\n%s", input, sql)
+			}
+		}
+	})
+}
+
+// fuzzTranslate compiles and translates ast and returns every SQL fragment
+// the plan produced.
+func fuzzTranslate(ast parser.Node) ([]string, error) {
+	plan, _, err := hrql.NewCompiler(testCache, selfUUID).Compile(ast)
+	if err != nil {
+		return nil, err
+	}
+	empObj := testCache.Get("employees")
+
+	if plan.Kind == hrql.PlanBoolean {
+		sql, _, err := pg.TranslateBooleanPlan(plan, empObj)
+		return []string{sql}, err
+	}
+
+	result, err := pg.Translate(plan, empObj, testCache)
+	if err != nil {
+		return nil, err
+	}
+	stmts := []string{result.AggSQL}
+	for _, c := range result.Conditions {
+		sql, _, err := c.ToSql()
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, sql)
+	}
+	for _, c := range result.Computed {
+		stmts = append(stmts, c.SQL)
+	}
+	return slices.DeleteFunc(stmts, func(s string) bool { return s == "" }), nil
+}
@@ -2,8 +2,12 @@ package hrql
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/atlekbai/schema_registry/internal/hrql/parser"
+	"github.com/atlekbai/schema_registry/internal/schema"
 )
 
 // SourceCall compiles a function at source position into a Plan.
@@ -13,23 +17,45 @@ type SourceCall func(c *Compiler, fn *parser.FuncCall) (*Plan, error)
 type PipeCall func(c *Compiler, plan *Plan, fn *parser.FuncCall) (*Plan, error)
 
 // SourceCalls maps function names to their source-position compilers.
-var SourceCalls = map[string]SourceCall{
-	"chain":      (*Compiler).compileChain,
-	"reports":    (*Compiler).compileReports,
-	"peers":      (*Compiler).compilePeers,
-	"colleagues": (*Compiler).compileColleagues,
-	"reports_to": (*Compiler).compileReportsTo,
-}
+var SourceCalls map[string]SourceCall
 
 // PipeCalls maps function names to their pipe-position handlers.
-var PipeCalls = map[string]PipeCall{
-	"contains":    pipeStringOpError,
-	"starts_with": pipeStringOpError,
-	"ends_with":   pipeStringOpError,
-	"unique":      pipePassthrough,
-	"upper":       pipePassthrough,
-	"lower":       pipePassthrough,
-	"length":      pipeLength,
+var PipeCalls map[string]PipeCall
+
+// The tables are filled in init because their compilers recurse back into them:
+// employee arguments may themselves be pipes (reports(chain("<uuid>") | first)),
+// and source functions may appear in pipe position (... | first | reports(.)).
+func init() {
+	SourceCalls = map[string]SourceCall{
+		"chain":      (*Compiler).compileChain,
+		"reports":    (*Compiler).compileReports,
+		"peers":      (*Compiler).compilePeers,
+		"colleagues": (*Compiler).compileColleagues,
+		"network":    (*Compiler).compileNetwork,
+		"reports_to": (*Compiler).compileReportsTo,
+		"team":       (*Compiler).compileTeam,
+		"union":      (*Compiler).compileUnion,
+	}
+
+	PipeCalls = map[string]PipeCall{
+		"contains":      pipeStringOpError,
+		"starts_with":   pipeStringOpError,
+		"ends_with":     pipeStringOpError,
+		"contains_fold": pipeStringOpError,
+		"unique":        pipeUnique,
+		"upper":         pipePassthrough,
+		"lower":         pipePassthrough,
+		"length":        pipeLength,
+		"years_since":   pipeSince,
+		"months_since":  pipeSince,
+		"days_since":    pipeSince,
+		"as_of":         pipeAsOf,
+		"sample":        pipeSample,
+		"round":         pipeScalarFunc,
+		"floor":         pipeScalarFunc,
+		"ceil":          pipeScalarFunc,
+		"percent_of":    pipeScalarFunc,
+	}
 }
 
 // --- Dispatchers ---
@@ -53,11 +79,105 @@ func (c *Compiler) applyFuncInPipe(plan *Plan, fn *parser.FuncCall) (*Plan, erro
 
 // --- Source function implementations ---
 
+// resolveVia returns the hierarchy field named by an org function's via:
+// argument, or "" for the default hierarchy (the management tree). The field
+// must be an employees LOOKUP back to employees with a path column in the
+// schema (see schema.ObjectDef.Hierarchy). via: positions selects the
+// position-based graph (ViaPositions).
+func (c *Compiler) resolveVia(fn *parser.FuncCall) (string, error) {
+	arg, ok := fn.Named["via"]
+	if !ok {
+		if c.empObj.Hierarchy("") == nil {
+			return "", fmt.Errorf("%s: object %q has no hierarchy (a lookup to itself with a path column)", fn.Name, c.empObj.APIName)
+		}
+		return "", nil
+	}
+	if ident, ok := arg.(*parser.IdentExpr); ok && ident.Name == "positions" {
+		if err := c.checkPositions(); err != nil {
+			return "", fmt.Errorf("%s via: %w", fn.Name, err)
+		}
+		return ViaPositions, nil
+	}
+	fa, ok := arg.(*parser.FieldAccess)
+	if !ok || len(fa.Chain) != 1 {
+		return "", fmt.Errorf("%s via: expected a single field (.field) or positions", fn.Name)
+	}
+	fd, ok := c.field(c.empObj, fa.Chain[0])
+	if !ok {
+		return "", fmt.Errorf("%s via: unknown field %q", fn.Name, fa.Chain[0])
+	}
+	if c.empObj.Hierarchy(fd.APIName) == nil {
+		return "", fmt.Errorf("%s via: field %q is not a hierarchy (a lookup to employees with a path column)", fn.Name, fd.APIName)
+	}
+	return fd.APIName, nil
+}
+
+// checkPositions verifies the schema has the positions object with the
+// employee and manager lookups the position-based graph follows.
+func (c *Compiler) checkPositions() error {
+	pos := c.cache.Get("positions")
+	if pos == nil {
+		return fmt.Errorf("positions object not found in schema cache")
+	}
+	for _, name := range []string{"employee", "manager"} {
+		fd := pos.FieldsByAPIName[name]
+		if fd == nil || fd.Type != schema.FieldLookup || fd.LookupObjectID == nil || *fd.LookupObjectID != c.empObj.ID {
+			return fmt.Errorf("positions.%s is not a lookup to employees", name)
+		}
+	}
+	return nil
+}
+
+// requireTree rejects via: positions for org functions that rely on the
+// paths of a tree, which the position graph does not have.
+func requireTree(fn *parser.FuncCall, via string) error {
+	if via == ViaPositions {
+		return fmt.Errorf("%s via: positions is only supported by chain, reports and reports_to queries", fn.Name)
+	}
+	return nil
+}
+
+// excludeTerminated reports whether org function fn leaves out employees who
+// have left: unless the compiler includes them, or fn's include_terminated:
+// argument says otherwise. Schemas without EndDateField have no such employees.
+func (c *Compiler) excludeTerminated(fn *parser.FuncCall) (bool, error) {
+	if fd := c.empObj.FieldsByAPIName[EndDateField]; fd == nil || (fd.Type != schema.FieldDate && fd.Type != schema.FieldDatetime) {
+		return false, nil
+	}
+	arg, ok := fn.Named["include_terminated"]
+	if !ok {
+		return !c.includeTerminated, nil
+	}
+	lit, ok := arg.(*parser.Literal)
+	if !ok || (lit.Kind != parser.TokTrue && lit.Kind != parser.TokFalse) {
+		return false, fmt.Errorf("%s include_terminated: expected true or false", fn.Name)
+	}
+	return lit.Kind == parser.TokFalse, nil
+}
+
+// orgList builds the list plan of org function fn selecting cond, leaving
+// out employees who have left unless they are included.
+func (c *Compiler) orgList(fn *parser.FuncCall, cond Condition) (*Plan, error) {
+	exclude, err := c.excludeTerminated(fn)
+	if err != nil {
+		return nil, err
+	}
+	plan := &Plan{Kind: PlanList, Conditions: []Condition{cond}}
+	if exclude {
+		plan.Conditions = append(plan.Conditions, Employed{})
+	}
+	return plan, nil
+}
+
 func (c *Compiler) compileChain(fn *parser.FuncCall) (*Plan, error) {
 	ref, err := c.resolveEmployeeArg(fn.Args[0])
 	if err != nil {
 		return nil, fmt.Errorf("chain arg 1: %w", err)
 	}
+	via, err := c.resolveVia(fn)
+	if err != nil {
+		return nil, err
+	}
 
 	depth := 0
 	if len(fn.Args) == 2 {
@@ -69,12 +189,12 @@ func (c *Compiler) compileChain(fn *parser.FuncCall) (*Plan, error) {
 
 	var cond Condition
 	if depth == 0 {
-		cond = OrgChainAll{Emp: ref}
+		cond = OrgChainAll{Emp: ref, Via: via}
 	} else {
-		cond = OrgChainUp{Emp: ref, Steps: depth}
+		cond = OrgChainUp{Emp: ref, Steps: depth, Via: via}
 	}
 
-	return &Plan{Kind: PlanList, Conditions: []Condition{cond}}, nil
+	return c.orgList(fn, cond)
 }
 
 func (c *Compiler) compileReports(fn *parser.FuncCall) (*Plan, error) {
@@ -82,6 +202,10 @@ func (c *Compiler) compileReports(fn *parser.FuncCall) (*Plan, error) {
 	if err != nil {
 		return nil, fmt.Errorf("reports arg 1: %w", err)
 	}
+	via, err := c.resolveVia(fn)
+	if err != nil {
+		return nil, err
+	}
 
 	depth := 0
 	if len(fn.Args) == 2 {
@@ -93,12 +217,12 @@ func (c *Compiler) compileReports(fn *parser.FuncCall) (*Plan, error) {
 
 	var cond Condition
 	if depth == 0 {
-		cond = OrgSubtree{Emp: ref}
+		cond = OrgSubtree{Emp: ref, Via: via}
 	} else {
-		cond = OrgChainDown{Emp: ref, Depth: depth}
+		cond = OrgChainDown{Emp: ref, Depth: depth, Via: via}
 	}
 
-	return &Plan{Kind: PlanList, Conditions: []Condition{cond}}, nil
+	return c.orgList(fn, cond)
 }
 
 func (c *Compiler) compilePeers(fn *parser.FuncCall) (*Plan, error) {
@@ -106,11 +230,18 @@ func (c *Compiler) compilePeers(fn *parser.FuncCall) (*Plan, error) {
 	if err != nil {
 		return nil, fmt.Errorf("peers arg 1: %w", err)
 	}
+	via, err := c.resolveVia(fn)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireTree(fn, via); err != nil {
+		return nil, err
+	}
+	if via == "" {
+		via = "manager"
+	}
 
-	return &Plan{
-		Kind:       PlanList,
-		Conditions: []Condition{SameFieldCond{Field: "manager", Emp: ref}},
-	}, nil
+	return c.orgList(fn, SameFieldCond{Field: via, Emp: ref})
 }
 
 func (c *Compiler) compileColleagues(fn *parser.FuncCall) (*Plan, error) {
@@ -128,14 +259,45 @@ func (c *Compiler) compileColleagues(fn *parser.FuncCall) (*Plan, error) {
 	}
 
 	fieldName := fa.Chain[0]
-	if _, ok := c.empObj.FieldsByAPIName[fieldName]; !ok {
+	fd, ok := c.field(c.empObj, fieldName)
+	if !ok {
 		return nil, fmt.Errorf("colleagues arg 2: unknown field %q", fieldName)
 	}
+	if err := checkComparable(fd); err != nil {
+		return nil, fmt.Errorf("colleagues arg 2: %w", err)
+	}
+	if err := fd.CheckFilterable(); err != nil {
+		return nil, fmt.Errorf("colleagues arg 2: %w", err)
+	}
 
-	return &Plan{
-		Kind:       PlanList,
-		Conditions: []Condition{SameFieldCond{Field: fieldName, Emp: ref}},
-	}, nil
+	return c.orgList(fn, SameFieldCond{Field: fieldName, Emp: ref})
+}
+
+// maxNetworkDegree bounds network() so the generated predicate stays small.
+const maxNetworkDegree = 6
+
+func (c *Compiler) compileNetwork(fn *parser.FuncCall) (*Plan, error) {
+	ref, err := c.resolveEmployeeArg(fn.Args[0])
+	if err != nil {
+		return nil, fmt.Errorf("network arg 1: %w", err)
+	}
+
+	degree, err := c.resolveIntArg(fn.Args[1])
+	if err != nil {
+		return nil, fmt.Errorf("network arg 2: %w", err)
+	}
+	if degree < 1 || degree > maxNetworkDegree {
+		return nil, fmt.Errorf("network arg 2: degree must be between 1 and %d, got %d", maxNetworkDegree, degree)
+	}
+	via, err := c.resolveVia(fn)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireTree(fn, via); err != nil {
+		return nil, err
+	}
+
+	return c.orgList(fn, OrgNetwork{Emp: ref, Degree: degree, Via: via})
 }
 
 func (c *Compiler) compileReportsTo(fn *parser.FuncCall) (*Plan, error) {
@@ -148,20 +310,78 @@ func (c *Compiler) compileReportsTo(fn *parser.FuncCall) (*Plan, error) {
 	if err != nil {
 		return nil, fmt.Errorf("reports_to arg 2: %w", err)
 	}
+	via, err := c.resolveVia(fn)
+	if err != nil {
+		return nil, err
+	}
 
 	return &Plan{
 		Kind:          PlanBoolean,
-		BoolCondition: ReportsToCheck{Emp: empRef, Target: tgtRef},
+		BoolCondition: ReportsToCheck{Emp: empRef, Target: tgtRef, Via: via},
 	}, nil
 }
 
+// compileTeam compiles team("slug"): the employees on the team.
+func (c *Compiler) compileTeam(fn *parser.FuncCall) (*Plan, error) {
+	cond, err := c.resolveTeam(fn)
+	if err != nil {
+		return nil, err
+	}
+	return &Plan{Kind: PlanList, Conditions: []Condition{cond}}, nil
+}
+
+// resolveTeam builds the TeamMember condition of team("slug") or
+// member_of("slug"). The slug is not looked up: an unknown team has no members.
+func (c *Compiler) resolveTeam(fn *parser.FuncCall) (Condition, error) {
+	lit, ok := fn.Args[0].(*parser.Literal)
+	if !ok || lit.Kind != parser.TokString {
+		return nil, fmt.Errorf("%s: expected a team slug string, got %T", fn.Name, fn.Args[0])
+	}
+	if err := c.checkTeams(); err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name, err)
+	}
+	return TeamMember{Team: lit.Value}, nil
+}
+
+// checkTeams verifies the schema has the teams object and the memberships
+// joining it to employees.
+func (c *Compiler) checkTeams() error {
+	if c.cache.Get("teams") == nil {
+		return fmt.Errorf("teams object not found in schema cache")
+	}
+	m := c.cache.Get("team_memberships")
+	if m == nil {
+		return fmt.Errorf("team_memberships object not found in schema cache")
+	}
+	fd := m.FieldsByAPIName["employee"]
+	if fd == nil || fd.Type != schema.FieldLookup || fd.LookupObjectID == nil || *fd.LookupObjectID != c.empObj.ID {
+		return fmt.Errorf("team_memberships.employee is not a lookup to employees")
+	}
+	return nil
+}
+
 // --- Pipe function implementations ---
 
 func pipeStringOpError(_ *Compiler, _ *Plan, fn *parser.FuncCall) (*Plan, error) {
 	return nil, fmt.Errorf("%s() is only supported inside where() conditions", fn.Name)
 }
 
-func pipePassthrough(_ *Compiler, plan *Plan, _ *parser.FuncCall) (*Plan, error) {
+// pipePassthrough accepts unique, upper and lower, which HRQL does not
+// implement: records are already distinct and values are not transformed.
+func pipePassthrough(c *Compiler, plan *Plan, fn *parser.FuncCall) (*Plan, error) {
+	c.warn(WarnNoOp, "%s() has no effect and was ignored", fn.Name)
+	return plan, nil
+}
+
+// pipeUnique compiles unique after a field access: the aggregate that follows
+// takes each value of the field once, so reports(self) | .department |
+// unique | count is the number of departments the reports span. Records are
+// already distinct, so on a list of records it has no effect.
+func pipeUnique(c *Compiler, plan *Plan, fn *parser.FuncCall) (*Plan, error) {
+	if plan.Kind != PlanList || plan.AggField == "" {
+		return pipePassthrough(c, plan, fn)
+	}
+	plan.Distinct = true
 	return plan, nil
 }
 
@@ -170,3 +390,151 @@ func pipeLength(_ *Compiler, plan *Plan, _ *parser.FuncCall) (*Plan, error) {
 	plan.AggFunc = "count"
 	return plan, nil
 }
+
+// pipeSince projects the date field selected by the previous step as the
+// whole years, months or days elapsed since it: employees | .start_date |
+// years_since, optionally followed by an aggregation such as avg.
+func pipeSince(c *Compiler, plan *Plan, fn *parser.FuncCall) (*Plan, error) {
+	if plan.Kind != PlanList || plan.AggField == "" {
+		return nil, fmt.Errorf("%s requires a date field, e.g. employees | .start_date | %s", fn.Name, fn.Name)
+	}
+	if err := c.checkDateField(fn.Name, plan.AggField); err != nil {
+		return nil, err
+	}
+	plan.Since = sinceUnit(fn.Name)
+	return plan, nil
+}
+
+// sinceUnit returns the interval unit of years_since, months_since or days_since.
+func sinceUnit(name string) string {
+	unit, _, _ := strings.Cut(name, "_")
+	return unit
+}
+
+// checkDateField rejects fields that are not DATE or DATETIME.
+func (c *Compiler) checkDateField(fnName, field string) error {
+	fd, ok := c.field(c.obj, field)
+	if !ok {
+		return fmt.Errorf("%s: unknown field %q", fnName, field)
+	}
+	if fd.Type != schema.FieldDate && fd.Type != schema.FieldDatetime {
+		return fmt.Errorf("%s: field %q is %s, expected DATE or DATETIME", fnName, field, fd.Type)
+	}
+	return checkQueryable(fd)
+}
+
+// DurationInterval converts a duration literal (2y, 6mo, 3w, 90d) to a
+// Postgres interval such as "2 years".
+func DurationInterval(lit string) (string, error) {
+	i := strings.IndexFunc(lit, func(r rune) bool { return r != '.' && (r < '0' || r > '9') })
+	if i <= 0 {
+		return "", fmt.Errorf("invalid duration %q", lit)
+	}
+	unit, ok := parser.DurationUnits[lit[i:]]
+	if !ok {
+		return "", fmt.Errorf("invalid duration %q, expected a unit of y, mo, w or d", lit)
+	}
+	return lit[:i] + " " + unit, nil
+}
+
+// pipeSample draws n records at random from the list built so far:
+// employees | where(.employment_type == "FULL_TIME") | sample(100).
+func pipeSample(c *Compiler, plan *Plan, fn *parser.FuncCall) (*Plan, error) {
+	if plan.Kind != PlanList {
+		return nil, fmt.Errorf("sample requires a list source")
+	}
+	if plan.OrderBy != nil || plan.Limit > 0 {
+		return nil, fmt.Errorf("sample cannot follow sort_by, first, last, nth or a single record")
+	}
+	n, err := c.resolveIntArg(fn.Args[0])
+	if err != nil {
+		return nil, fmt.Errorf("sample arg 1: %w", err)
+	}
+	if n < 1 {
+		return nil, fmt.Errorf("sample arg 1: expected a positive count, got %d", n)
+	}
+	plan.OrderBy = &OrderBy{Random: true}
+	plan.Limit = n
+	plan.Sample = n
+	return plan, nil
+}
+
+// pipeAsOf marks the whole plan for evaluation against historical state.
+// It may appear anywhere after the source, including after an aggregation.
+func pipeAsOf(_ *Compiler, plan *Plan, fn *parser.FuncCall) (*Plan, error) {
+	lit, ok := fn.Args[0].(*parser.Literal)
+	if !ok || lit.Kind != parser.TokString {
+		return nil, fmt.Errorf("as_of: expected date string, got %T", fn.Args[0])
+	}
+	if plan.AsOf != nil {
+		return nil, fmt.Errorf("as_of may only appear once")
+	}
+	ts, err := ParseAsOf(lit.Value)
+	if err != nil {
+		return nil, fmt.Errorf("as_of: %w", err)
+	}
+	plan.AsOf = &ts
+	return plan, nil
+}
+
+// maxRoundDecimals bounds round(n) in both directions; negative n rounds to
+// tens, hundreds, ...
+const maxRoundDecimals = 10
+
+// pipeScalarFunc applies round, floor, ceil or percent_of to a scalar result:
+// employees | where(...) | count | percent_of(employees | count) | round(1).
+func pipeScalarFunc(c *Compiler, plan *Plan, fn *parser.FuncCall) (*Plan, error) {
+	if plan.Kind != PlanScalar {
+		return nil, fmt.Errorf("%s requires a scalar (e.g. after count or avg)", fn.Name)
+	}
+	if err := c.checkNumericProjection(plan, fn.Name); err != nil {
+		return nil, err
+	}
+	value := plan.ScalarExpr
+	if value == nil {
+		inner := *plan
+		value = ScalarSubquery{Plan: &inner}
+	}
+
+	args := []ScalarExpr{value}
+	switch fn.Name {
+	case "round":
+		decimals := 0
+		if len(fn.Args) == 1 {
+			var err error
+			decimals, err = c.resolveIntArg(fn.Args[0])
+			if err != nil {
+				return nil, fmt.Errorf("round arg 1: %w", err)
+			}
+			if decimals > maxRoundDecimals || decimals < -maxRoundDecimals {
+				return nil, fmt.Errorf("round arg 1: decimals must be between %d and %d, got %d", -maxRoundDecimals, maxRoundDecimals, decimals)
+			}
+		}
+		args = append(args, ScalarLiteral{Value: strconv.Itoa(decimals)})
+	case "percent_of":
+		total, err := c.compileScalarExpr(fn.Args[0])
+		if err != nil {
+			return nil, fmt.Errorf("percent_of arg 1: %w", err)
+		}
+		args = append(args, total)
+	}
+
+	return &Plan{
+		Kind:       PlanScalar,
+		ScalarExpr: ScalarFunc{Name: fn.Name, Args: args},
+		AsOf:       plan.AsOf,
+	}, nil
+}
+
+// ParseAsOf parses a point-in-time as either a date (2006-01-02, midnight UTC)
+// or an RFC 3339 timestamp.
+func ParseAsOf(s string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD or RFC 3339", s)
+	}
+	return t, nil
+}
@@ -1,6 +1,8 @@
 package parser
 
-// Node is the interface all AST nodes implement.
+// Node is the interface all AST nodes implement. Every node records the byte
+// offset of the token that introduces it in Pos: its first token, or the
+// operator for BinaryOp and InExpr.
 type Node interface {
 	node() // marker method
 }
@@ -9,34 +11,53 @@ type Node interface {
 // Steps[0] is the source, Steps[1:] are pipe operations.
 type PipeExpr struct {
 	Steps []Node
+	Pos   int // start of the first step
 }
 
 // FieldAccess represents dot-notation field access: .field or .field.subfield
 type FieldAccess struct {
 	Chain []string // e.g. ["department", "title"]
+	Pos   int      // the leading dot
 }
 
 // SelfExpr represents the `self` pronoun.
-type SelfExpr struct{}
+type SelfExpr struct {
+	Pos int
+}
 
 // DotExpr represents the `.` pronoun (current pipe item).
-type DotExpr struct{}
+type DotExpr struct {
+	Pos int
+}
 
 // IdentExpr represents a bare identifier like `employees`.
 type IdentExpr struct {
 	Name string
+	Pos  int
 }
 
-// FuncCall represents a function call: name(arg1, arg2, ...)
+// FuncCall represents a function call: name(arg1, arg2, ..., key: value)
 type FuncCall struct {
-	Func *FuncDef // set by parser from function registry; nil for unknown
-	Name string
-	Args []Node
+	Func  *FuncDef // set by parser from function registry; nil for unknown
+	Name  string
+	Args  []Node
+	Named map[string]Node // named arguments; nil when there are none
+	Pos   int             // the function name
+}
+
+// RelationExpr represents the records of another object whose LOOKUP field
+// points at the current record: employees(.) inside a departments query, or
+// employees(., via: .cost_center) when several fields could.
+type RelationExpr struct {
+	Object string
+	Via    *FieldAccess // nil when omitted
+	Pos    int          // the object name
 }
 
 // WhereExpr represents where(condition).
 type WhereExpr struct {
 	Cond Node
+	Pos  int
 }
 
 // BinaryOp represents a binary operation: left op right.
@@ -44,46 +65,199 @@ type BinaryOp struct {
 	Op    string // "==", "!=", ">", ">=", "<", "<=", "and", "or", "+", "-", "*", "/"
 	Left  Node
 	Right Node
+	Pos   int // byte offset of the operator in the query, for type errors
+}
+
+// InExpr represents membership: left in (a, b, ...) or left in source.
+// A parenthesized list sets Values, anything else sets Source; a single
+// parenthesized list expression, (employees | where(...)), is also a source
+// to the compiler.
+type InExpr struct {
+	Left   Node
+	Values []Node
+	Source Node
+	Pos    int // byte offset of the in keyword
 }
 
 // UnaryMinus represents negation: -expr.
 type UnaryMinus struct {
 	Expr Node
+	Pos  int
 }
 
 // Literal represents a string, number, or boolean literal.
 type Literal struct {
-	Kind  TokenKind // TokString, TokNumber, TokTrue, TokFalse
+	Kind  TokenKind // TokString, TokNumber, TokDuration, TokTrue, TokFalse
 	Value string
+	Pos   int
 }
 
-// SortExpr represents sort_by(.field, asc/desc).
+// SortExpr represents sort_by(.field, asc/desc, nulls_first/nulls_last) or
+// sort_by(random).
 type SortExpr struct {
-	Field *FieldAccess
-	Desc  bool
+	Field  *FieldAccess // nil when Random
+	Desc   bool
+	Nulls  string // "first", "last", or "" when not given
+	Random bool
+	Pos    int
 }
 
-// PickExpr represents first, last, or nth(n).
+// PickExpr represents first, last, nth(n), min_by(.field), or max_by(.field).
 type PickExpr struct {
-	Op string // "first", "last", "nth"
-	N  int    // 1-indexed, only meaningful for "nth"
+	Op    string       // "first", "last", "nth", "min_by", "max_by"
+	N     int          // 1-indexed, only meaningful for "nth"
+	Field *FieldAccess // only set for "min_by" and "max_by"
+	Pos   int
+}
+
+// BucketExpr represents bucket(.field, [b1, b2, ...]): the number of records
+// whose value falls below b1, in each [b(i), b(i+1)) range, and at or above
+// the last bound. Value is nil in bucket([b1, ...]), which buckets the value
+// the previous steps selected (employees | .start_date | years_since | ...).
+type BucketExpr struct {
+	Value  Node     // .field or .date_field | years_since; nil for the piped value
+	Bounds []string // number literals as written, "-"-prefixed when negative
+	Pos    int
 }
 
 // AggExpr represents count, sum, avg, min, or max.
 type AggExpr struct {
-	Op string // "count", "sum", "avg", "min", "max"
-}
-
-func (*PipeExpr) node()    {}
-func (*FieldAccess) node() {}
-func (*SelfExpr) node()    {}
-func (*DotExpr) node()     {}
-func (*IdentExpr) node()   {}
-func (*FuncCall) node()    {}
-func (*WhereExpr) node()   {}
-func (*BinaryOp) node()    {}
-func (*UnaryMinus) node()  {}
-func (*Literal) node()     {}
-func (*SortExpr) node()    {}
-func (*PickExpr) node()    {}
-func (*AggExpr) node()     {}
+	Op  string // "count", "sum", "avg", "min", "max"
+	Pos int
+}
+
+// IDsExpr represents ids: the IDs of the records in the list, without the
+// records themselves.
+type IDsExpr struct {
+	Pos int
+}
+
+// CaseExpr represents case(when cond then value ... [else value]).
+type CaseExpr struct {
+	Whens []CaseWhen
+	Else  Node // nil when omitted
+	Pos   int
+}
+
+// CaseWhen is one `when cond then value` branch of a CaseExpr.
+type CaseWhen struct {
+	Cond Node
+	Then Node
+}
+
+func (*PipeExpr) node()     {}
+func (*FieldAccess) node()  {}
+func (*SelfExpr) node()     {}
+func (*DotExpr) node()      {}
+func (*IdentExpr) node()    {}
+func (*FuncCall) node()     {}
+func (*RelationExpr) node() {}
+func (*WhereExpr) node()    {}
+func (*BinaryOp) node()     {}
+func (*InExpr) node()       {}
+func (*UnaryMinus) node()   {}
+func (*Literal) node()      {}
+func (*SortExpr) node()     {}
+func (*PickExpr) node()     {}
+func (*BucketExpr) node()   {}
+func (*AggExpr) node()      {}
+func (*IDsExpr) node()      {}
+func (*CaseExpr) node()     {}
+
+// Walk calls fn for node and every node beneath it, depth-first.
+func Walk(node Node, fn func(Node)) {
+	if node == nil {
+		return
+	}
+	fn(node)
+	switch n := node.(type) {
+	case *PipeExpr:
+		for _, step := range n.Steps {
+			Walk(step, fn)
+		}
+	case *FuncCall:
+		for _, arg := range n.Args {
+			Walk(arg, fn)
+		}
+		for _, arg := range n.Named {
+			Walk(arg, fn)
+		}
+	case *RelationExpr:
+		if n.Via != nil {
+			Walk(n.Via, fn)
+		}
+	case *WhereExpr:
+		Walk(n.Cond, fn)
+	case *BinaryOp:
+		Walk(n.Left, fn)
+		Walk(n.Right, fn)
+	case *InExpr:
+		Walk(n.Left, fn)
+		for _, v := range n.Values {
+			Walk(v, fn)
+		}
+		Walk(n.Source, fn)
+	case *UnaryMinus:
+		Walk(n.Expr, fn)
+	case *SortExpr:
+		if n.Field != nil {
+			Walk(n.Field, fn)
+		}
+	case *PickExpr:
+		if n.Field != nil {
+			Walk(n.Field, fn)
+		}
+	case *BucketExpr:
+		Walk(n.Value, fn)
+	case *CaseExpr:
+		for _, w := range n.Whens {
+			Walk(w.Cond, fn)
+			Walk(w.Then, fn)
+		}
+		Walk(n.Else, fn)
+	}
+}
+
+// start returns the offset where node begins: its Pos, or for operators the
+// start of their left operand.
+func start(node Node) int {
+	switch n := node.(type) {
+	case *BinaryOp:
+		return start(n.Left)
+	case *InExpr:
+		return start(n.Left)
+	case *PipeExpr:
+		return n.Pos
+	case *FieldAccess:
+		return n.Pos
+	case *SelfExpr:
+		return n.Pos
+	case *DotExpr:
+		return n.Pos
+	case *IdentExpr:
+		return n.Pos
+	case *FuncCall:
+		return n.Pos
+	case *RelationExpr:
+		return n.Pos
+	case *WhereExpr:
+		return n.Pos
+	case *UnaryMinus:
+		return n.Pos
+	case *Literal:
+		return n.Pos
+	case *SortExpr:
+		return n.Pos
+	case *PickExpr:
+		return n.Pos
+	case *BucketExpr:
+		return n.Pos
+	case *AggExpr:
+		return n.Pos
+	case *IDsExpr:
+		return n.Pos
+	case *CaseExpr:
+		return n.Pos
+	}
+	return 0
+}
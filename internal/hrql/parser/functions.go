@@ -19,6 +19,8 @@ const (
 	ArgField                   // .field access
 	ArgString                  // string literal
 	ArgAny                     // unconstrained
+	ArgPredicate               // boolean condition evaluated per item, as in where()
+	ArgBool                    // true or false
 )
 
 // FuncDef describes a registered HRQL call-style function.
@@ -27,33 +29,92 @@ type FuncDef struct {
 	ArgTypes   []ArgKind
 	Variadic   int       // 0=fixed, N=N optional trailing args
 	ReturnKind ValueKind
+	// Named lists the optional named arguments (name: value) accepted after
+	// the positional ones.
+	Named map[string]ArgKind
 }
 
+// hierarchyArgs selects the hierarchy an org function traverses:
+// reports(self, via: .dotted_manager).
+var hierarchyArgs = map[string]ArgKind{"via": ArgField}
+
+// orgListArgs are the named arguments of org functions listing employees:
+// via:, and include_terminated: to keep employees who have left
+// (reports(self, include_terminated: true)).
+var orgListArgs = map[string]ArgKind{"via": ArgField, "include_terminated": ArgBool}
+
 // Functions is the canonical registry of all HRQL call-style functions.
 // Aggregation operators (count, sum, avg, min, max) and special-syntax forms
 // (where, sort_by, first, last, nth) are NOT included — they have dedicated AST nodes.
 var Functions = map[string]*FuncDef{
 	// Org-tree traversal
-	"chain":   {Name: "chain", ArgTypes: []ArgKind{ArgEmployee, ArgInt}, Variadic: 1, ReturnKind: KindList},
-	"reports": {Name: "reports", ArgTypes: []ArgKind{ArgEmployee, ArgInt}, Variadic: 1, ReturnKind: KindList},
-	"peers":   {Name: "peers", ArgTypes: []ArgKind{ArgEmployee}, ReturnKind: KindList},
-	"colleagues": {Name: "colleagues", ArgTypes: []ArgKind{ArgEmployee, ArgField}, ReturnKind: KindList},
+	"chain":   {Name: "chain", ArgTypes: []ArgKind{ArgEmployee, ArgInt}, Variadic: 1, ReturnKind: KindList, Named: orgListArgs},
+	"reports": {Name: "reports", ArgTypes: []ArgKind{ArgEmployee, ArgInt}, Variadic: 1, ReturnKind: KindList, Named: orgListArgs},
+	"peers":   {Name: "peers", ArgTypes: []ArgKind{ArgEmployee}, ReturnKind: KindList, Named: orgListArgs},
+	"colleagues": {Name: "colleagues", ArgTypes: []ArgKind{ArgEmployee, ArgField}, ReturnKind: KindList, Named: map[string]ArgKind{"include_terminated": ArgBool}},
+	"network":    {Name: "network", ArgTypes: []ArgKind{ArgEmployee, ArgInt}, ReturnKind: KindList, Named: orgListArgs},
 
 	// Boolean predicate
-	"reports_to": {Name: "reports_to", ArgTypes: []ArgKind{ArgAny, ArgEmployee}, ReturnKind: KindBoolean},
+	"reports_to": {Name: "reports_to", ArgTypes: []ArgKind{ArgAny, ArgEmployee}, ReturnKind: KindBoolean, Named: hierarchyArgs},
+
+	// Team membership: team("platform") lists members, member_of("platform")
+	// tests one inside where
+	"team":      {Name: "team", ArgTypes: []ArgKind{ArgString}, ReturnKind: KindList},
+	"member_of": {Name: "member_of", ArgTypes: []ArgKind{ArgString}, ReturnKind: KindBoolean},
+
+	// Quantifiers over an org list (inside where)
+	"all":  {Name: "all", ArgTypes: []ArgKind{ArgAny, ArgPredicate}, ReturnKind: KindBoolean},
+	"none": {Name: "none", ArgTypes: []ArgKind{ArgAny, ArgPredicate}, ReturnKind: KindBoolean},
 
 	// String operations
 	"contains":    {Name: "contains", ArgTypes: []ArgKind{ArgString}, ReturnKind: KindBoolean},
 	"starts_with": {Name: "starts_with", ArgTypes: []ArgKind{ArgString}, ReturnKind: KindBoolean},
 	"ends_with":   {Name: "ends_with", ArgTypes: []ArgKind{ArgString}, ReturnKind: KindBoolean},
 
+	// contains, ignoring accents as well as case
+	"contains_fold": {Name: "contains_fold", ArgTypes: []ArgKind{ArgString}, ReturnKind: KindBoolean},
+
 	// Transforms (zero-arg, used without parens in pipe position)
 	"unique": {Name: "unique", ReturnKind: KindTransform},
 	"upper":  {Name: "upper", ReturnKind: KindTransform},
 	"lower":  {Name: "lower", ReturnKind: KindTransform},
 
+	// Time travel (pipe modifier, applies to the whole query)
+	"as_of": {Name: "as_of", ArgTypes: []ArgKind{ArgString}, ReturnKind: KindTransform},
+
+	// Random subsets (pipe position, on a list)
+	"sample": {Name: "sample", ArgTypes: []ArgKind{ArgInt}, ReturnKind: KindTransform},
+
 	// Scalar (zero-arg)
 	"length": {Name: "length", ReturnKind: KindScalar},
+
+	// Durations from date fields: tenure(.start_date) > 2y in where, or
+	// .start_date | years_since projected per record.
+	"tenure":       {Name: "tenure", ArgTypes: []ArgKind{ArgField, ArgField}, Variadic: 1, ReturnKind: KindScalar},
+	"years_since":  {Name: "years_since", ReturnKind: KindTransform},
+	"months_since": {Name: "months_since", ReturnKind: KindTransform},
+	"days_since":   {Name: "days_since", ReturnKind: KindTransform},
+
+	// Calendar buckets (where value position), resolved server-side in the
+	// request time zone: .start_date >= start_of_month(), .hired == this_year()
+	"start_of_month":   {Name: "start_of_month", ReturnKind: KindScalar},
+	"start_of_quarter": {Name: "start_of_quarter", ReturnKind: KindScalar},
+	"start_of_year":    {Name: "start_of_year", ReturnKind: KindScalar},
+	"end_of_month":     {Name: "end_of_month", ReturnKind: KindScalar},
+	"end_of_quarter":   {Name: "end_of_quarter", ReturnKind: KindScalar},
+	"end_of_year":      {Name: "end_of_year", ReturnKind: KindScalar},
+	"this_month":       {Name: "this_month", ReturnKind: KindScalar},
+	"this_quarter":     {Name: "this_quarter", ReturnKind: KindScalar},
+	"this_year":        {Name: "this_year", ReturnKind: KindScalar},
+
+	// Scalar formatting (pipe position, after an aggregate)
+	"round":      {Name: "round", ArgTypes: []ArgKind{ArgInt}, Variadic: 1, ReturnKind: KindScalar},
+	"floor":      {Name: "floor", ReturnKind: KindScalar},
+	"ceil":       {Name: "ceil", ReturnKind: KindScalar},
+	"percent_of": {Name: "percent_of", ArgTypes: []ArgKind{ArgAny}, ReturnKind: KindScalar},
+
+	// Multi-object lists: union(employees | where(...), contractors), 2 to 4 sources
+	"union": {Name: "union", ArgTypes: []ArgKind{ArgAny, ArgAny, ArgAny, ArgAny}, Variadic: 2, ReturnKind: KindList},
 }
 
 // GetFunction returns the FuncDef for name and whether it was found.
@@ -0,0 +1,91 @@
+package parser
+
+import (
+	"testing"
+)
+
+// fuzzSeeds covers every token kind and the grammar exercised by the parser
+// tests; the e2e package seeds the full pipeline separately.
+var fuzzSeeds = []string{
+	`employees`,
+	`self`,
+	`self.manager.manager`,
+	`"hello \"world\""`,
+	`'O"Brien'`,
+	`r"O'Brien \ 100%"`,
+	`42.5`,
+	`-(1 + 2) * 3 / 4`,
+	`true and false or true`,
+	`employees | where(.employment_type == "FULL_TIME" and .start_date >= "2024-01-01") | count`,
+	`employees | where(.department.title | contains("Eng"))`,
+	`employees | sort_by(.start_date, desc) | first`,
+	`employees | where(.employment_type == "FULL_TIME") | sample(20)`,
+	`departments | where(employees(.) | where(.employment_type == "FULL_TIME") | count > 50)`,
+	`employees | sort_by(random) | first`,
+	`employees | nth(3) | .employee_number`,
+	`employees | max_by(.start_date)`,
+	`chain(self.manager, 2)`,
+	`reports(self, 1) | where(reports(.) | count > 0)`,
+	`reports(self, via: .mentor) | count`,
+	`peers(self) | unique`,
+	`colleagues(self, .department)`,
+	`reports_to(self, "bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb")`,
+	`employees | case(when .employment_type == "CONTRACTOR" then 1 else 0) | sum`,
+	`employees | where(all(reports(., 1), .employment_type != "INTERN"))`,
+	`employees | where(.employment_type == "FULL_TIME") | count | percent_of(employees | count) | round(1)`,
+	`reports(self) | count | as_of("2024-06-01")`,
+	`employees | where(tenure(.start_date, .end_date) >= 1.5y and .start_date | days_since < 90)`,
+	`6mo`,
+	"employees // trailing comment\n| count",
+	`employees | where(`,
+	`"unterminated`,
+	`employees | nth(0)`,
+	`reports(via: .manager, self)`,
+}
+
+// FuzzLexer checks the lexer never panics, always makes progress and ends
+// with EOF or an error.
+func FuzzLexer(f *testing.F) {
+	for _, s := range fuzzSeeds {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		lex := NewLexer(input)
+		last := -1
+		for range len(input) + 2 {
+			tok, err := lex.Next()
+			if err != nil {
+				return
+			}
+			if tok.Kind == TokEOF {
+				return
+			}
+			if tok.Pos <= last || tok.Pos >= len(input) {
+				t.Fatalf("token %v at offset %d after %d in %q", tok, tok.Pos, last, input)
+			}
+			last = tok.Pos
+		}
+		t.Fatalf("lexer did not reach EOF on %q", input)
+	})
+}
+
+// FuzzParse checks Parse never panics and returns either an AST or an error,
+// and that every AST it returns can be walked.
+func FuzzParse(f *testing.F) {
+	for _, s := range fuzzSeeds {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		node, err := Parse(input)
+		if err != nil {
+			if node != nil {
+				t.Fatalf("Parse(%q) returned both a node and error %v", input, err)
+			}
+			return
+		}
+		if node == nil {
+			t.Fatalf("Parse(%q) returned neither a node nor an error", input)
+		}
+		Walk(node, func(Node) {})
+	})
+}
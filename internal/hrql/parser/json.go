@@ -0,0 +1,153 @@
+package parser
+
+import (
+	"encoding/json"
+)
+
+// ParseToJSON parses an HRQL expression and returns its AST as JSON, for
+// tooling (linters, editors, query builders) that should not re-implement
+// the parser. See NodeJSON for the structure.
+func ParseToJSON(input string) ([]byte, error) {
+	node, err := Parse(input)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(NodeJSON(node))
+}
+
+// NodeJSON returns node as a JSON object. Every object has "type" and "pos"
+// (the byte offset Node describes); the other keys depend on the type:
+//
+//	pipe      steps
+//	field     chain
+//	self, dot, ids
+//	ident     name
+//	call      name, args, named (object of argument name to node, if any)
+//	relation  object, via (field, if any)
+//	where     cond
+//	binary    op, left, right
+//	in        left, and values (list) or source
+//	neg       expr
+//	literal   kind (string, number, duration or bool), value (as written)
+//	sort      field, desc; or random
+//	pick      op, n (nth), field (min_by, max_by)
+//	bucket    bounds (list of numbers as written), value (if any)
+//	agg       op
+//	case      whens (list of {cond, then}), else (if any)
+//
+// Child nodes are nested objects and lists are JSON arrays, so the result
+// also converts to a google.protobuf.Struct.
+func NodeJSON(node Node) map[string]any {
+	switch n := node.(type) {
+	case *PipeExpr:
+		return object("pipe", n.Pos, "steps", nodeList(n.Steps))
+	case *FieldAccess:
+		chain := make([]any, len(n.Chain))
+		for i, name := range n.Chain {
+			chain[i] = name
+		}
+		return object("field", n.Pos, "chain", chain)
+	case *SelfExpr:
+		return object("self", n.Pos)
+	case *DotExpr:
+		return object("dot", n.Pos)
+	case *IdentExpr:
+		return object("ident", n.Pos, "name", n.Name)
+	case *FuncCall:
+		o := object("call", n.Pos, "name", n.Name, "args", nodeList(n.Args))
+		if len(n.Named) > 0 {
+			named := make(map[string]any, len(n.Named))
+			for name, arg := range n.Named {
+				named[name] = NodeJSON(arg)
+			}
+			o["named"] = named
+		}
+		return o
+	case *RelationExpr:
+		o := object("relation", n.Pos, "object", n.Object)
+		if n.Via != nil {
+			o["via"] = NodeJSON(n.Via)
+		}
+		return o
+	case *WhereExpr:
+		return object("where", n.Pos, "cond", NodeJSON(n.Cond))
+	case *BinaryOp:
+		return object("binary", n.Pos, "op", n.Op, "left", NodeJSON(n.Left), "right", NodeJSON(n.Right))
+	case *InExpr:
+		o := object("in", n.Pos, "left", NodeJSON(n.Left))
+		if n.Source != nil {
+			o["source"] = NodeJSON(n.Source)
+		} else {
+			o["values"] = nodeList(n.Values)
+		}
+		return o
+	case *UnaryMinus:
+		return object("neg", n.Pos, "expr", NodeJSON(n.Expr))
+	case *Literal:
+		kind := n.Kind.String()
+		if n.Kind == TokTrue || n.Kind == TokFalse {
+			kind = "bool"
+		}
+		return object("literal", n.Pos, "kind", kind, "value", n.Value)
+	case *SortExpr:
+		if n.Random {
+			return object("sort", n.Pos, "random", true)
+		}
+		o := object("sort", n.Pos, "field", NodeJSON(n.Field), "desc", n.Desc)
+		if n.Nulls != "" {
+			o["nulls"] = n.Nulls
+		}
+		return o
+	case *PickExpr:
+		o := object("pick", n.Pos, "op", n.Op)
+		if n.Op == "nth" {
+			o["n"] = n.N
+		}
+		if n.Field != nil {
+			o["field"] = NodeJSON(n.Field)
+		}
+		return o
+	case *BucketExpr:
+		bounds := make([]any, len(n.Bounds))
+		for i, b := range n.Bounds {
+			bounds[i] = b
+		}
+		o := object("bucket", n.Pos, "bounds", bounds)
+		if n.Value != nil {
+			o["value"] = NodeJSON(n.Value)
+		}
+		return o
+	case *AggExpr:
+		return object("agg", n.Pos, "op", n.Op)
+	case *IDsExpr:
+		return object("ids", n.Pos)
+	case *CaseExpr:
+		whens := make([]any, len(n.Whens))
+		for i, w := range n.Whens {
+			whens[i] = map[string]any{"cond": NodeJSON(w.Cond), "then": NodeJSON(w.Then)}
+		}
+		o := object("case", n.Pos, "whens", whens)
+		if n.Else != nil {
+			o["else"] = NodeJSON(n.Else)
+		}
+		return o
+	}
+	return nil
+}
+
+// object builds a node object from its type, position and key/value pairs.
+func object(typ string, pos int, kv ...any) map[string]any {
+	o := map[string]any{"type": typ, "pos": pos}
+	for i := 0; i < len(kv); i += 2 {
+		o[kv[i].(string)] = kv[i+1]
+	}
+	return o
+}
+
+func nodeList(nodes []Node) []any {
+	out := make([]any, len(nodes))
+	for i, n := range nodes {
+		out[i] = NodeJSON(n)
+	}
+	return out
+}
@@ -0,0 +1,50 @@
+package parser
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseToJSON(t *testing.T) {
+	got, err := ParseToJSON(`employees | where(.department.title == "Eng" and .age in (30, 40)) | sort_by(.age, desc) | first`)
+	if err != nil {
+		t.Fatalf("ParseToJSON: %v", err)
+	}
+	want := `{"pos":0,"steps":[` +
+		`{"name":"employees","pos":0,"type":"ident"},` +
+		`{"cond":{"left":{"left":{"chain":["department","title"],"pos":18,"type":"field"},"op":"==","pos":36,"right":{"kind":"string","pos":39,"type":"literal","value":"Eng"},"type":"binary"},` +
+		`"op":"and","pos":45,"right":{"left":{"chain":["age"],"pos":49,"type":"field"},"pos":54,"type":"in","values":[` +
+		`{"kind":"number","pos":58,"type":"literal","value":"30"},{"kind":"number","pos":62,"type":"literal","value":"40"}]},"type":"binary"},"pos":12,"type":"where"},` +
+		`{"desc":true,"field":{"chain":["age"],"pos":77,"type":"field"},"pos":69,"type":"sort"},` +
+		`{"op":"first","pos":91,"type":"pick"}],"type":"pipe"}`
+	if string(got) != want {
+		t.Errorf("ParseToJSON =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestNodeJSONPositions(t *testing.T) {
+	// A pipe starts where its first step does, even inside parentheses.
+	node := mustParse(t, `  (self.manager | count) ?? 0`)
+	obj := NodeJSON(node)
+	if obj["type"] != "binary" || obj["pos"] != 25 {
+		t.Fatalf("root = %v, want binary at 25", obj)
+	}
+	left := obj["left"].(map[string]any)
+	if left["type"] != "pipe" || left["pos"] != 3 {
+		t.Errorf("left = %v, want pipe at 3", left)
+	}
+
+	named := NodeJSON(mustParse(t, `chain(self, 2)`))
+	if named["type"] != "call" || named["name"] != "chain" || len(named["args"].([]any)) != 2 {
+		t.Errorf("call = %v", named)
+	}
+	if _, err := json.Marshal(named); err != nil {
+		t.Errorf("marshal call: %v", err)
+	}
+}
+
+func TestParseToJSONError(t *testing.T) {
+	if _, err := ParseToJSON(`employees |`); err == nil {
+		t.Error("ParseToJSON of an incomplete pipe succeeded")
+	}
+}
@@ -62,9 +62,18 @@ func (l *Lexer) next() (Token, error) {
 	case ')':
 		l.pos++
 		return Token{Kind: TokRParen, Lit: ")", Pos: pos}, nil
+	case '[':
+		l.pos++
+		return Token{Kind: TokLBracket, Lit: "[", Pos: pos}, nil
+	case ']':
+		l.pos++
+		return Token{Kind: TokRBracket, Lit: "]", Pos: pos}, nil
 	case ',':
 		l.pos++
 		return Token{Kind: TokComma, Lit: ",", Pos: pos}, nil
+	case ':':
+		l.pos++
+		return Token{Kind: TokColon, Lit: ":", Pos: pos}, nil
 	case '+':
 		l.pos++
 		return Token{Kind: TokPlus, Lit: "+", Pos: pos}, nil
@@ -108,12 +117,25 @@ func (l *Lexer) next() (Token, error) {
 		}
 		l.pos++
 		return Token{Kind: TokLt, Lit: "<", Pos: pos}, nil
+	case '?':
+		if l.pos+1 < len(l.input) && l.input[l.pos+1] == '?' {
+			l.pos += 2
+			return Token{Kind: TokCoalesce, Lit: "??", Pos: pos}, nil
+		}
+		return Token{}, l.errorf(pos, "unexpected '?', did you mean '??'?")
 	case '"':
 		return l.readString(pos)
+	case '\'':
+		l.pos++ // skip opening '
+		return l.readRawString(pos, '\'')
 	default:
 		if unicode.IsDigit(ch) {
 			return l.readNumber(pos)
 		}
+		if ch == 'r' && l.pos+1 < len(l.input) && l.input[l.pos+1] == '"' {
+			l.pos += 2 // skip r"
+			return l.readRawString(pos, '"')
+		}
 		if isIdentStart(ch) {
 			return l.readIdent(pos)
 		}
@@ -121,24 +143,43 @@ func (l *Lexer) next() (Token, error) {
 	}
 }
 
+// readString reads a double-quoted string. \" and \\ stand for a quote and a
+// backslash; any other backslash is kept as written.
 func (l *Lexer) readString(pos int) (Token, error) {
 	l.pos++ // skip opening "
-	start := l.pos
+	var lit []rune
 	for l.pos < len(l.input) {
-		if l.input[l.pos] == '\\' && l.pos+1 < len(l.input) {
-			l.pos += 2 // skip escaped char
+		ch := l.input[l.pos]
+		if ch == '\\' && l.pos+1 < len(l.input) && (l.input[l.pos+1] == '"' || l.input[l.pos+1] == '\\') {
+			lit = append(lit, l.input[l.pos+1])
+			l.pos += 2
 			continue
 		}
-		if l.input[l.pos] == '"' {
-			lit := string(l.input[start:l.pos])
+		if ch == '"' {
 			l.pos++ // skip closing "
-			return Token{Kind: TokString, Lit: lit, Pos: pos}, nil
+			return Token{Kind: TokString, Lit: string(lit), Pos: pos}, nil
 		}
+		lit = append(lit, ch)
 		l.pos++
 	}
 	return Token{}, l.errorf(pos, "unterminated string literal")
 }
 
+// readRawString reads the rest of a raw string, 'O"Brien' or r"O'Brien", up
+// to the closing quote. Nothing is escaped, so it cannot contain quote.
+func (l *Lexer) readRawString(pos int, quote rune) (Token, error) {
+	start := l.pos
+	for l.pos < len(l.input) {
+		if l.input[l.pos] == quote {
+			lit := string(l.input[start:l.pos])
+			l.pos++ // skip closing quote
+			return Token{Kind: TokString, Lit: lit, Pos: pos}, nil
+		}
+		l.pos++
+	}
+	return Token{}, l.errorf(pos, "unterminated raw string literal")
+}
+
 func (l *Lexer) readNumber(pos int) (Token, error) {
 	start := l.pos
 	for l.pos < len(l.input) && unicode.IsDigit(l.input[l.pos]) {
@@ -153,6 +194,15 @@ func (l *Lexer) readNumber(pos int) (Token, error) {
 			}
 		}
 	}
+	// A unit suffix directly after the number makes a duration (2y, 6mo).
+	end := l.pos
+	for end < len(l.input) && isIdentCont(l.input[end]) {
+		end++
+	}
+	if _, ok := DurationUnits[string(l.input[l.pos:end])]; ok {
+		l.pos = end
+		return Token{Kind: TokDuration, Lit: string(l.input[start:l.pos]), Pos: pos}, nil
+	}
 	return Token{Kind: TokNumber, Lit: string(l.input[start:l.pos]), Pos: pos}, nil
 }
 
@@ -31,6 +31,7 @@ func TestLexerSingleCharTokens(t *testing.T) {
 		{"(", TokLParen},
 		{")", TokRParen},
 		{",", TokComma},
+		{":", TokColon},
 		{"+", TokPlus},
 		{"-", TokMinus},
 		{"*", TokStar},
@@ -60,6 +61,7 @@ func TestLexerTwoCharTokens(t *testing.T) {
 		{"<=", TokLte, "<="},
 		{">", TokGt, ">"},
 		{"<", TokLt, "<"},
+		{"??", TokCoalesce, "??"},
 	}
 	for _, tt := range tests {
 		toks := collectTokens(t, tt.input)
@@ -117,10 +119,10 @@ func TestLexerStrings(t *testing.T) {
 		t.Fatalf("expected lit %q, got %q", "hello", toks[0].Lit)
 	}
 
-	// Escaped quote
-	toks = collectTokens(t, `"a\"b"`)
-	if toks[0].Lit != `a\"b` {
-		t.Fatalf("expected lit %q, got %q", `a\"b`, toks[0].Lit)
+	// Escaped quote and backslash; other backslashes are kept
+	toks = collectTokens(t, `"a\"b\\c\d"`)
+	if toks[0].Lit != `a"b\c\d` {
+		t.Fatalf("expected lit %q, got %q", `a"b\c\d`, toks[0].Lit)
 	}
 
 	// Empty string
@@ -130,11 +132,33 @@ func TestLexerStrings(t *testing.T) {
 	}
 }
 
+func TestLexerRawStrings(t *testing.T) {
+	for input, lit := range map[string]string{
+		`'O"Brien'`:   `O"Brien`,
+		`r"O'Brien"`:  `O'Brien`,
+		`'C:\temp\'`:  `C:\temp\`,
+		`r"50%\_off"`: `50%\_off`,
+		`''`:          ``,
+	} {
+		toks := collectTokens(t, input)
+		if toks[0].Kind != TokString || toks[0].Lit != lit || toks[0].Pos != 0 {
+			t.Errorf("input %s: got %v at %d, want string %q", input, toks[0], toks[0].Pos, lit)
+		}
+	}
+
+	// r is still an identifier when no quote follows
+	toks := collectTokens(t, `r | r2`)
+	if toks[0].Kind != TokIdent || toks[0].Lit != "r" || toks[2].Lit != "r2" {
+		t.Errorf("r | r2: got %v", toks)
+	}
+}
+
 func TestLexerUnterminatedString(t *testing.T) {
-	lex := NewLexer(`"hello`)
-	_, err := lex.Next()
-	if err == nil {
-		t.Fatal("expected error for unterminated string")
+	for _, input := range []string{`"hello`, `'hello`, `r"hello`, `"hello\"`} {
+		lex := NewLexer(input)
+		if _, err := lex.Next(); err == nil {
+			t.Errorf("%s: expected error for unterminated string", input)
+		}
 	}
 }
 
@@ -159,6 +183,21 @@ func TestLexerNumbers(t *testing.T) {
 	}
 }
 
+func TestLexerDurations(t *testing.T) {
+	for _, input := range []string{"2y", "6mo", "3w", "90d", "1.5y"} {
+		toks := collectTokens(t, input)
+		if toks[0].Kind != TokDuration || toks[0].Lit != input {
+			t.Errorf("input %q: expected duration %q, got %v", input, input, toks[0])
+		}
+	}
+
+	// Unknown suffixes stay a number followed by an identifier.
+	toks := collectTokens(t, "2yr")
+	if toks[0].Kind != TokNumber || toks[1].Kind != TokIdent || toks[1].Lit != "yr" {
+		t.Errorf("2yr: got %v", toks)
+	}
+}
+
 func TestLexerWhitespace(t *testing.T) {
 	toks := collectTokens(t, "  foo  ")
 	if len(toks) != 2 { // ident + EOF
@@ -187,6 +226,7 @@ func TestLexerErrors(t *testing.T) {
 		{"=", "did you mean '=='"},
 		{"!", "did you mean '!='"},
 		{"@", "unexpected character"},
+		{"?", "did you mean '??'"},
 	}
 	for _, tt := range tests {
 		lex := NewLexer(tt.input)
@@ -255,6 +295,21 @@ func TestLexerPositionTracking(t *testing.T) {
 	}
 }
 
+func TestReservedWords(t *testing.T) {
+	words := make(map[string]bool)
+	for _, w := range ReservedWords() {
+		words[w] = true
+	}
+	for _, w := range []string{"and", "desc", "self", "where", "sort_by", "count", "reports_to", "as_of"} {
+		if !words[w] {
+			t.Errorf("expected %q to be reserved", w)
+		}
+	}
+	if words["department"] {
+		t.Error("department should not be reserved")
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(substr) == 0 ||
 		(len(s) > 0 && stringContains(s, substr)))
@@ -28,8 +28,33 @@ type parser struct {
 	input string
 }
 
-// parsePipeExpr: arithExpr { "|" pipeStep }
+// parsePipeExpr: pipeChain { "??" pipeChain }
+// ?? binds loosest, so `x | avg ?? 0` defaults the average and
+// `.bonus ?? 0 > 100` compares the defaulted field.
 func (p *parser) parsePipeExpr() (Node, error) {
+	left, err := p.parsePipeChain()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if tok.Kind != TokCoalesce {
+			return left, nil
+		}
+		p.advance()
+		right, err := p.parsePipeChain()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryOp{Op: "??", Left: left, Right: right, Pos: tok.Pos}
+	}
+}
+
+// parsePipeChain: arithExpr { "|" pipeStep }
+func (p *parser) parsePipeChain() (Node, error) {
 	first, err := p.parseArithExpr()
 	if err != nil {
 		return nil, err
@@ -46,7 +71,7 @@ func (p *parser) parsePipeExpr() (Node, error) {
 		if err != nil {
 			return nil, err
 		}
-		first = &PipeExpr{Steps: []Node{first, fa}}
+		first = &PipeExpr{Steps: []Node{first, fa}, Pos: start(first)}
 		tok, err = p.peek()
 		if err != nil {
 			return nil, err
@@ -78,7 +103,7 @@ func (p *parser) parsePipeExpr() (Node, error) {
 	if len(steps) == 1 {
 		return steps[0], nil
 	}
-	return &PipeExpr{Steps: steps}, nil
+	return &PipeExpr{Steps: steps, Pos: start(first)}, nil
 }
 
 // parseArithExpr: arithTerm { ("+" | "-") arithTerm }
@@ -100,7 +125,7 @@ func (p *parser) parseArithExpr() (Node, error) {
 		if err != nil {
 			return nil, err
 		}
-		left = &BinaryOp{Op: tok.Lit, Left: left, Right: right}
+		left = &BinaryOp{Op: tok.Lit, Left: left, Right: right, Pos: tok.Pos}
 	}
 	return left, nil
 }
@@ -124,7 +149,7 @@ func (p *parser) parseArithTerm() (Node, error) {
 		if err != nil {
 			return nil, err
 		}
-		left = &BinaryOp{Op: tok.Lit, Left: left, Right: right}
+		left = &BinaryOp{Op: tok.Lit, Left: left, Right: right, Pos: tok.Pos}
 	}
 	return left, nil
 }
@@ -163,12 +188,21 @@ func (p *parser) parsePipeIdent() (Node, error) {
 		return p.parseSortBy()
 	case "first", "last":
 		p.advance()
-		return &PickExpr{Op: name}, nil
+		return &PickExpr{Op: name, Pos: tok.Pos}, nil
 	case "nth":
 		return p.parseNth()
+	case "min_by", "max_by":
+		return p.parsePickBy()
 	case "count", "sum", "avg", "min", "max":
 		p.advance()
-		return &AggExpr{Op: name}, nil
+		return &AggExpr{Op: name, Pos: tok.Pos}, nil
+	case "ids":
+		p.advance()
+		return &IDsExpr{Pos: tok.Pos}, nil
+	case "bucket":
+		return p.parseBucket()
+	case "case":
+		return p.parseCase()
 	default:
 		// Check if it's a function call: ident(
 		return p.parseFuncCallOrIdent()
@@ -185,11 +219,16 @@ func (p *parser) parsePrimary() (Node, error) {
 	switch {
 	case tok.Kind == TokIdent && tok.Lit == "self":
 		p.advance()
-		return &SelfExpr{}, nil
+		return &SelfExpr{Pos: tok.Pos}, nil
 
 	case tok.Kind == TokIdent && tok.Lit == "employees":
 		p.advance()
-		return &IdentExpr{Name: "employees"}, nil
+		if next, err := p.peek(); err != nil {
+			return nil, err
+		} else if next.Kind == TokLParen {
+			return p.parseRelation("employees", tok.Pos)
+		}
+		return &IdentExpr{Name: "employees", Pos: tok.Pos}, nil
 
 	case tok.Kind == TokIdent:
 		return p.parseFuncCallOrIdent()
@@ -198,13 +237,13 @@ func (p *parser) parsePrimary() (Node, error) {
 		// . alone or .field
 		return p.parseDotOrFieldAccess()
 
-	case tok.Kind == TokString || tok.Kind == TokNumber:
+	case tok.Kind == TokString || tok.Kind == TokNumber || tok.Kind == TokDuration:
 		p.advance()
-		return &Literal{Kind: tok.Kind, Value: tok.Lit}, nil
+		return &Literal{Kind: tok.Kind, Value: tok.Lit, Pos: tok.Pos}, nil
 
 	case tok.Kind == TokTrue || tok.Kind == TokFalse:
 		p.advance()
-		return &Literal{Kind: tok.Kind, Value: tok.Lit}, nil
+		return &Literal{Kind: tok.Kind, Value: tok.Lit, Pos: tok.Pos}, nil
 
 	case tok.Kind == TokMinus:
 		p.advance()
@@ -212,7 +251,7 @@ func (p *parser) parsePrimary() (Node, error) {
 		if err != nil {
 			return nil, err
 		}
-		return &UnaryMinus{Expr: expr}, nil
+		return &UnaryMinus{Expr: expr, Pos: tok.Pos}, nil
 
 	case tok.Kind == TokLParen:
 		p.advance() // consume (
@@ -230,17 +269,77 @@ func (p *parser) parsePrimary() (Node, error) {
 	}
 }
 
+// parseRelation parses the parenthesized part of a relation, name(.) or
+// name(., via: .field), with the name already consumed. Anything else is a
+// call to an unknown function.
+func (p *parser) parseRelation(name string, pos int) (Node, error) {
+	p.advance() // consume (
+	tok, err := p.peek()
+	if err != nil {
+		return nil, err
+	}
+	if tok.Kind != TokDot {
+		return nil, p.errorf(pos, "unknown function %q", name)
+	}
+	arg, err := p.parseDotOrFieldAccess()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := arg.(*DotExpr); !ok {
+		return nil, p.errorf(pos, "unknown function %q", name)
+	}
+
+	rel := &RelationExpr{Object: name, Pos: pos}
+	if tok, err = p.peek(); err != nil {
+		return nil, err
+	}
+	if tok.Kind == TokComma {
+		p.advance()
+		if err := p.expectWord("via"); err != nil {
+			return nil, err
+		}
+		if err := p.expect(TokColon); err != nil {
+			return nil, err
+		}
+		if tok, err = p.peek(); err != nil {
+			return nil, err
+		}
+		if tok.Kind != TokDot {
+			return nil, p.errorf(tok.Pos, "%s(.) via: expected a field (.field), got %s", name, tok.Kind)
+		}
+		via, err := p.parseDotOrFieldAccess()
+		if err != nil {
+			return nil, err
+		}
+		fa, ok := via.(*FieldAccess)
+		if !ok {
+			return nil, p.errorf(tok.Pos, "%s(.) via: expected a field (.field), got '.'", name)
+		}
+		rel.Via = fa
+	}
+	if err := p.expect(TokRParen); err != nil {
+		return nil, err
+	}
+	return rel, nil
+}
+
 // parseDotOrFieldAccess handles `.` (dot pronoun) or `.field.subfield` (field access).
 func (p *parser) parseDotOrFieldAccess() (Node, error) {
+	tok, err := p.peek()
+	if err != nil {
+		return nil, err
+	}
+	pos := tok.Pos
+	start := pos
 	p.advance() // consume .
 
-	tok, err := p.peek()
+	tok, err = p.peek()
 	if err != nil {
 		return nil, err
 	}
 
-	if tok.Kind != TokIdent {
-		return &DotExpr{}, nil
+	if _, ok := fieldName(tok, pos); !ok {
+		return &DotExpr{Pos: pos}, nil
 	}
 
 	// .field.subfield...
@@ -250,11 +349,15 @@ func (p *parser) parseDotOrFieldAccess() (Node, error) {
 		if err != nil {
 			return nil, err
 		}
-		if tok.Kind != TokIdent {
+		name, ok := fieldName(tok, pos)
+		if !ok {
 			break
 		}
+		if name == "" {
+			return nil, p.errorf(tok.Pos, "empty field name")
+		}
 		p.advance()
-		chain = append(chain, tok.Lit)
+		chain = append(chain, name)
 
 		// Check for more dots
 		tok, err = p.peek()
@@ -264,15 +367,16 @@ func (p *parser) parseDotOrFieldAccess() (Node, error) {
 		if tok.Kind != TokDot {
 			break
 		}
-		// Look ahead: is the next thing after the dot an identifier?
+		// Look ahead: is the next thing after the dot a field name?
 		// If so, continue the chain. Otherwise, stop (it could be a pipe step).
 		// Save state to potentially backtrack.
+		pos = tok.Pos
 		p.advance() // consume .
 		next, err := p.peek()
 		if err != nil {
 			return nil, err
 		}
-		if next.Kind != TokIdent {
+		if _, ok := fieldName(next, pos); !ok {
 			// It was a trailing dot — put it back by creating a synthetic token.
 			// Actually, we need to handle this as the dot becoming a pipe's field access.
 			// This shouldn't happen in practice since `.field.` without continuation is invalid.
@@ -280,7 +384,22 @@ func (p *parser) parseDotOrFieldAccess() (Node, error) {
 		}
 	}
 
-	return &FieldAccess{Chain: chain}, nil
+	return &FieldAccess{Chain: chain, Pos: start}, nil
+}
+
+// fieldName returns the field named by tok, which follows the '.' at dot:
+// an identifier, a quoted name (."count") or a keyword written right after
+// the dot (.desc), which there names a field rather than acting as the
+// keyword. Quoting reaches fields whose api_name HRQL gives a meaning.
+func fieldName(tok Token, dot int) (string, bool) {
+	switch tok.Kind {
+	case TokIdent, TokString:
+		return tok.Lit, true
+	}
+	if kind, ok := keywords[tok.Lit]; ok && kind == tok.Kind && tok.Pos == dot+1 {
+		return tok.Lit, true
+	}
+	return "", false
 }
 
 // parseFieldAccessChain handles .field.subfield in pipe position.
@@ -292,28 +411,25 @@ func (p *parser) parseFieldAccessChain() (Node, error) {
 	if tok.Kind != TokDot {
 		return nil, p.errorf(tok.Pos, "expected '.', got %s", tok.Kind)
 	}
+	start := tok.Pos
+	dot := start
 	p.advance() // consume .
 
-	// Must have at least one field name
-	tok, err = p.peek()
-	if err != nil {
-		return nil, err
-	}
-	if tok.Kind != TokIdent {
-		return nil, p.errorf(tok.Pos, "expected field name after '.', got %s", tok.Kind)
-	}
-
 	var chain []string
 	for {
 		tok, err = p.peek()
 		if err != nil {
 			return nil, err
 		}
-		if tok.Kind != TokIdent {
-			break
+		name, ok := fieldName(tok, dot)
+		if !ok {
+			return nil, p.errorf(tok.Pos, "expected field name after '.', got %s", tok.Kind)
+		}
+		if name == "" {
+			return nil, p.errorf(tok.Pos, "empty field name")
 		}
 		p.advance()
-		chain = append(chain, tok.Lit)
+		chain = append(chain, name)
 
 		tok, err = p.peek()
 		if err != nil {
@@ -322,21 +438,16 @@ func (p *parser) parseFieldAccessChain() (Node, error) {
 		if tok.Kind != TokDot {
 			break
 		}
+		dot = tok.Pos
 		p.advance() // consume .
-		next, err := p.peek()
-		if err != nil {
-			return nil, err
-		}
-		if next.Kind != TokIdent {
-			return nil, p.errorf(next.Pos, "expected field name after '.', got %s", next.Kind)
-		}
 	}
 
-	return &FieldAccess{Chain: chain}, nil
+	return &FieldAccess{Chain: chain, Pos: start}, nil
 }
 
 // parseWhere: where(boolExpr)
 func (p *parser) parseWhere() (Node, error) {
+	pos := p.pos()
 	p.advance() // consume "where"
 	if err := p.expect(TokLParen); err != nil {
 		return nil, err
@@ -348,55 +459,138 @@ func (p *parser) parseWhere() (Node, error) {
 	if err := p.expect(TokRParen); err != nil {
 		return nil, err
 	}
-	return &WhereExpr{Cond: cond}, nil
+	return &WhereExpr{Cond: cond, Pos: pos}, nil
 }
 
-// parseSortBy: sort_by(.field [, asc|desc])
+// parseCase: case( ("when" boolExpr "then" primary)+ ["else" primary] )
+func (p *parser) parseCase() (Node, error) {
+	c := &CaseExpr{Pos: p.pos()}
+	p.advance() // consume "case"
+	if err := p.expect(TokLParen); err != nil {
+		return nil, err
+	}
+
+	for {
+		tok, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if tok.Kind != TokIdent || tok.Lit != "when" {
+			break
+		}
+		p.advance() // consume "when"
+		cond, err := p.parseBoolExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectWord("then"); err != nil {
+			return nil, err
+		}
+		then, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		c.Whens = append(c.Whens, CaseWhen{Cond: cond, Then: then})
+	}
+	if len(c.Whens) == 0 {
+		tok, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+		return nil, p.errorf(tok.Pos, "case expects at least one 'when', got %s", tok.Kind)
+	}
+
+	tok, err := p.peek()
+	if err != nil {
+		return nil, err
+	}
+	if tok.Kind == TokIdent && tok.Lit == "else" {
+		p.advance() // consume "else"
+		if c.Else, err = p.parsePrimary(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.expect(TokRParen); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// parseSortBy: sort_by(.field [, asc|desc] [, nulls_first|nulls_last]) or sort_by(random)
 func (p *parser) parseSortBy() (Node, error) {
+	pos := p.pos()
 	p.advance() // consume "sort_by"
 	if err := p.expect(TokLParen); err != nil {
 		return nil, err
 	}
 
-	fa, err := p.parseFieldAccessChain()
+	tok, err := p.peek()
 	if err != nil {
 		return nil, err
 	}
-	fieldAccess, ok := fa.(*FieldAccess)
-	if !ok {
-		return nil, fmt.Errorf("sort_by expects a field access (.field), got %T", fa)
+	if tok.Kind == TokIdent && tok.Lit == "random" {
+		p.advance() // consume "random"
+		if err := p.expect(TokRParen); err != nil {
+			return nil, err
+		}
+		return &SortExpr{Random: true, Pos: pos}, nil
 	}
 
-	desc := false
-	tok, err := p.peek()
+	fa, err := p.parseFieldAccessChain()
 	if err != nil {
 		return nil, err
 	}
-	if tok.Kind == TokComma {
+	fieldAccess, ok := fa.(*FieldAccess)
+	if !ok {
+		return nil, fmt.Errorf("sort_by expects a field access (.field) or random, got %T", fa)
+	}
+
+	sort := &SortExpr{Field: fieldAccess, Pos: pos}
+	// Each optional argument follows a comma: the direction first, then the
+	// placement of nulls.
+	for _, arg := range []string{"direction", "nulls"} {
+		tok, err = p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if tok.Kind != TokComma {
+			break
+		}
 		p.advance() // consume ,
 		tok, err = p.peek()
 		if err != nil {
 			return nil, err
 		}
-		switch tok.Kind {
-		case TokAsc:
+		switch {
+		case arg == "direction" && (tok.Kind == TokAsc || tok.Kind == TokDesc):
 			p.advance()
-		case TokDesc:
+			sort.Desc = tok.Kind == TokDesc
+		case tok.Kind == TokIdent && tok.Lit == "nulls_first":
 			p.advance()
-			desc = true
+			sort.Nulls = "first"
+		case tok.Kind == TokIdent && tok.Lit == "nulls_last":
+			p.advance()
+			sort.Nulls = "last"
+		case arg == "direction":
+			return nil, p.errorf(tok.Pos, "expected 'asc' or 'desc', or 'nulls_first' or 'nulls_last', got %s", tok.Kind)
 		default:
-			return nil, p.errorf(tok.Pos, "expected 'asc' or 'desc', got %s", tok.Kind)
+			return nil, p.errorf(tok.Pos, "expected 'nulls_first' or 'nulls_last', got %s", tok.Kind)
+		}
+		if sort.Nulls != "" {
+			break
 		}
 	}
 
 	if err := p.expect(TokRParen); err != nil {
 		return nil, err
 	}
-	return &SortExpr{Field: fieldAccess, Desc: desc}, nil
+	return sort, nil
 }
 
 // parseNth: nth(n)
 func (p *parser) parseNth() (Node, error) {
+	pos := p.pos()
 	p.advance() // consume "nth"
 	if err := p.expect(TokLParen); err != nil {
 		return nil, err
@@ -416,7 +610,97 @@ func (p *parser) parseNth() (Node, error) {
 	if err := p.expect(TokRParen); err != nil {
 		return nil, err
 	}
-	return &PickExpr{Op: "nth", N: n}, nil
+	return &PickExpr{Op: "nth", N: n, Pos: pos}, nil
+}
+
+// parsePickBy: min_by(.field) or max_by(.field)
+func (p *parser) parsePickBy() (Node, error) {
+	tok, err := p.peek()
+	if err != nil {
+		return nil, err
+	}
+	p.advance() // consume "min_by" / "max_by"
+	if err := p.expect(TokLParen); err != nil {
+		return nil, err
+	}
+
+	fa, err := p.parseFieldAccessChain()
+	if err != nil {
+		return nil, err
+	}
+	fieldAccess, ok := fa.(*FieldAccess)
+	if !ok {
+		return nil, fmt.Errorf("%s expects a field access (.field), got %T", tok.Lit, fa)
+	}
+
+	if err := p.expect(TokRParen); err != nil {
+		return nil, err
+	}
+	return &PickExpr{Op: tok.Lit, Field: fieldAccess, Pos: tok.Pos}, nil
+}
+
+// parseBucket: bucket(.field, [b1, b2, ...]) or bucket([b1, b2, ...])
+func (p *parser) parseBucket() (Node, error) {
+	pos := p.pos()
+	p.advance() // consume "bucket"
+	if err := p.expect(TokLParen); err != nil {
+		return nil, err
+	}
+
+	tok, err := p.peek()
+	if err != nil {
+		return nil, err
+	}
+	var value Node
+	if tok.Kind != TokLBracket {
+		if value, err = p.parsePipeExpr(); err != nil {
+			return nil, err
+		}
+		if err := p.expect(TokComma); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expect(TokLBracket); err != nil {
+		return nil, err
+	}
+
+	var bounds []string
+	for {
+		tok, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if len(bounds) > 0 {
+			if tok.Kind == TokRBracket {
+				p.advance() // consume ]
+				break
+			}
+			if err := p.expect(TokComma); err != nil {
+				return nil, err
+			}
+			if tok, err = p.peek(); err != nil {
+				return nil, err
+			}
+		}
+		sign := ""
+		if tok.Kind == TokMinus {
+			p.advance() // consume -
+			sign = "-"
+			if tok, err = p.peek(); err != nil {
+				return nil, err
+			}
+		}
+		if tok.Kind != TokNumber {
+			return nil, p.errorf(tok.Pos, "bucket bounds must be numbers, got %s", tok.Kind)
+		}
+		p.advance()
+		bounds = append(bounds, sign+tok.Lit)
+	}
+
+	if err := p.expect(TokRParen); err != nil {
+		return nil, err
+	}
+	return &BucketExpr{Value: value, Bounds: bounds, Pos: pos}, nil
 }
 
 // parseFuncCallOrIdent handles `ident(args...)` or bare `ident`.
@@ -439,24 +723,26 @@ func (p *parser) parseFuncCallOrIdent() (Node, error) {
 		return nil, err
 	}
 	if next.Kind != TokLParen {
-		// No parens — check for zero-arg registered function.
+		// No parens — check for a registered function with no required args.
 		if def, ok := GetFunction(name); ok {
-			if len(def.ArgTypes) > 0 {
+			if len(def.ArgTypes) > def.Variadic {
 				return nil, p.errorf(pos, "function %q requires arguments", name)
 			}
-			return &FuncCall{Func: def, Name: name}, nil
+			return &FuncCall{Func: def, Name: name, Pos: pos}, nil
 		}
-		return &IdentExpr{Name: name}, nil
+		return &IdentExpr{Name: name, Pos: pos}, nil
 	}
 
-	// Function call with parens — lookup required.
+	// Function call with parens — lookup required, except for relations
+	// (object(.)), whose names come from the schema.
 	def, ok := GetFunction(name)
 	if !ok {
-		return nil, p.errorf(pos, "unknown function %q", name)
+		return p.parseRelation(name, pos)
 	}
 
 	p.advance() // consume (
 	var args []Node
+	var named map[string]Node
 	for {
 		tok, err = p.peek()
 		if err != nil {
@@ -465,15 +751,49 @@ func (p *parser) parseFuncCallOrIdent() (Node, error) {
 		if tok.Kind == TokRParen {
 			break
 		}
-		if len(args) > 0 {
+		if len(args) > 0 || len(named) > 0 {
 			if err := p.expect(TokComma); err != nil {
 				return nil, err
 			}
+			if tok, err = p.peek(); err != nil {
+				return nil, err
+			}
+		}
+		parseArg := p.parsePipeExpr
+		if i := len(args); i < len(def.ArgTypes) && def.ArgTypes[i] == ArgPredicate {
+			parseArg = p.parseBoolExpr
 		}
-		arg, err := p.parsePipeExpr()
+		arg, err := parseArg()
 		if err != nil {
 			return nil, err
 		}
+
+		// name: value
+		next, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if ident, ok := arg.(*IdentExpr); ok && next.Kind == TokColon {
+			if _, ok := def.Named[ident.Name]; !ok {
+				return nil, p.errorf(tok.Pos, "function %q has no argument named %q", name, ident.Name)
+			}
+			if _, dup := named[ident.Name]; dup {
+				return nil, p.errorf(tok.Pos, "function %q: argument %q given twice", name, ident.Name)
+			}
+			p.advance() // consume :
+			value, err := p.parsePipeExpr()
+			if err != nil {
+				return nil, err
+			}
+			if named == nil {
+				named = make(map[string]Node)
+			}
+			named[ident.Name] = value
+			continue
+		}
+		if len(named) > 0 {
+			return nil, p.errorf(tok.Pos, "function %q: positional argument after named arguments", name)
+		}
 		args = append(args, arg)
 	}
 	p.advance() // consume )
@@ -488,7 +808,7 @@ func (p *parser) parseFuncCallOrIdent() (Node, error) {
 		return nil, p.errorf(pos, "function %q requires %d to %d arguments, got %d", name, minArgs, maxArgs, len(args))
 	}
 
-	return &FuncCall{Func: def, Name: name, Args: args}, nil
+	return &FuncCall{Func: def, Name: name, Args: args, Named: named, Pos: pos}, nil
 }
 
 // --- Boolean expression parsing (inside where) ---
@@ -512,7 +832,7 @@ func (p *parser) parseBoolExpr() (Node, error) {
 		if err != nil {
 			return nil, err
 		}
-		left = &BinaryOp{Op: "or", Left: left, Right: right}
+		left = &BinaryOp{Op: "or", Left: left, Right: right, Pos: tok.Pos}
 	}
 	return left, nil
 }
@@ -536,7 +856,7 @@ func (p *parser) parseBoolTerm() (Node, error) {
 		if err != nil {
 			return nil, err
 		}
-		left = &BinaryOp{Op: "and", Left: left, Right: right}
+		left = &BinaryOp{Op: "and", Left: left, Right: right, Pos: tok.Pos}
 	}
 	return left, nil
 }
@@ -583,6 +903,9 @@ func (p *parser) parseBoolFactor() (Node, error) {
 	if isComparisonOp(tok.Kind) {
 		return p.finishComparison(left)
 	}
+	if tok.Kind == TokIn {
+		return p.finishIn(left)
+	}
 
 	// No comparison operator — this is a boolean subexpression (e.g., a function call that returns bool)
 	return left, nil
@@ -610,7 +933,58 @@ func (p *parser) finishComparison(left Node) (Node, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &BinaryOp{Op: op, Left: left, Right: right}, nil
+	return &BinaryOp{Op: op, Left: left, Right: right, Pos: tok.Pos}, nil
+}
+
+// finishIn: given left side already parsed, parse
+// `in ( "(" valueExpr { "," valueExpr } ")" | valueExpr )`.
+func (p *parser) finishIn(left Node) (Node, error) {
+	tok, err := p.peek()
+	if err != nil {
+		return nil, err
+	}
+	pos := tok.Pos
+	p.advance() // in
+	tok, err = p.peek()
+	if err != nil {
+		return nil, err
+	}
+	if tok.Kind != TokLParen {
+		source, err := p.parseValueExpr()
+		if err != nil {
+			return nil, err
+		}
+		return &InExpr{Left: left, Source: source, Pos: pos}, nil
+	}
+
+	p.advance()
+	tok, err = p.peek()
+	if err != nil {
+		return nil, err
+	}
+	if tok.Kind == TokRParen {
+		return nil, p.errorf(tok.Pos, "in requires at least one value")
+	}
+	var values []Node
+	for {
+		v, err := p.parseValueExpr()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+		tok, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if tok.Kind != TokComma {
+			break
+		}
+		p.advance()
+	}
+	if err := p.expect(TokRParen); err != nil {
+		return nil, err
+	}
+	return &InExpr{Left: left, Values: values, Pos: pos}, nil
 }
 
 func isComparisonOp(k TokenKind) bool {
@@ -627,6 +1001,13 @@ func (p *parser) peek() (Token, error) {
 	return p.lexer.Peek()
 }
 
+// pos returns the offset of the next token; the caller has already peeked
+// it, so there is no error to report.
+func (p *parser) pos() int {
+	tok, _ := p.lexer.Peek()
+	return tok.Pos
+}
+
 func (p *parser) advance() {
 	p.lexer.Next() //nolint:errcheck
 }
@@ -642,6 +1023,18 @@ func (p *parser) expect(kind TokenKind) error {
 	return nil
 }
 
+// expectWord consumes a contextual word such as "then".
+func (p *parser) expectWord(word string) error {
+	tok, err := p.lexer.Next()
+	if err != nil {
+		return err
+	}
+	if tok.Kind != TokIdent || tok.Lit != word {
+		return p.errorf(tok.Pos, "expected '%s', got %s", word, tok.Kind)
+	}
+	return nil
+}
+
 func (p *parser) errorf(pos int, format string, args ...any) error {
 	return fmt.Errorf("parse error at position %d: %s", pos, fmt.Sprintf(format, args...))
 }
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"slices"
 	"strings"
 	"testing"
 )
@@ -102,6 +103,26 @@ func TestParseNumberLiteral(t *testing.T) {
 	}
 }
 
+func TestParseDurationLiteral(t *testing.T) {
+	node := mustParse(t, "6mo")
+	lit, ok := node.(*Literal)
+	if !ok {
+		t.Fatalf("expected *Literal, got %T", node)
+	}
+	if lit.Kind != TokDuration || lit.Value != "6mo" {
+		t.Fatalf("expected duration 6mo, got %v %q", lit.Kind, lit.Value)
+	}
+
+	node = mustParse(t, `employees | where(tenure(.start_date) > 2y)`)
+	cmp, ok := node.(*PipeExpr).Steps[1].(*WhereExpr).Cond.(*BinaryOp)
+	if !ok || cmp.Op != ">" {
+		t.Fatalf("where cond: expected >, got %v", node)
+	}
+	if lit, ok := cmp.Right.(*Literal); !ok || lit.Kind != TokDuration || lit.Value != "2y" {
+		t.Fatalf("expected duration 2y, got %#v", cmp.Right)
+	}
+}
+
 func TestParseBooleanLiterals(t *testing.T) {
 	for _, tt := range []struct {
 		input string
@@ -180,6 +201,32 @@ func TestParsePipeFieldAccess(t *testing.T) {
 	}
 }
 
+func TestParseKeywordFieldNames(t *testing.T) {
+	for input, want := range map[string][]string{
+		`employees | ."count"`:                    {"count"},
+		`employees | .department."title"`:         {"department", "title"},
+		`employees | where(.desc == "x")`:         {"desc"},
+		`employees | sort_by(.in, desc)`:          {"in"},
+		`self."first"`:                            {"first"},
+		`employees | where(."and" and .or)`:       {"and"},
+		`employees | min_by(.department."count")`: {"department", "count"},
+	} {
+		var chain []string
+		Walk(mustParse(t, input), func(n Node) {
+			if fa, ok := n.(*FieldAccess); ok && chain == nil {
+				chain = fa.Chain
+			}
+		})
+		if !slices.Equal(chain, want) {
+			t.Errorf("%s: first field chain = %v, want %v", input, chain, want)
+		}
+	}
+
+	// A keyword only names a field right after the dot.
+	expectParseError(t, `employees | . desc`, "expected field name")
+	expectParseError(t, `employees | .""`, "empty field name")
+}
+
 func TestParsePipeSortBy(t *testing.T) {
 	node := mustParse(t, `employees | sort_by(.name)`)
 	pipe := node.(*PipeExpr)
@@ -204,6 +251,28 @@ func TestParsePipeSortByDesc(t *testing.T) {
 	}
 }
 
+func TestParsePipeSortByNulls(t *testing.T) {
+	s := mustParse(t, `employees | sort_by(.end_date, desc, nulls_first)`).(*PipeExpr).Steps[1].(*SortExpr)
+	if !s.Desc || s.Nulls != "first" {
+		t.Fatalf("expected desc nulls first, got %+v", s)
+	}
+	s = mustParse(t, `employees | sort_by(.end_date, nulls_last)`).(*PipeExpr).Steps[1].(*SortExpr)
+	if s.Desc || s.Nulls != "last" {
+		t.Fatalf("expected asc nulls last, got %+v", s)
+	}
+	expectParseError(t, "employees | sort_by(.end_date, nulls_last, desc)", "expected )")
+	expectParseError(t, "employees | sort_by(.end_date, desc, asc)", "expected 'nulls_first' or 'nulls_last'")
+}
+
+func TestParsePipeSortByRandom(t *testing.T) {
+	node := mustParse(t, `employees | sort_by(random)`)
+	s := node.(*PipeExpr).Steps[1].(*SortExpr)
+	if !s.Random || s.Field != nil {
+		t.Fatalf("expected a random sort, got %+v", s)
+	}
+	expectParseError(t, "employees | sort_by(random, desc)", "expected )")
+}
+
 func TestParsePipeFirst(t *testing.T) {
 	node := mustParse(t, `employees | first`)
 	pipe := node.(*PipeExpr)
@@ -234,6 +303,18 @@ func TestParsePipeNth(t *testing.T) {
 	}
 }
 
+func TestParsePipeMaxBy(t *testing.T) {
+	node := mustParse(t, `reports(self, 1) | max_by(.start_date)`)
+	pipe := node.(*PipeExpr)
+	p, ok := pipe.Steps[1].(*PickExpr)
+	if !ok {
+		t.Fatalf("expected *PickExpr, got %T", pipe.Steps[1])
+	}
+	if p.Op != "max_by" || p.Field == nil || p.Field.Chain[0] != "start_date" {
+		t.Fatalf("expected max_by(.start_date), got %q(%v)", p.Op, p.Field)
+	}
+}
+
 func TestParsePipeCount(t *testing.T) {
 	node := mustParse(t, `employees | count`)
 	pipe := node.(*PipeExpr)
@@ -608,6 +689,63 @@ func TestParseArithNested(t *testing.T) {
 	}
 }
 
+func TestParseCoalesce(t *testing.T) {
+	// ?? binds looser than pipe: (employees | .fte | avg) ?? 0
+	node := mustParse(t, "employees | .fte | avg ?? 0")
+	op, ok := node.(*BinaryOp)
+	if !ok || op.Op != "??" {
+		t.Fatalf("expected ?? BinaryOp, got %T %v", node, node)
+	}
+	if _, ok := op.Left.(*PipeExpr); !ok {
+		t.Fatalf("left: expected *PipeExpr, got %T", op.Left)
+	}
+	if lit, ok := op.Right.(*Literal); !ok || lit.Value != "0" {
+		t.Fatalf("right: expected literal 0, got %T %v", op.Right, op.Right)
+	}
+}
+
+func TestParseCoalesceInWhere(t *testing.T) {
+	// .bonus ?? 0 > 100 → (.bonus ?? 0) > 100
+	node := mustParse(t, "employees | where(.bonus ?? 0 > 100)")
+	pipe := node.(*PipeExpr)
+	where := pipe.Steps[1].(*WhereExpr)
+	cmp := where.Cond.(*BinaryOp)
+	if cmp.Op != ">" {
+		t.Fatalf("expected >, got %q", cmp.Op)
+	}
+	if left, ok := cmp.Left.(*BinaryOp); !ok || left.Op != "??" {
+		t.Fatalf("left: expected ?? BinaryOp, got %T %v", cmp.Left, cmp.Left)
+	}
+}
+
+func TestParseIn(t *testing.T) {
+	node := mustParse(t, `employees | where(.id in chain(self))`)
+	in, ok := node.(*PipeExpr).Steps[1].(*WhereExpr).Cond.(*InExpr)
+	if !ok {
+		t.Fatalf("expected *InExpr, got %T", node.(*PipeExpr).Steps[1].(*WhereExpr).Cond)
+	}
+	if fn, ok := in.Source.(*FuncCall); !ok || fn.Name != "chain" {
+		t.Fatalf("source: expected chain call, got %T", in.Source)
+	}
+
+	node = mustParse(t, `employees | where(.department in (self.department, "x") and .id != self)`)
+	and := node.(*PipeExpr).Steps[1].(*WhereExpr).Cond.(*BinaryOp)
+	in, ok = and.Left.(*InExpr)
+	if !ok || in.Source != nil || len(in.Values) != 2 {
+		t.Fatalf("expected in with 2 values, got %T %+v", and.Left, and.Left)
+	}
+	if in.Pos != 30 {
+		t.Errorf("in.Pos = %d, want 30", in.Pos)
+	}
+	if cmp := and.Right.(*BinaryOp); cmp.Op != "!=" || cmp.Pos != 64 {
+		t.Errorf("comparison = %s at %d, want != at 64", cmp.Op, cmp.Pos)
+	}
+}
+
+func TestParseErrorInEmpty(t *testing.T) {
+	expectParseError(t, `employees | where(.id in ())`, "at least one value")
+}
+
 // --- Error cases ---
 
 func TestParseErrorTrailingTokens(t *testing.T) {
@@ -674,3 +812,142 @@ func TestParsePipeTransformFuncs(t *testing.T) {
 		}
 	}
 }
+
+func TestParseCase(t *testing.T) {
+	node := mustParse(t, `employees | case(when .employment_type == "FULL_TIME" then "FT" when .employment_type == "CONTRACTOR" then 1 else "Other")`)
+	pipe := node.(*PipeExpr)
+	ce, ok := pipe.Steps[1].(*CaseExpr)
+	if !ok {
+		t.Fatalf("expected *CaseExpr, got %T", pipe.Steps[1])
+	}
+	if len(ce.Whens) != 2 {
+		t.Fatalf("expected 2 whens, got %d", len(ce.Whens))
+	}
+	if cond, ok := ce.Whens[0].Cond.(*BinaryOp); !ok || cond.Op != "==" {
+		t.Fatalf("when 0: expected == comparison, got %T", ce.Whens[0].Cond)
+	}
+	if lit, ok := ce.Whens[0].Then.(*Literal); !ok || lit.Value != "FT" {
+		t.Fatalf("when 0: expected literal FT, got %T", ce.Whens[0].Then)
+	}
+	if lit, ok := ce.Else.(*Literal); !ok || lit.Value != "Other" {
+		t.Fatalf("else: expected literal Other, got %T", ce.Else)
+	}
+}
+
+func TestParseErrorCase(t *testing.T) {
+	expectParseError(t, `employees | case(else "x")`, "at least one 'when'")
+	expectParseError(t, `employees | case(when .x == 1 "a")`, "expected 'then'")
+}
+
+func TestParseBucket(t *testing.T) {
+	node := mustParse(t, `employees | bucket(.start_date | years_since, [-1, 0, 2.5, 10])`)
+	b, ok := node.(*PipeExpr).Steps[1].(*BucketExpr)
+	if !ok {
+		t.Fatalf("expected *BucketExpr, got %T", node.(*PipeExpr).Steps[1])
+	}
+	if got := strings.Join(b.Bounds, ","); got != "-1,0,2.5,10" {
+		t.Errorf("bounds = %s", got)
+	}
+	if pipe, ok := b.Value.(*PipeExpr); !ok || len(pipe.Steps) != 2 {
+		t.Errorf("expected .start_date | years_since value, got %#v", b.Value)
+	}
+
+	node = mustParse(t, `employees | .salary | bucket([100])`)
+	if b := node.(*PipeExpr).Steps[2].(*BucketExpr); b.Value != nil || len(b.Bounds) != 1 {
+		t.Errorf("expected piped bucket with one bound, got %#v", b)
+	}
+
+	expectParseError(t, `employees | bucket(.salary, [])`, "bucket bounds must be numbers")
+	expectParseError(t, `employees | bucket(.salary, ["a"])`, "bucket bounds must be numbers")
+	expectParseError(t, `employees | bucket(.salary, 1, 2)`, "expected [")
+	expectParseError(t, `employees | bucket(.salary, [1 2])`, "expected ,")
+}
+
+func TestParseIDs(t *testing.T) {
+	node := mustParse(t, `reports(self) | where(.employment_type == "FULL_TIME") | ids`)
+	if _, ok := node.(*PipeExpr).Steps[2].(*IDsExpr); !ok {
+		t.Fatalf("expected *IDsExpr, got %T", node.(*PipeExpr).Steps[2])
+	}
+	if _, ok := mustParse(t, `employees | where(.ids == 1)`).(*PipeExpr).Steps[1].(*WhereExpr); !ok {
+		t.Error("expected .ids to stay a field access")
+	}
+}
+
+func TestParseQuantifierPredicateArg(t *testing.T) {
+	node := mustParse(t, `employees | where(all(reports(., 1), .employment_type == "FULL_TIME" or .employment_type == "PART_TIME"))`)
+	where := node.(*PipeExpr).Steps[1].(*WhereExpr)
+	fn, ok := where.Cond.(*FuncCall)
+	if !ok || fn.Name != "all" {
+		t.Fatalf("expected all() call, got %T", where.Cond)
+	}
+	if pred, ok := fn.Args[1].(*BinaryOp); !ok || pred.Op != "or" {
+		t.Fatalf("expected or predicate, got %T", fn.Args[1])
+	}
+}
+
+func TestParseNamedArg(t *testing.T) {
+	node := mustParse(t, `reports(self, 2, via: .dotted_manager)`)
+	fn, ok := node.(*FuncCall)
+	if !ok {
+		t.Fatalf("expected FuncCall, got %T", node)
+	}
+	if len(fn.Args) != 2 {
+		t.Fatalf("expected 2 positional args, got %d", len(fn.Args))
+	}
+	via, ok := fn.Named["via"].(*FieldAccess)
+	if !ok || via.Chain[0] != "dotted_manager" {
+		t.Fatalf("expected via: .dotted_manager, got %#v", fn.Named["via"])
+	}
+
+	for _, input := range []string{
+		`colleagues(self, .department, via: .manager)`,
+		`reports(self, via: .manager, 1)`,
+		`reports(self, via: .manager, via: .manager)`,
+	} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("%s: expected error", input)
+		}
+	}
+}
+
+func TestParseRelation(t *testing.T) {
+	node := mustParse(t, `departments | where(employees(.) | count > 50)`)
+	cmp := node.(*PipeExpr).Steps[1].(*WhereExpr).Cond.(*BinaryOp)
+	rel, ok := cmp.Left.(*PipeExpr).Steps[0].(*RelationExpr)
+	if !ok || rel.Object != "employees" || rel.Via != nil {
+		t.Fatalf("expected employees(.) relation, got %#v", cmp.Left.(*PipeExpr).Steps[0])
+	}
+
+	node = mustParse(t, `departments | where(projects(., via: .owner_department) | count > 0)`)
+	cmp = node.(*PipeExpr).Steps[1].(*WhereExpr).Cond.(*BinaryOp)
+	rel = cmp.Left.(*PipeExpr).Steps[0].(*RelationExpr)
+	if rel.Object != "projects" || rel.Via == nil || rel.Via.Chain[0] != "owner_department" {
+		t.Fatalf("expected projects(., via: .owner_department), got %#v", rel)
+	}
+
+	expectParseError(t, `projects(self)`, `unknown function "projects"`)
+	expectParseError(t, `projects(.name)`, `unknown function "projects"`)
+	expectParseError(t, `employees(., .manager)`, "expected 'via'")
+	expectParseError(t, `employees(., via: 1)`, "expected a field")
+}
+
+func TestWalk(t *testing.T) {
+	node := mustParse(t, `reports(self, 1) | where(all(reports(., 1), .employment_type == "FULL_TIME")) | sort_by(.start_date) | first`)
+	var funcs []string
+	Walk(node, func(n Node) {
+		switch n := n.(type) {
+		case *FuncCall:
+			funcs = append(funcs, n.Name)
+		case *WhereExpr:
+			funcs = append(funcs, "where")
+		case *SortExpr:
+			funcs = append(funcs, "sort_by")
+		case *PickExpr:
+			funcs = append(funcs, n.Op)
+		}
+	})
+	want := []string{"reports", "where", "all", "reports", "sort_by", "first"}
+	if !slices.Equal(funcs, want) {
+		t.Fatalf("expected %v, got %v", want, funcs)
+	}
+}
@@ -6,31 +6,37 @@ import "fmt"
 type TokenKind int
 
 const (
-	TokEOF    TokenKind = iota
-	TokPipe             // |
-	TokDot              // .
-	TokLParen           // (
-	TokRParen           // )
-	TokComma            // ,
-	TokEq               // ==
-	TokNeq              // !=
-	TokGt               // >
-	TokGte              // >=
-	TokLt               // <
-	TokLte              // <=
-	TokPlus             // +
-	TokMinus            // -
-	TokStar             // *
-	TokSlash            // /
-	TokIdent            // identifier
-	TokString           // "string literal"
-	TokNumber           // 42, 3.14
-	TokTrue             // true
-	TokFalse            // false
-	TokAnd              // and
-	TokOr               // or
-	TokAsc              // asc
-	TokDesc             // desc
+	TokEOF      TokenKind = iota
+	TokPipe               // |
+	TokDot                // .
+	TokLParen             // (
+	TokRParen             // )
+	TokComma              // ,
+	TokColon              // :
+	TokEq                 // ==
+	TokNeq                // !=
+	TokGt                 // >
+	TokGte                // >=
+	TokLt                 // <
+	TokLte                // <=
+	TokPlus               // +
+	TokMinus              // -
+	TokStar               // *
+	TokSlash              // /
+	TokIdent              // identifier
+	TokString             // "string literal"
+	TokNumber             // 42, 3.14
+	TokDuration           // 2y, 6mo, 3w, 90d
+	TokTrue               // true
+	TokFalse              // false
+	TokAnd                // and
+	TokOr                 // or
+	TokAsc                // asc
+	TokDesc               // desc
+	TokCoalesce           // ??
+	TokIn                 // in
+	TokLBracket           // [
+	TokRBracket           // ]
 )
 
 // Token is a single lexical token produced by the lexer.
@@ -48,31 +54,37 @@ func (t Token) String() string {
 }
 
 var kindNames = map[TokenKind]string{
-	TokEOF:    "EOF",
-	TokPipe:   "|",
-	TokDot:    ".",
-	TokLParen: "(",
-	TokRParen: ")",
-	TokComma:  ",",
-	TokEq:     "==",
-	TokNeq:    "!=",
-	TokGt:     ">",
-	TokGte:    ">=",
-	TokLt:     "<",
-	TokLte:    "<=",
-	TokPlus:   "+",
-	TokMinus:  "-",
-	TokStar:   "*",
-	TokSlash:  "/",
-	TokIdent:  "identifier",
-	TokString: "string",
-	TokNumber: "number",
-	TokTrue:   "true",
-	TokFalse:  "false",
-	TokAnd:    "and",
-	TokOr:     "or",
-	TokAsc:    "asc",
-	TokDesc:   "desc",
+	TokEOF:      "EOF",
+	TokPipe:     "|",
+	TokDot:      ".",
+	TokLParen:   "(",
+	TokRParen:   ")",
+	TokComma:    ",",
+	TokColon:    ":",
+	TokEq:       "==",
+	TokNeq:      "!=",
+	TokGt:       ">",
+	TokGte:      ">=",
+	TokLt:       "<",
+	TokLte:      "<=",
+	TokPlus:     "+",
+	TokMinus:    "-",
+	TokStar:     "*",
+	TokSlash:    "/",
+	TokIdent:    "identifier",
+	TokString:   "string",
+	TokNumber:   "number",
+	TokDuration: "duration",
+	TokTrue:     "true",
+	TokFalse:    "false",
+	TokAnd:      "and",
+	TokOr:       "or",
+	TokAsc:      "asc",
+	TokDesc:     "desc",
+	TokCoalesce: "??",
+	TokIn:       "in",
+	TokLBracket: "[",
+	TokRBracket: "]",
 }
 
 func (k TokenKind) String() string {
@@ -82,11 +94,45 @@ func (k TokenKind) String() string {
 	return fmt.Sprintf("TokenKind(%d)", int(k))
 }
 
+// DurationUnits maps the suffixes of duration literals to Postgres interval
+// units: 2y is "2 years".
+var DurationUnits = map[string]string{
+	"y":  "years",
+	"mo": "months",
+	"w":  "weeks",
+	"d":  "days",
+}
+
 var keywords = map[string]TokenKind{
 	"true":  TokTrue,
 	"false": TokFalse,
 	"and":   TokAnd,
 	"or":    TokOr,
+	"in":    TokIn,
 	"asc":   TokAsc,
 	"desc":  TokDesc,
 }
+
+// contextualWords are identifiers the lexer emits as TokIdent but the parser
+// treats specially: pronouns, the employees source and special-syntax steps.
+var contextualWords = []string{
+	"self", "employees",
+	"where", "sort_by", "first", "last", "nth", "min_by", "max_by",
+	"count", "sum", "avg", "min", "max", "bucket", "ids",
+	"case", "when", "then", "else",
+}
+
+// ReservedWords returns every word with a meaning in HRQL: keywords, contextual
+// words and registered function names. Schema api_names must avoid them so
+// fields stay addressable from queries.
+func ReservedWords() []string {
+	words := make([]string, 0, len(keywords)+len(contextualWords)+len(Functions))
+	for w := range keywords {
+		words = append(words, w)
+	}
+	words = append(words, contextualWords...)
+	for name := range Functions {
+		words = append(words, name)
+	}
+	return words
+}
@@ -12,16 +12,31 @@ import (
 // Builder generates SQL queries for a given object definition.
 type Builder interface {
 	BuildList(params *QueryParams) (string, []any, error)
+	BuildIDs(params *QueryParams) (string, []any, error)
 	BuildGetByID(id uuid.UUID, params *QueryParams) (string, []any, error)
 	BuildCount(params *QueryParams) (string, []any, error)
 	// BuildEstimate returns SELECT 1 FROM ... WHERE ... for use with EXPLAIN (FORMAT JSON).
 	BuildEstimate(params *QueryParams) (string, []any, error)
+
+	BuildInsert(values map[string]any) (string, []any, error)
+	// BuildUpsert returns INSERT ... ON CONFLICT on the external ID field, RETURNING id and inserted.
+	BuildUpsert(values map[string]any, externalID string) (string, []any, error)
+	BuildUpdate(id uuid.UUID, values map[string]any, expectedVersion int64) (string, []any, error)
+	// BuildUpdateIDs applies one patch to several records, RETURNING the id
+	// of each one updated.
+	BuildUpdateIDs(ids []uuid.UUID, values map[string]any) (string, []any, error)
+	BuildDelete(id uuid.UUID, expectedVersion int64) (string, []any, error)
+	BuildVersion(id uuid.UUID) (string, []any, error)
+	// BuildDocumentSize and BuildDocumentStats measure record documents
+	// against the object's data limits (see schema.DataLimits).
+	BuildDocumentSize(id uuid.UUID) (string, []any, error)
+	BuildDocumentStats() (string, []any, error)
 }
 
-// isSystemField returns true for system fields (id, created_at, updated_at)
+// isSystemField returns true for system fields (id, created_at, updated_at, version)
 // that are always emitted by jsonObject and should be skipped in the field loop.
 func isSystemField(apiName string) bool {
-	return apiName == "id" || apiName == "created_at" || apiName == "updated_at"
+	return schema.IsSystemField(apiName)
 }
 
 // QueryBuilder builds SQL for both standard and custom objects.
@@ -38,45 +53,63 @@ func NewBuilder(obj *schema.ObjectDef) Builder {
 
 func (b *QueryBuilder) BuildList(params *QueryParams) (string, []any, error) {
 	expandSet := makeExpandSet(params.ExpandPlans)
-	jsonExpr := buildJsonObject(b.obj, params, expandSet)
+	jsonExpr, jsonArgs := buildJsonObject(b.obj, params, expandSet)
+
+	qb := sq.Select().Column(sq.Expr(jsonExpr+" AS _row", jsonArgs...))
+	qb = addLateralJoins(b.page(qb, params), params)
+	return qb.ToSql()
+}
+
+// BuildIDs is BuildList without the records: each row holds _cursor_id and,
+// when ordered, _cursor_val. Expands and computed columns are not built.
+func (b *QueryBuilder) BuildIDs(params *QueryParams) (string, []any, error) {
+	return b.page(sq.Select(), params).ToSql()
+}
 
-	columns := []string{jsonExpr + " AS _row"}
-	columns = append(columns, fmt.Sprintf(`%s."id"::text AS _cursor_id`, QI(qAlias)))
+// page adds the cursor columns, source, conditions, order, cursor and limit
+// of a list page to qb.
+func (b *QueryBuilder) page(qb sq.SelectBuilder, params *QueryParams) sq.SelectBuilder {
+	columns := []string{fmt.Sprintf(`%s."id"::text AS _cursor_id`, QI(qAlias))}
 	if params.Order != nil {
 		fd := b.obj.FieldsByAPIName[params.Order.FieldAPIName]
 		if fd != nil {
-			col := FilterExpr(qAlias, fd)
+			col := sortExpr(qAlias, fd)
 			columns = append(columns, fmt.Sprintf(`%s::text AS _cursor_val`, col))
 		}
 	}
 
 	from, baseWhere := TableSource(b.obj, qAlias)
-	qb := sq.Select(columns...).From(from).PlaceholderFormat(sq.Dollar)
+	if params.SamplePercent > 0 {
+		from += tableSample(params.SamplePercent)
+	}
+	qb = qb.Columns(columns...).From(from).PlaceholderFormat(sq.Dollar)
 	if baseWhere != nil {
 		qb = qb.Where(baseWhere)
 	}
 
-	qb = addLateralJoins(qb, params)
 	for _, cond := range params.SQLConditions {
 		qb = qb.Where(cond)
 	}
+	if part := params.Partition(); part != nil {
+		qb = qb.Where(part.Condition())
+	}
 	for _, clause := range buildOrderBy(b.obj, params) {
 		qb = qb.OrderBy(clause)
 	}
 	qb = applyCursor(qb, b.obj, params)
 	qb = qb.Suffix("LIMIT ?", params.Limit+1)
-
-	return qb.ToSql()
+	if params.Lock {
+		qb = qb.Suffix("FOR UPDATE OF " + QI(qAlias))
+	}
+	return qb
 }
 
 func (b *QueryBuilder) BuildGetByID(id uuid.UUID, params *QueryParams) (string, []any, error) {
 	expandSet := makeExpandSet(params.ExpandPlans)
-	jsonExpr := buildJsonObject(b.obj, params, expandSet)
-
-	columns := []string{jsonExpr + " AS _row"}
+	jsonExpr, jsonArgs := buildJsonObject(b.obj, params, expandSet)
 
 	from, baseWhere := TableSource(b.obj, qAlias)
-	qb := sq.Select(columns...).
+	qb := sq.Select().Column(sq.Expr(jsonExpr+" AS _row", jsonArgs...)).
 		From(from).
 		Where(sq.Eq{QI(qAlias) + `."id"`: id}).
 		PlaceholderFormat(sq.Dollar).
@@ -99,6 +132,9 @@ func (b *QueryBuilder) BuildCount(params *QueryParams) (string, []any, error) {
 	for _, cond := range params.SQLConditions {
 		qb = qb.Where(cond)
 	}
+	if part := params.Partition(); part != nil {
+		qb = qb.Where(part.Condition())
+	}
 	return qb.ToSql()
 }
 
@@ -111,31 +147,64 @@ func (b *QueryBuilder) BuildEstimate(params *QueryParams) (string, []any, error)
 	for _, cond := range params.SQLConditions {
 		qb = qb.Where(cond)
 	}
+	if part := params.Partition(); part != nil {
+		qb = qb.Where(part.Condition())
+	}
 	return qb.ToSql()
 }
 
 // buildJsonObject builds a json_build_object(...) expression for the SELECT clause.
-func buildJsonObject(obj *schema.ObjectDef, params *QueryParams, expandSet map[string]*ExpandPlan) string {
-	var pairs []string
+// Args are only returned for params.Computed values and RelatedCounts.
+func buildJsonObject(obj *schema.ObjectDef, params *QueryParams, expandSet map[string]*ExpandPlan) (string, []any) {
+	var (
+		pairs []string
+		args  []any
+	)
 	pairs = append(pairs,
 		fmt.Sprintf(`'id', %s."id"`, QI(qAlias)),
 		fmt.Sprintf(`'created_at', %s."created_at"`, QI(qAlias)),
 		fmt.Sprintf(`'updated_at', %s."updated_at"`, QI(qAlias)),
+		fmt.Sprintf(`'version', %s."version"`, QI(qAlias)),
 	)
 
 	for _, f := range resolveFields(obj, params, expandSet) {
 		if isSystemField(f.APIName) {
 			continue
 		}
-		if ep, ok := expandSet[f.APIName]; ok {
+		if ep, ok := expandSet[f.APIName]; ok && params.ExpandStrategy == ExpandBatch {
+			pairs = append(pairs, fmt.Sprintf(`%s, %s`, QuoteLit(f.APIName), FKRef(qAlias, f)))
+		} else if ok {
 			alias := expandAlias(ep.FieldName)
 			pairs = append(pairs, fmt.Sprintf(`%s, %s`, QuoteLit(f.APIName), expandExpr(alias)))
 		} else {
 			pairs = append(pairs, fmt.Sprintf(`%s, %s`, QuoteLit(jsonKey(f)), SelectFieldExpr(qAlias, f)))
 		}
+		if f.LabelField() != "" {
+			pairs = append(pairs, fmt.Sprintf(`%s, %s`, QuoteLit(LabelKey(f)), labelSelectExpr(obj, qAlias, f)))
+		}
 	}
 
-	return fmt.Sprintf("json_build_object(%s)", strings.Join(pairs, ", "))
+	// Reverse expands are not fields of obj; with ExpandBatch they are
+	// stitched in after the query.
+	if params.ExpandStrategy != ExpandBatch {
+		for _, ep := range params.ExpandPlans {
+			if ep.Reverse {
+				pairs = append(pairs, fmt.Sprintf(`%s, %s.%s`, QuoteLit(ep.FieldName), QI(expandAlias(ep.FieldName)), QI(reverseRecordsKey)))
+			}
+		}
+	}
+
+	for _, c := range params.Computed {
+		pairs = append(pairs, fmt.Sprintf(`%s, %s`, QuoteLit(c.Key), c.SQL))
+		args = append(args, c.Args...)
+	}
+	for _, rc := range params.RelatedCounts {
+		sql, rcArgs := relatedCountSQL(obj, rc)
+		pairs = append(pairs, fmt.Sprintf(`%s, %s`, QuoteLit(rc.Key), sql))
+		args = append(args, rcArgs...)
+	}
+
+	return fmt.Sprintf("json_build_object(%s)", strings.Join(pairs, ", ")), args
 }
 
 // resolveFields returns which fields to include. Expanded fields are always included.
@@ -168,8 +237,16 @@ func resolveFields(obj *schema.ObjectDef, params *QueryParams, expandSet map[str
 }
 
 func addLateralJoins(qb sq.SelectBuilder, params *QueryParams) sq.SelectBuilder {
+	if params.ExpandStrategy == ExpandBatch {
+		return qb
+	}
 	for i := range params.ExpandPlans {
 		ep := &params.ExpandPlans[i]
+		if ep.Reverse {
+			joinSQL, joinArgs := buildReverseLateral(ep, fmt.Sprintf(`%s."id"`, QI(qAlias)))
+			qb = qb.LeftJoin(joinSQL, joinArgs...)
+			continue
+		}
 		outerRef := FKRef(qAlias, ep.Field)
 		joinSQL, joinArgs := buildLateral(ep, outerRef, "", 0)
 		qb = qb.LeftJoin(joinSQL, joinArgs...)
@@ -178,6 +255,10 @@ func addLateralJoins(qb sq.SelectBuilder, params *QueryParams) sq.SelectBuilder
 }
 
 func buildOrderBy(obj *schema.ObjectDef, params *QueryParams) []string {
+	if params.Random {
+		return []string{"random()"}
+	}
+
 	var (
 		clauses []string
 		dir     = orderDir(params)
@@ -185,7 +266,12 @@ func buildOrderBy(obj *schema.ObjectDef, params *QueryParams) []string {
 
 	if params.Order != nil {
 		if fd := obj.FieldsByAPIName[params.Order.FieldAPIName]; fd != nil {
-			clauses = append(clauses, fmt.Sprintf(`%s %s`, FilterExpr(qAlias, fd), dir))
+			col := sortExpr(qAlias, fd)
+			if key, _ := nullsKey(col, fd, params.Order); key != "" {
+				clauses = append(clauses, fmt.Sprintf(`%s %s`, key, dir), fmt.Sprintf(`%s %s`, col, dir))
+			} else {
+				clauses = append(clauses, fmt.Sprintf(`%s %s %s`, col, dir, nullsOrder(params.Order)))
+			}
 		}
 	}
 
@@ -200,26 +286,58 @@ func orderDir(params *QueryParams) string {
 	return "ASC"
 }
 
+// nullsOrder places the NULLs of an ordered field: last unless the order
+// asks for them first, whatever the direction.
+func nullsOrder(o *OrderClause) string {
+	if o != nil && o.NullsFirst {
+		return "NULLS FIRST"
+	}
+	return "NULLS LAST"
+}
+
+// applyCursor resumes an ordered page after the cursor's row. Document fields
+// are ordered by their nulls key first, so a single row comparison over
+// (key, value, id) resumes them and matches their sort indexes; values only
+// meet in it when both are set. On storage columns row comparison alone would
+// drop every NULL, so they are placed explicitly: after the last value when
+// nulls sort last, before the first when they sort first.
 func applyCursor(qb sq.SelectBuilder, obj *schema.ObjectDef, params *QueryParams) sq.SelectBuilder {
-	if params.Cursor == nil {
+	c := params.Cursor
+	if c == nil {
 		return qb
 	}
 	idCol := fmt.Sprintf(`%s."id"`, QI(qAlias))
 
-	if params.Order != nil && params.Cursor.OrderVal != "" {
+	if params.Order != nil && (c.OrderVal != "" || c.Null || c.OrderField != "") {
 		fd := obj.FieldsByAPIName[params.Order.FieldAPIName]
 		if fd != nil {
-			sortCol := FilterExpr(qAlias, fd)
+			sortCol := sortExpr(qAlias, fd)
 			cmp := ">"
 			if params.Order.Desc {
 				cmp = "<"
 			}
-			qb = qb.Where(fmt.Sprintf(`(%s, %s) %s (?, ?)`, sortCol, idCol, cmp),
-				params.Cursor.OrderVal, params.Cursor.ID)
+			if key, isNull := nullsKey(sortCol, fd, params.Order); key != "" {
+				keyVal := c.Null == isNull
+				if c.Null {
+					return qb.Where(fmt.Sprintf(`(%s, %s) %s (?, ?)`, key, idCol, cmp), keyVal, c.ID)
+				}
+				return qb.Where(fmt.Sprintf(`(%s, %s, %s) %s (?, ?, ?)`, key, sortCol, idCol, cmp), keyVal, c.OrderVal, c.ID)
+			}
+			nullsFirst := params.Order.NullsFirst
+			switch {
+			case c.Null && nullsFirst:
+				qb = qb.Where(fmt.Sprintf(`((%[1]s IS NULL AND %[2]s %[3]s ?) OR %[1]s IS NOT NULL)`, sortCol, idCol, cmp), c.ID)
+			case c.Null:
+				qb = qb.Where(fmt.Sprintf(`(%s IS NULL AND %s %s ?)`, sortCol, idCol, cmp), c.ID)
+			case nullsFirst:
+				qb = qb.Where(fmt.Sprintf(`(%s, %s) %s (?, ?)`, sortCol, idCol, cmp), c.OrderVal, c.ID)
+			default:
+				qb = qb.Where(fmt.Sprintf(`((%[1]s, %[2]s) %[3]s (?, ?) OR %[1]s IS NULL)`, sortCol, idCol, cmp), c.OrderVal, c.ID)
+			}
 			return qb
 		}
 	}
 
-	qb = qb.Where(sq.Gt{idCol: params.Cursor.ID})
+	qb = qb.Where(sq.Gt{idCol: c.ID})
 	return qb
 }
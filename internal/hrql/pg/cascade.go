@@ -0,0 +1,39 @@
+package pg
+
+import (
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// BuildReferenceCount returns a query counting the records of obj whose
+// LOOKUP field fd is set.
+func BuildReferenceCount(obj *schema.ObjectDef, fd *schema.FieldDef) (string, []any, error) {
+	return NewBuilder(obj).BuildCount(&QueryParams{
+		SQLConditions: []sq.Sqlizer{sq.Expr(FKRef(qAlias, fd) + " IS NOT NULL")},
+	})
+}
+
+// BuildNullifyLookup returns the statement clearing LOOKUP field fd on every
+// record of obj that sets it, as DeleteObject's "nullify" cascade does before
+// dropping the field. Document values lose their key; storage columns are
+// set to NULL.
+func BuildNullifyLookup(obj *schema.ObjectDef, fd *schema.FieldDef) (string, []any, error) {
+	if fd.Type != schema.FieldLookup {
+		return "", nil, fmt.Errorf("field %q is not a LOOKUP", fd.APIName)
+	}
+	table, base := TableSource(obj, qAlias)
+	ub := sq.Update(table).PlaceholderFormat(sq.Dollar)
+	if fd.StorageColumn != nil {
+		ub = ub.Set(QI(*fd.StorageColumn), nil)
+	} else {
+		doc := QI(obj.DocumentColumn())
+		ub = ub.Set(doc, sq.Expr(doc+" - ?::text", fd.APIName))
+	}
+	ub = ub.Where(FKRef(qAlias, fd) + " IS NOT NULL")
+	if base != nil {
+		ub = ub.Where(base)
+	}
+	return ub.ToSql()
+}
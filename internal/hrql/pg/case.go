@@ -0,0 +1,72 @@
+package pg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/atlekbai/schema_registry/internal/hrql"
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// CaseKey is the JSON key under which a projected case(...) value is added to each record.
+const CaseKey = "case"
+
+// ComputedColumn is an extra key added to each record's JSON, computed by a
+// SQL expression with ? placeholders.
+type ComputedColumn struct {
+	Key  string
+	SQL  string
+	Args []any
+}
+
+// CaseToSQL renders a compiled case(...) as a SQL CASE expression. Numeric
+// cases yield numeric values so they can be summed; the rest yield text.
+func CaseToSQL(c *hrql.Case, obj *schema.ObjectDef, cache *schema.Cache) (string, []any, error) {
+	var (
+		b    strings.Builder
+		args []any
+	)
+	b.WriteString("CASE")
+	for _, w := range c.Whens {
+		cond, err := ConditionToSQL(w.Cond, obj, cache)
+		if err != nil {
+			return "", nil, err
+		}
+		condSQL, condArgs, err := cond.ToSql()
+		if err != nil {
+			return "", nil, err
+		}
+		val, valArgs, err := caseValueToSQL(w.Then, obj, c.Numeric)
+		if err != nil {
+			return "", nil, err
+		}
+		fmt.Fprintf(&b, " WHEN %s THEN %s", condSQL, val)
+		args = append(args, condArgs...)
+		args = append(args, valArgs...)
+	}
+	if c.Else != nil {
+		val, valArgs, err := caseValueToSQL(*c.Else, obj, c.Numeric)
+		if err != nil {
+			return "", nil, err
+		}
+		fmt.Fprintf(&b, " ELSE %s", val)
+		args = append(args, valArgs...)
+	}
+	b.WriteString(" END")
+	return b.String(), args, nil
+}
+
+func caseValueToSQL(v hrql.CaseValue, obj *schema.ObjectDef, numeric bool) (string, []any, error) {
+	cast := "::text"
+	if numeric {
+		cast = "::numeric"
+	}
+	if v.Field == "" {
+		return "?" + cast, []any{v.Literal}, nil
+	}
+	fd := obj.FieldsByAPIName[v.Field]
+	if fd == nil {
+		return "", nil, fmt.Errorf("case: unknown field %q", v.Field)
+	}
+	return fmt.Sprintf("(%s)%s", FilterExpr(qAlias, fd), cast), nil, nil
+}
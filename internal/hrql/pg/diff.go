@@ -0,0 +1,101 @@
+package pg
+
+import (
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+const (
+	diffFromAlias = "_a"
+	diffToAlias   = "_b"
+)
+
+// DiffRow is one employee whose reporting line differs between two
+// snapshots. The From*/To* pointers are nil where the employee or the value
+// is absent.
+type DiffRow struct {
+	ID             string
+	Existed        bool // present in the from snapshot
+	Exists         bool // present in the to snapshot
+	FromManager    *string
+	ToManager      *string
+	FromDepartment *string
+	ToDepartment   *string
+	// Left is set when end_date passed between the snapshots while the
+	// record stayed.
+	Left    bool
+	Display *string
+}
+
+// ScanDest returns the scan destinations matching BuildOrgDiff's columns.
+func (r *DiffRow) ScanDest() []any {
+	return []any{&r.ID, &r.Existed, &r.Exists, &r.FromManager, &r.ToManager,
+		&r.FromDepartment, &r.ToDepartment, &r.Left, &r.Display}
+}
+
+// BuildOrgDiff returns a query comparing employees as of from and as of to
+// (see AsOf): one DiffRow per employee hired, gone, past their end_date, or
+// with a different manager or department, ordered by id. With rootID set,
+// only employees under that record's path in either snapshot (the record
+// included) are compared. At most limit rows are returned.
+func BuildOrgDiff(obj *schema.ObjectDef, from, to time.Time, rootID string, limit int) (string, []any, error) {
+	manager, dept, endDate := obj.Hierarchy(""), obj.FieldsByAPIName["department"], obj.FieldsByAPIName["end_date"]
+	if manager == nil || dept == nil || endDate == nil {
+		return "", nil, fmt.Errorf("object %q has no reporting hierarchy", obj.APIName)
+	}
+	before, err := AsOf(obj, from)
+	if err != nil {
+		return "", nil, err
+	}
+	after, err := AsOf(obj, to)
+	if err != nil {
+		return "", nil, err
+	}
+
+	a, b := QI(diffFromAlias), QI(diffToAlias)
+	ref := func(alias string, fd *schema.FieldDef) string { return FKRef(alias, fd) + "::text" }
+	// Gone by end_date: set and reached at to, but not yet at from.
+	left := fmt.Sprintf(`(%[1]s IS NOT NULL AND %[1]s <= ?::date AND (%[2]s IS NULL OR %[2]s > ?::date))`,
+		FilterExpr(diffToAlias, endDate), FilterExpr(diffFromAlias, endDate))
+	fromDate, toDate := from.UTC().Format(time.DateOnly), to.UTC().Format(time.DateOnly)
+
+	display := "NULL::text"
+	if d := DisplayExpr(after, diffToAlias); d != "" {
+		display = fmt.Sprintf(`COALESCE(%s, %s)`, d, DisplayExpr(before, diffFromAlias))
+	}
+
+	qb := sq.Select(
+		fmt.Sprintf(`COALESCE(%s."id", %s."id")::text`, b, a),
+		a+`."id" IS NOT NULL`,
+		b+`."id" IS NOT NULL`,
+		ref(diffFromAlias, manager), ref(diffToAlias, manager),
+		ref(diffFromAlias, dept), ref(diffToAlias, dept),
+	).
+		Column(sq.Expr(fmt.Sprintf(`COALESCE(%s, false)`, left), toDate, fromDate)).
+		Column(display).
+		From(fmt.Sprintf(`%s %s FULL JOIN %s %s ON %s."id" = %s."id"`,
+			before.TableName(), a, after.TableName(), b, a, b)).
+		Where(sq.Or{
+			sq.Expr(a + `."id" IS NULL`),
+			sq.Expr(b + `."id" IS NULL`),
+			sq.Expr(fmt.Sprintf(`%s IS DISTINCT FROM %s`, FKRef(diffFromAlias, manager), FKRef(diffToAlias, manager))),
+			sq.Expr(fmt.Sprintf(`%s IS DISTINCT FROM %s`, FKRef(diffFromAlias, dept), FKRef(diffToAlias, dept))),
+			sq.Expr(left, toDate, fromDate),
+		}).
+		OrderBy(fmt.Sprintf(`COALESCE(%s."id", %s."id")`, b, a)).
+		Limit(uint64(limit)).
+		PlaceholderFormat(sq.Dollar)
+
+	if rootID != "" {
+		path := QI(manager.PathColumn)
+		under := func(alias string, snapshot *schema.ObjectDef) sq.Sqlizer {
+			return sq.Expr(fmt.Sprintf(`%s.%s <@ (SELECT "_r".%s FROM %s "_r" WHERE "_r"."id" = ?)`,
+				QI(alias), path, path, snapshot.TableName()), rootID)
+		}
+		qb = qb.Where(sq.Or{under(diffFromAlias, before), under(diffToAlias, after)})
+	}
+	return qb.ToSql()
+}
@@ -0,0 +1,102 @@
+package pg
+
+import (
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// DisplayKey is the key under which expanded records carry their display
+// name. The leading underscore keeps it clear of field api_names.
+const DisplayKey = "_display"
+
+// DisplayExpr returns the SQL rendering obj's display template for the row
+// aliased alias, or "" when obj has none. Null fields render empty and a
+// name that is blank after trimming is NULL.
+func DisplayExpr(obj *schema.ObjectDef, alias string) string {
+	parts := obj.DisplayParts()
+	if len(parts) == 0 {
+		return ""
+	}
+	args := make([]string, len(parts))
+	for i, p := range parts {
+		if p.Field == "" {
+			args[i] = textLit(p.Text)
+			continue
+		}
+		args[i] = fmt.Sprintf(`(%s)::text`, FilterExpr(alias, obj.FieldsByAPIName[p.Field]))
+	}
+	return fmt.Sprintf(`NULLIF(btrim(concat(%s)), '')`, strings.Join(args, ", "))
+}
+
+// textLit quotes s as a SQL string literal. Unlike QuoteLit it escapes
+// quotes, for text taken from metadata rather than identifiers.
+func textLit(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// likeEscaper escapes LIKE wildcards and the escape character.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// likePrefix returns the LIKE pattern matching strings that start with s.
+func likePrefix(s string) string {
+	return likeEscaper.Replace(s) + "%"
+}
+
+// likeContains returns the LIKE pattern matching strings that contain s.
+func likeContains(s string) string {
+	return "%" + likeEscaper.Replace(s) + "%"
+}
+
+// BuildTypeahead returns a query for up to limit records of obj whose display
+// name starts with prefix, case-insensitively, as (id, display) rows ordered
+// by display name.
+func BuildTypeahead(obj *schema.ObjectDef, prefix string, limit int) (string, []any, error) {
+	display := DisplayExpr(obj, qAlias)
+	if display == "" {
+		return "", nil, fmt.Errorf("object %q has no display template", obj.APIName)
+	}
+	idCol := fmt.Sprintf(`%s."id"`, QI(qAlias))
+
+	from, baseWhere := TableSource(obj, qAlias)
+	qb := sq.Select(idCol+"::text", display).From(from).PlaceholderFormat(sq.Dollar)
+	if baseWhere != nil {
+		qb = qb.Where(baseWhere)
+	}
+	return qb.Where(sq.Expr(display+" ILIKE ?", likePrefix(prefix))).
+		OrderBy(display, idCol).
+		Suffix("LIMIT ?", limit).
+		ToSql()
+}
+
+// LookupSimilarity is the pg_trgm word_similarity a display name needs to
+// match a lookup search it does not contain, e.g. "enginering" for
+// "Engineering".
+const LookupSimilarity = 0.4
+
+// BuildLookupSearch returns a query for up to limit records of obj matching
+// q for a lookup picker, as (id, display) rows. A display name matches when
+// it contains q, case-insensitively, or has a word similar to q. Matches
+// starting with q come first, then the most similar, then alphabetically.
+// An empty q lists records alphabetically.
+func BuildLookupSearch(obj *schema.ObjectDef, q string, limit int) (string, []any, error) {
+	display := DisplayExpr(obj, qAlias)
+	if display == "" {
+		return "", nil, fmt.Errorf("object %q has no display template", obj.APIName)
+	}
+	idCol := fmt.Sprintf(`%s."id"`, QI(qAlias))
+
+	from, baseWhere := TableSource(obj, qAlias)
+	qb := sq.Select(idCol+"::text", display).From(from).PlaceholderFormat(sq.Dollar)
+	if baseWhere != nil {
+		qb = qb.Where(baseWhere)
+	}
+	if q != "" {
+		qb = qb.Where(sq.Expr(fmt.Sprintf("(%s ILIKE ? OR word_similarity(?, %s) >= ?)", display, display), likeContains(q), q, LookupSimilarity)).
+			OrderByClause(display+" ILIKE ? DESC", likePrefix(q)).
+			OrderByClause(fmt.Sprintf("word_similarity(?, %s) DESC", display), q)
+	}
+	return qb.OrderBy(display, idCol).Suffix("LIMIT ?", limit).ToSql()
+}
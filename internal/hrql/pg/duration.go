@@ -0,0 +1,55 @@
+package pg
+
+import (
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/atlekbai/schema_registry/internal/hrql"
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// Durations are measured up to now(), also under as_of: the snapshot changes
+// which records match, not the reference time.
+
+// sinceSQL returns the whole years, months or days elapsed from the date col
+// to now.
+func sinceSQL(unit, col string) string {
+	return elapsedSQL(unit, col, "now()")
+}
+
+// elapsedSQL returns the whole units between the dates from and to. Years and
+// months follow age(), so 2024-01-31 to 2024-02-29 is less than a month.
+func elapsedSQL(unit, from, to string) string {
+	age := fmt.Sprintf(`age(%s, %s)`, to, from)
+	switch unit {
+	case "years":
+		return fmt.Sprintf(`EXTRACT(YEAR FROM %s)`, age)
+	case "months":
+		return fmt.Sprintf(`(EXTRACT(YEAR FROM %s) * 12 + EXTRACT(MONTH FROM %s))`, age, age)
+	default:
+		return fmt.Sprintf(`EXTRACT(DAY FROM (%s)::timestamptz - (%s)::timestamptz)`, to, from)
+	}
+}
+
+// durationCmpToSQL translates tenure(.from[, .to]) op interval and
+// .from | <unit>_since op n.
+func durationCmpToSQL(c hrql.DurationCmp, obj *schema.ObjectDef) (sq.Sqlizer, error) {
+	from := obj.FieldsByAPIName[c.From]
+	if from == nil {
+		return nil, fmt.Errorf("unknown field %q", c.From)
+	}
+	fromCol := FilterExpr(Alias(), from)
+	to := "now()"
+	if c.To != "" {
+		fd := obj.FieldsByAPIName[c.To]
+		if fd == nil {
+			return nil, fmt.Errorf("unknown field %q", c.To)
+		}
+		to = fmt.Sprintf(`COALESCE(%s, now())`, FilterExpr(Alias(), fd))
+	}
+
+	if c.Unit == "" {
+		return sq.Expr(fmt.Sprintf(`age(%s, %s) %s ?::interval`, to, fromCol, sqlOp(c.Op)), c.Value), nil
+	}
+	return sq.Expr(fmt.Sprintf(`%s %s ?::numeric`, elapsedSQL(c.Unit, fromCol, to), sqlOp(c.Op)), c.Value), nil
+}
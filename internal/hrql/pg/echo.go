@@ -0,0 +1,105 @@
+package pg
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/atlekbai/schema_registry/internal/hrql"
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// Echo is a list query as the server ran it, reported back to clients so
+// they can see defaults, caps and dropped parts of their request.
+type Echo struct {
+	Conditions []EchoCondition
+	Order      string // as ParseOrder reads it, "random", or "" for id order
+	Limit      int
+	Select     []string
+	Expand     []string // dotted paths of the applied expands
+	// Redacted lists the ENCRYPTED fields read without permission, as
+	// dotted paths for expanded records.
+	Redacted []string
+}
+
+// EchoCondition is one AND'd condition: its REST filter form (see
+// ParseFilterCondition) when it has one, its Go type name otherwise.
+type EchoCondition struct {
+	Field  string
+	Filter string
+	Kind   string
+}
+
+// EchoParams describes the query params will run against obj. reveal is
+// whether the caller may read ENCRYPTED values.
+func EchoParams(obj *schema.ObjectDef, params *QueryParams, reveal bool) Echo {
+	e := Echo{
+		Conditions: EchoConditions(params.Conditions),
+		Order:      orderClauseString(params.Order),
+		Limit:      params.Limit,
+		Select:     slices.Clone(params.Select),
+	}
+	if params.Random {
+		e.Order = "random"
+	}
+	var walk func(prefix string, plans []ExpandPlan)
+	walk = func(prefix string, plans []ExpandPlan) {
+		for _, ep := range plans {
+			path := prefix + ep.FieldName
+			e.Expand = append(e.Expand, path)
+			if !reveal {
+				e.Redacted = append(e.Redacted, redactedFields(ep.Target, ep.Select, path+".")...)
+			}
+			walk(path+".", ep.Children)
+		}
+	}
+	if !reveal {
+		e.Redacted = redactedFields(obj, params.Select, "")
+	}
+	walk("", params.ExpandPlans)
+	for _, rc := range params.RelatedCounts {
+		e.Expand = append(e.Expand, rc.Relation+":count")
+	}
+	return e
+}
+
+// redactedFields returns the ENCRYPTED fields of obj among selected (all
+// fields when empty), prefixed with prefix.
+func redactedFields(obj *schema.ObjectDef, selected []string, prefix string) []string {
+	var out []string
+	for i := range obj.Fields {
+		fd := &obj.Fields[i]
+		if fd.IsEncrypted() && (len(selected) == 0 || slices.Contains(selected, fd.APIName)) {
+			out = append(out, prefix+fd.APIName)
+		}
+	}
+	return out
+}
+
+// EchoConditions flattens AND'd conditions and sorts them by field and
+// filter, so equal queries echo equally however they were written.
+func EchoConditions(conds []hrql.Condition) []EchoCondition {
+	var out []EchoCondition
+	var add func(c hrql.Condition)
+	add = func(c hrql.Condition) {
+		if and, ok := c.(hrql.AndCond); ok {
+			add(and.Left)
+			add(and.Right)
+			return
+		}
+		field, filter, err := conditionToFilter(c)
+		if err != nil {
+			out = append(out, EchoCondition{Kind: strings.TrimPrefix(fmt.Sprintf("%T", c), "hrql.")})
+			return
+		}
+		out = append(out, EchoCondition{Field: field, Filter: filter})
+	}
+	for _, c := range conds {
+		add(c)
+	}
+	slices.SortFunc(out, func(a, b EchoCondition) int {
+		return cmp.Or(strings.Compare(a.Field, b.Field), strings.Compare(a.Filter, b.Filter), strings.Compare(a.Kind, b.Kind))
+	})
+	return out
+}
@@ -0,0 +1,77 @@
+package pg
+
+import (
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// ExpandStrategy selects how expanded LOOKUP fields are fetched.
+type ExpandStrategy string
+
+const (
+	// ExpandAuto picks a strategy per query from the page size (see Resolve).
+	ExpandAuto ExpandStrategy = "auto"
+	// ExpandLateral embeds expands in the main query as one LATERAL subquery per row.
+	ExpandLateral ExpandStrategy = "lateral"
+	// ExpandBatch emits raw foreign keys in the main query and loads each expanded
+	// field with one `WHERE id = ANY(...)` query; the caller stitches the results in.
+	ExpandBatch ExpandStrategy = "batch"
+)
+
+// batchExpandMinRows is the page size from which ExpandAuto switches to ExpandBatch.
+// Below it the extra round-trips cost more than the per-row lateral lookups save.
+const batchExpandMinRows = 100
+
+// ParseExpandStrategy parses a configured strategy name. Empty means ExpandAuto.
+func ParseExpandStrategy(s string) (ExpandStrategy, error) {
+	switch ExpandStrategy(s) {
+	case "", ExpandAuto:
+		return ExpandAuto, nil
+	case ExpandLateral, ExpandBatch:
+		return ExpandStrategy(s), nil
+	default:
+		return "", fmt.Errorf("unknown expand strategy %q (want auto, lateral or batch)", s)
+	}
+}
+
+// Resolve returns the concrete strategy for a list query with the given params.
+func (s ExpandStrategy) Resolve(params *QueryParams) ExpandStrategy {
+	if s != ExpandAuto {
+		return s
+	}
+	if len(params.ExpandPlans) > 0 && params.Limit >= batchExpandMinRows {
+		return ExpandBatch
+	}
+	return ExpandLateral
+}
+
+// BuildExpandBatch returns a query loading the expanded records for ep whose ids
+// are in ids, one JSON object per row. The objects have the same shape the
+// LATERAL strategy embeds, including nested expands.
+func BuildExpandBatch(ep *ExpandPlan, ids []string) (string, []any, error) {
+	name := ep.FieldName
+	pred := fmt.Sprintf(`%s."id" = ANY(?::uuid[])`, QI(expandInner(name)))
+	inner, args := expandSelect(ep, name, 0, pred, []any{ids})
+
+	sql, err := sq.Dollar.ReplacePlaceholders(
+		fmt.Sprintf(`SELECT to_jsonb(%s.*) FROM (%s) %s`, QI(expandAlias(name)), inner, QI(expandAlias(name))))
+	if err != nil {
+		return "", nil, err
+	}
+	return sql, args, nil
+}
+
+// BuildReverseExpandBatch returns a query loading the Reverse expand ep for
+// the records whose ids are in ids: one row per id with the id and the JSON
+// array the LATERAL strategy embeds.
+func BuildReverseExpandBatch(ep *ExpandPlan, ids []string) (string, []any, error) {
+	lateral, args := buildReverseLateral(ep, `"_o"."id"`)
+	sql, err := sq.Dollar.ReplacePlaceholders(fmt.Sprintf(
+		`SELECT "_o"."id"::text, %s.%s FROM unnest(?::uuid[]) AS "_o"("id") LEFT JOIN %s`,
+		QI(expandAlias(ep.FieldName)), QI(reverseRecordsKey), lateral))
+	if err != nil {
+		return "", nil, err
+	}
+	return sql, concatArgs([]any{ids}, args), nil
+}
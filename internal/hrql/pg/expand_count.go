@@ -0,0 +1,96 @@
+package pg
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/atlekbai/schema_registry/internal/hrql"
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// ReportsRelation names an employee's reports in an inline count
+// (expand=reports:count).
+const ReportsRelation = "reports"
+
+// RelatedCount is an inline count of the records related to each row,
+// requested as expand=<relation>:count and returned under Key. The relation
+// is a reverse expand (Reverse set), or ReportsRelation on employees: the
+// row's reports at any depth along manager_path, as HRQL reports(.) counts
+// them.
+type RelatedCount struct {
+	Relation string
+	Key      string // Relation + "_count"
+	Reverse  *ExpandPlan
+	// Employed leaves out reports who have left (see Employed).
+	Employed bool
+}
+
+// parseRelatedCount resolves an expand entry of the form relation:agg.
+func parseRelatedCount(obj *schema.ObjectDef, entry string, input ParamsInput) (RelatedCount, error) {
+	relation, agg, _ := strings.Cut(entry, ":")
+	relation, agg = strings.TrimSpace(relation), strings.TrimSpace(agg)
+	if agg != "count" {
+		return RelatedCount{}, fmt.Errorf("expand %q: unsupported aggregate %q, expected count", entry, agg)
+	}
+	rc := RelatedCount{Relation: relation, Key: relation + "_count"}
+	if _, ok := obj.FieldsByAPIName[rc.Key]; ok {
+		return RelatedCount{}, fmt.Errorf("expand %q: %q is already a field of %s", entry, rc.Key, obj.APIName)
+	}
+	if input.Cache != nil {
+		rc.Reverse = reverseExpand(relation, obj, input.Cache)
+	}
+	switch {
+	case rc.Reverse != nil:
+	case relation == ReportsRelation && obj.APIName == "employees" && HierarchyPath(obj, "") != "":
+		rc.Employed = !input.IncludeTerminated && obj.FieldsByAPIName[hrql.EndDateField] != nil
+	default:
+		return RelatedCount{}, fmt.Errorf("expand %q: %q is not a relation of %s to count", entry, relation, obj.APIName)
+	}
+	return rc, nil
+}
+
+// addRelatedCount adds rc to p unless it is already there.
+func (p *QueryParams) addRelatedCount(rc RelatedCount) {
+	if !slices.ContainsFunc(p.RelatedCounts, func(c RelatedCount) bool { return c.Key == rc.Key }) {
+		p.RelatedCounts = append(p.RelatedCounts, rc)
+	}
+}
+
+// relatedCountSQL renders rc as a correlated count of obj's row under the
+// builder alias:
+//
+//	reverse: (SELECT count(*) FROM <related> "_r" WHERE <fk of _r> = "_e"."id")
+//	reports: (SELECT count(*) FROM <obj> "_r" WHERE "_r".mp <@ "_e".mp AND "_r".mp != "_e".mp)
+func relatedCountSQL(obj *schema.ObjectDef, rc RelatedCount) (string, []any) {
+	const inner = "_r"
+	target := obj
+	if rc.Reverse != nil {
+		target = rc.Reverse.Target
+	}
+	from, baseWhere := TableSource(target, inner)
+	var (
+		where []string
+		args  []any
+	)
+	if baseWhere != nil {
+		sql, a, _ := baseWhere.ToSql()
+		where = append(where, sql)
+		args = append(args, a...)
+	}
+	if rc.Reverse != nil {
+		where = append(where, fmt.Sprintf(`%s = %s."id"`, FKRef(inner, rc.Reverse.Field), QI(qAlias)))
+	} else {
+		path := QI(HierarchyPath(obj, ""))
+		where = append(where, fmt.Sprintf(`%[1]s.%[3]s <@ %[2]s.%[3]s AND %[1]s.%[3]s != %[2]s.%[3]s`, QI(inner), QI(qAlias), path))
+		if rc.Employed {
+			// Employed only fails for objects without end_date, which
+			// parseRelatedCount leaves unset.
+			cond, _ := Employed(inner, obj)
+			sql, a, _ := cond.ToSql()
+			where = append(where, sql)
+			args = append(args, a...)
+		}
+	}
+	return fmt.Sprintf(`(SELECT count(*) FROM %s WHERE %s)`, from, strings.Join(where, " AND ")), args
+}
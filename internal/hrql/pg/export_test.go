@@ -0,0 +1,3 @@
+package pg
+
+var UnregisterFilterOperator = unregisterFilterOperator
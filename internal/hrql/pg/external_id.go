@@ -0,0 +1,147 @@
+package pg
+
+import (
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// upsertAlias is the target table alias in upserts, so ON CONFLICT DO UPDATE
+// can refer to the stored row next to EXCLUDED.
+const upsertAlias = "_t"
+
+// ExternalIDIndexName returns the name of the unique index backing a custom
+// external ID field, e.g. "uq_external_id_0190...".
+func ExternalIDIndexName(fd *schema.FieldDef) string {
+	return "uq_external_id_" + strings.ReplaceAll(fd.ID.String(), "-", "")
+}
+
+// externalIDKey returns the JSONB document column and the key expression a
+// custom external ID field is indexed and conflict-checked on.
+func externalIDKey(obj *schema.ObjectDef, fd *schema.FieldDef) string {
+	col := `"data"`
+	if obj.IsStandard {
+		col = QI(customFieldsColumn)
+	}
+	return fmt.Sprintf(`(%s->>%s)`, col, QuoteLit(fd.APIName))
+}
+
+// conflictTarget returns the ON CONFLICT target matching the unique constraint
+// or index of an external ID field.
+func conflictTarget(obj *schema.ObjectDef, fd *schema.FieldDef) string {
+	if obj.IsStandard && fd.StorageColumn != nil {
+		return "(" + QI(*fd.StorageColumn) + ")"
+	}
+	if obj.IsStandard {
+		return "(" + externalIDKey(obj, fd) + ")"
+	}
+	return fmt.Sprintf(`(%s) WHERE "object_id" = %s::uuid`, externalIDKey(obj, fd), QuoteLit(obj.ID.String()))
+}
+
+// BuildExternalIDIndex returns the DDL creating the unique index for a custom
+// external ID field. Standard columns rely on their table's UNIQUE constraint,
+// so the result is empty for them.
+func BuildExternalIDIndex(obj *schema.ObjectDef, fd *schema.FieldDef) string {
+	if obj.IsStandard && fd.StorageColumn != nil {
+		return ""
+	}
+	if !obj.IsStandard {
+		return fmt.Sprintf(`CREATE UNIQUE INDEX %s ON "metadata"."records" (%s) WHERE "object_id" = %s::uuid`,
+			QI(ExternalIDIndexName(fd)), externalIDKey(obj, fd), QuoteLit(obj.ID.String()))
+	}
+	return fmt.Sprintf(`CREATE UNIQUE INDEX %s ON %s (%s)`,
+		QI(ExternalIDIndexName(fd)), obj.TableName(), externalIDKey(obj, fd))
+}
+
+// BuildDropExternalIDIndex returns the DDL dropping the index created by
+// BuildExternalIDIndex, or "" for standard columns.
+func BuildDropExternalIDIndex(obj *schema.ObjectDef, fd *schema.FieldDef) string {
+	if obj.IsStandard && fd.StorageColumn != nil {
+		return ""
+	}
+	indexSchema := "metadata"
+	if obj.IsStandard {
+		indexSchema = *obj.StorageSchema
+	}
+	return fmt.Sprintf(`DROP INDEX IF EXISTS %s.%s`, QI(indexSchema), QI(ExternalIDIndexName(fd)))
+}
+
+// BuildUpsert returns INSERT ... ON CONFLICT ... DO UPDATE keyed by the external
+// ID field externalID, RETURNING "id"::text and whether a row was inserted.
+// On conflict the supplied values are merged into the stored record: columns
+// are overwritten, custom values are merged into the JSONB document, and the
+// version is bumped as in BuildUpdate. The payload must satisfy the same
+// requirements as an insert.
+func (b *QueryBuilder) BuildUpsert(values map[string]any, externalID string) (string, []any, error) {
+	fd, ok := b.obj.FieldsByAPIName[externalID]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown field %q", externalID)
+	}
+	if !fd.IsExternalID {
+		return "", nil, fmt.Errorf("field %q is not an external ID", externalID)
+	}
+	if err := resolveAliases(b.obj, values); err != nil {
+		return "", nil, err
+	}
+	externalID = fd.APIName
+	if v, ok := values[externalID]; !ok || v == nil {
+		return "", nil, fmt.Errorf("external ID field %q must have a value", externalID)
+	}
+	if err := checkRequired(b.obj, values); err != nil {
+		return "", nil, err
+	}
+	rv, err := splitValues(b.obj, values)
+	if err != nil {
+		return "", nil, err
+	}
+	customJSON, err := jsonArg(rv.Custom)
+	if err != nil {
+		return "", nil, err
+	}
+
+	t := QI(upsertAlias)
+	sets := []string{
+		fmt.Sprintf(`"version" = %s."version" + 1`, t),
+		`"updated_at" = now()`,
+	}
+	returning := fmt.Sprintf(`RETURNING %s."id"::text, (%s."xmax" = 0)`, t, t)
+
+	var sql string
+	var args []any
+	if !b.obj.IsStandard {
+		sets = append(sets, fmt.Sprintf(`"data" = %s."data" || EXCLUDED."data"`, t))
+		sql = fmt.Sprintf(`INSERT INTO "metadata"."records" AS %s ("object_id", "data") VALUES (?, ?::jsonb) ON CONFLICT %s DO UPDATE SET %s %s`,
+			t, conflictTarget(b.obj, fd), strings.Join(sets, ", "), returning)
+		args = []any{b.obj.ID, customJSON}
+	} else {
+		colsJSON, err := jsonArg(rv.Columns)
+		if err != nil {
+			return "", nil, err
+		}
+		table := b.obj.TableName()
+		var insertCols, selectCols []string
+		for _, k := range sortedKeys(rv.Columns) {
+			insertCols = append(insertCols, QI(k))
+			selectCols = append(selectCols, QI(k))
+			sets = append(sets, fmt.Sprintf(`%s = EXCLUDED.%s`, QI(k), QI(k)))
+		}
+		if len(rv.Custom) > 0 {
+			insertCols = append(insertCols, QI(customFieldsColumn))
+			selectCols = append(selectCols, "?::jsonb")
+			args = append(args, customJSON)
+			sets = append(sets, fmt.Sprintf(`%s = %s.%s || EXCLUDED.%s`,
+				QI(customFieldsColumn), t, QI(customFieldsColumn), QI(customFieldsColumn)))
+		}
+		args = append(args, colsJSON)
+		sql = fmt.Sprintf(
+			`INSERT INTO %s AS %s (%s) SELECT %s FROM jsonb_populate_record(NULL::%s, ?::jsonb) ON CONFLICT %s DO UPDATE SET %s %s`,
+			table, t, strings.Join(insertCols, ", "), strings.Join(selectCols, ", "), table,
+			conflictTarget(b.obj, fd), strings.Join(sets, ", "), returning,
+		)
+	}
+
+	finalSQL, err := sq.Dollar.ReplacePlaceholders(sql)
+	return finalSQL, args, err
+}
@@ -2,75 +2,297 @@ package pg
 
 import (
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+
+	sq "github.com/Masterminds/squirrel"
 
 	"github.com/atlekbai/schema_registry/internal/hrql"
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// FilterOperator describes a REST API filter operator parsed from "op.value" strings.
+//
+// Built-in operators set Parse and map onto storage-agnostic hrql conditions.
+// Deployment-specific operators (e.g. phonetic matching) usually set SQL instead:
+// the filter is carried as an hrql.CustomFilter and rendered by SQL at translation time.
+type FilterOperator struct {
+	// Types restricts the operator to these field types. Nil means any type.
+	Types []schema.FieldType
+
+	// Parse turns the raw value into a condition on field.
+	Parse func(field []string, value string) (hrql.Condition, error)
+
+	// SQL renders the filter for col, the field's filter expression (see FilterExpr).
+	SQL func(col string, fd *schema.FieldDef, value string) (sq.Sqlizer, error)
+}
+
+// appliesTo reports whether the operator accepts fields of type t.
+func (o FilterOperator) appliesTo(t schema.FieldType) bool {
+	return o.Types == nil || slices.Contains(o.Types, t)
+}
+
+var (
+	textTypes = []schema.FieldType{
+		schema.FieldText, schema.FieldChoice, schema.FieldEmail, schema.FieldURL, schema.FieldPhone,
+	}
+	orderedTypes = append([]schema.FieldType{
+		schema.FieldNumber, schema.FieldCurrency, schema.FieldPercentage,
+		schema.FieldDate, schema.FieldDatetime,
+	}, textTypes...)
 )
 
-// filterOp is a REST API filter operator parsed from "op.value" strings.
-type filterOp string
-
-const (
-	opEq    filterOp = "eq"
-	opNeq   filterOp = "neq"
-	opGt    filterOp = "gt"
-	opGte   filterOp = "gte"
-	opLt    filterOp = "lt"
-	opLte   filterOp = "lte"
-	opLike  filterOp = "like"
-	opIlike filterOp = "ilike"
-	opIn    filterOp = "in"
-	opIs    filterOp = "is"
+var (
+	filterOpsMu sync.RWMutex
+	filterOps   = map[string]FilterOperator{
+		"eq":    {Parse: cmpOp("==")},
+		"neq":   {Parse: cmpOp("!=")},
+		"gt":    {Types: orderedTypes, Parse: cmpOp(">")},
+		"gte":   {Types: orderedTypes, Parse: cmpOp(">=")},
+		"lt":    {Types: orderedTypes, Parse: cmpOp("<")},
+		"lte":   {Types: orderedTypes, Parse: cmpOp("<=")},
+		"like":  {Types: textTypes, Parse: likeOp(false)},
+		"ilike": {Types: textTypes, Parse: likeOp(true)},
+		"in": {Parse: func(field []string, value string) (hrql.Condition, error) {
+			return hrql.InFilter{Field: field, Values: strings.Split(value, ",")}, nil
+		}},
+		"is": {Parse: func(field []string, value string) (hrql.Condition, error) {
+			if value != "null" && value != "not_null" {
+				return nil, fmt.Errorf("is operator only accepts null or not_null, got %q", value)
+			}
+			return hrql.IsNullFilter{Field: field, IsNull: value == "null"}, nil
+		}},
+	}
 )
 
-var validOps = map[filterOp]bool{
-	opEq: true, opNeq: true, opGt: true, opGte: true,
-	opLt: true, opLte: true, opLike: true, opIlike: true,
-	opIn: true, opIs: true,
+func cmpOp(op string) func([]string, string) (hrql.Condition, error) {
+	return func(field []string, value string) (hrql.Condition, error) {
+		return hrql.FieldCmp{Field: field, Op: op, Value: value}, nil
+	}
+}
+
+func likeOp(caseInsensitive bool) func([]string, string) (hrql.Condition, error) {
+	return func(field []string, value string) (hrql.Condition, error) {
+		return hrql.LikeFilter{Field: field, Pattern: value, CaseInsensitive: caseInsensitive}, nil
+	}
+}
+
+// RegisterFilterOperator adds or replaces a REST API filter operator.
+// It is meant to be called at startup, before the server accepts requests.
+func RegisterFilterOperator(name string, op FilterOperator) error {
+	if name == "" || strings.Contains(name, ".") {
+		return fmt.Errorf("invalid filter operator name %q", name)
+	}
+	if (op.Parse == nil) == (op.SQL == nil) {
+		return fmt.Errorf("filter operator %q: exactly one of Parse or SQL must be set", name)
+	}
+	filterOpsMu.Lock()
+	defer filterOpsMu.Unlock()
+	filterOps[name] = op
+	return nil
+}
+
+// unregisterFilterOperator removes an operator added by RegisterFilterOperator,
+// so tests can undo their registrations.
+func unregisterFilterOperator(name string) {
+	filterOpsMu.Lock()
+	defer filterOpsMu.Unlock()
+	delete(filterOps, name)
+}
+
+// FilterOperators returns the registered operator names, sorted.
+func FilterOperators() []string {
+	filterOpsMu.RLock()
+	defer filterOpsMu.RUnlock()
+	names := make([]string, 0, len(filterOps))
+	for name := range filterOps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func lookupFilterOperator(name string) (FilterOperator, bool) {
+	filterOpsMu.RLock()
+	defer filterOpsMu.RUnlock()
+	op, ok := filterOps[name]
+	return op, ok
 }
 
 // ParseFilterCondition parses a REST API filter string like "eq.hello" and returns
 // a storage-agnostic hrql.Condition for the given field.
-func ParseFilterCondition(fieldAPIName, raw string) (hrql.Condition, error) {
-	before, after, ok := strings.Cut(raw, ".")
+func ParseFilterCondition(fd *schema.FieldDef, raw string) (hrql.Condition, error) {
+	if fd.IsEncrypted() {
+		return nil, fmt.Errorf("field %q is ENCRYPTED and cannot be filtered", fd.APIName)
+	}
+	if err := fd.CheckFilterable(); err != nil {
+		return nil, err
+	}
+
+	name, value, ok := strings.Cut(raw, ".")
 	if !ok {
 		return nil, fmt.Errorf("invalid filter format %q, expected op.value", raw)
 	}
 
-	op := filterOp(before)
-	if !validOps[op] {
-		return nil, fmt.Errorf("unknown filter operator %q", op)
-	}
-
-	value := after
-	if op == opIs && value != "null" && value != "not_null" {
-		return nil, fmt.Errorf("is operator only accepts null or not_null, got %q", value)
-	}
-
-	field := []string{fieldAPIName}
-
-	switch op {
-	case opEq:
-		return hrql.FieldCmp{Field: field, Op: "==", Value: value}, nil
-	case opNeq:
-		return hrql.FieldCmp{Field: field, Op: "!=", Value: value}, nil
-	case opGt:
-		return hrql.FieldCmp{Field: field, Op: ">", Value: value}, nil
-	case opGte:
-		return hrql.FieldCmp{Field: field, Op: ">=", Value: value}, nil
-	case opLt:
-		return hrql.FieldCmp{Field: field, Op: "<", Value: value}, nil
-	case opLte:
-		return hrql.FieldCmp{Field: field, Op: "<=", Value: value}, nil
-	case opLike:
-		return hrql.LikeFilter{Field: field, Pattern: value, CaseInsensitive: false}, nil
-	case opIlike:
-		return hrql.LikeFilter{Field: field, Pattern: value, CaseInsensitive: true}, nil
-	case opIn:
-		return hrql.InFilter{Field: field, Values: strings.Split(value, ",")}, nil
-	case opIs:
-		return hrql.IsNullFilter{Field: field, IsNull: value == "null"}, nil
+	op, ok := lookupFilterOperator(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown filter operator %q", name)
+	}
+	if fd.IsJSON() && name != "is" {
+		return nil, fmt.Errorf("field %q is JSON and only supports is.null and is.not_null filters", fd.APIName)
+	}
+	if !op.appliesTo(fd.Type) {
+		return nil, fmt.Errorf("filter operator %q does not apply to %s field %q", name, fd.Type, fd.APIName)
+	}
+
+	field := []string{fd.APIName}
+	if op.Parse != nil {
+		return op.Parse(field, value)
+	}
+	return hrql.CustomFilter{Field: field, Op: name, Value: value}, nil
+}
+
+// customFilterToSQL renders a CustomFilter through its registered SQL generator.
+func customFilterToSQL(c hrql.CustomFilter, obj *schema.ObjectDef) (sq.Sqlizer, error) {
+	fd := obj.FieldsByAPIName[c.Field[0]]
+	if fd == nil {
+		return nil, fmt.Errorf("unknown field %q", c.Field[0])
+	}
+	op, ok := lookupFilterOperator(c.Op)
+	if !ok || op.SQL == nil {
+		return nil, fmt.Errorf("filter operator %q has no SQL generator", c.Op)
+	}
+	return op.SQL(FilterExpr(Alias(), fd), fd, c.Value)
+}
+
+// restCmpOps maps HRQL comparison operators to REST filter operators.
+var restCmpOps = map[string]string{
+	"==": "eq", "!=": "neq", ">": "gt", ">=": "gte", "<": "lt", "<=": "lte",
+}
+
+// PlanToFilters converts a where-only list plan (e.g. `employees | where(...)`) into
+// the equivalent ParamsInput.Filters map. It returns an error explaining why when the
+// plan has no REST equivalent: ordering, limits, as_of, OR, org functions, lookup
+// chains, or more than one condition on the same field.
+func PlanToFilters(plan *hrql.Plan) (map[string]string, error) {
+	if plan.Kind != hrql.PlanList {
+		return nil, fmt.Errorf("only list expressions can be expressed as filters")
+	}
+	if plan.Object != "" || plan.Union != nil {
+		return nil, fmt.Errorf("only employees expressions can be expressed as filters")
+	}
+	if plan.OrderBy != nil || plan.Limit > 0 || plan.PickOp != "" {
+		return nil, fmt.Errorf("sort_by, first, last and nth have no filter equivalent; use order/limit")
+	}
+	if plan.AsOf != nil {
+		return nil, fmt.Errorf("as_of has no filter equivalent; filters read current state")
+	}
+
+	filters := make(map[string]string)
+	var add func(c hrql.Condition) error
+	add = func(c hrql.Condition) error {
+		if and, ok := c.(hrql.AndCond); ok {
+			if err := add(and.Left); err != nil {
+				return err
+			}
+			return add(and.Right)
+		}
+		field, filter, err := conditionToFilter(c)
+		if err != nil {
+			return err
+		}
+		if _, dup := filters[field]; dup {
+			return fmt.Errorf("field %q is filtered more than once; REST filters allow one condition per field", field)
+		}
+		filters[field] = filter
+		return nil
+	}
+	for _, c := range plan.Conditions {
+		if err := add(c); err != nil {
+			return nil, err
+		}
+	}
+	return filters, nil
+}
+
+// conditionToFilter converts a single non-compound condition to a (field, "op.value") pair.
+func conditionToFilter(c hrql.Condition) (string, string, error) {
+	single := func(field []string) (string, error) {
+		if len(field) != 1 {
+			return "", fmt.Errorf("lookup chain .%s has no filter equivalent", strings.Join(field, "."))
+		}
+		return field[0], nil
+	}
+
+	switch c := c.(type) {
+	case hrql.FieldCmp:
+		f, err := single(c.Field)
+		if err != nil {
+			return "", "", err
+		}
+		if c.Default != nil {
+			return "", "", fmt.Errorf("?? has no filter equivalent")
+		}
+		op, ok := restCmpOps[c.Op]
+		if !ok {
+			return "", "", fmt.Errorf("operator %q has no filter equivalent", c.Op)
+		}
+		return f, op + "." + c.Value, nil
+	case hrql.StringMatch:
+		f, err := single(c.Field)
+		if err != nil {
+			return "", "", err
+		}
+		if c.Fold {
+			return "", "", fmt.Errorf("accent-insensitive %s has no filter equivalent", c.Op)
+		}
+		pattern := likeEscaper.Replace(c.Pattern)
+		switch c.Op {
+		case "contains":
+			return f, "ilike.%" + pattern + "%", nil
+		case "starts_with":
+			return f, "ilike." + pattern + "%", nil
+		case "ends_with":
+			return f, "ilike.%" + pattern, nil
+		}
+		return "", "", fmt.Errorf("string operation %q has no filter equivalent", c.Op)
+	case hrql.LikeFilter:
+		f, err := single(c.Field)
+		if err != nil {
+			return "", "", err
+		}
+		if c.CaseInsensitive {
+			return f, "ilike." + c.Pattern, nil
+		}
+		return f, "like." + c.Pattern, nil
+	case hrql.InFilter:
+		f, err := single(c.Field)
+		if err != nil {
+			return "", "", err
+		}
+		return f, "in." + strings.Join(c.Values, ","), nil
+	case hrql.IsNullFilter:
+		f, err := single(c.Field)
+		if err != nil {
+			return "", "", err
+		}
+		if c.IsNull {
+			return f, "is.null", nil
+		}
+		return f, "is.not_null", nil
+	case hrql.CustomFilter:
+		f, err := single(c.Field)
+		if err != nil {
+			return "", "", err
+		}
+		return f, c.Op + "." + c.Value, nil
+	case hrql.OrCond:
+		return "", "", fmt.Errorf("or has no filter equivalent; REST filters are AND'd")
+	case hrql.FieldCmpRef:
+		return "", "", fmt.Errorf("comparisons against self or employee references have no filter equivalent")
 	default:
-		return nil, fmt.Errorf("unsupported filter operator %q", op)
+		return "", "", fmt.Errorf("%T has no filter equivalent", c)
 	}
 }
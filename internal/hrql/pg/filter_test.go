@@ -0,0 +1,63 @@
+package pg_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+
+	"github.com/atlekbai/schema_registry/internal/hrql/pg"
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+func TestRestFilterRegisteredOp(t *testing.T) {
+	err := pg.RegisterFilterOperator("phonetic", pg.FilterOperator{
+		Types: []schema.FieldType{schema.FieldText},
+		SQL: func(col string, _ *schema.FieldDef, value string) (sq.Sqlizer, error) {
+			return sq.Expr(fmt.Sprintf("soundex(%s) = soundex(?)", col), value), nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	t.Cleanup(func() { pg.UnregisterFilterOperator("phonetic") })
+
+	obj := &schema.ObjectDef{
+		ID:            uuid.New(),
+		APIName:       "employees",
+		IsStandard:    true,
+		StorageSchema: new("core"),
+		StorageTable:  new("employees"),
+		Fields: []schema.FieldDef{
+			{ID: uuid.New(), APIName: "employee_number", Type: schema.FieldText, IsStandard: true, StorageColumn: new("employee_number")},
+			{ID: uuid.New(), APIName: "start_date", Type: schema.FieldDate, IsStandard: true, StorageColumn: new("start_date")},
+		},
+	}
+	cache := schema.NewCacheFromObjects(obj)
+	obj = cache.Get("employees")
+
+	params, err := pg.ParseParams(obj, pg.ParamsInput{Filters: map[string]string{"employee_number": "phonetic.smith"}})
+	if err != nil {
+		t.Fatalf("parse filter: %v", err)
+	}
+	conds, err := pg.TranslateConditions(params.Conditions, obj, cache)
+	if err != nil {
+		t.Fatalf("translate filter: %v", err)
+	}
+	sql, args, err := conds[0].ToSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `soundex("_e"."employee_number") = soundex(?)`; !strings.Contains(sql, want) {
+		t.Errorf("sql = %s, want it to contain %s", sql, want)
+	}
+	if len(args) != 1 || args[0] != "smith" {
+		t.Errorf("args = %v, want [smith]", args)
+	}
+
+	if _, err := pg.ParseParams(obj, pg.ParamsInput{Filters: map[string]string{"start_date": "phonetic.x"}}); err == nil {
+		t.Fatal("expected phonetic to be rejected on DATE field")
+	}
+}
@@ -0,0 +1,146 @@
+package pg
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/atlekbai/schema_registry/internal/schema"
+	"github.com/google/uuid"
+)
+
+// historyObjects lists standard objects that have a history table and a
+// <table>_as_of(timestamptz) snapshot function (see migration 000008).
+var historyObjects = map[string]bool{
+	"employees": true,
+}
+
+// AsOf returns a copy of obj whose reads go through its snapshot function, so every
+// query built from it (lists, aggregates, ltree subqueries) sees state as of ts.
+// Expanded lookups to other objects still read current data.
+func AsOf(obj *schema.ObjectDef, ts time.Time) (*schema.ObjectDef, error) {
+	if !obj.IsStandard || !historyObjects[obj.APIName] {
+		return nil, fmt.Errorf("object %q has no history; as_of is not supported", obj.APIName)
+	}
+	cp := *obj
+	cp.TableExpr = fmt.Sprintf(`%s.%s(%s::timestamptz)`,
+		QI(*obj.StorageSchema), QI(*obj.StorageTable+"_as_of"), QuoteLit(ts.UTC().Format(time.RFC3339Nano)))
+	cp.SnapshotAt = ts
+	return &cp, nil
+}
+
+// recordHistoryTable holds every version of registry records, written by the
+// trg_record_history triggers (see migration 000027).
+const recordHistoryTable = `"metadata"."record_history"`
+
+// HistoryRow is one version of a record as returned by BuildRecordHistory.
+type HistoryRow struct {
+	Version   int64
+	Operation string
+	ChangedAt time.Time
+	ActorID   *string
+	RequestID *string
+	Record    json.RawMessage // nil for DELETE
+}
+
+// ScanDest returns the scan destinations matching BuildRecordHistory's columns.
+func (r *HistoryRow) ScanDest() []any {
+	return []any{&r.Version, &r.Operation, &r.ChangedAt, &r.ActorID, &r.RequestID, &r.Record}
+}
+
+// BuildRecordHistory returns the versions of obj's record id, oldest first.
+// Each stored row is expanded back into the object's table type, so the
+// record is built like a read of the row as it was then.
+func BuildRecordHistory(obj *schema.ObjectDef, id uuid.UUID) (string, []any, error) {
+	rowType := `"metadata"."records"`
+	if obj.IsStandard {
+		if obj.StorageSchema == nil || obj.StorageTable == nil {
+			return "", nil, fmt.Errorf("object %q has no storage table", obj.APIName)
+		}
+		rowType = QI(*obj.StorageSchema) + "." + QI(*obj.StorageTable)
+	}
+	jsonExpr, _ := buildJsonObject(obj, &QueryParams{}, nil)
+
+	return sq.Select(`h."version"`, `h."operation"`, `h."changed_at"`, `h."actor_id"`, `h."request_id"`).
+		Column(fmt.Sprintf(`CASE WHEN h."data" IS NULL THEN NULL ELSE %s END`, jsonExpr)).
+		From(fmt.Sprintf(`%s h CROSS JOIN LATERAL jsonb_populate_record(NULL::%s, h."data") %s`,
+			recordHistoryTable, rowType, QI(qAlias))).
+		Where(sq.Eq{`h."object_id"`: obj.ID, `h."record_id"`: id}).
+		OrderBy(`h."version"`).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+}
+
+// BuildScrubHistory returns a DELETE of the stored versions of obj's record
+// id older than its latest one, for retention: after a purge the latest is
+// the DELETE marker (no data) or the anonymized row.
+func BuildScrubHistory(obj *schema.ObjectDef, id uuid.UUID) (string, []any, error) {
+	return fmt.Sprintf(`DELETE FROM %[1]s WHERE "object_id" = $1 AND "record_id" = $2 AND "version" < (
+	SELECT max("version") FROM %[1]s WHERE "object_id" = $1 AND "record_id" = $2)`, recordHistoryTable),
+		[]any{obj.ID, id}, nil
+}
+
+// BuildScrubSnapshots returns the statement dropping the purged values of
+// obj's record id from its history table (see AsOf), for retention. After a
+// delete every stored row of the record goes, so snapshots no longer return
+// it at all; otherwise the earlier rows go and the current one, already
+// rewritten, covers their time too. ok is false for objects without a
+// history table.
+func BuildScrubSnapshots(obj *schema.ObjectDef, id uuid.UUID, deleted bool) (sql string, args []any, ok bool) {
+	if !obj.IsStandard || !historyObjects[obj.APIName] {
+		return "", nil, false
+	}
+	table := QI(*obj.StorageSchema) + "." + QI(*obj.StorageTable+"_history")
+	if deleted {
+		return fmt.Sprintf(`DELETE FROM %s WHERE "id" = $1`, table), []any{id}, true
+	}
+	return fmt.Sprintf(`WITH gone AS (
+	DELETE FROM %[1]s WHERE "id" = $1 AND "valid_to" IS NOT NULL RETURNING "valid_from")
+UPDATE %[1]s SET "valid_from" = COALESCE((SELECT min("valid_from") FROM gone), "valid_from")
+WHERE "id" = $1 AND "valid_to" IS NULL`, table), []any{id}, true
+}
+
+// FieldChange is a field whose value differs between two versions. Key is
+// the field's key in records (see jsonKey).
+type FieldChange struct {
+	Field *schema.FieldDef
+	Key   string
+}
+
+// FieldChanges returns obj's fields whose values differ between the records
+// before and after, in field order. Lookup labels are derived data and are
+// not compared.
+func FieldChanges(obj *schema.ObjectDef, before, after map[string]any) []FieldChange {
+	var changes []FieldChange
+	for i := range obj.Fields {
+		fd := &obj.Fields[i]
+		if isSystemField(fd.APIName) {
+			continue
+		}
+		key := jsonKey(fd)
+		if !reflect.DeepEqual(before[key], after[key]) {
+			changes = append(changes, FieldChange{Field: fd, Key: key})
+		}
+	}
+	return changes
+}
+
+// RevertValues returns the write payload restoring record's writable field
+// values, keyed by API name. Fields absent from record (added since) are
+// set to null; computed fields are left out.
+func RevertValues(obj *schema.ObjectDef, record map[string]any) map[string]any {
+	values := make(map[string]any, len(obj.Fields))
+	for i := range obj.Fields {
+		fd := &obj.Fields[i]
+		if isSystemField(fd.APIName) || fd.Type == schema.FieldFormula {
+			continue
+		}
+		if obj.IsStandard && fd.StorageColumn == nil && !obj.SupportsCustomFields {
+			continue
+		}
+		values[fd.APIName] = record[jsonKey(fd)]
+	}
+	return values
+}
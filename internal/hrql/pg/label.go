@@ -0,0 +1,175 @@
+package pg
+
+import (
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/atlekbai/schema_registry/internal/schema"
+	"github.com/google/uuid"
+)
+
+// Denormalized lookup labels: a LOOKUP field with type_config
+// {"denormalize_label": "title"} keeps the target's title next to the
+// reference, under LabelKey in the record's JSONB document, so listings can
+// show it without an expand. The write path refreshes a record's labels after
+// each write and propagates a target's new label to the records pointing at it.
+
+const labelAlias = "_r"
+
+// labelSuffix marks label keys. "__" cannot appear in user api_names (see
+// schema.IdentifierPolicy), so label keys never collide with fields.
+const labelSuffix = "__label"
+
+// LabelKey returns the document key and JSON output key of a denormalized
+// label, e.g. "department__label".
+func LabelKey(fd *schema.FieldDef) string {
+	return fd.APIName + labelSuffix
+}
+
+// LabelStatement is a single label maintenance statement.
+type LabelStatement struct {
+	SQL  string
+	Args []any
+}
+
+// labelLink is a LOOKUP field denormalizing label from its target object.
+type labelLink struct {
+	field  *schema.FieldDef
+	target *schema.ObjectDef
+	label  *schema.FieldDef
+}
+
+// labelLinks returns obj's LOOKUP fields that denormalize a label. Settings
+// naming a missing object or field are skipped.
+func labelLinks(obj *schema.ObjectDef, cache *schema.Cache) []labelLink {
+	var links []labelLink
+	for i := range obj.Fields {
+		fd := &obj.Fields[i]
+		name := fd.LabelField()
+		if name == "" || fd.LookupObjectID == nil {
+			continue
+		}
+		target := cache.GetByID(*fd.LookupObjectID)
+		if target == nil {
+			continue
+		}
+		if label, ok := target.FieldsByAPIName[name]; ok {
+			links = append(links, labelLink{field: fd, target: target, label: label})
+		}
+	}
+	return links
+}
+
+// ValidateLabelField checks a denormalize_label setting: the target must have
+// a readable, non-lookup field called name.
+func ValidateLabelField(target *schema.ObjectDef, name string) error {
+	fd, ok := target.FieldsByAPIName[name]
+	if !ok {
+		return fmt.Errorf("denormalize_label: unknown field %q on %s", name, target.APIName)
+	}
+	switch fd.Type {
+	case schema.FieldEncrypted, schema.FieldLookup, schema.FieldFormula, schema.FieldJSON:
+		return fmt.Errorf("denormalize_label: %s fields cannot be denormalized", fd.Type)
+	}
+	return nil
+}
+
+// docColumn returns the JSONB column holding a record's document values.
+func docColumn(obj *schema.ObjectDef) string {
+	return QI(obj.DocumentColumn())
+}
+
+// refExpr returns the uuid a LOOKUP field holds on the row aliased alias.
+func refExpr(obj *schema.ObjectDef, alias string, fd *schema.FieldDef) string {
+	if obj.IsStandard && fd.StorageColumn != nil {
+		return fmt.Sprintf(`%s.%s`, QI(alias), QI(*fd.StorageColumn))
+	}
+	return fmt.Sprintf(`(%s.%s->>%s)::uuid`, QI(alias), docColumn(obj), QuoteLit(fd.APIName))
+}
+
+// objectFilter restricts metadata.records rows to obj; empty for standard tables.
+func objectFilter(obj *schema.ObjectDef, alias string) string {
+	if obj.IsStandard {
+		return ""
+	}
+	return fmt.Sprintf(` AND %s."object_id" = %s::uuid`, QI(alias), QuoteLit(obj.ID.String()))
+}
+
+// labelValue returns a scalar subquery reading link's label as jsonb for the
+// target record ref.
+func labelValue(link labelLink, ref string) string {
+	const alias = "_lt"
+	value := fmt.Sprintf(`%s.%s->%s`, QI(alias), docColumn(link.target), QuoteLit(link.label.APIName))
+	if link.target.IsStandard && link.label.StorageColumn != nil {
+		value = fmt.Sprintf(`to_jsonb(%s.%s)`, QI(alias), QI(*link.label.StorageColumn))
+	}
+	return fmt.Sprintf(`(SELECT %s FROM %s %s WHERE %s."id" = %s%s)`,
+		value, writeTable(link.target), QI(alias), QI(alias), ref, objectFilter(link.target, alias))
+}
+
+// labelSelectExpr returns the SQL reading a stored label in SELECT context.
+func labelSelectExpr(obj *schema.ObjectDef, alias string, fd *schema.FieldDef) string {
+	return fmt.Sprintf(`%s.%s->%s`, QI(alias), docColumn(obj), QuoteLit(LabelKey(fd)))
+}
+
+// BuildRefreshLabels returns an UPDATE recomputing the denormalized labels of
+// record id whose LOOKUP fields appear in changed, or every label of all of
+// obj's records when id is uuid.Nil and changed is nil (backfill after a field
+// gains the setting). ok is false when there is nothing to refresh. Labels are
+// derived data, so version and updated_at are left alone.
+func BuildRefreshLabels(obj *schema.ObjectDef, cache *schema.Cache, id uuid.UUID, changed map[string]any) (stmt LabelStatement, ok bool, err error) {
+	var links []labelLink
+	for _, link := range labelLinks(obj, cache) {
+		if _, ok := changed[link.field.APIName]; changed == nil || ok {
+			links = append(links, link)
+		}
+	}
+	if len(links) == 0 {
+		return LabelStatement{}, false, nil
+	}
+	pairs := make([]string, len(links))
+	for i, link := range links {
+		pairs[i] = fmt.Sprintf(`%s, %s`, QuoteLit(LabelKey(link.field)), labelValue(link, refExpr(obj, labelAlias, link.field)))
+	}
+
+	doc := docColumn(obj)
+	sql := fmt.Sprintf(`UPDATE %s AS %s SET %s = %s.%s || jsonb_build_object(%s) WHERE TRUE%s`,
+		writeTable(obj), QI(labelAlias), doc, QI(labelAlias), doc, strings.Join(pairs, ", "), objectFilter(obj, labelAlias))
+	var args []any
+	if id != uuid.Nil {
+		sql += fmt.Sprintf(` AND %s."id" = ?`, QI(labelAlias))
+		args = append(args, id)
+	}
+	finalSQL, err := sq.Dollar.ReplacePlaceholders(sql)
+	return LabelStatement{SQL: finalSQL, Args: args}, true, err
+}
+
+// BuildPropagateLabels returns UPDATEs copying the label of target record id
+// to every record whose denormalized LOOKUP points at it. changed limits the
+// result to labels read from those fields of target; nil means all (e.g. on
+// delete, which clears the labels of records still pointing at id).
+func BuildPropagateLabels(target *schema.ObjectDef, cache *schema.Cache, id uuid.UUID, changed map[string]any) ([]LabelStatement, error) {
+	var stmts []LabelStatement
+	for _, obj := range cache.Objects() {
+		for _, link := range labelLinks(obj, cache) {
+			if link.target.ID != target.ID {
+				continue
+			}
+			if _, ok := changed[link.label.APIName]; changed != nil && !ok {
+				continue
+			}
+			doc := docColumn(obj)
+			sql := fmt.Sprintf(`UPDATE %s AS %s SET %s = %s.%s || jsonb_build_object(%s, %s) WHERE %s = ?%s`,
+				writeTable(obj), QI(labelAlias), doc, QI(labelAlias), doc,
+				QuoteLit(LabelKey(link.field)), labelValue(link, "?"),
+				refExpr(obj, labelAlias, link.field), objectFilter(obj, labelAlias))
+			finalSQL, err := sq.Dollar.ReplacePlaceholders(sql)
+			if err != nil {
+				return nil, err
+			}
+			stmts = append(stmts, LabelStatement{SQL: finalSQL, Args: []any{id, id}})
+		}
+	}
+	return stmts, nil
+}
@@ -3,6 +3,8 @@ package pg
 import (
 	"fmt"
 	"strings"
+
+	"github.com/atlekbai/schema_registry/internal/schema"
 )
 
 // expandAlias returns the join alias for an expand field, e.g. "_xp_organization".
@@ -27,10 +29,47 @@ const maxExpandDepth = 2
 // prefix namespaces nested aliases to avoid collisions.
 // depth controls recursion: 0 = top level (caller adds LEFT JOIN via Squirrel), 1+ = nested.
 func buildLateral(ep *ExpandPlan, outerRef, prefix string, depth int) (sql string, args []any) {
-	target := ep.Target
 	name := prefix + ep.FieldName
+	inner, args := expandSelect(ep, name, depth, fmt.Sprintf(`%s."id" = %s`, QI(expandInner(name)), outerRef), nil)
+
+	joinPrefix := ""
+	if depth > 0 {
+		joinPrefix = "LEFT JOIN "
+	}
+
+	sql = fmt.Sprintf(`%sLATERAL (%s) %s ON TRUE`, joinPrefix, inner, QI(expandAlias(name)))
+	return sql, args
+}
+
+// maxReverseExpandRows bounds the records a reverse expand embeds per row.
+const maxReverseExpandRows = 100
+
+// reverseRecordsKey is the column of a reverse expand's lateral holding its array.
+const reverseRecordsKey = "records"
+
+// buildReverseLateral builds the LATERAL join of a Reverse expand plan: the
+// target records whose back-reference equals outerRef, as a JSON array
+// ordered by id (empty when there are none), at most maxReverseExpandRows.
+func buildReverseLateral(ep *ExpandPlan, outerRef string) (sql string, args []any) {
+	name := ep.FieldName
+	rows := QI(expandAlias(name) + "_r")
+	inner, args := expandSelect(ep, name, 0, fmt.Sprintf(`%s = %s`, FKRef(expandInner(name), ep.Field), outerRef), nil)
+
+	sql = fmt.Sprintf(
+		`LATERAL (SELECT COALESCE(jsonb_agg(to_jsonb(%[1]s.*) ORDER BY %[1]s."id"), '[]'::jsonb) AS %[2]s `+
+			`FROM (%[3]s ORDER BY 1 LIMIT %[4]d) %[1]s) %[5]s ON TRUE`,
+		rows, QI(reverseRecordsKey), inner, maxReverseExpandRows, QI(expandAlias(name)),
+	)
+	return sql, args
+}
+
+// expandSelect builds the SELECT producing an expanded record of ep.Target: system
+// fields plus every target field (or only ep.Select) and the target's display
+// name under DisplayKey, with nested expands joined laterally. pred selects the target rows and is evaluated against the
+// expandInner(name) alias.
+func expandSelect(ep *ExpandPlan, name string, depth int, pred string, predArgs []any) (sql string, args []any) {
+	target := ep.Target
 	inner := expandInner(name)
-	alias := expandAlias(name)
 
 	childSet := makeExpandSet(ep.Children)
 
@@ -42,9 +81,10 @@ func buildLateral(ep *ExpandPlan, outerRef, prefix string, depth int) (sql strin
 		fmt.Sprintf(`%s."id"`, QI(inner)),
 		fmt.Sprintf(`%s."created_at"`, QI(inner)),
 		fmt.Sprintf(`%s."updated_at"`, QI(inner)),
+		fmt.Sprintf(`%s."version"`, QI(inner)),
 	)
 
-	for _, f := range target.Fields {
+	for _, f := range projectedFields(ep) {
 		if isSystemField(f.APIName) {
 			continue
 		}
@@ -58,30 +98,47 @@ func buildLateral(ep *ExpandPlan, outerRef, prefix string, depth int) (sql strin
 			nestedJoins = append(nestedJoins, nj)
 			args = append(args, na...)
 		} else {
-			cols = append(cols, fmt.Sprintf(`%s AS %s`, SelectFieldExpr(inner, &f), QI(f.APIName)))
+			cols = append(cols, fmt.Sprintf(`%s AS %s`, SelectFieldExpr(inner, f), QI(f.APIName)))
 		}
 	}
 
+	if display := DisplayExpr(target, inner); display != "" {
+		cols = append(cols, fmt.Sprintf(`%s AS %s`, display, QI(DisplayKey)))
+	}
+
 	from, baseWhere := TableSource(target, inner)
-	joinCond := fmt.Sprintf(`%s."id" = %s`, QI(inner), outerRef)
+	where := pred
 	if baseWhere != nil {
 		baseSql, baseArgs, _ := baseWhere.ToSql()
-		joinCond = baseSql + " AND " + joinCond
+		where = baseSql + " AND " + where
 		args = append(args, baseArgs...)
 	}
+	args = append(args, predArgs...)
 
-	joinPrefix := ""
-	if depth > 0 {
-		joinPrefix = "LEFT JOIN "
-	}
-
-	sql = fmt.Sprintf(`%sLATERAL (SELECT %s FROM %s %s WHERE %s) %s ON TRUE`,
-		joinPrefix,
+	sql = fmt.Sprintf(`SELECT %s FROM %s %s WHERE %s`,
 		strings.Join(cols, ", "),
 		from,
 		strings.Join(nestedJoins, " "),
-		joinCond,
-		QI(alias))
+		where)
 
 	return sql, args
 }
+
+// projectedFields returns the target fields an expand reads: ep.Select when
+// set, every field otherwise.
+func projectedFields(ep *ExpandPlan) []*schema.FieldDef {
+	if ep.Select != nil {
+		fields := make([]*schema.FieldDef, 0, len(ep.Select))
+		for _, name := range ep.Select {
+			if f := ep.Target.FieldsByAPIName[name]; f != nil {
+				fields = append(fields, f)
+			}
+		}
+		return fields
+	}
+	fields := make([]*schema.FieldDef, 0, len(ep.Target.Fields))
+	for i := range ep.Target.Fields {
+		fields = append(fields, &ep.Target.Fields[i])
+	}
+	return fields
+}
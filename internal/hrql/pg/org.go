@@ -2,6 +2,9 @@ package pg
 
 import (
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 
@@ -10,10 +13,14 @@ import (
 )
 
 // ChainUp returns a condition matching the ancestor at exactly `steps` levels above target.
+// Via hrql.ViaPositions it matches the managers `steps` edges up the position graph.
 // SQL: t.manager_path = subpath(PathSubquery(ref), 0, nlevel(PathSubquery(ref)) - steps)
-func ChainUp(ref hrql.EmployeeRef, steps int, obj *schema.ObjectDef) sq.Sqlizer {
-	col := fmt.Sprintf(`%s."manager_path"`, QI(Alias()))
-	pathSQL, pathArgs, _ := PathSubquery(ref, obj).ToSql()
+func ChainUp(ref hrql.EmployeeRef, steps int, via string, obj *schema.ObjectDef) sq.Sqlizer {
+	if via == hrql.ViaPositions {
+		return positionMember(ref, true, steps, obj)
+	}
+	col := fmt.Sprintf(`%s.%s`, QI(Alias()), QI(HierarchyPath(obj, via)))
+	pathSQL, pathArgs, _ := PathSubquery(ref, via, obj).ToSql()
 	sql := fmt.Sprintf(
 		`%s = subpath(%s, 0, GREATEST(nlevel(%s) - ?, 0))`,
 		col, pathSQL, pathSQL,
@@ -24,9 +31,12 @@ func ChainUp(ref hrql.EmployeeRef, steps int, obj *schema.ObjectDef) sq.Sqlizer
 
 // ChainDown returns a condition matching descendants at exactly `depth` levels below target.
 // SQL: t.manager_path <@ PathSubquery(ref) AND nlevel(t.mp) = nlevel(PathSubquery(ref)) + depth
-func ChainDown(ref hrql.EmployeeRef, depth int, obj *schema.ObjectDef) sq.Sqlizer {
-	col := fmt.Sprintf(`%s."manager_path"`, QI(Alias()))
-	pathSQL, pathArgs, _ := PathSubquery(ref, obj).ToSql()
+func ChainDown(ref hrql.EmployeeRef, depth int, via string, obj *schema.ObjectDef) sq.Sqlizer {
+	if via == hrql.ViaPositions {
+		return positionMember(ref, false, depth, obj)
+	}
+	col := fmt.Sprintf(`%s.%s`, QI(Alias()), QI(HierarchyPath(obj, via)))
+	pathSQL, pathArgs, _ := PathSubquery(ref, via, obj).ToSql()
 	sql := fmt.Sprintf(
 		`%s <@ %s AND nlevel(%s) = nlevel(%s) + ?`,
 		col, pathSQL, col, pathSQL,
@@ -36,10 +46,14 @@ func ChainDown(ref hrql.EmployeeRef, depth int, obj *schema.ObjectDef) sq.Sqlize
 }
 
 // Subtree returns a condition matching all descendants (any depth), excluding the target itself.
+// ChainDown, Subtree and ChainAll walk the position graph via hrql.ViaPositions (see positionReach).
 // SQL: t.manager_path <@ PathSubquery(ref) AND t.manager_path != PathSubquery(ref)
-func Subtree(ref hrql.EmployeeRef, obj *schema.ObjectDef) sq.Sqlizer {
-	col := fmt.Sprintf(`%s."manager_path"`, QI(Alias()))
-	pathSQL, pathArgs, _ := PathSubquery(ref, obj).ToSql()
+func Subtree(ref hrql.EmployeeRef, via string, obj *schema.ObjectDef) sq.Sqlizer {
+	if via == hrql.ViaPositions {
+		return positionMember(ref, false, 0, obj)
+	}
+	col := fmt.Sprintf(`%s.%s`, QI(Alias()), QI(HierarchyPath(obj, via)))
+	pathSQL, pathArgs, _ := PathSubquery(ref, via, obj).ToSql()
 	sql := fmt.Sprintf(
 		`%s <@ %s AND %s != %s`,
 		col, pathSQL, col, pathSQL,
@@ -48,6 +62,34 @@ func Subtree(ref hrql.EmployeeRef, obj *schema.ObjectDef) sq.Sqlizer {
 	return sq.Expr(sql, args...)
 }
 
+// Network returns a condition matching employees within `degree` hops of target,
+// where a hop is one manager edge in either direction. For each k in 0..degree the
+// k-th ancestor A_k contributes its subtree down to degree-k levels below it:
+//
+//	(nlevel(p) > k AND t.mp <@ subpath(p, 0, nlevel(p) - k) AND nlevel(t.mp) <= nlevel(p) + degree - 2k) OR ...
+//
+// The target itself is excluded.
+func Network(ref hrql.EmployeeRef, degree int, via string, obj *schema.ObjectDef) sq.Sqlizer {
+	col := fmt.Sprintf(`%s.%s`, QI(Alias()), QI(HierarchyPath(obj, via)))
+	pathSQL, pathArgs, _ := PathSubquery(ref, via, obj).ToSql()
+	refSQL, refArgs, _ := RefToSQL(ref, obj).ToSql()
+
+	var (
+		ors  []string
+		args []any
+	)
+	for k := 0; k <= degree; k++ {
+		ors = append(ors, fmt.Sprintf(
+			`(nlevel(%s) > ? AND %s <@ subpath(%s, 0, nlevel(%s) - ?) AND nlevel(%s) <= nlevel(%s) + ?)`,
+			pathSQL, col, pathSQL, pathSQL, col, pathSQL,
+		))
+		args = concatArgs(args, pathArgs, []any{k}, pathArgs, pathArgs, []any{k}, pathArgs, []any{degree - 2*k})
+	}
+
+	sql := fmt.Sprintf(`(%s) AND %s."id" != %s`, strings.Join(ors, " OR "), QI(Alias()), refSQL)
+	return sq.Expr(sql, concatArgs(args, refArgs)...)
+}
+
 // SameField returns: column = (SELECT field FROM emp WHERE id = ref.ID) AND id != ref.ID.
 // Includes IS NOT NULL guard for the subquery to handle null field values.
 func SameField(fieldAPIName string, ref hrql.EmployeeRef, obj *schema.ObjectDef) sq.Sqlizer {
@@ -68,9 +110,12 @@ func SameField(fieldAPIName string, ref hrql.EmployeeRef, obj *schema.ObjectDef)
 // ChainAll returns a condition matching ALL ancestors of the target.
 // SQL: t.manager_path @> PathSubquery(ref) AND t.id != RefToSQL(ref)
 // Uses the SP-GiST index on manager_path.
-func ChainAll(ref hrql.EmployeeRef, obj *schema.ObjectDef) sq.Sqlizer {
-	col := fmt.Sprintf(`%s."manager_path"`, QI(Alias()))
-	pathSQL, pathArgs, _ := PathSubquery(ref, obj).ToSql()
+func ChainAll(ref hrql.EmployeeRef, via string, obj *schema.ObjectDef) sq.Sqlizer {
+	if via == hrql.ViaPositions {
+		return positionMember(ref, true, 0, obj)
+	}
+	col := fmt.Sprintf(`%s.%s`, QI(Alias()), QI(HierarchyPath(obj, via)))
+	pathSQL, pathArgs, _ := PathSubquery(ref, via, obj).ToSql()
 	refSQL, refArgs, _ := RefToSQL(ref, obj).ToSql()
 
 	sql := fmt.Sprintf(
@@ -83,15 +128,18 @@ func ChainAll(ref hrql.EmployeeRef, obj *schema.ObjectDef) sq.Sqlizer {
 
 // ReportsToWhere generates a WHERE condition for reports_to(., target) inside where.
 // Semantically identical to Subtree — checks if current row is a descendant of target.
-func ReportsToWhere(ref hrql.EmployeeRef, obj *schema.ObjectDef) sq.Sqlizer {
-	return Subtree(ref, obj)
+func ReportsToWhere(ref hrql.EmployeeRef, via string, obj *schema.ObjectDef) sq.Sqlizer {
+	return Subtree(ref, via, obj)
 }
 
 // ReportsToCheckSQL builds a SQL query that returns a boolean for a top-level reports_to(emp, target).
 // SELECT (emp_path <@ target_path AND emp_path != target_path)
-func ReportsToCheckSQL(emp, target hrql.EmployeeRef, obj *schema.ObjectDef) (string, []any, error) {
-	empPathSQL, empPathArgs, _ := PathSubquery(emp, obj).ToSql()
-	tgtPathSQL, tgtPathArgs, _ := PathSubquery(target, obj).ToSql()
+func ReportsToCheckSQL(emp, target hrql.EmployeeRef, via string, obj *schema.ObjectDef) (string, []any, error) {
+	if via == hrql.ViaPositions {
+		return positionReportsToSQL(emp, target, obj)
+	}
+	empPathSQL, empPathArgs, _ := PathSubquery(emp, via, obj).ToSql()
+	tgtPathSQL, tgtPathArgs, _ := PathSubquery(target, via, obj).ToSql()
 
 	sql := fmt.Sprintf(
 		`SELECT (%s <@ %s AND %s != %s)`,
@@ -101,6 +149,94 @@ func ReportsToCheckSQL(emp, target hrql.EmployeeRef, obj *schema.ObjectDef) (str
 	return sql, args, nil
 }
 
+// BatchReportsToSQL builds a single query evaluating many top-level reports_to checks.
+// Each check becomes a VALUES row (idx, emp_id, target_id) joined to both employees:
+//
+//	SELECT idx, (e.path <@ t.path AND e.path != t.path)
+//	FROM (VALUES (0, $1::uuid, $2::uuid), ...) b(idx, emp, tgt)
+//	LEFT JOIN employees e ON e.id = b.emp LEFT JOIN employees t ON t.id = b.tgt
+//
+// Rows come back in check order; the result is NULL when either employee does not exist.
+// Checks over other hierarchies (Via) pick their path column with a CASE on the row.
+func BatchReportsToSQL(checks []hrql.ReportsToCheck, obj *schema.ObjectDef) (string, []any, error) {
+	if len(checks) == 0 {
+		return "", nil, fmt.Errorf("batch has no checks")
+	}
+	if slices.ContainsFunc(checks, func(c hrql.ReportsToCheck) bool { return c.Via == hrql.ViaPositions }) {
+		return "", nil, fmt.Errorf("reports_to via: positions is not supported in batches")
+	}
+
+	// Distinct path columns; a row's "path" value indexes this slice.
+	var paths []string
+	pathIdx := make([]int, len(checks))
+	for i, c := range checks {
+		path := HierarchyPath(obj, c.Via)
+		p := slices.Index(paths, path)
+		if p < 0 {
+			p = len(paths)
+			paths = append(paths, path)
+		}
+		pathIdx[i] = p
+	}
+
+	rows := make([]string, len(checks))
+	var args []any
+	for i, c := range checks {
+		empSQL, empArgs, _ := RefToSQL(c.Emp, obj).ToSql()
+		tgtSQL, tgtArgs, _ := RefToSQL(c.Target, obj).ToSql()
+		if len(paths) > 1 {
+			rows[i] = fmt.Sprintf(`(%d, %s::uuid, %s::uuid, %d)`, i, empSQL, tgtSQL, pathIdx[i])
+		} else {
+			rows[i] = fmt.Sprintf(`(%d, %s::uuid, %s::uuid)`, i, empSQL, tgtSQL)
+		}
+		args = concatArgs(args, empArgs, tgtArgs)
+	}
+
+	check := func(path string) string {
+		return fmt.Sprintf(`("_be".%s <@ "_bt".%s AND "_be".%s != "_bt".%s)`, QI(path), QI(path), QI(path), QI(path))
+	}
+	result := check(paths[0])
+	columns := `"idx", "emp", "tgt"`
+	if len(paths) > 1 {
+		whens := make([]string, len(paths))
+		for p, path := range paths {
+			whens[p] = fmt.Sprintf(`WHEN %d THEN %s`, p, check(path))
+		}
+		result = fmt.Sprintf(`CASE "_b"."path" %s END`, strings.Join(whens, " "))
+		columns += `, "path"`
+	}
+
+	sql := fmt.Sprintf(
+		`SELECT "_b"."idx", %s `+
+			`FROM (VALUES %s) AS "_b"(%s) `+
+			`LEFT JOIN %s "_be" ON "_be"."id" = "_b"."emp" `+
+			`LEFT JOIN %s "_bt" ON "_bt"."id" = "_b"."tgt" `+
+			`ORDER BY "_b"."idx"`,
+		result, strings.Join(rows, ", "), columns, obj.TableName(), obj.TableName(),
+	)
+	sql, err := sq.Dollar.ReplacePlaceholders(sql)
+	if err != nil {
+		return "", nil, err
+	}
+	return sql, args, nil
+}
+
+// Employed returns a condition matching employees under alias who have not
+// left: end_date is NULL or after today, or after the date obj's snapshot
+// reads (see AsOf).
+// SQL: (t.end_date IS NULL OR t.end_date > CURRENT_DATE)
+func Employed(alias string, obj *schema.ObjectDef) (sq.Sqlizer, error) {
+	fd := obj.FieldsByAPIName[hrql.EndDateField]
+	if fd == nil {
+		return nil, fmt.Errorf("object %q has no %s field", obj.APIName, hrql.EndDateField)
+	}
+	col := FilterExpr(alias, fd)
+	if obj.SnapshotAt.IsZero() {
+		return sq.Expr(fmt.Sprintf(`(%[1]s IS NULL OR %[1]s > CURRENT_DATE)`, col)), nil
+	}
+	return sq.Expr(fmt.Sprintf(`(%[1]s IS NULL OR %[1]s > ?::date)`, col), obj.SnapshotAt.UTC().Format(time.DateOnly)), nil
+}
+
 // NullCondition returns an always-false condition.
 func NullCondition() sq.Sqlizer {
 	return sq.Eq{fmt.Sprintf(`%s."id"`, QI(Alias())): nil}
@@ -1,10 +1,16 @@
 package pg
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/atlekbai/schema_registry/internal/hrql"
@@ -20,16 +26,35 @@ type ParamsInput struct {
 	Limit   int32             // 0 means use default
 	Cursor  string            // opaque cursor token
 	Filters map[string]string // field API name -> "op.value"
+	// Cursors verifies Cursor; a cursor token other than a plain id is
+	// rejected without it.
+	Cursors *CursorSigner
+	// IncludeTerminated counts employees who have left in reports:count.
+	IncludeTerminated bool
+	// Cache resolves expands naming another object (reverse expands, see
+	// ExpandPlan.Reverse); without it only LOOKUP fields expand.
+	Cache *schema.Cache
+	// Limits are the server's list limits; zero values use DefaultLimit and
+	// MaxLimit. The object's own page sizes apply on top (ListLimits.For).
+	Limits schema.ListLimits
 }
 
 const (
-	DefaultLimit = 50
-	MaxLimit     = 200
+	// DefaultLimit and MaxLimit are the built-in page sizes of a List, used
+	// unless the server configures its own (ParamsInput.Limits).
+	DefaultLimit = schema.DefaultPageSize
+	MaxLimit     = schema.MaxPageSize
+
+	// DefaultIDsLimit and MaxIDsLimit bound the page of an HRQL query ending
+	// in ids: rows without records are cheap enough for a higher ceiling.
+	DefaultIDsLimit = 1000
+	MaxIDsLimit     = 10000
 )
 
 type OrderClause struct {
 	FieldAPIName string
 	Desc         bool
+	NullsFirst   bool // NULLs sort before values; by default they sort last
 }
 
 type ExpandPlan struct {
@@ -37,28 +62,107 @@ type ExpandPlan struct {
 	Field     *schema.FieldDef
 	Target    *schema.ObjectDef
 	Children  []ExpandPlan
+	// Reverse marks an expand of the Target records whose LOOKUP Field points
+	// at the expanded record, e.g. an employee's positions. FieldName is then
+	// the Target's API name and the expand is an array (see buildReverseLateral).
+	Reverse bool
+	// Select lists the target fields to project (system fields are always
+	// included); nil projects every field. Set by ProjectExpands.
+	Select []string
 }
 
 // Cursor holds keyset pagination state: the last row's ID and optional sort column value.
+// OrderField/Desc/NullsFirst record the ordering the cursor was issued for so a schema change
+// between pages is detected instead of silently mis-ordering results.
 type Cursor struct {
 	ID       string `json:"id"`
 	OrderVal string `json:"v,omitempty"`
+	// Null marks a last row without a value in the sort field; OrderVal is
+	// then empty.
+	Null       bool   `json:"n,omitempty"`
+	OrderField string `json:"f,omitempty"`
+	Desc       bool   `json:"d,omitempty"`
+	NullsFirst bool   `json:"nf,omitempty"`
+	// Snapshot pins the pages of a snapshot List to one database snapshot,
+	// valid until SnapshotExpires (unix seconds).
+	Snapshot        string `json:"s,omitempty"`
+	SnapshotExpires int64  `json:"x,omitempty"`
+	// Partition keeps every page of a parallel scan partition within its id
+	// range (see Partition.Condition).
+	Partition *Partition `json:"p,omitempty"`
+	// Object is the ID of the object the cursor was issued for, and Schema
+	// the fingerprint of its sort field then (see sortFieldSchema).
+	Object string `json:"o,omitempty"`
+	Schema string `json:"g,omitempty"`
+}
+
+// CursorSigner signs the cursor tokens handed out with a page and verifies
+// those handed back, so a client can neither forge the sort value a page
+// starts from nor replay a cursor against another object or a sort field
+// whose definition has changed since.
+type CursorSigner struct {
+	key []byte
+}
+
+// NewCursorSigner returns a signer keyed by key; instances serving the same
+// clients must share it.
+func NewCursorSigner(key []byte) *CursorSigner {
+	return &CursorSigner{key: key}
+}
+
+// NewRandomCursorSigner returns a signer with a random key, whose cursors
+// only the same process accepts.
+func NewRandomCursorSigner() *CursorSigner {
+	key := make([]byte, 32)
+	rand.Read(key)
+	return NewCursorSigner(key)
+}
+
+// Encode returns the opaque token of a cursor on obj. orderVal is the last
+// row's sort value, nil when it has none.
+func (s *CursorSigner) Encode(obj *schema.ObjectDef, id string, orderVal *string, order *OrderClause) string {
+	return s.EncodeSnapshot(obj, id, orderVal, order, nil, "", time.Time{})
 }
 
-// EncodeCursor returns an opaque base64 token for the cursor.
-func EncodeCursor(id string, orderVal string) string {
-	c := Cursor{ID: id, OrderVal: orderVal}
+// EncodeSnapshot is Encode for a page of partition (nil for a whole-object
+// List) read from snapshot, which later pages keep reading until expires.
+func (s *CursorSigner) EncodeSnapshot(obj *schema.ObjectDef, id string, orderVal *string, order *OrderClause, partition *Partition, snapshot string, expires time.Time) string {
+	c := Cursor{ID: id, Object: obj.ID.String(), Snapshot: snapshot, Partition: partition}
+	if order != nil {
+		c.OrderField = order.FieldAPIName
+		c.Desc = order.Desc
+		c.NullsFirst = order.NullsFirst
+		c.Schema = sortFieldSchema(obj.FieldsByAPIName[order.FieldAPIName])
+		if orderVal != nil {
+			c.OrderVal = *orderVal
+		} else {
+			c.Null = true
+		}
+	}
+	if snapshot != "" {
+		c.SnapshotExpires = expires.Unix()
+	}
 	b, _ := json.Marshal(c)
-	return base64.RawURLEncoding.EncodeToString(b)
+	payload := base64.RawURLEncoding.EncodeToString(b)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(s.sign(payload))
 }
 
-// DecodeCursor parses a cursor token. Accepts both base64 tokens and plain UUIDs.
-func DecodeCursor(raw string) (*Cursor, error) {
+// Decode parses and verifies a cursor token handed back for obj. Plain UUIDs,
+// which carry no sort value, are accepted unsigned.
+func (s *CursorSigner) Decode(obj *schema.ObjectDef, raw string) (*Cursor, error) {
 	// Plain UUID (backward compat / default id-only ordering)
 	if _, err := uuid.Parse(raw); err == nil {
 		return &Cursor{ID: raw}, nil
 	}
-	b, err := base64.RawURLEncoding.DecodeString(raw)
+	payload, sig, ok := strings.Cut(raw, ".")
+	if !ok {
+		return nil, fmt.Errorf("unsigned cursor")
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || s == nil || !hmac.Equal(mac, s.sign(payload)) {
+		return nil, fmt.Errorf("cursor signature mismatch")
+	}
+	b, err := base64.RawURLEncoding.DecodeString(payload)
 	if err != nil {
 		return nil, fmt.Errorf("invalid cursor encoding")
 	}
@@ -69,25 +173,138 @@ func DecodeCursor(raw string) (*Cursor, error) {
 	if _, err := uuid.Parse(c.ID); err != nil {
 		return nil, fmt.Errorf("invalid cursor id")
 	}
+	if c.Partition != nil && !c.Partition.valid() {
+		return nil, fmt.Errorf("invalid cursor partition")
+	}
+	if c.Object != obj.ID.String() {
+		return nil, fmt.Errorf("cursor was issued for another object than %q", obj.APIName)
+	}
 	return &c, nil
 }
 
+// sign returns the truncated HMAC-SHA256 of a cursor payload.
+func (s *CursorSigner) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)[:16]
+}
+
+// sortFieldSchema fingerprints the definition of a cursor's sort field, so a
+// cursor outlives neither a changed type nor a field dropped and re-created
+// under the same name.
+func sortFieldSchema(fd *schema.FieldDef) string {
+	if fd == nil {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(fd.ID.String() + "/" + string(fd.Type)))
+	return hex.EncodeToString(sum[:6])
+}
+
+// SnapshotExpired reports whether the cursor pins a snapshot whose TTL has passed.
+func (c *Cursor) SnapshotExpired(now time.Time) bool {
+	return c.Snapshot != "" && now.Unix() >= c.SnapshotExpires
+}
+
+// CursorInvalidatedError is returned by ParseParams when a cursor no longer
+// matches the object schema or the requested ordering. Clients should restart
+// pagination without a cursor.
+type CursorInvalidatedError struct {
+	OrderField string
+	Reason     string
+}
+
+func (e *CursorInvalidatedError) Error() string {
+	return fmt.Sprintf("CURSOR_INVALIDATED: %s; restart pagination without a cursor", e.Reason)
+}
+
+// checkCursor verifies that a cursor still applies to obj and the requested order.
+// Cursors issued before OrderField was recorded are only checked for a missing order.
+func checkCursor(c *Cursor, obj *schema.ObjectDef, order *OrderClause) error {
+	if c.OrderField != "" {
+		fd, ok := obj.FieldsByAPIName[c.OrderField]
+		if !ok {
+			return &CursorInvalidatedError{
+				OrderField: c.OrderField,
+				Reason:     fmt.Sprintf("sort field %q no longer exists on %q", c.OrderField, obj.APIName),
+			}
+		}
+		if c.Schema != "" && c.Schema != sortFieldSchema(fd) {
+			return &CursorInvalidatedError{
+				OrderField: c.OrderField,
+				Reason:     fmt.Sprintf("sort field %q changed since the cursor was issued", c.OrderField),
+			}
+		}
+		// A cursor issued before a rename of its sort field names it by the
+		// alias; it still continues the order by the field's current name.
+		issued := OrderClause{FieldAPIName: fd.APIName, Desc: c.Desc, NullsFirst: c.NullsFirst}
+		if order == nil || *order != issued {
+			return &CursorInvalidatedError{
+				OrderField: c.OrderField,
+				Reason:     fmt.Sprintf("cursor was issued for order %q, request orders by %q", orderClauseString(&issued), orderClauseString(order)),
+			}
+		}
+		return nil
+	}
+	if (c.OrderVal != "" || c.Null) && order == nil {
+		return &CursorInvalidatedError{Reason: "cursor was issued for an ordered query, request has no order"}
+	}
+	return nil
+}
+
+// orderClauseString renders o as ParseOrder reads it, with the default
+// placement of nulls left out.
+func orderClauseString(o *OrderClause) string {
+	if o == nil {
+		return ""
+	}
+	s := o.FieldAPIName
+	if o.Desc {
+		s += ".desc"
+	}
+	if o.NullsFirst {
+		s += ".nullsfirst"
+	}
+	return s
+}
+
 type QueryParams struct {
-	Select      []string
-	Expand      []string
-	ExpandPlans []ExpandPlan
-	Conditions  []hrql.Condition // storage-agnostic conditions (from REST filters + HRQL plan)
-	Order       *OrderClause
-	Limit       int
-	Cursor      *Cursor
+	Select []string
+	// ExpandSelect holds the nested fields of dotted select entries by expand
+	// path, e.g. select=department.title gives {"department": ["title"]}.
+	ExpandSelect map[string][]string
+	Expand       []string
+	ExpandPlans  []ExpandPlan
+	Conditions   []hrql.Condition // storage-agnostic conditions (from REST filters + HRQL plan)
+	Order        *OrderClause
+	Limit        int
+	Cursor       *Cursor
+	// Random orders rows by random() instead of Order; such lists have no cursor.
+	Random bool
+	// SamplePercent, when set, reads only that percentage of the table's rows
+	// (TABLESAMPLE BERNOULLI) before filtering; see SamplePercent.
+	SamplePercent float64
+	// Lock locks the rows of a BuildIDs page FOR UPDATE, for a write
+	// transaction about to change them (see BuildUpdateIDs).
+	Lock bool
 
 	SQLConditions []sq.Sqlizer // translated SQL conditions, populated after TranslateConditions
+
+	// Computed adds SQL-computed keys to each record (from SQLResult.Computed).
+	Computed []ComputedColumn
+	// RelatedCounts add the expand=<relation>:count keys to each record.
+	RelatedCounts []RelatedCount
+
+	// ExpandStrategy is the resolved strategy for ExpandPlans. With ExpandBatch the
+	// expanded fields carry raw foreign keys for the caller to stitch.
+	ExpandStrategy ExpandStrategy
 }
 
 // ParseParams builds QueryParams from a transport-agnostic ParamsInput.
 func ParseParams(obj *schema.ObjectDef, input ParamsInput) (*QueryParams, error) {
+	limits := input.Limits.For(obj)
+	maxLimit := limits.MaxPageSize
 	p := &QueryParams{
-		Limit: DefaultLimit,
+		Limit: limits.DefaultPageSize,
 	}
 
 	// select
@@ -97,10 +314,20 @@ func ParseParams(obj *schema.ObjectDef, input ParamsInput) (*QueryParams, error)
 			if f == "" {
 				continue
 			}
-			if _, ok := obj.FieldsByAPIName[f]; !ok {
+			if strings.Contains(f, ".") {
+				if err := p.addNestedSelect(obj, f, input.Cache); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			fd, ok := obj.FieldsByAPIName[f]
+			if !ok {
 				return nil, fmt.Errorf("unknown field %q in select", f)
 			}
-			p.Select = append(p.Select, f)
+			// Former names select the field under its current one.
+			if !slices.Contains(p.Select, fd.APIName) {
+				p.Select = append(p.Select, fd.APIName)
+			}
 		}
 	}
 
@@ -111,56 +338,84 @@ func ParseParams(obj *schema.ObjectDef, input ParamsInput) (*QueryParams, error)
 			if f == "" {
 				continue
 			}
+			if strings.Contains(f, ":") {
+				rc, err := parseRelatedCount(obj, f, input)
+				if err != nil {
+					return nil, err
+				}
+				p.addRelatedCount(rc)
+				continue
+			}
 			topLevel := f
 			if before, _, ok := strings.Cut(f, "."); ok {
 				topLevel = before
 			}
 			fd, ok := obj.FieldsByAPIName[topLevel]
+			if !ok && input.Cache != nil && reverseExpand(topLevel, obj, input.Cache) != nil {
+				p.Expand = append(p.Expand, f)
+				continue
+			}
 			if !ok {
 				return nil, fmt.Errorf("unknown field %q in expand", topLevel)
 			}
 			if fd.Type != schema.FieldLookup {
 				return nil, fmt.Errorf("field %q is not a LOOKUP field, cannot expand", topLevel)
 			}
-			p.Expand = append(p.Expand, f)
+			p.Expand = append(p.Expand, fd.APIName+strings.TrimPrefix(f, topLevel))
+		}
+	}
+	for path := range p.ExpandSelect {
+		expanded := slices.ContainsFunc(p.Expand, func(e string) bool {
+			return e == path || strings.HasPrefix(e, path+".")
+		})
+		if !expanded {
+			return nil, fmt.Errorf("select of %q fields requires expand=%s", path, path)
 		}
 	}
 
+	// cursor (decoded before order so a deleted sort field reports CURSOR_INVALIDATED)
+	if input.Cursor != "" {
+		c, err := input.Cursors.Decode(obj, input.Cursor)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor %q: %w", input.Cursor, err)
+		}
+		if c.OrderField != "" && obj.FieldsByAPIName[c.OrderField] == nil {
+			return nil, checkCursor(c, obj, nil)
+		}
+		p.Cursor = c
+	}
+
 	// order
 	if input.Order != "" {
-		parts := strings.SplitN(input.Order, ".", 2)
-		fieldName := parts[0]
-		if _, ok := obj.FieldsByAPIName[fieldName]; !ok {
-			return nil, fmt.Errorf("unknown field %q in order", fieldName)
-		}
-		clause := &OrderClause{FieldAPIName: fieldName}
-		if len(parts) == 2 && strings.EqualFold(parts[1], "desc") {
-			clause.Desc = true
+		clause, err := ParseOrder(obj, input.Order)
+		if err != nil {
+			return nil, err
 		}
 		p.Order = clause
+	} else if obj.DefaultOrder != "" {
+		// A default naming a since-deleted field falls back to id order.
+		p.Order, _ = ParseOrder(obj, obj.DefaultOrder)
+	}
+
+	if p.Cursor != nil {
+		if err := checkCursor(p.Cursor, obj, p.Order); err != nil {
+			return nil, err
+		}
 	}
 
 	// limit
 	if input.Limit > 0 {
-		n := min(int(input.Limit), MaxLimit)
+		n := min(int(input.Limit), maxLimit)
 		p.Limit = n
 	}
 
-	// cursor
-	if input.Cursor != "" {
-		c, err := DecodeCursor(input.Cursor)
-		if err != nil {
-			return nil, fmt.Errorf("invalid cursor %q: %w", input.Cursor, err)
-		}
-		p.Cursor = c
-	}
-
 	// filters
 	for key, value := range input.Filters {
-		if _, ok := obj.FieldsByAPIName[key]; !ok {
+		fd, ok := obj.FieldsByAPIName[key]
+		if !ok {
 			return nil, fmt.Errorf("unknown filter field %q", key)
 		}
-		cond, err := ParseFilterCondition(key, value)
+		cond, err := ParseFilterCondition(fd, value)
 		if err != nil {
 			return nil, fmt.Errorf("filter %q: %w", key, err)
 		}
@@ -170,6 +425,76 @@ func ParseParams(obj *schema.ObjectDef, input ParamsInput) (*QueryParams, error)
 	return p, nil
 }
 
+// addNestedSelect records a dotted select entry such as "department.title" or
+// "department.organization.title": the top-level lookup joins Select and each
+// further segment is projected from the expand path before it. Nested names
+// are checked against the expand targets by ProjectExpands.
+func (p *QueryParams) addNestedSelect(obj *schema.ObjectDef, entry string, cache *schema.Cache) error {
+	segments := strings.Split(entry, ".")
+	if len(segments) > maxExpandDepth+1 || slices.Contains(segments, "") {
+		return fmt.Errorf("invalid select path %q", entry)
+	}
+	top := segments[0]
+	fd, ok := obj.FieldsByAPIName[top]
+	switch {
+	case !ok && cache != nil && reverseExpand(top, obj, cache) != nil:
+		// A reverse expand's records; obj has no field to select.
+	case !ok:
+		return fmt.Errorf("unknown field %q in select", top)
+	case fd.Type != schema.FieldLookup:
+		return fmt.Errorf("field %q is not a LOOKUP field, cannot select %q", top, entry)
+	default:
+		segments[0] = fd.APIName
+		if !slices.Contains(p.Select, fd.APIName) {
+			p.Select = append(p.Select, fd.APIName)
+		}
+	}
+	if p.ExpandSelect == nil {
+		p.ExpandSelect = make(map[string][]string)
+	}
+	for i := 1; i < len(segments); i++ {
+		path := strings.Join(segments[:i], ".")
+		if !slices.Contains(p.ExpandSelect[path], segments[i]) {
+			p.ExpandSelect[path] = append(p.ExpandSelect[path], segments[i])
+		}
+	}
+	return nil
+}
+
+// ParseOrder parses an order clause against obj: a field, then optionally
+// asc or desc, then optionally nullsfirst or nullslast ("end_date.desc.nullsfirst").
+// NULLs sort last by default in either direction.
+func ParseOrder(obj *schema.ObjectDef, order string) (*OrderClause, error) {
+	fieldName, rest, _ := strings.Cut(order, ".")
+	clause := &OrderClause{FieldAPIName: fieldName}
+	if rest != "" {
+		mods := strings.Split(strings.ToLower(rest), ".")
+		if mods[0] == "asc" || mods[0] == "desc" {
+			clause.Desc = mods[0] == "desc"
+			mods = mods[1:]
+		}
+		if len(mods) > 0 && (mods[0] == "nullsfirst" || mods[0] == "nullslast") {
+			clause.NullsFirst = mods[0] == "nullsfirst"
+			mods = mods[1:]
+		}
+		if len(mods) > 0 {
+			return nil, fmt.Errorf("invalid order %q: expected field[.asc|.desc][.nullsfirst|.nullslast]", order)
+		}
+	}
+	fd, ok := obj.FieldsByAPIName[fieldName]
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q in order", fieldName)
+	}
+	clause.FieldAPIName = fd.APIName
+	if fd.IsEncrypted() {
+		return nil, fmt.Errorf("field %q is ENCRYPTED and cannot be used in order", fieldName)
+	}
+	if err := fd.CheckSortable(); err != nil {
+		return nil, err
+	}
+	return clause, nil
+}
+
 // ResolveExpands resolves expand strings into ExpandPlans using the schema cache.
 func ResolveExpands(expands []string, obj *schema.ObjectDef, cache *schema.Cache) []ExpandPlan {
 	type nested struct{ parent, child string }
@@ -196,7 +521,14 @@ func ResolveExpands(expands []string, obj *schema.ObjectDef, cache *schema.Cache
 		seen[fn] = true
 
 		fd := obj.FieldsByAPIName[fn]
-		if fd == nil || fd.Type != schema.FieldLookup || fd.LookupObjectID == nil {
+		if fd == nil {
+			if ep := reverseExpand(fn, obj, cache); ep != nil {
+				planMap[fn] = ep
+				ordered = append(ordered, fn)
+			}
+			continue
+		}
+		if fd.Type != schema.FieldLookup || fd.LookupObjectID == nil {
 			continue
 		}
 		target := cache.GetByID(*fd.LookupObjectID)
@@ -231,3 +563,117 @@ func ResolveExpands(expands []string, obj *schema.ObjectDef, cache *schema.Cache
 	}
 	return plans
 }
+
+// reverseExpand resolves an expand naming another object to a Reverse plan
+// over its records pointing at obj. The back-reference is the object's only
+// LOOKUP to obj or, among several, its only required one (positions.employee
+// rather than positions.manager); otherwise the name resolves to nothing.
+func reverseExpand(name string, obj *schema.ObjectDef, cache *schema.Cache) *ExpandPlan {
+	related := cache.Get(name)
+	if related == nil {
+		return nil
+	}
+	var refs, required []*schema.FieldDef
+	for i := range related.Fields {
+		fd := &related.Fields[i]
+		if fd.Type != schema.FieldLookup || fd.LookupObjectID == nil || *fd.LookupObjectID != obj.ID {
+			continue
+		}
+		refs = append(refs, fd)
+		if fd.IsRequired {
+			required = append(required, fd)
+		}
+	}
+	switch {
+	case len(refs) == 1:
+		return &ExpandPlan{FieldName: name, Field: refs[0], Target: related, Reverse: true}
+	case len(required) == 1:
+		return &ExpandPlan{FieldName: name, Field: required[0], Target: related, Reverse: true}
+	default:
+		return nil
+	}
+}
+
+// ExpandCost is one expand's share of the ExpandCosts total: the columns its
+// records add to every result row, excluding nested expands.
+type ExpandCost struct {
+	Path    string // e.g. "manager.department"
+	Columns int
+}
+
+// ExpandCosts estimates what expand plans add to every result row: each
+// expanded record contributes its projected fields (every field of the
+// target without a select), nested expands included. Costs are ordered
+// most expensive first.
+func ExpandCosts(plans []ExpandPlan) (total int, costs []ExpandCost) {
+	var walk func(plans []ExpandPlan, prefix string)
+	walk = func(plans []ExpandPlan, prefix string) {
+		for _, ep := range plans {
+			cols := len(ep.Target.FieldsByAPIName)
+			if ep.Select != nil {
+				cols = len(ep.Select) + len(schema.SystemFieldNames())
+			}
+			total += cols
+			costs = append(costs, ExpandCost{Path: prefix + ep.FieldName, Columns: cols})
+			walk(ep.Children, prefix+ep.FieldName+".")
+		}
+	}
+	walk(plans, "")
+	slices.SortStableFunc(costs, func(a, b ExpandCost) int { return b.Columns - a.Columns })
+	return total, costs
+}
+
+// ExpandBudgetError is returned by CheckExpandBudget.
+type ExpandBudgetError struct {
+	Columns, Budget int
+	Costs           []ExpandCost
+}
+
+func (e *ExpandBudgetError) Error() string {
+	parts := make([]string, len(e.Costs))
+	for i, c := range e.Costs {
+		parts[i] = fmt.Sprintf("%s (%d)", c.Path, c.Columns)
+	}
+	return fmt.Sprintf("expands add %d columns per record, over the budget of %d: %s; "+
+		"select fewer fields of the expanded records (select=%s.<field>) or expand less",
+		e.Columns, e.Budget, strings.Join(parts, ", "), e.Costs[0].Path)
+}
+
+// CheckExpandBudget returns an *ExpandBudgetError when plans add more than
+// budget columns per record (see ExpandCosts); 0 disables the check. Call it
+// after ProjectExpands, which narrows the plans.
+func CheckExpandBudget(plans []ExpandPlan, budget int) error {
+	if budget <= 0 {
+		return nil
+	}
+	if total, costs := ExpandCosts(plans); total > budget {
+		return &ExpandBudgetError{Columns: total, Budget: budget, Costs: costs}
+	}
+	return nil
+}
+
+// ProjectExpands narrows each expand plan to the nested fields selected for
+// its path (QueryParams.ExpandSelect), so lateral joins and batch expands only
+// read the requested columns of the target.
+func ProjectExpands(plans []ExpandPlan, selects map[string][]string) error {
+	return projectExpands(plans, selects, "")
+}
+
+func projectExpands(plans []ExpandPlan, selects map[string][]string, prefix string) error {
+	for i := range plans {
+		ep := &plans[i]
+		path := prefix + ep.FieldName
+		if names, ok := selects[path]; ok {
+			for _, name := range names {
+				if ep.Target.FieldsByAPIName[name] == nil {
+					return fmt.Errorf("unknown field %q in select on %q", name, ep.Target.APIName)
+				}
+			}
+			ep.Select = names
+		}
+		if err := projectExpands(ep.Children, selects, path+"."); err != nil {
+			return err
+		}
+	}
+	return nil
+}
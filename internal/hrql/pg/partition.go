@@ -0,0 +1,96 @@
+package pg
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/atlekbai/schema_registry/internal/schema"
+	"github.com/google/uuid"
+)
+
+// Partition restricts a List cursor to the records whose id is in
+// (After, Until], one slice of a parallel scan. Ids are UUIDv7, so the
+// bounds come from the data (BuildPartitionBounds) rather than from splitting
+// the id space evenly.
+type Partition struct {
+	After string `json:"a"`
+	Until string `json:"u"`
+}
+
+const (
+	firstPartitionID = "00000000-0000-0000-0000-000000000000"
+	lastPartitionID  = "ffffffff-ffff-ffff-ffff-ffffffffffff"
+)
+
+// partitionSampleRows is about how many rows BuildPartitionBounds reads to
+// place the bounds of a large table.
+const partitionSampleRows = 100_000
+
+// BuildPartitionBounds returns SQL selecting, as one text[], the ids that
+// split the records matching params into n partitions of about equal size.
+// Standard tables of more than partitionSampleRows rows (the planner's
+// estimate) are read through TABLESAMPLE, so the split is approximate.
+func BuildPartitionBounds(obj *schema.ObjectDef, params *QueryParams, n int, rows float64) (string, []any, error) {
+	fractions := make([]float64, n-1)
+	for i := range fractions {
+		fractions[i] = float64(i+1) / float64(n)
+	}
+	from, baseWhere := TableSource(obj, qAlias)
+	if Sampleable(obj) && rows > partitionSampleRows {
+		pct := math.Ceil(100*partitionSampleRows/rows*1000) / 1000
+		from += tableSample(pct)
+	}
+	qb := sq.Select().
+		Column(sq.Expr(fmt.Sprintf(`percentile_disc(?::float8[]) WITHIN GROUP (ORDER BY %s."id")::text[]`, QI(qAlias)), fractions)).
+		From(from).PlaceholderFormat(sq.Dollar)
+	if baseWhere != nil {
+		qb = qb.Where(baseWhere)
+	}
+	for _, cond := range params.SQLConditions {
+		qb = qb.Where(cond)
+	}
+	return qb.ToSql()
+}
+
+// Partitions turns the ids selected by BuildPartitionBounds into ranges
+// covering every id. Repeated bounds (few matching records) are merged, so
+// there may be fewer partitions than requested.
+func Partitions(bounds []string) []Partition {
+	bounds = slices.Compact(slices.DeleteFunc(slices.Clone(bounds), func(b string) bool { return b == "" }))
+	parts := make([]Partition, 0, len(bounds)+1)
+	after := firstPartitionID
+	for _, b := range bounds {
+		parts = append(parts, Partition{After: after, Until: b})
+		after = b
+	}
+	return append(parts, Partition{After: after, Until: lastPartitionID})
+}
+
+// Condition returns the WHERE condition restricting a query to p.
+func (p Partition) Condition() sq.Sqlizer {
+	idCol := fmt.Sprintf(`%s."id"`, QI(qAlias))
+	return sq.And{sq.Gt{idCol: p.After}, sq.LtOrEq{idCol: p.Until}}
+}
+
+func (p Partition) valid() bool {
+	after, err1 := uuid.Parse(p.After)
+	until, err2 := uuid.Parse(p.Until)
+	return err1 == nil && err2 == nil && after.String() < until.String()
+}
+
+// Partition returns the partition the request's cursor reads, if any.
+func (p *QueryParams) Partition() *Partition {
+	if p.Cursor == nil {
+		return nil
+	}
+	return p.Cursor.Partition
+}
+
+// EncodePartition returns the cursor a List starts reading p from. Its
+// later pages stay in p and, with a snapshot, keep reading it.
+func (s *CursorSigner) EncodePartition(obj *schema.ObjectDef, p Partition, snapshot string, expires time.Time) string {
+	return s.EncodeSnapshot(obj, p.After, nil, nil, &p, snapshot, expires)
+}
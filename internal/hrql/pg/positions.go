@@ -0,0 +1,81 @@
+package pg
+
+import (
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/atlekbai/schema_registry/internal/hrql"
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// positionsTable is the storage of the standard positions object.
+const positionsTable = `"core"."positions"`
+
+// maxPositionDepth bounds walks of the position graph, like
+// ltreeutil.MaxDepth bounds the tree hierarchies.
+const maxPositionDepth = 64
+
+// activePosition is the condition for a position of alias being held today.
+func activePosition(alias string) string {
+	return fmt.Sprintf(`%[1]s."start_date" <= CURRENT_DATE AND (%[1]s."end_date" IS NULL OR %[1]s."end_date" >= CURRENT_DATE)`, QI(alias))
+}
+
+// positionReach returns a subquery yielding the ids of the employees reachable
+// from ref in the position-based reporting graph (hrql.ViaPositions): its
+// reports going down, or its managers going up. An employee reports to the
+// manager of every active position they hold, so an id can be reached along
+// several paths; depth > 0 keeps the ids reached in exactly depth steps along
+// some path, 0 keeps all of them. Walks stop after maxPositionDepth steps,
+// which also ends cycles.
+//
+//	(WITH RECURSIVE "_pg"("id", "depth") AS (
+//	  SELECT p.employee_id, 1 FROM positions p WHERE p.manager_id = <ref> AND <active>
+//	  UNION
+//	  SELECT p.employee_id, g.depth + 1 FROM positions p JOIN "_pg" g ON p.manager_id = g.id
+//	  WHERE <active> AND g.depth < ?
+//	) SELECT "id" FROM "_pg" [WHERE "depth" = ?])
+func positionReach(ref hrql.EmployeeRef, up bool, depth int, obj *schema.ObjectDef) (string, []any) {
+	from, to := `"manager_id"`, `"employee_id"`
+	if up {
+		from, to = to, from
+	}
+	refSQL, refArgs, _ := RefToSQL(ref, obj).ToSql()
+
+	sql := fmt.Sprintf(
+		`(WITH RECURSIVE "_pg"("id", "depth") AS (`+
+			`SELECT "_p".%[2]s, 1 FROM %[3]s "_p" WHERE "_p".%[1]s = %[4]s AND "_p".%[2]s IS NOT NULL AND %[5]s `+
+			`UNION `+
+			`SELECT "_p".%[2]s, "_pg"."depth" + 1 FROM %[3]s "_p" JOIN "_pg" ON "_p".%[1]s = "_pg"."id" `+
+			`WHERE "_p".%[2]s IS NOT NULL AND %[5]s AND "_pg"."depth" < ?`+
+			`) SELECT "id" FROM "_pg"`,
+		from, to, positionsTable, refSQL, activePosition("_p"),
+	)
+	args := concatArgs(refArgs, []any{maxPositionDepth})
+	if depth > 0 {
+		sql += ` WHERE "depth" = ?`
+		args = append(args, depth)
+	}
+	return sql + ")", args
+}
+
+// positionMember returns a condition matching the current row against the
+// employees positionReach yields, excluding ref itself.
+func positionMember(ref hrql.EmployeeRef, up bool, depth int, obj *schema.ObjectDef) sq.Sqlizer {
+	reachSQL, reachArgs := positionReach(ref, up, depth, obj)
+	refSQL, refArgs, _ := RefToSQL(ref, obj).ToSql()
+	sql := fmt.Sprintf(`%s."id" IN %s AND %s."id" != %s`, QI(Alias()), reachSQL, QI(Alias()), refSQL)
+	return sq.Expr(sql, concatArgs(reachArgs, refArgs)...)
+}
+
+// positionReportsToSQL builds the top-level reports_to(emp, target, via:
+// positions) query: whether target is among emp's managers at any level.
+func positionReportsToSQL(emp, target hrql.EmployeeRef, obj *schema.ObjectDef) (string, []any, error) {
+	tgtSQL, tgtArgs, _ := RefToSQL(target, obj).ToSql()
+	reachSQL, reachArgs := positionReach(emp, true, 0, obj)
+	sql, err := sq.Dollar.ReplacePlaceholders(fmt.Sprintf(`SELECT (%s IN %s)`, tgtSQL, reachSQL))
+	if err != nil {
+		return "", nil, err
+	}
+	return sql, concatArgs(tgtArgs, reachArgs), nil
+}
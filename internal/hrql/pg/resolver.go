@@ -1,6 +1,7 @@
 package pg
 
 import (
+	"cmp"
 	"fmt"
 
 	sq "github.com/Masterminds/squirrel"
@@ -11,41 +12,101 @@ import (
 
 // RefToSQL resolves an EmployeeRef to a SQL expression that yields an employee UUID.
 //   - {ID: "abc", Chain: nil}          → $1 (bind "abc")
-//   - {ID: "abc", Chain: ["manager"]}  → (SELECT "manager_id" FROM "core"."employees" WHERE "id" = $1)
+//   - {ID: "abc", Chain: ["manager"]}  → (SELECT "manager_id" FROM <obj table> WHERE "id" = $1)
+//   - {Source: plan}                   → (SELECT "_e"."id" FROM ... WHERE <plan> ORDER BY ... LIMIT 1)
 func RefToSQL(ref hrql.EmployeeRef, obj *schema.ObjectDef) sq.Sqlizer {
+	sql, args := "?", []any{ref.ID}
+	if ref.Source != nil {
+		sql, args = sourceSubquery(ref.Source, obj)
+	}
 	if len(ref.Chain) == 0 {
-		return sq.Expr("?", ref.ID)
+		return sq.Expr(sql, args...)
 	}
 
 	// Walk the chain: each step dereferences a LOOKUP field.
 	// Start from the base ID, wrap in nested subqueries.
-	sql := "?"
-	args := []any{ref.ID}
-
 	for _, fieldName := range ref.Chain {
-		col := ResolveColumn(obj, fieldName)
 		sql = fmt.Sprintf(
 			`(SELECT %s FROM %s WHERE "id" = %s)`,
-			QI(col), obj.TableName(), sql,
+			chainColumn(obj, fieldName), obj.TableName(), sql,
 		)
 	}
 
 	return sq.Expr(sql, args...)
 }
 
-// PathSubquery wraps an EmployeeRef in a subquery that yields the manager_path.
-// Result: (SELECT "manager_path" FROM "core"."employees" WHERE "id" = <RefToSQL>)
-func PathSubquery(ref hrql.EmployeeRef, obj *schema.ObjectDef) sq.Sqlizer {
+// chainColumn returns the unqualified expression a chain step dereferences:
+// the storage column, or the uuid stored in the document for custom LOOKUPs.
+func chainColumn(obj *schema.ObjectDef, fieldName string) string {
+	if fd := obj.FieldsByAPIName[fieldName]; fd != nil && fd.StorageColumn == nil {
+		return fmt.Sprintf(`(%s->>%s)::uuid`, QI(cmp.Or(fd.DocColumn, "data")), QuoteLit(fd.APIName))
+	}
+	return QI(ResolveColumn(obj, fieldName))
+}
+
+// sourceSubquery renders a single-record plan as a scalar subquery yielding its
+// employee's id, ordered like the list query would be (plan order, then id).
+// The compiler only admits plans that translate without the schema cache.
+func sourceSubquery(plan *hrql.Plan, obj *schema.ObjectDef) (string, []any) {
+	from, base := TableSource(obj, Alias())
+	qb := sq.Select(fmt.Sprintf(`%s."id"`, QI(Alias()))).From(from)
+	if base != nil {
+		qb = qb.Where(base)
+	}
+	for _, c := range plan.Conditions {
+		cond, err := ConditionToSQL(c, obj, nil)
+		if err != nil {
+			return "(SELECT NULL::uuid)", nil
+		}
+		qb = qb.Where(cond)
+	}
+
+	if plan.OrderBy != nil && plan.OrderBy.Random {
+		sql, args, _ := qb.OrderBy("random()").Limit(1).ToSql()
+		return "(" + sql + ")", args
+	}
+
+	dir := "ASC"
+	if plan.OrderBy != nil && plan.OrderBy.Desc {
+		dir = "DESC"
+	}
+	if plan.OrderBy != nil {
+		if fd := obj.FieldsByAPIName[plan.OrderBy.Field]; fd != nil {
+			nulls := nullsOrder(&OrderClause{NullsFirst: plan.OrderBy.NullsFirst})
+			qb = qb.OrderBy(fmt.Sprintf(`%s %s %s`, FilterExpr(Alias(), fd), dir, nulls))
+		}
+	}
+	qb = qb.OrderBy(fmt.Sprintf(`%s."id" %s`, QI(Alias()), dir)).Limit(1)
+
+	sql, args, _ := qb.ToSql()
+	return "(" + sql + ")", args
+}
+
+// HierarchyPath returns the ltree column of obj's hierarchy along the
+// self-lookup field via, or along its default hierarchy for via "" (see
+// ObjectDef.Hierarchy); manager_path on employees. It is empty when obj has
+// no such hierarchy, which the compiler rejects before translation.
+func HierarchyPath(obj *schema.ObjectDef, via string) string {
+	if fd := obj.Hierarchy(via); fd != nil {
+		return fd.PathColumn
+	}
+	return ""
+}
+
+// PathSubquery wraps an EmployeeRef in a subquery that yields its path in the
+// hierarchy along via (see HierarchyPath).
+// Result: (SELECT <path column> FROM <obj table> WHERE "id" = <RefToSQL>)
+func PathSubquery(ref hrql.EmployeeRef, via string, obj *schema.ObjectDef) sq.Sqlizer {
 	refSQL, refArgs, _ := RefToSQL(ref, obj).ToSql()
 	sql := fmt.Sprintf(
-		`(SELECT "manager_path" FROM %s WHERE "id" = %s)`,
-		obj.TableName(), refSQL,
+		`(SELECT %s FROM %s WHERE "id" = %s)`,
+		QI(HierarchyPath(obj, via)), obj.TableName(), refSQL,
 	)
 	return sq.Expr(sql, refArgs...)
 }
 
 // FieldSubquery wraps an EmployeeRef in a subquery that yields a specific field value.
-// Result: (SELECT "col" FROM "core"."employees" WHERE "id" = <RefToSQL>)
+// Result: (SELECT "col" FROM <obj table> WHERE "id" = <RefToSQL>)
 func FieldSubquery(ref hrql.EmployeeRef, fieldAPIName string, obj *schema.ObjectDef) sq.Sqlizer {
 	col := ResolveColumn(obj, fieldAPIName)
 	refSQL, refArgs, _ := RefToSQL(ref, obj).ToSql()
@@ -0,0 +1,43 @@
+package pg
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// SampleScanMinRows is the estimated table size from which sample(n) reads a
+// TABLESAMPLE BERNOULLI fraction of the table instead of ordering every
+// matching row by random().
+const SampleScanMinRows = 100_000
+
+// sampleOversample scales the sampled fraction so that filters applied after
+// the table sample usually still leave n rows.
+const sampleOversample = 20
+
+// Sampleable reports whether obj's table can be read with TABLESAMPLE: only
+// plain standard tables can, as custom objects share metadata.records and
+// as_of reads a function.
+func Sampleable(obj *schema.ObjectDef) bool {
+	return obj.IsStandard && obj.TableExpr == ""
+}
+
+// SamplePercent returns the TABLESAMPLE BERNOULLI percentage for drawing n
+// rows from a table of about rows rows (pg_class.reltuples), or 0 when
+// ORDER BY random() LIMIT n should scan the table instead.
+func SamplePercent(n int, rows float64) float64 {
+	if rows < SampleScanMinRows {
+		return 0
+	}
+	pct := 100 * float64(n) * sampleOversample / rows
+	if pct >= 100 {
+		return 0
+	}
+	return math.Ceil(pct*1000) / 1000
+}
+
+// tableSample returns the TABLESAMPLE clause for a QueryParams.SamplePercent.
+func tableSample(pct float64) string {
+	return " TABLESAMPLE BERNOULLI (" + strconv.FormatFloat(pct, 'f', -1, 64) + ")"
+}
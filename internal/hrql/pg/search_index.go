@@ -0,0 +1,63 @@
+package pg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// SearchIndexName returns the name of the trigram index backing a searchable
+// field, e.g. "ix_search_0190...".
+func SearchIndexName(fd *schema.FieldDef) string {
+	return "ix_search_" + strings.ReplaceAll(fd.ID.String(), "-", "")
+}
+
+// searchIndexSchema returns the schema the field's search index lives in.
+func searchIndexSchema(obj *schema.ObjectDef) string {
+	if obj.IsStandard {
+		return *obj.StorageSchema
+	}
+	return "metadata"
+}
+
+// BuildSearchIndex returns the DDL creating a pg_trgm GIN index for a
+// searchable field, so contains, starts_with and ends_with (ILIKE) can use an
+// index scan. Standard columns are indexed directly; custom values are
+// indexed on their JSONB key expression, partial to the object for
+// metadata.records. The index is built CONCURRENTLY and must run outside a
+// transaction.
+func BuildSearchIndex(obj *schema.ObjectDef, fd *schema.FieldDef) string {
+	key := externalIDKey(obj, fd)
+	if obj.IsStandard && fd.StorageColumn != nil {
+		key = QI(*fd.StorageColumn)
+	}
+	// Accent-insensitive fields are matched on the unaccented value (see
+	// stringMatchToSQL), so that is what gets indexed.
+	if fd.AccentInsensitive {
+		key = "(" + unaccent(key) + ")"
+	}
+	if obj.IsStandard {
+		return fmt.Sprintf(`CREATE INDEX CONCURRENTLY IF NOT EXISTS %s ON %s USING gin (%s gin_trgm_ops)`,
+			QI(SearchIndexName(fd)), obj.TableName(), key)
+	}
+	return fmt.Sprintf(`CREATE INDEX CONCURRENTLY IF NOT EXISTS %s ON "metadata"."records" USING gin (%s gin_trgm_ops) WHERE "object_id" = %s::uuid`,
+		QI(SearchIndexName(fd)), key, QuoteLit(obj.ID.String()))
+}
+
+// unaccent wraps a text expression in the IMMUTABLE accent-stripping
+// function of migration 000028.
+func unaccent(expr string) string {
+	return `"metadata"."unaccent"(` + expr + `)`
+}
+
+// BuildDropSearchIndex returns the DDL dropping the index created by
+// BuildSearchIndex. Drop it concurrently outside a transaction, or plainly
+// inside one (when the field itself is deleted).
+func BuildDropSearchIndex(obj *schema.ObjectDef, fd *schema.FieldDef, concurrently bool) string {
+	drop := "DROP INDEX"
+	if concurrently {
+		drop += " CONCURRENTLY"
+	}
+	return fmt.Sprintf(`%s IF EXISTS %s.%s`, drop, QI(searchIndexSchema(obj)), QI(SearchIndexName(fd)))
+}
@@ -0,0 +1,110 @@
+package pg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// Sort indexes serve keyset pagination over document fields, whose values
+// have no index of their own. A page is ordered by (nulls key, value, id),
+// all in the list's direction, where the nulls key is the value's IS NULL or
+// IS NOT NULL test, whichever places NULLs as asked. The cursor predicate
+// compares that row as a whole, which a btree on the same row answers with
+// a single range scan. Each field gets two indexes: one on the IS NULL key,
+// scanned forward for ascending pages with NULLs last and backward for
+// descending ones with NULLs first, and one on the IS NOT NULL key for the
+// other two orders.
+
+// SortIndexNames returns the names of the two sort indexes of a field, e.g.
+// "ix_sort_0190..." (IS NULL key) and "ix_sort_0190..._nn" (IS NOT NULL).
+func SortIndexNames(fd *schema.FieldDef) [2]string {
+	name := "ix_sort_" + strings.ReplaceAll(fd.ID.String(), "-", "")
+	return [2]string{name, name + "_nn"}
+}
+
+// HasSortIndexes reports whether fd is indexed for sorting when flagged
+// is_sortable: document values of types with an order. Storage columns are
+// indexed by their table's own DDL.
+func HasSortIndexes(fd *schema.FieldDef) bool {
+	if fd.StorageColumn != nil || fd.NotSortable {
+		return false
+	}
+	switch fd.Type {
+	case schema.FieldEncrypted, schema.FieldJSON, schema.FieldFormula, schema.FieldMultichoice:
+		return false
+	}
+	return true
+}
+
+// sortValue returns the expression a document field is ordered by, read from
+// the JSONB column doc. Dates go through metadata.sort_timestamptz
+// (migration 000031), which unlike the ::timestamptz cast can be indexed.
+func sortValue(doc string, fd *schema.FieldDef) string {
+	key := fmt.Sprintf(`%s->>%s`, doc, QuoteLit(fd.APIName))
+	switch {
+	case fd.IsNumeric():
+		return fmt.Sprintf(`(%s)::numeric`, key)
+	case fd.Type == schema.FieldDate || fd.Type == schema.FieldDatetime:
+		return `"metadata"."sort_timestamptz"(` + key + `)`
+	}
+	return key
+}
+
+// sortExpr returns the expression fd is ordered by on the row aliased alias.
+func sortExpr(alias string, fd *schema.FieldDef) string {
+	if fd.StorageColumn != nil {
+		return FilterExpr(alias, fd)
+	}
+	return sortValue(docRef(alias, fd), fd)
+}
+
+// nullsKey returns the nulls key an order over expr sorts by first, and
+// whether it is the IS NULL test; "" for storage columns, which are ordered
+// with NULLS FIRST/LAST and left to the indexes of their table.
+func nullsKey(expr string, fd *schema.FieldDef, o *OrderClause) (string, bool) {
+	if fd.StorageColumn != nil {
+		return "", false
+	}
+	// IS NULL is false, so first, for values: ascending it puts NULLs last,
+	// descending first.
+	if isNull := o.NullsFirst == o.Desc; isNull {
+		return fmt.Sprintf(`(%s IS NULL)`, expr), true
+	}
+	return fmt.Sprintf(`(%s IS NOT NULL)`, expr), false
+}
+
+// BuildSortIndexes returns the DDL creating the two sort indexes of a
+// document field, partial to the object on metadata.records. They are built
+// CONCURRENTLY and must run outside a transaction.
+func BuildSortIndexes(obj *schema.ObjectDef, fd *schema.FieldDef) []string {
+	value := sortValue(QI(obj.DocumentColumn()), fd)
+	table, where := obj.TableName(), ""
+	if !obj.IsStandard {
+		table = `"metadata"."records"`
+		where = fmt.Sprintf(` WHERE "object_id" = %s::uuid`, QuoteLit(obj.ID.String()))
+	}
+	names := SortIndexNames(fd)
+	ddl := make([]string, len(names))
+	for i, test := range []string{"IS NULL", "IS NOT NULL"} {
+		ddl[i] = fmt.Sprintf(`CREATE INDEX CONCURRENTLY IF NOT EXISTS %s ON %s ((%s %s), (%s), "id")%s`,
+			QI(names[i]), table, value, test, value, where)
+	}
+	return ddl
+}
+
+// BuildDropSortIndexes returns the DDL dropping the indexes created by
+// BuildSortIndexes, concurrently outside a transaction or plainly inside one.
+func BuildDropSortIndexes(obj *schema.ObjectDef, fd *schema.FieldDef, concurrently bool) []string {
+	drop := "DROP INDEX"
+	if concurrently {
+		drop += " CONCURRENTLY"
+	}
+	names := SortIndexNames(fd)
+	ddl := make([]string, len(names))
+	for i, name := range names {
+		ddl[i] = fmt.Sprintf(`%s IF EXISTS %s.%s`, drop, QI(searchIndexSchema(obj)), QI(name))
+	}
+	return ddl
+}
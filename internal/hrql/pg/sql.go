@@ -1,6 +1,7 @@
 package pg
 
 import (
+	"cmp"
 	"fmt"
 
 	sq "github.com/Masterminds/squirrel"
@@ -23,7 +24,7 @@ func SelectFieldExpr(alias string, fd *schema.FieldDef) string {
 	if fd.StorageColumn != nil {
 		return fmt.Sprintf(`%s.%s`, QI(alias), QI(*fd.StorageColumn))
 	}
-	return fmt.Sprintf(`%s."data"->%s`, QI(alias), QuoteLit(fd.APIName))
+	return fmt.Sprintf(`%s->%s`, docRef(alias, fd), QuoteLit(fd.APIName))
 }
 
 // FilterExpr returns the SQL for a field in WHERE/ORDER context (text extraction via ->> with casts).
@@ -32,12 +33,18 @@ func FilterExpr(alias string, fd *schema.FieldDef) string {
 		return fmt.Sprintf(`%s.%s`, QI(alias), QI(*fd.StorageColumn))
 	}
 	if fd.IsNumeric() {
-		return fmt.Sprintf(`(%s."data"->>%s)::numeric`, QI(alias), QuoteLit(fd.APIName))
+		return fmt.Sprintf(`(%s->>%s)::numeric`, docRef(alias, fd), QuoteLit(fd.APIName))
 	}
 	if fd.Type == schema.FieldDate || fd.Type == schema.FieldDatetime {
-		return fmt.Sprintf(`(%s."data"->>%s)::timestamptz`, QI(alias), QuoteLit(fd.APIName))
+		return fmt.Sprintf(`(%s->>%s)::timestamptz`, docRef(alias, fd), QuoteLit(fd.APIName))
 	}
-	return fmt.Sprintf(`%s."data"->>%s`, QI(alias), QuoteLit(fd.APIName))
+	return fmt.Sprintf(`%s->>%s`, docRef(alias, fd), QuoteLit(fd.APIName))
+}
+
+// docRef returns the JSONB document column a field without a storage column
+// lives in. Definitions built outside the cache default to metadata.records.
+func docRef(alias string, fd *schema.FieldDef) string {
+	return fmt.Sprintf(`%s.%s`, QI(alias), QI(cmp.Or(fd.DocColumn, "data")))
 }
 
 // jsonKey returns the JSON output key for a field.
@@ -60,7 +67,7 @@ func FKRef(alias string, fd *schema.FieldDef) string {
 	if fd.StorageColumn != nil {
 		return fmt.Sprintf(`%s.%s`, QI(alias), QI(*fd.StorageColumn))
 	}
-	return fmt.Sprintf(`(%s."data"->>%s)::uuid`, QI(alias), QuoteLit(fd.APIName))
+	return fmt.Sprintf(`(%s->>%s)::uuid`, docRef(alias, fd), QuoteLit(fd.APIName))
 }
 
 // TableSource returns the FROM clause and optional base WHERE for an object.
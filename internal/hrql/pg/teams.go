@@ -0,0 +1,27 @@
+package pg
+
+import (
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// teamsTable and teamMembershipsTable are the storage of the standard teams
+// and team_memberships objects.
+const (
+	teamsTable           = `"core"."teams"`
+	teamMembershipsTable = `"core"."team_memberships"`
+)
+
+// TeamMemberWhere returns a condition matching the employees on the team
+// with the given slug:
+//
+//	EXISTS (SELECT 1 FROM team_memberships m JOIN teams t ON t.id = m.team_id
+//	        WHERE m.employee_id = _e.id AND t.slug = ?)
+func TeamMemberWhere(slug string) sq.Sqlizer {
+	sql := fmt.Sprintf(
+		`EXISTS (SELECT 1 FROM %s "_tm" JOIN %s "_t" ON "_t"."id" = "_tm"."team_id" WHERE "_tm"."employee_id" = %s."id" AND "_t"."slug" = ?)`,
+		teamMembershipsTable, teamsTable, QI(Alias()),
+	)
+	return sq.Expr(sql, slug)
+}
@@ -1,7 +1,9 @@
 package pg
 
 import (
+	"cmp"
 	"fmt"
+	"strings"
 
 	sq "github.com/Masterminds/squirrel"
 
@@ -16,8 +18,15 @@ type SQLResult struct {
 	Limit      int
 	PickOp     string
 	PickN      int
-
-	// For PlanScalar: pre-built aggregate query.
+	// Random orders the list by random() (sort_by(random), sample(n)); OrderBy is nil.
+	Random bool
+	// Sample is n of sample(n), 0 otherwise.
+	Sample int
+	// Computed holds per-record values projected by the plan, e.g. case(...).
+	Computed []ComputedColumn
+
+	// For PlanScalar: pre-built aggregate query. For PlanBuckets: the query
+	// returning each non-empty range's index and record count (buildBuckets).
 	AggSQL  string
 	AggArgs []any
 }
@@ -28,14 +37,46 @@ func Translate(plan *hrql.Plan, obj *schema.ObjectDef, cache *schema.Cache) (*SQ
 		Limit:  plan.Limit,
 		PickOp: plan.PickOp,
 		PickN:  plan.PickN,
+		Sample: plan.Sample,
 	}
 
 	// Translate ordering.
-	if plan.OrderBy != nil {
+	if plan.OrderBy != nil && plan.OrderBy.Random {
+		result.Random = true
+	} else if plan.OrderBy != nil {
 		result.OrderBy = &OrderClause{
 			FieldAPIName: plan.OrderBy.Field,
 			Desc:         plan.OrderBy.Desc,
+			NullsFirst:   plan.OrderBy.NullsFirst,
+		}
+	}
+
+	if plan.Kind == hrql.PlanList && plan.Case != nil {
+		sql, args, err := CaseToSQL(plan.Case, obj, cache)
+		if err != nil {
+			return nil, err
+		}
+		result.Computed = append(result.Computed, ComputedColumn{Key: CaseKey, SQL: sql, Args: args})
+	}
+	if plan.Kind == hrql.PlanList && plan.Since != "" {
+		fd := obj.FieldsByAPIName[plan.AggField]
+		if fd == nil {
+			return nil, fmt.Errorf("unknown field %q", plan.AggField)
+		}
+		result.Computed = append(result.Computed, ComputedColumn{Key: plan.Since + "_since", SQL: sinceSQL(plan.Since, FilterExpr(Alias(), fd))})
+	}
+
+	// A union's sources carry their own conditions; lists are built by
+	// BuildUnionList.
+	if plan.Union != nil {
+		if plan.Kind == hrql.PlanScalar {
+			sql, args, err := buildUnionScalar(plan, obj, cache)
+			if err != nil {
+				return nil, fmt.Errorf("build scalar: %w", err)
+			}
+			result.AggSQL, result.AggArgs = sql, args
 		}
+		return result, nil
 	}
 
 	// Translate conditions.
@@ -58,7 +99,7 @@ func Translate(plan *hrql.Plan, obj *schema.ObjectDef, cache *schema.Cache) (*SQ
 		if plan.ScalarExpr != nil {
 			sql, args, err = buildArithmeticQuery(plan.ScalarExpr, obj, cache)
 		} else {
-			sql, args, err = buildAggregate(obj, plan.AggFunc, plan.AggField, result.Conditions)
+			sql, args, err = buildAggregate(obj, plan, result.Conditions, cache)
 		}
 		if err != nil {
 			return nil, fmt.Errorf("build scalar: %w", err)
@@ -66,6 +107,14 @@ func Translate(plan *hrql.Plan, obj *schema.ObjectDef, cache *schema.Cache) (*SQ
 		result.AggSQL = sql
 		result.AggArgs = args
 	}
+	if plan.Kind == hrql.PlanBuckets {
+		sql, args, err := buildBuckets(obj, plan, result.Conditions)
+		if err != nil {
+			return nil, fmt.Errorf("build buckets: %w", err)
+		}
+		result.AggSQL = sql
+		result.AggArgs = args
+	}
 
 	return result, nil
 }
@@ -81,7 +130,7 @@ func TranslateBooleanPlan(plan *hrql.Plan, obj *schema.ObjectDef) (string, []any
 		return "", nil, fmt.Errorf("unsupported boolean condition type %T", plan.BoolCondition)
 	}
 
-	return ReportsToCheckSQL(check.Emp, check.Target, obj)
+	return ReportsToCheckSQL(check.Emp, check.Target, check.Via, obj)
 }
 
 // TranslateConditions converts a slice of storage-agnostic Conditions to SQL expressions.
@@ -139,26 +188,44 @@ func ConditionToSQL(c hrql.Condition, obj *schema.ObjectDef, cache *schema.Cache
 		return sq.Or{left, right}, nil
 
 	case hrql.OrgChainUp:
-		return ChainUp(c.Emp, c.Steps, obj), nil
+		return ChainUp(c.Emp, c.Steps, c.Via, obj), nil
 
 	case hrql.OrgChainDown:
-		return ChainDown(c.Emp, c.Depth, obj), nil
+		return ChainDown(c.Emp, c.Depth, c.Via, obj), nil
 
 	case hrql.OrgChainAll:
-		return ChainAll(c.Emp, obj), nil
+		return ChainAll(c.Emp, c.Via, obj), nil
 
 	case hrql.OrgSubtree:
-		return Subtree(c.Emp, obj), nil
+		return Subtree(c.Emp, c.Via, obj), nil
+
+	case hrql.OrgNetwork:
+		return Network(c.Emp, c.Degree, c.Via, obj), nil
 
 	case hrql.SameFieldCond:
 		return SameField(c.Field, c.Emp, obj), nil
 
 	case hrql.ReportsTo:
-		return ReportsToWhere(c.Target, obj), nil
+		return ReportsToWhere(c.Target, c.Via, obj), nil
+
+	case hrql.TeamMember:
+		return TeamMemberWhere(c.Team), nil
+
+	case hrql.Employed:
+		return Employed(Alias(), obj)
 
 	case hrql.SubqueryAgg:
 		return subqueryAggToSQL(c, obj)
 
+	case hrql.RelatedAgg:
+		return relatedAggToSQL(c, cache)
+
+	case hrql.DurationCmp:
+		return durationCmpToSQL(c, obj)
+
+	case hrql.Quantified:
+		return quantifiedToSQL(c, obj, cache)
+
 	case hrql.InFilter:
 		fd := obj.FieldsByAPIName[c.Field[0]]
 		if fd == nil {
@@ -167,6 +234,9 @@ func ConditionToSQL(c hrql.Condition, obj *schema.ObjectDef, cache *schema.Cache
 		col := FilterExpr(Alias(), fd)
 		return sq.Expr(fmt.Sprintf(`%s = ANY(?)`, col), c.Values), nil
 
+	case hrql.InSubquery:
+		return inSubqueryToSQL(c, obj, cache)
+
 	case hrql.IsNullFilter:
 		fd := obj.FieldsByAPIName[c.Field[0]]
 		if fd == nil {
@@ -189,6 +259,9 @@ func ConditionToSQL(c hrql.Condition, obj *schema.ObjectDef, cache *schema.Cache
 		}
 		return sq.Expr(fmt.Sprintf(`%s LIKE ?`, col), c.Pattern), nil
 
+	case hrql.CustomFilter:
+		return customFilterToSQL(c, obj)
+
 	default:
 		return nil, fmt.Errorf("unknown condition type %T", c)
 	}
@@ -203,8 +276,7 @@ func fieldCmpToSQL(c hrql.FieldCmp, obj *schema.ObjectDef, cache *schema.Cache)
 		if fd == nil {
 			return nil, fmt.Errorf("unknown field %q", c.Field[0])
 		}
-		col := FilterExpr(alias, fd)
-		return comparisonExpr(col, c.Op, c.Value), nil
+		return defaultedComparison(FilterExpr(alias, fd), c), nil
 	}
 
 	// Lookup chain: .department.title == "Eng"
@@ -227,7 +299,7 @@ func fieldCmpRefToSQL(c hrql.FieldCmpRef, obj *schema.ObjectDef) (sq.Sqlizer, er
 
 	// RefToSQL already walks the chain to produce the correct subquery.
 	// For {ID: selfID, Chain: ["department"]} → (SELECT "department_id" FROM ... WHERE "id" = $1)
-	if len(c.Ref.Chain) == 0 {
+	if len(c.Ref.Chain) == 0 && c.Ref.Source == nil {
 		return comparisonExpr(col, c.Op, c.Ref.ID), nil
 	}
 
@@ -238,35 +310,99 @@ func fieldCmpRefToSQL(c hrql.FieldCmpRef, obj *schema.ObjectDef) (sq.Sqlizer, er
 
 // lookupChainToSQL builds a subquery for lookup-chain field comparisons.
 func lookupChainToSQL(c hrql.FieldCmp, obj *schema.ObjectDef, cache *schema.Cache) (sq.Sqlizer, error) {
-	alias := Alias()
+	expr, err := lookupChainExpr(Alias(), c.Field, obj, cache, 1)
+	if err != nil {
+		return nil, err
+	}
+	return defaultedComparison(expr, c), nil
+}
 
-	fd := obj.FieldsByAPIName[c.Field[0]]
+// lookupChainExpr returns the value at the end of a LOOKUP chain starting on
+// alias, one correlated subquery per hop:
+// (SELECT col FROM target "_sub" WHERE "_sub"."id" = fk_ref). Deeper chains
+// nest the next hop in place of col, aliasing it "_sub2", "_sub3", and so on.
+// Custom targets are read from metadata.records, restricted to the target
+// object.
+func lookupChainExpr(alias string, chain []string, obj *schema.ObjectDef, cache *schema.Cache, depth int) (string, error) {
+	fd := obj.FieldsByAPIName[chain[0]]
 	if fd == nil || fd.Type != schema.FieldLookup || fd.LookupObjectID == nil {
-		return nil, fmt.Errorf("field %q is not a LOOKUP field", c.Field[0])
+		return "", fmt.Errorf("field %q is not a LOOKUP field", chain[0])
+	}
+	target := cache.GetByID(*fd.LookupObjectID)
+	if target == nil {
+		return "", fmt.Errorf("lookup target for field %q not found", chain[0])
+	}
+
+	sub := "_sub"
+	if depth > 1 {
+		sub = fmt.Sprintf("_sub%d", depth)
+	}
+	var col string
+	if len(chain) == 2 {
+		next := target.FieldsByAPIName[chain[1]]
+		if next == nil {
+			return "", fmt.Errorf("unknown field %q on %s", chain[1], target.APIName)
+		}
+		col = FilterExpr(sub, next)
+	} else {
+		var err error
+		if col, err = lookupChainExpr(sub, chain[1:], target, cache, depth+1); err != nil {
+			return "", err
+		}
 	}
 
-	targetObj := cache.GetByID(*fd.LookupObjectID)
-	if targetObj == nil {
-		return nil, fmt.Errorf("lookup target for field %q not found", c.Field[0])
+	from := target.TableName() + " " + QI(sub)
+	where := fmt.Sprintf(`%s."id" = %s`, QI(sub), FKRef(alias, fd))
+	if !target.IsStandard {
+		from = `"metadata"."records" ` + QI(sub)
+		where += fmt.Sprintf(` AND %s."object_id" = %s::uuid`, QI(sub), QuoteLit(target.ID.String()))
 	}
+	return fmt.Sprintf(`(SELECT %s FROM %s WHERE %s)`, col, from, where), nil
+}
 
-	// For 2-level chains: (SELECT col FROM target WHERE id = fk_ref)
-	if len(c.Field) == 2 {
-		fkCol := FKRef(alias, fd)
-		nextFd := targetObj.FieldsByAPIName[c.Field[1]]
-		if nextFd == nil {
-			return nil, fmt.Errorf("unknown field %q on %s", c.Field[1], targetObj.APIName)
+// inSubqueryToSQL renders .field in <list> as
+// field IN (SELECT "_e"."id" FROM <source> "_e" WHERE <source conditions>).
+// The source is uncorrelated, so its alias may shadow the outer one.
+func inSubqueryToSQL(c hrql.InSubquery, obj *schema.ObjectDef, cache *schema.Cache) (sq.Sqlizer, error) {
+	fd := obj.FieldsByAPIName[c.Field]
+	if fd == nil {
+		return nil, fmt.Errorf("unknown field %q", c.Field)
+	}
+	src := obj
+	if name := cmp.Or(c.Source.Object, "employees"); name != obj.APIName {
+		if cache == nil {
+			return nil, fmt.Errorf("in over %s needs the schema cache", name)
+		}
+		if src = cache.Get(name); src == nil {
+			return nil, fmt.Errorf("object %q not found", name)
 		}
-		targetCol := FilterExpr("_sub", nextFd)
-		targetFrom := targetObj.TableName()
-		subSQL := fmt.Sprintf(`(SELECT %s FROM %s "_sub" WHERE "_sub"."id" = %s)`, targetCol, targetFrom, fkCol)
-		return comparisonExpr(subSQL, c.Op, c.Value), nil
 	}
 
-	return nil, fmt.Errorf("LOOKUP chain too deep (max 2 levels)")
+	alias := Alias()
+	from, baseWhere := TableSource(src, alias)
+	qb := sq.Select(fmt.Sprintf(`%s."id"`, QI(alias))).From(from)
+	if baseWhere != nil {
+		qb = qb.Where(baseWhere)
+	}
+	conds, err := TranslateConditions(c.Source.Conditions, src, cache)
+	if err != nil {
+		return nil, err
+	}
+	for _, cond := range conds {
+		qb = qb.Where(cond)
+	}
+	subSQL, args, err := qb.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	return sq.Expr(fmt.Sprintf(`%s IN (%s)`, FKRef(alias, fd), subSQL), args...), nil
 }
 
-// stringMatchToSQL translates a StringMatch to an ILIKE expression.
+// stringMatchToSQL translates a StringMatch to an ILIKE expression. The
+// pattern matches literally: its %, _ and backslashes are escaped. Folded
+// matches compare metadata.unaccent of both sides (migration 000028), the
+// expression an accent-insensitive field's search index is built on.
 func stringMatchToSQL(c hrql.StringMatch, obj *schema.ObjectDef) (sq.Sqlizer, error) {
 	if len(c.Field) == 0 {
 		return nil, fmt.Errorf("empty field in string match")
@@ -275,15 +411,19 @@ func stringMatchToSQL(c hrql.StringMatch, obj *schema.ObjectDef) (sq.Sqlizer, er
 	if fd == nil {
 		return nil, fmt.Errorf("unknown field %q", c.Field[0])
 	}
-	col := FilterExpr(Alias(), fd)
+	col, arg := FilterExpr(Alias(), fd), "?"
+	pattern := likeEscaper.Replace(c.Pattern)
+	if c.Fold {
+		col, arg = unaccent(col), unaccent(arg)
+	}
 
 	switch c.Op {
 	case "contains":
-		return sq.Expr(fmt.Sprintf(`%s ILIKE '%%' || ? || '%%'`, col), c.Pattern), nil
+		return sq.Expr(fmt.Sprintf(`%s ILIKE '%%' || %s || '%%'`, col, arg), pattern), nil
 	case "starts_with":
-		return sq.Expr(fmt.Sprintf(`%s ILIKE ? || '%%'`, col), c.Pattern), nil
+		return sq.Expr(fmt.Sprintf(`%s ILIKE %s || '%%'`, col, arg), pattern), nil
 	case "ends_with":
-		return sq.Expr(fmt.Sprintf(`%s ILIKE '%%' || ?`, col), c.Pattern), nil
+		return sq.Expr(fmt.Sprintf(`%s ILIKE '%%' || %s`, col, arg), pattern), nil
 	default:
 		return nil, fmt.Errorf("unknown string op %q", c.Op)
 	}
@@ -292,60 +432,201 @@ func stringMatchToSQL(c hrql.StringMatch, obj *schema.ObjectDef) (sq.Sqlizer, er
 // subqueryAggToSQL translates a SubqueryAgg to a correlated subquery expression.
 func subqueryAggToSQL(c hrql.SubqueryAgg, obj *schema.ObjectDef) (sq.Sqlizer, error) {
 	from := obj.TableName() + ` "_sub_e"`
-	subCol := `"_sub_e"."manager_path"`
+	path := QI(HierarchyPath(obj, c.Via))
+	subCol := `"_sub_e".` + path
 
 	switch c.OrgFunc {
 	case "reports":
-		outerPath := fmt.Sprintf(`%s."manager_path"`, QI(Alias()))
+		outerPath := fmt.Sprintf(`%s.%s`, QI(Alias()), path)
 
-		var whereCond string
-		if c.Depth == 0 {
-			whereCond = fmt.Sprintf(`%s <@ %s AND %s != %s`, subCol, outerPath, subCol, outerPath)
-		} else {
-			whereCond = fmt.Sprintf(`%s <@ %s AND nlevel(%s) = nlevel(%s) + %d`,
-				subCol, outerPath, subCol, outerPath, c.Depth)
+		whereCond := reportsMember(subCol, outerPath, c.Depth)
+		var args []any
+		if c.Employed {
+			employed, err := Employed("_sub_e", obj)
+			if err != nil {
+				return nil, err
+			}
+			employedSQL, employedArgs, err := employed.ToSql()
+			if err != nil {
+				return nil, err
+			}
+			whereCond += " AND " + employedSQL
+			args = employedArgs
 		}
 
 		subSQL := fmt.Sprintf(`(SELECT %s(*) FROM %s WHERE %s)`, c.AggFunc, from, whereCond)
 
 		if c.Op != "" && c.Value != "" {
-			return sq.Expr(fmt.Sprintf(`%s %s ?`, subSQL, sqlOp(c.Op)), c.Value), nil
+			return sq.Expr(fmt.Sprintf(`%s %s ?`, subSQL, sqlOp(c.Op)), append(args, c.Value)...), nil
 		}
-		return sq.Expr(subSQL), nil
+		return sq.Expr(subSQL, args...), nil
 
 	default:
 		return nil, fmt.Errorf("correlated subquery not supported for %s()", c.OrgFunc)
 	}
 }
 
+// relatedAggToSQL translates a RelatedAgg to a correlated aggregate over the
+// related records pointing at the outer one. As in quantifiedToSQL, their
+// conditions render in a derived table whose own "_e" alias shadows the outer
+// one; a sum over no records is 0 rather than NULL.
+func relatedAggToSQL(c hrql.RelatedAgg, cache *schema.Cache) (sq.Sqlizer, error) {
+	related := cache.Get(c.Object)
+	if related == nil {
+		return nil, fmt.Errorf("unknown object %q", c.Object)
+	}
+	via := related.FieldsByAPIName[c.Via]
+	if via == nil {
+		return nil, fmt.Errorf("unknown field %q on %s", c.Via, related.APIName)
+	}
+
+	alias := Alias()
+	val := "NULL"
+	if c.AggField != "" {
+		fd := related.FieldsByAPIName[c.AggField]
+		if fd == nil {
+			return nil, fmt.Errorf("unknown field %q on %s", c.AggField, related.APIName)
+		}
+		val = FilterExpr(alias, fd)
+	}
+
+	from, baseWhere := TableSource(related, alias)
+	inner := sq.Select(FKRef(alias, via)+` AS "fk"`, val+` AS "v"`).From(from)
+	if baseWhere != nil {
+		inner = inner.Where(baseWhere)
+	}
+	for _, cond := range c.Conditions {
+		sqlCond, err := ConditionToSQL(cond, related, cache)
+		if err != nil {
+			return nil, err
+		}
+		inner = inner.Where(sqlCond)
+	}
+	innerSQL, args, err := inner.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	arg := `"_r"."v"`
+	if c.Distinct {
+		arg = "DISTINCT " + arg
+	}
+	agg := `count(*)`
+	switch {
+	case c.AggFunc == "sum":
+		agg = fmt.Sprintf(`COALESCE(sum(%s), 0)`, arg)
+	case c.AggField != "":
+		agg = fmt.Sprintf(`%s(%s)`, c.AggFunc, arg)
+	}
+	subSQL := fmt.Sprintf(`(SELECT %s FROM (%s) "_r" WHERE "_r"."fk" = %s."id")`, agg, innerSQL, QI(alias))
+
+	if c.Op != "" && c.Value != "" {
+		return sq.Expr(fmt.Sprintf(`%s %s ?`, subSQL, sqlOp(c.Op)), append(args, c.Value)...), nil
+	}
+	return sq.Expr(subSQL, args...), nil
+}
+
+// reportsMember returns the condition that the path sub belongs to
+// reports(outer, depth): a descendant at exactly depth levels, or at any
+// depth when depth is 0.
+func reportsMember(sub, outer string, depth int) string {
+	if depth == 0 {
+		return fmt.Sprintf(`%s <@ %s AND %s != %s`, sub, outer, sub, outer)
+	}
+	return fmt.Sprintf(`%s <@ %s AND nlevel(%s) = nlevel(%s) + %d`, sub, outer, sub, outer, depth)
+}
+
+// quantifiedToSQL translates all()/none() to a correlated NOT EXISTS over the
+// members violating the quantifier. The predicate is rendered in a derived
+// table whose own "_e" alias shadows the outer one, so conditions translate
+// unchanged; a NULL predicate counts as not satisfied.
+func quantifiedToSQL(c hrql.Quantified, obj *schema.ObjectDef, cache *schema.Cache) (sq.Sqlizer, error) {
+	if c.OrgFunc != "reports" {
+		return nil, fmt.Errorf("%s() is not supported over %s()", c.Quantifier, c.OrgFunc)
+	}
+	pred, err := ConditionToSQL(c.Pred, obj, cache)
+	if err != nil {
+		return nil, err
+	}
+	predSQL, predArgs, err := pred.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	violates := fmt.Sprintf(`(%s) IS NOT TRUE`, predSQL)
+	if c.Quantifier == "none" {
+		violates = fmt.Sprintf(`(%s)`, predSQL)
+	}
+
+	path := QI(HierarchyPath(obj, c.Via))
+	from, baseWhere := TableSource(obj, Alias())
+	inner := sq.Select(fmt.Sprintf(`%s.%s`, QI(Alias()), path)).From(from).Where(sq.Expr(violates, predArgs...))
+	if baseWhere != nil {
+		inner = inner.Where(baseWhere)
+	}
+	if c.Employed {
+		employed, err := Employed(Alias(), obj)
+		if err != nil {
+			return nil, err
+		}
+		inner = inner.Where(employed)
+	}
+	innerSQL, innerArgs, err := inner.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	member := reportsMember(`"_q".`+path, fmt.Sprintf(`%s.%s`, QI(Alias()), path), c.Depth)
+	return sq.Expr(fmt.Sprintf(`NOT EXISTS (SELECT 1 FROM (%s) "_q" WHERE %s)`, innerSQL, member), innerArgs...), nil
+}
+
 // buildAggregateBuilder builds a Squirrel select builder for a terminal aggregation
 // without applying PlaceholderFormat. Used by both buildAggregate and arithmetic queries.
+// A case(...) step on the plan is aggregated in place of its field.
 func buildAggregateBuilder(
 	obj *schema.ObjectDef,
-	aggFunc string,
-	aggField string,
+	plan *hrql.Plan,
 	conditions []sq.Sqlizer,
-) sq.SelectBuilder {
+	cache *schema.Cache,
+) (sq.SelectBuilder, error) {
+	if plan.IsProjection() {
+		return buildProjectionBuilder(obj, plan, conditions)
+	}
+
 	alias := Alias()
 	from, baseWhere := TableSource(obj, alias)
 
-	var col string
+	col := "*"
+	var colArgs []any
+	numeric := true
 	switch {
-	case aggFunc == "count" && aggField == "":
-		col = "*"
-	case aggField != "":
-		fd := obj.FieldsByAPIName[aggField]
-		if fd != nil {
+	case plan.Case != nil:
+		var err error
+		col, colArgs, err = CaseToSQL(plan.Case, obj, cache)
+		if err != nil {
+			return sq.SelectBuilder{}, err
+		}
+		numeric = plan.Case.Numeric
+	case plan.AggField != "":
+		if fd := obj.FieldsByAPIName[plan.AggField]; fd != nil {
 			col = FilterExpr(alias, fd)
-		} else {
-			col = "*"
+			numeric = fd.IsNumeric()
+			if plan.Since != "" {
+				col, numeric = sinceSQL(plan.Since, col), true
+			}
 		}
-	default:
-		col = "*"
 	}
 
-	selectExpr := fmt.Sprintf(`%s(%s)`, aggFunc, col)
-	qb := sq.Select(selectExpr).From(from)
+	// Aggregates skip NULLs. sum of no values is 0, as in RelatedAgg; avg,
+	// min and max of no values stay NULL (use ?? for a default).
+	if plan.Distinct {
+		col = "DISTINCT " + col
+	}
+	selectExpr := textAggregate(fmt.Sprintf(`%s(%s)`, plan.AggFunc, col), plan.AggFunc, numeric)
+	if plan.AggFunc == "sum" {
+		selectExpr = fmt.Sprintf(`COALESCE(sum(%s), 0)`, col)
+	}
+	qb := sq.Select().Column(sq.Expr(selectExpr, colArgs...)).From(from)
 
 	if baseWhere != nil {
 		qb = qb.Where(baseWhere)
@@ -354,18 +635,101 @@ func buildAggregateBuilder(
 		qb = qb.Where(cond)
 	}
 
-	return qb
+	return qb, nil
+}
+
+// buildProjectionBuilder selects the AggField value of the record a pick
+// selects: the list's order (by id when it has none), LIMIT 1, offset to the
+// nth record. Values other than numbers are selected as text, as min and max
+// are.
+func buildProjectionBuilder(obj *schema.ObjectDef, plan *hrql.Plan, conditions []sq.Sqlizer) (sq.SelectBuilder, error) {
+	fd := obj.FieldsByAPIName[plan.AggField]
+	if fd == nil {
+		return sq.SelectBuilder{}, fmt.Errorf("unknown field %q", plan.AggField)
+	}
+	col := FilterExpr(Alias(), fd)
+	if !fd.IsNumeric() {
+		col += "::text"
+	}
+	from, baseWhere := TableSource(obj, Alias())
+	qb := sq.Select(col).From(from)
+	if baseWhere != nil {
+		qb = qb.Where(baseWhere)
+	}
+	for _, cond := range conditions {
+		qb = qb.Where(cond)
+	}
+
+	params := &QueryParams{}
+	if plan.OrderBy != nil && plan.OrderBy.Random {
+		params.Random = true
+	} else if plan.OrderBy != nil {
+		params.Order = &OrderClause{FieldAPIName: plan.OrderBy.Field, Desc: plan.OrderBy.Desc, NullsFirst: plan.OrderBy.NullsFirst}
+	}
+	qb = qb.OrderBy(buildOrderBy(obj, params)...).Limit(1)
+	if plan.PickOp == "nth" && plan.PickN > 1 {
+		qb = qb.Offset(uint64(plan.PickN - 1))
+	}
+	return qb, nil
+}
+
+// textAggregate casts min or max over values that are not numbers, such as
+// dates or text, to text: the service returns the result as a string rather
+// than parsing a number from it.
+func textAggregate(agg, fn string, numeric bool) string {
+	if !numeric && (fn == "min" || fn == "max") {
+		return agg + "::text"
+	}
+	return agg
 }
 
 // buildAggregate builds a SQL query for a terminal aggregation.
 func buildAggregate(
 	obj *schema.ObjectDef,
-	aggFunc string,
-	aggField string,
+	plan *hrql.Plan,
 	conditions []sq.Sqlizer,
+	cache *schema.Cache,
 ) (string, []any, error) {
-	return buildAggregateBuilder(obj, aggFunc, aggField, conditions).
-		PlaceholderFormat(sq.Dollar).ToSql()
+	qb, err := buildAggregateBuilder(obj, plan, conditions, cache)
+	if err != nil {
+		return "", nil, err
+	}
+	return qb.PlaceholderFormat(sq.Dollar).ToSql()
+}
+
+// buildBuckets builds the query for a bucket(...) plan: one row per non-empty
+// range with its width_bucket index (0 below the first bound, len(Bounds) at
+// or above the last) and record count. Null values are not counted.
+func buildBuckets(obj *schema.ObjectDef, plan *hrql.Plan, conditions []sq.Sqlizer) (string, []any, error) {
+	alias := Alias()
+	from, baseWhere := TableSource(obj, alias)
+
+	fd := obj.FieldsByAPIName[plan.AggField]
+	if fd == nil {
+		return "", nil, fmt.Errorf("unknown field %q", plan.AggField)
+	}
+	col := FilterExpr(alias, fd)
+	if plan.Since != "" {
+		col = sinceSQL(plan.Since, col)
+	}
+	bounds := make([]string, len(plan.Bounds))
+	args := make([]any, len(plan.Bounds))
+	for i, b := range plan.Bounds {
+		bounds[i], args[i] = "?::numeric", b
+	}
+
+	qb := sq.Select().
+		Column(sq.Expr(fmt.Sprintf(`width_bucket((%s)::numeric, ARRAY[%s])`, col, strings.Join(bounds, ", ")), args...)).
+		Column(`count(*)`).
+		From(from).
+		Where(col + ` IS NOT NULL`)
+	if baseWhere != nil {
+		qb = qb.Where(baseWhere)
+	}
+	for _, cond := range conditions {
+		qb = qb.Where(cond)
+	}
+	return qb.GroupBy("1").PlaceholderFormat(sq.Dollar).ToSql()
 }
 
 // scalarExprToSQL translates a ScalarExpr tree into a SQL fragment with ? placeholders.
@@ -375,11 +739,22 @@ func scalarExprToSQL(expr hrql.ScalarExpr, obj *schema.ObjectDef, cache *schema.
 		return "?::numeric", []any{e.Value}, nil
 
 	case hrql.ScalarSubquery:
+		if e.Plan.Union != nil {
+			subSQL, subArgs, err := unionAggregate(e.Plan, cache)
+			if err != nil {
+				return "", nil, err
+			}
+			return "(" + subSQL + ")", subArgs, nil
+		}
 		conds, err := TranslateConditions(e.Plan.Conditions, obj, cache)
 		if err != nil {
 			return "", nil, err
 		}
-		subSQL, subArgs, err := buildAggregateBuilder(obj, e.Plan.AggFunc, e.Plan.AggField, conds).ToSql()
+		qb, err := buildAggregateBuilder(obj, e.Plan, conds, cache)
+		if err != nil {
+			return "", nil, err
+		}
+		subSQL, subArgs, err := qb.ToSql()
 		if err != nil {
 			return "", nil, err
 		}
@@ -399,14 +774,89 @@ func scalarExprToSQL(expr hrql.ScalarExpr, obj *schema.ObjectDef, cache *schema.
 		if err != nil {
 			return "", nil, err
 		}
+		if e.Op == "/" {
+			// Division by zero yields NULL rather than failing the query.
+			sql := fmt.Sprintf("(%s / NULLIF(%s, 0))", numericSQL(e.Left, leftSQL), numericSQL(e.Right, rightSQL))
+			return sql, concatArgs(leftArgs, rightArgs), nil
+		}
 		sql := fmt.Sprintf("(%s %s %s)", leftSQL, e.Op, rightSQL)
 		return sql, concatArgs(leftArgs, rightArgs), nil
 
+	case hrql.ScalarCoalesce:
+		valueSQL, valueArgs, err := scalarExprToSQL(e.Value, obj, cache)
+		if err != nil {
+			return "", nil, err
+		}
+		defSQL, defArgs, err := scalarExprToSQL(e.Default, obj, cache)
+		if err != nil {
+			return "", nil, err
+		}
+		sql := fmt.Sprintf("COALESCE(%s, %s)", numericSQL(e.Value, valueSQL), numericSQL(e.Default, defSQL))
+		return sql, concatArgs(valueArgs, defArgs), nil
+
+	case hrql.ScalarFunc:
+		return scalarFuncToSQL(e, obj, cache)
+
 	default:
 		return "", nil, fmt.Errorf("unknown scalar expr type %T", expr)
 	}
 }
 
+// numericSQL casts the rendering of e to numeric unless it is a literal,
+// which already is.
+func numericSQL(e hrql.ScalarExpr, sql string) string {
+	if _, ok := e.(hrql.ScalarLiteral); ok {
+		return sql
+	}
+	return sql + "::numeric"
+}
+
+// scalarFuncArity is the argument count of each ScalarFunc, value included.
+var scalarFuncArity = map[string]int{"round": 2, "floor": 1, "ceil": 1, "percent_of": 2}
+
+// scalarFuncToSQL renders a formatting function. Values are cast to numeric so
+// round(value, decimals) applies to every aggregate (count returns bigint, avg
+// numeric). percent_of yields NULL for a zero total.
+func scalarFuncToSQL(e hrql.ScalarFunc, obj *schema.ObjectDef, cache *schema.Cache) (string, []any, error) {
+	arity, ok := scalarFuncArity[e.Name]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown scalar function %q", e.Name)
+	}
+	if len(e.Args) != arity {
+		return "", nil, fmt.Errorf("%s: expected %d arguments, got %d", e.Name, arity, len(e.Args))
+	}
+
+	if e.Name == "round" {
+		decimals, ok := e.Args[1].(hrql.ScalarLiteral)
+		if !ok {
+			return "", nil, fmt.Errorf("round: decimals must be a literal")
+		}
+		value, args, err := scalarExprToSQL(e.Args[0], obj, cache)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("round(%s::numeric, ?::int)", value), append(args, decimals.Value), nil
+	}
+
+	sqls := make([]string, len(e.Args))
+	var args []any
+	for i, a := range e.Args {
+		s, aArgs, err := scalarExprToSQL(a, obj, cache)
+		if err != nil {
+			return "", nil, err
+		}
+		sqls[i] = numericSQL(a, s)
+		args = append(args, aArgs...)
+	}
+
+	switch e.Name {
+	case "percent_of":
+		return fmt.Sprintf("(100 * %s / NULLIF(%s, 0))", sqls[0], sqls[1]), args, nil
+	default: // floor, ceil
+		return fmt.Sprintf("%s(%s)", e.Name, sqls[0]), args, nil
+	}
+}
+
 // buildArithmeticQuery builds a full SELECT for an arithmetic scalar expression.
 func buildArithmeticQuery(expr hrql.ScalarExpr, obj *schema.ObjectDef, cache *schema.Cache) (string, []any, error) {
 	rawSQL, args, err := scalarExprToSQL(expr, obj, cache)
@@ -434,6 +884,15 @@ func comparisonExpr(col, op, val string) sq.Sqlizer {
 	}
 }
 
+// defaultedComparison compares col with c.Value, substituting c.Default
+// (from .field ?? default) where col is NULL.
+func defaultedComparison(col string, c hrql.FieldCmp) sq.Sqlizer {
+	if c.Default == nil {
+		return comparisonExpr(col, c.Op, c.Value)
+	}
+	return sq.Expr(fmt.Sprintf(`COALESCE(%s, ?) %s ?`, col, sqlOp(c.Op)), *c.Default, c.Value)
+}
+
 func sqlOp(op string) string {
 	switch op {
 	case "==":
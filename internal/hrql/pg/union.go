@@ -0,0 +1,220 @@
+package pg
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/atlekbai/schema_registry/internal/hrql"
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// UnionObjectKey is the key naming the object each record of a union list
+// comes from.
+const UnionObjectKey = "_object"
+
+// unionAlias aliases the UNION ALL of the sources in the outer query.
+const unionAlias = "_u"
+
+// UnionListParams are the request-dependent parts of a union list query.
+type UnionListParams struct {
+	// Select restricts the projected fields to these shared fields; empty
+	// projects them all.
+	Select []string
+	Order  *OrderClause
+	Limit  int
+}
+
+// BuildUnionList returns the list query of a union plan: each source projects
+// the shared fields into a _row object with the same keys, plus the record's
+// object under UnionObjectKey, and the outer query orders and limits their
+// UNION ALL. Rows scan like BuildList rows without a cursor value: _row and
+// the id as text. LIMIT is Limit+1 to detect a further page.
+func BuildUnionList(u *hrql.Union, cache *schema.Cache, params UnionListParams) (string, []any, error) {
+	fields := u.Fields
+	if len(params.Select) > 0 {
+		fields = slices.DeleteFunc(slices.Clone(u.Fields), func(name string) bool {
+			return !slices.Contains(params.Select, name)
+		})
+	}
+	project := func(obj *schema.ObjectDef) (string, []any, error) {
+		pairs := []string{
+			fmt.Sprintf(`'id', %s."id"`, QI(qAlias)),
+			fmt.Sprintf(`'created_at', %s."created_at"`, QI(qAlias)),
+			fmt.Sprintf(`'updated_at', %s."updated_at"`, QI(qAlias)),
+			fmt.Sprintf(`'version', %s."version"`, QI(qAlias)),
+			fmt.Sprintf(`%s, %s`, QuoteLit(UnionObjectKey), QuoteLit(obj.APIName)),
+		}
+		for _, name := range fields {
+			fd := obj.FieldsByAPIName[name]
+			if isSystemField(name) || fd == nil {
+				continue
+			}
+			// Keyed by API name: jsonKey names standard LOOKUPs after their
+			// column, which other sources do not have.
+			pairs = append(pairs, fmt.Sprintf(`%s, %s`, QuoteLit(fd.APIName), SelectFieldExpr(qAlias, fd)))
+		}
+		cols := fmt.Sprintf(`json_build_object(%s) AS _row, %s."id" AS _id`, strings.Join(pairs, ", "), QI(qAlias))
+		if params.Order != nil {
+			fd := obj.FieldsByAPIName[params.Order.FieldAPIName]
+			if fd == nil {
+				return "", nil, fmt.Errorf("union: unknown sort field %q on %s", params.Order.FieldAPIName, obj.APIName)
+			}
+			cols += ", " + unionValue(fd) + " AS _sort"
+		}
+		return cols, nil, nil
+	}
+	sources, args, err := unionSources(u, cache, project)
+	if err != nil {
+		return "", nil, err
+	}
+
+	dir := "ASC"
+	if params.Order != nil && params.Order.Desc {
+		dir = "DESC"
+	}
+	order := fmt.Sprintf(`%s."_id" %s`, QI(unionAlias), dir)
+	if params.Order != nil {
+		order = fmt.Sprintf(`%s."_sort" %s %s, %s`, QI(unionAlias), dir, nullsOrder(params.Order), order)
+	}
+	sql, err := sq.Dollar.ReplacePlaceholders(fmt.Sprintf(`SELECT %s."_row", %s."_id"::text FROM (%s) %s ORDER BY %s LIMIT ?`,
+		QI(unionAlias), QI(unionAlias), sources, QI(unionAlias), order))
+	return sql, append(args, params.Limit+1), err
+}
+
+// BuildUnionCount returns SELECT count(*) over the records of a union.
+func BuildUnionCount(u *hrql.Union, cache *schema.Cache) (string, []any, error) {
+	sources, args, err := unionSources(u, cache, func(*schema.ObjectDef) (string, []any, error) {
+		return "1", nil, nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	sql, err := sq.Dollar.ReplacePlaceholders(fmt.Sprintf(`SELECT count(*) FROM (%s) %s`, sources, QI(unionAlias)))
+	return sql, args, err
+}
+
+// unionAggregate returns the aggregate of a scalar plan over a union, with ?
+// placeholders: count(*), or the aggregate of the AggField value every
+// source projects.
+func unionAggregate(plan *hrql.Plan, cache *schema.Cache) (string, []any, error) {
+	numeric := true
+	project := func(obj *schema.ObjectDef) (string, []any, error) {
+		if plan.AggField == "" {
+			return "1 AS _v", nil, nil
+		}
+		fd := obj.FieldsByAPIName[plan.AggField]
+		if fd == nil {
+			return "", nil, fmt.Errorf("union: unknown field %q on %s", plan.AggField, obj.APIName)
+		}
+		numeric = fd.IsNumeric() || plan.Since != ""
+		col := unionValue(fd)
+		if plan.Since != "" {
+			col = sinceSQL(plan.Since, FilterExpr(qAlias, fd))
+		}
+		return col + " AS _v", nil, nil
+	}
+	sources, args, err := unionSources(plan.Union, cache, project)
+	if err != nil {
+		return "", nil, err
+	}
+	col := "*"
+	if plan.AggField != "" {
+		col = QI(unionAlias) + `."_v"`
+	}
+	if plan.Distinct {
+		col = "DISTINCT " + col
+	}
+	agg := textAggregate(fmt.Sprintf(`%s(%s)`, plan.AggFunc, col), plan.AggFunc, numeric)
+	if plan.AggFunc == "sum" {
+		agg = fmt.Sprintf(`COALESCE(sum(%s), 0)`, col)
+	}
+	return fmt.Sprintf(`SELECT %s FROM (%s) %s`, agg, sources, QI(unionAlias)), args, nil
+}
+
+// unionSources returns (source 1) UNION ALL (source 2) ..., each source
+// selecting the columns project returns for its object from the records that
+// pass its conditions, ordered and limited when it picks its first records.
+// Placeholders are ?.
+func unionSources(u *hrql.Union, cache *schema.Cache, project func(*schema.ObjectDef) (string, []any, error)) (string, []any, error) {
+	var (
+		parts []string
+		args  []any
+	)
+	for _, src := range u.Sources {
+		obj := cache.Get(src.Object)
+		if obj == nil {
+			return "", nil, fmt.Errorf("union: object %q not in cache", src.Object)
+		}
+		cols, colArgs, err := project(obj)
+		if err != nil {
+			return "", nil, err
+		}
+		from, baseWhere := TableSource(obj, qAlias)
+		qb := sq.Select().Column(sq.Expr(cols, colArgs...)).From(from)
+		if baseWhere != nil {
+			qb = qb.Where(baseWhere)
+		}
+		conds, err := TranslateConditions(src.Conditions, obj, cache)
+		if err != nil {
+			return "", nil, err
+		}
+		for _, cond := range conds {
+			qb = qb.Where(cond)
+		}
+		if src.Limit > 0 {
+			params := &QueryParams{}
+			if src.OrderBy != nil {
+				params.Order = &OrderClause{FieldAPIName: src.OrderBy.Field, Desc: src.OrderBy.Desc, NullsFirst: src.OrderBy.NullsFirst}
+			}
+			qb = qb.OrderBy(buildOrderBy(obj, params)...).Suffix("LIMIT ?", src.Limit)
+		}
+		sql, srcArgs, err := qb.ToSql()
+		if err != nil {
+			return "", nil, err
+		}
+		parts = append(parts, "("+sql+")")
+		args = append(args, srcArgs...)
+	}
+	return strings.Join(parts, " UNION ALL "), args, nil
+}
+
+// unionValue returns a field's value cast to the type every source agrees
+// on, so the UNION ALL columns line up whether the field is a column (of any
+// type, enums included) or a JSONB key.
+func unionValue(fd *schema.FieldDef) string {
+	col := FilterExpr(qAlias, fd)
+	if fd.StorageColumn == nil {
+		// FilterExpr already extracts JSONB values as numeric, timestamptz
+		// or text.
+		if fd.Type == schema.FieldBoolean {
+			return "(" + col + ")::boolean"
+		}
+		return col
+	}
+	switch {
+	case fd.IsNumeric():
+		return col + "::numeric"
+	case fd.Type == schema.FieldDate || fd.Type == schema.FieldDatetime:
+		return col + "::timestamptz"
+	case fd.Type == schema.FieldBoolean:
+		return col
+	}
+	return col + "::text"
+}
+
+// buildUnionScalar builds the query of a scalar plan over a union: an
+// aggregate, or arithmetic whose subqueries aggregate the union.
+func buildUnionScalar(plan *hrql.Plan, obj *schema.ObjectDef, cache *schema.Cache) (string, []any, error) {
+	if plan.ScalarExpr != nil {
+		return buildArithmeticQuery(plan.ScalarExpr, obj, cache)
+	}
+	sql, args, err := unionAggregate(plan, cache)
+	if err != nil {
+		return "", nil, err
+	}
+	sql, err = sq.Dollar.ReplacePlaceholders(sql)
+	return sql, args, err
+}
@@ -0,0 +1,25 @@
+package pg
+
+import (
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// BuildFieldUsage returns a query counting the object's records and, for
+// each of fields (document fields), whether any record holds a non-null
+// value: one row of count followed by a boolean per field, in order.
+func BuildFieldUsage(obj *schema.ObjectDef, fields []*schema.FieldDef) (string, []any, error) {
+	from, where := TableSource(obj, qAlias)
+	cols := []string{"count(*)"}
+	for _, fd := range fields {
+		cols = append(cols, fmt.Sprintf(`COALESCE(bool_or(jsonb_typeof(%s) <> 'null'), false)`, SelectFieldExpr(qAlias, fd)))
+	}
+	q := sq.Select(cols...).From(from).PlaceholderFormat(sq.Dollar)
+	if where != nil {
+		q = q.Where(where)
+	}
+	return q.ToSql()
+}
@@ -0,0 +1,303 @@
+package pg
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/atlekbai/schema_registry/internal/schema"
+	"github.com/google/uuid"
+)
+
+// customFieldsColumn is the JSONB column holding custom field values on standard tables.
+const customFieldsColumn = "custom_fields"
+
+// recordValues is a write payload split by storage location.
+type recordValues struct {
+	Columns map[string]any // storage column -> value (standard objects)
+	Custom  map[string]any // field API name -> value (JSONB document)
+}
+
+// resolveAliases renames payload keys naming a field by a former api_name
+// (see schema.FieldDef.Aliases) to its current one, in place, so values
+// written through an alias land under the field's document key. A payload
+// setting a field under both names is rejected.
+func resolveAliases(obj *schema.ObjectDef, values map[string]any) error {
+	for name, v := range values {
+		fd, ok := obj.FieldsByAPIName[name]
+		if !ok || fd.APIName == name {
+			continue
+		}
+		if _, dup := values[fd.APIName]; dup {
+			return fmt.Errorf("field %q was renamed to %q; set only one of them", name, fd.APIName)
+		}
+		delete(values, name)
+		values[fd.APIName] = v
+	}
+	return nil
+}
+
+// splitValues validates a write payload against the object definition and partitions
+// it into physical columns and JSONB-stored custom field values.
+func splitValues(obj *schema.ObjectDef, values map[string]any) (*recordValues, error) {
+	rv := &recordValues{
+		Columns: make(map[string]any),
+		Custom:  make(map[string]any),
+	}
+	for name, v := range values {
+		if isSystemField(name) {
+			return nil, fmt.Errorf("field %q is read-only", name)
+		}
+		fd, ok := obj.FieldsByAPIName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q", name)
+		}
+		if fd.Type == schema.FieldFormula {
+			return nil, fmt.Errorf("field %q is computed and cannot be written", name)
+		}
+		if obj.IsStandard && fd.StorageColumn != nil {
+			rv.Columns[*fd.StorageColumn] = v
+			continue
+		}
+		if obj.IsStandard && !obj.SupportsCustomFields {
+			return nil, fmt.Errorf("object %q does not support custom fields", obj.APIName)
+		}
+		rv.Custom[name] = v
+	}
+	return rv, nil
+}
+
+// sortedKeys returns map keys in a stable order so generated SQL is deterministic.
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// quotedColumns returns the quoted, comma-separated column list for keys.
+func quotedColumns(keys []string) string {
+	quoted := make([]string, len(keys))
+	for i, k := range keys {
+		quoted[i] = QI(k)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func jsonArg(m map[string]any) (string, error) {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return "", fmt.Errorf("encode values: %w", err)
+	}
+	return string(b), nil
+}
+
+// checkRequired returns an error if a required field is missing from an insert payload.
+func checkRequired(obj *schema.ObjectDef, values map[string]any) error {
+	for _, f := range obj.Fields {
+		if !f.IsRequired || isSystemField(f.APIName) {
+			continue
+		}
+		if v, ok := values[f.APIName]; !ok || v == nil {
+			return fmt.Errorf("field %q is required", f.APIName)
+		}
+	}
+	return nil
+}
+
+// BuildInsert returns INSERT ... RETURNING "id"::text for a new record.
+// Standard columns are converted via jsonb_populate_record so Postgres applies
+// the column types; custom values go to the JSONB document.
+func (b *QueryBuilder) BuildInsert(values map[string]any) (string, []any, error) {
+	if err := resolveAliases(b.obj, values); err != nil {
+		return "", nil, err
+	}
+	if err := checkRequired(b.obj, values); err != nil {
+		return "", nil, err
+	}
+	rv, err := splitValues(b.obj, values)
+	if err != nil {
+		return "", nil, err
+	}
+	customJSON, err := jsonArg(rv.Custom)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if !b.obj.IsStandard {
+		return sq.Insert(`"metadata"."records"`).
+			Columns(`"object_id"`, `"data"`).
+			Values(b.obj.ID, sq.Expr("?::jsonb", customJSON)).
+			Suffix(`RETURNING "id"::text`).
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+	}
+
+	table := b.obj.TableName()
+	if len(rv.Columns) == 0 && len(rv.Custom) == 0 {
+		return fmt.Sprintf(`INSERT INTO %s DEFAULT VALUES RETURNING "id"::text`, table), nil, nil
+	}
+
+	colsJSON, err := jsonArg(rv.Columns)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var (
+		insertCols []string
+		selectCols []string
+		args       []any
+	)
+	for _, k := range sortedKeys(rv.Columns) {
+		insertCols = append(insertCols, QI(k))
+		selectCols = append(selectCols, QI(k))
+	}
+	if len(rv.Custom) > 0 {
+		insertCols = append(insertCols, QI(customFieldsColumn))
+		selectCols = append(selectCols, "?::jsonb")
+		args = append(args, customJSON)
+	}
+	args = append(args, colsJSON)
+
+	sql := fmt.Sprintf(
+		`INSERT INTO %s (%s) SELECT %s FROM jsonb_populate_record(NULL::%s, ?::jsonb) RETURNING "id"::text`,
+		table, strings.Join(insertCols, ", "), strings.Join(selectCols, ", "), table,
+	)
+	finalSQL, err := sq.Dollar.ReplacePlaceholders(sql)
+	return finalSQL, args, err
+}
+
+// BuildUpdate returns UPDATE ... RETURNING "version" for a partial record update.
+// When expectedVersion > 0 the update only applies if the stored version matches
+// (compare-and-swap); no row is returned on mismatch.
+func (b *QueryBuilder) BuildUpdate(id uuid.UUID, values map[string]any, expectedVersion int64) (string, []any, error) {
+	qb, err := b.update(values)
+	if err != nil {
+		return "", nil, err
+	}
+	qb = qb.Where(sq.Eq{`"id"`: id})
+	if expectedVersion > 0 {
+		qb = qb.Where(sq.Eq{`"version"`: expectedVersion})
+	}
+
+	return qb.Suffix(`RETURNING "version"`).PlaceholderFormat(sq.Dollar).ToSql()
+}
+
+func (b *QueryBuilder) BuildUpdateIDs(ids []uuid.UUID, values map[string]any) (string, []any, error) {
+	qb, err := b.update(values)
+	if err != nil {
+		return "", nil, err
+	}
+	qb = qb.Where(sq.Expr(`"id" = ANY(?::uuid[])`, ids))
+	return qb.Suffix(`RETURNING "id"`).PlaceholderFormat(sq.Dollar).ToSql()
+}
+
+// update returns the UPDATE setting values on the object's records and
+// bumping their version, for the caller to narrow to the records written.
+func (b *QueryBuilder) update(values map[string]any) (sq.UpdateBuilder, error) {
+	if err := resolveAliases(b.obj, values); err != nil {
+		return sq.UpdateBuilder{}, err
+	}
+	rv, err := splitValues(b.obj, values)
+	if err != nil {
+		return sq.UpdateBuilder{}, err
+	}
+
+	var qb sq.UpdateBuilder
+	if b.obj.IsStandard {
+		qb = sq.Update(b.obj.TableName())
+		if len(rv.Columns) > 0 {
+			colsJSON, err := jsonArg(rv.Columns)
+			if err != nil {
+				return sq.UpdateBuilder{}, err
+			}
+			cols := quotedColumns(sortedKeys(rv.Columns))
+			qb = qb.Set("("+cols+")", sq.Expr(
+				fmt.Sprintf(`(SELECT %s FROM jsonb_populate_record(NULL::%s, ?::jsonb))`, cols, b.obj.TableName()),
+				colsJSON,
+			))
+		}
+		if len(rv.Custom) > 0 {
+			customJSON, err := jsonArg(rv.Custom)
+			if err != nil {
+				return sq.UpdateBuilder{}, err
+			}
+			qb = qb.Set(QI(customFieldsColumn), sq.Expr(QI(customFieldsColumn)+" || ?::jsonb", customJSON))
+		}
+	} else {
+		customJSON, err := jsonArg(rv.Custom)
+		if err != nil {
+			return sq.UpdateBuilder{}, err
+		}
+		qb = sq.Update(`"metadata"."records"`).
+			Set(`"data"`, sq.Expr(`"data" || ?::jsonb`, customJSON)).
+			Where(sq.Eq{`"object_id"`: b.obj.ID})
+	}
+
+	return qb.
+		Set(`"version"`, sq.Expr(`"version" + 1`)).
+		Set(`"updated_at"`, sq.Expr("now()")), nil
+}
+
+// BuildDelete returns DELETE for a record, guarded by expectedVersion when > 0.
+func (b *QueryBuilder) BuildDelete(id uuid.UUID, expectedVersion int64) (string, []any, error) {
+	qb := sq.Delete(writeTable(b.obj)).Where(sq.Eq{`"id"`: id})
+	if !b.obj.IsStandard {
+		qb = qb.Where(sq.Eq{`"object_id"`: b.obj.ID})
+	}
+	if expectedVersion > 0 {
+		qb = qb.Where(sq.Eq{`"version"`: expectedVersion})
+	}
+	return qb.PlaceholderFormat(sq.Dollar).ToSql()
+}
+
+// BuildVersion returns SELECT "version" for a single record, used to report
+// the current version after a failed compare-and-swap.
+func (b *QueryBuilder) BuildVersion(id uuid.UUID) (string, []any, error) {
+	qb := sq.Select(`"version"`).From(writeTable(b.obj)).Where(sq.Eq{`"id"`: id})
+	if !b.obj.IsStandard {
+		qb = qb.Where(sq.Eq{`"object_id"`: b.obj.ID})
+	}
+	return qb.PlaceholderFormat(sq.Dollar).ToSql()
+}
+
+// BuildDocumentSize returns the size of record id's document (its JSONB data
+// or custom_fields column) in bytes of JSON text.
+func (b *QueryBuilder) BuildDocumentSize(id uuid.UUID) (string, []any, error) {
+	qb := sq.Select(documentSize(b.obj)).From(writeTable(b.obj)).Where(sq.Eq{`"id"`: id})
+	if !b.obj.IsStandard {
+		qb = qb.Where(sq.Eq{`"object_id"`: b.obj.ID})
+	}
+	return qb.PlaceholderFormat(sq.Dollar).ToSql()
+}
+
+// BuildDocumentStats returns the object's record count and the size of its
+// largest document, as BuildDocumentSize measures it.
+func (b *QueryBuilder) BuildDocumentStats() (string, []any, error) {
+	qb := sq.Select("count(*)", "COALESCE(max("+documentSize(b.obj)+"), 0)").From(writeTable(b.obj))
+	if !b.obj.IsStandard {
+		qb = qb.Where(sq.Eq{`"object_id"`: b.obj.ID})
+	}
+	return qb.PlaceholderFormat(sq.Dollar).ToSql()
+}
+
+// documentSize is the expression measuring a record's document; 0 on
+// standard tables without custom fields.
+func documentSize(obj *schema.ObjectDef) string {
+	if obj.IsStandard && !obj.SupportsCustomFields {
+		return "0"
+	}
+	return fmt.Sprintf(`COALESCE(octet_length(%s::text), 0)`, QI(obj.DocumentColumn()))
+}
+
+// writeTable returns the unaliased table that stores the object's records.
+func writeTable(obj *schema.ObjectDef) string {
+	if obj.IsStandard {
+		return obj.TableName()
+	}
+	return `"metadata"."records"`
+}
@@ -1,6 +1,9 @@
 package hrql
 
-import "strings"
+import (
+	"strings"
+	"time"
+)
 
 // PlanKind classifies the output of a compiled HRQL expression.
 type PlanKind int
@@ -9,33 +12,128 @@ const (
 	PlanList    PlanKind = iota // produces a list of records
 	PlanScalar                  // produces a single value (aggregation)
 	PlanBoolean                 // produces a boolean (reports_to)
+	PlanBuckets                 // produces record counts per value range (bucket)
+	PlanIDs                     // produces the IDs of a list's records (ids)
 )
 
+func (k PlanKind) String() string {
+	switch k {
+	case PlanList:
+		return "list"
+	case PlanScalar:
+		return "scalar"
+	case PlanBoolean:
+		return "boolean"
+	case PlanBuckets:
+		return "buckets"
+	case PlanIDs:
+		return "ids"
+	}
+	return "unknown"
+}
+
 // Plan is the storage-agnostic output of compiling an HRQL expression.
 // It captures what the query means, not how to execute it in SQL.
 type Plan struct {
 	Kind PlanKind
+	// Object is the API name of the object a query over records other than
+	// employees reads (departments | where(...)); empty for employees.
+	Object string
 
-	// PlanList fields
+	// PlanList fields, also used by PlanIDs
 	Conditions []Condition // top-level conditions, AND'd together
 	OrderBy    *OrderBy
 	Limit      int    // 0 = no override
 	PickOp     string // "first", "last", "nth"
 	PickN      int    // for nth (1-indexed)
+	Sample     int    // sample(n): n records drawn at random (random order, Limit n)
+	Case       *Case  // computed case(...) value, projected per record or aggregated
 
-	// PlanScalar fields
+	// PlanScalar fields. With no AggFunc, the plan is a projection: the
+	// AggField value of the record a pick selects (first | .field).
 	AggFunc    string     // "count", "sum", "avg", "min", "max"
 	AggField   string     // field API name, "" for count(*)
+	Since      string     // "years", "months" or "days": AggField is a date projected as time elapsed (years_since)
+	Distinct   bool       // unique after the field: the aggregate takes each AggField value once
 	ScalarExpr ScalarExpr // if set, arithmetic expression tree (overrides AggFunc/AggField)
 
 	// PlanBoolean fields
 	BoolCondition Condition // deferred to SQL execution
+
+	// PlanBuckets fields: AggField (projected by Since, if set) is counted
+	// into the ranges Bounds delimit, ascending number literals as written.
+	Bounds []string
+
+	// AsOf, if set, evaluates the whole query against historical state (as_of step).
+	AsOf *time.Time
+
+	// Union, if set, makes the plan a list over the records of several
+	// sources (union(...)); Object and Conditions are unused.
+	Union *Union
+}
+
+// IsProjection reports whether the plan is the value of one field of a
+// picked record rather than an aggregate.
+func (p *Plan) IsProjection() bool {
+	return p.Kind == PlanScalar && p.AggFunc == "" && p.ScalarExpr == nil && p.AggField != ""
 }
 
+// Union is the UNION ALL of list plans over one or more objects, projected
+// onto the fields all of them share.
+type Union struct {
+	Sources []*Plan
+	// Fields are the API names every source has with comparable types, in
+	// the order of the first source. Records carry only these fields.
+	Fields []string
+}
+
+// Warning reports a construct the compiler accepted but approximated, so a
+// result may not mean exactly what the query says.
+type Warning struct {
+	Code    string // stable identifier, one of the Warn* constants
+	Message string
+}
+
+const (
+	// WarnNoOp: a function that has no effect (upper, lower, unique on
+	// records) was ignored.
+	WarnNoOp = "no_op"
+	// WarnChainTruncated: only the first field of a chain (.department.title)
+	// was used, where the step does not follow lookups.
+	WarnChainTruncated = "lookup_chain_truncated"
+	// WarnRenamedField: a field was named by a former api_name, which still
+	// resolves to it (see MetadataService.RenameField).
+	WarnRenamedField = "renamed_field"
+)
+
 // OrderBy specifies sort order for a list result.
 type OrderBy struct {
 	Field string
 	Desc  bool
+	// NullsFirst sorts records without a value before the others; by
+	// default they sort last in either direction.
+	NullsFirst bool
+	Random     bool // sort_by(random) or sample(n); Field is empty
+}
+
+// Case is a compiled case(when ... then ... else ...) expression.
+type Case struct {
+	Whens []CaseWhen
+	Else  *CaseValue // nil yields NULL when no branch matches
+	// Numeric is true when every branch yields a number, so the value can be summed or averaged.
+	Numeric bool
+}
+
+// CaseWhen is one branch of a Case.
+type CaseWhen struct {
+	Cond Condition
+	Then CaseValue
+}
+
+// CaseValue is a branch result: a literal or a single field of the record.
+type CaseValue struct {
+	Literal string
+	Field   string // API name; set instead of Literal
 }
 
 // EmployeeRef is an unresolved reference to an employee or a derived value.
@@ -43,6 +141,9 @@ type OrderBy struct {
 type EmployeeRef struct {
 	ID    string   // base UUID (selfID or literal)
 	Chain []string // optional field chain: ["manager"] for self.manager
+	// Source, when set, replaces ID: a single-record list plan whose employee is
+	// the base, e.g. chain("<uuid>") | first.
+	Source *Plan
 }
 
 // --- Condition types ---
@@ -54,9 +155,10 @@ type Condition interface {
 
 // FieldCmp: .field == "value" (single or lookup-chain field)
 type FieldCmp struct {
-	Field []string // API name chain, e.g. ["department", "title"]
-	Op    string   // "==", "!=", ">", ">=", "<", "<="
-	Value string
+	Field   []string // API name chain, e.g. ["department", "title"]
+	Op      string   // "==", "!=", ">", ">=", "<", "<="
+	Value   string
+	Default *string // .field ?? default: compare the default where the field is NULL
 }
 
 func (FieldCmp) condition() {}
@@ -75,6 +177,9 @@ type StringMatch struct {
 	Field   []string // API name chain
 	Op      string   // "contains", "starts_with", "ends_with"
 	Pattern string
+	// Fold ignores accents: contains_fold, or any operation on a field
+	// flagged accent-insensitive.
+	Fold bool
 }
 
 func (StringMatch) condition() {}
@@ -100,12 +205,20 @@ type OrCond struct{ Left, Right Condition }
 func (OrCond) condition() {}
 
 // --- Org hierarchy conditions ---
-// These carry unresolved EmployeeRef data, not resolved paths.
+// These carry unresolved EmployeeRef data, not resolved paths. Via names the
+// self-lookup field whose hierarchy is traversed (via: .dotted_manager); empty
+// means the management tree and ViaPositions the position-based graph.
+
+// ViaPositions is the Via of org conditions traversing the reporting graph
+// formed by active positions (via: positions): an employee reports to the
+// manager of each position they hold. It is not a valid field name.
+const ViaPositions = "@positions"
 
 // OrgChainUp: ancestor at exactly N levels above target.
 type OrgChainUp struct {
 	Emp   EmployeeRef
 	Steps int
+	Via   string
 }
 
 func (OrgChainUp) condition() {}
@@ -114,20 +227,37 @@ func (OrgChainUp) condition() {}
 type OrgChainDown struct {
 	Emp   EmployeeRef
 	Depth int
+	Via   string
 }
 
 func (OrgChainDown) condition() {}
 
 // OrgChainAll: all ancestors of target (full chain to root).
-type OrgChainAll struct{ Emp EmployeeRef }
+type OrgChainAll struct {
+	Emp EmployeeRef
+	Via string
+}
 
 func (OrgChainAll) condition() {}
 
 // OrgSubtree: all descendants of target (any depth).
-type OrgSubtree struct{ Emp EmployeeRef }
+type OrgSubtree struct {
+	Emp EmployeeRef
+	Via string
+}
 
 func (OrgSubtree) condition() {}
 
+// OrgNetwork: employees within Degree hops of target in the management tree
+// (up, down, or across through a shared manager), excluding target.
+type OrgNetwork struct {
+	Emp    EmployeeRef
+	Degree int
+	Via    string
+}
+
+func (OrgNetwork) condition() {}
+
 // SameFieldCond: column = (SELECT field FROM emp WHERE id = ref.ID) AND id != ref.ID
 type SameFieldCond struct {
 	Field string      // API name
@@ -137,7 +267,10 @@ type SameFieldCond struct {
 func (SameFieldCond) condition() {}
 
 // ReportsTo: reports_to(., target) inside where — ltree descendant check.
-type ReportsTo struct{ Target EmployeeRef }
+type ReportsTo struct {
+	Target EmployeeRef
+	Via    string
+}
 
 func (ReportsTo) condition() {}
 
@@ -145,23 +278,90 @@ func (ReportsTo) condition() {}
 type ReportsToCheck struct {
 	Emp    EmployeeRef
 	Target EmployeeRef
+	Via    string
 }
 
 func (ReportsToCheck) condition() {}
 
+// TeamMember: team("slug") or member_of("slug") inside where — the employee
+// belongs to the team with that slug.
+type TeamMember struct {
+	Team string // teams.slug
+}
+
+func (TeamMember) condition() {}
+
+// EndDateField is the employees field that ends employment: an employee whose
+// end_date is on or before the reference date has left.
+const EndDateField = "end_date"
+
+// Employed: the employee has not left — EndDateField is NULL or after today,
+// or after the as_of moment on a snapshot. Org functions add it unless
+// terminated employees are included.
+type Employed struct{}
+
+func (Employed) condition() {}
+
 // SubqueryAgg: correlated subquery like reports(., 1) | count > 0
 type SubqueryAgg struct {
 	OrgFunc string // "reports"
 	Depth   int
+	Via     string
 	AggFunc string // "count", "sum", etc.
 	Op      string // comparison op in outer context
 	Value   string // comparison value in outer context
+	// Employed counts only reports who have not left (see Employed).
+	Employed bool
 }
 
 func (SubqueryAgg) condition() {}
 
+// RelatedAgg: correlated aggregate over the records of another object whose
+// LOOKUP points at the outer record, e.g. employees(.) | count > 50 inside a
+// departments query.
+type RelatedAgg struct {
+	Object     string      // API name of the related object
+	Via        string      // its LOOKUP field referencing the outer object
+	Conditions []Condition // where() steps, evaluated against the related object
+	AggFunc    string      // "count", "sum", "avg", "min", "max"
+	AggField   string      // field of the related object, "" for count(*)
+	Distinct   bool        // unique after the field: each value counts once
+	Op         string      // comparison op in outer context
+	Value      string      // comparison value in outer context
+}
+
+func (RelatedAgg) condition() {}
+
+// Quantified: all(reports(., depth), pred) / none(...) inside where — whether
+// every or no member of the outer employee's org list satisfies Pred.
+type Quantified struct {
+	Quantifier string // "all", "none"
+	OrgFunc    string // "reports"
+	Depth      int
+	Via        string
+	Pred       Condition // evaluated against each member
+	Employed   bool      // only members who have not left (see Employed)
+}
+
+func (Quantified) condition() {}
+
 // --- REST API filter conditions ---
 
+// DurationCmp: tenure(.start_date) > 2y, or .start_date | years_since >= 5.
+// The duration runs from the From date to the To date, or to now when To is
+// empty or null on the record.
+type DurationCmp struct {
+	From string // date field API name
+	To   string // optional end date field API name
+	// Unit is "" to compare the interval with Value ("2 years"), or "years",
+	// "months" or "days" to compare the whole units elapsed with the number Value.
+	Unit  string
+	Op    string
+	Value string
+}
+
+func (DurationCmp) condition() {}
+
 // InFilter: field IN (values)
 type InFilter struct {
 	Field  []string
@@ -170,6 +370,16 @@ type InFilter struct {
 
 func (InFilter) condition() {}
 
+// InSubquery: .field in <list>, e.g. .id in chain(self) or .department in
+// (departments | where(.title == "Eng")). Field is the id or a LOOKUP to the
+// object Source reads; the ids of Source's records are the set.
+type InSubquery struct {
+	Field  string // API name
+	Source *Plan  // an unpaged list plan
+}
+
+func (InSubquery) condition() {}
+
 // IsNullFilter: field IS NULL / IS NOT NULL
 type IsNullFilter struct {
 	Field  []string
@@ -187,6 +397,15 @@ type LikeFilter struct {
 
 func (LikeFilter) condition() {}
 
+// CustomFilter: deployment-registered REST operator, rendered by the backend's operator registry
+type CustomFilter struct {
+	Field []string
+	Op    string // registered operator name
+	Value string
+}
+
+func (CustomFilter) condition() {}
+
 // --- Scalar expression types (arithmetic) ---
 
 // ScalarExpr represents an expression that produces a single numeric value.
@@ -207,11 +426,28 @@ type ScalarArith struct {
 
 func (ScalarArith) scalarExpr() {}
 
+// ScalarCoalesce is `value ?? default`: Default when Value is NULL (an
+// avg, min or max over no rows, or a division by zero).
+type ScalarCoalesce struct {
+	Value, Default ScalarExpr
+}
+
+func (ScalarCoalesce) scalarExpr() {}
+
 // ScalarSubquery is a sub-plan that produces a scalar (e.g. employees | count).
 type ScalarSubquery struct{ Plan *Plan }
 
 func (ScalarSubquery) scalarExpr() {}
 
+// ScalarFunc applies a formatting function to scalar arguments:
+// "round" (value, decimals), "floor", "ceil" and "percent_of" (value, total).
+type ScalarFunc struct {
+	Name string
+	Args []ScalarExpr
+}
+
+func (ScalarFunc) scalarExpr() {}
+
 // --- Helpers ---
 
 func joinChain(chain []string) string {
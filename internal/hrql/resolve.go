@@ -2,9 +2,11 @@ package hrql
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 
 	"github.com/atlekbai/schema_registry/internal/hrql/parser"
+	"github.com/atlekbai/schema_registry/internal/schema"
 )
 
 // --- Argument resolution helpers ---
@@ -19,31 +21,24 @@ func (c *Compiler) resolveEmployeeArg(arg parser.Node) (EmployeeRef, error) {
 		}
 		return EmployeeRef{ID: c.selfID}, nil
 	case *parser.DotExpr:
+		if c.pipeRef != nil {
+			return *c.pipeRef, nil
+		}
 		return EmployeeRef{}, fmt.Errorf("'.' cannot be resolved to an employee ID outside of where subqueries")
 	case *parser.PipeExpr:
-		// self.manager → EmployeeRef with chain.
-		if len(a.Steps) == 2 {
-			if _, ok := a.Steps[0].(*parser.SelfExpr); ok {
-				if fa, ok := a.Steps[1].(*parser.FieldAccess); ok {
-					if c.selfID == "" {
-						return EmployeeRef{}, fmt.Errorf("`self` requires self_id in the request")
-					}
-					if len(fa.Chain) == 0 {
-						return EmployeeRef{}, fmt.Errorf("empty field access")
-					}
-					// Validate all fields in the chain exist.
-					for _, fieldName := range fa.Chain {
-						if _, ok := c.empObj.FieldsByAPIName[fieldName]; !ok {
-							return EmployeeRef{}, fmt.Errorf("unknown field %q", fieldName)
-						}
-					}
-					return EmployeeRef{ID: c.selfID, Chain: fa.Chain}, nil
-				}
-			}
+		return c.resolvePipeRef(a, false)
+	case *parser.FuncCall:
+		plan, err := c.compileFuncCall(a)
+		if err != nil {
+			return EmployeeRef{}, err
 		}
-		return EmployeeRef{}, fmt.Errorf("cannot resolve complex pipe expression to employee ID")
+		return sourceRef(plan)
 	case *parser.IdentExpr:
-		return EmployeeRef{ID: a.Name}, nil
+		plan, err := c.compileIdent(a)
+		if err != nil {
+			return EmployeeRef{}, err
+		}
+		return sourceRef(plan)
 	case *parser.Literal:
 		if a.Kind == parser.TokString {
 			return EmployeeRef{ID: a.Value}, nil
@@ -54,6 +49,114 @@ func (c *Compiler) resolveEmployeeArg(arg parser.Node) (EmployeeRef, error) {
 	}
 }
 
+// resolvePipeRef resolves a pipe to an EmployeeRef: a root (self, a UUID literal
+// or a single-record pipe such as chain("<uuid>") | first) followed by any number
+// of employee LOOKUP accesses, e.g. self.manager.manager. With value set the last
+// field may be any queryable field (self.manager.department in where values).
+func (c *Compiler) resolvePipeRef(pipe *parser.PipeExpr, value bool) (EmployeeRef, error) {
+	steps := pipe.Steps
+	var chain []string
+	for len(steps) > 1 {
+		fa, ok := steps[len(steps)-1].(*parser.FieldAccess)
+		if !ok {
+			break
+		}
+		if len(fa.Chain) == 0 {
+			return EmployeeRef{}, fmt.Errorf("empty field access")
+		}
+		chain = append(slices.Clone(fa.Chain), chain...)
+		steps = steps[:len(steps)-1]
+	}
+
+	var ref EmployeeRef
+	var err error
+	if len(steps) == 1 {
+		ref, err = c.resolveEmployeeArg(steps[0])
+	} else {
+		var plan *Plan
+		plan, err = c.compilePipe(&parser.PipeExpr{Steps: steps})
+		if err == nil {
+			ref, err = sourceRef(plan)
+		}
+	}
+	if err != nil {
+		return EmployeeRef{}, err
+	}
+
+	for i, fieldName := range chain {
+		if value && i == len(chain)-1 {
+			fd, ok := c.field(c.empObj, fieldName)
+			if !ok {
+				return EmployeeRef{}, fmt.Errorf("unknown field %q", fieldName)
+			}
+			if err := checkComparable(fd); err != nil {
+				return EmployeeRef{}, err
+			}
+			continue
+		}
+		if err := c.checkEmployeeLookup(fieldName); err != nil {
+			return EmployeeRef{}, err
+		}
+	}
+	ref.Chain = append(slices.Clone(ref.Chain), chain...)
+	return ref, nil
+}
+
+// checkEmployeeLookup validates one step of a reference chain: it must be a
+// LOOKUP to employees so the chain keeps resolving to an employee.
+func (c *Compiler) checkEmployeeLookup(fieldName string) error {
+	fd, ok := c.field(c.empObj, fieldName)
+	if !ok {
+		return fmt.Errorf("unknown field %q", fieldName)
+	}
+	if err := checkQueryable(fd); err != nil {
+		return err
+	}
+	if fd.Type != schema.FieldLookup || fd.LookupObjectID == nil || *fd.LookupObjectID != c.empObj.ID {
+		return fmt.Errorf("field %q does not reference an employee", fieldName)
+	}
+	return nil
+}
+
+// sourceRef wraps a compiled plan as the base of an EmployeeRef. The plan must
+// select at most one employee (self, or a list ending in first, last, min_by or
+// max_by) and be translatable as a standalone subquery.
+func sourceRef(plan *Plan) (EmployeeRef, error) {
+	if plan.Kind != PlanList || plan.Limit != 1 {
+		return EmployeeRef{}, fmt.Errorf("employee reference must select a single employee (end the pipe with first, last, min_by or max_by)")
+	}
+	if plan.PickOp == "nth" {
+		return EmployeeRef{}, fmt.Errorf("nth is not supported in employee references")
+	}
+	if plan.AsOf != nil {
+		return EmployeeRef{}, fmt.Errorf("as_of is not supported inside employee references; apply it to the whole query")
+	}
+	// A bare identity plan (self) needs no subquery.
+	if len(plan.Conditions) == 1 && plan.OrderBy == nil {
+		if id, ok := plan.Conditions[0].(IdentityFilter); ok {
+			return EmployeeRef{ID: id.ID}, nil
+		}
+	}
+	if slices.ContainsFunc(plan.Conditions, hasLookupChain) {
+		return EmployeeRef{}, fmt.Errorf("employee references cannot filter on lookup chains")
+	}
+	return EmployeeRef{Source: plan}, nil
+}
+
+// hasLookupChain reports whether cond compares through a LOOKUP chain
+// (.department.title), which needs the schema cache to translate.
+func hasLookupChain(cond Condition) bool {
+	switch c := cond.(type) {
+	case FieldCmp:
+		return len(c.Field) > 1
+	case AndCond:
+		return hasLookupChain(c.Left) || hasLookupChain(c.Right)
+	case OrCond:
+		return hasLookupChain(c.Left) || hasLookupChain(c.Right)
+	}
+	return false
+}
+
 func (c *Compiler) resolveIntArg(arg parser.Node) (int, error) {
 	switch a := arg.(type) {
 	case *parser.Literal:
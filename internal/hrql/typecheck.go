@@ -0,0 +1,272 @@
+package hrql
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/atlekbai/schema_registry/internal/hrql/parser"
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// TypeError reports a where comparison whose operands cannot be compared,
+// such as a date field with a number. Pos is the byte offset of the
+// comparison operator in the query.
+type TypeError struct {
+	Pos int
+	Msg string
+}
+
+func (e *TypeError) Error() string {
+	return fmt.Sprintf("type error at position %d: %s", e.Pos, e.Msg)
+}
+
+// typeClass groups the field types that compare with each other.
+type typeClass int
+
+const (
+	classAny    typeClass = iota // FORMULA: result type unknown, not checked
+	classText                    // TEXT, EMAIL, URL, PHONE
+	classNumber                  // NUMBER, CURRENCY, PERCENTAGE
+	classTime                    // DATE, DATETIME
+	classBool
+	classChoice
+	classMulti // MULTICHOICE
+	classRef   // LOOKUP and id: record ids
+)
+
+func classOf(fd *schema.FieldDef) typeClass {
+	if fd.APIName == "id" {
+		return classRef
+	}
+	switch fd.Type {
+	case schema.FieldText, schema.FieldEmail, schema.FieldURL, schema.FieldPhone:
+		return classText
+	case schema.FieldNumber, schema.FieldCurrency, schema.FieldPercentage:
+		return classNumber
+	case schema.FieldDate, schema.FieldDatetime:
+		return classTime
+	case schema.FieldBoolean:
+		return classBool
+	case schema.FieldChoice:
+		return classChoice
+	case schema.FieldMultichoice:
+		return classMulti
+	case schema.FieldLookup:
+		return classRef
+	}
+	return classAny
+}
+
+// comparable reports whether fields of classes a and b can be compared.
+// CHOICE values are text, so they compare with text fields.
+func comparable(a, b typeClass) bool {
+	if a == b || a == classAny || b == classAny {
+		return true
+	}
+	text := func(c typeClass) bool { return c == classText || c == classChoice }
+	return text(a) && text(b)
+}
+
+// checkComparison type-checks the compiled operands of a where comparison
+// against the fields they reference, before any SQL is generated: the
+// operator must apply to the field type, a literal must be a valid value of
+// it, and two fields must hold comparable values.
+func (c *Compiler) checkComparison(op *parser.BinaryOp, left, right any) error {
+	lf, lok := left.(fieldRef)
+	rf, rok := right.(fieldRef)
+	switch {
+	case lok && rok:
+		return c.checkFields(op.Pos, op.Op, c.fieldAt(c.obj, lf.chain), fieldName(lf.chain), c.fieldAt(c.obj, rf.chain), fieldName(rf.chain))
+	case lok:
+		return c.checkFieldOperand(op.Pos, op.Op, lf, op.Left, right, op.Right)
+	case rok:
+		return c.checkFieldOperand(op.Pos, reverseOp(op.Op), rf, op.Right, left, op.Left)
+	}
+	return nil
+}
+
+// checkFieldOperand checks field op other, where other is the compiled
+// operand from node.
+func (c *Compiler) checkFieldOperand(pos int, op string, f fieldRef, fieldNode parser.Node, other any, node parser.Node) error {
+	fd := c.fieldAt(c.obj, f.chain)
+	if fd == nil {
+		return nil
+	}
+	if err := checkOperator(pos, op, fd, fieldName(f.chain)); err != nil {
+		return err
+	}
+	if f.def != nil {
+		if def, ok := fieldNode.(*parser.BinaryOp); ok {
+			if err := checkLiteral(pos, fd, f.chain, whereLiteral(def.Right)); err != nil {
+				return err
+			}
+		}
+	}
+	switch v := other.(type) {
+	case literalVal:
+		return checkLiteral(pos, fd, f.chain, whereLiteral(node))
+	case empRefVal:
+		if v.ref.Source != nil || len(v.ref.Chain) == 0 {
+			return nil
+		}
+		return c.checkFields(pos, op, fd, fieldName(f.chain), c.fieldAt(c.empObj, v.ref.Chain), "self"+fieldName(v.ref.Chain))
+	}
+	return nil
+}
+
+// checkFields checks a comparison between two fields.
+func (c *Compiler) checkFields(pos int, op string, l *schema.FieldDef, lname string, r *schema.FieldDef, rname string) error {
+	if l == nil || r == nil {
+		return nil
+	}
+	if err := checkOperator(pos, op, l, lname); err != nil {
+		return err
+	}
+	if !comparable(classOf(l), classOf(r)) {
+		return typeErr(pos, "cannot compare %s (%s) with %s (%s)", lname, l.Type, rname, r.Type)
+	}
+	if classOf(l) == classRef && classOf(r) == classRef {
+		lt, rt := c.refTarget(l), c.refTarget(r)
+		if lt != "" && rt != "" && lt != rt {
+			return typeErr(pos, "cannot compare %s (a %s record) with %s (a %s record)", lname, lt, rname, rt)
+		}
+	}
+	return nil
+}
+
+// checkOperator rejects ordering comparisons on types without an order; op
+// "in" tests equality.
+func checkOperator(pos int, op string, fd *schema.FieldDef, name string) error {
+	cl := classOf(fd)
+	if cl == classMulti {
+		return typeErr(pos, "%s is MULTICHOICE and holds several options; it cannot be compared with %s", name, op)
+	}
+	if op == "==" || op == "!=" || op == "in" {
+		return nil
+	}
+	switch cl {
+	case classBool, classChoice, classRef:
+		return typeErr(pos, "%s is %s and has no order; use == or !=, not %s", name, fd.Type, op)
+	}
+	return nil
+}
+
+// checkLiteral checks that lit is a valid value of fd. A nil lit, a value
+// that is not written as a literal, is not checked.
+func checkLiteral(pos int, fd *schema.FieldDef, chain []string, lit *parser.Literal) error {
+	if lit == nil {
+		return nil
+	}
+	name := fieldName(chain)
+	switch classOf(fd) {
+	case classText:
+		if lit.Kind == parser.TokTrue || lit.Kind == parser.TokFalse {
+			return typeErr(pos, "%s is %s; compare it with a string, not %s", name, fd.Type, lit.Value)
+		}
+	case classNumber:
+		if lit.Kind == parser.TokNumber {
+			return nil
+		}
+		if _, err := strconv.ParseFloat(lit.Value, 64); lit.Kind != parser.TokString || err != nil {
+			return typeErr(pos, "%s is %s; compare it with a number, not %s", name, fd.Type, describeLiteral(lit))
+		}
+	case classTime:
+		if lit.Kind != parser.TokString {
+			return typeErr(pos, "%s is %s; compare it with a date string such as \"2024-01-31\", not %s", name, fd.Type, describeLiteral(lit))
+		}
+		if _, err := ParseAsOf(lit.Value); err != nil {
+			return typeErr(pos, "%s is %s; %q is not a date (expected YYYY-MM-DD or RFC 3339)", name, fd.Type, lit.Value)
+		}
+	case classBool:
+		if lit.Kind != parser.TokTrue && lit.Kind != parser.TokFalse {
+			return typeErr(pos, "%s is BOOLEAN; compare it with true or false, not %s", name, describeLiteral(lit))
+		}
+	case classChoice:
+		if lit.Kind != parser.TokString {
+			return typeErr(pos, "%s is CHOICE; compare it with one of its options, not %s", name, describeLiteral(lit))
+		}
+		var options []string
+		for _, o := range fd.ChoiceOptions("") {
+			options = append(options, o.Value)
+		}
+		if len(options) > 0 && !slices.Contains(options, lit.Value) {
+			return typeErr(pos, "%q is not an option of %s (%s)", lit.Value, name, strings.Join(options, ", "))
+		}
+	case classRef:
+		if lit.Kind != parser.TokString {
+			return typeErr(pos, "%s holds record ids; compare it with a UUID string, not %s", name, describeLiteral(lit))
+		}
+		if _, err := uuid.Parse(lit.Value); err != nil {
+			return typeErr(pos, "%s holds record ids; %q is not a UUID", name, lit.Value)
+		}
+	}
+	return nil
+}
+
+// whereLiteral returns the literal a where operand is written as, looking
+// through unary minus; nil for anything else.
+func whereLiteral(node parser.Node) *parser.Literal {
+	switch n := node.(type) {
+	case *parser.Literal:
+		return n
+	case *parser.UnaryMinus:
+		if lit, ok := n.Expr.(*parser.Literal); ok {
+			return &parser.Literal{Kind: lit.Kind, Value: "-" + lit.Value}
+		}
+	}
+	return nil
+}
+
+func describeLiteral(lit *parser.Literal) string {
+	if lit.Kind == parser.TokString {
+		return fmt.Sprintf("the string %q", lit.Value)
+	}
+	return fmt.Sprintf("the %s %s", lit.Kind, lit.Value)
+}
+
+// fieldAt returns the field a validated chain ends at, following LOOKUPs
+// from obj; nil when it does not resolve.
+func (c *Compiler) fieldAt(obj *schema.ObjectDef, chain []string) *schema.FieldDef {
+	var fd *schema.FieldDef
+	for i, name := range chain {
+		if obj == nil {
+			return nil
+		}
+		fd = obj.FieldsByAPIName[name]
+		if fd == nil {
+			return nil
+		}
+		if i < len(chain)-1 {
+			if fd.LookupObjectID == nil {
+				return nil
+			}
+			obj = c.cache.GetByID(*fd.LookupObjectID)
+		}
+	}
+	return fd
+}
+
+// refTarget returns the object whose ids fd holds: its LOOKUP target, or
+// its own object for id. Empty when unknown.
+func (c *Compiler) refTarget(fd *schema.FieldDef) string {
+	id := fd.ObjectID
+	if fd.LookupObjectID != nil {
+		id = *fd.LookupObjectID
+	}
+	if obj := c.cache.GetByID(id); obj != nil {
+		return obj.APIName
+	}
+	return ""
+}
+
+func fieldName(chain []string) string {
+	return "." + joinChain(chain)
+}
+
+func typeErr(pos int, format string, args ...any) error {
+	return &TypeError{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
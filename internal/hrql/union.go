@@ -0,0 +1,150 @@
+package hrql
+
+import (
+	"fmt"
+
+	"github.com/atlekbai/schema_registry/internal/hrql/parser"
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// compileUnion compiles union(a, b, ...), the records of every source in one
+// list: union(employees | where(.employment_type == "FULL_TIME"),
+// contractors | where(.active == true)) | sort_by(.start_date). Each source is
+// a query of its own, starting from its object; it may filter and take its
+// first records, but not sample, project or use as_of. The result has the
+// fields the sources share (see unionObject), which the steps after union
+// resolve against.
+func (c *Compiler) compileUnion(fn *parser.FuncCall) (*Plan, error) {
+	if c.pipeRef != nil {
+		return nil, fmt.Errorf("union must start a query")
+	}
+	u := &Union{}
+	objs := make([]*schema.ObjectDef, len(fn.Args))
+	for i, arg := range fn.Args {
+		obj := c.empObj
+		if root := rootIdent(arg); root != nil && root.Name != "employees" {
+			if obj = c.cache.Get(root.Name); obj == nil {
+				return nil, fmt.Errorf("union source %d: unknown identifier %q", i+1, root.Name)
+			}
+		}
+		c.obj = obj
+		src, err := c.compileNode(arg)
+		if err != nil {
+			return nil, fmt.Errorf("union source %d: %w", i+1, err)
+		}
+		if err := checkUnionSource(src); err != nil {
+			return nil, fmt.Errorf("union source %d: %w", i+1, err)
+		}
+		src.Object = obj.APIName
+		objs[i] = obj
+		u.Sources = append(u.Sources, src)
+	}
+
+	shared, err := c.unionObject(objs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range shared.Fields {
+		u.Fields = append(u.Fields, shared.Fields[i].APIName)
+	}
+	c.obj = shared
+	return &Plan{Kind: PlanList, Union: u}, nil
+}
+
+// checkUnionSource rejects sources that are not plain record lists.
+func checkUnionSource(p *Plan) error {
+	switch {
+	case p.Kind != PlanList:
+		return fmt.Errorf("expected a list of records, got %v", p.Kind)
+	case p.Union != nil:
+		return fmt.Errorf("unions cannot be nested; list every source in one union")
+	case p.AggField != "" || p.Case != nil:
+		return fmt.Errorf("field access and case are not supported inside union")
+	case p.Sample > 0 || (p.OrderBy != nil && p.OrderBy.Random):
+		return fmt.Errorf("sample and random order are not supported inside union")
+	case p.PickOp == "nth":
+		return fmt.Errorf("nth is not supported inside union")
+	case p.AsOf != nil:
+		return fmt.Errorf("as_of is not supported inside union")
+	case p.OrderBy != nil && p.Limit == 0:
+		return fmt.Errorf("sort_by inside union has no effect; sort after union(...)")
+	}
+	return nil
+}
+
+// unionObject returns a pseudo-object holding the fields every object has,
+// in the order of the first. A field shared by name must hold comparable
+// values everywhere (LOOKUPs must reference the same object); ENCRYPTED
+// fields are left out, as they cannot be queried, and so are masked fields,
+// whose records are not presented per object.
+func (c *Compiler) unionObject(objs []*schema.ObjectDef) (*schema.ObjectDef, error) {
+	shared := &schema.ObjectDef{APIName: "union", FieldsByAPIName: make(map[string]*schema.FieldDef)}
+	first := objs[0]
+	for i := range first.Fields {
+		fd := &first.Fields[i]
+		if fd.IsEncrypted() || fd.Masking != nil {
+			continue
+		}
+		common := true
+		field := *fd
+		for _, obj := range objs[1:] {
+			other := obj.FieldsByAPIName[fd.APIName]
+			if other == nil || other.IsEncrypted() || other.Masking != nil {
+				common = false
+				break
+			}
+			if !comparable(classOf(fd), classOf(other)) {
+				return nil, fmt.Errorf("union: field %q is %s on %s but %s on %s", fd.APIName, fd.Type, first.APIName, other.Type, obj.APIName)
+			}
+			if fd.APIName != "id" && classOf(fd) == classRef && c.refTarget(fd) != c.refTarget(other) {
+				return nil, fmt.Errorf("union: field %q references %s on %s but %s on %s", fd.APIName, c.refTarget(fd), first.APIName, c.refTarget(other), obj.APIName)
+			}
+			// A field restricted on any source stays restricted on the union.
+			field.NotFilterable = field.NotFilterable || other.NotFilterable
+			field.NotSortable = field.NotSortable || other.NotSortable
+		}
+		if common {
+			shared.Fields = append(shared.Fields, field)
+		}
+	}
+	for i := range shared.Fields {
+		shared.FieldsByAPIName[shared.Fields[i].APIName] = &shared.Fields[i]
+	}
+	return shared, nil
+}
+
+// checkAfterUnion rejects steps a union list does not support: records of
+// different objects can be sorted, picked and aggregated, but filters belong
+// in the sources.
+func checkAfterUnion(step parser.Node) error {
+	var name string
+	switch s := step.(type) {
+	case *parser.WhereExpr:
+		return fmt.Errorf("where cannot follow union(...); filter each source inside union")
+	case *parser.SortExpr:
+		if !s.Random {
+			return nil
+		}
+		name = "sort_by(random)"
+	case *parser.PickExpr:
+		if s.Op == "first" || s.Op == "last" {
+			return nil
+		}
+		name = s.Op
+	case *parser.CaseExpr:
+		name = "case"
+	case *parser.BucketExpr:
+		name = "bucket"
+	case *parser.IDsExpr:
+		name = "ids"
+	case *parser.FuncCall:
+		switch s.Name {
+		case "length", "unique", "upper", "lower":
+			return nil
+		}
+		name = s.Name
+	default:
+		return nil
+	}
+	return fmt.Errorf("%s cannot follow union(...)", name)
+}
@@ -0,0 +1,109 @@
+package ltreeutil
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	hrqlpg "github.com/atlekbai/schema_registry/internal/hrql/pg"
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// Querier is satisfied by *pgxpool.Pool and pgx.Tx.
+type Querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// CheckWrite validates the hierarchy lookups a write payload sets on record
+// id (uuid.Nil for a new record) before the write runs, so cycles and
+// overly deep trees are rejected with a clear error instead of failing in
+// the path triggers. Unknown parents are left to the foreign key.
+func CheckWrite(ctx context.Context, q Querier, obj *schema.ObjectDef, id uuid.UUID, values map[string]any) error {
+	for name, v := range values {
+		fd := obj.FieldsByAPIName[name]
+		if fd == nil || fd.PathColumn == "" || v == nil {
+			continue
+		}
+		parentID, err := uuid.Parse(fmt.Sprint(v))
+		if err != nil {
+			continue // rejected by the write itself
+		}
+		if parentID == id {
+			return fmt.Errorf("%s: %w: a record cannot be its own parent", name, ErrCycle)
+		}
+
+		path := hrqlpg.QI(fd.PathColumn)
+		var parentPath, selfPath *string
+		var subtreeDepth int
+		err = q.QueryRow(ctx, fmt.Sprintf(`
+SELECT
+	(SELECT %[1]s::text FROM %[2]s WHERE "id" = $1),
+	(SELECT %[1]s::text FROM %[2]s WHERE "id" = $2),
+	COALESCE((SELECT max(nlevel(d.%[1]s)) - nlevel(t.%[1]s) FROM %[2]s t JOIN %[2]s d ON d.%[1]s <@ t.%[1]s WHERE t."id" = $2 GROUP BY t.%[1]s), 0)`,
+			path, obj.TableName()), parentID, id).Scan(&parentPath, &selfPath, &subtreeDepth)
+		if err != nil {
+			return fmt.Errorf("check %s hierarchy: %w", name, err)
+		}
+		if parentPath == nil {
+			continue
+		}
+		if err := CheckParent(deref(selfPath), *parentPath, subtreeDepth); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// RebuildPaths recomputes the path column of the hierarchy along fd from the
+// lookup column in Go and rewrites the rows whose stored path differs. Rows
+// BuildPaths cannot place (cycles, dangling parents, past MaxDepth) keep
+// their stored path and are returned in broken. Run it in a transaction; the
+// table is locked against concurrent writes until it ends.
+func RebuildPaths(ctx context.Context, tx pgx.Tx, obj *schema.ObjectDef, fd *schema.FieldDef) (updated int64, broken []uuid.UUID, err error) {
+	if fd.PathColumn == "" || fd.StorageColumn == nil {
+		return 0, nil, fmt.Errorf("field %q of %q has no hierarchy path", fd.APIName, obj.APIName)
+	}
+	table, fk, path := obj.TableName(), hrqlpg.QI(*fd.StorageColumn), hrqlpg.QI(fd.PathColumn)
+
+	if _, err := tx.Exec(ctx, "LOCK TABLE "+table+" IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+		return 0, nil, fmt.Errorf("lock %s: %w", table, err)
+	}
+	rows, err := tx.Query(ctx, fmt.Sprintf(`SELECT "id", COALESCE(%s, '00000000-0000-0000-0000-000000000000'::uuid) FROM %s`, fk, table))
+	if err != nil {
+		return 0, nil, fmt.Errorf("load %s hierarchy: %w", fd.APIName, err)
+	}
+	parents := make(map[uuid.UUID]uuid.UUID)
+	var id, parent uuid.UUID
+	if _, err := pgx.ForEachRow(rows, []any{&id, &parent}, func() error {
+		parents[id] = parent
+		return nil
+	}); err != nil {
+		return 0, nil, fmt.Errorf("load %s hierarchy: %w", fd.APIName, err)
+	}
+
+	paths, broken := BuildPaths(parents)
+	ids := make([]uuid.UUID, 0, len(paths))
+	labels := make([]string, 0, len(paths))
+	for id, p := range paths {
+		ids = append(ids, id)
+		labels = append(labels, p)
+	}
+	tag, err := tx.Exec(ctx, fmt.Sprintf(`
+UPDATE %[1]s t SET %[2]s = v."path"::ltree
+FROM unnest($1::uuid[], $2::text[]) AS v("id", "path")
+WHERE t."id" = v."id" AND t.%[2]s IS DISTINCT FROM v."path"::ltree`, table, path), ids, labels)
+	if err != nil {
+		return 0, broken, fmt.Errorf("rewrite %s paths: %w", fd.APIName, err)
+	}
+	return tag.RowsAffected(), broken, nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,161 @@
+// Package ltreeutil manipulates the ltree paths that materialize hierarchies
+// along self-referencing LOOKUP fields (employees.manager → manager_path).
+// Each label is a record id without dashes, as core.uuid_to_ltree_label
+// builds them, so a path lists a record's ancestors root first and ends with
+// the record itself.
+package ltreeutil
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// MaxDepth bounds the number of levels in a path. Deeper hierarchies are
+// almost certainly bad data, and every org function's cost grows with depth.
+const MaxDepth = 64
+
+var (
+	// ErrCycle is returned when a parent assignment would make a record its
+	// own ancestor.
+	ErrCycle = errors.New("hierarchy cycle")
+	// ErrTooDeep is returned when a path would exceed MaxDepth levels.
+	ErrTooDeep = errors.New("hierarchy too deep")
+)
+
+// Label returns the ltree label of id.
+func Label(id uuid.UUID) string {
+	return strings.ReplaceAll(id.String(), "-", "")
+}
+
+// LabelToUUID converts a 32-char hex ltree label back to UUID format (8-4-4-4-12).
+func LabelToUUID(label string) string {
+	if len(label) != 32 {
+		return label
+	}
+	return label[0:8] + "-" + label[8:12] + "-" + label[12:16] + "-" + label[16:20] + "-" + label[20:32]
+}
+
+// NLevel returns the number of labels in path, like ltree's nlevel.
+func NLevel(path string) int {
+	if path == "" {
+		return 0
+	}
+	return strings.Count(path, ".") + 1
+}
+
+// IsDescendant checks if path is a strict descendant of ancestor using ltree
+// semantics: path <@ ancestor AND path != ancestor.
+func IsDescendant(path, ancestor string) bool {
+	if path == ancestor {
+		return false
+	}
+	return strings.HasPrefix(path, ancestor+".")
+}
+
+// Build returns the path of a chain of ids ordered root first, e.g. a
+// manager chain ending with the record itself.
+func Build(chain []uuid.UUID) string {
+	labels := make([]string, len(chain))
+	for i, id := range chain {
+		labels[i] = Label(id)
+	}
+	return strings.Join(labels, ".")
+}
+
+// IDs parses path back into its ids, root first.
+func IDs(path string) ([]uuid.UUID, error) {
+	if path == "" {
+		return nil, nil
+	}
+	labels := strings.Split(path, ".")
+	ids := make([]uuid.UUID, len(labels))
+	for i, label := range labels {
+		id, err := uuid.Parse(LabelToUUID(label))
+		if err != nil {
+			return nil, fmt.Errorf("invalid ltree label %q: %w", label, err)
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// CheckParent validates moving a record whose current path is path (empty
+// for a new record) under the parent whose path is parentPath. The parent
+// must not be the record or one of its descendants, and the record's
+// deepest descendant (subtreeDepth levels below it, 0 for a leaf) must stay
+// within MaxDepth.
+func CheckParent(path, parentPath string, subtreeDepth int) error {
+	if path != "" && (parentPath == path || IsDescendant(parentPath, path)) {
+		return fmt.Errorf("%w: %s is a descendant of %s", ErrCycle,
+			LabelToUUID(last(parentPath)), LabelToUUID(last(path)))
+	}
+	if depth := NLevel(parentPath) + 1 + subtreeDepth; depth > MaxDepth {
+		return fmt.Errorf("%w: %d levels, at most %d allowed", ErrTooDeep, depth, MaxDepth)
+	}
+	return nil
+}
+
+// BuildPaths computes the path of every record from its parent (uuid.Nil for
+// roots). Records on a cycle, below one, below a parent missing from
+// parents, or deeper than MaxDepth get no path and are returned in broken,
+// in no particular order.
+func BuildPaths(parents map[uuid.UUID]uuid.UUID) (paths map[uuid.UUID]string, broken []uuid.UUID) {
+	paths = make(map[uuid.UUID]string, len(parents))
+	bad := make(map[uuid.UUID]bool)
+
+	for id := range parents {
+		// Walk up until a resolved ancestor, a root or a dead end, then
+		// resolve the walked chain top-down.
+		var chain []uuid.UUID
+		onChain := make(map[uuid.UUID]bool)
+		cur := id
+		prefix, ok := "", true
+		for {
+			if p, done := paths[cur]; done {
+				prefix = p
+				break
+			}
+			if bad[cur] || onChain[cur] {
+				ok = false
+				break
+			}
+			parent, known := parents[cur]
+			if !known {
+				ok = false
+				break
+			}
+			chain = append(chain, cur)
+			onChain[cur] = true
+			if parent == uuid.Nil {
+				break
+			}
+			cur = parent
+		}
+
+		for _, n := range slices.Backward(chain) {
+			if ok {
+				path := Label(n)
+				if prefix != "" {
+					path = prefix + "." + path
+				}
+				if NLevel(path) > MaxDepth {
+					ok = false
+				} else {
+					paths[n], prefix = path, path
+					continue
+				}
+			}
+			bad[n] = true
+			broken = append(broken, n)
+		}
+	}
+	return paths, broken
+}
+
+func last(path string) string {
+	return path[strings.LastIndexByte(path, '.')+1:]
+}
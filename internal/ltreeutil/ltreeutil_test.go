@@ -0,0 +1,149 @@
+package ltreeutil
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestLabelToUUID(t *testing.T) {
+	label := "550e8400e29b41d4a716446655440000"
+	got := LabelToUUID(label)
+	want := "550e8400-e29b-41d4-a716-446655440000"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if got := Label(uuid.MustParse(want)); got != label {
+		t.Fatalf("Label: expected %q, got %q", label, got)
+	}
+}
+
+func TestLabelToUUIDShort(t *testing.T) {
+	label := "short"
+	got := LabelToUUID(label)
+	if got != label {
+		t.Fatalf("expected %q, got %q", label, got)
+	}
+}
+
+func TestIsDescendant(t *testing.T) {
+	tests := []struct {
+		emp, tgt string
+		want     bool
+	}{
+		{"a.b.c", "a.b", true},
+		{"a.b", "a.b", false},
+		{"a.b", "a.b.c", false},
+		{"a.b.c", "x.y", false},
+		{"a.bc", "a.b", false},
+	}
+	for _, tt := range tests {
+		got := IsDescendant(tt.emp, tt.tgt)
+		if got != tt.want {
+			t.Errorf("IsDescendant(%q, %q): expected %v, got %v", tt.emp, tt.tgt, tt.want, got)
+		}
+	}
+}
+
+func TestBuildAndIDs(t *testing.T) {
+	chain := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	path := Build(chain)
+	if NLevel(path) != 3 {
+		t.Fatalf("NLevel(%q) = %d, want 3", path, NLevel(path))
+	}
+	ids, err := IDs(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(ids, chain) {
+		t.Fatalf("IDs(%q) = %v, want %v", path, ids, chain)
+	}
+	if NLevel("") != 0 {
+		t.Fatal("empty path should have no levels")
+	}
+	if _, err := IDs("a.b"); err == nil {
+		t.Fatal("expected an error for non-uuid labels")
+	}
+}
+
+func TestCheckParent(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	pathB := Build([]uuid.UUID{a, b})
+	pathC := Build([]uuid.UUID{a, b, c})
+
+	if err := CheckParent(pathC, Build([]uuid.UUID{a}), 0); err != nil {
+		t.Fatalf("moving c under a: %v", err)
+	}
+	if err := CheckParent("", pathC, 0); err != nil {
+		t.Fatalf("new record under c: %v", err)
+	}
+	for _, parent := range []string{pathB, pathC} {
+		err := CheckParent(pathB, parent, 1)
+		if !errors.Is(err, ErrCycle) {
+			t.Errorf("moving b under %q: expected ErrCycle, got %v", parent, err)
+		}
+	}
+
+	deep := make([]uuid.UUID, MaxDepth-1)
+	for i := range deep {
+		deep[i] = uuid.New()
+	}
+	if err := CheckParent("", Build(deep), 0); err != nil {
+		t.Fatalf("leaf at MaxDepth: %v", err)
+	}
+	if err := CheckParent(pathB, Build(deep), 1); !errors.Is(err, ErrTooDeep) {
+		t.Fatalf("expected ErrTooDeep, got %v", err)
+	}
+}
+
+func TestBuildPaths(t *testing.T) {
+	root, mid, leaf := uuid.New(), uuid.New(), uuid.New()
+	x, y, below, orphan := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	parents := map[uuid.UUID]uuid.UUID{
+		root:   uuid.Nil,
+		mid:    root,
+		leaf:   mid,
+		x:      y, // x ↔ y cycle
+		y:      x,
+		below:  x,
+		orphan: uuid.New(),
+	}
+
+	paths, broken := BuildPaths(parents)
+	if got, want := paths[leaf], Build([]uuid.UUID{root, mid, leaf}); got != want {
+		t.Errorf("leaf path = %q, want %q", got, want)
+	}
+	if paths[root] != Label(root) {
+		t.Errorf("root path = %q", paths[root])
+	}
+	slices.SortFunc(broken, func(a, b uuid.UUID) int { return strings.Compare(a.String(), b.String()) })
+	want := []uuid.UUID{x, y, below, orphan}
+	slices.SortFunc(want, func(a, b uuid.UUID) int { return strings.Compare(a.String(), b.String()) })
+	if !slices.Equal(broken, want) {
+		t.Errorf("broken = %v, want %v", broken, want)
+	}
+	if len(paths) != 3 {
+		t.Errorf("expected 3 paths, got %d", len(paths))
+	}
+}
+
+func TestBuildPathsMaxDepth(t *testing.T) {
+	parents := make(map[uuid.UUID]uuid.UUID)
+	prev := uuid.Nil
+	chain := make([]uuid.UUID, MaxDepth+2)
+	for i := range chain {
+		chain[i] = uuid.New()
+		parents[chain[i]] = prev
+		prev = chain[i]
+	}
+	paths, broken := BuildPaths(parents)
+	if len(paths) != MaxDepth || len(broken) != 2 {
+		t.Fatalf("got %d paths and %d broken, want %d and 2", len(paths), len(broken), MaxDepth)
+	}
+	if NLevel(paths[chain[MaxDepth-1]]) != MaxDepth {
+		t.Fatalf("deepest path has %d levels", NLevel(paths[chain[MaxDepth-1]]))
+	}
+}
@@ -0,0 +1,206 @@
+// Package metrics records HRQL usage: which functions and steps queries use,
+// the plan kinds they compile to, parse and compile failures and compile
+// time. HRQL implements prometheus.Collector and also serves a snapshot for
+// StatsService, so both read the same counters. APIUsage rolls requests up
+// per object, method and principal for UsageService.
+package metrics
+
+import (
+	"cmp"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atlekbai/schema_registry/internal/hrql"
+	"github.com/atlekbai/schema_registry/internal/hrql/parser"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	queriesDesc = prometheus.NewDesc("hrql_queries_total",
+		"HRQL queries received, by outcome (ok, parse_error, compile_error).", []string{"outcome"}, nil)
+	plansDesc = prometheus.NewDesc("hrql_plans_total",
+		"Compiled HRQL plans by kind (list, scalar, boolean).", []string{"kind"}, nil)
+	functionsDesc = prometheus.NewDesc("hrql_function_uses_total",
+		"Parsed HRQL queries using a function or step, counted once per occurrence.", []string{"function"}, nil)
+	stringOpsDesc = prometheus.NewDesc("hrql_string_op_uses_total",
+		"String operations (contains, starts_with, ends_with) in compiled HRQL filters, by the field they match.", []string{"object", "field"}, nil)
+)
+
+// HRQL collects HRQL usage. The zero value is not usable; use NewHRQL.
+type HRQL struct {
+	mu            sync.Mutex
+	parsed        uint64
+	parseErrors   uint64
+	compileErrors uint64
+	plans         map[string]uint64
+	functions     map[string]uint64
+	stringOps     map[StringOpField]uint64
+
+	compileDuration prometheus.Histogram
+	compileSeconds  float64
+	compiles        uint64
+}
+
+func NewHRQL() *HRQL {
+	return &HRQL{
+		plans:     make(map[string]uint64),
+		functions: make(map[string]uint64),
+		stringOps: make(map[StringOpField]uint64),
+		compileDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "hrql_compile_duration_seconds",
+			Help:    "Time to compile a parsed HRQL query into a plan.",
+			Buckets: []float64{.00005, .0001, .00025, .0005, .001, .0025, .005, .01, .025},
+		}),
+	}
+}
+
+// ObserveParse records a parse attempt and, on success, the functions and
+// steps the query uses.
+func (m *HRQL) ObserveParse(ast parser.Node, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err != nil {
+		m.parseErrors++
+		return
+	}
+	m.parsed++
+	parser.Walk(ast, func(n parser.Node) {
+		if name := functionName(n); name != "" {
+			m.functions[name]++
+		}
+	})
+}
+
+// ObserveCompile records a compile attempt of a parsed query and its duration.
+func (m *HRQL) ObserveCompile(plan *hrql.Plan, d time.Duration, err error) {
+	m.compileDuration.Observe(d.Seconds())
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.compiles++
+	m.compileSeconds += d.Seconds()
+	if err != nil {
+		m.compileErrors++
+		return
+	}
+	m.plans[plan.Kind.String()]++
+	object := cmp.Or(plan.Object, "employees")
+	for _, c := range plan.Conditions {
+		m.countStringOps(object, c)
+	}
+	if plan.BoolCondition != nil {
+		m.countStringOps(object, plan.BoolCondition)
+	}
+	if plan.Union != nil {
+		for _, src := range plan.Union.Sources {
+			for _, c := range src.Conditions {
+				m.countStringOps(src.Object, c)
+			}
+		}
+	}
+}
+
+// StringOpField is a field matched by HRQL string operations: Field is the
+// API name chain from Object, e.g. ["department", "title"] on employees.
+type StringOpField struct {
+	Object string
+	Field  string // chain joined with "."
+}
+
+// countStringOps counts the StringMatch conditions in c against the object
+// they filter. Must be called with m.mu held.
+func (m *HRQL) countStringOps(object string, c hrql.Condition) {
+	switch c := c.(type) {
+	case hrql.StringMatch:
+		m.stringOps[StringOpField{Object: object, Field: strings.Join(c.Field, ".")}]++
+	case hrql.AndCond:
+		m.countStringOps(object, c.Left)
+		m.countStringOps(object, c.Right)
+	case hrql.OrCond:
+		m.countStringOps(object, c.Left)
+		m.countStringOps(object, c.Right)
+	case hrql.Quantified:
+		m.countStringOps("employees", c.Pred)
+	case hrql.RelatedAgg:
+		for _, rc := range c.Conditions {
+			m.countStringOps(c.Object, rc)
+		}
+	}
+}
+
+// functionName names the HRQL function or step a node stands for, or "".
+func functionName(n parser.Node) string {
+	switch n := n.(type) {
+	case *parser.FuncCall:
+		return n.Name
+	case *parser.WhereExpr:
+		return "where"
+	case *parser.SortExpr:
+		return "sort_by"
+	case *parser.PickExpr:
+		return n.Op
+	case *parser.AggExpr:
+		return n.Op
+	case *parser.CaseExpr:
+		return "case"
+	case *parser.RelationExpr:
+		return "relation"
+	}
+	return ""
+}
+
+// Snapshot is a point-in-time copy of the counters.
+type Snapshot struct {
+	Queries       uint64 // parse attempts
+	ParseErrors   uint64
+	CompileErrors uint64
+	Plans         map[string]uint64
+	Functions     map[string]uint64
+	StringOps     map[StringOpField]uint64
+	// MeanCompile is the mean compile duration; zero before the first compile.
+	MeanCompile time.Duration
+}
+
+func (m *HRQL) Snapshot() Snapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := Snapshot{
+		Queries:       m.parsed + m.parseErrors,
+		ParseErrors:   m.parseErrors,
+		CompileErrors: m.compileErrors,
+		Plans:         maps.Clone(m.plans),
+		Functions:     maps.Clone(m.functions),
+		StringOps:     maps.Clone(m.stringOps),
+	}
+	if m.compiles > 0 {
+		s.MeanCompile = time.Duration(m.compileSeconds / float64(m.compiles) * float64(time.Second))
+	}
+	return s
+}
+
+func (m *HRQL) Describe(ch chan<- *prometheus.Desc) {
+	ch <- queriesDesc
+	ch <- plansDesc
+	ch <- functionsDesc
+	ch <- stringOpsDesc
+	m.compileDuration.Describe(ch)
+}
+
+func (m *HRQL) Collect(ch chan<- prometheus.Metric) {
+	s := m.Snapshot()
+	ok := s.Queries - s.ParseErrors - s.CompileErrors
+	ch <- prometheus.MustNewConstMetric(queriesDesc, prometheus.CounterValue, float64(ok), "ok")
+	ch <- prometheus.MustNewConstMetric(queriesDesc, prometheus.CounterValue, float64(s.ParseErrors), "parse_error")
+	ch <- prometheus.MustNewConstMetric(queriesDesc, prometheus.CounterValue, float64(s.CompileErrors), "compile_error")
+	for kind, n := range s.Plans {
+		ch <- prometheus.MustNewConstMetric(plansDesc, prometheus.CounterValue, float64(n), kind)
+	}
+	for name, n := range s.Functions {
+		ch <- prometheus.MustNewConstMetric(functionsDesc, prometheus.CounterValue, float64(n), name)
+	}
+	for f, n := range s.StringOps {
+		ch <- prometheus.MustNewConstMetric(stringOpsDesc, prometheus.CounterValue, float64(n), f.Object, f.Field)
+	}
+	m.compileDuration.Collect(ch)
+}
@@ -0,0 +1,176 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// APIUsage rolls requests up per hour, object, RPC method and principal, and
+// periodically adds the rollups to diagnostics.api_usage, where every
+// instance's traffic meets (UsageService reads it back). Between flushes the
+// rollups live in memory, so a crash loses at most one flush interval. A nil
+// *APIUsage tracks nothing.
+type APIUsage struct {
+	retention time.Duration
+
+	mu      sync.Mutex
+	pending map[usageKey]*usageTotals
+}
+
+type usageKey struct {
+	bucket    time.Time
+	object    string
+	method    string
+	principal string
+}
+
+type usageTotals struct {
+	requests   int64
+	errors     int64
+	latencySum float64 // ms
+	latencyMax float64 // ms
+	records    int64
+}
+
+// NewAPIUsage returns a usage recorder whose stored buckets are pruned once
+// older than retention.
+func NewAPIUsage(retention time.Duration) *APIUsage {
+	return &APIUsage{retention: retention, pending: make(map[usageKey]*usageTotals)}
+}
+
+type usageObjectKey struct{}
+
+// Track starts tracking a request for object (empty when it addresses none)
+// by principal. Call done with the records the response returned and the
+// handler's error. Handlers that only learn their object while running (an
+// HRQL query) name it with SetUsageObject on the returned context.
+func (u *APIUsage) Track(ctx context.Context, object, method, principal string) (context.Context, func(records int, err error)) {
+	if u == nil {
+		return ctx, func(int, error) {}
+	}
+	start := time.Now()
+	obj := &object
+	ctx = context.WithValue(ctx, usageObjectKey{}, obj)
+	return ctx, func(records int, err error) {
+		u.observe(usageKey{
+			bucket:    start.UTC().Truncate(time.Hour),
+			object:    *obj,
+			method:    method,
+			principal: principal,
+		}, time.Since(start), records, err)
+	}
+}
+
+// SetUsageObject names the object a tracked request addresses.
+func SetUsageObject(ctx context.Context, object string) {
+	if obj, ok := ctx.Value(usageObjectKey{}).(*string); ok {
+		*obj = object
+	}
+}
+
+func (u *APIUsage) observe(k usageKey, d time.Duration, records int, err error) {
+	ms := float64(d.Microseconds()) / 1000
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	t := u.pending[k]
+	if t == nil {
+		t = &usageTotals{}
+		u.pending[k] = t
+	}
+	t.requests++
+	if err != nil {
+		t.errors++
+	}
+	t.latencySum += ms
+	t.latencyMax = max(t.latencyMax, ms)
+	t.records += int64(records)
+}
+
+// Start flushes the rollups to pool every interval until ctx is done, then
+// flushes once more.
+func (u *APIUsage) Start(ctx context.Context, pool *pgxpool.Pool, interval time.Duration) {
+	if u == nil {
+		return
+	}
+	go func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				if err := u.Flush(context.WithoutCancel(ctx), pool); err != nil {
+					log.Printf("api usage: %v", err)
+				}
+				return
+			case <-t.C:
+				if err := u.Flush(ctx, pool); err != nil {
+					log.Printf("api usage: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Flush adds the pending rollups to diagnostics.api_usage and prunes buckets
+// past the retention. Rollups that fail to write are kept for the next flush.
+func (u *APIUsage) Flush(ctx context.Context, pool *pgxpool.Pool) error {
+	u.mu.Lock()
+	pending := u.pending
+	u.pending = make(map[usageKey]*usageTotals)
+	u.mu.Unlock()
+
+	if len(pending) > 0 {
+		batch := &pgx.Batch{}
+		for k, t := range pending {
+			batch.Queue(`
+				INSERT INTO diagnostics.api_usage AS u
+					("bucket", "object_name", "method", "principal", "requests", "errors", "latency_ms_sum", "latency_ms_max", "result_records")
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+				ON CONFLICT ("bucket", "object_name", "method", "principal") DO UPDATE SET
+					"requests" = u."requests" + EXCLUDED."requests",
+					"errors" = u."errors" + EXCLUDED."errors",
+					"latency_ms_sum" = u."latency_ms_sum" + EXCLUDED."latency_ms_sum",
+					"latency_ms_max" = GREATEST(u."latency_ms_max", EXCLUDED."latency_ms_max"),
+					"result_records" = u."result_records" + EXCLUDED."result_records"
+			`, k.bucket, k.object, k.method, k.principal, t.requests, t.errors, t.latencySum, t.latencyMax, t.records)
+		}
+		// One transaction, so a failed flush leaves nothing half-added and
+		// the restored rollups are not counted twice.
+		if err := pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+			return tx.SendBatch(ctx, batch).Close()
+		}); err != nil {
+			u.restore(pending)
+			return fmt.Errorf("flush: %w", err)
+		}
+	}
+
+	if _, err := pool.Exec(ctx, `DELETE FROM diagnostics.api_usage WHERE "bucket" < $1`,
+		time.Now().Add(-u.retention)); err != nil {
+		return fmt.Errorf("prune: %w", err)
+	}
+	return nil
+}
+
+// restore merges rollups that failed to flush back into the pending ones.
+func (u *APIUsage) restore(failed map[usageKey]*usageTotals) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for k, f := range failed {
+		t := u.pending[k]
+		if t == nil {
+			u.pending[k] = f
+			continue
+		}
+		t.requests += f.requests
+		t.errors += f.errors
+		t.latencySum += f.latencySum
+		t.latencyMax = max(t.latencyMax, f.latencyMax)
+		t.records += f.records
+	}
+}
@@ -0,0 +1,187 @@
+package retention
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/atlekbai/schema_registry/internal/db"
+	hrqlpg "github.com/atlekbai/schema_registry/internal/hrql/pg"
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// AnonymizeResult summarizes an Anonymize run.
+type AnonymizeResult struct {
+	Fields     []string // the is_pii fields rewritten
+	Anonymized int      // records that held a value in one of them
+}
+
+// Anonymize replaces the values of obj's is_pii fields with Fake values
+// under salt, for restoring production snapshots into lower environments.
+// Records are rewritten batch by batch through the regular update, so
+// versions and lookup labels follow, and their earlier history versions and
+// as_of snapshots are dropped as purges drop them.
+func (e *Enforcer) Anonymize(ctx context.Context, obj *schema.ObjectDef, salt string) (AnonymizeResult, error) {
+	var res AnonymizeResult
+	var fields []*schema.FieldDef
+	for i := range obj.Fields {
+		if fd := &obj.Fields[i]; fd.IsPII {
+			fields = append(fields, fd)
+			res.Fields = append(res.Fields, fd.APIName)
+		}
+	}
+	if len(fields) == 0 {
+		return res, nil
+	}
+
+	after := uuid.Nil
+	for {
+		n, last, more, err := e.anonymizeBatch(ctx, obj, fields, salt, after)
+		res.Anonymized += n
+		if err != nil || !more {
+			return res, err
+		}
+		after = last
+	}
+}
+
+// anonymizeBatch rewrites the next batchSize records with ids after the given
+// one that hold a value in one of fields, in a single transaction. more is
+// false once no records are left.
+func (e *Enforcer) anonymizeBatch(ctx context.Context, obj *schema.ObjectDef, fields []*schema.FieldDef, salt string, after uuid.UUID) (n int, last uuid.UUID, more bool, err error) {
+	tx, err := db.Begin(ctx, e.pool)
+	if err != nil {
+		return 0, after, false, fmt.Errorf("begin: %w", err)
+	}
+	defer tx.Rollback(context.Background())
+
+	records, err := e.piiValues(ctx, tx, obj, fields, after)
+	if err != nil {
+		return 0, after, false, err
+	}
+	if len(records) == 0 {
+		return 0, after, false, nil
+	}
+
+	builder := hrqlpg.NewBuilder(obj)
+	for _, r := range records {
+		changed := make(map[string]any, len(fields))
+		for i, fd := range fields {
+			if r.values[i] != nil {
+				changed[fd.APIName] = Fake(fd.Type, salt, *r.values[i])
+			}
+		}
+		sqlStr, args, err := builder.BuildUpdate(r.id, changed, 0)
+		if err != nil {
+			return 0, after, false, fmt.Errorf("build anonymize: %w", err)
+		}
+		if _, err := tx.Exec(ctx, sqlStr, args...); err != nil {
+			return 0, after, false, fmt.Errorf("anonymize %s: %w", r.id, err)
+		}
+		if err := e.syncLabels(ctx, tx, obj, r.id, changed); err != nil {
+			return 0, after, false, fmt.Errorf("sync labels of %s: %w", r.id, err)
+		}
+		scrubSQL, scrubArgs, err := hrqlpg.BuildScrubHistory(obj, r.id)
+		if err != nil {
+			return 0, after, false, fmt.Errorf("build history scrub: %w", err)
+		}
+		if _, err := tx.Exec(ctx, scrubSQL, scrubArgs...); err != nil {
+			return 0, after, false, fmt.Errorf("scrub history: %w", err)
+		}
+		if snapSQL, snapArgs, ok := hrqlpg.BuildScrubSnapshots(obj, r.id, false); ok {
+			if _, err := tx.Exec(ctx, snapSQL, snapArgs...); err != nil {
+				return 0, after, false, fmt.Errorf("scrub snapshots: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, after, false, fmt.Errorf("commit: %w", err)
+	}
+	return len(records), records[len(records)-1].id, len(records) == e.batchSize, nil
+}
+
+type piiRecord struct {
+	id     uuid.UUID
+	values []*string // per field, as text; nil when unset
+}
+
+// piiValues locks and returns the next batch of records holding a value in
+// one of fields, with those values.
+func (e *Enforcer) piiValues(ctx context.Context, tx pgx.Tx, obj *schema.ObjectDef, fields []*schema.FieldDef, after uuid.UUID) ([]piiRecord, error) {
+	alias := hrqlpg.Alias()
+	from, base := hrqlpg.TableSource(obj, alias)
+
+	cols := []string{hrqlpg.QI(alias) + `."id"`}
+	held := sq.Or{}
+	for _, fd := range fields {
+		// to_jsonb #>> '{}' reads storage columns and document values alike
+		// as their text, dates as YYYY-MM-DD.
+		value := fmt.Sprintf(`to_jsonb(%s) #>> '{}'`, hrqlpg.SelectFieldExpr(alias, fd))
+		cols = append(cols, value)
+		held = append(held, sq.Expr(value+" IS NOT NULL"))
+	}
+	qb := sq.Select(cols...).From(from).
+		Where(sq.Gt{hrqlpg.QI(alias) + `."id"`: after}).
+		Where(held).
+		OrderBy(hrqlpg.QI(alias) + `."id"`).
+		Limit(uint64(e.batchSize)).
+		Suffix("FOR UPDATE")
+	if base != nil {
+		qb = qb.Where(base)
+	}
+
+	sqlStr, args, err := qb.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build anonymize query: %w", err)
+	}
+	rows, err := tx.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, fmt.Errorf("select records to anonymize: %w", err)
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (piiRecord, error) {
+		r := piiRecord{values: make([]*string, len(fields))}
+		dest := []any{&r.id}
+		for i := range r.values {
+			dest = append(dest, &r.values[i])
+		}
+		return r, row.Scan(dest...)
+	})
+}
+
+// Fake returns the anonymized stand-in for value in a field of type t: an
+// HMAC-SHA256 of value under salt shaped like the type, so equal values get
+// equal fakes wherever they occur and on every run with the same salt.
+// Emails and URLs use the reserved example.com domain, phones the fictional
+// 555 exchange, and dates keep their year.
+func Fake(t schema.FieldType, salt, value string) string {
+	mac := hmac.New(sha256.New, []byte(salt))
+	mac.Write([]byte(value))
+	sum := mac.Sum(nil)
+	tag := "anon-" + hex.EncodeToString(sum[:5])
+
+	switch t {
+	case schema.FieldEmail:
+		return tag + "@example.com"
+	case schema.FieldURL:
+		return "https://example.com/" + tag
+	case schema.FieldPhone:
+		return fmt.Sprintf("+1555%07d", binary.BigEndian.Uint64(sum[:8])%10_000_000)
+	case schema.FieldDate:
+		year := 2000
+		if d, err := time.Parse(time.DateOnly, value[:min(len(value), len(time.DateOnly))]); err == nil {
+			year = d.Year()
+		}
+		day := int(binary.BigEndian.Uint16(sum[:2]) % 365)
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, day).Format(time.DateOnly)
+	}
+	return tag
+}
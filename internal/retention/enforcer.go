@@ -0,0 +1,301 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/atlekbai/schema_registry/internal/db"
+	hrqlpg "github.com/atlekbai/schema_registry/internal/hrql/pg"
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// advisoryLockKey serializes retention runs across server instances.
+const advisoryLockKey int64 = 0x7265_7465_6e74 // "retent"
+
+// scheduledActor is the app.actor_id of purges run on the Start schedule;
+// runs triggered through the admin API carry the caller's actor.
+const scheduledActor = "system:retention"
+
+// Result summarizes one policy's run.
+type Result struct {
+	ObjectName string
+	Action     Action
+	Purged     int
+	Skipped    int // records that could not be purged, e.g. still referenced
+	Err        error
+}
+
+// Enforcer applies enabled retention policies in batches.
+type Enforcer struct {
+	pool      *pgxpool.Pool
+	cache     *schema.Cache
+	batchSize int
+	paused    func() bool
+}
+
+// NewEnforcer returns an enforcer purging at most batchSize records per
+// transaction. Runs are skipped while paused returns true (maintenance mode).
+func NewEnforcer(pool *pgxpool.Pool, cache *schema.Cache, batchSize int, paused func() bool) *Enforcer {
+	return &Enforcer{pool: pool, cache: cache, batchSize: batchSize, paused: paused}
+}
+
+// Start runs all policies every interval until ctx is done.
+func (e *Enforcer) Start(ctx context.Context, interval time.Duration) {
+	ctx = db.WithActor(ctx, db.Actor{ID: scheduledActor})
+	go func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				results, _, err := e.Run(ctx)
+				if err != nil {
+					log.Printf("retention: %v", err)
+					continue
+				}
+				for _, r := range results {
+					if r.Purged > 0 || r.Skipped > 0 || r.Err != nil {
+						log.Printf("retention: %s %s purged=%d skipped=%d err=%v", r.ObjectName, r.Action, r.Purged, r.Skipped, r.Err)
+					}
+				}
+			}
+		}
+	}()
+}
+
+// Run applies every enabled policy once. ran is false when another instance
+// is already running or the server is in maintenance mode. A policy that fails
+// is reported in its Result and does not stop the others.
+func (e *Enforcer) Run(ctx context.Context) (results []Result, ran bool, err error) {
+	if e.paused != nil && e.paused() {
+		return nil, false, nil
+	}
+
+	conn, err := e.pool.Acquire(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	var locked bool
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", advisoryLockKey).Scan(&locked); err != nil {
+		return nil, false, fmt.Errorf("retention lock: %w", err)
+	}
+	if !locked {
+		return nil, false, nil
+	}
+	defer conn.Exec(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", advisoryLockKey)
+
+	policies, err := ListPolicies(ctx, e.pool)
+	if err != nil {
+		return nil, true, err
+	}
+
+	for _, p := range policies {
+		if !p.Enabled {
+			continue
+		}
+		obj := e.cache.GetByID(p.ObjectID)
+		if obj == nil {
+			continue
+		}
+		r := Result{ObjectName: obj.APIName, Action: p.Action}
+		if err := p.Validate(obj); err != nil {
+			// The object's fields changed since the policy was saved.
+			r.Err = fmt.Errorf("invalid policy: %w", err)
+		} else {
+			r.Purged, r.Skipped, r.Err = e.apply(ctx, obj, p)
+		}
+		if _, err := e.pool.Exec(ctx, `UPDATE metadata.retention_policies SET "last_run_at" = now() WHERE "object_id" = $1`, p.ObjectID); err != nil {
+			return results, true, fmt.Errorf("record retention run: %w", err)
+		}
+		results = append(results, r)
+	}
+	return results, true, nil
+}
+
+// apply purges obj's expired records batch by batch, walking ids in order so
+// records that fail (and stay expired) are not selected again.
+func (e *Enforcer) apply(ctx context.Context, obj *schema.ObjectDef, p Policy) (purged, skipped int, err error) {
+	after := uuid.Nil
+	for {
+		n, s, last, err := e.batch(ctx, obj, p, after)
+		purged += n
+		skipped += s
+		if err != nil || n+s < e.batchSize {
+			return purged, skipped, err
+		}
+		after = last
+	}
+}
+
+// batch purges up to batchSize expired records with ids after the given one
+// in a single transaction. Each record runs under a savepoint, so one that
+// cannot be deleted (e.g. still referenced by a LOOKUP) is skipped.
+func (e *Enforcer) batch(ctx context.Context, obj *schema.ObjectDef, p Policy, after uuid.UUID) (purged, skipped int, last uuid.UUID, err error) {
+	tx, err := db.Begin(ctx, e.pool)
+	if err != nil {
+		return 0, 0, after, fmt.Errorf("begin: %w", err)
+	}
+	defer tx.Rollback(context.Background())
+
+	ids, err := e.expired(ctx, tx, obj, p, after)
+	if err != nil {
+		return 0, 0, after, err
+	}
+	if len(ids) == 0 {
+		return 0, 0, after, nil
+	}
+
+	builder := hrqlpg.NewBuilder(obj)
+	for _, id := range ids {
+		ok, err := e.purge(ctx, tx, obj, builder, p, id)
+		if err != nil {
+			return 0, 0, after, err
+		}
+		if !ok {
+			skipped++
+			continue
+		}
+		if _, err := tx.Exec(ctx, `
+INSERT INTO metadata.retention_audit ("object_id", "object_name", "record_id", "action", "date_field", "retain_days")
+VALUES ($1, $2, $3, $4, $5, $6)`,
+			obj.ID, obj.APIName, id, p.Action, p.DateField, p.RetainDays); err != nil {
+			return 0, 0, after, fmt.Errorf("write retention audit: %w", err)
+		}
+		purged++
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, 0, after, fmt.Errorf("commit: %w", err)
+	}
+	return purged, skipped, ids[len(ids)-1], nil
+}
+
+// expired locks and returns the next batch of expired record ids. Anonymized
+// records stay expired, so ANONYMIZE only selects records that still hold a
+// value in one of the fields it clears.
+func (e *Enforcer) expired(ctx context.Context, tx pgx.Tx, obj *schema.ObjectDef, p Policy, after uuid.UUID) ([]uuid.UUID, error) {
+	alias := hrqlpg.Alias()
+	from, base := hrqlpg.TableSource(obj, alias)
+	date := hrqlpg.FilterExpr(alias, obj.FieldsByAPIName[p.DateField])
+
+	qb := sq.Select(hrqlpg.QI(alias) + `."id"`).From(from).
+		Where(sq.Expr(date+" < now() - make_interval(days => ?)", p.RetainDays)).
+		Where(sq.Gt{hrqlpg.QI(alias) + `."id"`: after}).
+		OrderBy(hrqlpg.QI(alias) + `."id"`).
+		Limit(uint64(e.batchSize)).
+		Suffix("FOR UPDATE SKIP LOCKED")
+	if base != nil {
+		qb = qb.Where(base)
+	}
+	if p.Action == ActionAnonymize {
+		held := sq.Or{}
+		for _, name := range p.AnonymizeFields {
+			held = append(held, sq.Expr(hrqlpg.FilterExpr(alias, obj.FieldsByAPIName[name])+" IS NOT NULL"))
+		}
+		qb = qb.Where(held)
+	}
+
+	sqlStr, args, err := qb.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build retention query: %w", err)
+	}
+	rows, err := tx.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, fmt.Errorf("select expired records: %w", err)
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+}
+
+// purge deletes or anonymizes one record under a savepoint, keeps lookup
+// labels in sync and drops the record's earlier history versions and
+// snapshots. It reports false when the record was left untouched.
+func (e *Enforcer) purge(ctx context.Context, tx pgx.Tx, obj *schema.ObjectDef, builder hrqlpg.Builder, p Policy, id uuid.UUID) (bool, error) {
+	var (
+		sqlStr  string
+		args    []any
+		changed map[string]any
+		err     error
+	)
+	if p.Action == ActionDelete {
+		sqlStr, args, err = builder.BuildDelete(id, 0)
+	} else {
+		changed = make(map[string]any, len(p.AnonymizeFields))
+		for _, name := range p.AnonymizeFields {
+			changed[name] = nil
+		}
+		sqlStr, args, err = builder.BuildUpdate(id, changed, 0)
+	}
+	if err != nil {
+		return false, fmt.Errorf("build purge: %w", err)
+	}
+
+	sp, err := tx.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("savepoint: %w", err)
+	}
+	if _, err := sp.Exec(ctx, sqlStr, args...); err != nil {
+		sp.Rollback(ctx)
+		log.Printf("retention: skip %s %s: %v", obj.APIName, id, err)
+		return false, nil
+	}
+	if err := e.syncLabels(ctx, sp, obj, id, changed); err != nil {
+		sp.Rollback(ctx)
+		log.Printf("retention: skip %s %s: %v", obj.APIName, id, err)
+		return false, nil
+	}
+	// Earlier versions in the record history still hold the purged values.
+	scrubSQL, scrubArgs, err := hrqlpg.BuildScrubHistory(obj, id)
+	if err != nil {
+		sp.Rollback(ctx)
+		return false, fmt.Errorf("build history scrub: %w", err)
+	}
+	if _, err := sp.Exec(ctx, scrubSQL, scrubArgs...); err != nil {
+		sp.Rollback(ctx)
+		return false, fmt.Errorf("scrub history: %w", err)
+	}
+	// So do the snapshots as_of reads for objects with a history table.
+	if snapSQL, snapArgs, ok := hrqlpg.BuildScrubSnapshots(obj, id, p.Action == ActionDelete); ok {
+		if _, err := sp.Exec(ctx, snapSQL, snapArgs...); err != nil {
+			sp.Rollback(ctx)
+			return false, fmt.Errorf("scrub snapshots: %w", err)
+		}
+	}
+	if err := sp.Commit(ctx); err != nil {
+		return false, fmt.Errorf("release savepoint: %w", err)
+	}
+	return true, nil
+}
+
+// syncLabels mirrors RegistryService.syncLabels: a nil changed is a delete.
+func (e *Enforcer) syncLabels(ctx context.Context, tx pgx.Tx, obj *schema.ObjectDef, id uuid.UUID, changed map[string]any) error {
+	stmts, err := hrqlpg.BuildPropagateLabels(obj, e.cache, id, changed)
+	if err != nil {
+		return err
+	}
+	if changed != nil {
+		refresh, ok, err := hrqlpg.BuildRefreshLabels(obj, e.cache, id, changed)
+		if err != nil {
+			return err
+		}
+		if ok {
+			stmts = append([]hrqlpg.LabelStatement{refresh}, stmts...)
+		}
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(ctx, stmt.SQL, stmt.Args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+// Package retention enforces object-level data retention policies: records
+// whose date field is older than a policy's retention period are deleted or
+// anonymized in batches, and every purged record is written to
+// metadata.retention_audit. Anonymize rewrites an object's PII fields with
+// fakes for lower environments.
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// Action is what a policy does to an expired record.
+type Action string
+
+const (
+	ActionDelete    Action = "DELETE"
+	ActionAnonymize Action = "ANONYMIZE" // clears AnonymizeFields, keeps the record
+)
+
+// Policy is the retention policy of one object.
+type Policy struct {
+	ObjectID        uuid.UUID
+	DateField       string // DATE or DATETIME field the retention period counts from
+	RetainDays      int
+	Action          Action
+	AnonymizeFields []string
+	Enabled         bool
+
+	LastRunAt *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Querier is satisfied by *pgxpool.Pool and pgx.Tx.
+type Querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// Validate checks the policy against the object's fields.
+func (p *Policy) Validate(obj *schema.ObjectDef) error {
+	if p.RetainDays < 1 {
+		return fmt.Errorf("retain_days must be positive, got %d", p.RetainDays)
+	}
+	fd := obj.FieldsByAPIName[p.DateField]
+	if fd == nil {
+		return fmt.Errorf("unknown date field %q on %q", p.DateField, obj.APIName)
+	}
+	if fd.Type != schema.FieldDate && fd.Type != schema.FieldDatetime {
+		return fmt.Errorf("date field %q is %s, expected DATE or DATETIME", p.DateField, fd.Type)
+	}
+
+	switch p.Action {
+	case ActionDelete:
+		if len(p.AnonymizeFields) > 0 {
+			return errors.New("anonymize_fields only apply to the ANONYMIZE action")
+		}
+	case ActionAnonymize:
+		if len(p.AnonymizeFields) == 0 {
+			return errors.New("ANONYMIZE requires at least one field in anonymize_fields")
+		}
+		for _, name := range p.AnonymizeFields {
+			fd := obj.FieldsByAPIName[name]
+			switch {
+			case fd == nil:
+				return fmt.Errorf("unknown anonymize field %q on %q", name, obj.APIName)
+			case schema.IsSystemField(name):
+				return fmt.Errorf("system field %q cannot be anonymized", name)
+			case fd.IsRequired:
+				return fmt.Errorf("required field %q cannot be anonymized", name)
+			case fd.Type == schema.FieldFormula:
+				return fmt.Errorf("formula field %q cannot be anonymized", name)
+			}
+		}
+		if slices.Contains(p.AnonymizeFields, p.DateField) {
+			// Clearing it would hide the record from later runs, but also
+			// lose when it expired; keep it and clear the rest.
+			return fmt.Errorf("date field %q cannot be anonymized", p.DateField)
+		}
+	default:
+		return fmt.Errorf("unknown action %q, expected DELETE or ANONYMIZE", p.Action)
+	}
+	return nil
+}
+
+const policyColumns = `"object_id", "date_field", "retain_days", "action", "anonymize_fields", "enabled", "last_run_at", "created_at", "updated_at"`
+
+func scanPolicy(row pgx.Row) (Policy, error) {
+	var p Policy
+	err := row.Scan(&p.ObjectID, &p.DateField, &p.RetainDays, &p.Action, &p.AnonymizeFields, &p.Enabled, &p.LastRunAt, &p.CreatedAt, &p.UpdatedAt)
+	return p, err
+}
+
+// ListPolicies returns every retention policy.
+func ListPolicies(ctx context.Context, q Querier) ([]Policy, error) {
+	rows, err := q.Query(ctx, `SELECT `+policyColumns+` FROM metadata.retention_policies ORDER BY "created_at"`)
+	if err != nil {
+		return nil, fmt.Errorf("list retention policies: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Policy
+	for rows.Next() {
+		p, err := scanPolicy(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan retention policy: %w", err)
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+// SavePolicy creates or replaces the policy of p.ObjectID.
+func SavePolicy(ctx context.Context, q Querier, p Policy) (Policy, error) {
+	if p.AnonymizeFields == nil {
+		p.AnonymizeFields = []string{}
+	}
+	row := q.QueryRow(ctx, `
+INSERT INTO metadata.retention_policies ("object_id", "date_field", "retain_days", "action", "anonymize_fields", "enabled")
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT ("object_id") DO UPDATE SET
+	"date_field" = EXCLUDED."date_field",
+	"retain_days" = EXCLUDED."retain_days",
+	"action" = EXCLUDED."action",
+	"anonymize_fields" = EXCLUDED."anonymize_fields",
+	"enabled" = EXCLUDED."enabled",
+	"updated_at" = now()
+RETURNING `+policyColumns,
+		p.ObjectID, p.DateField, p.RetainDays, p.Action, p.AnonymizeFields, p.Enabled)
+	saved, err := scanPolicy(row)
+	if err != nil {
+		return Policy{}, fmt.Errorf("save retention policy: %w", err)
+	}
+	return saved, nil
+}
+
+// DeletePolicy removes the policy of objectID and reports whether it existed.
+func DeletePolicy(ctx context.Context, q Querier, objectID uuid.UUID) (bool, error) {
+	var n int
+	err := q.QueryRow(ctx, `WITH d AS (DELETE FROM metadata.retention_policies WHERE "object_id" = $1 RETURNING 1) SELECT count(*) FROM d`, objectID).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("delete retention policy: %w", err)
+	}
+	return n > 0, nil
+}
+
+// AuditEntry is one record purged by a policy.
+type AuditEntry struct {
+	PurgedAt   time.Time
+	ObjectName string
+	RecordID   uuid.UUID
+	Action     Action
+	DateField  string
+	RetainDays int
+}
+
+// ListAudit returns the most recent audit entries, newest first, optionally
+// for one object.
+func ListAudit(ctx context.Context, q Querier, objectName string, limit int) ([]AuditEntry, error) {
+	sql := `SELECT "purged_at", "object_name", "record_id", "action", "date_field", "retain_days" FROM metadata.retention_audit`
+	args := []any{limit}
+	if objectName != "" {
+		sql += ` WHERE "object_name" = $2`
+		args = append(args, objectName)
+	}
+	rows, err := q.Query(ctx, sql+` ORDER BY "purged_at" DESC, "id" DESC LIMIT $1`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list retention audit: %w", err)
+	}
+	defer rows.Close()
+
+	var out []AuditEntry
+	for rows.Next() {
+		var e AuditEntry
+		if err := rows.Scan(&e.PurgedAt, &e.ObjectName, &e.RecordID, &e.Action, &e.DateField, &e.RetainDays); err != nil {
+			return nil, fmt.Errorf("scan retention audit: %w", err)
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
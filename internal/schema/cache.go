@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -14,67 +17,212 @@ const loadQuery = `
 SELECT
 	o.id, o.api_name, o.title, o.plural_title,
 	o.is_standard, o.storage_schema, o.storage_table, o.supports_custom_fields,
+	COALESCE(o.description, ''), o.category_id, o.created_at, o.updated_at,
+	COALESCE(o.default_order, ''), COALESCE(o.default_page_size, 0), COALESCE(o.max_page_size, 0),
+	COALESCE(o.max_custom_fields, 0), COALESCE(o.max_data_bytes, 0), COALESCE(o.exact_count_threshold, 0),
+	COALESCE(o.cache_max_age_seconds, 0),
+	o.validation_webhook_url, COALESCE(o.validation_webhook_timeout_ms, 0), o.validation_webhook_fail_open,
+	COALESCE(o.display_template, ''),
+	o.deprecated_at, o.sunset_at, COALESCE(o.replacement, ''),
 	f.id, f.api_name, f.title, f.type, f.type_config,
-	f.is_required, f.is_unique, f.is_standard,
-	f.storage_column, f.lookup_object_id
+	f.is_required, f.is_unique, f.is_external_id, f.is_searchable, f.is_accent_insensitive,
+	f.is_filterable, f.is_sortable, f.is_standard,
+	f.storage_column, f.lookup_object_id, COALESCE(f.hierarchy_path_column, ''),
+	f.description, f.created_at, f.updated_at, f.masking, f.is_pii
 FROM metadata.objects o
 LEFT JOIN metadata.fields f ON f.object_id = o.id
-ORDER BY o.api_name, f.created_at
+`
+
+const loadOrder = ` ORDER BY o.api_name, f.created_at`
+
+const aliasQuery = `
+SELECT a.alias, o.api_name, a.created_at
+FROM metadata.object_aliases a
+JOIN metadata.objects o ON o.id = a.object_id
+`
+
+const aliasOrder = ` ORDER BY a.created_at, a.alias`
+
+const fieldAliasQuery = `
+SELECT a.alias, o.api_name, a.field_id, a.created_at
+FROM metadata.field_aliases a
+JOIN metadata.objects o ON o.id = a.object_id
 `
 
 type Cache struct {
 	mu      sync.RWMutex
 	objects map[string]*ObjectDef
 	byID    map[uuid.UUID]*ObjectDef
+	aliases map[string]*ObjectDef
+
+	// generation counts loads and object reloads; loadedAt is the last full load.
+	generation uint64
+	loadedAt   time.Time
 }
 
 func NewCache() *Cache {
 	return &Cache{
 		objects: make(map[string]*ObjectDef),
 		byID:    make(map[uuid.UUID]*ObjectDef),
+		aliases: make(map[string]*ObjectDef),
 	}
 }
 
 func (c *Cache) Load(ctx context.Context, pool *pgxpool.Pool) error {
-	rows, err := pool.Query(ctx, loadQuery)
+	objects, err := fetchObjects(ctx, pool, "")
 	if err != nil {
-		return fmt.Errorf("schema cache load: %w", err)
+		return err
+	}
+
+	byID := make(map[uuid.UUID]*ObjectDef, len(objects))
+	aliases := make(map[string]*ObjectDef)
+	for _, obj := range objects {
+		byID[obj.ID] = obj
+		for _, a := range obj.Aliases {
+			aliases[a.Name] = obj
+		}
+	}
+
+	c.mu.Lock()
+	c.objects = objects
+	c.byID = byID
+	c.aliases = aliases
+	c.generation++
+	c.loadedAt = time.Now()
+	c.mu.Unlock()
+
+	return nil
+}
+
+// ReloadObject re-reads one object from the catalog and replaces its entry,
+// dropping it when it no longer exists. It reports whether the object exists.
+// apiName may be the object's new name after a rename; the entry under its
+// old name is replaced too.
+func (c *Cache) ReloadObject(ctx context.Context, pool *pgxpool.Pool, apiName string) (bool, error) {
+	objects, err := fetchObjects(ctx, pool, " WHERE o.api_name = $1", apiName)
+	if err != nil {
+		return false, err
+	}
+	obj := objects[apiName]
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.remove(c.objects[apiName])
+	if obj != nil {
+		c.remove(c.byID[obj.ID])
+		c.objects[apiName] = obj
+		c.byID[obj.ID] = obj
+		for _, a := range obj.Aliases {
+			c.aliases[a.Name] = obj
+		}
+	}
+	c.generation++
+	return obj != nil, nil
+}
+
+// remove drops obj's entries, if obj is non-nil. Callers hold c.mu.
+func (c *Cache) remove(obj *ObjectDef) {
+	if obj == nil {
+		return
+	}
+	delete(c.objects, obj.APIName)
+	delete(c.byID, obj.ID)
+	for _, a := range obj.Aliases {
+		if c.aliases[a.Name] == obj {
+			delete(c.aliases, a.Name)
+		}
+	}
+}
+
+// Generation returns the number of loads and object reloads so far and the
+// time of the last full load.
+func (c *Cache) Generation() (uint64, time.Time) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.generation, c.loadedAt
+}
+
+// fetchObjects runs loadQuery with an optional WHERE clause and builds the
+// object definitions it returns, keyed by API name.
+func fetchObjects(ctx context.Context, pool *pgxpool.Pool, where string, args ...any) (map[string]*ObjectDef, error) {
+	rows, err := pool.Query(ctx, loadQuery+where+loadOrder, args...)
+	if err != nil {
+		return nil, fmt.Errorf("schema cache load: %w", err)
 	}
 	defer rows.Close()
 
 	objects := make(map[string]*ObjectDef)
+	loadedAt := time.Now()
 
 	for rows.Next() {
 		var (
-			oID             uuid.UUID
-			oAPIName        string
-			oTitle          string
-			oPluralTitle    string
-			oIsStandard     bool
-			oStorageSchema  *string
-			oStorageTable   *string
-			oSupportsCustom bool
-			fID             *uuid.UUID
-			fAPIName        *string
-			fTitle          *string
-			fType           *string
-			fTypeConfig     json.RawMessage
-			fIsRequired     *bool
-			fIsUnique       *bool
-			fIsStandard     *bool
-			fStorageColumn  *string
-			fLookupObjectID *uuid.UUID
+			oID                  uuid.UUID
+			oAPIName             string
+			oTitle               string
+			oPluralTitle         string
+			oIsStandard          bool
+			oStorageSchema       *string
+			oStorageTable        *string
+			oSupportsCustom      bool
+			oDescription         string
+			oCategoryID          *uuid.UUID
+			oCreatedAt           time.Time
+			oUpdatedAt           time.Time
+			oDefaultOrder        string
+			oDefaultPage         int
+			oMaxPage             int
+			oMaxFields           int
+			oMaxDataBytes        int
+			oCountThreshold      int64
+			oCacheMaxAge         int
+			oWebhookURL          *string
+			oWebhookTimeout      int
+			oWebhookFailOpen     bool
+			oDisplayTemplate     string
+			oDeprecatedAt        *time.Time
+			oSunsetAt            *time.Time
+			oReplacement         string
+			fID                  *uuid.UUID
+			fAPIName             *string
+			fTitle               *string
+			fType                *string
+			fTypeConfig          json.RawMessage
+			fIsRequired          *bool
+			fIsUnique            *bool
+			fIsExternalID        *bool
+			fIsSearchable        *bool
+			fIsAccentInsensitive *bool
+			fIsFilterable        *bool
+			fIsSortable          *bool
+			fIsStandard          *bool
+			fStorageColumn       *string
+			fLookupObjectID      *uuid.UUID
+			fPathColumn          *string
+			fDescription         *string
+			fCreatedAt           *time.Time
+			fUpdatedAt           *time.Time
+			fMasking             *MaskingPolicy
+			fIsPII               *bool
 		)
 
 		err := rows.Scan(
 			&oID, &oAPIName, &oTitle, &oPluralTitle,
 			&oIsStandard, &oStorageSchema, &oStorageTable, &oSupportsCustom,
+			&oDescription, &oCategoryID, &oCreatedAt, &oUpdatedAt,
+			&oDefaultOrder, &oDefaultPage, &oMaxPage,
+			&oMaxFields, &oMaxDataBytes, &oCountThreshold,
+			&oCacheMaxAge,
+			&oWebhookURL, &oWebhookTimeout, &oWebhookFailOpen,
+			&oDisplayTemplate,
+			&oDeprecatedAt, &oSunsetAt, &oReplacement,
 			&fID, &fAPIName, &fTitle, &fType, &fTypeConfig,
-			&fIsRequired, &fIsUnique, &fIsStandard,
-			&fStorageColumn, &fLookupObjectID,
+			&fIsRequired, &fIsUnique, &fIsExternalID, &fIsSearchable, &fIsAccentInsensitive,
+			&fIsFilterable, &fIsSortable, &fIsStandard,
+			&fStorageColumn, &fLookupObjectID, &fPathColumn,
+			&fDescription, &fCreatedAt, &fUpdatedAt, &fMasking, &fIsPII,
 		)
 		if err != nil {
-			return fmt.Errorf("schema cache scan: %w", err)
+			return nil, fmt.Errorf("schema cache scan: %w", err)
 		}
 
 		obj, exists := objects[oAPIName]
@@ -88,51 +236,138 @@ func (c *Cache) Load(ctx context.Context, pool *pgxpool.Pool) error {
 				StorageSchema:        oStorageSchema,
 				StorageTable:         oStorageTable,
 				SupportsCustomFields: oSupportsCustom,
+				Description:          oDescription,
+				CategoryID:           oCategoryID,
+				CreatedAt:            oCreatedAt,
+				UpdatedAt:            oUpdatedAt,
+				DefaultOrder:         oDefaultOrder,
+				DefaultPageSize:      oDefaultPage,
+				MaxPageSize:          oMaxPage,
+				MaxCustomFields:      oMaxFields,
+				MaxDataBytes:         oMaxDataBytes,
+				ExactCountThreshold:  oCountThreshold,
+				CacheMaxAge:          time.Duration(oCacheMaxAge) * time.Second,
+				LoadedAt:             loadedAt,
+				DisplayTemplate:      oDisplayTemplate,
 				FieldsByAPIName:      make(map[string]*FieldDef),
 			}
+			if oWebhookURL != nil {
+				obj.ValidationWebhook = &ValidationWebhook{
+					URL:      *oWebhookURL,
+					Timeout:  time.Duration(oWebhookTimeout) * time.Millisecond,
+					FailOpen: oWebhookFailOpen,
+				}
+			}
+			if oDeprecatedAt != nil {
+				obj.Deprecation = &Deprecation{At: *oDeprecatedAt, SunsetAt: oSunsetAt, Replacement: oReplacement}
+			}
 			objects[oAPIName] = obj
 		}
 
 		if fID != nil {
 			field := FieldDef{
-				ID:             *fID,
-				ObjectID:       oID,
-				APIName:        *fAPIName,
-				Title:          *fTitle,
-				Type:           FieldType(*fType),
-				TypeConfig:     fTypeConfig,
-				IsRequired:     *fIsRequired,
-				IsUnique:       *fIsUnique,
-				IsStandard:     *fIsStandard,
-				StorageColumn:  fStorageColumn,
-				LookupObjectID: fLookupObjectID,
+				ID:                *fID,
+				ObjectID:          oID,
+				APIName:           *fAPIName,
+				Title:             *fTitle,
+				Type:              FieldType(*fType),
+				TypeConfig:        fTypeConfig,
+				IsRequired:        *fIsRequired,
+				IsUnique:          *fIsUnique,
+				IsExternalID:      *fIsExternalID,
+				IsSearchable:      *fIsSearchable,
+				AccentInsensitive: *fIsAccentInsensitive,
+				NotFilterable:     !*fIsFilterable,
+				NotSortable:       !*fIsSortable,
+				IsStandard:        *fIsStandard,
+				StorageColumn:     fStorageColumn,
+				LookupObjectID:    fLookupObjectID,
+				CreatedAt:         *fCreatedAt,
+				UpdatedAt:         *fUpdatedAt,
+				Masking:           fMasking,
+				IsPII:             *fIsPII,
+			}
+			if fDescription != nil {
+				field.Description = *fDescription
+			}
+			if fPathColumn != nil {
+				field.PathColumn = *fPathColumn
 			}
 			obj.Fields = append(obj.Fields, field)
-			obj.FieldsByAPIName[field.APIName] = &obj.Fields[len(obj.Fields)-1]
 		}
 	}
 
 	if err := rows.Err(); err != nil {
-		return fmt.Errorf("schema cache rows: %w", err)
+		return nil, fmt.Errorf("schema cache rows: %w", err)
 	}
 
-	byID := make(map[uuid.UUID]*ObjectDef, len(objects))
-	for _, obj := range objects {
-		byID[obj.ID] = obj
+	rows, err = pool.Query(ctx, aliasQuery+where+aliasOrder, args...)
+	if err != nil {
+		return nil, fmt.Errorf("schema cache load aliases: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			alias   ObjectAlias
+			apiName string
+		)
+		if err := rows.Scan(&alias.Name, &apiName, &alias.CreatedAt); err != nil {
+			return nil, fmt.Errorf("schema cache scan alias: %w", err)
+		}
+		if obj := objects[apiName]; obj != nil {
+			obj.Aliases = append(obj.Aliases, alias)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("schema cache alias rows: %w", err)
 	}
 
-	c.mu.Lock()
-	c.objects = objects
-	c.byID = byID
-	c.mu.Unlock()
+	rows, err = pool.Query(ctx, fieldAliasQuery+where+aliasOrder, args...)
+	if err != nil {
+		return nil, fmt.Errorf("schema cache load field aliases: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			alias   FieldAlias
+			apiName string
+			fieldID uuid.UUID
+		)
+		if err := rows.Scan(&alias.Name, &apiName, &fieldID, &alias.CreatedAt); err != nil {
+			return nil, fmt.Errorf("schema cache scan field alias: %w", err)
+		}
+		obj := objects[apiName]
+		if obj == nil {
+			continue
+		}
+		for i := range obj.Fields {
+			if obj.Fields[i].ID == fieldID {
+				obj.Fields[i].Aliases = append(obj.Fields[i].Aliases, alias)
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("schema cache field alias rows: %w", err)
+	}
 
-	return nil
+	for _, obj := range objects {
+		obj.indexFields()
+		obj.AddSystemFields()
+		obj.bindDocColumns()
+	}
+	return objects, nil
 }
 
+// Get finds an object definition by its api_name or, for renamed objects, a
+// former one (see ObjectDef.Aliases). Callers that must tell the two apart
+// compare the result's APIName.
 func (c *Cache) Get(apiName string) *ObjectDef {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	return c.objects[apiName]
+	if obj := c.objects[apiName]; obj != nil {
+		return obj
+	}
+	return c.aliases[apiName]
 }
 
 // GetByID finds an object definition by its UUID.
@@ -146,8 +381,14 @@ func (c *Cache) GetByID(id uuid.UUID) *ObjectDef {
 func NewCacheFromObjects(objs ...*ObjectDef) *Cache {
 	c := NewCache()
 	for _, obj := range objs {
+		obj.AddSystemFields()
+		obj.indexFields()
+		obj.bindDocColumns()
 		c.objects[obj.APIName] = obj
 		c.byID[obj.ID] = obj
+		for _, a := range obj.Aliases {
+			c.aliases[a.Name] = obj
+		}
 	}
 	return c
 }
@@ -158,3 +399,15 @@ func (c *Cache) ObjectCount() int {
 	defer c.mu.RUnlock()
 	return len(c.objects)
 }
+
+// Objects returns all loaded object definitions sorted by API name.
+func (c *Cache) Objects() []*ObjectDef {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	objs := make([]*ObjectDef, 0, len(c.objects))
+	for _, obj := range c.objects {
+		objs = append(objs, obj)
+	}
+	slices.SortFunc(objs, func(a, b *ObjectDef) int { return strings.Compare(a.APIName, b.APIName) })
+	return objs
+}
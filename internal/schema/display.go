@@ -0,0 +1,126 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxDisplayTemplateLen bounds an object's display template.
+const MaxDisplayTemplateLen = 200
+
+// DisplayPart is a piece of a display template: literal text or a field.
+type DisplayPart struct {
+	Text  string
+	Field string // API name; set instead of Text
+}
+
+// ParseDisplayTemplate splits a display template such as
+// "{first_name} {last_name}" into parts. Braces only delimit field names;
+// the template must reference at least one field.
+func ParseDisplayTemplate(tmpl string) ([]DisplayPart, error) {
+	if len(tmpl) > MaxDisplayTemplateLen {
+		return nil, fmt.Errorf("display template longer than %d characters", MaxDisplayTemplateLen)
+	}
+	var parts []DisplayPart
+	hasField := false
+	for rest := tmpl; rest != ""; {
+		open := strings.IndexAny(rest, "{}")
+		if open < 0 {
+			parts = append(parts, DisplayPart{Text: rest})
+			break
+		}
+		if rest[open] == '}' {
+			return nil, fmt.Errorf("display template %q: unmatched }", tmpl)
+		}
+		if open > 0 {
+			parts = append(parts, DisplayPart{Text: rest[:open]})
+		}
+		name, after, ok := strings.Cut(rest[open+1:], "}")
+		if !ok {
+			return nil, fmt.Errorf("display template %q: unclosed {", tmpl)
+		}
+		name = strings.TrimSpace(name)
+		if name == "" || strings.ContainsAny(name, "{") {
+			return nil, fmt.Errorf("display template %q: expected a field name inside {}", tmpl)
+		}
+		parts = append(parts, DisplayPart{Field: name})
+		hasField = true
+		rest = after
+	}
+	if !hasField {
+		return nil, fmt.Errorf("display template %q references no field", tmpl)
+	}
+	return parts, nil
+}
+
+// DisplayParts returns the parsed display template of o, or nil when o has
+// none. Fields deleted since the template was set are dropped.
+func (o *ObjectDef) DisplayParts() []DisplayPart {
+	if o.DisplayTemplate == "" {
+		return nil
+	}
+	parts, err := ParseDisplayTemplate(o.DisplayTemplate)
+	if err != nil {
+		return nil // validated on write
+	}
+	out := parts[:0]
+	for _, p := range parts {
+		if p.Field == "" || o.FieldsByAPIName[p.Field] != nil {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// CheckDisplayTemplate validates tmpl for o: every placeholder must name a
+// field of o that can be rendered as text, so not ENCRYPTED, LOOKUP,
+// MULTICHOICE or JSON.
+func (o *ObjectDef) CheckDisplayTemplate(tmpl string) error {
+	parts, err := ParseDisplayTemplate(tmpl)
+	if err != nil {
+		return err
+	}
+	for _, p := range parts {
+		if p.Field == "" {
+			continue
+		}
+		fd, ok := o.FieldsByAPIName[p.Field]
+		if !ok {
+			return fmt.Errorf("display template: unknown field %q on %s", p.Field, o.APIName)
+		}
+		switch fd.Type {
+		case FieldEncrypted, FieldLookup, FieldMultichoice, FieldJSON:
+			return fmt.Errorf("display template: %s fields cannot be displayed", fd.Type)
+		}
+	}
+	return nil
+}
+
+// RenameDisplayField rewrites the placeholders of tmpl naming field from to
+// name field to instead. It reports whether any did; a template that does
+// not parse is returned as is.
+func RenameDisplayField(tmpl, from, to string) (string, bool) {
+	parts, err := ParseDisplayTemplate(tmpl)
+	if err != nil {
+		return tmpl, false
+	}
+	var (
+		b       strings.Builder
+		renamed bool
+	)
+	for _, p := range parts {
+		switch p.Field {
+		case "":
+			b.WriteString(p.Text)
+		case from:
+			b.WriteString("{" + to + "}")
+			renamed = true
+		default:
+			b.WriteString("{" + p.Field + "}")
+		}
+	}
+	if !renamed {
+		return tmpl, false
+	}
+	return b.String(), true
+}
@@ -0,0 +1,179 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// fieldTranslations is the per-locale section of a field's type_config:
+//
+//	{"options": ["FULL_TIME", ...],
+//	 "translations": {"de": {"title": "Beschäftigungsart", "options": {"FULL_TIME": "Vollzeit"}}}}
+//
+// Option values stay the stored and filtered keys; translations only change
+// the labels metadata reads present.
+type fieldTranslations struct {
+	Options      []string                     `json:"options"`
+	Translations map[string]localeTranslation `json:"translations"`
+}
+
+type localeTranslation struct {
+	Title   string            `json:"title"`
+	Options map[string]string `json:"options"`
+}
+
+// ChoiceOption is a CHOICE/MULTICHOICE option key with its display label.
+type ChoiceOption struct {
+	Value string
+	Label string
+}
+
+func (f *FieldDef) translations() fieldTranslations {
+	var t fieldTranslations
+	if len(f.TypeConfig) > 0 {
+		_ = json.Unmarshal(f.TypeConfig, &t) // validated on write
+	}
+	return t
+}
+
+// LocalizedTitle returns the field title for locale, falling back to the
+// base language ("de" for "de-CH") and then to the stored title.
+func (f *FieldDef) LocalizedTitle(locale string) string {
+	if tr, ok := lookupLocale(f.translations().Translations, locale); ok && tr.Title != "" {
+		return tr.Title
+	}
+	return f.Title
+}
+
+// ChoiceOptions returns the options of a CHOICE or MULTICHOICE field, labelled
+// for locale. Untranslated options are labelled with their value.
+func (f *FieldDef) ChoiceOptions(locale string) []ChoiceOption {
+	if f.Type != FieldChoice && f.Type != FieldMultichoice {
+		return nil
+	}
+	t := f.translations()
+	tr, _ := lookupLocale(t.Translations, locale)
+	opts := make([]ChoiceOption, len(t.Options))
+	for i, v := range t.Options {
+		label := tr.Options[v]
+		if label == "" {
+			label = v
+		}
+		opts[i] = ChoiceOption{Value: v, Label: label}
+	}
+	return opts
+}
+
+// OptionLabels returns every label of a CHOICE or MULTICHOICE field's
+// options: each option's value, then its translation in each locale (in
+// locale order), option by option.
+func (f *FieldDef) OptionLabels() []ChoiceOption {
+	if f.Type != FieldChoice && f.Type != FieldMultichoice {
+		return nil
+	}
+	t := f.translations()
+	locales := slices.Sorted(maps.Keys(t.Translations))
+	var labels []ChoiceOption
+	for _, v := range t.Options {
+		labels = append(labels, ChoiceOption{Value: v, Label: v})
+		for _, loc := range locales {
+			if label := t.Translations[loc].Options[v]; label != "" {
+				labels = append(labels, ChoiceOption{Value: v, Label: label})
+			}
+		}
+	}
+	return labels
+}
+
+// ValidateTranslations checks the "translations" section of a type_config:
+// locales must be tags such as "de" or "pt-BR", and option labels may only
+// name options the field defines.
+func ValidateTranslations(fieldType FieldType, typeConfig string) error {
+	if typeConfig == "" {
+		return nil
+	}
+	var t fieldTranslations
+	if err := json.Unmarshal([]byte(typeConfig), &t); err != nil {
+		return fmt.Errorf("type_config: %w", err)
+	}
+	known := make(map[string]bool, len(t.Options))
+	for _, v := range t.Options {
+		known[v] = true
+	}
+	for locale, tr := range t.Translations {
+		if !validLocale(locale) {
+			return fmt.Errorf("type_config: invalid locale %q (expected a tag such as \"de\" or \"pt-BR\")", locale)
+		}
+		if len(tr.Options) > 0 && fieldType != FieldChoice && fieldType != FieldMultichoice {
+			return fmt.Errorf("type_config: option labels for locale %q on a %s field", locale, fieldType)
+		}
+		for v := range tr.Options {
+			if !known[v] {
+				return fmt.Errorf("type_config: locale %q labels unknown option %q", locale, v)
+			}
+		}
+	}
+	return nil
+}
+
+// NormalizeLocale canonicalizes a locale tag ("pt_br" -> "pt-BR") and takes
+// the first entry of an Accept-Language style list, ignoring weights.
+func NormalizeLocale(locale string) string {
+	locale, _, _ = strings.Cut(locale, ",")
+	locale, _, _ = strings.Cut(locale, ";")
+	locale = strings.ReplaceAll(strings.TrimSpace(locale), "_", "-")
+	lang, region, ok := strings.Cut(locale, "-")
+	if !ok {
+		return strings.ToLower(lang)
+	}
+	return strings.ToLower(lang) + "-" + strings.ToUpper(region)
+}
+
+func lookupLocale(m map[string]localeTranslation, locale string) (localeTranslation, bool) {
+	if locale == "" || len(m) == 0 {
+		return localeTranslation{}, false
+	}
+	locale = NormalizeLocale(locale)
+	for k, tr := range m {
+		if NormalizeLocale(k) == locale {
+			return tr, true
+		}
+	}
+	if lang, _, ok := strings.Cut(locale, "-"); ok {
+		for k, tr := range m {
+			if NormalizeLocale(k) == lang {
+				return tr, true
+			}
+		}
+	}
+	return localeTranslation{}, false
+}
+
+func validLocale(locale string) bool {
+	lang, region, hasRegion := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-")
+	if len(lang) < 2 || len(lang) > 3 || !isLetters(lang) {
+		return false
+	}
+	return !hasRegion || (len(region) >= 2 && len(region) <= 4 && isAlnum(region))
+}
+
+func isLetters(s string) bool {
+	for _, ch := range s {
+		if !(ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlnum(s string) bool {
+	for _, ch := range s {
+		if !(ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,104 @@
+package schema
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const employmentTypeConfig = `{
+	"options": ["FULL_TIME", "PART_TIME"],
+	"translations": {
+		"de": {"title": "Beschäftigungsart", "options": {"FULL_TIME": "Vollzeit", "PART_TIME": "Teilzeit"}},
+		"de-CH": {"title": "Anstellungsart"},
+		"pt_br": {"options": {"FULL_TIME": "Tempo integral"}}
+	}
+}`
+
+func TestNormalizeLocale(t *testing.T) {
+	for in, want := range map[string]string{
+		"":                      "",
+		"de":                    "de",
+		"DE":                    "de",
+		"pt_br":                 "pt-BR",
+		" fr-ca ":               "fr-CA",
+		"de-CH,de;q=0.9,en;q=0": "de-CH",
+		"en;q=0.8":              "en",
+	} {
+		if got := NormalizeLocale(in); got != want {
+			t.Errorf("NormalizeLocale(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestLocalizedField(t *testing.T) {
+	fd := &FieldDef{Title: "Employment type", Type: FieldChoice, TypeConfig: json.RawMessage(employmentTypeConfig)}
+
+	tests := []struct {
+		locale string
+		title  string
+		labels []string
+	}{
+		{"", "Employment type", []string{"FULL_TIME", "PART_TIME"}},
+		{"de", "Beschäftigungsart", []string{"Vollzeit", "Teilzeit"}},
+		{"de-AT", "Beschäftigungsart", []string{"Vollzeit", "Teilzeit"}},
+		// An exact match wins over the base language, even when it leaves
+		// the options (or the title, for pt-BR) untranslated.
+		{"de-CH", "Anstellungsart", []string{"FULL_TIME", "PART_TIME"}},
+		{"pt-BR", "Employment type", []string{"Tempo integral", "PART_TIME"}},
+		{"PT_br", "Employment type", []string{"Tempo integral", "PART_TIME"}},
+		{"pt", "Employment type", []string{"FULL_TIME", "PART_TIME"}},
+		{"fr", "Employment type", []string{"FULL_TIME", "PART_TIME"}},
+	}
+	for _, tt := range tests {
+		if got := fd.LocalizedTitle(tt.locale); got != tt.title {
+			t.Errorf("LocalizedTitle(%q) = %q, want %q", tt.locale, got, tt.title)
+		}
+		opts := fd.ChoiceOptions(tt.locale)
+		labels := make([]string, len(opts))
+		for i, o := range opts {
+			labels[i] = o.Label
+			if want := []string{"FULL_TIME", "PART_TIME"}[i]; o.Value != want {
+				t.Errorf("ChoiceOptions(%q)[%d].Value = %q, want %q", tt.locale, i, o.Value, want)
+			}
+		}
+		if !slices.Equal(labels, tt.labels) {
+			t.Errorf("ChoiceOptions(%q) labels = %v, want %v", tt.locale, labels, tt.labels)
+		}
+	}
+
+	text := &FieldDef{Title: "Nickname", Type: FieldText, TypeConfig: json.RawMessage(`{"translations": {"de": {"title": "Spitzname"}}}`)}
+	if got := text.LocalizedTitle("de-DE"); got != "Spitzname" {
+		t.Errorf("text LocalizedTitle(de-DE) = %q", got)
+	}
+	if opts := text.ChoiceOptions("de"); opts != nil {
+		t.Errorf("text field has options %v", opts)
+	}
+}
+
+func TestValidateTranslations(t *testing.T) {
+	tests := []struct {
+		fieldType FieldType
+		config    string
+		want      string // error substring; "" when valid
+	}{
+		{FieldChoice, "", ""},
+		{FieldChoice, employmentTypeConfig, ""},
+		{FieldText, `{"translations": {"de": {"title": "Spitzname"}}}`, ""},
+		{FieldChoice, `{"translations": {"german": {}}}`, `invalid locale "german"`},
+		{FieldChoice, `{"translations": {"de-C": {}}}`, `invalid locale "de-C"`},
+		{FieldText, `{"translations": {"de": {"options": {"A": "a"}}}}`, `option labels for locale "de" on a TEXT field`},
+		{FieldChoice, `{"options": ["A"], "translations": {"de": {"options": {"B": "b"}}}}`, `labels unknown option "B"`},
+		{FieldChoice, `{"translations": []}`, "type_config:"},
+	}
+	for _, tt := range tests {
+		err := ValidateTranslations(tt.fieldType, tt.config)
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("%s %s: unexpected error %v", tt.fieldType, tt.config, err)
+		case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+			t.Errorf("%s %s: error %v, want it to contain %q", tt.fieldType, tt.config, err, tt.want)
+		}
+	}
+}
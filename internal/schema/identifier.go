@@ -0,0 +1,186 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultMaxIdentifierLen matches PostgreSQL's identifier limit (NAMEDATALEN - 1).
+const DefaultMaxIdentifierLen = 63
+
+// systemFieldNames are emitted on every record and cannot be redefined.
+var systemFieldNames = []string{"id", "created_at", "updated_at", "version"}
+
+// IdentifierPolicy validates api_names for objects and fields: a safe
+// snake_case grammar, a reserved word list and uniqueness.
+type IdentifierPolicy struct {
+	maxLen   int
+	reserved map[string]bool
+}
+
+// NewIdentifierPolicy returns a policy allowing names up to maxLen characters
+// (DefaultMaxIdentifierLen when <= 0) and rejecting the system field names plus
+// reserved (compared case-insensitively).
+func NewIdentifierPolicy(maxLen int, reserved ...string) *IdentifierPolicy {
+	if maxLen <= 0 {
+		maxLen = DefaultMaxIdentifierLen
+	}
+	p := &IdentifierPolicy{maxLen: maxLen, reserved: make(map[string]bool)}
+	for _, w := range systemFieldNames {
+		p.reserved[w] = true
+	}
+	for _, w := range reserved {
+		if w = strings.ToLower(strings.TrimSpace(w)); w != "" {
+			p.reserved[w] = true
+		}
+	}
+	return p
+}
+
+// ValidateObjectName checks a new object api_name against the policy and the
+// objects already in cache, including their former names.
+func (p *IdentifierPolicy) ValidateObjectName(cache *Cache, name string) error {
+	return p.ValidateObjectRename(cache, nil, name)
+}
+
+// ValidateObjectRename checks name as the new api_name of obj. It is
+// ValidateObjectName except that obj may take back one of its own aliases.
+func (p *IdentifierPolicy) ValidateObjectRename(cache *Cache, obj *ObjectDef, name string) error {
+	taken := make(map[string]bool)
+	for _, o := range cache.Objects() {
+		taken[o.APIName] = true
+		if obj == nil || o.ID != obj.ID {
+			for _, a := range o.Aliases {
+				taken[a.Name] = true
+			}
+		}
+	}
+	return p.validate("object", name, taken)
+}
+
+// ValidateFieldName checks a new field api_name against the policy and the
+// fields already defined on obj, including their former names.
+func (p *IdentifierPolicy) ValidateFieldName(obj *ObjectDef, name string) error {
+	return p.ValidateFieldRename(obj, nil, name)
+}
+
+// ValidateFieldRename checks name as the new api_name of fd on obj. It is
+// ValidateFieldName except that fd may take back one of its own aliases.
+func (p *IdentifierPolicy) ValidateFieldRename(obj *ObjectDef, fd *FieldDef, name string) error {
+	taken := make(map[string]bool)
+	if obj != nil {
+		for _, f := range obj.Fields {
+			taken[f.APIName] = true
+			if fd == nil || f.ID != fd.ID {
+				for _, a := range f.Aliases {
+					taken[a.Name] = true
+				}
+			}
+		}
+	}
+	return p.validate("field", name, taken)
+}
+
+func (p *IdentifierPolicy) validate(kind, name string, taken map[string]bool) error {
+	if reason := p.grammarError(name); reason != "" {
+		return p.errorf(kind, name, reason, p.normalize(name), taken)
+	}
+	if p.reserved[name] {
+		return p.errorf(kind, name, "is a reserved word", name+"_"+kind, taken)
+	}
+	if taken[name] {
+		return p.errorf(kind, name, "already exists", name, taken)
+	}
+	return nil
+}
+
+// customSuffix marks user-defined objects and fields (e.g. "salary__c").
+const customSuffix = "__c"
+
+// grammarError describes why name is not a valid identifier, or returns "".
+// Valid names are lowercase snake_case starting with a letter, optionally
+// ending in "__c"; "__" is otherwise reserved for generated aliases (e.g.
+// nested expands).
+func (p *IdentifierPolicy) grammarError(name string) string {
+	if len(name) > p.maxLen {
+		return fmt.Sprintf("must be at most %d characters", p.maxLen)
+	}
+	name = strings.TrimSuffix(name, customSuffix)
+	switch {
+	case name == "":
+		return "must not be empty"
+	case name[0] < 'a' || name[0] > 'z':
+		return "must start with a lowercase letter"
+	case strings.Contains(name, "__"):
+		return `must not contain "__" except in a trailing "__c"`
+	case strings.HasSuffix(name, "_"):
+		return `must not end with "_"`
+	}
+	for _, ch := range name {
+		if !(ch >= 'a' && ch <= 'z' || ch >= '0' && ch <= '9' || ch == '_') {
+			return "may only contain lowercase letters, digits and underscores"
+		}
+	}
+	return ""
+}
+
+// normalize maps name onto the identifier grammar: lowercase, runs of other
+// characters collapsed to "_", and a letter prefix when needed.
+func (p *IdentifierPolicy) normalize(name string) string {
+	suffix := ""
+	if before, ok := strings.CutSuffix(name, customSuffix); ok {
+		name, suffix = before, customSuffix
+	}
+	var b strings.Builder
+	underscore := false
+	for _, ch := range strings.ToLower(name) {
+		if ch >= 'a' && ch <= 'z' || ch >= '0' && ch <= '9' {
+			b.WriteRune(ch)
+			underscore = false
+		} else if !underscore && b.Len() > 0 {
+			b.WriteByte('_')
+			underscore = true
+		}
+	}
+	s := strings.TrimSuffix(b.String(), "_")
+	if s == "" || s[0] < 'a' || s[0] > 'z' {
+		s = strings.TrimSuffix("f_"+s, "_")
+	}
+	if len(s)+len(suffix) > p.maxLen && p.maxLen > len(suffix) {
+		s = strings.TrimSuffix(s[:p.maxLen-len(suffix)], "_")
+	}
+	return s + suffix
+}
+
+// suggest returns a valid, unused name derived from base, or "" if none fits.
+func (p *IdentifierPolicy) suggest(base string, taken map[string]bool) string {
+	ok := func(s string) bool { return p.grammarError(s) == "" && !p.reserved[s] && !taken[s] }
+	if ok(base) {
+		return base
+	}
+	custom := ""
+	if before, ok0 := strings.CutSuffix(base, customSuffix); ok0 {
+		base, custom = before, customSuffix
+	}
+	for i := 2; i < 100; i++ {
+		suffix := fmt.Sprintf("_%d", i) + custom
+		stem := base
+		if len(stem)+len(suffix) > p.maxLen {
+			if p.maxLen-len(suffix) < 1 {
+				break
+			}
+			stem = strings.TrimSuffix(stem[:p.maxLen-len(suffix)], "_")
+		}
+		if s := stem + suffix; ok(s) {
+			return s
+		}
+	}
+	return ""
+}
+
+func (p *IdentifierPolicy) errorf(kind, name, reason, base string, taken map[string]bool) error {
+	if s := p.suggest(base, taken); s != "" {
+		return fmt.Errorf("%s api_name %q %s; try %q", kind, name, reason, s)
+	}
+	return fmt.Errorf("%s api_name %q %s", kind, name, reason)
+}
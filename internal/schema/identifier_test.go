@@ -0,0 +1,71 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIdentifierPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		maxLen int
+		taken  []string
+		want   string // error substring; "" for a valid name
+	}{
+		{name: "salary"},
+		{name: "salary__c"},
+		{name: "x__c"},
+		{name: "base_pay2"},
+		{name: "a__b", want: `must not contain "__" except in a trailing "__c"; try "a_b"`},
+		{name: "a__b__c", want: `except in a trailing "__c"; try "a_b__c"`},
+		{name: "__c", want: "must not be empty"},
+		{name: "pay_", want: `must not end with "_"; try "pay"`},
+		{name: "2fa", want: `must start with a lowercase letter; try "f_2fa"`},
+		{name: "_x", want: `must start with a lowercase letter; try "x"`},
+		{name: "Base Pay", want: `must start with a lowercase letter; try "base_pay"`},
+		{name: "base-pay__c", want: `may only contain lowercase letters, digits and underscores; try "base_pay__c"`},
+		{name: "select", want: `is a reserved word; try "select_field"`},
+		{name: "version", want: `is a reserved word; try "version_field"`},
+		{name: "select", taken: []string{"select_field"}, want: `try "select_field_2"`},
+		{name: "salary__c", taken: []string{"salary__c"}, want: `already exists; try "salary_2__c"`},
+		{name: "salary__c", taken: []string{"salary__c", "salary_2__c"}, want: `try "salary_3__c"`},
+		// The limit counts the suffix, and suggestions are cut to fit.
+		{name: "abcdefg__c", maxLen: 10},
+		{name: "abcdefgh__c", maxLen: 10, want: `must be at most 10 characters; try "abcdefg__c"`},
+		{name: "abcdefg__c", maxLen: 10, taken: []string{"abcdefg__c"}, want: `try "abcde_2__c"`},
+		{name: "abcdefghijk", maxLen: 10, want: `try "abcdefghij"`},
+	}
+	for _, tt := range tests {
+		p := NewIdentifierPolicy(tt.maxLen, "select")
+		obj := &ObjectDef{}
+		for _, name := range tt.taken {
+			obj.Fields = append(obj.Fields, FieldDef{APIName: name})
+		}
+		err := p.ValidateFieldName(obj, tt.name)
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("%q (max %d): unexpected error %v", tt.name, tt.maxLen, err)
+		case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+			t.Errorf("%q (max %d): error %v, want it to contain %s", tt.name, tt.maxLen, err, tt.want)
+		}
+	}
+}
+
+func TestIdentifierPolicyObjectSuggestions(t *testing.T) {
+	p := NewIdentifierPolicy(0, "select")
+	cache := NewCacheFromObjects(&ObjectDef{APIName: "projects__c", Aliases: []ObjectAlias{{Name: "jobs__c"}}})
+
+	for name, want := range map[string]string{
+		"select":      `object api_name "select" is a reserved word; try "select_object"`,
+		"projects__c": `already exists; try "projects_2__c"`,
+		"jobs__c":     `already exists; try "jobs_2__c"`,
+	} {
+		if err := p.ValidateObjectName(cache, name); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: error %v, want it to contain %s", name, err, want)
+		}
+	}
+	// An object may take back its own former name.
+	if err := p.ValidateObjectRename(cache, cache.Get("projects__c"), "jobs__c"); err != nil {
+		t.Errorf("rename to own alias: %v", err)
+	}
+}
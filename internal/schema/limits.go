@@ -0,0 +1,93 @@
+package schema
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// DataLimits are soft limits on an object's custom data: how many custom
+// fields it may have and how large a record's document (data on
+// metadata.records, custom_fields on standard tables) may grow, in bytes of
+// JSON text. Zero disables a limit.
+type DataLimits struct {
+	MaxCustomFields int
+	MaxDataBytes    int
+}
+
+// Built-in list limits, used where a server configures none.
+const (
+	DefaultPageSize            = 50
+	MaxPageSize                = 200
+	DefaultExactCountThreshold = 50_000
+)
+
+// ListLimits size list pages and counts: the page a request without a limit
+// gets, the largest page a request may ask for, and the planner estimate of
+// a list's rows at or below which its total count is exact. Zero uses the
+// built-in value.
+type ListLimits struct {
+	DefaultPageSize     int
+	MaxPageSize         int
+	ExactCountThreshold int64
+}
+
+// Validate rejects negative limits and a default page above the maximum.
+func (l ListLimits) Validate() error {
+	if l.DefaultPageSize < 0 || l.MaxPageSize < 0 || l.ExactCountThreshold < 0 {
+		return fmt.Errorf("list limits must not be negative")
+	}
+	def, maxSize := cmp.Or(l.DefaultPageSize, DefaultPageSize), cmp.Or(l.MaxPageSize, MaxPageSize)
+	if def > maxSize {
+		return fmt.Errorf("default page size %d exceeds max page size %d", def, maxSize)
+	}
+	return nil
+}
+
+// For returns the limits applying to obj (nil for the server's own): its
+// overrides, else l, else the built-in values. An object's page sizes never
+// exceed the server's maximum.
+func (l ListLimits) For(obj *ObjectDef) ListLimits {
+	out := ListLimits{
+		DefaultPageSize:     cmp.Or(l.DefaultPageSize, DefaultPageSize),
+		MaxPageSize:         cmp.Or(l.MaxPageSize, MaxPageSize),
+		ExactCountThreshold: cmp.Or(l.ExactCountThreshold, DefaultExactCountThreshold),
+	}
+	if obj != nil {
+		if obj.MaxPageSize > 0 {
+			out.MaxPageSize = min(obj.MaxPageSize, out.MaxPageSize)
+		}
+		out.DefaultPageSize = cmp.Or(obj.DefaultPageSize, out.DefaultPageSize)
+		out.ExactCountThreshold = cmp.Or(obj.ExactCountThreshold, out.ExactCountThreshold)
+	}
+	out.DefaultPageSize = min(out.DefaultPageSize, out.MaxPageSize)
+	return out
+}
+
+// LimitWarnRatio is the share of a limit from which usage is reported as
+// approaching it.
+const LimitWarnRatio = 0.8
+
+// For returns the limits applying to obj: its own overrides, else l.
+func (l DataLimits) For(obj *ObjectDef) DataLimits {
+	return DataLimits{
+		MaxCustomFields: cmp.Or(obj.MaxCustomFields, l.MaxCustomFields),
+		MaxDataBytes:    cmp.Or(obj.MaxDataBytes, l.MaxDataBytes),
+	}
+}
+
+// NearLimit reports whether used has reached LimitWarnRatio of limit. It is
+// always false for a disabled (zero) limit.
+func NearLimit(used, limit int64) bool {
+	return limit > 0 && float64(used) >= LimitWarnRatio*float64(limit)
+}
+
+// CustomFieldCount returns the number of obj's custom (non-standard) fields.
+func (o *ObjectDef) CustomFieldCount() int {
+	n := 0
+	for i := range o.Fields {
+		if !o.Fields[i].IsStandard {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,258 @@
+package schema
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// LintFinding is a quality problem in the catalog (see Lint).
+type LintFinding struct {
+	Severity string // LintError, LintWarning or LintInfo
+	Code     string // LintLookupCycle, ...
+	Object   string
+	Field    string // "" for findings about the object itself
+	Message  string
+	// Related lists the other objects or fields (object.field) involved.
+	Related []string
+}
+
+// Lint severities.
+const (
+	LintError   = "error" // the schema cannot work as defined
+	LintWarning = "warning"
+	LintInfo    = "info"
+)
+
+// Lint codes.
+const (
+	LintLookupCycle   = "lookup_cycle"
+	LintMissingTitle  = "missing_title"
+	LintNameOverlap   = "name_overlap"
+	LintUnusedField   = "unused_field"
+	LintUnindexedSort = "unindexed_sort"
+)
+
+// Lint checks the definitions of objs for problems their records are not
+// needed to find: lookup cycles, missing titles and overlapping api_names.
+func Lint(objs []*ObjectDef) []LintFinding {
+	var out []LintFinding
+	out = append(out, lintCycles(objs)...)
+
+	names := make([]string, 0, len(objs))
+	for _, obj := range objs {
+		names = append(names, obj.APIName)
+		if strings.TrimSpace(obj.Title) == "" {
+			out = append(out, LintFinding{Severity: LintWarning, Code: LintMissingTitle, Object: obj.APIName, Message: "object has no title"})
+		}
+		fields := slices.Clone(systemFieldNames)
+		for i := range obj.Fields {
+			fd := &obj.Fields[i]
+			fields = append(fields, fd.APIName)
+			if strings.TrimSpace(fd.Title) == "" {
+				out = append(out, LintFinding{Severity: LintWarning, Code: LintMissingTitle, Object: obj.APIName, Field: fd.APIName, Message: "field has no title"})
+			}
+		}
+		for _, group := range overlaps(fields) {
+			out = append(out, LintFinding{
+				Severity: LintWarning, Code: LintNameOverlap, Object: obj.APIName, Field: group[0],
+				Message: fmt.Sprintf("field api_name %q overlaps %s once case, underscores and the __c suffix are ignored", group[0], quoteAll(group[1:])),
+				Related: qualify(obj.APIName, group[1:]),
+			})
+		}
+	}
+	for _, group := range overlaps(names) {
+		out = append(out, LintFinding{
+			Severity: LintWarning, Code: LintNameOverlap, Object: group[0],
+			Message: fmt.Sprintf("object api_name %q overlaps %s once case, underscores and the __c suffix are ignored", group[0], quoteAll(group[1:])),
+			Related: group[1:],
+		})
+	}
+	return out
+}
+
+// overlapKey is the form two api_names overlap in when they differ only in
+// case, underscores or the custom suffix: first_name, firstname__c.
+func overlapKey(name string) string {
+	name = strings.TrimSuffix(strings.ToLower(name), customSuffix)
+	return strings.ReplaceAll(name, "_", "")
+}
+
+// overlaps groups names by overlapKey, returning the groups of two or more,
+// each sorted. A system field name is never reported first.
+func overlaps(names []string) [][]string {
+	byKey := make(map[string][]string)
+	for _, n := range names {
+		byKey[overlapKey(n)] = append(byKey[overlapKey(n)], n)
+	}
+	var out [][]string
+	for _, group := range byKey {
+		if len(group) < 2 {
+			continue
+		}
+		slices.Sort(group)
+		group = slices.Compact(group)
+		if len(group) < 2 {
+			continue
+		}
+		// Report the defined field, not the system one it shadows.
+		slices.SortStableFunc(group, func(a, b string) int {
+			return boolRank(slices.Contains(systemFieldNames, a)) - boolRank(slices.Contains(systemFieldNames, b))
+		})
+		out = append(out, group)
+	}
+	slices.SortFunc(out, func(a, b []string) int { return strings.Compare(a[0], b[0]) })
+	return out
+}
+
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// lintCycles reports the strongly connected components of the lookup graph.
+// A cycle of required lookups is an error: none of its records can be
+// created first. Other cycles are reported for information.
+func lintCycles(objs []*ObjectDef) []LintFinding {
+	byID := make(map[uuid.UUID]*ObjectDef, len(objs))
+	for _, obj := range objs {
+		byID[obj.ID] = obj
+	}
+	edges := func(requiredOnly bool) map[*ObjectDef][]*FieldDef {
+		out := make(map[*ObjectDef][]*FieldDef)
+		for _, obj := range objs {
+			for i := range obj.Fields {
+				fd := &obj.Fields[i]
+				if fd.Type != FieldLookup || fd.LookupObjectID == nil || byID[*fd.LookupObjectID] == nil {
+					continue
+				}
+				if requiredOnly && !fd.IsRequired {
+					continue
+				}
+				out[obj] = append(out[obj], fd)
+			}
+		}
+		return out
+	}
+
+	var out []LintFinding
+	required := stronglyConnected(objs, edges(true), byID)
+	blocked := make(map[string]bool)
+	for _, comp := range required {
+		blocked[componentKey(comp)] = true
+		out = append(out, cycleFinding(LintError, comp, edges(true),
+			"required lookups form a cycle through %s: no record can be created before the others exist"))
+	}
+	for _, comp := range stronglyConnected(objs, edges(false), byID) {
+		if blocked[componentKey(comp)] || len(comp) == 1 {
+			continue
+		}
+		out = append(out, cycleFinding(LintInfo, comp, edges(false),
+			"lookups form a cycle through %s: deletes and nested expands can come back to the same object"))
+	}
+	return out
+}
+
+// stronglyConnected returns the strongly connected components of the lookup
+// graph (Tarjan) that contain a cycle: two or more objects, or one object
+// with a lookup to itself. Each is sorted by api_name.
+func stronglyConnected(objs []*ObjectDef, edges map[*ObjectDef][]*FieldDef, byID map[uuid.UUID]*ObjectDef) [][]*ObjectDef {
+	index := make(map[*ObjectDef]int)
+	low := make(map[*ObjectDef]int)
+	onStack := make(map[*ObjectDef]bool)
+	var stack []*ObjectDef
+	var out [][]*ObjectDef
+
+	var visit func(v *ObjectDef)
+	visit = func(v *ObjectDef) {
+		index[v] = len(index)
+		low[v] = index[v]
+		stack = append(stack, v)
+		onStack[v] = true
+		selfLoop := false
+		for _, fd := range edges[v] {
+			w := byID[*fd.LookupObjectID]
+			if w == v {
+				selfLoop = true
+			}
+			if _, seen := index[w]; !seen {
+				visit(w)
+				low[v] = min(low[v], low[w])
+			} else if onStack[w] {
+				low[v] = min(low[v], index[w])
+			}
+		}
+		if low[v] != index[v] {
+			return
+		}
+		var comp []*ObjectDef
+		for {
+			w := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[w] = false
+			comp = append(comp, w)
+			if w == v {
+				break
+			}
+		}
+		if len(comp) > 1 || selfLoop {
+			slices.SortFunc(comp, func(a, b *ObjectDef) int { return strings.Compare(a.APIName, b.APIName) })
+			out = append(out, comp)
+		}
+	}
+	for _, obj := range objs {
+		if _, seen := index[obj]; !seen {
+			visit(obj)
+		}
+	}
+	return out
+}
+
+func componentKey(comp []*ObjectDef) string {
+	names := make([]string, len(comp))
+	for i, obj := range comp {
+		names[i] = obj.APIName
+	}
+	return strings.Join(names, ",")
+}
+
+// cycleFinding describes a component of the lookup graph, relating the
+// lookups between its objects.
+func cycleFinding(severity string, comp []*ObjectDef, edges map[*ObjectDef][]*FieldDef, format string) LintFinding {
+	in := make(map[uuid.UUID]bool, len(comp))
+	names := make([]string, len(comp))
+	for i, obj := range comp {
+		in[obj.ID] = true
+		names[i] = obj.APIName
+	}
+	f := LintFinding{Severity: severity, Code: LintLookupCycle, Object: comp[0].APIName, Message: fmt.Sprintf(format, strings.Join(names, ", "))}
+	for _, obj := range comp {
+		for _, fd := range edges[obj] {
+			if in[*fd.LookupObjectID] {
+				f.Related = append(f.Related, obj.APIName+"."+fd.APIName)
+			}
+		}
+	}
+	slices.Sort(f.Related)
+	return f
+}
+
+func quoteAll(names []string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = fmt.Sprintf("%q", n)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func qualify(obj string, names []string) []string {
+	out := make([]string, len(names))
+	for i, n := range names {
+		out[i] = obj + "." + n
+	}
+	return out
+}
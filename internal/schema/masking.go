@@ -0,0 +1,112 @@
+package schema
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// MaskTransform de-identifies a field value for callers who may only see part
+// of it.
+type MaskTransform string
+
+const (
+	MaskNone        MaskTransform = "none"         // the value as stored
+	MaskHide        MaskTransform = "hide"         // null
+	MaskLast4       MaskTransform = "last4"        // "***1234"
+	MaskEmailDomain MaskTransform = "email_domain" // "example.com"
+	MaskYear        MaskTransform = "year"         // "1990"
+)
+
+// MaskingPolicy selects the transform applied to a field's value in record
+// reads: the first rule whose permission the caller holds, else Default.
+type MaskingPolicy struct {
+	Default MaskTransform `json:"default"`
+	Rules   []MaskRule    `json:"rules,omitempty"`
+}
+
+// MaskRule applies Transform to callers holding Permission.
+type MaskRule struct {
+	Permission string        `json:"permission"`
+	Transform  MaskTransform `json:"transform"`
+}
+
+// Transform returns the transform for a caller holding the permissions has
+// reports.
+func (p *MaskingPolicy) Transform(has func(perm string) bool) MaskTransform {
+	for _, r := range p.Rules {
+		if has(r.Permission) {
+			return r.Transform
+		}
+	}
+	return p.Default
+}
+
+// maskTypes lists the field types each partial transform reads; none and
+// hide apply to any field. ENCRYPTED fields hold any JSON value and are
+// masked after decryption.
+var maskTypes = map[MaskTransform][]FieldType{
+	MaskLast4:       {FieldText, FieldPhone, FieldEncrypted},
+	MaskEmailDomain: {FieldEmail, FieldEncrypted},
+	MaskYear:        {FieldDate, FieldDatetime, FieldEncrypted},
+}
+
+// Validate checks that every transform of p is known and applies to fields of
+// type t.
+func (p *MaskingPolicy) Validate(t FieldType) error {
+	check := func(what string, m MaskTransform) error {
+		if m == MaskNone || m == MaskHide {
+			return nil
+		}
+		types, ok := maskTypes[m]
+		if !ok {
+			return fmt.Errorf("masking %s: unknown transform %q", what, m)
+		}
+		if !slices.Contains(types, t) {
+			return fmt.Errorf("masking %s: %s does not apply to %s fields", what, m, t)
+		}
+		return nil
+	}
+	if err := check("default", p.Default); err != nil {
+		return err
+	}
+	for i, r := range p.Rules {
+		if r.Permission == "" {
+			return fmt.Errorf("masking rule %d: permission is required", i)
+		}
+		if err := check(fmt.Sprintf("rule %q", r.Permission), r.Transform); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Apply returns v, a JSON value, as m presents it. Partial transforms of a
+// value they cannot read (not a string, no "@", no leading year) give null,
+// as does a value of four characters or fewer under last4.
+func (m MaskTransform) Apply(v any) any {
+	if m == MaskNone || v == nil {
+		return v
+	}
+	s, ok := v.(string)
+	if !ok {
+		return nil
+	}
+	switch m {
+	case MaskLast4:
+		r := []rune(s)
+		if len(r) <= 4 {
+			return nil
+		}
+		return "***" + string(r[len(r)-4:])
+	case MaskEmailDomain:
+		if i := strings.LastIndexByte(s, '@'); i >= 0 && i < len(s)-1 {
+			return s[i+1:]
+		}
+	case MaskYear:
+		if len(s) >= 4 && strings.Trim(s[:4], "0123456789") == "" {
+			return s[:4]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package schema
+
+import "slices"
+
+// systemFieldTypes are the types of the system columns every record carries,
+// on standard tables and metadata.records alike.
+var systemFieldTypes = map[string]FieldType{
+	"id":         FieldText,
+	"created_at": FieldDatetime,
+	"updated_at": FieldDatetime,
+	"version":    FieldNumber,
+}
+
+// IsSystemField reports whether apiName is a system field (id, created_at,
+// updated_at, version).
+func IsSystemField(apiName string) bool {
+	_, ok := systemFieldTypes[apiName]
+	return ok
+}
+
+// SystemFieldNames returns the system field names in their canonical order.
+func SystemFieldNames() []string {
+	return slices.Clone(systemFieldNames)
+}
+
+// AddSystemFields makes the system fields resolvable through FieldsByAPIName,
+// so filters, sorting and HRQL where() accept them on every object. The cache
+// calls it on load; call it on definitions built outside the cache. Standard
+// objects already register id/created_at/updated_at in the catalog; the
+// synthetic definitions fill the rest. They are not added to Fields, which
+// stays the catalog's view for metadata reads and writes.
+func (o *ObjectDef) AddSystemFields() {
+	if o.FieldsByAPIName == nil {
+		o.FieldsByAPIName = make(map[string]*FieldDef)
+	}
+	for _, name := range systemFieldNames {
+		if _, ok := o.FieldsByAPIName[name]; ok {
+			continue
+		}
+		o.FieldsByAPIName[name] = &FieldDef{
+			ObjectID:      o.ID,
+			APIName:       name,
+			Title:         name,
+			Type:          systemFieldTypes[name],
+			IsRequired:    true,
+			IsStandard:    true,
+			StorageColumn: new(name),
+		}
+	}
+}
@@ -2,7 +2,9 @@ package schema
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -27,22 +29,68 @@ const (
 	FieldEmail       FieldType = "EMAIL"
 	FieldURL         FieldType = "URL"
 	FieldPhone       FieldType = "PHONE"
+	FieldEncrypted   FieldType = "ENCRYPTED"
 	FieldLookup      FieldType = "LOOKUP"
 	FieldFormula     FieldType = "FORMULA"
+	// FieldJSON holds any JSON value, stored and returned as written. It has
+	// no order and is only tested for null.
+	FieldJSON FieldType = "JSON"
 )
 
 type FieldDef struct {
-	ID             uuid.UUID
-	ObjectID       uuid.UUID
-	APIName        string
-	Title          string
-	Type           FieldType
-	TypeConfig     json.RawMessage
-	IsRequired     bool
-	IsUnique       bool
+	ID           uuid.UUID
+	ObjectID     uuid.UUID
+	APIName      string
+	Title        string
+	Type         FieldType
+	TypeConfig   json.RawMessage
+	IsRequired   bool
+	IsUnique     bool
+	IsExternalID bool
+	// IsSearchable marks a field searched with HRQL string operations, which
+	// gets a trigram index when the server creates search indexes.
+	IsSearchable bool
+	// AccentInsensitive makes contains, starts_with and ends_with on the
+	// field ignore accents, as contains_fold does, and builds its search
+	// index on the unaccented value.
+	AccentInsensitive bool
+	// NotFilterable and NotSortable clear a field's is_filterable and
+	// is_sortable flags: filters, HRQL where conditions and orders on it are
+	// rejected with a FieldAccessError. Inverted so the zero value (system
+	// fields, hand-built definitions) leaves the field unrestricted.
+	NotFilterable  bool
+	NotSortable    bool
 	IsStandard     bool
 	StorageColumn  *string
 	LookupObjectID *uuid.UUID
+	// DocColumn is the JSONB column holding the value when StorageColumn is
+	// nil: custom_fields on standard tables, data on metadata.records. Set by
+	// the cache from the owning object.
+	DocColumn string
+	// PathColumn is the ltree column materializing the hierarchy along a
+	// self-referencing LOOKUP (manager_path for employees.manager), which
+	// HRQL org functions traverse with via: .field. Empty for other fields.
+	PathColumn string
+	// Masking, when set, de-identifies the field's value in record reads for
+	// callers without full access (see MaskingPolicy).
+	Masking *MaskingPolicy
+	// IsPII marks personal data, which anonymization replaces (see
+	// CanAnonymize).
+	IsPII bool
+	// Aliases are the field's former api_names, oldest first. They resolve
+	// through FieldsByAPIName to the field, whose APIName is the current one.
+	Aliases []FieldAlias
+
+	// Catalog attributes, carried so metadata reads can be served from the cache.
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// FieldAlias is a former api_name of a renamed field.
+type FieldAlias struct {
+	Name      string
+	CreatedAt time.Time // when the field was renamed away from Name
 }
 
 // IsNumeric returns true if the field type requires numeric casting in queries.
@@ -50,6 +98,98 @@ func (f *FieldDef) IsNumeric() bool {
 	return f.Type == FieldNumber || f.Type == FieldCurrency || f.Type == FieldPercentage
 }
 
+// DocumentColumn returns the JSONB column holding the object's document
+// values: custom_fields on standard tables, data on metadata.records.
+func (o *ObjectDef) DocumentColumn() string {
+	if o.IsStandard {
+		return "custom_fields"
+	}
+	return "data"
+}
+
+// indexFields points FieldsByAPIName at the loaded Fields, under their
+// api_names and their aliases.
+func (o *ObjectDef) indexFields() {
+	for i := range o.Fields {
+		fd := &o.Fields[i]
+		o.FieldsByAPIName[fd.APIName] = fd
+		for _, a := range fd.Aliases {
+			if _, ok := o.FieldsByAPIName[a.Name]; !ok {
+				o.FieldsByAPIName[a.Name] = fd
+			}
+		}
+	}
+}
+
+// bindDocColumns sets DocColumn on fields stored in the object's document.
+func (o *ObjectDef) bindDocColumns() {
+	for i := range o.Fields {
+		if o.Fields[i].StorageColumn == nil {
+			o.Fields[i].DocColumn = o.DocumentColumn()
+		}
+	}
+}
+
+// CanSearch reports whether the field can be flagged IsSearchable: it holds
+// text that string operations match.
+func (f *FieldDef) CanSearch() bool {
+	switch f.Type {
+	case FieldText, FieldEmail, FieldURL, FieldPhone, FieldChoice:
+		return true
+	}
+	return false
+}
+
+// CanAnonymize reports whether the field can be flagged IsPII: a fake
+// value of its type can be made (migration 000036).
+func (f *FieldDef) CanAnonymize() bool {
+	switch f.Type {
+	case FieldText, FieldEmail, FieldURL, FieldPhone, FieldDate:
+		return true
+	}
+	return false
+}
+
+// IsEncrypted returns true if the field stores application-encrypted ciphertext,
+// which the database cannot compare, so it is never filterable or sortable.
+func (f *FieldDef) IsEncrypted() bool {
+	return f.Type == FieldEncrypted
+}
+
+// IsJSON returns true if the field holds an arbitrary JSON value, which is
+// neither unique nor sortable and only filtered by null.
+func (f *FieldDef) IsJSON() bool {
+	return f.Type == FieldJSON
+}
+
+// FieldAccessError reports a filter or sort on a field whose is_filterable or
+// is_sortable flag is off. The field exists, so callers report it as a
+// permission error rather than an unknown field.
+type FieldAccessError struct {
+	Field string
+	Use   string // "filtered" or "sorted"
+}
+
+func (e *FieldAccessError) Error() string {
+	return fmt.Sprintf("field %q cannot be %s", e.Field, e.Use)
+}
+
+// CheckFilterable returns a FieldAccessError if the field is not filterable.
+func (f *FieldDef) CheckFilterable() error {
+	if f.NotFilterable {
+		return &FieldAccessError{Field: f.APIName, Use: "filtered"}
+	}
+	return nil
+}
+
+// CheckSortable returns a FieldAccessError if the field is not sortable.
+func (f *FieldDef) CheckSortable() error {
+	if f.NotSortable {
+		return &FieldAccessError{Field: f.APIName, Use: "sorted"}
+	}
+	return nil
+}
+
 type ObjectDef struct {
 	ID                   uuid.UUID
 	APIName              string
@@ -61,12 +201,144 @@ type ObjectDef struct {
 	SupportsCustomFields bool
 	Fields               []FieldDef
 	FieldsByAPIName      map[string]*FieldDef
+
+	// Catalog attributes, carried so metadata reads can be served from the cache.
+	Description string
+	CategoryID  *uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	// List defaults, applied when a request omits order or limit. DefaultOrder
+	// uses the REST order syntax ("last_name", "start_date.desc"); zero page
+	// sizes and ExactCountThreshold mean the server's ListLimits.
+	DefaultOrder        string
+	DefaultPageSize     int
+	MaxPageSize         int
+	ExactCountThreshold int64
+
+	// MaxCustomFields and MaxDataBytes override the server's DataLimits for
+	// the object; zero means the server default.
+	MaxCustomFields int
+	MaxDataBytes    int
+
+	// CacheMaxAge, when set, lets browsers and CDNs reuse REST reads of the
+	// object for that long (Cache-Control), for rarely changing reference data.
+	CacheMaxAge time.Duration
+
+	// LoadedAt is when the cache read this definition; a response rendered
+	// with it is no older than it (Last-Modified).
+	LoadedAt time.Time
+
+	// ValidationWebhook, when set, validates candidate records before writes
+	// commit (see internal/webhook).
+	ValidationWebhook *ValidationWebhook
+
+	// DisplayTemplate renders a record's display name for pickers and
+	// expands, e.g. "{first_name} {last_name}"; see ParseDisplayTemplate.
+	DisplayTemplate string
+
+	// Deprecation, when set, announces the object's removal: registry reads
+	// of it carry Deprecation/Sunset headers and a warning.
+	Deprecation *Deprecation
+
+	// Aliases are the object's former api_names, oldest first. Cache.Get
+	// still resolves them; registry reads through one carry a deprecation
+	// warning.
+	Aliases []ObjectAlias
+
+	// TableExpr, when set, replaces the table in read queries, e.g. a set-returning
+	// snapshot function for point-in-time reads. Never set on cached definitions.
+	TableExpr string
+	// SnapshotAt is the moment TableExpr's snapshot reads, zero for current
+	// reads; date conditions relative to today use it instead.
+	SnapshotAt time.Time
+}
+
+// Deprecation is the API lifecycle notice of a deprecated object.
+type Deprecation struct {
+	At          time.Time
+	SunsetAt    *time.Time // planned removal; nil when not scheduled
+	Replacement string     // API name of the object to migrate to, if any
+}
+
+// ObjectAlias is a former api_name of a renamed object.
+type ObjectAlias struct {
+	Name      string
+	CreatedAt time.Time // when the object was renamed away from Name
+}
+
+// Alias returns obj's alias called name, or nil when name is not one.
+func (o *ObjectDef) Alias(name string) *ObjectAlias {
+	for i := range o.Aliases {
+		if o.Aliases[i].Name == name {
+			return &o.Aliases[i]
+		}
+	}
+	return nil
+}
+
+// Hierarchy returns the self-referencing LOOKUP field of obj whose
+// PathColumn materializes the hierarchy along via, or nil when via is not
+// one. An empty via names the object's default hierarchy: its first such
+// field (manager on employees).
+func (o *ObjectDef) Hierarchy(via string) *FieldDef {
+	if via != "" {
+		if fd := o.FieldsByAPIName[via]; fd != nil && o.isHierarchy(fd) {
+			return fd
+		}
+		return nil
+	}
+	for i := range o.Fields {
+		if o.isHierarchy(&o.Fields[i]) {
+			return &o.Fields[i]
+		}
+	}
+	return nil
+}
+
+func (o *ObjectDef) isHierarchy(fd *FieldDef) bool {
+	return fd.PathColumn != "" && fd.Type == FieldLookup && fd.LookupObjectID != nil && *fd.LookupObjectID == o.ID
+}
+
+// ValidationWebhook is an object's external record validator.
+type ValidationWebhook struct {
+	URL      string
+	Timeout  time.Duration // 0 uses the validator's default
+	FailOpen bool          // accept writes when the webhook fails or times out
 }
 
 // TableName returns the fully qualified, quoted table name for standard objects.
 func (o *ObjectDef) TableName() string {
+	if o.TableExpr != "" {
+		return o.TableExpr
+	}
 	if o.StorageSchema != nil && o.StorageTable != nil {
 		return QuoteIdent(*o.StorageSchema) + "." + QuoteIdent(*o.StorageTable)
 	}
 	return ""
 }
+
+// ExternalIDs returns the fields flagged as external keys, usable by Upsert.
+func (o *ObjectDef) ExternalIDs() []*FieldDef {
+	var out []*FieldDef
+	for i := range o.Fields {
+		if o.Fields[i].IsExternalID {
+			out = append(out, &o.Fields[i])
+		}
+	}
+	return out
+}
+
+// LabelField returns the target field a LOOKUP field denormalizes next to its
+// reference (type_config "denormalize_label", e.g. "title"), or "" when the
+// field is not a LOOKUP or has no such setting.
+func (f *FieldDef) LabelField() string {
+	if f.Type != FieldLookup || len(f.TypeConfig) == 0 {
+		return ""
+	}
+	var cfg struct {
+		DenormalizeLabel string `json:"denormalize_label"`
+	}
+	_ = json.Unmarshal(f.TypeConfig, &cfg) // validated on write
+	return cfg.DenormalizeLabel
+}
@@ -0,0 +1,126 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ChangesChannel is the channel metadata.schema_outbox entries are announced
+// on (migration 000032).
+const ChangesChannel = "schema_changes"
+
+// outboxRetention is how long applied outbox entries are kept; the newest one
+// is always kept, as the mark other servers compare against.
+const outboxRetention = 24 * time.Hour
+
+// Watcher keeps a Cache in step with catalog writes made anywhere: the
+// mutation's transaction writes the outbox and notifies, and the watcher
+// reloads the cache on each notification. A reload that fails is retried.
+// Notifications missed while the listening connection was down are caught up
+// by reloading on reconnecting, and every poll interval by comparing the
+// newest outbox entry with the last one applied. Outbox ids are taken at
+// insert, not commit, so an entry can commit after a newer one was applied;
+// a notification therefore always reloads, whatever its id.
+type Watcher struct {
+	pool  *pgxpool.Pool
+	cache *Cache
+	poll  time.Duration
+
+	// applied is the newest outbox entry the cache reflects.
+	applied int64
+}
+
+// NewWatcher returns a watcher reloading cache from pool. poll bounds how
+// long a missed change can go unnoticed (0 relies on notifications alone).
+func NewWatcher(pool *pgxpool.Pool, cache *Cache, poll time.Duration) *Watcher {
+	return &Watcher{pool: pool, cache: cache, poll: poll}
+}
+
+// Start listens for catalog changes until ctx is done, reconnecting with
+// backoff when the connection is lost.
+func (w *Watcher) Start(ctx context.Context) {
+	go func() {
+		backoff := time.Second
+		for {
+			started := time.Now()
+			err := w.listen(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			if time.Since(started) > time.Minute {
+				backoff = time.Second
+			}
+			log.Printf("schema watcher: %v; retrying in %s", err, backoff)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(2*backoff, time.Minute)
+		}
+	}()
+}
+
+// listen holds a connection LISTENing on ChangesChannel and syncs the cache
+// after subscribing, on each notification and on each idle poll interval.
+func (w *Watcher) listen(ctx context.Context) error {
+	conn, err := w.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection: %w", err)
+	}
+	// The connection is closed rather than returned, so it cannot go back
+	// to the pool still subscribed.
+	defer func() {
+		_ = conn.Conn().Close(context.Background())
+		conn.Release()
+	}()
+	if _, err := conn.Exec(ctx, "LISTEN "+ChangesChannel); err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+
+	// Changes committed while no connection was listening went unannounced.
+	force := true
+	for {
+		if err := w.catchUp(ctx, force); err != nil {
+			return err
+		}
+		waitCtx, cancel := ctx, context.CancelFunc(func() {})
+		if w.poll > 0 {
+			waitCtx, cancel = context.WithTimeout(ctx, w.poll)
+		}
+		_, err := conn.Conn().WaitForNotification(waitCtx)
+		cancel()
+		if err != nil && (ctx.Err() != nil || !errors.Is(err, context.DeadlineExceeded)) {
+			return fmt.Errorf("wait for notification: %w", err)
+		}
+		force = err == nil
+	}
+}
+
+// catchUp reloads the cache when force is set or the outbox holds an entry
+// newer than the last one applied, then prunes entries past outboxRetention.
+// A failed reload leaves the mark alone and ends the connection, so the
+// reconnect retries it.
+func (w *Watcher) catchUp(ctx context.Context, force bool) error {
+	var newest int64
+	if err := w.pool.QueryRow(ctx, `SELECT COALESCE(max("id"), 0) FROM metadata.schema_outbox`).Scan(&newest); err != nil {
+		return fmt.Errorf("read schema outbox: %w", err)
+	}
+	if !force && newest <= w.applied {
+		return nil
+	}
+	if err := w.cache.Load(ctx, w.pool); err != nil {
+		return fmt.Errorf("reload schema cache: %w", err)
+	}
+	w.applied = newest
+	if _, err := w.pool.Exec(ctx, `DELETE FROM metadata.schema_outbox WHERE "id" < $1 AND "created_at" < now() - make_interval(secs => $2)`,
+		newest, outboxRetention.Seconds()); err != nil {
+		log.Printf("schema watcher: prune outbox: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"maps"
+	"net/http"
+	"strings"
+)
+
+// ConditionalGET answers GETs carrying If-None-Match or If-Modified-Since
+// with 304 Not Modified when the response they would get still matches, so
+// caches can revalidate the REST reads of cacheable objects cheaply. Every
+// validator sent must match: the ETag listed in If-None-Match (weak
+// comparison) and Last-Modified no later than If-Modified-Since. RFC 9110
+// lets If-None-Match decide alone, but a record's ETag is its version, which
+// a schema change leaves alone while moving Last-Modified.
+func ConditionalGET(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inm, ims := r.Header.Get("If-None-Match"), r.Header.Get("If-Modified-Since")
+		if r.Method != http.MethodGet || (inm == "" && ims == "") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &bufferedResponse{header: make(http.Header), status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		if rec.status == http.StatusOK && notModified(rec.header, inm, ims) {
+			for _, k := range []string{"Cache-Control", "ETag", "Expires", "Last-Modified", "Vary"} {
+				if v := rec.header.Values(k); len(v) > 0 {
+					w.Header()[k] = v
+				}
+			}
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		maps.Copy(w.Header(), rec.header)
+		w.WriteHeader(rec.status)
+		w.Write(rec.body.Bytes())
+	})
+}
+
+// notModified reports whether a response with headers h satisfies the
+// request's If-None-Match (inm) and If-Modified-Since (ims). A validator the
+// response cannot be compared with, or an unparseable date, is ignored;
+// a response without either validator is always modified.
+func notModified(h http.Header, inm, ims string) bool {
+	etag, lastModified := h.Get("ETag"), h.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return false
+	}
+	if inm != "" && (etag == "" || !etagListed(inm, etag)) {
+		return false
+	}
+	if ims != "" && lastModified != "" {
+		since, err := http.ParseTime(ims)
+		modified, merr := http.ParseTime(lastModified)
+		if err == nil && merr == nil && modified.After(since) {
+			return false
+		}
+	}
+	return true
+}
+
+// etagListed reports whether list, an If-None-Match value, names etag under
+// weak comparison.
+func etagListed(list, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for tag := range strings.SplitSeq(list, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
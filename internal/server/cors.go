@@ -0,0 +1,106 @@
+package server
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORS configures cross-origin access for browser clients.
+type CORS struct {
+	// AllowedOrigins are the origins allowed to call the API, exactly as
+	// browsers send them (https://app.example.com), or "*" for any. Empty
+	// disables CORS: no Access-Control headers are sent.
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache a preflight response (0 leaves
+	// it to the browser).
+	MaxAge time.Duration
+}
+
+// exposedHeaders are the response headers browsers let scripts read: the
+// protocol headers Connect and gRPC-Web clients rely on, plus the ones
+// reads and writes set (ETag, deprecation notices).
+var exposedHeaders = strings.Join([]string{
+	"Content-Encoding",
+	"Connect-Content-Encoding",
+	"Connect-Accept-Encoding",
+	"Grpc-Status",
+	"Grpc-Message",
+	"Grpc-Status-Details-Bin",
+	"ETag",
+	"Deprecation",
+	"Sunset",
+	"Link",
+}, ", ")
+
+// CORSHandler answers preflight requests from allowed origins and adds the
+// Access-Control headers to their actual requests. Requests from other
+// origins are served without them, so the browser blocks the response.
+func CORSHandler(opts CORS, next http.Handler) http.Handler {
+	if len(opts.AllowedOrigins) == 0 {
+		return next
+	}
+	anyOrigin := slices.Contains(opts.AllowedOrigins, "*")
+	methods := strings.Join(opts.AllowedMethods, ", ")
+	headers := strings.Join(opts.AllowedHeaders, ", ")
+	maxAge := strconv.Itoa(int(opts.MaxAge.Seconds()))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		h := w.Header()
+		h.Add("Vary", "Origin")
+		if preflight {
+			h.Add("Vary", "Access-Control-Request-Method")
+			h.Add("Vary", "Access-Control-Request-Headers")
+		}
+		if origin == "" || !(anyOrigin || slices.Contains(opts.AllowedOrigins, origin)) {
+			if preflight {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if anyOrigin && !opts.AllowCredentials {
+			h.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			h.Set("Access-Control-Allow-Origin", origin)
+		}
+		if opts.AllowCredentials {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			h.Set("Access-Control-Expose-Headers", exposedHeaders)
+			next.ServeHTTP(w, r)
+			return
+		}
+		h.Set("Access-Control-Allow-Methods", methods)
+		h.Set("Access-Control-Allow-Headers", headers)
+		if opts.MaxAge > 0 {
+			h.Set("Access-Control-Max-Age", maxAge)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// SecurityHeaders sets the standard hardening headers on every response.
+// The API serves only JSON and protobuf, so nothing may be framed, sniffed
+// into another content type or load subresources.
+func SecurityHeaders(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("X-Frame-Options", "DENY")
+		h.Set("Referrer-Policy", "no-referrer")
+		h.Set("Content-Security-Policy", "default-src 'none'; frame-ancestors 'none'")
+		next.ServeHTTP(w, r)
+	})
+}
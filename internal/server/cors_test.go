@@ -0,0 +1,149 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCORSHandler(t *testing.T) {
+	const app, evil = "https://app.example.com", "https://evil.example.com"
+	listed := CORS{
+		AllowedOrigins:   []string{app},
+		AllowedMethods:   []string{"GET", "POST"},
+		AllowedHeaders:   []string{"Content-Type", "Connect-Protocol-Version"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}
+	wildcard := CORS{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}}
+
+	tests := []struct {
+		name      string
+		opts      CORS
+		method    string
+		origin    string
+		preflight bool
+		status    int
+		want      map[string]string // "" means the header must be absent
+		vary      []string
+	}{
+		{
+			name: "preflight from a listed origin", opts: listed, method: http.MethodOptions, origin: app, preflight: true,
+			status: http.StatusNoContent,
+			want: map[string]string{
+				"Access-Control-Allow-Origin":      app,
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Allow-Methods":     "GET, POST",
+				"Access-Control-Allow-Headers":     "Content-Type, Connect-Protocol-Version",
+				"Access-Control-Max-Age":           "600",
+				"Access-Control-Expose-Headers":    "",
+			},
+			vary: []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		},
+		{
+			name: "preflight from another origin", opts: listed, method: http.MethodOptions, origin: evil, preflight: true,
+			status: http.StatusForbidden,
+			want:   map[string]string{"Access-Control-Allow-Origin": "", "Access-Control-Allow-Methods": ""},
+			vary:   []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		},
+		{
+			name: "request from a listed origin with credentials", opts: listed, method: http.MethodPost, origin: app,
+			status: http.StatusOK,
+			want: map[string]string{
+				"Access-Control-Allow-Origin":      app,
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Expose-Headers":    exposedHeaders,
+				"Access-Control-Allow-Methods":     "",
+			},
+			vary: []string{"Origin"},
+		},
+		{
+			name: "request from another origin is served bare", opts: listed, method: http.MethodGet, origin: evil,
+			status: http.StatusOK,
+			want:   map[string]string{"Access-Control-Allow-Origin": "", "Access-Control-Expose-Headers": ""},
+			vary:   []string{"Origin"},
+		},
+		{
+			name: "wildcard without credentials", opts: wildcard, method: http.MethodGet, origin: evil,
+			status: http.StatusOK,
+			want: map[string]string{
+				"Access-Control-Allow-Origin":      "*",
+				"Access-Control-Allow-Credentials": "",
+				"Access-Control-Expose-Headers":    exposedHeaders,
+			},
+			vary: []string{"Origin"},
+		},
+		{
+			name: "wildcard preflight leaves max age to the browser", opts: wildcard, method: http.MethodOptions, origin: evil, preflight: true,
+			status: http.StatusNoContent,
+			want:   map[string]string{"Access-Control-Allow-Origin": "*", "Access-Control-Max-Age": ""},
+			vary:   []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		},
+		{
+			name: "wildcard with credentials echoes the origin", opts: CORS{AllowedOrigins: []string{"*"}, AllowCredentials: true},
+			method: http.MethodGet, origin: app,
+			status: http.StatusOK,
+			want:   map[string]string{"Access-Control-Allow-Origin": app, "Access-Control-Allow-Credentials": "true"},
+			vary:   []string{"Origin"},
+		},
+		{
+			name: "no origin", opts: listed, method: http.MethodGet,
+			status: http.StatusOK,
+			want:   map[string]string{"Access-Control-Allow-Origin": ""},
+			vary:   []string{"Origin"},
+		},
+		{
+			name: "disabled", opts: CORS{}, method: http.MethodGet, origin: app,
+			status: http.StatusOK,
+			want:   map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+	}
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/registry.v1.RegistryService/List", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+			rec := httptest.NewRecorder()
+			CORSHandler(tt.opts, ok).ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			for name, want := range tt.want {
+				if got := rec.Header().Get(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+			if got := rec.Header().Values("Vary"); !slices.Equal(got, tt.vary) {
+				t.Errorf("Vary = %v, want %v", got, tt.vary)
+			}
+		})
+	}
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	rec := httptest.NewRecorder()
+	SecurityHeaders(http.NotFoundHandler()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	for name, want := range map[string]string{
+		"X-Content-Type-Options":  "nosniff",
+		"X-Frame-Options":         "DENY",
+		"Referrer-Policy":         "no-referrer",
+		"Content-Security-Policy": "default-src 'none'; frame-ancestors 'none'",
+	} {
+		if got := rec.Header().Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want the wrapped handler's 404", rec.Code)
+	}
+}
@@ -2,22 +2,136 @@ package server
 
 import (
 	"context"
+	"path"
 
 	"buf.build/go/protovalidate"
 	"connectrpc.com/connect"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/atlekbai/schema_registry/internal/db"
+	"github.com/atlekbai/schema_registry/internal/metrics"
+)
+
+// ValidationInterceptor rejects requests that fail protovalidate constraints,
+// including the request messages of streaming RPCs (OrgService.ExecuteStream).
+func ValidationInterceptor(validator protovalidate.Validator) connect.Interceptor {
+	return validationInterceptor{validator: validator}
+}
+
+type validationInterceptor struct {
+	validator protovalidate.Validator
+}
+
+func (i validationInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if err := i.validate(req.Any()); err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+func (i validationInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i validationInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		return next(ctx, validatingConn{StreamingHandlerConn: conn, validate: i.validate})
+	}
+}
+
+func (i validationInterceptor) validate(msg any) error {
+	if m, ok := msg.(proto.Message); ok {
+		if err := i.validator.Validate(m); err != nil {
+			return connect.NewError(connect.CodeInvalidArgument, err)
+		}
+	}
+	return nil
+}
+
+// validatingConn validates each message a streaming handler receives.
+type validatingConn struct {
+	connect.StreamingHandlerConn
+	validate func(any) error
+}
+
+func (c validatingConn) Receive(msg any) error {
+	if err := c.StreamingHandlerConn.Receive(msg); err != nil {
+		return err
+	}
+	return c.validate(msg)
+}
+
+// QueryLabelsInterceptor tags the request context with the RPC procedure and,
+// for requests addressing an object, its api_name, so database diagnostics
+// (e.g. the slow query log) can attribute queries.
+func QueryLabelsInterceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			var object string
+			if msg, ok := req.Any().(interface{ GetObjectName() string }); ok {
+				object = msg.GetObjectName()
+			}
+			return next(db.WithQueryLabels(ctx, req.Spec().Procedure, object), req)
+		}
+	}
+}
+
+// Headers naming the caller and the request. Like X-Principal-Permissions
+// they are set by the trusted gateway in front of the server.
+const (
+	principalIDHeader = "X-Principal-Id"
+	requestIDHeader   = "X-Request-Id"
 )
 
-// ValidationInterceptor rejects requests that fail protovalidate constraints.
-func ValidationInterceptor(validator protovalidate.Validator) connect.UnaryInterceptorFunc {
+// ActorInterceptor attaches the caller's principal and request id to the
+// request context, so write transactions set app.actor_id and app.request_id
+// for database audit triggers and RLS policies (see db.Begin).
+func ActorInterceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			actor := db.Actor{
+				ID:        req.Header().Get(principalIDHeader),
+				RequestID: req.Header().Get(requestIDHeader),
+			}
+			return next(db.WithActor(ctx, actor), req)
+		}
+	}
+}
+
+// UsageInterceptor records each request in usage under its object, method
+// (e.g. "List") and X-Principal-Id, with its latency, outcome and the records
+// its response returned.
+func UsageInterceptor(usage *metrics.APIUsage) connect.UnaryInterceptorFunc {
 	return func(next connect.UnaryFunc) connect.UnaryFunc {
 		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
-			if msg, ok := req.Any().(proto.Message); ok {
-				if err := validator.Validate(msg); err != nil {
-					return nil, connect.NewError(connect.CodeInvalidArgument, err)
-				}
+			var object string
+			if msg, ok := req.Any().(interface{ GetObjectName() string }); ok {
+				object = msg.GetObjectName()
 			}
-			return next(ctx, req)
+			ctx, done := usage.Track(ctx, object, path.Base(req.Spec().Procedure), req.Header().Get(principalIDHeader))
+			resp, err := next(ctx, req)
+			done(resultRecords(resp), err)
+			return resp, err
+		}
+	}
+}
+
+// resultRecords counts the records a response carries: its results, or its
+// single record.
+func resultRecords(resp connect.AnyResponse) int {
+	if resp == nil {
+		return 0
+	}
+	switch msg := resp.Any().(type) {
+	case interface{ GetResults() []*structpb.Struct }:
+		return len(msg.GetResults())
+	case interface{ GetRecord() *structpb.Struct }:
+		if msg.GetRecord() != nil {
+			return 1
 		}
 	}
+	return 0
 }
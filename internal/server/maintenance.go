@@ -0,0 +1,95 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+
+	"github.com/atlekbai/schema_registry/gen/registry/v1/registryv1connect"
+)
+
+// writeProcedures are the RPCs rejected in read-only mode: record writes,
+// metadata mutations and their reviews, retention changes, hierarchy path
+// rebuilds, anonymization and the standard object seed.
+// Everything else, including HRQL, keeps working. TestWriteProcedures fails
+// on a non-GET RPC that is missing here.
+var writeProcedures = map[string]bool{
+	registryv1connect.RegistryServiceCreateProcedure:             true,
+	registryv1connect.RegistryServiceUpdateProcedure:             true,
+	registryv1connect.RegistryServiceUpdateWhereProcedure:        true,
+	registryv1connect.RegistryServiceUpsertProcedure:             true,
+	registryv1connect.RegistryServiceDeleteProcedure:             true,
+	registryv1connect.RegistryServiceRevertRecordProcedure:       true,
+	registryv1connect.MetadataServiceCreateObjectProcedure:       true,
+	registryv1connect.MetadataServiceUpdateObjectProcedure:       true,
+	registryv1connect.MetadataServiceDeleteObjectProcedure:       true,
+	registryv1connect.MetadataServiceRenameObjectProcedure:       true,
+	registryv1connect.MetadataServiceCreateFieldProcedure:        true,
+	registryv1connect.MetadataServiceUpdateFieldProcedure:        true,
+	registryv1connect.MetadataServiceDeleteFieldProcedure:        true,
+	registryv1connect.MetadataServiceRenameFieldProcedure:        true,
+	registryv1connect.AdminServiceSetRetentionPolicyProcedure:    true,
+	registryv1connect.AdminServiceDeleteRetentionPolicyProcedure: true,
+	registryv1connect.AdminServiceRunRetentionProcedure:          true,
+	registryv1connect.AdminServiceRebuildHierarchyPathsProcedure: true,
+	registryv1connect.AdminServiceAnonymizeObjectProcedure:       true,
+	registryv1connect.AdminServiceSeedStandardObjectsProcedure:   true,
+	registryv1connect.ReviewServiceApproveChangeRequestProcedure: true,
+	registryv1connect.ReviewServiceRejectChangeRequestProcedure:  true,
+}
+
+// Maintenance is the runtime read-only switch of a server instance.
+type Maintenance struct {
+	mu       sync.RWMutex
+	readOnly bool
+	reason   string
+	since    time.Time
+}
+
+// NewMaintenance returns a switch starting in the given mode.
+func NewMaintenance(readOnly bool, reason string) *Maintenance {
+	m := &Maintenance{}
+	m.Set(readOnly, reason)
+	return m
+}
+
+// Set switches read-only mode on or off.
+func (m *Maintenance) Set(readOnly bool, reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if readOnly && !m.readOnly {
+		m.since = time.Now()
+	}
+	if !readOnly {
+		m.since, reason = time.Time{}, ""
+	}
+	m.readOnly, m.reason = readOnly, reason
+}
+
+// State returns the current mode, its reason and when it was switched on.
+func (m *Maintenance) State() (readOnly bool, reason string, since time.Time) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.readOnly, m.reason, m.since
+}
+
+// ReadOnlyInterceptor rejects write RPCs with UNAVAILABLE while m is read-only.
+func ReadOnlyInterceptor(m *Maintenance) connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if writeProcedures[req.Spec().Procedure] {
+				if readOnly, reason, _ := m.State(); readOnly {
+					msg := "server is in read-only maintenance mode"
+					if reason != "" {
+						msg += ": " + reason
+					}
+					return nil, connect.NewError(connect.CodeUnavailable, errors.New(msg))
+				}
+			}
+			return next(ctx, req)
+		}
+	}
+}
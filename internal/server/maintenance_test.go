@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	registryv1 "github.com/atlekbai/schema_registry/gen/registry/v1"
+	"github.com/atlekbai/schema_registry/gen/registry/v1/registryv1connect"
+)
+
+// readOnlySafe are the non-GET RPCs that stay available in read-only mode.
+var readOnlySafe = map[string]bool{
+	registryv1connect.AdminServiceSetMaintenanceModeProcedure:     true, // must be able to switch it off
+	registryv1connect.AdminServiceReloadSchemaCacheProcedure:      true, // in-memory only
+	registryv1connect.AdminServiceEvictSchemaCacheObjectProcedure: true, // in-memory only
+	registryv1connect.OrgServiceQueryProcedure:                    true,
+	registryv1connect.OrgServiceToFiltersProcedure:                true,
+	registryv1connect.OrgServiceExplainProcedure:                  true,
+	registryv1connect.OrgServiceBatchEvaluateProcedure:            true,
+	registryv1connect.OrgServiceExecuteStreamProcedure:            true,
+}
+
+// TestWriteProcedures walks every RPC of the registry.v1 services: anything
+// not served over GET must be in writeProcedures or listed above as safe, so
+// a new write RPC cannot be left out of read-only mode by accident.
+func TestWriteProcedures(t *testing.T) {
+	seen := map[string]bool{}
+	protoregistry.GlobalFiles.RangeFilesByPackage("registry.v1", func(fd protoreflect.FileDescriptor) bool {
+		for i := range fd.Services().Len() {
+			svc := fd.Services().Get(i)
+			for j := range svc.Methods().Len() {
+				m := svc.Methods().Get(j)
+				procedure := "/" + string(svc.FullName()) + "/" + string(m.Name())
+				seen[procedure] = true
+
+				rule, _ := proto.GetExtension(m.Options(), annotations.E_Http).(*annotations.HttpRule)
+				isGet := rule.GetGet() != ""
+				switch {
+				case isGet && (writeProcedures[procedure] || readOnlySafe[procedure]):
+					t.Errorf("%s is served over GET but listed as a write or an exception", procedure)
+				case !isGet && writeProcedures[procedure] == readOnlySafe[procedure]:
+					t.Errorf("%s is not a GET: add it to writeProcedures, or to readOnlySafe if it does not write", procedure)
+				}
+			}
+		}
+		return true
+	})
+	if len(seen) == 0 {
+		t.Fatal("no registry.v1 services registered")
+	}
+	for procedure := range writeProcedures {
+		if !seen[procedure] {
+			t.Errorf("writeProcedures lists unknown procedure %s", procedure)
+		}
+	}
+}
+
+func TestReadOnlyInterceptor(t *testing.T) {
+	m := NewMaintenance(true, "restoring backup")
+	mux := http.NewServeMux()
+	mux.Handle(registryv1connect.NewAdminServiceHandler(
+		registryv1connect.UnimplementedAdminServiceHandler{},
+		connect.WithInterceptors(ReadOnlyInterceptor(m)),
+	))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	client := registryv1connect.NewAdminServiceClient(srv.Client(), srv.URL)
+	ctx := context.Background()
+
+	write := func() error {
+		_, err := client.RunRetention(ctx, connect.NewRequest(&registryv1.RunRetentionRequest{}))
+		return err
+	}
+	read := func() error {
+		_, err := client.GetSchemaCache(ctx, connect.NewRequest(&registryv1.GetSchemaCacheRequest{}))
+		return err
+	}
+
+	err := write()
+	if connect.CodeOf(err) != connect.CodeUnavailable {
+		t.Fatalf("write in read-only mode: %v, want UNAVAILABLE", err)
+	}
+	if ce, ok := errors.AsType[*connect.Error](err); !ok ||
+		ce.Message() != "server is in read-only maintenance mode: restoring backup" {
+		t.Errorf("write in read-only mode: message %q", err)
+	}
+	if err := read(); connect.CodeOf(err) != connect.CodeUnimplemented {
+		t.Errorf("read in read-only mode: %v, want it to reach the handler", err)
+	}
+
+	m.Set(true, "")
+	if err := write(); err == nil || !strings.HasSuffix(err.Error(), "server is in read-only maintenance mode") {
+		t.Errorf("write without a reason: %v", err)
+	}
+
+	m.Set(false, "")
+	if err := write(); connect.CodeOf(err) != connect.CodeUnimplemented {
+		t.Errorf("write after leaving read-only mode: %v, want it to reach the handler", err)
+	}
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"strconv"
+)
+
+// RawListHandler serves REST lists requested with raw=true as a bare JSON
+// array of the records: it unwraps the "results" of the transcoded
+// ListResponse, which has no count or cursor in raw mode anyway. Errors and
+// every other request pass through unchanged.
+func RawListHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Query().Get("raw") != "true" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// The envelope is rewritten below, so ask for it uncompressed.
+		r = r.Clone(r.Context())
+		r.Header.Del("Accept-Encoding")
+		rec := &bufferedResponse{header: make(http.Header), status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		body := rec.body.Bytes()
+		if rec.status == http.StatusOK {
+			var envelope struct {
+				Results json.RawMessage `json:"results"`
+			}
+			if err := json.Unmarshal(body, &envelope); err == nil {
+				body = rawResults(envelope.Results)
+				rec.header.Set("Content-Type", "application/json")
+			}
+		}
+
+		maps.Copy(w.Header(), rec.header)
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.WriteHeader(rec.status)
+		w.Write(body)
+	})
+}
+
+// rawResults returns the results array, [] when proto JSON omitted it as empty.
+func rawResults(results json.RawMessage) []byte {
+	if len(results) == 0 || bytes.Equal(results, []byte("null")) {
+		return []byte("[]")
+	}
+	return results
+}
+
+// bufferedResponse records a response so RawListHandler can rewrite it.
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header         { return b.header }
+func (b *bufferedResponse) Write(p []byte) (int, error) { return b.body.Write(p) }
+func (b *bufferedResponse) WriteHeader(status int)      { b.status = status }
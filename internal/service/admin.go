@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+
+	"connectrpc.com/connect"
+
+	registryv1 "github.com/atlekbai/schema_registry/gen/registry/v1"
+	registryv1connect "github.com/atlekbai/schema_registry/gen/registry/v1/registryv1connect"
+	"github.com/atlekbai/schema_registry/internal/bootstrap"
+	"github.com/atlekbai/schema_registry/internal/db"
+	"github.com/atlekbai/schema_registry/internal/ltreeutil"
+	"github.com/atlekbai/schema_registry/internal/metrics"
+	"github.com/atlekbai/schema_registry/internal/retention"
+	"github.com/atlekbai/schema_registry/internal/schema"
+	"github.com/atlekbai/schema_registry/internal/server"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AdminService struct {
+	pool        *pgxpool.Pool
+	cache       *schema.Cache
+	migrator    *db.Migrator
+	maintenance *server.Maintenance
+	retention   *retention.Enforcer
+	usage       *metrics.HRQL
+}
+
+func NewAdminService(pool *pgxpool.Pool, cache *schema.Cache, migrator *db.Migrator, maintenance *server.Maintenance, enforcer *retention.Enforcer, usage *metrics.HRQL) *AdminService {
+	return &AdminService{pool: pool, cache: cache, migrator: migrator, maintenance: maintenance, retention: enforcer, usage: usage}
+}
+
+func (s *AdminService) RegisterHandler(interceptors ...connect.Interceptor) (string, http.Handler) {
+	return registryv1connect.NewAdminServiceHandler(s, connect.WithInterceptors(interceptors...))
+}
+
+func (s *AdminService) MigrationStatus(ctx context.Context, _ *connect.Request[registryv1.MigrationStatusRequest]) (*connect.Response[registryv1.MigrationStatusResponse], error) {
+	st, err := s.migrator.Status(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("migration status: %w", err))
+	}
+
+	resp := &registryv1.MigrationStatusResponse{
+		CurrentVersion: st.Current(),
+		Dirty:          st.Dirty(),
+		Pending:        int32(len(st.Pending())),
+	}
+	for _, m := range st.Migrations {
+		out := &registryv1.Migration{Version: m.Version, Name: m.Name}
+		if a, ok := st.Applied[m.Version]; ok {
+			out.Applied = true
+			if a.AppliedAt != nil {
+				out.AppliedAt = pgTimestamp(*a.AppliedAt)
+			}
+		}
+		resp.Migrations = append(resp.Migrations, out)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *AdminService) GetMaintenanceMode(_ context.Context, _ *connect.Request[registryv1.GetMaintenanceModeRequest]) (*connect.Response[registryv1.GetMaintenanceModeResponse], error) {
+	return connect.NewResponse(&registryv1.GetMaintenanceModeResponse{Mode: s.maintenanceMode()}), nil
+}
+
+func (s *AdminService) SetMaintenanceMode(_ context.Context, req *connect.Request[registryv1.SetMaintenanceModeRequest]) (*connect.Response[registryv1.SetMaintenanceModeResponse], error) {
+	s.maintenance.Set(req.Msg.ReadOnly, req.Msg.Reason)
+	mode := s.maintenanceMode()
+	log.Printf("maintenance mode: read_only=%t reason=%q", mode.ReadOnly, mode.Reason)
+	return connect.NewResponse(&registryv1.SetMaintenanceModeResponse{Mode: mode}), nil
+}
+
+func (s *AdminService) maintenanceMode() *registryv1.MaintenanceMode {
+	readOnly, reason, since := s.maintenance.State()
+	mode := &registryv1.MaintenanceMode{ReadOnly: readOnly, Reason: reason}
+	if readOnly {
+		mode.Since = pgTimestamp(since)
+	}
+	return mode
+}
+
+func (s *AdminService) GetSchemaCache(_ context.Context, _ *connect.Request[registryv1.GetSchemaCacheRequest]) (*connect.Response[registryv1.GetSchemaCacheResponse], error) {
+	return connect.NewResponse(&registryv1.GetSchemaCacheResponse{Cache: s.schemaCache()}), nil
+}
+
+func (s *AdminService) ReloadSchemaCache(ctx context.Context, _ *connect.Request[registryv1.ReloadSchemaCacheRequest]) (*connect.Response[registryv1.ReloadSchemaCacheResponse], error) {
+	if err := s.cache.Load(ctx, s.pool); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	log.Printf("schema cache reloaded: %d objects", s.cache.ObjectCount())
+	return connect.NewResponse(&registryv1.ReloadSchemaCacheResponse{Cache: s.schemaCache()}), nil
+}
+
+func (s *AdminService) EvictSchemaCacheObject(ctx context.Context, req *connect.Request[registryv1.EvictSchemaCacheObjectRequest]) (*connect.Response[registryv1.EvictSchemaCacheObjectResponse], error) {
+	found, err := s.cache.ReloadObject(ctx, s.pool, req.Msg.ApiName)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	log.Printf("schema cache: evicted %q (found=%t)", req.Msg.ApiName, found)
+	generation, _ := s.cache.Generation()
+	return connect.NewResponse(&registryv1.EvictSchemaCacheObjectResponse{Found: found, Generation: generation}), nil
+}
+
+func (s *AdminService) schemaCache() *registryv1.SchemaCache {
+	generation, loadedAt := s.cache.Generation()
+	out := &registryv1.SchemaCache{Generation: generation}
+	if !loadedAt.IsZero() {
+		out.LoadedAt = pgTimestamp(loadedAt)
+	}
+	for _, obj := range s.cache.Objects() {
+		out.Objects = append(out.Objects, &registryv1.CachedObject{
+			Id:         obj.ID.String(),
+			ApiName:    obj.APIName,
+			IsStandard: obj.IsStandard,
+			FieldCount: int32(len(obj.Fields)),
+		})
+	}
+	return out
+}
+
+func (s *AdminService) ListRetentionPolicies(ctx context.Context, _ *connect.Request[registryv1.ListRetentionPoliciesRequest]) (*connect.Response[registryv1.ListRetentionPoliciesResponse], error) {
+	policies, err := retention.ListPolicies(ctx, s.pool)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	resp := &registryv1.ListRetentionPoliciesResponse{}
+	for _, p := range policies {
+		obj := s.cache.GetByID(p.ObjectID)
+		if obj == nil {
+			continue
+		}
+		resp.Policies = append(resp.Policies, retentionPolicy(obj, p))
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *AdminService) SetRetentionPolicy(ctx context.Context, req *connect.Request[registryv1.SetRetentionPolicyRequest]) (*connect.Response[registryv1.SetRetentionPolicyResponse], error) {
+	msg := req.Msg
+	obj := s.cache.Get(msg.ObjectName)
+	if obj == nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("no object registered with api_name %q", msg.ObjectName))
+	}
+	p := retention.Policy{
+		ObjectID:        obj.ID,
+		DateField:       msg.DateField,
+		RetainDays:      int(msg.RetainDays),
+		Action:          retention.Action(msg.Action),
+		AnonymizeFields: msg.AnonymizeFields,
+		Enabled:         msg.Enabled,
+	}
+	if err := p.Validate(obj); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	saved, err := retention.SavePolicy(ctx, s.pool, p)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	log.Printf("retention policy: %s %s after %d days of %s (enabled=%t)", obj.APIName, p.Action, p.RetainDays, p.DateField, p.Enabled)
+	return connect.NewResponse(&registryv1.SetRetentionPolicyResponse{Policy: retentionPolicy(obj, saved)}), nil
+}
+
+func (s *AdminService) DeleteRetentionPolicy(ctx context.Context, req *connect.Request[registryv1.DeleteRetentionPolicyRequest]) (*connect.Response[registryv1.DeleteRetentionPolicyResponse], error) {
+	obj := s.cache.Get(req.Msg.ObjectName)
+	if obj == nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("no object registered with api_name %q", req.Msg.ObjectName))
+	}
+	found, err := retention.DeletePolicy(ctx, s.pool, obj.ID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	if !found {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("object %q has no retention policy", obj.APIName))
+	}
+	return connect.NewResponse(&registryv1.DeleteRetentionPolicyResponse{}), nil
+}
+
+func (s *AdminService) RunRetention(ctx context.Context, _ *connect.Request[registryv1.RunRetentionRequest]) (*connect.Response[registryv1.RunRetentionResponse], error) {
+	results, ran, err := s.retention.Run(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	resp := &registryv1.RunRetentionResponse{Ran: ran}
+	for _, r := range results {
+		out := &registryv1.RetentionRunResult{
+			ObjectName: r.ObjectName,
+			Action:     string(r.Action),
+			Purged:     int32(r.Purged),
+			Skipped:    int32(r.Skipped),
+		}
+		if r.Err != nil {
+			out.Error = r.Err.Error()
+		}
+		resp.Results = append(resp.Results, out)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *AdminService) ListRetentionAudit(ctx context.Context, req *connect.Request[registryv1.ListRetentionAuditRequest]) (*connect.Response[registryv1.ListRetentionAuditResponse], error) {
+	limit := int(req.Msg.Limit)
+	if limit == 0 {
+		limit = 100
+	}
+	entries, err := retention.ListAudit(ctx, s.pool, req.Msg.ObjectName, limit)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	resp := &registryv1.ListRetentionAuditResponse{}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, &registryv1.RetentionAuditEntry{
+			PurgedAt:   pgTimestamp(e.PurgedAt),
+			ObjectName: e.ObjectName,
+			RecordId:   e.RecordID.String(),
+			Action:     string(e.Action),
+			DateField:  e.DateField,
+			RetainDays: int32(e.RetainDays),
+		})
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *AdminService) AnonymizeObject(ctx context.Context, req *connect.Request[registryv1.AnonymizeObjectRequest]) (*connect.Response[registryv1.AnonymizeObjectResponse], error) {
+	obj := s.cache.Get(req.Msg.ObjectName)
+	if obj == nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("no object registered with api_name %q", req.Msg.ObjectName))
+	}
+	res, err := s.retention.Anonymize(ctx, obj, req.Msg.Salt)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("anonymize %s: %w", obj.APIName, err))
+	}
+	log.Printf("anonymize: %s, %d records, fields %v", obj.APIName, res.Anonymized, res.Fields)
+	return connect.NewResponse(&registryv1.AnonymizeObjectResponse{Fields: res.Fields, Anonymized: int32(res.Anonymized)}), nil
+}
+
+func (s *AdminService) RebuildHierarchyPaths(ctx context.Context, req *connect.Request[registryv1.RebuildHierarchyPathsRequest]) (*connect.Response[registryv1.RebuildHierarchyPathsResponse], error) {
+	msg := req.Msg
+	obj := s.cache.Get(msg.ObjectName)
+	if obj == nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("no object registered with api_name %q", msg.ObjectName))
+	}
+	fd := obj.FieldsByAPIName[msg.Field]
+	if fd == nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("unknown field %q on %q", msg.Field, obj.APIName))
+	}
+	if fd.PathColumn == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("field %q of %q has no hierarchy path", fd.APIName, obj.APIName))
+	}
+
+	tx, err := db.Begin(ctx, s.pool)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("begin transaction: %w", err))
+	}
+	defer tx.Rollback(ctx)
+
+	updated, broken, err := ltreeutil.RebuildPaths(ctx, tx, obj, fd)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("commit: %w", err))
+	}
+	log.Printf("hierarchy paths: rebuilt %s.%s, %d updated, %d broken", obj.APIName, fd.APIName, updated, len(broken))
+
+	resp := &registryv1.RebuildHierarchyPathsResponse{Updated: updated}
+	for _, id := range broken {
+		resp.BrokenIds = append(resp.BrokenIds, id.String())
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func retentionPolicy(obj *schema.ObjectDef, p retention.Policy) *registryv1.RetentionPolicy {
+	out := &registryv1.RetentionPolicy{
+		ObjectName:      obj.APIName,
+		DateField:       p.DateField,
+		RetainDays:      int32(p.RetainDays),
+		Action:          string(p.Action),
+		AnonymizeFields: p.AnonymizeFields,
+		Enabled:         p.Enabled,
+	}
+	if p.LastRunAt != nil {
+		out.LastRunAt = pgTimestamp(*p.LastRunAt)
+	}
+	return out
+}
+
+func (s *AdminService) SeedStandardObjects(ctx context.Context, req *connect.Request[registryv1.SeedStandardObjectsRequest]) (*connect.Response[registryv1.SeedStandardObjectsResponse], error) {
+	res, err := bootstrap.Apply(ctx, s.pool, bootstrap.Standard(), req.Msg.Force)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("seed standard objects: %w", err))
+	}
+	if res.ObjectsCreated+res.ObjectsUpdated+res.FieldsCreated+res.FieldsUpdated > 0 {
+		if err := s.cache.Load(ctx, s.pool); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("reload schema cache: %w", err))
+		}
+	}
+	log.Printf("standard object seed: version %d applied=%t objects +%d ~%d fields +%d ~%d",
+		res.Version, res.Applied, res.ObjectsCreated, res.ObjectsUpdated, res.FieldsCreated, res.FieldsUpdated)
+	return connect.NewResponse(&registryv1.SeedStandardObjectsResponse{
+		Version:        int32(res.Version),
+		Applied:        res.Applied,
+		ObjectsCreated: int32(res.ObjectsCreated),
+		ObjectsUpdated: int32(res.ObjectsUpdated),
+		FieldsCreated:  int32(res.FieldsCreated),
+		FieldsUpdated:  int32(res.FieldsUpdated),
+	}), nil
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	registryv1 "github.com/atlekbai/schema_registry/gen/registry/v1"
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// setCacheHeaders lets browsers and CDNs reuse a REST read of an object with
+// a cache max age (schema.ObjectDef.CacheMaxAge). Responses with PII revealed
+// or masked fields stay private to the caller, and every response varies with
+// the caller's permissions. modified is the newest record change the response reflects;
+// Last-Modified is the later of it and the load of the schema the response
+// was rendered with, and is omitted when modified is zero.
+func setCacheHeaders(h http.Header, obj *schema.ObjectDef, v viewer, modified time.Time) {
+	if obj.CacheMaxAge <= 0 {
+		return
+	}
+	scope := "public"
+	if v.pii || slices.ContainsFunc(obj.Fields, func(fd schema.FieldDef) bool { return fd.Masking != nil }) {
+		scope = "private"
+	}
+	h.Set("Cache-Control", fmt.Sprintf("%s, max-age=%d", scope, int(obj.CacheMaxAge/time.Second)))
+	h.Add("Vary", permissionsHeader)
+	if modified.IsZero() {
+		return
+	}
+	if obj.LoadedAt.After(modified) {
+		modified = obj.LoadedAt
+	}
+	h.Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+}
+
+// recordUpdatedAt returns the updated_at of a record as read, zero when it
+// is missing or malformed.
+func recordUpdatedAt(record *structpb.Struct) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, record.GetFields()["updated_at"].GetStringValue())
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// listETag returns a weak validator of a List page: a hash of its content,
+// which any record change, deletion or schema change showing on the page
+// alters. Pages have no Last-Modified, which deletions would not move.
+func listETag(resp *registryv1.ListResponse) string {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(resp)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
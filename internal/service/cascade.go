@@ -0,0 +1,130 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	registryv1 "github.com/atlekbai/schema_registry/gen/registry/v1"
+	hrqlpg "github.com/atlekbai/schema_registry/internal/hrql/pg"
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// cascadeNullify is the DeleteObject cascade that clears and drops the
+// LOOKUP fields referencing the object instead of refusing the delete.
+const cascadeNullify = "nullify"
+
+// deleteReference is a LOOKUP field of another object that targets an object
+// being deleted.
+type deleteReference struct {
+	obj *schema.ObjectDef
+	fd  *schema.FieldDef
+}
+
+func (r deleteReference) String() string { return r.obj.APIName + "." + r.fd.APIName }
+
+// referencesTo returns the LOOKUP fields of other objects that target obj,
+// ordered by object and field api_name. Self-references go with the object.
+func referencesTo(cache *schema.Cache, obj *schema.ObjectDef) []deleteReference {
+	var refs []deleteReference
+	for _, o := range cache.Objects() {
+		if o.ID == obj.ID {
+			continue
+		}
+		for i := range o.Fields {
+			fd := &o.Fields[i]
+			if fd.Type == schema.FieldLookup && fd.LookupObjectID != nil && *fd.LookupObjectID == obj.ID {
+				refs = append(refs, deleteReference{obj: o, fd: fd})
+			}
+		}
+	}
+	slices.SortFunc(refs, func(a, b deleteReference) int {
+		return cmp.Or(strings.Compare(a.obj.APIName, b.obj.APIName), strings.Compare(a.fd.APIName, b.fd.APIName))
+	})
+	return refs
+}
+
+// PreviewDelete reports what DeleteObject would touch: the references it
+// blocks on or nullifies, then the object's records and webhook.
+func (s *MetadataService) PreviewDelete(ctx context.Context, req *connect.Request[registryv1.PreviewDeleteRequest]) (*connect.Response[registryv1.PreviewDeleteResponse], error) {
+	obj := s.cache.GetByID(uuid.MustParse(req.Msg.Id))
+	if obj == nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("object not found"))
+	}
+
+	resp := &registryv1.PreviewDeleteResponse{ApiName: obj.APIName}
+	for _, ref := range referencesTo(s.cache, obj) {
+		r := &registryv1.DeleteReference{
+			ObjectId:      ref.obj.ID.String(),
+			ObjectApiName: ref.obj.APIName,
+			FieldId:       ref.fd.ID.String(),
+			FieldApiName:  ref.fd.APIName,
+			IsStandard:    ref.fd.IsStandard,
+		}
+		sqlStr, args, err := hrqlpg.BuildReferenceCount(ref.obj, ref.fd)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("build reference count: %w", err))
+		}
+		if err := s.pool.QueryRow(ctx, sqlStr, args...).Scan(&r.Records); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("count references from %s: %w", ref, err))
+		}
+		resp.References = append(resp.References, r)
+	}
+
+	sqlStr, args, err := hrqlpg.NewBuilder(obj).BuildCount(&hrqlpg.QueryParams{})
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("build count: %w", err))
+	}
+	if err := s.pool.QueryRow(ctx, sqlStr, args...).Scan(&resp.Records); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("count records: %w", err))
+	}
+	if obj.ValidationWebhook != nil {
+		resp.ValidationWebhookUrl = obj.ValidationWebhook.URL
+	}
+	return connect.NewResponse(resp), nil
+}
+
+// cascadeDelete handles the references to obj inside DeleteObject's
+// transaction: any of them fails the default cascade, while "nullify" clears
+// each field on every record and drops it. Standard fields cannot be dropped.
+func (s *MetadataService) cascadeDelete(ctx context.Context, tx pgx.Tx, obj *schema.ObjectDef, cascade string) error {
+	refs := referencesTo(s.cache, obj)
+	if len(refs) == 0 {
+		return nil
+	}
+	if cascade != cascadeNullify {
+		names := make([]string, len(refs))
+		for i, ref := range refs {
+			names[i] = ref.String()
+		}
+		return connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("object %q is referenced by %s; delete those fields or use cascade %q", obj.APIName, strings.Join(names, ", "), cascadeNullify))
+	}
+	for _, ref := range refs {
+		if ref.fd.IsStandard {
+			return connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("standard field %s references %q and cannot be dropped", ref, obj.APIName))
+		}
+	}
+
+	for _, ref := range refs {
+		sqlStr, args, err := hrqlpg.BuildNullifyLookup(ref.obj, ref.fd)
+		if err != nil {
+			return connect.NewError(connect.CodeInternal, fmt.Errorf("build nullify: %w", err))
+		}
+		if _, err := tx.Exec(ctx, sqlStr, args...); err != nil {
+			return connect.NewError(connect.CodeInternal, fmt.Errorf("nullify %s: %w", ref, err))
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM metadata.fields WHERE id = $1`, ref.fd.ID); err != nil {
+			return connect.NewError(connect.CodeInternal, fmt.Errorf("drop %s: %w", ref, err))
+		}
+		if err := dropFieldIndexes(ctx, tx, ref.obj, ref.fd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
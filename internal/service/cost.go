@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"connectrpc.com/connect"
+
+	registryv1 "github.com/atlekbai/schema_registry/gen/registry/v1"
+)
+
+// costOverridePermission allows skip_cost_check on HRQL queries.
+const costOverridePermission = "hrql:unbounded"
+
+// queryEstimate is the planner's estimate for a query: the total cost of its
+// top plan node and the most rows any node is expected to produce.
+type queryEstimate struct {
+	Cost float64
+	Rows int64
+}
+
+// planNode is the part of an EXPLAIN (FORMAT JSON) node the estimate reads.
+type planNode struct {
+	TotalCost float64    `json:"Total Cost"`
+	PlanRows  float64    `json:"Plan Rows"`
+	Plans     []planNode `json:"Plans"`
+}
+
+// maxRows returns the largest row estimate in the subtree. A LIMIT or an
+// aggregate reports few rows at the top, so the rows a query has to read
+// show up further down.
+func (n planNode) maxRows() float64 {
+	rows := n.PlanRows
+	for _, c := range n.Plans {
+		rows = max(rows, c.maxRows())
+	}
+	return rows
+}
+
+func parseEstimate(planJSON string) (queryEstimate, error) {
+	var plan []struct {
+		Plan planNode `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(planJSON), &plan); err != nil {
+		return queryEstimate{}, err
+	}
+	if len(plan) == 0 {
+		return queryEstimate{}, fmt.Errorf("empty plan")
+	}
+	return queryEstimate{Cost: plan[0].Plan.TotalCost, Rows: int64(plan[0].Plan.maxRows())}, nil
+}
+
+// costChecked reports whether queries must pass the cost ceilings: they are
+// configured and the caller did not skip the check. Skipping it without
+// costOverridePermission fails with PERMISSION_DENIED.
+func (l QueryLimits) costChecked(skip bool, h http.Header) (bool, error) {
+	if l.MaxCost <= 0 && l.MaxRows <= 0 {
+		return false, nil
+	}
+	if !skip {
+		return true, nil
+	}
+	if !hasPermission(h, costOverridePermission) {
+		return false, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("skip_cost_check requires the %s permission", costOverridePermission))
+	}
+	return false, nil
+}
+
+// checkCost runs EXPLAIN on a query and rejects it with FAILED_PRECONDITION
+// and a QueryCostExceeded detail when its estimate exceeds the ceilings.
+func (s *OrgService) checkCost(ctx context.Context, sql string, args []any) error {
+	var planJSON string
+	if err := s.pool.QueryRow(ctx, "EXPLAIN (FORMAT JSON) "+sql, args...).Scan(&planJSON); err != nil {
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("explain query: %w", err))
+	}
+	est, err := parseEstimate(planJSON)
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("parse query plan: %w", err))
+	}
+
+	var over string
+	switch {
+	case s.limits.MaxCost > 0 && est.Cost > s.limits.MaxCost:
+		over = fmt.Sprintf("estimated cost %.0f exceeds the limit of %.0f", est.Cost, s.limits.MaxCost)
+	case s.limits.MaxRows > 0 && est.Rows > s.limits.MaxRows:
+		over = fmt.Sprintf("estimated %d rows exceed the limit of %d", est.Rows, s.limits.MaxRows)
+	default:
+		return nil
+	}
+	cerr := connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("query too expensive: %s; narrow it with filters", over))
+	if detail, derr := connect.NewErrorDetail(&registryv1.QueryCostExceeded{
+		Reason:        "QUERY_COST_EXCEEDED",
+		EstimatedCost: est.Cost,
+		EstimatedRows: est.Rows,
+		MaxCost:       s.limits.MaxCost,
+		MaxRows:       s.limits.MaxRows,
+	}); derr == nil {
+		cerr.AddDetail(detail)
+	}
+	return cerr
+}
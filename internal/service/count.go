@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	hrqlpg "github.com/atlekbai/schema_registry/internal/hrql/pg"
+)
+
+// After countBreakerThreshold consecutive failed counts of an object, lists of
+// it skip the count for countBreakerCooldown; the first list after that tries
+// again.
+const (
+	countBreakerThreshold = 5
+	countBreakerCooldown  = 30 * time.Second
+)
+
+// countBreaker makes list counts best-effort: a failed count leaves the page
+// without a total instead of failing it, and an object whose counts keep
+// failing stops being counted for a while, so lists do not pay for an
+// EXPLAIN that is bound to fail.
+type countBreaker struct {
+	mu      sync.Mutex
+	objects map[string]*countBreakerState
+	now     func() time.Time
+}
+
+type countBreakerState struct {
+	failures  int
+	openUntil time.Time
+}
+
+func newCountBreaker() *countBreaker {
+	return &countBreaker{objects: make(map[string]*countBreakerState), now: time.Now}
+}
+
+// count resolves a list's total through resolveCount, exactly when the
+// estimate is at most threshold. It returns -1 and false
+// when the count is skipped or fails; failures are logged, except when ctx is
+// done (the list itself failed or the caller went away).
+func (b *countBreaker) count(ctx context.Context, q querier, object string, threshold int64, builder hrqlpg.Builder, params *hrqlpg.QueryParams) (int64, bool) {
+	if !b.allow(object) {
+		return -1, false
+	}
+	n, err := resolveCount(ctx, q, builder, params, threshold)
+	if err != nil {
+		if ctx.Err() != nil {
+			return -1, false
+		}
+		log.Printf("count %s: %v", object, err)
+		b.record(object, false)
+		return -1, false
+	}
+	b.record(object, true)
+	return n, true
+}
+
+// allow reports whether object may be counted: its breaker is closed, or
+// its cooldown has passed.
+func (b *countBreaker) allow(object string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	st := b.objects[object]
+	return st == nil || !b.now().Before(st.openUntil)
+}
+
+// record counts a success, which closes the breaker, or a failure, which
+// opens it once countBreakerThreshold have happened in a row.
+func (b *countBreaker) record(object string, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if ok {
+		delete(b.objects, object)
+		return
+	}
+	st := b.objects[object]
+	if st == nil {
+		st = &countBreakerState{}
+		b.objects[object] = st
+	}
+	st.failures++
+	if st.failures >= countBreakerThreshold {
+		if st.failures == countBreakerThreshold {
+			log.Printf("count %s: %d failures in a row, skipping counts for %s", object, st.failures, countBreakerCooldown)
+		}
+		st.openUntil = b.now().Add(countBreakerCooldown)
+	}
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCountBreaker(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := newCountBreaker()
+	b.now = func() time.Time { return now }
+
+	for range countBreakerThreshold - 1 {
+		b.record("employees", false)
+	}
+	if !b.allow("employees") {
+		t.Fatalf("breaker open after %d failures", countBreakerThreshold-1)
+	}
+	b.record("employees", false)
+	if b.allow("employees") {
+		t.Fatalf("breaker closed after %d failures", countBreakerThreshold)
+	}
+	if !b.allow("departments") {
+		t.Error("another object's failures opened the breaker")
+	}
+
+	// After the cooldown one try is let through; another failure reopens it.
+	now = now.Add(countBreakerCooldown)
+	if !b.allow("employees") {
+		t.Fatal("breaker still open after the cooldown")
+	}
+	b.record("employees", false)
+	if b.allow("employees") {
+		t.Fatal("failed retry did not reopen the breaker")
+	}
+
+	now = now.Add(countBreakerCooldown)
+	b.record("employees", true)
+	for range countBreakerThreshold - 1 {
+		b.record("employees", false)
+	}
+	if !b.allow("employees") {
+		t.Error("success did not reset the failure count")
+	}
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/atlekbai/schema_registry/internal/fieldcrypt"
+	hrqlpg "github.com/atlekbai/schema_registry/internal/hrql/pg"
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// permissionsHeader carries the caller's permissions. It is set by the trusted
+// gateway in front of the server, which strips any client-supplied value.
+const permissionsHeader = "X-Principal-Permissions"
+
+// piiReadPermission allows reading decrypted ENCRYPTED field values.
+const piiReadPermission = "pii:read"
+
+// canReadPII reports whether the caller may see plaintext ENCRYPTED values.
+func canReadPII(h http.Header) bool {
+	return hasPermission(h, piiReadPermission)
+}
+
+// hasPermission reports whether permissionsHeader grants perm.
+func hasPermission(h http.Header, perm string) bool {
+	for _, v := range h.Values(permissionsHeader) {
+		for p := range strings.SplitSeq(v, ",") {
+			if strings.TrimSpace(p) == perm {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// encryptValues replaces ENCRYPTED field values in a write payload with their
+// ciphertext. The JSON encoding of the value is encrypted so reads restore its
+// original type. Nulls are stored as-is.
+func encryptValues(c *fieldcrypt.Cipher, obj *schema.ObjectDef, values map[string]any) error {
+	for name, v := range values {
+		fd := obj.FieldsByAPIName[name]
+		if fd == nil || !fd.IsEncrypted() || v == nil {
+			continue
+		}
+		plain, err := json.Marshal(v)
+		if err != nil {
+			return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("field %q: %w", name, err))
+		}
+		enc, err := c.Encrypt(plain, fd.ID[:])
+		if errors.Is(err, fieldcrypt.ErrNoKey) {
+			return connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("field %q: %w", name, err))
+		}
+		if err != nil {
+			return connect.NewError(connect.CodeInternal, fmt.Errorf("encrypt field %q: %w", name, err))
+		}
+		values[name] = enc
+	}
+	return nil
+}
+
+// viewer is what a caller may see of record values, from permissionsHeader:
+// plaintext ENCRYPTED values (pii:read) and, for fields with a masking policy,
+// the transform its permissions select. The zero viewer is the server's own
+// unprivileged view: ENCRYPTED values nulled, masking policies not applied.
+type viewer struct {
+	pii    bool
+	header http.Header // nil when masking policies are not applied
+}
+
+// viewerOf returns the viewer of a request with headers h.
+func viewerOf(h http.Header) viewer {
+	return viewer{pii: canReadPII(h), header: h}
+}
+
+// transform returns the masking transform fd's value gets for v.
+func (v viewer) transform(fd *schema.FieldDef) schema.MaskTransform {
+	if fd.Masking == nil || v.header == nil {
+		return schema.MaskNone
+	}
+	return fd.Masking.Transform(func(perm string) bool { return hasPermission(v.header, perm) })
+}
+
+// masks reports whether v sees any field of obj through a masking transform.
+func (v viewer) masks(obj *schema.ObjectDef) bool {
+	for i := range obj.Fields {
+		if v.transform(&obj.Fields[i]) != schema.MaskNone {
+			return true
+		}
+	}
+	return false
+}
+
+// revealRecord presents a result record to v, including inside expanded
+// lookups and reverse expands: ENCRYPTED fields are decrypted when v may read
+// PII and nulled otherwise, and masked fields are replaced by their
+// transform. Partial transforms of ENCRYPTED fields apply to the decrypted
+// value whatever v's pii:read, which is what makes them useful.
+func revealRecord(c *fieldcrypt.Cipher, obj *schema.ObjectDef, expands []hrqlpg.ExpandPlan, record *structpb.Struct, v viewer) error {
+	if record == nil {
+		return nil
+	}
+	for i := range obj.Fields {
+		fd := &obj.Fields[i]
+		val, ok := record.Fields[fd.APIName]
+		if !ok {
+			continue
+		}
+		mask := v.transform(fd)
+		if mask == schema.MaskHide {
+			record.Fields[fd.APIName] = structpb.NewNullValue()
+			continue
+		}
+		if idx := slices.IndexFunc(expands, func(ep hrqlpg.ExpandPlan) bool { return ep.FieldName == fd.APIName }); idx >= 0 {
+			ep := expands[idx]
+			if err := revealRecord(c, ep.Target, ep.Children, val.GetStructValue(), v); err != nil {
+				return err
+			}
+			continue
+		}
+		if !fd.IsEncrypted() {
+			if mask != schema.MaskNone {
+				record.Fields[fd.APIName] = maskedValue(mask, val.AsInterface())
+			}
+			continue
+		}
+		enc, isString := val.Kind.(*structpb.Value_StringValue)
+		if !isString || (mask == schema.MaskNone && !v.pii) {
+			record.Fields[fd.APIName] = structpb.NewNullValue()
+			continue
+		}
+		plain, err := c.Decrypt(enc.StringValue, fd.ID[:])
+		if err != nil {
+			return connect.NewError(connect.CodeInternal, fmt.Errorf("field %q: %w", fd.APIName, err))
+		}
+		var decoded any
+		if err := json.Unmarshal(plain, &decoded); err != nil {
+			return connect.NewError(connect.CodeInternal, fmt.Errorf("field %q: %w", fd.APIName, err))
+		}
+		if mask != schema.MaskNone {
+			record.Fields[fd.APIName] = maskedValue(mask, decoded)
+			continue
+		}
+		dv, err := structpb.NewValue(decoded)
+		if err != nil {
+			return connect.NewError(connect.CodeInternal, fmt.Errorf("field %q: %w", fd.APIName, err))
+		}
+		record.Fields[fd.APIName] = dv
+	}
+	for _, ep := range expands {
+		if !ep.Reverse {
+			continue
+		}
+		for _, val := range record.Fields[ep.FieldName].GetListValue().GetValues() {
+			if err := revealRecord(c, ep.Target, ep.Children, val.GetStructValue(), v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// maskedValue is the Value of a JSON value under a partial transform, which
+// only yields strings or null.
+func maskedValue(mask schema.MaskTransform, v any) *structpb.Value {
+	if s, ok := mask.Apply(v).(string); ok {
+		return structpb.NewStringValue(s)
+	}
+	return structpb.NewNullValue()
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	hrqlpg "github.com/atlekbai/schema_registry/internal/hrql/pg"
+)
+
+// stitchBatchExpands completes a list fetched with hrqlpg.ExpandBatch: for each
+// top-level expand it loads the referenced records with a single id = ANY(...)
+// query and replaces the foreign keys in records with them. Keys whose target
+// no longer exists become null, as with the LATERAL strategy. Reverse expands
+// are added as arrays (see stitchReverseExpand).
+func stitchBatchExpands(ctx context.Context, pool *pgxpool.Pool, plans []hrqlpg.ExpandPlan, records []*structpb.Struct) error {
+	for i := range plans {
+		ep := &plans[i]
+		if ep.Reverse {
+			if err := stitchReverseExpand(ctx, pool, ep, records); err != nil {
+				return err
+			}
+			continue
+		}
+
+		seen := make(map[string]bool)
+		var ids []string
+		for _, rec := range records {
+			id := rec.Fields[ep.FieldName].GetStringValue()
+			if id != "" && !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+
+		targets := make(map[string]*structpb.Value, len(ids))
+		if len(ids) > 0 {
+			sqlStr, args, err := hrqlpg.BuildExpandBatch(ep, ids)
+			if err != nil {
+				return fmt.Errorf("build expand %q: %w", ep.FieldName, err)
+			}
+			rows, err := pool.Query(ctx, sqlStr, args...)
+			if err != nil {
+				return fmt.Errorf("expand %q: %w", ep.FieldName, err)
+			}
+			for rows.Next() {
+				var data json.RawMessage
+				if err := rows.Scan(&data); err != nil {
+					rows.Close()
+					return fmt.Errorf("expand %q: %w", ep.FieldName, err)
+				}
+				st, err := rawJSONToStruct(data)
+				if err != nil {
+					rows.Close()
+					return fmt.Errorf("expand %q: %w", ep.FieldName, err)
+				}
+				targets[st.Fields["id"].GetStringValue()] = structpb.NewStructValue(st)
+			}
+			rows.Close()
+			if err := rows.Err(); err != nil {
+				return fmt.Errorf("expand %q: %w", ep.FieldName, err)
+			}
+		}
+
+		for _, rec := range records {
+			if v, ok := targets[rec.Fields[ep.FieldName].GetStringValue()]; ok {
+				// Each record gets its own copy: later passes (e.g. revealRecord) mutate in place.
+				rec.Fields[ep.FieldName] = proto.Clone(v).(*structpb.Value)
+			} else {
+				rec.Fields[ep.FieldName] = structpb.NewNullValue()
+			}
+		}
+	}
+	return nil
+}
+
+// referenceExpands replaces the expanded records in records with their ids
+// and returns them keyed by id, for ListRequest.expand_mode "referenced".
+// Nested expands are referenced too, so each record is returned once however
+// many records point at it; Reverse expands become arrays of ids. A record
+// reached through expands selecting different fields carries all of them.
+func referenceExpands(plans []hrqlpg.ExpandPlan, records []*structpb.Struct) map[string]*structpb.Struct {
+	included := make(map[string]*structpb.Struct)
+	for _, rec := range records {
+		referenceRecord(plans, rec, included)
+	}
+	return included
+}
+
+func referenceRecord(plans []hrqlpg.ExpandPlan, rec *structpb.Struct, included map[string]*structpb.Struct) {
+	for i := range plans {
+		ep := &plans[i]
+		v, ok := rec.Fields[ep.FieldName]
+		if !ok {
+			continue
+		}
+		if !ep.Reverse {
+			rec.Fields[ep.FieldName] = reference(ep, v, included)
+			continue
+		}
+		for j, item := range v.GetListValue().GetValues() {
+			v.GetListValue().Values[j] = reference(ep, item, included)
+		}
+	}
+}
+
+// reference adds the expanded record v to included and returns its id; null
+// (a dangling or hidden lookup) is returned as is.
+func reference(ep *hrqlpg.ExpandPlan, v *structpb.Value, included map[string]*structpb.Struct) *structpb.Value {
+	st := v.GetStructValue()
+	id := st.GetFields()["id"].GetStringValue()
+	if id == "" {
+		return v
+	}
+	referenceRecord(ep.Children, st, included)
+	if prev, ok := included[id]; ok {
+		for k, fv := range st.Fields {
+			if _, ok := prev.Fields[k]; !ok {
+				prev.Fields[k] = fv
+			}
+		}
+	} else {
+		included[id] = st
+	}
+	return structpb.NewStringValue(id)
+}
+
+// stitchReverseExpand sets a Reverse expand on every record to the array of
+// related records loaded for all of them in one query.
+func stitchReverseExpand(ctx context.Context, pool *pgxpool.Pool, ep *hrqlpg.ExpandPlan, records []*structpb.Struct) error {
+	if len(records) == 0 {
+		return nil
+	}
+	ids := make([]string, len(records))
+	for i, rec := range records {
+		ids[i] = rec.Fields["id"].GetStringValue()
+	}
+	sqlStr, args, err := hrqlpg.BuildReverseExpandBatch(ep, ids)
+	if err != nil {
+		return fmt.Errorf("build expand %q: %w", ep.FieldName, err)
+	}
+	rows, err := pool.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return fmt.Errorf("expand %q: %w", ep.FieldName, err)
+	}
+	defer rows.Close()
+
+	related := make(map[string]*structpb.Value, len(ids))
+	for rows.Next() {
+		var (
+			id   string
+			data json.RawMessage
+		)
+		if err := rows.Scan(&id, &data); err != nil {
+			return fmt.Errorf("expand %q: %w", ep.FieldName, err)
+		}
+		v := &structpb.Value{}
+		if err := protojson.Unmarshal(data, v); err != nil {
+			return fmt.Errorf("expand %q: %w", ep.FieldName, err)
+		}
+		related[id] = v
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("expand %q: %w", ep.FieldName, err)
+	}
+
+	for _, rec := range records {
+		v, ok := related[rec.Fields["id"].GetStringValue()]
+		if !ok {
+			v = structpb.NewListValue(&structpb.ListValue{})
+		}
+		rec.Fields[ep.FieldName] = v
+	}
+	return nil
+}
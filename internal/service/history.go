@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	registryv1 "github.com/atlekbai/schema_registry/gen/registry/v1"
+	hrqlpg "github.com/atlekbai/schema_registry/internal/hrql/pg"
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// GetRecordHistory returns the stored versions of a record, oldest first,
+// each with the fields it changed from the one before.
+func (s *RegistryService) GetRecordHistory(ctx context.Context, req *connect.Request[registryv1.GetRecordHistoryRequest]) (*connect.Response[registryv1.GetRecordHistoryResponse], error) {
+	msg := req.Msg
+	obj := s.cache.Get(msg.ObjectName)
+	if obj == nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("no object registered with api_name %q", msg.ObjectName))
+	}
+
+	id, err := uuid.Parse(msg.Id)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid ID format: %w", err))
+	}
+
+	rows, err := s.recordHistory(ctx, obj, id)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := viewerOf(req.Header())
+	resp := &registryv1.GetRecordHistoryResponse{Versions: make([]*registryv1.RecordVersion, 0, len(rows))}
+	var before map[string]any
+	var prev *structpb.Struct
+	for _, row := range rows {
+		v := &registryv1.RecordVersion{
+			Version:   row.Version,
+			Operation: row.Operation,
+			ChangedAt: row.ChangedAt.UTC().Format(time.RFC3339Nano),
+		}
+		if row.ActorID != nil {
+			v.ActorId = *row.ActorID
+		}
+		if row.RequestID != nil {
+			v.RequestId = *row.RequestID
+		}
+		resp.Versions = append(resp.Versions, v)
+		if row.Record == nil {
+			before, prev = nil, nil
+			continue
+		}
+
+		var after map[string]any
+		if err := json.Unmarshal(row.Record, &after); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("decode version %d: %w", row.Version, err))
+		}
+		record, err := rawJSONToStruct(row.Record)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("marshal result: %w", err))
+		}
+		if err := revealRecord(s.cipher, obj, nil, record, reader); err != nil {
+			return nil, err
+		}
+		v.Record = record
+		// The first version and the one after a delete have nothing to
+		// compare against.
+		if before != nil {
+			v.Changes = recordChanges(obj, before, after, prev, record, reader.pii)
+		}
+		before, prev = after, record
+	}
+	return connect.NewResponse(resp), nil
+}
+
+// recordChanges lists the fields that differ between two versions. They are
+// compared as stored, so a rewritten ENCRYPTED value shows as changed; when
+// the caller can see plaintext, rewrites of the same value are dropped.
+func recordChanges(obj *schema.ObjectDef, before, after map[string]any, prev, record *structpb.Struct, reveal bool) []*registryv1.FieldChange {
+	var changes []*registryv1.FieldChange
+	for _, c := range hrqlpg.FieldChanges(obj, before, after) {
+		old, cur := structValue(prev, c.Key), structValue(record, c.Key)
+		if reveal && c.Field.IsEncrypted() && proto.Equal(old, cur) {
+			continue
+		}
+		changes = append(changes, &registryv1.FieldChange{Field: c.Field.APIName, OldValue: old, NewValue: cur})
+	}
+	return changes
+}
+
+// structValue returns the value of key in record, or null when absent.
+func structValue(record *structpb.Struct, key string) *structpb.Value {
+	if v, ok := record.GetFields()[key]; ok {
+		return v
+	}
+	return structpb.NewNullValue()
+}
+
+// RevertRecord writes the field values of a prior version back to the record
+// through Update, so the revert is validated like any update and recorded as
+// a new version.
+func (s *RegistryService) RevertRecord(ctx context.Context, req *connect.Request[registryv1.RevertRecordRequest]) (*connect.Response[registryv1.RevertRecordResponse], error) {
+	msg := req.Msg
+	obj := s.cache.Get(msg.ObjectName)
+	if obj == nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("no object registered with api_name %q", msg.ObjectName))
+	}
+
+	id, err := uuid.Parse(msg.Id)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid ID format: %w", err))
+	}
+
+	rows, err := s.recordHistory(ctx, obj, id)
+	if err != nil {
+		return nil, err
+	}
+	var target *hrqlpg.HistoryRow
+	for i := range rows {
+		if rows[i].Version == msg.Version {
+			target = &rows[i]
+		}
+	}
+	if target == nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("record has no version %d", msg.Version))
+	}
+	if target.Record == nil {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("version %d is a delete and has no values to restore", msg.Version))
+	}
+
+	// ENCRYPTED values are restored from plaintext, which Update encrypts
+	// again; the caller only sees them if it could anyway.
+	record, err := rawJSONToStruct(target.Record)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("marshal result: %w", err))
+	}
+	if err := revealRecord(s.cipher, obj, nil, record, viewer{pii: true}); err != nil {
+		return nil, err
+	}
+	data, err := structpb.NewStruct(hrqlpg.RevertValues(obj, record.AsMap()))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("build revert: %w", err))
+	}
+
+	update := connect.NewRequest(&registryv1.UpdateRequest{
+		ObjectName:      msg.ObjectName,
+		Id:              msg.Id,
+		Data:            data,
+		ExpectedVersion: msg.ExpectedVersion,
+		DryRun:          msg.DryRun,
+	})
+	for k, v := range req.Header() {
+		update.Header()[k] = v
+	}
+	updated, err := s.Update(ctx, update)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := connect.NewResponse(&registryv1.RevertRecordResponse{Record: updated.Msg.Record, DryRun: updated.Msg.DryRun})
+	for k, v := range updated.Header() {
+		resp.Header()[k] = v
+	}
+	return resp, nil
+}
+
+// recordHistory reads the stored versions of obj's record id, oldest first.
+// A record with no history is reported as not found.
+func (s *RegistryService) recordHistory(ctx context.Context, obj *schema.ObjectDef, id uuid.UUID) ([]hrqlpg.HistoryRow, error) {
+	sqlStr, args, err := hrqlpg.BuildRecordHistory(obj, id)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("build query: %w", err))
+	}
+	rows, err := s.pool.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("query history: %w", err))
+	}
+	history, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (hrqlpg.HistoryRow, error) {
+		var h hrqlpg.HistoryRow
+		err := row.Scan(h.ScanDest()...)
+		return h, err
+	})
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("query history: %w", err))
+	}
+	if len(history) == 0 {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("record not found"))
+	}
+	return history, nil
+}
@@ -0,0 +1,3044 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	registryv1 "github.com/atlekbai/schema_registry/gen/registry/v1"
+	"github.com/atlekbai/schema_registry/gen/registry/v1/registryv1connect"
+	"github.com/atlekbai/schema_registry/internal/db"
+	hrqlpg "github.com/atlekbai/schema_registry/internal/hrql/pg"
+	"github.com/atlekbai/schema_registry/internal/ltreeutil"
+	"github.com/atlekbai/schema_registry/internal/metrics"
+	"github.com/atlekbai/schema_registry/internal/retention"
+	"github.com/atlekbai/schema_registry/internal/schema"
+	"github.com/atlekbai/schema_registry/internal/service"
+	"github.com/atlekbai/schema_registry/internal/testutil"
+	"github.com/atlekbai/schema_registry/internal/webhook"
+)
+
+// --- Test: org functions against the seeded hierarchy ---
+
+func TestIntegrationReports(t *testing.T) {
+	env := testutil.NewEnv(t)
+
+	resp := env.Query(t, fmt.Sprintf(`reports("%s") | count`, testutil.Org.CEO), "")
+	if resp.Scalar == nil || *resp.Scalar != 4 {
+		t.Errorf("reports(CEO) | count = %v, want 4", resp.Scalar)
+	}
+
+	ids := env.QueryIDs(t, fmt.Sprintf(`reports("%s", 1)`, testutil.Org.CTO), "")
+	if len(ids) != 2 {
+		t.Errorf("direct reports of CTO = %v, want 2 records", ids)
+	}
+}
+
+func TestIntegrationReportsTo(t *testing.T) {
+	env := testutil.NewEnv(t)
+
+	resp := env.Query(t, fmt.Sprintf(`reports_to(self, "%s")`, testutil.Org.CEO), testutil.Org.Engineer1)
+	if resp.ReportsTo == nil || !*resp.ReportsTo {
+		t.Errorf("reports_to(Engineer1, CEO) = %v, want true", resp.ReportsTo)
+	}
+	resp = env.Query(t, fmt.Sprintf(`reports_to(self, "%s")`, testutil.Org.CFO), testutil.Org.Engineer1)
+	if resp.ReportsTo == nil || *resp.ReportsTo {
+		t.Errorf("reports_to(Engineer1, CFO) = %v, want false", resp.ReportsTo)
+	}
+}
+
+func TestIntegrationCreateGet(t *testing.T) {
+	env := testutil.NewEnv(t)
+
+	created := env.Create(t, "departments", map[string]any{
+		"title":        "Research",
+		"organization": testutil.Org.Organization,
+		"parent":       testutil.Org.Engineering,
+	})
+	id := created.Fields["id"].GetStringValue()
+	if id == "" {
+		t.Fatalf("created record has no id: %v", created)
+	}
+
+	got := env.Get(t, "departments", id)
+	if title := got.Fields["title"].GetStringValue(); title != "Research" {
+		t.Errorf("title = %q, want %q", title, "Research")
+	}
+}
+
+// --- Test: paging over NULL sort values ---
+
+func TestIntegrationListNullOrder(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	data, _ := structpb.NewStruct(map[string]any{"end_date": "2030-01-01"})
+	if _, err := env.Registry.Update(ctx, connect.NewRequest(&registryv1.UpdateRequest{ObjectName: "employees", Id: testutil.Org.CFO, Data: data})); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	for order, cfoAt := range map[string]int{"end_date.desc": 0, "end_date.desc.nullsfirst": 4} {
+		var ids []string
+		req := &registryv1.ListRequest{ObjectName: "employees", Limit: 2, Order: order}
+		for {
+			resp, err := env.Registry.List(ctx, connect.NewRequest(req))
+			if err != nil {
+				t.Fatalf("%s: list: %v", order, err)
+			}
+			for _, r := range resp.Msg.Results {
+				ids = append(ids, r.Fields["id"].GetStringValue())
+			}
+			if resp.Msg.NextCursor == nil {
+				break
+			}
+			req.Cursor = *resp.Msg.NextCursor
+		}
+		if len(ids) != 5 || len(slices.Compact(slices.Sorted(slices.Values(ids)))) != 5 {
+			t.Errorf("%s: pages returned %v, want the 5 employees once each", order, ids)
+		} else if ids[cfoAt] != testutil.Org.CFO {
+			t.Errorf("%s: CFO at %d, want %d", order, slices.Index(ids, testutil.Org.CFO), cfoAt)
+		}
+	}
+}
+
+// --- Test: snapshot List pagination ---
+
+func TestIntegrationListSnapshot(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	req := &registryv1.ListRequest{ObjectName: "departments", Limit: 1, Snapshot: true}
+	resp, err := env.Registry.List(ctx, connect.NewRequest(req))
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	want := resp.Msg.TotalCount
+	seen := len(resp.Msg.Results)
+
+	// Rows written after the first page must not show up in later pages.
+	env.Create(t, "departments", map[string]any{
+		"title":        "Late",
+		"organization": testutil.Org.Organization,
+	})
+
+	for resp.Msg.NextCursor != nil {
+		req := &registryv1.ListRequest{ObjectName: "departments", Limit: 1, Cursor: *resp.Msg.NextCursor}
+		resp, err = env.Registry.List(ctx, connect.NewRequest(req))
+		if err != nil {
+			t.Fatalf("list page %d: %v", seen+1, err)
+		}
+		if resp.Msg.TotalCount != want {
+			t.Errorf("page %d total_count = %d, want %d", seen+1, resp.Msg.TotalCount, want)
+		}
+		seen += len(resp.Msg.Results)
+	}
+	if int64(seen) != want {
+		t.Errorf("snapshot pages returned %d rows, want %d", seen, want)
+	}
+}
+
+func TestIntegrationListRaw(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	req := &registryv1.ListRequest{ObjectName: "employees", Limit: 2, Raw: true}
+	resp, err := env.Registry.List(ctx, connect.NewRequest(req))
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(resp.Msg.Results) != 2 {
+		t.Errorf("raw list returned %d rows, want 2", len(resp.Msg.Results))
+	}
+	if resp.Msg.TotalCount != 0 || resp.Msg.NextCursor != nil {
+		t.Errorf("raw list has total_count %d and cursor %v, want neither", resp.Msg.TotalCount, resp.Msg.NextCursor)
+	}
+
+	req = &registryv1.ListRequest{ObjectName: "employees", Raw: true, Snapshot: true}
+	if _, err := env.Registry.List(ctx, connect.NewRequest(req)); connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("raw snapshot list: expected INVALID_ARGUMENT, got %v", err)
+	}
+}
+
+// --- Test: compare-and-swap writes ---
+
+func TestIntegrationVersionedWrites(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	obj, err := env.Metadata.CreateObject(ctx, connect.NewRequest(&registryv1.CreateObjectRequest{
+		ApiName: "badges", Title: "Badge", PluralTitle: "Badges",
+	}))
+	if err != nil {
+		t.Fatalf("create object: %v", err)
+	}
+	if _, err := env.Metadata.CreateField(ctx, connect.NewRequest(&registryv1.CreateFieldRequest{
+		ObjectId: obj.Msg.Object.Id, ApiName: "holder", Title: "Holder", Type: "TEXT",
+	})); err != nil {
+		t.Fatalf("create field: %v", err)
+	}
+	id := env.Create(t, "badges", map[string]any{"holder": "Ada"}).Fields["id"].GetStringValue()
+
+	update := func(holder string, expected int64, ifMatch string) (*registryv1.UpdateResponse, error) {
+		data, _ := structpb.NewStruct(map[string]any{"holder": holder})
+		req := connect.NewRequest(&registryv1.UpdateRequest{ObjectName: "badges", Id: id, Data: data, ExpectedVersion: expected})
+		if ifMatch != "" {
+			req.Header().Set("If-Match", ifMatch)
+		}
+		resp, err := env.Registry.Update(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Msg, nil
+	}
+	// conflict checks err is FAILED_PRECONDITION with a VersionConflict
+	// detail reporting expected and current.
+	conflict := func(what string, err error, expected, current int64) {
+		t.Helper()
+		if connect.CodeOf(err) != connect.CodeFailedPrecondition {
+			t.Errorf("%s: error %v, want FAILED_PRECONDITION", what, err)
+			return
+		}
+		var cerr *connect.Error
+		errors.As(err, &cerr)
+		for _, d := range cerr.Details() {
+			if v, derr := d.Value(); derr == nil {
+				if vc, ok := v.(*registryv1.VersionConflict); ok {
+					if vc.Id != id || vc.ExpectedVersion != expected || vc.CurrentVersion != current {
+						t.Errorf("%s: conflict %v, want expected %d and current %d", what, vc, expected, current)
+					}
+					return
+				}
+			}
+		}
+		t.Errorf("%s: no VersionConflict detail", what)
+	}
+
+	resp, err := update("Grace", 1, "")
+	if err != nil {
+		t.Fatalf("update at version 1: %v", err)
+	}
+	if v := resp.Record.Fields["version"].GetNumberValue(); v != 2 {
+		t.Errorf("version after update = %v, want 2", v)
+	}
+	_, err = update("Edsger", 1, "")
+	conflict("stale expected_version", err, 1, 2)
+	if got := env.Get(t, "badges", id).Fields["holder"].GetStringValue(); got != "Grace" {
+		t.Errorf("holder after a stale update = %q, want Grace", got)
+	}
+
+	// An ETag from a read round-trips through If-Match.
+	_, err = update("Edsger", 0, `"1"`)
+	conflict("stale If-Match", err, 1, 2)
+	if _, err := update("Edsger", 0, `W/"2"`); err != nil {
+		t.Fatalf("update with If-Match 2: %v", err)
+	}
+	if _, err := update("Alan", 0, "abc"); connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("malformed If-Match: error %v, want INVALID_ARGUMENT", err)
+	}
+	// The request field wins over the header.
+	_, err = update("Alan", 2, `"3"`)
+	conflict("expected_version over If-Match", err, 2, 3)
+
+	del := func(expected int64, ifMatch string) error {
+		req := connect.NewRequest(&registryv1.DeleteRequest{ObjectName: "badges", Id: id, ExpectedVersion: expected})
+		if ifMatch != "" {
+			req.Header().Set("If-Match", ifMatch)
+		}
+		_, err := env.Registry.Delete(ctx, req)
+		return err
+	}
+	conflict("stale delete", del(2, ""), 2, 3)
+	conflict("stale delete If-Match", del(0, `"1"`), 1, 3)
+	if err := del(0, `"3"`); err != nil {
+		t.Fatalf("delete with If-Match 3: %v", err)
+	}
+	if err := del(3, ""); connect.CodeOf(err) != connect.CodeNotFound {
+		t.Errorf("guarded delete of a deleted record: error %v, want NOT_FOUND", err)
+	}
+}
+
+// --- Test: dry-run writes ---
+
+func TestIntegrationDryRunWrites(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	badges, err := env.Metadata.CreateObject(ctx, connect.NewRequest(&registryv1.CreateObjectRequest{
+		ApiName: "badges", Title: "Badge", PluralTitle: "Badges",
+	}))
+	if err != nil {
+		t.Fatalf("create object: %v", err)
+	}
+	if _, err := env.Metadata.CreateField(ctx, connect.NewRequest(&registryv1.CreateFieldRequest{
+		ObjectId: badges.Msg.Object.Id, ApiName: "holder", Title: "Holder", Type: "TEXT",
+	})); err != nil {
+		t.Fatalf("create field: %v", err)
+	}
+	passes, err := env.Metadata.CreateObject(ctx, connect.NewRequest(&registryv1.CreateObjectRequest{
+		ApiName: "passes", Title: "Pass", PluralTitle: "Passes",
+	}))
+	if err != nil {
+		t.Fatalf("create object: %v", err)
+	}
+	if _, err := env.Metadata.CreateField(ctx, connect.NewRequest(&registryv1.CreateFieldRequest{
+		ObjectId: passes.Msg.Object.Id, ApiName: "badge", Title: "Badge", Type: "LOOKUP",
+		LookupObjectId: badges.Msg.Object.Id, TypeConfig: `{"denormalize_label": "holder"}`,
+	})); err != nil {
+		t.Fatalf("create lookup: %v", err)
+	}
+	id := env.Create(t, "badges", map[string]any{"holder": "Ada"}).Fields["id"].GetStringValue()
+	pass := env.Create(t, "passes", map[string]any{"badge": id}).Fields["id"].GetStringValue()
+
+	// unchanged checks the badge, its history and the pass's label are as
+	// before the dry runs.
+	unchanged := func(what string) {
+		t.Helper()
+		got := env.Get(t, "badges", id).Fields
+		if got["holder"].GetStringValue() != "Ada" || got["version"].GetNumberValue() != 1 {
+			t.Errorf("%s: badge = %v, want Ada at version 1", what, got)
+		}
+		history, err := env.Registry.GetRecordHistory(ctx, connect.NewRequest(&registryv1.GetRecordHistoryRequest{ObjectName: "badges", Id: id}))
+		if err != nil {
+			t.Fatalf("history: %v", err)
+		}
+		if n := len(history.Msg.Versions); n != 1 {
+			t.Errorf("%s: %d history versions, want 1", what, n)
+		}
+		if label := env.Get(t, "passes", pass).Fields["badge__label"].GetStringValue(); label != "Ada" {
+			t.Errorf("%s: pass label = %q, want Ada", what, label)
+		}
+	}
+
+	data, _ := structpb.NewStruct(map[string]any{"holder": "Grace"})
+	created, err := env.Registry.Create(ctx, connect.NewRequest(&registryv1.CreateRequest{ObjectName: "badges", Data: data, DryRun: true}))
+	if err != nil {
+		t.Fatalf("dry-run create: %v", err)
+	}
+	rec := created.Msg.Record.Fields
+	if !created.Msg.DryRun || rec["id"].GetStringValue() == "" || rec["created_at"].GetStringValue() == "" || rec["version"].GetNumberValue() != 1 {
+		t.Errorf("dry-run create = %v, want database defaults and version 1", created.Msg)
+	}
+	if _, err := env.Registry.Get(ctx, connect.NewRequest(&registryv1.GetRequest{ObjectName: "badges", Id: rec["id"].GetStringValue()})); connect.CodeOf(err) != connect.CodeNotFound {
+		t.Errorf("dry-run created record: get error %v, want NOT_FOUND", err)
+	}
+
+	updated, err := env.Registry.Update(ctx, connect.NewRequest(&registryv1.UpdateRequest{ObjectName: "badges", Id: id, Data: data, DryRun: true}))
+	if err != nil {
+		t.Fatalf("dry-run update: %v", err)
+	}
+	if rec := updated.Msg.Record.Fields; rec["holder"].GetStringValue() != "Grace" || rec["version"].GetNumberValue() != 2 {
+		t.Errorf("dry-run update = %v, want Grace at version 2", rec)
+	}
+	unchanged("dry-run update")
+
+	deleted, err := env.Registry.Delete(ctx, connect.NewRequest(&registryv1.DeleteRequest{ObjectName: "badges", Id: id, DryRun: true}))
+	if err != nil {
+		t.Fatalf("dry-run delete: %v", err)
+	}
+	if deleted.Msg.Record.Fields["id"].GetStringValue() != id {
+		t.Errorf("dry-run delete record = %v, want the badge", deleted.Msg.Record)
+	}
+	unchanged("dry-run delete")
+
+	// Deferred constraints are checked at once, so a dry run reports what
+	// the commit would.
+	if _, err := env.Pool.Exec(ctx, `ALTER TABLE core.departments ADD CONSTRAINT uq_departments_title UNIQUE ("title") DEFERRABLE INITIALLY DEFERRED`); err != nil {
+		t.Fatalf("add deferred constraint: %v", err)
+	}
+	data, _ = structpb.NewStruct(map[string]any{"title": "Finance", "organization": testutil.Org.Organization})
+	_, err = env.Registry.Create(ctx, connect.NewRequest(&registryv1.CreateRequest{ObjectName: "departments", Data: data, DryRun: true}))
+	if connect.CodeOf(err) != connect.CodeAlreadyExists {
+		t.Errorf("dry-run create violating a deferred constraint: error %v, want ALREADY_EXISTS", err)
+	}
+}
+
+// --- Test: parallel scan partitions ---
+
+func TestIntegrationSplitList(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	all, err := env.Registry.List(ctx, connect.NewRequest(&registryv1.ListRequest{ObjectName: "employees", Limit: 200}))
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+
+	split, err := env.Registry.SplitList(ctx, connect.NewRequest(&registryv1.SplitListRequest{ObjectName: "employees", Partitions: 3}))
+	if err != nil {
+		t.Fatalf("split: %v", err)
+	}
+	if n := len(split.Msg.Partitions); n < 2 || n > 3 {
+		t.Fatalf("got %d partitions, want 2 or 3", n)
+	}
+
+	// Reading every partition page by page returns each record exactly once.
+	seen := make(map[string]int)
+	for _, p := range split.Msg.Partitions {
+		cursor := p.Cursor
+		for {
+			resp, err := env.Registry.List(ctx, connect.NewRequest(&registryv1.ListRequest{ObjectName: "employees", Limit: 1, Cursor: cursor}))
+			if err != nil {
+				t.Fatalf("list partition %s: %v", p.Until, err)
+			}
+			for _, r := range resp.Msg.Results {
+				seen[r.Fields["id"].GetStringValue()]++
+			}
+			if resp.Msg.NextCursor == nil {
+				break
+			}
+			cursor = *resp.Msg.NextCursor
+		}
+	}
+	if len(seen) != len(all.Msg.Results) {
+		t.Errorf("partitions returned %d records, want %d", len(seen), len(all.Msg.Results))
+	}
+	for id, n := range seen {
+		if n != 1 {
+			t.Errorf("record %s returned %d times", id, n)
+		}
+	}
+}
+
+// --- Test: object deprecation ---
+
+func TestIntegrationObjectDeprecation(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	obj, err := env.Metadata.CreateObject(ctx, connect.NewRequest(&registryv1.CreateObjectRequest{
+		ApiName: "badges", Title: "Badge", PluralTitle: "Badges",
+	}))
+	if err != nil {
+		t.Fatalf("create object: %v", err)
+	}
+
+	_, err = env.Metadata.UpdateObject(ctx, connect.NewRequest(&registryv1.UpdateObjectRequest{
+		Id:          obj.Msg.Object.Id,
+		Deprecation: &registryv1.ObjectDeprecation{SunsetAt: "2000-01-01T00:00:00Z"},
+	}))
+	if connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("sunset before deprecation: expected INVALID_ARGUMENT, got %v", err)
+	}
+
+	updated, err := env.Metadata.UpdateObject(ctx, connect.NewRequest(&registryv1.UpdateObjectRequest{
+		Id: obj.Msg.Object.Id,
+		Deprecation: &registryv1.ObjectDeprecation{
+			DeprecatedAt: "2026-01-01T00:00:00Z",
+			SunsetAt:     "2026-06-30T00:00:00Z",
+			Replacement:  "positions",
+		},
+	}))
+	if err != nil {
+		t.Fatalf("deprecate: %v", err)
+	}
+	if got := updated.Msg.Object.Deprecation.GetSunsetAt(); got != "2026-06-30T00:00:00Z" {
+		t.Errorf("sunset_at = %q", got)
+	}
+
+	resp, err := env.Registry.List(ctx, connect.NewRequest(&registryv1.ListRequest{ObjectName: "badges"}))
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if got := resp.Header().Get("Deprecation"); got != "@1767225600" {
+		t.Errorf("Deprecation = %q", got)
+	}
+	if got := resp.Header().Get("Sunset"); got != "Tue, 30 Jun 2026 00:00:00 GMT" {
+		t.Errorf("Sunset = %q", got)
+	}
+	if got := resp.Header().Get("Link"); got != `</api/positions>; rel="successor-version"` {
+		t.Errorf("Link = %q", got)
+	}
+	if !strings.Contains(resp.Msg.Warning, `migrate to "positions"`) {
+		t.Errorf("warning = %q", resp.Msg.Warning)
+	}
+
+	_, err = env.Metadata.UpdateObject(ctx, connect.NewRequest(&registryv1.UpdateObjectRequest{Id: obj.Msg.Object.Id, ClearDeprecation: true}))
+	if err != nil {
+		t.Fatalf("undeprecate: %v", err)
+	}
+	resp, err = env.Registry.List(ctx, connect.NewRequest(&registryv1.ListRequest{ObjectName: "badges"}))
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if resp.Header().Get("Deprecation") != "" || resp.Msg.Warning != "" {
+		t.Errorf("undeprecated object still warns: %q", resp.Msg.Warning)
+	}
+}
+
+// --- Test: object renames ---
+
+func TestIntegrationRenameObject(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	obj, err := env.Metadata.CreateObject(ctx, connect.NewRequest(&registryv1.CreateObjectRequest{
+		ApiName: "badges", Title: "Badge", PluralTitle: "Badges",
+	}))
+	if err != nil {
+		t.Fatalf("create object: %v", err)
+	}
+	id := obj.Msg.Object.Id
+	env.Create(t, "badges", map[string]any{})
+
+	renamed, err := env.Metadata.RenameObject(ctx, connect.NewRequest(&registryv1.RenameObjectRequest{Id: id, ApiName: "credentials"}))
+	if err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if got := renamed.Msg.Object; got.ApiName != "credentials" || !slices.Equal(got.Aliases, []string{"badges"}) {
+		t.Errorf("renamed object = %q aliases %v", got.ApiName, got.Aliases)
+	}
+
+	resp, err := env.Registry.List(ctx, connect.NewRequest(&registryv1.ListRequest{ObjectName: "badges"}))
+	if err != nil {
+		t.Fatalf("list by alias: %v", err)
+	}
+	if len(resp.Msg.Results) != 1 {
+		t.Errorf("list by alias returned %d rows, want 1", len(resp.Msg.Results))
+	}
+	if resp.Header().Get("Deprecation") == "" || !strings.Contains(resp.Msg.Warning, `renamed to "credentials"`) {
+		t.Errorf("list by alias: Deprecation %q, warning %q", resp.Header().Get("Deprecation"), resp.Msg.Warning)
+	}
+	if got := resp.Header().Get("Link"); got != `</api/credentials>; rel="successor-version"` {
+		t.Errorf("Link = %q", got)
+	}
+	resp, err = env.Registry.List(ctx, connect.NewRequest(&registryv1.ListRequest{ObjectName: "credentials"}))
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if resp.Header().Get("Deprecation") != "" || resp.Msg.Warning != "" {
+		t.Errorf("list by current name warns: %q", resp.Msg.Warning)
+	}
+	if ids := env.QueryIDs(t, "badges", ""); len(ids) != 1 {
+		t.Errorf("HRQL by alias returned %v, want 1 record", ids)
+	}
+
+	// An alias is taken for other objects, but its object may take it back.
+	_, err = env.Metadata.CreateObject(ctx, connect.NewRequest(&registryv1.CreateObjectRequest{
+		ApiName: "badges", Title: "Badge", PluralTitle: "Badges",
+	}))
+	if connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("create object named after an alias: expected INVALID_ARGUMENT, got %v", err)
+	}
+	back, err := env.Metadata.RenameObject(ctx, connect.NewRequest(&registryv1.RenameObjectRequest{Id: id, ApiName: "badges"}))
+	if err != nil {
+		t.Fatalf("rename back: %v", err)
+	}
+	if got := back.Msg.Object; got.ApiName != "badges" || !slices.Equal(got.Aliases, []string{"credentials"}) {
+		t.Errorf("renamed back object = %q aliases %v", got.ApiName, got.Aliases)
+	}
+
+	_, err = env.Metadata.RenameObject(ctx, connect.NewRequest(&registryv1.RenameObjectRequest{
+		Id: env.Cache.Get("employees").ID.String(), ApiName: "staff",
+	}))
+	if connect.CodeOf(err) != connect.CodeFailedPrecondition {
+		t.Errorf("rename standard object: expected FAILED_PRECONDITION, got %v", err)
+	}
+}
+
+// --- Test: field rename ---
+
+func TestIntegrationRenameField(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	obj, err := env.Metadata.CreateObject(ctx, connect.NewRequest(&registryv1.CreateObjectRequest{
+		ApiName: "badges", Title: "Badge", PluralTitle: "Badges",
+	}))
+	if err != nil {
+		t.Fatalf("create object: %v", err)
+	}
+	objID := obj.Msg.Object.Id
+	field, err := env.Metadata.CreateField(ctx, connect.NewRequest(&registryv1.CreateFieldRequest{
+		ObjectId: objID, ApiName: "holder", Title: "Holder", Type: "TEXT",
+	}))
+	if err != nil {
+		t.Fatalf("create field: %v", err)
+	}
+	fieldID := field.Msg.Field.Id
+	if _, err := env.Metadata.UpdateObject(ctx, connect.NewRequest(&registryv1.UpdateObjectRequest{
+		Id: objID, DefaultOrder: new("holder.desc"), DisplayTemplate: new("Badge of {holder}"),
+	})); err != nil {
+		t.Fatalf("update object: %v", err)
+	}
+	ada := env.Create(t, "badges", map[string]any{"holder": "Ada"}).Fields["id"].GetStringValue()
+	env.Create(t, "badges", map[string]any{"holder": "Grace"})
+	env.Create(t, "badges", map[string]any{})
+
+	renamed, err := env.Metadata.RenameField(ctx, connect.NewRequest(&registryv1.RenameFieldRequest{
+		ObjectId: objID, Id: fieldID, ApiName: "owner",
+	}))
+	if err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if got := renamed.Msg.Field; got.ApiName != "owner" || !slices.Equal(got.Aliases, []string{"holder"}) || renamed.Msg.Records != 2 {
+		t.Errorf("renamed field = %q aliases %v, %d records", got.ApiName, got.Aliases, renamed.Msg.Records)
+	}
+
+	// Values moved to the new key; the version is untouched.
+	if r := env.Get(t, "badges", ada); r.Fields["owner"].GetStringValue() != "Ada" || r.Fields["holder"] != nil || r.Fields["version"].GetNumberValue() != 1 {
+		t.Errorf("record after rename = %v", r)
+	}
+	var stray int
+	if err := env.Pool.QueryRow(ctx, `SELECT count(*) FROM metadata.records WHERE object_id = $1 AND data ? 'holder'`, objID).Scan(&stray); err != nil || stray != 0 {
+		t.Errorf("documents still under the old key: %d (%v)", stray, err)
+	}
+	history, err := env.Registry.GetRecordHistory(ctx, connect.NewRequest(&registryv1.GetRecordHistoryRequest{ObjectName: "badges", Id: ada}))
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if v := history.Msg.Versions; len(v) != 1 || v[0].Record.Fields["owner"].GetStringValue() != "Ada" {
+		t.Errorf("history after rename = %v", v)
+	}
+
+	// Settings naming the field follow it.
+	got := env.Cache.Get("badges")
+	if got.DefaultOrder != "owner.desc" || got.DisplayTemplate != "Badge of {owner}" {
+		t.Errorf("default_order %q, display_template %q", got.DefaultOrder, got.DisplayTemplate)
+	}
+
+	// The old name keeps working in select, filters, order, writes and HRQL.
+	resp, err := env.Registry.List(ctx, connect.NewRequest(&registryv1.ListRequest{
+		ObjectName: "badges", Select: "holder", Order: "holder", Filters: map[string]string{"holder": "eq.Ada"},
+	}))
+	if err != nil {
+		t.Fatalf("list by alias: %v", err)
+	}
+	if len(resp.Msg.Results) != 1 || resp.Msg.Results[0].Fields["owner"].GetStringValue() != "Ada" {
+		t.Errorf("list by alias = %v", resp.Msg.Results)
+	}
+	written := env.Create(t, "badges", map[string]any{"holder": "Linus"})
+	if written.Fields["owner"].GetStringValue() != "Linus" {
+		t.Errorf("create by alias = %v", written)
+	}
+	q := env.Query(t, `badges | where(.holder == "Ada") | count`, "")
+	if q.Scalar == nil || *q.Scalar != 1 || len(q.Warnings) != 1 || q.Warnings[0].Code != "renamed_field" {
+		t.Errorf("HRQL by alias: count %v, warnings %v", q.Scalar, q.Warnings)
+	}
+
+	// The alias is taken for other fields, but its field may take it back.
+	_, err = env.Metadata.CreateField(ctx, connect.NewRequest(&registryv1.CreateFieldRequest{
+		ObjectId: objID, ApiName: "holder", Title: "Holder", Type: "TEXT",
+	}))
+	if connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("create field named after an alias: expected INVALID_ARGUMENT, got %v", err)
+	}
+	back, err := env.Metadata.RenameField(ctx, connect.NewRequest(&registryv1.RenameFieldRequest{
+		ObjectId: objID, Id: fieldID, ApiName: "holder",
+	}))
+	if err != nil {
+		t.Fatalf("rename back: %v", err)
+	}
+	if got := back.Msg.Field; got.ApiName != "holder" || !slices.Equal(got.Aliases, []string{"owner"}) || back.Msg.Records != 3 {
+		t.Errorf("renamed back field = %q aliases %v, %d records", got.ApiName, got.Aliases, back.Msg.Records)
+	}
+
+	emp := env.Cache.Get("employees")
+	_, err = env.Metadata.RenameField(ctx, connect.NewRequest(&registryv1.RenameFieldRequest{
+		ObjectId: emp.ID.String(), Id: emp.FieldsByAPIName["start_date"].ID.String(), ApiName: "hired_on",
+	}))
+	if connect.CodeOf(err) != connect.CodeFailedPrecondition {
+		t.Errorf("rename standard field: expected FAILED_PRECONDITION, got %v", err)
+	}
+}
+
+// --- Test: data limits ---
+
+func TestIntegrationDataLimits(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	obj, err := env.Metadata.CreateObject(ctx, connect.NewRequest(&registryv1.CreateObjectRequest{
+		ApiName: "badges", Title: "Badge", PluralTitle: "Badges", MaxCustomFields: 2, MaxDataBytes: 100,
+	}))
+	if err != nil {
+		t.Fatalf("create object: %v", err)
+	}
+	id := obj.Msg.Object.Id
+	createField := func(name string) (*connect.Response[registryv1.CreateFieldResponse], error) {
+		return env.Metadata.CreateField(ctx, connect.NewRequest(&registryv1.CreateFieldRequest{
+			ObjectId: id, ApiName: name, Title: name, Type: "TEXT",
+		}))
+	}
+	for _, f := range []struct{ name, warning string }{{"holder", ""}, {"note", "2 of its 2 custom fields"}} {
+		resp, err := createField(f.name)
+		if err != nil {
+			t.Fatalf("create field %s: %v", f.name, err)
+		}
+		if got := resp.Msg.Warning; (f.warning == "") != (got == "") || !strings.Contains(got, f.warning) {
+			t.Errorf("field %s: warning %q, want %q", f.name, got, f.warning)
+		}
+	}
+	if _, err := createField("extra"); connect.CodeOf(err) != connect.CodeResourceExhausted {
+		t.Errorf("field over the limit: expected RESOURCE_EXHAUSTED, got %v", err)
+	}
+
+	rec := env.Create(t, "badges", map[string]any{"holder": "Ada"})
+	data, _ := structpb.NewStruct(map[string]any{"note": strings.Repeat("x", 100)})
+	_, err = env.Registry.Update(ctx, connect.NewRequest(&registryv1.UpdateRequest{
+		ObjectName: "badges", Id: rec.Fields["id"].GetStringValue(), Data: data,
+	}))
+	if connect.CodeOf(err) != connect.CodeInvalidArgument || !strings.Contains(err.Error(), "byte limit") {
+		t.Errorf("oversized update: expected INVALID_ARGUMENT, got %v", err)
+	}
+
+	usage, err := env.Metadata.GetObjectUsage(ctx, connect.NewRequest(&registryv1.GetObjectUsageRequest{Id: id}))
+	if err != nil {
+		t.Fatalf("usage: %v", err)
+	}
+	u := usage.Msg
+	if u.CustomFields != 2 || u.MaxCustomFields != 2 || u.Records != 1 || u.LargestDataBytes != int64(len(`{"holder": "Ada"}`)) || u.MaxDataBytes != 100 {
+		t.Errorf("usage = %v", u)
+	}
+	if len(u.Warnings) != 1 {
+		t.Errorf("warnings = %v, want the custom field one", u.Warnings)
+	}
+}
+
+// --- Test: exact count threshold override ---
+
+func TestIntegrationExactCountThreshold(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	obj, err := env.Metadata.CreateObject(ctx, connect.NewRequest(&registryv1.CreateObjectRequest{
+		ApiName: "badges", Title: "Badge", PluralTitle: "Badges", ExactCountThreshold: 10,
+	}))
+	if err != nil {
+		t.Fatalf("create object: %v", err)
+	}
+	if got := obj.Msg.Object.ExactCountThreshold; got != 10 {
+		t.Errorf("created threshold = %d, want 10", got)
+	}
+	if got := env.Cache.Get("badges").ExactCountThreshold; got != 10 {
+		t.Errorf("cached threshold = %d, want 10", got)
+	}
+
+	upd, err := env.Metadata.UpdateObject(ctx, connect.NewRequest(&registryv1.UpdateObjectRequest{
+		Id: obj.Msg.Object.Id, ExactCountThreshold: new(int64(0)),
+	}))
+	if err != nil {
+		t.Fatalf("update object: %v", err)
+	}
+	if got := upd.Msg.Object.ExactCountThreshold; got != 0 {
+		t.Errorf("cleared threshold = %d, want 0 (server default)", got)
+	}
+}
+
+// --- Test: bulk update by filter ---
+
+func TestIntegrationUpdateWhere(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	obj, err := env.Metadata.CreateObject(ctx, connect.NewRequest(&registryv1.CreateObjectRequest{
+		ApiName: "badges", Title: "Badge", PluralTitle: "Badges",
+	}))
+	if err != nil {
+		t.Fatalf("create object: %v", err)
+	}
+	for _, name := range []string{"holder", "status"} {
+		if _, err := env.Metadata.CreateField(ctx, connect.NewRequest(&registryv1.CreateFieldRequest{
+			ObjectId: obj.Msg.Object.Id, ApiName: name, Title: name, Type: "TEXT",
+		})); err != nil {
+			t.Fatalf("create field %s: %v", name, err)
+		}
+	}
+	for _, holder := range []string{"Ada", "Grace", "Linus"} {
+		status := "active"
+		if holder == "Linus" {
+			status = "lost"
+		}
+		env.Create(t, "badges", map[string]any{"holder": holder, "status": status})
+	}
+
+	patch, _ := structpb.NewStruct(map[string]any{"status": "revoked"})
+	updateWhere := func(m *registryv1.UpdateWhereRequest) (*registryv1.UpdateWhereResponse, error) {
+		m.ObjectName, m.Data = "badges", patch
+		resp, err := env.Registry.UpdateWhere(ctx, connect.NewRequest(m))
+		if err != nil {
+			return nil, err
+		}
+		return resp.Msg, nil
+	}
+
+	if _, err := updateWhere(&registryv1.UpdateWhereRequest{Filters: map[string]string{"status": "eq.active"}}); connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("without a dry run: expected INVALID_ARGUMENT, got %v", err)
+	}
+	if _, err := updateWhere(&registryv1.UpdateWhereRequest{DryRun: true}); connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("without a filter: expected INVALID_ARGUMENT, got %v", err)
+	}
+	dry, err := updateWhere(&registryv1.UpdateWhereRequest{Where: `.status == "active"`, DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if dry.Matched != 2 || !dry.DryRun {
+		t.Errorf("dry run = %v, want 2 matched", dry)
+	}
+	if _, err := updateWhere(&registryv1.UpdateWhereRequest{Where: `.status == "active"`, DryRun: true, MaxRows: 1}); connect.CodeOf(err) != connect.CodeFailedPrecondition {
+		t.Errorf("over max_rows: expected FAILED_PRECONDITION, got %v", err)
+	}
+	if _, err := updateWhere(&registryv1.UpdateWhereRequest{Filters: map[string]string{"status": "eq.active"}, ExpectedCount: 3}); connect.CodeOf(err) != connect.CodeFailedPrecondition {
+		t.Errorf("stale expected_count: expected FAILED_PRECONDITION, got %v", err)
+	}
+	if _, err := updateWhere(&registryv1.UpdateWhereRequest{Filters: map[string]string{"status": "eq.active"}, ExpectedCount: dry.Matched}); err != nil {
+		t.Fatalf("update where: %v", err)
+	}
+
+	ids := env.QueryIDs(t, `badges | where(.status == "revoked")`, "")
+	if len(ids) != 2 {
+		t.Errorf("revoked badges = %v, want 2", ids)
+	}
+}
+
+// --- Test: record history and revert ---
+
+func TestIntegrationRecordHistory(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	obj, err := env.Metadata.CreateObject(ctx, connect.NewRequest(&registryv1.CreateObjectRequest{
+		ApiName: "badges", Title: "Badge", PluralTitle: "Badges",
+	}))
+	if err != nil {
+		t.Fatalf("create object: %v", err)
+	}
+	if _, err := env.Metadata.CreateField(ctx, connect.NewRequest(&registryv1.CreateFieldRequest{
+		ObjectId: obj.Msg.Object.Id, ApiName: "holder", Title: "Holder", Type: "TEXT",
+	})); err != nil {
+		t.Fatalf("create field: %v", err)
+	}
+
+	as := func(actor string) context.Context {
+		return db.WithActor(ctx, db.Actor{ID: actor, RequestID: "req-" + actor})
+	}
+	data, _ := structpb.NewStruct(map[string]any{"holder": "Ada"})
+	created, err := env.Registry.Create(as("alice"), connect.NewRequest(&registryv1.CreateRequest{ObjectName: "badges", Data: data}))
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	id := created.Msg.Record.Fields["id"].GetStringValue()
+	data, _ = structpb.NewStruct(map[string]any{"holder": "Grace"})
+	if _, err := env.Registry.Update(as("bob"), connect.NewRequest(&registryv1.UpdateRequest{ObjectName: "badges", Id: id, Data: data})); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	history := func() []*registryv1.RecordVersion {
+		t.Helper()
+		resp, err := env.Registry.GetRecordHistory(ctx, connect.NewRequest(&registryv1.GetRecordHistoryRequest{ObjectName: "badges", Id: id}))
+		if err != nil {
+			t.Fatalf("history: %v", err)
+		}
+		return resp.Msg.Versions
+	}
+	versions := history()
+	if len(versions) != 2 {
+		t.Fatalf("versions = %v, want 2", versions)
+	}
+	if v := versions[0]; v.Version != 1 || v.Operation != "INSERT" || v.ActorId != "alice" || v.RequestId != "req-alice" || len(v.Changes) != 0 {
+		t.Errorf("version 1 = %v", v)
+	}
+	v := versions[1]
+	if v.Version != 2 || v.Operation != "UPDATE" || v.ActorId != "bob" || v.Record.Fields["holder"].GetStringValue() != "Grace" {
+		t.Errorf("version 2 = %v", v)
+	}
+	if len(v.Changes) != 1 || v.Changes[0].Field != "holder" ||
+		v.Changes[0].OldValue.GetStringValue() != "Ada" || v.Changes[0].NewValue.GetStringValue() != "Grace" {
+		t.Errorf("version 2 changes = %v, want holder Ada -> Grace", v.Changes)
+	}
+
+	reverted, err := env.Registry.RevertRecord(as("carol"), connect.NewRequest(&registryv1.RevertRecordRequest{ObjectName: "badges", Id: id, Version: 1}))
+	if err != nil {
+		t.Fatalf("revert: %v", err)
+	}
+	if r := reverted.Msg.Record; r.Fields["holder"].GetStringValue() != "Ada" || r.Fields["version"].GetNumberValue() != 3 {
+		t.Errorf("reverted record = %v, want holder Ada at version 3", r)
+	}
+	if versions := history(); len(versions) != 3 || versions[2].ActorId != "carol" || len(versions[2].Changes) != 1 {
+		t.Errorf("versions after revert = %v", versions)
+	}
+
+	_, err = env.Registry.RevertRecord(ctx, connect.NewRequest(&registryv1.RevertRecordRequest{ObjectName: "badges", Id: id, Version: 9}))
+	if connect.CodeOf(err) != connect.CodeNotFound {
+		t.Errorf("revert to a missing version: expected NOT_FOUND, got %v", err)
+	}
+}
+
+// --- Test: retention policies ---
+
+func TestIntegrationRetention(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	obj, err := env.Metadata.CreateObject(ctx, connect.NewRequest(&registryv1.CreateObjectRequest{
+		ApiName: "badges", Title: "Badge", PluralTitle: "Badges",
+	}))
+	if err != nil {
+		t.Fatalf("create object: %v", err)
+	}
+	for _, f := range []struct{ name, typ string }{{"holder", "TEXT"}, {"expires_on", "DATE"}} {
+		_, err := env.Metadata.CreateField(ctx, connect.NewRequest(&registryv1.CreateFieldRequest{
+			ObjectId: obj.Msg.Object.Id, ApiName: f.name, Title: f.name, Type: f.typ,
+		}))
+		if err != nil {
+			t.Fatalf("create field %s: %v", f.name, err)
+		}
+	}
+
+	old := env.Create(t, "badges", map[string]any{"holder": "Ada", "expires_on": "2000-01-01"})
+	fresh := env.Create(t, "badges", map[string]any{"holder": "Grace", "expires_on": "2999-01-01"})
+
+	def := env.Cache.Get("badges")
+	policy := retention.Policy{ObjectID: def.ID, DateField: "expires_on", RetainDays: 30, Action: retention.ActionAnonymize, AnonymizeFields: []string{"holder"}, Enabled: true}
+	if err := policy.Validate(def); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if _, err := retention.SavePolicy(ctx, env.Pool, policy); err != nil {
+		t.Fatalf("save policy: %v", err)
+	}
+
+	enforcer := retention.NewEnforcer(env.Pool, env.Cache, 1, nil)
+	results, ran, err := enforcer.Run(ctx)
+	if err != nil || !ran {
+		t.Fatalf("run: ran=%t err=%v", ran, err)
+	}
+	if len(results) != 1 || results[0].Purged != 1 || results[0].Err != nil {
+		t.Fatalf("results = %+v, want one policy purging 1 record", results)
+	}
+
+	if got := env.Get(t, "badges", old.Fields["id"].GetStringValue()).Fields["holder"]; got.GetStringValue() != "" {
+		t.Errorf("expired holder = %v, want cleared", got)
+	}
+	if got := env.Get(t, "badges", fresh.Fields["id"].GetStringValue()).Fields["holder"].GetStringValue(); got != "Grace" {
+		t.Errorf("fresh holder = %q, want Grace", got)
+	}
+
+	// Anonymized records are not selected again.
+	results, _, err = enforcer.Run(ctx)
+	if err != nil || results[0].Purged != 0 {
+		t.Fatalf("second run: results=%+v err=%v", results, err)
+	}
+
+	audit, err := retention.ListAudit(ctx, env.Pool, "badges", 10)
+	if err != nil {
+		t.Fatalf("list audit: %v", err)
+	}
+	if len(audit) != 1 || audit[0].RecordID.String() != old.Fields["id"].GetStringValue() {
+		t.Errorf("audit = %+v, want the expired record", audit)
+	}
+}
+
+func TestIntegrationRetentionSnapshots(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	data, _ := structpb.NewStruct(map[string]any{"end_date": "2000-01-01"})
+	if _, err := env.Registry.Update(ctx, connect.NewRequest(&registryv1.UpdateRequest{ObjectName: "employees", Id: testutil.Org.Engineer2, Data: data})); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	before := time.Now()
+
+	asOf := func() []string {
+		t.Helper()
+		resp, err := env.Org.Query(ctx, connect.NewRequest(&registryv1.QueryRequest{
+			Query:             fmt.Sprintf(`employees | as_of("%s")`, before.UTC().Format(time.RFC3339Nano)),
+			IncludeTerminated: true,
+		}))
+		if err != nil {
+			t.Fatalf("as_of: %v", err)
+		}
+		var ids []string
+		for _, r := range resp.Msg.Results {
+			ids = append(ids, r.Fields["id"].GetStringValue())
+		}
+		return ids
+	}
+	if !slices.Contains(asOf(), testutil.Org.Engineer2) {
+		t.Fatal("as_of before the purge misses Engineer2")
+	}
+
+	def := env.Cache.Get("employees")
+	policy := retention.Policy{ObjectID: def.ID, DateField: "end_date", RetainDays: 30, Action: retention.ActionDelete, Enabled: true}
+	if err := policy.Validate(def); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if _, err := retention.SavePolicy(ctx, env.Pool, policy); err != nil {
+		t.Fatalf("save policy: %v", err)
+	}
+	results, ran, err := retention.NewEnforcer(env.Pool, env.Cache, 10, nil).Run(ctx)
+	if err != nil || !ran {
+		t.Fatalf("run: ran=%t err=%v", ran, err)
+	}
+	if len(results) != 1 || results[0].Purged != 1 || results[0].Err != nil {
+		t.Fatalf("results = %+v, want one policy purging 1 record", results)
+	}
+
+	// The purged employee is gone from snapshots before the purge too.
+	if ids := asOf(); slices.Contains(ids, testutil.Org.Engineer2) {
+		t.Errorf("as_of before the purge = %v, still holds Engineer2", ids)
+	}
+	var kept int
+	if err := env.Pool.QueryRow(ctx, `SELECT count(*) FROM core.employees_history WHERE "id" = $1`, testutil.Org.Engineer2).Scan(&kept); err != nil {
+		t.Fatal(err)
+	}
+	if kept != 0 {
+		t.Errorf("%d history rows of the purged employee are left", kept)
+	}
+}
+
+// --- Test: PII anonymization ---
+
+func TestIntegrationAnonymize(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+	if fd := env.Cache.Get("individuals").FieldsByAPIName["email"]; fd == nil || !fd.IsPII {
+		t.Error("individuals.email is not flagged PII")
+	}
+
+	obj, err := env.Metadata.CreateObject(ctx, connect.NewRequest(&registryv1.CreateObjectRequest{
+		ApiName: "contacts", Title: "Contact", PluralTitle: "Contacts",
+	}))
+	if err != nil {
+		t.Fatalf("create object: %v", err)
+	}
+	if _, err := env.Metadata.CreateField(ctx, connect.NewRequest(&registryv1.CreateFieldRequest{
+		ObjectId: obj.Msg.Object.Id, ApiName: "score", Title: "Score", Type: "NUMBER", IsPii: true,
+	})); connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Fatalf("PII NUMBER: err = %v, want INVALID_ARGUMENT", err)
+	}
+	for _, f := range []struct {
+		name, typ string
+		pii       bool
+	}{{"email", "EMAIL", true}, {"birthday", "DATE", true}, {"notes", "TEXT", false}} {
+		if _, err := env.Metadata.CreateField(ctx, connect.NewRequest(&registryv1.CreateFieldRequest{
+			ObjectId: obj.Msg.Object.Id, ApiName: f.name, Title: f.name, Type: f.typ, IsPii: f.pii,
+		})); err != nil {
+			t.Fatalf("create field %s: %v", f.name, err)
+		}
+	}
+	a := env.Create(t, "contacts", map[string]any{"email": "ada@corp.test", "birthday": "1990-06-15", "notes": "keep"})
+	b := env.Create(t, "contacts", map[string]any{"email": "ada@corp.test"})
+	env.Create(t, "contacts", map[string]any{"notes": "no PII"})
+
+	const salt = "staging-salt-0123456789"
+	admin := service.NewAdminService(env.Pool, env.Cache, nil, nil, retention.NewEnforcer(env.Pool, env.Cache, 1, nil), nil)
+	resp, err := admin.AnonymizeObject(ctx, connect.NewRequest(&registryv1.AnonymizeObjectRequest{ObjectName: "contacts", Salt: salt}))
+	if err != nil {
+		t.Fatalf("anonymize: %v", err)
+	}
+	if resp.Msg.Anonymized != 2 || !slices.Equal(resp.Msg.Fields, []string{"email", "birthday"}) {
+		t.Errorf("response = %+v, want 2 records of email and birthday", resp.Msg)
+	}
+
+	wantEmail := retention.Fake(schema.FieldEmail, salt, "ada@corp.test")
+	got := env.Get(t, "contacts", a.Fields["id"].GetStringValue()).Fields
+	if got["email"].GetStringValue() != wantEmail || got["notes"].GetStringValue() != "keep" {
+		t.Errorf("record a = email %v, notes %v; want %q and keep", got["email"], got["notes"], wantEmail)
+	}
+	if day := got["birthday"].GetStringValue(); day == "1990-06-15" || !strings.HasPrefix(day, "1990-") {
+		t.Errorf("birthday = %q, want another day of 1990", day)
+	}
+	// Equal values get equal fakes.
+	if email := env.Get(t, "contacts", b.Fields["id"].GetStringValue()).Fields["email"].GetStringValue(); email != wantEmail {
+		t.Errorf("record b email = %q, want %q", email, wantEmail)
+	}
+
+	// Only the anonymized version is left in the record history.
+	var leaked int
+	if err := env.Pool.QueryRow(ctx, `SELECT count(*) FROM metadata.record_history WHERE "data"->'data'->>'email' = 'ada@corp.test'`).Scan(&leaked); err != nil {
+		t.Fatal(err)
+	}
+	if leaked != 0 {
+		t.Errorf("%d history versions still hold the original email", leaked)
+	}
+}
+
+func TestIntegrationAnonymizeSnapshots(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	if _, err := env.Metadata.CreateField(ctx, connect.NewRequest(&registryv1.CreateFieldRequest{
+		ObjectId: env.Cache.Get("employees").ID.String(), ApiName: "nickname", Title: "Nickname", Type: "TEXT", IsPii: true,
+	})); err != nil {
+		t.Fatalf("create field: %v", err)
+	}
+	const nickname = "secret-nickname-0001"
+	data, _ := structpb.NewStruct(map[string]any{"nickname": nickname})
+	if _, err := env.Registry.Update(ctx, connect.NewRequest(&registryv1.UpdateRequest{ObjectName: "employees", Id: testutil.Org.Engineer1, Data: data})); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	before := time.Now()
+
+	admin := service.NewAdminService(env.Pool, env.Cache, nil, nil, retention.NewEnforcer(env.Pool, env.Cache, 10, nil), nil)
+	resp, err := admin.AnonymizeObject(ctx, connect.NewRequest(&registryv1.AnonymizeObjectRequest{ObjectName: "employees", Salt: "staging-salt-0123456789"}))
+	if err != nil {
+		t.Fatalf("anonymize: %v", err)
+	}
+	if resp.Msg.Anonymized != 1 {
+		t.Errorf("anonymized = %d, want 1", resp.Msg.Anonymized)
+	}
+
+	var leaked int
+	if err := env.Pool.QueryRow(ctx, `SELECT count(*) FROM core.employees_history h WHERE to_jsonb(h."row")::text LIKE '%' || $1 || '%'`, nickname).Scan(&leaked); err != nil {
+		t.Fatal(err)
+	}
+	if leaked != 0 {
+		t.Errorf("%d employees_history rows still hold the original nickname", leaked)
+	}
+	// The anonymized row stands in for the dropped ones in earlier snapshots.
+	ids := env.QueryIDs(t, fmt.Sprintf(`employees | as_of("%s")`, before.UTC().Format(time.RFC3339Nano)), "")
+	if !slices.Contains(ids, testutil.Org.Engineer1) {
+		t.Errorf("as_of before anonymizing = %v, misses Engineer1", ids)
+	}
+}
+
+// --- Test: hierarchy cycle guard and path rebuild ---
+
+func TestIntegrationHierarchyPaths(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	data, err := structpb.NewStruct(map[string]any{"manager": testutil.Org.Engineer1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = env.Registry.Update(ctx, connect.NewRequest(&registryv1.UpdateRequest{
+		ObjectName: "employees", Id: testutil.Org.CTO, Data: data,
+	}))
+	if connect.CodeOf(err) != connect.CodeInvalidArgument || !errors.Is(err, ltreeutil.ErrCycle) {
+		t.Fatalf("moving CTO under Engineer1: err = %v, want a cycle", err)
+	}
+
+	// Corrupt one path behind the triggers' back, then rebuild.
+	if _, err := env.Pool.Exec(ctx, `UPDATE core.employees SET "manager_path" = text2ltree(core.uuid_to_ltree_label("id")) WHERE "id" = $1`, testutil.Org.Engineer1); err != nil {
+		t.Fatalf("corrupt path: %v", err)
+	}
+	obj := env.Cache.Get("employees")
+	tx, err := env.Pool.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback(ctx)
+	updated, broken, err := ltreeutil.RebuildPaths(ctx, tx, obj, obj.FieldsByAPIName["manager"])
+	if err != nil {
+		t.Fatalf("rebuild: %v", err)
+	}
+	if updated != 1 || len(broken) != 0 {
+		t.Fatalf("rebuild updated %d, broken %v; want 1 and none", updated, broken)
+	}
+
+	var path string
+	if err := tx.QueryRow(ctx, `SELECT "manager_path"::text FROM core.employees WHERE "id" = $1`, testutil.Org.Engineer1).Scan(&path); err != nil {
+		t.Fatal(err)
+	}
+	want := ltreeutil.Build([]uuid.UUID{uuid.MustParse(testutil.Org.CEO), uuid.MustParse(testutil.Org.CTO), uuid.MustParse(testutil.Org.Engineer1)})
+	if path != want {
+		t.Errorf("rebuilt path = %q, want %q", path, want)
+	}
+}
+
+// --- Test: org chart diff ---
+
+func TestIntegrationOrgDiff(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	var from string
+	if err := env.Pool.QueryRow(ctx, `SELECT to_char(clock_timestamp() AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS.US"Z"')`).Scan(&from); err != nil {
+		t.Fatal(err)
+	}
+	data, err := structpb.NewStruct(map[string]any{"manager": testutil.Org.CFO, "department": testutil.Org.Finance})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.Registry.Update(ctx, connect.NewRequest(&registryv1.UpdateRequest{
+		ObjectName: "employees", Id: testutil.Org.Engineer2, Data: data,
+	})); err != nil {
+		t.Fatalf("move Engineer2: %v", err)
+	}
+
+	resp, err := env.Org.Diff(ctx, connect.NewRequest(&registryv1.DiffRequest{
+		From: from, To: time.Now().Add(time.Minute).UTC().Format(time.RFC3339),
+	}))
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	got := make(map[string]*registryv1.OrgChange)
+	for _, c := range resp.Msg.Changes {
+		if c.EmployeeId != testutil.Org.Engineer2 {
+			t.Errorf("unexpected change %v", c)
+		}
+		got[c.Kind] = c
+	}
+	if c := got["manager_change"]; c == nil || c.FromManagerId != testutil.Org.CTO || c.ToManagerId != testutil.Org.CFO {
+		t.Errorf("manager change = %v", c)
+	}
+	if c := got["department_change"]; c == nil || c.FromDepartmentId != testutil.Org.Engineering || c.ToDepartmentId != testutil.Org.Finance {
+		t.Errorf("department change = %v", c)
+	}
+	if s := resp.Msg.Summary; s.ManagerChanges != 1 || s.DepartmentChanges != 1 || s.Hires != 0 || s.Departures != 0 {
+		t.Errorf("summary = %v", s)
+	}
+
+	// Scoped to the CTO's subtree, the move still shows: Engineer2 was in it at from.
+	resp, err = env.Org.Diff(ctx, connect.NewRequest(&registryv1.DiffRequest{
+		From: from, To: time.Now().Add(time.Minute).UTC().Format(time.RFC3339), RootId: testutil.Org.CTO,
+	}))
+	if err != nil {
+		t.Fatalf("diff under CTO: %v", err)
+	}
+	if len(resp.Msg.Changes) != 2 {
+		t.Errorf("changes under CTO = %v, want Engineer2's two", resp.Msg.Changes)
+	}
+}
+
+// --- Test: validation webhooks ---
+
+func TestIntegrationValidationWebhook(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Record map[string]any `json:"record"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Record["holder"] == "Mallory" {
+			fmt.Fprint(w, `{"allowed": false, "violations": [{"field": "holder", "message": "blocked holder"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"allowed": true}`)
+	}))
+	defer hook.Close()
+
+	obj, err := env.Metadata.CreateObject(ctx, connect.NewRequest(&registryv1.CreateObjectRequest{
+		ApiName: "passes", Title: "Pass", PluralTitle: "Passes",
+		ValidationWebhook: &registryv1.ValidationWebhook{Url: hook.URL, TimeoutMs: 1000},
+	}))
+	if err != nil {
+		t.Fatalf("create object: %v", err)
+	}
+	if obj.Msg.Object.ValidationWebhook.GetUrl() != hook.URL {
+		t.Fatalf("webhook = %v, want %s", obj.Msg.Object.ValidationWebhook, hook.URL)
+	}
+	if _, err := env.Metadata.CreateField(ctx, connect.NewRequest(&registryv1.CreateFieldRequest{
+		ObjectId: obj.Msg.Object.Id, ApiName: "holder", Title: "Holder", Type: "TEXT",
+	})); err != nil {
+		t.Fatalf("create field: %v", err)
+	}
+
+	env.Create(t, "passes", map[string]any{"holder": "Ada"})
+
+	data, _ := structpb.NewStruct(map[string]any{"holder": "Mallory"})
+	_, err = env.Registry.Create(ctx, connect.NewRequest(&registryv1.CreateRequest{ObjectName: "passes", Data: data}))
+	if connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Fatalf("create Mallory: err = %v, want INVALID_ARGUMENT", err)
+	}
+	var cerr *connect.Error
+	errors.As(err, &cerr)
+	var violations []*registryv1.FieldViolation
+	for _, d := range cerr.Details() {
+		if msg, err := d.Value(); err == nil {
+			if vf, ok := msg.(*registryv1.ValidationFailed); ok {
+				violations = vf.Violations
+			}
+		}
+	}
+	if len(violations) != 1 || violations[0].Field != "holder" || violations[0].Source != "webhook" {
+		t.Errorf("violations = %v, want one on holder", violations)
+	}
+
+	// Rejected writes roll back.
+	resp := env.Query(t, `passes | count`, "")
+	if resp.Scalar == nil || *resp.Scalar != 1 {
+		t.Errorf("passes | count = %v, want 1", resp.Scalar)
+	}
+}
+
+// --- Test: display templates ---
+
+func TestIntegrationDisplayTemplate(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	rooms, err := env.Metadata.CreateObject(ctx, connect.NewRequest(&registryv1.CreateObjectRequest{
+		ApiName: "rooms", Title: "Room", PluralTitle: "Rooms",
+	}))
+	if err != nil {
+		t.Fatalf("create object: %v", err)
+	}
+	for _, name := range []string{"name", "floor"} {
+		if _, err := env.Metadata.CreateField(ctx, connect.NewRequest(&registryv1.CreateFieldRequest{
+			ObjectId: rooms.Msg.Object.Id, ApiName: name, Title: name, Type: "TEXT",
+		})); err != nil {
+			t.Fatalf("create field %s: %v", name, err)
+		}
+	}
+	updated, err := env.Metadata.UpdateObject(ctx, connect.NewRequest(&registryv1.UpdateObjectRequest{
+		Id: rooms.Msg.Object.Id, DisplayTemplate: new("{name} (floor {floor})"),
+	}))
+	if err != nil {
+		t.Fatalf("set display template: %v", err)
+	}
+	if got := updated.Msg.Object.DisplayTemplate; got != "{name} (floor {floor})" {
+		t.Errorf("display_template = %q", got)
+	}
+
+	lima := env.Create(t, "rooms", map[string]any{"name": "Lima", "floor": "3"})
+	env.Create(t, "rooms", map[string]any{"name": "Lisbon", "floor": "1"})
+	env.Create(t, "rooms", map[string]any{"name": "Oslo", "floor": "2"})
+
+	resp, err := env.Registry.Typeahead(ctx, connect.NewRequest(&registryv1.TypeaheadRequest{ObjectName: "rooms", Q: "li"}))
+	if err != nil {
+		t.Fatalf("typeahead: %v", err)
+	}
+	var got []string
+	for _, m := range resp.Msg.Matches {
+		got = append(got, m.Display)
+	}
+	if strings.Join(got, ",") != "Lima (floor 3),Lisbon (floor 1)" {
+		t.Errorf("matches = %v", got)
+	}
+
+	desks, err := env.Metadata.CreateObject(ctx, connect.NewRequest(&registryv1.CreateObjectRequest{
+		ApiName: "desks", Title: "Desk", PluralTitle: "Desks",
+	}))
+	if err != nil {
+		t.Fatalf("create object: %v", err)
+	}
+	if _, err := env.Metadata.CreateField(ctx, connect.NewRequest(&registryv1.CreateFieldRequest{
+		ObjectId: desks.Msg.Object.Id, ApiName: "room", Title: "Room", Type: "LOOKUP", LookupObjectId: rooms.Msg.Object.Id,
+	})); err != nil {
+		t.Fatalf("create lookup: %v", err)
+	}
+	desk := env.Create(t, "desks", map[string]any{"room": lima.Fields["id"].GetStringValue()})
+	record, err := env.Registry.Get(ctx, connect.NewRequest(&registryv1.GetRequest{
+		ObjectName: "desks", Id: desk.Fields["id"].GetStringValue(), Expand: "room",
+	}))
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if d := record.Msg.Record.Fields["room"].GetStructValue().Fields["_display"].GetStringValue(); d != "Lima (floor 3)" {
+		t.Errorf("_display = %q", d)
+	}
+
+	lookup, err := env.Registry.Lookup(ctx, connect.NewRequest(&registryv1.LookupRequest{ObjectName: "desks", Field: "room", Q: "lisbn"}))
+	if err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+	if lookup.Msg.ObjectName != "rooms" || len(lookup.Msg.Matches) == 0 || lookup.Msg.Matches[0].Display != "Lisbon (floor 1)" {
+		t.Errorf("lookup(lisbn) = %v, want Lisbon first", lookup.Msg)
+	}
+	if _, err := env.Registry.Lookup(ctx, connect.NewRequest(&registryv1.LookupRequest{ObjectName: "rooms", Field: "name"})); connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("lookup on a TEXT field: err = %v, want INVALID_ARGUMENT", err)
+	}
+
+	_, err = env.Registry.Typeahead(ctx, connect.NewRequest(&registryv1.TypeaheadRequest{ObjectName: "desks"}))
+	if connect.CodeOf(err) != connect.CodeFailedPrecondition {
+		t.Errorf("typeahead without template: err = %v, want FAILED_PRECONDITION", err)
+	}
+}
+
+// --- Test: positions ---
+
+func TestIntegrationPositions(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	// Engineer1 reports to the CTO and holds a second, half-time position under the CFO.
+	env.Create(t, "positions", map[string]any{
+		"employee": testutil.Org.Engineer1, "manager": testutil.Org.CTO, "department": testutil.Org.Engineering,
+		"title": "Engineer", "fte": 0.5, "is_primary": true, "start_date": "2020-01-01",
+	})
+	env.Create(t, "positions", map[string]any{
+		"employee": testutil.Org.Engineer1, "manager": testutil.Org.CFO, "department": testutil.Org.Finance,
+		"title": "Finance Analyst", "fte": 0.5, "start_date": "2020-01-01",
+	})
+
+	resp := env.Query(t, fmt.Sprintf(`reports("%s", via: positions) | count`, testutil.Org.CFO), "")
+	if resp.Scalar == nil || *resp.Scalar != 1 {
+		t.Errorf("CFO position reports = %v, want 1", resp.Scalar)
+	}
+	resp = env.Query(t, fmt.Sprintf(`chain("%s", via: positions) | count`, testutil.Org.Engineer1), "")
+	if resp.Scalar == nil || *resp.Scalar != 2 {
+		t.Errorf("Engineer1 position managers = %v, want 2", resp.Scalar)
+	}
+	resp = env.Query(t, fmt.Sprintf(`reports_to("%s", "%s", via: positions)`, testutil.Org.Engineer1, testutil.Org.CFO), "")
+	if resp.ReportsTo == nil || !*resp.ReportsTo {
+		t.Errorf("reports_to CFO via positions = %v, want true", resp.ReportsTo)
+	}
+
+	record, err := env.Registry.Get(ctx, connect.NewRequest(&registryv1.GetRequest{
+		ObjectName: "employees", Id: testutil.Org.Engineer1, Expand: "positions,positions.department", Select: "employee_number,positions.title,positions.department",
+	}))
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	positions := record.Msg.Record.Fields["positions"].GetListValue().GetValues()
+	if len(positions) != 2 {
+		t.Fatalf("positions = %v", positions)
+	}
+	second := positions[1].GetStructValue()
+	if second.Fields["title"].GetStringValue() != "Finance Analyst" ||
+		second.Fields["department"].GetStructValue().Fields["title"].GetStringValue() == "" {
+		t.Errorf("second position = %v", second)
+	}
+}
+
+// --- Test: teams ---
+
+func TestIntegrationTeams(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	team := env.Create(t, "teams", map[string]any{
+		"organization": testutil.Org.Organization, "slug": "platform", "title": "Platform",
+	})
+	teamID := team.Fields["id"].GetStringValue()
+	for _, emp := range []string{testutil.Org.Engineer1, testutil.Org.CFO} {
+		env.Create(t, "team_memberships", map[string]any{"team": teamID, "employee": emp})
+	}
+
+	resp := env.Query(t, `team("platform") | count`, "")
+	if resp.Scalar == nil || *resp.Scalar != 2 {
+		t.Errorf("team members = %v, want 2", resp.Scalar)
+	}
+	ids := env.QueryIDs(t, fmt.Sprintf(`reports("%s") | where(member_of("platform"))`, testutil.Org.CTO), "")
+	if len(ids) != 1 || ids[0] != testutil.Org.Engineer1 {
+		t.Errorf("CTO reports on platform = %v, want Engineer1", ids)
+	}
+	if ids := env.QueryIDs(t, `team("nope")`, ""); len(ids) != 0 {
+		t.Errorf("unknown team = %v, want none", ids)
+	}
+
+	record, err := env.Registry.Get(ctx, connect.NewRequest(&registryv1.GetRequest{ObjectName: "teams", Id: teamID, Expand: "team_memberships"}))
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if members := record.Msg.Record.Fields["team_memberships"].GetListValue().GetValues(); len(members) != 2 {
+		t.Errorf("team_memberships = %v, want 2", members)
+	}
+
+	data, _ := structpb.NewStruct(map[string]any{"team": teamID, "employee": testutil.Org.CFO})
+	if _, err := env.Registry.Create(ctx, connect.NewRequest(&registryv1.CreateRequest{ObjectName: "team_memberships", Data: data})); err == nil {
+		t.Error("expected a duplicate membership to be rejected")
+	}
+}
+
+// --- Test: HRQL bucket counts ---
+
+func TestIntegrationBucket(t *testing.T) {
+	env := testutil.NewEnv(t)
+
+	total := env.Query(t, "employees | where(.start_date | years_since >= 0) | count", "").GetScalar()
+	resp := env.Query(t, "employees | bucket(.start_date | years_since, [1, 5, 1000])", "")
+	if len(resp.Buckets) != 4 {
+		t.Fatalf("buckets = %d, want 4", len(resp.Buckets))
+	}
+	if resp.Buckets[0].Lower != nil || resp.Buckets[0].GetUpper() != 1 {
+		t.Errorf("first bucket = %v, want below 1", resp.Buckets[0])
+	}
+	if resp.Buckets[3].GetLower() != 1000 || resp.Buckets[3].Upper != nil || resp.Buckets[3].Count != 0 {
+		t.Errorf("last bucket = %v, want an empty range from 1000 up", resp.Buckets[3])
+	}
+	var sum int64
+	for _, b := range resp.Buckets {
+		sum += b.Count
+	}
+	if float64(sum) != total {
+		t.Errorf("bucket counts sum to %d, want %v", sum, total)
+	}
+}
+
+func TestIntegrationIDs(t *testing.T) {
+	env := testutil.NewEnv(t)
+
+	resp := env.Query(t, fmt.Sprintf(`reports("%s") | ids`, testutil.Org.CEO), "")
+	if len(resp.Ids) != 4 || len(resp.Results) != 0 || resp.TotalCount != 4 {
+		t.Errorf("reports(CEO) | ids = %v (total %d), want 4 IDs and no records", resp.Ids, resp.TotalCount)
+	}
+
+	req := &registryv1.QueryRequest{Query: fmt.Sprintf(`reports("%s") | ids`, testutil.Org.CEO), Limit: 3}
+	page, err := env.Org.Query(context.Background(), connect.NewRequest(req))
+	if err != nil {
+		t.Fatalf("first page: %v", err)
+	}
+	if len(page.Msg.Ids) != 3 || page.Msg.NextCursor == nil {
+		t.Fatalf("first page = %v, want 3 IDs and a cursor", page.Msg.Ids)
+	}
+	req.Cursor = *page.Msg.NextCursor
+	page, err = env.Org.Query(context.Background(), connect.NewRequest(req))
+	if err != nil {
+		t.Fatalf("second page: %v", err)
+	}
+	if len(page.Msg.Ids) != 1 || page.Msg.NextCursor != nil {
+		t.Errorf("second page = %v, want the last ID", page.Msg.Ids)
+	}
+
+	req = &registryv1.QueryRequest{Query: "employees | ids", Select: "id"}
+	if _, err := env.Org.Query(context.Background(), connect.NewRequest(req)); connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("ids with select: err = %v, want INVALID_ARGUMENT", err)
+	}
+}
+
+// --- Test: HRQL cost ceilings ---
+
+func TestIntegrationQueryCost(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+	org := service.NewOrgService(env.Pool, env.Cache, nil, hrqlpg.ExpandAuto, metrics.NewHRQL(), service.QueryLimits{MaxRows: 1}, env.Cursors)
+
+	_, err := org.Query(ctx, connect.NewRequest(&registryv1.QueryRequest{Query: "employees | count"}))
+	if connect.CodeOf(err) != connect.CodeFailedPrecondition {
+		t.Fatalf("over the row ceiling: err = %v, want FAILED_PRECONDITION", err)
+	}
+	var cerr *connect.Error
+	errors.As(err, &cerr)
+	var detail *registryv1.QueryCostExceeded
+	for _, d := range cerr.Details() {
+		if v, err := d.Value(); err == nil {
+			detail, _ = v.(*registryv1.QueryCostExceeded)
+		}
+	}
+	if detail == nil || detail.EstimatedRows <= 1 || detail.MaxRows != 1 {
+		t.Errorf("QueryCostExceeded detail = %v, want the estimate and a max_rows of 1", detail)
+	}
+
+	req := connect.NewRequest(&registryv1.QueryRequest{Query: "employees | count", SkipCostCheck: true})
+	if _, err := org.Query(ctx, req); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Errorf("skip_cost_check without permission: err = %v, want PERMISSION_DENIED", err)
+	}
+	req.Header().Set("X-Principal-Permissions", "hrql:unbounded")
+	if _, err := org.Query(ctx, req); err != nil {
+		t.Errorf("skip_cost_check with hrql:unbounded: %v", err)
+	}
+}
+
+func TestIntegrationSearchIndex(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+	meta := service.NewMetadataService(env.Pool, env.Cache, schema.NewIdentifierPolicy(0), schema.DataLimits{}, true, false, false)
+
+	obj, err := meta.CreateObject(ctx, connect.NewRequest(&registryv1.CreateObjectRequest{
+		ApiName: "vendors", Title: "Vendor", PluralTitle: "Vendors",
+	}))
+	if err != nil {
+		t.Fatalf("create object: %v", err)
+	}
+	if _, err := meta.CreateField(ctx, connect.NewRequest(&registryv1.CreateFieldRequest{
+		ObjectId: obj.Msg.Object.Id, ApiName: "rating", Title: "Rating", Type: "NUMBER", IsSearchable: true,
+	})); connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Fatalf("searchable NUMBER: err = %v, want INVALID_ARGUMENT", err)
+	}
+	field, err := meta.CreateField(ctx, connect.NewRequest(&registryv1.CreateFieldRequest{
+		ObjectId: obj.Msg.Object.Id, ApiName: "name", Title: "Name", Type: "TEXT", IsSearchable: true,
+	}))
+	if err != nil {
+		t.Fatalf("create field: %v", err)
+	}
+	if !field.Msg.Field.IsSearchable {
+		t.Error("created field is not searchable")
+	}
+
+	indexName := "ix_search_" + strings.ReplaceAll(field.Msg.Field.Id, "-", "")
+	indexed := func() bool {
+		var n int
+		if err := env.Pool.QueryRow(ctx, `SELECT count(*) FROM pg_class WHERE relname = $1`, indexName).Scan(&n); err != nil {
+			t.Fatalf("look up index: %v", err)
+		}
+		return n == 1
+	}
+	if !indexed() {
+		t.Fatalf("index %s was not created", indexName)
+	}
+
+	if _, err := meta.UpdateField(ctx, connect.NewRequest(&registryv1.UpdateFieldRequest{
+		ObjectId: obj.Msg.Object.Id, Id: field.Msg.Field.Id, IsSearchable: new(false),
+	})); err != nil {
+		t.Fatalf("clear is_searchable: %v", err)
+	}
+	if indexed() {
+		t.Errorf("index %s was not dropped", indexName)
+	}
+}
+
+func TestIntegrationSortIndexes(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+	meta := service.NewMetadataService(env.Pool, env.Cache, schema.NewIdentifierPolicy(0), schema.DataLimits{}, false, true, false)
+
+	obj, err := meta.CreateObject(ctx, connect.NewRequest(&registryv1.CreateObjectRequest{
+		ApiName: "tickets", Title: "Ticket", PluralTitle: "Tickets",
+	}))
+	if err != nil {
+		t.Fatalf("create object: %v", err)
+	}
+	field, err := meta.CreateField(ctx, connect.NewRequest(&registryv1.CreateFieldRequest{
+		ObjectId: obj.Msg.Object.Id, ApiName: "priority", Title: "Priority", Type: "NUMBER", IsSortable: new(true),
+	}))
+	if err != nil {
+		t.Fatalf("create field: %v", err)
+	}
+	for i := range 500 {
+		data := map[string]any{}
+		if i%10 != 0 {
+			data["priority"] = i % 7
+		}
+		env.Create(t, "tickets", data)
+	}
+	if _, err := env.Pool.Exec(ctx, `ANALYZE metadata.records`); err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+
+	// Pages walk every record once, NULLs included, in either direction.
+	for _, order := range []string{"priority", "priority.desc", "priority.nullsfirst", "priority.desc.nullslast"} {
+		seen := map[string]bool{}
+		req := &registryv1.ListRequest{ObjectName: "tickets", Limit: 37, Order: order}
+		for {
+			resp, err := env.Registry.List(ctx, connect.NewRequest(req))
+			if err != nil {
+				t.Fatalf("%s: list: %v", order, err)
+			}
+			for _, r := range resp.Msg.Results {
+				seen[r.Fields["id"].GetStringValue()] = true
+			}
+			if resp.Msg.NextCursor == nil {
+				break
+			}
+			req.Cursor = *resp.Msg.NextCursor
+		}
+		if len(seen) != 500 {
+			t.Errorf("%s: pages returned %d records, want 500", order, len(seen))
+		}
+	}
+
+	// A page past a cursor is read off a sort index: with scans of the whole
+	// table and explicit sorts priced out, the plan is still an index scan.
+	o := env.Cache.Get("tickets")
+	index := "ix_sort_" + strings.ReplaceAll(field.Msg.Field.Id, "-", "")
+	for order, clause := range map[string]*hrqlpg.OrderClause{
+		"priority":            {FieldAPIName: "priority"},
+		"priority.desc":       {FieldAPIName: "priority", Desc: true},
+		"priority.nullsfirst": {FieldAPIName: "priority", NullsFirst: true},
+	} {
+		params, err := hrqlpg.ParseParams(o, hrqlpg.ParamsInput{
+			Order: order, Cursor: env.Cursors.Encode(o, uuid.NewString(), new("3"), clause), Limit: 20, Cursors: env.Cursors,
+		})
+		if err != nil {
+			t.Fatalf("%s: parse params: %v", order, err)
+		}
+		sqlStr, args, err := hrqlpg.NewBuilder(o).BuildIDs(params)
+		if err != nil {
+			t.Fatalf("%s: build: %v", order, err)
+		}
+		tx, err := env.Pool.Begin(ctx)
+		if err != nil {
+			t.Fatalf("begin: %v", err)
+		}
+		if _, err := tx.Exec(ctx, `SET LOCAL enable_seqscan = off; SET LOCAL enable_sort = off`); err != nil {
+			t.Fatalf("set planner options: %v", err)
+		}
+		rows, err := tx.Query(ctx, "EXPLAIN "+sqlStr, args...)
+		if err != nil {
+			t.Fatalf("%s: explain: %v", order, err)
+		}
+		var plan []string
+		for rows.Next() {
+			var line string
+			if err := rows.Scan(&line); err != nil {
+				t.Fatalf("scan plan: %v", err)
+			}
+			plan = append(plan, line)
+		}
+		_ = tx.Rollback(ctx)
+		if text := strings.Join(plan, "\n"); !strings.Contains(text, index) || strings.Contains(text, "Sort Key") {
+			t.Errorf("%s: plan does not page through a sort index:\n%s", order, text)
+		}
+	}
+
+	if _, err := meta.UpdateField(ctx, connect.NewRequest(&registryv1.UpdateFieldRequest{
+		ObjectId: obj.Msg.Object.Id, Id: field.Msg.Field.Id, IsSortable: new(false),
+	})); err != nil {
+		t.Fatalf("clear is_sortable: %v", err)
+	}
+	var n int
+	if err := env.Pool.QueryRow(ctx, `SELECT count(*) FROM pg_class WHERE relname LIKE 'ix_sort_' || $1 || '%'`, strings.ReplaceAll(field.Msg.Field.Id, "-", "")).Scan(&n); err != nil {
+		t.Fatalf("look up indexes: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("%d sort indexes left after clearing is_sortable, want 0", n)
+	}
+}
+
+func TestIntegrationAccentInsensitive(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+	meta := service.NewMetadataService(env.Pool, env.Cache, schema.NewIdentifierPolicy(0), schema.DataLimits{}, true, false, false)
+
+	obj := env.Cache.Get("employees")
+	field, err := meta.CreateField(ctx, connect.NewRequest(&registryv1.CreateFieldRequest{
+		ObjectId: obj.ID.String(), ApiName: "nickname", Title: "Nickname", Type: "TEXT", IsSearchable: true,
+	}))
+	if err != nil {
+		t.Fatalf("create field: %v", err)
+	}
+	data, _ := structpb.NewStruct(map[string]any{"nickname": "José"})
+	if _, err := env.Registry.Update(ctx, connect.NewRequest(&registryv1.UpdateRequest{ObjectName: "employees", Id: testutil.Org.CTO, Data: data})); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	if ids := env.QueryIDs(t, `employees | where(.nickname | contains("jose"))`, ""); len(ids) != 0 {
+		t.Errorf("contains: ids = %v, want none", ids)
+	}
+	if ids := env.QueryIDs(t, `employees | where(.nickname | contains_fold("jose"))`, ""); len(ids) != 1 || ids[0] != testutil.Org.CTO {
+		t.Errorf("contains_fold: ids = %v, want the CTO", ids)
+	}
+
+	if _, err := meta.UpdateField(ctx, connect.NewRequest(&registryv1.UpdateFieldRequest{
+		ObjectId: obj.ID.String(), Id: field.Msg.Field.Id, IsAccentInsensitive: new(true),
+	})); err != nil {
+		t.Fatalf("set is_accent_insensitive: %v", err)
+	}
+	if ids := env.QueryIDs(t, `employees | where(.nickname | starts_with("JOS"))`, ""); len(ids) != 1 {
+		t.Errorf("starts_with on a flagged field: ids = %v, want the CTO", ids)
+	}
+	var def string
+	indexName := "ix_search_" + strings.ReplaceAll(field.Msg.Field.Id, "-", "")
+	if err := env.Pool.QueryRow(ctx, `SELECT pg_get_indexdef($1::regclass)`, "core."+indexName).Scan(&def); err != nil {
+		t.Fatalf("look up index: %v", err)
+	}
+	if !strings.Contains(def, "unaccent") {
+		t.Errorf("index %s = %q, want it on the unaccented value", indexName, def)
+	}
+
+	if _, err := meta.CreateField(ctx, connect.NewRequest(&registryv1.CreateFieldRequest{
+		ObjectId: obj.ID.String(), ApiName: "rating", Title: "Rating", Type: "NUMBER", IsAccentInsensitive: true,
+	})); connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("accent-insensitive NUMBER: err = %v, want INVALID_ARGUMENT", err)
+	}
+}
+
+func TestIntegrationFieldAccessFlags(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	obj := env.Cache.Get("employees")
+	field, err := env.Metadata.CreateField(ctx, connect.NewRequest(&registryv1.CreateFieldRequest{
+		ObjectId: obj.ID.String(), ApiName: "salary", Title: "Salary", Type: "NUMBER",
+		IsFilterable: new(false),
+	}))
+	if err != nil {
+		t.Fatalf("create field: %v", err)
+	}
+	if field.Msg.Field.IsFilterable || !field.Msg.Field.IsSortable {
+		t.Errorf("created field: is_filterable = %v, is_sortable = %v, want false, true", field.Msg.Field.IsFilterable, field.Msg.Field.IsSortable)
+	}
+
+	list := func(req *registryv1.ListRequest) error {
+		req.ObjectName = "employees"
+		_, err := env.Registry.List(ctx, connect.NewRequest(req))
+		return err
+	}
+	if err := list(&registryv1.ListRequest{Filters: map[string]string{"salary": "gt.100"}}); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Errorf("filter: err = %v, want PERMISSION_DENIED", err)
+	}
+	if err := list(&registryv1.ListRequest{Order: "salary.desc"}); err != nil {
+		t.Errorf("order: %v", err)
+	}
+	query := func(q string) error {
+		_, err := env.Org.Query(ctx, connect.NewRequest(&registryv1.QueryRequest{Query: q}))
+		return err
+	}
+	if err := query("employees | where(.salary > 100)"); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Errorf("where: err = %v, want PERMISSION_DENIED", err)
+	}
+
+	if _, err := env.Metadata.UpdateField(ctx, connect.NewRequest(&registryv1.UpdateFieldRequest{
+		ObjectId: obj.ID.String(), Id: field.Msg.Field.Id, IsSortable: new(false),
+	})); err != nil {
+		t.Fatalf("clear is_sortable: %v", err)
+	}
+	if err := list(&registryv1.ListRequest{Order: "salary.desc"}); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Errorf("order: err = %v, want PERMISSION_DENIED", err)
+	}
+	if err := query("employees | sort_by(.salary)"); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Errorf("sort_by: err = %v, want PERMISSION_DENIED", err)
+	}
+	if err := list(&registryv1.ListRequest{Order: "nope"}); connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("unknown order field: err = %v, want INVALID_ARGUMENT", err)
+	}
+}
+
+func TestIntegrationUnion(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	obj, err := env.Metadata.CreateObject(ctx, connect.NewRequest(&registryv1.CreateObjectRequest{
+		ApiName: "contractors", Title: "Contractor", PluralTitle: "Contractors",
+	}))
+	if err != nil {
+		t.Fatalf("create object: %v", err)
+	}
+	if _, err := env.Metadata.CreateField(ctx, connect.NewRequest(&registryv1.CreateFieldRequest{
+		ObjectId: obj.Msg.Object.Id, ApiName: "start_date", Title: "Start Date", Type: "DATE",
+	})); err != nil {
+		t.Fatalf("create field: %v", err)
+	}
+	env.Create(t, "contractors", map[string]any{"start_date": "2999-01-01"})
+
+	employees := env.Query(t, "employees | count", "").GetScalar()
+	total := env.Query(t, "union(employees, contractors) | count", "").GetScalar()
+	if total != employees+1 {
+		t.Errorf("union count = %v, want %v", total, employees+1)
+	}
+
+	resp := env.Query(t, "union(employees, contractors) | sort_by(.start_date, desc) | first", "")
+	if len(resp.Results) != 1 {
+		t.Fatalf("results = %d, want 1", len(resp.Results))
+	}
+	if got := resp.Results[0].Fields["_object"].GetStringValue(); got != "contractors" {
+		t.Errorf("latest start is from %q, want contractors", got)
+	}
+	if resp.TotalCount != int64(total) {
+		t.Errorf("total_count = %d, want %v", resp.TotalCount, total)
+	}
+}
+
+func TestIntegrationActorSettings(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := db.WithActor(context.Background(), db.Actor{ID: "user-42", RequestID: "req-7"})
+
+	tx, err := db.Begin(ctx, env.Pool)
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	var actor, requestID string
+	if err := tx.QueryRow(ctx, `SELECT current_setting('app.actor_id', true), current_setting('app.request_id', true)`).Scan(&actor, &requestID); err != nil {
+		t.Fatalf("read settings: %v", err)
+	}
+	if actor != "user-42" || requestID != "req-7" {
+		t.Errorf("settings = %q, %q, want user-42, req-7", actor, requestID)
+	}
+	if err := tx.Rollback(ctx); err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+
+	// SET LOCAL semantics: nothing leaks past the transaction.
+	var after *string
+	if err := env.Pool.QueryRow(context.Background(), `SELECT NULLIF(current_setting('app.actor_id', true), '')`).Scan(&after); err != nil {
+		t.Fatalf("read setting: %v", err)
+	}
+	if after != nil {
+		t.Errorf("app.actor_id = %q after the transaction", *after)
+	}
+}
+
+func TestIntegrationAPIUsage(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	if _, err := env.Metadata.CreateObject(ctx, connect.NewRequest(&registryv1.CreateObjectRequest{
+		ApiName: "gadgets", Title: "Gadget", PluralTitle: "Gadgets",
+	})); err != nil {
+		t.Fatalf("create object: %v", err)
+	}
+
+	usage := metrics.NewAPIUsage(24 * time.Hour)
+	for i := range 3 {
+		reqCtx, done := usage.Track(ctx, "", "Query", "svc-reporting")
+		metrics.SetUsageObject(reqCtx, "employees")
+		var err error
+		if i == 2 {
+			err = errors.New("boom")
+		}
+		done(10, err)
+	}
+	_, done := usage.Track(ctx, "employees", "List", "")
+	done(5, nil)
+	if err := usage.Flush(ctx, env.Pool); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	// Flushing again adds to the stored buckets.
+	_, done = usage.Track(ctx, "employees", "List", "")
+	done(1, nil)
+	if err := usage.Flush(ctx, env.Pool); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	svc := service.NewUsageService(env.Pool, env.Cache)
+	resp, err := svc.GetUsage(ctx, connect.NewRequest(&registryv1.GetUsageRequest{}))
+	if err != nil {
+		t.Fatalf("get usage: %v", err)
+	}
+	if len(resp.Msg.Stats) != 2 {
+		t.Fatalf("stats = %d rows, want 2", len(resp.Msg.Stats))
+	}
+	query := resp.Msg.Stats[0]
+	if query.ObjectName != "employees" || query.Method != "Query" || query.Principal != "svc-reporting" {
+		t.Errorf("busiest row = %s/%s/%s", query.ObjectName, query.Method, query.Principal)
+	}
+	if query.Requests != 3 || query.Errors != 1 || query.ResultRecords != 30 {
+		t.Errorf("query row = %d requests, %d errors, %d records", query.Requests, query.Errors, query.ResultRecords)
+	}
+	if list := resp.Msg.Stats[1]; list.Requests != 2 || list.ResultRecords != 6 {
+		t.Errorf("list row = %d requests, %d records, want 2, 6", list.Requests, list.ResultRecords)
+	}
+	if !slices.Contains(resp.Msg.UnusedObjects, "gadgets") {
+		t.Errorf("unused objects %v do not include gadgets", resp.Msg.UnusedObjects)
+	}
+
+	filtered, err := svc.GetUsage(ctx, connect.NewRequest(&registryv1.GetUsageRequest{Principal: "svc-reporting"}))
+	if err != nil {
+		t.Fatalf("get usage: %v", err)
+	}
+	if len(filtered.Msg.Stats) != 1 || len(filtered.Msg.UnusedObjects) != 0 {
+		t.Errorf("filtered = %d rows, %v unused", len(filtered.Msg.Stats), filtered.Msg.UnusedObjects)
+	}
+}
+
+func TestIntegrationObjectStats(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	if _, err := env.Metadata.CreateObject(ctx, connect.NewRequest(&registryv1.CreateObjectRequest{
+		ApiName: "gadgets", Title: "Gadget", PluralTitle: "Gadgets",
+	})); err != nil {
+		t.Fatalf("create object: %v", err)
+	}
+	for range 3 {
+		env.Create(t, "gadgets", map[string]any{})
+	}
+	var employees int64
+	if err := env.Pool.QueryRow(ctx, `SELECT count(*) FROM core.employees`).Scan(&employees); err != nil {
+		t.Fatalf("count employees: %v", err)
+	}
+	// ANALYZE fills n_live_tup, which the estimates read.
+	if _, err := env.Pool.Exec(ctx, `ANALYZE core.employees`); err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+
+	stats := func(lists schema.ListLimits, exact bool) map[string]*registryv1.ObjectStats {
+		t.Helper()
+		svc := service.NewStatsService(env.Pool, env.Cache, metrics.NewHRQL(), lists)
+		resp, err := svc.ObjectStats(ctx, connect.NewRequest(&registryv1.ObjectStatsRequest{Exact: exact}))
+		if err != nil {
+			t.Fatalf("object stats (exact %v): %v", exact, err)
+		}
+		out := make(map[string]*registryv1.ObjectStats)
+		for _, st := range resp.Msg.Objects {
+			out[st.ApiName] = st
+		}
+		return out
+	}
+
+	exact := stats(schema.ListLimits{ExactCountThreshold: 1}, true)
+	for name, want := range map[string]int64{"gadgets": 3, "employees": employees} {
+		st := exact[name]
+		if st == nil || st.RecordCount != want || st.CountIsEstimate {
+			t.Errorf("exact %s = %v, want %d records, not estimated", name, st, want)
+			continue
+		}
+		if st.LastWriteAt == "" || st.StorageBytes <= 0 {
+			t.Errorf("exact %s: last_write_at %q, storage_bytes %d", name, st.LastWriteAt, st.StorageBytes)
+		}
+	}
+
+	// Above the threshold the standard table's count comes from n_live_tup.
+	estimated := stats(schema.ListLimits{ExactCountThreshold: 1}, false)
+	if st := estimated["employees"]; st == nil || !st.CountIsEstimate || st.RecordCount != employees {
+		t.Errorf("estimated employees = %v, want estimate of %d", st, employees)
+	}
+
+	// Below it the count is exact even without exact. Table statistics have
+	// no last write time, so it stays empty.
+	counted := stats(schema.ListLimits{}, false)
+	for name, want := range map[string]int64{"gadgets": 3, "employees": employees} {
+		if st := counted[name]; st == nil || st.RecordCount != want || st.CountIsEstimate {
+			t.Errorf("counted %s = %v, want %d records, not estimated", name, st, want)
+		}
+	}
+	for name, st := range counted {
+		if st.LastWriteAt != "" {
+			t.Errorf("%s: last_write_at = %q without exact, want empty", name, st.LastWriteAt)
+		}
+	}
+}
+
+// --- Test: schema cache admin ---
+
+func TestIntegrationSchemaCacheAdmin(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	obj, err := env.Metadata.CreateObject(ctx, connect.NewRequest(&registryv1.CreateObjectRequest{
+		ApiName: "gadgets", Title: "Gadget", PluralTitle: "Gadgets",
+	}))
+	if err != nil {
+		t.Fatalf("create object: %v", err)
+	}
+
+	// A second cache, loaded now and not kept in sync, stands in for another
+	// instance that missed the changes below.
+	stale := schema.NewCache()
+	if err := stale.Load(ctx, env.Pool); err != nil {
+		t.Fatalf("load cache: %v", err)
+	}
+	admin := service.NewAdminService(env.Pool, stale, nil, nil, nil, nil)
+
+	listing := func() (uint64, map[string]*registryv1.CachedObject) {
+		t.Helper()
+		resp, err := admin.GetSchemaCache(ctx, connect.NewRequest(&registryv1.GetSchemaCacheRequest{}))
+		if err != nil {
+			t.Fatalf("get schema cache: %v", err)
+		}
+		objects := make(map[string]*registryv1.CachedObject)
+		for _, o := range resp.Msg.Cache.Objects {
+			objects[o.ApiName] = o
+		}
+		if resp.Msg.Cache.LoadedAt == "" {
+			t.Error("cache listing has no loaded_at")
+		}
+		return resp.Msg.Cache.Generation, objects
+	}
+	evict := func(apiName string) *registryv1.EvictSchemaCacheObjectResponse {
+		t.Helper()
+		resp, err := admin.EvictSchemaCacheObject(ctx, connect.NewRequest(&registryv1.EvictSchemaCacheObjectRequest{ApiName: apiName}))
+		if err != nil {
+			t.Fatalf("evict %s: %v", apiName, err)
+		}
+		return resp.Msg
+	}
+
+	generation, objects := listing()
+	before := objects["gadgets"]
+	if before == nil || before.Id != obj.Msg.Object.Id || before.IsStandard {
+		t.Fatalf("listed gadgets = %v", before)
+	}
+	employees := objects["employees"]
+
+	if _, err := env.Metadata.CreateField(ctx, connect.NewRequest(&registryv1.CreateFieldRequest{
+		ObjectId: obj.Msg.Object.Id, ApiName: "serial", Title: "Serial", Type: "TEXT",
+	})); err != nil {
+		t.Fatalf("create field: %v", err)
+	}
+	if _, objects := listing(); objects["gadgets"].FieldCount != before.FieldCount {
+		t.Fatalf("stale cache already lists %d fields", objects["gadgets"].FieldCount)
+	}
+
+	// Evicting gadgets reloads it alone.
+	if got := evict("gadgets"); !got.Found || got.Generation != generation+1 {
+		t.Errorf("evict gadgets = %v, want found at generation %d", got, generation+1)
+	}
+	if stale.Get("gadgets").FieldsByAPIName["serial"] == nil {
+		t.Error("evicted object was not reloaded with its new field")
+	}
+	generation, objects = listing()
+	if got := objects["gadgets"].FieldCount; got != before.FieldCount+1 {
+		t.Errorf("gadgets lists %d fields after eviction, want %d", got, before.FieldCount+1)
+	}
+	if objects["employees"].FieldCount != employees.FieldCount {
+		t.Errorf("employees changed: %v, was %v", objects["employees"], employees)
+	}
+
+	// An object gone from the catalog is dropped from the cache.
+	if _, err := env.Metadata.DeleteObject(ctx, connect.NewRequest(&registryv1.DeleteObjectRequest{Id: obj.Msg.Object.Id})); err != nil {
+		t.Fatalf("delete object: %v", err)
+	}
+	if got := evict("gadgets"); got.Found || got.Generation != generation+1 {
+		t.Errorf("evict deleted gadgets = %v, want not found at generation %d", got, generation+1)
+	}
+	if _, objects := listing(); objects["gadgets"] != nil {
+		t.Errorf("deleted gadgets still listed: %v", objects["gadgets"])
+	}
+	if stale.GetByID(uuid.MustParse(obj.Msg.Object.Id)) != nil {
+		t.Error("deleted gadgets still cached by id")
+	}
+
+	// A full reload matches a freshly loaded cache.
+	reloaded, err := admin.ReloadSchemaCache(ctx, connect.NewRequest(&registryv1.ReloadSchemaCacheRequest{}))
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if got := reloaded.Msg.Cache.Generation; got <= generation+1 {
+		t.Errorf("reload generation = %d, want above %d", got, generation+1)
+	}
+	if got, want := len(reloaded.Msg.Cache.Objects), len(env.Cache.Objects()); got != want {
+		t.Errorf("reloaded %d objects, want %d", got, want)
+	}
+}
+
+func TestIntegrationSeedStandardObjects(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+	admin := service.NewAdminService(env.Pool, env.Cache, nil, nil, nil, nil)
+	seed := func(force bool) *registryv1.SeedStandardObjectsResponse {
+		t.Helper()
+		resp, err := admin.SeedStandardObjects(ctx, connect.NewRequest(&registryv1.SeedStandardObjectsRequest{Force: force}))
+		if err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+		return resp.Msg
+	}
+
+	// The migrations registered the same definitions, so the first run only
+	// records its version.
+	first := seed(false)
+	if !first.Applied || first.ObjectsCreated+first.ObjectsUpdated+first.FieldsCreated+first.FieldsUpdated != 0 {
+		t.Errorf("first run = %+v, want applied with no changes", first)
+	}
+	if again := seed(false); again.Applied {
+		t.Errorf("second run applied again: %+v", again)
+	}
+
+	// Drift: a constraint edited by hand and a field deleted.
+	if _, err := env.Pool.Exec(ctx, `
+		UPDATE metadata.fields f SET "is_required" = FALSE FROM metadata.objects o
+		WHERE f."object_id" = o."id" AND o."api_name" = 'employees' AND f."api_name" = 'start_date'`); err != nil {
+		t.Fatalf("edit field: %v", err)
+	}
+	if _, err := env.Pool.Exec(ctx, `
+		DELETE FROM metadata.fields f USING metadata.objects o
+		WHERE f."object_id" = o."id" AND o."api_name" = 'positions' AND f."api_name" = 'end_date'`); err != nil {
+		t.Fatalf("delete field: %v", err)
+	}
+	forced := seed(true)
+	if !forced.Applied || forced.FieldsUpdated != 1 || forced.FieldsCreated != 1 || forced.ObjectsCreated != 0 {
+		t.Errorf("forced run = %+v, want 1 field updated and 1 created", forced)
+	}
+	positions := env.Cache.Get("positions")
+	if positions == nil || positions.FieldsByAPIName["end_date"] == nil {
+		t.Error("positions.end_date missing from the reloaded cache")
+	}
+	if fd := env.Cache.Get("employees").FieldsByAPIName["start_date"]; fd == nil || !fd.IsRequired {
+		t.Error("employees.start_date is not required after the seed")
+	}
+}
+
+// --- Test: schema lint ---
+
+func TestIntegrationLintSchema(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+	admin := service.NewAdminService(env.Pool, env.Cache, nil, nil, nil, nil)
+
+	obj, err := env.Metadata.CreateObject(ctx, connect.NewRequest(&registryv1.CreateObjectRequest{
+		ApiName: "gadgets", Title: "Gadget", PluralTitle: "Gadgets",
+	}))
+	if err != nil {
+		t.Fatalf("create object: %v", err)
+	}
+	for _, name := range []string{"serial_no", "serialno", "notes"} {
+		if _, err := env.Metadata.CreateField(ctx, connect.NewRequest(&registryv1.CreateFieldRequest{
+			ObjectId: obj.Msg.Object.Id, ApiName: name, Title: name, Type: "TEXT",
+		})); err != nil {
+			t.Fatalf("create field %s: %v", name, err)
+		}
+	}
+	env.Create(t, "gadgets", map[string]any{"serial_no": "G-1"})
+
+	lint := func(skipData bool) map[string]*registryv1.LintFinding {
+		t.Helper()
+		resp, err := admin.LintSchema(ctx, connect.NewRequest(&registryv1.LintSchemaRequest{SkipData: skipData}))
+		if err != nil {
+			t.Fatalf("lint: %v", err)
+		}
+		out := make(map[string]*registryv1.LintFinding)
+		for _, f := range resp.Msg.Findings {
+			if f.ObjectName == "gadgets" {
+				out[f.Code+":"+f.Field] = f
+			}
+		}
+		return out
+	}
+
+	got := lint(false)
+	if f := got["name_overlap:serial_no"]; f == nil || f.Severity != "warning" || len(f.Related) != 1 || f.Related[0] != "gadgets.serialno" {
+		t.Errorf("name_overlap = %v, want serial_no related to gadgets.serialno", f)
+	}
+	for _, field := range []string{"serialno", "notes"} {
+		if f := got["unused_field:"+field]; f == nil || f.Severity != "info" {
+			t.Errorf("unused_field %s = %v, want info", field, f)
+		}
+	}
+	if f := got["unused_field:serial_no"]; f != nil {
+		t.Errorf("serial_no has a value but is reported unused: %v", f)
+	}
+	// The metadata service of the env builds no sort indexes.
+	if f := got["unindexed_sort:notes"]; f == nil || f.Severity != "warning" {
+		t.Errorf("unindexed_sort notes = %v, want warning", f)
+	}
+
+	if f := lint(true)["unused_field:notes"]; f != nil {
+		t.Errorf("skip_data still reports unused fields: %v", f)
+	}
+}
+
+// --- Test: metadata change approval ---
+
+func TestIntegrationChangeApproval(t *testing.T) {
+	env := testutil.NewEnv(t)
+	meta := service.NewMetadataService(env.Pool, env.Cache, schema.NewIdentifierPolicy(0), schema.DataLimits{}, false, false, true)
+	review := service.NewReviewService(env.Pool, meta)
+	as := func(principal string) context.Context {
+		return db.WithActor(context.Background(), db.Actor{ID: principal})
+	}
+	reviewReq := func(msg *registryv1.ApproveChangeRequestRequest, perms string) *connect.Request[registryv1.ApproveChangeRequestRequest] {
+		req := connect.NewRequest(msg)
+		req.Header().Set("X-Principal-Permissions", perms)
+		return req
+	}
+	employees := env.Cache.Get("employees")
+
+	_, err := meta.CreateField(as("alice"), connect.NewRequest(&registryv1.CreateFieldRequest{
+		ObjectId: employees.ID.String(), ApiName: "badge_color", Title: "Badge color", Type: "TEXT",
+	}))
+	if connect.CodeOf(err) != connect.CodeFailedPrecondition {
+		t.Fatalf("held CreateField error = %v, want FAILED_PRECONDITION", err)
+	}
+	var pending *registryv1.ChangePending
+	var cerr *connect.Error
+	if errors.As(err, &cerr) {
+		for _, d := range cerr.Details() {
+			if v, derr := d.Value(); derr == nil {
+				pending, _ = v.(*registryv1.ChangePending)
+			}
+		}
+	}
+	if pending == nil || pending.Reason != "CHANGE_PENDING_APPROVAL" {
+		t.Fatalf("held CreateField detail = %v, want ChangePending", pending)
+	}
+	if env.Cache.Get("employees").FieldsByAPIName["badge_color"] != nil {
+		t.Fatal("held field was created")
+	}
+
+	list, err := review.ListChangeRequests(context.Background(), connect.NewRequest(&registryv1.ListChangeRequestsRequest{Status: "PENDING"}))
+	if err != nil {
+		t.Fatalf("list change requests: %v", err)
+	}
+	if len(list.Msg.ChangeRequests) != 1 || list.Msg.ChangeRequests[0].Method != "CreateField" || list.Msg.ChangeRequests[0].RequestedBy != "alice" {
+		t.Fatalf("pending change requests = %v, want alice's CreateField", list.Msg.ChangeRequests)
+	}
+
+	approve := &registryv1.ApproveChangeRequestRequest{Id: pending.ChangeRequestId, Comment: "ok"}
+	if _, err := review.ApproveChangeRequest(as("bob"), reviewReq(approve, "")); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Errorf("approve without permission error = %v, want PERMISSION_DENIED", err)
+	}
+	if _, err := review.ApproveChangeRequest(as("alice"), reviewReq(approve, "metadata:review")); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Errorf("self-approval error = %v, want PERMISSION_DENIED", err)
+	}
+	resp, err := review.ApproveChangeRequest(as("bob"), reviewReq(approve, "metadata:review"))
+	if err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	if cr := resp.Msg.ChangeRequest; cr.Status != "APPROVED" || cr.ReviewedBy != "bob" || cr.ReviewComment != "ok" {
+		t.Errorf("approved change request = %v", cr)
+	}
+	if env.Cache.Get("employees").FieldsByAPIName["badge_color"] == nil {
+		t.Error("approved field missing from the reloaded cache")
+	}
+	if _, err := review.ApproveChangeRequest(as("bob"), reviewReq(approve, "metadata:review")); connect.CodeOf(err) != connect.CodeFailedPrecondition {
+		t.Errorf("second approval error = %v, want FAILED_PRECONDITION", err)
+	}
+
+	// A change that no longer applies is marked FAILED.
+	_, err = meta.CreateField(as("alice"), connect.NewRequest(&registryv1.CreateFieldRequest{
+		ObjectId: employees.ID.String(), ApiName: "badge_color", Title: "Badge color", Type: "TEXT",
+	}))
+	if connect.CodeOf(err) != connect.CodeFailedPrecondition || !errors.As(err, &cerr) {
+		t.Fatalf("held duplicate CreateField error = %v", err)
+	}
+	list, err = review.ListChangeRequests(context.Background(), connect.NewRequest(&registryv1.ListChangeRequestsRequest{Status: "PENDING"}))
+	if err != nil || len(list.Msg.ChangeRequests) != 1 {
+		t.Fatalf("pending change requests = %v, %v", list, err)
+	}
+	dup := &registryv1.ApproveChangeRequestRequest{Id: list.Msg.ChangeRequests[0].Id}
+	if _, err := review.ApproveChangeRequest(as("bob"), reviewReq(dup, "metadata:review")); err == nil {
+		t.Error("approving a duplicate field succeeded")
+	}
+	list, err = review.ListChangeRequests(context.Background(), connect.NewRequest(&registryv1.ListChangeRequestsRequest{Status: "FAILED"}))
+	if err != nil || len(list.Msg.ChangeRequests) != 1 || list.Msg.ChangeRequests[0].Error == "" {
+		t.Errorf("failed change requests = %v, %v", list, err)
+	}
+}
+
+// --- Test: connection statement cache ---
+
+// statementCachePool opens a pool on base's database with its own statement
+// cache settings and stats.
+func statementCachePool(tb testing.TB, base *pgxpool.Pool, size int) (*service.RegistryService, *db.StatementCacheStats) {
+	tb.Helper()
+	ctx := context.Background()
+	stats := db.NewStatementCacheStats()
+	pool, err := db.NewPool(ctx, base.Config().ConnString(), stats, size)
+	if err != nil {
+		tb.Fatalf("connect: %v", err)
+	}
+	tb.Cleanup(pool.Close)
+	cache := schema.NewCache()
+	if err := cache.Load(ctx, pool); err != nil {
+		tb.Fatalf("load schema cache: %v", err)
+	}
+	registry := service.NewRegistryService(pool, cache, nil, hrqlpg.ExpandAuto, db.NewSnapshots(pool, time.Minute, 0), webhook.NewValidator(nil), service.QueryLimits{}, schema.DataLimits{}, hrqlpg.NewRandomCursorSigner())
+	return registry, stats
+}
+
+var seedEmployeeNumbers = []string{"T-001", "T-002", "T-003", "T-004", "T-005"}
+
+func TestIntegrationStatementCache(t *testing.T) {
+	registry, stats := statementCachePool(t, testutil.NewDatabase(t), 512)
+
+	// Lists differing only in a filter value share their statements.
+	for _, number := range seedEmployeeNumbers {
+		req := &registryv1.ListRequest{ObjectName: "employees", Filters: map[string]string{"employee_number": "eq." + number}}
+		resp, err := registry.List(context.Background(), connect.NewRequest(req))
+		if err != nil {
+			t.Fatalf("list %s: %v", number, err)
+		}
+		if len(resp.Msg.Results) != 1 {
+			t.Errorf("list %s returned %d rows, want 1", number, len(resp.Msg.Results))
+		}
+	}
+	misses := stats.Misses()
+	if stats.Hits() == 0 {
+		t.Errorf("statement cache hits = 0 (misses %d), want repeated lists to hit", misses)
+	}
+
+	req := &registryv1.ListRequest{ObjectName: "employees", Filters: map[string]string{"employee_number": "eq.T-001"}}
+	if _, err := registry.List(context.Background(), connect.NewRequest(req)); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if stats.Misses() != misses {
+		t.Errorf("statement cache misses went from %d to %d on a repeated list", misses, stats.Misses())
+	}
+}
+
+// BenchmarkIntegrationListStatementCache compares List latency with and
+// without the statement cache, for lists of one shape whose filter value
+// changes every call.
+func BenchmarkIntegrationListStatementCache(b *testing.B) {
+	base := testutil.NewDatabase(b)
+	for _, bc := range []struct {
+		name string
+		size int
+	}{{"cached", 512}, {"uncached", 0}} {
+		b.Run(bc.name, func(b *testing.B) {
+			registry, stats := statementCachePool(b, base, bc.size)
+			ctx := context.Background()
+			i := 0
+			for b.Loop() {
+				number := seedEmployeeNumbers[i%len(seedEmployeeNumbers)]
+				i++
+				req := &registryv1.ListRequest{ObjectName: "employees", Filters: map[string]string{"employee_number": "eq." + number}}
+				if _, err := registry.List(ctx, connect.NewRequest(req)); err != nil {
+					b.Fatal(err)
+				}
+			}
+			if lookups := stats.Hits() + stats.Misses(); bc.size > 0 && lookups > 0 {
+				b.ReportMetric(float64(stats.Hits())/float64(lookups), "hit-ratio")
+			}
+		})
+	}
+}
+
+func TestIntegrationListQueryEcho(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	req := &registryv1.ListRequest{
+		ObjectName: "employees",
+		Filters:    map[string]string{"employment_type": "eq.FULL_TIME"},
+		Order:      "start_date.desc",
+		Expand:     "manager",
+	}
+	resp, err := env.Registry.List(ctx, connect.NewRequest(req))
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	echo := resp.Msg.QueryEcho
+	if echo.GetLimit() != 50 || echo.GetOrder() != "start_date.desc" {
+		t.Errorf("echo limit, order = %d, %q; want 50, start_date.desc", echo.GetLimit(), echo.GetOrder())
+	}
+	conds := echo.GetConditions()
+	if len(conds) != 1 || conds[0].Field != "employment_type" || conds[0].Filter != "eq.FULL_TIME" {
+		t.Errorf("echo conditions = %v", conds)
+	}
+	if exp := echo.GetExpand(); len(exp) != 1 || exp[0] != "manager" {
+		t.Errorf("echo expand = %v, want [manager]", exp)
+	}
+}
+
+func TestIntegrationListReferencedExpands(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	resp, err := env.Registry.List(ctx, connect.NewRequest(&registryv1.ListRequest{
+		ObjectName: "employees",
+		Filters:    map[string]string{"id": "eq." + testutil.Org.Engineer1},
+		Expand:     "manager,manager.manager",
+		ExpandMode: "referenced",
+	}))
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(resp.Msg.Results) != 1 {
+		t.Fatalf("got %d results, want 1", len(resp.Msg.Results))
+	}
+	if got := resp.Msg.Results[0].Fields["manager"].GetStringValue(); got != testutil.Org.CTO {
+		t.Errorf("manager = %v, want the CTO's id", resp.Msg.Results[0].Fields["manager"])
+	}
+	cto := resp.Msg.Included[testutil.Org.CTO]
+	if cto == nil {
+		t.Fatalf("included = %v, want the CTO", resp.Msg.Included)
+	}
+	if got := cto.Fields["manager"].GetStringValue(); got != testutil.Org.CEO {
+		t.Errorf("included CTO manager = %v, want the CEO's id", cto.Fields["manager"])
+	}
+	if resp.Msg.Included[testutil.Org.CEO] == nil {
+		t.Errorf("included = %v, want the CEO", resp.Msg.Included)
+	}
+
+	// Every employee pointing at the same manager shares one included record.
+	all, err := env.Registry.List(ctx, connect.NewRequest(&registryv1.ListRequest{
+		ObjectName: "employees", Expand: "manager", ExpandMode: "referenced",
+	}))
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	managers := map[string]bool{}
+	for _, rec := range all.Msg.Results {
+		if id := rec.Fields["manager"].GetStringValue(); id != "" {
+			managers[id] = true
+		}
+	}
+	if len(all.Msg.Included) != len(managers) {
+		t.Errorf("included %d records for %d distinct managers", len(all.Msg.Included), len(managers))
+	}
+
+	_, err = env.Registry.List(ctx, connect.NewRequest(&registryv1.ListRequest{
+		ObjectName: "employees", Expand: "manager", ExpandMode: "referenced", Raw: true,
+	}))
+	if connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("raw referenced list: expected INVALID_ARGUMENT, got %v", err)
+	}
+}
+
+func TestIntegrationJSONField(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	obj := env.Cache.Get("employees")
+	field, err := env.Metadata.CreateField(ctx, connect.NewRequest(&registryv1.CreateFieldRequest{
+		ObjectId: obj.ID.String(), ApiName: "preferences", Title: "Preferences", Type: "JSON",
+	}))
+	if err != nil {
+		t.Fatalf("create field: %v", err)
+	}
+	if field.Msg.Field.IsFilterable || field.Msg.Field.IsSortable {
+		t.Errorf("created field: is_filterable = %v, is_sortable = %v, want false, false", field.Msg.Field.IsFilterable, field.Msg.Field.IsSortable)
+	}
+	for name, req := range map[string]*registryv1.CreateFieldRequest{
+		"unique":   {ObjectId: obj.ID.String(), ApiName: "blob", Title: "Blob", Type: "JSON", IsUnique: true},
+		"sortable": {ObjectId: obj.ID.String(), ApiName: "blob", Title: "Blob", Type: "JSON", IsSortable: new(true)},
+	} {
+		if _, err := env.Metadata.CreateField(ctx, connect.NewRequest(req)); connect.CodeOf(err) != connect.CodeInvalidArgument {
+			t.Errorf("%s JSON field: err = %v, want INVALID_ARGUMENT", name, err)
+		}
+	}
+
+	prefs := map[string]any{"theme": "dark", "shortcuts": []any{"g i", "g p"}, "beta": map[string]any{"enabled": true, "cohort": 3.0}}
+	data, _ := structpb.NewStruct(map[string]any{"preferences": prefs})
+	if _, err := env.Registry.Update(ctx, connect.NewRequest(&registryv1.UpdateRequest{ObjectName: "employees", Id: testutil.Org.CTO, Data: data})); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	record, err := env.Registry.Get(ctx, connect.NewRequest(&registryv1.GetRequest{ObjectName: "employees", Id: testutil.Org.CTO, Select: "preferences"}))
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	got, _ := json.Marshal(record.Msg.Record.Fields["preferences"].AsInterface())
+	want, _ := json.Marshal(prefs)
+	if string(got) != string(want) {
+		t.Errorf("preferences = %s, want %s", got, want)
+	}
+
+	list := func(filter string) (*registryv1.ListResponse, error) {
+		resp, err := env.Registry.List(ctx, connect.NewRequest(&registryv1.ListRequest{ObjectName: "employees", Filters: map[string]string{"preferences": filter}}))
+		if err != nil {
+			return nil, err
+		}
+		return resp.Msg, nil
+	}
+	if _, err := list("is.not_null"); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Errorf("filter before is_filterable: err = %v, want PERMISSION_DENIED", err)
+	}
+	if _, err := env.Metadata.UpdateField(ctx, connect.NewRequest(&registryv1.UpdateFieldRequest{
+		ObjectId: obj.ID.String(), Id: field.Msg.Field.Id, IsFilterable: new(true),
+	})); err != nil {
+		t.Fatalf("set is_filterable: %v", err)
+	}
+	resp, err := list("is.not_null")
+	if err != nil {
+		t.Fatalf("is.not_null: %v", err)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].Fields["id"].GetStringValue() != testutil.Org.CTO {
+		t.Errorf("is.not_null: %d results, want the CTO", len(resp.Results))
+	}
+	if _, err := list("eq.dark"); connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("eq filter: err = %v, want INVALID_ARGUMENT", err)
+	}
+	if _, err := env.Org.Query(ctx, connect.NewRequest(&registryv1.QueryRequest{Query: `employees | sort_by(.preferences)`})); connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("sort_by: err = %v, want INVALID_ARGUMENT", err)
+	}
+	if _, err := env.Metadata.UpdateField(ctx, connect.NewRequest(&registryv1.UpdateFieldRequest{
+		ObjectId: obj.ID.String(), Id: field.Msg.Field.Id, IsSortable: new(true),
+	})); connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("set is_sortable: err = %v, want INVALID_ARGUMENT", err)
+	}
+}
+
+func TestIntegrationDeleteCascade(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	offices, err := env.Metadata.CreateObject(ctx, connect.NewRequest(&registryv1.CreateObjectRequest{
+		ApiName: "offices", Title: "Office", PluralTitle: "Offices",
+	}))
+	if err != nil {
+		t.Fatalf("create object: %v", err)
+	}
+	officeID := offices.Msg.Object.Id
+	if _, err := env.Metadata.CreateField(ctx, connect.NewRequest(&registryv1.CreateFieldRequest{
+		ObjectId: officeID, ApiName: "city", Title: "City", Type: "TEXT",
+	})); err != nil {
+		t.Fatalf("create field: %v", err)
+	}
+	emp := env.Cache.Get("employees")
+	if _, err := env.Metadata.CreateField(ctx, connect.NewRequest(&registryv1.CreateFieldRequest{
+		ObjectId: emp.ID.String(), ApiName: "office", Title: "Office", Type: "LOOKUP", LookupObjectId: officeID,
+	})); err != nil {
+		t.Fatalf("create lookup: %v", err)
+	}
+
+	lima := env.Create(t, "offices", map[string]any{"city": "Lima"})
+	env.Create(t, "offices", map[string]any{"city": "Oslo"})
+	data, _ := structpb.NewStruct(map[string]any{"office": lima.Fields["id"].GetStringValue()})
+	if _, err := env.Registry.Update(ctx, connect.NewRequest(&registryv1.UpdateRequest{ObjectName: "employees", Id: testutil.Org.CTO, Data: data})); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	preview, err := env.Metadata.PreviewDelete(ctx, connect.NewRequest(&registryv1.PreviewDeleteRequest{Id: officeID}))
+	if err != nil {
+		t.Fatalf("preview: %v", err)
+	}
+	if preview.Msg.Records != 2 || len(preview.Msg.References) != 1 {
+		t.Fatalf("preview = %v, want 2 records and 1 reference", preview.Msg)
+	}
+	if ref := preview.Msg.References[0]; ref.ObjectApiName != "employees" || ref.FieldApiName != "office" || ref.Records != 1 || ref.IsStandard {
+		t.Errorf("reference = %v", ref)
+	}
+
+	del := func(cascade string) error {
+		_, err := env.Metadata.DeleteObject(ctx, connect.NewRequest(&registryv1.DeleteObjectRequest{Id: officeID, Cascade: cascade}))
+		return err
+	}
+	if err := del(""); connect.CodeOf(err) != connect.CodeFailedPrecondition || !strings.Contains(err.Error(), "employees.office") {
+		t.Fatalf("delete with references: err = %v, want FAILED_PRECONDITION naming employees.office", err)
+	}
+	if err := del("nullify"); err != nil {
+		t.Fatalf("delete with nullify: %v", err)
+	}
+	if env.Cache.Get("offices") != nil || env.Cache.Get("employees").FieldsByAPIName["office"] != nil {
+		t.Error("object or referencing field still cached")
+	}
+	var kept bool
+	if err := env.Pool.QueryRow(ctx, `SELECT custom_fields ? 'office' FROM core.employees WHERE id = $1`, testutil.Org.CTO).Scan(&kept); err != nil {
+		t.Fatalf("read employee: %v", err)
+	}
+	if kept {
+		t.Error("employee still holds the office reference")
+	}
+}
+
+func TestIntegrationTypedResults(t *testing.T) {
+	env := testutil.NewEnv(t)
+
+	count := env.Query(t, `employees | count`, "")
+	if n, ok := count.Result.GetValue().(*registryv1.ResultValue_IntValue); !ok || float64(n.IntValue) != count.GetScalar() {
+		t.Errorf("count result = %v, want an int64 equal to scalar %v", count.Result, count.GetScalar())
+	}
+	avg := env.Query(t, `employees | .start_date | years_since | avg`, "")
+	if _, ok := avg.Result.GetValue().(*registryv1.ResultValue_DoubleValue); !ok {
+		t.Errorf("avg result = %v, want a double", avg.Result)
+	}
+	first := env.Query(t, `employees | .start_date | min`, "")
+	if _, err := time.Parse(time.DateOnly, first.Result.GetStringValue()); err != nil || first.Scalar != nil {
+		t.Errorf("min of a date: result = %v, scalar = %v, want the date as a string", first.Result, first.Scalar)
+	}
+	empty := env.Query(t, `employees | where(.employee_number == "NOPE") | .start_date | years_since | avg`, "")
+	if _, ok := empty.Result.GetValue().(*registryv1.ResultValue_NullValue); !ok || !empty.ScalarNull {
+		t.Errorf("avg of no values: result = %v, want null", empty.Result)
+	}
+
+	reports := env.Query(t, fmt.Sprintf(`reports_to(self, "%s")`, testutil.Org.CEO), testutil.Org.Engineer1)
+	if v, ok := reports.Result.GetValue().(*registryv1.ResultValue_BoolValue); !ok || !v.BoolValue {
+		t.Errorf("reports_to result = %v, want true", reports.Result)
+	}
+	list := env.Query(t, `employees`, "")
+	if got := list.Result.GetListValue().GetValues(); len(got) == 0 || len(got) != len(list.Results) {
+		t.Errorf("list result holds %d records, want the %d results", len(got), len(list.Results))
+	}
+	if ids := env.Query(t, `employees | ids`, ""); ids.Result != nil {
+		t.Errorf("ids result = %v, want unset", ids.Result)
+	}
+}
+
+func TestIntegrationPickProjection(t *testing.T) {
+	env := testutil.NewEnv(t)
+
+	first := env.Query(t, `reports(self, 1) | first`, testutil.Org.CEO)
+	if len(first.Results) != 1 {
+		t.Fatalf("first report: %d results, want 1", len(first.Results))
+	}
+	want := first.Results[0].Fields["employee_number"].GetStringValue()
+	number := env.Query(t, `reports(self, 1) | first | .employee_number`, testutil.Org.CEO)
+	if got := number.Result.GetStringValue(); got == "" || got != want {
+		t.Errorf("first | .employee_number = %v, want %q", number.Result, want)
+	}
+	if len(number.Results) != 0 || number.Scalar != nil {
+		t.Errorf("a text projection sets only result, got results %v, scalar %v", number.Results, number.Scalar)
+	}
+
+	start := env.Query(t, `employees | sort_by(.start_date) | first | .start_date`, "")
+	if _, err := time.Parse(time.DateOnly, start.Result.GetStringValue()); err != nil {
+		t.Errorf("first | .start_date = %v, want a date", start.Result)
+	}
+	none := env.Query(t, `employees | where(.employee_number == "NOPE") | first | .employee_number`, "")
+	if _, ok := none.Result.GetValue().(*registryv1.ResultValue_NullValue); !ok || !none.ScalarNull {
+		t.Errorf("projection of no record = %v, want null", none.Result)
+	}
+}
+
+func TestIntegrationSchemaWatcher(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// A second server's cache, kept current only by its watcher.
+	other := schema.NewCache()
+	if err := other.Load(ctx, env.Pool); err != nil {
+		t.Fatalf("load schema cache: %v", err)
+	}
+	schema.NewWatcher(env.Pool, other, 100*time.Millisecond).Start(ctx)
+	eventually := func(what string, cond func() bool) {
+		t.Helper()
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+			if cond() {
+				return
+			}
+		}
+		t.Errorf("%s: the watched cache never caught up", what)
+	}
+
+	emp := env.Cache.Get("employees")
+	if _, err := env.Metadata.CreateField(ctx, connect.NewRequest(&registryv1.CreateFieldRequest{
+		ObjectId: emp.ID.String(), ApiName: "nickname", Title: "Nickname", Type: "TEXT",
+	})); err != nil {
+		t.Fatalf("create field: %v", err)
+	}
+	eventually("CreateField", func() bool { return other.Get("employees").FieldsByAPIName["nickname"] != nil })
+
+	// Writes outside the service are announced by the same triggers.
+	if _, err := env.Pool.Exec(ctx, `UPDATE metadata.objects SET title = 'Staff member' WHERE api_name = 'employees'`); err != nil {
+		t.Fatalf("update object: %v", err)
+	}
+	eventually("direct update", func() bool { return other.Get("employees").Title == "Staff member" })
+
+	// A rolled back write is never announced.
+	var before int64
+	if err := env.Pool.QueryRow(ctx, `SELECT max(id) FROM metadata.schema_outbox`).Scan(&before); err != nil {
+		t.Fatalf("read outbox: %v", err)
+	}
+	tx, err := env.Pool.Begin(ctx)
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE metadata.objects SET title = 'Rolled back' WHERE api_name = 'employees'`); err != nil {
+		t.Fatalf("update object: %v", err)
+	}
+	_ = tx.Rollback(ctx)
+	var after int64
+	if err := env.Pool.QueryRow(ctx, `SELECT max(id) FROM metadata.schema_outbox`).Scan(&after); err != nil {
+		t.Fatalf("read outbox: %v", err)
+	}
+	if after != before {
+		t.Errorf("outbox moved from %d to %d on a rolled back write", before, after)
+	}
+
+	// Outbox ids follow insert order, not commit order: a change committing
+	// after a newer one was applied still reaches the cache.
+	older, err := env.Pool.Begin(ctx)
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	defer older.Rollback(ctx)
+	if _, err := older.Exec(ctx, `UPDATE metadata.objects SET title = 'Older writer' WHERE api_name = 'departments'`); err != nil {
+		t.Fatalf("update object: %v", err)
+	}
+	if _, err := env.Pool.Exec(ctx, `UPDATE metadata.objects SET title = 'Newer writer' WHERE api_name = 'employees'`); err != nil {
+		t.Fatalf("update object: %v", err)
+	}
+	eventually("newer writer", func() bool { return other.Get("employees").Title == "Newer writer" })
+	if err := older.Commit(ctx); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	eventually("older writer", func() bool { return other.Get("departments").Title == "Older writer" })
+}
+
+// --- Test: caching headers ---
+
+func TestIntegrationCacheHeaders(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	if _, err := env.Metadata.CreateObject(ctx, connect.NewRequest(&registryv1.CreateObjectRequest{
+		ApiName: "countries", Title: "Country", PluralTitle: "Countries", CacheMaxAgeSeconds: 3600,
+	})); err != nil {
+		t.Fatalf("create object: %v", err)
+	}
+	rec := env.Create(t, "countries", map[string]any{})
+
+	got, err := env.Registry.Get(ctx, connect.NewRequest(&registryv1.GetRequest{ObjectName: "countries", Id: rec.Fields["id"].GetStringValue()}))
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if cc := got.Header().Get("Cache-Control"); cc != "public, max-age=3600" {
+		t.Errorf("get Cache-Control = %q", cc)
+	}
+	if got.Header().Get("Last-Modified") == "" {
+		t.Error("get: missing Last-Modified")
+	}
+
+	list := func() string {
+		t.Helper()
+		resp, err := env.Registry.List(ctx, connect.NewRequest(&registryv1.ListRequest{ObjectName: "countries"}))
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		return resp.Header().Get("ETag")
+	}
+	first := list()
+	if !strings.HasPrefix(first, `W/"`) {
+		t.Errorf("list ETag = %q, want a weak tag", first)
+	}
+	if second := list(); second != first {
+		t.Errorf("list ETag changed between identical reads: %q, %q", first, second)
+	}
+
+	// Objects without a max age are not cacheable.
+	resp, err := env.Registry.List(ctx, connect.NewRequest(&registryv1.ListRequest{ObjectName: "employees"}))
+	if err != nil {
+		t.Fatalf("list employees: %v", err)
+	}
+	if resp.Header().Get("Cache-Control") != "" || resp.Header().Get("ETag") != "" {
+		t.Errorf("employees list carries caching headers: %v", resp.Header())
+	}
+}
+
+// --- Test: field masking ---
+
+func TestIntegrationFieldMasking(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	obj, err := env.Metadata.CreateObject(ctx, connect.NewRequest(&registryv1.CreateObjectRequest{
+		ApiName: "contractors", Title: "Contractor", PluralTitle: "Contractors",
+	}))
+	if err != nil {
+		t.Fatalf("create object: %v", err)
+	}
+	field := func(name, typ string, masking *registryv1.FieldMasking) (*registryv1.FieldMeta, error) {
+		resp, err := env.Metadata.CreateField(ctx, connect.NewRequest(&registryv1.CreateFieldRequest{
+			ObjectId: obj.Msg.Object.Id, ApiName: name, Title: name, Type: typ, Masking: masking,
+		}))
+		if err != nil {
+			return nil, err
+		}
+		return resp.Msg.Field, nil
+	}
+	if _, err := field("badge", "NUMBER", &registryv1.FieldMasking{DefaultTransform: "last4"}); connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("last4 on a NUMBER field: expected INVALID_ARGUMENT, got %v", err)
+	}
+	tax, err := field("tax_id", "TEXT", &registryv1.FieldMasking{
+		DefaultTransform: "last4",
+		Rules:            []*registryv1.MaskingRule{{Permission: "payroll:read", Transform: "none"}},
+	})
+	if err != nil {
+		t.Fatalf("create tax_id: %v", err)
+	}
+	if tax.Masking.GetDefaultTransform() != "last4" || len(tax.Masking.GetRules()) != 1 {
+		t.Errorf("tax_id masking = %v", tax.Masking)
+	}
+	if _, err := field("email", "EMAIL", &registryv1.FieldMasking{DefaultTransform: "email_domain"}); err != nil {
+		t.Fatalf("create email: %v", err)
+	}
+	if _, err := field("born_on", "DATE", &registryv1.FieldMasking{
+		DefaultTransform: "year",
+		Rules:            []*registryv1.MaskingRule{{Permission: "payroll:read", Transform: "hide"}},
+	}); err != nil {
+		t.Fatalf("create born_on: %v", err)
+	}
+	rec := env.Create(t, "contractors", map[string]any{"tax_id": "123-45-6789", "email": "ana@example.com", "born_on": "1990-05-17"})
+	id := rec.Fields["id"].GetStringValue()
+
+	get := func(perms string) map[string]any {
+		t.Helper()
+		req := connect.NewRequest(&registryv1.GetRequest{ObjectName: "contractors", Id: id})
+		if perms != "" {
+			req.Header().Set("X-Principal-Permissions", perms)
+		}
+		resp, err := env.Registry.Get(ctx, req)
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		return resp.Msg.Record.AsMap()
+	}
+	masked := get("")
+	if masked["tax_id"] != "***6789" || masked["email"] != "example.com" || masked["born_on"] != "1990" {
+		t.Errorf("masked record = %v", masked)
+	}
+	payroll := get("payroll:read")
+	if payroll["tax_id"] != "123-45-6789" || payroll["email"] != "example.com" || payroll["born_on"] != nil {
+		t.Errorf("payroll record = %v", payroll)
+	}
+
+	list, err := env.Registry.List(ctx, connect.NewRequest(&registryv1.ListRequest{ObjectName: "contractors"}))
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if got := list.Msg.Results[0].AsMap()["tax_id"]; got != "***6789" {
+		t.Errorf("listed tax_id = %v", got)
+	}
+
+	// Projections would expose the masked value.
+	query := func(perms string) error {
+		req := connect.NewRequest(&registryv1.QueryRequest{Query: "contractors | first | .tax_id"})
+		if perms != "" {
+			req.Header().Set("X-Principal-Permissions", perms)
+		}
+		_, err := env.Org.Query(ctx, req)
+		return err
+	}
+	if err := query(""); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Errorf("masked projection: expected PERMISSION_DENIED, got %v", err)
+	}
+	if err := query("payroll:read"); err != nil {
+		t.Errorf("unmasked projection: %v", err)
+	}
+
+	if _, err := env.Metadata.UpdateField(ctx, connect.NewRequest(&registryv1.UpdateFieldRequest{
+		ObjectId: obj.Msg.Object.Id, Id: tax.Id, ClearMasking: true,
+	})); err != nil {
+		t.Fatalf("clear masking: %v", err)
+	}
+	if got := get("")["tax_id"]; got != "123-45-6789" {
+		t.Errorf("tax_id after clear_masking = %v", got)
+	}
+}
+
+// --- Test: terminated employees ---
+
+func TestIntegrationTerminatedEmployees(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	if _, err := env.Pool.Exec(ctx, `UPDATE core.employees SET "end_date" = '2021-06-30' WHERE "id" = $1`, testutil.Org.Engineer1); err != nil {
+		t.Fatalf("terminate Engineer1: %v", err)
+	}
+
+	if ids := env.QueryIDs(t, fmt.Sprintf(`reports("%s", 1)`, testutil.Org.CTO), ""); len(ids) != 1 || ids[0] != testutil.Org.Engineer2 {
+		t.Errorf("direct reports of CTO = %v, want only Engineer2", ids)
+	}
+	if ids := env.QueryIDs(t, fmt.Sprintf(`reports("%s", 1, include_terminated: true)`, testutil.Org.CTO), ""); len(ids) != 2 {
+		t.Errorf("direct reports of CTO with include_terminated: true = %v, want 2", ids)
+	}
+	if ids := env.QueryIDs(t, `employees | where(reports(., 1) | count == 1)`, ""); len(ids) != 1 || ids[0] != testutil.Org.CTO {
+		t.Errorf("managers of one employed report = %v, want the CTO", ids)
+	}
+
+	resp, err := env.Org.Query(ctx, connect.NewRequest(&registryv1.QueryRequest{
+		Query:             fmt.Sprintf(`reports("%s", 1)`, testutil.Org.CTO),
+		IncludeTerminated: true,
+	}))
+	if err != nil {
+		t.Fatalf("query with include_terminated: %v", err)
+	}
+	if len(resp.Msg.Results) != 2 {
+		t.Errorf("include_terminated query returned %d records, want 2", len(resp.Msg.Results))
+	}
+
+	list := func(include bool) int {
+		resp, err := env.Registry.List(ctx, connect.NewRequest(&registryv1.ListRequest{ObjectName: "employees", IncludeTerminated: include}))
+		if err != nil {
+			t.Fatalf("list employees (include_terminated=%v): %v", include, err)
+		}
+		return len(resp.Msg.Results)
+	}
+	if got := list(false); got != 4 {
+		t.Errorf("employees list = %d records, want 4 without Engineer1", got)
+	}
+	if got := list(true); got != 5 {
+		t.Errorf("employees list with include_terminated = %d records, want 5", got)
+	}
+}
+
+// --- Test: inline related counts ---
+
+func TestIntegrationExpandCount(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	if _, err := env.Pool.Exec(ctx, `UPDATE core.employees SET "end_date" = '2021-06-30' WHERE "id" = $1`, testutil.Org.Engineer1); err != nil {
+		t.Fatalf("terminate Engineer1: %v", err)
+	}
+
+	counts := func(include bool) map[string]float64 {
+		resp, err := env.Registry.List(ctx, connect.NewRequest(&registryv1.ListRequest{
+			ObjectName: "employees", Expand: "manager,reports:count", IncludeTerminated: include,
+		}))
+		if err != nil {
+			t.Fatalf("list employees with reports:count: %v", err)
+		}
+		out := make(map[string]float64)
+		for _, r := range resp.Msg.Results {
+			out[r.Fields["id"].GetStringValue()] = r.Fields["reports_count"].GetNumberValue()
+		}
+		return out
+	}
+	got := counts(false)
+	for id, want := range map[string]float64{testutil.Org.CEO: 3, testutil.Org.CTO: 1, testutil.Org.Engineer2: 0} {
+		if got[id] != want {
+			t.Errorf("reports_count of %s = %v, want %v", id, got[id], want)
+		}
+	}
+	if got := counts(true); got[testutil.Org.CEO] != 4 || got[testutil.Org.CTO] != 2 {
+		t.Errorf("reports_count with include_terminated: CEO %v, CTO %v; want 4 and 2", got[testutil.Org.CEO], got[testutil.Org.CTO])
+	}
+
+	resp, err := env.Registry.Get(ctx, connect.NewRequest(&registryv1.GetRequest{
+		ObjectName: "departments", Id: testutil.Org.Engineering, Expand: "employees:count",
+	}))
+	if err != nil {
+		t.Fatalf("get department with employees:count: %v", err)
+	}
+	if n := resp.Msg.Record.Fields["employees_count"].GetNumberValue(); n != 3 {
+		t.Errorf("employees_count of Engineering = %v, want 3", n)
+	}
+}
+
+// --- Test: streaming HRQL execution ---
+
+func TestIntegrationExecuteStream(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	mux := http.NewServeMux()
+	mux.Handle(env.Org.RegisterHandler())
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	client := registryv1connect.NewOrgServiceClient(srv.Client(), srv.URL)
+
+	// run streams req and returns the messages' batch sizes and the last message.
+	run := func(req *registryv1.ExecuteStreamRequest) ([]int, *registryv1.ExecuteStreamResponse) {
+		t.Helper()
+		stream, err := client.ExecuteStream(ctx, connect.NewRequest(req))
+		if err != nil {
+			t.Fatalf("%s: %v", req.Query, err)
+		}
+		defer stream.Close()
+		var (
+			sizes []int
+			last  *registryv1.ExecuteStreamResponse
+		)
+		for stream.Receive() {
+			last = stream.Msg()
+			sizes = append(sizes, len(last.Results)+len(last.Ids))
+		}
+		if err := stream.Err(); err != nil {
+			t.Fatalf("%s: %v", req.Query, err)
+		}
+		return sizes, last
+	}
+
+	sizes, last := run(&registryv1.ExecuteStreamRequest{Query: "employees", BatchSize: 2})
+	if !slices.Equal(sizes, []int{2, 2, 1}) || !last.Done || last.Total != 5 || last.Truncated {
+		t.Errorf("employees in batches of 2: sizes %v, last %v", sizes, last)
+	}
+
+	sizes, last = run(&registryv1.ExecuteStreamRequest{Query: fmt.Sprintf(`reports("%s") | ids`, testutil.Org.CEO), Limit: 3})
+	if !slices.Equal(sizes, []int{3}) || last.Total != 3 || !last.Truncated {
+		t.Errorf("reports ids with limit 3: sizes %v, last %v", sizes, last)
+	}
+
+	stream, err := client.ExecuteStream(ctx, connect.NewRequest(&registryv1.ExecuteStreamRequest{Query: "employees | count"}))
+	if err == nil {
+		for stream.Receive() {
+		}
+		err = stream.Err()
+	}
+	if connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("scalar query: error %v, want INVALID_ARGUMENT", err)
+	}
+}
+
+func TestIntegrationExecuteStreamOneConnection(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// A stream with batch expands configured must not need a second
+	// connection while its rows hold the only one.
+	cfg := env.Pool.Config().Copy()
+	cfg.MaxConns = 1
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer pool.Close()
+	org := service.NewOrgService(pool, env.Cache, nil, hrqlpg.ExpandBatch, metrics.NewHRQL(), service.QueryLimits{}, env.Cursors)
+
+	mux := http.NewServeMux()
+	mux.Handle(org.RegisterHandler())
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	client := registryv1connect.NewOrgServiceClient(srv.Client(), srv.URL)
+
+	stream, err := client.ExecuteStream(ctx, connect.NewRequest(&registryv1.ExecuteStreamRequest{Query: "employees", Expand: "department", BatchSize: 2}))
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	defer stream.Close()
+	var expanded int
+	for stream.Receive() {
+		for _, r := range stream.Msg().Results {
+			if r.Fields["department"].GetStructValue() != nil {
+				expanded++
+			}
+		}
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	if expanded != 5 {
+		t.Errorf("%d of 5 employees have their department expanded", expanded)
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	registryv1 "github.com/atlekbai/schema_registry/gen/registry/v1"
+	hrqlpg "github.com/atlekbai/schema_registry/internal/hrql/pg"
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// checkFieldLimit rejects a new custom field on object id once the object has
+// reached its custom field limit. It locks the object row, so concurrent
+// CreateFields count each other. The returned warning is set when the new
+// field brings the object near the limit.
+func (s *MetadataService) checkFieldLimit(ctx context.Context, tx pgx.Tx, id uuid.UUID) (string, error) {
+	var (
+		apiName        string
+		override, used int
+	)
+	err := tx.QueryRow(ctx, `
+		SELECT api_name, COALESCE(max_custom_fields, 0),
+		       (SELECT count(*) FROM metadata.fields f WHERE f.object_id = o.id AND NOT f.is_standard)
+		FROM metadata.objects o
+		WHERE id = $1
+		FOR UPDATE`, id).Scan(&apiName, &override, &used)
+	if err == pgx.ErrNoRows {
+		return "", connect.NewError(connect.CodeNotFound, fmt.Errorf("object not found"))
+	}
+	if err != nil {
+		return "", connect.NewError(connect.CodeInternal, fmt.Errorf("count custom fields: %w", err))
+	}
+
+	limit := cmp.Or(override, s.limits.MaxCustomFields)
+	if limit > 0 && used >= limit {
+		return "", connect.NewError(connect.CodeResourceExhausted,
+			fmt.Errorf("object %q has %d custom fields, its limit; raise max_custom_fields or delete unused fields", apiName, used))
+	}
+	return fieldLimitWarning(apiName, used+1, limit), nil
+}
+
+// fieldLimitWarning describes an object with used custom fields near its
+// limit, or returns "".
+func fieldLimitWarning(apiName string, used, limit int) string {
+	if !schema.NearLimit(int64(used), int64(limit)) {
+		return ""
+	}
+	return fmt.Sprintf("object %q has %d of its %d custom fields", apiName, used, limit)
+}
+
+// checkDocumentSize rejects a write leaving record id's document over obj's
+// data size limit. It runs in the write transaction, after the write, so the
+// size is that of the merged document.
+func (s *RegistryService) checkDocumentSize(ctx context.Context, q querier, obj *schema.ObjectDef, builder hrqlpg.Builder, id uuid.UUID) error {
+	limit := s.data.For(obj).MaxDataBytes
+	if limit == 0 {
+		return nil
+	}
+	sqlStr, args, err := builder.BuildDocumentSize(id)
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("build document size: %w", err))
+	}
+	var size int64
+	if err := q.QueryRow(ctx, sqlStr, args...).Scan(&size); err != nil {
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("measure document: %w", err))
+	}
+	if size > int64(limit) {
+		return connect.NewError(connect.CodeInvalidArgument,
+			fmt.Errorf("record data is %d bytes, over the %d byte limit of object %q", size, limit, obj.APIName))
+	}
+	return nil
+}
+
+// GetObjectUsage reports an object's custom field count and largest record
+// document against its data limits.
+func (s *MetadataService) GetObjectUsage(ctx context.Context, req *connect.Request[registryv1.GetObjectUsageRequest]) (*connect.Response[registryv1.GetObjectUsageResponse], error) {
+	obj := s.cache.GetByID(uuid.MustParse(req.Msg.Id))
+	if obj == nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("object not found"))
+	}
+	limits := s.limits.For(obj)
+
+	resp := &registryv1.GetObjectUsageResponse{
+		CustomFields:    int32(obj.CustomFieldCount()),
+		MaxCustomFields: int32(limits.MaxCustomFields),
+		MaxDataBytes:    int32(limits.MaxDataBytes),
+	}
+	sqlStr, args, err := hrqlpg.NewBuilder(obj).BuildDocumentStats()
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("build document stats: %w", err))
+	}
+	if err := s.pool.QueryRow(ctx, sqlStr, args...).Scan(&resp.Records, &resp.LargestDataBytes); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("measure documents: %w", err))
+	}
+
+	if w := fieldLimitWarning(obj.APIName, obj.CustomFieldCount(), limits.MaxCustomFields); w != "" {
+		resp.Warnings = append(resp.Warnings, w)
+	}
+	if schema.NearLimit(resp.LargestDataBytes, int64(limits.MaxDataBytes)) {
+		resp.Warnings = append(resp.Warnings, fmt.Sprintf("the largest %s record holds %d of its %d data bytes", obj.APIName, resp.LargestDataBytes, limits.MaxDataBytes))
+	}
+	return connect.NewResponse(resp), nil
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/jackc/pgx/v5"
+
+	registryv1 "github.com/atlekbai/schema_registry/gen/registry/v1"
+	hrqlpg "github.com/atlekbai/schema_registry/internal/hrql/pg"
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// LintSchema reports quality problems in the registry: the definition checks
+// of schema.Lint, sortable fields without an index to sort by and, unless
+// skip_data is set, custom fields no record has ever set.
+func (s *AdminService) LintSchema(ctx context.Context, req *connect.Request[registryv1.LintSchemaRequest]) (*connect.Response[registryv1.LintSchemaResponse], error) {
+	objs := s.cache.Objects()
+	findings := schema.Lint(objs)
+
+	unindexed, err := s.lintSortIndexes(ctx, objs)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("lint sort indexes: %w", err))
+	}
+	findings = append(findings, unindexed...)
+	if !req.Msg.SkipData {
+		unused, err := s.lintUnusedFields(ctx, objs)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("lint field usage: %w", err))
+		}
+		findings = append(findings, unused...)
+	}
+
+	rank := map[string]int{schema.LintError: 0, schema.LintWarning: 1, schema.LintInfo: 2}
+	slices.SortFunc(findings, func(a, b schema.LintFinding) int {
+		return cmp.Or(
+			cmp.Compare(rank[a.Severity], rank[b.Severity]),
+			strings.Compare(a.Object, b.Object),
+			strings.Compare(a.Field, b.Field),
+			strings.Compare(a.Code, b.Code),
+		)
+	})
+	out := make([]*registryv1.LintFinding, len(findings))
+	for i, f := range findings {
+		out[i] = &registryv1.LintFinding{
+			Severity:   f.Severity,
+			Code:       f.Code,
+			ObjectName: f.Object,
+			Field:      f.Field,
+			Message:    f.Message,
+			Related:    f.Related,
+		}
+	}
+	return connect.NewResponse(&registryv1.LintSchemaResponse{Findings: out}), nil
+}
+
+// lintSortIndexes flags sortable fields a sort would scan for: document
+// fields missing either of their sort indexes, and storage columns no valid
+// index leads with.
+func (s *AdminService) lintSortIndexes(ctx context.Context, objs []*schema.ObjectDef) ([]schema.LintFinding, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT c.relname, n.nspname, t.relname, COALESCE(a.attname, '')
+		FROM pg_index i
+		JOIN pg_class c ON c.oid = i.indexrelid
+		JOIN pg_class t ON t.oid = i.indrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		LEFT JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = i.indkey[0]
+		WHERE i.indisvalid
+	`)
+	if err != nil {
+		return nil, err
+	}
+	named := make(map[string]bool)
+	leading := make(map[string]bool) // schema.table.column
+	var index, nsp, table, column string
+	if _, err := pgx.ForEachRow(rows, []any{&index, &nsp, &table, &column}, func() error {
+		named[index] = true
+		if column != "" {
+			leading[nsp+"."+table+"."+column] = true
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	var out []schema.LintFinding
+	for _, obj := range objs {
+		for i := range obj.Fields {
+			fd := &obj.Fields[i]
+			switch {
+			case hrqlpg.HasSortIndexes(fd):
+				names := hrqlpg.SortIndexNames(fd)
+				if named[names[0]] && named[names[1]] {
+					continue
+				}
+				out = append(out, schema.LintFinding{
+					Severity: schema.LintWarning, Code: schema.LintUnindexedSort, Object: obj.APIName, Field: fd.APIName,
+					Message: fmt.Sprintf("field is sortable but sort indexes %s and %s are missing or invalid: sorting scans every record", names[0], names[1]),
+				})
+			case fd.StorageColumn != nil && !fd.NotSortable && obj.StorageSchema != nil && obj.StorageTable != nil:
+				switch fd.Type {
+				case schema.FieldEncrypted, schema.FieldJSON, schema.FieldFormula, schema.FieldMultichoice:
+					continue
+				}
+				if leading[*obj.StorageSchema+"."+*obj.StorageTable+"."+*fd.StorageColumn] {
+					continue
+				}
+				out = append(out, schema.LintFinding{
+					Severity: schema.LintWarning, Code: schema.LintUnindexedSort, Object: obj.APIName, Field: fd.APIName,
+					Message: fmt.Sprintf("field is sortable but no index on %s.%s starts with column %s: sorting scans the table", *obj.StorageSchema, *obj.StorageTable, *fd.StorageColumn),
+				})
+			}
+		}
+	}
+	return out, nil
+}
+
+// lintUnusedFields flags the custom fields of objects with records that no
+// record holds a value for. Formulas are computed, never stored, and skipped.
+func (s *AdminService) lintUnusedFields(ctx context.Context, objs []*schema.ObjectDef) ([]schema.LintFinding, error) {
+	var out []schema.LintFinding
+	for _, obj := range objs {
+		var fields []*schema.FieldDef
+		for i := range obj.Fields {
+			if fd := &obj.Fields[i]; !fd.IsStandard && fd.StorageColumn == nil && fd.Type != schema.FieldFormula {
+				fields = append(fields, fd)
+			}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		query, args, err := hrqlpg.BuildFieldUsage(obj, fields)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", obj.APIName, err)
+		}
+		var count int64
+		used := make([]bool, len(fields))
+		dest := []any{&count}
+		for i := range used {
+			dest = append(dest, &used[i])
+		}
+		if err := s.pool.QueryRow(ctx, query, args...).Scan(dest...); err != nil {
+			return nil, fmt.Errorf("%s: %w", obj.APIName, err)
+		}
+		if count == 0 {
+			continue
+		}
+		for i, fd := range fields {
+			if !used[i] {
+				out = append(out, schema.LintFinding{
+					Severity: schema.LintInfo, Code: schema.LintUnusedField, Object: obj.APIName, Field: fd.APIName,
+					Message: fmt.Sprintf("none of the object's %d records has a value for this field", count),
+				})
+			}
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/jackc/pgx/v5"
+
+	registryv1 "github.com/atlekbai/schema_registry/gen/registry/v1"
+	hrqlpg "github.com/atlekbai/schema_registry/internal/hrql/pg"
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+const (
+	// defaultLookupLimit applies to Lookup requests without a limit.
+	defaultLookupLimit = 20
+	// lookupCacheTTL is how long Lookup results are reused, in process and by
+	// clients (Cache-Control). Dropdowns search on every keystroke, and a new
+	// record showing up a few seconds late is acceptable there.
+	lookupCacheTTL = 15 * time.Second
+	// lookupCacheMax caps the cached searches.
+	lookupCacheMax = 2000
+)
+
+// Lookup returns the records the LOOKUP field can reference whose display
+// name matches q, ranked for dropdowns (pg.BuildLookupSearch).
+func (s *RegistryService) Lookup(ctx context.Context, req *connect.Request[registryv1.LookupRequest]) (*connect.Response[registryv1.LookupResponse], error) {
+	msg := req.Msg
+	obj := s.cache.Get(msg.ObjectName)
+	if obj == nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("no object registered with api_name %q", msg.ObjectName))
+	}
+	fd := obj.FieldsByAPIName[msg.Field]
+	if fd == nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("unknown field %q on %s", msg.Field, obj.APIName))
+	}
+	if fd.Type != schema.FieldLookup || fd.LookupObjectID == nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("field %q is %s, not a LOOKUP", fd.APIName, fd.Type))
+	}
+	target := s.cache.GetByID(*fd.LookupObjectID)
+	if target == nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("lookup target of %s.%s not in cache", obj.APIName, fd.APIName))
+	}
+	if target.DisplayTemplate == "" {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("object %q has no display_template; set one with UpdateObject", target.APIName))
+	}
+
+	q := strings.TrimSpace(msg.Q)
+	limit := cmp.Or(int(msg.Limit), defaultLookupLimit)
+	key := lookupKey{object: target.APIName, template: target.DisplayTemplate, q: strings.ToLower(q), limit: limit}
+	matches, ok := s.lookups.get(key)
+	if !ok {
+		var err error
+		if matches, err = s.lookupSearch(ctx, target, q, limit); err != nil {
+			return nil, err
+		}
+		s.lookups.put(key, matches)
+	}
+
+	resp := connect.NewResponse(&registryv1.LookupResponse{ObjectName: target.APIName, Matches: matches})
+	resp.Header().Set("Cache-Control", "private, max-age="+strconv.Itoa(int(lookupCacheTTL.Seconds())))
+	return resp, nil
+}
+
+func (s *RegistryService) lookupSearch(ctx context.Context, target *schema.ObjectDef, q string, limit int) ([]*registryv1.TypeaheadMatch, error) {
+	sqlStr, args, err := hrqlpg.BuildLookupSearch(target, q, limit)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	rows, err := s.pool.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("lookup: %w", err))
+	}
+	var matches []*registryv1.TypeaheadMatch
+	var id, display string
+	if _, err := pgx.ForEachRow(rows, []any{&id, &display}, func() error {
+		matches = append(matches, &registryv1.TypeaheadMatch{Id: id, Display: display})
+		return nil
+	}); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("lookup: %w", err))
+	}
+	return matches, nil
+}
+
+// lookupKey identifies a cached search. The display template is part of it,
+// so changing the template misses instead of serving stale names.
+type lookupKey struct {
+	object, template, q string
+	limit               int
+}
+
+type lookupEntry struct {
+	matches []*registryv1.TypeaheadMatch
+	expires time.Time
+}
+
+// lookupCache holds recent Lookup results for lookupCacheTTL. The cached
+// messages are shared between responses and must not be modified.
+type lookupCache struct {
+	mu      sync.Mutex
+	entries map[lookupKey]lookupEntry
+}
+
+func newLookupCache() *lookupCache {
+	return &lookupCache{entries: make(map[lookupKey]lookupEntry)}
+}
+
+func (c *lookupCache) get(k lookupKey) ([]*registryv1.TypeaheadMatch, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[k]
+	if !ok || time.Now().After(e.expires) {
+		return nil, false
+	}
+	return e.matches, true
+}
+
+func (c *lookupCache) put(k lookupKey, matches []*registryv1.TypeaheadMatch) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if len(c.entries) >= lookupCacheMax {
+		for k, e := range c.entries {
+			if now.After(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= lookupCacheMax {
+			clear(c.entries)
+		}
+	}
+	c.entries[k] = lookupEntry{matches: matches, expires: now.Add(lookupCacheTTL)}
+}
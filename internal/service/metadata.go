@@ -1,26 +1,53 @@
 package service
 
 import (
+	"bytes"
+	"cmp"
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
 
 	"connectrpc.com/connect"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	registryv1 "github.com/atlekbai/schema_registry/gen/registry/v1"
 	registryv1connect "github.com/atlekbai/schema_registry/gen/registry/v1/registryv1connect"
+	"github.com/atlekbai/schema_registry/internal/codegen"
+	"github.com/atlekbai/schema_registry/internal/db"
+	hrqlpg "github.com/atlekbai/schema_registry/internal/hrql/pg"
 	"github.com/atlekbai/schema_registry/internal/schema"
 )
 
 type MetadataService struct {
-	pool  *pgxpool.Pool
-	cache *schema.Cache
+	pool   *pgxpool.Pool
+	cache  *schema.Cache
+	idents *schema.IdentifierPolicy
+	// limits are the server's data limits, which objects may override.
+	limits schema.DataLimits
+	// searchIndexes creates a trigram index for each field flagged
+	// is_searchable, and drops it when the flag is cleared.
+	searchIndexes bool
+	// sortIndexes creates the sort indexes of each document field flagged
+	// is_sortable, and drops them when the flag is cleared.
+	sortIndexes bool
+	// approval holds mutations as change requests for ReviewService instead
+	// of applying them (see holdChange).
+	approval bool
 }
 
-func NewMetadataService(pool *pgxpool.Pool, cache *schema.Cache) *MetadataService {
-	return &MetadataService{pool: pool, cache: cache}
+func NewMetadataService(pool *pgxpool.Pool, cache *schema.Cache, idents *schema.IdentifierPolicy, limits schema.DataLimits, searchIndexes, sortIndexes, approval bool) *MetadataService {
+	return &MetadataService{pool: pool, cache: cache, idents: idents, limits: limits, searchIndexes: searchIndexes, sortIndexes: sortIndexes, approval: approval}
 }
 
 func (s *MetadataService) RegisterHandler(interceptors ...connect.Interceptor) (string, http.Handler) {
@@ -30,100 +57,107 @@ func (s *MetadataService) RegisterHandler(interceptors ...connect.Interceptor) (
 // ── Objects ─────────────────────────────────────────────────────────
 
 func (s *MetadataService) ListObjects(ctx context.Context, req *connect.Request[registryv1.ListObjectsRequest]) (*connect.Response[registryv1.ListObjectsResponse], error) {
-	rows, err := s.pool.Query(ctx, `
-		SELECT id, api_name, title, plural_title, COALESCE(description,''),
-		       is_standard, COALESCE(storage_schema,''), COALESCE(storage_table,''),
-		       supports_custom_fields, COALESCE(category_id::text,''),
-		       created_at::text, updated_at::text
-		FROM metadata.objects ORDER BY created_at
-	`)
-	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("query objects: %w", err))
+	if err := s.syncCache(ctx, req.Msg.Consistency); err != nil {
+		return nil, err
 	}
-	defer rows.Close()
 
-	var objects []*registryv1.ObjectMeta
-	for rows.Next() {
-		o := &registryv1.ObjectMeta{}
-		if err := rows.Scan(
-			&o.Id, &o.ApiName, &o.Title, &o.PluralTitle, &o.Description,
-			&o.IsStandard, &o.StorageSchema, &o.StorageTable,
-			&o.SupportsCustomFields, &o.CategoryId,
-			&o.CreatedAt, &o.UpdatedAt,
-		); err != nil {
-			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("scan object: %w", err))
-		}
-		objects = append(objects, o)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
-	}
+	defs := s.cache.Objects()
+	slices.SortFunc(defs, func(a, b *schema.ObjectDef) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), bytes.Compare(a.ID[:], b.ID[:]))
+	})
 
+	objects := make([]*registryv1.ObjectMeta, len(defs))
+	for i, obj := range defs {
+		objects[i] = objectMeta(obj)
+	}
 	return connect.NewResponse(&registryv1.ListObjectsResponse{Objects: objects}), nil
 }
 
 func (s *MetadataService) GetObject(ctx context.Context, req *connect.Request[registryv1.GetObjectRequest]) (*connect.Response[registryv1.GetObjectResponse], error) {
-	o := &registryv1.ObjectMeta{}
-	err := s.pool.QueryRow(ctx, `
-		SELECT id, api_name, title, plural_title, COALESCE(description,''),
-		       is_standard, COALESCE(storage_schema,''), COALESCE(storage_table,''),
-		       supports_custom_fields, COALESCE(category_id::text,''),
-		       created_at::text, updated_at::text
-		FROM metadata.objects WHERE id = $1
-	`, req.Msg.Id).Scan(
-		&o.Id, &o.ApiName, &o.Title, &o.PluralTitle, &o.Description,
-		&o.IsStandard, &o.StorageSchema, &o.StorageTable,
-		&o.SupportsCustomFields, &o.CategoryId,
-		&o.CreatedAt, &o.UpdatedAt,
-	)
-	if err == pgx.ErrNoRows {
-		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("object not found"))
-	}
-	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("query object: %w", err))
+	if err := s.syncCache(ctx, req.Msg.Consistency); err != nil {
+		return nil, err
 	}
 
-	// Load fields for this object
-	fields, err := s.listFieldsForObject(ctx, o.Id)
-	if err != nil {
-		return nil, err
+	obj := s.cache.GetByID(uuid.MustParse(req.Msg.Id))
+	if obj == nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("object not found"))
 	}
-	o.Fields = fields
 
+	locale := requestLocale(req.Msg.Locale, req.Header())
+	o := objectMeta(obj)
+	o.Fields = make([]*registryv1.FieldMeta, len(obj.Fields))
+	for i := range obj.Fields {
+		o.Fields[i] = fieldMeta(&obj.Fields[i], locale)
+	}
 	return connect.NewResponse(&registryv1.GetObjectResponse{Object: o}), nil
 }
 
 func (s *MetadataService) CreateObject(ctx context.Context, req *connect.Request[registryv1.CreateObjectRequest]) (*connect.Response[registryv1.CreateObjectResponse], error) {
+	if err := s.holdChange(ctx, "CreateObject", "", req.Msg); err != nil {
+		return nil, err
+	}
+
 	msg := req.Msg
 	o := &registryv1.ObjectMeta{}
 
+	if err := s.idents.ValidateObjectName(s.cache, msg.ApiName); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
 	var categoryID *string
 	if msg.CategoryId != "" {
 		categoryID = &msg.CategoryId
 	}
 
-	err := s.pool.QueryRow(ctx, `
-		INSERT INTO metadata.objects (api_name, title, plural_title, description, category_id, supports_custom_fields)
-		VALUES ($1, $2, $3, NULLIF($4,''), $5::uuid, $6)
-		RETURNING id, api_name, title, plural_title, COALESCE(description,''),
-		          is_standard, COALESCE(storage_schema,''), COALESCE(storage_table,''),
-		          supports_custom_fields, COALESCE(category_id::text,''),
-		          created_at::text, updated_at::text
-	`, msg.ApiName, msg.Title, msg.PluralTitle, msg.Description, categoryID, msg.SupportsCustomFields).Scan(
-		&o.Id, &o.ApiName, &o.Title, &o.PluralTitle, &o.Description,
-		&o.IsStandard, &o.StorageSchema, &o.StorageTable,
-		&o.SupportsCustomFields, &o.CategoryId,
-		&o.CreatedAt, &o.UpdatedAt,
+	// A new object has only system fields to order by.
+	newObj := &schema.ObjectDef{APIName: msg.ApiName}
+	newObj.AddSystemFields()
+	if err := checkListDefaults(newObj, msg.DefaultOrder, msg.DefaultPageSize, msg.MaxPageSize); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if msg.DisplayTemplate != "" {
+		if err := newObj.CheckDisplayTemplate(msg.DisplayTemplate); err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+	}
+	hook, err := webhookColumns(msg.ValidationWebhook)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	var (
+		scanned   objectWebhook
+		lifecycle objectLifecycle
 	)
+	err = s.pool.QueryRow(ctx, `
+		INSERT INTO metadata.objects (api_name, title, plural_title, description, category_id, supports_custom_fields,
+		                              default_order, default_page_size, max_page_size,
+		                              validation_webhook_url, validation_webhook_timeout_ms, validation_webhook_fail_open,
+		                              display_template, max_custom_fields, max_data_bytes, exact_count_threshold,
+		                              cache_max_age_seconds)
+		VALUES ($1, $2, $3, NULLIF($4,''), $5::uuid, $6, NULLIF($7,''), NULLIF($8,0), NULLIF($9,0), $10, NULLIF($11,0), $12, NULLIF($13,''),
+		        NULLIF($14,0), NULLIF($15,0), NULLIF($16,0), NULLIF($17,0))
+		RETURNING `+objectReturning,
+		msg.ApiName, msg.Title, msg.PluralTitle, msg.Description, categoryID, msg.SupportsCustomFields,
+		msg.DefaultOrder, msg.DefaultPageSize, msg.MaxPageSize,
+		hook.url, hook.timeoutMS, hook.failOpen,
+		msg.DisplayTemplate, msg.MaxCustomFields, msg.MaxDataBytes, msg.ExactCountThreshold, msg.CacheMaxAgeSeconds,
+	).Scan(objectScanDest(o, &scanned, &lifecycle)...)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("create object: %w", err))
 	}
+	o.ValidationWebhook = scanned.meta()
+	o.Deprecation = lifecycle.meta()
 
 	s.reloadCache(ctx)
 	return connect.NewResponse(&registryv1.CreateObjectResponse{Object: o}), nil
 }
 
 func (s *MetadataService) UpdateObject(ctx context.Context, req *connect.Request[registryv1.UpdateObjectRequest]) (*connect.Response[registryv1.UpdateObjectResponse], error) {
+	if err := s.holdChange(ctx, "UpdateObject", req.Msg.Id, req.Msg); err != nil {
+		return nil, err
+	}
+
 	msg := req.Msg
 	o := &registryv1.ObjectMeta{}
 
@@ -132,86 +166,386 @@ func (s *MetadataService) UpdateObject(ctx context.Context, req *connect.Request
 		categoryID = &msg.CategoryId
 	}
 
-	err := s.pool.QueryRow(ctx, `
+	if obj := s.cache.GetByID(uuid.MustParse(msg.Id)); obj != nil {
+		order := cmp.Or(msg.DefaultOrder, &obj.DefaultOrder)
+		defaultSize := cmp.Or(msg.DefaultPageSize, new(int32(obj.DefaultPageSize)))
+		maxSize := cmp.Or(msg.MaxPageSize, new(int32(obj.MaxPageSize)))
+		if err := checkListDefaults(obj, *order, *defaultSize, *maxSize); err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+		if tmpl := msg.DisplayTemplate; tmpl != nil && *tmpl != "" {
+			if err := obj.CheckDisplayTemplate(*tmpl); err != nil {
+				return nil, connect.NewError(connect.CodeInvalidArgument, err)
+			}
+		}
+	}
+
+	if msg.ValidationWebhook != nil && msg.ClearValidationWebhook {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("validation_webhook and clear_validation_webhook are mutually exclusive"))
+	}
+	hook, err := webhookColumns(msg.ValidationWebhook)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if msg.Deprecation != nil && msg.ClearDeprecation {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("deprecation and clear_deprecation are mutually exclusive"))
+	}
+	dep, err := s.deprecationColumns(msg.Id, msg.Deprecation)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	var (
+		scanned   objectWebhook
+		lifecycle objectLifecycle
+	)
+	err = s.pool.QueryRow(ctx, `
 		UPDATE metadata.objects
 		SET title = COALESCE(NULLIF($2,''), title),
 		    plural_title = COALESCE(NULLIF($3,''), plural_title),
 		    description = CASE WHEN $4 = '' THEN description ELSE $4 END,
 		    category_id = COALESCE($5::uuid, category_id),
 		    supports_custom_fields = $6,
+		    default_order = CASE WHEN $7::text IS NULL THEN default_order ELSE NULLIF($7, '') END,
+		    default_page_size = CASE WHEN $8::int IS NULL THEN default_page_size ELSE NULLIF($8, 0) END,
+		    max_page_size = CASE WHEN $9::int IS NULL THEN max_page_size ELSE NULLIF($9, 0) END,
+		    validation_webhook_url = CASE WHEN $13 THEN NULL WHEN $10::text IS NULL THEN validation_webhook_url ELSE $10 END,
+		    validation_webhook_timeout_ms = CASE WHEN $13 THEN NULL WHEN $10::text IS NULL THEN validation_webhook_timeout_ms ELSE NULLIF($11::int, 0) END,
+		    validation_webhook_fail_open = CASE WHEN $13 THEN false WHEN $10::text IS NULL THEN validation_webhook_fail_open ELSE $12::bool END,
+		    display_template = CASE WHEN $14::text IS NULL THEN display_template ELSE NULLIF($14, '') END,
+		    deprecated_at = CASE WHEN $18 THEN NULL WHEN $15::timestamptz IS NULL THEN deprecated_at ELSE $15 END,
+		    sunset_at = CASE WHEN $18 THEN NULL WHEN $15::timestamptz IS NULL THEN sunset_at ELSE $16::timestamptz END,
+		    replacement = CASE WHEN $18 THEN NULL WHEN $15::timestamptz IS NULL THEN replacement ELSE NULLIF($17, '') END,
+		    max_custom_fields = CASE WHEN $19::int IS NULL THEN max_custom_fields ELSE NULLIF($19, 0) END,
+		    max_data_bytes = CASE WHEN $20::int IS NULL THEN max_data_bytes ELSE NULLIF($20, 0) END,
+		    exact_count_threshold = CASE WHEN $21::bigint IS NULL THEN exact_count_threshold ELSE NULLIF($21, 0) END,
+		    cache_max_age_seconds = CASE WHEN $22::int IS NULL THEN cache_max_age_seconds ELSE NULLIF($22, 0) END,
 		    updated_at = now()
 		WHERE id = $1
-		RETURNING id, api_name, title, plural_title, COALESCE(description,''),
-		          is_standard, COALESCE(storage_schema,''), COALESCE(storage_table,''),
-		          supports_custom_fields, COALESCE(category_id::text,''),
-		          created_at::text, updated_at::text
-	`, msg.Id, msg.Title, msg.PluralTitle, msg.Description, categoryID, msg.SupportsCustomFields).Scan(
-		&o.Id, &o.ApiName, &o.Title, &o.PluralTitle, &o.Description,
-		&o.IsStandard, &o.StorageSchema, &o.StorageTable,
-		&o.SupportsCustomFields, &o.CategoryId,
-		&o.CreatedAt, &o.UpdatedAt,
-	)
+		RETURNING `+objectReturning,
+		msg.Id, msg.Title, msg.PluralTitle, msg.Description, categoryID, msg.SupportsCustomFields,
+		msg.DefaultOrder, msg.DefaultPageSize, msg.MaxPageSize,
+		hook.url, hook.timeoutMS, hook.failOpen, msg.ClearValidationWebhook,
+		msg.DisplayTemplate,
+		dep.deprecatedAt, dep.sunsetAt, dep.replacement, msg.ClearDeprecation,
+		msg.MaxCustomFields, msg.MaxDataBytes, msg.ExactCountThreshold, msg.CacheMaxAgeSeconds,
+	).Scan(objectScanDest(o, &scanned, &lifecycle)...)
 	if err == pgx.ErrNoRows {
 		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("object not found"))
 	}
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("update object: %w", err))
 	}
+	o.ValidationWebhook = scanned.meta()
+	o.Deprecation = lifecycle.meta()
 
 	s.reloadCache(ctx)
 	return connect.NewResponse(&registryv1.UpdateObjectResponse{Object: o}), nil
 }
 
+// objectReturning is the RETURNING list of object writes, scanned by
+// objectScanDest.
+const objectReturning = `id, api_name, title, plural_title, COALESCE(description,''),
+		          is_standard, COALESCE(storage_schema,''), COALESCE(storage_table,''),
+		          supports_custom_fields, COALESCE(category_id::text,''),
+		          created_at::text, updated_at::text,
+		          COALESCE(default_order,''), COALESCE(default_page_size,0), COALESCE(max_page_size,0),
+		          validation_webhook_url, COALESCE(validation_webhook_timeout_ms,0), validation_webhook_fail_open,
+		          COALESCE(display_template,''),
+		          deprecated_at, sunset_at, COALESCE(replacement,''),
+		          ARRAY(SELECT a.alias FROM metadata.object_aliases a WHERE a.object_id = objects.id ORDER BY a.created_at, a.alias),
+		          COALESCE(max_custom_fields,0), COALESCE(max_data_bytes,0), COALESCE(exact_count_threshold,0),
+		          COALESCE(cache_max_age_seconds,0)`
+
+func objectScanDest(o *registryv1.ObjectMeta, hook *objectWebhook, lifecycle *objectLifecycle) []any {
+	return []any{
+		&o.Id, &o.ApiName, &o.Title, &o.PluralTitle, &o.Description,
+		&o.IsStandard, &o.StorageSchema, &o.StorageTable,
+		&o.SupportsCustomFields, &o.CategoryId,
+		&o.CreatedAt, &o.UpdatedAt,
+		&o.DefaultOrder, &o.DefaultPageSize, &o.MaxPageSize,
+		&hook.url, &hook.timeoutMS, &hook.failOpen,
+		&o.DisplayTemplate,
+		&lifecycle.deprecatedAt, &lifecycle.sunsetAt, &lifecycle.replacement,
+		&o.Aliases,
+		&o.MaxCustomFields, &o.MaxDataBytes, &o.ExactCountThreshold,
+		&o.CacheMaxAgeSeconds,
+	}
+}
+
+// objectWebhook holds the validation webhook columns of metadata.objects.
+type objectWebhook struct {
+	url       *string
+	timeoutMS int32
+	failOpen  bool
+}
+
+// webhookColumns validates a requested webhook and returns its column
+// values; nil leaves url unset.
+func webhookColumns(hook *registryv1.ValidationWebhook) (objectWebhook, error) {
+	if hook == nil {
+		return objectWebhook{}, nil
+	}
+	u, err := url.Parse(hook.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return objectWebhook{}, fmt.Errorf("validation_webhook.url must be an http or https URL, got %q", hook.Url)
+	}
+	return objectWebhook{url: &hook.Url, timeoutMS: hook.TimeoutMs, failOpen: hook.FailOpen}, nil
+}
+
+func (h objectWebhook) meta() *registryv1.ValidationWebhook {
+	if h.url == nil {
+		return nil
+	}
+	return &registryv1.ValidationWebhook{Url: *h.url, TimeoutMs: h.timeoutMS, FailOpen: h.failOpen}
+}
+
+// objectLifecycle holds the deprecation columns of metadata.objects.
+type objectLifecycle struct {
+	deprecatedAt *time.Time
+	sunsetAt     *time.Time
+	replacement  string
+}
+
+// deprecationColumns validates a requested deprecation of object id and
+// returns its column values; nil leaves deprecatedAt unset. An empty
+// deprecated_at means now, and the replacement must be another object.
+func (s *MetadataService) deprecationColumns(id string, dep *registryv1.ObjectDeprecation) (objectLifecycle, error) {
+	if dep == nil {
+		return objectLifecycle{}, nil
+	}
+	cols := objectLifecycle{deprecatedAt: new(time.Now()), replacement: dep.Replacement}
+	if dep.DeprecatedAt != "" {
+		t, err := time.Parse(time.RFC3339, dep.DeprecatedAt)
+		if err != nil {
+			return objectLifecycle{}, fmt.Errorf("deprecation.deprecated_at: expected an RFC 3339 timestamp, got %q", dep.DeprecatedAt)
+		}
+		cols.deprecatedAt = &t
+	}
+	if dep.SunsetAt != "" {
+		t, err := time.Parse(time.RFC3339, dep.SunsetAt)
+		if err != nil {
+			return objectLifecycle{}, fmt.Errorf("deprecation.sunset_at: expected an RFC 3339 timestamp, got %q", dep.SunsetAt)
+		}
+		if t.Before(*cols.deprecatedAt) {
+			return objectLifecycle{}, fmt.Errorf("deprecation.sunset_at is before deprecated_at")
+		}
+		cols.sunsetAt = &t
+	}
+	if dep.Replacement != "" {
+		target := s.cache.Get(dep.Replacement)
+		if target == nil {
+			return objectLifecycle{}, fmt.Errorf("deprecation.replacement: no object registered with api_name %q", dep.Replacement)
+		}
+		if target.ID.String() == id {
+			return objectLifecycle{}, fmt.Errorf("deprecation.replacement: an object cannot replace itself")
+		}
+		cols.replacement = target.APIName
+	}
+	return cols, nil
+}
+
+func (l objectLifecycle) meta() *registryv1.ObjectDeprecation {
+	if l.deprecatedAt == nil {
+		return nil
+	}
+	return deprecationMeta(&schema.Deprecation{At: *l.deprecatedAt, SunsetAt: l.sunsetAt, Replacement: l.replacement})
+}
+
+func deprecationMeta(dep *schema.Deprecation) *registryv1.ObjectDeprecation {
+	m := &registryv1.ObjectDeprecation{
+		DeprecatedAt: dep.At.UTC().Format(time.RFC3339),
+		Replacement:  dep.Replacement,
+	}
+	if dep.SunsetAt != nil {
+		m.SunsetAt = dep.SunsetAt.UTC().Format(time.RFC3339)
+	}
+	return m
+}
+
+// checkListDefaults validates an object's list defaults: default_order must
+// name a sortable field of obj, and the default page size may not exceed the
+// maximum.
+func checkListDefaults(obj *schema.ObjectDef, order string, defaultSize, maxSize int32) error {
+	if order != "" {
+		if _, err := hrqlpg.ParseOrder(obj, order); err != nil {
+			return fmt.Errorf("default_order: %w", err)
+		}
+	}
+	if defaultSize > 0 && maxSize > 0 && defaultSize > maxSize {
+		return fmt.Errorf("default_page_size %d exceeds max_page_size %d", defaultSize, maxSize)
+	}
+	return nil
+}
+
 func (s *MetadataService) DeleteObject(ctx context.Context, req *connect.Request[registryv1.DeleteObjectRequest]) (*connect.Response[registryv1.DeleteObjectResponse], error) {
-	tag, err := s.pool.Exec(ctx, `DELETE FROM metadata.objects WHERE id = $1`, req.Msg.Id)
+	if err := s.holdChange(ctx, "DeleteObject", req.Msg.Id, req.Msg); err != nil {
+		return nil, err
+	}
+
+	// In a transaction so the records deleted with the object, and those
+	// whose references a cascade clears, are attributed to the caller.
+	tx, err := db.Begin(ctx, s.pool)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("begin transaction: %w", err))
+	}
+	defer tx.Rollback(ctx)
+
+	if obj := s.cache.GetByID(uuid.MustParse(req.Msg.Id)); obj != nil {
+		if err := s.cascadeDelete(ctx, tx, obj, req.Msg.Cascade); err != nil {
+			return nil, err
+		}
+	}
+	tag, err := tx.Exec(ctx, `DELETE FROM metadata.objects WHERE id = $1`, req.Msg.Id)
+	if pgErr, ok := errors.AsType[*pgconn.PgError](err); ok && pgErr.Code == "23503" {
+		// A reference created since the cache was loaded.
+		return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("object is referenced by LOOKUP fields of other objects"))
+	}
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("delete object: %w", err))
 	}
 	if tag.RowsAffected() == 0 {
 		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("object not found"))
 	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("commit: %w", err))
+	}
 
 	s.reloadCache(ctx)
 	return connect.NewResponse(&registryv1.DeleteObjectResponse{}), nil
 }
 
+// RenameObject changes an object's api_name and records the old one as an
+// alias in the same transaction, so every request finds the object under
+// one name or the other. Renaming back to an alias drops it.
+func (s *MetadataService) RenameObject(ctx context.Context, req *connect.Request[registryv1.RenameObjectRequest]) (*connect.Response[registryv1.RenameObjectResponse], error) {
+	if err := s.holdChange(ctx, "RenameObject", req.Msg.Id, req.Msg); err != nil {
+		return nil, err
+	}
+
+	msg := req.Msg
+	obj := s.cache.GetByID(uuid.MustParse(msg.Id))
+	if obj == nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("object not found"))
+	}
+	// Standard objects are named in migrations, seeds and the HRQL compiler.
+	if obj.IsStandard {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("standard object %q cannot be renamed", obj.APIName))
+	}
+	if err := s.idents.ValidateObjectRename(s.cache, obj, msg.ApiName); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	tx, err := db.Begin(ctx, s.pool)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("begin transaction: %w", err))
+	}
+	defer tx.Rollback(ctx)
+
+	var oldName string
+	err = tx.QueryRow(ctx, `SELECT api_name FROM metadata.objects WHERE id = $1 FOR UPDATE`, msg.Id).Scan(&oldName)
+	if err == pgx.ErrNoRows {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("object not found"))
+	}
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("rename object: %w", err))
+	}
+
+	var (
+		o         = &registryv1.ObjectMeta{}
+		scanned   objectWebhook
+		lifecycle objectLifecycle
+	)
+	err = func() error {
+		if _, err := tx.Exec(ctx, `DELETE FROM metadata.object_aliases WHERE alias = $1 AND object_id = $2`, msg.ApiName, msg.Id); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `INSERT INTO metadata.object_aliases (alias, object_id) VALUES ($1, $2)`, oldName, msg.Id); err != nil {
+			return err
+		}
+		if err := tx.QueryRow(ctx, `
+			UPDATE metadata.objects SET api_name = $2, updated_at = now()
+			WHERE id = $1
+			RETURNING `+objectReturning, msg.Id, msg.ApiName,
+		).Scan(objectScanDest(o, &scanned, &lifecycle)...); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `UPDATE metadata.objects SET replacement = $2 WHERE replacement = $1`, oldName, msg.ApiName)
+		return err
+	}()
+	if pgErr, ok := errors.AsType[*pgconn.PgError](err); ok && pgErr.Code == "23505" {
+		return nil, connect.NewError(connect.CodeAlreadyExists, fmt.Errorf("object api_name %q is taken", msg.ApiName))
+	}
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("rename object: %w", err))
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("commit: %w", err))
+	}
+	o.ValidationWebhook = scanned.meta()
+	o.Deprecation = lifecycle.meta()
+
+	s.reloadCache(ctx)
+	return connect.NewResponse(&registryv1.RenameObjectResponse{Object: o}), nil
+}
+
 // ── Fields ──────────────────────────────────────────────────────────
 
 func (s *MetadataService) ListFields(ctx context.Context, req *connect.Request[registryv1.ListFieldsRequest]) (*connect.Response[registryv1.ListFieldsResponse], error) {
-	fields, err := s.listFieldsForObject(ctx, req.Msg.ObjectId)
-	if err != nil {
+	msg := req.Msg
+	if err := s.syncCache(ctx, msg.Consistency); err != nil {
 		return nil, err
 	}
-	return connect.NewResponse(&registryv1.ListFieldsResponse{Fields: fields}), nil
-}
 
-func (s *MetadataService) GetField(ctx context.Context, req *connect.Request[registryv1.GetFieldRequest]) (*connect.Response[registryv1.GetFieldResponse], error) {
-	f := &registryv1.FieldMeta{}
-	err := s.pool.QueryRow(ctx, `
-		SELECT id, object_id::text, api_name, title, COALESCE(description,''),
-		       type, COALESCE(type_config::text,'{}'),
-		       is_required, is_unique, is_standard,
-		       COALESCE(storage_column,''), COALESCE(lookup_object_id::text,''),
-		       created_at::text, updated_at::text
-		FROM metadata.fields WHERE object_id = $1 AND id = $2
-	`, req.Msg.ObjectId, req.Msg.Id).Scan(
-		&f.Id, &f.ObjectId, &f.ApiName, &f.Title, &f.Description,
-		&f.Type, &f.TypeConfig,
-		&f.IsRequired, &f.IsUnique, &f.IsStandard,
-		&f.StorageColumn, &f.LookupObjectId,
-		&f.CreatedAt, &f.UpdatedAt,
-	)
-	if err == pgx.ErrNoRows {
-		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("field not found"))
+	// Unknown objects list no fields, as the catalog query always did.
+	var fields []*schema.FieldDef
+	if obj := s.cache.GetByID(uuid.MustParse(msg.ObjectId)); obj != nil {
+		fields = make([]*schema.FieldDef, len(obj.Fields))
+		for i := range obj.Fields {
+			fields[i] = &obj.Fields[i]
+		}
 	}
+
+	page, next, err := paginateFields(fields, msg.OrderBy, int(msg.PageSize), msg.PageToken)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("query field: %w", err))
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	resp := &registryv1.ListFieldsResponse{
+		Fields:        make([]*registryv1.FieldMeta, len(page)),
+		NextPageToken: next,
+		TotalSize:     int32(len(fields)),
+	}
+	locale := requestLocale(msg.Locale, req.Header())
+	for i, fd := range page {
+		resp.Fields[i] = fieldMeta(fd, locale)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *MetadataService) GetField(ctx context.Context, req *connect.Request[registryv1.GetFieldRequest]) (*connect.Response[registryv1.GetFieldResponse], error) {
+	if err := s.syncCache(ctx, req.Msg.Consistency); err != nil {
+		return nil, err
 	}
 
-	return connect.NewResponse(&registryv1.GetFieldResponse{Field: f}), nil
+	obj := s.cache.GetByID(uuid.MustParse(req.Msg.ObjectId))
+	if obj != nil {
+		id := uuid.MustParse(req.Msg.Id)
+		for i := range obj.Fields {
+			if obj.Fields[i].ID == id {
+				locale := requestLocale(req.Msg.Locale, req.Header())
+				return connect.NewResponse(&registryv1.GetFieldResponse{Field: fieldMeta(&obj.Fields[i], locale)}), nil
+			}
+		}
+	}
+	return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("field not found"))
 }
 
 func (s *MetadataService) CreateField(ctx context.Context, req *connect.Request[registryv1.CreateFieldRequest]) (*connect.Response[registryv1.CreateFieldResponse], error) {
+	if err := s.holdChange(ctx, "CreateField", req.Msg.ObjectId, req.Msg); err != nil {
+		return nil, err
+	}
+
 	msg := req.Msg
 	f := &registryv1.FieldMeta{}
 
@@ -220,129 +554,749 @@ func (s *MetadataService) CreateField(ctx context.Context, req *connect.Request[
 		lookupObjID = &msg.LookupObjectId
 	}
 
+	objID, err := uuid.Parse(msg.ObjectId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("object_id: %w", err))
+	}
+	obj := s.cache.GetByID(objID)
+	if err := s.idents.ValidateFieldName(obj, msg.ApiName); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	isUnique := msg.IsUnique || msg.IsExternalId
+	if schema.FieldType(msg.Type) == schema.FieldEncrypted && isUnique {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("ENCRYPTED fields cannot be unique or external IDs"))
+	}
+	// JSON fields start out unfilterable and can never be sorted.
+	filterable, sortable := msg.IsFilterable, msg.IsSortable
+	if schema.FieldType(msg.Type) == schema.FieldJSON {
+		if err := checkJSONField(isUnique, sortable); err != nil {
+			return nil, err
+		}
+		filterable = cmp.Or(filterable, new(false))
+		sortable = new(false)
+	}
+	if msg.IsSearchable {
+		if err := checkSearchable(schema.FieldType(msg.Type)); err != nil {
+			return nil, err
+		}
+	}
+	if msg.IsAccentInsensitive {
+		if err := checkAccentInsensitive(schema.FieldType(msg.Type)); err != nil {
+			return nil, err
+		}
+	}
+	if msg.IsPii {
+		if err := checkPII(schema.FieldType(msg.Type)); err != nil {
+			return nil, err
+		}
+	}
+	masking, err := maskingPolicy(msg.Masking, schema.FieldType(msg.Type))
+	if err != nil {
+		return nil, err
+	}
+	if msg.IsExternalId || msg.IsSearchable {
+		if obj == nil {
+			return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("object not found"))
+		}
+	}
+	if msg.IsExternalId {
+		if schema.FieldType(msg.Type) == schema.FieldFormula {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("FORMULA fields cannot be external IDs"))
+		}
+	}
+
+	if err := schema.ValidateTranslations(schema.FieldType(msg.Type), msg.TypeConfig); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	denormalized, err := s.checkLabelSetting(schema.FieldType(msg.Type), msg.LookupObjectId, msg.TypeConfig)
+	if err != nil {
+		return nil, err
+	}
 	typeConfig := msg.TypeConfig
 	if typeConfig == "" {
 		typeConfig = "{}"
 	}
 
-	err := s.pool.QueryRow(ctx, `
+	var storedMasking *schema.MaskingPolicy
+	tx, err := db.Begin(ctx, s.pool)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("begin transaction: %w", err))
+	}
+	defer tx.Rollback(ctx)
+
+	warning, err := s.checkFieldLimit(ctx, tx, objID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(ctx, `
 		INSERT INTO metadata.fields (
 			object_id, api_name, title, description, type, type_config,
-			is_required, is_unique, lookup_object_id
-		) VALUES ($1, $2, $3, NULLIF($4,''), $5, $6::jsonb, $7, $8, $9::uuid)
-		RETURNING id, object_id::text, api_name, title, COALESCE(description,''),
-		          type, COALESCE(type_config::text,'{}'),
-		          is_required, is_unique, is_standard,
-		          COALESCE(storage_column,''), COALESCE(lookup_object_id::text,''),
-		          created_at::text, updated_at::text
-	`, msg.ObjectId, msg.ApiName, msg.Title, msg.Description, msg.Type, typeConfig,
-		msg.IsRequired, msg.IsUnique, lookupObjID).Scan(
-		&f.Id, &f.ObjectId, &f.ApiName, &f.Title, &f.Description,
-		&f.Type, &f.TypeConfig,
-		&f.IsRequired, &f.IsUnique, &f.IsStandard,
-		&f.StorageColumn, &f.LookupObjectId,
-		&f.CreatedAt, &f.UpdatedAt,
-	)
+			is_required, is_unique, lookup_object_id, is_external_id, is_searchable,
+			is_filterable, is_sortable, is_accent_insensitive, masking, is_pii
+		) VALUES ($1, $2, $3, NULLIF($4,''), $5, $6::jsonb, $7, $8, $9::uuid, $10, $11,
+			COALESCE($12::boolean, TRUE), COALESCE($13::boolean, TRUE), $14, $15::jsonb, $16)
+		RETURNING `+fieldReturning,
+		msg.ObjectId, msg.ApiName, msg.Title, msg.Description, msg.Type, typeConfig,
+		msg.IsRequired, isUnique, lookupObjID, msg.IsExternalId, msg.IsSearchable,
+		filterable, sortable, msg.IsAccentInsensitive, masking, msg.IsPii,
+	).Scan(fieldScanDest(f, &storedMasking)...)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("create field: %w", err))
 	}
+	f.Masking = maskingMeta(storedMasking)
+
+	if msg.IsExternalId {
+		fd := &schema.FieldDef{ID: uuid.MustParse(f.Id), APIName: f.ApiName}
+		if _, err := tx.Exec(ctx, hrqlpg.BuildExternalIDIndex(obj, fd)); err != nil {
+			if pgErr, ok := errors.AsType[*pgconn.PgError](err); ok && pgErr.Code == "23505" {
+				return nil, connect.NewError(connect.CodeFailedPrecondition,
+					fmt.Errorf("existing %s records have duplicate %q values: %s", obj.APIName, f.ApiName, pgErr.Detail))
+			}
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("create external ID index: %w", err))
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("commit: %w", err))
+	}
 
 	s.reloadCache(ctx)
-	return connect.NewResponse(&registryv1.CreateFieldResponse{Field: f}), nil
+	if denormalized {
+		s.backfillLabels(ctx, objID)
+	}
+	if f.IsSearchable {
+		s.syncSearchIndex(ctx, objID, uuid.MustParse(f.Id), false)
+	}
+	if f.IsSortable {
+		s.syncSortIndexes(ctx, objID, uuid.MustParse(f.Id))
+	}
+	return connect.NewResponse(&registryv1.CreateFieldResponse{Field: f, Warning: warning}), nil
 }
 
 func (s *MetadataService) UpdateField(ctx context.Context, req *connect.Request[registryv1.UpdateFieldRequest]) (*connect.Response[registryv1.UpdateFieldResponse], error) {
+	if err := s.holdChange(ctx, "UpdateField", req.Msg.ObjectId, req.Msg); err != nil {
+		return nil, err
+	}
+
 	msg := req.Msg
 	f := &registryv1.FieldMeta{}
+	if msg.Masking != nil && msg.ClearMasking {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("masking and clear_masking are mutually exclusive"))
+	}
 
+	var (
+		denormalized bool
+		masking      *schema.MaskingPolicy
+	)
+	if obj := s.cache.GetByID(uuid.MustParse(msg.ObjectId)); obj != nil {
+		id := uuid.MustParse(msg.Id)
+		for i := range obj.Fields {
+			fd := &obj.Fields[i]
+			if fd.ID != id {
+				continue
+			}
+			if err := schema.ValidateTranslations(fd.Type, msg.TypeConfig); err != nil {
+				return nil, connect.NewError(connect.CodeInvalidArgument, err)
+			}
+			if msg.GetIsSearchable() {
+				if err := checkSearchable(fd.Type); err != nil {
+					return nil, err
+				}
+			}
+			if msg.GetIsAccentInsensitive() {
+				if err := checkAccentInsensitive(fd.Type); err != nil {
+					return nil, err
+				}
+			}
+			if msg.GetIsPii() {
+				if err := checkPII(fd.Type); err != nil {
+					return nil, err
+				}
+			}
+			if fd.IsJSON() {
+				if err := checkJSONField(msg.IsUnique, msg.IsSortable); err != nil {
+					return nil, err
+				}
+			}
+			var err error
+			if masking, err = maskingPolicy(msg.Masking, fd.Type); err != nil {
+				return nil, err
+			}
+			var lookupID string
+			if fd.LookupObjectID != nil {
+				lookupID = fd.LookupObjectID.String()
+			}
+			if denormalized, err = s.checkLabelSetting(fd.Type, lookupID, msg.TypeConfig); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if msg.Masking != nil && masking == nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("field not found"))
+	}
 	typeConfig := msg.TypeConfig
 	if typeConfig == "" {
 		typeConfig = "{}"
 	}
 
+	var storedMasking *schema.MaskingPolicy
 	err := s.pool.QueryRow(ctx, `
 		UPDATE metadata.fields
 		SET title = COALESCE(NULLIF($3,''), title),
 		    description = CASE WHEN $4 = '' THEN description ELSE $4 END,
 		    type_config = CASE WHEN $5 = '{}' THEN type_config ELSE $5::jsonb END,
 		    is_required = $6,
-		    is_unique = $7,
+		    is_unique = $7 OR is_external_id,
+		    is_searchable = COALESCE($8, is_searchable),
+		    is_filterable = COALESCE($9, is_filterable),
+		    is_sortable = COALESCE($10, is_sortable),
+		    is_accent_insensitive = COALESCE($11, is_accent_insensitive),
+		    masking = CASE WHEN $13 THEN NULL WHEN $12::jsonb IS NULL THEN masking ELSE $12::jsonb END,
+		    is_pii = COALESCE($14, is_pii),
 		    updated_at = now()
 		WHERE object_id = $1 AND id = $2
-		RETURNING id, object_id::text, api_name, title, COALESCE(description,''),
+		RETURNING `+fieldReturning,
+		msg.ObjectId, msg.Id, msg.Title, msg.Description, typeConfig,
+		msg.IsRequired, msg.IsUnique, msg.IsSearchable, msg.IsFilterable, msg.IsSortable,
+		msg.IsAccentInsensitive, masking, msg.ClearMasking, msg.IsPii,
+	).Scan(fieldScanDest(f, &storedMasking)...)
+	if err == pgx.ErrNoRows {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("field not found"))
+	}
+	if pgErr, ok := errors.AsType[*pgconn.PgError](err); ok && pgErr.ConstraintName == "chk_fields_searchable_type" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("only text and choice fields can be searchable"))
+	}
+	if pgErr, ok := errors.AsType[*pgconn.PgError](err); ok && pgErr.ConstraintName == "chk_fields_accent_insensitive_type" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("only text and choice fields can be accent-insensitive"))
+	}
+	if pgErr, ok := errors.AsType[*pgconn.PgError](err); ok && pgErr.ConstraintName == "chk_fields_json_unordered" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("JSON fields cannot be unique or sortable"))
+	}
+	if pgErr, ok := errors.AsType[*pgconn.PgError](err); ok && pgErr.ConstraintName == "chk_fields_pii_type" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("only text, email, url, phone and date fields can be flagged PII"))
+	}
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("update field: %w", err))
+	}
+	f.Masking = maskingMeta(storedMasking)
+
+	s.reloadCache(ctx)
+	if denormalized {
+		s.backfillLabels(ctx, uuid.MustParse(msg.ObjectId))
+	}
+	switch {
+	case msg.IsAccentInsensitive != nil:
+		// The index expression changes with the flag.
+		s.syncSearchIndex(ctx, uuid.MustParse(msg.ObjectId), uuid.MustParse(f.Id), true)
+	case msg.IsSearchable != nil:
+		s.syncSearchIndex(ctx, uuid.MustParse(msg.ObjectId), uuid.MustParse(f.Id), false)
+	}
+	if msg.IsSortable != nil {
+		s.syncSortIndexes(ctx, uuid.MustParse(msg.ObjectId), uuid.MustParse(f.Id))
+	}
+	return connect.NewResponse(&registryv1.UpdateFieldResponse{Field: f}), nil
+}
+
+// fieldReturning is the RETURNING list of field writes, scanned by
+// fieldScanDest.
+const fieldReturning = `id, object_id::text, api_name, title, COALESCE(description,''),
 		          type, COALESCE(type_config::text,'{}'),
 		          is_required, is_unique, is_standard,
 		          COALESCE(storage_column,''), COALESCE(lookup_object_id::text,''),
-		          created_at::text, updated_at::text
-	`, msg.ObjectId, msg.Id, msg.Title, msg.Description, typeConfig,
-		msg.IsRequired, msg.IsUnique).Scan(
+		          created_at::text, updated_at::text, is_external_id, is_searchable,
+		          is_filterable, is_sortable, is_accent_insensitive, masking, is_pii,
+		          ARRAY(SELECT a.alias FROM metadata.field_aliases a WHERE a.field_id = fields.id ORDER BY a.created_at, a.alias)`
+
+func fieldScanDest(f *registryv1.FieldMeta, masking **schema.MaskingPolicy) []any {
+	return []any{
 		&f.Id, &f.ObjectId, &f.ApiName, &f.Title, &f.Description,
 		&f.Type, &f.TypeConfig,
 		&f.IsRequired, &f.IsUnique, &f.IsStandard,
 		&f.StorageColumn, &f.LookupObjectId,
-		&f.CreatedAt, &f.UpdatedAt,
+		&f.CreatedAt, &f.UpdatedAt, &f.IsExternalId, &f.IsSearchable,
+		&f.IsFilterable, &f.IsSortable, &f.IsAccentInsensitive, masking, &f.IsPii,
+		&f.Aliases,
+	}
+}
+
+func (s *MetadataService) DeleteField(ctx context.Context, req *connect.Request[registryv1.DeleteFieldRequest]) (*connect.Response[registryv1.DeleteFieldResponse], error) {
+	if err := s.holdChange(ctx, "DeleteField", req.Msg.ObjectId, req.Msg); err != nil {
+		return nil, err
+	}
+
+	tx, err := db.Begin(ctx, s.pool)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("begin transaction: %w", err))
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		fd        schema.FieldDef
+		objID     uuid.UUID
+		isExtID   bool
+		storedCol *string
 	)
+	err = tx.QueryRow(ctx, `
+		DELETE FROM metadata.fields WHERE object_id = $1 AND id = $2
+		RETURNING id, object_id, is_external_id, is_searchable, storage_column
+	`, req.Msg.ObjectId, req.Msg.Id).Scan(&fd.ID, &objID, &isExtID, &fd.IsSearchable, &storedCol)
 	if err == pgx.ErrNoRows {
 		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("field not found"))
 	}
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("update field: %w", err))
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("delete field: %w", err))
+	}
+
+	if obj := s.cache.GetByID(objID); obj != nil {
+		fd.IsExternalID, fd.StorageColumn = isExtID, storedCol
+		if err := dropFieldIndexes(ctx, tx, obj, &fd); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("commit: %w", err))
 	}
 
 	s.reloadCache(ctx)
-	return connect.NewResponse(&registryv1.UpdateFieldResponse{Field: f}), nil
+	return connect.NewResponse(&registryv1.DeleteFieldResponse{}), nil
 }
 
-func (s *MetadataService) DeleteField(ctx context.Context, req *connect.Request[registryv1.DeleteFieldRequest]) (*connect.Response[registryv1.DeleteFieldResponse], error) {
-	tag, err := s.pool.Exec(ctx, `DELETE FROM metadata.fields WHERE object_id = $1 AND id = $2`, req.Msg.ObjectId, req.Msg.Id)
+// dropFieldIndexes drops the indexes a field of obj deleted in tx owned.
+func dropFieldIndexes(ctx context.Context, tx pgx.Tx, obj *schema.ObjectDef, fd *schema.FieldDef) error {
+	// Custom external IDs own a unique index that would otherwise outlive the field.
+	if fd.IsExternalID {
+		if ddl := hrqlpg.BuildDropExternalIDIndex(obj, fd); ddl != "" {
+			if _, err := tx.Exec(ctx, ddl); err != nil {
+				return connect.NewError(connect.CodeInternal, fmt.Errorf("drop external ID index: %w", err))
+			}
+		}
+	}
+	// So does a searchable field's trigram index, whether or not this
+	// instance created it.
+	if fd.IsSearchable {
+		if _, err := tx.Exec(ctx, hrqlpg.BuildDropSearchIndex(obj, fd, false)); err != nil {
+			return connect.NewError(connect.CodeInternal, fmt.Errorf("drop search index: %w", err))
+		}
+	}
+	if fd.StorageColumn == nil {
+		for _, ddl := range hrqlpg.BuildDropSortIndexes(obj, fd, false) {
+			if _, err := tx.Exec(ctx, ddl); err != nil {
+				return connect.NewError(connect.CodeInternal, fmt.Errorf("drop sort index: %w", err))
+			}
+		}
+	}
+	return nil
+}
+
+// ── Helpers ─────────────────────────────────────────────────────────
+
+// syncCache reloads the schema cache before a "strong" read so it reflects
+// catalog writes made through other instances. Default reads use the cache as is.
+// ── Clients ─────────────────────────────────────────────────────────
+
+func (s *MetadataService) GenerateClient(ctx context.Context, req *connect.Request[registryv1.GenerateClientRequest]) (*connect.Response[registryv1.GenerateClientResponse], error) {
+	if err := s.syncCache(ctx, req.Msg.Consistency); err != nil {
+		return nil, err
+	}
+
+	objs := s.cache.Objects()
+	if len(req.Msg.Objects) > 0 {
+		objs = make([]*schema.ObjectDef, 0, len(req.Msg.Objects))
+		for _, name := range req.Msg.Objects {
+			obj := s.cache.Get(name)
+			if obj == nil {
+				return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("object %q not found", name))
+			}
+			if !slices.Contains(objs, obj) {
+				objs = append(objs, obj)
+			}
+		}
+	}
+
+	file, err := codegen.Generate(codegen.Language(req.Msg.Language), objs, s.cache, codegen.Options{GoPackage: req.Msg.GoPackage})
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("delete field: %w", err))
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
 	}
-	if tag.RowsAffected() == 0 {
-		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("field not found"))
+	return connect.NewResponse(&registryv1.GenerateClientResponse{
+		Filename:   file.Name,
+		Source:     file.Source,
+		SchemaHash: file.SchemaHash,
+	}), nil
+}
+
+func (s *MetadataService) syncCache(ctx context.Context, consistency string) error {
+	if consistency != "strong" {
+		return nil
+	}
+	if err := s.cache.Load(ctx, s.pool); err != nil {
+		return connect.NewError(connect.CodeInternal, err)
 	}
+	return nil
+}
 
-	s.reloadCache(ctx)
-	return connect.NewResponse(&registryv1.DeleteFieldResponse{}), nil
+// pgTimestamp renders t the way Postgres renders timestamptz::text in a UTC
+// session, so cached reads match the timestamps returned by writes.
+func pgTimestamp(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05.999999-07")
 }
 
-// ── Helpers ─────────────────────────────────────────────────────────
+func objectMeta(obj *schema.ObjectDef) *registryv1.ObjectMeta {
+	o := &registryv1.ObjectMeta{
+		Id:                   obj.ID.String(),
+		ApiName:              obj.APIName,
+		Title:                obj.Title,
+		PluralTitle:          obj.PluralTitle,
+		Description:          obj.Description,
+		IsStandard:           obj.IsStandard,
+		SupportsCustomFields: obj.SupportsCustomFields,
+		CreatedAt:            pgTimestamp(obj.CreatedAt),
+		UpdatedAt:            pgTimestamp(obj.UpdatedAt),
+		DefaultOrder:         obj.DefaultOrder,
+		DefaultPageSize:      int32(obj.DefaultPageSize),
+		MaxPageSize:          int32(obj.MaxPageSize),
+		DisplayTemplate:      obj.DisplayTemplate,
+		MaxCustomFields:      int32(obj.MaxCustomFields),
+		MaxDataBytes:         int32(obj.MaxDataBytes),
+		ExactCountThreshold:  obj.ExactCountThreshold,
+		CacheMaxAgeSeconds:   int32(obj.CacheMaxAge / time.Second),
+	}
+	if obj.StorageSchema != nil {
+		o.StorageSchema = *obj.StorageSchema
+	}
+	if obj.StorageTable != nil {
+		o.StorageTable = *obj.StorageTable
+	}
+	if obj.CategoryID != nil {
+		o.CategoryId = obj.CategoryID.String()
+	}
+	if hook := obj.ValidationWebhook; hook != nil {
+		o.ValidationWebhook = &registryv1.ValidationWebhook{
+			Url:       hook.URL,
+			TimeoutMs: int32(hook.Timeout.Milliseconds()),
+			FailOpen:  hook.FailOpen,
+		}
+	}
+	if obj.Deprecation != nil {
+		o.Deprecation = deprecationMeta(obj.Deprecation)
+	}
+	for _, a := range obj.Aliases {
+		o.Aliases = append(o.Aliases, a.Name)
+	}
+	return o
+}
+
+// fieldMeta renders fd with its title and choice option labels translated
+// for locale ("" for the stored title and option keys).
+func fieldMeta(fd *schema.FieldDef, locale string) *registryv1.FieldMeta {
+	f := &registryv1.FieldMeta{
+		Id:                  fd.ID.String(),
+		ObjectId:            fd.ObjectID.String(),
+		ApiName:             fd.APIName,
+		Title:               fd.LocalizedTitle(locale),
+		Description:         fd.Description,
+		Type:                string(fd.Type),
+		TypeConfig:          string(fd.TypeConfig),
+		IsRequired:          fd.IsRequired,
+		IsUnique:            fd.IsUnique,
+		IsStandard:          fd.IsStandard,
+		IsExternalId:        fd.IsExternalID,
+		IsSearchable:        fd.IsSearchable,
+		IsAccentInsensitive: fd.AccentInsensitive,
+		IsFilterable:        !fd.NotFilterable,
+		IsSortable:          !fd.NotSortable,
+		Masking:             maskingMeta(fd.Masking),
+		IsPii:               fd.IsPII,
+		CreatedAt:           pgTimestamp(fd.CreatedAt),
+		UpdatedAt:           pgTimestamp(fd.UpdatedAt),
+	}
+	if f.TypeConfig == "" {
+		f.TypeConfig = "{}"
+	}
+	if fd.StorageColumn != nil {
+		f.StorageColumn = *fd.StorageColumn
+	}
+	if fd.LookupObjectID != nil {
+		f.LookupObjectId = fd.LookupObjectID.String()
+	}
+	for _, opt := range fd.ChoiceOptions(locale) {
+		f.Options = append(f.Options, &registryv1.ChoiceOption{Value: opt.Value, Label: opt.Label})
+	}
+	for _, a := range fd.Aliases {
+		f.Aliases = append(f.Aliases, a.Name)
+	}
+	return f
+}
 
-func (s *MetadataService) listFieldsForObject(ctx context.Context, objectID string) ([]*registryv1.FieldMeta, error) {
-	rows, err := s.pool.Query(ctx, `
-		SELECT id, object_id::text, api_name, title, COALESCE(description,''),
-		       type, COALESCE(type_config::text,'{}'),
-		       is_required, is_unique, is_standard,
-		       COALESCE(storage_column,''), COALESCE(lookup_object_id::text,''),
-		       created_at::text, updated_at::text
-		FROM metadata.fields WHERE object_id = $1 ORDER BY created_at
-	`, objectID)
+// requestLocale returns the locale requested explicitly or, failing that, the
+// preferred language of the Accept-Language header.
+func requestLocale(locale string, h http.Header) string {
+	if locale == "" {
+		locale = h.Get("Accept-Language")
+	}
+	if locale == "*" {
+		return ""
+	}
+	return schema.NormalizeLocale(locale)
+}
+
+// fieldPageToken is the keyset position of the last field on a ListFields page.
+type fieldPageToken struct {
+	Order string    `json:"o"`
+	Key   string    `json:"k"`
+	ID    uuid.UUID `json:"id"`
+}
+
+// fieldSortKey returns the value a field is ordered by. Timestamps use a
+// fixed-width layout so they compare correctly as strings.
+func fieldSortKey(fd *schema.FieldDef, orderField string) string {
+	switch orderField {
+	case "api_name":
+		return fd.APIName
+	case "title":
+		return fd.Title
+	default:
+		return fd.CreatedAt.UTC().Format("2006-01-02T15:04:05.000000000Z")
+	}
+}
+
+// paginateFields orders fields by orderBy ("created_at" when empty, ".desc" to
+// reverse), with the field ID as tie-breaker, and returns the page after
+// pageToken. A pageSize of 0 returns every remaining field.
+func paginateFields(fields []*schema.FieldDef, orderBy string, pageSize int, pageToken string) ([]*schema.FieldDef, string, error) {
+	if orderBy == "" {
+		orderBy = "created_at"
+	}
+	orderField, desc := strings.CutSuffix(orderBy, ".desc")
+
+	compare := func(aKey string, aID uuid.UUID, bKey string, bID uuid.UUID) int {
+		c := cmp.Or(strings.Compare(aKey, bKey), bytes.Compare(aID[:], bID[:]))
+		if desc {
+			return -c
+		}
+		return c
+	}
+
+	sorted := slices.Clone(fields)
+	slices.SortFunc(sorted, func(a, b *schema.FieldDef) int {
+		return compare(fieldSortKey(a, orderField), a.ID, fieldSortKey(b, orderField), b.ID)
+	})
+
+	if pageToken != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(pageToken)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid page_token")
+		}
+		var tok fieldPageToken
+		if err := json.Unmarshal(raw, &tok); err != nil {
+			return nil, "", fmt.Errorf("invalid page_token")
+		}
+		if tok.Order != orderBy {
+			return nil, "", fmt.Errorf("page_token was issued for order_by %q, not %q", tok.Order, orderBy)
+		}
+		start := slices.IndexFunc(sorted, func(fd *schema.FieldDef) bool {
+			return compare(fieldSortKey(fd, orderField), fd.ID, tok.Key, tok.ID) > 0
+		})
+		if start < 0 {
+			start = len(sorted)
+		}
+		sorted = sorted[start:]
+	}
+
+	if pageSize == 0 || len(sorted) <= pageSize {
+		return sorted, "", nil
+	}
+
+	page := sorted[:pageSize]
+	last := page[len(page)-1]
+	raw, err := json.Marshal(fieldPageToken{Order: orderBy, Key: fieldSortKey(last, orderField), ID: last.ID})
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("query fields: %w", err))
+		return nil, "", err
 	}
-	defer rows.Close()
+	return page, base64.RawURLEncoding.EncodeToString(raw), nil
+}
 
-	var fields []*registryv1.FieldMeta
-	for rows.Next() {
-		f := &registryv1.FieldMeta{}
-		if err := rows.Scan(
-			&f.Id, &f.ObjectId, &f.ApiName, &f.Title, &f.Description,
-			&f.Type, &f.TypeConfig,
-			&f.IsRequired, &f.IsUnique, &f.IsStandard,
-			&f.StorageColumn, &f.LookupObjectId,
-			&f.CreatedAt, &f.UpdatedAt,
-		); err != nil {
-			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("scan field: %w", err))
+// checkLabelSetting validates type_config.denormalize_label and reports whether
+// it is set. Only LOOKUP fields may denormalize a label, and it must name a
+// plain field of the lookup target.
+func (s *MetadataService) checkLabelSetting(fieldType schema.FieldType, lookupObjectID, typeConfig string) (bool, error) {
+	probe := &schema.FieldDef{Type: schema.FieldLookup, TypeConfig: json.RawMessage(typeConfig)}
+	name := probe.LabelField()
+	if name == "" {
+		return false, nil
+	}
+	if fieldType != schema.FieldLookup {
+		return false, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("denormalize_label requires a LOOKUP field"))
+	}
+	id, err := uuid.Parse(lookupObjectID)
+	if err != nil {
+		return false, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("denormalize_label requires lookup_object_id"))
+	}
+	target := s.cache.GetByID(id)
+	if target == nil {
+		return false, connect.NewError(connect.CodeNotFound, fmt.Errorf("lookup object not found"))
+	}
+	if err := hrqlpg.ValidateLabelField(target, name); err != nil {
+		return false, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	return true, nil
+}
+
+// backfillLabels fills the denormalized labels of every record of an object
+// after one of its LOOKUP fields gains denormalize_label. Like reloadCache it
+// is best effort: the next write to a record refreshes its labels anyway.
+func (s *MetadataService) backfillLabels(ctx context.Context, objectID uuid.UUID) {
+	obj := s.cache.GetByID(objectID)
+	if obj == nil {
+		return
+	}
+	stmt, ok, err := hrqlpg.BuildRefreshLabels(obj, s.cache, uuid.Nil, nil)
+	if err == nil && ok {
+		_, err = s.pool.Exec(ctx, stmt.SQL, stmt.Args...)
+	}
+	if err != nil {
+		log.Printf("backfill lookup labels for %s: %v", obj.APIName, err)
+	}
+}
+
+// checkSearchable rejects is_searchable on a type string operations do not
+// match (migration 000020 enforces the same list).
+func checkSearchable(t schema.FieldType) error {
+	if fd := (schema.FieldDef{Type: t}); !fd.CanSearch() {
+		return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("%s fields cannot be searchable; only text and choice fields can", t))
+	}
+	return nil
+}
+
+// checkJSONField rejects the flags a JSON field cannot have: its values
+// have no identity or order (migration 000030).
+func checkJSONField(unique bool, sortable *bool) error {
+	if unique {
+		return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("JSON fields cannot be unique or external IDs"))
+	}
+	if sortable != nil && *sortable {
+		return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("JSON fields cannot be sortable"))
+	}
+	return nil
+}
+
+// checkAccentInsensitive is checkSearchable for is_accent_insensitive
+// (migration 000028).
+func checkAccentInsensitive(t schema.FieldType) error {
+	if fd := (schema.FieldDef{Type: t}); !fd.CanSearch() {
+		return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("%s fields cannot be accent-insensitive; only text and choice fields can", t))
+	}
+	return nil
+}
+
+// checkPII rejects is_pii on fields anonymization cannot make a fake value
+// for (migration 000036).
+func checkPII(t schema.FieldType) error {
+	if fd := (schema.FieldDef{Type: t}); !fd.CanAnonymize() {
+		return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("%s fields cannot be flagged PII; only text, email, url, phone and date fields can", t))
+	}
+	return nil
+}
+
+// maskingPolicy converts a FieldMasking to the policy stored in
+// metadata.fields.masking (migration 000035), checking that its transforms
+// apply to fields of type t. A nil m gives a nil policy.
+func maskingPolicy(m *registryv1.FieldMasking, t schema.FieldType) (*schema.MaskingPolicy, error) {
+	if m == nil {
+		return nil, nil
+	}
+	p := &schema.MaskingPolicy{Default: schema.MaskTransform(m.DefaultTransform)}
+	for _, r := range m.Rules {
+		p.Rules = append(p.Rules, schema.MaskRule{Permission: r.Permission, Transform: schema.MaskTransform(r.Transform)})
+	}
+	if err := p.Validate(t); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	return p, nil
+}
+
+// maskingMeta is the FieldMasking of a stored policy, nil when unmasked.
+func maskingMeta(p *schema.MaskingPolicy) *registryv1.FieldMasking {
+	if p == nil {
+		return nil
+	}
+	m := &registryv1.FieldMasking{DefaultTransform: string(p.Default)}
+	for _, r := range p.Rules {
+		m.Rules = append(m.Rules, &registryv1.MaskingRule{Permission: r.Permission, Transform: string(r.Transform)})
+	}
+	return m
+}
+
+// syncSearchIndex creates or drops the trigram index of a field to match its
+// is_searchable flag, when search indexes are enabled; rebuild drops an
+// existing index first, for a changed expression. The index is built
+// CONCURRENTLY, outside the field's transaction, so writes to a large table
+// are not blocked; a failed build is logged and the field stays unindexed
+// (SearchIndexReport lists it).
+func (s *MetadataService) syncSearchIndex(ctx context.Context, objectID, fieldID uuid.UUID, rebuild bool) {
+	if !s.searchIndexes {
+		return
+	}
+	obj := s.cache.GetByID(objectID)
+	if obj == nil {
+		return
+	}
+	for i := range obj.Fields {
+		fd := &obj.Fields[i]
+		if fd.ID != fieldID {
+			continue
+		}
+		var ddl []string
+		if !fd.IsSearchable || rebuild {
+			ddl = append(ddl, hrqlpg.BuildDropSearchIndex(obj, fd, true))
 		}
-		fields = append(fields, f)
+		if fd.IsSearchable {
+			ddl = append(ddl, hrqlpg.BuildSearchIndex(obj, fd))
+		}
+		for _, stmt := range ddl {
+			if _, err := s.pool.Exec(ctx, stmt); err != nil {
+				log.Printf("sync search index for %s.%s: %v", obj.APIName, fd.APIName, err)
+				break
+			}
+		}
+	}
+}
+
+// syncSortIndexes creates or drops the sort indexes of a document field to
+// match its is_sortable flag, when sort indexes are enabled. Like search
+// indexes they are built CONCURRENTLY after the field commits, and a failed
+// build is only logged: the field still sorts, by scanning.
+func (s *MetadataService) syncSortIndexes(ctx context.Context, objectID, fieldID uuid.UUID) {
+	if !s.sortIndexes {
+		return
+	}
+	obj := s.cache.GetByID(objectID)
+	if obj == nil {
+		return
 	}
-	if err := rows.Err(); err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
+	for i := range obj.Fields {
+		fd := &obj.Fields[i]
+		if fd.ID != fieldID || fd.StorageColumn != nil {
+			continue
+		}
+		ddl := hrqlpg.BuildDropSortIndexes(obj, fd, true)
+		if hrqlpg.HasSortIndexes(fd) {
+			ddl = hrqlpg.BuildSortIndexes(obj, fd)
+		}
+		for _, stmt := range ddl {
+			if _, err := s.pool.Exec(ctx, stmt); err != nil {
+				log.Printf("sync sort indexes for %s.%s: %v", obj.APIName, fd.APIName, err)
+				break
+			}
+		}
 	}
-	return fields, nil
 }
 
 func (s *MetadataService) reloadCache(ctx context.Context) {
-	// Best-effort reload; errors are logged but don't fail the mutation.
+	// Best-effort reload so the caller reads its own write; errors don't fail
+	// the mutation, as the committed change is also in the schema outbox and
+	// the server's schema.Watcher retries until the cache reflects it.
 	_ = s.cache.Load(ctx, s.pool)
 }
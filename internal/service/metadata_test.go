@@ -0,0 +1,117 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+func TestPaginateFields(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	field := func(id byte, apiName, title string, created time.Time) *schema.FieldDef {
+		return &schema.FieldDef{ID: uuid.UUID{15: id}, APIName: apiName, Title: title, CreatedAt: created}
+	}
+	// bonus and amount share a created_at, so the ID breaks the tie.
+	fields := []*schema.FieldDef{
+		field(4, "salary", "Salary", t0.Add(2*time.Second)),
+		field(2, "bonus", "Annual bonus", t0.Add(time.Second)),
+		field(1, "amount", "Zero-based amount", t0.Add(time.Second)),
+		field(3, "currency", "Currency", t0),
+	}
+
+	tests := []struct {
+		orderBy string
+		want    []string
+	}{
+		{"", []string{"currency", "amount", "bonus", "salary"}},
+		{"created_at.desc", []string{"salary", "bonus", "amount", "currency"}},
+		{"api_name", []string{"amount", "bonus", "currency", "salary"}},
+		{"api_name.desc", []string{"salary", "currency", "bonus", "amount"}},
+		{"title", []string{"bonus", "currency", "salary", "amount"}},
+	}
+	for _, tt := range tests {
+		all, next, err := paginateFields(fields, tt.orderBy, 0, "")
+		if err != nil || next != "" {
+			t.Fatalf("order_by %q: unpaged: next %q, err %v", tt.orderBy, next, err)
+		}
+		if got := apiNames(all); !slices.Equal(got, tt.want) {
+			t.Errorf("order_by %q: unpaged %v, want %v", tt.orderBy, got, tt.want)
+		}
+
+		// Walk pages of 3 through the tokens: the second page holds the last
+		// field and carries no token.
+		first, token, err := paginateFields(fields, tt.orderBy, 3, "")
+		if err != nil || token == "" {
+			t.Fatalf("order_by %q: first page: token %q, err %v", tt.orderBy, token, err)
+		}
+		second, token2, err := paginateFields(fields, tt.orderBy, 3, token)
+		if err != nil {
+			t.Fatalf("order_by %q: second page: %v", tt.orderBy, err)
+		}
+		if token2 != "" {
+			t.Errorf("order_by %q: last page returned token %q", tt.orderBy, token2)
+		}
+		if got := apiNames(append(first, second...)); !slices.Equal(got, tt.want) {
+			t.Errorf("order_by %q: paged %v, want %v", tt.orderBy, got, tt.want)
+		}
+	}
+
+	// A page ending exactly on the last field issues no token.
+	if _, next, _ := paginateFields(fields, "", 4, ""); next != "" {
+		t.Errorf("full page returned token %q", next)
+	}
+
+	_, token, _ := paginateFields(fields, "api_name", 2, "")
+	if _, _, err := paginateFields(fields, "api_name.desc", 2, token); err == nil ||
+		!strings.Contains(err.Error(), `issued for order_by "api_name", not "api_name.desc"`) {
+		t.Errorf("token reused with another order_by: %v", err)
+	}
+	if _, _, err := paginateFields(fields, "", 2, "not-a-token!"); err == nil {
+		t.Error("malformed token accepted")
+	}
+}
+
+func TestFieldMetaLocale(t *testing.T) {
+	fd := &schema.FieldDef{
+		APIName: "employment_type", Title: "Employment type", Type: schema.FieldChoice,
+		TypeConfig: json.RawMessage(`{"options": ["FULL_TIME"], "translations": {"de": {"title": "Beschäftigungsart", "options": {"FULL_TIME": "Vollzeit"}}}}`),
+	}
+
+	tests := []struct {
+		locale, acceptLanguage string
+		title, label           string
+	}{
+		{"", "", "Employment type", "FULL_TIME"},
+		{"de", "", "Beschäftigungsart", "Vollzeit"},
+		{"", "de-CH,de;q=0.9,en;q=0.8", "Beschäftigungsart", "Vollzeit"},
+		{"en", "de", "Employment type", "FULL_TIME"}, // the field beats the header
+		{"", "fr, de", "Employment type", "FULL_TIME"},
+		{"", "*", "Employment type", "FULL_TIME"},
+	}
+	for _, tt := range tests {
+		h := http.Header{}
+		if tt.acceptLanguage != "" {
+			h.Set("Accept-Language", tt.acceptLanguage)
+		}
+		f := fieldMeta(fd, requestLocale(tt.locale, h))
+		if f.Title != tt.title || len(f.Options) != 1 || f.Options[0].Label != tt.label || f.Options[0].Value != "FULL_TIME" {
+			t.Errorf("locale %q, Accept-Language %q: title %q, options %v; want %q, %q",
+				tt.locale, tt.acceptLanguage, f.Title, f.Options, tt.title, tt.label)
+		}
+	}
+}
+
+func apiNames(fields []*schema.FieldDef) []string {
+	names := make([]string, len(fields))
+	for i, fd := range fields {
+		names[i] = fd.APIName
+	}
+	return names
+}
@@ -1,31 +1,44 @@
 package service
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/protobuf/types/known/structpb"
 
 	registryv1 "github.com/atlekbai/schema_registry/gen/registry/v1"
 	"github.com/atlekbai/schema_registry/gen/registry/v1/registryv1connect"
+	"github.com/atlekbai/schema_registry/internal/fieldcrypt"
 	"github.com/atlekbai/schema_registry/internal/hrql"
 	hrqlpg "github.com/atlekbai/schema_registry/internal/hrql/pg"
 	"github.com/atlekbai/schema_registry/internal/hrql/parser"
+	"github.com/atlekbai/schema_registry/internal/metrics"
 	"github.com/atlekbai/schema_registry/internal/schema"
 )
 
 type OrgService struct {
-	pool  *pgxpool.Pool
-	cache *schema.Cache
+	pool    *pgxpool.Pool
+	cache   *schema.Cache
+	cipher  *fieldcrypt.Cipher
+	expand  hrqlpg.ExpandStrategy
+	usage   *metrics.HRQL
+	limits  QueryLimits
+	counts  *countBreaker
+	cursors *hrqlpg.CursorSigner
 }
 
-func NewOrgService(pool *pgxpool.Pool, cache *schema.Cache) *OrgService {
-	return &OrgService{pool: pool, cache: cache}
+func NewOrgService(pool *pgxpool.Pool, cache *schema.Cache, cipher *fieldcrypt.Cipher, expand hrqlpg.ExpandStrategy, usage *metrics.HRQL, limits QueryLimits, cursors *hrqlpg.CursorSigner) *OrgService {
+	return &OrgService{pool: pool, cache: cache, cipher: cipher, expand: expand, usage: usage, limits: limits, counts: newCountBreaker(), cursors: cursors}
 }
 
 func (s *OrgService) RegisterHandler(interceptors ...connect.Interceptor) (string, http.Handler) {
@@ -35,45 +48,424 @@ func (s *OrgService) RegisterHandler(interceptors ...connect.Interceptor) (strin
 func (s *OrgService) Query(ctx context.Context, req *connect.Request[registryv1.QueryRequest]) (*connect.Response[registryv1.QueryResponse], error) {
 	msg := req.Msg
 
-	// Parse HRQL expression.
-	ast, err := parser.Parse(msg.Query)
+	// Parse and compile HRQL to a storage-agnostic Plan.
+	plan, warnings, err := s.compile(msg.Query, msg.SelfId, msg.TimeZone, msg.IncludeTerminated)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		return nil, compileError(err)
+	}
+	if plan.Union == nil {
+		metrics.SetUsageObject(ctx, cmp.Or(plan.Object, "employees"))
+	}
+
+	if err := applyAsOf(plan, msg.AsOf); err != nil {
+		return nil, err
 	}
 
-	// Compile AST to a storage-agnostic Plan.
-	compiler := hrql.NewCompiler(s.cache, msg.SelfId)
-	plan, err := compiler.Compile(ast)
+	checkCost, err := s.limits.costChecked(msg.SkipCostCheck, req.Header())
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		return nil, err
+	}
+	if err := s.checkMasked(plan, viewerOf(req.Header())); err != nil {
+		return nil, err
 	}
 
+	var resp *connect.Response[registryv1.QueryResponse]
 	switch plan.Kind {
-	case hrql.PlanList:
-		return s.runHRQLList(ctx, plan, msg)
+	case hrql.PlanList, hrql.PlanIDs:
+		resp, err = s.runHRQLList(ctx, plan, msg, viewerOf(req.Header()), checkCost)
 	case hrql.PlanScalar:
-		return s.runScalar(ctx, plan)
+		resp, err = s.runScalar(ctx, plan, checkCost)
 	case hrql.PlanBoolean:
-		return s.runBoolean(ctx, plan)
+		resp, err = s.runBoolean(ctx, plan)
+	case hrql.PlanBuckets:
+		resp, err = s.runBuckets(ctx, plan, checkCost)
 	default:
 		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("unknown plan kind %v", plan.Kind))
 	}
+	if err != nil {
+		return nil, err
+	}
+	if plan.Kind == hrql.PlanList {
+		resp.Msg.Result = &registryv1.ResultValue{Value: &registryv1.ResultValue_ListValue{ListValue: &registryv1.StructList{Values: resp.Msg.Results}}}
+	}
+	resp.Msg.Warnings = queryWarnings(warnings)
+	return resp, nil
 }
 
-// runHRQLList executes a list-producing HRQL plan.
-func (s *OrgService) runHRQLList(ctx context.Context, plan *hrql.Plan, msg *registryv1.QueryRequest) (*connect.Response[registryv1.QueryResponse], error) {
-	obj, err := s.employeesObj()
+// applyAsOf sets the point in time of a request's as_of field (if any) on
+// plan, which must not conflict with an as_of step in the query.
+func applyAsOf(plan *hrql.Plan, asOf string) error {
+	if asOf == "" {
+		return nil
+	}
+	ts, err := hrql.ParseAsOf(asOf)
+	if err != nil {
+		return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("as_of: %w", err))
+	}
+	if plan.AsOf != nil && !plan.AsOf.Equal(ts) {
+		return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("as_of request field conflicts with as_of step in query"))
+	}
+	plan.AsOf = &ts
+	return nil
+}
+
+// compile parses and compiles an HRQL query, recording usage metrics.
+// Calendar helpers resolve in timeZone (an IANA name, UTC when empty); org
+// functions keep employees who have left when includeTerminated is set.
+func (s *OrgService) compile(query, selfID, timeZone string, includeTerminated bool) (*hrql.Plan, []hrql.Warning, error) {
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, nil, fmt.Errorf("time_zone: unknown time zone %q", timeZone)
+	}
+	ast, err := parser.Parse(query)
+	s.usage.ObserveParse(ast, err)
+	if err != nil {
+		return nil, nil, err
+	}
+	start := time.Now()
+	plan, warnings, err := hrql.NewCompiler(s.cache, selfID).At(time.Now().In(loc)).IncludeTerminated(includeTerminated).Compile(ast)
+	s.usage.ObserveCompile(plan, time.Since(start), err)
+	return plan, warnings, err
+}
+
+// compileError maps a compile error to a Connect error: PermissionDenied for a
+// where or sort on a field outside the object's allowlist, InvalidArgument
+// otherwise.
+func compileError(err error) error {
+	if aerr := accessError(err); aerr != nil {
+		return aerr
+	}
+	return connect.NewError(connect.CodeInvalidArgument, err)
+}
+
+// checkMasked rejects a plan that computes a value from a field masked for v
+// (a projection, aggregate, bucket count or case branch), which would expose
+// what the mask hides. Records in list results are masked instead.
+func (s *OrgService) checkMasked(plan *hrql.Plan, v viewer) error {
+	obj := s.cache.Get(cmp.Or(plan.Object, "employees"))
+	if obj == nil {
+		return nil
+	}
+	fields := []string{plan.AggField}
+	if plan.Case != nil {
+		for _, w := range plan.Case.Whens {
+			fields = append(fields, w.Then.Field)
+		}
+		if plan.Case.Else != nil {
+			fields = append(fields, plan.Case.Else.Field)
+		}
+	}
+	for _, name := range fields {
+		if fd := obj.FieldsByAPIName[name]; fd != nil && v.transform(fd) != schema.MaskNone {
+			return connect.NewError(connect.CodePermissionDenied, fmt.Errorf("field %q is masked and cannot be projected or aggregated", name))
+		}
+	}
+	return s.checkMaskedExpr(plan.ScalarExpr, v)
+}
+
+// checkMaskedExpr is checkMasked for the sub-plans of a scalar expression.
+func (s *OrgService) checkMaskedExpr(expr hrql.ScalarExpr, v viewer) error {
+	switch e := expr.(type) {
+	case hrql.ScalarArith:
+		return cmp.Or(s.checkMaskedExpr(e.Left, v), s.checkMaskedExpr(e.Right, v))
+	case hrql.ScalarCoalesce:
+		return cmp.Or(s.checkMaskedExpr(e.Value, v), s.checkMaskedExpr(e.Default, v))
+	case hrql.ScalarFunc:
+		for _, arg := range e.Args {
+			if err := s.checkMaskedExpr(arg, v); err != nil {
+				return err
+			}
+		}
+	case hrql.ScalarSubquery:
+		return s.checkMasked(e.Plan, v)
+	}
+	return nil
+}
+
+func queryWarnings(warnings []hrql.Warning) []*registryv1.QueryWarning {
+	out := make([]*registryv1.QueryWarning, len(warnings))
+	for i, w := range warnings {
+		out[i] = &registryv1.QueryWarning{Code: w.Code, Message: w.Message}
+	}
+	return out
+}
+
+// ToFilters converts a where-only HRQL expression into REST list filters so
+// clients of GET /api/employees can adopt HRQL incrementally.
+func (s *OrgService) ToFilters(ctx context.Context, req *connect.Request[registryv1.ToFiltersRequest]) (*connect.Response[registryv1.ToFiltersResponse], error) {
+	msg := req.Msg
+
+	plan, warnings, err := s.compile(msg.Query, msg.SelfId, msg.TimeZone, false)
+	if err != nil {
+		return nil, compileError(err)
+	}
+
+	resp := &registryv1.ToFiltersResponse{Warnings: queryWarnings(warnings)}
+	filters, err := hrqlpg.PlanToFilters(plan)
+	if err != nil {
+		resp.Reason = err.Error()
+		return connect.NewResponse(resp), nil
+	}
+	resp.Translatable, resp.Filters = true, filters
+	return connect.NewResponse(resp), nil
+}
+
+// Explain returns the parsed AST of a query without compiling it, so it works
+// for expressions that reference objects or fields this schema lacks.
+func (s *OrgService) Explain(ctx context.Context, req *connect.Request[registryv1.ExplainRequest]) (*connect.Response[registryv1.ExplainResponse], error) {
+	node, err := parser.Parse(req.Msg.Query)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	ast, err := structpb.NewStruct(parser.NodeJSON(node))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("encode ast: %w", err))
+	}
+	return connect.NewResponse(&registryv1.ExplainResponse{Ast: ast}), nil
+}
+
+// BatchEvaluate answers many boolean checks with one round-trip, for callers
+// (e.g. access control) that need reports_to for dozens of pairs at once.
+// Items that fail to compile get a per-item error; the rest are still evaluated.
+func (s *OrgService) BatchEvaluate(ctx context.Context, req *connect.Request[registryv1.BatchEvaluateRequest]) (*connect.Response[registryv1.BatchEvaluateResponse], error) {
+	msg := req.Msg
+
+	batchPlan := &hrql.Plan{Kind: hrql.PlanBoolean}
+	if msg.AsOf != "" {
+		ts, err := hrql.ParseAsOf(msg.AsOf)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("as_of: %w", err))
+		}
+		batchPlan.AsOf = &ts
+	}
+	obj, err := s.planObj(batchPlan)
 	if err != nil {
 		return nil, err
 	}
 
+	results := make([]*registryv1.BatchEvaluateResult, len(msg.Items))
+	var checks []hrql.ReportsToCheck
+	var checkItems []int
+	for i, item := range msg.Items {
+		results[i] = &registryv1.BatchEvaluateResult{}
+		check, err := s.compileBatchItem(item, msg.SelfId, batchPlan)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		checks = append(checks, check)
+		checkItems = append(checkItems, i)
+	}
+
+	if len(checks) > 0 {
+		sql, args, err := hrqlpg.BatchReportsToSQL(checks, obj)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("translate batch: %w", err))
+		}
+		rows, err := s.pool.Query(ctx, sql, args...)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("batch query: %w", err))
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var idx int
+			var result *bool
+			if err := rows.Scan(&idx, &result); err != nil {
+				return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("scan batch row: %w", err))
+			}
+			results[checkItems[idx]].Result = result
+		}
+		if err := rows.Err(); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("batch query: %w", err))
+		}
+	}
+
+	return connect.NewResponse(&registryv1.BatchEvaluateResponse{Results: results}), nil
+}
+
+// compileBatchItem turns one BatchEvaluate item into a reports_to check. Query
+// items must compile to a boolean plan evaluated at the batch's as_of.
+func (s *OrgService) compileBatchItem(item *registryv1.BatchEvaluateItem, selfID string, batchPlan *hrql.Plan) (hrql.ReportsToCheck, error) {
+	if pair := item.GetReportsTo(); pair != nil {
+		return hrql.ReportsToCheck{
+			Emp:    hrql.EmployeeRef{ID: pair.EmployeeId},
+			Target: hrql.EmployeeRef{ID: pair.ManagerId},
+		}, nil
+	}
+
+	plan, _, err := s.compile(item.GetQuery(), selfID, "", false)
+	if err != nil {
+		return hrql.ReportsToCheck{}, err
+	}
+	if plan.Kind != hrql.PlanBoolean {
+		return hrql.ReportsToCheck{}, fmt.Errorf("query does not produce a boolean")
+	}
+	if plan.AsOf != nil && (batchPlan.AsOf == nil || !plan.AsOf.Equal(*batchPlan.AsOf)) {
+		return hrql.ReportsToCheck{}, fmt.Errorf("as_of steps are not supported per item; set as_of on the request")
+	}
+	check, ok := plan.BoolCondition.(hrql.ReportsToCheck)
+	if !ok {
+		return hrql.ReportsToCheck{}, fmt.Errorf("unsupported boolean condition type %T", plan.BoolCondition)
+	}
+	return check, nil
+}
+
+// Diff lists how the reporting tree changed between two points in time,
+// read from the employee history (see hrqlpg.AsOf).
+func (s *OrgService) Diff(ctx context.Context, req *connect.Request[registryv1.DiffRequest]) (*connect.Response[registryv1.DiffResponse], error) {
+	msg := req.Msg
+	from, err := hrql.ParseAsOf(msg.From)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("from: %w", err))
+	}
+	to, err := hrql.ParseAsOf(msg.To)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("to: %w", err))
+	}
+	if !from.Before(to) {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("from must be before to"))
+	}
+	if msg.RootId != "" {
+		if _, err := uuid.Parse(msg.RootId); err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("root_id: %w", err))
+		}
+	}
+	obj := s.cache.Get("employees")
+	if obj == nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("employees object not loaded"))
+	}
+
+	limit := int(cmp.Or(msg.Limit, defaultDiffLimit))
+	sql, args, err := hrqlpg.BuildOrgDiff(obj, from, to, msg.RootId, limit+1)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("build diff: %w", err))
+	}
+
+	var diffRows []hrqlpg.DiffRow
+	err = s.limits.List.Do(ctx, func() error {
+		rows, err := s.pool.Query(ctx, sql, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var r hrqlpg.DiffRow
+			if err := rows.Scan(r.ScanDest()...); err != nil {
+				return fmt.Errorf("scan diff row: %w", err)
+			}
+			diffRows = append(diffRows, r)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, queryFailed(fmt.Errorf("diff query: %w", err))
+	}
+
+	resp := &registryv1.DiffResponse{Summary: &registryv1.DiffSummary{}}
+	if len(diffRows) > limit {
+		diffRows, resp.Truncated = diffRows[:limit], true
+	}
+	for _, r := range diffRows {
+		for _, c := range orgChanges(r) {
+			switch c.Kind {
+			case changeHire:
+				resp.Summary.Hires++
+			case changeDeparture:
+				resp.Summary.Departures++
+			case changeManager:
+				resp.Summary.ManagerChanges++
+			case changeDepartment:
+				resp.Summary.DepartmentChanges++
+			}
+			resp.Changes = append(resp.Changes, c)
+		}
+	}
+	return connect.NewResponse(resp), nil
+}
+
+// defaultDiffLimit caps Diff responses when the request sets no limit.
+const defaultDiffLimit = 1000
+
+// OrgChange kinds.
+const (
+	changeHire       = "hire"
+	changeDeparture  = "departure"
+	changeManager    = "manager_change"
+	changeDepartment = "department_change"
+)
+
+// orgChanges classifies a diff row. Hires and departures carry the side the
+// employee is present on; an employee present on both sides may change
+// manager and department at once, and may also depart by end_date.
+func orgChanges(r hrqlpg.DiffRow) []*registryv1.OrgChange {
+	change := func(kind string) *registryv1.OrgChange {
+		return &registryv1.OrgChange{EmployeeId: r.ID, Kind: kind, Display: deref(r.Display)}
+	}
+	switch {
+	case !r.Existed:
+		c := change(changeHire)
+		c.ToManagerId, c.ToDepartmentId = deref(r.ToManager), deref(r.ToDepartment)
+		return []*registryv1.OrgChange{c}
+	case !r.Exists:
+		c := change(changeDeparture)
+		c.FromManagerId, c.FromDepartmentId = deref(r.FromManager), deref(r.FromDepartment)
+		return []*registryv1.OrgChange{c}
+	}
+
+	var changes []*registryv1.OrgChange
+	if deref(r.FromManager) != deref(r.ToManager) {
+		c := change(changeManager)
+		c.FromManagerId, c.ToManagerId = deref(r.FromManager), deref(r.ToManager)
+		changes = append(changes, c)
+	}
+	if deref(r.FromDepartment) != deref(r.ToDepartment) {
+		c := change(changeDepartment)
+		c.FromDepartmentId, c.ToDepartmentId = deref(r.FromDepartment), deref(r.ToDepartment)
+		changes = append(changes, c)
+	}
+	if r.Left {
+		c := change(changeDeparture)
+		c.FromManagerId, c.FromDepartmentId = deref(r.ToManager), deref(r.ToDepartment)
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// listParams resolves a list or ids plan and the list parameters of msg to
+// the object it reads and the parameters of its pages, along with the
+// plan's SQL translation.
+func (s *OrgService) listParams(ctx context.Context, plan *hrql.Plan, msg *registryv1.QueryRequest) (*schema.ObjectDef, *hrqlpg.QueryParams, *hrqlpg.SQLResult, error) {
+	obj, err := s.planObj(plan)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
 	// Translate plan to SQL.
 	sqlResult, err := hrqlpg.Translate(plan, obj, s.cache)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("translate plan: %w", err))
+		return nil, nil, nil, connect.NewError(connect.CodeInternal, fmt.Errorf("translate plan: %w", err))
 	}
 
-	input := listInputFromMsg(msg)
+	idsOnly := plan.Kind == hrql.PlanIDs
+	input := listInputFromMsg(msg, s.cursors)
+	input.Limits = s.limits.Lists
+	input.Cache = s.cache
+	if idsOnly {
+		if input.Select != "" || input.Expand != "" {
+			return nil, nil, nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("select and expand do not apply to a query ending in ids"))
+		}
+	}
+	idsLimit := hrqlpg.DefaultIDsLimit
+	if msg.Limit > 0 {
+		idsLimit = min(int(msg.Limit), hrqlpg.MaxIDsLimit)
+	}
 
 	// Apply plan-determined ordering/limit overrides.
 	if sqlResult.OrderBy != nil {
@@ -82,77 +474,189 @@ func (s *OrgService) runHRQLList(ctx context.Context, plan *hrql.Plan, msg *regi
 			input.Order += ".desc"
 		}
 	}
-	if sqlResult.Limit > 0 && input.Limit == 0 {
+	if sqlResult.Limit > 0 && msg.Limit == 0 {
 		input.Limit = int32(sqlResult.Limit)
+		idsLimit = min(sqlResult.Limit, hrqlpg.MaxIDsLimit)
+	}
+	if sqlResult.Random {
+		if input.Cursor != "" {
+			return nil, nil, nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("a random order has no pages; drop the cursor"))
+		}
+		input.Order = ""
+	}
+	if n := sqlResult.Sample; n > 0 {
+		maxN := s.limits.Lists.For(obj).MaxPageSize
+		if idsOnly {
+			maxN = hrqlpg.MaxIDsLimit
+		}
+		if n > maxN {
+			return nil, nil, nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("sample(%d) exceeds the page size limit of %d", n, maxN))
+		}
+		input.Limit = int32(n)
+		idsLimit = n
+	}
+	if idsOnly {
+		// ParseParams caps the page at the record limit; ids sets its own.
+		input.Limit = 0
 	}
 
 	params, err := hrqlpg.ParseParams(obj, input)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		return nil, nil, nil, paramsError(err)
+	}
+	if idsOnly {
+		params.Limit = idsLimit
+	}
+	if sqlResult.Random {
+		params.Order, params.Random = nil, true
+	}
+	if sqlResult.Sample > 0 {
+		if params.SamplePercent, err = s.samplePercent(ctx, obj, sqlResult.Sample); err != nil {
+			return nil, nil, nil, connect.NewError(connect.CodeInternal, err)
+		}
 	}
 
 	// Merge HRQL plan conditions with REST conditions.
 	params.Conditions = append(params.Conditions, plan.Conditions...)
 	params.SQLConditions, err = hrqlpg.TranslateConditions(params.Conditions, obj, s.cache)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		return nil, nil, nil, connect.NewError(connect.CodeInvalidArgument, err)
 	}
 
+	params.Computed = sqlResult.Computed
 	params.ExpandPlans = hrqlpg.ResolveExpands(params.Expand, obj, s.cache)
+	if err := hrqlpg.ProjectExpands(params.ExpandPlans, params.ExpandSelect); err != nil {
+		return nil, nil, nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if err := s.limits.checkExpands(params.ExpandPlans); err != nil {
+		return nil, nil, nil, err
+	}
+	params.ExpandStrategy = s.expand.Resolve(params)
+	return obj, params, sqlResult, nil
+}
+
+// runHRQLList executes a list-producing HRQL plan.
+func (s *OrgService) runHRQLList(ctx context.Context, plan *hrql.Plan, msg *registryv1.QueryRequest, v viewer, checkCost bool) (*connect.Response[registryv1.QueryResponse], error) {
+	if plan.Union != nil {
+		return s.runUnionList(ctx, plan, msg, checkCost)
+	}
+	obj, params, _, err := s.listParams(ctx, plan, msg)
+	if err != nil {
+		return nil, err
+	}
+	idsOnly := plan.Kind == hrql.PlanIDs
 
 	builder := hrqlpg.NewBuilder(obj)
+	buildPage := builder.BuildList
+	if idsOnly {
+		buildPage = builder.BuildIDs
+	}
+	if checkCost {
+		sqlStr, args, err := buildPage(params)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("build query: %w", err))
+		}
+		if err := s.checkCost(ctx, sqlStr, args); err != nil {
+			return nil, err
+		}
+	}
+
 	g, gctx := errgroup.WithContext(ctx)
 
-	var totalCount int64
+	var (
+		totalCount int64
+		countKnown bool
+	)
 	g.Go(func() error {
-		var err error
-		totalCount, err = s.resolveCount(gctx, builder, params)
-		return err
+		return s.limits.Count.Do(gctx, func() error {
+			totalCount, countKnown = s.counts.count(gctx, s.pool, obj.APIName, s.limits.Lists.For(obj).ExactCountThreshold, builder, params)
+			return nil
+		})
 	})
 
 	var rows []jsonRow
 	g.Go(func() error {
-		sqlStr, args, err := builder.BuildList(params)
-		if err != nil {
-			return err
-		}
-		dbRows, err := s.pool.Query(gctx, sqlStr, args...)
-		if err != nil {
+		return s.limits.List.Do(gctx, func() error {
+			var err error
+			rows, err = s.queryList(gctx, buildPage, params, idsOnly)
+			if err != nil || params.SamplePercent == 0 || len(rows) >= params.Limit {
+				return err
+			}
+			// The table sample held too few matching rows for the filters;
+			// draw from the whole table instead.
+			params.SamplePercent = 0
+			rows, err = s.queryList(gctx, buildPage, params, idsOnly)
 			return err
-		}
-		defer dbRows.Close()
-		rows, err = scanJSONRows(dbRows, params.Order != nil)
-		return err
+		})
 	})
 
 	if err := g.Wait(); err != nil {
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("query failed: %w", err))
+		return nil, queryFailed(err)
 	}
 
-	resp := &registryv1.QueryResponse{TotalCount: totalCount}
+	// An ids page returns no values to redact.
+	resp := &registryv1.QueryResponse{TotalCount: totalCount, CountUnknown: !countKnown, QueryEcho: queryEcho(obj, params, v.pii || idsOnly)}
 
 	if len(rows) > params.Limit {
 		rows = rows[:params.Limit]
-		last := rows[params.Limit-1]
-		encoded := hrqlpg.EncodeCursor(last.CursorID, last.CursorVal)
-		resp.NextCursor = &encoded
+		if !params.Random {
+			last := rows[params.Limit-1]
+			encoded := s.cursors.EncodeSnapshot(obj, last.CursorID, last.CursorVal, params.Order, params.Partition(), "", time.Time{})
+			resp.NextCursor = &encoded
+		}
 	}
 
-	resp.Results = make([]*structpb.Struct, len(rows))
-	for i, r := range rows {
-		st, err := rawJSONToStruct(r.Data)
-		if err != nil {
-			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("marshal result: %w", err))
+	if idsOnly {
+		resp.Ids = make([]string, len(rows))
+		for i, r := range rows {
+			resp.Ids[i] = r.CursorID
 		}
-		resp.Results[i] = st
+		return connect.NewResponse(resp), nil
+	}
+
+	resp.Results, err = listResults(ctx, s.pool, s.cipher, obj, params, rows, v)
+	if err != nil {
+		return nil, err
 	}
 
 	return connect.NewResponse(resp), nil
 }
 
+// queryList runs the page query built by build; idsOnly rows carry no
+// record, only the cursor columns (BuildIDs).
+func (s *OrgService) queryList(ctx context.Context, build func(*hrqlpg.QueryParams) (string, []any, error), params *hrqlpg.QueryParams, idsOnly bool) ([]jsonRow, error) {
+	sqlStr, args, err := build(params)
+	if err != nil {
+		return nil, err
+	}
+	dbRows, err := s.pool.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer dbRows.Close()
+	if idsOnly {
+		return scanIDRows(dbRows, params.Order != nil)
+	}
+	return scanJSONRows(dbRows, params.Order != nil)
+}
+
+// samplePercent picks the table sample for sample(n) on obj from the
+// planner's row estimate; 0 orders the whole table by random().
+func (s *OrgService) samplePercent(ctx context.Context, obj *schema.ObjectDef, n int) (float64, error) {
+	if !hrqlpg.Sampleable(obj) {
+		return 0, nil
+	}
+	var reltuples float64
+	err := s.pool.QueryRow(ctx, `SELECT reltuples FROM pg_class WHERE oid = $1::regclass`, obj.TableName()).Scan(&reltuples)
+	if err != nil {
+		return 0, fmt.Errorf("read pg_class: %w", err)
+	}
+	return hrqlpg.SamplePercent(n, reltuples), nil
+}
+
 // runScalar executes a scalar-producing HRQL plan (aggregation).
-func (s *OrgService) runScalar(ctx context.Context, plan *hrql.Plan) (*connect.Response[registryv1.QueryResponse], error) {
-	obj, err := s.employeesObj()
+func (s *OrgService) runScalar(ctx context.Context, plan *hrql.Plan, checkCost bool) (*connect.Response[registryv1.QueryResponse], error) {
+	obj, err := s.planObj(plan)
 	if err != nil {
 		return nil, err
 	}
@@ -162,29 +666,130 @@ func (s *OrgService) runScalar(ctx context.Context, plan *hrql.Plan) (*connect.R
 		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("translate plan: %w", err))
 	}
 
+	if checkCost {
+		if err := s.checkCost(ctx, sqlResult.AggSQL, sqlResult.AggArgs); err != nil {
+			return nil, err
+		}
+	}
+
 	var rawResult *string
-	if err := s.pool.QueryRow(ctx, sqlResult.AggSQL, sqlResult.AggArgs...).Scan(&rawResult); err != nil {
+	// A projection of a pick that selects no record has no row: its value
+	// is NULL, as an aggregate of no values is.
+	err = s.pool.QueryRow(ctx, sqlResult.AggSQL, sqlResult.AggArgs...).Scan(&rawResult)
+	if err != nil && !(errors.Is(err, pgx.ErrNoRows) && plan.IsProjection()) {
 		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("aggregate query: %w", err))
 	}
 
-	var scalar float64
-	if rawResult != nil {
-		scalar, err = strconv.ParseFloat(*rawResult, 64)
-		if err != nil {
-			n, err2 := strconv.ParseInt(*rawResult, 10, 64)
-			if err2 != nil {
-				return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("parse aggregate result %q: %w", *rawResult, err))
+	if rawResult == nil {
+		return connect.NewResponse(&registryv1.QueryResponse{ScalarNull: true, Result: nullResult()}), nil
+	}
+	result, err := scalarResult(plan, obj, *rawResult)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	resp := &registryv1.QueryResponse{Result: result}
+	switch v := result.Value.(type) {
+	case *registryv1.ResultValue_IntValue:
+		resp.Scalar = new(float64(v.IntValue))
+	case *registryv1.ResultValue_DoubleValue:
+		resp.Scalar = &v.DoubleValue
+	}
+	return connect.NewResponse(resp), nil
+}
+
+// scalarResult types the text of an aggregate: count is an integer, min or
+// max of a date or text field stays text, and any other aggregate or
+// arithmetic is a double. A projection (first | .field) is typed by its
+// field: a number is a double, a boolean a bool, anything else text.
+func scalarResult(plan *hrql.Plan, obj *schema.ObjectDef, raw string) (*registryv1.ResultValue, error) {
+	if fd := obj.FieldsByAPIName[plan.AggField]; plan.IsProjection() && fd != nil && !fd.IsNumeric() {
+		if fd.Type == schema.FieldBoolean {
+			b, err := strconv.ParseBool(raw)
+			if err != nil {
+				return nil, fmt.Errorf("parse boolean %q: %w", raw, err)
 			}
-			scalar = float64(n)
+			return &registryv1.ResultValue{Value: &registryv1.ResultValue_BoolValue{BoolValue: b}}, nil
 		}
+		return &registryv1.ResultValue{Value: &registryv1.ResultValue_StringValue{StringValue: raw}}, nil
+	}
+	if plan.AggFunc == "count" && plan.ScalarExpr == nil {
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse count %q: %w", raw, err)
+		}
+		return &registryv1.ResultValue{Value: &registryv1.ResultValue_IntValue{IntValue: n}}, nil
+	}
+	f, err := strconv.ParseFloat(raw, 64)
+	switch {
+	case err == nil:
+		return &registryv1.ResultValue{Value: &registryv1.ResultValue_DoubleValue{DoubleValue: f}}, nil
+	case plan.ScalarExpr == nil && (plan.AggFunc == "min" || plan.AggFunc == "max"):
+		return &registryv1.ResultValue{Value: &registryv1.ResultValue_StringValue{StringValue: raw}}, nil
 	}
+	return nil, fmt.Errorf("parse aggregate result %q: %w", raw, err)
+}
 
-	return connect.NewResponse(&registryv1.QueryResponse{Scalar: &scalar}), nil
+// nullResult is the ResultValue of a scalar or boolean that is NULL.
+func nullResult() *registryv1.ResultValue {
+	return &registryv1.ResultValue{Value: &registryv1.ResultValue_NullValue{}}
+}
+
+// runBuckets executes a bucket(...) plan, reporting every range its bounds
+// delimit, empty ones included.
+func (s *OrgService) runBuckets(ctx context.Context, plan *hrql.Plan, checkCost bool) (*connect.Response[registryv1.QueryResponse], error) {
+	obj, err := s.planObj(plan)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlResult, err := hrqlpg.Translate(plan, obj, s.cache)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("translate plan: %w", err))
+	}
+
+	if checkCost {
+		if err := s.checkCost(ctx, sqlResult.AggSQL, sqlResult.AggArgs); err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := s.pool.Query(ctx, sqlResult.AggSQL, sqlResult.AggArgs...)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("bucket query: %w", err))
+	}
+	counts := make(map[int]int64)
+	var idx int
+	var n int64
+	if _, err := pgx.ForEachRow(rows, []any{&idx, &n}, func() error {
+		counts[idx] = n
+		return nil
+	}); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("bucket query: %w", err))
+	}
+
+	// The compiler checked that every bound parses.
+	bounds := make([]float64, len(plan.Bounds))
+	for i, b := range plan.Bounds {
+		bounds[i], _ = strconv.ParseFloat(b, 64)
+	}
+	buckets := make([]*registryv1.QueryBucket, len(bounds)+1)
+	for i := range buckets {
+		b := &registryv1.QueryBucket{Count: counts[i]}
+		if i > 0 {
+			b.Lower = &bounds[i-1]
+		}
+		if i < len(bounds) {
+			b.Upper = &bounds[i]
+		}
+		buckets[i] = b
+	}
+	return connect.NewResponse(&registryv1.QueryResponse{Buckets: buckets}), nil
 }
 
 // runBoolean executes a boolean-producing HRQL plan (e.g. reports_to) via SQL.
 func (s *OrgService) runBoolean(ctx context.Context, plan *hrql.Plan) (*connect.Response[registryv1.QueryResponse], error) {
-	obj, err := s.employeesObj()
+	obj, err := s.planObj(plan)
 	if err != nil {
 		return nil, err
 	}
@@ -199,54 +804,43 @@ func (s *OrgService) runBoolean(ctx context.Context, plan *hrql.Plan) (*connect.
 		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("boolean query: %w", err))
 	}
 
-	return connect.NewResponse(&registryv1.QueryResponse{ReportsTo: result}), nil
+	resp := &registryv1.QueryResponse{ReportsTo: result, Result: nullResult()}
+	if result != nil {
+		resp.Result = &registryv1.ResultValue{Value: &registryv1.ResultValue_BoolValue{BoolValue: *result}}
+	}
+	return connect.NewResponse(resp), nil
 }
 
 // -- helpers --
 
-func listInputFromMsg(msg *registryv1.QueryRequest) hrqlpg.ParamsInput {
+func listInputFromMsg(msg *registryv1.QueryRequest, cursors *hrqlpg.CursorSigner) hrqlpg.ParamsInput {
 	return hrqlpg.ParamsInput{
-		Select: msg.Select,
-		Expand: msg.Expand,
-		Order:  msg.Order,
-		Limit:  msg.Limit,
-		Cursor: msg.Cursor,
+		Select:  msg.Select,
+		Expand:  msg.Expand,
+		Order:   msg.Order,
+		Limit:   msg.Limit,
+		Cursor:  msg.Cursor,
+		Cursors: cursors,
+
+		IncludeTerminated: msg.IncludeTerminated,
 	}
 }
 
-func (s *OrgService) employeesObj() (*schema.ObjectDef, error) {
-	obj := s.cache.Get("employees")
+// planObj returns the definition of the object the plan reads (employees
+// unless the query starts from another object), switched to its point-in-time
+// snapshot when the plan carries as_of.
+func (s *OrgService) planObj(plan *hrql.Plan) (*schema.ObjectDef, error) {
+	name := cmp.Or(plan.Object, "employees")
+	obj := s.cache.Get(name)
 	if obj == nil {
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("employees object not in cache"))
-	}
-	return obj, nil
-}
-
-func (s *OrgService) resolveCount(ctx context.Context, builder hrqlpg.Builder, params *hrqlpg.QueryParams) (int64, error) {
-	estSQL, estArgs, err := builder.BuildEstimate(params)
-	if err != nil {
-		return 0, err
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("%s object not in cache", name))
 	}
-
-	var planJSON string
-	err = s.pool.QueryRow(ctx, "EXPLAIN (FORMAT JSON) "+estSQL, estArgs...).Scan(&planJSON)
-	if err != nil {
-		return 0, fmt.Errorf("explain estimate: %w", err)
-	}
-
-	estimated := parsePlanRows(planJSON)
-
-	if estimated <= exactCountThreshold {
-		countSQL, countArgs, err := builder.BuildCount(params)
+	if plan.AsOf != nil {
+		hist, err := hrqlpg.AsOf(obj, *plan.AsOf)
 		if err != nil {
-			return estimated, nil
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
 		}
-		var count int64
-		if err := s.pool.QueryRow(ctx, countSQL, countArgs...).Scan(&count); err != nil {
-			return estimated, nil
-		}
-		return count, nil
+		return hist, nil
 	}
-
-	return estimated, nil
+	return obj, nil
 }
@@ -1,34 +1,88 @@
 package service
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"connectrpc.com/connect"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/protobuf/types/known/structpb"
 
 	registryv1 "github.com/atlekbai/schema_registry/gen/registry/v1"
 	registryv1connect "github.com/atlekbai/schema_registry/gen/registry/v1/registryv1connect"
+	"github.com/atlekbai/schema_registry/internal/db"
+	"github.com/atlekbai/schema_registry/internal/fieldcrypt"
+	"github.com/atlekbai/schema_registry/internal/hrql"
 	hrqlpg "github.com/atlekbai/schema_registry/internal/hrql/pg"
+	"github.com/atlekbai/schema_registry/internal/ltreeutil"
 	"github.com/atlekbai/schema_registry/internal/schema"
+	"github.com/atlekbai/schema_registry/internal/webhook"
 )
 
-// exactCountThreshold is the planner estimate below which we run an exact count.
-const exactCountThreshold = 50_000
+// QueryLimits bound the queries a List or an HRQL list fans out to: the page
+// and the total count each wait for a slot in their own limiter, so counts
+// cannot starve pages. Nil limiters leave queries unbounded. ExpandColumns
+// caps the columns expands may add to each record (see
+// hrqlpg.CheckExpandBudget); 0 leaves them unbounded.
+type QueryLimits struct {
+	List          *db.Limiter
+	Count         *db.Limiter
+	ExpandColumns int
+	// MaxCost and MaxRows reject HRQL list and scalar queries whose planner
+	// estimate exceeds them (0 disables each).
+	MaxCost float64
+	MaxRows int64
+	// Lists are the server's page sizes and exact count threshold, which
+	// objects may override (schema.ListLimits.For).
+	Lists schema.ListLimits
+	// StreamRows caps the records of one ExecuteStream (0 means
+	// DefaultStreamRows).
+	StreamRows int64
+}
+
+// checkExpands rejects expand plans over the column budget.
+func (l QueryLimits) checkExpands(plans []hrqlpg.ExpandPlan) error {
+	if err := hrqlpg.CheckExpandBudget(plans, l.ExpandColumns); err != nil {
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	return nil
+}
+
+// queryFailed maps an error from a List's fan-out to a connect error.
+func queryFailed(err error) error {
+	if errors.Is(err, db.ErrSaturated) {
+		return connect.NewError(connect.CodeResourceExhausted, fmt.Errorf("server busy: %w; retry later", err))
+	}
+	return connect.NewError(connect.CodeInternal, fmt.Errorf("query failed: %w", err))
+}
 
 type RegistryService struct {
-	pool  *pgxpool.Pool
-	cache *schema.Cache
+	pool      *pgxpool.Pool
+	cache     *schema.Cache
+	cipher    *fieldcrypt.Cipher
+	expand    hrqlpg.ExpandStrategy
+	snapshots *db.Snapshots
+	validator *webhook.Validator
+	limits    QueryLimits
+	data      schema.DataLimits
+	lookups   *lookupCache
+	counts    *countBreaker
+	cursors   *hrqlpg.CursorSigner
 }
 
-func NewRegistryService(pool *pgxpool.Pool, cache *schema.Cache) *RegistryService {
-	return &RegistryService{pool: pool, cache: cache}
+func NewRegistryService(pool *pgxpool.Pool, cache *schema.Cache, cipher *fieldcrypt.Cipher, expand hrqlpg.ExpandStrategy, snapshots *db.Snapshots, validator *webhook.Validator, limits QueryLimits, data schema.DataLimits, cursors *hrqlpg.CursorSigner) *RegistryService {
+	return &RegistryService{pool: pool, cache: cache, cipher: cipher, expand: expand, snapshots: snapshots, validator: validator, limits: limits, data: data, lookups: newLookupCache(), counts: newCountBreaker(), cursors: cursors}
 }
 
 func (s *RegistryService) RegisterHandler(interceptors ...connect.Interceptor) (string, http.Handler) {
@@ -49,28 +103,77 @@ func (s *RegistryService) List(ctx context.Context, req *connect.Request[registr
 		Limit:   msg.Limit,
 		Cursor:  msg.Cursor,
 		Filters: msg.Filters,
+		Cursors: s.cursors,
+		Cache:   s.cache,
+		Limits:  s.limits.Lists,
+
+		IncludeTerminated: msg.IncludeTerminated,
 	})
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		return nil, paramsError(err)
 	}
 
 	params.ExpandPlans = hrqlpg.ResolveExpands(params.Expand, obj, s.cache)
+	if err := hrqlpg.ProjectExpands(params.ExpandPlans, params.ExpandSelect); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if err := s.limits.checkExpands(params.ExpandPlans); err != nil {
+		return nil, err
+	}
+	params.ExpandStrategy = s.expand.Resolve(params)
+	referenced := msg.ExpandMode == "referenced"
+	if referenced {
+		if msg.Raw {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("raw lists have no envelope for included records; use expand_mode inline"))
+		}
+		// Each referenced record is loaded once, not per row.
+		params.ExpandStrategy = hrqlpg.ExpandBatch
+	}
 
+	// Employees who have left stay out of employee lists unless asked for.
+	if obj.APIName == "employees" && !msg.IncludeTerminated && obj.FieldsByAPIName[hrql.EndDateField] != nil {
+		params.Conditions = append(params.Conditions, hrql.Employed{})
+	}
 	params.SQLConditions, err = hrqlpg.TranslateConditions(params.Conditions, obj, s.cache)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInvalidArgument, err)
 	}
 
+	if msg.Raw && msg.Snapshot {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("raw lists have no cursor to carry a snapshot; list without raw"))
+	}
+	snapshot, expires, err := s.listSnapshot(ctx, msg.Snapshot, params.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
 	builder := hrqlpg.NewBuilder(obj)
 
 	g, gctx := errgroup.WithContext(ctx)
 
-	var totalCount int64
-	g.Go(func() error {
-		var err error
-		totalCount, err = s.resolveCount(gctx, builder, obj, params)
-		return err
-	})
+	// Raw lists skip the count, the slower of the two queries. A count that
+	// fails leaves the page without a total rather than failing it.
+	var (
+		totalCount int64
+		countKnown = true
+	)
+	if !msg.Raw {
+		g.Go(func() error {
+			return s.limits.Count.Do(gctx, func() error {
+				err := s.read(gctx, snapshot, func(q querier) error {
+					totalCount, countKnown = s.counts.count(gctx, q, obj.APIName, s.limits.Lists.For(obj).ExactCountThreshold, builder, params)
+					return nil
+				})
+				if err != nil && !errors.Is(err, db.ErrSnapshotExpired) {
+					// A failed count aborts its snapshot transaction, which
+					// then fails to commit; the list reads in its own.
+					totalCount, countKnown = -1, false
+					return nil
+				}
+				return err
+			})
+		})
+	}
 
 	var rows []jsonRow
 	g.Go(func() error {
@@ -79,41 +182,95 @@ func (s *RegistryService) List(ctx context.Context, req *connect.Request[registr
 			return err
 		}
 
-		dbRows, err := s.pool.Query(gctx, sqlStr, args...)
-		if err != nil {
-			return err
-		}
-		defer dbRows.Close()
-		rows, err = scanJSONRows(dbRows, params.Order != nil)
-		return err
+		return s.limits.List.Do(gctx, func() error {
+			return s.read(gctx, snapshot, func(q querier) error {
+				dbRows, err := q.Query(gctx, sqlStr, args...)
+				if err != nil {
+					return err
+				}
+				defer dbRows.Close()
+				rows, err = scanJSONRows(dbRows, params.Order != nil)
+				return err
+			})
+		})
 	})
 
 	if err := g.Wait(); err != nil {
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("query failed: %w", err))
+		if errors.Is(err, db.ErrSnapshotExpired) {
+			return nil, snapshotExpiredError()
+		}
+		return nil, queryFailed(err)
 	}
 
 	resp := &registryv1.ListResponse{
-		TotalCount: totalCount,
+		TotalCount:   totalCount,
+		CountUnknown: !countKnown,
+		QueryEcho:    queryEcho(obj, params, canReadPII(req.Header())),
 	}
 
 	// Pagination: if we got limit+1 rows, there's a next page.
 	if len(rows) > params.Limit {
 		rows = rows[:params.Limit]
-		last := rows[params.Limit-1]
-		encoded := hrqlpg.EncodeCursor(last.CursorID, last.CursorVal)
-		resp.NextCursor = &encoded
+		if !msg.Raw {
+			last := rows[params.Limit-1]
+			encoded := s.cursors.EncodeSnapshot(obj, last.CursorID, last.CursorVal, params.Order, params.Partition(), snapshot, expires)
+			resp.NextCursor = &encoded
+		}
 	}
 
-	resp.Results = make([]*structpb.Struct, len(rows))
-	for i, r := range rows {
-		st, err := rawJSONToStruct(r.Data)
-		if err != nil {
-			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("marshal result: %w", err))
+	resp.Results, err = listResults(ctx, s.pool, s.cipher, obj, params, rows, viewerOf(req.Header()))
+	if err != nil {
+		return nil, err
+	}
+	if referenced {
+		resp.Included = referenceExpands(params.ExpandPlans, resp.Results)
+	}
+
+	out := connect.NewResponse(resp)
+	resp.Warning = setDeprecation(out.Header(), obj, msg.ObjectName)
+	// Snapshot pages pin a transaction of their own; they are not reused.
+	if snapshot == "" && obj.CacheMaxAge > 0 {
+		setCacheHeaders(out.Header(), obj, viewerOf(req.Header()), time.Time{})
+		if etag := listETag(resp); etag != "" {
+			out.Header().Set("ETag", etag)
 		}
-		resp.Results[i] = st
 	}
+	return out, nil
+}
 
-	return connect.NewResponse(resp), nil
+// listSnapshot returns the snapshot a List page reads: the one pinned in the
+// cursor, a newly exported one for the first page of a snapshot List, or ""
+// to read the latest data.
+func (s *RegistryService) listSnapshot(ctx context.Context, requested bool, cursor *hrqlpg.Cursor) (string, time.Time, error) {
+	if cursor != nil && cursor.Snapshot != "" {
+		if cursor.SnapshotExpired(time.Now()) {
+			return "", time.Time{}, snapshotExpiredError()
+		}
+		return cursor.Snapshot, time.Unix(cursor.SnapshotExpires, 0), nil
+	}
+	if !requested {
+		return "", time.Time{}, nil
+	}
+	id, expires, err := s.snapshots.Export(ctx)
+	if errors.Is(err, db.ErrSnapshotLimit) {
+		return "", time.Time{}, connect.NewError(connect.CodeResourceExhausted, fmt.Errorf("snapshot list unavailable: %w; retry later or list without snapshot", err))
+	}
+	if err != nil {
+		return "", time.Time{}, connect.NewError(connect.CodeInternal, err)
+	}
+	return id, expires, nil
+}
+
+// read runs fn against the pool, or inside snapshot when one is given.
+func (s *RegistryService) read(ctx context.Context, snapshot string, fn func(querier) error) error {
+	if snapshot == "" {
+		return fn(s.pool)
+	}
+	return s.snapshots.Run(ctx, snapshot, func(tx pgx.Tx) error { return fn(tx) })
+}
+
+func snapshotExpiredError() error {
+	return paramsError(&hrqlpg.CursorInvalidatedError{Reason: "the snapshot pinned by the cursor has expired"})
 }
 
 func (s *RegistryService) Get(ctx context.Context, req *connect.Request[registryv1.GetRequest]) (*connect.Response[registryv1.GetResponse], error) {
@@ -131,13 +288,454 @@ func (s *RegistryService) Get(ctx context.Context, req *connect.Request[registry
 	params, err := hrqlpg.ParseParams(obj, hrqlpg.ParamsInput{
 		Select: msg.Select,
 		Expand: msg.Expand,
+		Cache:  s.cache,
+		Limits: s.limits.Lists,
 	})
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInvalidArgument, err)
 	}
 
 	params.ExpandPlans = hrqlpg.ResolveExpands(params.Expand, obj, s.cache)
+	if err := hrqlpg.ProjectExpands(params.ExpandPlans, params.ExpandSelect); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if err := s.limits.checkExpands(params.ExpandPlans); err != nil {
+		return nil, err
+	}
+
+	record, err := s.fetchRecord(ctx, s.pool, obj, hrqlpg.NewBuilder(obj), id, params, viewerOf(req.Header()))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := connect.NewResponse(&registryv1.GetResponse{Record: record})
+	setETag(resp.Header(), record)
+	setCacheHeaders(resp.Header(), obj, viewerOf(req.Header()), recordUpdatedAt(record))
+	resp.Msg.Warning = setDeprecation(resp.Header(), obj, msg.ObjectName)
+	return resp, nil
+}
+
+// defaultTypeaheadLimit applies to Typeahead requests without a limit.
+const defaultTypeaheadLimit = 10
+
+// Typeahead returns the records whose display name starts with q, for
+// lookup pickers.
+func (s *RegistryService) Typeahead(ctx context.Context, req *connect.Request[registryv1.TypeaheadRequest]) (*connect.Response[registryv1.TypeaheadResponse], error) {
+	msg := req.Msg
+	obj := s.cache.Get(msg.ObjectName)
+	if obj == nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("no object registered with api_name %q", msg.ObjectName))
+	}
+	if obj.DisplayTemplate == "" {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("object %q has no display_template; set one with UpdateObject", obj.APIName))
+	}
+
+	limit := cmp.Or(int(msg.Limit), defaultTypeaheadLimit)
+	sqlStr, args, err := hrqlpg.BuildTypeahead(obj, strings.TrimSpace(msg.Q), limit)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	rows, err := s.pool.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("typeahead: %w", err))
+	}
+	resp := &registryv1.TypeaheadResponse{}
+	var id, display string
+	if _, err := pgx.ForEachRow(rows, []any{&id, &display}, func() error {
+		resp.Matches = append(resp.Matches, &registryv1.TypeaheadMatch{Id: id, Display: display})
+		return nil
+	}); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("typeahead: %w", err))
+	}
+	return connect.NewResponse(resp), nil
+}
+
+// ── Writes ──────────────────────────────────────────────────────────
+
+func (s *RegistryService) Create(ctx context.Context, req *connect.Request[registryv1.CreateRequest]) (*connect.Response[registryv1.CreateResponse], error) {
+	msg := req.Msg
+	obj := s.cache.Get(msg.ObjectName)
+	if obj == nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("no object registered with api_name %q", msg.ObjectName))
+	}
+
+	data := msg.Data.AsMap()
+	if err := encryptValues(s.cipher, obj, data); err != nil {
+		return nil, err
+	}
+
 	builder := hrqlpg.NewBuilder(obj)
+	sqlStr, args, err := builder.BuildInsert(data)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	tx, err := s.beginWrite(ctx, msg.DryRun)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := checkHierarchy(ctx, tx, obj, uuid.Nil, data); err != nil {
+		return nil, err
+	}
+
+	var rawID string
+	if err := tx.QueryRow(ctx, sqlStr, args...).Scan(&rawID); err != nil {
+		return nil, writeError("create record", err)
+	}
+	if err := s.syncLabels(ctx, tx, obj, uuid.MustParse(rawID), data); err != nil {
+		return nil, err
+	}
+	if err := s.checkDocumentSize(ctx, tx, obj, builder, uuid.MustParse(rawID)); err != nil {
+		return nil, err
+	}
+
+	record, err := s.fetchRecord(ctx, tx, obj, builder, uuid.MustParse(rawID), nil, viewerOf(req.Header()))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateRecord(ctx, tx, obj, builder, webhook.OpCreate, uuid.MustParse(rawID), record, viewerOf(req.Header())); err != nil {
+		return nil, err
+	}
+
+	if err := finishWrite(ctx, tx, msg.DryRun); err != nil {
+		return nil, err
+	}
+
+	resp := connect.NewResponse(&registryv1.CreateResponse{Record: record, DryRun: msg.DryRun})
+	if !msg.DryRun {
+		setETag(resp.Header(), record)
+	}
+	return resp, nil
+}
+
+func (s *RegistryService) Update(ctx context.Context, req *connect.Request[registryv1.UpdateRequest]) (*connect.Response[registryv1.UpdateResponse], error) {
+	msg := req.Msg
+	obj := s.cache.Get(msg.ObjectName)
+	if obj == nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("no object registered with api_name %q", msg.ObjectName))
+	}
+
+	id, err := uuid.Parse(msg.Id)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid ID format: %w", err))
+	}
+
+	expected, err := expectedVersion(req.Header(), msg.ExpectedVersion)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	data := msg.Data.AsMap()
+	if err := encryptValues(s.cipher, obj, data); err != nil {
+		return nil, err
+	}
+
+	builder := hrqlpg.NewBuilder(obj)
+	sqlStr, args, err := builder.BuildUpdate(id, data, expected)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	tx, err := s.beginWrite(ctx, msg.DryRun)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := checkHierarchy(ctx, tx, obj, id, data); err != nil {
+		return nil, err
+	}
+
+	var version int64
+	err = tx.QueryRow(ctx, sqlStr, args...).Scan(&version)
+	if err == pgx.ErrNoRows {
+		return nil, s.versionConflict(ctx, tx, builder, id, expected)
+	}
+	if err != nil {
+		return nil, writeError("update record", err)
+	}
+	if err := s.syncLabels(ctx, tx, obj, id, data); err != nil {
+		return nil, err
+	}
+	if err := s.checkDocumentSize(ctx, tx, obj, builder, id); err != nil {
+		return nil, err
+	}
+
+	record, err := s.fetchRecord(ctx, tx, obj, builder, id, nil, viewerOf(req.Header()))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateRecord(ctx, tx, obj, builder, webhook.OpUpdate, id, record, viewerOf(req.Header())); err != nil {
+		return nil, err
+	}
+
+	if err := finishWrite(ctx, tx, msg.DryRun); err != nil {
+		return nil, err
+	}
+
+	resp := connect.NewResponse(&registryv1.UpdateResponse{Record: record, DryRun: msg.DryRun})
+	if !msg.DryRun {
+		setETag(resp.Header(), record)
+	}
+	return resp, nil
+}
+
+// Upsert inserts a record or merges data into the one with the same external ID,
+// in a single INSERT ... ON CONFLICT statement.
+func (s *RegistryService) Upsert(ctx context.Context, req *connect.Request[registryv1.UpsertRequest]) (*connect.Response[registryv1.UpsertResponse], error) {
+	msg := req.Msg
+	obj := s.cache.Get(msg.ObjectName)
+	if obj == nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("no object registered with api_name %q", msg.ObjectName))
+	}
+
+	externalID := msg.ExternalIdField
+	if externalID == "" {
+		ids := obj.ExternalIDs()
+		if len(ids) != 1 {
+			return nil, connect.NewError(connect.CodeInvalidArgument,
+				fmt.Errorf("object %q has %d external ID fields; set external_id_field", obj.APIName, len(ids)))
+		}
+		externalID = ids[0].APIName
+	}
+
+	data := msg.Data.AsMap()
+	if err := encryptValues(s.cipher, obj, data); err != nil {
+		return nil, err
+	}
+
+	builder := hrqlpg.NewBuilder(obj)
+	sqlStr, args, err := builder.BuildUpsert(data, externalID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	tx, err := s.beginWrite(ctx, msg.DryRun)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// The target is unknown until the write, so this checks it as a new
+	// record; the path triggers still reject cycles through an existing one.
+	if err := checkHierarchy(ctx, tx, obj, uuid.Nil, data); err != nil {
+		return nil, err
+	}
+
+	var rawID string
+	var created bool
+	if err := tx.QueryRow(ctx, sqlStr, args...).Scan(&rawID, &created); err != nil {
+		return nil, writeError("upsert record", err)
+	}
+	if err := s.syncLabels(ctx, tx, obj, uuid.MustParse(rawID), data); err != nil {
+		return nil, err
+	}
+	if err := s.checkDocumentSize(ctx, tx, obj, builder, uuid.MustParse(rawID)); err != nil {
+		return nil, err
+	}
+
+	record, err := s.fetchRecord(ctx, tx, obj, builder, uuid.MustParse(rawID), nil, viewerOf(req.Header()))
+	if err != nil {
+		return nil, err
+	}
+	op := webhook.OpUpdate
+	if created {
+		op = webhook.OpCreate
+	}
+	if err := s.validateRecord(ctx, tx, obj, builder, op, uuid.MustParse(rawID), record, viewerOf(req.Header())); err != nil {
+		return nil, err
+	}
+
+	if err := finishWrite(ctx, tx, msg.DryRun); err != nil {
+		return nil, err
+	}
+
+	resp := connect.NewResponse(&registryv1.UpsertResponse{Record: record, Created: created, DryRun: msg.DryRun})
+	if !msg.DryRun {
+		setETag(resp.Header(), record)
+	}
+	return resp, nil
+}
+
+func (s *RegistryService) Delete(ctx context.Context, req *connect.Request[registryv1.DeleteRequest]) (*connect.Response[registryv1.DeleteResponse], error) {
+	msg := req.Msg
+	obj := s.cache.Get(msg.ObjectName)
+	if obj == nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("no object registered with api_name %q", msg.ObjectName))
+	}
+
+	id, err := uuid.Parse(msg.Id)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid ID format: %w", err))
+	}
+
+	expected, err := expectedVersion(req.Header(), msg.ExpectedVersion)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	builder := hrqlpg.NewBuilder(obj)
+	sqlStr, args, err := builder.BuildDelete(id, expected)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("build query: %w", err))
+	}
+
+	tx, err := s.beginWrite(ctx, msg.DryRun)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	resp := &registryv1.DeleteResponse{DryRun: msg.DryRun}
+	if msg.DryRun {
+		// Report the record as it stands; a missing record surfaces as NotFound here.
+		resp.Record, err = s.fetchRecord(ctx, tx, obj, builder, id, nil, viewerOf(req.Header()))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tag, err := tx.Exec(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, writeError("delete record", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, s.versionConflict(ctx, tx, builder, id, expected)
+	}
+	if err := s.syncLabels(ctx, tx, obj, id, nil); err != nil {
+		return nil, err
+	}
+
+	if err := finishWrite(ctx, tx, msg.DryRun); err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(resp), nil
+}
+
+// checkHierarchy rejects payloads that would create a cycle or exceed
+// ltreeutil.MaxDepth in a hierarchy with a path column.
+func checkHierarchy(ctx context.Context, q querier, obj *schema.ObjectDef, id uuid.UUID, data map[string]any) error {
+	err := ltreeutil.CheckWrite(ctx, q, obj, id, data)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ltreeutil.ErrCycle), errors.Is(err, ltreeutil.ErrTooDeep):
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	default:
+		return connect.NewError(connect.CodeInternal, err)
+	}
+}
+
+// validateRecord runs obj's validation webhook on the record a write is about
+// to commit, while the write transaction stays open. The webhook gets the
+// record as an unprivileged reader sees it, unmasked, so it is refetched when
+// the caller's copy has PII revealed or masks applied.
+func (s *RegistryService) validateRecord(ctx context.Context, q querier, obj *schema.ObjectDef, builder hrqlpg.Builder, op webhook.Operation, id uuid.UUID, record *structpb.Struct, v viewer) error {
+	if obj.ValidationWebhook == nil {
+		return nil
+	}
+	if v.pii || v.masks(obj) {
+		var err error
+		if record, err = s.fetchRecord(ctx, q, obj, builder, id, nil, viewer{}); err != nil {
+			return err
+		}
+	}
+
+	err := s.validator.Validate(ctx, obj, op, id.String(), record.AsMap())
+	if rejected, ok := errors.AsType[*webhook.RejectedError](err); ok {
+		cerr := connect.NewError(connect.CodeInvalidArgument, err)
+		detail := &registryv1.ValidationFailed{}
+		for _, v := range rejected.Violations {
+			detail.Violations = append(detail.Violations, &registryv1.FieldViolation{
+				Field: v.Field, Message: v.Message, Source: "webhook",
+			})
+		}
+		if d, derr := connect.NewErrorDetail(detail); derr == nil {
+			cerr.AddDetail(d)
+		}
+		return cerr
+	}
+	if errors.Is(err, webhook.ErrUnavailable) {
+		return connect.NewError(connect.CodeUnavailable, err)
+	}
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+// querier is satisfied by both *pgxpool.Pool and pgx.Tx, so reads can run
+// inside a write transaction and see its uncommitted changes.
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// beginWrite starts the transaction for a write RPC. Dry runs make deferred
+// constraints immediate so every violation surfaces before the rollback.
+func (s *RegistryService) beginWrite(ctx context.Context, dryRun bool) (pgx.Tx, error) {
+	tx, err := db.Begin(ctx, s.pool)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("begin transaction: %w", err))
+	}
+	if dryRun {
+		if _, err := tx.Exec(ctx, "SET CONSTRAINTS ALL IMMEDIATE"); err != nil {
+			tx.Rollback(ctx)
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("set constraints: %w", err))
+		}
+	}
+	return tx, nil
+}
+
+// finishWrite commits a write transaction. Dry runs are left for the caller's
+// deferred Rollback, discarding every change (including trigger side effects).
+func finishWrite(ctx context.Context, tx pgx.Tx, dryRun bool) error {
+	if dryRun {
+		return nil
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return writeError("commit", err)
+	}
+	return nil
+}
+
+// syncLabels maintains denormalized lookup labels within a write transaction:
+// it refreshes the labels stored on record id and copies the record's new
+// label values to the records whose LOOKUPs point at it. changed is the write
+// payload, limiting propagation to the fields it touched; nil (delete)
+// propagates every label, clearing it on records left pointing at id.
+func (s *RegistryService) syncLabels(ctx context.Context, tx pgx.Tx, obj *schema.ObjectDef, id uuid.UUID, changed map[string]any) error {
+	stmts, err := hrqlpg.BuildPropagateLabels(obj, s.cache, id, changed)
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("build label sync: %w", err))
+	}
+	if changed != nil {
+		refresh, ok, err := hrqlpg.BuildRefreshLabels(obj, s.cache, id, changed)
+		if err != nil {
+			return connect.NewError(connect.CodeInternal, fmt.Errorf("build label sync: %w", err))
+		}
+		if ok {
+			stmts = append([]hrqlpg.LabelStatement{refresh}, stmts...)
+		}
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(ctx, stmt.SQL, stmt.Args...); err != nil {
+			return writeError("sync lookup labels", err)
+		}
+	}
+	return nil
+}
+
+// fetchRecord reads a single record as a Struct. A nil params selects all fields
+// without expands, which is what write RPCs return. It is presented to v by
+// revealRecord.
+func (s *RegistryService) fetchRecord(ctx context.Context, q querier, obj *schema.ObjectDef, builder hrqlpg.Builder, id uuid.UUID, params *hrqlpg.QueryParams, v viewer) (*structpb.Struct, error) {
+	if params == nil {
+		params = &hrqlpg.QueryParams{}
+	}
 
 	sqlStr, args, err := builder.BuildGetByID(id, params)
 	if err != nil {
@@ -145,7 +743,7 @@ func (s *RegistryService) Get(ctx context.Context, req *connect.Request[registry
 	}
 
 	var data json.RawMessage
-	err = s.pool.QueryRow(ctx, sqlStr, args...).Scan(&data)
+	err = q.QueryRow(ctx, sqlStr, args...).Scan(&data)
 	if err == pgx.ErrNoRows {
 		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("record not found"))
 	}
@@ -157,33 +755,164 @@ func (s *RegistryService) Get(ctx context.Context, req *connect.Request[registry
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("marshal result: %w", err))
 	}
-
-	return connect.NewResponse(&registryv1.GetResponse{Record: record}), nil
+	if err := revealRecord(s.cipher, obj, params.ExpandPlans, record, v); err != nil {
+		return nil, err
+	}
+	return record, nil
 }
 
-// resolveCount uses the EXPLAIN trick for cheap estimation on large tables,
-// falling back to exact count only when the planner estimate is small.
-func (s *RegistryService) resolveCount(ctx context.Context, builder hrqlpg.Builder, obj *schema.ObjectDef, params *hrqlpg.QueryParams) (int64, error) {
-	estSQL, estArgs, err := builder.BuildEstimate(params)
+// versionConflict is called when a guarded write matched no rows. It tells a
+// missing record apart from a stale expected_version and reports the current
+// version as a VersionConflict error detail.
+func (s *RegistryService) versionConflict(ctx context.Context, q querier, builder hrqlpg.Builder, id uuid.UUID, expected int64) error {
+	sqlStr, args, err := builder.BuildVersion(id)
 	if err != nil {
-		return 0, err
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("build query: %w", err))
 	}
 
-	var planJSON string
-	err = s.pool.QueryRow(ctx, "EXPLAIN (FORMAT JSON) "+estSQL, estArgs...).Scan(&planJSON)
+	var current int64
+	err = q.QueryRow(ctx, sqlStr, args...).Scan(&current)
+	if err == pgx.ErrNoRows {
+		return connect.NewError(connect.CodeNotFound, fmt.Errorf("record not found"))
+	}
 	if err != nil {
-		return 0, fmt.Errorf("explain estimate: %w", err)
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("query version: %w", err))
+	}
+
+	cerr := connect.NewError(connect.CodeFailedPrecondition,
+		fmt.Errorf("version conflict: expected version %d, current version %d", expected, current))
+	if detail, err := connect.NewErrorDetail(&registryv1.VersionConflict{
+		Id:              id.String(),
+		ExpectedVersion: expected,
+		CurrentVersion:  current,
+	}); err == nil {
+		cerr.AddDetail(detail)
+	}
+	return cerr
+}
+
+// expectedVersion returns the version a write is conditioned on. The request
+// field takes precedence; otherwise an If-Match header carrying an ETag from a
+// previous read is used. Zero means the write is unconditional.
+func expectedVersion(h http.Header, fromMsg int64) (int64, error) {
+	if fromMsg > 0 {
+		return fromMsg, nil
+	}
+	tag := strings.TrimSpace(h.Get("If-Match"))
+	if tag == "" || tag == "*" {
+		return 0, nil
 	}
+	tag = strings.Trim(strings.TrimPrefix(tag, "W/"), `"`)
+	v, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil || v <= 0 {
+		return 0, fmt.Errorf("invalid If-Match header %q: expected a record version", h.Get("If-Match"))
+	}
+	return v, nil
+}
+
+// setETag exposes the record version as an ETag so REST clients can round-trip
+// it through If-Match.
+func setETag(h http.Header, record *structpb.Struct) {
+	v, ok := record.GetFields()["version"]
+	if !ok {
+		return
+	}
+	h.Set("ETag", strconv.Quote(strconv.FormatInt(int64(v.GetNumberValue()), 10)))
+}
+
+// setDeprecation announces a deprecated object's removal to REST clients
+// with Deprecation (RFC 9745), Sunset (RFC 8594) and successor-version Link
+// headers, and returns the matching warning for the response body. Reads
+// through a former name of obj (the requested name) are announced the same
+// way, with the current name as successor. It does nothing for current
+// objects read by their name.
+func setDeprecation(h http.Header, obj *schema.ObjectDef, name string) string {
+	var warnings []string
+	if alias := obj.Alias(name); alias != nil {
+		h.Set("Deprecation", "@"+strconv.FormatInt(alias.CreatedAt.Unix(), 10))
+		h.Add("Link", fmt.Sprintf(`</api/%s>; rel="successor-version"`, obj.APIName))
+		warnings = append(warnings, fmt.Sprintf("object %q was renamed to %q on %s; use the new name", name, obj.APIName, alias.CreatedAt.UTC().Format(time.DateOnly)))
+	}
+	if dep := obj.Deprecation; dep != nil {
+		h.Set("Deprecation", "@"+strconv.FormatInt(dep.At.Unix(), 10))
+		warning := fmt.Sprintf("object %q is deprecated since %s", obj.APIName, dep.At.UTC().Format(time.DateOnly))
+		if dep.SunsetAt != nil {
+			h.Set("Sunset", dep.SunsetAt.UTC().Format(http.TimeFormat))
+			warning += fmt.Sprintf(" and will be removed on %s", dep.SunsetAt.UTC().Format(time.DateOnly))
+		}
+		if dep.Replacement != "" {
+			h.Add("Link", fmt.Sprintf(`</api/%s>; rel="successor-version"`, dep.Replacement))
+			warning += fmt.Sprintf("; migrate to %q", dep.Replacement)
+		}
+		warnings = append(warnings, warning)
+	}
+	return strings.Join(warnings, "; ")
+}
+
+// paramsError maps a ParseParams error to a Connect error. Stale cursors become
+// FailedPrecondition with a CursorInvalidated detail, filters and orders on
+// fields outside the object's allowlist PermissionDenied (see accessError),
+// and everything else InvalidArgument.
+func paramsError(err error) error {
+	if aerr := accessError(err); aerr != nil {
+		return aerr
+	}
+	var ci *hrqlpg.CursorInvalidatedError
+	if !errors.As(err, &ci) {
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	cerr := connect.NewError(connect.CodeFailedPrecondition, err)
+	if detail, derr := connect.NewErrorDetail(&registryv1.CursorInvalidated{
+		Reason:     "CURSOR_INVALIDATED",
+		OrderField: ci.OrderField,
+		Message:    ci.Reason,
+	}); derr == nil {
+		cerr.AddDetail(detail)
+	}
+	return cerr
+}
+
+// accessError returns a PermissionDenied error if err reports a filter or sort
+// on a field that is not filterable or sortable, and nil otherwise.
+func accessError(err error) error {
+	if _, ok := errors.AsType[*schema.FieldAccessError](err); ok {
+		return connect.NewError(connect.CodePermissionDenied, err)
+	}
+	return nil
+}
 
-	estimated := parsePlanRows(planJSON)
+// writeError maps Postgres unique violations to AlreadyExists, other integrity
+// constraint (class 23) and data exception (class 22) errors to InvalidArgument,
+// and everything else to Internal.
+func writeError(op string, err error) error {
+	if pgErr, ok := errors.AsType[*pgconn.PgError](err); ok {
+		switch {
+		case pgErr.Code == "23505":
+			return connect.NewError(connect.CodeAlreadyExists, fmt.Errorf("%s: %s", op, pgErr.Message))
+		case strings.HasPrefix(pgErr.Code, "23"), strings.HasPrefix(pgErr.Code, "22"),
+			pgErr.Code == "P0001": // RAISE EXCEPTION in a trigger, e.g. a hierarchy cycle
+			return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("%s: %s", op, pgErr.Message))
+		}
+	}
+	return connect.NewError(connect.CodeInternal, fmt.Errorf("%s: %w", op, err))
+}
 
-	if estimated <= exactCountThreshold {
+// resolveCount uses the EXPLAIN trick for cheap estimation on large tables,
+// falling back to exact count only when the planner estimate is at most
+// threshold.
+func resolveCount(ctx context.Context, q querier, builder hrqlpg.Builder, params *hrqlpg.QueryParams, threshold int64) (int64, error) {
+	estimated, err := estimateCount(ctx, q, builder, params)
+	if err != nil {
+		return 0, err
+	}
+
+	if estimated <= threshold {
 		countSQL, countArgs, err := builder.BuildCount(params)
 		if err != nil {
 			return estimated, nil
 		}
 		var count int64
-		if err := s.pool.QueryRow(ctx, countSQL, countArgs...).Scan(&count); err != nil {
+		if err := q.QueryRow(ctx, countSQL, countArgs...).Scan(&count); err != nil {
 			return estimated, nil
 		}
 		return count, nil
@@ -192,23 +921,55 @@ func (s *RegistryService) resolveCount(ctx context.Context, builder hrqlpg.Build
 	return estimated, nil
 }
 
+// estimateCount returns the planner's row estimate for params.
+func estimateCount(ctx context.Context, q querier, builder hrqlpg.Builder, params *hrqlpg.QueryParams) (int64, error) {
+	estSQL, estArgs, err := builder.BuildEstimate(params)
+	if err != nil {
+		return 0, err
+	}
+	var planJSON string
+	if err := q.QueryRow(ctx, "EXPLAIN (FORMAT JSON) "+estSQL, estArgs...).Scan(&planJSON); err != nil {
+		return 0, fmt.Errorf("explain estimate: %w", err)
+	}
+	return parsePlanRows(planJSON), nil
+}
+
 // jsonRow holds a single result row as raw JSON plus cursor extraction columns.
 type jsonRow struct {
 	Data      json.RawMessage
 	CursorID  string
-	CursorVal string
+	CursorVal *string // nil when the sort field is NULL
 }
 
 func scanJSONRows(rows pgx.Rows, hasOrderVal bool) ([]jsonRow, error) {
 	var results []jsonRow
 	for rows.Next() {
-		var r jsonRow
-		var err error
-		if hasOrderVal {
-			err = rows.Scan(&r.Data, &r.CursorID, &r.CursorVal)
-		} else {
-			err = rows.Scan(&r.Data, &r.CursorID)
+		r, err := scanJSONRow(rows, hasOrderVal)
+		if err != nil {
+			return nil, err
 		}
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
+
+// scanJSONRow scans the current row of BuildList.
+func scanJSONRow(rows pgx.Rows, hasOrderVal bool) (jsonRow, error) {
+	var r jsonRow
+	var err error
+	if hasOrderVal {
+		err = rows.Scan(&r.Data, &r.CursorID, &r.CursorVal)
+	} else {
+		err = rows.Scan(&r.Data, &r.CursorID)
+	}
+	return r, err
+}
+
+// scanIDRows scans the rows of BuildIDs.
+func scanIDRows(rows pgx.Rows, hasOrderVal bool) ([]jsonRow, error) {
+	var results []jsonRow
+	for rows.Next() {
+		r, err := scanIDRow(rows, hasOrderVal)
 		if err != nil {
 			return nil, err
 		}
@@ -217,6 +978,60 @@ func scanJSONRows(rows pgx.Rows, hasOrderVal bool) ([]jsonRow, error) {
 	return results, rows.Err()
 }
 
+// scanIDRow scans the current row of BuildIDs.
+func scanIDRow(rows pgx.Rows, hasOrderVal bool) (jsonRow, error) {
+	var r jsonRow
+	var err error
+	if hasOrderVal {
+		err = rows.Scan(&r.CursorID, &r.CursorVal)
+	} else {
+		err = rows.Scan(&r.CursorID)
+	}
+	return r, err
+}
+
+// listResults converts list rows to Structs, loading batch-strategy expands and
+// presenting them to v (decrypting or redacting ENCRYPTED fields, masking).
+func listResults(ctx context.Context, pool *pgxpool.Pool, cipher *fieldcrypt.Cipher, obj *schema.ObjectDef, params *hrqlpg.QueryParams, rows []jsonRow, v viewer) ([]*structpb.Struct, error) {
+	results := make([]*structpb.Struct, len(rows))
+	for i, r := range rows {
+		st, err := rawJSONToStruct(r.Data)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("marshal result: %w", err))
+		}
+		results[i] = st
+	}
+
+	if params.ExpandStrategy == hrqlpg.ExpandBatch {
+		if err := stitchBatchExpands(ctx, pool, params.ExpandPlans, results); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+	}
+
+	for _, st := range results {
+		if err := revealRecord(cipher, obj, params.ExpandPlans, st, v); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// queryEcho reports how a list query was interpreted (see hrqlpg.EchoParams).
+func queryEcho(obj *schema.ObjectDef, params *hrqlpg.QueryParams, reveal bool) *registryv1.QueryEcho {
+	e := hrqlpg.EchoParams(obj, params, reveal)
+	echo := &registryv1.QueryEcho{
+		Order:          e.Order,
+		Limit:          int32(e.Limit),
+		Select:         e.Select,
+		Expand:         e.Expand,
+		RedactedFields: e.Redacted,
+	}
+	for _, c := range e.Conditions {
+		echo.Conditions = append(echo.Conditions, &registryv1.QueryEchoCondition{Field: c.Field, Filter: c.Filter, Kind: c.Kind})
+	}
+	return echo
+}
+
 func parsePlanRows(planJSON string) int64 {
 	var plan []struct {
 		Plan struct {
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	registryv1 "github.com/atlekbai/schema_registry/gen/registry/v1"
+	"github.com/atlekbai/schema_registry/internal/db"
+	hrqlpg "github.com/atlekbai/schema_registry/internal/hrql/pg"
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// renameFieldBatch is how many documents each statement of a RenameField
+// rewrites, so no single statement holds a large object's rows at once.
+const renameFieldBatch = 1000
+
+// RenameField changes the api_name of a custom object's field and records the
+// old one as an alias, so requests and HRQL naming the field either way find
+// it. In the same transaction the field's values move to the new key in the
+// object's record documents and record history, in batches, and the catalog
+// settings naming the field (default_order, display_template, the retention
+// policy, lookups denormalizing it as their label) follow the rename.
+// Renaming back to an alias drops it.
+func (s *MetadataService) RenameField(ctx context.Context, req *connect.Request[registryv1.RenameFieldRequest]) (*connect.Response[registryv1.RenameFieldResponse], error) {
+	if err := s.holdChange(ctx, "RenameField", req.Msg.ObjectId, req.Msg); err != nil {
+		return nil, err
+	}
+
+	msg := req.Msg
+	obj := s.cache.GetByID(uuid.MustParse(msg.ObjectId))
+	if obj == nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("object not found"))
+	}
+	var fd *schema.FieldDef
+	for i := range obj.Fields {
+		if obj.Fields[i].ID.String() == msg.Id {
+			fd = &obj.Fields[i]
+		}
+	}
+	if fd == nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("field not found"))
+	}
+	// Standard fields are named in migrations and the HRQL compiler, and
+	// standard tables keep point-in-time snapshots of their documents that
+	// a rename cannot rewrite.
+	if obj.IsStandard || fd.IsStandard {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("fields of standard object %q cannot be renamed", obj.APIName))
+	}
+	if err := s.idents.ValidateFieldRename(obj, fd, msg.ApiName); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	tx, err := db.Begin(ctx, s.pool)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("begin transaction: %w", err))
+	}
+	defer tx.Rollback(ctx)
+
+	// Locking the object serializes renames of its fields, which rewrite
+	// its settings.
+	var (
+		oldName         string
+		defaultOrder    string
+		displayTemplate string
+	)
+	err = tx.QueryRow(ctx, `
+		SELECT f.api_name, COALESCE(o.default_order, ''), COALESCE(o.display_template, '')
+		FROM metadata.objects o
+		JOIN metadata.fields f ON f.object_id = o.id
+		WHERE o.id = $1 AND f.id = $2
+		FOR UPDATE
+	`, msg.ObjectId, msg.Id).Scan(&oldName, &defaultOrder, &displayTemplate)
+	if err == pgx.ErrNoRows {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("field not found"))
+	}
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("rename field: %w", err))
+	}
+
+	// The field's indexes are on expressions over its document key. Dropping
+	// them first also spares the rewrite their upkeep.
+	if err := dropFieldIndexes(ctx, tx, obj, fd); err != nil {
+		return nil, err
+	}
+
+	var (
+		f             = &registryv1.FieldMeta{}
+		storedMasking *schema.MaskingPolicy
+	)
+	err = func() error {
+		if _, err := tx.Exec(ctx, `DELETE FROM metadata.field_aliases WHERE object_id = $1 AND alias = $2 AND field_id = $3`, msg.ObjectId, msg.ApiName, msg.Id); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `INSERT INTO metadata.field_aliases (object_id, alias, field_id) VALUES ($1, $2, $3)`, msg.ObjectId, oldName, msg.Id); err != nil {
+			return err
+		}
+		return tx.QueryRow(ctx, `
+			UPDATE metadata.fields SET api_name = $3, updated_at = now()
+			WHERE object_id = $1 AND id = $2
+			RETURNING `+fieldReturning,
+			msg.ObjectId, msg.Id, msg.ApiName,
+		).Scan(fieldScanDest(f, &storedMasking)...)
+	}()
+	if pgErr, ok := errors.AsType[*pgconn.PgError](err); ok && pgErr.Code == "23505" {
+		return nil, connect.NewError(connect.CodeAlreadyExists, fmt.Errorf("field api_name %q is taken", msg.ApiName))
+	}
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("rename field: %w", err))
+	}
+	f.Masking = maskingMeta(storedMasking)
+
+	renamed := *fd
+	renamed.APIName = msg.ApiName
+	records, err := moveDocumentKey(ctx, tx, obj, oldName, msg.ApiName)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("rename field values: %w", err))
+	}
+	if fd.LabelField() != "" {
+		if _, err := moveDocumentKey(ctx, tx, obj, hrqlpg.LabelKey(fd), hrqlpg.LabelKey(&renamed)); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("rename field labels: %w", err))
+		}
+	}
+	if err := renameFieldReferences(ctx, tx, obj, oldName, msg.ApiName, defaultOrder, displayTemplate); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("rename field references: %w", err))
+	}
+	if fd.IsExternalID {
+		if _, err := tx.Exec(ctx, hrqlpg.BuildExternalIDIndex(obj, &renamed)); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("create external ID index: %w", err))
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("commit: %w", err))
+	}
+
+	s.reloadCache(ctx)
+	if f.IsSearchable {
+		s.syncSearchIndex(ctx, obj.ID, fd.ID, false)
+	}
+	if f.IsSortable {
+		s.syncSortIndexes(ctx, obj.ID, fd.ID)
+	}
+	return connect.NewResponse(&registryv1.RenameFieldResponse{Field: f, Records: records}), nil
+}
+
+// moveDocumentKey moves the values under key from to key to in the record
+// documents of the custom object obj and in their history, renaming batches
+// of renameFieldBatch until none is left. A value already under to, left by
+// a deleted field of that name, is overwritten. It returns how many records
+// were rewritten. The version of a record is left alone, so the rewrite adds
+// no history of its own.
+func moveDocumentKey(ctx context.Context, tx pgx.Tx, obj *schema.ObjectDef, from, to string) (int64, error) {
+	records, err := execBatches(ctx, tx, `
+		UPDATE metadata.records SET data = (data - $2::text) || jsonb_build_object($3::text, data->$2::text)
+		WHERE id IN (
+			SELECT id FROM metadata.records
+			WHERE object_id = $1 AND data ? $2::text
+			LIMIT $4
+		)
+	`, obj.ID, from, to)
+	if err != nil {
+		return 0, err
+	}
+	_, err = execBatches(ctx, tx, `
+		UPDATE metadata.record_history
+		SET data = jsonb_set(data, '{data}', ((data->'data') - $2::text) || jsonb_build_object($3::text, data->'data'->$2::text))
+		WHERE object_id = $1 AND (record_id, version) IN (
+			SELECT record_id, version FROM metadata.record_history
+			WHERE object_id = $1 AND data->'data' ? $2::text
+			LIMIT $4
+		)
+	`, obj.ID, from, to)
+	return records, err
+}
+
+// execBatches runs a statement limited to renameFieldBatch rows (its last
+// parameter) until a run affects fewer, and returns the rows affected.
+func execBatches(ctx context.Context, tx pgx.Tx, sql string, args ...any) (int64, error) {
+	args = append(args, renameFieldBatch)
+	var total int64
+	for {
+		tag, err := tx.Exec(ctx, sql, args...)
+		if err != nil {
+			return total, err
+		}
+		total += tag.RowsAffected()
+		if tag.RowsAffected() < renameFieldBatch {
+			return total, nil
+		}
+	}
+}
+
+// renameFieldReferences points the catalog settings naming field from of obj
+// at to: the object's default_order and display_template (as read under
+// lock), its retention policy, and the label settings of lookups to obj.
+func renameFieldReferences(ctx context.Context, tx pgx.Tx, obj *schema.ObjectDef, from, to, defaultOrder, displayTemplate string) error {
+	order, changed := defaultOrder, false
+	if field, rest, _ := strings.Cut(defaultOrder, "."); field == from {
+		order, changed = strings.TrimSuffix(to+"."+rest, "."), true
+	}
+	tmpl, renamed := schema.RenameDisplayField(displayTemplate, from, to)
+	if changed || renamed {
+		if _, err := tx.Exec(ctx, `
+			UPDATE metadata.objects
+			SET default_order = NULLIF($2, ''), display_template = NULLIF($3, ''), updated_at = now()
+			WHERE id = $1
+		`, obj.ID, order, tmpl); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE metadata.retention_policies
+		SET date_field = CASE WHEN date_field = $2 THEN $3 ELSE date_field END,
+		    anonymize_fields = array_replace(anonymize_fields, $2, $3),
+		    updated_at = now()
+		WHERE object_id = $1 AND (date_field = $2 OR $2 = ANY(anonymize_fields))
+	`, obj.ID, from, to); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx, `
+		UPDATE metadata.fields
+		SET type_config = jsonb_set(type_config, '{denormalize_label}', to_jsonb($3::text)), updated_at = now()
+		WHERE lookup_object_id = $1 AND type_config->>'denormalize_label' = $2
+	`, obj.ID, from, to)
+	return err
+}
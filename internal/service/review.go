@@ -0,0 +1,286 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	registryv1 "github.com/atlekbai/schema_registry/gen/registry/v1"
+	registryv1connect "github.com/atlekbai/schema_registry/gen/registry/v1/registryv1connect"
+	"github.com/atlekbai/schema_registry/internal/db"
+)
+
+// reviewPermission allows approving and rejecting change requests.
+const reviewPermission = "metadata:review"
+
+// defaultChangeRequestLimit is the ListChangeRequests page when the request
+// sets none.
+const defaultChangeRequestLimit = 50
+
+// approvedChangeKey marks a context replaying an approved change request, so
+// MetadataService applies it instead of holding it again.
+type approvedChangeKey struct{}
+
+// holdChange records a metadata mutation as a pending change request while
+// change approval is on, returning the FAILED_PRECONDITION error the caller
+// gets instead of the change. It returns nil when the mutation should be
+// applied: approval is off, or ReviewService is replaying an approved change.
+func (s *MetadataService) holdChange(ctx context.Context, method, objectID string, msg proto.Message) error {
+	if !s.approval || ctx.Value(approvedChangeKey{}) != nil {
+		return nil
+	}
+	payload, err := protojson.Marshal(msg)
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("encode change: %w", err))
+	}
+	actor, _ := db.ActorFrom(ctx)
+
+	var id string
+	err = s.pool.QueryRow(ctx, `
+		INSERT INTO metadata.change_requests ("method", "object_id", "payload", "requested_by")
+		VALUES ($1, NULLIF($2, '')::uuid, $3, $4)
+		RETURNING "id"::text
+	`, method, objectID, payload, actor.ID).Scan(&id)
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("record change request: %w", err))
+	}
+
+	cerr := connect.NewError(connect.CodeFailedPrecondition,
+		fmt.Errorf("metadata changes require approval: %s is pending as change request %s", method, id))
+	if detail, derr := connect.NewErrorDetail(&registryv1.ChangePending{
+		Reason:          "CHANGE_PENDING_APPROVAL",
+		ChangeRequestId: id,
+	}); derr == nil {
+		cerr.AddDetail(detail)
+	}
+	return cerr
+}
+
+// ReviewService lists the change requests MetadataService holds while change
+// approval is on, and approves (replays) or rejects them.
+type ReviewService struct {
+	pool *pgxpool.Pool
+	meta *MetadataService
+}
+
+func NewReviewService(pool *pgxpool.Pool, meta *MetadataService) *ReviewService {
+	return &ReviewService{pool: pool, meta: meta}
+}
+
+func (s *ReviewService) RegisterHandler(interceptors ...connect.Interceptor) (string, http.Handler) {
+	return registryv1connect.NewReviewServiceHandler(s, connect.WithInterceptors(interceptors...))
+}
+
+const changeRequestColumns = `
+	"id"::text, "method", COALESCE("object_id"::text, ''), "payload"::text, "status",
+	"requested_by", "created_at", COALESCE("reviewed_by", ''), "reviewed_at",
+	COALESCE("review_comment", ''), COALESCE("error", '')`
+
+func (s *ReviewService) ListChangeRequests(ctx context.Context, req *connect.Request[registryv1.ListChangeRequestsRequest]) (*connect.Response[registryv1.ListChangeRequestsResponse], error) {
+	msg := req.Msg
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+changeRequestColumns+`
+		FROM metadata.change_requests
+		WHERE ($1 = '' OR "status" = $1)
+		  AND ($2 = '' OR "object_id" = NULLIF($2, '')::uuid)
+		ORDER BY "created_at" DESC, "id" DESC
+		LIMIT $3
+	`, msg.Status, msg.ObjectId, cmp.Or(msg.Limit, defaultChangeRequestLimit))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("list change requests: %w", err))
+	}
+	defer rows.Close()
+
+	resp := &registryv1.ListChangeRequestsResponse{}
+	for rows.Next() {
+		cr, err := scanChangeRequest(rows)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("scan change request: %w", err))
+		}
+		resp.ChangeRequests = append(resp.ChangeRequests, cr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("list change requests: %w", err))
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *ReviewService) ApproveChangeRequest(ctx context.Context, req *connect.Request[registryv1.ApproveChangeRequestRequest]) (*connect.Response[registryv1.ApproveChangeRequestResponse], error) {
+	if !hasPermission(req.Header(), reviewPermission) {
+		return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("approving changes requires the %s permission", reviewPermission))
+	}
+	reviewer, _ := db.ActorFrom(ctx)
+
+	// The row stays locked while the change is applied, so concurrent
+	// reviews of the same request wait and then find it no longer pending.
+	tx, err := db.Begin(ctx, s.pool)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("begin transaction: %w", err))
+	}
+	defer tx.Rollback(ctx)
+
+	cr, err := pendingChangeRequest(ctx, tx, req.Msg.Id)
+	if err != nil {
+		return nil, err
+	}
+	if cr.RequestedBy != "" && cr.RequestedBy == reviewer.ID {
+		return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("change request %s cannot be approved by its requester", cr.Id))
+	}
+
+	status, applyErr := "APPROVED", s.apply(ctx, cr)
+	var errText string
+	if applyErr != nil {
+		status, errText = "FAILED", applyErr.Error()
+	}
+	cr, err = reviewChangeRequest(ctx, tx, cr.Id, status, reviewer.ID, req.Msg.Comment, errText)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("commit: %w", err))
+	}
+	if applyErr != nil {
+		return nil, applyErr
+	}
+	return connect.NewResponse(&registryv1.ApproveChangeRequestResponse{ChangeRequest: cr}), nil
+}
+
+func (s *ReviewService) RejectChangeRequest(ctx context.Context, req *connect.Request[registryv1.RejectChangeRequestRequest]) (*connect.Response[registryv1.RejectChangeRequestResponse], error) {
+	if !hasPermission(req.Header(), reviewPermission) {
+		return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("rejecting changes requires the %s permission", reviewPermission))
+	}
+	reviewer, _ := db.ActorFrom(ctx)
+
+	tx, err := db.Begin(ctx, s.pool)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("begin transaction: %w", err))
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := pendingChangeRequest(ctx, tx, req.Msg.Id); err != nil {
+		return nil, err
+	}
+	cr, err := reviewChangeRequest(ctx, tx, req.Msg.Id, "REJECTED", reviewer.ID, req.Msg.Comment, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("commit: %w", err))
+	}
+	return connect.NewResponse(&registryv1.RejectChangeRequestResponse{ChangeRequest: cr}), nil
+}
+
+// apply replays a held change through MetadataService, which reloads the
+// schema cache as after a direct call.
+func (s *ReviewService) apply(ctx context.Context, cr *registryv1.ChangeRequest) error {
+	ctx = context.WithValue(ctx, approvedChangeKey{}, true)
+	payload, err := protojson.Marshal(cr.Payload)
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("decode change: %w", err))
+	}
+
+	var (
+		msg  proto.Message
+		call func() error
+	)
+	switch cr.Method {
+	case "CreateObject":
+		m := &registryv1.CreateObjectRequest{}
+		msg, call = m, func() error { _, err := s.meta.CreateObject(ctx, connect.NewRequest(m)); return err }
+	case "UpdateObject":
+		m := &registryv1.UpdateObjectRequest{}
+		msg, call = m, func() error { _, err := s.meta.UpdateObject(ctx, connect.NewRequest(m)); return err }
+	case "DeleteObject":
+		m := &registryv1.DeleteObjectRequest{}
+		msg, call = m, func() error { _, err := s.meta.DeleteObject(ctx, connect.NewRequest(m)); return err }
+	case "RenameObject":
+		m := &registryv1.RenameObjectRequest{}
+		msg, call = m, func() error { _, err := s.meta.RenameObject(ctx, connect.NewRequest(m)); return err }
+	case "CreateField":
+		m := &registryv1.CreateFieldRequest{}
+		msg, call = m, func() error { _, err := s.meta.CreateField(ctx, connect.NewRequest(m)); return err }
+	case "UpdateField":
+		m := &registryv1.UpdateFieldRequest{}
+		msg, call = m, func() error { _, err := s.meta.UpdateField(ctx, connect.NewRequest(m)); return err }
+	case "DeleteField":
+		m := &registryv1.DeleteFieldRequest{}
+		msg, call = m, func() error { _, err := s.meta.DeleteField(ctx, connect.NewRequest(m)); return err }
+	case "RenameField":
+		m := &registryv1.RenameFieldRequest{}
+		msg, call = m, func() error { _, err := s.meta.RenameField(ctx, connect.NewRequest(m)); return err }
+	default:
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("unknown change method %q", cr.Method))
+	}
+	if err := protojson.Unmarshal(payload, msg); err != nil {
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("decode change: %w", err))
+	}
+	return call()
+}
+
+// pendingChangeRequest locks a change request for review; it must exist and
+// still be PENDING.
+func pendingChangeRequest(ctx context.Context, tx pgx.Tx, id string) (*registryv1.ChangeRequest, error) {
+	cr, err := scanChangeRequest(tx.QueryRow(ctx, `
+		SELECT `+changeRequestColumns+`
+		FROM metadata.change_requests WHERE "id" = $1
+		FOR UPDATE
+	`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("change request not found"))
+	}
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("read change request: %w", err))
+	}
+	if cr.Status != "PENDING" {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("change request %s is already %s", cr.Id, cr.Status))
+	}
+	return cr, nil
+}
+
+// reviewChangeRequest records the outcome of a review.
+func reviewChangeRequest(ctx context.Context, tx pgx.Tx, id, status, reviewer, comment, errText string) (*registryv1.ChangeRequest, error) {
+	cr, err := scanChangeRequest(tx.QueryRow(ctx, `
+		UPDATE metadata.change_requests
+		SET "status" = $2, "reviewed_by" = $3, "reviewed_at" = now(),
+		    "review_comment" = NULLIF($4, ''), "error" = NULLIF($5, '')
+		WHERE "id" = $1
+		RETURNING `+changeRequestColumns,
+		id, status, reviewer, comment, errText))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("review change request: %w", err))
+	}
+	return cr, nil
+}
+
+func scanChangeRequest(row pgx.Row) (*registryv1.ChangeRequest, error) {
+	var (
+		cr         registryv1.ChangeRequest
+		payload    string
+		createdAt  time.Time
+		reviewedAt *time.Time
+	)
+	if err := row.Scan(&cr.Id, &cr.Method, &cr.ObjectId, &payload, &cr.Status,
+		&cr.RequestedBy, &createdAt, &cr.ReviewedBy, &reviewedAt,
+		&cr.ReviewComment, &cr.Error); err != nil {
+		return nil, err
+	}
+	cr.Payload = &structpb.Struct{}
+	if err := protojson.Unmarshal([]byte(payload), cr.Payload); err != nil {
+		return nil, fmt.Errorf("payload: %w", err)
+	}
+	cr.CreatedAt = createdAt.UTC().Format(time.RFC3339)
+	if reviewedAt != nil {
+		cr.ReviewedAt = reviewedAt.UTC().Format(time.RFC3339)
+	}
+	return &cr, nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/jackc/pgx/v5"
+
+	registryv1 "github.com/atlekbai/schema_registry/gen/registry/v1"
+	hrqlpg "github.com/atlekbai/schema_registry/internal/hrql/pg"
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// SearchIndexReport joins the string operation counts of the HRQL metrics
+// with the searchable flags and the trigram indexes in the catalog, so a DBA
+// can see which contains/starts_with/ends_with filters scan without an index.
+func (s *AdminService) SearchIndexReport(ctx context.Context, _ *connect.Request[registryv1.SearchIndexReportRequest]) (*connect.Response[registryv1.SearchIndexReportResponse], error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT c.relname, i.indisvalid
+		FROM pg_index i JOIN pg_class c ON c.oid = i.indexrelid
+		WHERE c.relname LIKE 'ix\_search\_%'
+	`)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("list search indexes: %w", err))
+	}
+	valid := make(map[string]bool)
+	var name string
+	var ok bool
+	if _, err := pgx.ForEachRow(rows, []any{&name, &ok}, func() error {
+		valid[name] = ok
+		return nil
+	}); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("list search indexes: %w", err))
+	}
+
+	entries := make(map[*schema.FieldDef]*registryv1.SearchIndexEntry)
+	entry := func(obj *schema.ObjectDef, fd *schema.FieldDef) *registryv1.SearchIndexEntry {
+		e := entries[fd]
+		if e == nil {
+			e = &registryv1.SearchIndexEntry{
+				ObjectName:   obj.APIName,
+				Field:        fd.APIName,
+				IsSearchable: fd.IsSearchable,
+				IndexName:    hrqlpg.SearchIndexName(fd),
+				Indexed:      valid[hrqlpg.SearchIndexName(fd)],
+			}
+			entries[fd] = e
+		}
+		return e
+	}
+	for _, obj := range s.cache.Objects() {
+		for i := range obj.Fields {
+			if fd := &obj.Fields[i]; fd.IsSearchable {
+				entry(obj, fd)
+			}
+		}
+	}
+	if s.usage != nil {
+		for f, n := range s.usage.Snapshot().StringOps {
+			if obj, fd := s.resolveChain(f.Object, strings.Split(f.Field, ".")); fd != nil {
+				entry(obj, fd).StringOpUses += int64(n)
+			}
+		}
+	}
+
+	out := make([]*registryv1.SearchIndexEntry, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, e)
+	}
+	// Unindexed fields that queries search come first, most searched first.
+	slices.SortFunc(out, func(a, b *registryv1.SearchIndexEntry) int {
+		needs := func(e *registryv1.SearchIndexEntry) bool { return !e.Indexed && e.StringOpUses > 0 }
+		if na, nb := needs(a), needs(b); na != nb {
+			if na {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Or(
+			cmp.Compare(b.StringOpUses, a.StringOpUses),
+			strings.Compare(a.ObjectName, b.ObjectName),
+			strings.Compare(a.Field, b.Field),
+		)
+	})
+	return connect.NewResponse(&registryv1.SearchIndexReportResponse{Fields: out}), nil
+}
+
+// resolveChain returns the field a chain of API names ends at, following
+// LOOKUPs from the object named object, and the object it belongs to; nil
+// when the chain no longer resolves.
+func (s *AdminService) resolveChain(object string, chain []string) (*schema.ObjectDef, *schema.FieldDef) {
+	obj := s.cache.Get(object)
+	for i, name := range chain {
+		if obj == nil {
+			return nil, nil
+		}
+		fd := obj.FieldsByAPIName[name]
+		if fd == nil {
+			return nil, nil
+		}
+		if i == len(chain)-1 {
+			return obj, fd
+		}
+		if fd.LookupObjectID == nil {
+			return nil, nil
+		}
+		obj = s.cache.GetByID(*fd.LookupObjectID)
+	}
+	return nil, nil
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"connectrpc.com/connect"
+
+	registryv1 "github.com/atlekbai/schema_registry/gen/registry/v1"
+	"github.com/atlekbai/schema_registry/internal/db"
+	hrqlpg "github.com/atlekbai/schema_registry/internal/hrql/pg"
+)
+
+// SplitList places the bounds of n id-range partitions over the records
+// matching the filters (pg.BuildPartitionBounds) and returns a List cursor
+// for each. With snapshot, the bounds are read from the snapshot every
+// partition cursor pins.
+func (s *RegistryService) SplitList(ctx context.Context, req *connect.Request[registryv1.SplitListRequest]) (*connect.Response[registryv1.SplitListResponse], error) {
+	msg := req.Msg
+	obj := s.cache.Get(msg.ObjectName)
+	if obj == nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("no object registered with api_name %q", msg.ObjectName))
+	}
+
+	params, err := hrqlpg.ParseParams(obj, hrqlpg.ParamsInput{Filters: msg.Filters, Cache: s.cache})
+	if err != nil {
+		return nil, paramsError(err)
+	}
+	params.SQLConditions, err = hrqlpg.TranslateConditions(params.Conditions, obj, s.cache)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	snapshot, expires, err := s.listSnapshot(ctx, msg.Snapshot, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := hrqlpg.NewBuilder(obj)
+	var (
+		rows   int64
+		bounds []string
+	)
+	err = s.limits.List.Do(ctx, func() error {
+		return s.read(ctx, snapshot, func(q querier) error {
+			estSQL, estArgs, err := builder.BuildEstimate(params)
+			if err != nil {
+				return err
+			}
+			var planJSON string
+			if err := q.QueryRow(ctx, "EXPLAIN (FORMAT JSON) "+estSQL, estArgs...).Scan(&planJSON); err != nil {
+				return fmt.Errorf("explain estimate: %w", err)
+			}
+			rows = parsePlanRows(planJSON)
+			if msg.Partitions < 2 {
+				return nil
+			}
+
+			sqlStr, args, err := hrqlpg.BuildPartitionBounds(obj, params, int(msg.Partitions), float64(rows))
+			if err != nil {
+				return err
+			}
+			return q.QueryRow(ctx, sqlStr, args...).Scan(&bounds)
+		})
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrSnapshotExpired) {
+			return nil, snapshotExpiredError()
+		}
+		return nil, queryFailed(err)
+	}
+
+	resp := &registryv1.SplitListResponse{EstimatedRows: rows}
+	for _, p := range hrqlpg.Partitions(bounds) {
+		resp.Partitions = append(resp.Partitions, &registryv1.ListPartition{
+			Cursor: s.cursors.EncodePartition(obj, p, snapshot, expires),
+			After:  p.After,
+			Until:  p.Until,
+		})
+	}
+	return connect.NewResponse(resp), nil
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	registryv1 "github.com/atlekbai/schema_registry/gen/registry/v1"
+	registryv1connect "github.com/atlekbai/schema_registry/gen/registry/v1/registryv1connect"
+	hrqlpg "github.com/atlekbai/schema_registry/internal/hrql/pg"
+	"github.com/atlekbai/schema_registry/internal/metrics"
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+type StatsService struct {
+	pool  *pgxpool.Pool
+	cache *schema.Cache
+	usage *metrics.HRQL
+	lists schema.ListLimits
+}
+
+func NewStatsService(pool *pgxpool.Pool, cache *schema.Cache, usage *metrics.HRQL, lists schema.ListLimits) *StatsService {
+	return &StatsService{pool: pool, cache: cache, usage: usage, lists: lists}
+}
+
+func (s *StatsService) RegisterHandler(interceptors ...connect.Interceptor) (string, http.Handler) {
+	return registryv1connect.NewStatsServiceHandler(s, connect.WithInterceptors(interceptors...))
+}
+
+func (s *StatsService) ObjectStats(ctx context.Context, req *connect.Request[registryv1.ObjectStatsRequest]) (*connect.Response[registryv1.ObjectStatsResponse], error) {
+	exact := req.Msg.Exact
+	var (
+		custom  map[string]*registryv1.ObjectStats
+		records relationStats
+		err     error
+	)
+	if exact {
+		custom, err = s.exactCustomObjectStats(ctx)
+	} else {
+		records, err = s.relationStats(ctx, "metadata.records")
+	}
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("custom object stats: %w", err))
+	}
+
+	var out []*registryv1.ObjectStats
+	for _, obj := range s.cache.Objects() {
+		if !obj.IsStandard && exact {
+			st, ok := custom[obj.ID.String()]
+			if !ok {
+				st = &registryv1.ObjectStats{}
+			}
+			st.ObjectId, st.ApiName = obj.ID.String(), obj.APIName
+			out = append(out, st)
+			continue
+		}
+
+		var st *registryv1.ObjectStats
+		if obj.IsStandard {
+			st, err = s.standardObjectStats(ctx, obj, exact)
+		} else {
+			st, err = s.customObjectStats(ctx, obj, records)
+		}
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("stats for %q: %w", obj.APIName, err))
+		}
+		out = append(out, st)
+	}
+
+	return connect.NewResponse(&registryv1.ObjectStatsResponse{Objects: out}), nil
+}
+
+// relationStats is what pg_class and pg_stat_user_tables know of a table.
+type relationStats struct {
+	bytes      int64
+	liveTuples int64
+}
+
+func (s *StatsService) relationStats(ctx context.Context, table string) (relationStats, error) {
+	var rel relationStats
+	err := s.pool.QueryRow(ctx, `
+		SELECT pg_total_relation_size(c.oid), COALESCE(t.n_live_tup, 0)
+		FROM pg_class c
+		LEFT JOIN pg_stat_user_tables t ON t.relid = c.oid
+		WHERE c.oid = $1::regclass
+	`, table).Scan(&rel.bytes, &rel.liveTuples)
+	if err != nil {
+		return rel, fmt.Errorf("read table statistics: %w", err)
+	}
+	return rel, nil
+}
+
+// standardObjectStats reads the live row estimate and relation size from the
+// table statistics, falling back to an exact count for small tables. exact
+// counts the rows and also reads the latest updated_at; the statistics have
+// no last write time, so without exact it is left empty.
+func (s *StatsService) standardObjectStats(ctx context.Context, obj *schema.ObjectDef, exact bool) (*registryv1.ObjectStats, error) {
+	st := &registryv1.ObjectStats{ObjectId: obj.ID.String(), ApiName: obj.APIName, IsStandard: true}
+	table := obj.TableName()
+
+	rel, err := s.relationStats(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	st.StorageBytes = rel.bytes
+
+	// n_live_tup is 0 until the statistics collector has seen the table.
+	if exact || rel.liveTuples <= s.lists.For(obj).ExactCountThreshold {
+		if err := s.pool.QueryRow(ctx, fmt.Sprintf(`SELECT count(*) FROM %s`, table)).Scan(&st.RecordCount); err != nil {
+			return nil, fmt.Errorf("count: %w", err)
+		}
+	} else {
+		st.RecordCount = rel.liveTuples
+		st.CountIsEstimate = true
+	}
+
+	if !exact {
+		return st, nil
+	}
+	err = s.pool.QueryRow(ctx,
+		fmt.Sprintf(`SELECT COALESCE(max("updated_at")::text, '') FROM %s`, table),
+	).Scan(&st.LastWriteAt)
+	if err != nil {
+		return nil, fmt.Errorf("last write: %w", err)
+	}
+	return st, nil
+}
+
+// customObjectStats estimates obj's records in metadata.records like list
+// counts do (resolveCount), and its storage as its share of the table's size.
+func (s *StatsService) customObjectStats(ctx context.Context, obj *schema.ObjectDef, records relationStats) (*registryv1.ObjectStats, error) {
+	st := &registryv1.ObjectStats{ObjectId: obj.ID.String(), ApiName: obj.APIName}
+
+	builder, params := hrqlpg.NewBuilder(obj), &hrqlpg.QueryParams{}
+	estimated, err := estimateCount(ctx, s.pool, builder, params)
+	if err != nil {
+		return nil, err
+	}
+	if estimated <= s.lists.For(obj).ExactCountThreshold {
+		countSQL, countArgs, err := builder.BuildCount(params)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.pool.QueryRow(ctx, countSQL, countArgs...).Scan(&st.RecordCount); err != nil {
+			return nil, fmt.Errorf("count: %w", err)
+		}
+	} else {
+		st.RecordCount = estimated
+		st.CountIsEstimate = true
+	}
+
+	if records.liveTuples > 0 {
+		st.StorageBytes = records.bytes * min(st.RecordCount, records.liveTuples) / records.liveTuples
+	}
+	return st, nil
+}
+
+// exactCustomObjectStats groups metadata.records by object in a single scan.
+func (s *StatsService) exactCustomObjectStats(ctx context.Context) (map[string]*registryv1.ObjectStats, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT object_id::text, count(*), COALESCE(sum(pg_column_size(r.*)), 0)::bigint,
+		       max(updated_at)::text
+		FROM metadata.records r
+		GROUP BY object_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make(map[string]*registryv1.ObjectStats)
+	for rows.Next() {
+		st := &registryv1.ObjectStats{}
+		var id string
+		if err := rows.Scan(&id, &st.RecordCount, &st.StorageBytes, &st.LastWriteAt); err != nil {
+			return nil, err
+		}
+		stats[id] = st
+	}
+	return stats, rows.Err()
+}
+
+func (s *StatsService) HRQLUsage(_ context.Context, _ *connect.Request[registryv1.HRQLUsageRequest]) (*connect.Response[registryv1.HRQLUsageResponse], error) {
+	snap := s.usage.Snapshot()
+	resp := &registryv1.HRQLUsageResponse{
+		Queries:       int64(snap.Queries),
+		ParseErrors:   int64(snap.ParseErrors),
+		CompileErrors: int64(snap.CompileErrors),
+		Plans:         make(map[string]int64, len(snap.Plans)),
+		MeanCompileMs: float64(snap.MeanCompile) / float64(time.Millisecond),
+	}
+	if snap.Queries > 0 {
+		resp.ParseErrorRate = float64(snap.ParseErrors) / float64(snap.Queries)
+	}
+	for kind, n := range snap.Plans {
+		resp.Plans[kind] = int64(n)
+	}
+	for name, n := range snap.Functions {
+		resp.Functions = append(resp.Functions, &registryv1.HRQLFunctionUsage{Name: name, Uses: int64(n)})
+	}
+	slices.SortFunc(resp.Functions, func(a, b *registryv1.HRQLFunctionUsage) int {
+		return cmp.Or(cmp.Compare(b.Uses, a.Uses), strings.Compare(a.Name, b.Name))
+	})
+	return connect.NewResponse(resp), nil
+}
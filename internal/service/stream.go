@@ -0,0 +1,166 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+
+	"connectrpc.com/connect"
+
+	registryv1 "github.com/atlekbai/schema_registry/gen/registry/v1"
+	"github.com/atlekbai/schema_registry/internal/db"
+	"github.com/atlekbai/schema_registry/internal/hrql"
+	hrqlpg "github.com/atlekbai/schema_registry/internal/hrql/pg"
+	"github.com/atlekbai/schema_registry/internal/metrics"
+)
+
+const (
+	// DefaultStreamRows caps an ExecuteStream unless the server configures
+	// its own limit (QueryLimits.StreamRows).
+	DefaultStreamRows = 1_000_000
+	// defaultStreamBatch is the records per message without a batch_size.
+	defaultStreamBatch = 100
+)
+
+// streamRows returns the server's cap on the records of one ExecuteStream.
+func (l QueryLimits) streamRows() int64 {
+	return cmp.Or(l.StreamRows, DefaultStreamRows)
+}
+
+// ExecuteStream runs a list or ids plan as one query and sends its rows in
+// batches while pgx reads them, so memory stays at one batch however many
+// records match. Send blocks while the client is behind, which stops reading
+// rows and leaves the rest of the result waiting in the connection, so a slow
+// consumer holds back the query rather than piling records up in the server.
+// The stream holds a list slot (QueryLimits.List) and a pool connection until
+// it ends, and takes no other connection.
+func (s *OrgService) ExecuteStream(ctx context.Context, req *connect.Request[registryv1.ExecuteStreamRequest], stream *connect.ServerStream[registryv1.ExecuteStreamResponse]) error {
+	msg := req.Msg
+
+	plan, warnings, err := s.compile(msg.Query, msg.SelfId, msg.TimeZone, msg.IncludeTerminated)
+	if err != nil {
+		return compileError(err)
+	}
+	if (plan.Kind != hrql.PlanList && plan.Kind != hrql.PlanIDs) || plan.Union != nil {
+		return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("ExecuteStream runs list and ids queries other than union; use Query"))
+	}
+	metrics.SetUsageObject(ctx, cmp.Or(plan.Object, "employees"))
+	if err := applyAsOf(plan, msg.AsOf); err != nil {
+		return err
+	}
+	checkCost, err := s.limits.costChecked(msg.SkipCostCheck, req.Header())
+	if err != nil {
+		return err
+	}
+	v := viewerOf(req.Header())
+	if err := s.checkMasked(plan, v); err != nil {
+		return err
+	}
+
+	obj, params, sqlResult, err := s.listParams(ctx, plan, &registryv1.QueryRequest{
+		Select:            msg.Select,
+		Expand:            msg.Expand,
+		Order:             msg.Order,
+		IncludeTerminated: msg.IncludeTerminated,
+	})
+	if err != nil {
+		return err
+	}
+	limit := s.limits.streamRows()
+	if msg.Limit > 0 {
+		limit = min(limit, msg.Limit)
+	}
+	if n := int64(max(sqlResult.Limit, sqlResult.Sample)); n > 0 {
+		limit = min(limit, n)
+	}
+	params.Limit = int(limit)
+	// Batch expands would query another pool connection while the stream's
+	// rows hold this one, so streams with every connection taken would wait
+	// on each other forever. Lateral expands arrive with the rows.
+	params.ExpandStrategy = hrqlpg.ExpandLateral
+	// A table sample may hold too few rows, which a page would draw again;
+	// a stream orders the whole table by random() instead.
+	params.SamplePercent = 0
+
+	idsOnly := plan.Kind == hrql.PlanIDs
+	builder := hrqlpg.NewBuilder(obj)
+	build, scan := builder.BuildList, scanJSONRow
+	if idsOnly {
+		build, scan = builder.BuildIDs, scanIDRow
+	}
+	sqlStr, args, err := build(params)
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("build query: %w", err))
+	}
+	if checkCost {
+		if err := s.checkCost(ctx, sqlStr, args); err != nil {
+			return err
+		}
+	}
+
+	batchSize := int(cmp.Or(msg.BatchSize, defaultStreamBatch))
+	// send sends batch in last, or in a message of its own when last is nil,
+	// with the query warnings on the first message.
+	send := func(batch []jsonRow, last *registryv1.ExecuteStreamResponse) error {
+		resp := last
+		if resp == nil {
+			resp = &registryv1.ExecuteStreamResponse{}
+		}
+		resp.Warnings, warnings = queryWarnings(warnings), nil
+		if idsOnly {
+			for _, r := range batch {
+				resp.Ids = append(resp.Ids, r.CursorID)
+			}
+			return stream.Send(resp)
+		}
+		results, err := listResults(ctx, s.pool, s.cipher, obj, params, batch, v)
+		if err != nil {
+			return err
+		}
+		resp.Results = results
+		return stream.Send(resp)
+	}
+
+	err = s.limits.List.Do(ctx, func() error {
+		rows, err := s.pool.Query(ctx, sqlStr, args...)
+		if err != nil {
+			return queryFailed(err)
+		}
+		defer rows.Close()
+
+		var (
+			batch     = make([]jsonRow, 0, batchSize)
+			total     int64
+			truncated bool
+		)
+		for rows.Next() {
+			// The query reads one row past the limit to tell if more matched.
+			if total == limit {
+				truncated = true
+				break
+			}
+			r, err := scan(rows, params.Order != nil)
+			if err != nil {
+				return queryFailed(err)
+			}
+			batch = append(batch, r)
+			total++
+			if len(batch) == batchSize {
+				if err := send(batch, nil); err != nil {
+					return err
+				}
+				batch = batch[:0]
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return queryFailed(err)
+		}
+		rows.Close()
+		return send(batch, &registryv1.ExecuteStreamResponse{Done: true, Total: total, Truncated: truncated})
+	})
+	if errors.Is(err, db.ErrSaturated) {
+		return queryFailed(err)
+	}
+	return err
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"connectrpc.com/connect"
+	"golang.org/x/sync/errgroup"
+
+	registryv1 "github.com/atlekbai/schema_registry/gen/registry/v1"
+	"github.com/atlekbai/schema_registry/internal/hrql"
+	hrqlpg "github.com/atlekbai/schema_registry/internal/hrql/pg"
+)
+
+// runUnionList executes a union(...) list plan. Its records come from several
+// objects, so it takes the request's select (of shared fields), order and
+// limit, but neither cursors nor expands: a union is read as a single page of
+// at most the server's max page size of records, with an exact total count.
+func (s *OrgService) runUnionList(ctx context.Context, plan *hrql.Plan, msg *registryv1.QueryRequest, checkCost bool) (*connect.Response[registryv1.QueryResponse], error) {
+	u := plan.Union
+	if msg.Cursor != "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("union queries have no further pages; drop the cursor and raise the limit"))
+	}
+	if msg.Expand != "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("expand is not supported on union queries"))
+	}
+	lists := s.limits.Lists.For(nil)
+	params := hrqlpg.UnionListParams{Limit: lists.DefaultPageSize}
+	for name := range strings.SplitSeq(msg.Select, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		if !slices.Contains(u.Fields, name) {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("select: %q is not a field every union source has (%s)", name, strings.Join(u.Fields, ", ")))
+		}
+		params.Select = append(params.Select, name)
+	}
+
+	// As in runHRQLList, sort_by in the query wins over the request's order.
+	if plan.OrderBy != nil {
+		params.Order = &hrqlpg.OrderClause{FieldAPIName: plan.OrderBy.Field, Desc: plan.OrderBy.Desc}
+	} else if msg.Order != "" {
+		field, dir, _ := strings.Cut(msg.Order, ".")
+		if !slices.Contains(u.Fields, field) {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("order: %q is not a field every union source has", field))
+		}
+		params.Order = &hrqlpg.OrderClause{FieldAPIName: field, Desc: strings.EqualFold(dir, "desc")}
+	}
+	switch {
+	case msg.Limit > 0:
+		params.Limit = min(int(msg.Limit), lists.MaxPageSize)
+	case plan.Limit > 0:
+		params.Limit = plan.Limit
+	}
+
+	listSQL, listArgs, err := hrqlpg.BuildUnionList(u, s.cache, params)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("build query: %w", err))
+	}
+	if checkCost {
+		if err := s.checkCost(ctx, listSQL, listArgs); err != nil {
+			return nil, err
+		}
+	}
+	countSQL, countArgs, err := hrqlpg.BuildUnionCount(u, s.cache)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("build count: %w", err))
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	var totalCount int64
+	g.Go(func() error {
+		return s.limits.Count.Do(gctx, func() error {
+			return s.pool.QueryRow(gctx, countSQL, countArgs...).Scan(&totalCount)
+		})
+	})
+	var rows []jsonRow
+	g.Go(func() error {
+		return s.limits.List.Do(gctx, func() error {
+			dbRows, err := s.pool.Query(gctx, listSQL, listArgs...)
+			if err != nil {
+				return err
+			}
+			defer dbRows.Close()
+			rows, err = scanJSONRows(dbRows, false)
+			return err
+		})
+	})
+	if err := g.Wait(); err != nil {
+		return nil, queryFailed(err)
+	}
+
+	resp := &registryv1.QueryResponse{TotalCount: totalCount}
+	if len(rows) > params.Limit {
+		rows = rows[:params.Limit]
+	}
+	for _, r := range rows {
+		st, err := rawJSONToStruct(r.Data)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("marshal result: %w", err))
+		}
+		resp.Results = append(resp.Results, st)
+	}
+	return connect.NewResponse(resp), nil
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+
+	registryv1 "github.com/atlekbai/schema_registry/gen/registry/v1"
+	"github.com/atlekbai/schema_registry/internal/hrql"
+	"github.com/atlekbai/schema_registry/internal/hrql/parser"
+	hrqlpg "github.com/atlekbai/schema_registry/internal/hrql/pg"
+	"github.com/atlekbai/schema_registry/internal/schema"
+	"github.com/atlekbai/schema_registry/internal/webhook"
+)
+
+const (
+	// maxUpdateWhereRows caps the records a single UpdateWhere may touch.
+	maxUpdateWhereRows = 10_000
+	// updateWhereBatch is how many records each UPDATE statement of an
+	// UpdateWhere writes.
+	updateWhereBatch = 500
+)
+
+// UpdateWhere locks the records matching the request's filter, refuses the
+// write when they number more than the row cap or, outside dry runs, other
+// than expected_count, and then applies the patch in batches within one
+// transaction. Each record gets the checks of Update: hierarchy, lookup
+// labels, document size and the validation webhook.
+func (s *RegistryService) UpdateWhere(ctx context.Context, req *connect.Request[registryv1.UpdateWhereRequest]) (*connect.Response[registryv1.UpdateWhereResponse], error) {
+	msg := req.Msg
+	obj := s.cache.Get(msg.ObjectName)
+	if obj == nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("no object registered with api_name %q", msg.ObjectName))
+	}
+	if !msg.DryRun && msg.ExpectedCount == 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("expected_count is required: run a dry run first and pass the matched count it reports"))
+	}
+
+	params, err := s.updateWhereParams(obj, msg)
+	if err != nil {
+		return nil, err
+	}
+	limit := maxUpdateWhereRows
+	if msg.MaxRows > 0 {
+		limit = min(int(msg.MaxRows), limit)
+	}
+	params.Limit = limit
+	params.Lock = true
+
+	data := msg.Data.AsMap()
+	if err := encryptValues(s.cipher, obj, data); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.beginWrite(ctx, msg.DryRun)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	builder := hrqlpg.NewBuilder(obj)
+	sqlStr, args, err := builder.BuildIDs(params)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("build query: %w", err))
+	}
+	rows, err := tx.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("match records: %w", err))
+	}
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("scan id: %w", err))
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("match records: %w", err))
+	}
+
+	if len(ids) > limit {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("the filter matches more than %d records; narrow it or raise max_rows", limit))
+	}
+	if !msg.DryRun && int64(len(ids)) != msg.ExpectedCount {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("the filter matches %d records, not the expected %d; run a new dry run", len(ids), msg.ExpectedCount))
+	}
+
+	for _, id := range ids {
+		if err := checkHierarchy(ctx, tx, obj, id, data); err != nil {
+			return nil, err
+		}
+	}
+	for batch := range slices.Chunk(ids, updateWhereBatch) {
+		sqlStr, args, err := builder.BuildUpdateIDs(batch, data)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+		if _, err := tx.Exec(ctx, sqlStr, args...); err != nil {
+			return nil, writeError("update records", err)
+		}
+	}
+	v := viewerOf(req.Header())
+	for _, id := range ids {
+		if err := s.syncLabels(ctx, tx, obj, id, data); err != nil {
+			return nil, err
+		}
+		if err := s.checkDocumentSize(ctx, tx, obj, builder, id); err != nil {
+			return nil, err
+		}
+		if obj.ValidationWebhook == nil {
+			continue
+		}
+		record, err := s.fetchRecord(ctx, tx, obj, builder, id, nil, v)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.validateRecord(ctx, tx, obj, builder, webhook.OpUpdate, id, record, v); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := finishWrite(ctx, tx, msg.DryRun); err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&registryv1.UpdateWhereResponse{Matched: int64(len(ids)), DryRun: msg.DryRun}), nil
+}
+
+// updateWhereParams returns the conditions selecting an UpdateWhere's
+// records, ordered by id so concurrent bulk updates lock in the same order.
+// An HRQL where is compiled as "<object> | where(...)" and must stay a plain
+// filter of the object.
+func (s *RegistryService) updateWhereParams(obj *schema.ObjectDef, msg *registryv1.UpdateWhereRequest) (*hrqlpg.QueryParams, error) {
+	if (len(msg.Filters) == 0) == (msg.Where == "") {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("set exactly one of filters and where"))
+	}
+	params, err := hrqlpg.ParseParams(obj, hrqlpg.ParamsInput{Filters: msg.Filters, Cache: s.cache, Limits: s.limits.Lists})
+	if err != nil {
+		return nil, paramsError(err)
+	}
+	params.Order = nil
+
+	conds := params.Conditions
+	if msg.Where != "" {
+		ast, err := parser.Parse(fmt.Sprintf("%s | where(%s)", obj.APIName, msg.Where))
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("where: %w", err))
+		}
+		plan, _, err := hrql.NewCompiler(s.cache, "").At(time.Now()).Compile(ast)
+		if err != nil {
+			return nil, compileError(err)
+		}
+		if plan.Kind != hrql.PlanList || cmp.Or(plan.Object, "employees") != obj.APIName || plan.Union != nil ||
+			plan.OrderBy != nil || plan.Limit > 0 || plan.PickOp != "" || plan.Sample > 0 || plan.Case != nil || plan.AsOf != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("where must be a condition on %s records", obj.APIName))
+		}
+		conds = plan.Conditions
+	}
+	params.SQLConditions, err = hrqlpg.TranslateConditions(conds, obj, s.cache)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	return params, nil
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	registryv1 "github.com/atlekbai/schema_registry/gen/registry/v1"
+	registryv1connect "github.com/atlekbai/schema_registry/gen/registry/v1/registryv1connect"
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// defaultUsageHours is the GetUsage window when the request sets none.
+const defaultUsageHours = 24
+
+// UsageService reads the API usage rollups that metrics.APIUsage writes to
+// diagnostics.api_usage.
+type UsageService struct {
+	pool  *pgxpool.Pool
+	cache *schema.Cache
+}
+
+func NewUsageService(pool *pgxpool.Pool, cache *schema.Cache) *UsageService {
+	return &UsageService{pool: pool, cache: cache}
+}
+
+func (s *UsageService) RegisterHandler(interceptors ...connect.Interceptor) (string, http.Handler) {
+	return registryv1connect.NewUsageServiceHandler(s, connect.WithInterceptors(interceptors...))
+}
+
+func (s *UsageService) GetUsage(ctx context.Context, req *connect.Request[registryv1.GetUsageRequest]) (*connect.Response[registryv1.GetUsageResponse], error) {
+	msg := req.Msg
+	if msg.Hours < 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("hours must not be negative"))
+	}
+	since := time.Now().Add(-time.Duration(cmp.Or(msg.Hours, defaultUsageHours)) * time.Hour).Truncate(time.Hour)
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT "object_name", "method", "principal",
+		       sum("requests")::bigint, sum("errors")::bigint, sum("latency_ms_sum"),
+		       max("latency_ms_max"), sum("result_records")::bigint, max("bucket")
+		FROM diagnostics.api_usage
+		WHERE "bucket" >= $1
+		  AND ($2 = '' OR "object_name" = $2)
+		  AND ($3 = '' OR "method" = $3)
+		  AND ($4 = '' OR "principal" = $4)
+		GROUP BY 1, 2, 3
+		ORDER BY 4 DESC, 1, 2, 3
+	`, since, msg.ObjectName, msg.Method, msg.Principal)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("read usage: %w", err))
+	}
+	defer rows.Close()
+
+	resp := &registryv1.GetUsageResponse{Since: since.UTC().Format(time.RFC3339)}
+	used := make(map[string]bool)
+	for rows.Next() {
+		var (
+			st         registryv1.UsageStats
+			latencySum float64
+			lastSeen   time.Time
+		)
+		if err := rows.Scan(&st.ObjectName, &st.Method, &st.Principal, &st.Requests, &st.Errors,
+			&latencySum, &st.MaxLatencyMs, &st.ResultRecords, &lastSeen); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("scan usage: %w", err))
+		}
+		if st.Requests > 0 {
+			st.ErrorRate = float64(st.Errors) / float64(st.Requests)
+			st.MeanLatencyMs = latencySum / float64(st.Requests)
+			st.MeanResultRecords = float64(st.ResultRecords) / float64(st.Requests)
+			used[st.ObjectName] = true
+		}
+		st.LastSeenAt = lastSeen.UTC().Format(time.RFC3339)
+		resp.Stats = append(resp.Stats, &st)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("read usage: %w", err))
+	}
+
+	if msg.ObjectName == "" && msg.Method == "" && msg.Principal == "" {
+		for _, obj := range s.cache.Objects() {
+			if !obj.IsStandard && !used[obj.APIName] {
+				resp.UnusedObjects = append(resp.UnusedObjects, obj.APIName)
+			}
+		}
+	}
+	return connect.NewResponse(resp), nil
+}
@@ -0,0 +1,88 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Org is the fixed org every test database starts with. The manager_path
+// trigger builds the ltree paths from manager_id:
+//
+//	CEO
+//	├── CTO
+//	│   ├── Engineer1
+//	│   └── Engineer2 (CONTRACTOR)
+//	└── CFO
+var Org = struct {
+	Organization string
+	Executive    string // departments
+	Engineering  string
+	Finance      string
+	CEO          string // employees
+	CTO          string
+	CFO          string
+	Engineer1    string
+	Engineer2    string
+}{
+	Organization: "0190aaaa-0000-7000-8000-000000000001",
+	Executive:    "0190aaaa-0001-7000-8000-000000000001",
+	Engineering:  "0190aaaa-0001-7000-8000-000000000002",
+	Finance:      "0190aaaa-0001-7000-8000-000000000003",
+	CEO:          "0190aaaa-0004-7000-8000-000000000001",
+	CTO:          "0190aaaa-0004-7000-8000-000000000002",
+	CFO:          "0190aaaa-0004-7000-8000-000000000003",
+	Engineer1:    "0190aaaa-0004-7000-8000-000000000004",
+	Engineer2:    "0190aaaa-0004-7000-8000-000000000005",
+}
+
+type seedEmployee struct {
+	id, manager, department, number, employmentType, firstName string
+}
+
+func seedOrg(ctx context.Context, conn *pgx.Conn) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `INSERT INTO core.organizations ("id", "title") VALUES ($1, 'Test Corp')`, Org.Organization); err != nil {
+		return err
+	}
+	for id, title := range map[string]string{Org.Executive: "Executive", Org.Engineering: "Engineering", Org.Finance: "Finance"} {
+		if _, err := tx.Exec(ctx, `INSERT INTO core.departments ("id", "organization_id", "title") VALUES ($1, $2, $3)`,
+			id, Org.Organization, title); err != nil {
+			return err
+		}
+	}
+
+	// Managers before their reports, so manager_path resolves on insert.
+	employees := []seedEmployee{
+		{Org.CEO, "", Org.Executive, "T-001", "FULL_TIME", "Ada"},
+		{Org.CTO, Org.CEO, Org.Engineering, "T-002", "FULL_TIME", "Grace"},
+		{Org.CFO, Org.CEO, Org.Finance, "T-003", "FULL_TIME", "Mary"},
+		{Org.Engineer1, Org.CTO, Org.Engineering, "T-004", "FULL_TIME", "Alan"},
+		{Org.Engineer2, Org.CTO, Org.Engineering, "T-005", "CONTRACTOR", "Edsger"},
+	}
+	for _, e := range employees {
+		var individualID string
+		if err := tx.QueryRow(ctx, `INSERT INTO core.individuals ("email", "first_name", "last_name") VALUES ($1, $2, 'Test') RETURNING "id"::text`,
+			fmt.Sprintf("%s@test.example", e.number), e.firstName).Scan(&individualID); err != nil {
+			return err
+		}
+		var manager *string
+		if e.manager != "" {
+			manager = &e.manager
+		}
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO core.employees ("id", "individual_id", "organization_id", "department_id", "manager_id",
+				"employee_number", "employment_type", "start_date")
+			VALUES ($1, $2, $3, $4, $5, $6, $7, '2020-01-01')`,
+			e.id, individualID, Org.Organization, e.department, manager, e.number, e.employmentType); err != nil {
+			return fmt.Errorf("employee %s: %w", e.number, err)
+		}
+	}
+	return tx.Commit(ctx)
+}
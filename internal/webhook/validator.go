@@ -0,0 +1,157 @@
+// Package webhook calls the external validation webhooks objects register in
+// metadata.objects: before a write commits, the candidate record is POSTed to
+// the object's URL, which may reject it with field-level messages.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+// DefaultTimeout applies to webhooks registered without a timeout.
+const DefaultTimeout = 2 * time.Second
+
+// maxResponseBytes caps how much of a webhook response is read.
+const maxResponseBytes = 1 << 20
+
+// Operation is the write being validated.
+type Operation string
+
+const (
+	OpCreate Operation = "CREATE"
+	OpUpdate Operation = "UPDATE"
+)
+
+// Violation is one message from a webhook; Field is empty for record-level
+// messages.
+type Violation struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// RejectedError is returned when the webhook rejected the record.
+type RejectedError struct {
+	Object     string
+	Violations []Violation
+}
+
+func (e *RejectedError) Error() string {
+	if len(e.Violations) == 0 {
+		return fmt.Sprintf("%s rejected by validation webhook", e.Object)
+	}
+	v := e.Violations[0]
+	msg := v.Message
+	if v.Field != "" {
+		msg = v.Field + ": " + msg
+	}
+	if n := len(e.Violations) - 1; n > 0 {
+		msg += fmt.Sprintf(" (and %d more)", n)
+	}
+	return fmt.Sprintf("%s rejected by validation webhook: %s", e.Object, msg)
+}
+
+// ErrUnavailable wraps webhook failures (network errors, timeouts, non-2xx
+// responses, malformed bodies) of fail-closed webhooks.
+var ErrUnavailable = errors.New("validation webhook unavailable")
+
+type request struct {
+	Object    string         `json:"object"`
+	Operation Operation      `json:"operation"`
+	ID        string         `json:"id"`
+	Record    map[string]any `json:"record"`
+}
+
+type response struct {
+	Allowed    bool        `json:"allowed"`
+	Violations []Violation `json:"violations"`
+}
+
+// Validator posts candidate records to validation webhooks.
+type Validator struct {
+	client *http.Client
+	// OnFailOpen, when set, is called with the error of a fail-open webhook
+	// whose write was accepted anyway.
+	OnFailOpen func(obj *schema.ObjectDef, err error)
+}
+
+// NewValidator returns a Validator using client, or http.DefaultClient when
+// client is nil. Timeouts come from each object's webhook.
+func NewValidator(client *http.Client) *Validator {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Validator{client: client}
+}
+
+// Validate checks record against obj's webhook, if any. It returns a
+// *RejectedError when the webhook rejects the record, and an error wrapping
+// ErrUnavailable when a fail-closed webhook cannot be reached or answers
+// badly; fail-open webhooks accept the record in that case.
+func (v *Validator) Validate(ctx context.Context, obj *schema.ObjectDef, op Operation, id string, record map[string]any) error {
+	hook := obj.ValidationWebhook
+	if v == nil || hook == nil {
+		return nil
+	}
+
+	resp, err := v.call(ctx, hook, request{Object: obj.APIName, Operation: op, ID: id, Record: record})
+	if err != nil {
+		if hook.FailOpen {
+			if v.OnFailOpen != nil {
+				v.OnFailOpen(obj, err)
+			}
+			return nil
+		}
+		return fmt.Errorf("%w: %s: %w", ErrUnavailable, obj.APIName, err)
+	}
+	if resp.Allowed && len(resp.Violations) == 0 {
+		return nil
+	}
+	return &RejectedError{Object: obj.APIName, Violations: resp.Violations}
+}
+
+func (v *Validator) call(ctx context.Context, hook *schema.ValidationWebhook, body request) (*response, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("encode request: %w", err)
+	}
+
+	timeout := hook.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := v.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, maxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if res.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("status %d", res.StatusCode)
+	}
+	var out response
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &out, nil
+}
@@ -0,0 +1,97 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/atlekbai/schema_registry/internal/schema"
+)
+
+func testObject(url string, timeout time.Duration, failOpen bool) *schema.ObjectDef {
+	return &schema.ObjectDef{
+		APIName:           "badges",
+		ValidationWebhook: &schema.ValidationWebhook{URL: url, Timeout: timeout, FailOpen: failOpen},
+	}
+}
+
+func TestValidateAllowsAndRejects(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		if req.Object != "badges" || req.Operation != OpCreate || req.ID != "id-1" {
+			t.Errorf("unexpected request %+v", req)
+		}
+		if req.Record["holder"] == "bad" {
+			json.NewEncoder(w).Encode(response{Violations: []Violation{
+				{Field: "holder", Message: "not allowed"},
+				{Message: "record-level"},
+			}})
+			return
+		}
+		json.NewEncoder(w).Encode(response{Allowed: true})
+	}))
+	defer srv.Close()
+
+	v := NewValidator(srv.Client())
+	obj := testObject(srv.URL, 0, false)
+	if err := v.Validate(context.Background(), obj, OpCreate, "id-1", map[string]any{"holder": "ok"}); err != nil {
+		t.Fatalf("allowed record: %v", err)
+	}
+
+	err := v.Validate(context.Background(), obj, OpCreate, "id-1", map[string]any{"holder": "bad"})
+	rejected, ok := errors.AsType[*RejectedError](err)
+	if !ok {
+		t.Fatalf("expected *RejectedError, got %v", err)
+	}
+	if len(rejected.Violations) != 2 || rejected.Violations[0].Field != "holder" {
+		t.Errorf("violations = %+v", rejected.Violations)
+	}
+	if want := "badges rejected by validation webhook: holder: not allowed (and 1 more)"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestValidateFailurePolicy(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(200 * time.Millisecond):
+		}
+	}))
+	defer slow.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+
+	for _, url := range []string{slow.URL, broken.URL} {
+		v := NewValidator(nil)
+		err := v.Validate(context.Background(), testObject(url, 20*time.Millisecond, false), OpUpdate, "id", nil)
+		if !errors.Is(err, ErrUnavailable) {
+			t.Errorf("%s fail-closed: expected ErrUnavailable, got %v", url, err)
+		}
+
+		var failedOpen error
+		v.OnFailOpen = func(_ *schema.ObjectDef, err error) { failedOpen = err }
+		if err := v.Validate(context.Background(), testObject(url, 20*time.Millisecond, true), OpUpdate, "id", nil); err != nil {
+			t.Errorf("%s fail-open: %v", url, err)
+		}
+		if failedOpen == nil {
+			t.Errorf("%s fail-open: OnFailOpen not called", url)
+		}
+	}
+}
+
+func TestValidateWithoutWebhook(t *testing.T) {
+	var v *Validator
+	if err := v.Validate(context.Background(), &schema.ObjectDef{APIName: "x"}, OpCreate, "", nil); err != nil {
+		t.Fatal(err)
+	}
+}
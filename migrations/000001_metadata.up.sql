@@ -3,6 +3,11 @@ begin;
 -- Enable UUID v7 extension
 CREATE EXTENSION IF NOT EXISTS pg_uuidv7;
 
+-- ltree backs hierarchy paths (migration 000005). Also created by
+-- scripts/init.sql; repeated so migrations applied by the server
+-- (MIGRATE_ON_START=auto) need nothing else.
+CREATE EXTENSION IF NOT EXISTS ltree;
+
 -- Create metadata schema
 CREATE SCHEMA IF NOT EXISTS metadata;
 
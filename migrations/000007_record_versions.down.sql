@@ -0,0 +1,11 @@
+begin;
+
+ALTER TABLE core.employees DROP COLUMN IF EXISTS "version";
+ALTER TABLE core.individuals DROP COLUMN IF EXISTS "version";
+ALTER TABLE core.departments DROP COLUMN IF EXISTS "version";
+ALTER TABLE core.organizations DROP COLUMN IF EXISTS "version";
+ALTER TABLE core.users DROP COLUMN IF EXISTS "version";
+
+ALTER TABLE metadata.records DROP COLUMN IF EXISTS "version";
+
+commit;
@@ -0,0 +1,16 @@
+begin;
+
+-- Optimistic concurrency control: every record carries a monotonically
+-- increasing version that the write path compares-and-swaps on update/delete.
+ALTER TABLE metadata.records ADD COLUMN "version" BIGINT NOT NULL DEFAULT 1;
+
+ALTER TABLE core.users ADD COLUMN "version" BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE core.organizations ADD COLUMN "version" BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE core.departments ADD COLUMN "version" BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE core.individuals ADD COLUMN "version" BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE core.employees ADD COLUMN "version" BIGINT NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN metadata.records.version IS 'Record version for optimistic concurrency control - incremented on every write';
+COMMENT ON COLUMN core.employees.version IS 'Record version for optimistic concurrency control - incremented on every write';
+
+commit;
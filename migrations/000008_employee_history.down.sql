@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP FUNCTION IF EXISTS core.employees_as_of(TIMESTAMPTZ);
+DROP TRIGGER IF EXISTS trg_employees_history ON core.employees;
+DROP FUNCTION IF EXISTS core.trg_employees_history();
+DROP TABLE IF EXISTS core.employees_history;
+
+COMMIT;
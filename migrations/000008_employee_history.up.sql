@@ -0,0 +1,50 @@
+BEGIN;
+
+-- Point-in-time history for employees. Each row version is stored as a full
+-- core.employees composite so manager_path (and every other column) can be
+-- read back exactly as it was; [valid_from, valid_to) bounds its lifetime.
+CREATE TABLE core.employees_history (
+	"id"			UUID NOT NULL,
+	"valid_from"	TIMESTAMPTZ NOT NULL,
+	"valid_to"		TIMESTAMPTZ,
+	"row"			core.employees NOT NULL
+);
+CREATE INDEX idx_employees_history_id ON core.employees_history ("id", "valid_from");
+CREATE INDEX idx_employees_history_valid ON core.employees_history ("valid_from", "valid_to");
+
+-- Backfill current state, valid since each record was created.
+INSERT INTO core.employees_history ("id", "valid_from", "row")
+SELECT e."id", e."created_at", e FROM core.employees e;
+
+-- AFTER trigger: close the open version and append the new one. Also fires for
+-- the manager_path cascade, so descendants' historical paths stay consistent.
+CREATE OR REPLACE FUNCTION core.trg_employees_history()
+RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+	IF TG_OP IN ('UPDATE', 'DELETE') THEN
+		UPDATE core.employees_history SET "valid_to" = now()
+		WHERE "id" = OLD."id" AND "valid_to" IS NULL;
+	END IF;
+	IF TG_OP IN ('INSERT', 'UPDATE') THEN
+		INSERT INTO core.employees_history ("id", "valid_from", "row")
+		VALUES (NEW."id", now(), NEW);
+	END IF;
+	RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER trg_employees_history
+	AFTER INSERT OR UPDATE OR DELETE ON core.employees
+	FOR EACH ROW
+	EXECUTE FUNCTION core.trg_employees_history();
+
+-- Snapshot of core.employees as of ts. Usable anywhere the table is, e.g.
+-- SELECT ... FROM core.employees_as_of('2024-06-01') "_e".
+CREATE OR REPLACE FUNCTION core.employees_as_of(ts TIMESTAMPTZ)
+RETURNS SETOF core.employees LANGUAGE sql STABLE AS $$
+	SELECT (h."row").*
+	FROM core.employees_history h
+	WHERE h."valid_from" <= ts AND (h."valid_to" IS NULL OR h."valid_to" > ts);
+$$;
+
+COMMIT;
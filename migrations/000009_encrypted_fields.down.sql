@@ -0,0 +1,19 @@
+begin;
+
+DELETE FROM metadata.fields WHERE "type" = 'ENCRYPTED';
+
+ALTER TABLE metadata.fields DROP CONSTRAINT chk_fields_encrypted_not_unique;
+ALTER TABLE metadata.fields DROP CONSTRAINT fields_type_check;
+ALTER TABLE metadata.fields ADD CONSTRAINT fields_type_check CHECK (
+	"type" IN (
+		-- Basic
+		'TEXT', 'NUMBER', 'CURRENCY', 'PERCENTAGE', 'DATE', 'DATETIME',
+		'BOOLEAN', 'CHOICE', 'MULTICHOICE', 'EMAIL', 'URL', 'PHONE',
+		-- Relationship
+		'LOOKUP',
+		-- Computed
+		'FORMULA', 'SIMPLE_FORMULA', 'SUMMARY'
+	)
+);
+
+commit;
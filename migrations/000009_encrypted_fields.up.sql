@@ -0,0 +1,22 @@
+begin;
+
+-- ENCRYPTED fields hold application-layer AES-GCM ciphertext. The database never
+-- sees plaintext, so uniqueness (and filtering/ordering) cannot be supported.
+ALTER TABLE metadata.fields DROP CONSTRAINT fields_type_check;
+ALTER TABLE metadata.fields ADD CONSTRAINT fields_type_check CHECK (
+	"type" IN (
+		-- Basic
+		'TEXT', 'NUMBER', 'CURRENCY', 'PERCENTAGE', 'DATE', 'DATETIME',
+		'BOOLEAN', 'CHOICE', 'MULTICHOICE', 'EMAIL', 'URL', 'PHONE',
+		-- Sensitive
+		'ENCRYPTED',
+		-- Relationship
+		'LOOKUP',
+		-- Computed
+		'FORMULA', 'SIMPLE_FORMULA', 'SUMMARY'
+	)
+);
+ALTER TABLE metadata.fields ADD CONSTRAINT chk_fields_encrypted_not_unique
+	CHECK (NOT ("type" = 'ENCRYPTED' AND "is_unique"));
+
+commit;
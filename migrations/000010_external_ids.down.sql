@@ -0,0 +1,19 @@
+begin;
+
+-- Drop the per-field unique indexes created for custom external ID fields.
+DO $$
+DECLARE
+	idx TEXT;
+BEGIN
+	FOR idx IN
+		SELECT format('%I.%I', schemaname, indexname) FROM pg_indexes WHERE indexname LIKE 'uq_external_id_%'
+	LOOP
+		EXECUTE 'DROP INDEX ' || idx;
+	END LOOP;
+END
+$$;
+
+ALTER TABLE metadata.fields DROP CONSTRAINT chk_fields_external_id_unique;
+ALTER TABLE metadata.fields DROP COLUMN "is_external_id";
+
+commit;
@@ -0,0 +1,18 @@
+begin;
+
+-- External IDs are the keys integrations (e.g. an HRIS sync) use to address
+-- records they do not know the registry UUID of. Upsert resolves conflicts on
+-- them, so an external ID field is always unique: standard columns carry a
+-- UNIQUE constraint, custom fields get a per-field unique expression index
+-- created alongside the field.
+ALTER TABLE metadata.fields ADD COLUMN "is_external_id" BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE metadata.fields ADD CONSTRAINT chk_fields_external_id_unique
+	CHECK (NOT "is_external_id" OR "is_unique");
+
+COMMENT ON COLUMN metadata.fields.is_external_id IS 'Field is an external key usable by Upsert - implies is_unique';
+
+UPDATE metadata.fields f SET "is_external_id" = TRUE
+FROM metadata.objects o
+WHERE f.object_id = o.id AND o.api_name = 'employees' AND f.api_name = 'employee_number';
+
+commit;
@@ -0,0 +1,6 @@
+begin;
+
+DROP TABLE diagnostics.slow_queries;
+DROP SCHEMA IF EXISTS diagnostics;
+
+commit;
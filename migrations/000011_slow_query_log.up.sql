@@ -0,0 +1,24 @@
+begin;
+
+-- Slow queries recorded by the server's query tracer (SLOW_QUERY_THRESHOLD).
+-- SQL is normalized and arguments are described by type only, so entries
+-- never carry record values. plan holds EXPLAIN (ANALYZE, BUFFERS) output for
+-- the sampled fraction of read-only queries (SLOW_QUERY_EXPLAIN_SAMPLE).
+CREATE SCHEMA IF NOT EXISTS diagnostics;
+
+CREATE TABLE diagnostics.slow_queries (
+	"id"          UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+	"occurred_at" TIMESTAMPTZ NOT NULL,
+	"duration_ms" DOUBLE PRECISION NOT NULL,
+	"object_name" TEXT,
+	"procedure"   TEXT,
+	"sql"         TEXT NOT NULL,
+	"arg_types"   JSONB NOT NULL DEFAULT '[]',
+	"plan"        JSONB,
+	"error"       TEXT
+);
+
+CREATE INDEX idx_slow_queries_occurred_at ON diagnostics.slow_queries ("occurred_at" DESC);
+CREATE INDEX idx_slow_queries_object_name ON diagnostics.slow_queries ("object_name", "occurred_at" DESC);
+
+commit;
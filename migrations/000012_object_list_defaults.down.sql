@@ -0,0 +1,8 @@
+begin;
+
+ALTER TABLE metadata.objects DROP CONSTRAINT chk_objects_page_sizes;
+ALTER TABLE metadata.objects DROP COLUMN "max_page_size";
+ALTER TABLE metadata.objects DROP COLUMN "default_page_size";
+ALTER TABLE metadata.objects DROP COLUMN "default_order";
+
+commit;
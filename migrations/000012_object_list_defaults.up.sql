@@ -0,0 +1,24 @@
+begin;
+
+-- Per-object list defaults, applied by List and HRQL queries when a request
+-- omits order or limit. default_order uses the REST order syntax
+-- ("last_name" or "start_date.desc"); max_page_size caps requested limits
+-- below the service-wide maximum.
+ALTER TABLE metadata.objects ADD COLUMN "default_order" TEXT;
+ALTER TABLE metadata.objects ADD COLUMN "default_page_size" INTEGER;
+ALTER TABLE metadata.objects ADD COLUMN "max_page_size" INTEGER;
+ALTER TABLE metadata.objects ADD CONSTRAINT chk_objects_page_sizes CHECK (
+	("default_page_size" IS NULL OR "default_page_size" > 0)
+	AND ("max_page_size" IS NULL OR "max_page_size" > 0)
+	AND ("default_page_size" IS NULL OR "max_page_size" IS NULL OR "default_page_size" <= "max_page_size")
+);
+
+COMMENT ON COLUMN metadata.objects.default_order IS 'Order applied when a list request has none, e.g. "last_name" or "start_date.desc"';
+COMMENT ON COLUMN metadata.objects.default_page_size IS 'Page size applied when a list request has no limit';
+COMMENT ON COLUMN metadata.objects.max_page_size IS 'Largest page size a list request may ask for';
+
+-- Directory-style objects list by name.
+UPDATE metadata.objects SET "default_order" = 'title' WHERE api_name IN ('organizations', 'departments');
+UPDATE metadata.objects SET "default_order" = 'last_name' WHERE api_name = 'individuals';
+
+commit;
@@ -0,0 +1,9 @@
+begin;
+
+DROP FUNCTION IF EXISTS metadata.add_hierarchy_path(TEXT, TEXT, TEXT);
+DROP FUNCTION IF EXISTS core.trg_hierarchy_path_after();
+DROP FUNCTION IF EXISTS core.trg_hierarchy_path_before();
+ALTER TABLE metadata.fields DROP CONSTRAINT chk_fields_hierarchy_path;
+ALTER TABLE metadata.fields DROP COLUMN "hierarchy_path_column";
+
+commit;
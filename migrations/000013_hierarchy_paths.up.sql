@@ -0,0 +1,123 @@
+begin;
+
+-- Hierarchies besides the management tree (a dotted-line manager, a cost
+-- center tree, ...) are self-referencing LOOKUP fields whose ancestry is
+-- materialized in an ltree column, as employees.manager is in manager_path.
+-- HRQL org functions traverse one with via: .field.
+ALTER TABLE metadata.fields ADD COLUMN "hierarchy_path_column" TEXT;
+ALTER TABLE metadata.fields ADD CONSTRAINT chk_fields_hierarchy_path CHECK (
+	"hierarchy_path_column" IS NULL
+	OR ("type" = 'LOOKUP' AND "storage_column" IS NOT NULL AND "lookup_object_id" = "object_id")
+);
+
+COMMENT ON COLUMN metadata.fields.hierarchy_path_column IS 'ltree column holding each row''s ancestry along this self-referencing lookup';
+
+UPDATE metadata.fields f SET "hierarchy_path_column" = 'manager_path'
+FROM metadata.objects o
+WHERE f."object_id" = o."id" AND o."api_name" = 'employees' AND f."api_name" = 'manager';
+
+-- Generic versions of the manager_path triggers. Arguments: the lookup
+-- column, then the path column.
+CREATE OR REPLACE FUNCTION core.trg_hierarchy_path_before()
+RETURNS trigger LANGUAGE plpgsql AS $$
+DECLARE
+	fk_col      text := TG_ARGV[0];
+	path_col    text := TG_ARGV[1];
+	parent_id   uuid := (to_jsonb(NEW) ->> TG_ARGV[0])::uuid;
+	self_label  ltree := text2ltree(core.uuid_to_ltree_label(NEW."id"));
+	parent_path ltree := ''::ltree;
+	is_cycle    boolean;
+BEGIN
+	IF TG_OP = 'UPDATE' AND parent_id IS NOT NULL THEN
+		EXECUTE format('SELECT EXISTS (SELECT 1 FROM %I.%I WHERE "id" = $1 AND %I <@ $2)',
+			TG_TABLE_SCHEMA, TG_TABLE_NAME, path_col)
+		INTO is_cycle
+		USING parent_id, (to_jsonb(OLD) ->> path_col)::ltree;
+		IF is_cycle THEN
+			RAISE EXCEPTION 'Cycle detected in %: % is a descendant of %', fk_col, parent_id, NEW."id";
+		END IF;
+	END IF;
+
+	IF parent_id IS NOT NULL THEN
+		EXECUTE format('SELECT %I FROM %I.%I WHERE "id" = $1', path_col, TG_TABLE_SCHEMA, TG_TABLE_NAME)
+		INTO STRICT parent_path
+		USING parent_id;
+	END IF;
+
+	NEW := jsonb_populate_record(NEW, jsonb_build_object(path_col, (parent_path || self_label)::text));
+	RETURN NEW;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION core.trg_hierarchy_path_after()
+RETURNS trigger LANGUAGE plpgsql AS $$
+DECLARE
+	path_col text := TG_ARGV[1];
+	old_path ltree := (to_jsonb(OLD) ->> TG_ARGV[1])::ltree;
+	new_path ltree := (to_jsonb(NEW) ->> TG_ARGV[1])::ltree;
+BEGIN
+	IF pg_trigger_depth() > 1 THEN RETURN NULL; END IF;
+
+	IF old_path IS DISTINCT FROM new_path THEN
+		EXECUTE format('UPDATE %I.%I SET %I = $1 || subpath(%I, nlevel($2)) WHERE %I <@ $2 AND "id" != $3',
+			TG_TABLE_SCHEMA, TG_TABLE_NAME, path_col, path_col, path_col)
+		USING new_path, old_path, NEW."id";
+	END IF;
+
+	RETURN NULL;
+END;
+$$;
+
+-- Materialize the hierarchy along a self-referencing LOOKUP column of a
+-- standard object: adds the ltree column with its GiST index, backfills it
+-- from the roots down, installs the maintenance triggers and records the
+-- column in the catalog. Reload the schema cache afterwards.
+CREATE FUNCTION metadata.add_hierarchy_path(
+	p_object_api_name	TEXT,
+	p_field_api_name	TEXT,
+	p_path_column		TEXT
+) RETURNS VOID
+LANGUAGE plpgsql AS $$
+DECLARE
+	v_field_id	UUID;
+	v_fk		TEXT;
+	v_table		TEXT;
+	v_name		TEXT;
+BEGIN
+	SELECT f."id", f."storage_column", format('%I.%I', o."storage_schema", o."storage_table"), o."storage_table"
+	INTO v_field_id, v_fk, v_table, v_name
+	FROM metadata.fields f
+	JOIN metadata.objects o ON o."id" = f."object_id"
+	WHERE o."api_name" = p_object_api_name AND f."api_name" = p_field_api_name
+		AND f."type" = 'LOOKUP' AND f."lookup_object_id" = f."object_id"
+		AND f."storage_column" IS NOT NULL AND o."storage_table" IS NOT NULL;
+
+	IF v_field_id IS NULL THEN
+		RAISE EXCEPTION 'Field "%.%" is not a self-referencing lookup column', p_object_api_name, p_field_api_name;
+	END IF;
+
+	EXECUTE format('ALTER TABLE %s ADD COLUMN %I ltree NOT NULL DEFAULT ''''::ltree', v_table, p_path_column);
+	EXECUTE format('CREATE INDEX %I ON %s USING GIST (%I)', 'idx_' || v_name || '_' || p_path_column, v_table, p_path_column);
+
+	-- Rows caught in a cycle keep an empty path.
+	EXECUTE format($sql$
+		WITH RECURSIVE tree AS (
+			SELECT "id", text2ltree(core.uuid_to_ltree_label("id")) AS path
+			FROM %1$s WHERE %2$I IS NULL
+			UNION ALL
+			SELECT c."id", tree.path || text2ltree(core.uuid_to_ltree_label(c."id"))
+			FROM %1$s c JOIN tree ON c.%2$I = tree."id"
+		)
+		UPDATE %1$s t SET %3$I = tree.path FROM tree WHERE t."id" = tree."id"
+	$sql$, v_table, v_fk, p_path_column);
+
+	EXECUTE format('CREATE TRIGGER %I BEFORE INSERT OR UPDATE OF %I ON %s FOR EACH ROW EXECUTE FUNCTION core.trg_hierarchy_path_before(%L, %L)',
+		'trg_' || v_name || '_' || p_path_column || '_before', v_fk, v_table, v_fk, p_path_column);
+	EXECUTE format('CREATE TRIGGER %I AFTER UPDATE OF %I ON %s FOR EACH ROW EXECUTE FUNCTION core.trg_hierarchy_path_after(%L, %L)',
+		'trg_' || v_name || '_' || p_path_column || '_after', v_fk, v_table, v_fk, p_path_column);
+
+	UPDATE metadata.fields SET "hierarchy_path_column" = p_path_column WHERE "id" = v_field_id;
+END;
+$$;
+
+commit;
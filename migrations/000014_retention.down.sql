@@ -0,0 +1,6 @@
+begin;
+
+DROP TABLE IF EXISTS metadata.retention_audit;
+DROP TABLE IF EXISTS metadata.retention_policies;
+
+commit;
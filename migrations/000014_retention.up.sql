@@ -0,0 +1,41 @@
+begin;
+
+-- Object-level retention: records whose date_field is more than retain_days
+-- in the past are deleted, or have anonymize_fields cleared, by the server's
+-- retention scheduler (RETENTION_INTERVAL).
+CREATE TABLE metadata.retention_policies (
+	"id"               UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+	"created_at"       TIMESTAMPTZ NOT NULL DEFAULT now(),
+	"updated_at"       TIMESTAMPTZ NOT NULL DEFAULT now(),
+	"object_id"        UUID NOT NULL UNIQUE REFERENCES metadata.objects(id) ON DELETE CASCADE,
+	"date_field"       TEXT NOT NULL,
+	"retain_days"      INTEGER NOT NULL CHECK ("retain_days" > 0),
+	"action"           TEXT NOT NULL CHECK ("action" IN ('DELETE', 'ANONYMIZE')),
+	"anonymize_fields" TEXT[] NOT NULL DEFAULT '{}',
+	"enabled"          BOOLEAN NOT NULL DEFAULT TRUE,
+	"last_run_at"      TIMESTAMPTZ,
+	CONSTRAINT chk_retention_anonymize_fields CHECK (
+		("action" = 'ANONYMIZE') = (cardinality("anonymize_fields") > 0)
+	)
+);
+
+COMMENT ON COLUMN metadata.retention_policies.date_field IS 'DATE or DATETIME field api_name the retention period counts from, e.g. end_date';
+COMMENT ON COLUMN metadata.retention_policies.anonymize_fields IS 'Field api_names cleared by ANONYMIZE; empty for DELETE';
+
+-- One row per record purged by a retention policy. Object and record are kept
+-- by value so entries outlive both; no other record values are stored.
+CREATE TABLE metadata.retention_audit (
+	"id"          UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+	"purged_at"   TIMESTAMPTZ NOT NULL DEFAULT now(),
+	"object_id"   UUID NOT NULL,
+	"object_name" TEXT NOT NULL,
+	"record_id"   UUID NOT NULL,
+	"action"      TEXT NOT NULL,
+	"date_field"  TEXT NOT NULL,
+	"retain_days" INTEGER NOT NULL
+);
+
+CREATE INDEX idx_retention_audit_purged_at ON metadata.retention_audit ("purged_at" DESC);
+CREATE INDEX idx_retention_audit_object_name ON metadata.retention_audit ("object_name", "purged_at" DESC);
+
+commit;
@@ -0,0 +1,8 @@
+begin;
+
+ALTER TABLE metadata.objects DROP CONSTRAINT chk_objects_validation_webhook;
+ALTER TABLE metadata.objects DROP COLUMN "validation_webhook_fail_open";
+ALTER TABLE metadata.objects DROP COLUMN "validation_webhook_timeout_ms";
+ALTER TABLE metadata.objects DROP COLUMN "validation_webhook_url";
+
+commit;
@@ -0,0 +1,19 @@
+begin;
+
+-- External record validation. When validation_webhook_url is set, Create,
+-- Update and Upsert POST the candidate record to it before committing; the
+-- endpoint may reject the write with field-level messages. A webhook that
+-- fails or times out rejects the write unless validation_webhook_fail_open.
+ALTER TABLE metadata.objects ADD COLUMN "validation_webhook_url" TEXT;
+ALTER TABLE metadata.objects ADD COLUMN "validation_webhook_timeout_ms" INTEGER;
+ALTER TABLE metadata.objects ADD COLUMN "validation_webhook_fail_open" BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE metadata.objects ADD CONSTRAINT chk_objects_validation_webhook CHECK (
+	("validation_webhook_url" IS NULL OR "validation_webhook_url" ~ '^https?://')
+	AND ("validation_webhook_timeout_ms" IS NULL OR "validation_webhook_timeout_ms" BETWEEN 1 AND 30000)
+);
+
+COMMENT ON COLUMN metadata.objects.validation_webhook_url IS 'Endpoint validating candidate records before writes commit';
+COMMENT ON COLUMN metadata.objects.validation_webhook_timeout_ms IS 'Validation webhook timeout; NULL uses the service default';
+COMMENT ON COLUMN metadata.objects.validation_webhook_fail_open IS 'Accept writes when the validation webhook fails or times out';
+
+commit;
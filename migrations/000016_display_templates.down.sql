@@ -0,0 +1,6 @@
+begin;
+
+ALTER TABLE metadata.objects DROP CONSTRAINT chk_objects_display_template;
+ALTER TABLE metadata.objects DROP COLUMN "display_template";
+
+commit;
@@ -0,0 +1,16 @@
+begin;
+
+-- Display names rendered from a template over the object's fields, e.g.
+-- "{first_name} {last_name}". Expands carry the result as "_display" and
+-- /api/{object}/typeahead prefix-searches it for pickers.
+ALTER TABLE metadata.objects ADD COLUMN "display_template" TEXT;
+ALTER TABLE metadata.objects ADD CONSTRAINT chk_objects_display_template CHECK (
+	"display_template" IS NULL OR length("display_template") BETWEEN 1 AND 200
+);
+
+COMMENT ON COLUMN metadata.objects.display_template IS 'Display name template, e.g. "{first_name} {last_name}"';
+
+UPDATE metadata.objects SET "display_template" = '{title}' WHERE api_name IN ('organizations', 'departments');
+UPDATE metadata.objects SET "display_template" = '{first_name} {last_name}' WHERE api_name = 'individuals';
+
+commit;
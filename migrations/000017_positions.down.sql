@@ -0,0 +1,7 @@
+begin;
+
+DELETE FROM metadata.objects WHERE "api_name" = 'positions';
+
+DROP TABLE IF EXISTS core.positions;
+
+commit;
@@ -0,0 +1,74 @@
+begin;
+
+-- Positions: the assignments an employee holds. An employee can hold several
+-- at once, each with its own department, manager and FTE. employees.manager
+-- and employees.department remain the primary reporting line; org functions
+-- traverse the position-based graph with via: positions.
+CREATE TABLE core.positions (
+	"id"				UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+	"created_at"		TIMESTAMPTZ NOT NULL DEFAULT now(),
+	"updated_at"		TIMESTAMPTZ NOT NULL DEFAULT now(),
+	"version"			BIGINT NOT NULL DEFAULT 1,
+	"employee_id"		UUID NOT NULL REFERENCES core.employees("id") ON DELETE CASCADE,
+	"department_id"		UUID NOT NULL REFERENCES core.departments("id") ON DELETE RESTRICT,
+	"manager_id"		UUID REFERENCES core.employees("id") ON DELETE SET NULL,
+	"title"				TEXT NOT NULL,
+	"fte"				NUMERIC(4, 3) NOT NULL DEFAULT 1,
+	"is_primary"		BOOLEAN NOT NULL DEFAULT FALSE,
+	"start_date"		DATE NOT NULL,
+	"end_date"			DATE,
+	"custom_fields"		JSONB NOT NULL DEFAULT '{}',
+
+	CONSTRAINT chk_positions_fte CHECK ("fte" > 0 AND "fte" <= 1),
+	CONSTRAINT chk_positions_end_date CHECK (
+		"end_date" IS NULL OR "end_date" >= "start_date"
+	),
+	CONSTRAINT chk_positions_manager CHECK ("manager_id" IS DISTINCT FROM "employee_id")
+);
+
+-- At most one primary position per employee.
+CREATE UNIQUE INDEX uq_positions_primary ON core.positions("employee_id") WHERE "is_primary";
+
+CREATE INDEX idx_positions_employee_id ON core.positions("employee_id");
+CREATE INDEX idx_positions_manager_id ON core.positions("manager_id") WHERE "manager_id" IS NOT NULL;
+CREATE INDEX idx_positions_department_id ON core.positions("department_id");
+CREATE INDEX idx_positions_custom_fields ON core.positions USING GIN ("custom_fields" jsonb_path_ops);
+
+COMMENT ON TABLE core.positions IS 'Employee assignments - an employee can hold several positions concurrently';
+COMMENT ON COLUMN core.positions.manager_id IS 'Manager for this assignment - edges of the position-based reporting graph';
+COMMENT ON COLUMN core.positions.fte IS 'Full-time equivalent of the assignment, in (0, 1]';
+
+SELECT metadata.register_object('HR', 'positions', 'Position', 'Positions',
+	'Employee assignments with their own department, manager and FTE',
+	TRUE, 'core', 'positions', TRUE);
+
+SELECT metadata.add_field('positions', 'employee', 'Employee', 'Employee holding the position',
+	'LOOKUP', p_is_required := TRUE, p_is_standard := TRUE,
+	p_storage_column := 'employee_id', p_lookup_object_api_name := 'employees');
+
+SELECT metadata.add_field('positions', 'department', 'Department', 'Department of the assignment',
+	'LOOKUP', p_is_required := TRUE, p_is_standard := TRUE,
+	p_storage_column := 'department_id', p_lookup_object_api_name := 'departments');
+
+SELECT metadata.add_field('positions', 'manager', 'Manager', 'Manager for this assignment',
+	'LOOKUP', p_is_standard := TRUE,
+	p_storage_column := 'manager_id', p_lookup_object_api_name := 'employees');
+
+SELECT metadata.add_field('positions', 'title', 'Title', 'Position title',
+	'TEXT', p_is_required := TRUE, p_is_standard := TRUE, p_storage_column := 'title');
+
+SELECT metadata.add_field('positions', 'fte', 'FTE', 'Full-time equivalent, in (0, 1]',
+	'NUMBER', p_is_required := TRUE, p_is_standard := TRUE, p_storage_column := 'fte');
+
+SELECT metadata.add_field('positions', 'is_primary', 'Primary', 'Whether this is the employee''s primary position',
+	'BOOLEAN', p_is_standard := TRUE, p_storage_column := 'is_primary');
+
+SELECT metadata.add_field('positions', 'start_date', 'Start Date', 'Assignment start date',
+	'DATE', p_is_required := TRUE, p_is_standard := TRUE, p_storage_column := 'start_date');
+
+SELECT metadata.add_field('positions', 'end_date', 'End Date', 'Assignment end date',
+	'DATE', p_is_standard := TRUE, p_storage_column := 'end_date');
+
+UPDATE metadata.objects SET "display_template" = '{title}' WHERE api_name = 'positions';
+
+commit;
@@ -0,0 +1,8 @@
+begin;
+
+ALTER TABLE metadata.objects DROP CONSTRAINT chk_objects_lifecycle;
+ALTER TABLE metadata.objects DROP COLUMN "replacement";
+ALTER TABLE metadata.objects DROP COLUMN "sunset_at";
+ALTER TABLE metadata.objects DROP COLUMN "deprecated_at";
+
+commit;
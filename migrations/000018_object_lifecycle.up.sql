@@ -0,0 +1,18 @@
+begin;
+
+-- API lifecycle of objects. A deprecated object keeps working, but List and
+-- Get on it answer with Deprecation and Sunset headers and a warning naming
+-- the replacement, so consumers migrate before the object is removed.
+ALTER TABLE metadata.objects ADD COLUMN "deprecated_at" TIMESTAMPTZ;
+ALTER TABLE metadata.objects ADD COLUMN "sunset_at" TIMESTAMPTZ;
+ALTER TABLE metadata.objects ADD COLUMN "replacement" TEXT;
+ALTER TABLE metadata.objects ADD CONSTRAINT chk_objects_lifecycle CHECK (
+	("sunset_at" IS NULL OR ("deprecated_at" IS NOT NULL AND "sunset_at" >= "deprecated_at"))
+	AND ("replacement" IS NULL OR "deprecated_at" IS NOT NULL)
+);
+
+COMMENT ON COLUMN metadata.objects.deprecated_at IS 'When the object was deprecated; NULL while it is current';
+COMMENT ON COLUMN metadata.objects.sunset_at IS 'Planned removal of a deprecated object';
+COMMENT ON COLUMN metadata.objects.replacement IS 'API name of the object consumers should migrate to';
+
+commit;
@@ -0,0 +1,5 @@
+begin;
+
+DROP EXTENSION IF EXISTS pg_trgm;
+
+commit;
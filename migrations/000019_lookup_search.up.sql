@@ -0,0 +1,7 @@
+begin;
+
+-- Trigram similarity for lookup picklist search, which ranks display names
+-- by word_similarity so typos and partial words still find a record.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+commit;
@@ -0,0 +1,6 @@
+begin;
+
+ALTER TABLE metadata.fields DROP CONSTRAINT chk_fields_searchable_type;
+ALTER TABLE metadata.fields DROP COLUMN "is_searchable";
+
+commit;
@@ -0,0 +1,12 @@
+begin;
+
+-- Fields searched with contains/starts_with/ends_with. HRQL renders those as
+-- ILIKE, which only a pg_trgm GIN index (enabled in 000019) can serve; the
+-- server creates one per searchable field when AUTO_SEARCH_INDEXES is set.
+ALTER TABLE metadata.fields ADD COLUMN "is_searchable" BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE metadata.fields ADD CONSTRAINT chk_fields_searchable_type
+	CHECK (NOT "is_searchable" OR "type" IN ('TEXT', 'EMAIL', 'URL', 'PHONE', 'CHOICE'));
+
+COMMENT ON COLUMN metadata.fields.is_searchable IS 'Field is string-searched and gets a trigram index';
+
+commit;
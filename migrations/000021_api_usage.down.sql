@@ -0,0 +1,5 @@
+begin;
+
+DROP TABLE IF EXISTS diagnostics.api_usage;
+
+commit;
@@ -0,0 +1,23 @@
+begin;
+
+-- Hourly API usage rollups flushed by every server instance (see
+-- metrics.APIUsage): one row per hour, object, RPC method and principal,
+-- incremented in place. Latencies are in milliseconds; result_records counts
+-- the records responses returned. Buckets older than USAGE_RETENTION are
+-- pruned by the server.
+CREATE TABLE diagnostics.api_usage (
+	"bucket"         TIMESTAMPTZ NOT NULL,
+	"object_name"    TEXT NOT NULL DEFAULT '',
+	"method"         TEXT NOT NULL,
+	"principal"      TEXT NOT NULL DEFAULT '',
+	"requests"       BIGINT NOT NULL DEFAULT 0,
+	"errors"         BIGINT NOT NULL DEFAULT 0,
+	"latency_ms_sum" DOUBLE PRECISION NOT NULL DEFAULT 0,
+	"latency_ms_max" DOUBLE PRECISION NOT NULL DEFAULT 0,
+	"result_records" BIGINT NOT NULL DEFAULT 0,
+	PRIMARY KEY ("bucket", "object_name", "method", "principal")
+);
+
+CREATE INDEX idx_api_usage_object_name ON diagnostics.api_usage ("object_name", "bucket" DESC);
+
+commit;
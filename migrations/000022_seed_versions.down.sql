@@ -0,0 +1,5 @@
+begin;
+
+DROP TABLE IF EXISTS metadata.seed_versions;
+
+commit;
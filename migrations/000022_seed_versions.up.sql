@@ -0,0 +1,10 @@
+begin;
+
+-- Versions of the standard object seed (internal/bootstrap) applied to this
+-- database. A seed run skips versions recorded here unless forced.
+CREATE TABLE metadata.seed_versions (
+	"version"    INTEGER PRIMARY KEY,
+	"applied_at" TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+commit;
@@ -0,0 +1,24 @@
+begin;
+
+-- Metadata mutations held for review while METADATA_CHANGE_APPROVAL is on.
+-- payload is the RPC request (protojson); approving a request replays it
+-- through MetadataService. object_id is NULL for CreateObject and is not a
+-- foreign key, so requests outlive deleted objects.
+CREATE TABLE metadata.change_requests (
+	"id"             UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+	"method"         TEXT NOT NULL,
+	"object_id"      UUID,
+	"payload"        JSONB NOT NULL,
+	"status"         TEXT NOT NULL DEFAULT 'PENDING',
+	"requested_by"   TEXT NOT NULL DEFAULT '',
+	"created_at"     TIMESTAMPTZ NOT NULL DEFAULT now(),
+	"reviewed_by"    TEXT,
+	"reviewed_at"    TIMESTAMPTZ,
+	"review_comment" TEXT,
+	"error"          TEXT,
+	CONSTRAINT chk_change_requests_status CHECK ("status" IN ('PENDING', 'APPROVED', 'REJECTED', 'FAILED'))
+);
+
+CREATE INDEX idx_change_requests_pending ON metadata.change_requests ("created_at") WHERE "status" = 'PENDING';
+
+commit;
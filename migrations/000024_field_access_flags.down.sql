@@ -0,0 +1,6 @@
+begin;
+
+ALTER TABLE metadata.fields DROP COLUMN "is_sortable";
+ALTER TABLE metadata.fields DROP COLUMN "is_filterable";
+
+commit;
@@ -0,0 +1,12 @@
+begin;
+
+-- Allowlist of fields that may be filtered (REST filters, HRQL where) and
+-- sorted (order, sort_by, min_by/max_by). Clearing a flag keeps a field
+-- readable while stopping queries from probing its values.
+ALTER TABLE metadata.fields ADD COLUMN "is_filterable" BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE metadata.fields ADD COLUMN "is_sortable" BOOLEAN NOT NULL DEFAULT TRUE;
+
+COMMENT ON COLUMN metadata.fields.is_filterable IS 'Field may be used in filters and where conditions';
+COMMENT ON COLUMN metadata.fields.is_sortable IS 'Field may be used to order lists';
+
+commit;
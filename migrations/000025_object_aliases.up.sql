@@ -0,0 +1,14 @@
+begin;
+
+-- Former api_names of renamed objects (MetadataService.RenameObject). They
+-- keep resolving to the object, with a deprecation warning, so clients can
+-- move to the new name at their own pace.
+CREATE TABLE metadata.object_aliases (
+	"alias"      TEXT PRIMARY KEY CHECK ("alias" ~ '^[A-Za-z][A-Za-z0-9_]*(__c)?$'),
+	"object_id"  UUID NOT NULL REFERENCES metadata.objects ("id") ON DELETE CASCADE,
+	"created_at" TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_object_aliases_object_id ON metadata.object_aliases ("object_id");
+
+commit;
@@ -0,0 +1,7 @@
+begin;
+
+ALTER TABLE metadata.objects DROP CONSTRAINT chk_objects_data_limits;
+ALTER TABLE metadata.objects DROP COLUMN "max_data_bytes";
+ALTER TABLE metadata.objects DROP COLUMN "max_custom_fields";
+
+commit;
@@ -0,0 +1,17 @@
+begin;
+
+-- Per-object overrides of the server's soft limits on custom data: how many
+-- custom fields the object may have and how large a record's document (data
+-- on metadata.records, custom_fields on standard tables) may grow, in bytes
+-- of JSON text. NULL uses the server default.
+ALTER TABLE metadata.objects ADD COLUMN "max_custom_fields" INTEGER;
+ALTER TABLE metadata.objects ADD COLUMN "max_data_bytes" INTEGER;
+ALTER TABLE metadata.objects ADD CONSTRAINT chk_objects_data_limits CHECK (
+	("max_custom_fields" IS NULL OR "max_custom_fields" > 0)
+	AND ("max_data_bytes" IS NULL OR "max_data_bytes" > 0)
+);
+
+COMMENT ON COLUMN metadata.objects.max_custom_fields IS 'Most custom fields the object may have';
+COMMENT ON COLUMN metadata.objects.max_data_bytes IS 'Largest record document, in bytes of JSON text';
+
+commit;
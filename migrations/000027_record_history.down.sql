@@ -0,0 +1,13 @@
+begin;
+
+DROP TRIGGER trg_positions_history ON core.positions;
+DROP TRIGGER trg_employees_record_history ON core.employees;
+DROP TRIGGER trg_individuals_history ON core.individuals;
+DROP TRIGGER trg_departments_history ON core.departments;
+DROP TRIGGER trg_organizations_history ON core.organizations;
+DROP TRIGGER trg_users_history ON core.users;
+DROP TRIGGER trg_records_history ON metadata.records;
+DROP FUNCTION metadata.trg_record_history();
+DROP TABLE metadata.record_history;
+
+commit;
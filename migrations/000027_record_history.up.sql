@@ -0,0 +1,105 @@
+begin;
+
+-- Per-record version history for every registry object. Each write that
+-- bumps a record's version appends the row as it was written, keyed by the
+-- owning object and the record's version, with the actor and request of the
+-- transaction (see db.WithActor). Derived updates that leave the version
+-- alone (hierarchy path cascades, label refreshes) are not recorded.
+CREATE TABLE metadata.record_history (
+	"object_id"		UUID NOT NULL,
+	"record_id"		UUID NOT NULL,
+	"version"		BIGINT NOT NULL,
+	"operation"		TEXT NOT NULL,
+	-- to_jsonb of the stored row; NULL for DELETE.
+	"data"			JSONB,
+	"actor_id"		TEXT,
+	"request_id"	TEXT,
+	"changed_at"	TIMESTAMPTZ NOT NULL DEFAULT now(),
+	PRIMARY KEY ("object_id", "record_id", "version"),
+	CONSTRAINT chk_record_history_operation CHECK ("operation" IN ('SNAPSHOT', 'INSERT', 'UPDATE', 'DELETE'))
+);
+
+COMMENT ON TABLE metadata.record_history IS 'Versions of registry records with the actor that wrote them';
+COMMENT ON COLUMN metadata.record_history.operation IS 'SNAPSHOT marks the state when history began';
+
+-- AFTER trigger shared by metadata.records and the core tables. Custom
+-- records carry their object; standard rows are attributed to the object
+-- stored in the trigger's table. A DELETE is recorded as the version after
+-- the last one, without data.
+CREATE OR REPLACE FUNCTION metadata.trg_record_history()
+RETURNS trigger LANGUAGE plpgsql AS $$
+DECLARE
+	v_row		JSONB;
+	v_object	UUID;
+	v_version	BIGINT;
+BEGIN
+	IF TG_OP = 'UPDATE' AND NEW."version" IS NOT DISTINCT FROM OLD."version" THEN
+		RETURN NULL;
+	END IF;
+	IF TG_OP = 'DELETE' THEN
+		v_row := to_jsonb(OLD);
+		v_version := OLD."version" + 1;
+	ELSE
+		v_row := to_jsonb(NEW);
+		v_version := NEW."version";
+	END IF;
+
+	IF TG_TABLE_SCHEMA = 'metadata' THEN
+		v_object := (v_row->>'object_id')::uuid;
+	ELSE
+		SELECT o."id" INTO v_object FROM metadata.objects o
+		WHERE o."storage_schema" = TG_TABLE_SCHEMA AND o."storage_table" = TG_TABLE_NAME;
+		IF v_object IS NULL THEN
+			RETURN NULL;
+		END IF;
+	END IF;
+
+	INSERT INTO metadata.record_history ("object_id", "record_id", "version", "operation", "data", "actor_id", "request_id")
+	VALUES (v_object, (v_row->>'id')::uuid, v_version, TG_OP,
+		CASE WHEN TG_OP = 'DELETE' THEN NULL ELSE v_row END,
+		NULLIF(current_setting('app.actor_id', true), ''),
+		NULLIF(current_setting('app.request_id', true), ''));
+	RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER trg_records_history
+	AFTER INSERT OR UPDATE OR DELETE ON metadata.records
+	FOR EACH ROW EXECUTE FUNCTION metadata.trg_record_history();
+CREATE TRIGGER trg_users_history
+	AFTER INSERT OR UPDATE OR DELETE ON core.users
+	FOR EACH ROW EXECUTE FUNCTION metadata.trg_record_history();
+CREATE TRIGGER trg_organizations_history
+	AFTER INSERT OR UPDATE OR DELETE ON core.organizations
+	FOR EACH ROW EXECUTE FUNCTION metadata.trg_record_history();
+CREATE TRIGGER trg_departments_history
+	AFTER INSERT OR UPDATE OR DELETE ON core.departments
+	FOR EACH ROW EXECUTE FUNCTION metadata.trg_record_history();
+CREATE TRIGGER trg_individuals_history
+	AFTER INSERT OR UPDATE OR DELETE ON core.individuals
+	FOR EACH ROW EXECUTE FUNCTION metadata.trg_record_history();
+CREATE TRIGGER trg_employees_record_history
+	AFTER INSERT OR UPDATE OR DELETE ON core.employees
+	FOR EACH ROW EXECUTE FUNCTION metadata.trg_record_history();
+CREATE TRIGGER trg_positions_history
+	AFTER INSERT OR UPDATE OR DELETE ON core.positions
+	FOR EACH ROW EXECUTE FUNCTION metadata.trg_record_history();
+
+-- Backfill current state as each record's first known version.
+INSERT INTO metadata.record_history ("object_id", "record_id", "version", "operation", "data", "changed_at")
+SELECT r."object_id", r."id", r."version", 'SNAPSHOT', to_jsonb(r), r."updated_at" FROM metadata.records r;
+
+INSERT INTO metadata.record_history ("object_id", "record_id", "version", "operation", "data", "changed_at")
+SELECT o."id", (t.row->>'id')::uuid, (t.row->>'version')::bigint, 'SNAPSHOT', t.row, (t.row->>'updated_at')::timestamptz
+FROM metadata.objects o
+CROSS JOIN LATERAL (
+	SELECT to_jsonb(u) AS row FROM core.users u WHERE o."storage_table" = 'users'
+	UNION ALL SELECT to_jsonb(g) FROM core.organizations g WHERE o."storage_table" = 'organizations'
+	UNION ALL SELECT to_jsonb(d) FROM core.departments d WHERE o."storage_table" = 'departments'
+	UNION ALL SELECT to_jsonb(i) FROM core.individuals i WHERE o."storage_table" = 'individuals'
+	UNION ALL SELECT to_jsonb(e) FROM core.employees e WHERE o."storage_table" = 'employees'
+	UNION ALL SELECT to_jsonb(p) FROM core.positions p WHERE o."storage_table" = 'positions'
+) t
+WHERE o."storage_schema" = 'core';
+
+commit;
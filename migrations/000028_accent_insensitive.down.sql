@@ -0,0 +1,7 @@
+begin;
+
+ALTER TABLE metadata.fields DROP CONSTRAINT chk_fields_accent_insensitive_type;
+ALTER TABLE metadata.fields DROP COLUMN "is_accent_insensitive";
+DROP FUNCTION metadata.unaccent(TEXT);
+
+commit;
@@ -0,0 +1,22 @@
+begin;
+
+-- Accent-insensitive string matching: HRQL contains_fold(), and contains,
+-- starts_with and ends_with on fields flagged is_accent_insensitive, compare
+-- metadata.unaccent of both sides, so "Jose" matches "José". unaccent() is
+-- only STABLE (its dictionary could change), which expression indexes
+-- reject; the wrapper pins the dictionary and is declared IMMUTABLE so
+-- search indexes can be built on it.
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+CREATE OR REPLACE FUNCTION metadata.unaccent(TEXT)
+RETURNS TEXT LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE AS $$
+	SELECT public.unaccent('public.unaccent'::regdictionary, $1);
+$$;
+
+ALTER TABLE metadata.fields ADD COLUMN "is_accent_insensitive" BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE metadata.fields ADD CONSTRAINT chk_fields_accent_insensitive_type
+	CHECK (NOT "is_accent_insensitive" OR "type" IN ('TEXT', 'EMAIL', 'URL', 'PHONE', 'CHOICE'));
+
+COMMENT ON COLUMN metadata.fields.is_accent_insensitive IS 'String operations on the field ignore accents';
+
+commit;
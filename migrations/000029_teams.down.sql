@@ -0,0 +1,12 @@
+begin;
+
+DELETE FROM metadata.record_history h
+USING metadata.objects o
+WHERE h.object_id = o.id AND o.api_name IN ('teams', 'team_memberships');
+
+DELETE FROM metadata.objects WHERE "api_name" IN ('team_memberships', 'teams');
+
+DROP TABLE IF EXISTS core.team_memberships;
+DROP TABLE IF EXISTS core.teams;
+
+commit;
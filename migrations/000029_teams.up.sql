@@ -0,0 +1,87 @@
+begin;
+
+-- Teams: groups of employees that cut across the reporting line. An employee
+-- can belong to several teams; HRQL reads membership with team("slug") and
+-- where(member_of("slug")).
+CREATE TABLE core.teams (
+	"id"				UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+	"created_at"		TIMESTAMPTZ NOT NULL DEFAULT now(),
+	"updated_at"		TIMESTAMPTZ NOT NULL DEFAULT now(),
+	"version"			BIGINT NOT NULL DEFAULT 1,
+	"organization_id"	UUID NOT NULL REFERENCES core.organizations("id") ON DELETE CASCADE,
+	"slug"				TEXT NOT NULL UNIQUE,
+	"title"				TEXT NOT NULL,
+	"description"		TEXT,
+	"custom_fields"		JSONB NOT NULL DEFAULT '{}',
+
+	CONSTRAINT chk_teams_slug CHECK ("slug" ~ '^[a-z][a-z0-9_-]*$')
+);
+
+CREATE TABLE core.team_memberships (
+	"id"				UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+	"created_at"		TIMESTAMPTZ NOT NULL DEFAULT now(),
+	"updated_at"		TIMESTAMPTZ NOT NULL DEFAULT now(),
+	"version"			BIGINT NOT NULL DEFAULT 1,
+	"team_id"			UUID NOT NULL REFERENCES core.teams("id") ON DELETE CASCADE,
+	"employee_id"		UUID NOT NULL REFERENCES core.employees("id") ON DELETE CASCADE,
+	"role"				TEXT NOT NULL DEFAULT 'MEMBER',
+	"custom_fields"		JSONB NOT NULL DEFAULT '{}',
+
+	CONSTRAINT uq_team_memberships UNIQUE ("team_id", "employee_id")
+);
+
+CREATE INDEX idx_teams_organization_id ON core.teams("organization_id");
+CREATE INDEX idx_teams_custom_fields ON core.teams USING GIN ("custom_fields" jsonb_path_ops);
+CREATE INDEX idx_team_memberships_employee_id ON core.team_memberships("employee_id");
+CREATE INDEX idx_team_memberships_custom_fields ON core.team_memberships USING GIN ("custom_fields" jsonb_path_ops);
+
+COMMENT ON TABLE core.teams IS 'Teams - groups of employees independent of the reporting line';
+COMMENT ON COLUMN core.teams.slug IS 'Stable key HRQL addresses the team by, e.g. team("platform")';
+COMMENT ON TABLE core.team_memberships IS 'Employees belonging to teams - an employee can be on several';
+
+SELECT metadata.register_object('HR', 'teams', 'Team', 'Teams',
+	'Groups of employees independent of the reporting line',
+	TRUE, 'core', 'teams', TRUE);
+
+SELECT metadata.add_field('teams', 'organization', 'Organization', 'Organization the team belongs to',
+	'LOOKUP', p_is_required := TRUE, p_is_standard := TRUE,
+	p_storage_column := 'organization_id', p_lookup_object_api_name := 'organizations');
+
+SELECT metadata.add_field('teams', 'slug', 'Slug', 'Stable key of the team, used by HRQL team() and member_of()',
+	'TEXT', p_is_required := TRUE, p_is_unique := TRUE, p_is_standard := TRUE, p_storage_column := 'slug');
+
+SELECT metadata.add_field('teams', 'title', 'Title', 'Team name',
+	'TEXT', p_is_required := TRUE, p_is_standard := TRUE, p_storage_column := 'title');
+
+SELECT metadata.add_field('teams', 'description', 'Description', 'What the team does',
+	'TEXT', p_is_standard := TRUE, p_storage_column := 'description');
+
+SELECT metadata.register_object('HR', 'team_memberships', 'Team Membership', 'Team Memberships',
+	'Employees belonging to teams',
+	TRUE, 'core', 'team_memberships', TRUE);
+
+SELECT metadata.add_field('team_memberships', 'team', 'Team', 'Team the employee belongs to',
+	'LOOKUP', p_is_required := TRUE, p_is_standard := TRUE,
+	p_storage_column := 'team_id', p_lookup_object_api_name := 'teams');
+
+SELECT metadata.add_field('team_memberships', 'employee', 'Employee', 'Member of the team',
+	'LOOKUP', p_is_required := TRUE, p_is_standard := TRUE,
+	p_storage_column := 'employee_id', p_lookup_object_api_name := 'employees');
+
+SELECT metadata.add_field('team_memberships', 'role', 'Role', 'Role of the member in the team',
+	'CHOICE', '{"options": ["LEAD", "MEMBER"]}', p_is_standard := TRUE, p_storage_column := 'role');
+
+UPDATE metadata.fields f SET "is_external_id" = TRUE
+FROM metadata.objects o
+WHERE f.object_id = o.id AND o.api_name = 'teams' AND f.api_name = 'slug';
+
+UPDATE metadata.objects SET "display_template" = '{title}', "default_order" = 'title' WHERE api_name = 'teams';
+
+CREATE TRIGGER trg_teams_history
+	AFTER INSERT OR UPDATE OR DELETE ON core.teams
+	FOR EACH ROW EXECUTE FUNCTION metadata.trg_record_history();
+CREATE TRIGGER trg_team_memberships_history
+	AFTER INSERT OR UPDATE OR DELETE ON core.team_memberships
+	FOR EACH ROW EXECUTE FUNCTION metadata.trg_record_history();
+
+commit;
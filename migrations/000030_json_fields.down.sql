@@ -0,0 +1,21 @@
+begin;
+
+DELETE FROM metadata.fields WHERE "type" = 'JSON';
+
+ALTER TABLE metadata.fields DROP CONSTRAINT chk_fields_json_unordered;
+ALTER TABLE metadata.fields DROP CONSTRAINT fields_type_check;
+ALTER TABLE metadata.fields ADD CONSTRAINT fields_type_check CHECK (
+	"type" IN (
+		-- Basic
+		'TEXT', 'NUMBER', 'CURRENCY', 'PERCENTAGE', 'DATE', 'DATETIME',
+		'BOOLEAN', 'CHOICE', 'MULTICHOICE', 'EMAIL', 'URL', 'PHONE',
+		-- Sensitive
+		'ENCRYPTED',
+		-- Relationship
+		'LOOKUP',
+		-- Computed
+		'FORMULA', 'SIMPLE_FORMULA', 'SUMMARY'
+	)
+);
+
+commit;
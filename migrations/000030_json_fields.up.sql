@@ -0,0 +1,25 @@
+begin;
+
+-- JSON fields attach arbitrary structured payloads to records. Values are
+-- stored and returned as written; they have no order or identity, so they
+-- are never unique or sortable, and are created unfilterable.
+ALTER TABLE metadata.fields DROP CONSTRAINT fields_type_check;
+ALTER TABLE metadata.fields ADD CONSTRAINT fields_type_check CHECK (
+	"type" IN (
+		-- Basic
+		'TEXT', 'NUMBER', 'CURRENCY', 'PERCENTAGE', 'DATE', 'DATETIME',
+		'BOOLEAN', 'CHOICE', 'MULTICHOICE', 'EMAIL', 'URL', 'PHONE',
+		-- Structured
+		'JSON',
+		-- Sensitive
+		'ENCRYPTED',
+		-- Relationship
+		'LOOKUP',
+		-- Computed
+		'FORMULA', 'SIMPLE_FORMULA', 'SUMMARY'
+	)
+);
+ALTER TABLE metadata.fields ADD CONSTRAINT chk_fields_json_unordered
+	CHECK (NOT ("type" = 'JSON' AND ("is_unique" OR "is_sortable")));
+
+commit;
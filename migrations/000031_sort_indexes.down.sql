@@ -0,0 +1,6 @@
+begin;
+
+-- Sort indexes on custom date fields go with the function.
+DROP FUNCTION IF EXISTS metadata.sort_timestamptz(TEXT) CASCADE;
+
+commit;
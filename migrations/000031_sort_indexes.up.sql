@@ -0,0 +1,14 @@
+begin;
+
+-- Keyset pagination over custom DATE and DATETIME fields orders by their
+-- document value as a timestamptz. The text cast is only STABLE (it reads
+-- the TimeZone setting for values without an offset), which expression
+-- indexes reject; the wrapper pins TimeZone to UTC and is declared
+-- IMMUTABLE so the server's sort indexes can be built on it.
+CREATE OR REPLACE FUNCTION metadata.sort_timestamptz(TEXT)
+RETURNS TIMESTAMPTZ LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE
+SET "TimeZone" = 'UTC' AS $$
+	SELECT $1::timestamptz;
+$$;
+
+commit;
@@ -0,0 +1,9 @@
+begin;
+
+DROP TRIGGER IF EXISTS trg_object_aliases_outbox ON metadata.object_aliases;
+DROP TRIGGER IF EXISTS trg_fields_outbox ON metadata.fields;
+DROP TRIGGER IF EXISTS trg_objects_outbox ON metadata.objects;
+DROP FUNCTION IF EXISTS metadata.trg_schema_outbox();
+DROP TABLE IF EXISTS metadata.schema_outbox;
+
+commit;
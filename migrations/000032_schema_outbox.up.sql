@@ -0,0 +1,42 @@
+begin;
+
+-- Outbox of catalog changes. Every statement that writes the tables the
+-- schema cache is loaded from appends an entry and notifies the
+-- schema_changes channel in the writer's own transaction, so a committed
+-- change is always announced and a rolled back one never is. Servers reload
+-- their cache on the notification and, after missing notifications while
+-- disconnected, on finding an entry newer than the last one they applied
+-- (see schema.Watcher).
+CREATE TABLE metadata.schema_outbox (
+	"id"			BIGSERIAL PRIMARY KEY,
+	"table_name"	TEXT NOT NULL,
+	"operation"		TEXT NOT NULL,
+	"created_at"	TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMENT ON TABLE metadata.schema_outbox IS 'Catalog writes, announced on the schema_changes channel';
+
+CREATE OR REPLACE FUNCTION metadata.trg_schema_outbox()
+RETURNS trigger LANGUAGE plpgsql AS $$
+DECLARE
+	v_id	BIGINT;
+BEGIN
+	INSERT INTO metadata.schema_outbox ("table_name", "operation")
+	VALUES (TG_TABLE_NAME, TG_OP)
+	RETURNING "id" INTO v_id;
+	PERFORM pg_notify('schema_changes', v_id::text);
+	RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER trg_objects_outbox
+	AFTER INSERT OR UPDATE OR DELETE ON metadata.objects
+	FOR EACH STATEMENT EXECUTE FUNCTION metadata.trg_schema_outbox();
+CREATE TRIGGER trg_fields_outbox
+	AFTER INSERT OR UPDATE OR DELETE ON metadata.fields
+	FOR EACH STATEMENT EXECUTE FUNCTION metadata.trg_schema_outbox();
+CREATE TRIGGER trg_object_aliases_outbox
+	AFTER INSERT OR UPDATE OR DELETE ON metadata.object_aliases
+	FOR EACH STATEMENT EXECUTE FUNCTION metadata.trg_schema_outbox();
+
+commit;
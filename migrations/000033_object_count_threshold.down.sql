@@ -0,0 +1,6 @@
+begin;
+
+ALTER TABLE metadata.objects DROP CONSTRAINT chk_objects_exact_count_threshold;
+ALTER TABLE metadata.objects DROP COLUMN "exact_count_threshold";
+
+commit;
@@ -0,0 +1,13 @@
+begin;
+
+-- Per-object override of the server's exact count threshold: the planner
+-- estimate of a list's rows at or below which its total count is exact
+-- rather than estimated. NULL uses the server default.
+ALTER TABLE metadata.objects ADD COLUMN "exact_count_threshold" BIGINT;
+ALTER TABLE metadata.objects ADD CONSTRAINT chk_objects_exact_count_threshold CHECK (
+	"exact_count_threshold" IS NULL OR "exact_count_threshold" > 0
+);
+
+COMMENT ON COLUMN metadata.objects.exact_count_threshold IS 'Largest row estimate counted exactly';
+
+commit;
@@ -0,0 +1,6 @@
+begin;
+
+ALTER TABLE metadata.objects DROP CONSTRAINT chk_objects_cache_max_age;
+ALTER TABLE metadata.objects DROP COLUMN "cache_max_age_seconds";
+
+commit;
@@ -0,0 +1,13 @@
+begin;
+
+-- Per-object HTTP caching of REST reads: how many seconds browsers and CDNs
+-- may reuse a List or Get response of the object, for reference data that
+-- rarely changes (departments, locations). NULL disables caching.
+ALTER TABLE metadata.objects ADD COLUMN "cache_max_age_seconds" INTEGER;
+ALTER TABLE metadata.objects ADD CONSTRAINT chk_objects_cache_max_age CHECK (
+	"cache_max_age_seconds" IS NULL OR "cache_max_age_seconds" > 0
+);
+
+COMMENT ON COLUMN metadata.objects.cache_max_age_seconds IS 'Seconds REST reads may be cached by clients';
+
+commit;
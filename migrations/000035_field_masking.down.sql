@@ -0,0 +1,6 @@
+begin;
+
+ALTER TABLE metadata.fields DROP CONSTRAINT chk_fields_masking;
+ALTER TABLE metadata.fields DROP COLUMN "masking";
+
+commit;
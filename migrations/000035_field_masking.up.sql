@@ -0,0 +1,13 @@
+begin;
+
+-- Masking policies: record reads present a field's value through the
+-- transform its policy selects for the caller's permissions,
+-- {"default": "last4", "rules": [{"permission": "hr:read", "transform": "none"}]}.
+-- NULL leaves the field unmasked.
+ALTER TABLE metadata.fields ADD COLUMN "masking" JSONB;
+ALTER TABLE metadata.fields ADD CONSTRAINT chk_fields_masking
+	CHECK ("masking" IS NULL OR jsonb_typeof("masking") = 'object');
+
+COMMENT ON COLUMN metadata.fields.masking IS 'Masking transforms of the field value by caller permission';
+
+commit;
@@ -0,0 +1,17 @@
+begin;
+
+-- PII flags: fields holding personal data, whose values AdminService
+-- AnonymizeObject replaces with deterministic fakes so production snapshots
+-- can be restored into lower environments. Only types a fake can be made
+-- for may be flagged; ENCRYPTED values are already unreadable without the key.
+ALTER TABLE metadata.fields ADD COLUMN "is_pii" BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE metadata.fields ADD CONSTRAINT chk_fields_pii_type
+	CHECK (NOT "is_pii" OR "type" IN ('TEXT', 'EMAIL', 'URL', 'PHONE', 'DATE'));
+
+COMMENT ON COLUMN metadata.fields.is_pii IS 'The field holds personal data replaced by anonymization';
+
+UPDATE metadata.fields f SET "is_pii" = TRUE FROM metadata.objects o
+WHERE f."object_id" = o."id" AND o."api_name" = 'individuals'
+	AND f."api_name" IN ('email', 'first_name', 'last_name');
+
+commit;
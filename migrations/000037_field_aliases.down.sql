@@ -0,0 +1,5 @@
+begin;
+
+DROP TABLE IF EXISTS metadata.field_aliases;
+
+commit;
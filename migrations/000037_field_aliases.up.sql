@@ -0,0 +1,20 @@
+begin;
+
+-- Former api_names of renamed fields (MetadataService.RenameField). They keep
+-- resolving to the field in registry requests and HRQL, so queries written
+-- against the old name survive the rename.
+CREATE TABLE metadata.field_aliases (
+	"object_id"  UUID NOT NULL REFERENCES metadata.objects ("id") ON DELETE CASCADE,
+	"alias"      TEXT NOT NULL CHECK ("alias" ~ '^[A-Za-z][A-Za-z0-9_]*(__c)?$'),
+	"field_id"   UUID NOT NULL REFERENCES metadata.fields ("id") ON DELETE CASCADE,
+	"created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+	PRIMARY KEY ("object_id", "alias")
+);
+
+CREATE INDEX idx_field_aliases_field_id ON metadata.field_aliases ("field_id");
+
+CREATE TRIGGER trg_field_aliases_outbox
+	AFTER INSERT OR UPDATE OR DELETE ON metadata.field_aliases
+	FOR EACH STATEMENT EXECUTE FUNCTION metadata.trg_schema_outbox();
+
+commit;
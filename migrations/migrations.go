@@ -0,0 +1,10 @@
+// Package migrations embeds the SQL schema migrations so the server can apply
+// or verify them at startup (see db.Migrator). Files are named
+// NNNNNN_name.up.sql / NNNNNN_name.down.sql and each wraps itself in a
+// transaction.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS